@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/repl"
+)
+
+// chatCommand chats with an agent from its YAML configuration in the terminal
+func chatCommand(args []string) error {
+	fs := flag.NewFlagSet("chat", flag.ExitOnError)
+	var flags agentFlags
+	flags.register(fs)
+	orgID := fs.String("org", multitenancy.DefaultOrgID, "organization ID of the conversation")
+	conversationID := fs.String("conversation", "cli", "conversation ID")
+	thinking := fs.Bool("thinking", false, "show the agent's thinking steps")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: agentsdk chat [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	assistant, err := flags.load()
+	if err != nil {
+		return err
+	}
+
+	// Switching models keeps the conversation
+	switchModel := func(ctx context.Context, model string) (interfaces.StreamingAgent, error) {
+		return flags.loadWithModel(model, agent.WithMemory(assistant.GetMemory()))
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), *orgID)
+	ctx = memory.WithConversationID(ctx, *conversationID)
+
+	fmt.Printf("Chatting with %s. Type /help for commands, /exit to quit.\n", flags.name)
+	return repl.New(assistant,
+		repl.WithModelSwitcher(switchModel),
+		repl.WithThinking(*thinking),
+		repl.WithInterrupt(true),
+	).Run(ctx)
+}
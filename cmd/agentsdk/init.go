@@ -0,0 +1,191 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// projectTemplate holds the values substituted in the scaffolded files
+type projectTemplate struct {
+	Module   string
+	Agent    string
+	Provider string
+	Model    string
+	APIKey   string // environment variable of the provider's API key
+}
+
+// EnvRef returns the config file reference of an environment variable
+func (projectTemplate) EnvRef(name string) string {
+	return "${" + name + "}"
+}
+
+// providerDefaults are the default model and API key variable of the
+// providers init can scaffold
+var providerDefaults = map[string]struct{ model, apiKey string }{
+	"openai":    {"gpt-4o-mini", "OPENAI_API_KEY"},
+	"anthropic": {"claude-sonnet-4-20250514", "ANTHROPIC_API_KEY"},
+	"gemini":    {"gemini-2.5-flash", "GEMINI_API_KEY"},
+	"ollama":    {"llama3.2", ""},
+}
+
+var agentsYAMLTemplate = template.Must(template.New("agents.yaml").Parse(`{{.Agent}}:
+  role: >
+    Helpful Assistant
+  goal: >
+    Answer questions clearly and accurately, using tools when they help
+  backstory: >
+    You are a knowledgeable assistant who gives concise, well-reasoned answers.
+
+  max_iterations: 5
+  require_plan_approval: false
+
+  llm_provider:
+    provider: {{.Provider}}
+    model: "${LLM_MODEL:-{{.Model}}}"
+{{- if .APIKey}}
+    config:
+      api_key: "{{.EnvRef .APIKey}}"
+{{- end}}
+
+  llm_config:
+    temperature: 0.7
+
+  tools:
+    - type: builtin
+      name: calculator
+`))
+
+var mainGoTemplate = template.Must(template.New("main.go").Parse(`package main
+
+import (
+	"log"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/microservice"
+)
+
+func main() {
+	configs, err := agent.LoadAgentConfigsFromFile("agents.yaml")
+	if err != nil {
+		log.Fatalf("Failed to load agents.yaml: %v", err)
+	}
+
+	assistant, err := agent.NewAgentFromConfig("{{.Agent}}", configs, nil,
+		agent.WithMemory(memory.NewConversationBuffer()),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create agent: %v", err)
+	}
+
+	server := microservice.NewHTTPServerWithUI(assistant, 8080, nil)
+	log.Println("Open http://localhost:8080 to chat with the agent")
+	if err := server.Start(); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+`))
+
+var envTemplate = template.Must(template.New(".env").Parse(`{{if .APIKey}}{{.APIKey}}=
+{{end}}# LLM_MODEL={{.Model}}
+`))
+
+var goModTemplate = template.Must(template.New("go.mod").Parse(`module {{.Module}}
+
+go 1.25
+`))
+
+// initCommand scaffolds a project with agents.yaml and main.go
+func initCommand(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	provider := fs.String("provider", "openai", "LLM provider: openai, anthropic, gemini or ollama")
+	model := fs.String("model", "", "model (default: the provider's default model)")
+	name := fs.String("agent", "assistant", "name of the agent")
+	module := fs.String("module", "", "Go module path (default: the directory name)")
+	force := fs.Bool("force", false, "overwrite existing files")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: agentsdk init [flags] [dir]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	defaults, ok := providerDefaults[*provider]
+	if !ok {
+		return fmt.Errorf("unsupported provider %s", *provider)
+	}
+	project := projectTemplate{
+		Module:   *module,
+		Agent:    *name,
+		Provider: *provider,
+		Model:    *model,
+		APIKey:   defaults.apiKey,
+	}
+	if project.Model == "" {
+		project.Model = defaults.model
+	}
+	if project.Module == "" {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		project.Module = strings.ToLower(filepath.Base(absDir))
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	files := []struct {
+		name     string
+		template *template.Template
+	}{
+		{"agents.yaml", agentsYAMLTemplate},
+		{"main.go", mainGoTemplate},
+		{".env", envTemplate},
+		{"go.mod", goModTemplate},
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		if _, err := os.Stat(path); err == nil && !*force {
+			fmt.Printf("  skipped %s (exists, use -force to overwrite)\n", path)
+			continue
+		}
+		if err := writeTemplate(path, file.template, project); err != nil {
+			return err
+		}
+		fmt.Printf("  created %s\n", path)
+	}
+
+	fmt.Printf("\nNext steps:\n")
+	if dir != "." {
+		fmt.Printf("  cd %s\n", dir)
+	}
+	if project.APIKey != "" {
+		fmt.Printf("  set %s in .env\n", project.APIKey)
+	}
+	fmt.Printf("  agentsdk chat    # chat in the terminal\n")
+	fmt.Printf("  agentsdk run     # serve the agent with the UI\n")
+	fmt.Printf("  go mod tidy && go run .  # build the scaffolded server\n")
+	return nil
+}
+
+func writeTemplate(path string, tmpl *template.Template, data projectTemplate) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600) // #nosec G304 - Path is in the directory being scaffolded
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := tmpl.Execute(file, data); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,166 @@
+// Command agentsdk scaffolds, serves and chats with agents configured in
+// agents.yaml.
+//
+// Usage:
+//
+//	agentsdk init [dir]         scaffold a project with agents.yaml and main.go
+//	agentsdk run                serve an agent with the embedded UI
+//	agentsdk chat               chat with an agent in the terminal
+//	agentsdk tools list         list the tools of an agent
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+const version = "0.1.0"
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch command, args := os.Args[1], os.Args[2:]; command {
+	case "init":
+		err = initCommand(args)
+	case "run":
+		err = runCommand(args)
+	case "chat":
+		err = chatCommand(args)
+	case "tools":
+		err = toolsCommand(args)
+	case "version", "--version", "-v":
+		fmt.Printf("agentsdk v%s\n", version)
+	case "help", "--help", "-h":
+		printUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", command)
+		printUsage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Println(`agentsdk - scaffold, serve and chat with agents configured in YAML
+
+Usage:
+  agentsdk <command> [flags]
+
+Commands:
+  init [dir]    Scaffold a project with agents.yaml and main.go
+  run           Serve an agent over HTTP with the embedded UI
+  chat          Chat with an agent in the terminal
+  tools list    List the tools of an agent, including its MCP tools
+  version       Show the version
+
+Run 'agentsdk <command> -h' for the flags of a command.`)
+}
+
+// agentFlags are the flags of the commands that load an agent
+type agentFlags struct {
+	config string
+	name   string
+	vars   variables
+}
+
+// register registers the agent flags on a command's flag set
+func (f *agentFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "agents.yaml", "agent configuration file")
+	fs.StringVar(&f.name, "agent", "", "agent to load (default: the only agent of the file)")
+	fs.Var(&f.vars, "var", "template variable as key=value (repeatable)")
+}
+
+// load builds the selected agent of the configuration file
+func (f *agentFlags) load(options ...agent.Option) (*agent.Agent, error) {
+	options = append([]agent.Option{agent.WithMemory(memory.NewConversationBuffer())}, options...)
+	return f.loadWithModel("", options...)
+}
+
+// loadWithModel builds the selected agent of the configuration file with
+// its LLM provider's model replaced by model, unless it is empty
+func (f *agentFlags) loadWithModel(model string, options ...agent.Option) (*agent.Agent, error) {
+	_ = agent.LoadEnvFile(".env")
+
+	configs, err := agent.LoadAgentConfigsFromFile(f.config)
+	if err != nil {
+		return nil, err
+	}
+	name, err := selectAgent(configs, f.name)
+	if err != nil {
+		return nil, err
+	}
+	f.name = name
+
+	if model != "" {
+		config := configs[name]
+		if config.LLMProvider == nil {
+			return nil, fmt.Errorf("agent %s has no llm_provider to switch the model of", name)
+		}
+		provider := *config.LLMProvider
+		provider.Model = model
+		config.LLMProvider = &provider
+		configs[name] = config
+	}
+	return agent.NewAgentFromConfig(name, configs, f.vars, options...)
+}
+
+// selectAgent returns name, or the name of the only agent of configs
+func selectAgent(configs agent.AgentConfigs, name string) (string, error) {
+	names := make([]string, 0, len(configs))
+	for configName := range configs {
+		names = append(names, configName)
+	}
+	sort.Strings(names)
+
+	if name != "" {
+		if _, ok := configs[name]; !ok {
+			return "", fmt.Errorf("agent %s not found, available: %s", name, strings.Join(names, ", "))
+		}
+		return name, nil
+	}
+	switch len(names) {
+	case 0:
+		return "", fmt.Errorf("no agents configured")
+	case 1:
+		return names[0], nil
+	}
+	return "", fmt.Errorf("several agents configured, select one with -agent: %s", strings.Join(names, ", "))
+}
+
+// variables is a repeatable key=value flag
+type variables map[string]string
+
+func (v *variables) String() string {
+	pairs := make([]string, 0, len(*v))
+	for key, value := range *v {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (v *variables) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	if *v == nil {
+		*v = make(variables)
+	}
+	(*v)[key] = val
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/microservice"
+)
+
+// runCommand serves an agent from its YAML configuration with the embedded UI
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	var flags agentFlags
+	flags.register(fs)
+	port := fs.Int("port", 8080, "HTTP port")
+	noUI := fs.Bool("no-ui", false, "serve the API without the embedded UI")
+	watch := fs.Bool("watch", false, "reload the agent when the configuration file changes")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: agentsdk run [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	assistant, err := flags.load()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var server interface{ Start() error }
+	var httpServer *microservice.HTTPServer
+	if *noUI {
+		httpServer = microservice.NewHTTPServer(assistant, *port)
+		server = httpServer
+	} else {
+		uiServer := microservice.NewHTTPServerWithUI(assistant, *port, nil)
+		httpServer = &uiServer.HTTPServer
+		server = uiServer
+	}
+
+	if *watch {
+		err := httpServer.EnableConfigReload(ctx, microservice.ConfigReloadConfig{
+			Path:      flags.config,
+			AgentName: flags.name,
+			Variables: flags.vars,
+			Options:   []agent.Option{agent.WithMemory(assistant.GetMemory())},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Serving agent %s on http://localhost:%d\n", flags.name, *port)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Start()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return httpServer.Stop(context.Background())
+	}
+}
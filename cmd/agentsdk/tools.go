@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// toolsCommand runs the tools subcommands
+func toolsCommand(args []string) error {
+	if len(args) == 0 || args[0] != "list" {
+		return fmt.Errorf("usage: agentsdk tools list [flags]")
+	}
+
+	fs := flag.NewFlagSet("tools list", flag.ExitOnError)
+	var flags agentFlags
+	flags.register(fs)
+	verbose := fs.Bool("v", false, "show the parameters of each tool")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: agentsdk tools list [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	assistant, err := flags.load()
+	if err != nil {
+		return err
+	}
+	printTools(assistant, *verbose)
+	return nil
+}
+
+// printTools prints the tools of an agent, and those of its sub-agents
+func printTools(a *agent.Agent, verbose bool) {
+	tools := a.GetTools()
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name() < tools[j].Name()
+	})
+
+	fmt.Printf("Tools of %s (%d):\n", a.GetName(), len(tools))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, tool := range tools {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\n", tool.Name(), firstLine(tool.Description()))
+		if verbose {
+			printParameters(w, tool.Parameters())
+		}
+	}
+	_ = w.Flush()
+
+	for _, subAgent := range a.GetSubAgents() {
+		fmt.Println()
+		printTools(subAgent, verbose)
+	}
+}
+
+// printParameters prints the parameters of a tool, required ones first
+func printParameters(w *tabwriter.Writer, parameters map[string]interfaces.ParameterSpec) {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if parameters[names[i]].Required != parameters[names[j]].Required {
+			return parameters[names[i]].Required
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		spec := parameters[name]
+		required := ""
+		if spec.Required {
+			required = ", required"
+		}
+		_, _ = fmt.Fprintf(w, "    - %s (%v%s)\t%s\n", name, spec.Type, required, firstLine(spec.Description))
+	}
+}
+
+func firstLine(s string) string {
+	for i, r := range s {
+		if r == '\n' {
+			return s[:i]
+		}
+	}
+	return s
+}
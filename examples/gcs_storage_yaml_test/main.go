@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging/requestlog"
 	"github.com/Ingenimax/agent-sdk-go/pkg/microservice"
 	_ "github.com/Ingenimax/agent-sdk-go/pkg/storage/gcs" // Register GCS storage backend
 )
@@ -46,64 +47,15 @@ func main() {
 	}
 	fmt.Printf("Loaded %d agent configuration(s)\n", len(configs))
 
-	// Debug: Print config details
-	for name, cfg := range configs {
-		fmt.Printf("\n=== DEBUG: Agent Config '%s' ===\n", name)
-		fmt.Printf("  Role length: %d\n", len(cfg.Role))
-		fmt.Printf("  Goal length: %d\n", len(cfg.Goal))
-		fmt.Printf("  Backstory length: %d\n", len(cfg.Backstory))
-		if cfg.LLMProvider != nil {
-			fmt.Printf("  LLM Provider: %s\n", cfg.LLMProvider.Provider)
-			fmt.Printf("  LLM Model: %s\n", cfg.LLMProvider.Model)
-			if cfg.LLMProvider.Config != nil {
-				for k, v := range cfg.LLMProvider.Config {
-					if str, ok := v.(string); ok {
-						fmt.Printf("  LLM Config[%s] length: %d\n", k, len(str))
-					} else {
-						fmt.Printf("  LLM Config[%s]: %v\n", k, v)
-					}
-				}
-			}
-		}
-		if cfg.ImageGeneration != nil {
-			fmt.Printf("  ImageGen Provider: %s\n", cfg.ImageGeneration.Provider)
-			fmt.Printf("  ImageGen Model: %s\n", cfg.ImageGeneration.Model)
-			if cfg.ImageGeneration.Config != nil {
-				for k, v := range cfg.ImageGeneration.Config {
-					if str, ok := v.(string); ok {
-						fmt.Printf("  ImageGen Config[%s] length: %d\n", k, len(str))
-					} else {
-						fmt.Printf("  ImageGen Config[%s]: %v\n", k, v)
-					}
-				}
-			}
-			if cfg.ImageGeneration.Storage != nil {
-				fmt.Printf("  Storage Type: %s\n", cfg.ImageGeneration.Storage.Type)
-				if cfg.ImageGeneration.Storage.GCS != nil {
-					fmt.Printf("  GCS Bucket: %s\n", cfg.ImageGeneration.Storage.GCS.Bucket)
-					fmt.Printf("  GCS CredentialsJSON length: %d\n", len(cfg.ImageGeneration.Storage.GCS.CredentialsJSON))
-				}
-			}
-		}
-		fmt.Printf("=== END DEBUG ===\n\n")
-	}
+	// Log prompts, completions and tool calls as JSON lines
+	requestLog := requestlog.New(requestlog.NewStdoutSink())
 
 	// Create agent from YAML config
-	ag, err := agent.NewAgentFromConfig("image_generator", configs, nil)
+	ag, err := agent.NewAgentFromConfig("image_generator", configs, nil, agent.WithRequestLog(requestLog))
 	if err != nil {
 		log.Fatalf("Failed to create agent: %v", err)
 	}
 
-	// Debug: Print agent details after creation
-	fmt.Printf("\n=== DEBUG: Agent After Creation ===\n")
-	fmt.Printf("  Name: %s\n", ag.GetName())
-	fmt.Printf("  System Prompt length: %d\n", len(ag.GetSystemPrompt()))
-	fmt.Printf("  Tools count: %d\n", len(ag.GetTools()))
-	for _, tool := range ag.GetTools() {
-		fmt.Printf("    - Tool: %s\n", tool.Name())
-	}
-	fmt.Printf("=== END DEBUG ===\n\n")
-
 	fmt.Println("Agent created successfully")
 	fmt.Println()
 
@@ -83,83 +83,62 @@ func main() {
 
 	// Example 1: Person Information
 	fmt.Println("1. Person Information Example")
-	personFormat := structuredoutput.NewResponseFormat(Person{})
 
-	personResponse, err := ollamaClient.Generate(
+	person, err := structuredoutput.GenerateAs[Person](
 		ctx,
+		ollamaClient,
 		"Tell me about Albert Einstein",
-		ollama.WithResponseFormat(*personFormat),
 		ollama.WithTemperature(0.7),
 		ollama.WithSystemMessage("You are a helpful assistant that provides accurate biographical information."),
 	)
-
 	if err != nil {
 		logger.Error(ctx, "Failed to generate person info", map[string]interface{}{"error": err.Error()})
 	} else {
-		var person Person
-		if err := json.Unmarshal([]byte(personResponse), &person); err != nil {
-			fmt.Printf("Failed to unmarshal person response: %v\n", err)
-		} else {
-			fmt.Printf("Name: %s\nProfession: %s\nDescription: %s\n", person.Name, person.Profession, person.Description)
-			if len(person.Companies) > 0 {
-				fmt.Printf("Companies: %d companies listed\n", len(person.Companies))
-			}
-			if len(person.Hobbies) > 0 {
-				fmt.Printf("Hobbies: %v\n", person.Hobbies)
-			}
+		fmt.Printf("Name: %s\nProfession: %s\nDescription: %s\n", person.Name, person.Profession, person.Description)
+		if len(person.Companies) > 0 {
+			fmt.Printf("Companies: %d companies listed\n", len(person.Companies))
+		}
+		if len(person.Hobbies) > 0 {
+			fmt.Printf("Hobbies: %v\n", person.Hobbies)
 		}
 		fmt.Println()
 	}
 
 	// Example 2: Weather Information
 	fmt.Println("2. Weather Information Example")
-	weatherFormat := structuredoutput.NewResponseFormat(WeatherInfo{})
 
-	weatherResponse, err := ollamaClient.Generate(
+	weather, err := structuredoutput.GenerateAs[WeatherInfo](
 		ctx,
+		ollamaClient,
 		"Provide current weather information for Tokyo, Japan",
-		ollama.WithResponseFormat(*weatherFormat),
 		ollama.WithTemperature(0.5),
 		ollama.WithSystemMessage("You are a weather assistant. Provide realistic weather data."),
 	)
-
 	if err != nil {
 		logger.Error(ctx, "Failed to generate weather info", map[string]interface{}{"error": err.Error()})
 	} else {
-		var weather WeatherInfo
-		if err := json.Unmarshal([]byte(weatherResponse), &weather); err != nil {
-			fmt.Printf("Failed to unmarshal weather response: %v\n", err)
-		} else {
-			fmt.Printf("Location: %s\nTemperature: %.1f°C\nCondition: %s\nHumidity: %d%%\nWind Speed: %.1f km/h\n",
-				weather.Location, weather.Temperature, weather.Condition, weather.Humidity, weather.WindSpeed)
-		}
+		fmt.Printf("Location: %s\nTemperature: %.1f°C\nCondition: %s\nHumidity: %d%%\nWind Speed: %.1f km/h\n",
+			weather.Location, weather.Temperature, weather.Condition, weather.Humidity, weather.WindSpeed)
 		fmt.Println()
 	}
 
 	// Example 3: Programming Task
 	fmt.Println("3. Programming Task Example")
-	programmingFormat := structuredoutput.NewResponseFormat(ProgrammingTask{})
 
-	programmingResponse, err := ollamaClient.Generate(
+	task, err := structuredoutput.GenerateAs[ProgrammingTask](
 		ctx,
+		ollamaClient,
 		"Write a function to calculate the factorial of a number in Go",
-		ollama.WithResponseFormat(*programmingFormat),
 		ollama.WithTemperature(0.3),
 		ollama.WithSystemMessage("You are a programming expert. Provide clear, well-documented code."),
 	)
-
 	if err != nil {
 		logger.Error(ctx, "Failed to generate programming task", map[string]interface{}{"error": err.Error()})
 	} else {
-		var task ProgrammingTask
-		if err := json.Unmarshal([]byte(programmingResponse), &task); err != nil {
-			fmt.Printf("Failed to unmarshal programming response: %v\n", err)
-		} else {
-			fmt.Printf("Language: %s\nTask: %s\nComplexity: %s\nTags: %v\n",
-				task.Language, task.Task, task.Complexity, task.Tags)
-			fmt.Printf("Code:\n%s\n", task.Code)
-			fmt.Printf("Explanation: %s\n", task.Explanation)
-		}
+		fmt.Printf("Language: %s\nTask: %s\nComplexity: %s\nTags: %v\n",
+			task.Language, task.Task, task.Complexity, task.Tags)
+		fmt.Printf("Code:\n%s\n", task.Code)
+		fmt.Printf("Explanation: %s\n", task.Explanation)
 		fmt.Println()
 	}
 
@@ -167,6 +146,7 @@ func main() {
 	fmt.Println("4. Agent with Structured Output Example")
 
 	// Create an agent with structured output
+	personFormat := structuredoutput.NewResponseFormat(Person{})
 	agent, err := agent.NewAgent(
 		agent.WithLLM(ollamaClient),
 		agent.WithMemory(memory.NewConversationBuffer()),
@@ -1,5 +1,6 @@
 // Simple agent that connects to an MCP server over HTTP for testing.
-// Set OPENAI_API_KEY and ensure the MCP server at the URL is reachable.
+// Set OPENAI_API_KEY and MCP_SERVER_URL, and MCP_SERVER_TOKEN if the server
+// requires a bearer token.
 package main
 
 import (
@@ -18,6 +19,9 @@ func main() {
 	apiKey := os.Getenv("OPENAI_API_KEY")
 	baseURL := os.Getenv("OPENAI_BASE_URL")
 	mcpServerURL := os.Getenv("MCP_SERVER_URL")
+	if mcpServerURL == "" {
+		mcpServerURL = "http://localhost:8000/mcp"
+	}
 
 	llm := openai.NewClient(apiKey,
 		openai.WithModel("gemini-2.5-flash"),
@@ -28,7 +32,13 @@ func main() {
 		agent.WithLLM(llm),
 		agent.WithMemory(memory.NewConversationBuffer()),
 		agent.WithSystemPrompt("You are a helpful assistant with access to MCP tools. Use them when relevant."),
-		agent.WithMCPURLs(mcpServerURL),
+		agent.WithLazyMCPConfigs([]agent.LazyMCPConfig{{
+			Name:              "http-test",
+			Type:              "http",
+			URL:               mcpServerURL,
+			Token:             os.Getenv("MCP_SERVER_TOKEN"), // sent as "Authorization: Bearer <token>"
+			HttpTransportMode: "streamable",
+		}}),
 	)
 	if err != nil {
 		log.Fatalf("Failed to create agent: %v", err)
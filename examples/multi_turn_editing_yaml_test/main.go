@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging/requestlog"
 	"github.com/Ingenimax/agent-sdk-go/pkg/microservice"
 	_ "github.com/Ingenimax/agent-sdk-go/pkg/storage/gcs" // Register GCS storage backend
 )
@@ -46,52 +47,15 @@ func main() {
 	}
 	fmt.Printf("Loaded %d agent configuration(s)\n", len(configs))
 
-	// Debug: Print config details
-	for name, cfg := range configs {
-		fmt.Printf("\n=== DEBUG: Agent Config '%s' ===\n", name)
-		fmt.Printf("  Role: %s\n", truncate(cfg.Role, 50))
-		fmt.Printf("  Goal: %s\n", truncate(cfg.Goal, 50))
-		if cfg.LLMProvider != nil {
-			fmt.Printf("  LLM Provider: %s\n", cfg.LLMProvider.Provider)
-			fmt.Printf("  LLM Model: %s\n", cfg.LLMProvider.Model)
-		}
-		if cfg.ImageGeneration != nil {
-			fmt.Printf("  ImageGen Provider: %s\n", cfg.ImageGeneration.Provider)
-			fmt.Printf("  ImageGen Model: %s\n", cfg.ImageGeneration.Model)
-			if cfg.ImageGeneration.MultiTurnEditing != nil {
-				enabled := cfg.ImageGeneration.MultiTurnEditing.Enabled == nil || *cfg.ImageGeneration.MultiTurnEditing.Enabled
-				fmt.Printf("  Multi-Turn Editing Enabled: %v\n", enabled)
-				fmt.Printf("  Multi-Turn Model: %s\n", cfg.ImageGeneration.MultiTurnEditing.Model)
-				fmt.Printf("  Session Timeout: %s\n", cfg.ImageGeneration.MultiTurnEditing.SessionTimeout)
-				if cfg.ImageGeneration.MultiTurnEditing.MaxSessionsPerOrg != nil {
-					fmt.Printf("  Max Sessions Per Org: %d\n", *cfg.ImageGeneration.MultiTurnEditing.MaxSessionsPerOrg)
-				}
-			}
-			if cfg.ImageGeneration.Storage != nil {
-				fmt.Printf("  Storage Type: %s\n", cfg.ImageGeneration.Storage.Type)
-				if cfg.ImageGeneration.Storage.GCS != nil {
-					fmt.Printf("  GCS Bucket: %s\n", cfg.ImageGeneration.Storage.GCS.Bucket)
-				}
-			}
-		}
-		fmt.Printf("=== END DEBUG ===\n\n")
-	}
+	// Log prompts, completions and tool calls as JSON lines
+	requestLog := requestlog.New(requestlog.NewStdoutSink())
 
 	// Create agent from YAML config
-	ag, err := agent.NewAgentFromConfig("image_editor", configs, nil)
+	ag, err := agent.NewAgentFromConfig("image_editor", configs, nil, agent.WithRequestLog(requestLog))
 	if err != nil {
 		log.Fatalf("Failed to create agent: %v", err)
 	}
 
-	// Debug: Print agent details after creation
-	fmt.Printf("\n=== DEBUG: Agent After Creation ===\n")
-	fmt.Printf("  Name: %s\n", ag.GetName())
-	fmt.Printf("  Tools count: %d\n", len(ag.GetTools()))
-	for _, tool := range ag.GetTools() {
-		fmt.Printf("    - Tool: %s (%s)\n", tool.Name(), tool.Description()[:min(50, len(tool.Description()))]+"...")
-	}
-	fmt.Printf("=== END DEBUG ===\n\n")
-
 	fmt.Println("Agent created successfully")
 	fmt.Println()
 
@@ -193,17 +157,3 @@ func findConfigFile() string {
 
 	return "agents.yaml"
 }
-
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen] + "..."
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
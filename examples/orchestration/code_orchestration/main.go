@@ -17,6 +17,7 @@ import (
 	"github.com/Ingenimax/agent-sdk-go/pkg/orchestration"
 	"github.com/Ingenimax/agent-sdk-go/pkg/tools"
 	"github.com/Ingenimax/agent-sdk-go/pkg/tools/calculator"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/scratchpad"
 	"github.com/Ingenimax/agent-sdk-go/pkg/tools/websearch"
 )
 
@@ -42,6 +43,9 @@ func NewCustomCodeOrchestrator(registry *orchestration.AgentRegistry) *CustomCod
 func (o *CustomCodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *orchestration.Workflow) (string, error) {
 	log.Debug(ctx, "Using custom workflow executor with enhanced dependency handling", nil)
 
+	// Share the workflow scratchpad with the agents
+	ctx = scratchpad.WithStore(ctx, workflow.Scratchpad)
+
 	// Execute tasks in order (no parallelism for simplicity)
 	for _, task := range workflow.Tasks {
 		log.Debug(ctx, fmt.Sprintf("Executing task: %s (Agent: %s)", task.ID, task.AgentID), nil)
@@ -273,6 +277,8 @@ func createAndRegisterAgents(registry *orchestration.AgentRegistry, llm interfac
 		agent.WithSystemPrompt(`You are a research agent specialized in finding and summarizing information.
 You excel at answering factual questions and providing up-to-date information.
 
+Save your full findings, including sources, to the scratchpad under the key "research_findings".
+
 When you've completed your research, you should hand off to the summary agent.
 To hand off to the summary agent, respond with:
 [HANDOFF:summary:Here are my research findings: {your detailed research}]
@@ -314,8 +320,10 @@ Make sure your research is thorough and includes at least 5 key points before ha
 	summaryAgent, err := agent.NewAgent(
 		agent.WithLLM(llm),
 		agent.WithMemory(summaryMem),
+		agent.WithTools(scratchpad.New(nil)),
 		agent.WithSystemPrompt(`You are a summarization agent specialized in creating concise summaries.
 You will receive input that includes research findings in the format: "Result from research: [research content]"
+The full findings, with sources, may also be in the scratchpad under the key "research_findings".
 
 Your task is to extract the research content and create a well-structured summary.
 Your summary should include:
@@ -345,6 +353,9 @@ func createResearchTools() *tools.Registry {
 	)
 	toolRegistry.Register(searchTool)
 
+	// Add scratchpad tool to share findings with the other agents
+	toolRegistry.Register(scratchpad.New(nil))
+
 	return toolRegistry
 }
 
@@ -8,22 +8,33 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/genai"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/compliance"
 	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
 	"github.com/Ingenimax/agent-sdk-go/pkg/grpc/client"
+	"github.com/Ingenimax/agent-sdk-go/pkg/guardrails"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/llm/gemini"
 	"github.com/Ingenimax/agent-sdk-go/pkg/llm/openai"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/router"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/stability"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging/requestlog"
 	"github.com/Ingenimax/agent-sdk-go/pkg/mcp"
 	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/metrics"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+	"github.com/Ingenimax/agent-sdk-go/pkg/structuredoutput"
 	"github.com/Ingenimax/agent-sdk-go/pkg/tools"
 	"github.com/Ingenimax/agent-sdk-go/pkg/tools/imagegen"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/retriever"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/toolcache"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/toolselect"
 	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
 
 	// Import storage backends for side-effect registration
@@ -38,10 +49,13 @@ type LazyMCPConfig struct {
 	Args              []string
 	Env               []string
 	URL               string
-	Token             string // Bearer token for HTTP authentication
+	Token             string            // Bearer token for HTTP authentication
+	Headers           map[string]string // Headers added to HTTP requests, e.g. API keys
+	OAuth             *mcp.OAuthConfig  // OAuth authorization of HTTP servers
 	Tools             []LazyMCPToolConfig
-	HttpTransportMode string   // "sse" or "streamable"
-	AllowedTools      []string // List of allowed tool names for this MCP server
+	HttpTransportMode string               // "sse" or "streamable"
+	AllowedTools      []string             // List of allowed tool names for this MCP server
+	Reconnect         *mcp.ReconnectConfig // Reconnection and health checks (default: mcp.DefaultReconnectConfig)
 }
 
 // LazyMCPToolConfig holds configuration for a lazy MCP tool
@@ -64,40 +78,80 @@ type Agent struct {
 	datastore            interfaces.DataStore     // DataStore for persistent data storage (PostgreSQL, Supabase, etc.)
 	graphRAGStore        interfaces.GraphRAGStore // GraphRAG store for knowledge graph operations
 	tools                []interfaces.Tool
-	subAgents            []*Agent // Sub-agents that can be called as tools
+	toolsMu              sync.RWMutex // Guards tools registered at runtime with AddTool and RemoveTool
+	subAgents            []*Agent     // Sub-agents that can be called as tools
+	handoffs             []Handoff    // Agents the conversation can be transferred to
 	orgID                string
 	tracer               interfaces.Tracer
 	guardrails           interfaces.Guardrails
+	injectionDetector    *guardrails.InjectionDetector
 	logger               logging.Logger // Logger for the agent
 	systemPrompt         string
 	name                 string                   // Name of the agent, e.g., "PlatformOps", "Math", "Research"
 	description          string                   // Description of what the agent does
 	requirePlanApproval  bool                     // New field to control whether execution plans require approval
+	maxPlanReplans       int                      // Revisions of an executing plan after failed steps
 	planStore            *executionplan.Store     // Store for execution plans
 	planGenerator        *executionplan.Generator // Generator for execution plans
 	planExecutor         *executionplan.Executor  // Executor for execution plans
 	generatedAgentConfig *AgentConfig
 	generatedTaskConfigs TaskConfigs
 	responseFormat       *interfaces.ResponseFormat // Response format for the agent
+	structuredRetries    int                        // Repair retries for responses that do not match responseFormat
+	outputValidators     []OutputValidator          // Validators applied to final responses
+	outputRetries        int                        // Re-generations for responses rejected by outputValidators
+	toolLoopPolicy       *interfaces.ToolLoopPolicy // Policy of the tool-calling loop, see WithToolLoopPolicy
 	llmConfig            *interfaces.LLMConfig
 	mcpServers           []interfaces.MCPServer   // MCP servers for the agent
 	lazyMCPConfigs       []LazyMCPConfig          // Lazy MCP server configurations
+	mcpCatalog           *mcp.Catalog             // Resources and prompts of the MCP servers
+	mcpResourceTools     bool                     // Expose MCP resources through tools
 	maxIterations        int                      // Maximum number of tool-calling iterations (default: 2)
 	disableFinalSummary  bool                     // When true, skip the final summary LLM call
 	streamConfig         *interfaces.StreamConfig // Streaming configuration for the agent
 	cacheConfig          *interfaces.CacheConfig  // Prompt caching configuration (Anthropic only)
+	retriever            *retriever.Retriever     // Retriever for automatic context injection (RAG)
+	toolSelector         *toolselect.Selector     // Selects relevant tools per turn
+	toolsets             map[string][]string      // Tool names by toolset, see WithToolset
+	toolCache            *toolcache.Cache         // Caches the results of tool calls
+	validateToolArgs     bool                     // Validate tool arguments against their ParameterSpecs
+	toolOutputLimit      int                      // Maximum tokens of a tool result (0 = unlimited)
+	toolOutputPolicy     ToolOutputPolicy         // How tool results over toolOutputLimit are shortened
+	overflowPolicy       ContextOverflowPolicy    // What to do when a request exceeds the context window
+	contextWindow        int                      // Context window of the model in tokens (0 = tokens.ContextWindow)
+	modelRouter          *router.ModelRouter      // Routes runs to a cheap or premium model by complexity
+	runRecorder          *compliance.Recorder     // Records runs for compliance export
+	metrics              *metrics.Metrics         // Records Prometheus metrics for runs
+	requestLog           *requestlog.Logger       // Logs prompts, completions and tool calls
+	connectionWarmup     bool                     // Pre-warm LLM provider connections at startup
+	keepAliveInterval    time.Duration            // Interval for refreshing warm connections (0 = startup only)
+	stopKeepAlive        func()                   // Stops the connection keepalive routine
+	rephrase             RephraseFunc             // Rewrites inputs blocked by content filters
+	contentFilterRetries int                      // Maximum rephrased retries after a content-filter block
+	contentFilterStats   contentFilterStats       // Content-filter outcome counters
+	speechOutput         *speechOutput            // Synthesizes final responses as audio
+	artifactStore        storage.ArtifactStore    // Stores files produced by tools
+	approvalTools        map[string]bool          // Tools whose calls require approval
+	realtime             *realtimeOptions         // Provider and configuration of realtime sessions
+	hooks                []Hooks                  // Callbacks on the lifecycle of runs
+	seed                 *int                     // Sampling seed of deterministic mode
+	// Per-organization overrides resolved at run time
+	tenantConfigs *multitenancy.ConfigManager
 
 	// Runtime configuration fields
-	memoryConfig   map[string]interface{} // Memory configuration from YAML
-	timeout        time.Duration          // Agent timeout from runtime config
-	tracingEnabled bool                   // Whether tracing is enabled
-	metricsEnabled bool                   // Whether metrics are enabled
+	memoryConfig    map[string]interface{} // Memory configuration from YAML
+	knowledgeConfig *KnowledgeYAML         // Knowledge base configuration from YAML
+	timeout         time.Duration          // Agent timeout from runtime config
+	tracingEnabled  bool                   // Whether tracing is enabled
+	metricsEnabled  bool                   // Whether metrics are enabled
 
 	// Remote agent fields
-	isRemote      bool                      // Whether this is a remote agent
-	remoteURL     string                    // URL of the remote agent service
-	remoteTimeout time.Duration             // Timeout for remote agent operations
-	remoteClient  *client.RemoteAgentClient // gRPC client for remote communication
+	isRemote      bool                         // Whether this is a remote agent
+	remoteURL     string                       // URL of the remote agent service
+	remoteURLs    []string                     // URLs of the replicas of the remote agent service
+	remoteTimeout time.Duration                // Timeout for remote agent operations
+	loadBalancing client.LoadBalancingStrategy // Strategy spreading requests across the replicas
+	remoteClient  remoteAgentClient            // gRPC client for remote communication
 
 	// Custom function fields
 	customRunFunc       CustomRunFunction       // Custom run function to replace default behavior
@@ -163,6 +217,19 @@ func deduplicateTools(tools []interfaces.Tool) []interfaces.Tool {
 	return result
 }
 
+// availableTools leaves out tools that are temporarily unavailable, such as
+// the tools of an MCP server that lost its connection
+func availableTools(tools []interfaces.Tool) []interfaces.Tool {
+	result := make([]interfaces.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if t, ok := tool.(interfaces.ToolWithAvailability); ok && !t.Available() {
+			continue
+		}
+		result = append(result, tool)
+	}
+	return result
+}
+
 // WithOrgID sets the organization ID for multi-tenancy
 func WithOrgID(orgID string) Option {
 	return func(a *Agent) {
@@ -205,6 +272,15 @@ func WithRequirePlanApproval(require bool) Option {
 	}
 }
 
+// WithPlanReplanning revises an executing plan when one of its steps fails,
+// asking the LLM for new steps given the failure, up to maxReplans times per
+// execution, instead of failing the plan
+func WithPlanReplanning(maxReplans int) Option {
+	return func(a *Agent) {
+		a.maxPlanReplans = maxReplans
+	}
+}
+
 // WithName sets the name for the agent
 func WithName(name string) Option {
 	return func(a *Agent) {
@@ -259,6 +335,9 @@ func WithAgentConfig(config AgentConfig, variables map[string]string) Option {
 		if expandedConfig.RequirePlanApproval != nil {
 			a.requirePlanApproval = *expandedConfig.RequirePlanApproval
 		}
+		if expandedConfig.ToolLoop != nil {
+			a.toolLoopPolicy = expandedConfig.ToolLoop
+		}
 
 		// Apply complex configuration objects
 		if expandedConfig.StreamConfig != nil {
@@ -366,6 +445,9 @@ func WithAgentConfig(config AgentConfig, variables map[string]string) Option {
 					}
 				} else if imgTool != nil {
 					a.tools = deduplicateTools(append(a.tools, imgTool))
+					if editTool := createImageEditToolFromConfig(expandedConfig.ImageGeneration, imgTool); editTool != nil {
+						a.tools = deduplicateTools(append(a.tools, editTool))
+					}
 					if a.logger != nil {
 						a.logger.Info(context.Background(), "Successfully created image generation tool from YAML config", map[string]interface{}{
 							"multi_turn_enabled": multiTurnEnabled,
@@ -375,6 +457,11 @@ func WithAgentConfig(config AgentConfig, variables map[string]string) Option {
 			}
 		}
 
+		// Store knowledge base config for later ingestion (after logger and org are set)
+		if expandedConfig.Knowledge != nil {
+			a.knowledgeConfig = expandedConfig.Knowledge
+		}
+
 		// Apply runtime settings
 		if expandedConfig.Runtime != nil {
 			// TODO: Set log level if logger supports it when LogLevel is specified
@@ -457,6 +544,43 @@ func WithLLMConfig(config interfaces.LLMConfig) Option {
 	}
 }
 
+// WithDeterministic runs the agent in deterministic mode: its LLM calls use
+// temperature 0 and the given sampling seed, on the providers that support
+// one, so that repeated runs with the same input give the same response. The
+// seed is recorded in the metadata of the responses and run records.
+func WithDeterministic(seed int) Option {
+	return func(a *Agent) {
+		a.seed = &seed
+	}
+}
+
+// runSeed returns the sampling seed of the agent's LLM calls, if any
+func (a *Agent) runSeed() *int {
+	if a.seed != nil {
+		return a.seed
+	}
+	if a.llmConfig != nil {
+		return a.llmConfig.Seed
+	}
+	return nil
+}
+
+// deterministic pins the sampling of the LLM calls of a deterministic agent,
+// on a copy of the LLM config so that the agent's own is left untouched
+func (a *Agent) deterministic() interfaces.GenerateOption {
+	return func(options *interfaces.GenerateOptions) {
+		if a.seed == nil {
+			return
+		}
+		llmConfig := interfaces.LLMConfig{}
+		if options.LLMConfig != nil {
+			llmConfig = *options.LLMConfig
+		}
+		options.LLMConfig = &llmConfig
+		interfaces.WithDeterministic(*a.seed)(options)
+	}
+}
+
 // WithCacheConfig sets the prompt caching configuration for the agent (Anthropic only)
 func WithCacheConfig(config interfaces.CacheConfig) Option {
 	return func(a *Agent) {
@@ -513,8 +637,11 @@ func WithMCPURLs(urls ...string) Option {
 				Env:               config.Env,
 				URL:               config.URL,
 				Token:             config.Token,
+				Headers:           config.Headers,
+				OAuth:             config.OAuth,
 				HttpTransportMode: config.HttpTransportMode,
 				AllowedTools:      config.AllowedTools,
+				Reconnect:         config.Reconnect,
 			}
 			a.lazyMCPConfigs = append(a.lazyMCPConfigs, agentConfig)
 		}
@@ -551,8 +678,11 @@ func WithMCPPresets(presetNames ...string) Option {
 				Env:               config.Env,
 				URL:               config.URL,
 				Token:             config.Token,
+				Headers:           config.Headers,
+				OAuth:             config.OAuth,
 				HttpTransportMode: config.HttpTransportMode,
 				AllowedTools:      config.AllowedTools,
+				Reconnect:         config.Reconnect,
 			}
 			a.lazyMCPConfigs = append(a.lazyMCPConfigs, agentConfig)
 		}
@@ -573,6 +703,16 @@ func WithDisableFinalSummary(disable bool) Option {
 	}
 }
 
+// WithToolLoopPolicy sets the policy of the tool-calling loop: its maximum
+// iterations, the tools that end it, the prompt of the final call without
+// tools and what happens when it runs out of iterations. Its fields take
+// precedence over WithMaxIterations and WithDisableFinalSummary.
+func WithToolLoopPolicy(policy interfaces.ToolLoopPolicy) Option {
+	return func(a *Agent) {
+		a.toolLoopPolicy = &policy
+	}
+}
+
 // WithStreamConfig sets the streaming configuration for the agent
 func WithStreamConfig(config *interfaces.StreamConfig) Option {
 	return func(a *Agent) {
@@ -585,6 +725,7 @@ func WithURL(url string) Option {
 	return func(a *Agent) {
 		a.isRemote = true
 		a.remoteURL = url
+		a.remoteURLs = nil
 		// For remote agents, LLM is not required locally
 		a.llm = nil
 	}
@@ -631,6 +772,8 @@ func NewAgent(options ...Option) (*Agent, error) {
 	agent := &Agent{
 		requirePlanApproval: true, // Default to requiring approval
 		maxIterations:       2,    // Default to 2 iterations (current behavior)
+		structuredRetries:   structuredoutput.DefaultMaxRepairRetries,
+		outputRetries:       DefaultOutputValidationRetries,
 	}
 
 	for _, option := range options {
@@ -642,6 +785,32 @@ func NewAgent(options ...Option) (*Agent, error) {
 		agent.logger = logging.New()
 	}
 
+	// Record metrics when enabled by the runtime configuration
+	if agent.metricsEnabled && agent.metrics == nil {
+		agent.metrics = metrics.Default()
+	}
+
+	// Record LLM calls in the run audit trail when a run recorder is configured
+	if agent.runRecorder != nil && agent.llm != nil {
+		if _, recorded := agent.llm.(*compliance.RecordingLLM); !recorded {
+			agent.llm = compliance.NewRecordingLLM(agent.llm)
+		}
+	}
+
+	// Trace LLM calls when a tracer is configured, unless the LLM is already traced
+	if agent.tracer != nil && agent.llm != nil {
+		if _, traced := agent.llm.(*tracing.TracedLLM); !traced {
+			agent.llm = tracing.NewTracedLLM(agent.llm, agent.tracer)
+		}
+	}
+
+	// Log LLM calls when request logging is configured
+	if agent.requestLog != nil && agent.llm != nil {
+		if _, logged := agent.llm.(*requestlog.LoggedLLM); !logged {
+			agent.llm = requestlog.NewLoggedLLM(agent.llm, agent.requestLog)
+		}
+	}
+
 	// Create memory from config if specified and LLM is available
 	if agent.memoryConfig != nil && agent.llm != nil && agent.memory == nil {
 		memoryInstance, err := CreateMemoryFromConfig(agent.memoryConfig, agent.llm)
@@ -659,6 +828,13 @@ func NewAgent(options ...Option) (*Agent, error) {
 		}
 	}
 
+	// Ingest the knowledge base declared in YAML
+	if agent.knowledgeConfig != nil && !agent.isRemote {
+		if err := agent.applyKnowledgeConfig(agent.knowledgeConfig); err != nil {
+			return nil, err
+		}
+	}
+
 	// Different validation for local vs remote agents
 	if agent.isRemote {
 		return validateRemoteAgent(agent)
@@ -696,13 +872,21 @@ func validateLocalAgent(agent *Agent) (*Agent, error) {
 		agent.logger.Warn(context.Background(), fmt.Sprintf("Failed to initialize MCP tools: %v", err), nil)
 	}
 
-	// Get all tools (manual + MCP) for execution plan components
-	allTools := agent.getAllToolsSync()
+	// Get all tools (manual + MCP) for execution plan components, wrapped
+	// like the tools of other runs so that approval, guardrails and hooks
+	// apply to plan steps
+	allTools := agent.wrapTools(agent.getAllToolsSync(), nil)
 
 	// Initialize execution plan components
 	agent.planStore = executionplan.NewStore()
 	agent.planGenerator = executionplan.NewGenerator(agent.llm, allTools, agent.systemPrompt, agent.requirePlanApproval)
-	agent.planExecutor = executionplan.NewExecutor(allTools)
+	var executorOptions []executionplan.ExecutorOption
+	if agent.maxPlanReplans > 0 {
+		executorOptions = append(executorOptions, executionplan.WithReplanning(executionplan.ReplannerFunc(agent.replanExecutionPlan), agent.maxPlanReplans))
+	}
+	agent.planExecutor = executionplan.NewExecutor(allTools, executorOptions...)
+
+	agent.startConnectionWarmup()
 
 	return agent, nil
 }
@@ -715,15 +899,23 @@ func validateRemoteAgent(agent *Agent) (*Agent, error) {
 	}
 
 	// Initialize remote client
-	config := client.RemoteAgentConfig{
-		URL: agent.remoteURL,
-	}
-	// Use custom timeout if specified, otherwise the default 5 minutes will be used
-	// Special case: 0 means infinite timeout (no timeout)
-	if agent.remoteTimeout >= 0 {
-		config.Timeout = agent.remoteTimeout
+	if len(agent.remoteURLs) > 1 {
+		agent.remoteClient = client.NewRemoteAgentPool(client.RemoteAgentPoolConfig{
+			URLs:     agent.remoteURLs,
+			Strategy: agent.loadBalancing,
+			Timeout:  agent.remoteTimeout,
+		})
+	} else {
+		config := client.RemoteAgentConfig{
+			URL: agent.remoteURL,
+		}
+		// Use custom timeout if specified, otherwise the default 5 minutes will be used
+		// Special case: 0 means infinite timeout (no timeout)
+		if agent.remoteTimeout >= 0 {
+			config.Timeout = agent.remoteTimeout
+		}
+		agent.remoteClient = client.NewRemoteAgentClient(config)
 	}
-	agent.remoteClient = client.NewRemoteAgentClient(config)
 
 	// Test connection and fetch metadata
 	if err := agent.initializeRemoteAgent(); err != nil {
@@ -781,6 +973,10 @@ func NewAgentFromConfig(agentName string, configs AgentConfigs, variables map[st
 		return nil, fmt.Errorf("agent configuration for %s not found", agentName)
 	}
 
+	if _, err := ConvertYAMLSchemaToResponseFormat(config.ResponseFormat); err != nil {
+		return nil, fmt.Errorf("invalid response format for agent %s: %w", agentName, err)
+	}
+
 	// Add the agent config option
 	configOption := WithAgentConfig(config, variables)
 	nameOption := WithName(agentName)
@@ -802,7 +998,10 @@ func CreateAgentForTask(taskName string, agentConfigs AgentConfigs, taskConfigs
 	taskConfig := taskConfigs[taskName]
 	if taskConfig.ResponseFormat != nil {
 		responseFormat, err := ConvertYAMLSchemaToResponseFormat(taskConfig.ResponseFormat)
-		if err == nil && responseFormat != nil {
+		if err != nil {
+			return nil, fmt.Errorf("invalid response format for task %s: %w", taskName, err)
+		}
+		if responseFormat != nil {
 			options = append(options, WithResponseFormat(*responseFormat))
 		}
 	}
@@ -826,31 +1025,51 @@ func (a *Agent) RunDetailed(ctx context.Context, input string) (*interfaces.Agen
 func (a *Agent) runInternal(ctx context.Context, input string, detailed bool) (*interfaces.AgentResponse, error) {
 	startTime := time.Now()
 
-	tracker := newUsageTracker(detailed)
+	// Metrics, usage observers and hooks need the token usage even when the caller does not
+	tracker := newUsageTracker(detailed || a.metrics != nil || a.tenantConfigs != nil || len(a.hooks) > 0 || hasUsageObserver(ctx))
 	ctx = withUsageTracker(ctx, tracker)
+	ctx = a.withArtifacts(ctx)
 
-	var response string
+	ctx, run := a.startComplianceRun(ctx, input, false)
+	if err := a.hookRunStart(ctx, input); err != nil {
+		a.finishComplianceRun(ctx, run, "", err)
+		a.hookRunEnd(ctx, RunResult{Input: input}, err)
+		return nil, err
+	}
+
+	var response, handoffTo string
 	var err error
 
 	if a.customRunFunc != nil {
 		response, err = a.customRunFunc(ctx, input, a)
-		if err != nil {
-			return nil, err
-		}
 	} else if a.isRemote {
 		response, err = a.runRemoteWithTracking(ctx, input)
-		if err != nil {
-			return nil, err
-		}
 	} else {
+		var handoff *handoffState
+		ctx, handoff = a.withHandoff(ctx)
 		response, err = a.runLocalWithTracking(ctx, input)
-		if err != nil {
-			return nil, err
+		if handoff != nil && err == nil {
+			if to, reason := handoff.requested(); to != nil {
+				handoffTo = to.Agent.GetName()
+				response, err = a.handOff(ctx, to, reason, input)
+			}
 		}
 	}
+	a.recordRunMetrics(startTime, tracker, err)
+	notifyUsageObserver(ctx, tracker)
+	if err != nil {
+		a.finishComplianceRun(ctx, run, "", err)
+		a.hookRunEnd(ctx, RunResult{Input: input, Duration: time.Since(startTime)}, err)
+		return nil, err
+	}
 
 	tracker.setExecutionTime(time.Since(startTime).Milliseconds())
 	usage, execSummary, primaryModel := tracker.getResults()
+	if run != nil {
+		run.SetUsage(usage, execSummary, primaryModel)
+		a.finishComplianceRun(ctx, run, response, nil)
+	}
+	a.hookRunEnd(ctx, RunResult{Input: input, Response: response, Usage: usage, Duration: time.Since(startTime)}, nil)
 
 	var execSum interfaces.ExecutionSummary
 	if execSummary != nil {
@@ -882,37 +1101,70 @@ func (a *Agent) runInternal(ctx context.Context, input string, detailed bool) (*
 		log.Printf("[Agent SDK] Agent execution completed: %+v", executionDetails)
 	}
 
+	metadata := map[string]interface{}{
+		"agent_name":            a.name,
+		"execution_timestamp":   startTime.Unix(),
+		"execution_duration_ms": time.Since(startTime).Milliseconds(),
+	}
+	if run != nil {
+		metadata["run_id"] = run.ID()
+	}
+	if handoffTo != "" {
+		metadata[MetadataHandoffTo] = handoffTo
+	}
+	if seed := a.runSeed(); seed != nil {
+		metadata["seed"] = *seed
+	}
+	if detailed {
+		for key, value := range a.synthesizeResponse(ctx, response) {
+			metadata[key] = value
+		}
+	}
+
 	return &interfaces.AgentResponse{
 		Content:          response,
 		Usage:            usage,
 		AgentName:        a.name,
 		Model:            primaryModel,
 		ExecutionSummary: execSum,
-		Metadata: map[string]interface{}{
-			"agent_name":            a.name,
-			"execution_timestamp":   startTime.Unix(),
-			"execution_duration_ms": time.Since(startTime).Milliseconds(),
-		},
+		Metadata:         metadata,
+		Artifacts:        storage.CollectedArtifacts(ctx),
 	}, nil
 }
 
-func (a *Agent) runLocalWithTracking(ctx context.Context, input string) (string, error) {
+func (a *Agent) runLocalWithTracking(ctx context.Context, input string) (result string, err error) {
 	ctx = tracing.WithAgentName(ctx, a.name)
+	ctx = a.takeInputParts(ctx)
+	ctx = a.routeModel(ctx, input)
 
 	if a.orgID != "" {
 		ctx = multitenancy.WithOrgID(ctx, a.orgID)
 	}
 
+	ctx, err = a.applyTenantConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer a.chargeTenant(ctx)
+
 	var span interfaces.Span
 	if a.tracer != nil {
 		ctx, span = a.tracer.StartSpan(ctx, "agent.Run")
-		defer span.End()
+		span.SetAttribute(tracing.GenAIOperationName, tracing.GenAIOperationInvokeAgent)
+		span.SetAttribute(tracing.GenAIAgentName, a.name)
+		defer func() {
+			if err != nil {
+				span.RecordError(err)
+			}
+			span.End()
+		}()
 	}
 
 	if a.memory != nil {
 		if err := a.memory.AddMessage(ctx, interfaces.Message{
-			Role:    interfaces.MessageRoleUser,
-			Content: input,
+			Role:         interfaces.MessageRoleUser,
+			Content:      input,
+			ContentParts: a.inputParts(ctx),
 		}); err != nil {
 			return "", fmt.Errorf("failed to add user message to memory: %w", err)
 		}
@@ -950,7 +1202,7 @@ func (a *Agent) runLocalWithTracking(ctx context.Context, input string) (string,
 	// initializeMCPTools already populated a.tools, so re-collecting here can append duplicates;
 	// always run the merged slice through deduplicateTools to defend against that and against
 	// MCP servers re-listing tools they already exposed at startup.
-	allTools := a.tools
+	allTools := a.currentTools()
 
 	if len(a.mcpServers) > 0 {
 		mcpTools, err := a.collectMCPTools(ctx)
@@ -967,12 +1219,18 @@ func (a *Agent) runLocalWithTracking(ctx context.Context, input string) (string,
 		allTools = deduplicateTools(append(allTools, lazyMCPTools...))
 	}
 
+	// Keep running with the remaining tools while an MCP server is down
+	allTools = availableTools(allTools)
+	allTools = a.exposeToolsets(ctx, allTools)
+
 	if (len(allTools) > 0) && a.requirePlanApproval {
-		a.planGenerator = executionplan.NewGenerator(a.llm, allTools, a.systemPrompt, a.requirePlanApproval)
+		a.planGenerator = executionplan.NewGenerator(a.llm, a.wrapTools(allTools, getUsageTracker(ctx)), a.systemPrompt, a.requirePlanApproval)
 		return a.runWithExecutionPlan(ctx, input)
 	}
 
-	return a.runWithoutExecutionPlanWithToolsTracked(ctx, input, allTools)
+	return a.generateWithContentFilterRetry(ctx, input, func(ctx context.Context, input string) (string, error) {
+		return a.runWithoutExecutionPlanWithToolsTracked(ctx, input, a.selectTools(ctx, input, allTools))
+	})
 }
 
 func (a *Agent) RunWithAuth(ctx context.Context, input string, authToken string) (string, error) {
@@ -1123,6 +1381,11 @@ func (a *Agent) collectMCPTools(ctx context.Context) ([]interfaces.Tool, error)
 	var mcpTools []interfaces.Tool
 
 	for _, server := range a.mcpServers {
+		// Skip servers that are reconnecting
+		if checker, ok := server.(mcp.HealthChecker); ok && !checker.Healthy() {
+			continue
+		}
+
 		// List tools from this server
 		tools, err := server.ListTools(ctx)
 		if err != nil {
@@ -1149,17 +1412,7 @@ func (a *Agent) createLazyMCPTools() []interfaces.Tool {
 	for _, config := range a.lazyMCPConfigs {
 		a.logger.Info(context.Background(), fmt.Sprintf("Processing MCP config: %s (type: %s)", config.Name, config.Type), nil)
 		// Create lazy server config
-		lazyServerConfig := mcp.LazyMCPServerConfig{
-			Name:              config.Name,
-			Type:              config.Type,
-			Command:           config.Command,
-			Args:              config.Args,
-			Env:               config.Env,
-			URL:               config.URL,
-			Token:             config.Token,
-			HttpTransportMode: config.HttpTransportMode,
-			AllowedTools:      config.AllowedTools,
-		}
+		lazyServerConfig := config.serverConfig()
 
 		// If no specific tools are defined, discover all tools from the server
 		if len(config.Tools) == 0 {
@@ -1243,14 +1496,16 @@ func (a *Agent) createLazyMCPTools() []interfaces.Tool {
 
 func (a *Agent) runWithoutExecutionPlanWithToolsTracked(ctx context.Context, input string, tools []interfaces.Tool) (string, error) {
 	prompt := input
+	registered := a.currentTools()
 
 	var response string
 	var err error
 
 	generateOptions := []interfaces.GenerateOption{}
-	if a.systemPrompt != "" {
-		a.logger.Debug(context.Background(), fmt.Sprintf("Using system prompt (length=%d)", len(a.systemPrompt)), nil)
-		generateOptions = append(generateOptions, openai.WithSystemMessage(a.systemPrompt))
+	systemPrompt := a.systemPromptWithRetrievedContext(ctx, input)
+	if systemPrompt != "" {
+		a.logger.Debug(context.Background(), fmt.Sprintf("Using system prompt (length=%d)", len(systemPrompt)), nil)
+		generateOptions = append(generateOptions, openai.WithSystemMessage(systemPrompt))
 	} else {
 		a.logger.Warn(context.Background(), fmt.Sprintf("No system prompt set for agent %s", a.name), nil)
 	}
@@ -1264,12 +1519,25 @@ func (a *Agent) runWithoutExecutionPlanWithToolsTracked(ctx context.Context, inp
 			options.LLMConfig = a.llmConfig
 		})
 	}
+	generateOptions = append(generateOptions, a.deterministic(), applyOutputLimits(ctx), capTenantTemperature(ctx))
 
 	generateOptions = append(generateOptions, interfaces.WithMaxIterations(a.maxIterations))
 	generateOptions = append(generateOptions, interfaces.WithDisableFinalSummary(a.disableFinalSummary))
+	if a.toolLoopPolicy != nil {
+		generateOptions = append(generateOptions, interfaces.WithToolLoopPolicy(*a.toolLoopPolicy))
+	}
 
-	if a.memory != nil {
-		generateOptions = append(generateOptions, interfaces.WithMemory(a.memory))
+	if parts := a.inputParts(ctx); len(parts) > 0 {
+		generateOptions = append(generateOptions, interfaces.WithContentParts(parts...))
+	}
+
+	// Fit the history in the model's context window
+	memory, err := a.fitContextWindow(ctx, systemPrompt, prompt, tools)
+	if err != nil {
+		return "", err
+	}
+	if memory != nil {
+		generateOptions = append(generateOptions, interfaces.WithMemory(memory))
 	}
 
 	if a.cacheConfig != nil {
@@ -1280,10 +1548,20 @@ func (a *Agent) runWithoutExecutionPlanWithToolsTracked(ctx context.Context, inp
 
 	tracker := getUsageTracker(ctx)
 
+	if err := a.hookLLMCall(ctx, prompt, tools, false); err != nil {
+		return "", err
+	}
+
 	if len(tools) > 0 {
 		// Record tool invocations as the LLM actually calls them, not the
 		// full set of available tools (#305).
-		toolsForLLM := wrapToolsWithTracker(tools, tracker)
+		toolsForLLM := a.wrapTools(tools, tracker)
+
+		// Pick up tools registered or removed while the LLM calls tools
+		currentTools := a.toolsFunc(tools, registered)
+		generateOptions = append(generateOptions, interfaces.WithToolsFunc(func() []interfaces.Tool {
+			return a.wrapTools(a.exposeToolsets(ctx, currentTools()), tracker)
+		}))
 
 		if tracker != nil && tracker.detailed {
 			llmResp, err := a.llm.GenerateWithToolsDetailed(ctx, prompt, toolsForLLM, generateOptions...)
@@ -1298,6 +1576,13 @@ func (a *Agent) runWithoutExecutionPlanWithToolsTracked(ctx context.Context, inp
 				return "", fmt.Errorf("failed to generate response: %w", err)
 			}
 		}
+
+		if a.responseFormat != nil {
+			response, err = a.finalizeStructuredOutput(ctx, prompt, response, generateOptions)
+			if err != nil {
+				return "", err
+			}
+		}
 	} else {
 		if tracker != nil && tracker.detailed {
 			llmResp, err := a.llm.GenerateDetailed(ctx, prompt, generateOptions...)
@@ -1314,6 +1599,20 @@ func (a *Agent) runWithoutExecutionPlanWithToolsTracked(ctx context.Context, inp
 		}
 	}
 
+	if a.responseFormat != nil {
+		response, err = a.repairStructuredOutput(ctx, prompt, response, generateOptions)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(a.outputValidators) > 0 {
+		response, err = a.validateOutput(ctx, prompt, response, generateOptions)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// Apply guardrails to output if available
 	if a.guardrails != nil {
 		guardedResponse, err := a.guardrails.ProcessOutput(ctx, response)
@@ -1367,6 +1666,12 @@ func (a *Agent) handlePlanAction(ctx context.Context, taskID, action, input stri
 
 // approvePlan approves and executes a plan
 func (a *Agent) approvePlan(ctx context.Context, plan *executionplan.ExecutionPlan) (string, error) {
+	return a.executeApprovedPlan(ctx, plan, nil)
+}
+
+// executeApprovedPlan approves and executes a plan, reporting the progress
+// of its steps to onEvent when not nil
+func (a *Agent) executeApprovedPlan(ctx context.Context, plan *executionplan.ExecutionPlan, onEvent func(executionplan.StepEvent)) (string, error) {
 	plan.UserApproved = true
 	plan.Status = executionplan.StatusApproved
 
@@ -1381,7 +1686,7 @@ func (a *Agent) approvePlan(ctx context.Context, plan *executionplan.ExecutionPl
 	}
 
 	// Execute the plan
-	result, err := a.planExecutor.ExecutePlan(ctx, plan)
+	result, err := a.planExecutor.ExecutePlanWithProgress(ctx, plan, onEvent)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute plan: %w", err)
 	}
@@ -1399,6 +1704,12 @@ func (a *Agent) approvePlan(ctx context.Context, plan *executionplan.ExecutionPl
 	return result, nil
 }
 
+// replanExecutionPlan revises a plan after a failed step with the current
+// plan generator
+func (a *Agent) replanExecutionPlan(ctx context.Context, plan *executionplan.ExecutionPlan, failure executionplan.StepFailure) (*executionplan.ExecutionPlan, error) {
+	return a.planGenerator.ReplanExecutionPlan(ctx, plan, failure)
+}
+
 // modifyPlan modifies a plan based on user input
 func (a *Agent) modifyPlan(ctx context.Context, plan *executionplan.ExecutionPlan, input string) (string, error) {
 	// Add the modification request to memory
@@ -1740,7 +2051,7 @@ func (a *Agent) GetAllConversations(ctx context.Context) ([]string, error) {
 
 	// Check if memory supports conversation operations
 	if convMem, ok := a.memory.(interfaces.ConversationMemory); ok {
-		return convMem.GetAllConversations(ctx)
+		return convMem.GetAllConversations(a.memoryContext(ctx))
 	}
 
 	// Fallback: return empty list for memories that don't support conversations
@@ -1755,7 +2066,7 @@ func (a *Agent) GetConversationMessages(ctx context.Context, conversationID stri
 
 	// Check if memory supports conversation operations
 	if convMem, ok := a.memory.(interfaces.ConversationMemory); ok {
-		return convMem.GetConversationMessages(ctx, conversationID)
+		return convMem.GetConversationMessages(a.memoryContext(ctx), conversationID)
 	}
 
 	// Fallback: return empty list for memories that don't support conversations
@@ -1780,7 +2091,7 @@ func (a *Agent) GetMemoryStatistics(ctx context.Context) (totalConversations, to
 // GetTools returns the tools slice (for use in custom functions)
 func (a *Agent) GetTools() []interfaces.Tool {
 	// Return pre-initialized tools (manual + MCP tools already combined during agent creation)
-	return a.tools
+	return a.currentTools()
 }
 
 // GetSubAgents returns the sub-agents slice
@@ -1798,6 +2109,36 @@ func (a *Agent) GetTracer() interfaces.Tracer {
 	return a.tracer
 }
 
+// wrapTools wraps the tools passed to the LLM with result caching, output
+// limits, argument validation, usage tracking, guardrails, approval,
+// injection detection, hooks, tracing and metrics, as configured
+func (a *Agent) wrapTools(tools []interfaces.Tool, tracker *usageTracker) []interfaces.Tool {
+	tools = a.wrapToolsWithCache(tools)
+	tools = a.wrapToolsWithOutputLimit(tools)
+	tools = a.wrapToolsWithValidation(tools)
+	tools = wrapToolsWithTracker(tools, tracker)
+	tools = a.wrapToolsWithGuardrails(tools)
+	tools = a.wrapToolsWithApproval(tools)
+	tools = a.wrapToolsWithInjectionGuard(tools)
+	tools = a.wrapToolsWithHooks(tools)
+	tools = a.wrapToolsWithTracing(tools)
+	tools = a.wrapToolsWithRequestLog(tools)
+	return a.wrapToolsWithMetrics(tools)
+}
+
+// wrapToolsWithTracing wraps each tool so its executions are traced.
+// Returns the original slice unchanged when no tracer is configured.
+func (a *Agent) wrapToolsWithTracing(tools []interfaces.Tool) []interfaces.Tool {
+	if a.tracer == nil || len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = tracing.NewTracedTool(t, a.tracer)
+	}
+	return wrapped
+}
+
 // GetSystemPrompt returns the system prompt (for use in custom functions)
 func (a *Agent) GetSystemPrompt() string {
 	return a.systemPrompt
@@ -1865,8 +2206,13 @@ func (a *Agent) GetRemoteURL() string {
 	return a.remoteURL
 }
 
-// Disconnect closes the connection to a remote agent
+// Disconnect closes the connection to a remote agent and stops the
+// connection keepalive routine of local agents
 func (a *Agent) Disconnect() error {
+	if a.stopKeepAlive != nil {
+		a.stopKeepAlive()
+		a.stopKeepAlive = nil
+	}
 	if a.isRemote && a.remoteClient != nil {
 		return a.remoteClient.Disconnect()
 	}
@@ -1902,6 +2248,10 @@ func (a *Agent) GetRemoteMetadata() (map[string]string, error) {
 func (a *Agent) initializeMCPTools() error {
 	ctx := context.Background()
 
+	if len(a.mcpServers) > 0 || len(a.lazyMCPConfigs) > 0 {
+		a.initializeMCPCatalog()
+	}
+
 	// Initialize regular MCP tools if available
 	if len(a.mcpServers) > 0 {
 		mcpTools, err := a.collectMCPTools(ctx)
@@ -2061,9 +2411,21 @@ func createImageGenerationToolFromConfig(config *ImageGenerationYAML, logger log
 		provider = "gemini"
 	}
 
-	// Currently only Gemini is supported
-	if provider != "gemini" {
-		return nil, fmt.Errorf("unsupported image generation provider: %s (only 'gemini' is supported)", provider)
+	switch provider {
+	case "gemini":
+	case "openai", "stability":
+		generator, err := createImageGeneratorFromConfig(provider, config)
+		if err != nil {
+			return nil, err
+		}
+		if config.MultiTurnEditing != nil && logger != nil {
+			logger.Warn(ctx, "Multi-turn image editing is only supported with the gemini provider", map[string]interface{}{
+				"provider": provider,
+			})
+		}
+		return imagegen.New(generator, createImageStorageForTool(ctx, config, logger), imageToolOptionsFromConfig(config)...), nil
+	default:
+		return nil, fmt.Errorf("unsupported image generation provider: %s (supported: gemini, openai, stability)", provider)
 	}
 
 	// Determine model (default to gemini-2.5-flash-image)
@@ -2153,34 +2515,10 @@ func createImageGenerationToolFromConfig(config *ImageGenerationYAML, logger log
 	}
 
 	// Create storage backend
-	var imageStorage storage.ImageStorage
-	if config.Storage != nil {
-		imageStorage, err = createImageStorageFromConfig(config.Storage)
-		if err != nil {
-			if logger != nil {
-				logger.Warn(ctx, "Failed to create image storage, images will be returned as base64", map[string]interface{}{
-					"error": err.Error(),
-				})
-			}
-			// Continue without storage - tool will return base64 data
-		}
-	}
+	imageStorage := createImageStorageForTool(ctx, config, logger)
 
 	// Build tool options
-	var toolOptions []imagegen.Option
-
-	// Apply config options
-	if config.Config != nil {
-		if maxLen, ok := config.Config["max_prompt_length"].(int); ok {
-			toolOptions = append(toolOptions, imagegen.WithMaxPromptLength(maxLen))
-		}
-		if ratio, ok := config.Config["default_aspect_ratio"].(string); ok {
-			toolOptions = append(toolOptions, imagegen.WithDefaultAspectRatio(ratio))
-		}
-		if format, ok := config.Config["default_format"].(string); ok {
-			toolOptions = append(toolOptions, imagegen.WithDefaultFormat(format))
-		}
-	}
+	toolOptions := imageToolOptionsFromConfig(config)
 
 	// Check if multi-turn editing is enabled
 	if config.MultiTurnEditing != nil {
@@ -2265,6 +2603,105 @@ func createImageGenerationToolFromConfig(config *ImageGenerationYAML, logger log
 	return imgTool, nil
 }
 
+// createImageEditToolFromConfig returns an edit_image tool sharing the image
+// generation tool's provider and storage when edit_image is enabled
+func createImageEditToolFromConfig(config *ImageGenerationYAML, imgTool interfaces.Tool) interfaces.Tool {
+	if config.EditImage == nil || !*config.EditImage {
+		return nil
+	}
+	if tool, ok := imgTool.(*imagegen.Tool); ok {
+		if editTool := tool.EditTool(); editTool != nil {
+			return editTool
+		}
+	}
+	return nil
+}
+
+// createImageGeneratorFromConfig creates an OpenAI or Stability AI image generator from YAML configuration
+func createImageGeneratorFromConfig(provider string, config *ImageGenerationYAML) (interfaces.ImageGenerator, error) {
+	apiKey := ""
+	baseURL := ""
+	if config.Config != nil {
+		if key, ok := config.Config["api_key"].(string); ok {
+			apiKey = key
+		}
+		if url, ok := config.Config["base_url"].(string); ok {
+			baseURL = url
+		}
+	}
+
+	switch provider {
+	case "openai":
+		if apiKey == "" {
+			apiKey = os.Getenv("OPENAI_API_KEY")
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("credentials required for image generation: set OPENAI_API_KEY or config.api_key")
+		}
+		model := config.Model
+		if model == "" {
+			model = openai.DefaultImageModel
+		}
+		options := []openai.Option{openai.WithModel(model)}
+		if baseURL != "" {
+			options = append(options, openai.WithBaseURL(baseURL))
+		}
+		return openai.NewClient(apiKey, options...), nil
+
+	default:
+		if apiKey == "" {
+			apiKey = os.Getenv("STABILITY_API_KEY")
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("credentials required for image generation: set STABILITY_API_KEY or config.api_key")
+		}
+		var options []stability.Option
+		if config.Model != "" {
+			options = append(options, stability.WithModel(config.Model))
+		}
+		if baseURL != "" {
+			options = append(options, stability.WithBaseURL(baseURL))
+		}
+		return stability.NewClient(apiKey, options...), nil
+	}
+}
+
+// createImageStorageForTool creates the storage of an image generation tool.
+// Without storage, the tool returns images as base64.
+func createImageStorageForTool(ctx context.Context, config *ImageGenerationYAML, logger logging.Logger) storage.ImageStorage {
+	if config.Storage == nil {
+		return nil
+	}
+	imageStorage, err := createImageStorageFromConfig(config.Storage)
+	if err != nil {
+		if logger != nil {
+			logger.Warn(ctx, "Failed to create image storage, images will be returned as base64", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		// Continue without storage - tool will return base64 data
+		return nil
+	}
+	return imageStorage
+}
+
+// imageToolOptionsFromConfig returns the image generation tool options set in YAML configuration
+func imageToolOptionsFromConfig(config *ImageGenerationYAML) []imagegen.Option {
+	var toolOptions []imagegen.Option
+	if config.Config != nil {
+		if maxLen, ok := config.Config["max_prompt_length"].(int); ok {
+			toolOptions = append(toolOptions, imagegen.WithMaxPromptLength(maxLen))
+		}
+		if ratio, ok := config.Config["default_aspect_ratio"].(string); ok {
+			toolOptions = append(toolOptions, imagegen.WithDefaultAspectRatio(ratio))
+		}
+		if format, ok := config.Config["default_format"].(string); ok {
+			toolOptions = append(toolOptions, imagegen.WithDefaultFormat(format))
+		}
+	}
+	return toolOptions
+}
+
 // createImageStorageFromConfig creates an image storage backend from YAML configuration
 func createImageStorageFromConfig(config *ImageStorageYAML) (storage.ImageStorage, error) {
 	if config == nil {
@@ -2278,6 +2715,10 @@ func createImageStorageFromConfig(config *ImageStorageYAML) (storage.ImageStorag
 			storageType = "local"
 		} else if config.GCS != nil {
 			storageType = "gcs"
+		} else if config.S3 != nil {
+			storageType = "s3"
+		} else if config.AzureBlob != nil {
+			storageType = "azblob"
 		} else {
 			storageType = "local" // Default to local
 		}
@@ -2299,9 +2740,6 @@ func createImageStorageFromConfig(config *ImageStorageYAML) (storage.ImageStorag
 		if config.GCS == nil {
 			return nil, fmt.Errorf("GCS storage configuration is required when type is 'gcs'")
 		}
-		// Debug: log the credentials being passed
-		fmt.Printf("[createImageStorageFromConfig] GCS config: bucket=%s, prefix=%s, creds_json_len=%d, creds_file=%s\n",
-			config.GCS.Bucket, config.GCS.Prefix, len(config.GCS.CredentialsJSON), config.GCS.CredentialsFile)
 		gcsCfg := storage.GCSConfig{
 			Bucket:          config.GCS.Bucket,
 			Prefix:          config.GCS.Prefix,
@@ -2322,7 +2760,62 @@ func createImageStorageFromConfig(config *ImageStorageYAML) (storage.ImageStorag
 		}
 		return storage.NewGCSStorage(gcsCfg)
 
+	case "s3":
+		if config.S3 == nil {
+			return nil, fmt.Errorf("S3 storage configuration is required when type is 's3'")
+		}
+		s3Cfg := storage.S3Config{
+			Bucket:               config.S3.Bucket,
+			Prefix:               config.S3.Prefix,
+			Region:               config.S3.Region,
+			Endpoint:             config.S3.Endpoint,
+			UsePathStyle:         config.S3.UsePathStyle != nil && *config.S3.UsePathStyle,
+			AccessKeyID:          config.S3.AccessKeyID,
+			SecretAccessKey:      config.S3.SecretAccessKey,
+			ServerSideEncryption: config.S3.ServerSideEncryption,
+			KMSKeyID:             config.S3.KMSKeyID,
+		}
+		// Parse presigned URL expiration duration
+		if config.S3.PresignedURLExpiration != "" {
+			duration, err := time.ParseDuration(config.S3.PresignedURLExpiration)
+			if err != nil {
+				return nil, fmt.Errorf("invalid presigned_url_expiration format: %w", err)
+			}
+			s3Cfg.PresignedURLExpiration = duration
+			s3Cfg.UsePresignedURLs = true
+		}
+		if storage.NewS3Storage == nil {
+			return nil, fmt.Errorf("S3 storage backend not registered (import github.com/Ingenimax/agent-sdk-go/pkg/storage/s3)")
+		}
+		return storage.NewS3Storage(s3Cfg)
+
+	case "azblob":
+		if config.AzureBlob == nil {
+			return nil, fmt.Errorf("azure blob storage configuration is required when type is 'azblob'")
+		}
+		azblobCfg := storage.AzureBlobConfig{
+			ConnectionString: config.AzureBlob.ConnectionString,
+			AccountName:      config.AzureBlob.AccountName,
+			AccountKey:       config.AzureBlob.AccountKey,
+			Container:        config.AzureBlob.Container,
+			Prefix:           config.AzureBlob.Prefix,
+			Endpoint:         config.AzureBlob.Endpoint,
+		}
+		// Parse SAS expiration duration
+		if config.AzureBlob.SASExpiration != "" {
+			duration, err := time.ParseDuration(config.AzureBlob.SASExpiration)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sas_expiration format: %w", err)
+			}
+			azblobCfg.SASExpiration = duration
+			azblobCfg.UseSASURLs = true
+		}
+		if storage.NewAzureBlobStorage == nil {
+			return nil, fmt.Errorf("azure blob storage backend not registered (import github.com/Ingenimax/agent-sdk-go/pkg/storage/azblob)")
+		}
+		return storage.NewAzureBlobStorage(azblobCfg)
+
 	default:
-		return nil, fmt.Errorf("unsupported storage type: %s (only 'local' and 'gcs' are supported)", storageType)
+		return nil, fmt.Errorf("unsupported storage type: %s (supported: local, gcs, s3, azblob)", storageType)
 	}
 }
@@ -0,0 +1,136 @@
+// Package agenttest provides helpers to unit test agents: fake tools, a
+// recorder for tool invocations and assertions on tool calls and outputs.
+// Combined with the scriptable LLM of pkg/llm/mock, agents can be tested
+// without a provider:
+//
+//	llm := mock.New()
+//	llm.On(mock.PromptContains("weather")).CallTool("get_weather", `{"city":"Paris"}`).Respond("Sunny")
+//
+//	recorder := agenttest.NewRecorder()
+//	a, _ := agent.NewAgent(
+//		agent.WithLLM(llm),
+//		agent.WithTools(recorder.Wrap(agenttest.StaticTool("get_weather", "sunny"))...),
+//		agent.WithRequirePlanApproval(false),
+//	)
+//
+//	output := agenttest.Run(t, a, "What is the weather in Paris?")
+//	agenttest.AssertToolCalledWith(t, recorder, "get_weather", `{"city":"Paris"}`)
+//	agenttest.AssertOutputContains(t, output, "Sunny")
+package agenttest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ToolCall is a recorded tool invocation
+type ToolCall struct {
+	Name      string
+	Arguments string
+	Result    string
+	Error     error
+}
+
+// Recorder records the invocations of the tools it wraps
+type Recorder struct {
+	mu    sync.Mutex
+	calls []ToolCall
+}
+
+// NewRecorder creates a recorder without calls
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Wrap returns tools that record their invocations in the recorder
+func (r *Recorder) Wrap(tools ...interfaces.Tool) []interfaces.Tool {
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = &recordedTool{tool: tool, recorder: r}
+	}
+	return wrapped
+}
+
+// Calls returns the recorded calls in invocation order
+func (r *Recorder) Calls() []ToolCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ToolCall(nil), r.calls...)
+}
+
+// CallsTo returns the recorded calls of the named tool
+func (r *Recorder) CallsTo(name string) []ToolCall {
+	var calls []ToolCall
+	for _, call := range r.Calls() {
+		if call.Name == name {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset removes the recorded calls
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+func (r *Recorder) add(call ToolCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+// recordedTool records the executions of a tool
+type recordedTool struct {
+	tool     interfaces.Tool
+	recorder *Recorder
+}
+
+// Name implements interfaces.Tool.Name
+func (t *recordedTool) Name() string {
+	return t.tool.Name()
+}
+
+// Description implements interfaces.Tool.Description
+func (t *recordedTool) Description() string {
+	return t.tool.Description()
+}
+
+// Parameters implements interfaces.Tool.Parameters
+func (t *recordedTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.tool.Parameters()
+}
+
+// Run implements interfaces.Tool.Run
+func (t *recordedTool) Run(ctx context.Context, input string) (string, error) {
+	result, err := t.tool.Run(ctx, input)
+	t.recorder.add(ToolCall{Name: t.tool.Name(), Arguments: input, Result: result, Error: err})
+	return result, err
+}
+
+// Execute implements interfaces.Tool.Execute
+func (t *recordedTool) Execute(ctx context.Context, args string) (string, error) {
+	result, err := t.tool.Execute(ctx, args)
+	t.recorder.add(ToolCall{Name: t.tool.Name(), Arguments: args, Result: result, Error: err})
+	return result, err
+}
+
+// DisplayName forwards to the underlying tool when it implements ToolWithDisplayName
+func (t *recordedTool) DisplayName() string {
+	if d, ok := t.tool.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.tool.Name()
+}
+
+// Internal forwards to the underlying tool when it implements InternalTool
+func (t *recordedTool) Internal() bool {
+	if i, ok := t.tool.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
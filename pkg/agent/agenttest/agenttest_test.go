@@ -0,0 +1,95 @@
+package agenttest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent/agenttest"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+)
+
+func TestScriptedAgent(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.PromptContains("weather")).
+		CallTool("get_weather", `{"city": "Paris"}`).
+		RespondFunc(func(request mock.Request, results []mock.ToolResult) string {
+			return "The weather in Paris is " + results[0].Result
+		})
+
+	recorder := agenttest.NewRecorder()
+	a, err := agent.NewAgent(
+		agent.WithLLM(llm),
+		agent.WithTools(recorder.Wrap(
+			agenttest.StaticTool("get_weather", "sunny"),
+			agenttest.FailingTool("book_flight", errors.New("unavailable")),
+		)...),
+		agent.WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	output := agenttest.Run(t, a, "What is the weather in Paris?")
+
+	agenttest.AssertToolCalled(t, recorder, "get_weather")
+	agenttest.AssertToolCalledTimes(t, recorder, "get_weather", 1)
+	agenttest.AssertToolCalledWith(t, recorder, "get_weather", `{"city":"Paris"}`)
+	agenttest.AssertToolNotCalled(t, recorder, "book_flight")
+	agenttest.AssertToolCallOrder(t, recorder, "get_weather")
+	agenttest.AssertOutputEquals(t, output, "The weather in Paris is sunny")
+	agenttest.AssertOutputContains(t, output, "sunny")
+	agenttest.AssertOutputMatches(t, output, `^The weather in \w+ is sunny$`)
+
+	if err := llm.ExpectationsMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAssertionsReportFailures(t *testing.T) {
+	recorder := agenttest.NewRecorder()
+	tools := recorder.Wrap(agenttest.StaticTool("search", "result"))
+	_, _ = tools[0].Execute(context.Background(), `{"query":"go"}`)
+
+	checks := map[string]func(t testing.TB){
+		"not called":   func(t testing.TB) { agenttest.AssertToolCalled(t, recorder, "other") },
+		"called":       func(t testing.TB) { agenttest.AssertToolNotCalled(t, recorder, "search") },
+		"times":        func(t testing.TB) { agenttest.AssertToolCalledTimes(t, recorder, "search", 2) },
+		"arguments":    func(t testing.TB) { agenttest.AssertToolCalledWith(t, recorder, "search", `{"query":"rust"}`) },
+		"order":        func(t testing.TB) { agenttest.AssertToolCallOrder(t, recorder, "search", "search") },
+		"output equal": func(t testing.TB) { agenttest.AssertOutputEquals(t, "a", "b") },
+		"contains":     func(t testing.TB) { agenttest.AssertOutputContains(t, "abc", "d") },
+		"matches":      func(t testing.TB) { agenttest.AssertOutputMatches(t, "abc", `^b`) },
+	}
+	for name, check := range checks {
+		t.Run(name, func(t *testing.T) {
+			fake := &recordingTB{TB: t}
+			check(fake)
+			if !fake.failed {
+				t.Error("expected the assertion to fail")
+			}
+		})
+	}
+
+	recorder.Reset()
+	if len(recorder.Calls()) != 0 {
+		t.Error("expected no calls after Reset")
+	}
+}
+
+// recordingTB records failures instead of failing the test
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.failed = true
+}
@@ -0,0 +1,138 @@
+package agenttest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Runner is an agent that can be run with an input
+type Runner interface {
+	Run(ctx context.Context, input string) (string, error)
+}
+
+// Run runs agent with input and fails the test if it returns an error
+func Run(t testing.TB, agent Runner, input string) string {
+	t.Helper()
+
+	output, err := agent.Run(context.Background(), input)
+	if err != nil {
+		t.Fatalf("agent run failed: %v", err)
+	}
+	return output
+}
+
+// AssertToolCalled fails the test if the named tool was not called
+func AssertToolCalled(t testing.TB, recorder *Recorder, name string) {
+	t.Helper()
+
+	if len(recorder.CallsTo(name)) == 0 {
+		t.Errorf("expected tool %q to be called, called tools: %v", name, toolNames(recorder))
+	}
+}
+
+// AssertToolNotCalled fails the test if the named tool was called
+func AssertToolNotCalled(t testing.TB, recorder *Recorder, name string) {
+	t.Helper()
+
+	if calls := recorder.CallsTo(name); len(calls) > 0 {
+		t.Errorf("expected tool %q not to be called, called %d times", name, len(calls))
+	}
+}
+
+// AssertToolCalledTimes fails the test if the named tool was not called exactly n times
+func AssertToolCalledTimes(t testing.TB, recorder *Recorder, name string, n int) {
+	t.Helper()
+
+	if calls := recorder.CallsTo(name); len(calls) != n {
+		t.Errorf("expected tool %q to be called %d times, called %d times", name, n, len(calls))
+	}
+}
+
+// AssertToolCalledWith fails the test if the named tool was not called with
+// arguments. JSON arguments are compared by value, so key order and
+// whitespace do not matter.
+func AssertToolCalledWith(t testing.TB, recorder *Recorder, name, arguments string) {
+	t.Helper()
+
+	calls := recorder.CallsTo(name)
+	for _, call := range calls {
+		if sameArguments(call.Arguments, arguments) {
+			return
+		}
+	}
+
+	got := make([]string, len(calls))
+	for i, call := range calls {
+		got[i] = call.Arguments
+	}
+	t.Errorf("expected tool %q to be called with %s, got calls with %v", name, arguments, got)
+}
+
+// AssertToolCallOrder fails the test if the recorded tool calls, in order,
+// are not exactly names
+func AssertToolCallOrder(t testing.TB, recorder *Recorder, names ...string) {
+	t.Helper()
+
+	got := toolNames(recorder)
+	if len(got) == 0 && len(names) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, names) {
+		t.Errorf("expected tool calls %v, got %v", names, got)
+	}
+}
+
+// AssertOutputEquals fails the test if output is not expected, ignoring
+// surrounding whitespace
+func AssertOutputEquals(t testing.TB, output, expected string) {
+	t.Helper()
+
+	if strings.TrimSpace(output) != strings.TrimSpace(expected) {
+		t.Errorf("expected output %q, got %q", expected, output)
+	}
+}
+
+// AssertOutputContains fails the test if output does not contain substr
+func AssertOutputContains(t testing.TB, output, substr string) {
+	t.Helper()
+
+	if !strings.Contains(output, substr) {
+		t.Errorf("expected output to contain %q, got %q", substr, output)
+	}
+}
+
+// AssertOutputMatches fails the test if output does not match the regular
+// expression pattern
+func AssertOutputMatches(t testing.TB, output, pattern string) {
+	t.Helper()
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		t.Fatalf("invalid output pattern %q: %v", pattern, err)
+	}
+	if !re.MatchString(output) {
+		t.Errorf("expected output to match %q, got %q", pattern, output)
+	}
+}
+
+func toolNames(recorder *Recorder) []string {
+	var names []string
+	for _, call := range recorder.Calls() {
+		names = append(names, call.Name)
+	}
+	return names
+}
+
+// sameArguments compares arguments as JSON values when both parse, and as
+// strings otherwise
+func sameArguments(got, expected string) bool {
+	var gotValue, expectedValue interface{}
+	if json.Unmarshal([]byte(got), &gotValue) == nil && json.Unmarshal([]byte(expected), &expectedValue) == nil {
+		return reflect.DeepEqual(gotValue, expectedValue)
+	}
+	return strings.TrimSpace(got) == strings.TrimSpace(expected)
+}
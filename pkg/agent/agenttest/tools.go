@@ -0,0 +1,76 @@
+package agenttest
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Tool is a fake tool backed by a function
+type Tool struct {
+	name        string
+	description string
+	parameters  map[string]interfaces.ParameterSpec
+	run         func(ctx context.Context, args string) (string, error)
+}
+
+// NewTool creates a fake tool that answers calls with run
+func NewTool(name string, run func(ctx context.Context, args string) (string, error)) *Tool {
+	return &Tool{
+		name:        name,
+		description: "Test tool " + name,
+		parameters:  map[string]interfaces.ParameterSpec{},
+		run:         run,
+	}
+}
+
+// StaticTool creates a fake tool that always returns result
+func StaticTool(name, result string) *Tool {
+	return NewTool(name, func(context.Context, string) (string, error) {
+		return result, nil
+	})
+}
+
+// FailingTool creates a fake tool that always returns err
+func FailingTool(name string, err error) *Tool {
+	return NewTool(name, func(context.Context, string) (string, error) {
+		return "", err
+	})
+}
+
+// WithDescription sets the description of the tool
+func (t *Tool) WithDescription(description string) *Tool {
+	t.description = description
+	return t
+}
+
+// WithParameters sets the parameters of the tool
+func (t *Tool) WithParameters(parameters map[string]interfaces.ParameterSpec) *Tool {
+	t.parameters = parameters
+	return t
+}
+
+// Name implements interfaces.Tool.Name
+func (t *Tool) Name() string {
+	return t.name
+}
+
+// Description implements interfaces.Tool.Description
+func (t *Tool) Description() string {
+	return t.description
+}
+
+// Parameters implements interfaces.Tool.Parameters
+func (t *Tool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.parameters
+}
+
+// Run implements interfaces.Tool.Run
+func (t *Tool) Run(ctx context.Context, input string) (string, error) {
+	return t.run(ctx, input)
+}
+
+// Execute implements interfaces.Tool.Execute
+func (t *Tool) Execute(ctx context.Context, args string) (string, error) {
+	return t.run(ctx, args)
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// WithArtifactStore sets the store for files produced by tools. Tools find it
+// with storage.ArtifactStoreFromContext, and the artifacts saved during a
+// run are returned in the Artifacts of RunDetailed's response. Pass the same
+// store as the storage of media tools to list their output per conversation.
+func WithArtifactStore(store storage.ArtifactStore) Option {
+	return func(a *Agent) {
+		a.artifactStore = store
+	}
+}
+
+// GetArtifactStore returns the artifact store, if configured
+func (a *Agent) GetArtifactStore() storage.ArtifactStore {
+	return a.artifactStore
+}
+
+// MetadataArtifacts is the metadata key of the artifacts saved during a
+// stream, set in the metadata of its complete event
+const MetadataArtifacts = "artifacts"
+
+// withArtifacts returns a context collecting the artifacts attached during a
+// run and carrying the artifact store for tools
+func (a *Agent) withArtifacts(ctx context.Context) context.Context {
+	ctx = storage.WithArtifactCollector(ctx)
+	if a.artifactStore != nil {
+		ctx = storage.WithArtifactStore(ctx, a.artifactStore)
+	}
+	return ctx
+}
+
+// artifactStream adds the artifacts collected in ctx to the metadata of the
+// complete event of a stream
+func (a *Agent) artifactStream(ctx context.Context, events <-chan interfaces.AgentStreamEvent) <-chan interfaces.AgentStreamEvent {
+	out := make(chan interfaces.AgentStreamEvent, cap(events))
+	go func() {
+		defer close(out)
+
+		delivered := true
+		for event := range events {
+			if event.Type == interfaces.AgentEventComplete {
+				if artifacts := storage.CollectedArtifacts(ctx); artifacts != nil {
+					metadata := make(map[string]interface{}, len(event.Metadata)+1)
+					for key, value := range event.Metadata {
+						metadata[key] = value
+					}
+					metadata[MetadataArtifacts] = artifacts
+					event.Metadata = metadata
+				}
+			}
+			// Keep draining after cancellation so the producer can exit
+			if delivered {
+				delivered = sendEvent(ctx, out, event)
+			}
+		}
+	}()
+	return out
+}
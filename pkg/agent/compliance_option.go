@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/compliance"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// WithRunRecorder records every run (prompts, responses, tool I/O, usage and
+// trace IDs) so it can later be exported as a signed compliance bundle with
+// compliance.Exporter
+func WithRunRecorder(recorder *compliance.Recorder) Option {
+	return func(a *Agent) {
+		a.runRecorder = recorder
+	}
+}
+
+// GetRunRecorder returns the run recorder, if configured
+func (a *Agent) GetRunRecorder() *compliance.Recorder {
+	return a.runRecorder
+}
+
+// startComplianceRun starts recording a run when a recorder is configured.
+// The returned context carries the run so tool calls are recorded with it.
+func (a *Agent) startComplianceRun(ctx context.Context, input string, streaming bool) (context.Context, *compliance.Run) {
+	if a.runRecorder == nil {
+		return ctx, nil
+	}
+
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	info := compliance.RunInfo{
+		AgentName:     a.name,
+		SystemPrompt:  a.systemPrompt,
+		Input:         input,
+		ConfigVersion: a.configFingerprint(),
+		Streaming:     streaming,
+		Memory:        a.memory,
+	}
+	if a.llm != nil {
+		info.Model = a.llm.Name()
+		if modelProvider, ok := a.llm.(interface{ GetModel() string }); ok && modelProvider.GetModel() != "" {
+			info.Model = modelProvider.GetModel()
+		}
+	}
+
+	ctx, run := a.runRecorder.StartRun(ctx, info)
+	if seed := a.runSeed(); seed != nil {
+		run.SetMetadata("seed", *seed)
+	}
+	return ctx, run
+}
+
+// finishComplianceRun saves the run record. Failures are logged and never
+// fail the run itself.
+func (a *Agent) finishComplianceRun(ctx context.Context, run *compliance.Run, output string, err error) {
+	if run == nil {
+		return
+	}
+	if saveErr := run.Finish(ctx, output, err); saveErr != nil {
+		a.logger.Warn(ctx, "Failed to save compliance run record", map[string]interface{}{
+			"run_id": run.ID(),
+			"error":  saveErr.Error(),
+		})
+	}
+}
+
+// configFingerprint returns a short hash of the agent's YAML configuration,
+// or an empty string for agents built purely from options
+func (a *Agent) configFingerprint() string {
+	if a.generatedAgentConfig == nil {
+		return ""
+	}
+	config := *a.generatedAgentConfig
+	config.ConfigSource = nil // Load time and variables do not change behavior
+	data, err := json.Marshal(config)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/compliance"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestRunRecorderCapturesLLMCalls(t *testing.T) {
+	store := compliance.NewMemoryStore()
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithName("audited"),
+		WithRunRecorder(compliance.NewRecorder(store)),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "org-a")
+	if _, err := agent.Run(ctx, "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	runs, err := store.Query(ctx, compliance.RunQuery{OrgID: "org-a", AgentName: "audited"})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(runs))
+	}
+	if len(runs[0].LLMCalls) == 0 || runs[0].LLMCalls[0].Prompt == "" {
+		t.Errorf("expected the LLM calls to be recorded, got %+v", runs[0].LLMCalls)
+	}
+}
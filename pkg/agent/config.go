@@ -23,11 +23,14 @@ type ConfigSourceMetadata struct {
 	LoadedAt    time.Time         `yaml:"loaded_at" json:"loaded_at"`
 }
 
-// ResponseFormatConfig represents the configuration for the response format of an agent or task
+// ResponseFormatConfig represents the configuration for the response format of an agent or task.
+// SchemaRef names a schema registered with RegisterSchema, RegisterSchemaType or
+// defined under the top-level schemas key of a config file, instead of an inline definition.
 type ResponseFormatConfig struct {
 	Type             string                 `yaml:"type"`
 	SchemaName       string                 `yaml:"schema_name"`
 	SchemaDefinition map[string]interface{} `yaml:"schema_definition"`
+	SchemaRef        string                 `yaml:"schema_ref,omitempty"`
 }
 
 // AgentConfig represents the configuration for an agent loaded from YAML
@@ -43,6 +46,13 @@ type AgentConfig struct {
 	DisableFinalSummary *bool `yaml:"disable_final_summary,omitempty"`
 	RequirePlanApproval *bool `yaml:"require_plan_approval,omitempty"`
 
+	// AllowDelegation lets the agent delegate work to the other agents of its
+	// crew, see the crew package
+	AllowDelegation *bool `yaml:"allow_delegation,omitempty"`
+
+	// ToolLoop sets the policy of the tool-calling loop, see WithToolLoopPolicy
+	ToolLoop *interfaces.ToolLoopPolicy `yaml:"tool_loop,omitempty"`
+
 	// NEW: Complex configuration objects
 	StreamConfig *StreamConfigYAML `yaml:"stream_config,omitempty"`
 	LLMConfig    *LLMConfigYAML    `yaml:"llm_config,omitempty"`
@@ -63,6 +73,9 @@ type AgentConfig struct {
 	// NEW: Image generation configuration
 	ImageGeneration *ImageGenerationYAML `yaml:"image_generation,omitempty"`
 
+	// NEW: Knowledge base configuration (ingestion + retrieval)
+	Knowledge *KnowledgeYAML `yaml:"knowledge,omitempty"`
+
 	// NEW: Sub-agents configuration (recursive)
 	SubAgents map[string]AgentConfig `yaml:"sub_agents,omitempty"`
 
@@ -77,6 +90,10 @@ type TaskConfig struct {
 	Agent          string                `yaml:"agent"`
 	OutputFile     string                `yaml:"output_file,omitempty"`
 	ResponseFormat *ResponseFormatConfig `yaml:"response_format,omitempty"`
+
+	// DependsOn lists the tasks whose results the task receives when run by
+	// a crew
+	DependsOn []string `yaml:"depends_on,omitempty"`
 }
 
 // StreamConfigYAML represents streaming configuration in YAML
@@ -104,6 +121,9 @@ type LLMConfigYAML struct {
 	EnableReasoning  *bool    `yaml:"enable_reasoning,omitempty"`
 	ReasoningBudget  *int     `yaml:"reasoning_budget,omitempty"`
 	Reasoning        *string  `yaml:"reasoning,omitempty"`
+	MaxOutputTokens  *int     `yaml:"max_output_tokens,omitempty"`
+	Seed             *int     `yaml:"seed,omitempty"`
+	Deterministic    *bool    `yaml:"deterministic,omitempty"`
 }
 
 // LLMProviderYAML represents LLM provider configuration in YAML
@@ -122,8 +142,10 @@ type ToolConfigYAML struct {
 	Enabled     *bool                  `yaml:"enabled,omitempty"`
 
 	// For agent tools
-	URL     string `yaml:"url,omitempty"`     // Remote agent URL
-	Timeout string `yaml:"timeout,omitempty"` // Timeout duration
+	URL           string   `yaml:"url,omitempty"`            // Remote agent URL
+	URLs          []string `yaml:"urls,omitempty"`           // URLs of replicas of the remote agent
+	LoadBalancing string   `yaml:"load_balancing,omitempty"` // "round_robin" or "least_loaded"
+	Timeout       string   `yaml:"timeout,omitempty"`        // Timeout duration
 }
 
 // MemoryConfigYAML represents memory configuration in YAML
@@ -143,11 +165,12 @@ type RuntimeConfigYAML struct {
 // ImageGenerationYAML represents image generation configuration in YAML
 type ImageGenerationYAML struct {
 	Enabled          *bool                  `yaml:"enabled,omitempty"`
-	Provider         string                 `yaml:"provider,omitempty"` // "gemini"
-	Model            string                 `yaml:"model,omitempty"`    // e.g., "gemini-2.5-flash-image"
+	Provider         string                 `yaml:"provider,omitempty"` // "gemini", "openai" or "stability"
+	Model            string                 `yaml:"model,omitempty"`    // e.g., "gemini-2.5-flash-image", "gpt-image-1", "ultra"
 	Config           map[string]interface{} `yaml:"config,omitempty"`
 	Storage          *ImageStorageYAML      `yaml:"storage,omitempty"`
 	MultiTurnEditing *MultiTurnEditingYAML  `yaml:"multi_turn_editing,omitempty"`
+	EditImage        *bool                  `yaml:"edit_image,omitempty"` // Also add the edit_image tool
 }
 
 // MultiTurnEditingYAML represents multi-turn image editing configuration in YAML
@@ -160,9 +183,11 @@ type MultiTurnEditingYAML struct {
 
 // ImageStorageYAML represents image storage configuration in YAML
 type ImageStorageYAML struct {
-	Type  string            `yaml:"type,omitempty"` // "local", "gcs"
-	Local *LocalStorageYAML `yaml:"local,omitempty"`
-	GCS   *GCSStorageYAML   `yaml:"gcs,omitempty"`
+	Type      string                `yaml:"type,omitempty"` // "local", "gcs", "s3", "azblob"
+	Local     *LocalStorageYAML     `yaml:"local,omitempty"`
+	GCS       *GCSStorageYAML       `yaml:"gcs,omitempty"`
+	S3        *S3StorageYAML        `yaml:"s3,omitempty"`
+	AzureBlob *AzureBlobStorageYAML `yaml:"azblob,omitempty"`
 }
 
 // LocalStorageYAML represents local storage configuration in YAML
@@ -180,13 +205,80 @@ type GCSStorageYAML struct {
 	SignedURLExpiration string `yaml:"signed_url_expiration,omitempty"`
 }
 
+// S3StorageYAML represents Amazon S3 or S3-compatible (e.g. MinIO) storage configuration in YAML
+type S3StorageYAML struct {
+	Bucket                 string `yaml:"bucket,omitempty"`
+	Prefix                 string `yaml:"prefix,omitempty"`
+	Region                 string `yaml:"region,omitempty"`
+	Endpoint               string `yaml:"endpoint,omitempty"` // e.g., "http://localhost:9000" for MinIO
+	UsePathStyle           *bool  `yaml:"use_path_style,omitempty"`
+	AccessKeyID            string `yaml:"access_key_id,omitempty"`
+	SecretAccessKey        string `yaml:"secret_access_key,omitempty"`
+	ServerSideEncryption   string `yaml:"server_side_encryption,omitempty"` // "AES256" or "aws:kms"
+	KMSKeyID               string `yaml:"kms_key_id,omitempty"`
+	PresignedURLExpiration string `yaml:"presigned_url_expiration,omitempty"` // e.g., "24h"
+}
+
+// AzureBlobStorageYAML represents Azure Blob Storage configuration in YAML
+type AzureBlobStorageYAML struct {
+	ConnectionString string `yaml:"connection_string,omitempty"`
+	AccountName      string `yaml:"account_name,omitempty"`
+	AccountKey       string `yaml:"account_key,omitempty"`
+	Container        string `yaml:"container,omitempty"`
+	Prefix           string `yaml:"prefix,omitempty"`
+	Endpoint         string `yaml:"endpoint,omitempty"`       // e.g., "http://127.0.0.1:10000/devstoreaccount1" for Azurite
+	SASExpiration    string `yaml:"sas_expiration,omitempty"` // e.g., "24h"
+}
+
+// KnowledgeYAML represents knowledge base configuration in YAML
+type KnowledgeYAML struct {
+	Enabled      *bool                 `yaml:"enabled,omitempty"`
+	Sources      []KnowledgeSourceYAML `yaml:"sources,omitempty"`
+	Embedding    *EmbeddingYAML        `yaml:"embedding,omitempty"`
+	VectorStore  *VectorStoreYAML      `yaml:"vector_store,omitempty"`
+	ChunkSize    *int                  `yaml:"chunk_size,omitempty"`
+	ChunkOverlap *int                  `yaml:"chunk_overlap,omitempty"`
+	TopK         *int                  `yaml:"top_k,omitempty"`
+	TokenBudget  *int                  `yaml:"token_budget,omitempty"`
+	MinScore     *float64              `yaml:"min_score,omitempty"`
+	AutoInject   *bool                 `yaml:"auto_inject,omitempty"`    // Inject retrieved context before LLM calls (default: true)
+	ExposeAsTool *bool                 `yaml:"expose_as_tool,omitempty"` // Register the retriever as a tool (default: true)
+}
+
+// KnowledgeSourceYAML represents a knowledge source in YAML
+type KnowledgeSourceYAML struct {
+	Type       string   `yaml:"type"` // "file", "directory", "url", "text"
+	Path       string   `yaml:"path,omitempty"`
+	URL        string   `yaml:"url,omitempty"`
+	Content    string   `yaml:"content,omitempty"`
+	Name       string   `yaml:"name,omitempty"`
+	Extensions []string `yaml:"extensions,omitempty"`
+}
+
+// EmbeddingYAML represents embedding model configuration in YAML
+type EmbeddingYAML struct {
+	Provider string                 `yaml:"provider,omitempty"` // "openai", "gemini"
+	Model    string                 `yaml:"model,omitempty"`
+	Config   map[string]interface{} `yaml:"config,omitempty"`
+}
+
+// VectorStoreYAML represents vector store configuration in YAML
+type VectorStoreYAML struct {
+	Type   string                 `yaml:"type,omitempty"` // "memory", "weaviate"
+	Class  string                 `yaml:"class,omitempty"`
+	Config map[string]interface{} `yaml:"config,omitempty"`
+}
+
 // AgentConfigs represents a map of agent configurations
 type AgentConfigs map[string]AgentConfig
 
 // TaskConfigs represents a map of task configurations
 type TaskConfigs map[string]TaskConfig
 
-// LoadAgentConfigsFromFile loads agent configurations from a YAML file
+// LoadAgentConfigsFromFile loads agent configurations from a YAML file.
+// ${VAR} and ${VAR:-default} in its values are replaced with environment
+// variables, and values that are secret references (gcp-secret://,
+// aws-sm://, vault://) with the secrets they reference.
 func LoadAgentConfigsFromFile(filePath string) (AgentConfigs, error) {
 	// Validate file path
 	if !isValidFilePath(filePath) {
@@ -199,10 +291,20 @@ func LoadAgentConfigsFromFile(filePath string) (AgentConfigs, error) {
 		return nil, fmt.Errorf("failed to read agent config file: %w", err)
 	}
 
+	data, err = expandConfigFile(context.Background(), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand agent config file %s: %w", filePath, err)
+	}
+
+	if err := registerSchemasFromYAML(data); err != nil {
+		return nil, err
+	}
+
 	var configs AgentConfigs
 	if err := yaml.Unmarshal(data, &configs); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal agent configs: %w", err)
 	}
+	delete(configs, schemasKey)
 
 	return configs, nil
 }
@@ -303,10 +405,15 @@ func LoadTaskConfigsFromFile(filePath string) (TaskConfigs, error) {
 		return nil, fmt.Errorf("failed to read task config file: %w", err)
 	}
 
+	if err := registerSchemasFromYAML(data); err != nil {
+		return nil, err
+	}
+
 	var configs TaskConfigs
 	if err := yaml.Unmarshal(data, &configs); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal task configs: %w", err)
 	}
+	delete(configs, schemasKey)
 
 	return configs, nil
 }
@@ -509,6 +616,17 @@ func ConvertYAMLSchemaToResponseFormat(config *ResponseFormatConfig) (*interface
 		return nil, nil
 	}
 
+	if config.SchemaRef != "" {
+		format, ok := LookupSchema(config.SchemaRef)
+		if !ok {
+			return nil, fmt.Errorf("response format references unknown schema %s", config.SchemaRef)
+		}
+		if config.SchemaName != "" {
+			format.Name = config.SchemaName
+		}
+		return format, nil
+	}
+
 	schema := interfaces.JSONSchema(config.SchemaDefinition)
 	return &interfaces.ResponseFormat{
 		Type:   interfaces.ResponseFormatType(config.Type),
@@ -601,13 +719,17 @@ func ExpandAgentConfig(config AgentConfig) AgentConfig {
 		expandedTools := make([]ToolConfigYAML, len(config.Tools))
 		for i, tool := range config.Tools {
 			expandedTools[i] = ToolConfigYAML{
-				Type:        tool.Type,
-				Name:        tool.Name,
-				Description: expandWithConfigVars(tool.Description, configVars),
-				Config:      expandConfigMap(tool.Config, configVars),
-				Enabled:     tool.Enabled,
-				URL:         expandWithConfigVars(tool.URL, configVars),
-				Timeout:     expandWithConfigVars(tool.Timeout, configVars),
+				Type:          tool.Type,
+				Name:          tool.Name,
+				Description:   expandWithConfigVars(tool.Description, configVars),
+				Config:        expandConfigMap(tool.Config, configVars),
+				Enabled:       tool.Enabled,
+				URL:           expandWithConfigVars(tool.URL, configVars),
+				LoadBalancing: expandWithConfigVars(tool.LoadBalancing, configVars),
+				Timeout:       expandWithConfigVars(tool.Timeout, configVars),
+			}
+			for _, url := range tool.URLs {
+				expandedTools[i].URLs = append(expandedTools[i].URLs, expandWithConfigVars(url, configVars))
 			}
 		}
 		expanded.Tools = expandedTools
@@ -651,10 +773,11 @@ func ExpandAgentConfig(config AgentConfig) AgentConfig {
 	// Expand image generation configuration
 	if config.ImageGeneration != nil {
 		expanded.ImageGeneration = &ImageGenerationYAML{
-			Enabled:  config.ImageGeneration.Enabled,
-			Provider: expandWithConfigVars(config.ImageGeneration.Provider, configVars),
-			Model:    expandWithConfigVars(config.ImageGeneration.Model, configVars),
-			Config:   expandConfigMap(config.ImageGeneration.Config, configVars),
+			Enabled:   config.ImageGeneration.Enabled,
+			Provider:  expandWithConfigVars(config.ImageGeneration.Provider, configVars),
+			Model:     expandWithConfigVars(config.ImageGeneration.Model, configVars),
+			Config:    expandConfigMap(config.ImageGeneration.Config, configVars),
+			EditImage: config.ImageGeneration.EditImage,
 		}
 		if config.ImageGeneration.Storage != nil {
 			expanded.ImageGeneration.Storage = &ImageStorageYAML{
@@ -675,6 +798,31 @@ func ExpandAgentConfig(config AgentConfig) AgentConfig {
 					SignedURLExpiration: expandWithConfigVars(config.ImageGeneration.Storage.GCS.SignedURLExpiration, configVars),
 				}
 			}
+			if s3 := config.ImageGeneration.Storage.S3; s3 != nil {
+				expanded.ImageGeneration.Storage.S3 = &S3StorageYAML{
+					Bucket:                 expandWithConfigVars(s3.Bucket, configVars),
+					Prefix:                 expandWithConfigVars(s3.Prefix, configVars),
+					Region:                 expandWithConfigVars(s3.Region, configVars),
+					Endpoint:               expandWithConfigVars(s3.Endpoint, configVars),
+					UsePathStyle:           s3.UsePathStyle,
+					AccessKeyID:            expandWithConfigVars(s3.AccessKeyID, configVars),
+					SecretAccessKey:        expandWithConfigVars(s3.SecretAccessKey, configVars),
+					ServerSideEncryption:   expandWithConfigVars(s3.ServerSideEncryption, configVars),
+					KMSKeyID:               expandWithConfigVars(s3.KMSKeyID, configVars),
+					PresignedURLExpiration: expandWithConfigVars(s3.PresignedURLExpiration, configVars),
+				}
+			}
+			if azblob := config.ImageGeneration.Storage.AzureBlob; azblob != nil {
+				expanded.ImageGeneration.Storage.AzureBlob = &AzureBlobStorageYAML{
+					ConnectionString: expandWithConfigVars(azblob.ConnectionString, configVars),
+					AccountName:      expandWithConfigVars(azblob.AccountName, configVars),
+					AccountKey:       expandWithConfigVars(azblob.AccountKey, configVars),
+					Container:        expandWithConfigVars(azblob.Container, configVars),
+					Prefix:           expandWithConfigVars(azblob.Prefix, configVars),
+					Endpoint:         expandWithConfigVars(azblob.Endpoint, configVars),
+					SASExpiration:    expandWithConfigVars(azblob.SASExpiration, configVars),
+				}
+			}
 		}
 		// Expand multi-turn editing configuration
 		if config.ImageGeneration.MultiTurnEditing != nil {
@@ -687,6 +835,37 @@ func ExpandAgentConfig(config AgentConfig) AgentConfig {
 		}
 	}
 
+	// Expand knowledge configuration
+	if config.Knowledge != nil {
+		knowledge := *config.Knowledge
+		knowledge.Sources = make([]KnowledgeSourceYAML, len(config.Knowledge.Sources))
+		for i, source := range config.Knowledge.Sources {
+			knowledge.Sources[i] = KnowledgeSourceYAML{
+				Type:       source.Type,
+				Path:       expandWithConfigVars(source.Path, configVars),
+				URL:        expandWithConfigVars(source.URL, configVars),
+				Content:    source.Content,
+				Name:       expandWithConfigVars(source.Name, configVars),
+				Extensions: source.Extensions,
+			}
+		}
+		if config.Knowledge.Embedding != nil {
+			knowledge.Embedding = &EmbeddingYAML{
+				Provider: expandWithConfigVars(config.Knowledge.Embedding.Provider, configVars),
+				Model:    expandWithConfigVars(config.Knowledge.Embedding.Model, configVars),
+				Config:   expandConfigMap(config.Knowledge.Embedding.Config, configVars),
+			}
+		}
+		if config.Knowledge.VectorStore != nil {
+			knowledge.VectorStore = &VectorStoreYAML{
+				Type:   expandWithConfigVars(config.Knowledge.VectorStore.Type, configVars),
+				Class:  expandWithConfigVars(config.Knowledge.VectorStore.Class, configVars),
+				Config: expandConfigMap(config.Knowledge.VectorStore.Config, configVars),
+			}
+		}
+		expanded.Knowledge = &knowledge
+	}
+
 	// Expand MCP configuration
 	if config.MCP != nil {
 		expandedMCP := &MCPConfiguration{
@@ -713,6 +892,14 @@ func ExpandAgentConfig(config AgentConfig) AgentConfig {
 				expandedServerConfig.Env[key] = expandWithConfigVars(value, configVars)
 			}
 
+			// Expand headers
+			if len(serverConfig.Headers) > 0 {
+				expandedServerConfig.Headers = make(map[string]string, len(serverConfig.Headers))
+				for key, value := range serverConfig.Headers {
+					expandedServerConfig.Headers[key] = expandWithConfigVars(value, configVars)
+				}
+			}
+
 			expandedMCP.MCPServers[serverName] = expandedServerConfig
 		}
 
@@ -797,6 +984,17 @@ func convertLLMConfigYAMLToInterface(config *LLMConfigYAML) *interfaces.LLMConfi
 	if config.Reasoning != nil {
 		llmConfig.Reasoning = *config.Reasoning
 	}
+	if config.MaxOutputTokens != nil {
+		llmConfig.MaxOutputTokens = *config.MaxOutputTokens
+	}
+	if config.Seed != nil {
+		seed := *config.Seed
+		llmConfig.Seed = &seed
+	}
+	if config.Deterministic != nil && *config.Deterministic {
+		llmConfig.Temperature = 0
+		llmConfig.Deterministic = true
+	}
 
 	return llmConfig
 }
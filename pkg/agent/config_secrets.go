@@ -0,0 +1,347 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"golang.org/x/oauth2/google"
+	"gopkg.in/yaml.v3"
+)
+
+// SecretResolver resolves the path of a secret reference, the part between
+// "<scheme>://" and the optional "#field", to the secret's value
+type SecretResolver func(ctx context.Context, path string) (string, error)
+
+var (
+	secretResolversMu sync.RWMutex
+	secretResolvers   = map[string]SecretResolver{
+		"gcp-secret": resolveGCPSecret,
+		"aws-sm":     resolveAWSSecret,
+		"vault":      resolveVaultSecret,
+	}
+)
+
+// secretTimeout bounds the resolution of each secret reference
+const secretTimeout = 30 * time.Second
+
+// gcpSecretManagerURL is the endpoint of the Google Secret Manager API
+var gcpSecretManagerURL = "https://secretmanager.googleapis.com"
+
+// RegisterSecretResolver registers the resolver of the secret references of
+// a scheme, replacing any existing one. Built-in resolvers handle
+// "gcp-secret", "aws-sm" and "vault"; register others for other secret
+// managers, or to stub secrets in tests.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolversMu.Lock()
+	defer secretResolversMu.Unlock()
+	secretResolvers[scheme] = resolver
+}
+
+// ResolveSecretRef resolves a value of the form "<scheme>://<path>[#field]"
+// with the resolver registered for its scheme. With a field, the secret must
+// be a JSON object and the field's value is returned. Other values are
+// returned unchanged.
+func ResolveSecretRef(ctx context.Context, value string) (string, error) {
+	scheme, rest, ok := strings.Cut(value, "://")
+	if !ok {
+		return value, nil
+	}
+	secretResolversMu.RLock()
+	resolver, ok := secretResolvers[scheme]
+	secretResolversMu.RUnlock()
+	if !ok {
+		return value, nil
+	}
+
+	path, field, _ := strings.Cut(rest, "#")
+	if path == "" {
+		return "", fmt.Errorf("secret reference %s has no path", value)
+	}
+	ctx, cancel := context.WithTimeout(ctx, secretTimeout)
+	defer cancel()
+	secret, err := resolver(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %s://%s: %w", scheme, path, err)
+	}
+	if field == "" {
+		return secret, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret %s://%s is not a JSON object", scheme, path)
+	}
+	fieldValue, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("secret %s://%s has no field %s", scheme, path, field)
+	}
+	if s, ok := fieldValue.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(fieldValue)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode field %s of secret %s://%s: %w", field, scheme, path, err)
+	}
+	return string(data), nil
+}
+
+// configEnvPattern matches ${VAR} and ${VAR:-default} in config files
+var configEnvPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// expandConfigValue replaces ${VAR} with the value of the environment
+// variable (or .env file) VAR, and ${VAR:-default} with default when VAR is
+// unset or empty
+func expandConfigValue(s string) string {
+	return configEnvPattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := configEnvPattern.FindStringSubmatch(match)
+		if value := ExpandEnv("${" + groups[1] + "}"); value != "" {
+			return value
+		}
+		return groups[2]
+	})
+}
+
+// expandConfigFile expands ${VAR} references and resolves secret references
+// in the string values of a YAML config file. Other "$" characters, such as
+// those of $VAR, are left for ExpandAgentConfig.
+func expandConfigFile(ctx context.Context, data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte("${")) && !bytes.Contains(data, []byte("://")) {
+		return data, nil
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse agent config file: %w", err)
+	}
+	changed, err := expandConfigNode(ctx, &document)
+	if err != nil || !changed {
+		return data, err
+	}
+
+	expanded, err := yaml.Marshal(&document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode expanded agent config file: %w", err)
+	}
+	return expanded, nil
+}
+
+// expandConfigNode expands the scalar values under node and reports whether
+// any changed
+func expandConfigNode(ctx context.Context, node *yaml.Node) (bool, error) {
+	if node.Kind != yaml.ScalarNode {
+		changed := false
+		for i, child := range node.Content {
+			// Mapping keys are not expanded
+			if node.Kind == yaml.MappingNode && i%2 == 0 {
+				continue
+			}
+			childChanged, err := expandConfigNode(ctx, child)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || childChanged
+		}
+		return changed, nil
+	}
+
+	if node.Tag != "" && node.Tag != "!!str" && node.Tag != "!" {
+		return false, nil
+	}
+	value, err := ResolveSecretRef(ctx, expandConfigValue(node.Value))
+	if err != nil {
+		return false, fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	if value == node.Value {
+		return false, nil
+	}
+
+	node.Value = value
+	if node.Style == 0 {
+		// Plain values take the type of their expansion, e.g. a number
+		node.Tag = ""
+	}
+	return true, nil
+}
+
+// resolveGCPSecret reads a Google Secret Manager secret, referenced as
+// gcp-secret://<project>/<secret>[/<version>] or by its resource name,
+// gcp-secret://projects/<project>/secrets/<secret>[/versions/<version>],
+// with the application default credentials
+func resolveGCPSecret(ctx context.Context, path string) (string, error) {
+	name := path
+	if !strings.HasPrefix(path, "projects/") {
+		parts := strings.Split(path, "/")
+		if len(parts) < 2 || len(parts) > 3 {
+			return "", fmt.Errorf("expected gcp-secret://<project>/<secret>[/<version>]")
+		}
+		name = "projects/" + parts[0] + "/secrets/" + parts[1]
+		if len(parts) == 3 {
+			name += "/versions/" + parts[2]
+		}
+	}
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		return "", fmt.Errorf("failed to load Google credentials: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpSecretManagerURL+"/v1/"+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	body, err := doSecretRequest(client, req)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	secret, err := base64.StdEncoding.DecodeString(response.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return string(secret), nil
+}
+
+// resolveAWSSecret reads an AWS Secrets Manager secret, referenced as
+// aws-sm://<name or ARN>, with the default AWS credential chain. The region
+// is the one of the ARN, or of the AWS configuration.
+func resolveAWSSecret(ctx context.Context, path string) (string, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	region := awsCfg.Region
+	if arn := strings.Split(path, ":"); len(arn) > 3 && arn[0] == "arn" {
+		region = arn[3]
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	credentials, err := awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return "", err
+	}
+	endpoint := fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	hash := sha256.Sum256(payload)
+	if err := v4.NewSigner().SignHTTP(ctx, credentials, req, hex.EncodeToString(hash[:]), "secretsmanager", region, time.Now()); err != nil {
+		return "", fmt.Errorf("failed to sign request: %w", err)
+	}
+	body, err := doSecretRequest(http.DefaultClient, req)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		SecretString string `json:"SecretString"`
+		SecretBinary []byte `json:"SecretBinary"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.SecretString != "" {
+		return response.SecretString, nil
+	}
+	return string(response.SecretBinary), nil
+}
+
+// resolveVaultSecret reads a HashiCorp Vault secret, referenced by its API
+// path as vault://<path>, e.g. vault://secret/data/app#api_key for a KV v2
+// secret, from VAULT_ADDR with VAULT_TOKEN (and VAULT_NAMESPACE, if set).
+// Secrets with a single field resolve to its value; others to their fields
+// as a JSON object.
+func resolveVaultSecret(ctx context.Context, path string) (string, error) {
+	addr := GetEnvValue("VAULT_ADDR")
+	if addr == "" {
+		return "", fmt.Errorf("VAULT_ADDR is not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", GetEnvValue("VAULT_TOKEN"))
+	if namespace := GetEnvValue("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	body, err := doSecretRequest(http.DefaultClient, req)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	fields := response.Data
+	// KV v2 secrets nest their fields under data.data
+	if nested, ok := fields["data"].(map[string]interface{}); ok && fields["metadata"] != nil {
+		fields = nested
+	}
+	if len(fields) == 1 {
+		for _, value := range fields {
+			if s, ok := value.(string); ok {
+				return s, nil
+			}
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode secret: %w", err)
+	}
+	return string(data), nil
+}
+
+// doSecretRequest sends a request to a secret manager and returns the body
+// of a successful response
+func doSecretRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("secret manager returned status %d", resp.StatusCode)
+	}
+	return body, nil
+}
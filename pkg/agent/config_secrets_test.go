@@ -0,0 +1,134 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAgentConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agents.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadAgentConfigsFromFileExpandsEnv(t *testing.T) {
+	t.Setenv("TEST_AGENT_MODEL", "gpt-4o")
+	t.Setenv("TEST_AGENT_MAX_ITERATIONS", "7")
+
+	path := writeAgentConfigFile(t, `
+assistant:
+  role: Assistant costing $5
+  goal: Answer with ${TEST_AGENT_MODEL}
+  backstory: Runs in ${TEST_AGENT_REGION:-us-central1}
+  max_iterations: ${TEST_AGENT_MAX_ITERATIONS}
+  llm_provider:
+    provider: openai
+    model: "${TEST_AGENT_MODEL}"
+    config:
+      base_url: https://api.openai.com/v1
+`)
+
+	configs, err := LoadAgentConfigsFromFile(path)
+	require.NoError(t, err)
+	config := configs["assistant"]
+
+	// Only braced references are expanded when loading
+	assert.Equal(t, "Assistant costing $5", config.Role)
+	assert.Equal(t, "Answer with gpt-4o", config.Goal)
+	assert.Equal(t, "Runs in us-central1", config.Backstory)
+	require.NotNil(t, config.MaxIterations)
+	assert.Equal(t, 7, *config.MaxIterations)
+	require.NotNil(t, config.LLMProvider)
+	assert.Equal(t, "gpt-4o", config.LLMProvider.Model)
+	assert.Equal(t, "https://api.openai.com/v1", config.LLMProvider.Config["base_url"])
+}
+
+func TestLoadAgentConfigsFromFileResolvesSecrets(t *testing.T) {
+	RegisterSecretResolver("test-secret", func(ctx context.Context, path string) (string, error) {
+		switch path {
+		case "credentials":
+			return `{"client_email":"agent@example.com","port":8443}`, nil
+		case "key":
+			return "sk-test", nil
+		}
+		return "", errors.New("not found")
+	})
+
+	path := writeAgentConfigFile(t, `
+assistant:
+  role: Assistant
+  llm_provider:
+    provider: openai
+    config:
+      api_key: test-secret://key
+      client_email: test-secret://credentials#client_email
+      port: test-secret://credentials#port
+`)
+
+	configs, err := LoadAgentConfigsFromFile(path)
+	require.NoError(t, err)
+	providerConfig := configs["assistant"].LLMProvider.Config
+	assert.Equal(t, "sk-test", providerConfig["api_key"])
+	assert.Equal(t, "agent@example.com", providerConfig["client_email"])
+	assert.Equal(t, 8443, providerConfig["port"])
+
+	path = writeAgentConfigFile(t, `
+assistant:
+  role: test-secret://missing
+`)
+	_, err = LoadAgentConfigsFromFile(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "test-secret://missing")
+	assert.Contains(t, err.Error(), "line 3")
+}
+
+func TestResolveVaultSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/app":
+			_, _ = w.Write([]byte(`{"data":{"data":{"api_key":"sk-vault","region":"eu"},"metadata":{"version":1}}}`))
+		case "/v1/secret/token":
+			_, _ = w.Write([]byte(`{"data":{"value":"kv1-token"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+
+	ctx := context.Background()
+	value, err := ResolveSecretRef(ctx, "vault://secret/data/app#api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-vault", value)
+
+	value, err = ResolveSecretRef(ctx, "vault://secret/data/app")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"api_key":"sk-vault","region":"eu"}`, value)
+
+	value, err = ResolveSecretRef(ctx, "vault://secret/token")
+	require.NoError(t, err)
+	assert.Equal(t, "kv1-token", value)
+
+	_, err = ResolveSecretRef(ctx, "vault://secret/missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+
+	// Values of other schemes are not secret references
+	value, err = ResolveSecretRef(ctx, "https://example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", value)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 func TestFormatSystemPromptFromConfig(t *testing.T) {
@@ -166,3 +167,101 @@ func TestConvertYAMLSchemaToResponseFormat(t *testing.T) {
 		t.Fatal("Expected nil ResponseFormat for nil config")
 	}
 }
+
+func TestKnowledgeConfigExpansion(t *testing.T) {
+	t.Setenv("KB_DOCS_DIR", "/srv/docs")
+
+	var configs AgentConfigs
+	err := yaml.Unmarshal([]byte(`
+support:
+  role: Support agent
+  knowledge:
+    sources:
+      - type: directory
+        path: ${KB_DOCS_DIR}
+        extensions: [".md"]
+    embedding:
+      provider: openai
+      model: text-embedding-3-small
+    vector_store:
+      type: memory
+    top_k: 3
+    token_budget: 500
+    expose_as_tool: false
+`), &configs)
+	assert.NoError(t, err)
+
+	expanded := ExpandAgentConfig(configs["support"])
+	if assert.NotNil(t, expanded.Knowledge) {
+		assert.Equal(t, "/srv/docs", expanded.Knowledge.Sources[0].Path)
+		assert.Equal(t, []string{".md"}, expanded.Knowledge.Sources[0].Extensions)
+		assert.Equal(t, "memory", expanded.Knowledge.VectorStore.Type)
+		assert.Equal(t, 3, *expanded.Knowledge.TopK)
+		assert.False(t, *expanded.Knowledge.ExposeAsTool)
+	}
+	// The original config must not be mutated by expansion
+	assert.Equal(t, "${KB_DOCS_DIR}", configs["support"].Knowledge.Sources[0].Path)
+}
+
+type registryReport struct {
+	Title    string   `json:"title"`
+	Sections []string `json:"sections"`
+}
+
+func TestSchemaRegistryFromYAML(t *testing.T) {
+	RegisterSchemaType("RegistryReport", registryReport{})
+
+	configs, err := LoadAgentConfigsFromFile("testdata/agents_with_schema_registry.yaml")
+	assert.NoError(t, err)
+	assert.Len(t, configs, 2, "the schemas key must not be loaded as an agent")
+
+	format, err := ConvertYAMLSchemaToResponseFormat(configs["researcher"].ResponseFormat)
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.ResponseFormatJSON, format.Type)
+	assert.Equal(t, "ResearchSummary", format.Name)
+	assert.Contains(t, format.Schema["properties"], "confidence")
+
+	format, err = ConvertYAMLSchemaToResponseFormat(configs["reporter"].ResponseFormat)
+	assert.NoError(t, err)
+	assert.Equal(t, "ReportRef", format.Name)
+	assert.Contains(t, format.Schema["properties"], "sections")
+
+	_, err = ConvertYAMLSchemaToResponseFormat(&ResponseFormatConfig{SchemaRef: "Missing"})
+	assert.Error(t, err)
+
+	_, err = NewAgentFromConfig("researcher", AgentConfigs{
+		"researcher": {Role: "Researcher", ResponseFormat: &ResponseFormatConfig{SchemaRef: "Missing"}},
+	}, nil)
+	assert.ErrorContains(t, err, "unknown schema Missing")
+}
+
+func TestImageGenerationProviderFromYAML(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("STABILITY_API_KEY", "")
+
+	config := &ImageGenerationYAML{Provider: "stability", Model: "ultra", Config: map[string]interface{}{"api_key": "key"}}
+	tool, err := createImageGenerationToolFromConfig(config, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "generate_image", tool.Name())
+	assert.Contains(t, tool.Parameters()["output_format"].Enum, "webp")
+	assert.Nil(t, createImageEditToolFromConfig(config, tool), "edit_image is opt-in")
+
+	enabled := true
+	config = &ImageGenerationYAML{Provider: "openai", EditImage: &enabled, Config: map[string]interface{}{"api_key": "key"}}
+	tool, err = createImageGenerationToolFromConfig(config, nil)
+	assert.NoError(t, err)
+	editTool := createImageEditToolFromConfig(config, tool)
+	if assert.NotNil(t, editTool) {
+		assert.Equal(t, "edit_image", editTool.Name())
+	}
+
+	generator, err := createImageGeneratorFromConfig("openai", &ImageGenerationYAML{Config: map[string]interface{}{"api_key": "key"}})
+	assert.NoError(t, err)
+	assert.True(t, generator.SupportsImageGeneration())
+
+	_, err = createImageGenerationToolFromConfig(&ImageGenerationYAML{Provider: "openai"}, nil)
+	assert.ErrorContains(t, err, "OPENAI_API_KEY")
+
+	_, err = createImageGenerationToolFromConfig(&ImageGenerationYAML{Provider: "midjourney"}, nil)
+	assert.ErrorContains(t, err, "unsupported image generation provider")
+}
@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+)
+
+// WithConnectionWarmup pre-establishes connections to the LLM provider when the
+// agent is created, so the first request does not pay for DNS, TLS and HTTP/2
+// session setup. When keepAliveInterval is > 0 the connections are refreshed
+// periodically so they survive idle periods; keep it below the HTTP transport's
+// idle connection timeout (90s by default, see llm.DefaultKeepAliveInterval).
+// Call Disconnect to stop the keepalive routine. Providers that do not
+// implement interfaces.ConnectionWarmer are left untouched.
+func WithConnectionWarmup(keepAliveInterval time.Duration) Option {
+	return func(a *Agent) {
+		a.connectionWarmup = true
+		a.keepAliveInterval = keepAliveInterval
+	}
+}
+
+// startConnectionWarmup warms the LLM provider's connections in the background
+// and starts the keepalive routine when configured
+func (a *Agent) startConnectionWarmup() {
+	if !a.connectionWarmup || a.stopKeepAlive != nil {
+		return
+	}
+
+	warmer, ok := a.llm.(interfaces.ConnectionWarmer)
+	if !ok {
+		a.logger.Debug(context.Background(), "LLM provider does not support connection warmup", map[string]interface{}{
+			"provider": a.llm.Name(),
+		})
+		return
+	}
+
+	a.stopKeepAlive = llm.KeepWarm(context.Background(), warmer, a.keepAliveInterval, func(err error) {
+		a.logger.Warn(context.Background(), "LLM connection warmup failed", map[string]interface{}{
+			"provider": a.llm.Name(),
+			"error":    err.Error(),
+		})
+	})
+}
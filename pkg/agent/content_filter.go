@@ -0,0 +1,191 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// RephraseFunc rewrites an input that a provider's content filter blocked so
+// the run can be retried
+type RephraseFunc func(ctx context.Context, input string, blocked *interfaces.ContentFilterError) (string, error)
+
+// WithContentFilterRetry retries runs blocked by a provider content filter (or
+// refused by the model) up to maxRetries times, rewriting the input with
+// rephrase before each attempt. When memory is configured the rewritten input
+// is stored as a new user message. If every attempt is blocked, the run fails
+// with the last *interfaces.ContentFilterError. Streaming runs are not retried.
+func WithContentFilterRetry(rephrase RephraseFunc, maxRetries int) Option {
+	return func(a *Agent) {
+		a.rephrase = rephrase
+		a.contentFilterRetries = maxRetries
+	}
+}
+
+// RephraseWithLLM returns a RephraseFunc that asks llm to reword the blocked
+// input neutrally while keeping its intent
+func RephraseWithLLM(llm interfaces.LLM) RephraseFunc {
+	return func(ctx context.Context, input string, blocked *interfaces.ContentFilterError) (string, error) {
+		prompt := fmt.Sprintf(`The following request was blocked by a content safety filter (category: %s).
+Rewrite it so that it keeps the user's legitimate intent but uses neutral, policy-compliant wording.
+Return only the rewritten request.
+
+Request:
+%s`, blocked.Category, input)
+
+		rephrased, err := llm.Generate(ctx, prompt)
+		if err != nil {
+			return "", err
+		}
+
+		rephrased = strings.TrimSpace(rephrased)
+		if rephrased == "" {
+			return "", fmt.Errorf("rephrasing returned an empty input")
+		}
+		return rephrased, nil
+	}
+}
+
+// ContentFilterMetrics counts provider content-filter outcomes
+type ContentFilterMetrics struct {
+	// Blocked is the number of generations blocked by a content filter, including retries
+	Blocked int
+
+	// PromptBlocked is the number of those blocks that rejected the input
+	PromptBlocked int
+
+	// ByCategory counts blocked generations per category
+	ByCategory map[interfaces.ContentFilterCategory]int
+
+	// ByProvider counts blocked generations per LLM provider
+	ByProvider map[string]int
+
+	// AffectedRuns is the number of runs that hit a content filter at least once
+	AffectedRuns int
+
+	// Retries is the number of rephrased retries
+	Retries int
+
+	// Recovered is the number of affected runs that succeeded after rephrasing
+	Recovered int
+}
+
+// RecoveryRate returns the fraction of affected runs that succeeded after rephrasing
+func (m ContentFilterMetrics) RecoveryRate() float64 {
+	if m.AffectedRuns == 0 {
+		return 0
+	}
+	return float64(m.Recovered) / float64(m.AffectedRuns)
+}
+
+// contentFilterStats is the concurrency-safe store behind ContentFilterMetrics
+type contentFilterStats struct {
+	mu      sync.Mutex
+	metrics ContentFilterMetrics
+}
+
+func (s *contentFilterStats) recordBlocked(blocked *interfaces.ContentFilterError, firstAttempt bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.metrics.ByCategory == nil {
+		s.metrics.ByCategory = make(map[interfaces.ContentFilterCategory]int)
+		s.metrics.ByProvider = make(map[string]int)
+	}
+
+	s.metrics.Blocked++
+	if blocked.PromptBlocked {
+		s.metrics.PromptBlocked++
+	}
+	s.metrics.ByCategory[blocked.Category]++
+	s.metrics.ByProvider[blocked.Provider]++
+	if firstAttempt {
+		s.metrics.AffectedRuns++
+	}
+}
+
+func (s *contentFilterStats) recordRetry() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics.Retries++
+}
+
+func (s *contentFilterStats) recordRecovered() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics.Recovered++
+}
+
+func (s *contentFilterStats) snapshot() ContentFilterMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	metrics := s.metrics
+	metrics.ByCategory = make(map[interfaces.ContentFilterCategory]int, len(s.metrics.ByCategory))
+	for category, count := range s.metrics.ByCategory {
+		metrics.ByCategory[category] = count
+	}
+	metrics.ByProvider = make(map[string]int, len(s.metrics.ByProvider))
+	for provider, count := range s.metrics.ByProvider {
+		metrics.ByProvider[provider] = count
+	}
+	return metrics
+}
+
+// GetContentFilterMetrics returns content-filter statistics for the agent's runs
+func (a *Agent) GetContentFilterMetrics() ContentFilterMetrics {
+	return a.contentFilterStats.snapshot()
+}
+
+// generateWithContentFilterRetry runs generate and records content-filter
+// blocks, retrying with a rephrased input when WithContentFilterRetry is set
+func (a *Agent) generateWithContentFilterRetry(ctx context.Context, input string, generate func(ctx context.Context, input string) (string, error)) (string, error) {
+	response, err := generate(ctx, input)
+
+	for attempt := 0; ; attempt++ {
+		blocked, ok := interfaces.AsContentFilterError(err)
+		if !ok {
+			if attempt > 0 && err == nil {
+				a.contentFilterStats.recordRecovered()
+			}
+			return response, err
+		}
+
+		a.contentFilterStats.recordBlocked(blocked, attempt == 0)
+		a.logger.Warn(ctx, "LLM provider blocked generation", map[string]interface{}{
+			"provider":       blocked.Provider,
+			"category":       string(blocked.Category),
+			"stop_reason":    blocked.StopReason,
+			"prompt_blocked": blocked.PromptBlocked,
+			"attempt":        attempt + 1,
+		})
+
+		if a.rephrase == nil || attempt >= a.contentFilterRetries {
+			return "", err
+		}
+
+		rephrased, rephraseErr := a.rephrase(ctx, input, blocked)
+		if rephraseErr != nil {
+			a.logger.Warn(ctx, "Failed to rephrase blocked input", map[string]interface{}{
+				"error": rephraseErr.Error(),
+			})
+			return "", err
+		}
+
+		if a.memory != nil {
+			if memErr := a.memory.AddMessage(ctx, interfaces.Message{
+				Role:    interfaces.MessageRoleUser,
+				Content: rephrased,
+			}); memErr != nil {
+				return "", fmt.Errorf("failed to add rephrased message to memory: %w", memErr)
+			}
+		}
+
+		a.contentFilterStats.recordRetry()
+		input = rephrased
+		response, err = generate(ctx, input)
+	}
+}
@@ -0,0 +1,127 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func blockingLLM(blockedWord string) *mockLLM {
+	return &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			if strings.Contains(prompt, blockedWord) {
+				return "", &interfaces.ContentFilterError{
+					Provider:   "mock",
+					Category:   interfaces.ContentFilterCategoryViolence,
+					StopReason: "content_filter",
+				}
+			}
+			return "answer to: " + prompt, nil
+		},
+	}
+}
+
+func TestContentFilterTypedOutcome(t *testing.T) {
+	agent, err := NewAgent(WithLLM(blockingLLM("attack")), WithRequirePlanApproval(false))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	_, err = agent.Run(context.Background(), "plan an attack")
+	blocked, ok := interfaces.AsContentFilterError(err)
+	if !ok {
+		t.Fatalf("expected ContentFilterError, got %v", err)
+	}
+	if blocked.Category != interfaces.ContentFilterCategoryViolence {
+		t.Errorf("expected violence category, got %s", blocked.Category)
+	}
+
+	metrics := agent.GetContentFilterMetrics()
+	if metrics.Blocked != 1 || metrics.AffectedRuns != 1 || metrics.Retries != 0 {
+		t.Errorf("unexpected metrics: %+v", metrics)
+	}
+	if metrics.ByCategory[interfaces.ContentFilterCategoryViolence] != 1 || metrics.ByProvider["mock"] != 1 {
+		t.Errorf("unexpected breakdown: %+v", metrics)
+	}
+}
+
+func TestContentFilterRetryWithRephrase(t *testing.T) {
+	var rephrased []string
+	rephrase := func(ctx context.Context, input string, blocked *interfaces.ContentFilterError) (string, error) {
+		rephrased = append(rephrased, input)
+		return strings.ReplaceAll(input, "attack", "defense"), nil
+	}
+
+	agent, err := NewAgent(
+		WithLLM(blockingLLM("attack")),
+		WithRequirePlanApproval(false),
+		WithContentFilterRetry(rephrase, 2),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	response, err := agent.Run(context.Background(), "plan an attack")
+	if err != nil {
+		t.Fatalf("expected run to recover, got %v", err)
+	}
+	if response != "answer to: plan an defense" {
+		t.Errorf("unexpected response %q", response)
+	}
+	if len(rephrased) != 1 {
+		t.Errorf("expected one rephrase, got %d", len(rephrased))
+	}
+
+	metrics := agent.GetContentFilterMetrics()
+	if metrics.Blocked != 1 || metrics.Retries != 1 || metrics.Recovered != 1 || metrics.RecoveryRate() != 1 {
+		t.Errorf("unexpected metrics: %+v", metrics)
+	}
+}
+
+func TestContentFilterRetryExhausted(t *testing.T) {
+	rephrase := func(ctx context.Context, input string, blocked *interfaces.ContentFilterError) (string, error) {
+		return input + " please", nil
+	}
+
+	agent, err := NewAgent(
+		WithLLM(blockingLLM("attack")),
+		WithRequirePlanApproval(false),
+		WithContentFilterRetry(rephrase, 2),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	_, err = agent.Run(context.Background(), "plan an attack")
+	if _, ok := interfaces.AsContentFilterError(err); !ok {
+		t.Fatalf("expected ContentFilterError, got %v", err)
+	}
+
+	metrics := agent.GetContentFilterMetrics()
+	if metrics.Blocked != 3 || metrics.Retries != 2 || metrics.AffectedRuns != 1 || metrics.Recovered != 0 {
+		t.Errorf("unexpected metrics: %+v", metrics)
+	}
+}
+
+func TestContentFilterRephraseFailure(t *testing.T) {
+	rephrase := func(ctx context.Context, input string, blocked *interfaces.ContentFilterError) (string, error) {
+		return "", errors.New("rephrase unavailable")
+	}
+
+	agent, err := NewAgent(
+		WithLLM(blockingLLM("attack")),
+		WithRequirePlanApproval(false),
+		WithContentFilterRetry(rephrase, 1),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	_, err = agent.Run(context.Background(), "plan an attack")
+	if _, ok := interfaces.AsContentFilterError(err); !ok {
+		t.Fatalf("expected the original ContentFilterError, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// inputPartsKey is the context key for the content parts of an agent's input.
+// It is scoped to the agent so sub-agents do not receive their parent's parts.
+type inputPartsKey struct {
+	agent *Agent
+}
+
+// RunWithContent runs the agent with input and content parts such as images,
+// audio or documents. The parts are sent to the LLM with the input and are
+// stored with the user message in memory.
+func (a *Agent) RunWithContent(ctx context.Context, input string, parts ...interfaces.ContentPart) (string, error) {
+	return a.Run(interfaces.ContextWithContentParts(ctx, parts), input)
+}
+
+// RunStreamWithContent is the streaming version of RunWithContent
+func (a *Agent) RunStreamWithContent(ctx context.Context, input string, parts ...interfaces.ContentPart) (<-chan interfaces.AgentStreamEvent, error) {
+	return a.RunStream(interfaces.ContextWithContentParts(ctx, parts), input)
+}
+
+// takeInputParts moves the content parts set with
+// interfaces.ContextWithContentParts to the agent's own context key
+func (a *Agent) takeInputParts(ctx context.Context) context.Context {
+	parts := interfaces.ContentPartsFromContext(ctx)
+	if len(parts) == 0 {
+		return ctx
+	}
+	ctx = interfaces.ContextWithContentParts(ctx, nil)
+	return context.WithValue(ctx, inputPartsKey{agent: a}, parts)
+}
+
+// inputParts returns the content parts of the agent's current input
+func (a *Agent) inputParts(ctx context.Context) []interfaces.ContentPart {
+	parts, _ := ctx.Value(inputPartsKey{agent: a}).([]interfaces.ContentPart)
+	return parts
+}
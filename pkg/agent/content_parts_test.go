@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestRunWithContent(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.Any()).Respond("A cat")
+
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithMemory(mem),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "org-a")
+	ctx = memory.WithConversationID(ctx, "conversation")
+	image := interfaces.ImagePart([]byte("png"), "image/png")
+	if _, err := agent.RunWithContent(ctx, "What is this?", image); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := llm.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 LLM call, got %d", len(calls))
+	}
+	if parts := calls[0].Request.Options.ContentParts; len(parts) != 1 || parts[0].MIMEType != "image/png" {
+		t.Errorf("expected the image to reach the LLM, got %+v", parts)
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+	if len(messages) == 0 || !messages[0].HasMedia() {
+		t.Errorf("expected the image to be stored with the user message, got %+v", messages)
+	}
+}
+
+func TestRunWithContentAndTools(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.WithTool("lookup")).CallTool("lookup", `{"input":"receipt"}`).Respond("Total is $12")
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithTools(&mockTool{name: "lookup", description: "Looks things up"}),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	image := interfaces.ImageURLPart("https://example.com/receipt.png")
+	if _, err := agent.RunWithContent(context.Background(), "What is the total?", image); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := llm.Calls()
+	if len(calls) != 1 || calls[0].Request.Method != "GenerateWithTools" {
+		t.Fatalf("expected 1 GenerateWithTools call, got %+v", calls)
+	}
+	if parts := calls[0].Request.Options.ContentParts; len(parts) != 1 || parts[0].URL != image.URL {
+		t.Errorf("expected the image to reach GenerateWithTools, got %+v", parts)
+	}
+}
+
+func TestInputPartsAreScopedToAgent(t *testing.T) {
+	parent, child := &Agent{}, &Agent{}
+	ctx := interfaces.ContextWithContentParts(context.Background(), []interfaces.ContentPart{interfaces.TextPart("hi")})
+
+	ctx = parent.takeInputParts(ctx)
+	if len(parent.inputParts(ctx)) != 1 {
+		t.Error("expected the parent to get the input parts")
+	}
+	if parts := child.inputParts(child.takeInputParts(ctx)); len(parts) != 0 {
+		t.Errorf("expected no input parts for the child, got %+v", parts)
+	}
+}
@@ -0,0 +1,176 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tokens"
+)
+
+// ContextOverflowPolicy is what the agent does when the conversation does
+// not fit in the model's context window
+type ContextOverflowPolicy string
+
+const (
+	// ContextOverflowTrim drops the oldest messages of the history
+	ContextOverflowTrim ContextOverflowPolicy = "trim"
+
+	// ContextOverflowSummarize replaces the oldest messages of the history
+	// with a summary written by the agent's LLM
+	ContextOverflowSummarize ContextOverflowPolicy = "summarize"
+
+	// ContextOverflowFail fails the run with a *ContextWindowError
+	ContextOverflowFail ContextOverflowPolicy = "fail"
+)
+
+// responseReserve is the number of tokens of the context window left for
+// the response
+const responseReserve = 4096
+
+// ErrContextWindowExceeded is returned, wrapped in a *ContextWindowError,
+// when the conversation does not fit in the model's context window
+var ErrContextWindowExceeded = errors.New("context window exceeded")
+
+// ContextWindowError reports a conversation that does not fit in the
+// model's context window
+type ContextWindowError struct {
+	Model  string // Model of the agent
+	Tokens int    // Estimated tokens of the request, including the response reserve
+	Limit  int    // Context window of the model
+}
+
+func (e *ContextWindowError) Error() string {
+	return fmt.Sprintf("%v: the request needs about %d tokens, but %s has a context window of %d tokens",
+		ErrContextWindowExceeded, e.Tokens, e.Model, e.Limit)
+}
+
+func (e *ContextWindowError) Unwrap() error {
+	return ErrContextWindowExceeded
+}
+
+// Is reports whether target is interfaces.ErrContextLengthExceeded, so the
+// error matches context length errors reported by providers
+func (e *ContextWindowError) Is(target error) bool {
+	return target == interfaces.ErrContextLengthExceeded
+}
+
+// WithContextOverflowPolicy checks that each request fits in the model's
+// context window before it is sent to the LLM. The tokens of the system
+// prompt, history, prompt and tool definitions are estimated against the
+// window from tokens.ContextWindow (or WithContextWindow). When they do not
+// fit, the history is trimmed or summarized, or the run fails with a
+// *ContextWindowError, instead of the provider rejecting the request.
+func WithContextOverflowPolicy(policy ContextOverflowPolicy) Option {
+	return func(a *Agent) {
+		a.overflowPolicy = policy
+	}
+}
+
+// WithContextWindow sets the context window of the agent's model in tokens,
+// overriding tokens.ContextWindow
+func WithContextWindow(tokens int) Option {
+	return func(a *Agent) {
+		a.contextWindow = tokens
+	}
+}
+
+// fitContextWindow returns the memory to send to the LLM with a request: the
+// agent's memory, or a view of it holding the history trimmed or summarized
+// to fit in the context window. Returns the agent's memory unchanged when no
+// ContextOverflowPolicy is set.
+func (a *Agent) fitContextWindow(ctx context.Context, systemPrompt, prompt string, tools []interfaces.Tool) (interfaces.Memory, error) {
+	if a.overflowPolicy == "" {
+		return a.memory, nil
+	}
+
+	model := a.modelName()
+	limit := a.contextWindow
+	if limit <= 0 {
+		limit = tokens.ContextWindow(model)
+	}
+
+	// Tokens of the request outside the history
+	fixed := responseReserve
+	for _, text := range []string{systemPrompt, prompt, toolDefinitions(tools)} {
+		count, err := tokens.CountText(model, text)
+		if err != nil {
+			return nil, err
+		}
+		fixed += count
+	}
+	if fixed > limit {
+		return nil, &ContextWindowError{Model: model, Tokens: fixed, Limit: limit}
+	}
+	if a.memory == nil {
+		return nil, nil
+	}
+
+	history, err := a.memory.GetMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory messages: %w", err)
+	}
+	historyTokens, err := tokens.CountTokens(model, history)
+	if err != nil {
+		return nil, err
+	}
+	budget := limit - fixed
+	if historyTokens <= budget {
+		return a.memory, nil
+	}
+
+	a.logger.Warn(ctx, "Conversation exceeds the context window", map[string]interface{}{
+		"model":  model,
+		"tokens": fixed + historyTokens,
+		"limit":  limit,
+		"policy": string(a.overflowPolicy),
+	})
+
+	var fitted []interfaces.Message
+	switch a.overflowPolicy {
+	case ContextOverflowTrim:
+		fitted, err = tokens.TrimToBudget(model, history, budget)
+	case ContextOverflowSummarize:
+		fitted, err = tokens.Compress(ctx, a.llm, model, history, budget)
+	default:
+		return nil, &ContextWindowError{Model: model, Tokens: fixed + historyTokens, Limit: limit}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fit the conversation in the context window: %w", err)
+	}
+	return &fittedMemory{Memory: a.memory, messages: fitted}, nil
+}
+
+// toolDefinitions returns the definitions of tools as sent to the LLM
+func toolDefinitions(tools []interfaces.Tool) string {
+	if len(tools) == 0 {
+		return ""
+	}
+	definitions := make([]map[string]interface{}, len(tools))
+	for i, tool := range tools {
+		definitions[i] = map[string]interface{}{
+			"name":        tool.Name(),
+			"description": tool.Description(),
+			"parameters":  tool.Parameters(),
+		}
+	}
+	data, err := json.Marshal(definitions)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// fittedMemory is a view of a memory whose history was fitted in the
+// context window. Messages are added to the underlying memory.
+type fittedMemory struct {
+	interfaces.Memory
+	messages []interfaces.Message
+}
+
+// GetMessages returns the fitted history
+func (m *fittedMemory) GetMessages(ctx context.Context, options ...interfaces.GetMessagesOption) ([]interfaces.Message, error) {
+	return m.messages, nil
+}
@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestContextOverflowPolicy(t *testing.T) {
+	ctx := multitenancy.WithOrgID(context.Background(), "org-a")
+	ctx = memory.WithConversationID(ctx, "conversation")
+
+	// A history of about 2000 tokens, in a context window leaving 500 tokens
+	// for it
+	newMemory := func() interfaces.Memory {
+		mem := memory.NewConversationBuffer()
+		for i := 0; i < 20; i++ {
+			_ = mem.AddMessage(ctx, interfaces.Message{
+				Role:    interfaces.MessageRoleUser,
+				Content: strings.Repeat("lorem ipsum dolor sit amet ", 20),
+			})
+		}
+		return mem
+	}
+	window := responseReserve + 500
+
+	t.Run("trim", func(t *testing.T) {
+		llm := mock.New()
+		llm.On(mock.Any()).Respond("done")
+		agent, err := NewAgent(
+			WithLLM(llm),
+			WithMemory(newMemory()),
+			WithContextOverflowPolicy(ContextOverflowTrim),
+			WithContextWindow(window),
+			WithRequirePlanApproval(false),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create agent: %v", err)
+		}
+
+		if _, err := agent.Run(ctx, "Continue"); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		calls := llm.Calls()
+		if len(calls) != 1 {
+			t.Fatalf("Expected 1 LLM call, got %d", len(calls))
+		}
+		history, err := calls[0].Request.Options.Memory.GetMessages(ctx)
+		if err != nil {
+			t.Fatalf("GetMessages: %v", err)
+		}
+		if len(history) == 0 || len(history) >= 20 {
+			t.Errorf("Expected the history to be trimmed, got %d messages", len(history))
+		}
+		if last := history[len(history)-1]; last.Content != "Continue" {
+			t.Errorf("Expected the latest message to be kept, got %q", last.Content)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		llm := mock.New()
+		llm.On(mock.Any()).Respond("done")
+		agent, err := NewAgent(
+			WithLLM(llm),
+			WithMemory(newMemory()),
+			WithContextOverflowPolicy(ContextOverflowFail),
+			WithContextWindow(window),
+			WithRequirePlanApproval(false),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create agent: %v", err)
+		}
+
+		_, err = agent.Run(ctx, "Continue")
+		var windowErr *ContextWindowError
+		if !errors.As(err, &windowErr) || !errors.Is(err, ErrContextWindowExceeded) {
+			t.Fatalf("Expected a ContextWindowError, got %v", err)
+		}
+		if windowErr.Limit != window || windowErr.Tokens <= window {
+			t.Errorf("Unexpected error %+v", windowErr)
+		}
+		if len(llm.Calls()) != 0 {
+			t.Errorf("Expected no LLM call, got %d", len(llm.Calls()))
+		}
+	})
+}
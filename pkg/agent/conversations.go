@@ -0,0 +1,140 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// ErrConversationExists is returned when renaming or branching a conversation
+// to an ID that is already in use
+var ErrConversationExists = interfaces.ErrConversationExists
+
+// ExportConversation returns all messages of a conversation in the agent's memory
+func (a *Agent) ExportConversation(ctx context.Context, conversationID string) ([]interfaces.Message, error) {
+	if a.memory == nil {
+		return nil, fmt.Errorf("agent has no memory configured")
+	}
+	return memory.Export(a.memoryContext(ctx), a.memory, conversationID)
+}
+
+// ImportConversation replaces the messages of a conversation in the agent's
+// memory, e.g. to migrate it from another backend or seed a test
+func (a *Agent) ImportConversation(ctx context.Context, conversationID string, messages []interfaces.Message) error {
+	if a.memory == nil {
+		return fmt.Errorf("agent has no memory configured")
+	}
+
+	ctx = a.memoryContext(ctx)
+	if err := a.memory.Clear(memory.WithConversationID(ctx, conversationID)); err != nil {
+		return fmt.Errorf("failed to clear conversation %s: %w", conversationID, err)
+	}
+	return memory.Import(ctx, a.memory, conversationID, messages)
+}
+
+// DeleteConversation removes all messages of a conversation from the agent's memory
+func (a *Agent) DeleteConversation(ctx context.Context, conversationID string) error {
+	if a.memory == nil {
+		return fmt.Errorf("agent has no memory configured")
+	}
+	if conversationID == "" {
+		return fmt.Errorf("conversation ID is required")
+	}
+
+	ctx = memory.WithConversationID(a.memoryContext(ctx), conversationID)
+	if err := a.memory.Clear(ctx); err != nil {
+		return fmt.Errorf("failed to delete conversation %s: %w", conversationID, err)
+	}
+	return nil
+}
+
+// RenameConversation moves the messages of a conversation to a new
+// conversation ID. It fails with ErrConversationExists if the new
+// conversation already has messages.
+func (a *Agent) RenameConversation(ctx context.Context, conversationID, newConversationID string) error {
+	if a.memory == nil {
+		return fmt.Errorf("agent has no memory configured")
+	}
+	if newConversationID == "" {
+		return fmt.Errorf("new conversation ID is required")
+	}
+	if conversationID == newConversationID {
+		return nil
+	}
+
+	ctx = a.memoryContext(ctx)
+	messages, err := memory.Export(ctx, a.memory, conversationID)
+	if err != nil {
+		return err
+	}
+	if err := memory.Create(ctx, a.memory, newConversationID, messages); err != nil {
+		return err
+	}
+	return a.DeleteConversation(ctx, conversationID)
+}
+
+// BranchConversation forks a conversation in the agent's memory: the new
+// conversation starts with the messages before index at, and the original is
+// kept. It fails with ErrConversationExists if the new conversation already
+// has messages.
+func (a *Agent) BranchConversation(ctx context.Context, conversationID, newConversationID string, at int) error {
+	if a.memory == nil {
+		return fmt.Errorf("agent has no memory configured")
+	}
+
+	return memory.Branch(a.memoryContext(ctx), a.memory, conversationID, newConversationID, at)
+}
+
+// Resend forks a conversation before the message at index at and runs input
+// in the new conversation, e.g. to edit a user message and send it again
+func (a *Agent) Resend(ctx context.Context, conversationID, newConversationID string, at int, input string) (*interfaces.AgentResponse, error) {
+	if err := a.BranchConversation(ctx, conversationID, newConversationID, at); err != nil {
+		return nil, err
+	}
+	return a.RunDetailed(memory.WithConversationID(ctx, newConversationID), input)
+}
+
+// Regenerate produces a new response to the last user message of a
+// conversation. The conversation is forked before that message, which is run
+// again in the new conversation, so the original keeps the previous response.
+func (a *Agent) Regenerate(ctx context.Context, conversationID, newConversationID string) (*interfaces.AgentResponse, error) {
+	if a.memory == nil {
+		return nil, fmt.Errorf("agent has no memory configured")
+	}
+
+	messages, err := memory.Export(a.memoryContext(ctx), a.memory, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != interfaces.MessageRoleUser {
+			continue
+		}
+		if len(messages[i].ContentParts) > 0 {
+			ctx = interfaces.ContextWithContentParts(ctx, messages[i].ContentParts)
+		}
+
+		// Fork on the stored message ID where the memory assigns one, as
+		// the index may shift when older messages are trimmed or summarized
+		messageID := memory.MessageID(messages[i])
+		if messageID == "" {
+			return a.Resend(ctx, conversationID, newConversationID, i, messages[i].Content)
+		}
+		if err := memory.BranchBefore(a.memoryContext(ctx), a.memory, conversationID, newConversationID, messageID); err != nil {
+			return nil, err
+		}
+		return a.RunDetailed(memory.WithConversationID(ctx, newConversationID), messages[i].Content)
+	}
+	return nil, fmt.Errorf("conversation %s has no user message to regenerate", conversationID)
+}
+
+// memoryContext applies the agent's organization the same way runs do
+func (a *Agent) memoryContext(ctx context.Context) context.Context {
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+	return ctx
+}
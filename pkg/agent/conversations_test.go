@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+func TestBranchConversationConcurrently(t *testing.T) {
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{name: "mock"}),
+		WithMemory(memory.NewConversationBuffer()),
+		WithOrgID("org1"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := agent.ImportConversation(ctx, "conv1", []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "Hi"},
+		{Role: interfaces.MessageRoleAssistant, Content: "Hello!"},
+	}); err != nil {
+		t.Fatalf("ImportConversation: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = agent.BranchConversation(ctx, "conv1", "conv2", 2)
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrConversationExists):
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("Expected exactly one branch to succeed, got %d", succeeded)
+	}
+
+	messages, err := agent.ExportConversation(ctx, "conv2")
+	if err != nil {
+		t.Fatalf("ExportConversation: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Errorf("Expected the branch to hold 2 messages, got %d", len(messages))
+	}
+}
+
+func TestRegenerateTrimmedConversation(t *testing.T) {
+	// The window holds six messages of one token each
+	window := memory.NewTokenWindowBuffer(6,
+		memory.TokenizerFunc(func(string) (int, error) { return 1, nil }),
+		memory.WithMessageTokenOverhead(0))
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{name: "mock", generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			return "Regenerated", nil
+		}}),
+		WithMemory(window),
+		WithOrgID("org1"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	// Older messages are trimmed, so the stored messages no longer start at
+	// the beginning of the conversation
+	ctx := memory.WithConversationID(context.Background(), "conv1")
+	for _, message := range []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "Hi"},
+		{Role: interfaces.MessageRoleAssistant, Content: "Hello!"},
+		{Role: interfaces.MessageRoleUser, Content: "Tell me a joke"},
+		{Role: interfaces.MessageRoleAssistant, Content: "Why did the gopher cross the road?"},
+		{Role: interfaces.MessageRoleUser, Content: "Another one"},
+		{Role: interfaces.MessageRoleAssistant, Content: "No."},
+	} {
+		if err := window.AddMessage(agent.memoryContext(ctx), message); err != nil {
+			t.Fatalf("AddMessage: %v", err)
+		}
+	}
+
+	response, err := agent.Regenerate(context.Background(), "conv1", "conv2")
+	if err != nil {
+		t.Fatalf("Regenerate: %v", err)
+	}
+	if response.Content != "Regenerated" {
+		t.Errorf("Expected the regenerated response, got %q", response.Content)
+	}
+
+	messages, err := agent.ExportConversation(context.Background(), "conv2")
+	if err != nil {
+		t.Fatalf("ExportConversation: %v", err)
+	}
+	if len(messages) < 3 {
+		t.Fatalf("Expected the branch to hold the regenerated turn, got %d messages", len(messages))
+	}
+	last := messages[len(messages)-3:]
+	if last[0].Content != "Why did the gopher cross the road?" || last[1].Content != "Another one" || last[2].Content != "Regenerated" {
+		t.Errorf("Expected the branch to fork before the last user message, got %v", last)
+	}
+
+	original, err := agent.ExportConversation(context.Background(), "conv1")
+	if err != nil {
+		t.Fatalf("ExportConversation: %v", err)
+	}
+	if original[len(original)-1].Content != "No." {
+		t.Errorf("Expected the original conversation to keep its response, got %q", original[len(original)-1].Content)
+	}
+}
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestWithDeterministic(t *testing.T) {
+	var config interfaces.LLMConfig
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			params := &interfaces.GenerateOptions{}
+			for _, option := range options {
+				option(params)
+			}
+			if params.LLMConfig != nil {
+				config = *params.LLMConfig
+			}
+			return "response", nil
+		},
+	}
+
+	agentConfig := interfaces.LLMConfig{Temperature: 0.9, TopP: 0.5}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithLLMConfig(agentConfig),
+		WithDeterministic(42),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	response, err := agent.RunDetailed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if config.Temperature != 0 || !config.Deterministic || config.Seed == nil || *config.Seed != 42 {
+		t.Errorf("Expected temperature 0 and seed 42, got %+v", config)
+	}
+	if config.TopP != 0.5 {
+		t.Errorf("Expected the rest of the LLM config to be kept, got %+v", config)
+	}
+	if agent.llmConfig.Temperature != 0.9 || agent.llmConfig.Seed != nil {
+		t.Errorf("Expected the agent's LLM config to be left untouched, got %+v", *agent.llmConfig)
+	}
+	if response.Metadata["seed"] != 42 {
+		t.Errorf("Expected the seed in the response metadata, got %v", response.Metadata["seed"])
+	}
+}
+
+func TestConvertLLMConfigYAMLDeterministic(t *testing.T) {
+	temperature := 0.7
+	seed := 7
+	deterministic := true
+	config := convertLLMConfigYAMLToInterface(&LLMConfigYAML{
+		Temperature:   &temperature,
+		Seed:          &seed,
+		Deterministic: &deterministic,
+	})
+
+	if config.Temperature != 0 || !config.Deterministic || config.Seed == nil || *config.Seed != 7 {
+		t.Errorf("Expected temperature 0 and seed 7, got %+v", config)
+	}
+}
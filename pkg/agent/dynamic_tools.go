@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// AddTool registers a tool with a running agent. Runs started afterwards
+// can use it, and so can runs in progress from their next tool-calling
+// iteration where the LLM supports interfaces.WithToolsFunc. It is safe to
+// call concurrently with runs.
+func (a *Agent) AddTool(tool interfaces.Tool) error {
+	if tool == nil || tool.Name() == "" {
+		return fmt.Errorf("tool must have a name")
+	}
+
+	a.toolsMu.Lock()
+	defer a.toolsMu.Unlock()
+
+	for _, t := range a.tools {
+		if t.Name() == tool.Name() {
+			return fmt.Errorf("tool %q is already registered", tool.Name())
+		}
+	}
+	a.tools = append(a.tools[:len(a.tools):len(a.tools)], tool)
+	return nil
+}
+
+// RemoveTool removes the tool with the given name from a running agent, and
+// reports whether it was registered. Like AddTool, it takes effect for runs
+// in progress from their next tool-calling iteration.
+func (a *Agent) RemoveTool(name string) bool {
+	a.toolsMu.Lock()
+	defer a.toolsMu.Unlock()
+
+	for i, t := range a.tools {
+		if t.Name() == name {
+			// Copy so that snapshots taken by runs in progress are unchanged
+			tools := make([]interfaces.Tool, 0, len(a.tools)-1)
+			tools = append(tools, a.tools[:i]...)
+			a.tools = append(tools, a.tools[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// currentTools returns a snapshot of the registered tools
+func (a *Agent) currentTools() []interfaces.Tool {
+	a.toolsMu.RLock()
+	defer a.toolsMu.RUnlock()
+	return a.tools
+}
+
+// toolsFunc returns the tools of a run for each of its tool-calling
+// iterations: the tools it started with, without the ones removed since,
+// followed by the ones registered since. registered are the tools that were
+// registered when the run started.
+func (a *Agent) toolsFunc(tools, registered []interfaces.Tool) func() []interfaces.Tool {
+	before := make(map[string]bool, len(registered))
+	for _, tool := range registered {
+		before[tool.Name()] = true
+	}
+
+	return func() []interfaces.Tool {
+		current := a.currentTools()
+		now := make(map[string]bool, len(current))
+		for _, tool := range current {
+			now[tool.Name()] = true
+		}
+
+		result := make([]interfaces.Tool, 0, len(tools))
+		for _, tool := range tools {
+			if before[tool.Name()] && !now[tool.Name()] {
+				continue
+			}
+			result = append(result, tool)
+		}
+		for _, tool := range current {
+			if !before[tool.Name()] {
+				result = append(result, tool)
+			}
+		}
+		return availableTools(deduplicateTools(result))
+	}
+}
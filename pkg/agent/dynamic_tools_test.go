@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func toolNames(tools []interfaces.Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name()
+	}
+	return names
+}
+
+func TestAddAndRemoveTool(t *testing.T) {
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{name: "mock"}),
+		WithTools(&mockTool{name: "search", description: "Search the web"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	if err := agent.AddTool(&mockTool{name: "fetch", description: "Fetch a page"}); err != nil {
+		t.Fatalf("AddTool: %v", err)
+	}
+	if err := agent.AddTool(&mockTool{name: "search", description: "Search again"}); err == nil {
+		t.Error("Expected an error when registering a tool name twice")
+	}
+
+	snapshot := agent.GetTools()
+	if !agent.RemoveTool("search") {
+		t.Error("Expected RemoveTool to report the registered tool")
+	}
+	if agent.RemoveTool("search") {
+		t.Error("Expected RemoveTool to report a missing tool")
+	}
+
+	if names := toolNames(agent.GetTools()); len(names) != 1 || names[0] != "fetch" {
+		t.Errorf("Expected only fetch to be registered, got %v", names)
+	}
+	if names := toolNames(snapshot); len(names) != 2 {
+		t.Errorf("Expected the earlier snapshot to be unchanged, got %v", names)
+	}
+}
+
+func TestToolsChangeDuringRun(t *testing.T) {
+	var agent *Agent
+	var iterationTools []string
+	llm := &mockLLM{name: "mock", generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+		params := &interfaces.GenerateOptions{}
+		for _, option := range options {
+			option(params)
+		}
+		if params.ToolsFunc == nil {
+			t.Fatal("Expected the run to pass the current tools to the LLM")
+		}
+
+		// Tools change while the LLM calls tools
+		if err := agent.AddTool(&mockTool{name: "fetch", description: "Fetch a page"}); err != nil {
+			t.Fatalf("AddTool: %v", err)
+		}
+		agent.RemoveTool("search")
+		iterationTools = toolNames(params.ToolsFunc())
+		return "done", nil
+	}}
+
+	var err error
+	agent, err = NewAgent(
+		WithLLM(llm),
+		WithTools(
+			&mockTool{name: "search", description: "Search the web"},
+			&mockTool{name: "calculator", description: "Do math"},
+		),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	if _, err := agent.Run(context.Background(), "Read the page"); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(iterationTools) != 2 || iterationTools[0] != "calculator" || iterationTools[1] != "fetch" {
+		t.Errorf("Expected calculator and fetch in the next iteration, got %v", iterationTools)
+	}
+}
+
+// toolsFuncStreamingLLM calls onToolsStream with the options of each
+// streaming call with tools
+type toolsFuncStreamingLLM struct {
+	*StreamingMockLLM
+	onToolsStream func(params *interfaces.GenerateOptions)
+}
+
+func (m *toolsFuncStreamingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	params := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(params)
+	}
+	m.onToolsStream(params)
+	return m.GenerateStream(ctx, prompt, options...)
+}
+
+func TestToolsChangeDuringRunStream(t *testing.T) {
+	var agent *Agent
+	var iterationTools []string
+	llm := &toolsFuncStreamingLLM{
+		StreamingMockLLM: &StreamingMockLLM{llmName: "mock", responseContent: "done"},
+		onToolsStream: func(params *interfaces.GenerateOptions) {
+			if params.ToolsFunc == nil {
+				t.Fatal("Expected the run to pass the current tools to the LLM")
+			}
+
+			// Tools change while the LLM calls tools
+			if err := agent.AddTool(&mockTool{name: "fetch", description: "Fetch a page"}); err != nil {
+				t.Fatalf("AddTool: %v", err)
+			}
+			agent.RemoveTool("search")
+			iterationTools = toolNames(params.ToolsFunc())
+		},
+	}
+
+	var err error
+	agent, err = NewAgent(
+		WithLLM(llm),
+		WithTools(
+			&mockTool{name: "search", description: "Search the web"},
+			&mockTool{name: "calculator", description: "Do math"},
+		),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	events, err := agent.RunStream(context.Background(), "Read the page")
+	if err != nil {
+		t.Fatalf("RunStream: %v", err)
+	}
+	for event := range events {
+		if event.Type == interfaces.AgentEventError {
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		}
+	}
+
+	if len(iterationTools) != 2 || iterationTools[0] != "calculator" || iterationTools[1] != "fetch" {
+		t.Errorf("Expected calculator and fetch in the next iteration, got %v", iterationTools)
+	}
+}
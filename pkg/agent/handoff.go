@@ -0,0 +1,284 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// MetadataHandoffTo is the metadata key of the agent a run was handed off
+// to, set in the metadata of RunDetailed's response
+const MetadataHandoffTo = "handoff_to"
+
+// Handoff lets an agent transfer the conversation to another agent, which
+// then answers instead of it
+type Handoff struct {
+	// Agent is the agent taking over the conversation
+	Agent *Agent
+
+	// Description tells the model when to hand off, the agent's description
+	// by default
+	Description string
+
+	// MaxMessages limits the transferred messages to the most recent ones,
+	// all of them when zero. Without memory no messages are transferred.
+	MaxMessages int
+
+	// Roles are the roles of the transferred messages, user and assistant by
+	// default
+	Roles []string
+}
+
+// WithHandoffs lets the agent transfer the conversation to other agents.
+// Each handoff is offered to the model as a transfer_to_<agent> tool; when
+// the model calls it, the other agent answers the input with the recent
+// conversation and the reason for the handoff. Streams emit an
+// AgentEventHandoff event before the events of the other agent.
+func WithHandoffs(handoffs ...Handoff) Option {
+	return func(a *Agent) {
+		a.handoffs = append(a.handoffs, handoffs...)
+		for _, handoff := range handoffs {
+			a.tools = append(a.tools, &handoffTool{handoff: handoff})
+		}
+	}
+}
+
+// GetHandoffs returns the handoffs of the agent
+func (a *Agent) GetHandoffs() []Handoff {
+	return a.handoffs
+}
+
+// handoffKey is the context key of the handoff state of a run
+type handoffKey struct{}
+
+// handoffState records the handoff requested during a run. The first
+// handoff wins.
+type handoffState struct {
+	mu      sync.Mutex
+	handoff *Handoff
+	reason  string
+}
+
+func (s *handoffState) request(handoff Handoff, reason string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.handoff != nil {
+		return false
+	}
+	s.handoff = &handoff
+	s.reason = reason
+	return true
+}
+
+func (s *handoffState) requested() (*Handoff, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handoff, s.reason
+}
+
+// withHandoff returns a context recording the handoff requested during a
+// run, nil if the agent has no handoffs
+func (a *Agent) withHandoff(ctx context.Context) (context.Context, *handoffState) {
+	if len(a.handoffs) == 0 {
+		return ctx, nil
+	}
+	state := &handoffState{}
+	return context.WithValue(ctx, handoffKey{}, state), state
+}
+
+// handOff runs the agent of a handoff with the input, the transferred
+// conversation and the reason of the handoff
+func (a *Agent) handOff(ctx context.Context, handoff *Handoff, reason, input string) (string, error) {
+	return handoff.Agent.Run(ctx, a.handoffInput(ctx, handoff, reason, input))
+}
+
+// handoffStream forwards the events of a stream. If the model handed off,
+// the complete events are replaced by a handoff event and the events of the
+// other agent's stream.
+func (a *Agent) handoffStream(ctx context.Context, events <-chan interfaces.AgentStreamEvent, state *handoffState, input string) <-chan interfaces.AgentStreamEvent {
+	if state == nil {
+		return events
+	}
+
+	out := make(chan interfaces.AgentStreamEvent, cap(events))
+	go func() {
+		defer close(out)
+
+		var completed []interfaces.AgentStreamEvent
+		delivered := true
+		for event := range events {
+			if event.Type == interfaces.AgentEventComplete {
+				completed = append(completed, event)
+				continue
+			}
+			// Keep draining after cancellation so the producer can exit
+			if delivered {
+				delivered = sendEvent(ctx, out, event)
+			}
+		}
+		if !delivered {
+			return
+		}
+
+		handoff, reason := state.requested()
+		if handoff == nil {
+			for _, event := range completed {
+				if !sendEvent(ctx, out, event) {
+					return
+				}
+			}
+			return
+		}
+
+		if !sendEvent(ctx, out, interfaces.AgentStreamEvent{
+			Type:      interfaces.AgentEventHandoff,
+			Content:   reason,
+			Timestamp: time.Now(),
+			Metadata: map[string]interface{}{
+				"from": a.name,
+				"to":   handoff.Agent.GetName(),
+			},
+		}) {
+			return
+		}
+
+		handedOff, err := handoff.Agent.RunStream(ctx, a.handoffInput(ctx, handoff, reason, input))
+		if err != nil {
+			sendEvent(ctx, out, interfaces.AgentStreamEvent{
+				Type:      interfaces.AgentEventError,
+				Error:     fmt.Errorf("failed to hand off to %s: %w", handoff.Agent.GetName(), err),
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		for event := range handedOff {
+			if delivered {
+				delivered = sendEvent(ctx, out, event)
+			}
+		}
+	}()
+	return out
+}
+
+// handoffInput prefixes the input with the reason of the handoff and the
+// recent messages of the conversation
+func (a *Agent) handoffInput(ctx context.Context, handoff *Handoff, reason, input string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The conversation was transferred to you by %s.", a.name)
+	if reason != "" {
+		fmt.Fprintf(&b, " Reason: %s", reason)
+	}
+	b.WriteString("\n\n")
+
+	if messages := a.handoffMessages(ctx, handoff, input); len(messages) > 0 {
+		b.WriteString("Conversation so far:\n")
+		for _, message := range messages {
+			fmt.Fprintf(&b, "%s: %s\n", message.Role, message.Content)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(input)
+	return b.String()
+}
+
+// handoffMessages returns the messages of the conversation before the input
+// to transfer
+func (a *Agent) handoffMessages(ctx context.Context, handoff *Handoff, input string) []interfaces.Message {
+	if a.memory == nil {
+		return nil
+	}
+
+	roles := handoff.Roles
+	if len(roles) == 0 {
+		roles = []string{string(interfaces.MessageRoleUser), string(interfaces.MessageRoleAssistant)}
+	}
+	messages, err := a.memory.GetMessages(ctx, interfaces.WithRoles(roles...))
+	if err != nil {
+		a.logger.Warn(ctx, "Failed to get messages to hand off", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	// The input and the reply handing off were added to memory during the
+	// run, the conversation so far ends before them
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == interfaces.MessageRoleUser && messages[i].Content == input {
+			messages = messages[:i]
+			break
+		}
+	}
+	if handoff.MaxMessages > 0 && len(messages) > handoff.MaxMessages {
+		messages = messages[len(messages)-handoff.MaxMessages:]
+	}
+	return messages
+}
+
+// invalidToolNameChars matches the characters not allowed in tool names
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// handoffTool requests a handoff when the model calls it
+type handoffTool struct {
+	handoff Handoff
+}
+
+func (t *handoffTool) Name() string {
+	return "transfer_to_" + strings.ToLower(invalidToolNameChars.ReplaceAllString(t.handoff.Agent.GetName(), "_"))
+}
+
+// DisplayName implements interfaces.ToolWithDisplayName.DisplayName
+func (t *handoffTool) DisplayName() string {
+	return fmt.Sprintf("Transfer to %s", t.handoff.Agent.GetName())
+}
+
+func (t *handoffTool) Description() string {
+	description := t.handoff.Description
+	if description == "" {
+		description = t.handoff.Agent.GetDescription()
+	}
+	return fmt.Sprintf("Transfer the conversation to %s, who then answers the user instead of you. %s", t.handoff.Agent.GetName(), description)
+}
+
+func (t *handoffTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"reason": {
+			Type:        "string",
+			Description: "Why the conversation is transferred, with what the other agent needs to know",
+			Required:    false,
+		},
+	}
+}
+
+func (t *handoffTool) Run(ctx context.Context, input string) (string, error) {
+	return t.transfer(ctx, input)
+}
+
+func (t *handoffTool) Execute(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Reason string `json:"reason"`
+	}
+	if args != "" {
+		if err := json.Unmarshal([]byte(args), &params); err != nil {
+			return "", fmt.Errorf("failed to parse handoff arguments: %w", err)
+		}
+	}
+	return t.transfer(ctx, params.Reason)
+}
+
+func (t *handoffTool) transfer(ctx context.Context, reason string) (string, error) {
+	state, ok := ctx.Value(handoffKey{}).(*handoffState)
+	if !ok {
+		return "", fmt.Errorf("handoff to %s is only available during a run", t.handoff.Agent.GetName())
+	}
+	if !state.request(t.handoff, reason) {
+		return "The conversation was already transferred.", nil
+	}
+	return fmt.Sprintf("The conversation was transferred to %s, who will answer the user. Do not answer the request yourself.", t.handoff.Agent.GetName()), nil
+}
@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// handoffLLM calls the tool named toolName, if it is offered, then answers
+// with response. It records the prompts it receives.
+type handoffLLM struct {
+	mockLLM
+	toolName string
+	args     string
+	response string
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+func (m *handoffLLM) generate(ctx context.Context, prompt string, tools []interfaces.Tool) (string, error) {
+	m.mu.Lock()
+	m.prompts = append(m.prompts, prompt)
+	m.mu.Unlock()
+	for _, tool := range tools {
+		if tool.Name() == m.toolName {
+			if _, err := tool.Execute(ctx, m.args); err != nil {
+				return "", err
+			}
+		}
+	}
+	return m.response, nil
+}
+
+func (m *handoffLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return m.generate(ctx, prompt, nil)
+}
+
+func (m *handoffLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.generate(ctx, prompt, tools)
+}
+
+func (m *handoffLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := m.Generate(ctx, prompt, options...)
+	return &interfaces.LLMResponse{Content: content}, err
+}
+
+func (m *handoffLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := m.GenerateWithTools(ctx, prompt, tools, options...)
+	return &interfaces.LLMResponse{Content: content}, err
+}
+
+func (m *handoffLLM) SupportsStreaming() bool { return true }
+
+func (m *handoffLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return m.GenerateWithToolsStream(ctx, prompt, nil, options...)
+}
+
+func (m *handoffLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	content, err := m.generate(ctx, prompt, tools)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan interfaces.StreamEvent, 2)
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: content, Timestamp: time.Now()}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventMessageStop, Timestamp: time.Now()}
+	close(events)
+	return events, nil
+}
+
+func newHandoffAgents(t *testing.T, options ...Option) (*Agent, *handoffLLM) {
+	t.Helper()
+	billingLLM := &handoffLLM{response: "Your refund is on its way"}
+	billing, err := NewAgent(WithName("Billing"), WithDescription("Handles refunds"), WithLLM(billingLLM))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	triage, err := NewAgent(append([]Option{
+		WithName("Triage"),
+		WithLLM(&handoffLLM{toolName: "transfer_to_billing", args: `{"reason": "refund request"}`, response: "Transferring you"}),
+		WithMemory(memory.NewConversationBuffer()),
+		WithRequirePlanApproval(false),
+		WithHandoffs(Handoff{Agent: billing, MaxMessages: 1}),
+	}, options...)...)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	return triage, billingLLM
+}
+
+func TestHandoff_Run(t *testing.T) {
+	triage, billingLLM := newHandoffAgents(t)
+	ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "org-1"), "conversation-1")
+	if err := triage.memory.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "Hi, I was charged twice"}); err != nil {
+		t.Fatalf("Failed to add message: %v", err)
+	}
+	if err := triage.memory.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleAssistant, Content: "Which order?"}); err != nil {
+		t.Fatalf("Failed to add message: %v", err)
+	}
+
+	response, err := triage.RunDetailed(ctx, "Order 42, please refund it")
+	if err != nil {
+		t.Fatalf("RunDetailed() error = %v", err)
+	}
+	if response.Content != "Your refund is on its way" {
+		t.Errorf("Expected the answer of the billing agent, got %q", response.Content)
+	}
+	if response.Metadata[MetadataHandoffTo] != "Billing" {
+		t.Errorf("Expected the handoff in the metadata, got %v", response.Metadata)
+	}
+
+	prompt := billingLLM.prompts[0]
+	for _, want := range []string{"transferred to you by Triage. Reason: refund request", "assistant: Which order?", "Order 42, please refund it"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected %q in the handed off input, got %q", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "charged twice") {
+		t.Errorf("Expected only the last message to be transferred, got %q", prompt)
+	}
+}
+
+func TestHandoff_RunStream(t *testing.T) {
+	triage, _ := newHandoffAgents(t)
+	ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "org-1"), "conversation-1")
+
+	events, err := triage.RunStream(ctx, "Please refund order 42")
+	if err != nil {
+		t.Fatalf("RunStream() error = %v", err)
+	}
+
+	var types []interfaces.AgentEventType
+	var content strings.Builder
+	var handoff interfaces.AgentStreamEvent
+	for event := range events {
+		types = append(types, event.Type)
+		switch event.Type {
+		case interfaces.AgentEventHandoff:
+			handoff = event
+		case interfaces.AgentEventContent:
+			content.WriteString(event.Content)
+		}
+	}
+
+	if handoff.Metadata["from"] != "Triage" || handoff.Metadata["to"] != "Billing" || handoff.Content != "refund request" {
+		t.Errorf("Expected a handoff event from Triage to Billing, got %+v", handoff)
+	}
+	if content.String() != "Transferring youYour refund is on its way" {
+		t.Errorf("Expected the content of both agents, got %q", content.String())
+	}
+	var completes int
+	for i, eventType := range types {
+		if eventType == interfaces.AgentEventComplete {
+			completes++
+			if i != len(types)-1 {
+				t.Errorf("Expected the complete event last, got %v", types)
+			}
+		}
+	}
+	if completes != 1 {
+		t.Errorf("Expected one complete event, got %v", types)
+	}
+}
+
+func TestHandoff_NotRequested(t *testing.T) {
+	billing, err := NewAgent(WithName("Billing"), WithLLM(&handoffLLM{}))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	triage, err := NewAgent(
+		WithName("Triage"),
+		WithLLM(&handoffLLM{response: "Hello!"}),
+		WithRequirePlanApproval(false),
+		WithHandoffs(Handoff{Agent: billing}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	response, err := triage.RunDetailed(context.Background(), "Hi")
+	if err != nil || response.Content != "Hello!" {
+		t.Fatalf("Expected the triage answer, got %v, %v", response, err)
+	}
+	if _, ok := response.Metadata[MetadataHandoffTo]; ok {
+		t.Error("Expected no handoff")
+	}
+}
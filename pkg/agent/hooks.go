@@ -0,0 +1,218 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Hooks are callbacks on the lifecycle of the agent's runs, for cross-cutting
+// concerns such as tracing, cost limits, custom logging or notifications.
+// Every callback is optional. The callbacks of a run are called from the
+// goroutine running it; tool callbacks may be called concurrently when the
+// model calls several tools at once.
+type Hooks struct {
+	// OnRunStart is called when a run starts. Returning an error fails the
+	// run before the LLM is called.
+	OnRunStart func(ctx context.Context, input string) error
+
+	// OnLLMCall is called before the agent sends a request to its LLM. The
+	// tool-calling loop of a request is one call. Returning an error fails
+	// the run.
+	OnLLMCall func(ctx context.Context, call LLMCall) error
+
+	// OnToolCall is called before a tool runs. Returning an error fails the
+	// tool call, which is reported to the model like other tool errors.
+	OnToolCall func(ctx context.Context, tool, args string) error
+
+	// OnToolResult is called after a tool ran, with its result or error
+	OnToolResult func(ctx context.Context, tool, args, result string, err error)
+
+	// OnRunEnd is called when a run succeeds
+	OnRunEnd func(ctx context.Context, run RunResult)
+
+	// OnError is called when a run fails
+	OnError func(ctx context.Context, input string, err error)
+}
+
+// LLMCall describes a request the agent is about to send to its LLM
+type LLMCall struct {
+	Model  string   // Model of the LLM, when it reports one
+	Prompt string   // Prompt of the request
+	Tools  []string // Names of the tools offered to the model
+	Stream bool     // Whether the response is streamed
+}
+
+// RunResult describes a successful run
+type RunResult struct {
+	Input    string
+	Response string
+	Usage    *interfaces.TokenUsage // Token usage, when tracked (nil for streaming runs)
+	Duration time.Duration
+}
+
+// WithHooks adds callbacks on the lifecycle of the agent's runs. It can be
+// passed several times; the hooks are called in the order they were added.
+//
+//	agent.WithHooks(agent.Hooks{
+//		OnToolCall: func(ctx context.Context, tool, args string) error {
+//			log.Printf("calling %s(%s)", tool, args)
+//			return nil
+//		},
+//	})
+func WithHooks(hooks Hooks) Option {
+	return func(a *Agent) {
+		a.hooks = append(a.hooks, hooks)
+	}
+}
+
+// hookRunStart calls the OnRunStart hooks, stopping at the first error
+func (a *Agent) hookRunStart(ctx context.Context, input string) error {
+	for _, hooks := range a.hooks {
+		if hooks.OnRunStart != nil {
+			if err := hooks.OnRunStart(ctx, input); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hookLLMCall calls the OnLLMCall hooks, stopping at the first error
+func (a *Agent) hookLLMCall(ctx context.Context, prompt string, tools []interfaces.Tool, stream bool) error {
+	if len(a.hooks) == 0 {
+		return nil
+	}
+
+	call := LLMCall{Prompt: prompt, Stream: stream}
+	if modelLLM, ok := a.llm.(interface{ GetModel() string }); ok {
+		call.Model = modelLLM.GetModel()
+	}
+	for _, tool := range tools {
+		call.Tools = append(call.Tools, tool.Name())
+	}
+
+	for _, hooks := range a.hooks {
+		if hooks.OnLLMCall != nil {
+			if err := hooks.OnLLMCall(ctx, call); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hookRunEnd calls the OnRunEnd hooks, or the OnError hooks when err is set
+func (a *Agent) hookRunEnd(ctx context.Context, run RunResult, err error) {
+	for _, hooks := range a.hooks {
+		switch {
+		case err != nil && hooks.OnError != nil:
+			hooks.OnError(ctx, run.Input, err)
+		case err == nil && hooks.OnRunEnd != nil:
+			hooks.OnRunEnd(ctx, run)
+		}
+	}
+}
+
+// hookStream forwards stream events and calls the OnRunEnd or OnError hooks
+// when the stream ends
+func (a *Agent) hookStream(ctx context.Context, input string, startTime time.Time, events <-chan interfaces.AgentStreamEvent) <-chan interfaces.AgentStreamEvent {
+	if len(a.hooks) == 0 {
+		return events
+	}
+
+	out := make(chan interfaces.AgentStreamEvent, cap(events))
+	go func() {
+		defer close(out)
+
+		var content strings.Builder
+		var streamErr error
+		delivered := true
+		for event := range events {
+			switch event.Type {
+			case interfaces.AgentEventContent:
+				content.WriteString(event.Content)
+			case interfaces.AgentEventError:
+				streamErr = event.Error
+			}
+			// Keep draining after cancellation so the producer can exit
+			if delivered {
+				delivered = sendEvent(ctx, out, event)
+			}
+		}
+
+		run := RunResult{Input: input, Response: content.String(), Duration: time.Since(startTime)}
+		a.hookRunEnd(context.WithoutCancel(ctx), run, streamErr)
+	}()
+	return out
+}
+
+// wrapToolsWithHooks wraps each tool so its calls and results are reported
+// to the OnToolCall and OnToolResult hooks. Returns the original slice
+// unchanged when no hooks are configured.
+func (a *Agent) wrapToolsWithHooks(tools []interfaces.Tool) []interfaces.Tool {
+	if len(a.hooks) == 0 || len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = &hookedTool{inner: t, agent: a}
+	}
+	return wrapped
+}
+
+// hookedTool reports the calls and results of a tool to the agent's hooks
+type hookedTool struct {
+	inner interfaces.Tool
+	agent *Agent
+}
+
+func (t *hookedTool) Name() string        { return t.inner.Name() }
+func (t *hookedTool) Description() string { return t.inner.Description() }
+func (t *hookedTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.inner.Parameters()
+}
+
+func (t *hookedTool) Run(ctx context.Context, input string) (string, error) {
+	return t.call(ctx, input, t.inner.Run)
+}
+
+func (t *hookedTool) Execute(ctx context.Context, args string) (string, error) {
+	return t.call(ctx, args, t.inner.Execute)
+}
+
+func (t *hookedTool) call(ctx context.Context, args string, run func(context.Context, string) (string, error)) (string, error) {
+	for _, hooks := range t.agent.hooks {
+		if hooks.OnToolCall != nil {
+			if err := hooks.OnToolCall(ctx, t.inner.Name(), args); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	result, err := run(ctx, args)
+	for _, hooks := range t.agent.hooks {
+		if hooks.OnToolResult != nil {
+			hooks.OnToolResult(ctx, t.inner.Name(), args, result, err)
+		}
+	}
+	return result, err
+}
+
+// DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
+func (t *hookedTool) DisplayName() string {
+	if d, ok := t.inner.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.inner.Name()
+}
+
+// Internal forwards to the inner tool when it implements InternalTool.
+func (t *hookedTool) Internal() bool {
+	if i, ok := t.inner.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
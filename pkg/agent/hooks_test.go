@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestHooks(t *testing.T) {
+	var calls []string
+	var llmCall LLMCall
+	var result RunResult
+	echo := &mockTool{
+		name: "echo",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return input, nil
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithTools(echo),
+		WithRequirePlanApproval(false),
+		WithHooks(Hooks{
+			OnRunStart: func(ctx context.Context, input string) error {
+				calls = append(calls, "start "+input)
+				return nil
+			},
+			OnLLMCall: func(ctx context.Context, call LLMCall) error {
+				calls = append(calls, "llm")
+				llmCall = call
+				return nil
+			},
+			OnRunEnd: func(ctx context.Context, run RunResult) {
+				calls = append(calls, "end")
+				result = run
+			},
+		}),
+		WithHooks(Hooks{
+			OnToolCall: func(ctx context.Context, tool, args string) error {
+				calls = append(calls, "tool "+tool)
+				if args == "deny" {
+					return errors.New("denied")
+				}
+				return nil
+			},
+			OnToolResult: func(ctx context.Context, tool, args, result string, err error) {
+				calls = append(calls, "result "+result)
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	if _, err := agent.Run(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"start hello", "llm", "end"}) {
+		t.Errorf("unexpected hook calls %v", calls)
+	}
+	if !reflect.DeepEqual(llmCall.Tools, []string{"echo"}) || llmCall.Stream {
+		t.Errorf("unexpected LLM call %+v", llmCall)
+	}
+	if result.Response != "mock response" || result.Usage == nil || result.Usage.TotalTokens != 150 {
+		t.Errorf("unexpected run result %+v", result)
+	}
+
+	// Tool hooks see each call and can veto it
+	calls = nil
+	wrapped := agent.wrapToolsWithHooks([]interfaces.Tool{echo})
+	if out, err := wrapped[0].Execute(context.Background(), "hi"); err != nil || out != "hi" {
+		t.Errorf("expected the tool to run, got %q, %v", out, err)
+	}
+	if _, err := wrapped[0].Execute(context.Background(), "deny"); err == nil || err.Error() != "denied" {
+		t.Errorf("expected the tool call to be denied, got %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"tool echo", "result hi", "tool echo"}) {
+		t.Errorf("unexpected tool hook calls %v", calls)
+	}
+}
+
+func TestHooksRejectRun(t *testing.T) {
+	limit := errors.New("budget exceeded")
+	var failed error
+	llm := &mockLLM{generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+		t.Error("expected the LLM not to be called")
+		return "", nil
+	}}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithRequirePlanApproval(false),
+		WithHooks(Hooks{
+			OnRunStart: func(ctx context.Context, input string) error {
+				return limit
+			},
+			OnRunEnd: func(ctx context.Context, run RunResult) {
+				t.Error("expected OnRunEnd not to be called")
+			},
+			OnError: func(ctx context.Context, input string, err error) {
+				failed = err
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	if _, err := agent.Run(context.Background(), "hello"); !errors.Is(err, limit) {
+		t.Errorf("expected the run to be rejected, got %v", err)
+	}
+	if !errors.Is(failed, limit) {
+		t.Errorf("expected OnError to receive the error, got %v", failed)
+	}
+}
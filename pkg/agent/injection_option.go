@@ -0,0 +1,115 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/compliance"
+	"github.com/Ingenimax/agent-sdk-go/pkg/guardrails"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// injectionAuditEvent is the audit event type recorded when injected
+// instructions are found in untrusted content
+const injectionAuditEvent = "prompt_injection"
+
+// WithInjectionDetection scans tool outputs and retrieved context for
+// injected instructions before the model sees them. Depending on the
+// detector's action, findings are stripped, flagged or blocked (a blocked
+// tool call fails, blocked retrieved context is dropped). Every finding is
+// logged and recorded as an audit event on the compliance run, if any.
+//
+//	agent.WithInjectionDetection(guardrails.NewInjectionDetector(guardrails.RedactAction))
+func WithInjectionDetection(detector *guardrails.InjectionDetector) Option {
+	return func(a *Agent) {
+		a.injectionDetector = detector
+	}
+}
+
+// screenUntrusted applies the injection detector to content from source
+func (a *Agent) screenUntrusted(ctx context.Context, source, content string) (string, error) {
+	processed, findings, err := a.injectionDetector.Process(content)
+	if len(findings) == 0 {
+		return processed, err
+	}
+
+	patterns := make([]string, len(findings))
+	matches := make([]string, len(findings))
+	for i, finding := range findings {
+		patterns[i] = finding.Pattern
+		matches[i] = finding.Match
+	}
+
+	a.logger.Warn(ctx, "Possible prompt injection in untrusted content", map[string]interface{}{
+		"source":   source,
+		"action":   a.injectionDetector.Action(),
+		"patterns": patterns,
+	})
+	compliance.RecordAuditEvent(ctx, compliance.AuditEvent{
+		Type:   injectionAuditEvent,
+		Source: source,
+		Details: map[string]interface{}{
+			"action":   string(a.injectionDetector.Action()),
+			"patterns": patterns,
+			"matches":  matches,
+		},
+	})
+
+	return processed, err
+}
+
+// wrapToolsWithInjectionGuard wraps each tool so its output is screened.
+// Returns the original slice unchanged when no detector is configured.
+func (a *Agent) wrapToolsWithInjectionGuard(tools []interfaces.Tool) []interfaces.Tool {
+	if a.injectionDetector == nil || len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = &injectionGuardTool{inner: t, agent: a}
+	}
+	return wrapped
+}
+
+// injectionGuardTool screens the output of a tool for injected instructions
+type injectionGuardTool struct {
+	inner interfaces.Tool
+	agent *Agent
+}
+
+func (t *injectionGuardTool) Name() string        { return t.inner.Name() }
+func (t *injectionGuardTool) Description() string { return t.inner.Description() }
+func (t *injectionGuardTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.inner.Parameters()
+}
+
+func (t *injectionGuardTool) Run(ctx context.Context, input string) (string, error) {
+	result, err := t.inner.Run(ctx, input)
+	if err != nil {
+		return result, err
+	}
+	return t.agent.screenUntrusted(ctx, "tool:"+t.inner.Name(), result)
+}
+
+func (t *injectionGuardTool) Execute(ctx context.Context, args string) (string, error) {
+	result, err := t.inner.Execute(ctx, args)
+	if err != nil {
+		return result, err
+	}
+	return t.agent.screenUntrusted(ctx, "tool:"+t.inner.Name(), result)
+}
+
+// DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
+func (t *injectionGuardTool) DisplayName() string {
+	if d, ok := t.inner.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.inner.Name()
+}
+
+// Internal forwards to the inner tool when it implements InternalTool.
+func (t *injectionGuardTool) Internal() bool {
+	if i, ok := t.inner.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/embedding"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/knowledge"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/retriever"
+	vectormemory "github.com/Ingenimax/agent-sdk-go/pkg/vectorstore/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/vectorstore/weaviate"
+)
+
+// knowledgeIngestTimeout bounds the ingestion of the knowledge base declared
+// in YAML when the agent is created
+const knowledgeIngestTimeout = 5 * time.Minute
+
+// applyKnowledgeConfig builds the knowledge base declared in YAML, ingests its
+// sources and attaches the resulting retriever to the agent
+func (a *Agent) applyKnowledgeConfig(config *KnowledgeYAML) error {
+	if config.Enabled != nil && !*config.Enabled {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), knowledgeIngestTimeout)
+	defer cancel()
+	// Store the documents under the organization the agent runs its requests
+	// in, so that its retrieval finds them
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+
+	kb, err := createKnowledgeRetrieverFromConfig(ctx, config, a.logger)
+	if err != nil {
+		return fmt.Errorf("failed to create knowledge base from config: %w", err)
+	}
+
+	if config.AutoInject == nil || *config.AutoInject {
+		a.retriever = kb
+	}
+	if config.ExposeAsTool == nil || *config.ExposeAsTool {
+		a.tools = deduplicateTools(append(a.tools, kb))
+	}
+	return nil
+}
+
+// createKnowledgeRetrieverFromConfig creates the embedder and vector store,
+// ingests the configured sources and returns a retriever over them
+func createKnowledgeRetrieverFromConfig(ctx context.Context, config *KnowledgeYAML, logger logging.Logger) (*retriever.Retriever, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	embedder, err := createEmbedderFromConfig(ctx, config.Embedding)
+	if err != nil {
+		return nil, err
+	}
+
+	store, class, err := createVectorStoreFromConfig(config.VectorStore, embedder)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ingest sources
+	sources := make([]knowledge.Source, 0, len(config.Sources))
+	for _, source := range config.Sources {
+		sources = append(sources, knowledge.Source{
+			Type:       source.Type,
+			Path:       source.Path,
+			URL:        source.URL,
+			Content:    source.Content,
+			Name:       source.Name,
+			Extensions: source.Extensions,
+		})
+	}
+
+	var ingestOptions []knowledge.Option
+	if config.ChunkSize != nil {
+		ingestOptions = append(ingestOptions, knowledge.WithChunkSize(*config.ChunkSize))
+	}
+	if config.ChunkOverlap != nil {
+		ingestOptions = append(ingestOptions, knowledge.WithChunkOverlap(*config.ChunkOverlap))
+	}
+	if class != "" {
+		ingestOptions = append(ingestOptions, knowledge.WithStoreOptions(interfaces.WithClass(class)))
+	}
+
+	chunks, err := knowledge.Ingest(ctx, store, sources, ingestOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ingest knowledge sources: %w", err)
+	}
+
+	if logger != nil {
+		logger.Info(ctx, "Knowledge base ingested from YAML config", map[string]interface{}{
+			"sources": len(sources),
+			"chunks":  chunks,
+		})
+	}
+
+	// Build retriever
+	var retrieverOptions []retriever.Option
+	if config.TopK != nil {
+		retrieverOptions = append(retrieverOptions, retriever.WithTopK(*config.TopK))
+	}
+	if config.TokenBudget != nil {
+		retrieverOptions = append(retrieverOptions, retriever.WithTokenBudget(*config.TokenBudget))
+	}
+	if config.MinScore != nil {
+		retrieverOptions = append(retrieverOptions, retriever.WithMinScore(float32(*config.MinScore)))
+	}
+	if class != "" {
+		retrieverOptions = append(retrieverOptions, retriever.WithSearchOptions(func(o *interfaces.SearchOptions) {
+			o.Class = class
+		}))
+	}
+
+	return retriever.New(store, retrieverOptions...), nil
+}
+
+// createEmbedderFromConfig creates an embedding client from YAML configuration
+func createEmbedderFromConfig(ctx context.Context, config *EmbeddingYAML) (embedding.Client, error) {
+	provider := "openai"
+	model := ""
+	var cfg map[string]interface{}
+	if config != nil {
+		if config.Provider != "" {
+			provider = config.Provider
+		}
+		model = config.Model
+		cfg = config.Config
+	}
+
+	apiKey, _ := cfg["api_key"].(string)
+
+	switch provider {
+	case "openai":
+		if apiKey == "" {
+			apiKey = os.Getenv("OPENAI_API_KEY")
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("OpenAI API key required for knowledge embeddings: set embedding.config.api_key or OPENAI_API_KEY")
+		}
+		return embedding.NewOpenAIEmbedder(apiKey, model), nil
+
+	case "gemini":
+		if apiKey == "" {
+			apiKey = os.Getenv("GEMINI_API_KEY")
+		}
+		if apiKey == "" {
+			apiKey = os.Getenv("GOOGLE_API_KEY")
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("gemini API key required for knowledge embeddings: set embedding.config.api_key or GEMINI_API_KEY")
+		}
+		options := []embedding.GeminiEmbedderOption{embedding.WithGeminiAPIKey(apiKey)}
+		if model != "" {
+			options = append(options, embedding.WithGeminiModel(model))
+		}
+		return embedding.NewGeminiEmbedder(ctx, options...)
+
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider: %s (supported: openai, gemini)", provider)
+	}
+}
+
+// createVectorStoreFromConfig creates a vector store from YAML configuration
+// and returns it together with the class/collection to use
+func createVectorStoreFromConfig(config *VectorStoreYAML, embedder embedding.Client) (interfaces.VectorStore, string, error) {
+	storeType := "memory"
+	class := ""
+	var cfg map[string]interface{}
+	if config != nil {
+		if config.Type != "" {
+			storeType = config.Type
+		}
+		class = config.Class
+		cfg = config.Config
+	}
+
+	switch storeType {
+	case "memory":
+		return vectormemory.New(embedder), class, nil
+
+	case "weaviate":
+		host, _ := cfg["host"].(string)
+		if host == "" {
+			host = os.Getenv("WEAVIATE_HOST")
+		}
+		if host == "" {
+			return nil, "", fmt.Errorf("weaviate host is required: set vector_store.config.host or WEAVIATE_HOST")
+		}
+		apiKey, _ := cfg["api_key"].(string)
+		if apiKey == "" {
+			apiKey = os.Getenv("WEAVIATE_API_KEY")
+		}
+		scheme, _ := cfg["scheme"].(string)
+		if scheme == "" {
+			scheme = "http"
+		}
+
+		var options []weaviate.Option
+		options = append(options, weaviate.WithEmbedder(embedder))
+		if class != "" {
+			options = append(options, weaviate.WithClassPrefix(class))
+		}
+
+		store := weaviate.New(&interfaces.VectorStoreConfig{
+			Host:   host,
+			APIKey: apiKey,
+			Scheme: scheme,
+		}, options...)
+		if store == nil {
+			return nil, "", fmt.Errorf("failed to create weaviate store")
+		}
+		return store, class, nil
+
+	default:
+		return nil, "", fmt.Errorf("unsupported vector store type: %s (supported: memory, weaviate)", storeType)
+	}
+}
@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// newEmbeddingServer serves OpenAI embeddings requests with a fixed vector
+// and points the OpenAI embedder at it
+func newEmbeddingServer(t *testing.T) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input json.RawMessage `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode embeddings request: %v", err)
+		}
+		count := 1
+		var inputs []string
+		if json.Unmarshal(req.Input, &inputs) == nil {
+			count = len(inputs)
+		}
+
+		data := make([]map[string]interface{}, count)
+		for i := range data {
+			data[i] = map[string]interface{}{"object": "embedding", "index": i, "embedding": []float32{0.1, 0.2, 0.3}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "data": data, "model": "text-embedding-3-small"})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("OPENAI_BASE_URL", server.URL)
+}
+
+// knowledgeAgentConfig returns an agent config with an inline knowledge base
+func knowledgeAgentConfig() AgentConfig {
+	exposeAsTool := false
+	return AgentConfig{
+		Role: "Support agent",
+		Knowledge: &KnowledgeYAML{
+			Sources:      []KnowledgeSourceYAML{{Type: "text", Name: "returns", Content: "Returns are accepted within 30 days of purchase."}},
+			Embedding:    &EmbeddingYAML{Provider: "openai", Config: map[string]interface{}{"api_key": "test-key"}},
+			ExposeAsTool: &exposeAsTool,
+		},
+	}
+}
+
+// systemPromptRecorder returns an LLM recording the system message of its last call
+func systemPromptRecorder(systemMessage *string) *mockLLM {
+	return &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			params := &interfaces.GenerateOptions{}
+			for _, option := range options {
+				option(params)
+			}
+			*systemMessage = params.SystemMessage
+			return "response", nil
+		},
+	}
+}
+
+func TestNewAgent_KnowledgeConfig(t *testing.T) {
+	newEmbeddingServer(t)
+
+	var systemMessage string
+	agent, err := NewAgent(
+		WithLLM(systemPromptRecorder(&systemMessage)),
+		WithAgentConfig(knowledgeAgentConfig(), nil),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	if agent.GetRetriever() == nil {
+		t.Fatal("Expected the knowledge base to be attached to the agent")
+	}
+
+	if _, err := agent.Run(context.Background(), "How long do I have to return an item?"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(systemMessage, "within 30 days") {
+		t.Errorf("Expected the ingested passage in the system prompt, got %q", systemMessage)
+	}
+}
+
+func TestNewAgent_KnowledgeConfigWithOrgID(t *testing.T) {
+	newEmbeddingServer(t)
+
+	var systemMessage string
+	agent, err := NewAgent(
+		WithLLM(systemPromptRecorder(&systemMessage)),
+		WithAgentConfig(knowledgeAgentConfig(), nil),
+		WithOrgID("acme"),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	// Requests run under the organization of the agent, which must also own
+	// the ingested documents
+	if _, err := agent.Run(context.Background(), "How long do I have to return an item?"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(systemMessage, "within 30 days") {
+		t.Errorf("Expected the ingested passage in the system prompt, got %q", systemMessage)
+	}
+}
+
+func TestNewAgent_KnowledgeConfigError(t *testing.T) {
+	config := knowledgeAgentConfig()
+	config.Knowledge.Embedding.Provider = "unknown"
+
+	_, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithAgentConfig(config, nil),
+	)
+	if err == nil || !strings.Contains(err.Error(), "unsupported embedding provider") {
+		t.Fatalf("Expected the knowledge base error from NewAgent, got %v", err)
+	}
+}
@@ -20,6 +20,7 @@ type MCPServerConfig struct {
 	Env               map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
 	URL               string            `json:"url,omitempty" yaml:"url,omitempty"`
 	Token             string            `json:"token,omitempty" yaml:"token,omitempty"`
+	Headers           map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`                     // e.g. {"X-API-Key": "${MCP_API_KEY}"}
 	HttpTransportMode string            `json:"httpTransportMode,omitempty" yaml:"httpTransportMode,omitempty"` // "sse" or "streamable"
 	AllowedTools      []string          `json:"allowedTools,omitempty" yaml:"allowedTools,omitempty"`
 }
@@ -66,6 +67,11 @@ type MCPGlobalConfig struct {
 	EnableSampling  *bool  `json:"enable_sampling,omitempty" yaml:"enable_sampling,omitempty"`
 	EnableSchemas   *bool  `json:"enable_schemas,omitempty" yaml:"enable_schemas,omitempty"`
 	LogLevel        string `json:"log_level,omitempty" yaml:"log_level,omitempty"`
+
+	// HealthCheckInterval and ToolRefreshInterval control how often servers
+	// are pinged and their tools refreshed, e.g. "30s"; "0" disables them
+	HealthCheckInterval string `json:"health_check_interval,omitempty" yaml:"health_check_interval,omitempty"`
+	ToolRefreshInterval string `json:"tool_refresh_interval,omitempty" yaml:"tool_refresh_interval,omitempty"`
 }
 
 // LoadMCPConfigFromJSON loads MCP configuration from a JSON file
@@ -221,6 +227,19 @@ func applyMCPConfig(a *Agent, config *MCPConfiguration, configVars map[string]st
 	// Apply health check to builder
 	builder.WithHealthCheck(*globalConfig.HealthCheck)
 
+	// Apply reconnection settings to builder and lazy configs
+	var reconnect *mcp.ReconnectConfig
+	if globalConfig.HealthCheckInterval != "" || globalConfig.ToolRefreshInterval != "" {
+		reconnect = mcp.DefaultReconnectConfig()
+		if interval, err := time.ParseDuration(globalConfig.HealthCheckInterval); err == nil {
+			reconnect.HealthCheckInterval = interval
+		}
+		if interval, err := time.ParseDuration(globalConfig.ToolRefreshInterval); err == nil {
+			reconnect.ToolRefreshInterval = interval
+		}
+		builder.WithReconnect(reconnect)
+	}
+
 	if a.logger != nil {
 		a.logger.Debug(ctx, "MCP global configuration applied", map[string]interface{}{
 			"health_check":     *globalConfig.HealthCheck,
@@ -262,23 +281,35 @@ func applyMCPConfig(a *Agent, config *MCPConfiguration, configVars map[string]st
 				Env:          envSlice,
 				Tools:        []LazyMCPToolConfig{}, // Will discover dynamically
 				AllowedTools: serverConfig.AllowedTools,
+				Reconnect:    reconnect,
 			}
 			lazyConfigs = append(lazyConfigs, lazyConfig)
 
 		case "http":
+			// Resolve header placeholders like environment values
+			var headers map[string]string
+			if len(serverConfig.Headers) > 0 {
+				headers = make(map[string]string, len(serverConfig.Headers))
+				for key, value := range serverConfig.Headers {
+					headers[key] = expandWithConfigVars(value, configVars)
+				}
+			}
+
 			if serverConfig.Token != "" {
 				builder.AddHTTPServerWithAuth(serverName, serverConfig.URL, serverConfig.Token)
 			} else {
-				builder.AddHTTPServer(serverName, serverConfig.URL)
+				builder.AddHTTPServerWithHeaders(serverName, serverConfig.URL, headers)
 			}
 
 			lazyConfig := LazyMCPConfig{
 				Name:         serverName,
 				Type:         "http",
 				URL:          serverConfig.URL,
-				Token:        serverConfig.Token,    // Preserve token for lazy initialization
+				Token:        serverConfig.Token, // Preserve token for lazy initialization
+				Headers:      headers,
 				Tools:        []LazyMCPToolConfig{}, // Will discover dynamically
 				AllowedTools: serverConfig.AllowedTools,
+				Reconnect:    reconnect,
 			}
 			if serverConfig.HttpTransportMode != "" {
 				// handle case-insensitivity
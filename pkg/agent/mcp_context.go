@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/mcp"
+)
+
+// WithMCPResourceTools gives the agent the mcp_list_resources and
+// mcp_read_resource tools, so the model can retrieve the resources of the
+// agent's MCP servers as context
+func WithMCPResourceTools() Option {
+	return func(a *Agent) {
+		a.mcpResourceTools = true
+	}
+}
+
+// serverConfig converts a lazy MCP configuration to the configuration of
+// its server
+func (config LazyMCPConfig) serverConfig() mcp.LazyMCPServerConfig {
+	return mcp.LazyMCPServerConfig{
+		Name:              config.Name,
+		Type:              config.Type,
+		Command:           config.Command,
+		Args:              config.Args,
+		Env:               config.Env,
+		URL:               config.URL,
+		Token:             config.Token,
+		Headers:           config.Headers,
+		OAuth:             config.OAuth,
+		HttpTransportMode: config.HttpTransportMode,
+		AllowedTools:      config.AllowedTools,
+		Reconnect:         config.Reconnect,
+	}
+}
+
+// initializeMCPCatalog creates the catalog of the resources and prompts of
+// the agent's MCP servers
+func (a *Agent) initializeMCPCatalog() {
+	lazyConfigs := make([]mcp.LazyMCPServerConfig, 0, len(a.lazyMCPConfigs))
+	for _, config := range a.lazyMCPConfigs {
+		lazyConfigs = append(lazyConfigs, config.serverConfig())
+	}
+	a.mcpCatalog = mcp.NewCatalog(a.mcpServers, lazyConfigs...)
+
+	if a.mcpResourceTools {
+		a.tools = deduplicateTools(append(a.tools, a.mcpCatalog.ResourceTools()...))
+	}
+}
+
+// ListMCPResources lists the resources of the agent's MCP servers
+func (a *Agent) ListMCPResources(ctx context.Context) ([]interfaces.MCPResource, error) {
+	if a.mcpCatalog == nil {
+		return nil, fmt.Errorf("agent has no MCP servers")
+	}
+	matches, err := a.mcpCatalog.Resources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]interfaces.MCPResource, 0, len(matches))
+	for _, match := range matches {
+		resources = append(resources, match.Resource)
+	}
+	return resources, nil
+}
+
+// ReadMCPResource reads a resource of the agent's MCP servers, e.g. to pass
+// it to Run as context
+func (a *Agent) ReadMCPResource(ctx context.Context, uri string) (*interfaces.MCPResourceContent, error) {
+	if a.mcpCatalog == nil {
+		return nil, fmt.Errorf("agent has no MCP servers")
+	}
+	return a.mcpCatalog.ReadResource(ctx, uri)
+}
+
+// ListMCPPrompts lists the prompt templates of the agent's MCP servers
+func (a *Agent) ListMCPPrompts(ctx context.Context) ([]interfaces.MCPPrompt, error) {
+	if a.mcpCatalog == nil {
+		return nil, fmt.Errorf("agent has no MCP servers")
+	}
+	matches, err := a.mcpCatalog.Prompts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prompts := make([]interfaces.MCPPrompt, 0, len(matches))
+	for _, match := range matches {
+		prompts = append(prompts, match.Prompt)
+	}
+	return prompts, nil
+}
+
+// GetMCPPrompt renders a prompt template of the agent's MCP servers
+func (a *Agent) GetMCPPrompt(ctx context.Context, name string, args map[string]interface{}) (*interfaces.MCPPromptResult, error) {
+	if a.mcpCatalog == nil {
+		return nil, fmt.Errorf("agent has no MCP servers")
+	}
+	return a.mcpCatalog.GetPrompt(ctx, name, args)
+}
+
+// RunMCPPrompt renders a prompt template of the agent's MCP servers and runs
+// the agent with it
+func (a *Agent) RunMCPPrompt(ctx context.Context, name string, args map[string]interface{}) (string, error) {
+	result, err := a.GetMCPPrompt(ctx, name, args)
+	if err != nil {
+		return "", err
+	}
+	return a.Run(ctx, mcp.PromptText(result))
+}
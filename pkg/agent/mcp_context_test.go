@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/mcp"
+)
+
+// newPromptServer connects to an in-memory MCP server with a resource and a
+// prompt template
+func newPromptServer(t *testing.T) interfaces.MCPServer {
+	t.Helper()
+	server := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "docs"}, nil)
+	server.AddResource(&sdkmcp.Resource{URI: "file:///policy.md", Name: "policy"},
+		func(_ context.Context, req *sdkmcp.ReadResourceRequest) (*sdkmcp.ReadResourceResult, error) {
+			return &sdkmcp.ReadResourceResult{Contents: []*sdkmcp.ResourceContents{{URI: req.Params.URI, Text: "Refunds within 30 days"}}}, nil
+		})
+	server.AddPrompt(&sdkmcp.Prompt{Name: "translate", Arguments: []*sdkmcp.PromptArgument{{Name: "text", Required: true}}},
+		func(_ context.Context, req *sdkmcp.GetPromptRequest) (*sdkmcp.GetPromptResult, error) {
+			return &sdkmcp.GetPromptResult{Messages: []*sdkmcp.PromptMessage{
+				{Role: "user", Content: &sdkmcp.TextContent{Text: "Translate to French: " + req.Params.Arguments["text"]}},
+			}}, nil
+		})
+
+	ctx := context.Background()
+	serverTransport, clientTransport := sdkmcp.NewInMemoryTransports()
+	session, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+
+	client, err := mcp.NewMCPServer(ctx, clientTransport)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestMCPResourceToolsAndPrompts(t *testing.T) {
+	var prompt string
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{generateFunc: func(ctx context.Context, p string, options ...interfaces.GenerateOption) (string, error) {
+			prompt = p
+			return "Bonjour", nil
+		}}),
+		WithMCPServers([]interfaces.MCPServer{newPromptServer(t)}),
+		WithMCPResourceTools(),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, tool := range agent.GetTools() {
+		names[tool.Name()] = true
+	}
+	if !names["mcp_list_resources"] || !names["mcp_read_resource"] {
+		t.Errorf("expected the resource tools, got %v", names)
+	}
+
+	ctx := context.Background()
+	content, err := agent.ReadMCPResource(ctx, "file:///policy.md")
+	if err != nil || content.Text != "Refunds within 30 days" {
+		t.Errorf("ReadMCPResource() = %+v, %v", content, err)
+	}
+
+	prompts, err := agent.ListMCPPrompts(ctx)
+	if err != nil || len(prompts) != 1 || prompts[0].Name != "translate" {
+		t.Fatalf("ListMCPPrompts() = %+v, %v", prompts, err)
+	}
+
+	response, err := agent.RunMCPPrompt(ctx, "translate", map[string]interface{}{"text": "Hello"})
+	if err != nil || response != "Bonjour" {
+		t.Fatalf("RunMCPPrompt() = %q, %v", response, err)
+	}
+	if !strings.Contains(prompt, "Translate to French: Hello") {
+		t.Errorf("expected the rendered prompt as input, got %q", prompt)
+	}
+}
+
+func TestMCPPromptsWithoutServers(t *testing.T) {
+	agent, err := NewAgent(WithLLM(&mockLLM{}))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	if _, err := agent.ListMCPPrompts(context.Background()); err == nil {
+		t.Error("expected an error without MCP servers")
+	}
+}
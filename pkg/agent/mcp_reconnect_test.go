@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/mcp"
+)
+
+// toolsLLM records the tools offered to the model
+type toolsLLM struct {
+	mockLLM
+	tools []string
+}
+
+func (m *toolsLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	m.tools = nil
+	for _, tool := range tools {
+		m.tools = append(m.tools, tool.Name())
+	}
+	return "done", nil
+}
+
+func TestRunWithUnavailableMCPServer(t *testing.T) {
+	server := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "lookup"}, nil)
+	sdkmcp.AddTool(server, &sdkmcp.Tool{Name: "lookup", Description: "Looks things up"},
+		func(context.Context, *sdkmcp.CallToolRequest, map[string]any) (*sdkmcp.CallToolResult, any, error) {
+			return &sdkmcp.CallToolResult{Content: []sdkmcp.Content{&sdkmcp.TextContent{Text: "found"}}}, nil, nil
+		})
+
+	var session *sdkmcp.ServerSession
+	connected := false
+	connect := func(ctx context.Context) (interfaces.MCPServer, error) {
+		if connected {
+			return nil, errors.New("connection refused")
+		}
+		connected = true
+		serverTransport, clientTransport := sdkmcp.NewInMemoryTransports()
+		var err error
+		if session, err = server.Connect(ctx, serverTransport, nil); err != nil {
+			return nil, err
+		}
+		return mcp.NewMCPServer(ctx, clientTransport)
+	}
+
+	ctx := context.Background()
+	resilient, err := mcp.NewResilientServer(ctx, "lookup", connect, &mcp.ReconnectConfig{InitialDelay: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer resilient.Close()
+
+	llm := &toolsLLM{}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithMCPServers([]interfaces.MCPServer{resilient}),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	if _, err := agent.Run(ctx, "look it up"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(llm.tools) != 1 || llm.tools[0] != "lookup" {
+		t.Fatalf("expected the lookup tool, got %v", llm.tools)
+	}
+
+	// The server goes down mid-conversation
+	_ = session.Close()
+	if _, err := resilient.ListTools(ctx); err == nil {
+		t.Fatal("expected the lost connection to be noticed")
+	}
+
+	llm.tools = nil
+	if _, err := agent.Run(ctx, "look it up again"); err != nil {
+		t.Fatalf("Run() error = %v; expected the agent to keep running", err)
+	}
+	if len(llm.tools) != 0 {
+		t.Errorf("expected the tools of the unavailable server to be left out, got %v", llm.tools)
+	}
+}
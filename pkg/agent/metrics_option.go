@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/metrics"
+)
+
+// WithMetrics records Prometheus metrics for the agent's runs, token usage,
+// tool calls and streams. Serve them with metrics.Handler (or the /metrics
+// endpoint of the microservice HTTP server).
+//
+//	agent.WithMetrics(metrics.Default())
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(a *Agent) {
+		a.metrics = m
+	}
+}
+
+// recordRunMetrics records a run that started at startTime and failed with
+// err, if any, with the token usage collected by tracker
+func (a *Agent) recordRunMetrics(startTime time.Time, tracker *usageTracker, err error) {
+	if a.metrics == nil {
+		return
+	}
+	a.metrics.ObserveRequest(a.name, time.Since(startTime), err)
+	if usage, _, model := tracker.getResults(); usage != nil {
+		a.metrics.AddTokens(a.name, model, usage.InputTokens, usage.OutputTokens)
+	}
+}
+
+// meterStream forwards stream events while counting the stream as active
+func (a *Agent) meterStream(ctx context.Context, events <-chan interfaces.AgentStreamEvent) <-chan interfaces.AgentStreamEvent {
+	if a.metrics == nil {
+		return events
+	}
+
+	startTime := time.Now()
+	a.metrics.StreamStarted(a.name)
+
+	out := make(chan interfaces.AgentStreamEvent, cap(events))
+	go func() {
+		defer close(out)
+
+		var streamErr error
+		delivered := true
+		for event := range events {
+			if event.Type == interfaces.AgentEventError {
+				streamErr = event.Error
+			}
+			// Keep draining after cancellation so the producer can exit
+			if delivered {
+				delivered = sendEvent(ctx, out, event)
+			}
+		}
+
+		a.metrics.StreamEnded(a.name)
+		a.metrics.ObserveStream(a.name, time.Since(startTime), streamErr)
+	}()
+	return out
+}
+
+// wrapToolsWithMetrics wraps each tool so its executions are counted.
+// Returns the original slice unchanged when metrics are not enabled.
+func (a *Agent) wrapToolsWithMetrics(tools []interfaces.Tool) []interfaces.Tool {
+	if a.metrics == nil || len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = &meteredTool{inner: t, agent: a}
+	}
+	return wrapped
+}
+
+// meteredTool counts the executions of a tool
+type meteredTool struct {
+	inner interfaces.Tool
+	agent *Agent
+}
+
+func (t *meteredTool) Name() string        { return t.inner.Name() }
+func (t *meteredTool) Description() string { return t.inner.Description() }
+func (t *meteredTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.inner.Parameters()
+}
+
+func (t *meteredTool) Run(ctx context.Context, input string) (string, error) {
+	result, err := t.inner.Run(ctx, input)
+	t.agent.metrics.ObserveToolCall(t.agent.name, t.inner.Name(), err)
+	return result, err
+}
+
+func (t *meteredTool) Execute(ctx context.Context, args string) (string, error) {
+	result, err := t.inner.Execute(ctx, args)
+	t.agent.metrics.ObserveToolCall(t.agent.name, t.inner.Name(), err)
+	return result, err
+}
+
+// DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
+func (t *meteredTool) DisplayName() string {
+	if d, ok := t.inner.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.inner.Name()
+}
+
+// Internal forwards to the inner tool when it implements InternalTool.
+func (t *meteredTool) Internal() bool {
+	if i, ok := t.inner.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
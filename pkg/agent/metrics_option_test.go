@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/metrics"
+)
+
+func TestMetricsRecordRunsAndTokens(t *testing.T) {
+	m := metrics.New(metrics.NewRegistry())
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithName("metered"),
+		WithMetrics(m),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	if _, err := agent.Run(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := m.Requests.Value("metered", metrics.StatusSuccess); got != 1 {
+		t.Errorf("expected 1 successful request, got %v", got)
+	}
+	if got := m.RequestDuration.Count("metered"); got != 1 {
+		t.Errorf("expected 1 duration observation, got %d", got)
+	}
+	if got := m.Tokens.Value("metered", "mock-llm", "input"); got != 100 {
+		t.Errorf("expected 100 input tokens, got %v", got)
+	}
+}
+
+func TestMetricsRecordToolCalls(t *testing.T) {
+	m := metrics.New(metrics.NewRegistry())
+	agent, err := NewAgent(WithLLM(&mockLLM{}), WithName("metered"), WithMetrics(m))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	tool := &mockTool{
+		name: "flaky",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "", errors.New("unavailable")
+		},
+	}
+	wrapped := agent.wrapToolsWithMetrics([]interfaces.Tool{tool})
+	_, _ = wrapped[0].Run(context.Background(), "{}")
+
+	if got := m.ToolCalls.Value("metered", "flaky", metrics.StatusError); got != 1 {
+		t.Errorf("expected 1 failed tool call, got %v", got)
+	}
+	if got := m.Errors.Value("metered", "tool"); got != 1 {
+		t.Errorf("expected 1 tool error, got %v", got)
+	}
+}
@@ -0,0 +1,24 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+// MarkMilestone records a named milestone (e.g. "draft approved") in the
+// conversation identified by ctx. Models can mark milestones themselves when
+// the agent is given the milestone tool (see pkg/tools/milestone).
+func (a *Agent) MarkMilestone(ctx context.Context, name, description string, data map[string]interface{}) error {
+	return memory.MarkMilestone(ctx, a.memory, name, description, data)
+}
+
+// GetMilestones returns the milestones reached in the conversation identified by ctx
+func (a *Agent) GetMilestones(ctx context.Context) ([]memory.Milestone, error) {
+	return memory.GetMilestones(ctx, a.memory)
+}
+
+// HasMilestone reports whether the named milestone was reached in the conversation identified by ctx
+func (a *Agent) HasMilestone(ctx context.Context, name string) (bool, error) {
+	return memory.HasMilestone(ctx, a.memory, name)
+}
@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/router"
+)
+
+// WithModelRouter sets a model router as the agent's LLM. The input of each
+// run is classified once, and every LLM call of the run, including tool
+// iterations and summaries, goes to the model selected for it: the cheap
+// model for simple requests and the premium model for complex ones.
+// Sub-agents called during the run follow the same decision. Routing and
+// cost savings metrics are reported by the router's Metrics.
+func WithModelRouter(modelRouter *router.ModelRouter) Option {
+	return func(a *Agent) {
+		a.modelRouter = modelRouter
+		a.llm = modelRouter
+	}
+}
+
+// routeModel classifies the input of a run with the agent's model router,
+// unless the complexity of the run is already set on ctx
+func (a *Agent) routeModel(ctx context.Context, input string) context.Context {
+	if a.modelRouter == nil || a.llm != a.modelRouter {
+		return ctx
+	}
+	if _, ok := router.ComplexityFromContext(ctx); ok {
+		return ctx
+	}
+
+	complexity := a.modelRouter.Classify(ctx, input)
+	a.logger.Debug(ctx, "Routed run by complexity", map[string]interface{}{
+		"complexity": string(complexity),
+	})
+	return router.ContextWithComplexity(ctx, complexity)
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/router"
+)
+
+func TestWithModelRouter(t *testing.T) {
+	respond := func(answer string) func(context.Context, string, ...interfaces.GenerateOption) (string, error) {
+		return func(context.Context, string, ...interfaces.GenerateOption) (string, error) {
+			return answer, nil
+		}
+	}
+	cheap := &mockLLM{name: "cheap", generateFunc: respond("cheap answer")}
+	premium := &mockLLM{name: "premium", generateFunc: respond("premium answer")}
+
+	var classified []string
+	heuristic := router.NewHeuristicClassifier()
+	classifier := router.ClassifierFunc(func(ctx context.Context, prompt string) (router.Complexity, error) {
+		classified = append(classified, prompt)
+		return heuristic.Classify(ctx, prompt)
+	})
+	modelRouter := router.NewModelRouter(cheap, premium, router.WithClassifier(classifier))
+
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithModelRouter(modelRouter),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Hello there", "cheap answer"},
+		{"Design a migration strategy for our database", "premium answer"},
+	}
+	for _, tt := range tests {
+		classified = nil
+		response, err := agent.Run(context.Background(), tt.input)
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if response != tt.expected {
+			t.Errorf("Expected %q for %q, got %q", tt.expected, tt.input, response)
+		}
+		if len(classified) != 1 || classified[0] != tt.input {
+			t.Errorf("Expected the run input to be classified once, got %q", classified)
+		}
+	}
+
+	if m := modelRouter.Metrics(); m.Cheap != 1 || m.Premium != 1 {
+		t.Errorf("Unexpected routing metrics: %+v", m)
+	}
+}
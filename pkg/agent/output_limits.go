@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// OutputLimits bound the LLM responses of a run, e.g. to cap its cost or fit
+// a UI, in place of the max output tokens and stop sequences of the agent's
+// LLM config
+type OutputLimits struct {
+	// MaxOutputTokens is the maximum number of tokens of each LLM response
+	// (0 keeps the agent's)
+	MaxOutputTokens int
+
+	// StopSequences end a response when the LLM generates one of them (nil
+	// keeps the agent's)
+	StopSequences []string
+}
+
+// outputLimitsKey is the context key for the output limits of agent runs
+type outputLimitsKey struct{}
+
+// ContextWithOutputLimits returns a context whose agent runs use limits
+// instead of the agent's max output tokens and stop sequences. The limits
+// apply to sub-agents run with the context too.
+func ContextWithOutputLimits(ctx context.Context, limits OutputLimits) context.Context {
+	return context.WithValue(ctx, outputLimitsKey{}, limits)
+}
+
+// OutputLimitsFromContext returns the output limits set with
+// ContextWithOutputLimits, and whether any were set
+func OutputLimitsFromContext(ctx context.Context) (OutputLimits, bool) {
+	limits, ok := ctx.Value(outputLimitsKey{}).(OutputLimits)
+	return limits, ok
+}
+
+// applyOutputLimits applies the output limits of the run to its LLM calls, on
+// a copy of the LLM config so that the agent's own is left untouched
+func applyOutputLimits(ctx context.Context) interfaces.GenerateOption {
+	limits, ok := OutputLimitsFromContext(ctx)
+	return func(options *interfaces.GenerateOptions) {
+		if !ok || (limits.MaxOutputTokens == 0 && limits.StopSequences == nil) {
+			return
+		}
+		llmConfig := interfaces.LLMConfig{Temperature: defaultTemperature}
+		if options.LLMConfig != nil {
+			llmConfig = *options.LLMConfig
+		}
+		if limits.MaxOutputTokens > 0 {
+			llmConfig.MaxOutputTokens = limits.MaxOutputTokens
+		}
+		if limits.StopSequences != nil {
+			llmConfig.StopSequences = limits.StopSequences
+		}
+		options.LLMConfig = &llmConfig
+	}
+}
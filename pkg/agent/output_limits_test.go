@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestOutputLimits(t *testing.T) {
+	var config interfaces.LLMConfig
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			params := &interfaces.GenerateOptions{}
+			for _, option := range options {
+				option(params)
+			}
+			if params.LLMConfig != nil {
+				config = *params.LLMConfig
+			}
+			return "response", nil
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithLLMConfig(interfaces.LLMConfig{Temperature: 0.2, MaxOutputTokens: 1000, StopSequences: []string{"END"}}),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	if _, err := agent.Run(context.Background(), "hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if config.MaxOutputTokens != 1000 || !reflect.DeepEqual(config.StopSequences, []string{"END"}) {
+		t.Errorf("Expected the agent's limits by default, got %+v", config)
+	}
+
+	ctx := ContextWithOutputLimits(context.Background(), OutputLimits{MaxOutputTokens: 50})
+	if _, err := agent.Run(ctx, "hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if config.MaxOutputTokens != 50 || !reflect.DeepEqual(config.StopSequences, []string{"END"}) || config.Temperature != 0.2 {
+		t.Errorf("Expected the request's max output tokens with the agent's other settings, got %+v", config)
+	}
+
+	ctx = ContextWithOutputLimits(context.Background(), OutputLimits{StopSequences: []string{"\n\n"}})
+	if _, err := agent.Run(ctx, "hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if config.MaxOutputTokens != 1000 || !reflect.DeepEqual(config.StopSequences, []string{"\n\n"}) {
+		t.Errorf("Expected the request's stop sequences, got %+v", config)
+	}
+	if agent.llmConfig.MaxOutputTokens != 1000 || !reflect.DeepEqual(agent.llmConfig.StopSequences, []string{"END"}) {
+		t.Errorf("Expected the agent's LLM config to be left untouched, got %+v", *agent.llmConfig)
+	}
+}
@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// DefaultOutputValidationRetries is the number of times a response rejected
+// by an output validator is re-generated by default
+const DefaultOutputValidationRetries = 2
+
+// ErrOutputRejected is returned when output validators still reject the
+// response after all re-generation attempts. The last rejection is wrapped too.
+var ErrOutputRejected = errors.New("response rejected by output validator")
+
+// OutputValidator inspects the final response of a run. It returns the
+// response to use, which may be modified, or an error to reject it. The
+// error message is sent to the model when the response is re-generated.
+type OutputValidator func(ctx context.Context, response string) (string, error)
+
+// WithOutputValidator adds a validator for the agent's final responses.
+// Validators run in the order they were added, each receiving the response
+// returned by the previous one, after structured output validation and
+// before output guardrails. In RunStream they run once the response was
+// streamed: a response they modify or re-generate is sent again in full in a
+// content event with "replaces_streamed_content" metadata, and a rejected
+// one ends the stream with an error event.
+func WithOutputValidator(validator OutputValidator) Option {
+	return func(a *Agent) {
+		a.outputValidators = append(a.outputValidators, validator)
+	}
+}
+
+// WithOutputValidationRetries sets how many times a response rejected by an
+// output validator is re-generated (default: 2). Use 0 to fail on the first
+// rejection.
+func WithOutputValidationRetries(maxRetries int) Option {
+	return func(a *Agent) {
+		a.outputRetries = maxRetries
+	}
+}
+
+// MaxLengthValidator rejects responses longer than maxChars characters
+func MaxLengthValidator(maxChars int) OutputValidator {
+	return func(ctx context.Context, response string) (string, error) {
+		if length := len([]rune(response)); length > maxChars {
+			return "", fmt.Errorf("the response is %d characters long, the maximum is %d", length, maxChars)
+		}
+		return response, nil
+	}
+}
+
+// BannedContentValidator rejects responses containing any of the terms,
+// compared case-insensitively
+func BannedContentValidator(terms ...string) OutputValidator {
+	return func(ctx context.Context, response string) (string, error) {
+		lower := strings.ToLower(response)
+		for _, term := range terms {
+			if term != "" && strings.Contains(lower, strings.ToLower(term)) {
+				return "", fmt.Errorf("the response must not mention %q", term)
+			}
+		}
+		return response, nil
+	}
+}
+
+// JSONValidator rejects responses that are not valid JSON
+func JSONValidator() OutputValidator {
+	return func(ctx context.Context, response string) (string, error) {
+		if !json.Valid([]byte(strings.TrimSpace(response))) {
+			return "", fmt.Errorf("the response must be valid JSON only, without markdown or explanations")
+		}
+		return response, nil
+	}
+}
+
+// validateOutput runs the output validators on a response and re-generates
+// it with the rejection reason until every validator accepts it
+func (a *Agent) validateOutput(ctx context.Context, prompt, response string, generateOptions []interfaces.GenerateOption) (string, error) {
+	// Re-generations must not be recorded as conversation turns
+	options := append(generateOptions[:len(generateOptions):len(generateOptions)], interfaces.WithMemory(nil))
+
+	for attempt := 0; ; attempt++ {
+		validated, err := a.runOutputValidators(ctx, response)
+		if err == nil {
+			return validated, nil
+		}
+
+		a.logger.Warn(ctx, "Output validator rejected the response", map[string]interface{}{
+			"error":   err.Error(),
+			"attempt": attempt + 1,
+		})
+		if attempt >= a.outputRetries {
+			return "", fmt.Errorf("%w after %d re-generation attempts: %w", ErrOutputRejected, attempt, err)
+		}
+
+		response, err = a.llm.Generate(ctx, rejectionPrompt(prompt, response, err), options...)
+		if err != nil {
+			return "", fmt.Errorf("failed to re-generate rejected response: %w", err)
+		}
+
+		if a.responseFormat != nil {
+			response, err = a.repairStructuredOutput(ctx, prompt, response, generateOptions)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+}
+
+// runOutputValidators applies the validators in order
+func (a *Agent) runOutputValidators(ctx context.Context, response string) (string, error) {
+	for _, validator := range a.outputValidators {
+		var err error
+		response, err = validator(ctx, response)
+		if err != nil {
+			return "", err
+		}
+	}
+	return response, nil
+}
+
+// rejectionPrompt asks the model to answer again without the problem a
+// validator found in its previous response
+func rejectionPrompt(prompt, response string, rejection error) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\nYour previous response was:\n")
+	b.WriteString(response)
+	b.WriteString("\n\nIt was rejected for the following reason: ")
+	b.WriteString(rejection.Error())
+	b.WriteString("\n\nRespond again to the original request, fixing this problem.")
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestOutputValidatorChain(t *testing.T) {
+	var prompts []string
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			prompts = append(prompts, prompt)
+			if len(prompts) == 1 {
+				return "The secret password is hunter2", nil
+			}
+			return "I can't share that.", nil
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithRequirePlanApproval(false),
+		WithOutputValidator(BannedContentValidator("Hunter2")),
+		WithOutputValidator(func(ctx context.Context, response string) (string, error) {
+			return strings.ToUpper(response), nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	response, err := agent.Run(context.Background(), "what is the password?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response != "I CAN'T SHARE THAT." {
+		t.Errorf("expected the re-generated response modified by the second validator, got %q", response)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], `must not mention "Hunter2"`) {
+		t.Errorf("expected one re-generation with the rejection reason, got %q", prompts)
+	}
+}
+
+func TestOutputValidatorRetriesExhausted(t *testing.T) {
+	calls := 0
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			calls++
+			return "a response that is far too long", nil
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithRequirePlanApproval(false),
+		WithOutputValidator(MaxLengthValidator(10)),
+		WithOutputValidationRetries(1),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	_, err = agent.Run(context.Background(), "say something")
+	if !errors.Is(err, ErrOutputRejected) {
+		t.Fatalf("expected ErrOutputRejected, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the original call and one re-generation, got %d calls", calls)
+	}
+}
+
+func TestJSONValidator(t *testing.T) {
+	validate := JSONValidator()
+	if _, err := validate(context.Background(), `{"ok": true}`); err != nil {
+		t.Errorf("expected valid JSON to pass, got %v", err)
+	}
+	if _, err := validate(context.Background(), "```json\n{}\n```"); err == nil {
+		t.Error("expected fenced JSON to be rejected")
+	}
+}
+
+func TestOutputValidatorStreaming(t *testing.T) {
+	collect := func(t *testing.T, agent *Agent) []interfaces.AgentStreamEvent {
+		eventChan, err := agent.RunStream(context.Background(), "say something")
+		if err != nil {
+			t.Fatalf("failed to start stream: %v", err)
+		}
+		var events []interfaces.AgentStreamEvent
+		for event := range eventChan {
+			events = append(events, event)
+		}
+		return events
+	}
+
+	t.Run("rejected", func(t *testing.T) {
+		agent, err := NewAgent(
+			WithLLM(&StreamingMockLLM{llmName: "mock", responseContent: "a response that is far too long"}),
+			WithRequirePlanApproval(false),
+			WithOutputValidator(MaxLengthValidator(10)),
+			WithOutputValidationRetries(1),
+		)
+		if err != nil {
+			t.Fatalf("failed to create agent: %v", err)
+		}
+
+		var rejected bool
+		for _, event := range collect(t, agent) {
+			switch event.Type {
+			case interfaces.AgentEventError:
+				rejected = rejected || errors.Is(event.Error, ErrOutputRejected)
+			case interfaces.AgentEventComplete:
+				t.Error("expected a rejected response not to complete")
+			}
+		}
+		if !rejected {
+			t.Error("expected an error event with ErrOutputRejected")
+		}
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		agent, err := NewAgent(
+			WithLLM(&StreamingMockLLM{llmName: "mock", responseContent: "hello world"}),
+			WithRequirePlanApproval(false),
+			WithOutputValidator(func(ctx context.Context, response string) (string, error) {
+				return strings.ToUpper(strings.TrimSpace(response)), nil
+			}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create agent: %v", err)
+		}
+
+		var replaced string
+		var completed bool
+		for _, event := range collect(t, agent) {
+			switch {
+			case event.Type == interfaces.AgentEventContent && event.Metadata["replaces_streamed_content"] == true:
+				replaced = event.Content
+			case event.Type == interfaces.AgentEventComplete:
+				completed = true
+			case event.Type == interfaces.AgentEventError:
+				t.Errorf("unexpected error: %v", event.Error)
+			}
+		}
+		if replaced != "HELLO WORLD" || !completed {
+			t.Errorf("expected the validated response to replace the streamed one, got %q (completed: %v)", replaced, completed)
+		}
+	})
+}
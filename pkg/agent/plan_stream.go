@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ApproveExecutionPlanStream approves a plan and streams its execution. For
+// each step it sends a plan_step event when the step starts, tool_call and
+// tool_result events for the step's tool, and a plan_step event when the
+// step ends. The metadata of plan_step events holds the task_id, step,
+// total_steps, status (started, completed or failed), description, tool and
+// the progress of the plan in percent. With WithPlanReplanning, a failed step
+// is followed by a plan_step event with status replanned, the error as
+// content and the revised total_steps. The stream ends with the result as
+// content and a complete event, or an error event. Use PauseExecutionPlan,
+// ResumeExecutionPlan and CancelExecutionPlan to control the plan between
+// its steps.
+func (a *Agent) ApproveExecutionPlanStream(ctx context.Context, plan *executionplan.ExecutionPlan) (<-chan interfaces.AgentStreamEvent, error) {
+	if a.planExecutor == nil {
+		return nil, fmt.Errorf("agent has no execution plan executor")
+	}
+
+	eventChan := make(chan interfaces.AgentStreamEvent, 100)
+	go func() {
+		defer close(eventChan)
+
+		result, err := a.executeApprovedPlan(ctx, plan, func(event executionplan.StepEvent) {
+			for _, streamEvent := range planStepStreamEvents(event) {
+				sendEvent(ctx, eventChan, streamEvent)
+			}
+		})
+		if err != nil {
+			sendEvent(ctx, eventChan, interfaces.AgentStreamEvent{
+				Type:      interfaces.AgentEventError,
+				Error:     err,
+				Timestamp: time.Now(),
+			})
+			return
+		}
+
+		if !sendEvent(ctx, eventChan, interfaces.AgentStreamEvent{
+			Type:      interfaces.AgentEventContent,
+			Content:   result,
+			Timestamp: time.Now(),
+		}) {
+			return
+		}
+		sendEvent(ctx, eventChan, interfaces.AgentStreamEvent{
+			Type:      interfaces.AgentEventComplete,
+			Metadata:  map[string]interface{}{"task_id": plan.TaskID},
+			Timestamp: time.Now(),
+		})
+	}()
+
+	return eventChan, nil
+}
+
+// planStepStreamEvents converts a step event of a plan execution to stream
+// events
+func planStepStreamEvents(event executionplan.StepEvent) []interfaces.AgentStreamEvent {
+	now := time.Now()
+	stepEvent := func(status string) interfaces.AgentStreamEvent {
+		return interfaces.AgentStreamEvent{
+			Type: interfaces.AgentEventPlanStep,
+			Metadata: map[string]interface{}{
+				"task_id":     event.TaskID,
+				"step":        event.Step,
+				"total_steps": event.TotalSteps,
+				"status":      status,
+				"description": event.Description,
+				"tool":        event.ToolName,
+				"progress":    event.Progress,
+			},
+			Timestamp: now,
+		}
+	}
+	toolCall := &interfaces.ToolCallEvent{
+		ID:   fmt.Sprintf("%s-step-%d", event.TaskID, event.Step),
+		Name: event.ToolName,
+	}
+
+	switch event.Type {
+	case executionplan.StepStarted:
+		return []interfaces.AgentStreamEvent{stepEvent("started")}
+	case executionplan.StepToolInvoked:
+		toolCall.Arguments = event.Input
+		toolCall.Status = "executing"
+		return []interfaces.AgentStreamEvent{{Type: interfaces.AgentEventToolCall, ToolCall: toolCall, Timestamp: now}}
+	case executionplan.StepCompleted:
+		toolCall.Result = event.Result
+		toolCall.Status = "completed"
+		return []interfaces.AgentStreamEvent{
+			{Type: interfaces.AgentEventToolResult, ToolCall: toolCall, Timestamp: now},
+			stepEvent("completed"),
+		}
+	case executionplan.StepFailed:
+		failed := stepEvent("failed")
+		failed.Content = event.Error.Error()
+		if event.Input == "" {
+			// The tool was not invoked
+			return []interfaces.AgentStreamEvent{failed}
+		}
+		toolCall.Result = event.Error.Error()
+		toolCall.Status = "error"
+		return []interfaces.AgentStreamEvent{
+			{Type: interfaces.AgentEventToolResult, ToolCall: toolCall, Timestamp: now},
+			failed,
+		}
+	case executionplan.StepReplanned:
+		replanned := stepEvent("replanned")
+		replanned.Content = event.Error.Error()
+		return []interfaces.AgentStreamEvent{replanned}
+	}
+	return nil
+}
+
+// PauseExecutionPlan pauses an executing plan before its next step
+func (a *Agent) PauseExecutionPlan(plan *executionplan.ExecutionPlan) error {
+	return a.planExecutor.PausePlan(plan)
+}
+
+// ResumeExecutionPlan resumes a paused plan
+func (a *Agent) ResumeExecutionPlan(plan *executionplan.ExecutionPlan) error {
+	return a.planExecutor.ResumePlan(plan)
+}
+
+// CancelExecutionPlan cancels a plan. An executing plan stops before its
+// next step with executionplan.ErrPlanCancelled.
+func (a *Agent) CancelExecutionPlan(plan *executionplan.ExecutionPlan) {
+	a.planExecutor.CancelPlan(plan)
+}
@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestApproveExecutionPlanStream(t *testing.T) {
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{name: "mock"}),
+		WithTools(&mockTool{name: "search", description: "Search the web"}),
+		WithMemory(memory.NewConversationBuffer()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	plan := executionplan.NewExecutionPlan("Research", []executionplan.ExecutionStep{
+		{ToolName: "search", Description: "Search for Go", Parameters: map[string]interface{}{"input": "go"}},
+		{ToolName: "search", Description: "Search for Rust", Parameters: map[string]interface{}{"input": "rust"}},
+	})
+
+	ctx := multitenancy.WithOrgID(context.Background(), "org")
+	ctx = memory.WithConversationID(ctx, "conv")
+	events, err := agent.ApproveExecutionPlanStream(ctx, plan)
+	if err != nil {
+		t.Fatalf("ApproveExecutionPlanStream: %v", err)
+	}
+
+	var types []interfaces.AgentEventType
+	var steps []interfaces.AgentStreamEvent
+	var content string
+	for event := range events {
+		types = append(types, event.Type)
+		switch event.Type {
+		case interfaces.AgentEventPlanStep:
+			steps = append(steps, event)
+		case interfaces.AgentEventContent:
+			content = event.Content
+		case interfaces.AgentEventError:
+			t.Fatalf("Unexpected error event: %v", event.Error)
+		}
+	}
+
+	expected := []interfaces.AgentEventType{
+		interfaces.AgentEventPlanStep, interfaces.AgentEventToolCall, interfaces.AgentEventToolResult, interfaces.AgentEventPlanStep,
+		interfaces.AgentEventPlanStep, interfaces.AgentEventToolCall, interfaces.AgentEventToolResult, interfaces.AgentEventPlanStep,
+		interfaces.AgentEventContent, interfaces.AgentEventComplete,
+	}
+	if len(types) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Fatalf("Expected events %v, got %v", expected, types)
+		}
+	}
+
+	last := steps[len(steps)-1].Metadata
+	if last["status"] != "completed" || last["step"] != 2 || last["total_steps"] != 2 || last["progress"] != 100.0 || last["task_id"] != plan.TaskID {
+		t.Errorf("Unexpected last step metadata %v", last)
+	}
+	if steps[0].Metadata["status"] != "started" || steps[0].Metadata["progress"] != 0.0 {
+		t.Errorf("Unexpected first step metadata %v", steps[0].Metadata)
+	}
+	if plan.Status != executionplan.StatusCompleted || content == "" {
+		t.Errorf("Expected a completed plan with a result, got %s %q", plan.Status, content)
+	}
+}
+
+func TestExecutionPlanReplanning(t *testing.T) {
+	revised := `{"description": "Search instead", "steps": [{"toolName": "search", "description": "Search the archive", "parameters": {"input": "archive"}}]}`
+	var prompts []string
+	llm := &mockLLM{name: "mock", generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+		prompts = append(prompts, prompt)
+		return revised, nil
+	}}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithTools(
+			&mockTool{name: "search", description: "Search the web"},
+			&mockTool{name: "fetch", description: "Fetch a page", runFunc: func(ctx context.Context, input string) (string, error) {
+				return "", fmt.Errorf("connection refused")
+			}},
+		),
+		WithMemory(memory.NewConversationBuffer()),
+		WithPlanReplanning(1),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	plan := executionplan.NewExecutionPlan("Read the page", []executionplan.ExecutionStep{
+		{ToolName: "fetch", Description: "Fetch the page", Parameters: map[string]interface{}{"input": "https://example.com"}},
+	})
+
+	ctx := multitenancy.WithOrgID(context.Background(), "org")
+	ctx = memory.WithConversationID(ctx, "conv")
+	events, err := agent.ApproveExecutionPlanStream(ctx, plan)
+	if err != nil {
+		t.Fatalf("ApproveExecutionPlanStream: %v", err)
+	}
+
+	var statuses []interface{}
+	for event := range events {
+		if event.Type == interfaces.AgentEventPlanStep {
+			statuses = append(statuses, event.Metadata["status"])
+		}
+		if event.Type == interfaces.AgentEventError {
+			t.Fatalf("Unexpected error event: %v", event.Error)
+		}
+	}
+
+	if fmt.Sprint(statuses) != "[started failed replanned started completed]" {
+		t.Errorf("Unexpected step statuses %v", statuses)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "connection refused") {
+		t.Errorf("Expected one re-planning prompt with the error, got %v", prompts)
+	}
+	if plan.Status != executionplan.StatusCompleted || plan.Replans != 1 || plan.Steps[0].ToolName != "search" {
+		t.Errorf("Unexpected plan %+v", plan)
+	}
+}
@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ErrRealtimeNotSupported is returned by StartRealtime when the agent has no
+// realtime provider
+var ErrRealtimeNotSupported = errors.New("realtime sessions are not supported by the agent's LLM; configure a provider with WithRealtime")
+
+// realtimeOptions configures the realtime sessions of an agent
+type realtimeOptions struct {
+	provider interfaces.RealtimeProvider
+	config   interfaces.RealtimeConfig
+}
+
+// WithRealtime sets the provider and configuration of the agent's realtime
+// voice sessions, e.g. the OpenAI or Gemini client. Without it, sessions use
+// the agent's LLM when it supports realtime sessions. Instructions and tools
+// left empty in config default to the agent's system prompt and tools.
+//
+//	agent.WithRealtime(openai.NewClient(apiKey), interfaces.RealtimeConfig{Voice: "verse"})
+func WithRealtime(provider interfaces.RealtimeProvider, config interfaces.RealtimeConfig) Option {
+	return func(a *Agent) {
+		a.realtime = &realtimeOptions{provider: provider, config: config}
+	}
+}
+
+// StartRealtime opens a realtime voice session with the agent's instructions
+// and tools. Tool calls are run by the agent: the returned session reports
+// each call and its result as events, and sends the result to the model.
+// Callers must read Events until it is closed and Close the session when done.
+func (a *Agent) StartRealtime(ctx context.Context) (interfaces.RealtimeSession, error) {
+	var config interfaces.RealtimeConfig
+	provider := realtimeProvider(a.llm)
+	if a.realtime != nil {
+		provider, config = a.realtime.provider, a.realtime.config
+	}
+	if provider == nil {
+		return nil, ErrRealtimeNotSupported
+	}
+
+	if config.Instructions == "" {
+		config.Instructions = a.systemPrompt
+	}
+	if config.Tools == nil {
+		config.Tools = a.currentTools()
+	}
+	config.Tools = a.wrapTools(config.Tools, getUsageTracker(ctx))
+
+	session, err := provider.ConnectRealtime(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make(map[string]interfaces.Tool, len(config.Tools))
+	for _, tool := range config.Tools {
+		tools[tool.Name()] = tool
+	}
+	s := &agentRealtimeSession{
+		RealtimeSession: session,
+		agent:           a,
+		tools:           tools,
+		events:          make(chan interfaces.RealtimeEvent, cap(session.Events())),
+	}
+	go s.run(ctx)
+	return s, nil
+}
+
+// realtimeProvider returns the LLM, or the LLM wrapped by its middlewares,
+// when it opens realtime sessions
+func realtimeProvider(llm interfaces.LLM) interfaces.RealtimeProvider {
+	for llm != nil {
+		if provider, ok := llm.(interfaces.RealtimeProvider); ok {
+			return provider
+		}
+		wrapper, ok := llm.(interface{ Unwrap() interfaces.LLM })
+		if !ok {
+			return nil
+		}
+		llm = wrapper.Unwrap()
+	}
+	return nil
+}
+
+// agentRealtimeSession runs the tool calls of a realtime session
+type agentRealtimeSession struct {
+	interfaces.RealtimeSession
+	agent  *Agent
+	tools  map[string]interfaces.Tool
+	events chan interfaces.RealtimeEvent
+}
+
+// Events returns the events of the session, including tool results
+func (s *agentRealtimeSession) Events() <-chan interfaces.RealtimeEvent {
+	return s.events
+}
+
+// run forwards the events of the session and answers its tool calls
+func (s *agentRealtimeSession) run(ctx context.Context) {
+	defer close(s.events)
+	// Drain the events left when ctx ends so the session can shut down
+	defer func() {
+		go func() {
+			for range s.RealtimeSession.Events() {
+			}
+		}()
+	}()
+
+	for event := range s.RealtimeSession.Events() {
+		if !s.send(ctx, event) {
+			return
+		}
+		if event.Type != interfaces.RealtimeEventToolCall || event.ToolCall == nil {
+			continue
+		}
+
+		result := s.runTool(ctx, *event.ToolCall)
+		if err := s.SendToolResult(ctx, result); err != nil {
+			s.agent.logger.Warn(ctx, "Failed to send realtime tool result", map[string]interface{}{
+				"tool":  result.Name,
+				"error": err.Error(),
+			})
+			if !s.send(ctx, interfaces.RealtimeEvent{Type: interfaces.RealtimeEventError, Error: fmt.Errorf("failed to send result of %s: %w", result.Name, err)}) {
+				return
+			}
+			continue
+		}
+		if !s.send(ctx, interfaces.RealtimeEvent{Type: interfaces.RealtimeEventToolResult, ToolResult: &result}) {
+			return
+		}
+	}
+}
+
+// send forwards an event, returning false when ctx is done
+func (s *agentRealtimeSession) send(ctx context.Context, event interfaces.RealtimeEvent) bool {
+	select {
+	case s.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// runTool runs a tool call, returning failures as error results so the model
+// can recover
+func (s *agentRealtimeSession) runTool(ctx context.Context, call interfaces.ToolCall) interfaces.ToolResult {
+	result := interfaces.ToolResult{ToolCallID: call.ID, Name: call.Name}
+
+	tool, ok := s.tools[call.Name]
+	if !ok {
+		result.Content, result.IsError = fmt.Sprintf("tool %s not found", call.Name), true
+		return result
+	}
+
+	output, err := tool.Execute(ctx, call.Arguments)
+	if err != nil {
+		s.agent.logger.Warn(ctx, "Realtime tool call failed", map[string]interface{}{
+			"tool":  call.Name,
+			"error": err.Error(),
+		})
+		result.Content, result.IsError = err.Error(), true
+		return result
+	}
+	result.Content = output
+	return result
+}
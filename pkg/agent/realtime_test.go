@@ -0,0 +1,143 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+)
+
+type fakeRealtimeSession struct {
+	events  chan interfaces.RealtimeEvent
+	results chan interfaces.ToolResult
+}
+
+func (s *fakeRealtimeSession) SendAudio(ctx context.Context, audio []byte) error { return nil }
+
+func (s *fakeRealtimeSession) SendText(ctx context.Context, text string) error { return nil }
+
+func (s *fakeRealtimeSession) SendToolResult(ctx context.Context, result interfaces.ToolResult) error {
+	s.results <- result
+	return nil
+}
+
+func (s *fakeRealtimeSession) Interrupt(ctx context.Context) error { return nil }
+
+func (s *fakeRealtimeSession) InputAudioFormat() string { return "audio/pcm;rate=16000" }
+
+func (s *fakeRealtimeSession) Events() <-chan interfaces.RealtimeEvent { return s.events }
+
+func (s *fakeRealtimeSession) Close() error { return nil }
+
+type fakeRealtimeProvider struct {
+	config  interfaces.RealtimeConfig
+	session *fakeRealtimeSession
+}
+
+func (p *fakeRealtimeProvider) ConnectRealtime(ctx context.Context, config interfaces.RealtimeConfig) (interfaces.RealtimeSession, error) {
+	p.config = config
+	return p.session, nil
+}
+
+func TestStartRealtime(t *testing.T) {
+	session := &fakeRealtimeSession{
+		events:  make(chan interfaces.RealtimeEvent, 4),
+		results: make(chan interfaces.ToolResult, 2),
+	}
+	provider := &fakeRealtimeProvider{session: session}
+	weather := &mockTool{name: "weather", description: "Gets the weather"}
+
+	agent, err := NewAgent(
+		WithLLM(mock.New()),
+		WithSystemPrompt("You are a voice assistant."),
+		WithTools(weather),
+		WithRealtime(provider, interfaces.RealtimeConfig{Voice: "verse"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	realtime, err := agent.StartRealtime(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.config.Instructions != "You are a voice assistant." || provider.config.Voice != "verse" || len(provider.config.Tools) != 1 {
+		t.Errorf("unexpected config %+v", provider.config)
+	}
+
+	session.events <- interfaces.RealtimeEvent{Type: interfaces.RealtimeEventToolCall, ToolCall: &interfaces.ToolCall{ID: "call-1", Name: "weather", Arguments: `{"city":"Paris"}`}}
+	session.events <- interfaces.RealtimeEvent{Type: interfaces.RealtimeEventToolCall, ToolCall: &interfaces.ToolCall{ID: "call-2", Name: "missing"}}
+	session.events <- interfaces.RealtimeEvent{Type: interfaces.RealtimeEventTurnComplete}
+	close(session.events)
+
+	var types []interfaces.RealtimeEventType
+	for event := range realtime.Events() {
+		types = append(types, event.Type)
+	}
+	want := []interfaces.RealtimeEventType{
+		interfaces.RealtimeEventToolCall, interfaces.RealtimeEventToolResult,
+		interfaces.RealtimeEventToolCall, interfaces.RealtimeEventToolResult,
+		interfaces.RealtimeEventTurnComplete,
+	}
+	if len(types) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("expected events %v, got %v", want, types)
+		}
+	}
+
+	result := <-session.results
+	if result.ToolCallID != "call-1" || result.IsError || result.Content != `tool weather executed with: {"city":"Paris"}` {
+		t.Errorf("unexpected result %+v", result)
+	}
+	result = <-session.results
+	if result.ToolCallID != "call-2" || !result.IsError {
+		t.Errorf("expected an error result for an unknown tool, got %+v", result)
+	}
+}
+
+func TestStartRealtimeNotSupported(t *testing.T) {
+	agent, err := NewAgent(WithLLM(mock.New()))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	if _, err := agent.StartRealtime(context.Background()); !errors.Is(err, ErrRealtimeNotSupported) {
+		t.Errorf("expected ErrRealtimeNotSupported, got %v", err)
+	}
+}
+
+// realtimeLLM is an LLM that also opens realtime sessions
+type realtimeLLM struct {
+	mockLLM
+	*fakeRealtimeProvider
+}
+
+func TestStartRealtimeWithTracing(t *testing.T) {
+	session := &fakeRealtimeSession{events: make(chan interfaces.RealtimeEvent)}
+	llm := &realtimeLLM{fakeRealtimeProvider: &fakeRealtimeProvider{session: session}}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithSystemPrompt("You are a voice assistant."),
+		WithTracer(&recordingTracer{}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	realtime, err := agent.StartRealtime(context.Background())
+	if err != nil {
+		t.Fatalf("expected the traced LLM to open realtime sessions, got %v", err)
+	}
+	if llm.config.Instructions != "You are a voice assistant." {
+		t.Errorf("unexpected config %+v", llm.config)
+	}
+	close(session.events)
+	for range realtime.Events() {
+	}
+}
@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/grpc/client"
+	"github.com/Ingenimax/agent-sdk-go/pkg/grpc/pb"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// remoteAgentClient runs a remote agent, served by a single service or by a
+// pool of replicas
+type remoteAgentClient interface {
+	Connect() error
+	Disconnect() error
+	Run(ctx context.Context, input string) (string, error)
+	RunWithAuth(ctx context.Context, input string, authToken string) (string, error)
+	RunStream(ctx context.Context, input string) (<-chan interfaces.AgentStreamEvent, error)
+	RunStreamWithAuth(ctx context.Context, input string, authToken string) (<-chan interfaces.AgentStreamEvent, error)
+	GetMetadata(ctx context.Context) (*pb.MetadataResponse, error)
+}
+
+// WithURLs creates a remote agent that spreads its runs across replicas of
+// the same agent service. Replicas failing health checks get no runs until
+// they recover, and runs sent to an unreachable replica are retried on the
+// next one.
+func WithURLs(urls ...string) Option {
+	return func(a *Agent) {
+		if len(urls) == 0 {
+			return
+		}
+		a.isRemote = true
+		a.remoteURL = urls[0]
+		a.remoteURLs = urls
+		// For remote agents, LLM is not required locally
+		a.llm = nil
+	}
+}
+
+// WithLoadBalancing sets how the runs of a remote agent created with WithURLs
+// are spread across its replicas; defaults to client.RoundRobin
+func WithLoadBalancing(strategy client.LoadBalancingStrategy) Option {
+	return func(a *Agent) {
+		a.loadBalancing = strategy
+	}
+}
+
+// GetRemoteURLs returns the URLs of the replicas of a remote agent
+func (a *Agent) GetRemoteURLs() []string {
+	if len(a.remoteURLs) > 0 {
+		return a.remoteURLs
+	}
+	if a.remoteURL != "" {
+		return []string{a.remoteURL}
+	}
+	return nil
+}
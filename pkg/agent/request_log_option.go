@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging/requestlog"
+)
+
+// WithRequestLog logs the agent's prompts, completions, tool calls and their
+// timings with l. Fields are redacted by the logger's rules before they are
+// written to its sink.
+//
+//	agent.WithRequestLog(requestlog.New(requestlog.NewStdoutSink()))
+func WithRequestLog(l *requestlog.Logger) Option {
+	return func(a *Agent) {
+		a.requestLog = l
+	}
+}
+
+// wrapToolsWithRequestLog wraps each tool so its executions are logged.
+// Returns the original slice unchanged when request logging is not configured.
+func (a *Agent) wrapToolsWithRequestLog(tools []interfaces.Tool) []interfaces.Tool {
+	if a.requestLog == nil || len(tools) == 0 {
+		return tools
+	}
+
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = requestlog.NewLoggedTool(tool, a.requestLog)
+	}
+	return wrapped
+}
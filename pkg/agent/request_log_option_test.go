@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging/requestlog"
+)
+
+func TestRequestLogRecordsLLMAndToolCalls(t *testing.T) {
+	var buf bytes.Buffer
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithName("logged"),
+		WithRequestLog(requestlog.New(requestlog.NewJSONSink(&buf))),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	if _, err := agent.Run(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tool := &mockTool{
+		name: "lookup",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "found", nil
+		},
+	}
+	wrapped := agent.wrapTools([]interfaces.Tool{tool}, nil)
+	if _, err := wrapped[0].Run(context.Background(), "{}"); err != nil {
+		t.Fatalf("unexpected tool error: %v", err)
+	}
+
+	kinds := map[string]int{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+		kinds[record["kind"].(string)]++
+	}
+	if kinds["llm_call"] == 0 {
+		t.Errorf("expected llm_call records, got %v", kinds)
+	}
+	if kinds["tool_call"] != 1 {
+		t.Errorf("expected 1 tool_call record, got %v", kinds)
+	}
+}
@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/retriever"
+)
+
+// WithRetriever enables automatic retrieval-augmented generation.
+// Before each LLM call the agent queries the retriever with the user input and
+// appends the cited passages to the system prompt. Top-k and the token budget
+// are configured on the retriever itself (see retriever.WithTopK and
+// retriever.WithTokenBudget). Use WithTools to also expose it as a tool.
+func WithRetriever(r *retriever.Retriever) Option {
+	return func(a *Agent) {
+		a.retriever = r
+	}
+}
+
+// GetRetriever returns the retriever used for automatic context injection, if any
+func (a *Agent) GetRetriever() *retriever.Retriever {
+	return a.retriever
+}
+
+// systemPromptWithRetrievedContext returns the system prompt augmented with
+// passages retrieved for the given input. Retrieval failures are logged and the
+// plain system prompt is returned so that a knowledge base outage does not
+// break the agent.
+func (a *Agent) systemPromptWithRetrievedContext(ctx context.Context, input string) string {
+	if a.retriever == nil {
+		return a.systemPrompt
+	}
+
+	retrieved, err := a.retriever.RetrieveContext(ctx, input)
+	if err != nil {
+		a.logger.Warn(ctx, "Failed to retrieve context", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return a.systemPrompt
+	}
+	if retrieved == "" {
+		return a.systemPrompt
+	}
+
+	if a.injectionDetector != nil {
+		retrieved, err = a.screenUntrusted(ctx, "retrieval", retrieved)
+		if err != nil {
+			a.logger.Warn(ctx, "Dropped retrieved context", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return a.systemPrompt
+		}
+	}
+
+	contextBlock := fmt.Sprintf("Use the following retrieved passages to answer when relevant. "+
+		"Cite them using their bracketed numbers, e.g. [1].\n\n<retrieved_context>\n%s\n</retrieved_context>", retrieved)
+
+	if a.systemPrompt == "" {
+		return contextBlock
+	}
+	return a.systemPrompt + "\n\n" + contextBlock
+}
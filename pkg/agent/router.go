@@ -0,0 +1,310 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/structuredoutput"
+)
+
+// ErrNoRoute is returned when no agent of a router is confident enough to
+// handle a request and the router has no fallback agent
+var ErrNoRoute = errors.New("no agent to route the request to")
+
+// Route is the agent a routing strategy chose for a request
+type Route struct {
+	// Agent is the name of the chosen agent, empty if none matched
+	Agent string
+
+	// Confidence is how confident the strategy is in its choice, from 0 to 1
+	Confidence float64
+
+	// Reason explains the choice, if the strategy gives one
+	Reason string
+}
+
+// RoutingStrategy chooses which of the agents of a router handles a request
+type RoutingStrategy interface {
+	Route(ctx context.Context, input string, agents []*Agent) (Route, error)
+}
+
+// Router classifies requests and dispatches each to the best of its agents,
+// described by their name and description
+type Router struct {
+	agents    []*Agent
+	strategy  RoutingStrategy
+	threshold float64
+	fallback  *Agent
+	logger    logging.Logger
+}
+
+// RouterOption represents an option for configuring a router
+type RouterOption func(*Router)
+
+// WithRoutingStrategy sets how the router chooses agents: LLMRouting,
+// EmbeddingRouting, RuleRouting or a custom strategy
+func WithRoutingStrategy(strategy RoutingStrategy) RouterOption {
+	return func(r *Router) {
+		r.strategy = strategy
+	}
+}
+
+// WithConfidenceThreshold sets the confidence below which a request goes to
+// the fallback agent instead of the chosen one
+func WithConfidenceThreshold(threshold float64) RouterOption {
+	return func(r *Router) {
+		r.threshold = threshold
+	}
+}
+
+// WithFallbackAgent sets the agent handling the requests no agent is
+// confident enough to handle. Without it, they fail with ErrNoRoute.
+func WithFallbackAgent(fallback *Agent) RouterOption {
+	return func(r *Router) {
+		r.fallback = fallback
+	}
+}
+
+// WithRouterLogger sets the logger of the router
+func WithRouterLogger(logger logging.Logger) RouterOption {
+	return func(r *Router) {
+		r.logger = logger
+	}
+}
+
+// NewRouter creates a router dispatching requests to agents
+func NewRouter(agents []*Agent, options ...RouterOption) (*Router, error) {
+	r := &Router{
+		agents: agents,
+		logger: logging.New(),
+	}
+	for _, option := range options {
+		option(r)
+	}
+
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("router has no agents")
+	}
+	if r.strategy == nil {
+		return nil, fmt.Errorf("router has no routing strategy")
+	}
+	names := make(map[string]bool, len(agents))
+	for _, a := range agents {
+		if a.GetName() == "" {
+			return nil, fmt.Errorf("router agents must have a name")
+		}
+		if names[a.GetName()] {
+			return nil, fmt.Errorf("router has several agents named %s", a.GetName())
+		}
+		names[a.GetName()] = true
+	}
+	return r, nil
+}
+
+// Route chooses the agent handling the input. The fallback agent is chosen
+// when the strategy matched no agent or is not confident enough.
+func (r *Router) Route(ctx context.Context, input string) (*Agent, Route, error) {
+	route, err := r.strategy.Route(ctx, input, r.agents)
+	if err != nil {
+		if r.fallback == nil {
+			return nil, route, fmt.Errorf("failed to route request: %w", err)
+		}
+		r.logger.Warn(ctx, "Routing failed, using fallback agent", map[string]interface{}{
+			"error":    err.Error(),
+			"fallback": r.fallback.GetName(),
+		})
+		return r.fallback, route, nil
+	}
+
+	chosen := r.agent(route.Agent)
+	if chosen != nil && route.Confidence >= r.threshold {
+		r.logger.Debug(ctx, "Routed request", map[string]interface{}{
+			"agent":      route.Agent,
+			"confidence": route.Confidence,
+			"reason":     route.Reason,
+		})
+		return chosen, route, nil
+	}
+
+	if r.fallback == nil {
+		return nil, route, fmt.Errorf("%w: best match %q has confidence %.2f", ErrNoRoute, route.Agent, route.Confidence)
+	}
+	r.logger.Debug(ctx, "Routing confidence below threshold, using fallback agent", map[string]interface{}{
+		"agent":      route.Agent,
+		"confidence": route.Confidence,
+		"threshold":  r.threshold,
+		"fallback":   r.fallback.GetName(),
+	})
+	return r.fallback, route, nil
+}
+
+// Run routes the input and runs the chosen agent with it
+func (r *Router) Run(ctx context.Context, input string) (string, error) {
+	chosen, _, err := r.Route(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return chosen.Run(ctx, input)
+}
+
+// agent returns the agent with the given name, nil if there is none
+func (r *Router) agent(name string) *Agent {
+	for _, a := range r.agents {
+		if a.GetName() == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// llmRouting asks an LLM which agent handles a request
+type llmRouting struct {
+	llm interfaces.LLM
+}
+
+// LLMRouting returns a routing strategy asking the LLM to classify requests
+// by the descriptions of the agents, with a confidence
+func LLMRouting(llm interfaces.LLM) RoutingStrategy {
+	return &llmRouting{llm: llm}
+}
+
+func (s *llmRouting) Route(ctx context.Context, input string, agents []*Agent) (Route, error) {
+	var descriptions strings.Builder
+	for _, a := range agents {
+		fmt.Fprintf(&descriptions, "- %s: %s\n", a.GetName(), a.GetDescription())
+	}
+
+	prompt := fmt.Sprintf(`You are a router that determines which specialized agent should handle a user request.
+
+Available agents:
+%s
+User request: %s
+
+Respond with only a JSON object: {"agent": "<agent name>", "confidence": <0 to 1>, "reason": "<short reason>"}. Use an empty agent name if no agent fits the request.`, descriptions.String(), input)
+
+	response, err := s.llm.Generate(ctx, prompt)
+	if err != nil {
+		return Route{}, fmt.Errorf("failed to generate routing response: %w", err)
+	}
+
+	var decision struct {
+		Agent      string  `json:"agent"`
+		Confidence float64 `json:"confidence"`
+		Reason     string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(structuredoutput.ExtractJSON(response)), &decision); err != nil {
+		return Route{}, fmt.Errorf("failed to parse routing response: %w", err)
+	}
+	return Route{Agent: decision.Agent, Confidence: decision.Confidence, Reason: decision.Reason}, nil
+}
+
+// embeddingRouting chooses the agent whose description is the most similar
+// to a request
+type embeddingRouting struct {
+	embedder interfaces.Embedder
+
+	mu         sync.Mutex
+	embeddings map[string][]float32 // keyed by agent name + description
+}
+
+// EmbeddingRouting returns a routing strategy choosing the agent whose name
+// and description are the most similar to the request. The confidence is
+// the cosine similarity.
+func EmbeddingRouting(embedder interfaces.Embedder) RoutingStrategy {
+	return &embeddingRouting{
+		embedder:   embedder,
+		embeddings: make(map[string][]float32),
+	}
+}
+
+func (s *embeddingRouting) Route(ctx context.Context, input string, agents []*Agent) (Route, error) {
+	query, err := s.embedder.Embed(ctx, input)
+	if err != nil {
+		return Route{}, fmt.Errorf("failed to embed request: %w", err)
+	}
+
+	var best Route
+	for _, a := range agents {
+		embedding, err := s.embedding(ctx, a)
+		if err != nil {
+			return Route{}, err
+		}
+		similarity, err := s.embedder.CalculateSimilarity(query, embedding, "cosine")
+		if err != nil {
+			return Route{}, fmt.Errorf("failed to compare request with agent %s: %w", a.GetName(), err)
+		}
+		if best.Agent == "" || float64(similarity) > best.Confidence {
+			best = Route{Agent: a.GetName(), Confidence: float64(similarity)}
+		}
+	}
+	return best, nil
+}
+
+// embedding returns the embedding of an agent's name and description,
+// embedding it on first use
+func (s *embeddingRouting) embedding(ctx context.Context, a *Agent) ([]float32, error) {
+	text := a.GetName() + ": " + a.GetDescription()
+
+	s.mu.Lock()
+	embedding, ok := s.embeddings[text]
+	s.mu.Unlock()
+	if ok {
+		return embedding, nil
+	}
+
+	embedding, err := s.embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed agent %s: %w", a.GetName(), err)
+	}
+	s.mu.Lock()
+	s.embeddings[text] = embedding
+	s.mu.Unlock()
+	return embedding, nil
+}
+
+// RoutingRule routes the requests containing one of its keywords or matching
+// its pattern to an agent
+type RoutingRule struct {
+	// Agent is the name of the agent handling the matching requests
+	Agent string
+
+	// Keywords are matched case-insensitively
+	Keywords []string
+
+	// Pattern is matched against the request, if set
+	Pattern *regexp.Regexp
+}
+
+// ruleRouting routes requests by the first matching rule
+type ruleRouting struct {
+	rules []RoutingRule
+}
+
+// RuleRouting returns a routing strategy choosing the agent of the first
+// rule matching the request, with a confidence of 1. Requests matching no
+// rule have a confidence of 0 and go to the fallback agent.
+func RuleRouting(rules ...RoutingRule) RoutingStrategy {
+	return &ruleRouting{rules: rules}
+}
+
+func (s *ruleRouting) Route(ctx context.Context, input string, agents []*Agent) (Route, error) {
+	lower := strings.ToLower(input)
+	for _, rule := range s.rules {
+		for _, keyword := range rule.Keywords {
+			if strings.Contains(lower, strings.ToLower(keyword)) {
+				return Route{Agent: rule.Agent, Confidence: 1, Reason: fmt.Sprintf("request contains %q", keyword)}, nil
+			}
+		}
+		if rule.Pattern != nil && rule.Pattern.MatchString(input) {
+			return Route{Agent: rule.Agent, Confidence: 1, Reason: fmt.Sprintf("request matches %s", rule.Pattern)}, nil
+		}
+	}
+	return Route{}, nil
+}
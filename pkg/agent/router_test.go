@@ -0,0 +1,162 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/embedding"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// keywordEmbedder embeds text as a bag of known keywords
+type keywordEmbedder struct {
+	vocabulary []string
+}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	text = strings.ToLower(text)
+	vector := make([]float32, len(e.vocabulary)+1)
+	vector[len(e.vocabulary)] = 0.01 // avoid zero vectors
+	for i, word := range e.vocabulary {
+		if strings.Contains(text, word) {
+			vector[i] = 1
+		}
+	}
+	return vector, nil
+}
+
+func (e *keywordEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.Embed(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (e *keywordEmbedder) CalculateSimilarity(vec1, vec2 []float32, metric string) (float32, error) {
+	return embedding.CalculateSimilarity(vec1, vec2, metric)
+}
+
+func newRouterAgents(t *testing.T) []*Agent {
+	t.Helper()
+	var agents []*Agent
+	for name, description := range map[string]string{
+		"billing": "Handles invoices and payments",
+		"support": "Troubleshoots errors and outages",
+	} {
+		response := name + " answer"
+		a, err := NewAgent(
+			WithName(name),
+			WithDescription(description),
+			WithLLM(&mockLLM{generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+				return response, nil
+			}}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create agent: %v", err)
+		}
+		agents = append(agents, a)
+	}
+	return agents
+}
+
+func TestRouter_LLMRouting(t *testing.T) {
+	var routingPrompt string
+	classifier := &mockLLM{generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+		routingPrompt = prompt
+		if strings.Contains(prompt, "weather") {
+			return `{"agent": "support", "confidence": 0.3, "reason": "unclear"}`, nil
+		}
+		return "```json\n{\"agent\": \"billing\", \"confidence\": 0.9, \"reason\": \"invoice question\"}\n```", nil
+	}}
+	fallback, err := NewAgent(WithName("general"), WithLLM(&mockLLM{}))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	router, err := NewRouter(newRouterAgents(t),
+		WithRoutingStrategy(LLMRouting(classifier)),
+		WithConfidenceThreshold(0.5),
+		WithFallbackAgent(fallback),
+	)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	result, err := router.Run(context.Background(), "Why was my invoice charged twice?")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result != "billing answer" {
+		t.Errorf("Expected the billing agent's answer, got %q", result)
+	}
+	if !strings.Contains(routingPrompt, "- billing: Handles invoices and payments") {
+		t.Errorf("Expected the agents in the routing prompt, got %q", routingPrompt)
+	}
+
+	chosen, route, err := router.Route(context.Background(), "What's the weather?")
+	if err != nil {
+		t.Fatalf("Route() error = %v", err)
+	}
+	if chosen.GetName() != "general" || route.Agent != "support" || route.Confidence != 0.3 {
+		t.Errorf("Expected the fallback below the threshold, got %s for %+v", chosen.GetName(), route)
+	}
+}
+
+func TestRouter_EmbeddingRouting(t *testing.T) {
+	embedder := &keywordEmbedder{vocabulary: []string{"invoice", "payment", "error", "outage"}}
+	router, err := NewRouter(newRouterAgents(t), WithRoutingStrategy(EmbeddingRouting(embedder)))
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	chosen, route, err := router.Route(context.Background(), "I get an error during the outage")
+	if err != nil {
+		t.Fatalf("Route() error = %v", err)
+	}
+	if chosen.GetName() != "support" || route.Confidence <= 0 {
+		t.Errorf("Expected the support agent, got %s with confidence %.2f", chosen.GetName(), route.Confidence)
+	}
+}
+
+func TestRouter_RuleRouting(t *testing.T) {
+	router, err := NewRouter(newRouterAgents(t), WithRoutingStrategy(RuleRouting(
+		RoutingRule{Agent: "billing", Keywords: []string{"Invoice", "refund"}},
+		RoutingRule{Agent: "support", Pattern: regexp.MustCompile(`(?i)error \d+`)},
+	)))
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"Where is my invoice?":     "billing",
+		"I see ERROR 500 on login": "support",
+	}
+	for input, want := range tests {
+		chosen, _, err := router.Route(context.Background(), input)
+		if err != nil || chosen.GetName() != want {
+			t.Errorf("Route(%q) = %v, %v, want %s", input, chosen, err, want)
+		}
+	}
+
+	// Without a matching rule or fallback the request is not routed
+	if _, err := router.Run(context.Background(), "Hello"); !errors.Is(err, ErrNoRoute) {
+		t.Errorf("Expected ErrNoRoute, got %v", err)
+	}
+}
+
+func TestNewRouter_Errors(t *testing.T) {
+	agents := newRouterAgents(t)
+	if _, err := NewRouter(nil, WithRoutingStrategy(RuleRouting())); err == nil {
+		t.Error("Expected an error without agents")
+	}
+	if _, err := NewRouter(agents); err == nil {
+		t.Error("Expected an error without a routing strategy")
+	}
+	if _, err := NewRouter(append(agents, agents[0]), WithRoutingStrategy(RuleRouting())); err == nil {
+		t.Error("Expected an error for agents with the same name")
+	}
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/structuredoutput"
+)
+
+// schemasKey is the reserved top-level key of agent and task config files
+// under which named output schemas are defined
+const schemasKey = "schemas"
+
+// SchemaConfigYAML defines a named output schema in YAML, either inline or
+// as a reference to a Go type registered with RegisterSchemaType
+type SchemaConfigYAML struct {
+	GoType           string                 `yaml:"go_type,omitempty"`
+	SchemaDefinition map[string]interface{} `yaml:"schema_definition,omitempty"`
+}
+
+var (
+	schemaRegistryMu sync.RWMutex
+	schemaRegistry   = make(map[string]interfaces.ResponseFormat)
+)
+
+// RegisterSchema registers a JSON schema under name so YAML configs can
+// reference it with response_format.schema_ref. A schema registered under
+// an existing name replaces it.
+func RegisterSchema(name string, schema interfaces.JSONSchema) {
+	schemaRegistryMu.Lock()
+	defer schemaRegistryMu.Unlock()
+	schemaRegistry[name] = interfaces.ResponseFormat{
+		Type:   interfaces.ResponseFormatJSON,
+		Name:   name,
+		Schema: schema,
+	}
+}
+
+// RegisterSchemaType registers the JSON schema of a Go struct under name,
+// generated as with structuredoutput.NewResponseFormat
+//
+//	agent.RegisterSchemaType("Person", Person{})
+func RegisterSchemaType(name string, v interface{}) {
+	RegisterSchema(name, structuredoutput.NewResponseFormat(v).Schema)
+}
+
+// LookupSchema returns the response format registered under name
+func LookupSchema(name string) (*interfaces.ResponseFormat, bool) {
+	schemaRegistryMu.RLock()
+	defer schemaRegistryMu.RUnlock()
+	format, ok := schemaRegistry[name]
+	if !ok {
+		return nil, false
+	}
+	return &format, true
+}
+
+// registerSchemasFromYAML registers the schemas defined under the reserved
+// schemas key of a config file. Go type references must already be
+// registered.
+func registerSchemasFromYAML(data []byte) error {
+	var file struct {
+		Schemas map[string]SchemaConfigYAML `yaml:"schemas"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to unmarshal schemas: %w", err)
+	}
+
+	names := make([]string, 0, len(file.Schemas))
+	for name := range file.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		config := file.Schemas[name]
+		switch {
+		case config.GoType != "" && config.SchemaDefinition != nil:
+			return fmt.Errorf("schema %s must set either go_type or schema_definition, not both", name)
+		case config.GoType != "":
+			format, ok := LookupSchema(config.GoType)
+			if !ok {
+				return fmt.Errorf("schema %s references unregistered Go type %s: register it with RegisterSchemaType before loading configs", name, config.GoType)
+			}
+			RegisterSchema(name, format.Schema)
+		case config.SchemaDefinition != nil:
+			RegisterSchema(name, interfaces.JSONSchema(config.SchemaDefinition))
+		default:
+			return fmt.Errorf("schema %s must set go_type or schema_definition", name)
+		}
+	}
+	return nil
+}
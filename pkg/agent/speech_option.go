@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// Metadata keys of the synthesized speech of a response
+const (
+	// MetadataAudioURL is the storage URL of the synthesized response
+	MetadataAudioURL = "audio_url"
+
+	// MetadataAudioMIMEType is the MIME type of the synthesized response
+	MetadataAudioMIMEType = "audio_mime_type"
+)
+
+// speechOutput synthesizes final responses and stores the audio
+type speechOutput struct {
+	tts     interfaces.TextToSpeech
+	store   storage.MediaStorage
+	options []interfaces.SynthesizeOption
+}
+
+// WithSpeechOutput synthesizes the final response of RunDetailed and
+// RunStream with tts, for voice assistants. The audio is saved to store and
+// its URL is set in the response metadata (MetadataAudioURL), or in the
+// metadata of the complete event of streams. Failed synthesis is logged and
+// leaves the response without audio.
+//
+//	agent.WithSpeechOutput(speech.NewOpenAISynthesizer(apiKey), store, interfaces.WithSpeechVoice("nova"))
+func WithSpeechOutput(tts interfaces.TextToSpeech, store storage.MediaStorage, options ...interfaces.SynthesizeOption) Option {
+	return func(a *Agent) {
+		a.speechOutput = &speechOutput{tts: tts, store: store, options: options}
+	}
+}
+
+// synthesizeResponse synthesizes and stores response, returning the metadata
+// describing the audio, or nil when speech output is disabled or failed
+func (a *Agent) synthesizeResponse(ctx context.Context, response string) map[string]interface{} {
+	if a.speechOutput == nil || strings.TrimSpace(response) == "" {
+		return nil
+	}
+
+	speech, err := a.speechOutput.tts.Synthesize(ctx, response, a.speechOutput.options...)
+	if err != nil {
+		a.logger.Warn(ctx, "Failed to synthesize response", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	if a.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, a.orgID)
+	}
+	orgID, _ := multitenancy.GetOrgID(ctx)
+	conversationID, _ := memory.GetConversationID(ctx)
+	url, err := a.speechOutput.store.Store(ctx, &interfaces.GeneratedImage{
+		Data:     speech.Data,
+		MimeType: speech.MIMEType,
+	}, storage.StorageMetadata{
+		OrgID:     orgID,
+		ThreadID:  conversationID,
+		Prompt:    response,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		a.logger.Warn(ctx, "Failed to store synthesized response", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	return map[string]interface{}{
+		MetadataAudioURL:      url,
+		MetadataAudioMIMEType: speech.MIMEType,
+	}
+}
+
+// speakStream forwards stream events while accumulating the response, which
+// is synthesized when the complete event arrives
+func (a *Agent) speakStream(ctx context.Context, events <-chan interfaces.AgentStreamEvent) <-chan interfaces.AgentStreamEvent {
+	if a.speechOutput == nil {
+		return events
+	}
+
+	out := make(chan interfaces.AgentStreamEvent, cap(events))
+	go func() {
+		defer close(out)
+
+		var content strings.Builder
+		delivered := true
+		for event := range events {
+			switch event.Type {
+			case interfaces.AgentEventContent:
+				content.WriteString(event.Content)
+			case interfaces.AgentEventComplete:
+				if audio := a.synthesizeResponse(ctx, content.String()); audio != nil {
+					metadata := make(map[string]interface{}, len(event.Metadata)+len(audio))
+					for key, value := range event.Metadata {
+						metadata[key] = value
+					}
+					for key, value := range audio {
+						metadata[key] = value
+					}
+					event.Metadata = metadata
+				}
+			}
+			// Keep draining after cancellation so the producer can exit
+			if delivered {
+				delivered = sendEvent(ctx, out, event)
+			}
+		}
+	}()
+	return out
+}
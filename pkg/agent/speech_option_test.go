@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+type fakeSynthesizer struct {
+	text string
+	err  error
+}
+
+func (f *fakeSynthesizer) Synthesize(ctx context.Context, text string, options ...interfaces.SynthesizeOption) (*interfaces.Speech, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.text = text
+	return &interfaces.Speech{Data: []byte("ID3"), MIMEType: "audio/mpeg"}, nil
+}
+
+type fakeStorage struct {
+	stored map[string][]byte
+}
+
+func (f *fakeStorage) Store(ctx context.Context, image *interfaces.GeneratedImage, metadata storage.StorageMetadata) (string, error) {
+	url := "https://cdn.example.com/" + metadata.OrgID + "/speech.mp3"
+	f.stored[url] = image.Data
+	return url, nil
+}
+
+func (f *fakeStorage) Delete(ctx context.Context, url string) error { return nil }
+
+func (f *fakeStorage) Get(ctx context.Context, url string) ([]byte, error) { return f.stored[url], nil }
+
+func (f *fakeStorage) Name() string { return "fake" }
+
+func TestSpeechOutput(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.Any()).Respond("It is sunny.")
+
+	tts := &fakeSynthesizer{}
+	store := &fakeStorage{stored: map[string][]byte{}}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithOrgID("org-a"),
+		WithSpeechOutput(tts, store),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	response, err := agent.RunDetailed(context.Background(), "Weather?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tts.text != "It is sunny." {
+		t.Errorf("expected the response to be synthesized, got %q", tts.text)
+	}
+	url := response.Metadata[MetadataAudioURL]
+	if url != "https://cdn.example.com/org-a/speech.mp3" || response.Metadata[MetadataAudioMIMEType] != "audio/mpeg" {
+		t.Errorf("unexpected metadata %v", response.Metadata)
+	}
+	if string(store.stored[url.(string)]) != "ID3" {
+		t.Errorf("expected the audio to be stored")
+	}
+
+	events, err := agent.RunStream(context.Background(), "Weather?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var completeURL interface{}
+	for event := range events {
+		if event.Type == interfaces.AgentEventComplete {
+			completeURL = event.Metadata[MetadataAudioURL]
+		}
+	}
+	if completeURL != url {
+		t.Errorf("expected the complete event to carry the audio URL, got %v", completeURL)
+	}
+}
+
+func TestSpeechOutputFailure(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.Any()).Respond("It is sunny.")
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithSpeechOutput(&fakeSynthesizer{err: errors.New("quota exceeded")}, &fakeStorage{}),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	response, err := agent.RunDetailed(context.Background(), "Weather?")
+	if err != nil {
+		t.Fatalf("expected failed synthesis not to fail the run, got %v", err)
+	}
+	if response.Content != "It is sunny." || response.Metadata[MetadataAudioURL] != nil {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/compliance"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
@@ -30,18 +31,78 @@ func sendEvent(ctx context.Context, eventChan chan<- interfaces.AgentStreamEvent
 
 // RunStream executes the agent with streaming response
 func (a *Agent) RunStream(ctx context.Context, input string) (<-chan interfaces.AgentStreamEvent, error) {
-	// If custom stream function is set, use it instead
-	if a.customRunStreamFunc != nil {
-		return a.customRunStreamFunc(ctx, input, a)
+	startTime := time.Now()
+	ctx = a.withArtifacts(ctx)
+	ctx, run := a.startComplianceRun(ctx, input, true)
+	if err := a.hookRunStart(ctx, input); err != nil {
+		a.finishComplianceRun(ctx, run, "", err)
+		a.hookRunEnd(ctx, RunResult{Input: input}, err)
+		return nil, err
 	}
 
-	// If this is a remote agent, delegate to remote execution
-	if a.isRemote {
-		return a.runRemoteStream(ctx, input)
+	var events <-chan interfaces.AgentStreamEvent
+	var err error
+
+	if a.customRunStreamFunc != nil {
+		// If custom stream function is set, use it instead
+		events, err = a.customRunStreamFunc(ctx, input, a)
+	} else if a.isRemote {
+		// If this is a remote agent, delegate to remote execution
+		events, err = a.runRemoteStream(ctx, input)
+	} else {
+		// Local agent execution
+		var handoff *handoffState
+		ctx, handoff = a.withHandoff(ctx)
+		events, err = a.runLocalStream(ctx, input)
+		if err == nil {
+			events = a.handoffStream(ctx, events, handoff, input)
+		}
 	}
+	if err != nil {
+		a.finishComplianceRun(ctx, run, "", err)
+		if a.metrics != nil {
+			a.metrics.ObserveStream(a.name, 0, err)
+		}
+		a.hookRunEnd(ctx, RunResult{Input: input, Duration: time.Since(startTime)}, err)
+		return nil, err
+	}
+	events = a.speakStream(ctx, events)
+	events = a.artifactStream(ctx, events)
+	events = a.meterStream(ctx, events)
+	events = a.hookStream(ctx, input, startTime, events)
+	if run == nil {
+		return events, nil
+	}
+
+	return a.recordStream(ctx, run, events), nil
+}
+
+// recordStream forwards stream events while accumulating the response for the
+// compliance run, which is saved when the stream ends
+func (a *Agent) recordStream(ctx context.Context, run *compliance.Run, events <-chan interfaces.AgentStreamEvent) <-chan interfaces.AgentStreamEvent {
+	out := make(chan interfaces.AgentStreamEvent, cap(events))
+	go func() {
+		defer close(out)
+
+		var content strings.Builder
+		var streamErr error
+		delivered := true
+		for event := range events {
+			switch event.Type {
+			case interfaces.AgentEventContent:
+				content.WriteString(event.Content)
+			case interfaces.AgentEventError:
+				streamErr = event.Error
+			}
+			// Keep draining after cancellation so the producer can exit
+			if delivered {
+				delivered = sendEvent(ctx, out, event)
+			}
+		}
 
-	// Local agent execution
-	return a.runLocalStream(ctx, input)
+		a.finishComplianceRun(context.WithoutCancel(ctx), run, content.String(), streamErr)
+	}()
+	return out
 }
 
 // runLocalStream executes a local agent with streaming
@@ -67,6 +128,8 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 
 		// Inject agent name into context for tracing span naming
 		ctx = tracing.WithAgentName(ctx, a.name)
+		ctx = a.takeInputParts(ctx)
+		ctx = a.routeModel(ctx, input)
 
 		// If orgID is set on the agent, add it to the context
 		if a.orgID != "" {
@@ -76,6 +139,27 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 		// Create usage tracker for detailed metrics collection
 		tracker := newUsageTracker(true)
 		ctx = withUsageTracker(ctx, tracker)
+		defer notifyUsageObserver(ctx, tracker)
+
+		// Resolve the configuration of the run's organization
+		var tenantErr error
+		if ctx, tenantErr = a.applyTenantConfig(ctx); tenantErr != nil {
+			sendEvent(ctx, eventChan, interfaces.AgentStreamEvent{
+				Type:      interfaces.AgentEventError,
+				Error:     tenantErr,
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		defer a.chargeTenant(ctx)
+
+		// Record usage on the compliance run before the stream is closed
+		if run := compliance.RunFromContext(ctx); run != nil {
+			defer func() {
+				usage, execSummary, model := tracker.getResults()
+				run.SetUsage(usage, execSummary, model)
+			}()
+		}
 
 		// Track response length for span logging
 		var responseLength int64
@@ -142,8 +226,9 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 		// Add user message to memory
 		if a.memory != nil {
 			if err := a.memory.AddMessage(ctx, interfaces.Message{
-				Role:    "user",
-				Content: input,
+				Role:         "user",
+				Content:      input,
+				ContentParts: a.inputParts(ctx),
 			}); err != nil {
 				sendEvent(ctx, eventChan, interfaces.AgentStreamEvent{
 					Type:      interfaces.AgentEventError,
@@ -225,7 +310,7 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 		// runtime re-collect below can re-add the same tools; deduplicate after the
 		// append to keep tool names unique (LLM providers like Anthropic reject
 		// requests with duplicate tool names — see issue #308).
-		allTools := a.tools
+		allTools := a.currentTools()
 
 		// Add MCP tools if available
 		if len(a.mcpServers) > 0 {
@@ -239,6 +324,10 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 			}
 		}
 
+		// Keep running with the remaining tools while an MCP server is down
+		allTools = availableTools(allTools)
+		allTools = a.exposeToolsets(ctx, allTools)
+
 		// If tools are available and plan approval is required, we can't stream execution plans yet
 		if (len(allTools) > 0) && a.requirePlanApproval {
 			// For now, fall back to non-streaming execution plan generation
@@ -264,7 +353,7 @@ func (a *Agent) runLocalStream(ctx context.Context, input string) (<-chan interf
 		}
 
 		// Run with streaming
-		length, err := a.runStreamingGeneration(ctx, processedInput, allTools, streamingLLM, eventChan)
+		length, err := a.runStreamingGeneration(ctx, processedInput, a.selectTools(ctx, processedInput, allTools), streamingLLM, eventChan)
 		responseLength = length
 		if err != nil {
 			sendEvent(ctx, eventChan, interfaces.AgentStreamEvent{
@@ -286,13 +375,16 @@ func (a *Agent) runStreamingGeneration(
 	streamingLLM interfaces.StreamingLLM,
 	eventChan chan<- interfaces.AgentStreamEvent,
 ) (int64, error) {
+	registered := a.currentTools()
+
 	// Prepare generation options
 	options := []interfaces.GenerateOption{}
 
-	// Add system prompt if available
-	if a.systemPrompt != "" {
+	// Add system prompt (with retrieved context) if available
+	systemPrompt := a.systemPromptWithRetrievedContext(ctx, input)
+	if systemPrompt != "" {
 		options = append(options, func(opts *interfaces.GenerateOptions) {
-			opts.SystemMessage = a.systemPrompt
+			opts.SystemMessage = systemPrompt
 		})
 	}
 
@@ -302,6 +394,7 @@ func (a *Agent) runStreamingGeneration(
 			opts.LLMConfig = a.llmConfig
 		})
 	}
+	options = append(options, a.deterministic(), applyOutputLimits(ctx), capTenantTemperature(ctx))
 
 	// Add response format if available
 	if a.responseFormat != nil {
@@ -315,9 +408,24 @@ func (a *Agent) runStreamingGeneration(
 		options = append(options, interfaces.WithMaxIterations(a.maxIterations))
 	}
 
-	// Add memory if available
-	if a.memory != nil {
-		options = append(options, interfaces.WithMemory(a.memory))
+	// Add the tool loop policy if available
+	if a.toolLoopPolicy != nil {
+		options = append(options, interfaces.WithToolLoopPolicy(*a.toolLoopPolicy))
+	}
+
+	// Add memory if available, with the history fitted in the model's
+	// context window
+	memory, err := a.fitContextWindow(ctx, systemPrompt, input, allTools)
+	if err != nil {
+		return 0, err
+	}
+	if memory != nil {
+		options = append(options, interfaces.WithMemory(memory))
+	}
+
+	// Add content parts of the input if available
+	if parts := a.inputParts(ctx); len(parts) > 0 {
+		options = append(options, interfaces.WithContentParts(parts...))
 	}
 
 	// Add stream config if available
@@ -347,14 +455,25 @@ func (a *Agent) runStreamingGeneration(
 	// This is used by the tools package's AgentTool to forward sub-agent events
 	ctxWithForwarder := context.WithValue(ctx, interfaces.StreamForwarderKey, interfaces.StreamForwarder(streamForwarder))
 
+	if err := a.hookLLMCall(ctx, input, allTools, true); err != nil {
+		return 0, err
+	}
+
 	// Start LLM streaming
 	var llmEventChan <-chan interfaces.StreamEvent
-	var err error
 
 	if len(allTools) > 0 {
 		// Record tool invocations as the LLM actually calls them, not the
 		// full set of available tools (#305).
-		toolsForLLM := wrapToolsWithTracker(allTools, getUsageTracker(ctx))
+		tracker := getUsageTracker(ctx)
+		toolsForLLM := a.wrapTools(allTools, tracker)
+
+		// Pick up tools registered or removed while the LLM calls tools
+		currentTools := a.toolsFunc(allTools, registered)
+		options = append(options, interfaces.WithToolsFunc(func() []interfaces.Tool {
+			return a.wrapTools(a.exposeToolsets(ctx, currentTools()), tracker)
+		}))
+
 		llmEventChan, err = streamingLLM.GenerateWithToolsStream(ctxWithForwarder, input, toolsForLLM, options...)
 	} else {
 		llmEventChan, err = streamingLLM.GenerateStream(ctxWithForwarder, input, options...)
@@ -402,6 +521,33 @@ func (a *Agent) runStreamingGeneration(
 		}
 	}
 
+	// Apply the output validators to the streamed response. Its content was
+	// already streamed, so a response they modify or re-generate is sent
+	// again in full, and a rejected one is neither kept in memory nor
+	// completed: the run ends with an error event instead.
+	content := accumulatedContent.String()
+	var rejected error
+	if finalError == nil && len(a.outputValidators) > 0 {
+		validated, err := a.validateOutput(ctx, input, content, options)
+		switch {
+		case err != nil:
+			rejected = err
+			content = ""
+		case validated != content:
+			content = validated
+			if !sendEvent(ctx, eventChan, interfaces.AgentStreamEvent{
+				Type:      interfaces.AgentEventContent,
+				Content:   validated,
+				Timestamp: time.Now(),
+				Metadata: map[string]interface{}{
+					"replaces_streamed_content": true,
+				},
+			}) {
+				return int64(accumulatedContent.Len()), nil
+			}
+		}
+	}
+
 	// Add messages to memory if available (save even on error to preserve conversation history)
 	if a.memory != nil {
 		// If we have tool calls, save them in the correct order
@@ -409,7 +555,7 @@ func (a *Agent) runStreamingGeneration(
 			// Add assistant message with tool calls
 			err := a.memory.AddMessage(ctx, interfaces.Message{
 				Role:      "assistant",
-				Content:   accumulatedContent.String(), // May be empty or contain text before tools
+				Content:   content, // May be empty or contain text before tools
 				ToolCalls: toolCalls,
 			})
 			if err != nil {
@@ -432,11 +578,11 @@ func (a *Agent) runStreamingGeneration(
 					}
 				}
 			}
-		} else if accumulatedContent.Len() > 0 {
+		} else if content != "" {
 			// No tool calls, just content - add assistant message
 			err := a.memory.AddMessage(ctx, interfaces.Message{
 				Role:    "assistant",
-				Content: accumulatedContent.String(),
+				Content: content,
 			})
 			if err != nil {
 				fmt.Printf("Warning: Failed to add assistant response to memory: %v\n", err)
@@ -444,6 +590,10 @@ func (a *Agent) runStreamingGeneration(
 		}
 	}
 
+	if rejected != nil {
+		return int64(accumulatedContent.Len()), rejected
+	}
+
 	// Send completion event
 	sendEvent(ctx, eventChan, interfaces.AgentStreamEvent{
 		Type:      interfaces.AgentEventComplete,
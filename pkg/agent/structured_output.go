@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/structuredoutput"
+)
+
+// WithStructuredOutputRetries sets how many times a response that does not
+// match the agent's response format is sent back to the model with the
+// validation errors (default: 2). Use 0 to only validate. When every attempt
+// is invalid, the run fails with structuredoutput.ErrInvalidOutput.
+func WithStructuredOutputRetries(maxRetries int) Option {
+	return func(a *Agent) {
+		a.structuredRetries = maxRetries
+	}
+}
+
+// repairStructuredOutput validates a response against the agent's response
+// format and re-prompts the model until it matches. Valid responses are
+// returned as plain JSON, without markdown fences or surrounding text.
+func (a *Agent) repairStructuredOutput(ctx context.Context, prompt, response string, generateOptions []interfaces.GenerateOption) (string, error) {
+	// Repairs must not be recorded as conversation turns
+	options := append(generateOptions[:len(generateOptions):len(generateOptions)], interfaces.WithMemory(nil))
+
+	repaired, err := structuredoutput.Repair(ctx, a.llm, prompt, response, a.responseFormat, a.structuredRetries, options...)
+	if err != nil {
+		a.logger.Warn(ctx, "Structured output does not match the response format", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return "", err
+	}
+	return repaired, nil
+}
+
+// finalizeStructuredOutput runs a final constrained generation without tools
+// when the answer of a tool-calling run does not match the agent's response
+// format. Providers enforce the format natively in this pass (JSON schema
+// response formats, or a forced result tool for Anthropic), which is not
+// possible while the model is still free to call tools.
+func (a *Agent) finalizeStructuredOutput(ctx context.Context, prompt, response string, generateOptions []interfaces.GenerateOption) (string, error) {
+	if structuredoutput.Validate(a.responseFormat.Schema, []byte(structuredoutput.ExtractJSON(response))) == nil {
+		return response, nil
+	}
+
+	a.logger.Debug(ctx, "Tool-calling answer does not match the response format, running a final structured pass", nil)
+
+	// The final pass must not be recorded as a conversation turn
+	options := append(generateOptions[:len(generateOptions):len(generateOptions)], interfaces.WithMemory(nil))
+	finalPrompt := structuredPassPrompt(prompt, response)
+
+	tracker := getUsageTracker(ctx)
+	if tracker != nil && tracker.detailed {
+		llmResp, err := a.llm.GenerateDetailed(ctx, finalPrompt, options...)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate structured response: %w", err)
+		}
+		tracker.addLLMUsage(llmResp.Usage, llmResp.Model)
+		return llmResp.Content, nil
+	}
+
+	structured, err := a.llm.Generate(ctx, finalPrompt, options...)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate structured response: %w", err)
+	}
+	return structured, nil
+}
+
+// structuredPassPrompt asks the model to restate the answer of a
+// tool-calling run in the required format
+func structuredPassPrompt(prompt, response string) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\nYou already worked on this request with tools and answered:\n")
+	b.WriteString(response)
+	b.WriteString("\n\nRespond with this answer as JSON matching the required schema. Do not leave out any information it contains.")
+	return b.String()
+}
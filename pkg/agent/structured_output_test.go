@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/structuredoutput"
+)
+
+type weatherReport struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+func TestStructuredOutputRepair(t *testing.T) {
+	var prompts []string
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			prompts = append(prompts, prompt)
+			if len(prompts) == 1 {
+				return "```json\n{\"city\": \"Paris\", \"temperature\": \"warm\"}\n```", nil
+			}
+			return "Here you go: {\"city\": \"Paris\", \"temperature\": 21.5}", nil
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithRequirePlanApproval(false),
+		WithResponseFormat(*structuredoutput.NewResponseFormat(weatherReport{})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	response, err := agent.Run(context.Background(), "weather in Paris")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response != `{"city": "Paris", "temperature": 21.5}` {
+		t.Errorf("expected the repaired JSON only, got %q", response)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], "$.temperature: expected number, got string") {
+		t.Errorf("expected one repair prompt with the validation error, got %q", prompts)
+	}
+}
+
+func TestStructuredOutputRetriesExhausted(t *testing.T) {
+	calls := 0
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			calls++
+			return `{"city": "Paris"}`, nil
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithRequirePlanApproval(false),
+		WithResponseFormat(*structuredoutput.NewResponseFormat(weatherReport{})),
+		WithStructuredOutputRetries(1),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	_, err = agent.Run(context.Background(), "weather in Paris")
+	if !errors.Is(err, structuredoutput.ErrInvalidOutput) {
+		t.Fatalf("expected ErrInvalidOutput, got %v", err)
+	}
+	var validationErr *structuredoutput.ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 {
+		t.Errorf("expected the validation errors to be wrapped, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the original call and one repair, got %d calls", calls)
+	}
+}
+
+func TestStructuredOutputFinalPassWithTools(t *testing.T) {
+	var prompts []string
+	var finalFormat *interfaces.ResponseFormat
+	llm := &mockLLM{
+		generateFunc: func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+			prompts = append(prompts, prompt)
+			if len(prompts) == 1 {
+				return "It is 21.5 degrees in Paris.", nil
+			}
+			params := &interfaces.GenerateOptions{}
+			for _, option := range options {
+				option(params)
+			}
+			finalFormat = params.ResponseFormat
+			return `{"city": "Paris", "temperature": 21.5}`, nil
+		},
+	}
+
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithRequirePlanApproval(false),
+		WithTools(&mockTool{name: "weather", description: "Looks up the weather"}),
+		WithResponseFormat(*structuredoutput.NewResponseFormat(weatherReport{})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	response, err := agent.Run(context.Background(), "weather in Paris")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response != `{"city": "Paris", "temperature": 21.5}` {
+		t.Errorf("expected the structured answer, got %q", response)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], "It is 21.5 degrees in Paris.") {
+		t.Fatalf("expected a final pass restating the tool-calling answer, got %q", prompts)
+	}
+	if finalFormat == nil || finalFormat.Name != "weatherReport" {
+		t.Errorf("expected the final pass to request the response format, got %+v", finalFormat)
+	}
+}
@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// ErrModelNotAllowed is returned when the organization of a run may not use
+// the agent's model
+var ErrModelNotAllowed = errors.New("model not allowed for organization")
+
+// tenantConfigKey is the context key for the tenant configuration of a run
+type tenantConfigKey struct{}
+
+// WithTenantConfigs resolves the configuration of the run's organization
+// from configs at the start of each run. Runs of organizations with a
+// configuration fail with ErrModelNotAllowed when the agent's model is not
+// one of their allowed models, and with multitenancy.ErrBudgetExceeded when
+// they have used their token budget. Otherwise their temperature is capped,
+// only their allowed tools are exposed, their conversation IDs get their
+// memory prefix, and their token usage is counted against their budget.
+// Organizations without a configuration run unchanged.
+func WithTenantConfigs(configs *multitenancy.ConfigManager) Option {
+	return func(a *Agent) {
+		a.tenantConfigs = configs
+	}
+}
+
+// tenantConfigFromContext returns the tenant configuration resolved for the run
+func tenantConfigFromContext(ctx context.Context) *multitenancy.TenantConfig {
+	config, _ := ctx.Value(tenantConfigKey{}).(*multitenancy.TenantConfig)
+	return config
+}
+
+// applyTenantConfig resolves the configuration of the run's organization,
+// checks its model and budget, and returns a context carrying it
+func (a *Agent) applyTenantConfig(ctx context.Context) (context.Context, error) {
+	if a.tenantConfigs == nil {
+		return ctx, nil
+	}
+	config, err := a.tenantConfigs.GetTenantConfig(ctx)
+	if err != nil {
+		return ctx, nil
+	}
+
+	if model := a.modelName(); model != "" && !config.AllowsModel(model) {
+		return ctx, fmt.Errorf("%w: organization %s may not use %s", ErrModelNotAllowed, config.OrgID, model)
+	}
+	if err := a.tenantConfigs.CheckBudget(ctx); err != nil {
+		return ctx, err
+	}
+
+	if config.MemoryPrefix != "" {
+		if conversationID, ok := memory.GetConversationID(ctx); ok {
+			ctx = memory.WithConversationID(ctx, config.MemoryPrefix+conversationID)
+		}
+	}
+	return context.WithValue(ctx, tenantConfigKey{}, config), nil
+}
+
+// chargeTenant counts the token usage of the run against the budget of its
+// organization
+func (a *Agent) chargeTenant(ctx context.Context) {
+	tracker := getUsageTracker(ctx)
+	if tenantConfigFromContext(ctx) == nil || tracker == nil {
+		return
+	}
+	usage, _, _ := tracker.getResults()
+	if usage == nil {
+		return
+	}
+	if err := a.tenantConfigs.RecordUsage(ctx, int64(usage.TotalTokens)); err != nil {
+		a.logger.Warn(ctx, "Failed to record tenant token usage", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// allowTenantTools removes the tools the organization of the run may not use
+func allowTenantTools(ctx context.Context, tools []interfaces.Tool) []interfaces.Tool {
+	config := tenantConfigFromContext(ctx)
+	if config == nil || len(config.AllowedTools) == 0 {
+		return tools
+	}
+
+	allowed := make([]interfaces.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if config.AllowsTool(tool.Name()) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// defaultTemperature is the temperature of the LLM providers when none is set
+const defaultTemperature = 0.7
+
+// capTenantTemperature caps the temperature of the LLM calls of the run at
+// the maximum of its organization
+func capTenantTemperature(ctx context.Context) interfaces.GenerateOption {
+	config := tenantConfigFromContext(ctx)
+	return func(options *interfaces.GenerateOptions) {
+		if config == nil || config.MaxTemperature == nil {
+			return
+		}
+		llmConfig := interfaces.LLMConfig{Temperature: defaultTemperature}
+		if options.LLMConfig != nil {
+			llmConfig = *options.LLMConfig
+		}
+		llmConfig.Temperature = config.CapTemperature(llmConfig.Temperature)
+		options.LLMConfig = &llmConfig
+	}
+}
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestTenantConfigs(t *testing.T) {
+	maxTemperature := 0.2
+	configs := multitenancy.NewConfigManager()
+	for _, config := range []*multitenancy.TenantConfig{
+		{
+			OrgID:          "acme",
+			AllowedModels:  []string{"small-model"},
+			AllowedTools:   []string{"search"},
+			MaxTemperature: &maxTemperature,
+			MemoryPrefix:   "acme/",
+			TokenBudget:    100,
+		},
+		{OrgID: "globex", AllowedModels: []string{"large-model"}},
+	} {
+		if err := configs.RegisterTenant(config); err != nil {
+			t.Fatalf("RegisterTenant: %v", err)
+		}
+	}
+
+	llm := mock.New(mock.WithModel("small-model"), mock.WithUsage(interfaces.TokenUsage{InputTokens: 50, OutputTokens: 10, TotalTokens: 60}))
+	llm.On(mock.Any()).Respond("done")
+	mem := memory.NewConversationBuffer()
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithMemory(mem),
+		WithTools(
+			&mockTool{name: "search", description: "Search the web"},
+			&mockTool{name: "refund", description: "Refund a payment"},
+		),
+		WithLLMConfig(interfaces.LLMConfig{Temperature: 0.9}),
+		WithTenantConfigs(configs),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	ctx := multitenancy.WithOrgID(context.Background(), "acme")
+	ctx = memory.WithConversationID(ctx, "conversation")
+	if _, err := agent.Run(ctx, "Help me"); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	request := llm.Calls()[0].Request
+	if fmt.Sprint(request.Tools) != "[search]" {
+		t.Errorf("Expected only the allowed tools, got %v", request.Tools)
+	}
+	if temperature := request.Options.LLMConfig.Temperature; temperature != maxTemperature {
+		t.Errorf("Expected temperature capped at %v, got %v", maxTemperature, temperature)
+	}
+	if messages, _ := mem.GetMessages(memory.WithConversationID(ctx, "acme/conversation")); len(messages) == 0 {
+		t.Error("Expected the conversation to be stored under the memory prefix")
+	}
+
+	// The first run used 60 of 100 tokens, the second exhausts the budget
+	if _, err := agent.Run(ctx, "Help me again"); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if _, err := agent.Run(ctx, "One more"); !errors.Is(err, multitenancy.ErrBudgetExceeded) {
+		t.Errorf("Expected ErrBudgetExceeded, got %v", err)
+	}
+
+	globex := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "globex"), "conversation")
+	if _, err := agent.Run(globex, "Help me"); !errors.Is(err, ErrModelNotAllowed) {
+		t.Errorf("Expected ErrModelNotAllowed, got %v", err)
+	}
+
+	// Organizations without a configuration run unchanged
+	initech := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "initech"), "conversation")
+	if _, err := agent.Run(initech, "Help me"); err != nil {
+		t.Errorf("Expected runs of other organizations to succeed, got %v", err)
+	}
+}
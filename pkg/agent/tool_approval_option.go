@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ToolApprovalRequest describes a tool call waiting for approval
+type ToolApprovalRequest struct {
+	// ID identifies the request in the approver's answer
+	ID string `json:"id"`
+
+	// ToolName is the name of the tool to call
+	ToolName string `json:"tool_name"`
+
+	// Arguments are the arguments of the call
+	Arguments string `json:"arguments"`
+}
+
+// ToolApprover decides whether a tool call may run. It blocks until the call
+// is approved or denied; a denial reason is returned to the model.
+type ToolApprover func(ctx context.Context, req ToolApprovalRequest) (approved bool, reason string, err error)
+
+type toolApproverKey struct{}
+
+// WithToolApprover returns a context whose runs ask approver before calling
+// the tools that require approval
+func WithToolApprover(ctx context.Context, approver ToolApprover) context.Context {
+	return context.WithValue(ctx, toolApproverKey{}, approver)
+}
+
+// WithToolApproval requires approval before the named tools are called. Runs
+// ask the ToolApprover of their context, such as the one of a WebSocket
+// session whose client approves calls; without an approver, the calls are
+// denied.
+//
+//	agent.WithToolApproval("delete_records", "send_email")
+func WithToolApproval(toolNames ...string) Option {
+	return func(a *Agent) {
+		if a.approvalTools == nil {
+			a.approvalTools = make(map[string]bool, len(toolNames))
+		}
+		for _, name := range toolNames {
+			a.approvalTools[name] = true
+		}
+	}
+}
+
+// wrapToolsWithApproval wraps the tools requiring approval so their calls wait
+// for the approver. Returns the original slice unchanged when no tool requires approval.
+func (a *Agent) wrapToolsWithApproval(tools []interfaces.Tool) []interfaces.Tool {
+	if len(a.approvalTools) == 0 || len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, t := range tools {
+		if a.approvalTools[t.Name()] {
+			wrapped[i] = &approvalTool{inner: t}
+		} else {
+			wrapped[i] = t
+		}
+	}
+	return wrapped
+}
+
+// approvalTool asks the approver of the context before running a tool
+type approvalTool struct {
+	inner interfaces.Tool
+}
+
+func (t *approvalTool) Name() string        { return t.inner.Name() }
+func (t *approvalTool) Description() string { return t.inner.Description() }
+func (t *approvalTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.inner.Parameters()
+}
+
+func (t *approvalTool) Run(ctx context.Context, input string) (string, error) {
+	if err := t.approve(ctx, input); err != nil {
+		return "", err
+	}
+	return t.inner.Run(ctx, input)
+}
+
+func (t *approvalTool) Execute(ctx context.Context, args string) (string, error) {
+	if err := t.approve(ctx, args); err != nil {
+		return "", err
+	}
+	return t.inner.Execute(ctx, args)
+}
+
+// approve returns an error unless the approver of the context approves the call
+func (t *approvalTool) approve(ctx context.Context, args string) error {
+	approver, ok := ctx.Value(toolApproverKey{}).(ToolApprover)
+	if !ok || approver == nil {
+		return fmt.Errorf("tool %s requires approval, which this client cannot give", t.inner.Name())
+	}
+
+	approved, reason, err := approver(ctx, ToolApprovalRequest{
+		ID:        uuid.New().String(),
+		ToolName:  t.inner.Name(),
+		Arguments: args,
+	})
+	if err != nil {
+		return fmt.Errorf("tool %s approval failed: %w", t.inner.Name(), err)
+	}
+	if !approved {
+		if reason == "" {
+			return fmt.Errorf("tool %s call was denied by the user", t.inner.Name())
+		}
+		return fmt.Errorf("tool %s call was denied by the user: %s", t.inner.Name(), reason)
+	}
+	return nil
+}
+
+// DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
+func (t *approvalTool) DisplayName() string {
+	if d, ok := t.inner.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.inner.Name()
+}
+
+// Internal forwards to the inner tool when it implements InternalTool.
+func (t *approvalTool) Internal() bool {
+	if i, ok := t.inner.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
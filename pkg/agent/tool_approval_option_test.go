@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/executionplan"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+)
+
+func TestToolApproval(t *testing.T) {
+	tests := []struct {
+		name       string
+		approver   ToolApprover
+		wantRun    bool
+		wantErrSub string
+	}{
+		{
+			name: "approved",
+			approver: func(ctx context.Context, req ToolApprovalRequest) (bool, string, error) {
+				return true, "", nil
+			},
+			wantRun: true,
+		},
+		{
+			name: "denied",
+			approver: func(ctx context.Context, req ToolApprovalRequest) (bool, string, error) {
+				return false, "not today", nil
+			},
+			wantErrSub: "denied by the user: not today",
+		},
+		{
+			name:       "no approver",
+			wantErrSub: "requires approval",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := mock.New()
+			llm.On(mock.WithTool("delete_records")).
+				CallTool("delete_records", `{"input":"all"}`).
+				CallTool("lookup", `{"input":"x"}`).
+				Respond("done")
+
+			ran := false
+			var requests []ToolApprovalRequest
+			agent, err := NewAgent(
+				WithLLM(llm),
+				WithTools(
+					&mockTool{name: "delete_records", runFunc: func(ctx context.Context, input string) (string, error) {
+						ran = true
+						return "deleted", nil
+					}},
+					&mockTool{name: "lookup"},
+				),
+				WithToolApproval("delete_records"),
+				WithRequirePlanApproval(false),
+			)
+			if err != nil {
+				t.Fatalf("failed to create agent: %v", err)
+			}
+
+			ctx := context.Background()
+			if tt.approver != nil {
+				ctx = WithToolApprover(ctx, func(ctx context.Context, req ToolApprovalRequest) (bool, string, error) {
+					requests = append(requests, req)
+					return tt.approver(ctx, req)
+				})
+			}
+			if _, err := agent.Run(ctx, "clean up"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ran != tt.wantRun {
+				t.Errorf("expected the tool to run: %v, ran: %v", tt.wantRun, ran)
+			}
+			if tt.approver != nil && (len(requests) != 1 || requests[0].ToolName != "delete_records" || requests[0].Arguments != `{"input":"all"}` || requests[0].ID == "") {
+				t.Errorf("expected 1 approval request for delete_records, got %+v", requests)
+			}
+
+			results := llm.Calls()[0].ToolResults
+			if len(results) != 2 || results[1].Error != nil {
+				t.Fatalf("expected lookup to run without approval, got %+v", results)
+			}
+			if tt.wantErrSub != "" && (results[0].Error == nil || !strings.Contains(results[0].Error.Error(), tt.wantErrSub)) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErrSub, results[0].Error)
+			}
+		})
+	}
+}
+
+func TestToolApprovalRealtime(t *testing.T) {
+	session := &fakeRealtimeSession{
+		events:  make(chan interfaces.RealtimeEvent, 2),
+		results: make(chan interfaces.ToolResult, 1),
+	}
+	ran := false
+	agent, err := NewAgent(
+		WithLLM(mock.New()),
+		WithTools(&mockTool{name: "delete_records", runFunc: func(ctx context.Context, input string) (string, error) {
+			ran = true
+			return "deleted", nil
+		}}),
+		WithToolApproval("delete_records"),
+		WithRealtime(&fakeRealtimeProvider{session: session}, interfaces.RealtimeConfig{}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	realtime, err := agent.StartRealtime(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	session.events <- interfaces.RealtimeEvent{Type: interfaces.RealtimeEventToolCall, ToolCall: &interfaces.ToolCall{ID: "call-1", Name: "delete_records", Arguments: `{"input":"all"}`}}
+	close(session.events)
+	for range realtime.Events() {
+	}
+
+	result := <-session.results
+	if ran || !result.IsError || !strings.Contains(result.Content, "requires approval") {
+		t.Errorf("expected the unapproved call to be denied, ran: %v, result: %+v", ran, result)
+	}
+}
+
+func TestToolApprovalPlan(t *testing.T) {
+	ran := false
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{name: "mock"}),
+		WithTools(&mockTool{name: "delete_records", runFunc: func(ctx context.Context, input string) (string, error) {
+			ran = true
+			return "deleted", nil
+		}}),
+		WithToolApproval("delete_records"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	plan := executionplan.NewExecutionPlan("Clean up", []executionplan.ExecutionStep{
+		{ToolName: "delete_records", Description: "Delete all records", Parameters: map[string]interface{}{"input": "all"}},
+	})
+	ctx := WithToolApprover(context.Background(), func(ctx context.Context, req ToolApprovalRequest) (bool, string, error) {
+		return false, "not today", nil
+	})
+	_, err = agent.ApproveExecutionPlan(ctx, plan)
+	if ran || err == nil || !strings.Contains(err.Error(), "denied by the user: not today") {
+		t.Errorf("expected the plan step to be denied, ran: %v, err: %v", ran, err)
+	}
+}
@@ -0,0 +1,35 @@
+package agent
+
+import (
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/toolcache"
+)
+
+// WithToolCache caches the results of the tools named in the cache, so
+// repeated calls with the same arguments within the cache's TTL do not run
+// them again. Handoff tools are never cached, as a cached result would skip
+// the handoff.
+//
+//	agent.WithToolCache(toolcache.New(toolcache.WithTools("get_stock_price"), toolcache.WithTTL(time.Minute)))
+func WithToolCache(cache *toolcache.Cache) Option {
+	return func(a *Agent) {
+		a.toolCache = cache
+	}
+}
+
+// wrapToolsWithCache wraps the tools whose results are cached. Returns the
+// original slice unchanged when no cache is configured.
+func (a *Agent) wrapToolsWithCache(tools []interfaces.Tool) []interfaces.Tool {
+	if a.toolCache == nil || len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, t := range tools {
+		if _, ok := t.(*handoffTool); ok {
+			wrapped[i] = t
+			continue
+		}
+		wrapped[i] = a.toolCache.Wrap(t)
+	}
+	return wrapped
+}
@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/toolcache"
+)
+
+func TestToolCache(t *testing.T) {
+	calls := 0
+	tool := &mockTool{name: "get_stock_price", description: "Get a stock price", runFunc: func(ctx context.Context, input string) (string, error) {
+		calls++
+		return "189.5", nil
+	}}
+
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{name: "mock"}),
+		WithTools(tool),
+		WithToolCache(toolcache.New(toolcache.WithTools("get_stock_price"))),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	wrapped := agent.wrapTools([]interfaces.Tool{tool}, nil)
+	for i := 0; i < 3; i++ {
+		if result, err := wrapped[0].Execute(context.Background(), `{"input": "AAPL"}`); err != nil || result != "189.5" {
+			t.Fatalf("Unexpected result %q, %v", result, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the tool to run once, got %d calls", calls)
+	}
+}
+
+func TestToolCache_Handoff(t *testing.T) {
+	cache := toolcache.New(toolcache.WithTools("transfer_to_billing"))
+	triage, _ := newHandoffAgents(t, WithToolCache(cache))
+
+	// The same handoff in two conversations must transfer both times
+	for _, conversationID := range []string{"conversation-1", "conversation-2"} {
+		ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), "org-1"), conversationID)
+		response, err := triage.RunDetailed(ctx, "Please refund order 42")
+		if err != nil {
+			t.Fatalf("RunDetailed() error = %v", err)
+		}
+		if response.Metadata[MetadataHandoffTo] != "Billing" {
+			t.Errorf("Expected the handoff in %s, got %v", conversationID, response.Metadata)
+		}
+	}
+}
+
+func TestToolCache_OnlyListedTools(t *testing.T) {
+	calls := 0
+	tool := &mockTool{name: "send_email", description: "Send an email", runFunc: func(ctx context.Context, input string) (string, error) {
+		calls++
+		return "sent", nil
+	}}
+
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{name: "mock"}),
+		WithTools(tool),
+		WithToolCache(toolcache.New()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	wrapped := agent.wrapTools([]interfaces.Tool{tool}, nil)
+	for i := 0; i < 2; i++ {
+		if _, err := wrapped[0].Execute(context.Background(), `{"to": "a@example.com"}`); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected tools not named in the cache to run every time, got %d calls", calls)
+	}
+}
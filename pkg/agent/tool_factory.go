@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/grpc/client"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/tools/calculator"
 )
@@ -85,7 +86,7 @@ func (tf *ToolFactory) createCustomTool(config ToolConfigYAML) (interfaces.Tool,
 
 // createAgentToolWithParentConfig creates a tool that wraps a remote agent with parent config inheritance
 func (tf *ToolFactory) createAgentToolWithParentConfig(config ToolConfigYAML, parentConfig *AgentConfig) (interfaces.Tool, error) {
-	if config.URL == "" {
+	if config.URL == "" && len(config.URLs) == 0 {
 		return nil, fmt.Errorf("agent tool requires URL")
 	}
 
@@ -103,6 +104,9 @@ func (tf *ToolFactory) createAgentToolWithParentConfig(config ToolConfigYAML, pa
 		WithName(config.Name),
 		WithDescription(config.Description),
 	}
+	if len(config.URLs) > 0 {
+		options = append(options, WithURLs(config.URLs...), WithLoadBalancing(client.LoadBalancingStrategy(config.LoadBalancing)))
+	}
 
 	// Inherit plan approval setting from parent if available
 	if parentConfig != nil && parentConfig.RequirePlanApproval != nil {
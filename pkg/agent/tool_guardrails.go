@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// wrapToolsWithGuardrails wraps each tool so its arguments are checked by the
+// agent's guardrails. Returns the original slice unchanged when the
+// guardrails do not implement interfaces.ToolCallGuardrails.
+func (a *Agent) wrapToolsWithGuardrails(tools []interfaces.Tool) []interfaces.Tool {
+	guard, ok := a.guardrails.(interfaces.ToolCallGuardrails)
+	if !ok || len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = &guardedTool{inner: t, guard: guard}
+	}
+	return wrapped
+}
+
+// guardedTool checks the arguments of a tool call before running the tool
+type guardedTool struct {
+	inner interfaces.Tool
+	guard interfaces.ToolCallGuardrails
+}
+
+func (t *guardedTool) Name() string        { return t.inner.Name() }
+func (t *guardedTool) Description() string { return t.inner.Description() }
+func (t *guardedTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.inner.Parameters()
+}
+
+func (t *guardedTool) Run(ctx context.Context, input string) (string, error) {
+	input, err := t.guard.ProcessToolCall(ctx, t.inner.Name(), input)
+	if err != nil {
+		return "", err
+	}
+	return t.inner.Run(ctx, input)
+}
+
+func (t *guardedTool) Execute(ctx context.Context, args string) (string, error) {
+	args, err := t.guard.ProcessToolCall(ctx, t.inner.Name(), args)
+	if err != nil {
+		return "", err
+	}
+	return t.inner.Execute(ctx, args)
+}
+
+// DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
+func (t *guardedTool) DisplayName() string {
+	if d, ok := t.inner.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.inner.Name()
+}
+
+// Internal forwards to the inner tool when it implements InternalTool.
+func (t *guardedTool) Internal() bool {
+	if i, ok := t.inner.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/guardrails"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestGuardrailsCheckToolCalls(t *testing.T) {
+	var ran bool
+	tool := &mockTool{
+		name: "shell",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			ran = true
+			return "done", nil
+		},
+	}
+
+	engine, err := guardrails.ParseRules([]byte(`
+rules:
+  - name: no_deletes
+    stages: [tool_call]
+    match:
+      regex: ['rm\s+-rf']
+    action: deny
+`))
+	if err != nil {
+		t.Fatalf("failed to parse rules: %v", err)
+	}
+
+	agent, err := NewAgent(WithLLM(&mockLLM{}), WithGuardrails(engine))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	wrapped := agent.wrapToolsWithGuardrails([]interfaces.Tool{tool})
+	var violation *guardrails.RuleViolationError
+	if _, err := wrapped[0].Run(context.Background(), `{"command":"rm -rf /"}`); !errors.As(err, &violation) {
+		t.Errorf("expected a rule violation, got %v", err)
+	}
+	if ran {
+		t.Error("expected the tool not to run")
+	}
+	if output, err := wrapped[0].Run(context.Background(), `{"command":"ls"}`); err != nil || output != "done" {
+		t.Errorf("expected the tool to run, got %q, %v", output, err)
+	}
+}
@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tokens"
+)
+
+// ToolOutputPolicy is how tool results over the limit set with
+// WithToolOutputLimit are shortened
+type ToolOutputPolicy string
+
+const (
+	// ToolOutputTruncate keeps the beginning of the result
+	ToolOutputTruncate ToolOutputPolicy = "truncate"
+
+	// ToolOutputSummarize replaces the result with a summary written by the
+	// agent's LLM, and truncates it when the summary fails
+	ToolOutputSummarize ToolOutputPolicy = "summarize"
+)
+
+// maxSummarizedToolOutput bounds the tokens of a tool result sent to the LLM
+// for summarization; the rest is truncated first
+const maxSummarizedToolOutput = 50000
+
+// WithToolOutputLimit limits tool results to maxTokens tokens before they are
+// added to the conversation, so that a single verbose tool, such as one
+// returning a large SQL result, cannot fill the context window. Longer
+// results are shortened with the policy.
+func WithToolOutputLimit(maxTokens int, policy ToolOutputPolicy) Option {
+	return func(a *Agent) {
+		a.toolOutputLimit = maxTokens
+		a.toolOutputPolicy = policy
+	}
+}
+
+// wrapToolsWithOutputLimit wraps each tool so its results are limited.
+// Returns the original slice unchanged when no limit is set.
+func (a *Agent) wrapToolsWithOutputLimit(tools []interfaces.Tool) []interfaces.Tool {
+	if a.toolOutputLimit <= 0 || len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = &limitedTool{inner: t, agent: a}
+	}
+	return wrapped
+}
+
+// limitToolOutput shortens a tool result over the limit with the policy
+func (a *Agent) limitToolOutput(ctx context.Context, toolName, args, result string) string {
+	model := a.modelName()
+	count, err := tokens.CountText(model, result)
+	if err != nil || count <= a.toolOutputLimit {
+		return result
+	}
+
+	if a.toolOutputPolicy == ToolOutputSummarize {
+		summary, err := a.summarizeToolOutput(ctx, model, toolName, args, result)
+		if err == nil {
+			return summary
+		}
+		a.logger.Warn(ctx, "Failed to summarize tool output, truncating it", map[string]interface{}{
+			"tool":  toolName,
+			"error": err.Error(),
+		})
+	}
+
+	note := fmt.Sprintf("\n[Output truncated: the tool returned %d tokens]", count)
+	noteTokens, _ := tokens.CountText(model, note)
+	truncated, err := tokens.TruncateText(model, result, a.toolOutputLimit-noteTokens)
+	if err != nil {
+		return result
+	}
+	return truncated + note
+}
+
+// summarizeToolOutput summarizes a tool result with the agent's LLM
+func (a *Agent) summarizeToolOutput(ctx context.Context, model, toolName, args, result string) (string, error) {
+	output, err := tokens.TruncateText(model, result, maxSummarizedToolOutput)
+	if err != nil {
+		return "", err
+	}
+
+	prompt := fmt.Sprintf(`The tool %s was called with the arguments %s and returned the output below, which is too long to pass on.
+Summarize it in at most %d words. Keep every fact, number, name and identifier needed to answer the request the tool was called for; for tabular data, give the columns, the number of rows and the most relevant rows.
+
+Output:
+%s`, toolName, args, a.toolOutputLimit*3/4, output)
+
+	summary, err := a.llm.Generate(ctx, prompt,
+		interfaces.WithSystemMessage("You summarize tool outputs for an AI assistant that will use them."),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize tool output: %w", err)
+	}
+	summary, err = tokens.TruncateText(model, "Summary of the tool output:\n"+summary, a.toolOutputLimit)
+	if err != nil {
+		return "", err
+	}
+	return summary, nil
+}
+
+// modelName returns the model of the agent's LLM, when it reports one
+func (a *Agent) modelName() string {
+	if modelProvider, ok := a.llm.(interface{ GetModel() string }); ok {
+		return modelProvider.GetModel()
+	}
+	return ""
+}
+
+// limitedTool limits the results of a tool
+type limitedTool struct {
+	inner interfaces.Tool
+	agent *Agent
+}
+
+func (t *limitedTool) Name() string        { return t.inner.Name() }
+func (t *limitedTool) Description() string { return t.inner.Description() }
+func (t *limitedTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.inner.Parameters()
+}
+
+func (t *limitedTool) Run(ctx context.Context, input string) (string, error) {
+	result, err := t.inner.Run(ctx, input)
+	if err != nil {
+		return result, err
+	}
+	return t.agent.limitToolOutput(ctx, t.inner.Name(), input, result), nil
+}
+
+func (t *limitedTool) Execute(ctx context.Context, args string) (string, error) {
+	result, err := t.inner.Execute(ctx, args)
+	if err != nil {
+		return result, err
+	}
+	return t.agent.limitToolOutput(ctx, t.inner.Name(), args, result), nil
+}
+
+// DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
+func (t *limitedTool) DisplayName() string {
+	if d, ok := t.inner.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.inner.Name()
+}
+
+// Internal forwards to the inner tool when it implements InternalTool.
+func (t *limitedTool) Internal() bool {
+	if i, ok := t.inner.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tokens"
+)
+
+func TestToolOutputLimit(t *testing.T) {
+	rows := strings.Repeat("order 1234 shipped to Paris on 2026-01-02\n", 200)
+	tool := &mockTool{name: "sql", description: "Run SQL", runFunc: func(ctx context.Context, input string) (string, error) {
+		if input == `{"input": "short"}` {
+			return "3 rows", nil
+		}
+		return rows, nil
+	}}
+
+	var prompts []string
+	summarize := func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+		prompts = append(prompts, prompt)
+		return "200 orders shipped to Paris", nil
+	}
+	failing := func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+		return "", fmt.Errorf("rate limited")
+	}
+
+	tests := []struct {
+		name     string
+		policy   ToolOutputPolicy
+		generate func(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error)
+		contains string
+	}{
+		{"truncate", ToolOutputTruncate, summarize, "[Output truncated"},
+		{"summarize", ToolOutputSummarize, summarize, "200 orders shipped to Paris"},
+		{"summarize failure", ToolOutputSummarize, failing, "[Output truncated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompts = nil
+			agent, err := NewAgent(
+				WithLLM(&mockLLM{name: "mock", generateFunc: tt.generate}),
+				WithTools(tool),
+				WithToolOutputLimit(100, tt.policy),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create agent: %v", err)
+			}
+			wrapped := agent.wrapTools([]interfaces.Tool{tool}, nil)
+
+			result, err := wrapped[0].Execute(context.Background(), `{"input": "SELECT * FROM orders"}`)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if !strings.Contains(result, tt.contains) {
+				t.Errorf("Expected %q in %q", tt.contains, result)
+			}
+			if count, _ := tokens.CountText("", result); count > 100 {
+				t.Errorf("Expected at most 100 tokens, got %d", count)
+			}
+			if tt.name == "summarize" && (len(prompts) != 1 || !strings.Contains(prompts[0], "SELECT * FROM orders")) {
+				t.Errorf("Expected a summary prompt with the call, got %v", prompts)
+			}
+
+			if result, _ := wrapped[0].Execute(context.Background(), `{"input": "short"}`); result != "3 rows" {
+				t.Errorf("Expected short results unchanged, got %q", result)
+			}
+		})
+	}
+}
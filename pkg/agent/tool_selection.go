@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/toolselect"
+)
+
+// WithToolSelector enables dynamic tool selection. On each turn only the tools
+// most relevant to the user input (plus pinned tools) are sent to the model,
+// which keeps prompt size bounded for agents with many tools.
+func WithToolSelector(selector *toolselect.Selector) Option {
+	return func(a *Agent) {
+		a.toolSelector = selector
+	}
+}
+
+// GetToolSelector returns the tool selector, if configured
+func (a *Agent) GetToolSelector() *toolselect.Selector {
+	return a.toolSelector
+}
+
+// selectTools narrows tools to those relevant to the input. Selection errors
+// are logged and all tools are returned so the run can proceed.
+func (a *Agent) selectTools(ctx context.Context, input string, tools []interfaces.Tool) []interfaces.Tool {
+	if a.toolSelector == nil || len(tools) == 0 {
+		return tools
+	}
+
+	selected, err := a.toolSelector.Select(ctx, input, tools)
+	if err != nil {
+		a.logger.Warn(ctx, "Tool selection failed, sending all tools", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return tools
+	}
+
+	a.logger.Debug(ctx, "Selected tools for turn", map[string]interface{}{
+		"available": len(tools),
+		"selected":  len(selected),
+	})
+	return selected
+}
@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/toolargs"
+)
+
+// WithToolArgumentValidation validates the arguments of tool calls against
+// the tools' ParameterSpecs before the tools run, filling in defaults.
+// Invalid calls are not run; the problems are returned to the model as the
+// result of the call, so it can correct the call.
+func WithToolArgumentValidation() Option {
+	return func(a *Agent) {
+		a.validateToolArgs = true
+	}
+}
+
+// wrapToolsWithValidation wraps each tool so the arguments of its calls are
+// validated. Returns the original slice unchanged when validation is off.
+func (a *Agent) wrapToolsWithValidation(tools []interfaces.Tool) []interfaces.Tool {
+	if !a.validateToolArgs || len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = toolargs.Wrap(t)
+	}
+	return wrapped
+}
@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestToolArgumentValidation(t *testing.T) {
+	calls := 0
+	tool := &mockTool{name: "search", description: "Search the web", runFunc: func(ctx context.Context, input string) (string, error) {
+		calls++
+		return "results", nil
+	}}
+
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{name: "mock"}),
+		WithTools(tool),
+		WithToolArgumentValidation(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	wrapped := agent.wrapTools([]interfaces.Tool{tool}, nil)
+	if _, err := wrapped[0].Execute(context.Background(), `{"query": "go"}`); err == nil || !strings.Contains(err.Error(), "input: is required") {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected the invalid call not to run, got %d calls", calls)
+	}
+
+	if result, err := wrapped[0].Execute(context.Background(), `{"input": "go"}`); err != nil || result != "results" {
+		t.Errorf("Unexpected result %q, %v", result, err)
+	}
+}
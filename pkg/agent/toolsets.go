@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// toolsetsKey is the context key for the toolsets exposed to agent runs
+type toolsetsKey struct{}
+
+// WithToolset registers tools with the agent as the named toolset. Calls with
+// the same name add to the toolset, and a tool can be in several toolsets.
+// Runs expose every tool unless toolsets are selected with
+// ContextWithToolsets.
+func WithToolset(name string, tools ...interfaces.Tool) Option {
+	return func(a *Agent) {
+		if a.toolsets == nil {
+			a.toolsets = make(map[string][]string)
+		}
+		for _, tool := range tools {
+			if tool != nil && !slices.Contains(a.toolsets[name], tool.Name()) {
+				a.toolsets[name] = append(a.toolsets[name], tool.Name())
+			}
+		}
+		a.tools = deduplicateTools(append(a.tools, tools...))
+	}
+}
+
+// ContextWithToolsets returns a context that exposes only the tools of the
+// named toolsets to agent runs, along with the tools outside any toolset.
+// Without names, only the tools outside any toolset are exposed. The
+// selection applies to sub-agents run with the context too.
+func ContextWithToolsets(ctx context.Context, names ...string) context.Context {
+	return context.WithValue(ctx, toolsetsKey{}, append([]string{}, names...))
+}
+
+// ToolsetsFromContext returns the toolsets selected with ContextWithToolsets,
+// and whether any selection was made
+func ToolsetsFromContext(ctx context.Context) ([]string, bool) {
+	names, ok := ctx.Value(toolsetsKey{}).([]string)
+	return names, ok
+}
+
+// Toolsets returns the names of the agent's toolsets, sorted
+func (a *Agent) Toolsets() []string {
+	names := make([]string, 0, len(a.toolsets))
+	for name := range a.toolsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// exposeToolsets removes the tools of the toolsets that were not selected
+// for the run. Tools outside any toolset are always exposed, unless the
+// organization of the run may not use them.
+func (a *Agent) exposeToolsets(ctx context.Context, tools []interfaces.Tool) []interfaces.Tool {
+	tools = allowTenantTools(ctx, tools)
+	selected, ok := ToolsetsFromContext(ctx)
+	if !ok || len(a.toolsets) == 0 {
+		return tools
+	}
+
+	exposed := make(map[string]bool)
+	for _, name := range selected {
+		toolset, found := a.toolsets[name]
+		if !found {
+			a.logger.Warn(ctx, "Unknown toolset selected", map[string]interface{}{
+				"toolset": name,
+			})
+			continue
+		}
+		for _, toolName := range toolset {
+			exposed[toolName] = true
+		}
+	}
+
+	result := make([]interfaces.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if a.inToolset(tool.Name()) && !exposed[tool.Name()] {
+			continue
+		}
+		result = append(result, tool)
+	}
+	return result
+}
+
+// inToolset reports whether a tool is in any of the agent's toolsets
+func (a *Agent) inToolset(toolName string) bool {
+	for _, toolset := range a.toolsets {
+		if slices.Contains(toolset, toolName) {
+			return true
+		}
+	}
+	return false
+}
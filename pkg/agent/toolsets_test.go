@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// toolsCapturingLLM records the tools of each GenerateWithTools call
+type toolsCapturingLLM struct {
+	mockLLM
+	tools [][]string
+}
+
+func (m *toolsCapturingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	m.tools = append(m.tools, toolNames(tools))
+	return "done", nil
+}
+
+func TestToolsets(t *testing.T) {
+	llm := &toolsCapturingLLM{}
+	agent, err := NewAgent(
+		WithLLM(llm),
+		WithTools(&mockTool{name: "search", description: "Search the web"}),
+		WithToolset("github", &mockTool{name: "create_issue", description: "Create an issue"}),
+		WithToolset("billing", &mockTool{name: "refund", description: "Refund a payment"}),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	if toolsets := agent.Toolsets(); fmt.Sprint(toolsets) != "[billing github]" {
+		t.Errorf("Unexpected toolsets %v", toolsets)
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{"no selection", context.Background(), "[search create_issue refund]"},
+		{"one toolset", ContextWithToolsets(context.Background(), "github"), "[search create_issue]"},
+		{"no toolsets", ContextWithToolsets(context.Background()), "[search]"},
+		{"unknown toolset", ContextWithToolsets(context.Background(), "admin"), "[search]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm.tools = nil
+			if _, err := agent.Run(tt.ctx, "Help me"); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if len(llm.tools) != 1 || fmt.Sprint(llm.tools[0]) != tt.expected {
+				t.Errorf("Expected tools %s, got %v", tt.expected, llm.tools)
+			}
+		})
+	}
+}
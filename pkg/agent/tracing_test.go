@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
+)
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name       string
+	attributes map[string]interface{}
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, interfaces.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordingSpan{name: name, attributes: make(map[string]interface{})}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (t *recordingTracer) StartTraceSession(ctx context.Context, contextID string) (context.Context, interfaces.Span) {
+	return t.StartSpan(ctx, "trace-session")
+}
+
+func (t *recordingTracer) span(name string) *recordingSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, span := range t.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func (s *recordingSpan) End()                                                    {}
+func (s *recordingSpan) AddEvent(name string, attributes map[string]interface{}) {}
+func (s *recordingSpan) SetAttribute(key string, value interface{})              { s.attributes[key] = value }
+func (s *recordingSpan) RecordError(err error)                                   {}
+
+func TestTracerSpansForRunLLMAndTools(t *testing.T) {
+	tracer := &recordingTracer{}
+	agent, err := NewAgent(
+		WithLLM(&mockLLM{}),
+		WithTracer(tracer),
+		WithName("traced-agent"),
+		WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	if _, err := agent.Run(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	run := tracer.span("agent.Run")
+	if run == nil {
+		t.Fatal("expected an agent.Run span")
+	}
+	if run.attributes[tracing.GenAIAgentName] != "traced-agent" {
+		t.Errorf("expected agent name attribute, got %v", run.attributes[tracing.GenAIAgentName])
+	}
+	if tracer.span("llm.generate") == nil && tracer.span("llm.generate_detailed") == nil {
+		t.Error("expected a span for the LLM call")
+	}
+
+	tool := &mockTool{
+		name: "lookup",
+		runFunc: func(ctx context.Context, input string) (string, error) {
+			return "found", nil
+		},
+	}
+	wrapped := agent.wrapToolsWithTracing([]interfaces.Tool{tool})
+	if _, err := wrapped[0].Run(context.Background(), "{}"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if span := tracer.span("tool.execute"); span == nil || span.attributes[tracing.GenAIToolName] != "lookup" {
+		t.Error("expected a tool.execute span for the tool")
+	}
+}
+
+func TestTracerDoesNotWrapTracedLLM(t *testing.T) {
+	tracer := &recordingTracer{}
+	llm := tracing.NewTracedLLM(&mockLLM{}, tracer)
+	agent, err := NewAgent(WithLLM(llm), WithTracer(tracer))
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	if agent.llm != llm {
+		t.Error("expected an already traced LLM to be used as is")
+	}
+}
@@ -2,14 +2,17 @@ package agent
 
 import (
 	"context"
+	"time"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/compliance"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 )
 
 // trackingTool wraps a Tool and records each invocation with the usage tracker
-// before delegating. Records the call when the LLM client actually invokes
-// Execute or Run, so the execution summary reflects tools the model chose to
-// call rather than every tool that was made available.
+// before delegating, and its input and output with the compliance run, if any.
+// Records the call when the LLM client actually invokes Execute or Run, so the
+// execution summary reflects tools the model chose to call rather than every
+// tool that was made available.
 type trackingTool struct {
 	inner   interfaces.Tool
 	tracker *usageTracker
@@ -23,14 +26,20 @@ func (t *trackingTool) Run(ctx context.Context, input string) (string, error) {
 	if t.tracker != nil {
 		t.tracker.addToolCall(t.inner.Name())
 	}
-	return t.inner.Run(ctx, input)
+	startedAt := time.Now()
+	result, err := t.inner.Run(ctx, input)
+	compliance.RecordToolCall(ctx, t.inner.Name(), input, result, err, startedAt)
+	return result, err
 }
 
 func (t *trackingTool) Execute(ctx context.Context, args string) (string, error) {
 	if t.tracker != nil {
 		t.tracker.addToolCall(t.inner.Name())
 	}
-	return t.inner.Execute(ctx, args)
+	startedAt := time.Now()
+	result, err := t.inner.Execute(ctx, args)
+	compliance.RecordToolCall(ctx, t.inner.Name(), args, result, err, startedAt)
+	return result, err
 }
 
 // DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
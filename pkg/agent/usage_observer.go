@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// UsageObserver receives the token usage of a run when it ends, including
+// runs that failed. Sub-agent runs report their own usage.
+type UsageObserver func(ctx context.Context, usage interfaces.TokenUsage)
+
+type usageObserverKey struct{}
+
+// WithUsageObserver returns a context whose runs report their token usage to
+// observer, for example to enforce token quotas
+func WithUsageObserver(ctx context.Context, observer UsageObserver) context.Context {
+	return context.WithValue(ctx, usageObserverKey{}, observer)
+}
+
+// hasUsageObserver returns true if the runs of ctx report their usage
+func hasUsageObserver(ctx context.Context) bool {
+	observer, _ := ctx.Value(usageObserverKey{}).(UsageObserver)
+	return observer != nil
+}
+
+// notifyUsageObserver reports the usage collected by tracker to the observer
+// of ctx, if any
+func notifyUsageObserver(ctx context.Context, tracker *usageTracker) {
+	observer, _ := ctx.Value(usageObserverKey{}).(UsageObserver)
+	if observer == nil {
+		return
+	}
+	if usage, _, _ := tracker.getResults(); usage != nil && usage.TotalTokens > 0 {
+		observer(ctx, *usage)
+	}
+}
@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+)
+
+func TestUsageObserver(t *testing.T) {
+	llm := mock.New(mock.WithUsage(interfaces.TokenUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}))
+	llm.On(mock.Any()).Respond("Hello")
+
+	agent, err := NewAgent(WithLLM(llm), WithOrgID("org-a"))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	var reported []interfaces.TokenUsage
+	ctx := WithUsageObserver(context.Background(), func(ctx context.Context, usage interfaces.TokenUsage) {
+		reported = append(reported, usage)
+	})
+
+	// Run does not return usage, but observers still get it
+	if _, err := agent.Run(ctx, "Hi"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(reported) != 1 || reported[0].TotalTokens != 15 {
+		t.Fatalf("Expected the usage of the run, got %+v", reported)
+	}
+
+	// Runs without an observer report nothing
+	if _, err := agent.Run(context.Background(), "Hi"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(reported) != 1 {
+		t.Errorf("Expected no report without an observer, got %+v", reported)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	"gopkg.in/yaml.v3"
 )
 
@@ -324,15 +325,16 @@ func loadFromRemoteByID(ctx context.Context, agentID, environment string, opts *
 		return nil, fmt.Errorf("failed to fetch remote config: %w", err)
 	}
 
-	// DEBUG: Log what the config server returned
-	fmt.Printf("[DEBUG] Config server response - ResolvedVariables count: %d\n", len(response.ResolvedVariables))
-	for key, value := range response.ResolvedVariables {
-		displayValue := value
-		if len(value) > 10 {
-			displayValue = value[:10] + "..."
-		}
-		fmt.Printf("[DEBUG] ResolvedVariable: %s = '%s'\n", key, displayValue)
+	logger := logging.New()
+	resolved := make([]string, 0, len(response.ResolvedVariables))
+	for key := range response.ResolvedVariables {
+		resolved = append(resolved, key)
 	}
+	logger.Debug(ctx, "Fetched remote agent config", map[string]interface{}{
+		"agent_id":           agentID,
+		"environment":        environment,
+		"resolved_variables": resolved,
+	})
 
 	// Parse the resolved YAML - it has the agent name as top-level key
 	// Format: agent_name: { role: "...", goal: "...", ... }
@@ -351,10 +353,10 @@ func loadFromRemoteByID(ctx context.Context, agentID, environment string, opts *
 	for name, cfg := range wrappedConfig {
 		actualAgentName = name
 		config = cfg
-		fmt.Printf("[DEBUG] loadFromRemoteByID - Loaded config for agent: %s\n", actualAgentName)
-		fmt.Printf("[DEBUG] loadFromRemoteByID - Role: %s\n", cfg.Role)
-		fmt.Printf("[DEBUG] loadFromRemoteByID - Goal: %s\n", cfg.Goal)
-		fmt.Printf("[DEBUG] loadFromRemoteByID - Backstory: %s\n", cfg.Backstory)
+		logger.Debug(ctx, "Loaded remote agent config", map[string]interface{}{
+			"agent_name": actualAgentName,
+			"role":       cfg.Role,
+		})
 		break
 	}
 
@@ -478,6 +480,7 @@ func deepCopyAgentConfig(src *agent.AgentConfig) *agent.AgentConfig {
 					Env:     deepCopyStringMap(v.Env),
 					URL:     v.URL,
 					Token:   v.Token,
+					Headers: deepCopyStringMap(v.Headers),
 				}
 			}
 		}
@@ -531,6 +534,18 @@ func deepCopyAgentConfig(src *agent.AgentConfig) *agent.AgentConfig {
 			val := *src.LLMConfig.Reasoning
 			dst.LLMConfig.Reasoning = &val
 		}
+		if src.LLMConfig.MaxOutputTokens != nil {
+			val := *src.LLMConfig.MaxOutputTokens
+			dst.LLMConfig.MaxOutputTokens = &val
+		}
+		if src.LLMConfig.Seed != nil {
+			val := *src.LLMConfig.Seed
+			dst.LLMConfig.Seed = &val
+		}
+		if src.LLMConfig.Deterministic != nil {
+			val := *src.LLMConfig.Deterministic
+			dst.LLMConfig.Deterministic = &val
+		}
 		dst.LLMConfig.StopSequences = deepCopyStringSlice(src.LLMConfig.StopSequences)
 	}
 
@@ -598,6 +613,10 @@ func deepCopyAgentConfig(src *agent.AgentConfig) *agent.AgentConfig {
 			val := *src.ImageGeneration.Enabled
 			dst.ImageGeneration.Enabled = &val
 		}
+		if src.ImageGeneration.EditImage != nil {
+			val := *src.ImageGeneration.EditImage
+			dst.ImageGeneration.EditImage = &val
+		}
 		if src.ImageGeneration.Storage != nil {
 			dst.ImageGeneration.Storage = &agent.ImageStorageYAML{
 				Type: src.ImageGeneration.Storage.Type,
@@ -617,6 +636,18 @@ func deepCopyAgentConfig(src *agent.AgentConfig) *agent.AgentConfig {
 					SignedURLExpiration: src.ImageGeneration.Storage.GCS.SignedURLExpiration,
 				}
 			}
+			if src.ImageGeneration.Storage.S3 != nil {
+				s3 := *src.ImageGeneration.Storage.S3
+				if s3.UsePathStyle != nil {
+					val := *s3.UsePathStyle
+					s3.UsePathStyle = &val
+				}
+				dst.ImageGeneration.Storage.S3 = &s3
+			}
+			if src.ImageGeneration.Storage.AzureBlob != nil {
+				azblob := *src.ImageGeneration.Storage.AzureBlob
+				dst.ImageGeneration.Storage.AzureBlob = &azblob
+			}
 		}
 		if src.ImageGeneration.MultiTurnEditing != nil {
 			dst.ImageGeneration.MultiTurnEditing = &agent.MultiTurnEditingYAML{
@@ -811,6 +842,7 @@ func MergeAgentConfig(primary, base *agent.AgentConfig, strategy MergeStrategy)
 					Env:     deepCopyStringMap(v.Env),
 					URL:     v.URL,
 					Token:   v.Token,
+					Headers: deepCopyStringMap(v.Headers),
 				}
 			}
 		}
@@ -864,6 +896,18 @@ func MergeAgentConfig(primary, base *agent.AgentConfig, strategy MergeStrategy)
 			val := *base.LLMConfig.Reasoning
 			result.LLMConfig.Reasoning = &val
 		}
+		if base.LLMConfig.MaxOutputTokens != nil {
+			val := *base.LLMConfig.MaxOutputTokens
+			result.LLMConfig.MaxOutputTokens = &val
+		}
+		if base.LLMConfig.Seed != nil {
+			val := *base.LLMConfig.Seed
+			result.LLMConfig.Seed = &val
+		}
+		if base.LLMConfig.Deterministic != nil {
+			val := *base.LLMConfig.Deterministic
+			result.LLMConfig.Deterministic = &val
+		}
 		result.LLMConfig.StopSequences = deepCopyStringSlice(base.LLMConfig.StopSequences)
 	}
 
@@ -962,10 +1006,11 @@ func MergeAgentConfig(primary, base *agent.AgentConfig, strategy MergeStrategy)
 	// Merge ImageGeneration (deep copy from base if needed)
 	if result.ImageGeneration == nil && base.ImageGeneration != nil {
 		result.ImageGeneration = &agent.ImageGenerationYAML{
-			Enabled:  base.ImageGeneration.Enabled,
-			Provider: base.ImageGeneration.Provider,
-			Model:    base.ImageGeneration.Model,
-			Config:   deepCopyMap(base.ImageGeneration.Config),
+			Enabled:   base.ImageGeneration.Enabled,
+			Provider:  base.ImageGeneration.Provider,
+			Model:     base.ImageGeneration.Model,
+			Config:    deepCopyMap(base.ImageGeneration.Config),
+			EditImage: base.ImageGeneration.EditImage,
 		}
 		if base.ImageGeneration.Storage != nil {
 			result.ImageGeneration.Storage = &agent.ImageStorageYAML{
@@ -986,6 +1031,18 @@ func MergeAgentConfig(primary, base *agent.AgentConfig, strategy MergeStrategy)
 					SignedURLExpiration: base.ImageGeneration.Storage.GCS.SignedURLExpiration,
 				}
 			}
+			if base.ImageGeneration.Storage.S3 != nil {
+				s3 := *base.ImageGeneration.Storage.S3
+				if s3.UsePathStyle != nil {
+					val := *s3.UsePathStyle
+					s3.UsePathStyle = &val
+				}
+				result.ImageGeneration.Storage.S3 = &s3
+			}
+			if base.ImageGeneration.Storage.AzureBlob != nil {
+				azblob := *base.ImageGeneration.Storage.AzureBlob
+				result.ImageGeneration.Storage.AzureBlob = &azblob
+			}
 		}
 		if base.ImageGeneration.MultiTurnEditing != nil {
 			result.ImageGeneration.MultiTurnEditing = &agent.MultiTurnEditingYAML{
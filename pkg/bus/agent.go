@@ -0,0 +1,120 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// MessageTypeAgentResult is the type of the results AgentHandler
+	// publishes
+	MessageTypeAgentResult = "agent.result"
+
+	// MetadataCorrelationID is the metadata key of the ID of the message a
+	// result answers
+	MetadataCorrelationID = "correlation_id"
+)
+
+// Runner runs an agent on an input, such as an *agent.Agent
+type Runner interface {
+	Run(ctx context.Context, input string) (string, error)
+}
+
+// AgentHandler returns a handler running an agent on each message, with the
+// payload as input, and publishing the result on resultTopic when set. The
+// result's correlation_id metadata is the ID of the message.
+func AgentHandler(b Bus, runner Runner, name, resultTopic string) Handler {
+	return func(ctx context.Context, msg Message) error {
+		result, err := runner.Run(ctx, msg.Text())
+		if err != nil {
+			return fmt.Errorf("agent %s failed on message %s: %w", name, msg.ID, err)
+		}
+		if resultTopic == "" {
+			return nil
+		}
+
+		reply, err := NewMessage(resultTopic, MessageTypeAgentResult, result)
+		if err != nil {
+			return err
+		}
+		reply.Source = name
+		reply.Metadata = map[string]string{MetadataCorrelationID: msg.ID}
+		return b.Publish(ctx, reply)
+	}
+}
+
+// PublishTool lets an agent publish events on a topic
+type PublishTool struct {
+	bus    Bus
+	topic  string
+	source string
+}
+
+// NewPublishTool creates a tool publishing events on topic, with source as
+// their Source
+func NewPublishTool(b Bus, topic, source string) *PublishTool {
+	return &PublishTool{bus: b, topic: topic, source: source}
+}
+
+// Name implements interfaces.Tool.Name
+func (t *PublishTool) Name() string {
+	return "publish_event"
+}
+
+// Description implements interfaces.Tool.Description
+func (t *PublishTool) Description() string {
+	return fmt.Sprintf("Publish an event on the %s topic for the agents and services subscribed to it", t.topic)
+}
+
+// Parameters implements interfaces.Tool.Parameters
+func (t *PublishTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"type": {
+			Type:        "string",
+			Description: "The type of the event, e.g. research.completed",
+			Required:    true,
+		},
+		"payload": {
+			Type:        "string",
+			Description: "The content of the event, text or JSON",
+			Required:    true,
+		},
+	}
+}
+
+// Run implements interfaces.Tool.Run
+func (t *PublishTool) Run(ctx context.Context, input string) (string, error) {
+	return t.publish(ctx, "", input)
+}
+
+// Execute implements interfaces.Tool.Execute
+func (t *PublishTool) Execute(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Type    string `json:"type"`
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse arguments: %w", err)
+	}
+	return t.publish(ctx, params.Type, params.Payload)
+}
+
+func (t *PublishTool) publish(ctx context.Context, eventType, payload string) (string, error) {
+	msg := Message{Topic: t.topic, Type: eventType, Source: t.source}
+	if json.Valid([]byte(payload)) {
+		msg.Payload = json.RawMessage(payload)
+	} else {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode payload: %w", err)
+		}
+		msg.Payload = data
+	}
+	if err := t.bus.Publish(ctx, msg); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Published %s event on %s", eventType, t.topic), nil
+}
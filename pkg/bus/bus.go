@@ -0,0 +1,183 @@
+// Package bus provides a publish/subscribe message bus for event-driven
+// multi-agent systems. Agents, workflow tasks and application code publish
+// typed events on topics and subscribe to the topics they react to, instead
+// of calling each other directly.
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// ErrClosed is returned when publishing on or subscribing to a closed bus
+var ErrClosed = errors.New("message bus is closed")
+
+// Message is an event published on a topic
+type Message struct {
+	// ID is the unique identifier of the message, set when published
+	ID string `json:"id"`
+
+	// Topic is the topic the message is published on, e.g. orders.created
+	Topic string `json:"topic"`
+
+	// Type is the type of the event, e.g. research.completed
+	Type string `json:"type,omitempty"`
+
+	// Source is the agent or task that published the message
+	Source string `json:"source,omitempty"`
+
+	// Payload is the JSON payload of the event
+	Payload json.RawMessage `json:"payload,omitempty"`
+
+	// Metadata holds additional information, such as a correlation ID
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Timestamp is when the message was published
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewMessage creates a message with the JSON encoding of payload
+func NewMessage(topic, eventType string, payload interface{}) (Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return Message{Topic: topic, Type: eventType, Payload: data}, nil
+}
+
+// Decode decodes the payload of the message into v
+func (m Message) Decode(v interface{}) error {
+	if err := json.Unmarshal(m.Payload, v); err != nil {
+		return fmt.Errorf("failed to decode payload of message %s: %w", m.ID, err)
+	}
+	return nil
+}
+
+// Text returns the payload of the message as text: strings as is, other
+// values as JSON
+func (m Message) Text() string {
+	var text string
+	if err := json.Unmarshal(m.Payload, &text); err == nil {
+		return text
+	}
+	return string(m.Payload)
+}
+
+// Handler handles the messages of a subscription. Errors are logged; the
+// subscription keeps receiving messages.
+type Handler func(ctx context.Context, msg Message) error
+
+// Subscription is a subscription to the messages of a topic
+type Subscription interface {
+	// Unsubscribe stops the delivery of messages
+	Unsubscribe() error
+}
+
+// Bus publishes messages to the subscribers of their topic. Topics of
+// subscriptions may be glob patterns, e.g. orders.* matches orders.created.
+// Each subscription receives its messages in order, one at a time.
+type Bus interface {
+	// Publish publishes a message on its topic
+	Publish(ctx context.Context, msg Message) error
+
+	// Subscribe calls handler with the messages published on topic until
+	// the subscription or ctx ends
+	Subscribe(ctx context.Context, topic string, handler Handler) (Subscription, error)
+
+	// Close ends all subscriptions
+	Close() error
+}
+
+// Publish publishes payload as a typed event on a topic
+func Publish[T any](ctx context.Context, b Bus, topic, eventType string, payload T) error {
+	msg, err := NewMessage(topic, eventType, payload)
+	if err != nil {
+		return err
+	}
+	return b.Publish(ctx, msg)
+}
+
+// On subscribes to the typed events of a topic, decoding their payload
+func On[T any](ctx context.Context, b Bus, topic string, handler func(ctx context.Context, msg Message, payload T) error) (Subscription, error) {
+	return b.Subscribe(ctx, topic, func(ctx context.Context, msg Message) error {
+		var payload T
+		if err := msg.Decode(&payload); err != nil {
+			return err
+		}
+		return handler(ctx, msg, payload)
+	})
+}
+
+// Option represents an option for configuring a bus
+type Option func(*options)
+
+type options struct {
+	bufferSize int
+	logger     logging.Logger
+}
+
+// WithBufferSize sets how many messages a subscription buffers while its
+// handler is busy (default 100). Publishing blocks when the buffer is full.
+func WithBufferSize(size int) Option {
+	return func(o *options) {
+		o.bufferSize = size
+	}
+}
+
+// WithLogger sets the logger for handler errors
+func WithLogger(logger logging.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		bufferSize: 100,
+		logger:     logging.New(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// prepare sets the ID and timestamp of a message being published
+func prepare(msg Message) (Message, error) {
+	if msg.Topic == "" {
+		return msg, fmt.Errorf("message has no topic")
+	}
+	if msg.ID == "" {
+		msg.ID = uuid.New().String()
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	return msg, nil
+}
+
+// matches reports whether a topic matches the topic pattern of a
+// subscription
+func matches(pattern, topic string) bool {
+	matched, err := path.Match(pattern, topic)
+	return err == nil && matched
+}
+
+// handle calls a handler, logging its errors
+func handle(ctx context.Context, logger logging.Logger, handler Handler, msg Message) {
+	if err := handler(ctx, msg); err != nil {
+		logger.Error(ctx, "Message handler failed", map[string]interface{}{
+			"topic":      msg.Topic,
+			"message_id": msg.ID,
+			"error":      err.Error(),
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+type orderCreated struct {
+	OrderID string `json:"order_id"`
+	Total   int    `json:"total"`
+}
+
+// receive returns the next message of a channel, failing the test after a
+// second
+func receive(t *testing.T, messages <-chan Message) Message {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a message")
+		return Message{}
+	}
+}
+
+func collect(messages chan<- Message) Handler {
+	return func(ctx context.Context, msg Message) error {
+		messages <- msg
+		return nil
+	}
+}
+
+func testBus(t *testing.T, publisher, subscriber Bus) {
+	t.Helper()
+	ctx := context.Background()
+
+	all := make(chan Message, 10)
+	if _, err := subscriber.Subscribe(ctx, "orders.*", collect(all)); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	typed := make(chan orderCreated, 10)
+	subscription, err := On(ctx, subscriber, "orders.created", func(ctx context.Context, msg Message, order orderCreated) error {
+		typed <- order
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("On() error = %v", err)
+	}
+
+	for i, id := range []string{"A1", "A2"} {
+		if err := Publish(ctx, publisher, "orders.created", "order.created", orderCreated{OrderID: id, Total: i}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if err := Publish(ctx, publisher, "payments.received", "payment.received", "A1"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	// Messages arrive in order, only on matching subscriptions
+	for _, want := range []string{"A1", "A2"} {
+		msg := receive(t, all)
+		var order orderCreated
+		if err := msg.Decode(&order); err != nil || order.OrderID != want {
+			t.Errorf("Expected order %s, got %+v, %v", want, order, err)
+		}
+		if msg.ID == "" || msg.Type != "order.created" || msg.Timestamp.IsZero() {
+			t.Errorf("Expected the message to be complete, got %+v", msg)
+		}
+		select {
+		case order := <-typed:
+			if order.OrderID != want {
+				t.Errorf("Expected typed order %s, got %+v", want, order)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for a typed message")
+		}
+	}
+	select {
+	case msg := <-all:
+		t.Errorf("Expected no message of another topic, got %+v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Unsubscribed handlers receive nothing
+	if err := subscription.Unsubscribe(); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+	if err := Publish(ctx, publisher, "orders.created", "order.created", orderCreated{OrderID: "A3"}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	receive(t, all)
+	select {
+	case order := <-typed:
+		t.Errorf("Expected no message after Unsubscribe, got %+v", order)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := subscriber.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := subscriber.Subscribe(ctx, "orders.*", collect(all)); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestMemoryBus(t *testing.T) {
+	b := NewMemoryBus()
+	testBus(t, b, b)
+
+	if err := b.Publish(context.Background(), Message{Topic: "orders.created"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestRedisBus(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	// Buses of different processes share the messages
+	testBus(t, NewRedisBus(client, ""), NewRedisBus(client, ""))
+}
+
+type echoRunner struct{}
+
+func (echoRunner) Run(ctx context.Context, input string) (string, error) {
+	if input == "fail" {
+		return "", errors.New("model unavailable")
+	}
+	return "summary of " + input, nil
+}
+
+func TestAgentHandler(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBus()
+	defer b.Close()
+
+	results := make(chan Message, 1)
+	if _, err := b.Subscribe(ctx, "summaries", collect(results)); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if _, err := b.Subscribe(ctx, "articles", AgentHandler(b, echoRunner{}, "summarizer", "summaries")); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	msg, err := NewMessage("articles", "article.published", "the article")
+	if err != nil {
+		t.Fatalf("NewMessage() error = %v", err)
+	}
+	msg.ID = "article-1"
+	if err := b.Publish(ctx, msg); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	result := receive(t, results)
+	if result.Text() != "summary of the article" || result.Type != MessageTypeAgentResult || result.Source != "summarizer" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if result.Metadata[MetadataCorrelationID] != "article-1" {
+		t.Errorf("Expected the result to be correlated with the article, got %v", result.Metadata)
+	}
+
+	handler := AgentHandler(b, echoRunner{}, "summarizer", "")
+	if err := handler(ctx, Message{ID: "article-2", Payload: []byte(`"fail"`)}); err == nil || !strings.Contains(err.Error(), "article-2") {
+		t.Errorf("Expected the agent error, got %v", err)
+	}
+}
+
+func TestPublishTool(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBus()
+	defer b.Close()
+
+	messages := make(chan Message, 2)
+	if _, err := b.Subscribe(ctx, "research", collect(messages)); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	tool := NewPublishTool(b, "research", "researcher")
+	if _, err := tool.Execute(ctx, `{"type": "research.completed", "payload": "{\"findings\": 3}"}`); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, err := tool.Execute(ctx, `{"type": "research.note", "payload": "plain text"}`); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	msg := receive(t, messages)
+	var payload struct {
+		Findings int `json:"findings"`
+	}
+	if err := msg.Decode(&payload); err != nil || payload.Findings != 3 || msg.Source != "researcher" {
+		t.Errorf("Expected the JSON payload, got %+v, %v", msg, err)
+	}
+	if msg := receive(t, messages); msg.Text() != "plain text" {
+		t.Errorf("Expected the text payload, got %q", msg.Text())
+	}
+}
@@ -0,0 +1,123 @@
+package bus
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBus is a Bus delivering messages within the process
+type MemoryBus struct {
+	options options
+
+	mu            sync.RWMutex
+	subscriptions map[*memorySubscription]struct{}
+	closed        bool
+}
+
+// NewMemoryBus creates an in-memory message bus
+func NewMemoryBus(opts ...Option) *MemoryBus {
+	return &MemoryBus{
+		options:       newOptions(opts),
+		subscriptions: make(map[*memorySubscription]struct{}),
+	}
+}
+
+// memorySubscription delivers the messages of a subscription in order
+type memorySubscription struct {
+	bus      *MemoryBus
+	topic    string
+	messages chan Message
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Publish implements Bus.Publish
+func (b *MemoryBus) Publish(ctx context.Context, msg Message) error {
+	msg, err := prepare(msg)
+	if err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	var subscriptions []*memorySubscription
+	for subscription := range b.subscriptions {
+		if matches(subscription.topic, msg.Topic) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, subscription := range subscriptions {
+		select {
+		case subscription.messages <- msg:
+		case <-subscription.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe implements Bus.Subscribe
+func (b *MemoryBus) Subscribe(ctx context.Context, topic string, handler Handler) (Subscription, error) {
+	subscription := &memorySubscription{
+		bus:      b,
+		topic:    topic,
+		messages: make(chan Message, b.options.bufferSize),
+		done:     make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, ErrClosed
+	}
+	b.subscriptions[subscription] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case msg := <-subscription.messages:
+				handle(ctx, b.options.logger, handler, msg)
+			case <-subscription.done:
+				return
+			case <-ctx.Done():
+				_ = subscription.Unsubscribe()
+				return
+			}
+		}
+	}()
+	return subscription, nil
+}
+
+// Close implements Bus.Close
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	subscriptions := b.subscriptions
+	b.subscriptions = make(map[*memorySubscription]struct{})
+	b.mu.Unlock()
+
+	for subscription := range subscriptions {
+		subscription.stop()
+	}
+	return nil
+}
+
+// Unsubscribe implements Subscription.Unsubscribe
+func (s *memorySubscription) Unsubscribe() error {
+	s.bus.mu.Lock()
+	delete(s.bus.subscriptions, s)
+	s.bus.mu.Unlock()
+	s.stop()
+	return nil
+}
+
+func (s *memorySubscription) stop() {
+	s.once.Do(func() { close(s.done) })
+}
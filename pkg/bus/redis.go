@@ -0,0 +1,144 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisBus is a Bus using Redis pub/sub, delivering messages to the
+// subscribers of all the processes sharing the Redis server. Messages
+// published while a subscriber is disconnected are not delivered to it.
+type RedisBus struct {
+	client    *redis.Client
+	keyPrefix string
+	options   options
+
+	mu            sync.Mutex
+	subscriptions map[*redisSubscription]struct{}
+	closed        bool
+}
+
+// NewRedisBus creates a message bus whose Redis channels start with
+// keyPrefix (default "bus:")
+func NewRedisBus(client *redis.Client, keyPrefix string, opts ...Option) *RedisBus {
+	if keyPrefix == "" {
+		keyPrefix = "bus:"
+	}
+	return &RedisBus{
+		client:        client,
+		keyPrefix:     keyPrefix,
+		options:       newOptions(opts),
+		subscriptions: make(map[*redisSubscription]struct{}),
+	}
+}
+
+// redisSubscription is a Redis pattern subscription
+type redisSubscription struct {
+	bus    *RedisBus
+	pubsub *redis.PubSub
+	once   sync.Once
+}
+
+// Publish implements Bus.Publish
+func (b *RedisBus) Publish(ctx context.Context, msg Message) error {
+	msg, err := prepare(msg)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	closed := b.closed
+	b.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	if err := b.client.Publish(ctx, b.keyPrefix+msg.Topic, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements Bus.Subscribe
+func (b *RedisBus) Subscribe(ctx context.Context, topic string, handler Handler) (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	pubsub := b.client.PSubscribe(ctx, b.keyPrefix+topic)
+	// Wait for the confirmation, so that messages published after Subscribe
+	// returns are delivered
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+
+	subscription := &redisSubscription{bus: b, pubsub: pubsub}
+	b.subscriptions[subscription] = struct{}{}
+
+	messages := pubsub.Channel(redis.WithChannelSize(b.options.bufferSize))
+	go func() {
+		for {
+			select {
+			case redisMsg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var msg Message
+				if err := json.Unmarshal([]byte(redisMsg.Payload), &msg); err != nil {
+					b.options.logger.Error(ctx, "Failed to decode message", map[string]interface{}{
+						"channel": redisMsg.Channel,
+						"error":   err.Error(),
+					})
+					continue
+				}
+				handle(ctx, b.options.logger, handler, msg)
+			case <-ctx.Done():
+				_ = subscription.Unsubscribe()
+				return
+			}
+		}
+	}()
+	return subscription, nil
+}
+
+// Close implements Bus.Close. The Redis client is not closed.
+func (b *RedisBus) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	subscriptions := b.subscriptions
+	b.subscriptions = make(map[*redisSubscription]struct{})
+	b.mu.Unlock()
+
+	var firstErr error
+	for subscription := range subscriptions {
+		if err := subscription.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Unsubscribe implements Subscription.Unsubscribe
+func (s *redisSubscription) Unsubscribe() error {
+	s.bus.mu.Lock()
+	delete(s.bus.subscriptions, s)
+	s.bus.mu.Unlock()
+	return s.close()
+}
+
+func (s *redisSubscription) close() error {
+	var err error
+	s.once.Do(func() { err = s.pubsub.Close() })
+	return err
+}
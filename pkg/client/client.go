@@ -0,0 +1,395 @@
+// Package client is a typed Go client for agent microservices served by
+// microservice.HTTPServer, following the OpenAPI document the servers
+// publish at /openapi.json.
+//
+//	c := client.NewClient("http://agent:8080", client.WithAPIKey(key))
+//	resp, err := c.Run(ctx, &client.RunRequest{Input: "Hello", ConversationID: "conv-1"})
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client calls the endpoints of an agent server
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	apiKey      string
+	bearerToken string
+	orgID       string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithAPIKey authenticates requests with an API key in the X-API-Key header
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithBearerToken authenticates requests with a bearer token, such as a JWT
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.bearerToken = token
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests. Streams last as long
+// as runs, so set deadlines with the request context rather than a client
+// timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithOrgID sets the organization of requests on servers that take it from
+// the request rather than from authentication
+func WithOrgID(orgID string) Option {
+	return func(c *Client) {
+		c.orgID = orgID
+	}
+}
+
+// NewClient creates a client for the agent server at baseURL, e.g.
+// "http://localhost:8080"
+func NewClient(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// APIError is returned for responses with an error status
+type APIError struct {
+	StatusCode int
+	Message    string
+
+	// RunID is the run that failed, for run errors
+	RunID string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("agent server returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Health checks the health of the server
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	var health Health
+	if err := c.do(ctx, "GET", "/health", nil, nil, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// Metadata describes the agent of the server
+func (c *Client) Metadata(ctx context.Context) (*Metadata, error) {
+	var metadata Metadata
+	if err := c.do(ctx, "GET", "/api/v1/agent/metadata", nil, nil, &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// Run runs the agent and returns its response
+func (c *Client) Run(ctx context.Context, req *RunRequest) (*RunResponse, error) {
+	var resp RunResponse
+	if err := c.do(ctx, "POST", "/api/v1/agent/run", nil, c.runRequest(req), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Stream runs the agent and returns its events. The channel is closed when
+// the stream ends; a broken stream ends with an error event. Cancel ctx to
+// stop reading, and CancelRun to stop the run.
+func (c *Client) Stream(ctx context.Context, req *RunRequest) (<-chan StreamEvent, error) {
+	httpReq, err := c.newRequest(ctx, "POST", "/api/v1/agent/stream", nil, c.runRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	return c.stream(ctx, httpReq)
+}
+
+// ResumeStream resumes the stream of a run after the event with ID
+// lastEventID, e.g. after Stream ended with an error event because the
+// connection dropped. The events already received are not sent again.
+func (c *Client) ResumeStream(ctx context.Context, runID, lastEventID string) (<-chan StreamEvent, error) {
+	httpReq, err := c.newRequest(ctx, "GET", "/api/v1/agent/runs/"+url.PathEscape(runID)+"/events", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if lastEventID != "" {
+		httpReq.Header.Set("Last-Event-ID", lastEventID)
+	}
+	return c.stream(ctx, httpReq)
+}
+
+// stream sends a request for a stream of events and reads them
+func (c *Client) stream(ctx context.Context, httpReq *http.Request) (<-chan StreamEvent, error) {
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+
+	events := make(chan StreamEvent, 16)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		if err := readEvents(resp.Body, func(event StreamEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}); err != nil && ctx.Err() == nil {
+			select {
+			case events <- StreamEvent{Event: "error", Type: "error", Error: err.Error(), IsFinal: true}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return events, nil
+}
+
+// GetRun returns the status of a run
+func (c *Client) GetRun(ctx context.Context, runID string) (*RunStatus, error) {
+	var status RunStatus
+	if err := c.do(ctx, "GET", "/api/v1/agent/runs/"+url.PathEscape(runID), nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// CancelRun cancels a run and returns its status. The run may still be
+// running when CancelRun returns.
+func (c *Client) CancelRun(ctx context.Context, runID string) (*RunStatus, error) {
+	var status RunStatus
+	if err := c.do(ctx, "POST", "/api/v1/agent/runs/"+url.PathEscape(runID)+"/cancel", nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// CreateUpload returns a signed URL for uploading a file of the given MIME
+// type directly to the storage of the server
+func (c *Client) CreateUpload(ctx context.Context, mimeType string) (*Upload, error) {
+	var upload Upload
+	body := map[string]string{"mime_type": mimeType}
+	if err := c.do(ctx, "POST", "/api/v1/uploads", nil, body, &upload); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// UploadFile uploads a file and returns its storage reference for the
+// content parts of run requests. Storage services need the length of the
+// file, so pass a *bytes.Reader, *bytes.Buffer or *strings.Reader.
+func (c *Client) UploadFile(ctx context.Context, mimeType string, file io.Reader) (string, error) {
+	upload, err := c.CreateUpload(ctx, mimeType)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, upload.Method, upload.UploadURL, file)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	for name, value := range upload.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", newAPIError(resp)
+	}
+	return upload.StorageRef, nil
+}
+
+// ListArtifacts lists the files produced in a conversation, newest first
+func (c *Client) ListArtifacts(ctx context.Context, conversationID string, options *ArtifactListOptions) (*ArtifactList, error) {
+	query := url.Values{}
+	if options != nil {
+		if options.MimeType != "" {
+			query.Set("mime_type", options.MimeType)
+		}
+		if options.Limit > 0 {
+			query.Set("limit", strconv.Itoa(options.Limit))
+		}
+		if options.Offset > 0 {
+			query.Set("offset", strconv.Itoa(options.Offset))
+		}
+	}
+
+	var list ArtifactList
+	path := "/api/v1/conversations/" + url.PathEscape(conversationID) + "/artifacts"
+	if err := c.do(ctx, "GET", path, query, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// runRequest applies the organization of the client to a run request
+func (c *Client) runRequest(req *RunRequest) *RunRequest {
+	if req.OrgID != "" || c.orgID == "" {
+		return req
+	}
+	withOrg := *req
+	withOrg.OrgID = c.orgID
+	return &withOrg
+}
+
+// newRequest creates an authenticated request with a JSON body, if any
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Request, error) {
+	if c.orgID != "" {
+		if query == nil {
+			query = url.Values{}
+		}
+		query.Set("org_id", c.orgID)
+	}
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	return req, nil
+}
+
+// do sends a request and decodes its JSON response into result
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, result interface{}) error {
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return newAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// newAPIError reads the error of a response, which is JSON with an error
+// field or plain text
+func newAPIError(resp *http.Response) *APIError {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	var body struct {
+		Error string `json:"error"`
+		RunID string `json:"run_id"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		apiErr.Message = body.Error
+		apiErr.RunID = body.RunID
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// readEvents parses Server-Sent Events from r and passes them to emit until
+// it returns false
+func readEvents(r io.Reader, emit func(StreamEvent) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	var name, id string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data.Len() > 0 {
+				var event StreamEvent
+				if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+					return fmt.Errorf("failed to decode stream event: %w", err)
+				}
+				event.Event, event.ID = name, id
+				if event.Event == "" {
+					event.Event = event.Type
+				}
+				if !emit(event) {
+					return nil
+				}
+			}
+			name, id = "", ""
+			data.Reset()
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			name = value
+		case "id":
+			id = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,201 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/client"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/microservice"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	llm := mock.New(mock.WithUsage(interfaces.TokenUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}))
+	llm.On(mock.Any()).Respond("Hello from the agent")
+
+	testAgent, err := agent.NewAgent(
+		agent.WithLLM(llm),
+		agent.WithMemory(memory.NewConversationBuffer()),
+		agent.WithName("ClientTestAgent"),
+		agent.WithDescription("Answers client tests"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	server := httptest.NewServer(microservice.NewHTTPServer(testAgent, 0).Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient(t *testing.T) {
+	server := newTestServer(t)
+	c := client.NewClient(server.URL+"/", client.WithOrgID("org-1"))
+	ctx := context.Background()
+
+	health, err := c.Health(ctx)
+	if err != nil || health.Status != "healthy" || health.Agent != "ClientTestAgent" {
+		t.Fatalf("Health() = %+v, %v", health, err)
+	}
+
+	metadata, err := c.Metadata(ctx)
+	if err != nil || metadata.Name != "ClientTestAgent" || metadata.Description != "Answers client tests" || !metadata.SupportsStreaming {
+		t.Fatalf("Metadata() = %+v, %v", metadata, err)
+	}
+
+	resp, err := c.Run(ctx, &client.RunRequest{Input: "Hi", ConversationID: "conv-1", RunID: "run-1"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if resp.Output != "Hello from the agent" || resp.RunID != "run-1" || resp.Agent != "ClientTestAgent" {
+		t.Errorf("Unexpected run response %+v", resp)
+	}
+	if resp.Usage == nil || resp.Usage.TotalTokens != 15 || resp.ExecutionSummary.LLMCalls != 1 {
+		t.Errorf("Expected usage and execution summary, got %+v", resp)
+	}
+
+	status, err := c.GetRun(ctx, "run-1")
+	if err != nil || status.State != "completed" || status.OrgID != "org-1" {
+		t.Errorf("GetRun() = %+v, %v", status, err)
+	}
+
+	_, err = c.Run(ctx, &client.RunRequest{Input: "Hi", ConversationID: "conv-1", RunID: "run-1"})
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("Expected a conflict APIError for a reused run ID, got %v", err)
+	}
+
+	if _, err := c.GetRun(ctx, "missing"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a not found APIError, got %v", err)
+	}
+}
+
+func TestClient_Stream(t *testing.T) {
+	server := newTestServer(t)
+	c := client.NewClient(server.URL, client.WithOrgID("org-1"))
+
+	events, err := c.Stream(context.Background(), &client.RunRequest{Input: "Hi", ConversationID: "conv-1"})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	var names []string
+	var content strings.Builder
+	for event := range events {
+		names = append(names, event.Event)
+		if event.Event == "error" {
+			t.Fatalf("Unexpected error event: %s", event.Error)
+		}
+		if event.Event == "connected" && event.Metadata["run_id"] == "" {
+			t.Error("Expected the run ID in the connected event")
+		}
+		content.WriteString(event.Content)
+	}
+
+	if len(names) < 2 || names[0] != "connected" || names[len(names)-1] != "done" {
+		t.Errorf("Unexpected events %v", names)
+	}
+	if !strings.Contains(content.String(), "Hello from the agent") {
+		t.Errorf("Expected the streamed content, got %q", content.String())
+	}
+}
+
+func TestClient_ResumeStream(t *testing.T) {
+	server := newTestServer(t)
+	c := client.NewClient(server.URL, client.WithOrgID("org-1"))
+
+	events, err := c.Stream(context.Background(), &client.RunRequest{Input: "Hi", RunID: "run-1"})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	var ids []string
+	for event := range events {
+		if event.ID != "" {
+			ids = append(ids, event.ID)
+		}
+	}
+	if len(ids) < 2 {
+		t.Fatalf("Expected events with IDs, got %v", ids)
+	}
+
+	// Resuming after the first event replays the rest of the run
+	events, err = c.ResumeStream(context.Background(), "run-1", ids[0])
+	if err != nil {
+		t.Fatalf("ResumeStream failed: %v", err)
+	}
+	var resumed []string
+	for event := range events {
+		if event.ID != "" {
+			resumed = append(resumed, event.ID)
+		}
+	}
+	if strings.Join(resumed, ",") != strings.Join(ids[1:], ",") {
+		t.Errorf("Expected events %v, got %v", ids[1:], resumed)
+	}
+
+	var apiErr *client.APIError
+	if _, err := c.ResumeStream(context.Background(), "missing", ""); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a not found APIError, got %v", err)
+	}
+}
+
+func TestClient_Auth(t *testing.T) {
+	var gotKey, gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey, gotToken = r.Header.Get("X-API-Key"), r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid API key"})
+	}))
+	defer server.Close()
+
+	c := client.NewClient(server.URL, client.WithAPIKey("key-1"), client.WithBearerToken("token-1"))
+	_, err := c.Health(context.Background())
+
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "invalid API key" {
+		t.Errorf("Expected an unauthorized APIError, got %v", err)
+	}
+	if gotKey != "key-1" || gotToken != "Bearer token-1" {
+		t.Errorf("Expected credentials to be sent, got %q and %q", gotKey, gotToken)
+	}
+}
+
+// TestClient_MatchesOpenAPISpec checks that the client calls the operations
+// of the server's OpenAPI document
+func TestClient_MatchesOpenAPISpec(t *testing.T) {
+	server := newTestServer(t)
+	resp, err := http.Get(server.URL + "/openapi.json")
+	if err != nil {
+		t.Fatalf("Failed to get the OpenAPI document: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var spec struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatalf("Failed to decode the OpenAPI document: %v", err)
+	}
+
+	for path, method := range map[string]string{
+		"/health":                            "get",
+		"/api/v1/agent/metadata":             "get",
+		"/api/v1/agent/run":                  "post",
+		"/api/v1/agent/stream":               "post",
+		"/api/v1/agent/runs/{run_id}":        "get",
+		"/api/v1/agent/runs/{run_id}/cancel": "post",
+	} {
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("Expected %s %s in the OpenAPI document", strings.ToUpper(method), path)
+		}
+	}
+}
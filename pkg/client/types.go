@@ -0,0 +1,134 @@
+package client
+
+import (
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// RunRequest is the request of the run and stream endpoints
+type RunRequest struct {
+	Input          string            `json:"input"`
+	OrgID          string            `json:"org_id,omitempty"`
+	ConversationID string            `json:"conversation_id,omitempty"`
+	Context        map[string]string `json:"context,omitempty"`
+	MaxIterations  int               `json:"max_iterations,omitempty"`
+
+	// ContentParts holds images, audio or documents sent with the input.
+	// Files uploaded with CreateUpload are referenced by their StorageRef.
+	ContentParts []interfaces.ContentPart `json:"content_parts,omitempty"`
+
+	// Toolsets selects the toolsets of the agent exposed to the run. Nil
+	// exposes every toolset, and an empty slice none of them, which is why
+	// it is not omitted when empty.
+	Toolsets []string `json:"toolsets"`
+
+	// MaxOutputTokens and StopSequences override the output limits of the
+	// agent for the run. Zero values keep the agent's settings.
+	MaxOutputTokens int      `json:"max_output_tokens,omitempty"`
+	StopSequences   []string `json:"stop_sequences,omitempty"`
+
+	// RunID identifies the run for GetRun and CancelRun. The server
+	// generates one when empty.
+	RunID string `json:"run_id,omitempty"`
+}
+
+// RunResponse is the response of a run
+type RunResponse struct {
+	Output           string                      `json:"output"`
+	Agent            string                      `json:"agent"`
+	ExecutionSummary interfaces.ExecutionSummary `json:"execution_summary"`
+	RunID            string                      `json:"run_id"`
+	Usage            *interfaces.TokenUsage      `json:"usage,omitempty"`
+	AudioURL         string                      `json:"audio_url,omitempty"`
+	AudioMIMEType    string                      `json:"audio_mime_type,omitempty"`
+	Artifacts        []interfaces.Artifact       `json:"artifacts,omitempty"`
+}
+
+// StreamEvent is an event of a streamed run
+type StreamEvent struct {
+	// Event is the SSE event name: connected, content, thinking, tool_call,
+	// tool_result, error, complete or done
+	Event string `json:"-"`
+
+	// ID is the SSE event ID, if any
+	ID string `json:"-"`
+
+	Type         string                 `json:"type"`
+	Content      string                 `json:"content,omitempty"`
+	ThinkingStep string                 `json:"thinking_step,omitempty"`
+	ToolCall     *ToolCall              `json:"tool_call,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	IsFinal      bool                   `json:"is_final"`
+	Timestamp    int64                  `json:"timestamp"`
+}
+
+// ToolCall is the tool call of a stream event
+type ToolCall struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments,omitempty"`
+	Result    string `json:"result,omitempty"`
+	Status    string `json:"status"`
+}
+
+// Metadata describes the agent of a server
+type Metadata struct {
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	SupportsStreaming bool              `json:"supports_streaming"`
+	Capabilities      []string          `json:"capabilities"`
+	Endpoints         map[string]string `json:"endpoints"`
+}
+
+// Health is the health of a server
+type Health struct {
+	Status string `json:"status"`
+	Agent  string `json:"agent"`
+	Time   int64  `json:"time"`
+}
+
+// RunStatus describes a run
+type RunStatus struct {
+	ID              string     `json:"run_id"`
+	State           string     `json:"state"`
+	OrgID           string     `json:"org_id,omitempty"`
+	ConversationID  string     `json:"conversation_id,omitempty"`
+	Streaming       bool       `json:"streaming"`
+	CancelRequested bool       `json:"cancel_requested,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// Upload describes where to upload a file and how to reference it
+type Upload struct {
+	// StorageRef is set as the StorageRef of a content part once uploaded
+	StorageRef string `json:"storage_ref"`
+
+	// UploadURL is the signed URL to send the file to, with Method and Headers
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// ArtifactList is a page of the files produced in a conversation
+type ArtifactList struct {
+	ConversationID string                `json:"conversation_id"`
+	Artifacts      []interfaces.Artifact `json:"artifacts"`
+	Total          int                   `json:"total"`
+	Limit          int                   `json:"limit"`
+	Offset         int                   `json:"offset"`
+}
+
+// ArtifactListOptions filters and pages ListArtifacts
+type ArtifactListOptions struct {
+	// MimeType only lists files of this type, or of all types with this
+	// prefix when it ends in "/" such as "image/"
+	MimeType string
+
+	Limit  int
+	Offset int
+}
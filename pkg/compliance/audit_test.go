@@ -0,0 +1,256 @@
+package compliance
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+type stubLLM struct {
+	err error
+}
+
+func (l *stubLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return "plain", l.err
+}
+
+func (l *stubLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return "plain", l.err
+}
+
+func (l *stubLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return &interfaces.LLMResponse{
+		Content:    "detailed",
+		Model:      "stub-model",
+		StopReason: "stop",
+		Usage:      &interfaces.TokenUsage{InputTokens: 7, OutputTokens: 3, TotalTokens: 10},
+	}, nil
+}
+
+func (l *stubLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return l.GenerateDetailed(ctx, prompt, options...)
+}
+
+func (l *stubLLM) Name() string            { return "stub" }
+func (l *stubLLM) SupportsStreaming() bool { return false }
+
+func TestRecordingLLMRecordsCalls(t *testing.T) {
+	store := NewMemoryStore()
+	recorder := NewRecorder(store)
+	ctx, run := recorder.StartRun(multitenancy.WithOrgID(context.Background(), "org-a"), RunInfo{Input: "hi"})
+
+	llm := NewRecordingLLM(&stubLLM{})
+	if _, err := llm.GenerateDetailed(ctx, "first prompt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failing := NewRecordingLLM(&stubLLM{err: errors.New("overloaded")})
+	_, _ = failing.Generate(ctx, "second prompt")
+
+	// Calls outside a recorded run are not recorded
+	_, _ = llm.Generate(context.Background(), "ignored")
+
+	if err := run.Finish(ctx, "done", nil); err != nil {
+		t.Fatalf("failed to finish run: %v", err)
+	}
+	record, _ := store.Get(context.Background(), run.ID())
+	if len(record.LLMCalls) != 2 {
+		t.Fatalf("expected 2 LLM calls, got %+v", record.LLMCalls)
+	}
+	first := record.LLMCalls[0]
+	if first.Prompt != "first prompt" || first.Response != "detailed" || first.Model != "stub-model" || first.Usage.TotalTokens != 10 {
+		t.Errorf("unexpected first call: %+v", first)
+	}
+	if record.LLMCalls[1].Error != "overloaded" {
+		t.Errorf("expected the error of the second call, got %+v", record.LLMCalls[1])
+	}
+}
+
+func saveRun(t *testing.T, store Store, id, orgID, conversationID string, startedAt time.Time, tools []string, runErr string) {
+	t.Helper()
+
+	record := &RunRecord{
+		ID:             id,
+		OrgID:          orgID,
+		ConversationID: conversationID,
+		AgentName:      "support",
+		Input:          "input " + id,
+		Error:          runErr,
+		StartedAt:      startedAt,
+		CompletedAt:    startedAt.Add(time.Second),
+	}
+	for _, tool := range tools {
+		record.ToolCalls = append(record.ToolCalls, ToolCallRecord{Name: tool, StartedAt: startedAt})
+	}
+	if err := store.Save(context.Background(), record); err != nil {
+		t.Fatalf("failed to save run: %v", err)
+	}
+}
+
+func runIDs(records []*RunRecord) []string {
+	ids := []string{}
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+func testQueries(t *testing.T, store QueryableStore) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	saveRun(t, store, "run-1", "org-a", "conv-1", base, []string{"search"}, "")
+	saveRun(t, store, "run-2", "org-a", "conv-1", base.Add(time.Minute), []string{"search_web"}, "tool failed")
+	saveRun(t, store, "run-3", "org-a", "conv-2", base.Add(2*time.Minute), nil, "")
+	saveRun(t, store, "run-4", "org-b", "conv-1", base.Add(3*time.Minute), []string{"search"}, "")
+
+	tests := []struct {
+		name  string
+		query RunQuery
+		want  []string
+	}{
+		{"org", RunQuery{OrgID: "org-a"}, []string{"run-3", "run-2", "run-1"}},
+		{"conversation", RunQuery{OrgID: "org-a", ConversationID: "conv-1"}, []string{"run-2", "run-1"}},
+		{"tool", RunQuery{OrgID: "org-a", ToolName: "search"}, []string{"run-1"}},
+		{"errors", RunQuery{OrgID: "org-a", ErrorsOnly: true}, []string{"run-2"}},
+		{"time range", RunQuery{OrgID: "org-a", Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)}, []string{"run-2"}},
+		{"page", RunQuery{OrgID: "org-a", Limit: 1, Offset: 1}, []string{"run-2"}},
+		{"other org", RunQuery{OrgID: "org-c"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := store.Query(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+			if got := runIDs(records); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// Saving again replaces the record
+	saveRun(t, store, "run-1", "org-a", "conv-1", base, []string{"search"}, "retried")
+	record, err := store.Get(context.Background(), "run-1")
+	if err != nil || record.Error != "retried" || len(record.ToolCalls) != 1 {
+		t.Errorf("expected the replaced record, got %+v, %v", record, err)
+	}
+	if _, err := store.Get(context.Background(), "missing"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("expected ErrRunNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStoreQuery(t *testing.T) {
+	testQueries(t, NewMemoryStore())
+}
+
+func TestSQLStorePostgres(t *testing.T) {
+	dbURL := os.Getenv("POSTGRES_URL")
+	if dbURL == "" {
+		t.Skip("POSTGRES_URL environment variable not set")
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	table := "agent_runs_test"
+	_, _ = db.Exec("DROP TABLE IF EXISTS " + table)
+	defer func() { _, _ = db.Exec("DROP TABLE IF EXISTS " + table) }()
+
+	store, err := NewSQLStore(db, DialectPostgres, WithTable(table))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	testQueries(t, store)
+}
+
+func TestSQLStoreBuildQuery(t *testing.T) {
+	store, err := NewSQLStore(&sql.DB{}, DialectPostgres)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	query, args := store.buildQuery(RunQuery{OrgID: "org-a", ToolName: "web_search", ErrorsOnly: true, Limit: 5})
+	want := `SELECT record FROM agent_runs WHERE org_id = $1 AND tool_names LIKE $2 ESCAPE '\' AND failed = 1 ORDER BY started_at DESC LIMIT $3 OFFSET $4`
+	if query != want {
+		t.Errorf("unexpected query:\n%s\nwant:\n%s", query, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"org-a", `%,web\_search,%`, 5, 0}) {
+		t.Errorf("unexpected args %v", args)
+	}
+
+	sqlite, _ := NewSQLStore(&sql.DB{}, DialectSQLite)
+	if query, _ := sqlite.buildQuery(RunQuery{OrgID: "org-a"}); !strings.Contains(query, "org_id = ?") {
+		t.Errorf("expected ? placeholders for SQLite, got %s", query)
+	}
+
+	if _, err := NewSQLStore(&sql.DB{}, DialectPostgres, WithTable("runs; DROP TABLE x")); err == nil {
+		t.Error("expected an invalid table name to be rejected")
+	}
+	if _, err := NewSQLStore(&sql.DB{}, "mysql"); err == nil {
+		t.Error("expected an unsupported dialect to be rejected")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	store := NewMemoryStore()
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	saveRun(t, store, "run-1", "org-a", "conv-1", base, nil, "")
+	saveRun(t, store, "run-2", "org-a", "conv-2", base.Add(time.Minute), nil, "")
+	saveRun(t, store, "run-3", "org-b", "conv-1", base, nil, "")
+
+	handler := http.StripPrefix("/runs", NewHandler(store))
+	serve := func(orgID, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if orgID != "" {
+			req = req.WithContext(multitenancy.WithOrgID(req.Context(), orgID))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("org-a", "/runs?conversation_id=conv-1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var list RunList
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if got := runIDs(list.Runs); !reflect.DeepEqual(got, []string{"run-1"}) {
+		t.Errorf("expected [run-1], got %v", got)
+	}
+
+	if w := serve("org-a", "/runs/run-2"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"id":"run-2"`) {
+		t.Errorf("expected run-2, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve("org-a", "/runs/run-3"); w.Code != http.StatusNotFound {
+		t.Errorf("expected another org's run to be hidden, got %d", w.Code)
+	}
+	if w := serve("", "/runs"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without an organization, got %d", w.Code)
+	}
+	if w := serve("org-a", "/runs?since=yesterday"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid timestamp, got %d", w.Code)
+	}
+}
@@ -0,0 +1,145 @@
+package compliance
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func recordRun(t *testing.T, recorder *Recorder, orgID string) string {
+	t.Helper()
+
+	ctx := multitenancy.WithOrgID(context.Background(), orgID)
+	ctx = memory.WithConversationID(ctx, "conv-1")
+	mem := memory.NewConversationBuffer()
+	_ = mem.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "draw a cat"})
+
+	ctx, run := recorder.StartRun(ctx, RunInfo{
+		AgentName:    "artist",
+		SystemPrompt: "You draw things",
+		Input:        "draw a cat",
+		Memory:       mem,
+	})
+
+	RecordToolCall(ctx, "image_gen", `{"prompt":"cat"}`, "ok", nil, time.Now())
+	RecordToolCall(ctx, "upload", `{}`, "", fmt.Errorf("quota exceeded"), time.Now())
+	AddArtifact(ctx, Artifact{Name: "../cat.png", ContentType: "image/png", ToolName: "image_gen", Data: []byte("PNGDATA")})
+	run.SetUsage(&interfaces.TokenUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}, nil, "test-model")
+
+	if err := run.Finish(ctx, "Here is your cat", nil); err != nil {
+		t.Fatalf("failed to finish run: %v", err)
+	}
+	return run.ID()
+}
+
+func TestRecorderCapturesRun(t *testing.T) {
+	store := NewMemoryStore()
+	recorder := NewRecorder(store, WithConfigVersion("v42"))
+	runID := recordRun(t, recorder, "org-a")
+
+	record, err := store.Get(context.Background(), runID)
+	if err != nil {
+		t.Fatalf("failed to get run: %v", err)
+	}
+	if record.OrgID != "org-a" || record.ConversationID != "conv-1" || record.ConfigVersion != "v42" {
+		t.Errorf("unexpected run identity: %+v", record)
+	}
+	if record.Output != "Here is your cat" || record.Model != "test-model" || record.Usage.TotalTokens != 15 {
+		t.Errorf("unexpected run result: %+v", record)
+	}
+	if len(record.ToolCalls) != 2 || record.ToolCalls[1].Error != "quota exceeded" {
+		t.Errorf("unexpected tool calls: %+v", record.ToolCalls)
+	}
+	if len(record.Artifacts) != 1 || len(record.Messages) != 1 {
+		t.Errorf("expected 1 artifact and 1 message, got %d and %d", len(record.Artifacts), len(record.Messages))
+	}
+}
+
+func TestRecordToolCallWithoutRun(t *testing.T) {
+	// Must be a no-op outside of a recorded run
+	RecordToolCall(context.Background(), "tool", "{}", "", nil, time.Now())
+	AddArtifact(context.Background(), Artifact{Name: "x"})
+}
+
+func TestExportJSONBundle(t *testing.T) {
+	store := NewMemoryStore()
+	runID := recordRun(t, NewRecorder(store), "org-a")
+	signer := NewHMACSigner([]byte("secret"))
+	exporter := NewExporter(store, signer)
+
+	data, err := exporter.ExportJSON(multitenancy.WithOrgID(context.Background(), "org-a"), runID)
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	record, err := VerifyJSONBundle(data, signer)
+	if err != nil {
+		t.Fatalf("failed to verify bundle: %v", err)
+	}
+	if record.ID != runID || record.Input != "draw a cat" {
+		t.Errorf("unexpected record in bundle: %+v", record)
+	}
+
+	tampered := bytes.Replace(data, []byte("Here is your cat"), []byte("Here is your dog"), 1)
+	if _, err := VerifyJSONBundle(tampered, signer); err == nil {
+		t.Error("expected tampered bundle to fail verification")
+	}
+	if _, err := VerifyJSONBundle(data, NewHMACSigner([]byte("other"))); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected invalid signature with the wrong key, got %v", err)
+	}
+}
+
+func TestExportTarball(t *testing.T) {
+	store := NewMemoryStore()
+	runID := recordRun(t, NewRecorder(store), "org-a")
+
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	exporter := NewExporter(store, NewEd25519Signer(privateKey))
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTarball(multitenancy.WithOrgID(context.Background(), "org-a"), runID, &buf); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	verifier := NewEd25519Verifier(privateKey.Public().(ed25519.PublicKey))
+	record, err := VerifyTarball(bytes.NewReader(buf.Bytes()), verifier)
+	if err != nil {
+		t.Fatalf("failed to verify tarball: %v", err)
+	}
+	if record.ID != runID {
+		t.Errorf("expected run %s, got %s", runID, record.ID)
+	}
+	if _, err := verifier.Sign([]byte("x")); err == nil {
+		t.Error("expected a verify-only signer to refuse signing")
+	}
+}
+
+func TestExportIsOrgScoped(t *testing.T) {
+	store := NewMemoryStore()
+	runID := recordRun(t, NewRecorder(store), "org-a")
+	exporter := NewExporter(store, NewHMACSigner([]byte("secret")))
+
+	if _, err := exporter.ExportJSON(multitenancy.WithOrgID(context.Background(), "org-b"), runID); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("expected access denied for another org, got %v", err)
+	}
+	if _, err := exporter.ExportJSON(context.Background(), runID); err == nil || !strings.Contains(err.Error(), "organization") {
+		t.Errorf("expected an error without an organization, got %v", err)
+	}
+
+	runs, err := exporter.ListRuns(multitenancy.WithOrgID(context.Background(), "org-b"))
+	if err != nil || len(runs) != 0 {
+		t.Errorf("expected no runs for org-b, got %d (err: %v)", len(runs), err)
+	}
+}
@@ -0,0 +1,313 @@
+package compliance
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// BundleVersion is the version of the bundle format
+const BundleVersion = 1
+
+// ErrAccessDenied is returned when a run belongs to another organization
+var ErrAccessDenied = errors.New("access denied: run belongs to another organization")
+
+// Bundle file names
+const (
+	manifestFile  = "manifest.json"
+	runFile       = "run.json"
+	signatureFile = "signature"
+	artifactsDir  = "artifacts"
+)
+
+// Manifest describes the contents of a bundle. The signature covers the
+// manifest bytes, and the manifest covers every file through its SHA-256.
+type Manifest struct {
+	Version   int               `json:"version"`
+	RunID     string            `json:"run_id"`
+	OrgID     string            `json:"org_id"`
+	CreatedAt time.Time         `json:"created_at"`
+	Algorithm string            `json:"algorithm"`
+	Files     map[string]string `json:"files"` // file name -> hex SHA-256
+}
+
+// JSONBundle is the single-document export format. Manifest and Run are kept
+// as raw JSON so their signed bytes can be recovered exactly when verifying.
+type JSONBundle struct {
+	Manifest  json.RawMessage `json:"manifest"`
+	Run       json.RawMessage `json:"run"`
+	Signature string          `json:"signature"` // base64
+}
+
+// Exporter packages run records into signed bundles. Access is scoped to the
+// organization in the request context: a run can only be exported by its own org.
+type Exporter struct {
+	store  Store
+	signer Signer
+}
+
+// NewExporter creates a new exporter
+func NewExporter(store Store, signer Signer) *Exporter {
+	return &Exporter{store: store, signer: signer}
+}
+
+// ListRuns returns the runs of the organization in ctx
+func (e *Exporter) ListRuns(ctx context.Context) ([]*RunRecord, error) {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("organization is required: %w", err)
+	}
+	return e.store.List(ctx, orgID)
+}
+
+// ExportJSON exports a run as a signed JSON bundle
+func (e *Exporter) ExportJSON(ctx context.Context, runID string) ([]byte, error) {
+	files, manifest, signature, err := e.build(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(JSONBundle{
+		Manifest:  manifest,
+		Run:       files[runFile],
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	}, "", "  ")
+}
+
+// ExportTarball writes a run as a signed gzip-compressed tarball containing
+// manifest.json, run.json, signature and the raw artifacts
+func (e *Exporter) ExportTarball(ctx context.Context, runID string, w io.Writer) error {
+	files, manifest, signature, err := e.build(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	entries := []struct {
+		name string
+		data []byte
+	}{
+		{manifestFile, manifest},
+		{signatureFile, []byte(base64.StdEncoding.EncodeToString(signature))},
+		{runFile, files[runFile]},
+	}
+	for name, data := range files {
+		if name != runFile {
+			entries = append(entries, struct {
+				name string
+				data []byte
+			}{name, data})
+		}
+	}
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:    entry.name,
+			Mode:    0o644,
+			Size:    int64(len(entry.data)),
+			ModTime: time.Now().UTC(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", entry.name, err)
+		}
+		if _, err := tw.Write(entry.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", entry.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize tarball: %w", err)
+	}
+	return gz.Close()
+}
+
+// build loads the run after checking org access and returns its files, the
+// manifest and the manifest signature
+func (e *Exporter) build(ctx context.Context, runID string) (map[string][]byte, []byte, []byte, error) {
+	if e.signer == nil {
+		return nil, nil, nil, fmt.Errorf("a signer is required to export bundles")
+	}
+
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("organization is required: %w", err)
+	}
+
+	record, err := e.store.Get(ctx, runID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if record.OrgID != orgID {
+		return nil, nil, nil, ErrAccessDenied
+	}
+
+	runJSON, err := json.Marshal(record)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to encode run: %w", err)
+	}
+
+	files := map[string][]byte{runFile: runJSON}
+	for i, artifact := range record.Artifacts {
+		name := path.Join(artifactsDir, fmt.Sprintf("%03d-%s", i+1, sanitizeName(artifact.Name)))
+		files[name] = artifact.Data
+	}
+
+	hashes := make(map[string]string, len(files))
+	for name, data := range files {
+		hashes[name] = sha256Hex(data)
+	}
+
+	manifest, err := json.Marshal(Manifest{
+		Version:   BundleVersion,
+		RunID:     record.ID,
+		OrgID:     record.OrgID,
+		CreatedAt: time.Now().UTC(),
+		Algorithm: e.signer.Algorithm(),
+		Files:     hashes,
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	signature, err := e.signer.Sign(manifest)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to sign bundle: %w", err)
+	}
+
+	return files, manifest, signature, nil
+}
+
+// VerifyJSONBundle checks the signature and file hashes of a JSON bundle and
+// returns the run record it contains
+func VerifyJSONBundle(data []byte, verifier Signer) (*RunRecord, error) {
+	var bundle JSONBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(bundle.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	// The bundle is indented for readability; the signed bytes are the compact
+	// encoding. Artifacts are embedded in run.json rather than stored as files.
+	return verify(compactJSON(bundle.Manifest), signature, map[string][]byte{runFile: compactJSON(bundle.Run)}, verifier, false)
+}
+
+// VerifyTarball checks the signature and file hashes of a tarball bundle and
+// returns the run record it contains
+func VerifyTarball(r io.Reader, verifier Signer) (*RunRecord, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		files[header.Name] = data
+	}
+
+	manifest, ok := files[manifestFile]
+	if !ok {
+		return nil, fmt.Errorf("bundle has no manifest")
+	}
+	signature, err := base64.StdEncoding.DecodeString(string(files[signatureFile]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	delete(files, manifestFile)
+	delete(files, signatureFile)
+
+	return verify(manifest, signature, files, verifier, true)
+}
+
+func verify(manifestJSON, signature []byte, files map[string][]byte, verifier Signer, requireAllFiles bool) (*RunRecord, error) {
+	if err := verifier.Verify(manifestJSON, signature); err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	for name, hash := range manifest.Files {
+		data, ok := files[name]
+		if !ok {
+			if requireAllFiles {
+				return nil, fmt.Errorf("bundle is missing %s", name)
+			}
+			continue
+		}
+		if sha256Hex(data) != hash {
+			return nil, fmt.Errorf("%s does not match the manifest hash", name)
+		}
+	}
+	for name := range files {
+		if _, ok := manifest.Files[name]; !ok {
+			return nil, fmt.Errorf("bundle contains unexpected file %s", name)
+		}
+	}
+
+	var record RunRecord
+	if err := json.Unmarshal(files[runFile], &record); err != nil {
+		return nil, fmt.Errorf("failed to parse run: %w", err)
+	}
+	return &record, nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func compactJSON(data []byte) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
+
+// sanitizeName keeps artifact names safe to use as tar entry names
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimLeft(name, ".")
+	if name == "" {
+		return "artifact"
+	}
+	return name
+}
@@ -0,0 +1,137 @@
+package compliance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// RunList is the response of the run query endpoint
+type RunList struct {
+	Runs   []*RunRecord `json:"runs"`
+	Count  int          `json:"count"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+}
+
+// Handler serves the run records of a store over HTTP. Mount it with
+// http.StripPrefix; relative to its prefix it serves:
+//
+//	GET /?conversation_id=&agent=&tool=&errors=true&since=&until=&limit=&offset=
+//	GET /{run_id}
+//
+// since and until are RFC 3339 timestamps. Results are scoped to the
+// organization in the request context, so the handler must run behind
+// authentication that sets it (see multitenancy.WithOrgID).
+type Handler struct {
+	store QueryableStore
+}
+
+// NewHandler creates an HTTP handler for the runs of store
+func NewHandler(store QueryableStore) *Handler {
+	return &Handler{store: store}
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	orgID, err := multitenancy.GetOrgID(r.Context())
+	if err != nil || orgID == "" {
+		http.Error(w, "Organization is required", http.StatusBadRequest)
+		return
+	}
+
+	runID := strings.Trim(r.URL.Path, "/")
+	if runID == "" {
+		h.handleQuery(w, r, orgID)
+		return
+	}
+	if strings.Contains(runID, "/") {
+		http.Error(w, "Invalid run ID", http.StatusBadRequest)
+		return
+	}
+
+	record, err := h.store.Get(r.Context(), runID)
+	switch {
+	case errors.Is(err, ErrRunNotFound):
+		http.Error(w, "Run not found", http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, fmt.Sprintf("Failed to get run: %v", err), http.StatusInternalServerError)
+		return
+	case record.OrgID != orgID:
+		// Do not reveal that the run exists in another organization
+		http.Error(w, "Run not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, record)
+}
+
+func (h *Handler) handleQuery(w http.ResponseWriter, r *http.Request, orgID string) {
+	q, err := parseRunQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q.OrgID = orgID
+
+	runs, err := h.store.Query(r.Context(), q)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to query runs: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, RunList{Runs: runs, Count: len(runs), Limit: q.limit(), Offset: q.Offset})
+}
+
+func parseRunQuery(values url.Values) (RunQuery, error) {
+	q := RunQuery{
+		ConversationID: values.Get("conversation_id"),
+		AgentName:      values.Get("agent"),
+		ToolName:       values.Get("tool"),
+	}
+
+	if v := values.Get("errors"); v != "" {
+		errorsOnly, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, fmt.Errorf("invalid errors parameter %q", v)
+		}
+		q.ErrorsOnly = errorsOnly
+	}
+	for name, target := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if v := values.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s parameter %q (use RFC 3339)", name, v)
+			}
+			*target = t
+		}
+	}
+	for name, target := range map[string]*int{"limit": &q.Limit, "offset": &q.Offset} {
+		if v := values.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return q, fmt.Errorf("invalid %s parameter %q", name, v)
+			}
+			*target = n
+		}
+	}
+	return q, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
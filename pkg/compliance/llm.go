@@ -0,0 +1,173 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// RecordingLLM implements middleware that records every LLM call on the run
+// in the request context. Calls made outside a recorded run pass through.
+type RecordingLLM struct {
+	llm interfaces.LLM
+}
+
+// NewRecordingLLM creates an LLM middleware that records calls on the run in ctx
+func NewRecordingLLM(llm interfaces.LLM) *RecordingLLM {
+	return &RecordingLLM{llm: llm}
+}
+
+// Generate implements interfaces.LLM.Generate
+func (m *RecordingLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	startedAt := time.Now().UTC()
+	response, err := m.llm.Generate(ctx, prompt, options...)
+	m.record(ctx, prompt, nil, &interfaces.LLMResponse{Content: response}, startedAt, err)
+	return response, err
+}
+
+// GenerateWithTools implements interfaces.LLM.GenerateWithTools
+func (m *RecordingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	startedAt := time.Now().UTC()
+	response, err := m.llm.GenerateWithTools(ctx, prompt, tools, options...)
+	m.record(ctx, prompt, tools, &interfaces.LLMResponse{Content: response}, startedAt, err)
+	return response, err
+}
+
+// GenerateDetailed implements interfaces.LLM.GenerateDetailed
+func (m *RecordingLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	startedAt := time.Now().UTC()
+	response, err := m.llm.GenerateDetailed(ctx, prompt, options...)
+	m.record(ctx, prompt, nil, response, startedAt, err)
+	return response, err
+}
+
+// GenerateWithToolsDetailed implements interfaces.LLM.GenerateWithToolsDetailed
+func (m *RecordingLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	startedAt := time.Now().UTC()
+	response, err := m.llm.GenerateWithToolsDetailed(ctx, prompt, tools, options...)
+	m.record(ctx, prompt, tools, response, startedAt, err)
+	return response, err
+}
+
+// GenerateStream implements interfaces.StreamingLLM.GenerateStream. The call
+// is recorded when the stream ends.
+func (m *RecordingLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	streamingLLM, ok := m.llm.(interfaces.StreamingLLM)
+	if !ok {
+		return nil, fmt.Errorf("underlying LLM does not support streaming")
+	}
+
+	startedAt := time.Now().UTC()
+	events, err := streamingLLM.GenerateStream(ctx, prompt, options...)
+	if err != nil {
+		m.record(ctx, prompt, nil, nil, startedAt, err)
+		return nil, err
+	}
+	return m.recordStream(ctx, prompt, nil, events, startedAt), nil
+}
+
+// GenerateWithToolsStream implements interfaces.StreamingLLM.GenerateWithToolsStream.
+// The call is recorded when the stream ends.
+func (m *RecordingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	streamingLLM, ok := m.llm.(interfaces.StreamingLLM)
+	if !ok {
+		return nil, fmt.Errorf("underlying LLM does not support streaming")
+	}
+
+	startedAt := time.Now().UTC()
+	events, err := streamingLLM.GenerateWithToolsStream(ctx, prompt, tools, options...)
+	if err != nil {
+		m.record(ctx, prompt, tools, nil, startedAt, err)
+		return nil, err
+	}
+	return m.recordStream(ctx, prompt, tools, events, startedAt), nil
+}
+
+// Name implements interfaces.LLM.Name
+func (m *RecordingLLM) Name() string {
+	return m.llm.Name()
+}
+
+// SupportsStreaming implements interfaces.LLM.SupportsStreaming
+func (m *RecordingLLM) SupportsStreaming() bool {
+	return m.llm.SupportsStreaming()
+}
+
+// GetModel returns the model name from the underlying LLM
+func (m *RecordingLLM) GetModel() string {
+	if modelProvider, ok := m.llm.(interface{ GetModel() string }); ok {
+		return modelProvider.GetModel()
+	}
+	return m.llm.Name()
+}
+
+// Unwrap returns the underlying LLM, e.g. to reach the interfaces it
+// implements that the middleware does not forward
+func (m *RecordingLLM) Unwrap() interfaces.LLM {
+	return m.llm
+}
+
+// WarmConnections implements interfaces.ConnectionWarmer when the underlying LLM does
+func (m *RecordingLLM) WarmConnections(ctx context.Context) error {
+	if warmer, ok := m.llm.(interfaces.ConnectionWarmer); ok {
+		return warmer.WarmConnections(ctx)
+	}
+	return nil
+}
+
+func (m *RecordingLLM) recordStream(ctx context.Context, prompt string, tools []interfaces.Tool, events <-chan interfaces.StreamEvent, startedAt time.Time) <-chan interfaces.StreamEvent {
+	if RunFromContext(ctx) == nil {
+		return events
+	}
+
+	out := make(chan interfaces.StreamEvent, cap(events))
+	go func() {
+		defer close(out)
+
+		var content strings.Builder
+		var streamErr error
+		for event := range events {
+			switch event.Type {
+			case interfaces.StreamEventContentDelta:
+				content.WriteString(event.Content)
+			case interfaces.StreamEventError:
+				streamErr = event.Error
+			}
+			out <- event
+		}
+
+		m.record(ctx, prompt, tools, &interfaces.LLMResponse{Content: content.String()}, startedAt, streamErr)
+	}()
+	return out
+}
+
+func (m *RecordingLLM) record(ctx context.Context, prompt string, tools []interfaces.Tool, response *interfaces.LLMResponse, startedAt time.Time, err error) {
+	if RunFromContext(ctx) == nil {
+		return
+	}
+
+	call := LLMCallRecord{
+		Model:      m.GetModel(),
+		Prompt:     prompt,
+		StartedAt:  startedAt,
+		DurationMs: time.Since(startedAt).Milliseconds(),
+	}
+	for _, tool := range tools {
+		call.Tools = append(call.Tools, tool.Name())
+	}
+	if response != nil {
+		call.Response = response.Content
+		call.FinishReason = response.StopReason
+		call.Usage = response.Usage
+		if response.Model != "" {
+			call.Model = response.Model
+		}
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	RecordLLMCall(ctx, call)
+}
@@ -0,0 +1,102 @@
+package compliance
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// defaultQueryLimit is the number of runs returned by a query without a limit
+const defaultQueryLimit = 100
+
+// RunQuery selects run records. Empty fields match every run; OrgID is
+// required.
+type RunQuery struct {
+	OrgID          string    `json:"org_id"`
+	ConversationID string    `json:"conversation_id,omitempty"`
+	AgentName      string    `json:"agent_name,omitempty"`
+	ToolName       string    `json:"tool_name,omitempty"`   // Runs that called this tool
+	ErrorsOnly     bool      `json:"errors_only,omitempty"` // Runs that failed
+	Since          time.Time `json:"since,omitempty"`       // Runs started at or after
+	Until          time.Time `json:"until,omitempty"`       // Runs started before
+	Limit          int       `json:"limit,omitempty"`       // Default 100
+	Offset         int       `json:"offset,omitempty"`
+}
+
+// QueryableStore is a Store that can search run records
+type QueryableStore interface {
+	Store
+
+	// Query returns the runs matching q, newest first
+	Query(ctx context.Context, q RunQuery) ([]*RunRecord, error)
+}
+
+// Matches returns true if record matches the query filters (ignoring limit
+// and offset)
+func (q RunQuery) Matches(record *RunRecord) bool {
+	if record.OrgID != q.OrgID {
+		return false
+	}
+	if q.ConversationID != "" && record.ConversationID != q.ConversationID {
+		return false
+	}
+	if q.AgentName != "" && record.AgentName != q.AgentName {
+		return false
+	}
+	if q.ErrorsOnly && record.Error == "" {
+		return false
+	}
+	if !q.Since.IsZero() && record.StartedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !record.StartedAt.Before(q.Until) {
+		return false
+	}
+	if q.ToolName != "" && !contains(record.toolNames(), q.ToolName) {
+		return false
+	}
+	return true
+}
+
+func (q RunQuery) limit() int {
+	if q.Limit <= 0 {
+		return defaultQueryLimit
+	}
+	return q.Limit
+}
+
+// Query implements QueryableStore.Query
+func (s *MemoryStore) Query(ctx context.Context, q RunQuery) ([]*RunRecord, error) {
+	s.mu.RLock()
+	var records []*RunRecord
+	for _, record := range s.records {
+		if q.Matches(record) {
+			records = append(records, record)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartedAt.After(records[j].StartedAt)
+	})
+
+	if q.Offset >= len(records) {
+		return []*RunRecord{}, nil
+	}
+	records = records[q.Offset:]
+	if len(records) > q.limit() {
+		records = records[:q.limit()]
+	}
+	return records, nil
+}
+
+// toolNames returns the distinct names of the tools called during the run
+func (r *RunRecord) toolNames() []string {
+	var names []string
+	for _, call := range r.ToolCalls {
+		if !contains(names, call.Name) {
+			names = append(names, call.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,265 @@
+// Package compliance records everything about agent runs (prompts, responses,
+// tool I/O, artifacts, configuration version, usage and trace IDs) and exports
+// them as signed bundles for legal and compliance requests.
+package compliance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RunRecord is the complete record of a single agent run
+type RunRecord struct {
+	ID             string `json:"id"`
+	OrgID          string `json:"org_id"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	AgentName      string `json:"agent_name,omitempty"`
+	Model          string `json:"model,omitempty"`
+	ConfigVersion  string `json:"config_version,omitempty"`
+	Streaming      bool   `json:"streaming,omitempty"`
+
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	Input        string `json:"input"`
+	Output       string `json:"output"`
+	Error        string `json:"error,omitempty"`
+
+	// Messages is the conversation history from memory at the end of the run
+	Messages    []interfaces.Message `json:"messages,omitempty"`
+	LLMCalls    []LLMCallRecord      `json:"llm_calls,omitempty"`
+	ToolCalls   []ToolCallRecord     `json:"tool_calls,omitempty"`
+	Artifacts   []Artifact           `json:"artifacts,omitempty"`
+	AuditEvents []AuditEvent         `json:"audit_events,omitempty"`
+
+	Usage            *interfaces.TokenUsage       `json:"usage,omitempty"`
+	ExecutionSummary *interfaces.ExecutionSummary `json:"execution_summary,omitempty"`
+	TraceIDs         []string                     `json:"trace_ids,omitempty"`
+
+	StartedAt   time.Time              `json:"started_at"`
+	CompletedAt time.Time              `json:"completed_at"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// LLMCallRecord is the prompt, response and token usage of a single LLM call
+type LLMCallRecord struct {
+	Model        string                 `json:"model,omitempty"`
+	Prompt       string                 `json:"prompt"`
+	Response     string                 `json:"response"`
+	Tools        []string               `json:"tools,omitempty"`
+	FinishReason string                 `json:"finish_reason,omitempty"`
+	Usage        *interfaces.TokenUsage `json:"usage,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	StartedAt    time.Time              `json:"started_at"`
+	DurationMs   int64                  `json:"duration_ms"`
+}
+
+// ToolCallRecord is the input and output of a single tool invocation
+type ToolCallRecord struct {
+	Name       string    `json:"name"`
+	Arguments  string    `json:"arguments"`
+	Result     string    `json:"result"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Artifact is a file or binary output produced during a run
+type Artifact struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type,omitempty"`
+	ToolName    string `json:"tool_name,omitempty"`
+	Data        []byte `json:"data"`
+}
+
+// AuditEvent is a security-relevant event that occurred during a run, such
+// as a guardrail stripping injected instructions from a tool output
+type AuditEvent struct {
+	Type      string                 `json:"type"`
+	Source    string                 `json:"source,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// Run is an in-progress run being recorded. It is safe for concurrent use.
+type Run struct {
+	mu       sync.Mutex
+	record   RunRecord
+	recorder *Recorder
+	memory   interfaces.Memory
+}
+
+type runContextKey struct{}
+
+// WithRun adds an active run to the context
+func WithRun(ctx context.Context, run *Run) context.Context {
+	return context.WithValue(ctx, runContextKey{}, run)
+}
+
+// RunFromContext returns the active run from the context, or nil
+func RunFromContext(ctx context.Context) *Run {
+	run, _ := ctx.Value(runContextKey{}).(*Run)
+	return run
+}
+
+// ID returns the run ID
+func (r *Run) ID() string {
+	return r.record.ID
+}
+
+// RecordLLMCall records an LLM call. StartedAt is set to now when zero and
+// DurationMs is computed from StartedAt when zero.
+func (r *Run) RecordLLMCall(call LLMCallRecord) {
+	if call.StartedAt.IsZero() {
+		call.StartedAt = time.Now().UTC()
+	}
+	if call.DurationMs == 0 {
+		call.DurationMs = time.Since(call.StartedAt).Milliseconds()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.record.LLMCalls = append(r.record.LLMCalls, call)
+}
+
+// RecordToolCall records a tool invocation
+func (r *Run) RecordToolCall(name, arguments, result string, err error, startedAt time.Time) {
+	call := ToolCallRecord{
+		Name:       name,
+		Arguments:  arguments,
+		Result:     result,
+		StartedAt:  startedAt,
+		DurationMs: time.Since(startedAt).Milliseconds(),
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.record.ToolCalls = append(r.record.ToolCalls, call)
+}
+
+// AddArtifact attaches an artifact to the run
+func (r *Run) AddArtifact(artifact Artifact) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.record.Artifacts = append(r.record.Artifacts, artifact)
+}
+
+// RecordAuditEvent records an audit event
+func (r *Run) RecordAuditEvent(event AuditEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.record.AuditEvents = append(r.record.AuditEvents, event)
+}
+
+// SetUsage records token usage, the execution summary and the model used
+func (r *Run) SetUsage(usage *interfaces.TokenUsage, summary *interfaces.ExecutionSummary, model string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.record.Usage = usage
+	r.record.ExecutionSummary = summary
+	if model != "" {
+		r.record.Model = model
+	}
+}
+
+// SetMetadata sets a metadata value on the run record
+func (r *Run) SetMetadata(key string, value interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.record.Metadata == nil {
+		r.record.Metadata = make(map[string]interface{})
+	}
+	r.record.Metadata[key] = value
+}
+
+// AddTraceIDs records the trace IDs found in ctx (SDK trace ID and OpenTelemetry span context)
+func (r *Run) AddTraceIDs(ctx context.Context) {
+	var ids []string
+	if id, ok := tracing.GetTraceID(ctx); ok && id != "" {
+		ids = append(ids, id)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		ids = append(ids, sc.TraceID().String())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		if !contains(r.record.TraceIDs, id) {
+			r.record.TraceIDs = append(r.record.TraceIDs, id)
+		}
+	}
+}
+
+// Finish completes the run and saves its record to the recorder's store
+func (r *Run) Finish(ctx context.Context, output string, runErr error) error {
+	r.AddTraceIDs(ctx)
+
+	var messages []interfaces.Message
+	if r.memory != nil && r.recorder.includeMessages {
+		// Best effort: the record is still saved when history is unavailable
+		messages, _ = r.memory.GetMessages(ctx)
+	}
+
+	r.mu.Lock()
+	r.record.Output = output
+	if runErr != nil {
+		r.record.Error = runErr.Error()
+	}
+	r.record.Messages = messages
+	r.record.CompletedAt = time.Now().UTC()
+	record := r.record
+	r.mu.Unlock()
+
+	return r.recorder.store.Save(ctx, &record)
+}
+
+// RecordLLMCall records an LLM call on the run in ctx, if any
+func RecordLLMCall(ctx context.Context, call LLMCallRecord) {
+	if run := RunFromContext(ctx); run != nil {
+		run.AddTraceIDs(ctx)
+		run.RecordLLMCall(call)
+	}
+}
+
+// RecordToolCall records a tool invocation on the run in ctx, if any
+func RecordToolCall(ctx context.Context, name, arguments, result string, err error, startedAt time.Time) {
+	if run := RunFromContext(ctx); run != nil {
+		run.AddTraceIDs(ctx)
+		run.RecordToolCall(name, arguments, result, err, startedAt)
+	}
+}
+
+// RecordAuditEvent records an audit event on the run in ctx, if any
+func RecordAuditEvent(ctx context.Context, event AuditEvent) {
+	if run := RunFromContext(ctx); run != nil {
+		run.RecordAuditEvent(event)
+	}
+}
+
+// AddArtifact attaches an artifact to the run in ctx, if any. Tools use it to
+// include generated files (images, documents, ...) in compliance exports.
+func AddArtifact(ctx context.Context, artifact Artifact) {
+	if run := RunFromContext(ctx); run != nil {
+		run.AddArtifact(artifact)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
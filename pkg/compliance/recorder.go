@@ -0,0 +1,165 @@
+package compliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// ErrRunNotFound is returned when a run record does not exist
+var ErrRunNotFound = errors.New("run not found")
+
+// Store persists run records
+type Store interface {
+	// Save stores a run record, replacing any record with the same ID
+	Save(ctx context.Context, record *RunRecord) error
+
+	// Get returns the run record with the given ID
+	Get(ctx context.Context, runID string) (*RunRecord, error)
+
+	// List returns the run records of an organization, newest first
+	List(ctx context.Context, orgID string) ([]*RunRecord, error)
+}
+
+// MemoryStore is an in-memory Store
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[string]*RunRecord
+}
+
+// NewMemoryStore creates a new in-memory run store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]*RunRecord)}
+}
+
+// Save implements Store.Save
+func (s *MemoryStore) Save(ctx context.Context, record *RunRecord) error {
+	if record == nil || record.ID == "" {
+		return fmt.Errorf("run record must have an ID")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.ID] = record
+	return nil
+}
+
+// Get implements Store.Get
+func (s *MemoryStore) Get(ctx context.Context, runID string) (*RunRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.records[runID]
+	if !ok {
+		return nil, ErrRunNotFound
+	}
+	return record, nil
+}
+
+// List implements Store.List
+func (s *MemoryStore) List(ctx context.Context, orgID string) ([]*RunRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var records []*RunRecord
+	for _, record := range s.records {
+		if record.OrgID == orgID {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartedAt.After(records[j].StartedAt)
+	})
+	return records, nil
+}
+
+// Recorder starts and saves run records
+type Recorder struct {
+	store           Store
+	configVersion   string
+	includeMessages bool
+}
+
+// RecorderOption represents an option for configuring the recorder
+type RecorderOption func(*Recorder)
+
+// WithConfigVersion sets the agent configuration version stored with every run
+func WithConfigVersion(version string) RecorderOption {
+	return func(r *Recorder) {
+		r.configVersion = version
+	}
+}
+
+// WithConversationHistory controls whether the conversation history from
+// memory is included in run records (default: true)
+func WithConversationHistory(include bool) RecorderOption {
+	return func(r *Recorder) {
+		r.includeMessages = include
+	}
+}
+
+// NewRecorder creates a new run recorder backed by store
+func NewRecorder(store Store, options ...RecorderOption) *Recorder {
+	r := &Recorder{
+		store:           store,
+		includeMessages: true,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Store returns the recorder's run store
+func (r *Recorder) Store() Store {
+	return r.store
+}
+
+// RunInfo describes a run when it starts
+type RunInfo struct {
+	AgentName     string
+	SystemPrompt  string
+	Input         string
+	Model         string
+	ConfigVersion string // Used when the recorder has no configured version
+	Streaming     bool
+	Memory        interfaces.Memory
+}
+
+// StartRun begins recording a run and returns a context carrying it. The
+// organization and conversation are taken from ctx.
+func (r *Recorder) StartRun(ctx context.Context, info RunInfo) (context.Context, *Run) {
+	orgID, _ := multitenancy.GetOrgID(ctx)
+	conversationID, _ := memory.GetConversationID(ctx)
+
+	configVersion := r.configVersion
+	if configVersion == "" {
+		configVersion = info.ConfigVersion
+	}
+
+	run := &Run{
+		recorder: r,
+		memory:   info.Memory,
+		record: RunRecord{
+			ID:             uuid.New().String(),
+			OrgID:          orgID,
+			ConversationID: conversationID,
+			AgentName:      info.AgentName,
+			Model:          info.Model,
+			ConfigVersion:  configVersion,
+			Streaming:      info.Streaming,
+			SystemPrompt:   info.SystemPrompt,
+			Input:          info.Input,
+			StartedAt:      time.Now().UTC(),
+		},
+	}
+	run.AddTraceIDs(ctx)
+
+	return WithRun(ctx, run), run
+}
@@ -0,0 +1,103 @@
+package compliance
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidSignature is returned when a bundle signature does not verify
+var ErrInvalidSignature = errors.New("invalid bundle signature")
+
+// Signer signs and verifies bundle manifests
+type Signer interface {
+	// Algorithm returns the signature algorithm name recorded in the manifest
+	Algorithm() string
+
+	// Sign returns the signature of data
+	Sign(data []byte) ([]byte, error)
+
+	// Verify checks that signature is valid for data
+	Verify(data, signature []byte) error
+}
+
+// HMACSigner signs bundles with HMAC-SHA256 and a shared secret
+type HMACSigner struct {
+	key []byte
+}
+
+// NewHMACSigner creates a new HMAC-SHA256 signer
+func NewHMACSigner(key []byte) *HMACSigner {
+	return &HMACSigner{key: key}
+}
+
+// Algorithm implements Signer.Algorithm
+func (s *HMACSigner) Algorithm() string {
+	return "HMAC-SHA256"
+}
+
+// Sign implements Signer.Sign
+func (s *HMACSigner) Sign(data []byte) ([]byte, error) {
+	if len(s.key) == 0 {
+		return nil, fmt.Errorf("HMAC key is required")
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+// Verify implements Signer.Verify
+func (s *HMACSigner) Verify(data, signature []byte) error {
+	expected, err := s.Sign(data)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(expected, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Ed25519Signer signs bundles with an Ed25519 key pair, so recipients can
+// verify bundles with the public key only
+type Ed25519Signer struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// NewEd25519Signer creates a signer from an Ed25519 private key
+func NewEd25519Signer(privateKey ed25519.PrivateKey) *Ed25519Signer {
+	publicKey, _ := privateKey.Public().(ed25519.PublicKey)
+	return &Ed25519Signer{privateKey: privateKey, publicKey: publicKey}
+}
+
+// NewEd25519Verifier creates a verify-only signer from an Ed25519 public key
+func NewEd25519Verifier(publicKey ed25519.PublicKey) *Ed25519Signer {
+	return &Ed25519Signer{publicKey: publicKey}
+}
+
+// Algorithm implements Signer.Algorithm
+func (s *Ed25519Signer) Algorithm() string {
+	return "Ed25519"
+}
+
+// Sign implements Signer.Sign
+func (s *Ed25519Signer) Sign(data []byte) ([]byte, error) {
+	if len(s.privateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("Ed25519 private key is required for signing")
+	}
+	return ed25519.Sign(s.privateKey, data), nil
+}
+
+// Verify implements Signer.Verify
+func (s *Ed25519Signer) Verify(data, signature []byte) error {
+	if len(s.publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("Ed25519 public key is required for verification")
+	}
+	if !ed25519.Verify(s.publicKey, data, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package compliance
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect is the SQL dialect of a SQLStore database
+type Dialect string
+
+const (
+	// DialectPostgres stores runs in PostgreSQL (e.g. with github.com/lib/pq)
+	DialectPostgres Dialect = "postgres"
+
+	// DialectSQLite stores runs in SQLite 3.24 or later (e.g. with
+	// modernc.org/sqlite or github.com/mattn/go-sqlite3)
+	DialectSQLite Dialect = "sqlite"
+)
+
+// defaultRunsTable is the table SQLStore uses unless WithTable is set
+const defaultRunsTable = "agent_runs"
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLStore is a QueryableStore persisting run records in PostgreSQL or
+// SQLite. The full record is stored as JSON next to indexed columns used for
+// queries. The database driver is chosen by the caller when opening db.
+type SQLStore struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+}
+
+// SQLStoreOption represents an option for configuring the SQL store
+type SQLStoreOption func(*SQLStore)
+
+// WithTable sets the table runs are stored in (default: agent_runs)
+func WithTable(name string) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.table = name
+	}
+}
+
+// NewSQLStore creates a run store on db. Call Migrate to create the table.
+func NewSQLStore(db *sql.DB, dialect Dialect, options ...SQLStoreOption) (*SQLStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+	if dialect != DialectPostgres && dialect != DialectSQLite {
+		return nil, fmt.Errorf("unsupported SQL dialect %q (use postgres or sqlite)", dialect)
+	}
+
+	s := &SQLStore{
+		db:      db,
+		dialect: dialect,
+		table:   defaultRunsTable,
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	if !tableNamePattern.MatchString(s.table) {
+		return nil, fmt.Errorf("invalid table name %q", s.table)
+	}
+	return s, nil
+}
+
+// Migrate creates the runs table and its indexes if they do not exist
+func (s *SQLStore) Migrate(ctx context.Context) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS ` + s.table + ` (
+			id TEXT PRIMARY KEY,
+			org_id TEXT NOT NULL,
+			conversation_id TEXT NOT NULL DEFAULT '',
+			agent_name TEXT NOT NULL DEFAULT '',
+			model TEXT NOT NULL DEFAULT '',
+			tool_names TEXT NOT NULL DEFAULT '',
+			failed INTEGER NOT NULL DEFAULT 0,
+			input_tokens BIGINT NOT NULL DEFAULT 0,
+			output_tokens BIGINT NOT NULL DEFAULT 0,
+			started_at BIGINT NOT NULL,
+			completed_at BIGINT NOT NULL DEFAULT 0,
+			record TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS ` + s.table + `_org_started_idx ON ` + s.table + ` (org_id, started_at)`,
+		`CREATE INDEX IF NOT EXISTS ` + s.table + `_org_conversation_idx ON ` + s.table + ` (org_id, conversation_id)`,
+	}
+
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to migrate run store: %w", err)
+		}
+	}
+	return nil
+}
+
+// Save implements Store.Save
+func (s *SQLStore) Save(ctx context.Context, record *RunRecord) error {
+	if record == nil || record.ID == "" {
+		return fmt.Errorf("run record must have an ID")
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode run record: %w", err)
+	}
+
+	failed := 0
+	if record.Error != "" {
+		failed = 1
+	}
+	var inputTokens, outputTokens int
+	if record.Usage != nil {
+		inputTokens, outputTokens = record.Usage.InputTokens, record.Usage.OutputTokens
+	}
+	toolNames := ""
+	if names := record.toolNames(); len(names) > 0 {
+		toolNames = "," + strings.Join(names, ",") + ","
+	}
+
+	query := `INSERT INTO ` + s.table + ` (id, org_id, conversation_id, agent_name, model, tool_names, failed,
+		input_tokens, output_tokens, started_at, completed_at, record)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET org_id = excluded.org_id, conversation_id = excluded.conversation_id,
+		agent_name = excluded.agent_name, model = excluded.model, tool_names = excluded.tool_names,
+		failed = excluded.failed, input_tokens = excluded.input_tokens, output_tokens = excluded.output_tokens,
+		started_at = excluded.started_at, completed_at = excluded.completed_at, record = excluded.record`
+
+	_, err = s.db.ExecContext(ctx, s.bind(query),
+		record.ID, record.OrgID, record.ConversationID, record.AgentName, record.Model, toolNames, failed,
+		inputTokens, outputTokens, unixNano(record.StartedAt), unixNano(record.CompletedAt), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save run record: %w", err)
+	}
+	return nil
+}
+
+// Get implements Store.Get
+func (s *SQLStore) Get(ctx context.Context, runID string) (*RunRecord, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, s.bind(`SELECT record FROM `+s.table+` WHERE id = ?`), runID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run record: %w", err)
+	}
+	return decodeRunRecord(data)
+}
+
+// List implements Store.List
+func (s *SQLStore) List(ctx context.Context, orgID string) ([]*RunRecord, error) {
+	query := `SELECT record FROM ` + s.table + ` WHERE org_id = ? ORDER BY started_at DESC`
+	return s.queryRecords(ctx, s.bind(query), orgID)
+}
+
+// Query implements QueryableStore.Query
+func (s *SQLStore) Query(ctx context.Context, q RunQuery) ([]*RunRecord, error) {
+	query, args := s.buildQuery(q)
+	return s.queryRecords(ctx, query, args...)
+}
+
+// buildQuery returns the SELECT statement and arguments of q
+func (s *SQLStore) buildQuery(q RunQuery) (string, []interface{}) {
+	conditions := []string{"org_id = ?"}
+	args := []interface{}{q.OrgID}
+
+	if q.ConversationID != "" {
+		conditions = append(conditions, "conversation_id = ?")
+		args = append(args, q.ConversationID)
+	}
+	if q.AgentName != "" {
+		conditions = append(conditions, "agent_name = ?")
+		args = append(args, q.AgentName)
+	}
+	if q.ToolName != "" {
+		conditions = append(conditions, `tool_names LIKE ? ESCAPE '\'`)
+		args = append(args, "%,"+escapeLike(q.ToolName)+",%")
+	}
+	if q.ErrorsOnly {
+		conditions = append(conditions, "failed = 1")
+	}
+	if !q.Since.IsZero() {
+		conditions = append(conditions, "started_at >= ?")
+		args = append(args, unixNano(q.Since))
+	}
+	if !q.Until.IsZero() {
+		conditions = append(conditions, "started_at < ?")
+		args = append(args, unixNano(q.Until))
+	}
+
+	query := `SELECT record FROM ` + s.table + ` WHERE ` + strings.Join(conditions, " AND ") +
+		` ORDER BY started_at DESC LIMIT ? OFFSET ?`
+	args = append(args, q.limit(), max(q.Offset, 0))
+	return s.bind(query), args
+}
+
+func (s *SQLStore) queryRecords(ctx context.Context, query string, args ...interface{}) ([]*RunRecord, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query run records: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	records := []*RunRecord{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read run record: %w", err)
+		}
+		record, err := decodeRunRecord(data)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query run records: %w", err)
+	}
+	return records, nil
+}
+
+// bind rewrites ? placeholders to $n for PostgreSQL
+func (s *SQLStore) bind(query string) string {
+	if s.dialect != DialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func decodeRunRecord(data string) (*RunRecord, error) {
+	var record RunRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, fmt.Errorf("failed to decode run record: %w", err)
+	}
+	return &record, nil
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
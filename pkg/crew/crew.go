@@ -0,0 +1,259 @@
+// Package crew runs agents with roles on tasks with expected outputs, as
+// configured in agents.yaml and tasks.yaml, by building a workflow of the
+// tasks
+package crew
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/orchestration"
+	"github.com/Ingenimax/agent-sdk-go/pkg/workflow"
+)
+
+// Crew is a team of agents working on tasks. Each task runs once the tasks
+// it depends on complete, with their results.
+type Crew struct {
+	agents       agent.AgentConfigs
+	tasks        agent.TaskConfigs
+	finalTask    string
+	agentOptions []agent.Option
+	orchestrator func(*orchestration.AgentRegistry) *orchestration.CodeOrchestrator
+}
+
+// Option represents an option for configuring a crew
+type Option func(*Crew)
+
+// WithFinalTask sets the task whose result is the result of the crew. By
+// default it is the only task no other task depends on.
+func WithFinalTask(name string) Option {
+	return func(c *Crew) {
+		c.finalTask = name
+	}
+}
+
+// WithAgentOptions sets options applied to every agent of the crew, such
+// as its LLM, memory or tools
+func WithAgentOptions(options ...agent.Option) Option {
+	return func(c *Crew) {
+		c.agentOptions = append(c.agentOptions, options...)
+	}
+}
+
+// WithOrchestrator sets how the orchestrator executing the tasks is created,
+// e.g. to add a state store or an approver
+func WithOrchestrator(newOrchestrator func(*orchestration.AgentRegistry) *orchestration.CodeOrchestrator) Option {
+	return func(c *Crew) {
+		c.orchestrator = newOrchestrator
+	}
+}
+
+// New creates a crew of the agents and tasks
+func New(agents agent.AgentConfigs, tasks agent.TaskConfigs, options ...Option) (*Crew, error) {
+	c := &Crew{
+		agents:       agents,
+		tasks:        tasks,
+		orchestrator: orchestration.NewCodeOrchestrator,
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("crew has no tasks")
+	}
+	for name, task := range tasks {
+		if _, ok := agents[task.Agent]; !ok {
+			return nil, fmt.Errorf("agent %s of task %s not found", task.Agent, name)
+		}
+	}
+	if c.finalTask == "" {
+		finalTask, err := c.lastTask()
+		if err != nil {
+			return nil, err
+		}
+		c.finalTask = finalTask
+	}
+
+	// The workflow definition checks the dependencies and the final task
+	if err := c.definition().Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadFromDir creates a crew from the agents.yaml and tasks.yaml files of a
+// directory
+func LoadFromDir(dirPath string, options ...Option) (*Crew, error) {
+	agents, err := agent.LoadAgentConfigsFromFile(filepath.Join(dirPath, "agents.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := agent.LoadTaskConfigsFromFile(filepath.Join(dirPath, "tasks.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	return New(agents, tasks, options...)
+}
+
+// Run runs the tasks of the crew and returns the result of its final task.
+// The {variable} placeholders of the agents and tasks are replaced with
+// inputs. Results of tasks with an output file are written to it.
+func (c *Crew) Run(ctx context.Context, inputs map[string]string) (string, error) {
+	registry, err := c.Registry(inputs)
+	if err != nil {
+		return "", err
+	}
+	w, err := c.Workflow(inputs)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.orchestrator(registry).ExecuteWorkflow(ctx, w)
+	if err != nil {
+		return "", err
+	}
+
+	replacer := newReplacer(inputs)
+	for _, name := range c.taskNames() {
+		outputFile := c.tasks[name].OutputFile
+		if outputFile == "" {
+			continue
+		}
+		outputPath := replacer.Replace(outputFile)
+		if err := os.WriteFile(outputPath, []byte(w.Results[name]), 0600); err != nil {
+			return result, fmt.Errorf("failed to write output of task %s to file %s: %w", name, outputPath, err)
+		}
+	}
+	return result, nil
+}
+
+// Workflow returns the workflow of the tasks, to execute with the agents of
+// Registry. Each task runs the agent registered under the task's name.
+func (c *Crew) Workflow(inputs map[string]string) (*orchestration.Workflow, error) {
+	return c.definition().Build(inputs)
+}
+
+// definition returns the workflow definition of the tasks. The input of a
+// task is its description and expected output.
+func (c *Crew) definition() *workflow.Definition {
+	definition := &workflow.Definition{
+		FinalTask: c.finalTask,
+		Tasks:     make(map[string]workflow.TaskDefinition, len(c.tasks)),
+	}
+	for name, task := range c.tasks {
+		input := strings.TrimSpace(task.Description)
+		if expectedOutput := strings.TrimSpace(task.ExpectedOutput); expectedOutput != "" {
+			input = fmt.Sprintf("# Task\n%s\n\n# Expected Output\n%s", input, expectedOutput)
+		}
+		definition.Tasks[name] = workflow.TaskDefinition{
+			Agent:     name,
+			Input:     input,
+			DependsOn: task.DependsOn,
+		}
+	}
+	return definition
+}
+
+// Registry creates an agent for each task, registered under the task's name
+// with the response format of the task. Agents allowed to delegate get the
+// other agents of the crew as sub-agents.
+func (c *Crew) Registry(inputs map[string]string) (*orchestration.AgentRegistry, error) {
+	registry := orchestration.NewAgentRegistry()
+	for _, name := range c.taskNames() {
+		options := c.agentOptions
+		if config := c.agents[c.tasks[name].Agent]; config.AllowDelegation != nil && *config.AllowDelegation {
+			coworkers, err := c.newCoworkers(c.tasks[name].Agent, inputs)
+			if err != nil {
+				return nil, err
+			}
+			options = append(append([]agent.Option{}, options...), agent.WithAgents(coworkers...))
+		}
+
+		a, err := agent.CreateAgentForTask(name, c.agents, c.tasks, inputs, options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create agent for task %s: %w", name, err)
+		}
+		registry.Register(name, a)
+	}
+	return registry, nil
+}
+
+// newCoworkers creates the agents of the crew other than name, described by
+// their role and goal so that the delegating agent knows what they do
+func (c *Crew) newCoworkers(name string, inputs map[string]string) ([]*agent.Agent, error) {
+	replacer := newReplacer(inputs)
+	var coworkers []*agent.Agent
+	for _, coworker := range c.agentNames() {
+		if coworker == name {
+			continue
+		}
+		config := c.agents[coworker]
+		description := strings.TrimSpace(replacer.Replace(config.Role + ". " + config.Goal))
+		options := append(append([]agent.Option{}, c.agentOptions...), agent.WithDescription(description))
+		a, err := agent.NewAgentFromConfig(coworker, c.agents, inputs, options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create coworker %s of agent %s: %w", coworker, name, err)
+		}
+		coworkers = append(coworkers, a)
+	}
+	return coworkers, nil
+}
+
+// lastTask returns the only task no other task depends on
+func (c *Crew) lastTask() (string, error) {
+	dependedOn := make(map[string]bool)
+	for _, task := range c.tasks {
+		for _, dep := range task.DependsOn {
+			dependedOn[dep] = true
+		}
+	}
+	var last []string
+	for _, name := range c.taskNames() {
+		if !dependedOn[name] {
+			last = append(last, name)
+		}
+	}
+	if len(last) != 1 {
+		return "", fmt.Errorf("crew has %d final tasks (%s), set one with WithFinalTask", len(last), strings.Join(last, ", "))
+	}
+	return last[0], nil
+}
+
+// agentNames returns the names of the agents of the tasks in a stable order
+func (c *Crew) agentNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, task := range c.taskNames() {
+		if name := c.tasks[task].Agent; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// taskNames returns the names of the tasks in a stable order
+func (c *Crew) taskNames() []string {
+	names := make([]string, 0, len(c.tasks))
+	for name := range c.tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newReplacer replaces {variable} placeholders, like agent configurations do
+func newReplacer(variables map[string]string) *strings.Replacer {
+	pairs := make([]string, 0, len(variables)*2)
+	for key, value := range variables {
+		pairs = append(pairs, "{"+key+"}", value)
+	}
+	return strings.NewReplacer(pairs...)
+}
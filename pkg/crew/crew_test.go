@@ -0,0 +1,185 @@
+package crew
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const testAgents = `
+researcher:
+  role: Senior researcher on {topic}
+  goal: Find the latest developments
+  backstory: You know where to look.
+writer:
+  role: Technical writer
+  goal: Write clear reports
+  backstory: You turn research into prose.
+  allow_delegation: true
+`
+
+const testTasks = `
+research:
+  description: Research {topic}
+  expected_output: A list of 3 findings
+  agent: researcher
+report:
+  description: Write a report about {topic}
+  expected_output: A markdown report
+  agent: writer
+  depends_on: [research]
+  output_file: "{output_dir}/report.md"
+`
+
+// recordingLLM answers with the response of the first key its prompt
+// contains and records the prompts
+type recordingLLM struct {
+	responses map[string]string
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+func (m *recordingLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	m.mu.Lock()
+	m.prompts = append(m.prompts, prompt)
+	m.mu.Unlock()
+	for key, response := range m.responses {
+		if strings.Contains(prompt, key) {
+			return response, nil
+		}
+	}
+	return "", nil
+}
+
+func (m *recordingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *recordingLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := m.Generate(ctx, prompt, options...)
+	return &interfaces.LLMResponse{Content: content}, err
+}
+
+func (m *recordingLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return m.GenerateDetailed(ctx, prompt, options...)
+}
+
+func (m *recordingLLM) Name() string { return "recording" }
+
+func (m *recordingLLM) SupportsStreaming() bool { return false }
+
+func writeConfigs(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "agents.yaml"), []byte(testAgents), 0600); err != nil {
+		t.Fatalf("Failed to write agents: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tasks.yaml"), []byte(testTasks), 0600); err != nil {
+		t.Fatalf("Failed to write tasks: %v", err)
+	}
+	return dir
+}
+
+func TestCrew_Run(t *testing.T) {
+	dir := writeConfigs(t)
+	llm := &recordingLLM{responses: map[string]string{
+		"Research Go":    "1. generics 2. iterators 3. PGO",
+		"Write a report": "# Go in 2025",
+	}}
+
+	crew, err := LoadFromDir(dir, WithAgentOptions(agent.WithLLM(llm), agent.WithRequirePlanApproval(false)))
+	if err != nil {
+		t.Fatalf("LoadFromDir() error = %v", err)
+	}
+	result, err := crew.Run(context.Background(), map[string]string{"topic": "Go", "output_dir": dir})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result != "# Go in 2025" {
+		t.Errorf("Expected the result of the last task, got %q", result)
+	}
+
+	var reportPrompt string
+	for _, prompt := range llm.prompts {
+		if strings.Contains(prompt, "Write a report about Go") {
+			reportPrompt = prompt
+		}
+	}
+	if !strings.Contains(reportPrompt, "# Expected Output\nA markdown report") {
+		t.Errorf("Expected the expected output in the prompt, got %q", reportPrompt)
+	}
+	if !strings.Contains(reportPrompt, "1. generics 2. iterators 3. PGO") {
+		t.Errorf("Expected the research in the prompt, got %q", reportPrompt)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil || string(data) != "# Go in 2025" {
+		t.Errorf("Expected the report in its output file, got %q, %v", data, err)
+	}
+}
+
+func TestCrew_Delegation(t *testing.T) {
+	configs, err := agent.LoadAgentConfigsFromFile(filepath.Join(writeConfigs(t), "agents.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load agents: %v", err)
+	}
+	tasks := agent.TaskConfigs{
+		"research": {Description: "Research {topic}", Agent: "researcher"},
+		"report":   {Description: "Write a report", Agent: "writer", DependsOn: []string{"research"}},
+	}
+
+	crew, err := New(configs, tasks, WithAgentOptions(agent.WithLLM(&recordingLLM{})))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	registry, err := crew.Registry(map[string]string{"topic": "Go"})
+	if err != nil {
+		t.Fatalf("Registry() error = %v", err)
+	}
+
+	writer, _ := registry.Get("report")
+	coworkers := writer.GetSubAgents()
+	if len(coworkers) != 1 || coworkers[0].GetName() != "researcher" {
+		t.Fatalf("Expected the writer to delegate to the researcher, got %d coworkers", len(coworkers))
+	}
+	if description := coworkers[0].GetDescription(); !strings.Contains(description, "Senior researcher on Go") {
+		t.Errorf("Expected the coworker to be described by its role, got %q", description)
+	}
+	researcher, _ := registry.Get("research")
+	if len(researcher.GetSubAgents()) != 0 {
+		t.Error("Expected the researcher not to delegate")
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	configs := agent.AgentConfigs{"researcher": {Role: "Researcher"}}
+
+	tests := []struct {
+		name  string
+		tasks agent.TaskConfigs
+		want  string
+	}{
+		{"unknown agent", agent.TaskConfigs{"a": {Agent: "writer"}}, "agent writer of task a not found"},
+		{"unknown dependency", agent.TaskConfigs{"a": {Agent: "researcher", DependsOn: []string{"b"}}}, "unknown task b"},
+		{"several final tasks", agent.TaskConfigs{"a": {Agent: "researcher"}, "b": {Agent: "researcher"}}, "2 final tasks (a, b)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(configs, tt.tasks)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := New(configs, agent.TaskConfigs{"a": {Agent: "researcher"}, "b": {Agent: "researcher"}}, WithFinalTask("b")); err != nil {
+		t.Errorf("Expected the final task to be set, got %v", err)
+	}
+}
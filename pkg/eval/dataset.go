@@ -0,0 +1,136 @@
+package eval
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Case is a single evaluation example
+type Case struct {
+	// ID identifies the case in reports; defaults to its position in the dataset
+	ID string `json:"id,omitempty" yaml:"id,omitempty"`
+
+	// Input is the prompt sent to the target
+	Input string `json:"input" yaml:"input"`
+
+	// Expected is the reference output used by metrics such as ExactMatch
+	Expected string `json:"expected,omitempty" yaml:"expected,omitempty"`
+
+	// Schema is the JSON schema the output must match for the JSONSchema metric
+	Schema interfaces.JSONSchema `json:"schema,omitempty" yaml:"schema,omitempty"`
+
+	// Criteria describes a good answer for the LLMJudge metric
+	Criteria string `json:"criteria,omitempty" yaml:"criteria,omitempty"`
+
+	// Tags group cases in reports
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Metadata holds arbitrary data for custom metrics
+	Metadata map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// Dataset is a named list of evaluation cases
+type Dataset struct {
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Cases []Case `json:"cases" yaml:"cases"`
+}
+
+// LoadDataset loads a dataset from a JSON, JSON Lines or YAML file. JSON and
+// YAML files contain either a list of cases or an object with name and
+// cases; JSON Lines files (.jsonl) contain one case per line. The dataset
+// name defaults to the file name.
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the caller
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+
+	var dataset *Dataset
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".jsonl":
+		dataset, err = parseJSONLines(data)
+	case ".json":
+		dataset, err = parseDataset(data, json.Unmarshal)
+	case ".yaml", ".yml":
+		dataset, err = parseDataset(data, yaml.Unmarshal)
+	default:
+		return nil, fmt.Errorf("unsupported dataset format %q (use .json, .jsonl, .yaml or .yml)", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dataset %s: %w", path, err)
+	}
+
+	if dataset.Name == "" {
+		dataset.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := dataset.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid dataset %s: %w", path, err)
+	}
+	return dataset, nil
+}
+
+func parseDataset(data []byte, unmarshal func([]byte, interface{}) error) (*Dataset, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) || bytes.HasPrefix(trimmed, []byte("-")) {
+		var cases []Case
+		if err := unmarshal(data, &cases); err != nil {
+			return nil, err
+		}
+		return &Dataset{Cases: cases}, nil
+	}
+
+	var dataset Dataset
+	if err := unmarshal(data, &dataset); err != nil {
+		return nil, err
+	}
+	return &dataset, nil
+}
+
+func parseJSONLines(data []byte) (*Dataset, error) {
+	dataset := &Dataset{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var c Case
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		dataset.Cases = append(dataset.Cases, c)
+	}
+	return dataset, scanner.Err()
+}
+
+// normalize assigns missing IDs and checks that IDs are unique and inputs set
+func (d *Dataset) normalize() error {
+	seen := make(map[string]bool, len(d.Cases))
+	for i := range d.Cases {
+		c := &d.Cases[i]
+		if c.ID == "" {
+			c.ID = fmt.Sprintf("case-%d", i+1)
+		}
+		if seen[c.ID] {
+			return fmt.Errorf("duplicate case id %q", c.ID)
+		}
+		seen[c.ID] = true
+		if c.Input == "" {
+			return fmt.Errorf("case %q has no input", c.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+)
+
+func TestLoadDataset(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"list.json":   `[{"input": "2+2", "expected": "4"}, {"id": "capital", "input": "Capital of France?", "expected": "Paris"}]`,
+		"object.json": `{"name": "math", "cases": [{"input": "2+2", "expected": "4"}, {"input": "3+3", "expected": "6"}]}`,
+		"lines.jsonl": "{\"input\": \"2+2\", \"expected\": \"4\"}\n\n{\"input\": \"3+3\", \"expected\": \"6\"}\n",
+		"cases.yaml": `name: support
+cases:
+  - id: refund
+    input: How do I get a refund?
+    criteria: Mentions the refund form
+    tags: [billing]
+  - input: Return the order as JSON
+    schema:
+      type: object
+      required: [id]
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		file  string
+		name  string
+		ids   []string
+		check func(t *testing.T, d *Dataset)
+	}{
+		{file: "list.json", name: "list", ids: []string{"case-1", "capital"}},
+		{file: "object.json", name: "math", ids: []string{"case-1", "case-2"}},
+		{file: "lines.jsonl", name: "lines", ids: []string{"case-1", "case-2"}},
+		{file: "cases.yaml", name: "support", ids: []string{"refund", "case-2"}, check: func(t *testing.T, d *Dataset) {
+			if d.Cases[0].Criteria != "Mentions the refund form" || d.Cases[0].Tags[0] != "billing" {
+				t.Errorf("unexpected case %+v", d.Cases[0])
+			}
+			if d.Cases[1].Schema["type"] != "object" {
+				t.Errorf("expected the schema to be loaded, got %v", d.Cases[1].Schema)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			d, err := LoadDataset(filepath.Join(dir, tt.file))
+			if err != nil {
+				t.Fatalf("failed to load dataset: %v", err)
+			}
+			if d.Name != tt.name {
+				t.Errorf("expected name %q, got %q", tt.name, d.Name)
+			}
+			if len(d.Cases) != len(tt.ids) {
+				t.Fatalf("expected %d cases, got %d", len(tt.ids), len(d.Cases))
+			}
+			for i, id := range tt.ids {
+				if d.Cases[i].ID != id {
+					t.Errorf("expected case %d to have id %q, got %q", i, id, d.Cases[i].ID)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, d)
+			}
+		})
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "dup.json"), []byte(`[{"id":"a","input":"x"},{"id":"a","input":"y"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDataset(filepath.Join(dir, "dup.json")); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("expected a duplicate id error, got %v", err)
+	}
+	if _, err := LoadDataset(filepath.Join(dir, "cases.csv")); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	schema := interfaces.JSONSchema{
+		"type":     "object",
+		"required": []string{"id"},
+		"properties": map[string]interface{}{
+			"id": map[string]interface{}{"type": "integer"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		metric Metric
+		sample Sample
+		value  float64
+		passed bool
+	}{
+		{"exact match", ExactMatch(), Sample{Case: Case{Expected: "Paris"}, Output: " Paris\n"}, 1, true},
+		{"exact match case", ExactMatch(), Sample{Case: Case{Expected: "Paris"}, Output: "paris"}, 0, false},
+		{"exact match ignore case", ExactMatchIgnoreCase(), Sample{Case: Case{Expected: "Paris"}, Output: "paris"}, 1, true},
+		{"contains", Contains(), Sample{Case: Case{Expected: "paris"}, Output: "The capital is Paris."}, 1, true},
+		{"not contains", Contains(), Sample{Case: Case{Expected: "Lyon"}, Output: "Paris"}, 0, false},
+		{"json schema", JSONSchema(schema), Sample{Output: "```json\n{\"id\": 7}\n```"}, 1, true},
+		{"json schema violation", JSONSchema(schema), Sample{Output: `{"id": "seven"}`}, 0, false},
+		{"case schema", JSONSchema(nil), Sample{Case: Case{Schema: schema}, Output: `{}`}, 0, false},
+		{"fast", Latency(time.Second), Sample{Latency: 500 * time.Millisecond}, 1, true},
+		{"slow", Latency(time.Second), Sample{Latency: 1500 * time.Millisecond}, 0.5, false},
+		{"very slow", Latency(time.Second), Sample{Latency: 3 * time.Second}, 0, false},
+		{"cheap", Cost(Pricing{InputPerMillion: 1, OutputPerMillion: 2}, 0.01), Sample{Usage: &interfaces.TokenUsage{InputTokens: 1000, OutputTokens: 1000}}, 1, true},
+		{"expensive", Cost(Pricing{InputPerMillion: 1, OutputPerMillion: 2}, 0.002), Sample{Usage: &interfaces.TokenUsage{InputTokens: 1000, OutputTokens: 1000}}, 0.5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, err := tt.metric.Score(ctx, tt.sample)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if score.Passed != tt.passed || score.Value < tt.value-1e-9 || score.Value > tt.value+1e-9 {
+				t.Errorf("expected value %v passed %v, got %+v", tt.value, tt.passed, score)
+			}
+		})
+	}
+
+	if _, err := JSONSchema(nil).Score(ctx, Sample{Output: "{}"}); err == nil {
+		t.Error("expected an error for a case without schema")
+	}
+	if _, err := Cost(Pricing{}, 1).Score(ctx, Sample{}); err == nil {
+		t.Error("expected an error without token usage")
+	}
+}
+
+func TestLLMJudge(t *testing.T) {
+	judge := mock.New()
+	judge.On(mock.All(mock.PromptContains("Mentions the refund form"), mock.PromptContains("Use the refund form"))).
+		Respond(`{"score": 9, "reason": "Mentions the form"}`)
+	judge.On(mock.PromptContains("Mentions the refund form")).
+		Respond("Verdict: ```json\n{\"score\": 3, \"reason\": \"No form\"}\n```")
+	judge.On(mock.Any()).Respond("I cannot grade this")
+
+	metric := LLMJudge(judge, WithJudgeThreshold(0.8), WithJudgeName("helpfulness"))
+	if metric.Name() != "helpfulness" {
+		t.Errorf("expected the configured name, got %q", metric.Name())
+	}
+
+	c := Case{Input: "How do I get a refund?", Criteria: "Mentions the refund form"}
+	score, err := metric.Score(context.Background(), Sample{Case: c, Output: "Use the refund form"})
+	if err != nil || !score.Passed || score.Value != 0.9 || score.Reason != "Mentions the form" {
+		t.Errorf("unexpected score %+v, %v", score, err)
+	}
+	score, err = metric.Score(context.Background(), Sample{Case: c, Output: "Call us"})
+	if err != nil || score.Passed || score.Value != 0.3 {
+		t.Errorf("unexpected score %+v, %v", score, err)
+	}
+	if _, err := metric.Score(context.Background(), Sample{Case: Case{Input: "other"}, Output: "x"}); err == nil {
+		t.Error("expected an error for an invalid verdict")
+	}
+
+	call := judge.Calls()[0]
+	if !strings.Contains(call.Request.SystemMessage, "evaluator") {
+		t.Errorf("expected the judge system message, got %q", call.Request.SystemMessage)
+	}
+}
+
+func TestRunner(t *testing.T) {
+	llm := mock.New(mock.WithUsage(interfaces.TokenUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}))
+	llm.On(mock.PromptEquals("2+2")).Respond("4")
+	llm.On(mock.PromptEquals("3+3")).Respond("7")
+	llm.On(mock.PromptEquals("boom")).Fail(errors.New("provider unavailable"))
+
+	dataset := &Dataset{Name: "math", Cases: []Case{
+		{Input: "2+2", Expected: "4"},
+		{Input: "3+3", Expected: "6"},
+		{Input: "boom", Expected: "0"},
+	}}
+
+	runner := NewRunner(LLMTarget(llm), []Metric{ExactMatch(), Cost(Pricing{InputPerMillion: 1}, 1)},
+		WithName("mock"), WithConcurrency(3))
+	report, err := runner.Run(context.Background(), dataset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Target != "mock" || report.Dataset != "math" || len(report.Results) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if report.Results[0].CaseID != "case-1" || !report.Results[0].Passed() {
+		t.Errorf("expected the first case to pass, got %+v", report.Results[0])
+	}
+	if report.Results[1].Passed() || report.Results[1].Output != "7" {
+		t.Errorf("expected the second case to fail, got %+v", report.Results[1])
+	}
+	if report.Results[2].Error != "provider unavailable" {
+		t.Errorf("expected the target error to be reported, got %+v", report.Results[2])
+	}
+
+	exact := report.Summary["exact_match"]
+	if exact.Passed != 1 || exact.Failed != 1 || exact.Errors != 1 || exact.Mean != 0.5 {
+		t.Errorf("unexpected exact match summary %+v", exact)
+	}
+	if report.PassRate < 0.33 || report.PassRate > 0.34 {
+		t.Errorf("expected a pass rate of 1/3, got %v", report.PassRate)
+	}
+	if report.Usage.TotalTokens != 30 {
+		t.Errorf("expected the usage of 2 successful cases, got %+v", report.Usage)
+	}
+	if len(report.Failures()) != 2 {
+		t.Errorf("expected 2 failures, got %d", len(report.Failures()))
+	}
+
+	var markdown bytes.Buffer
+	if err := report.WriteMarkdown(&markdown); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"# Evaluation: mock on math", "| exact_match | 0.500 | 33.3% | 1 | 1 | 1 |", "### case-2", "- Error: provider unavailable"} {
+		if !strings.Contains(markdown.String(), expected) {
+			t.Errorf("expected the markdown report to contain %q:\n%s", expected, markdown.String())
+		}
+	}
+}
+
+func TestRunnerConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	target := TargetFunc(func(ctx context.Context, input string) (*interfaces.AgentResponse, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return &interfaces.AgentResponse{Content: input}, nil
+	})
+
+	dataset := &Dataset{}
+	for i := 0; i < 8; i++ {
+		dataset.Cases = append(dataset.Cases, Case{Input: strings.Repeat("x", i+1)})
+	}
+
+	report, err := NewRunner(target, nil, WithConcurrency(2)).Run(context.Background(), dataset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if peak.Load() != 2 {
+		t.Errorf("expected 2 cases to run in parallel, got %d", peak.Load())
+	}
+	for i, result := range report.Results {
+		if result.Output != dataset.Cases[i].Input {
+			t.Errorf("expected results in dataset order, got %q for case %d", result.Output, i)
+		}
+	}
+}
+
+func TestCompareAndRunTargets(t *testing.T) {
+	good := mock.New()
+	good.On(mock.Any()).Respond("4")
+	bad := mock.New()
+	bad.On(mock.Any()).Respond("5")
+
+	dataset := &Dataset{Name: "math", Cases: []Case{{Input: "2+2", Expected: "4"}}}
+	reports, err := RunTargets(context.Background(), dataset, map[string]Target{
+		"good": LLMTarget(good),
+		"bad":  LLMTarget(bad),
+	}, []Metric{ExactMatch()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reports) != 2 || reports[0].Target != "bad" || reports[1].Target != "good" {
+		t.Fatalf("expected reports ordered by target name, got %d reports", len(reports))
+	}
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := reports[1].SaveJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	regressions := reports[0].Compare(baseline, 0.05)
+	if len(regressions) != 1 || regressions[0].Metric != "exact_match" || regressions[0].BaselineMean != 1 {
+		t.Fatalf("expected an exact match regression, got %v", regressions)
+	}
+	if !strings.Contains(regressions[0].String(), "pass rate 100.0% -> 0.0%") {
+		t.Errorf("unexpected regression description %q", regressions[0])
+	}
+	if len(reports[1].Compare(baseline, 0)) != 0 {
+		t.Error("expected no regression against the same results")
+	}
+}
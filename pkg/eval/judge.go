@@ -0,0 +1,109 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/structuredoutput"
+)
+
+// JudgeOption represents an option for configuring the LLM judge
+type JudgeOption func(*llmJudge)
+
+// WithJudgeCriteria sets the criteria used for cases without their own
+func WithJudgeCriteria(criteria string) JudgeOption {
+	return func(j *llmJudge) {
+		j.criteria = criteria
+	}
+}
+
+// WithJudgeThreshold sets the minimum score between 0 and 1 for a sample to
+// pass (default: 0.7)
+func WithJudgeThreshold(threshold float64) JudgeOption {
+	return func(j *llmJudge) {
+		j.threshold = threshold
+	}
+}
+
+// WithJudgeName sets the metric name, to use several judges in one run
+// (default: "llm_judge")
+func WithJudgeName(name string) JudgeOption {
+	return func(j *llmJudge) {
+		j.name = name
+	}
+}
+
+// LLMJudge scores samples by asking llm to grade the output against the
+// case's criteria and expected output on a scale from 0 to 10
+func LLMJudge(llm interfaces.LLM, options ...JudgeOption) Metric {
+	j := &llmJudge{
+		llm:       llm,
+		name:      "llm_judge",
+		criteria:  "The response is correct, complete and answers the input.",
+		threshold: 0.7,
+	}
+
+	for _, option := range options {
+		option(j)
+	}
+
+	return j
+}
+
+type llmJudge struct {
+	llm       interfaces.LLM
+	name      string
+	criteria  string
+	threshold float64
+}
+
+type verdict struct {
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+func (j *llmJudge) Name() string {
+	return j.name
+}
+
+func (j *llmJudge) Score(ctx context.Context, sample Sample) (Score, error) {
+	response, err := j.llm.Generate(ctx, j.prompt(sample),
+		interfaces.WithSystemMessage("You are a strict evaluator of AI assistant responses. Reply only with JSON."),
+		interfaces.WithTemperature(0),
+	)
+	if err != nil {
+		return Score{}, fmt.Errorf("judge failed: %w", err)
+	}
+
+	var v verdict
+	if err := json.Unmarshal([]byte(structuredoutput.ExtractJSON(response)), &v); err != nil {
+		return Score{}, fmt.Errorf("judge returned an invalid verdict %q: %w", truncate(response, 200), err)
+	}
+	if v.Score < 0 || v.Score > 10 {
+		return Score{}, fmt.Errorf("judge score %v is not between 0 and 10", v.Score)
+	}
+
+	value := v.Score / 10
+	return Score{Value: value, Passed: value >= j.threshold, Reason: v.Reason}, nil
+}
+
+func (j *llmJudge) prompt(sample Sample) string {
+	criteria := sample.Case.Criteria
+	if criteria == "" {
+		criteria = j.criteria
+	}
+
+	var b strings.Builder
+	b.WriteString("Grade the response of an AI assistant.\n\n")
+	fmt.Fprintf(&b, "## Criteria\n%s\n\n", criteria)
+	fmt.Fprintf(&b, "## Input\n%s\n\n", sample.Case.Input)
+	if sample.Case.Expected != "" {
+		fmt.Fprintf(&b, "## Reference answer\n%s\n\n", sample.Case.Expected)
+	}
+	fmt.Fprintf(&b, "## Response\n%s\n\n", sample.Output)
+	b.WriteString(`Reply with a JSON object {"score": <0-10>, "reason": "<one sentence>"} where 10 fully meets the criteria and 0 does not meet them at all.`)
+	return b.String()
+}
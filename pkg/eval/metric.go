@@ -0,0 +1,218 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/structuredoutput"
+)
+
+// Sample is the outcome of running a case against the target
+type Sample struct {
+	Case    Case
+	Output  string
+	Model   string
+	Usage   *interfaces.TokenUsage
+	Latency time.Duration
+	Error   error
+}
+
+// Score is the result of a metric for a sample
+type Score struct {
+	// Value is the score between 0 and 1
+	Value float64 `json:"value"`
+
+	// Passed reports whether the sample meets the metric's threshold
+	Passed bool `json:"passed"`
+
+	// Reason explains the score
+	Reason string `json:"reason,omitempty"`
+}
+
+// Metric scores samples
+type Metric interface {
+	// Name identifies the metric in reports
+	Name() string
+
+	// Score scores a sample. Samples whose target run failed are not scored.
+	Score(ctx context.Context, sample Sample) (Score, error)
+}
+
+// pass returns a passing or failing score with value 1 or 0
+func pass(passed bool, reason string) Score {
+	if passed {
+		return Score{Value: 1, Passed: true, Reason: reason}
+	}
+	return Score{Value: 0, Passed: false, Reason: reason}
+}
+
+// ExactMatch scores 1 when the output equals the expected output, ignoring
+// surrounding whitespace
+func ExactMatch() Metric {
+	return &exactMatch{}
+}
+
+// ExactMatchIgnoreCase is ExactMatch with a case-insensitive comparison
+func ExactMatchIgnoreCase() Metric {
+	return &exactMatch{ignoreCase: true}
+}
+
+type exactMatch struct {
+	ignoreCase bool
+}
+
+func (m *exactMatch) Name() string {
+	return "exact_match"
+}
+
+func (m *exactMatch) Score(ctx context.Context, sample Sample) (Score, error) {
+	output, expected := strings.TrimSpace(sample.Output), strings.TrimSpace(sample.Case.Expected)
+	if m.ignoreCase {
+		output, expected = strings.ToLower(output), strings.ToLower(expected)
+	}
+	if output == expected {
+		return pass(true, ""), nil
+	}
+	return pass(false, fmt.Sprintf("expected %q", truncate(sample.Case.Expected, 200))), nil
+}
+
+// Contains scores 1 when the output contains the expected output, ignoring case
+func Contains() Metric {
+	return &contains{}
+}
+
+type contains struct{}
+
+func (m *contains) Name() string {
+	return "contains"
+}
+
+func (m *contains) Score(ctx context.Context, sample Sample) (Score, error) {
+	expected := strings.TrimSpace(sample.Case.Expected)
+	if strings.Contains(strings.ToLower(sample.Output), strings.ToLower(expected)) {
+		return pass(true, ""), nil
+	}
+	return pass(false, fmt.Sprintf("output does not contain %q", truncate(expected, 200))), nil
+}
+
+// JSONSchema scores 1 when the output is JSON matching schema. A nil schema
+// uses the schema of each case. JSON wrapped in text or code fences is
+// extracted before validation.
+func JSONSchema(schema interfaces.JSONSchema) Metric {
+	return &jsonSchema{schema: schema}
+}
+
+type jsonSchema struct {
+	schema interfaces.JSONSchema
+}
+
+func (m *jsonSchema) Name() string {
+	return "json_schema"
+}
+
+func (m *jsonSchema) Score(ctx context.Context, sample Sample) (Score, error) {
+	schema := m.schema
+	if schema == nil {
+		schema = sample.Case.Schema
+	}
+	if schema == nil {
+		return Score{}, fmt.Errorf("case %q has no schema", sample.Case.ID)
+	}
+
+	err := structuredoutput.Validate(schema, []byte(structuredoutput.ExtractJSON(sample.Output)))
+	var validationErr *structuredoutput.ValidationError
+	switch {
+	case err == nil:
+		return pass(true, ""), nil
+	case errors.As(err, &validationErr):
+		return pass(false, strings.Join(validationErr.Errors, "; ")), nil
+	default:
+		return Score{}, err
+	}
+}
+
+// Latency scores 1 when the target answered within max. The score decreases
+// linearly to 0 at twice max.
+func Latency(max time.Duration) Metric {
+	return &latency{max: max}
+}
+
+type latency struct {
+	max time.Duration
+}
+
+func (m *latency) Name() string {
+	return "latency"
+}
+
+func (m *latency) Score(ctx context.Context, sample Sample) (Score, error) {
+	reason := fmt.Sprintf("%s (max %s)", sample.Latency.Round(time.Millisecond), m.max)
+	if sample.Latency <= m.max {
+		return Score{Value: 1, Passed: true, Reason: reason}, nil
+	}
+	value := 2 - float64(sample.Latency)/float64(m.max)
+	if value < 0 {
+		value = 0
+	}
+	return Score{Value: value, Passed: false, Reason: reason}, nil
+}
+
+// Pricing is the price of a model in dollars per million tokens
+type Pricing struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// Cost returns the cost of usage in dollars
+func (p Pricing) Cost(usage *interfaces.TokenUsage) float64 {
+	if usage == nil {
+		return 0
+	}
+	return (float64(usage.InputTokens)*p.InputPerMillion + float64(usage.OutputTokens)*p.OutputPerMillion) / 1e6
+}
+
+// Cost scores 1 when a case cost at most max dollars with pricing. The score
+// decreases linearly to 0 at twice max. Targets must report token usage.
+func Cost(pricing Pricing, max float64) Metric {
+	return &cost{pricing: pricing, max: max}
+}
+
+type cost struct {
+	pricing Pricing
+	max     float64
+}
+
+func (m *cost) Name() string {
+	return "cost"
+}
+
+func (m *cost) Score(ctx context.Context, sample Sample) (Score, error) {
+	if sample.Usage == nil {
+		return Score{}, fmt.Errorf("target did not report token usage")
+	}
+
+	spent := m.pricing.Cost(sample.Usage)
+	reason := fmt.Sprintf("$%.6f (max $%.6f)", spent, m.max)
+	if spent <= m.max {
+		return Score{Value: 1, Passed: true, Reason: reason}, nil
+	}
+	value := 0.0
+	if m.max > 0 {
+		value = 2 - spent/m.max
+	}
+	if value < 0 {
+		value = 0
+	}
+	return Score{Value: value, Passed: false, Reason: reason}, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,263 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Result is the evaluation of a single case
+type Result struct {
+	CaseID       string                 `json:"case_id"`
+	Input        string                 `json:"input"`
+	Output       string                 `json:"output"`
+	Model        string                 `json:"model,omitempty"`
+	Usage        *interfaces.TokenUsage `json:"usage,omitempty"`
+	LatencyMs    int64                  `json:"latency_ms"`
+	Tags         []string               `json:"tags,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	Scores       map[string]Score       `json:"scores"`
+	MetricErrors map[string]string      `json:"metric_errors,omitempty"`
+}
+
+// Passed reports whether the case ran and passed every metric
+func (r Result) Passed() bool {
+	if r.Error != "" || len(r.MetricErrors) > 0 {
+		return false
+	}
+	for _, score := range r.Scores {
+		if !score.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// MetricSummary aggregates the scores of a metric over a dataset
+type MetricSummary struct {
+	// Mean is the mean score of the scored cases
+	Mean float64 `json:"mean"`
+
+	// PassRate is the fraction of cases that passed, counting failed runs
+	// and metric errors as failures
+	PassRate float64 `json:"pass_rate"`
+
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+	Errors int `json:"errors"`
+}
+
+// Report is the outcome of evaluating a target on a dataset
+type Report struct {
+	Dataset   string                   `json:"dataset"`
+	Target    string                   `json:"target"`
+	StartedAt time.Time                `json:"started_at"`
+	Duration  time.Duration            `json:"duration"`
+	Metrics   []string                 `json:"metrics"`
+	Results   []Result                 `json:"results"`
+	Summary   map[string]MetricSummary `json:"summary"`
+
+	// PassRate is the fraction of cases that passed every metric
+	PassRate float64 `json:"pass_rate"`
+
+	// Usage is the token usage of all cases
+	Usage interfaces.TokenUsage `json:"usage"`
+}
+
+// summarize computes the summary of the results
+func (r *Report) summarize() {
+	r.Summary = make(map[string]MetricSummary, len(r.Metrics))
+	sums := make(map[string]float64, len(r.Metrics))
+	passed := 0
+
+	for _, result := range r.Results {
+		if result.Passed() {
+			passed++
+		}
+		if result.Usage != nil {
+			r.Usage.InputTokens += result.Usage.InputTokens
+			r.Usage.OutputTokens += result.Usage.OutputTokens
+			r.Usage.TotalTokens += result.Usage.TotalTokens
+		}
+
+		for _, metric := range r.Metrics {
+			summary := r.Summary[metric]
+			score, scored := result.Scores[metric]
+			switch {
+			case !scored:
+				summary.Errors++
+			case score.Passed:
+				summary.Passed++
+			default:
+				summary.Failed++
+			}
+			if scored {
+				sums[metric] += score.Value
+			}
+			r.Summary[metric] = summary
+		}
+	}
+
+	for _, metric := range r.Metrics {
+		summary := r.Summary[metric]
+		if scored := summary.Passed + summary.Failed; scored > 0 {
+			summary.Mean = sums[metric] / float64(scored)
+		}
+		if len(r.Results) > 0 {
+			summary.PassRate = float64(summary.Passed) / float64(len(r.Results))
+		}
+		r.Summary[metric] = summary
+	}
+	if len(r.Results) > 0 {
+		r.PassRate = float64(passed) / float64(len(r.Results))
+	}
+}
+
+// Failures returns the results that did not pass every metric
+func (r *Report) Failures() []Result {
+	var failures []Result
+	for _, result := range r.Results {
+		if !result.Passed() {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Regression is a metric whose mean score or pass rate dropped compared to a baseline
+type Regression struct {
+	Metric           string  `json:"metric"`
+	BaselineMean     float64 `json:"baseline_mean"`
+	Mean             float64 `json:"mean"`
+	BaselinePassRate float64 `json:"baseline_pass_rate"`
+	PassRate         float64 `json:"pass_rate"`
+}
+
+// String implements fmt.Stringer
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: mean %.3f -> %.3f, pass rate %.1f%% -> %.1f%%",
+		r.Metric, r.BaselineMean, r.Mean, r.BaselinePassRate*100, r.PassRate*100)
+}
+
+// Compare returns the metrics whose mean score or pass rate is lower than in
+// baseline by more than tolerance. Metrics missing from either report are ignored.
+func (r *Report) Compare(baseline *Report, tolerance float64) []Regression {
+	var regressions []Regression
+	for _, metric := range r.Metrics {
+		current := r.Summary[metric]
+		previous, ok := baseline.Summary[metric]
+		if !ok {
+			continue
+		}
+		if previous.Mean-current.Mean > tolerance || previous.PassRate-current.PassRate > tolerance {
+			regressions = append(regressions, Regression{
+				Metric:           metric,
+				BaselineMean:     previous.Mean,
+				Mean:             current.Mean,
+				BaselinePassRate: previous.PassRate,
+				PassRate:         current.PassRate,
+			})
+		}
+	}
+	return regressions
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// SaveJSON writes the report to a JSON file, e.g. to use it as a baseline
+func (r *Report) SaveJSON(path string) error {
+	file, err := os.Create(path) // #nosec G304 -- path is provided by the caller
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer file.Close()
+	return r.WriteJSON(file)
+}
+
+// LoadReport reads a report written by WriteJSON or SaveJSON
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the caller
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// WriteMarkdown writes a summary table and the failed cases as Markdown
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Evaluation: %s on %s\n\n", r.Target, r.Dataset)
+	fmt.Fprintf(&b, "%d cases in %s, %.1f%% passed", len(r.Results), r.Duration.Round(time.Millisecond), r.PassRate*100)
+	if r.Usage.TotalTokens > 0 {
+		fmt.Fprintf(&b, ", %d tokens", r.Usage.TotalTokens)
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString("| Metric | Mean | Pass rate | Passed | Failed | Errors |\n")
+	b.WriteString("|--------|------|-----------|--------|--------|--------|\n")
+	for _, metric := range r.Metrics {
+		s := r.Summary[metric]
+		fmt.Fprintf(&b, "| %s | %.3f | %.1f%% | %d | %d | %d |\n", metric, s.Mean, s.PassRate*100, s.Passed, s.Failed, s.Errors)
+	}
+
+	if failures := r.Failures(); len(failures) > 0 {
+		b.WriteString("\n## Failures\n\n")
+		for _, result := range failures {
+			fmt.Fprintf(&b, "### %s\n\n", result.CaseID)
+			fmt.Fprintf(&b, "- Input: %s\n", oneLine(result.Input))
+			if result.Error != "" {
+				fmt.Fprintf(&b, "- Error: %s\n\n", oneLine(result.Error))
+				continue
+			}
+			fmt.Fprintf(&b, "- Output: %s\n", oneLine(result.Output))
+
+			metrics := make([]string, 0, len(result.Scores)+len(result.MetricErrors))
+			for metric := range result.Scores {
+				metrics = append(metrics, metric)
+			}
+			for metric := range result.MetricErrors {
+				metrics = append(metrics, metric)
+			}
+			sort.Strings(metrics)
+			for _, metric := range metrics {
+				if errMsg, ok := result.MetricErrors[metric]; ok {
+					fmt.Fprintf(&b, "- %s: error: %s\n", metric, oneLine(errMsg))
+					continue
+				}
+				score := result.Scores[metric]
+				if score.Passed {
+					continue
+				}
+				fmt.Fprintf(&b, "- %s: %.3f", metric, score.Value)
+				if score.Reason != "" {
+					fmt.Fprintf(&b, " (%s)", oneLine(score.Reason))
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func oneLine(s string) string {
+	return truncate(strings.Join(strings.Fields(s), " "), 300)
+}
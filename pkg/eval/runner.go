@@ -0,0 +1,197 @@
+// Package eval evaluates agents and LLMs against datasets of example inputs,
+// so changes to prompts, system messages or providers can be regression
+// tested.
+//
+//	dataset, _ := eval.LoadDataset("testdata/support.yaml")
+//	runner := eval.NewRunner(myAgent, []eval.Metric{
+//		eval.Contains(),
+//		eval.LLMJudge(judgeLLM),
+//		eval.Latency(5 * time.Second),
+//	}, eval.WithConcurrency(4))
+//
+//	report, _ := runner.Run(ctx, dataset)
+//	_ = report.WriteMarkdown(os.Stdout)
+package eval
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// Runner runs the cases of a dataset against a target and scores them
+type Runner struct {
+	target      Target
+	metrics     []Metric
+	name        string
+	concurrency int
+	timeout     time.Duration
+	logger      logging.Logger
+}
+
+// Option represents an option for configuring the runner
+type Option func(*Runner)
+
+// WithName sets the name of the target in reports (default: "target")
+func WithName(name string) Option {
+	return func(r *Runner) {
+		r.name = name
+	}
+}
+
+// WithConcurrency sets how many cases run in parallel (default: 1)
+func WithConcurrency(concurrency int) Option {
+	return func(r *Runner) {
+		if concurrency > 0 {
+			r.concurrency = concurrency
+		}
+	}
+}
+
+// WithTimeout sets a timeout for each case, including scoring
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *Runner) {
+		r.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the runner
+func WithLogger(logger logging.Logger) Option {
+	return func(r *Runner) {
+		r.logger = logger
+	}
+}
+
+// NewRunner creates a runner scoring the outputs of target with metrics
+func NewRunner(target Target, metrics []Metric, options ...Option) *Runner {
+	r := &Runner{
+		target:      target,
+		metrics:     metrics,
+		name:        "target",
+		concurrency: 1,
+		logger:      logging.New(),
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// Run evaluates every case of dataset. Failed target runs and metric errors
+// are reported per case; Run only returns an error when ctx is cancelled.
+func (r *Runner) Run(ctx context.Context, dataset *Dataset) (*Report, error) {
+	if err := dataset.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid dataset: %w", err)
+	}
+
+	report := &Report{
+		Dataset:   dataset.Name,
+		Target:    r.name,
+		StartedAt: time.Now(),
+		Results:   make([]Result, len(dataset.Cases)),
+	}
+	for _, metric := range r.metrics {
+		report.Metrics = append(report.Metrics, metric.Name())
+	}
+
+	semaphore := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+	for i, c := range dataset.Cases {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int, c Case) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			report.Results[i] = r.runCase(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report.Duration = time.Since(report.StartedAt)
+	report.summarize()
+	return report, nil
+}
+
+// runCase runs a case against the target and scores the output
+func (r *Runner) runCase(ctx context.Context, c Case) Result {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	response, err := r.target.RunDetailed(ctx, c.Input)
+	sample := Sample{Case: c, Latency: time.Since(start), Error: err}
+	if response != nil {
+		sample.Output = response.Content
+		sample.Model = response.Model
+		sample.Usage = response.Usage
+	}
+
+	result := Result{
+		CaseID:    c.ID,
+		Input:     c.Input,
+		Output:    sample.Output,
+		Model:     sample.Model,
+		Usage:     sample.Usage,
+		LatencyMs: sample.Latency.Milliseconds(),
+		Tags:      c.Tags,
+		Scores:    make(map[string]Score, len(r.metrics)),
+	}
+	if err != nil {
+		result.Error = err.Error()
+		r.logger.Warn(ctx, "Evaluation case failed", map[string]interface{}{"case": c.ID, "error": err.Error()})
+		return result
+	}
+
+	for _, metric := range r.metrics {
+		score, err := metric.Score(ctx, sample)
+		if err != nil {
+			if result.MetricErrors == nil {
+				result.MetricErrors = make(map[string]string)
+			}
+			result.MetricErrors[metric.Name()] = err.Error()
+			continue
+		}
+		result.Scores[metric.Name()] = score
+	}
+	return result
+}
+
+// RunTargets evaluates several targets, e.g. the same agent on different
+// providers, on dataset and returns one report per target, ordered by name
+func RunTargets(ctx context.Context, dataset *Dataset, targets map[string]Target, metrics []Metric, options ...Option) ([]*Report, error) {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	reports := make([]*Report, 0, len(targets))
+	for _, name := range names {
+		runner := NewRunner(targets[name], metrics, append(options, WithName(name))...)
+		report, err := runner.Run(ctx, dataset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate %s: %w", name, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,39 @@
+package eval
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Target is the system under evaluation. *agent.Agent implements it.
+type Target interface {
+	RunDetailed(ctx context.Context, input string) (*interfaces.AgentResponse, error)
+}
+
+// TargetFunc adapts a function to a Target
+type TargetFunc func(ctx context.Context, input string) (*interfaces.AgentResponse, error)
+
+// RunDetailed implements Target.RunDetailed
+func (f TargetFunc) RunDetailed(ctx context.Context, input string) (*interfaces.AgentResponse, error) {
+	return f(ctx, input)
+}
+
+// LLMTarget evaluates an LLM directly, which is useful to compare providers
+// on the same prompts without an agent
+func LLMTarget(llm interfaces.LLM, options ...interfaces.GenerateOption) Target {
+	return TargetFunc(func(ctx context.Context, input string) (*interfaces.AgentResponse, error) {
+		response, err := llm.GenerateDetailed(ctx, input, options...)
+		if err != nil {
+			return nil, err
+		}
+		return &interfaces.AgentResponse{
+			Content: response.Content,
+			Usage:   response.Usage,
+			Model:   response.Model,
+			ExecutionSummary: interfaces.ExecutionSummary{
+				LLMCalls: 1,
+			},
+		}, nil
+	})
+}
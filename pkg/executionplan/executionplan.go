@@ -23,6 +23,8 @@ const (
 	StatusApproved ExecutionPlanStatus = "approved"
 	// StatusExecuting indicates the plan is currently executing
 	StatusExecuting ExecutionPlanStatus = "executing"
+	// StatusPaused indicates the plan execution is paused between steps
+	StatusPaused ExecutionPlanStatus = "paused"
 	// StatusCompleted indicates the plan has completed execution
 	StatusCompleted ExecutionPlanStatus = "completed"
 	// StatusFailed indicates the plan execution failed
@@ -47,6 +49,8 @@ type ExecutionPlan struct {
 	CreatedAt time.Time
 	// UpdatedAt is the time when the plan was last updated
 	UpdatedAt time.Time
+	// Replans is the number of times the plan was revised after a failed step
+	Replans int
 }
 
 // ExecutionStep represents a single step in an execution plan
@@ -3,91 +3,381 @@ package executionplan
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 )
 
+// ErrPlanCancelled is returned when a plan is cancelled during execution
+var ErrPlanCancelled = errors.New("execution plan was cancelled")
+
+// StepEventType is the type of a StepEvent
+type StepEventType string
+
+const (
+	// StepStarted is sent before a step runs
+	StepStarted StepEventType = "step_started"
+	// StepToolInvoked is sent when the tool of a step is called, with its input
+	StepToolInvoked StepEventType = "tool_invoked"
+	// StepCompleted is sent with the result of a step
+	StepCompleted StepEventType = "step_completed"
+	// StepFailed is sent with the error of a step
+	StepFailed StepEventType = "step_failed"
+	// StepReplanned is sent when the steps from a failed one on were
+	// replaced by a revised plan, with the error of the failed step
+	StepReplanned StepEventType = "step_replanned"
+)
+
+// StepEvent reports the progress of a plan execution
+type StepEvent struct {
+	Type StepEventType
+	// TaskID identifies the plan
+	TaskID string
+	// Step is the number of the step, starting at 1
+	Step       int
+	TotalSteps int
+	// Description and ToolName describe the step
+	Description string
+	ToolName    string
+	// Input is the tool input of tool_invoked events, and of step_failed
+	// events when the tool was invoked
+	Input string
+	// Result is the tool result of a step_completed event
+	Result string
+	// Error is the error of a step_failed or step_replanned event
+	Error error
+	// Progress is the percentage of the steps completed, from 0 to 100
+	Progress float64
+}
+
+// StepFailure describes a failed step to a Replanner
+type StepFailure struct {
+	// Step is the number of the failed step, starting at 1
+	Step  int
+	Error error
+	// Results are the results of the steps completed before, formatted as
+	// in the output of the plan
+	Results []string
+}
+
+// Replanner revises a plan after one of its steps failed. The steps of the
+// returned plan replace the failed step and the steps after it.
+type Replanner interface {
+	ReplanExecutionPlan(ctx context.Context, plan *ExecutionPlan, failure StepFailure) (*ExecutionPlan, error)
+}
+
+// ReplannerFunc adapts a function to the Replanner interface
+type ReplannerFunc func(ctx context.Context, plan *ExecutionPlan, failure StepFailure) (*ExecutionPlan, error)
+
+// ReplanExecutionPlan implements Replanner
+func (f ReplannerFunc) ReplanExecutionPlan(ctx context.Context, plan *ExecutionPlan, failure StepFailure) (*ExecutionPlan, error) {
+	return f(ctx, plan, failure)
+}
+
+// ExecutorOption configures an Executor
+type ExecutorOption func(*Executor)
+
+// WithReplanning makes the executor ask replanner for a revised plan when a
+// step fails, instead of failing the plan, up to maxReplans times per
+// execution
+func WithReplanning(replanner Replanner, maxReplans int) ExecutorOption {
+	return func(e *Executor) {
+		e.replanner = replanner
+		e.maxReplans = maxReplans
+	}
+}
+
 // Executor handles execution of execution plans
 type Executor struct {
-	tools map[string]interfaces.Tool
+	tools      map[string]interfaces.Tool
+	replanner  Replanner
+	maxReplans int
+
+	// mu guards the status of the plans being executed and their controls
+	mu       sync.Mutex
+	controls map[string]*planControl
+}
+
+// planControl pauses and cancels a plan between its steps
+type planControl struct {
+	paused    bool
+	cancelled bool
+	// changed is closed and replaced when the plan is paused, resumed or
+	// cancelled
+	changed chan struct{}
 }
 
 // NewExecutor creates a new execution plan executor
-func NewExecutor(tools []interfaces.Tool) *Executor {
+func NewExecutor(tools []interfaces.Tool, options ...ExecutorOption) *Executor {
 	toolMap := make(map[string]interfaces.Tool)
 	for _, tool := range tools {
 		toolMap[tool.Name()] = tool
 	}
 
-	return &Executor{
-		tools: toolMap,
+	e := &Executor{
+		tools:    toolMap,
+		controls: make(map[string]*planControl),
+	}
+	for _, option := range options {
+		option(e)
 	}
+	return e
 }
 
 // ExecutePlan executes an approved execution plan
 func (e *Executor) ExecutePlan(ctx context.Context, plan *ExecutionPlan) (string, error) {
+	return e.ExecutePlanWithProgress(ctx, plan, nil)
+}
+
+// ExecutePlanWithProgress executes an approved execution plan, calling
+// onEvent (when not nil) as each step starts, invokes its tool and ends.
+// Between steps, the plan waits while paused with PausePlan and stops with
+// ErrPlanCancelled when cancelled with CancelPlan. With WithReplanning, a
+// failed step is followed by a step_replanned event and the revised steps.
+func (e *Executor) ExecutePlanWithProgress(ctx context.Context, plan *ExecutionPlan, onEvent func(StepEvent)) (string, error) {
 	if !plan.UserApproved {
 		return "", fmt.Errorf("execution plan has not been approved by the user")
 	}
+	if onEvent == nil {
+		onEvent = func(StepEvent) {}
+	}
 
-	// Update status to executing
+	control := &planControl{changed: make(chan struct{})}
+	e.mu.Lock()
+	e.controls[plan.TaskID] = control
 	plan.Status = StatusExecuting
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		delete(e.controls, plan.TaskID)
+		e.mu.Unlock()
+	}()
 
 	// Execute each step in the plan
 	results := make([]string, 0, len(plan.Steps))
-	for i, step := range plan.Steps {
-		// Get the tool
-		tool, ok := e.tools[step.ToolName]
-		if !ok {
-			plan.Status = StatusFailed
-			return "", fmt.Errorf("unknown tool: %s", step.ToolName)
+	replans := 0
+	for i := 0; i < len(plan.Steps); i++ {
+		step := plan.Steps[i]
+		if err := e.waitForStep(ctx, plan, control); err != nil {
+			return "", err
 		}
 
-		// Marshal parameters to JSON for the Execute method
-		// This ensures tools receive the expected JSON format
-		var inputJSON string
-		if len(step.Parameters) > 0 {
-			jsonBytes, err := json.Marshal(step.Parameters)
-			if err != nil {
-				plan.Status = StatusFailed
-				return "", fmt.Errorf("failed to marshal parameters for step %d: %w", i+1, err)
-			}
-			inputJSON = string(jsonBytes)
-		} else if step.Input != "" {
-			// Fallback to step.Input if no parameters are provided
-			// This maintains backward compatibility
-			inputJSON = step.Input
-		} else {
-			// If neither parameters nor input is provided, use empty JSON object
-			inputJSON = "{}"
+		event := StepEvent{
+			TaskID:      plan.TaskID,
+			Step:        i + 1,
+			TotalSteps:  len(plan.Steps),
+			Description: step.Description,
+			ToolName:    step.ToolName,
+			Progress:    progress(i, len(plan.Steps)),
 		}
+		started := event
+		started.Type = StepStarted
+		onEvent(started)
 
-		// Execute the tool with JSON input
-		result, err := tool.Execute(ctx, inputJSON)
+		var input string
+		result, err := e.executeStep(ctx, i, step, func(toolInput string) {
+			input = toolInput
+			invoked := event
+			invoked.Type = StepToolInvoked
+			invoked.Input = input
+			onEvent(invoked)
+		})
 		if err != nil {
-			plan.Status = StatusFailed
-			return "", fmt.Errorf("failed to execute step %d: %w", i+1, err)
+			failed := event
+			failed.Type = StepFailed
+			failed.Input = input
+			failed.Error = err
+			onEvent(failed)
+
+			if e.replanner == nil || replans >= e.maxReplans || ctx.Err() != nil {
+				e.setStatus(plan, StatusFailed)
+				return "", err
+			}
+			if replanErr := e.replan(ctx, plan, StepFailure{Step: i + 1, Error: err, Results: results}); replanErr != nil {
+				e.setStatus(plan, StatusFailed)
+				return "", fmt.Errorf("%w (re-planning failed: %v)", err, replanErr)
+			}
+			replans++
+
+			onEvent(StepEvent{
+				Type:        StepReplanned,
+				TaskID:      plan.TaskID,
+				Step:        i + 1,
+				TotalSteps:  len(plan.Steps),
+				Description: plan.Description,
+				Error:       err,
+				Progress:    progress(i, len(plan.Steps)),
+			})
+			// Run the revised steps from the failed one
+			i--
+			continue
 		}
 
+		completed := event
+		completed.Type = StepCompleted
+		completed.Result = result
+		completed.Progress = progress(i+1, len(plan.Steps))
+		onEvent(completed)
+
 		// Add the result to the list of results
 		results = append(results, fmt.Sprintf("Step %d (%s): %s", i+1, step.Description, result))
 	}
 
 	// Update status to completed
-	plan.Status = StatusCompleted
+	e.setStatus(plan, StatusCompleted)
 
 	// Format the results
 	return fmt.Sprintf("Execution plan completed successfully!\n\n%s", strings.Join(results, "\n\n")), nil
 }
 
-// CancelPlan cancels an execution plan
+// replan replaces the steps of plan from the failed one with those of a
+// revised plan
+func (e *Executor) replan(ctx context.Context, plan *ExecutionPlan, failure StepFailure) error {
+	revised, err := e.replanner.ReplanExecutionPlan(ctx, plan, failure)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	done := failure.Step - 1
+	plan.Steps = append(plan.Steps[:done:done], revised.Steps...)
+	if revised.Description != "" {
+		plan.Description = revised.Description
+	}
+	plan.Replans++
+	plan.UpdatedAt = time.Now()
+	return nil
+}
+
+// executeStep runs the tool of a step, calling invoked with its input first
+func (e *Executor) executeStep(ctx context.Context, i int, step ExecutionStep, invoked func(input string)) (string, error) {
+	// Get the tool
+	tool, ok := e.tools[step.ToolName]
+	if !ok {
+		return "", fmt.Errorf("unknown tool: %s", step.ToolName)
+	}
+
+	// Marshal parameters to JSON for the Execute method
+	// This ensures tools receive the expected JSON format
+	var inputJSON string
+	if len(step.Parameters) > 0 {
+		jsonBytes, err := json.Marshal(step.Parameters)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal parameters for step %d: %w", i+1, err)
+		}
+		inputJSON = string(jsonBytes)
+	} else if step.Input != "" {
+		// Fallback to step.Input if no parameters are provided
+		// This maintains backward compatibility
+		inputJSON = step.Input
+	} else {
+		// If neither parameters nor input is provided, use empty JSON object
+		inputJSON = "{}"
+	}
+
+	// Execute the tool with JSON input
+	invoked(inputJSON)
+	result, err := tool.Execute(ctx, inputJSON)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute step %d: %w", i+1, err)
+	}
+	return result, nil
+}
+
+// waitForStep returns once the next step may run: immediately unless the
+// plan is paused, or with an error when the plan is cancelled or ctx done
+func (e *Executor) waitForStep(ctx context.Context, plan *ExecutionPlan, control *planControl) error {
+	for {
+		e.mu.Lock()
+		paused, cancelled, changed := control.paused, control.cancelled, control.changed
+		e.mu.Unlock()
+
+		switch {
+		case cancelled:
+			return ErrPlanCancelled
+		case ctx.Err() != nil:
+			e.setStatus(plan, StatusFailed)
+			return ctx.Err()
+		case !paused:
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// progress returns the percentage of done steps out of total
+func progress(done, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(done) * 100 / float64(total)
+}
+
+// setStatus updates the status of a plan
+func (e *Executor) setStatus(plan *ExecutionPlan, status ExecutionPlanStatus) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	plan.Status = status
+}
+
+// PausePlan pauses an executing plan before its next step. The step in
+// progress finishes.
+func (e *Executor) PausePlan(plan *ExecutionPlan) error {
+	return e.control(plan, func(control *planControl) {
+		control.paused = true
+		plan.Status = StatusPaused
+	})
+}
+
+// ResumePlan resumes a paused plan
+func (e *Executor) ResumePlan(plan *ExecutionPlan) error {
+	return e.control(plan, func(control *planControl) {
+		control.paused = false
+		plan.Status = StatusExecuting
+	})
+}
+
+// control applies change to the control of an executing plan
+func (e *Executor) control(plan *ExecutionPlan, change func(*planControl)) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	control, ok := e.controls[plan.TaskID]
+	if !ok {
+		return fmt.Errorf("execution plan %s is not executing", plan.TaskID)
+	}
+	change(control)
+	close(control.changed)
+	control.changed = make(chan struct{})
+	return nil
+}
+
+// CancelPlan cancels an execution plan. An executing plan stops before its
+// next step.
 func (e *Executor) CancelPlan(plan *ExecutionPlan) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	plan.Status = StatusCancelled
+	if control, ok := e.controls[plan.TaskID]; ok {
+		control.cancelled = true
+		close(control.changed)
+		control.changed = make(chan struct{})
+	}
 }
 
 // GetPlanStatus returns the status of an execution plan
 func (e *Executor) GetPlanStatus(plan *ExecutionPlan) ExecutionPlanStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	return plan.Status
 }
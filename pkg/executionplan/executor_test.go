@@ -3,8 +3,10 @@ package executionplan
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 )
@@ -310,7 +312,204 @@ func TestExecutePlan_ToolExecuteError(t *testing.T) {
 	}
 }
 
+// blockingTool waits for a signal before returning
+type blockingTool struct {
+	mockTool
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingTool) Execute(ctx context.Context, args string) (string, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return "done", nil
+}
+
+func TestExecutePlanWithProgress(t *testing.T) {
+	executor := NewExecutor([]interfaces.Tool{
+		&mockTool{name: "search", executeResult: "results"},
+		&mockTool{name: "summarize", executeErr: fmt.Errorf("too long")},
+	})
+
+	plan := NewExecutionPlan("Research", []ExecutionStep{
+		{ToolName: "search", Description: "Search", Parameters: map[string]interface{}{"query": "go"}},
+		{ToolName: "search", Description: "Search again"},
+		{ToolName: "summarize", Description: "Summarize"},
+	})
+	plan.UserApproved = true
+
+	var events []StepEvent
+	_, err := executor.ExecutePlanWithProgress(context.Background(), plan, func(event StepEvent) {
+		events = append(events, event)
+	})
+	if err == nil || err.Error() != "failed to execute step 3: too long" {
+		t.Fatalf("expected the error of step 3, got %v", err)
+	}
+	if plan.Status != StatusFailed {
+		t.Errorf("expected status failed, got %s", plan.Status)
+	}
+
+	var types []StepEventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	expected := []StepEventType{
+		StepStarted, StepToolInvoked, StepCompleted,
+		StepStarted, StepToolInvoked, StepCompleted,
+		StepStarted, StepToolInvoked, StepFailed,
+	}
+	if fmt.Sprint(types) != fmt.Sprint(expected) {
+		t.Fatalf("expected events %v, got %v", expected, types)
+	}
+
+	if events[1].Input != `{"query":"go"}` || events[4].Input != "{}" {
+		t.Errorf("unexpected tool inputs %q and %q", events[1].Input, events[4].Input)
+	}
+	if events[2].Result != "results" || events[2].Step != 1 || events[2].TotalSteps != 3 {
+		t.Errorf("unexpected completed event %+v", events[2])
+	}
+	if events[0].Progress != 0 || events[5].Progress < 66 || events[5].Progress > 67 || events[8].Error == nil {
+		t.Errorf("unexpected progress %v, %v or error %v", events[0].Progress, events[5].Progress, events[8].Error)
+	}
+}
+
+func TestExecutePlanPauseAndCancel(t *testing.T) {
+	tool := &blockingTool{
+		mockTool: mockTool{name: "slow"},
+		started:  make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+	executor := NewExecutor([]interfaces.Tool{tool})
+
+	plan := NewExecutionPlan("Slow work", []ExecutionStep{
+		{ToolName: "slow", Description: "First"},
+		{ToolName: "slow", Description: "Second"},
+	})
+	plan.UserApproved = true
+
+	if err := executor.PausePlan(plan); err == nil {
+		t.Error("expected an error pausing a plan that is not executing")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := executor.ExecutePlanWithProgress(context.Background(), plan, nil)
+		done <- err
+	}()
+
+	// Pause during the first step: the second one does not start
+	<-tool.started
+	if err := executor.PausePlan(plan); err != nil {
+		t.Fatalf("PausePlan: %v", err)
+	}
+	tool.release <- struct{}{}
+	select {
+	case <-tool.started:
+		t.Fatal("the second step started while the plan was paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if status := executor.GetPlanStatus(plan); status != StatusPaused {
+		t.Errorf("expected status paused, got %s", status)
+	}
+
+	executor.CancelPlan(plan)
+	if err := <-done; !errors.Is(err, ErrPlanCancelled) {
+		t.Fatalf("expected ErrPlanCancelled, got %v", err)
+	}
+	if status := executor.GetPlanStatus(plan); status != StatusCancelled {
+		t.Errorf("expected status cancelled, got %s", status)
+	}
+
+	// A resumed plan runs its remaining steps
+	plan = NewExecutionPlan("Slow work", []ExecutionStep{
+		{ToolName: "slow", Description: "First"},
+		{ToolName: "slow", Description: "Second"},
+	})
+	plan.UserApproved = true
+	go func() {
+		_, err := executor.ExecutePlanWithProgress(context.Background(), plan, nil)
+		done <- err
+	}()
+	<-tool.started
+	if err := executor.PausePlan(plan); err != nil {
+		t.Fatalf("PausePlan: %v", err)
+	}
+	tool.release <- struct{}{}
+	if err := executor.ResumePlan(plan); err != nil {
+		t.Fatalf("ResumePlan: %v", err)
+	}
+	<-tool.started
+	tool.release <- struct{}{}
+	if err := <-done; err != nil || plan.Status != StatusCompleted {
+		t.Fatalf("expected a completed plan, got %v, %s", err, plan.Status)
+	}
+}
+
 // Helper function to check if a string contains a substring
 func testContains(s, substr string) bool {
 	return len(s) >= len(substr) && s[:len(substr)] == substr || len(s) > len(substr) && testContains(s[1:], substr)
 }
+
+func TestExecutePlanReplanning(t *testing.T) {
+	executor := NewExecutor([]interfaces.Tool{
+		&mockTool{name: "search", executeResult: "results"},
+		&mockTool{name: "scrape", executeErr: fmt.Errorf("403 forbidden")},
+	}, WithReplanning(ReplannerFunc(func(ctx context.Context, plan *ExecutionPlan, failure StepFailure) (*ExecutionPlan, error) {
+		if failure.Step != 2 || failure.Error == nil || len(failure.Results) != 1 {
+			t.Errorf("unexpected failure %+v", failure)
+		}
+		return NewExecutionPlan("Search instead of scraping", []ExecutionStep{
+			{ToolName: "search", Description: "Search the cached page"},
+		}), nil
+	}), 1))
+
+	plan := NewExecutionPlan("Read the page", []ExecutionStep{
+		{ToolName: "search", Description: "Find the page"},
+		{ToolName: "scrape", Description: "Scrape the page"},
+		{ToolName: "scrape", Description: "Scrape the next page"},
+	})
+	plan.UserApproved = true
+
+	var types []StepEventType
+	result, err := executor.ExecutePlanWithProgress(context.Background(), plan, func(event StepEvent) {
+		types = append(types, event.Type)
+	})
+	if err != nil {
+		t.Fatalf("expected the revised plan to succeed, got %v", err)
+	}
+	if plan.Status != StatusCompleted || plan.Replans != 1 || len(plan.Steps) != 2 || plan.Steps[1].Description != "Search the cached page" {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+	if !testContains(result, "Step 2 (Search the cached page): results") {
+		t.Errorf("unexpected result %q", result)
+	}
+	expected := []StepEventType{
+		StepStarted, StepToolInvoked, StepCompleted,
+		StepStarted, StepToolInvoked, StepFailed, StepReplanned,
+		StepStarted, StepToolInvoked, StepCompleted,
+	}
+	if fmt.Sprint(types) != fmt.Sprint(expected) {
+		t.Errorf("expected events %v, got %v", expected, types)
+	}
+}
+
+func TestExecutePlanReplanningLimit(t *testing.T) {
+	replans := 0
+	executor := NewExecutor([]interfaces.Tool{
+		&mockTool{name: "scrape", executeErr: fmt.Errorf("403 forbidden")},
+	}, WithReplanning(ReplannerFunc(func(ctx context.Context, plan *ExecutionPlan, failure StepFailure) (*ExecutionPlan, error) {
+		replans++
+		return NewExecutionPlan("Try again", []ExecutionStep{{ToolName: "scrape", Description: "Retry"}}), nil
+	}), 2))
+
+	plan := NewExecutionPlan("Scrape", []ExecutionStep{{ToolName: "scrape", Description: "Scrape"}})
+	plan.UserApproved = true
+
+	_, err := executor.ExecutePlan(context.Background(), plan)
+	if err == nil || plan.Status != StatusFailed {
+		t.Fatalf("expected the plan to fail, got %v, %s", err, plan.Status)
+	}
+	if replans != 2 || plan.Replans != 2 {
+		t.Errorf("expected 2 replans, got %d (plan %d)", replans, plan.Replans)
+	}
+}
@@ -3,6 +3,7 @@ package executionplan
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/llm/openai"
@@ -126,3 +127,77 @@ Modified Execution Plan:
 
 	return modifiedPlan, nil
 }
+
+// ReplanExecutionPlan revises a plan after one of its steps failed. The
+// returned plan holds the steps to run instead of the failed step and the
+// steps after it.
+func (g *Generator) ReplanExecutionPlan(ctx context.Context, plan *ExecutionPlan, failure StepFailure) (*ExecutionPlan, error) {
+	completed := "None"
+	if len(failure.Results) > 0 {
+		completed = strings.Join(failure.Results, "\n\n")
+	}
+
+	// Create a prompt for the LLM to revise the rest of the plan
+	prompt := fmt.Sprintf(`
+You are an AI assistant that revises execution plans when a step fails.
+Here is the current execution plan:
+
+%s
+
+Results of the completed steps:
+%s
+
+Step %d failed with the following error:
+%s
+
+Available tools:
+%s
+Create the steps to run from step %d on, to accomplish the plan despite the failure. Do not repeat the completed steps. You may retry the failed step with different parameters, use other tools, or return no steps if the plan cannot or need not continue. Return them in the same JSON format:
+{
+  "description": "High-level description of what the plan will accomplish",
+  "steps": [
+    {
+      "toolName": "Name of the tool to use",
+      "description": "Description of what this step will accomplish",
+      "input": "Input to provide to the tool",
+      "parameters": {
+        "param1": "value1",
+        "param2": "value2"
+      }
+    }
+  ]
+}
+
+Revised Steps:
+`, FormatExecutionPlan(plan), completed, failure.Step, failure.Error, toolList(g.tools), failure.Step)
+
+	// Add system prompt as a generate option
+	generateOptions := []interfaces.GenerateOption{}
+	if g.systemPrompt != "" {
+		generateOptions = append(generateOptions, openai.WithSystemMessage(g.systemPrompt))
+	}
+
+	// Generate the revised steps using the LLM
+	response, err := g.llm.Generate(ctx, prompt, generateOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate revised execution plan: %w", err)
+	}
+
+	revised, err := ParseExecutionPlanFromResponse(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse revised execution plan: %w", err)
+	}
+
+	// Preserve the task ID from the original plan
+	revised.TaskID = plan.TaskID
+	return revised, nil
+}
+
+// toolList lists the names and descriptions of tools, one per line
+func toolList(tools []interfaces.Tool) string {
+	var sb strings.Builder
+	for _, tool := range tools {
+		fmt.Fprintf(&sb, "- %s: %s\n", tool.Name(), tool.Description())
+	}
+	return sb.String()
+}
@@ -0,0 +1,346 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/grpc/pb"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// LoadBalancingStrategy selects the replica serving a request
+type LoadBalancingStrategy string
+
+const (
+	// RoundRobin sends requests to the replicas in turn
+	RoundRobin LoadBalancingStrategy = "round_robin"
+
+	// LeastLoaded sends requests to the replica with the fewest requests in
+	// flight
+	LeastLoaded LoadBalancingStrategy = "least_loaded"
+)
+
+// DefaultPoolHealthCheckInterval is how often a pool checks its replicas
+const DefaultPoolHealthCheckInterval = 10 * time.Second
+
+// RemoteAgentPoolConfig configures a pool of replicas of the same agent
+// service
+type RemoteAgentPoolConfig struct {
+	// URLs are the addresses of the replicas
+	URLs []string
+
+	// Strategy selects the replica serving a request; defaults to RoundRobin
+	Strategy LoadBalancingStrategy
+
+	// Timeout bounds each request, as in RemoteAgentConfig
+	Timeout time.Duration
+
+	// HealthCheckInterval is how often replicas are checked; unhealthy
+	// replicas get no requests until they pass a check again. Defaults to
+	// DefaultPoolHealthCheckInterval, negative disables the checks.
+	HealthCheckInterval time.Duration
+}
+
+// replica is a replica of a pool
+type replica struct {
+	client *RemoteAgentClient
+
+	// mu serializes connecting, which RemoteAgentClient does not guard
+	mu       sync.Mutex
+	healthy  atomic.Bool
+	inFlight atomic.Int64
+}
+
+// connect connects the replica if it is not connected yet
+func (r *replica) connect() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.client.ensureConnected()
+}
+
+// check runs a gRPC health check of the replica
+func (r *replica) check(ctx context.Context) error {
+	if err := r.connect(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r.mu.Lock()
+	conn := r.client.conn
+	r.mu.Unlock()
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("replica %s is %s", r.client.url, resp.Status)
+	}
+	return nil
+}
+
+// RemoteAgentPool spreads requests across replicas of the same agent
+// service. Replicas failing a health check, or a request with an unavailable
+// error, are ejected until they pass a health check again; requests to an
+// ejected replica are retried on the next one.
+type RemoteAgentPool struct {
+	replicas []*replica
+	strategy LoadBalancingStrategy
+	interval time.Duration
+	next     atomic.Uint64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	started  sync.Once
+}
+
+// NewRemoteAgentPool creates a pool of the replicas of config.URLs
+func NewRemoteAgentPool(config RemoteAgentPoolConfig) *RemoteAgentPool {
+	if config.Strategy == "" {
+		config.Strategy = RoundRobin
+	}
+	if config.HealthCheckInterval == 0 {
+		config.HealthCheckInterval = DefaultPoolHealthCheckInterval
+	}
+
+	pool := &RemoteAgentPool{
+		strategy: config.Strategy,
+		interval: config.HealthCheckInterval,
+		stop:     make(chan struct{}),
+	}
+	for _, url := range config.URLs {
+		r := &replica{client: NewRemoteAgentClient(RemoteAgentConfig{
+			URL:     url,
+			Timeout: config.Timeout,
+			// The pool retries on the other replicas instead
+			RetryCount: 1,
+		})}
+		r.healthy.Store(true)
+		pool.replicas = append(pool.replicas, r)
+	}
+	return pool
+}
+
+// Connect connects to the replicas and starts checking their health. It
+// fails only when no replica can be reached.
+func (p *RemoteAgentPool) Connect() error {
+	if len(p.replicas) == 0 {
+		return fmt.Errorf("remote agent pool has no replicas")
+	}
+
+	var wg sync.WaitGroup
+	for _, r := range p.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.healthy.Store(r.connect() == nil)
+		}()
+	}
+	wg.Wait()
+
+	if p.interval > 0 {
+		p.started.Do(func() { go p.monitor() })
+	}
+
+	if p.HealthyReplicas() == 0 {
+		return fmt.Errorf("failed to connect to any of %d replicas", len(p.replicas))
+	}
+	return nil
+}
+
+// Disconnect stops the health checks and closes the connections to the
+// replicas
+func (p *RemoteAgentPool) Disconnect() error {
+	p.stopOnce.Do(func() { close(p.stop) })
+
+	var firstErr error
+	for _, r := range p.replicas {
+		r.mu.Lock()
+		if err := r.client.Disconnect(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		r.mu.Unlock()
+	}
+	return firstErr
+}
+
+// HealthyReplicas returns the number of replicas receiving requests
+func (p *RemoteAgentPool) HealthyReplicas() int {
+	healthy := 0
+	for _, r := range p.replicas {
+		if r.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// URLs returns the addresses of the replicas
+func (p *RemoteAgentPool) URLs() []string {
+	urls := make([]string, len(p.replicas))
+	for i, r := range p.replicas {
+		urls[i] = r.client.url
+	}
+	return urls
+}
+
+// monitor checks the health of the replicas until the pool is disconnected
+func (p *RemoteAgentPool) monitor() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.checkHealth()
+		}
+	}
+}
+
+// checkHealth ejects the replicas failing a health check and restores the
+// ones passing it
+func (p *RemoteAgentPool) checkHealth() {
+	var wg sync.WaitGroup
+	for _, r := range p.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.healthy.Store(r.check(context.Background()) == nil)
+		}()
+	}
+	wg.Wait()
+}
+
+// pick returns the replicas to try in order, starting with the one selected
+// by the strategy. When every replica is ejected, all of them are tried, as
+// they may have recovered since the last health check.
+func (p *RemoteAgentPool) pick() []*replica {
+	n := len(p.replicas)
+	start := int(p.next.Add(1)-1) % n
+
+	var candidates []*replica
+	for i := 0; i < n; i++ {
+		if r := p.replicas[(start+i)%n]; r.healthy.Load() {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 {
+		for i := 0; i < n; i++ {
+			candidates = append(candidates, p.replicas[(start+i)%n])
+		}
+	}
+
+	if p.strategy == LeastLoaded {
+		least := 0
+		for i, r := range candidates {
+			if r.inFlight.Load() < candidates[least].inFlight.Load() {
+				least = i
+			}
+		}
+		candidates[0], candidates[least] = candidates[least], candidates[0]
+	}
+	return candidates
+}
+
+// do runs call on the selected replica, failing over to the next ones when a
+// replica cannot be reached
+func (p *RemoteAgentPool) do(ctx context.Context, call func(r *replica) error) error {
+	if len(p.replicas) == 0 {
+		return fmt.Errorf("remote agent pool has no replicas")
+	}
+
+	var err error
+	for _, r := range p.pick() {
+		if err = r.connect(); err == nil {
+			r.inFlight.Add(1)
+			err = call(r)
+			r.inFlight.Add(-1)
+		}
+		if err == nil || ctx.Err() != nil || !isUnavailable(err) {
+			return err
+		}
+		r.healthy.Store(false)
+	}
+	return err
+}
+
+// isUnavailable reports whether err shows that a replica cannot be reached,
+// rather than that the agent failed
+func isUnavailable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// Run executes the agent on a replica
+func (p *RemoteAgentPool) Run(ctx context.Context, input string) (string, error) {
+	var output string
+	err := p.do(ctx, func(r *replica) error {
+		var err error
+		output, err = r.client.Run(ctx, input)
+		return err
+	})
+	return output, err
+}
+
+// RunWithAuth executes the agent on a replica with an explicit auth token
+func (p *RemoteAgentPool) RunWithAuth(ctx context.Context, input string, authToken string) (string, error) {
+	var output string
+	err := p.do(ctx, func(r *replica) error {
+		var err error
+		output, err = r.client.RunWithAuth(ctx, input, authToken)
+		return err
+	})
+	return output, err
+}
+
+// RunStream executes the agent on a replica with streaming response
+func (p *RemoteAgentPool) RunStream(ctx context.Context, input string) (<-chan interfaces.AgentStreamEvent, error) {
+	return p.RunStreamWithAuth(ctx, input, "")
+}
+
+// RunStreamWithAuth executes the agent on a replica with streaming response
+// and an explicit auth token. The replica counts as loaded until the stream
+// ends.
+func (p *RemoteAgentPool) RunStreamWithAuth(ctx context.Context, input string, authToken string) (<-chan interfaces.AgentStreamEvent, error) {
+	var events <-chan interfaces.AgentStreamEvent
+	err := p.do(ctx, func(r *replica) error {
+		var err error
+		events, err = r.client.RunStreamWithAuth(ctx, input, authToken)
+		if err != nil {
+			return err
+		}
+
+		r.inFlight.Add(1)
+		forwarded := make(chan interfaces.AgentStreamEvent, cap(events))
+		go func() {
+			defer close(forwarded)
+			defer r.inFlight.Add(-1)
+			for event := range events {
+				forwarded <- event
+			}
+		}()
+		events = forwarded
+		return nil
+	})
+	return events, err
+}
+
+// GetMetadata retrieves the metadata of the agent from a replica
+func (p *RemoteAgentPool) GetMetadata(ctx context.Context) (*pb.MetadataResponse, error) {
+	var metadata *pb.MetadataResponse
+	err := p.do(ctx, func(r *replica) error {
+		var err error
+		metadata, err = r.client.GetMetadata(ctx)
+		return err
+	})
+	return metadata, err
+}
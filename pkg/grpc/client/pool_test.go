@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/grpc/pb"
+)
+
+// fakeReplica is an agent service answering with its own name
+type fakeReplica struct {
+	pb.UnimplementedAgentServiceServer
+
+	name   string
+	server *grpc.Server
+	health *health.Server
+	addr   string
+
+	mu   sync.Mutex
+	runs int
+}
+
+func startFakeReplica(t *testing.T, name string) *fakeReplica {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	f := &fakeReplica{name: name, server: grpc.NewServer(), health: health.NewServer(), addr: listener.Addr().String()}
+	pb.RegisterAgentServiceServer(f.server, f)
+	grpc_health_v1.RegisterHealthServer(f.server, f.health)
+	go func() { _ = f.server.Serve(listener) }()
+	t.Cleanup(f.server.Stop)
+	return f
+}
+
+func (f *fakeReplica) Run(ctx context.Context, req *pb.RunRequest) (*pb.RunResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs++
+	return &pb.RunResponse{Output: f.name}, nil
+}
+
+func (f *fakeReplica) runCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.runs
+}
+
+func newTestPool(t *testing.T, strategy LoadBalancingStrategy, replicas ...*fakeReplica) *RemoteAgentPool {
+	t.Helper()
+	var urls []string
+	for _, r := range replicas {
+		urls = append(urls, r.addr)
+	}
+	pool := NewRemoteAgentPool(RemoteAgentPoolConfig{URLs: urls, Strategy: strategy, HealthCheckInterval: -1})
+	if err := pool.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { _ = pool.Disconnect() })
+	return pool
+}
+
+func TestRemoteAgentPool_RoundRobin(t *testing.T) {
+	replicas := []*fakeReplica{startFakeReplica(t, "a"), startFakeReplica(t, "b"), startFakeReplica(t, "c")}
+	pool := newTestPool(t, RoundRobin, replicas...)
+
+	for i := 0; i < 6; i++ {
+		if _, err := pool.Run(context.Background(), "hello"); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+	for _, r := range replicas {
+		if r.runCount() != 2 {
+			t.Errorf("Expected replica %s to serve 2 runs, got %d", r.name, r.runCount())
+		}
+	}
+}
+
+func TestRemoteAgentPool_LeastLoaded(t *testing.T) {
+	pool := newTestPool(t, LeastLoaded, startFakeReplica(t, "a"), startFakeReplica(t, "b"), startFakeReplica(t, "c"))
+
+	pool.replicas[0].inFlight.Store(2)
+	pool.replicas[2].inFlight.Store(1)
+	for i := 0; i < 3; i++ {
+		if picked := pool.pick()[0]; picked != pool.replicas[1] {
+			t.Errorf("Expected the least loaded replica, got %s", picked.client.url)
+		}
+	}
+}
+
+func TestRemoteAgentPool_FailsOver(t *testing.T) {
+	down := startFakeReplica(t, "down")
+	up := startFakeReplica(t, "up")
+	pool := newTestPool(t, RoundRobin, down, up)
+
+	down.server.Stop()
+	for i := 0; i < 4; i++ {
+		output, err := pool.Run(context.Background(), "hello")
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if output != "up" {
+			t.Errorf("Expected the healthy replica to answer, got %q", output)
+		}
+	}
+	if healthy := pool.HealthyReplicas(); healthy != 1 {
+		t.Errorf("Expected the unreachable replica to be ejected, got %d healthy replicas", healthy)
+	}
+}
+
+func TestRemoteAgentPool_HealthCheckEjection(t *testing.T) {
+	draining := startFakeReplica(t, "draining")
+	serving := startFakeReplica(t, "serving")
+	pool := newTestPool(t, RoundRobin, draining, serving)
+
+	draining.health.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	pool.checkHealth()
+	if healthy := pool.HealthyReplicas(); healthy != 1 {
+		t.Fatalf("Expected 1 healthy replica, got %d", healthy)
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := pool.Run(context.Background(), "hello"); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+	if draining.runCount() != 0 {
+		t.Errorf("Expected no runs on the ejected replica, got %d", draining.runCount())
+	}
+
+	draining.health.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	pool.checkHealth()
+	if healthy := pool.HealthyReplicas(); healthy != 2 {
+		t.Errorf("Expected the replica to be restored, got %d healthy replicas", healthy)
+	}
+}
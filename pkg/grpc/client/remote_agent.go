@@ -2,8 +2,10 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"time"
 
@@ -398,71 +400,7 @@ func (r *RemoteAgentClient) RunStream(ctx context.Context, input string) (<-chan
 		return nil, fmt.Errorf("failed to start stream: %w", err)
 	}
 
-	// Create event channel
-	eventChan := make(chan interfaces.AgentStreamEvent, 100)
-
-	// Start goroutine to handle streaming response
-	go func() {
-		defer cancel()
-		defer close(eventChan)
-
-		// Recover from any panics in the streaming goroutine
-		defer func() {
-			if r := recover(); r != nil {
-				eventChan <- interfaces.AgentStreamEvent{
-					Type:      interfaces.AgentEventError,
-					Error:     fmt.Errorf("stream panic recovered: %v", r),
-					Timestamp: time.Now(),
-				}
-			}
-		}()
-
-		for {
-			resp, err := stream.Recv()
-			if err != nil {
-				if err == io.EOF {
-					// Stream completed normally
-					eventChan <- interfaces.AgentStreamEvent{
-						Type:      interfaces.AgentEventComplete,
-						Timestamp: time.Now(),
-					}
-					return
-				}
-				// Stream error
-				eventChan <- interfaces.AgentStreamEvent{
-					Type:      interfaces.AgentEventError,
-					Error:     fmt.Errorf("stream error: %w", err),
-					Timestamp: time.Now(),
-				}
-				return
-			}
-
-			// Check for nil response to prevent panic
-			if resp == nil {
-				eventChan <- interfaces.AgentStreamEvent{
-					Type:      interfaces.AgentEventError,
-					Error:     fmt.Errorf("received nil response from stream"),
-					Timestamp: time.Now(),
-				}
-				return
-			}
-
-			if resp.Error != "" {
-				eventChan <- interfaces.AgentStreamEvent{
-					Type:      interfaces.AgentEventError,
-					Error:     fmt.Errorf("remote agent error: %s", resp.Error),
-					Timestamp: time.Now(),
-				}
-				return
-			}
-
-			// Convert gRPC response to AgentStreamEvent
-			event := convertPbToStreamEvent(resp)
-			eventChan <- event
-		}
-	}()
-
-	return eventChan, nil
+	return receiveEvents(stream, cancel), nil
 }
 
 // RunStreamWithAuth executes the remote agent with streaming response and explicit auth token
@@ -503,10 +441,14 @@ func (r *RemoteAgentClient) RunStreamWithAuth(ctx context.Context, input string,
 		return nil, fmt.Errorf("failed to start stream: %w", err)
 	}
 
-	// Create event channel
+	return receiveEvents(stream, cancel), nil
+}
+
+// receiveEvents converts the responses of a RunStream call to agent events,
+// ending with a single complete event like local streams
+func receiveEvents(stream grpc.ServerStreamingClient[pb.RunStreamResponse], cancel context.CancelFunc) <-chan interfaces.AgentStreamEvent {
 	eventChan := make(chan interfaces.AgentStreamEvent, 100)
 
-	// Start goroutine to handle streaming response
 	go func() {
 		defer cancel()
 		defer close(eventChan)
@@ -522,14 +464,17 @@ func (r *RemoteAgentClient) RunStreamWithAuth(ctx context.Context, input string,
 			}
 		}()
 
+		completed := false
 		for {
 			resp, err := stream.Recv()
 			if err != nil {
 				if err == io.EOF {
 					// Stream completed normally
-					eventChan <- interfaces.AgentStreamEvent{
-						Type:      interfaces.AgentEventComplete,
-						Timestamp: time.Now(),
+					if !completed {
+						eventChan <- interfaces.AgentStreamEvent{
+							Type:      interfaces.AgentEventComplete,
+							Timestamp: time.Now(),
+						}
 					}
 					return
 				}
@@ -561,13 +506,19 @@ func (r *RemoteAgentClient) RunStreamWithAuth(ctx context.Context, input string,
 				return
 			}
 
+			// Older servers send a second complete event after the agent's
+			if completed && resp.EventType == pb.EventType_EVENT_TYPE_COMPLETE {
+				continue
+			}
+
 			// Convert gRPC response to AgentStreamEvent
 			event := convertPbToStreamEvent(resp)
+			completed = completed || event.Type == interfaces.AgentEventComplete
 			eventChan <- event
 		}
 	}()
 
-	return eventChan, nil
+	return eventChan
 }
 
 // OnThinking registers a handler for thinking events
@@ -690,13 +641,13 @@ func (r *RemoteAgentClient) executeHandlers(event interfaces.AgentStreamEvent) {
 func convertPbToStreamEvent(resp *pb.RunStreamResponse) interfaces.AgentStreamEvent {
 	event := interfaces.AgentStreamEvent{
 		Content:   resp.Chunk,
-		Timestamp: time.Unix(resp.Timestamp, 0),
+		Timestamp: time.UnixMilli(resp.Timestamp),
 		Metadata:  make(map[string]interface{}),
 	}
 
-	// Copy metadata
+	// Copy metadata, decoding the values the server encoded as JSON
 	for k, v := range resp.Metadata {
-		event.Metadata[k] = v
+		event.Metadata[k] = decodeMetadataValue(v)
 	}
 
 	// Convert event type
@@ -747,3 +698,16 @@ func convertPbToStreamEvent(resp *pb.RunStreamResponse) interfaces.AgentStreamEv
 
 	return event
 }
+
+// decodeMetadataValue decodes JSON objects and arrays, which servers send for
+// metadata values that are not strings
+func decodeMetadataValue(value string) interface{} {
+	if !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "[") {
+		return value
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		return value
+	}
+	return decoded
+}
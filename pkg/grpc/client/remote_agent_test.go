@@ -86,17 +86,17 @@ func TestRemoteAgentClient_RunStream(t *testing.T) {
 		{
 			EventType: pb.EventType_EVENT_TYPE_CONTENT,
 			Chunk:     "Hello",
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_CONTENT,
 			Chunk:     " World",
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_COMPLETE,
 			IsFinal:   true,
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 	}
 
@@ -126,9 +126,9 @@ func TestRemoteAgentClient_RunStream(t *testing.T) {
 		events = append(events, event)
 	}
 
-	// Verify events - should have 2 content events + 1 complete from mock + 1 auto-complete from stream end
-	if len(events) != 4 {
-		t.Fatalf("Expected 4 events, got %d", len(events))
+	// Verify events - should have 2 content events + 1 complete, without a second complete at stream end
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
 	}
 
 	// Check first content event
@@ -147,13 +147,10 @@ func TestRemoteAgentClient_RunStream(t *testing.T) {
 		t.Errorf("Expected second event content to be ' World', got '%s'", events[1].Content)
 	}
 
-	// Check complete events (both from mock and auto-generated)
+	// Check complete event
 	if events[2].Type != interfaces.AgentEventComplete {
 		t.Errorf("Expected third event type to be complete, got %s", events[2].Type)
 	}
-	if events[3].Type != interfaces.AgentEventComplete {
-		t.Errorf("Expected fourth event type to be complete, got %s", events[3].Type)
-	}
 }
 
 func TestRemoteAgentClient_RunStreamWithAuth(t *testing.T) {
@@ -162,17 +159,17 @@ func TestRemoteAgentClient_RunStreamWithAuth(t *testing.T) {
 		{
 			EventType: pb.EventType_EVENT_TYPE_THINKING,
 			Thinking:  "Let me think about this...",
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_CONTENT,
 			Chunk:     "Authenticated response",
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_COMPLETE,
 			IsFinal:   true,
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 	}
 
@@ -209,9 +206,9 @@ func TestRemoteAgentClient_RunStreamWithAuth(t *testing.T) {
 		events = append(events, event)
 	}
 
-	// Verify events - should have thinking + content + complete from mock + auto-complete from stream end
-	if len(events) != 4 {
-		t.Fatalf("Expected 4 events, got %d", len(events))
+	// Verify events - should have thinking + content + complete, without a second complete at stream end
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
 	}
 
 	// Check thinking event
@@ -230,13 +227,10 @@ func TestRemoteAgentClient_RunStreamWithAuth(t *testing.T) {
 		t.Errorf("Expected content 'Authenticated response', got '%s'", events[1].Content)
 	}
 
-	// Check complete events (both from mock and auto-generated)
+	// Check complete event
 	if events[2].Type != interfaces.AgentEventComplete {
 		t.Errorf("Expected third event type to be complete, got %s", events[2].Type)
 	}
-	if events[3].Type != interfaces.AgentEventComplete {
-		t.Errorf("Expected fourth event type to be complete, got %s", events[3].Type)
-	}
 }
 
 func TestRemoteAgentClient_RunStreamWithAuth_ErrorHandling(t *testing.T) {
@@ -264,7 +258,7 @@ func TestRemoteAgentClient_RunStreamWithAuth_ErrorHandling(t *testing.T) {
 			{
 				EventType: pb.EventType_EVENT_TYPE_ERROR,
 				Error:     "Remote agent error occurred",
-				Timestamp: time.Now().Unix(),
+				Timestamp: time.Now().UnixMilli(),
 			},
 		},
 	}
@@ -304,7 +298,7 @@ func TestRemoteAgentClient_RunStreamWithAuth_ToolCallEvent(t *testing.T) {
 				Arguments: `{"operation": "add", "a": 2, "b": 3}`,
 				Status:    "executing",
 			},
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_TOOL_RESULT,
@@ -314,12 +308,12 @@ func TestRemoteAgentClient_RunStreamWithAuth_ToolCallEvent(t *testing.T) {
 				Result: "5",
 				Status: "completed",
 			},
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_COMPLETE,
 			IsFinal:   true,
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 	}
 
@@ -349,9 +343,9 @@ func TestRemoteAgentClient_RunStreamWithAuth_ToolCallEvent(t *testing.T) {
 		events = append(events, event)
 	}
 
-	// Verify events - should have tool_call + tool_result + complete from mock + auto-complete from stream end
-	if len(events) != 4 {
-		t.Fatalf("Expected 4 events, got %d", len(events))
+	// Verify events - should have tool_call + tool_result + complete, without a second complete at stream end
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
 	}
 
 	// Check tool call event
@@ -379,17 +373,14 @@ func TestRemoteAgentClient_RunStreamWithAuth_ToolCallEvent(t *testing.T) {
 		t.Errorf("Expected tool result '5', got '%s'", events[1].ToolCall.Result)
 	}
 
-	// Check complete events (both from mock and auto-generated)
+	// Check complete event
 	if events[2].Type != interfaces.AgentEventComplete {
 		t.Errorf("Expected third event type to be complete, got %s", events[2].Type)
 	}
-	if events[3].Type != interfaces.AgentEventComplete {
-		t.Errorf("Expected fourth event type to be complete, got %s", events[3].Type)
-	}
 }
 
 func TestConvertPbToStreamEvent(t *testing.T) {
-	timestamp := time.Now().Unix()
+	timestamp := time.Now().UnixMilli()
 
 	tests := []struct {
 		name     string
@@ -407,7 +398,7 @@ func TestConvertPbToStreamEvent(t *testing.T) {
 			expected: interfaces.AgentStreamEvent{
 				Type:      interfaces.AgentEventContent,
 				Content:   "test content",
-				Timestamp: time.Unix(timestamp, 0),
+				Timestamp: time.UnixMilli(timestamp),
 				Metadata:  map[string]any{"key": "value"},
 			},
 		},
@@ -421,7 +412,7 @@ func TestConvertPbToStreamEvent(t *testing.T) {
 			expected: interfaces.AgentStreamEvent{
 				Type:         interfaces.AgentEventThinking,
 				ThinkingStep: "thinking step",
-				Timestamp:    time.Unix(timestamp, 0),
+				Timestamp:    time.UnixMilli(timestamp),
 				Metadata:     map[string]any{},
 			},
 		},
@@ -479,12 +470,12 @@ func TestRemoteAgentClient_EventHandlers(t *testing.T) {
 		{
 			EventType: pb.EventType_EVENT_TYPE_THINKING,
 			Thinking:  "Let me analyze this problem...",
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_CONTENT,
 			Chunk:     "Here's my response",
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_TOOL_CALL,
@@ -494,12 +485,12 @@ func TestRemoteAgentClient_EventHandlers(t *testing.T) {
 				Arguments: `{"query": "test"}`,
 				Status:    "executing",
 			},
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_COMPLETE,
 			IsFinal:   true,
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 	}
 
@@ -579,9 +570,9 @@ func TestRemoteAgentClient_EventHandlers(t *testing.T) {
 		t.Errorf("Expected tool call name 'search', got '%s'", toolCallCalls[0].Name)
 	}
 
-	// Should have 2 complete calls: 1 from mock response + 1 auto-generated
-	if completeCalls != 2 {
-		t.Errorf("Expected 2 complete calls, got %d", completeCalls)
+	// Should have 1 complete call, like local streams
+	if completeCalls != 1 {
+		t.Errorf("Expected 1 complete call, got %d", completeCalls)
 	}
 	mu.Unlock()
 }
@@ -592,12 +583,12 @@ func TestRemoteAgentClient_StreamWithAuthHandlers(t *testing.T) {
 		{
 			EventType: pb.EventType_EVENT_TYPE_CONTENT,
 			Chunk:     "Authenticated response",
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 		{
 			EventType: pb.EventType_EVENT_TYPE_COMPLETE,
 			IsFinal:   true,
-			Timestamp: time.Now().Unix(),
+			Timestamp: time.Now().UnixMilli(),
 		},
 	}
 
@@ -716,7 +707,7 @@ func (m *mockPanicStreamServer) Recv() (*pb.RunStreamResponse, error) {
 	return &pb.RunStreamResponse{
 		EventType: pb.EventType_EVENT_TYPE_CONTENT,
 		Chunk:     "test content",
-		Timestamp: time.Now().Unix(),
+		Timestamp: time.Now().UnixMilli(),
 	}, nil
 }
 
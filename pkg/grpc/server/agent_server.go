@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"strings"
@@ -160,24 +161,22 @@ func (s *AgentServer) RunStream(req *pb.RunRequest, stream pb.AgentService_RunSt
 	}
 
 	// Stream events to client
+	completed := false
 	for event := range eventChan {
+		timestamp := event.Timestamp
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
 		response := &pb.RunStreamResponse{
 			Chunk:     event.Content,
 			EventType: s.convertEventType(event.Type),
 			IsFinal:   false,
-			Timestamp: event.Timestamp.UnixMilli(),
+			Timestamp: timestamp.UnixMilli(),
 		}
 
 		// Add metadata if present
 		if event.Metadata != nil {
-			response.Metadata = make(map[string]string)
-			for k, v := range event.Metadata {
-				if str, ok := v.(string); ok {
-					response.Metadata[k] = str
-				} else {
-					response.Metadata[k] = fmt.Sprintf("%v", v)
-				}
-			}
+			response.Metadata = encodeMetadata(event.Metadata)
 		}
 
 		// Add tool call info if present
@@ -209,6 +208,7 @@ func (s *AgentServer) RunStream(req *pb.RunRequest, stream pb.AgentService_RunSt
 		if event.Type == interfaces.AgentEventComplete {
 			response.IsFinal = true
 			response.EventType = pb.EventType_EVENT_TYPE_COMPLETE
+			completed = true
 		}
 
 		// Send the event
@@ -217,6 +217,10 @@ func (s *AgentServer) RunStream(req *pb.RunRequest, stream pb.AgentService_RunSt
 		}
 	}
 
+	if completed {
+		return nil
+	}
+
 	// Send final completion if we haven't already
 	finalResponse := &pb.RunStreamResponse{
 		IsFinal:   true,
@@ -247,6 +251,22 @@ func (s *AgentServer) convertEventType(eventType interfaces.AgentEventType) pb.E
 	}
 }
 
+// encodeMetadata converts event metadata to strings. Values that are not
+// strings, such as the artifacts of a run, are encoded as JSON.
+func encodeMetadata(values map[string]interface{}) map[string]string {
+	encoded := make(map[string]string, len(values))
+	for k, v := range values {
+		if str, ok := v.(string); ok {
+			encoded[k] = str
+		} else if data, err := json.Marshal(v); err == nil {
+			encoded[k] = string(data)
+		} else {
+			encoded[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return encoded
+}
+
 // GetMetadata returns agent metadata
 func (s *AgentServer) GetMetadata(ctx context.Context, req *pb.MetadataRequest) (*pb.MetadataResponse, error) {
 	// Get LLM information
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+)
+
+func TestRunStreamRemoteParity(t *testing.T) {
+	start := time.Now()
+	local, err := agent.NewAgent(
+		agent.WithLLM(mock.New()),
+		agent.WithName("researcher"),
+		agent.WithCustomRunStreamFunction(func(ctx context.Context, input string, a *agent.Agent) (<-chan interfaces.AgentStreamEvent, error) {
+			events := make(chan interfaces.AgentStreamEvent, 4)
+			events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventThinking, ThinkingStep: "searching", Timestamp: time.Now()}
+			events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventToolCall, ToolCall: &interfaces.ToolCallEvent{ID: "call-1", Name: "search", Status: "executing"}}
+			events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: "found it", Timestamp: time.Now()}
+			events <- interfaces.AgentStreamEvent{
+				Type:      interfaces.AgentEventComplete,
+				Metadata:  map[string]interface{}{"sources": []string{"a", "b"}, "model": "mock"},
+				Timestamp: time.Now(),
+			}
+			close(events)
+			return events, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := NewAgentServer(local)
+	go func() { _ = server.StartWithListener(listener) }()
+	defer server.Stop()
+
+	remote, err := agent.NewAgent(agent.WithURL(listener.Addr().String()), agent.WithName("remote-researcher"))
+	if err != nil {
+		t.Fatalf("Failed to create remote agent: %v", err)
+	}
+
+	events, err := remote.RunStream(context.Background(), "find it")
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	var received []interfaces.AgentStreamEvent
+	for event := range events {
+		received = append(received, event)
+	}
+
+	wantTypes := []interfaces.AgentEventType{
+		interfaces.AgentEventThinking,
+		interfaces.AgentEventToolCall,
+		interfaces.AgentEventContent,
+		interfaces.AgentEventComplete,
+	}
+	if len(received) != len(wantTypes) {
+		t.Fatalf("Expected %d events like the local stream, got %+v", len(wantTypes), received)
+	}
+	for i, event := range received {
+		if event.Type != wantTypes[i] {
+			t.Errorf("Event %d: expected type %s, got %s", i, wantTypes[i], event.Type)
+		}
+		if event.Timestamp.Before(start.Truncate(time.Millisecond)) || event.Timestamp.After(time.Now()) {
+			t.Errorf("Event %d: unexpected timestamp %v", i, event.Timestamp)
+		}
+	}
+	if received[0].ThinkingStep != "searching" || received[1].ToolCall == nil || received[1].ToolCall.Name != "search" || received[2].Content != "found it" {
+		t.Errorf("Unexpected event contents %+v", received)
+	}
+
+	metadata := received[3].Metadata
+	if sources, ok := metadata["sources"].([]interface{}); !ok || len(sources) != 2 || sources[0] != "a" {
+		t.Errorf("Expected structured metadata to survive the stream, got %#v", metadata["sources"])
+	}
+	if metadata["model"] != "mock" {
+		t.Errorf("Expected string metadata unchanged, got %#v", metadata["model"])
+	}
+}
+
+func TestRemoteAgentReplicas(t *testing.T) {
+	var addrs []string
+	var servers []*AgentServer
+	for _, name := range []string{"replica-1", "replica-2"} {
+		local, err := agent.NewAgent(
+			agent.WithLLM(mock.New()),
+			agent.WithName(name),
+			agent.WithCustomRunFunction(func(ctx context.Context, input string, a *agent.Agent) (string, error) {
+				return a.GetName(), nil
+			}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create agent: %v", err)
+		}
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Failed to listen: %v", err)
+		}
+		server := NewAgentServer(local)
+		go func() { _ = server.StartWithListener(listener) }()
+		defer server.Stop()
+		addrs = append(addrs, listener.Addr().String())
+		servers = append(servers, server)
+	}
+
+	remote, err := agent.NewAgent(agent.WithURLs(addrs...), agent.WithName("researcher"))
+	if err != nil {
+		t.Fatalf("Failed to create remote agent: %v", err)
+	}
+	defer func() { _ = remote.Disconnect() }()
+
+	served := map[string]int{}
+	for i := 0; i < 4; i++ {
+		output, err := remote.Run(context.Background(), "hello")
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		served[output]++
+	}
+	if served["replica-1"] != 2 || served["replica-2"] != 2 {
+		t.Errorf("Expected the runs to be spread across the replicas, got %v", served)
+	}
+
+	// Runs keep succeeding on the remaining replica
+	servers[0].Stop()
+	for i := 0; i < 2; i++ {
+		output, err := remote.Run(context.Background(), "hello")
+		if err != nil {
+			t.Fatalf("Run failed after a replica stopped: %v", err)
+		}
+		if output != "replica-2" {
+			t.Errorf("Expected the remaining replica to answer, got %q", output)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 )
 
@@ -92,7 +93,7 @@ func (p *Pipeline) ProcessRequest(ctx context.Context, request string) (string,
 
 			switch guardrail.Action() {
 			case BlockAction:
-				return "", fmt.Errorf("request blocked by %s guardrail", guardrail.Type())
+				return "", &interfaces.GuardrailViolation{Guardrail: string(guardrail.Type()), Stage: "request"}
 			case RedactAction:
 				processedRequest = modified
 			case WarnAction:
@@ -131,7 +132,7 @@ func (p *Pipeline) ProcessResponse(ctx context.Context, response string) (string
 
 			switch guardrail.Action() {
 			case BlockAction:
-				return "", fmt.Errorf("response blocked by %s guardrail", guardrail.Type())
+				return "", &interfaces.GuardrailViolation{Guardrail: string(guardrail.Type()), Stage: "response"}
 			case RedactAction:
 				processedResponse = modified
 			case WarnAction:
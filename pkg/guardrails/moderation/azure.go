@@ -0,0 +1,101 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// azureMaxSeverity is the highest severity Azure AI Content Safety reports
+// with the FourSeverityLevels output type. Severities are scaled to [0, 1].
+const azureMaxSeverity = 6
+
+// AzureContentSafety moderates text with Azure AI Content Safety
+type AzureContentSafety struct {
+	endpoint   string
+	apiKey     string
+	apiVersion string
+	httpClient *http.Client
+}
+
+// AzureOption configures the Azure AI Content Safety backend
+type AzureOption func(*AzureContentSafety)
+
+// WithAzureAPIVersion sets the API version (default: 2023-10-01)
+func WithAzureAPIVersion(version string) AzureOption {
+	return func(a *AzureContentSafety) {
+		a.apiVersion = version
+	}
+}
+
+// WithAzureHTTPClient sets the HTTP client used for requests
+func WithAzureHTTPClient(client *http.Client) AzureOption {
+	return func(a *AzureContentSafety) {
+		a.httpClient = client
+	}
+}
+
+// NewAzureContentSafety creates an Azure AI Content Safety backend for the
+// resource endpoint, e.g. https://<resource>.cognitiveservices.azure.com.
+// Severities (0, 2, 4 or 6) are reported as scores of 0 to 1, so a threshold
+// of 0.5 flags medium and high severities.
+func NewAzureContentSafety(endpoint, apiKey string, options ...AzureOption) *AzureContentSafety {
+	a := &AzureContentSafety{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		apiKey:     apiKey,
+		apiVersion: "2023-10-01",
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	for _, option := range options {
+		option(a)
+	}
+
+	return a
+}
+
+// Name implements Backend.Name
+func (a *AzureContentSafety) Name() string {
+	return "azure-content-safety"
+}
+
+type azureAnalyzeResponse struct {
+	CategoriesAnalysis []struct {
+		Category string `json:"category"`
+		Severity int    `json:"severity"`
+	} `json:"categoriesAnalysis"`
+}
+
+// Moderate implements Backend.Moderate
+func (a *AzureContentSafety) Moderate(ctx context.Context, text string) (*Result, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"text":       text,
+		"outputType": "FourSeverityLevels",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/contentsafety/text:analyze?api-version=%s", a.endpoint, a.apiVersion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ocp-Apim-Subscription-Key", a.apiKey)
+
+	var response azureAnalyzeResponse
+	if err := doJSON(a.httpClient, req, &response); err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]float64, len(response.CategoriesAnalysis))
+	for _, analysis := range response.CategoriesAnalysis {
+		scores[normalizeCategory(analysis.Category)] = float64(analysis.Severity) / azureMaxSeverity
+	}
+	return &Result{Scores: scores}, nil
+}
@@ -0,0 +1,289 @@
+// Package moderation provides a guardrail that checks agent inputs and
+// outputs with a content moderation service, such as the OpenAI Moderation
+// API or Azure AI Content Safety.
+//
+//	agent.WithGuardrails(moderation.New(
+//		moderation.NewOpenAI(os.Getenv("OPENAI_API_KEY")),
+//		moderation.WithThreshold("violence", 0.7),
+//		moderation.WithPolicy(moderation.PolicyBlock),
+//	))
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// DefaultThreshold is the score at or above which a category is flagged
+// when no threshold is set for it
+const DefaultThreshold = 0.5
+
+// Result is the outcome of moderating a text
+type Result struct {
+	// Scores maps each category to a score between 0 and 1. Category names
+	// are lower case, with subcategories separated by a slash, e.g.
+	// "violence" or "self_harm/intent".
+	Scores map[string]float64
+}
+
+// Flag is a category whose score reached its threshold
+type Flag struct {
+	Category string
+	Score    float64
+}
+
+// Backend moderates text with a moderation service
+type Backend interface {
+	// Name returns the name of the moderation service
+	Name() string
+
+	// Moderate scores text in each category the service supports
+	Moderate(ctx context.Context, text string) (*Result, error)
+}
+
+// Policy is what the guardrail does with flagged content
+type Policy string
+
+const (
+	// PolicyBlock rejects flagged content with an *interfaces.ContentFilterError
+	PolicyBlock Policy = "block"
+
+	// PolicyWarn lets flagged content through and logs a warning
+	PolicyWarn Policy = "warn"
+
+	// PolicyAnnotate lets flagged content through, prefixed with a note
+	// listing the flagged categories
+	PolicyAnnotate Policy = "annotate"
+)
+
+// Guardrail checks inputs and outputs with a moderation backend. It
+// implements interfaces.Guardrails.
+type Guardrail struct {
+	backend          Backend
+	policy           Policy
+	defaultThreshold float64
+	thresholds       map[string]float64
+	checkInput       bool
+	checkOutput      bool
+	logger           logging.Logger
+}
+
+// Option configures a moderation guardrail
+type Option func(*Guardrail)
+
+// WithPolicy sets what to do with flagged content (default: PolicyBlock)
+func WithPolicy(policy Policy) Option {
+	return func(g *Guardrail) {
+		g.policy = policy
+	}
+}
+
+// WithThreshold sets the score at or above which category is flagged. A
+// threshold set on a category also applies to its subcategories, unless
+// they have their own.
+func WithThreshold(category string, score float64) Option {
+	return func(g *Guardrail) {
+		g.thresholds[normalizeCategory(category)] = score
+	}
+}
+
+// WithDefaultThreshold sets the threshold of categories without their own
+// (default: DefaultThreshold)
+func WithDefaultThreshold(score float64) Option {
+	return func(g *Guardrail) {
+		g.defaultThreshold = score
+	}
+}
+
+// WithInputModeration sets whether user inputs are moderated (default: true)
+func WithInputModeration(enabled bool) Option {
+	return func(g *Guardrail) {
+		g.checkInput = enabled
+	}
+}
+
+// WithOutputModeration sets whether agent outputs are moderated (default: true)
+func WithOutputModeration(enabled bool) Option {
+	return func(g *Guardrail) {
+		g.checkOutput = enabled
+	}
+}
+
+// WithLogger sets the logger for the guardrail
+func WithLogger(logger logging.Logger) Option {
+	return func(g *Guardrail) {
+		g.logger = logger
+	}
+}
+
+// New creates a moderation guardrail using backend
+func New(backend Backend, options ...Option) *Guardrail {
+	g := &Guardrail{
+		backend:          backend,
+		policy:           PolicyBlock,
+		defaultThreshold: DefaultThreshold,
+		thresholds:       make(map[string]float64),
+		checkInput:       true,
+		checkOutput:      true,
+	}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	if g.logger == nil {
+		g.logger = logging.New()
+	}
+
+	return g
+}
+
+// ProcessInput implements interfaces.Guardrails.ProcessInput
+func (g *Guardrail) ProcessInput(ctx context.Context, input string) (string, error) {
+	if !g.checkInput {
+		return input, nil
+	}
+	return g.process(ctx, input, true)
+}
+
+// ProcessOutput implements interfaces.Guardrails.ProcessOutput
+func (g *Guardrail) ProcessOutput(ctx context.Context, output string) (string, error) {
+	if !g.checkOutput {
+		return output, nil
+	}
+	return g.process(ctx, output, false)
+}
+
+// Check moderates text and returns the flagged categories with their
+// scores, sorted by decreasing score
+func (g *Guardrail) Check(ctx context.Context, text string) ([]Flag, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	result, err := g.backend.Moderate(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("%s moderation failed: %w", g.backend.Name(), err)
+	}
+
+	var flags []Flag
+	for category, score := range result.Scores {
+		if score >= g.threshold(category) {
+			flags = append(flags, Flag{Category: category, Score: score})
+		}
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		if flags[i].Score != flags[j].Score {
+			return flags[i].Score > flags[j].Score
+		}
+		return flags[i].Category < flags[j].Category
+	})
+	return flags, nil
+}
+
+func (g *Guardrail) process(ctx context.Context, text string, input bool) (string, error) {
+	flags, err := g.Check(ctx, text)
+	if err != nil {
+		return "", err
+	}
+	if len(flags) == 0 {
+		return text, nil
+	}
+
+	target := "output"
+	if input {
+		target = "input"
+	}
+
+	switch g.policy {
+	case PolicyWarn:
+		g.logger.Warn(ctx, "Moderation flagged "+target, map[string]interface{}{
+			"backend":    g.backend.Name(),
+			"categories": describeFlags(flags),
+		})
+		return text, nil
+	case PolicyAnnotate:
+		g.logger.Info(ctx, "Moderation flagged "+target, map[string]interface{}{
+			"backend":    g.backend.Name(),
+			"categories": describeFlags(flags),
+		})
+		return fmt.Sprintf("[Moderation: flagged for %s]\n%s", describeFlags(flags), text), nil
+	default:
+		return "", &interfaces.ContentFilterError{
+			Provider:      g.backend.Name(),
+			Category:      filterCategory(flags[0].Category),
+			Message:       "flagged for " + describeFlags(flags),
+			StopReason:    "moderation",
+			PromptBlocked: input,
+		}
+	}
+}
+
+// threshold returns the threshold of category, falling back to the
+// threshold of its parent category and then to the default
+func (g *Guardrail) threshold(category string) float64 {
+	for {
+		if score, ok := g.thresholds[category]; ok {
+			return score
+		}
+		i := strings.LastIndex(category, "/")
+		if i < 0 {
+			return g.defaultThreshold
+		}
+		category = category[:i]
+	}
+}
+
+// normalizeCategory converts a service category name such as "Self-Harm"
+// or "SelfHarm" to the form used in results, e.g. "self_harm"
+func normalizeCategory(category string) string {
+	var b strings.Builder
+	for i, r := range category {
+		switch {
+		case r == '-' || r == ' ':
+			b.WriteByte('_')
+		case r >= 'A' && r <= 'Z':
+			if i > 0 && category[i-1] >= 'a' && category[i-1] <= 'z' {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r + ('a' - 'A'))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// filterCategory maps a moderation category to a content filter category
+func filterCategory(category string) interfaces.ContentFilterCategory {
+	parent, _, _ := strings.Cut(category, "/")
+	switch parent {
+	case "hate":
+		return interfaces.ContentFilterCategoryHate
+	case "harassment":
+		return interfaces.ContentFilterCategoryHarassment
+	case "sexual":
+		return interfaces.ContentFilterCategorySexual
+	case "violence":
+		return interfaces.ContentFilterCategoryViolence
+	case "self_harm":
+		return interfaces.ContentFilterCategorySelfHarm
+	case "illicit":
+		return interfaces.ContentFilterCategoryDangerous
+	default:
+		return interfaces.ContentFilterCategoryUnknown
+	}
+}
+
+func describeFlags(flags []Flag) string {
+	parts := make([]string, len(flags))
+	for i, flag := range flags {
+		parts[i] = fmt.Sprintf("%s (%.2f)", flag.Category, flag.Score)
+	}
+	return strings.Join(parts, ", ")
+}
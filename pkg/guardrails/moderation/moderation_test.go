@@ -0,0 +1,103 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+type staticBackend map[string]float64
+
+func (b staticBackend) Name() string { return "static" }
+
+func (b staticBackend) Moderate(ctx context.Context, text string) (*Result, error) {
+	return &Result{Scores: b}, nil
+}
+
+func TestPolicies(t *testing.T) {
+	backend := staticBackend{"violence": 0.8, "violence/graphic": 0.3, "hate": 0.1}
+
+	_, err := New(backend).ProcessInput(context.Background(), "text")
+	filterErr, ok := interfaces.AsContentFilterError(err)
+	if !ok {
+		t.Fatalf("expected a content filter error, got %v", err)
+	}
+	if filterErr.Category != interfaces.ContentFilterCategoryViolence || !filterErr.PromptBlocked {
+		t.Errorf("unexpected error: %+v", filterErr)
+	}
+
+	output, err := New(backend, WithPolicy(PolicyWarn)).ProcessOutput(context.Background(), "text")
+	if err != nil || output != "text" {
+		t.Errorf("expected warn to pass the text through, got %q, %v", output, err)
+	}
+
+	output, err = New(backend, WithPolicy(PolicyAnnotate)).ProcessOutput(context.Background(), "text")
+	if err != nil || output != "[Moderation: flagged for violence (0.80)]\ntext" {
+		t.Errorf("expected an annotation, got %q, %v", output, err)
+	}
+
+	output, err = New(backend, WithInputModeration(false)).ProcessInput(context.Background(), "text")
+	if err != nil || output != "text" {
+		t.Errorf("expected input moderation to be skipped, got %q, %v", output, err)
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	backend := staticBackend{"violence": 0.4, "violence/graphic": 0.3, "self_harm": 0.2}
+	guardrail := New(backend, WithThreshold("Violence", 0.25), WithThreshold("self-harm", 0.9))
+
+	flags, err := guardrail.Check(context.Background(), "text")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(flags) != 2 || flags[0].Category != "violence" || flags[1].Category != "violence/graphic" {
+		t.Errorf("expected violence and its subcategory to be flagged, got %+v", flags)
+	}
+}
+
+func TestOpenAIBackend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/moderations" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s with authorization %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["input"] != "hello" || body["model"] != "omni-moderation-latest" {
+			t.Errorf("unexpected body: %v", body)
+		}
+		_, _ = w.Write([]byte(`{"results":[{"flagged":true,"category_scores":{"self-harm/intent":0.9,"hate":0.01}}]}`))
+	}))
+	defer server.Close()
+
+	result, err := NewOpenAI("key", WithOpenAIBaseURL(server.URL)).Moderate(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Scores["self_harm/intent"] != 0.9 || result.Scores["hate"] != 0.01 {
+		t.Errorf("unexpected scores: %v", result.Scores)
+	}
+}
+
+func TestAzureContentSafetyBackend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/contentsafety/text:analyze" || r.URL.Query().Get("api-version") != "2023-10-01" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "key" {
+			t.Errorf("missing subscription key header")
+		}
+		_, _ = w.Write([]byte(`{"categoriesAnalysis":[{"category":"Hate","severity":0},{"category":"SelfHarm","severity":4}]}`))
+	}))
+	defer server.Close()
+
+	guardrail := New(NewAzureContentSafety(server.URL+"/", "key"))
+	_, err := guardrail.ProcessOutput(context.Background(), "hello")
+	if err == nil || !strings.Contains(err.Error(), "self_harm (0.67)") {
+		t.Errorf("expected self harm to be blocked, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OpenAI moderates text with the OpenAI Moderation API
+type OpenAI struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// OpenAIOption configures the OpenAI moderation backend
+type OpenAIOption func(*OpenAI)
+
+// WithOpenAIModel sets the moderation model (default: omni-moderation-latest)
+func WithOpenAIModel(model string) OpenAIOption {
+	return func(o *OpenAI) {
+		o.model = model
+	}
+}
+
+// WithOpenAIBaseURL sets the API base URL (default: https://api.openai.com/v1)
+func WithOpenAIBaseURL(baseURL string) OpenAIOption {
+	return func(o *OpenAI) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithOpenAIHTTPClient sets the HTTP client used for requests
+func WithOpenAIHTTPClient(client *http.Client) OpenAIOption {
+	return func(o *OpenAI) {
+		o.httpClient = client
+	}
+}
+
+// NewOpenAI creates an OpenAI Moderation API backend
+func NewOpenAI(apiKey string, options ...OpenAIOption) *OpenAI {
+	o := &OpenAI{
+		apiKey:     apiKey,
+		model:      "omni-moderation-latest",
+		baseURL:    "https://api.openai.com/v1",
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	return o
+}
+
+// Name implements Backend.Name
+func (o *OpenAI) Name() string {
+	return "openai-moderation"
+}
+
+type openAIModerationResponse struct {
+	Results []struct {
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
+
+// Moderate implements Backend.Moderate
+func (o *OpenAI) Moderate(ctx context.Context, text string) (*Result, error) {
+	body, err := json.Marshal(map[string]string{
+		"model": o.model,
+		"input": text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/moderations", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	var response openAIModerationResponse
+	if err := doJSON(o.httpClient, req, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Results) == 0 {
+		return nil, fmt.Errorf("no moderation results in response")
+	}
+
+	scores := make(map[string]float64, len(response.Results[0].CategoryScores))
+	for category, score := range response.Results[0].CategoryScores {
+		scores[normalizeCategory(category)] = score
+	}
+	return &Result{Scores: scores}, nil
+}
+
+// doJSON sends req and decodes a successful JSON response into v
+func doJSON(client *http.Client, req *http.Request, v interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(data))
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
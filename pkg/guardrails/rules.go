@@ -0,0 +1,291 @@
+package guardrails
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Stage is a point of an agent run where rules are applied
+type Stage string
+
+const (
+	// StageInput applies to user input before it is sent to the LLM
+	StageInput Stage = "input"
+
+	// StageToolCall applies to the arguments of tool calls before the tool runs
+	StageToolCall Stage = "tool_call"
+
+	// StageOutput applies to the final response before it is returned
+	StageOutput Stage = "output"
+)
+
+// RuleAction is what a rule does when it matches
+type RuleAction string
+
+const (
+	// RuleAllow accepts the content and skips the remaining rules
+	RuleAllow RuleAction = "allow"
+
+	// RuleDeny rejects the content with a *RuleViolationError
+	RuleDeny RuleAction = "deny"
+
+	// RuleTransform replaces the matches with the rule's replacement and
+	// truncates content longer than max_length
+	RuleTransform RuleAction = "transform"
+)
+
+// defaultReplacement replaces matches of transform rules without a replacement
+const defaultReplacement = "[REDACTED]"
+
+// RuleMatch defines when a rule matches. A rule matches when any regex or
+// keyword is found, or when the content is longer than MaxLength characters.
+type RuleMatch struct {
+	Regex     []string `yaml:"regex,omitempty"`
+	Keywords  []string `yaml:"keywords,omitempty"`
+	MaxLength int      `yaml:"max_length,omitempty"`
+}
+
+// Rule is a guardrail rule loaded from YAML
+type Rule struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description,omitempty"`
+	Stages      []Stage    `yaml:"stages,omitempty"` // All stages when empty
+	Tools       []string   `yaml:"tools,omitempty"`  // Limits tool_call rules to these tools
+	Match       RuleMatch  `yaml:"match"`
+	Action      RuleAction `yaml:"action"`
+	Message     string     `yaml:"message,omitempty"`
+	Replacement string     `yaml:"replacement,omitempty"`
+}
+
+// RulesConfig is the YAML document of a rules file
+type RulesConfig struct {
+	Version int    `yaml:"version"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// RuleViolationError is returned when a deny rule matches. It unwraps to an
+// *interfaces.GuardrailViolation, so it matches interfaces.ErrGuardrailViolation.
+type RuleViolationError struct {
+	Rule    string
+	Stage   Stage
+	Message string
+}
+
+// Error implements the error interface
+func (e *RuleViolationError) Error() string {
+	message := fmt.Sprintf("%s blocked by rule %s", e.Stage, e.Rule)
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	return message
+}
+
+// Unwrap returns the violation as an *interfaces.GuardrailViolation
+func (e *RuleViolationError) Unwrap() error {
+	return &interfaces.GuardrailViolation{Guardrail: e.Rule, Stage: string(e.Stage), Reason: e.Message}
+}
+
+type compiledRule struct {
+	Rule
+	patterns []*regexp.Regexp
+}
+
+// RuleEngine applies YAML-defined rules at the input, tool-call and output
+// stages of agent runs. Rules are evaluated in order; the first allow rule
+// that matches ends the evaluation. It implements interfaces.Guardrails and
+// interfaces.ToolCallGuardrails, so it can be passed to agent.WithGuardrails.
+type RuleEngine struct {
+	mu    sync.RWMutex
+	path  string
+	rules []compiledRule
+}
+
+// NewRuleEngine creates an engine from rules
+func NewRuleEngine(rules []Rule) (*RuleEngine, error) {
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return &RuleEngine{rules: compiled}, nil
+}
+
+// ParseRules creates an engine from a YAML rules document
+func ParseRules(data []byte) (*RuleEngine, error) {
+	var config RulesConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	return NewRuleEngine(config.Rules)
+}
+
+// LoadRules creates an engine from a YAML rules file. Use Reload to pick up
+// changes to the file without restarting.
+func LoadRules(path string) (*RuleEngine, error) {
+	engine := &RuleEngine{path: path}
+	if err := engine.Reload(); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// Reload re-reads the rules file the engine was loaded from. The current
+// rules are kept when the file is invalid.
+func (e *RuleEngine) Reload() error {
+	if e.path == "" {
+		return fmt.Errorf("rule engine was not loaded from a file")
+	}
+
+	data, err := os.ReadFile(e.path) // #nosec G304 - Path is provided by the application
+	if err != nil {
+		return fmt.Errorf("failed to read rules file: %w", err)
+	}
+	loaded, err := ParseRules(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", e.path, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = loaded.rules
+	return nil
+}
+
+// Evaluate applies the rules of stage to content. toolName is the called
+// tool at the tool_call stage and empty otherwise.
+func (e *RuleEngine) Evaluate(ctx context.Context, stage Stage, toolName, content string) (string, error) {
+	e.mu.RLock()
+	rules := e.rules
+	e.mu.RUnlock()
+
+	for _, rule := range rules {
+		if !rule.appliesTo(stage, toolName) || !rule.matches(content) {
+			continue
+		}
+
+		switch rule.Action {
+		case RuleAllow:
+			return content, nil
+		case RuleDeny:
+			return "", &RuleViolationError{Rule: rule.Name, Stage: stage, Message: rule.Message}
+		case RuleTransform:
+			content = rule.transform(content)
+		}
+	}
+	return content, nil
+}
+
+// ProcessInput implements interfaces.Guardrails.ProcessInput
+func (e *RuleEngine) ProcessInput(ctx context.Context, input string) (string, error) {
+	return e.Evaluate(ctx, StageInput, "", input)
+}
+
+// ProcessOutput implements interfaces.Guardrails.ProcessOutput
+func (e *RuleEngine) ProcessOutput(ctx context.Context, output string) (string, error) {
+	return e.Evaluate(ctx, StageOutput, "", output)
+}
+
+// ProcessToolCall implements interfaces.ToolCallGuardrails.ProcessToolCall
+func (e *RuleEngine) ProcessToolCall(ctx context.Context, toolName, args string) (string, error) {
+	return e.Evaluate(ctx, StageToolCall, toolName, args)
+}
+
+func compileRules(rules []Rule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+			rule.Name = name
+		}
+
+		switch rule.Action {
+		case RuleAllow, RuleDeny, RuleTransform:
+		default:
+			return nil, fmt.Errorf("rule %s: unknown action %q (use allow, deny or transform)", name, rule.Action)
+		}
+		for _, stage := range rule.Stages {
+			switch stage {
+			case StageInput, StageToolCall, StageOutput:
+			default:
+				return nil, fmt.Errorf("rule %s: unknown stage %q (use input, tool_call or output)", name, stage)
+			}
+		}
+		if len(rule.Match.Regex) == 0 && len(rule.Match.Keywords) == 0 && rule.Match.MaxLength <= 0 {
+			return nil, fmt.Errorf("rule %s: match must set regex, keywords or max_length", name)
+		}
+
+		c := compiledRule{Rule: rule}
+		for _, expr := range rule.Match.Regex {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: invalid regex %q: %w", name, expr, err)
+			}
+			c.patterns = append(c.patterns, re)
+		}
+		for _, keyword := range rule.Match.Keywords {
+			if keyword != "" {
+				c.patterns = append(c.patterns, regexp.MustCompile(`(?i)`+regexp.QuoteMeta(keyword)))
+			}
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+func (r *compiledRule) appliesTo(stage Stage, toolName string) bool {
+	if len(r.Stages) > 0 && !containsStage(r.Stages, stage) {
+		return false
+	}
+	if stage == StageToolCall && len(r.Tools) > 0 {
+		for _, tool := range r.Tools {
+			if tool == toolName {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r *compiledRule) matches(content string) bool {
+	if r.Match.MaxLength > 0 && len([]rune(content)) > r.Match.MaxLength {
+		return true
+	}
+	for _, pattern := range r.patterns {
+		if pattern.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *compiledRule) transform(content string) string {
+	replacement := r.Replacement
+	if replacement == "" {
+		replacement = defaultReplacement
+	}
+	for _, pattern := range r.patterns {
+		content = pattern.ReplaceAllLiteralString(content, replacement)
+	}
+	if runes := []rune(content); r.Match.MaxLength > 0 && len(runes) > r.Match.MaxLength {
+		content = string(runes[:r.Match.MaxLength])
+	}
+	return strings.TrimSpace(content)
+}
+
+func containsStage(stages []Stage, stage Stage) bool {
+	for _, s := range stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
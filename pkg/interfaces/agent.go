@@ -7,6 +7,7 @@ type AgentResponse struct {
 	Model            string
 	ExecutionSummary ExecutionSummary
 	Metadata         map[string]interface{}
+	Artifacts        []Artifact // Artifacts stored by tools during the run
 }
 
 type ExecutionSummary struct {
@@ -0,0 +1,40 @@
+package interfaces
+
+import "time"
+
+// Artifact describes a file produced during a run, such as a generated image,
+// synthesized speech, a report or an exported dataset, kept in an artifact store
+type Artifact struct {
+	// ID identifies the artifact in its store
+	ID string `json:"id"`
+
+	// URL is where the artifact content can be fetched
+	URL string `json:"url"`
+
+	// Name is an optional human-friendly file name
+	Name string `json:"name,omitempty"`
+
+	// MimeType is the content type, e.g. "image/png" or "application/pdf"
+	MimeType string `json:"mime_type"`
+
+	// Size is the content length in bytes
+	Size int64 `json:"size"`
+
+	// OrgID is the organization the artifact belongs to
+	OrgID string `json:"org_id,omitempty"`
+
+	// ThreadID is the conversation that produced the artifact
+	ThreadID string `json:"thread_id,omitempty"`
+
+	// MessageID is the message that produced the artifact
+	MessageID string `json:"message_id,omitempty"`
+
+	// Tool is the name of the tool that produced the artifact
+	Tool string `json:"tool,omitempty"`
+
+	// Metadata contains custom metadata, e.g. the prompt of generated media
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// CreatedAt is when the artifact was stored
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,262 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ContentPartType identifies the kind of a content part
+type ContentPartType string
+
+const (
+	// ContentPartText is plain text
+	ContentPartText ContentPartType = "text"
+	// ContentPartImage is an image, given as data or URL
+	ContentPartImage ContentPartType = "image"
+	// ContentPartAudio is an audio clip, given as data
+	ContentPartAudio ContentPartType = "audio"
+	// ContentPartFile is a document such as a PDF, given as data or URL
+	ContentPartFile ContentPartType = "file"
+	// ContentPartToolCall is a tool call made by the assistant
+	ContentPartToolCall ContentPartType = "tool_call"
+	// ContentPartToolResult is the result of a tool call
+	ContentPartToolResult ContentPartType = "tool_result"
+)
+
+// ContentPart is a piece of a multimodal message. Providers convert content
+// parts to their own formats, so the same parts work with OpenAI, Anthropic
+// and Gemini.
+type ContentPart struct {
+	// Type is the kind of the part
+	Type ContentPartType `json:"type"`
+
+	// Text is the text of text parts
+	Text string `json:"text,omitempty"`
+
+	// URL references the media of image and file parts. Data URLs
+	// (data:image/png;base64,...) are decoded into Data and MIMEType.
+	URL string `json:"url,omitempty"`
+
+	// Data holds the media of image, audio and file parts (base64 in JSON)
+	Data []byte `json:"data,omitempty"`
+
+	// StorageRef references media uploaded to the storage of an agent server,
+	// which resolves it into URL or Data before the run
+	StorageRef string `json:"storage_ref,omitempty"`
+
+	// MIMEType is the media type of Data or URL, e.g. image/png or application/pdf
+	MIMEType string `json:"mime_type,omitempty"`
+
+	// FileName is the name of file parts
+	FileName string `json:"file_name,omitempty"`
+
+	// Detail is the image detail level: low, high or auto (OpenAI only)
+	Detail string `json:"detail,omitempty"`
+
+	// ToolCall is the call of tool call parts
+	ToolCall *ToolCall `json:"tool_call,omitempty"`
+
+	// ToolResult is the result of tool result parts
+	ToolResult *ToolResult `json:"tool_result,omitempty"`
+}
+
+// UnmarshalJSON decodes a content part. Image parts in the OpenAI chat format,
+// {"type":"image_url","image_url":{"url":"...","detail":"low"}}, are accepted
+// as well and decoded as image parts.
+func (p *ContentPart) UnmarshalJSON(data []byte) error {
+	type contentPart ContentPart
+	var part struct {
+		contentPart
+		ImageURL *struct {
+			URL    string `json:"url"`
+			Detail string `json:"detail,omitempty"`
+		} `json:"image_url,omitempty"`
+	}
+	if err := json.Unmarshal(data, &part); err != nil {
+		return err
+	}
+	*p = ContentPart(part.contentPart)
+	if p.Type == "image_url" {
+		p.Type = ContentPartImage
+		if part.ImageURL != nil {
+			p.URL, p.Detail = part.ImageURL.URL, part.ImageURL.Detail
+		}
+	}
+	return nil
+}
+
+// ToolResult is the result of a tool call
+type ToolResult struct {
+	ToolCallID string `json:"tool_call_id"`
+	Name       string `json:"name,omitempty"`
+	Content    string `json:"content"`
+	IsError    bool   `json:"is_error,omitempty"`
+}
+
+// TextPart creates a text part
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: ContentPartText, Text: text}
+}
+
+// ImagePart creates an image part from data
+func ImagePart(data []byte, mimeType string) ContentPart {
+	return ContentPart{Type: ContentPartImage, Data: data, MIMEType: mimeType}
+}
+
+// ImageURLPart creates an image part from a URL
+func ImageURLPart(url string) ContentPart {
+	return ContentPart{Type: ContentPartImage, URL: url}
+}
+
+// AudioPart creates an audio part from data, e.g. audio/wav or audio/mp3
+func AudioPart(data []byte, mimeType string) ContentPart {
+	return ContentPart{Type: ContentPartAudio, Data: data, MIMEType: mimeType}
+}
+
+// FilePart creates a file part from data, e.g. a PDF document
+func FilePart(data []byte, mimeType, fileName string) ContentPart {
+	return ContentPart{Type: ContentPartFile, Data: data, MIMEType: mimeType, FileName: fileName}
+}
+
+// ToolCallPart creates a tool call part
+func ToolCallPart(call ToolCall) ContentPart {
+	return ContentPart{Type: ContentPartToolCall, ToolCall: &call}
+}
+
+// ToolResultPart creates a tool result part
+func ToolResultPart(result ToolResult) ContentPart {
+	return ContentPart{Type: ContentPartToolResult, ToolResult: &result}
+}
+
+// IsMedia returns true for image, audio and file parts
+func (p ContentPart) IsMedia() bool {
+	return p.Type == ContentPartImage || p.Type == ContentPartAudio || p.Type == ContentPartFile
+}
+
+// Base64 returns Data encoded as standard base64
+func (p ContentPart) Base64() string {
+	return base64.StdEncoding.EncodeToString(p.Data)
+}
+
+// DataURL returns the media as a URL: URL when set, otherwise a data URL
+// built from Data and MIMEType
+func (p ContentPart) DataURL() string {
+	if p.URL != "" {
+		return p.URL
+	}
+	return "data:" + p.MIMEType + ";base64," + p.Base64()
+}
+
+// Normalize decodes data URLs into Data and MIMEType and checks that the
+// part has the fields its type requires
+func (p *ContentPart) Normalize() error {
+	if strings.HasPrefix(p.URL, "data:") {
+		mimeType, data, err := ParseDataURL(p.URL)
+		if err != nil {
+			return err
+		}
+		p.URL, p.Data = "", data
+		if p.MIMEType == "" {
+			p.MIMEType = mimeType
+		}
+	}
+
+	switch p.Type {
+	case ContentPartText:
+		if p.Text == "" {
+			return fmt.Errorf("text part has no text")
+		}
+	case ContentPartImage, ContentPartFile:
+		if p.URL == "" && len(p.Data) == 0 && p.StorageRef == "" {
+			return fmt.Errorf("%s part has no data, url or storage_ref", p.Type)
+		}
+		if len(p.Data) > 0 && p.MIMEType == "" {
+			return fmt.Errorf("%s part has data but no mime_type", p.Type)
+		}
+	case ContentPartAudio:
+		if (len(p.Data) == 0 && p.StorageRef == "") || p.MIMEType == "" {
+			return fmt.Errorf("audio part requires data or storage_ref and mime_type")
+		}
+	case ContentPartToolCall:
+		if p.ToolCall == nil {
+			return fmt.Errorf("tool_call part has no tool_call")
+		}
+	case ContentPartToolResult:
+		if p.ToolResult == nil {
+			return fmt.Errorf("tool_result part has no tool_result")
+		}
+	default:
+		return fmt.Errorf("unknown content part type %q", p.Type)
+	}
+	return nil
+}
+
+// ParseDataURL decodes a base64 data URL such as data:image/png;base64,...
+func ParseDataURL(url string) (mimeType string, data []byte, err error) {
+	rest, ok := strings.CutPrefix(url, "data:")
+	if !ok {
+		return "", nil, fmt.Errorf("not a data URL")
+	}
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok || !strings.HasSuffix(header, ";base64") {
+		return "", nil, fmt.Errorf("data URL must be base64 encoded")
+	}
+	data, err = base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid data URL: %w", err)
+	}
+	return strings.TrimSuffix(header, ";base64"), data, nil
+}
+
+// Parts returns the content of a message as content parts. Messages without
+// ContentParts are converted from Content, ToolCalls and, for tool messages,
+// ToolCallID; text in Content is placed before the parts of ContentParts.
+func (m Message) Parts() []ContentPart {
+	var parts []ContentPart
+	if m.Role == MessageRoleTool && m.ToolCallID != "" {
+		name, _ := m.Metadata["tool_name"].(string)
+		parts = append(parts, ToolResultPart(ToolResult{ToolCallID: m.ToolCallID, Name: name, Content: m.Content}))
+	} else if m.Content != "" {
+		parts = append(parts, TextPart(m.Content))
+	}
+	parts = append(parts, m.ContentParts...)
+	for _, call := range m.ToolCalls {
+		parts = append(parts, ToolCallPart(call))
+	}
+	return parts
+}
+
+// HasMedia returns true if the message has image, audio or file parts
+func (m Message) HasMedia() bool {
+	for _, part := range m.ContentParts {
+		if part.IsMedia() {
+			return true
+		}
+	}
+	return false
+}
+
+// WithContentParts creates a GenerateOption that attaches content parts,
+// such as images or documents, to the prompt
+func WithContentParts(parts ...ContentPart) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.ContentParts = append(options.ContentParts, parts...)
+	}
+}
+
+type contentPartsKey struct{}
+
+// ContextWithContentParts returns a context carrying content parts for the
+// input of the next agent run, e.g. images uploaded with a request
+func ContextWithContentParts(ctx context.Context, parts []ContentPart) context.Context {
+	return context.WithValue(ctx, contentPartsKey{}, parts)
+}
+
+// ContentPartsFromContext returns the content parts set by ContextWithContentParts
+func ContentPartsFromContext(ctx context.Context) []ContentPart {
+	parts, _ := ctx.Value(contentPartsKey{}).([]ContentPart)
+	return parts
+}
@@ -0,0 +1,78 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ContentFilterCategory classifies why a provider blocked a request or response
+type ContentFilterCategory string
+
+const (
+	// ContentFilterCategoryHate covers hate speech
+	ContentFilterCategoryHate ContentFilterCategory = "hate"
+	// ContentFilterCategoryHarassment covers harassment and bullying
+	ContentFilterCategoryHarassment ContentFilterCategory = "harassment"
+	// ContentFilterCategorySexual covers sexual content
+	ContentFilterCategorySexual ContentFilterCategory = "sexual"
+	// ContentFilterCategoryViolence covers violent content
+	ContentFilterCategoryViolence ContentFilterCategory = "violence"
+	// ContentFilterCategorySelfHarm covers self-harm content
+	ContentFilterCategorySelfHarm ContentFilterCategory = "self_harm"
+	// ContentFilterCategoryDangerous covers dangerous or prohibited content
+	ContentFilterCategoryDangerous ContentFilterCategory = "dangerous"
+	// ContentFilterCategoryRecitation covers responses blocked for reproducing protected material
+	ContentFilterCategoryRecitation ContentFilterCategory = "recitation"
+	// ContentFilterCategoryRefusal covers the model declining to answer
+	ContentFilterCategoryRefusal ContentFilterCategory = "refusal"
+	// ContentFilterCategoryUnknown is used when the provider does not report a category
+	ContentFilterCategoryUnknown ContentFilterCategory = "unknown"
+)
+
+// ContentFilterError is returned by LLM providers when a safety filter blocks
+// the prompt or the response, or when the model refuses to answer. It replaces
+// the empty responses and opaque errors providers would otherwise produce.
+type ContentFilterError struct {
+	// Provider is the name of the LLM provider
+	Provider string
+
+	// Category is the reason the content was blocked
+	Category ContentFilterCategory
+
+	// Message is the provider's explanation or the model's refusal text (optional)
+	Message string
+
+	// StopReason is the raw finish or block reason reported by the provider
+	StopReason string
+
+	// PromptBlocked is true when the input was blocked before generation
+	PromptBlocked bool
+}
+
+// Error implements the error interface
+func (e *ContentFilterError) Error() string {
+	target := "response"
+	if e.PromptBlocked {
+		target = "prompt"
+	}
+
+	message := fmt.Sprintf("%s blocked the %s (category: %s", e.Provider, target, e.Category)
+	if e.StopReason != "" {
+		message += fmt.Sprintf(", reason: %s", e.StopReason)
+	}
+	message += ")"
+
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	return message
+}
+
+// AsContentFilterError returns the ContentFilterError in err's chain, if any
+func AsContentFilterError(err error) (*ContentFilterError, bool) {
+	var filterErr *ContentFilterError
+	if errors.As(err, &filterErr) {
+		return filterErr, true
+	}
+	return nil, false
+}
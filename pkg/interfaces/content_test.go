@@ -0,0 +1,91 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestContentPartNormalize(t *testing.T) {
+	part := ContentPart{Type: ContentPartImage, URL: "data:image/png;base64,iVBORw0K"}
+	if err := part.Normalize(); err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	if part.URL != "" || part.MIMEType != "image/png" || len(part.Data) == 0 {
+		t.Fatalf("data URL not decoded: %+v", part)
+	}
+	if got := part.DataURL(); got != "data:image/png;base64,iVBORw0K" {
+		t.Errorf("DataURL() = %q", got)
+	}
+
+	invalid := []ContentPart{
+		{Type: ContentPartText},
+		{Type: ContentPartImage},
+		{Type: ContentPartFile, Data: []byte("%PDF")},
+		{Type: ContentPartAudio, URL: "https://example.com/a.wav"},
+		{Type: ContentPartImage, URL: "data:image/png,raw"},
+		{Type: "video", URL: "https://example.com/v.mp4"},
+	}
+	for _, part := range invalid {
+		if err := part.Normalize(); err == nil {
+			t.Errorf("Normalize(%+v) expected error", part)
+		}
+	}
+}
+
+func TestContentPartJSON(t *testing.T) {
+	var part ContentPart
+	if err := json.Unmarshal([]byte(`{"type":"file","data":"JVBERg==","mime_type":"application/pdf","file_name":"a.pdf"}`), &part); err != nil {
+		t.Fatal(err)
+	}
+	if err := part.Normalize(); err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	if string(part.Data) != "%PDF" || part.FileName != "a.pdf" {
+		t.Errorf("unexpected part %+v", part)
+	}
+}
+
+func TestContentPartJSONImageURL(t *testing.T) {
+	var part ContentPart
+	if err := json.Unmarshal([]byte(`{"type":"image_url","image_url":{"url":"https://example.com/cat.png","detail":"low"}}`), &part); err != nil {
+		t.Fatal(err)
+	}
+	if part.Type != ContentPartImage || part.URL != "https://example.com/cat.png" || part.Detail != "low" {
+		t.Errorf("unexpected part %+v", part)
+	}
+	if err := part.Normalize(); err != nil {
+		t.Errorf("Normalize() error = %v", err)
+	}
+}
+
+func TestMessageParts(t *testing.T) {
+	message := Message{
+		Role:         MessageRoleUser,
+		Content:      "What is in this image?",
+		ContentParts: []ContentPart{ImageURLPart("https://example.com/cat.png")},
+	}
+	parts := message.Parts()
+	if len(parts) != 2 || parts[0].Text != "What is in this image?" || parts[1].Type != ContentPartImage {
+		t.Errorf("Parts() = %+v", parts)
+	}
+	if !message.HasMedia() {
+		t.Error("HasMedia() = false")
+	}
+
+	tool := Message{Role: MessageRoleTool, Content: "Sunny", ToolCallID: "call_1", Metadata: map[string]interface{}{"tool_name": "weather"}}
+	parts = tool.Parts()
+	if len(parts) != 1 || parts[0].ToolResult == nil || parts[0].ToolResult.Name != "weather" {
+		t.Errorf("Parts() = %+v", parts)
+	}
+}
+
+func TestContentPartsContext(t *testing.T) {
+	ctx := ContextWithContentParts(context.Background(), []ContentPart{TextPart("hi")})
+	if parts := ContentPartsFromContext(ctx); len(parts) != 1 {
+		t.Errorf("ContentPartsFromContext() = %+v", parts)
+	}
+	if parts := ContentPartsFromContext(context.Background()); parts != nil {
+		t.Errorf("ContentPartsFromContext() = %+v", parts)
+	}
+}
@@ -0,0 +1,194 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The errors below classify the failures of agent runs and LLM calls, so that
+// callers can branch on them with errors.Is or, for their details, errors.As
+// on the matching struct type:
+//
+//	var rateLimited *interfaces.RateLimitError
+//	if errors.As(err, &rateLimited) {
+//		time.Sleep(rateLimited.RetryAfter)
+//	}
+//
+// Rate limits match ErrRateLimitExceeded.
+var (
+	// ErrContextLengthExceeded indicates the request does not fit in the
+	// model's context window
+	ErrContextLengthExceeded = errors.New("context length exceeded")
+
+	// ErrProviderUnavailable indicates the LLM provider could not be reached
+	// or failed to serve the request
+	ErrProviderUnavailable = errors.New("provider unavailable")
+
+	// ErrToolExecution indicates a tool call failed
+	ErrToolExecution = errors.New("tool execution failed")
+
+	// ErrGuardrailViolation indicates content was blocked by a guardrail
+	ErrGuardrailViolation = errors.New("guardrail violation")
+)
+
+// RateLimitError is returned when a provider rejects a request because of
+// rate limits or quotas. It matches ErrRateLimitExceeded.
+type RateLimitError struct {
+	// Provider is the name of the LLM provider
+	Provider string
+
+	// RetryAfter is how long the provider asked to wait before retrying (0 if unknown)
+	RetryAfter time.Duration
+
+	// Err is the error reported by the provider
+	Err error
+}
+
+// Error implements the error interface
+func (e *RateLimitError) Error() string {
+	message := fmt.Sprintf("%s: %v", e.Provider, ErrRateLimitExceeded)
+	if e.RetryAfter > 0 {
+		message += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return withCause(message, e.Err)
+}
+
+// Unwrap returns the error reported by the provider
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrRateLimitExceeded
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimitExceeded
+}
+
+// ContextLengthError is returned when a provider rejects a request that does
+// not fit in the model's context window. It matches ErrContextLengthExceeded.
+type ContextLengthError struct {
+	// Provider is the name of the LLM provider
+	Provider string
+
+	// Err is the error reported by the provider
+	Err error
+}
+
+// Error implements the error interface
+func (e *ContextLengthError) Error() string {
+	return withCause(fmt.Sprintf("%s: %v", e.Provider, ErrContextLengthExceeded), e.Err)
+}
+
+// Unwrap returns the error reported by the provider
+func (e *ContextLengthError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrContextLengthExceeded
+func (e *ContextLengthError) Is(target error) bool {
+	return target == ErrContextLengthExceeded
+}
+
+// ProviderUnavailable is returned when a provider cannot be reached, is
+// overloaded or fails with a server error. It matches ErrProviderUnavailable.
+type ProviderUnavailable struct {
+	// Provider is the name of the LLM provider
+	Provider string
+
+	// StatusCode is the HTTP status code of the response (0 when the
+	// provider could not be reached)
+	StatusCode int
+
+	// Err is the error of the request
+	Err error
+}
+
+// Error implements the error interface
+func (e *ProviderUnavailable) Error() string {
+	message := fmt.Sprintf("%s: %v", e.Provider, ErrProviderUnavailable)
+	if e.StatusCode != 0 {
+		message += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	return withCause(message, e.Err)
+}
+
+// Unwrap returns the error of the request
+func (e *ProviderUnavailable) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrProviderUnavailable
+func (e *ProviderUnavailable) Is(target error) bool {
+	return target == ErrProviderUnavailable
+}
+
+// ToolExecutionError is returned when a tool call fails or calls an unknown
+// tool. It matches ErrToolExecution.
+type ToolExecutionError struct {
+	// Tool is the name of the tool
+	Tool string
+
+	// Err is the error returned by the tool
+	Err error
+}
+
+// Error implements the error interface
+func (e *ToolExecutionError) Error() string {
+	return fmt.Sprintf("tool %s failed: %v", e.Tool, e.Err)
+}
+
+// Unwrap returns the error returned by the tool
+func (e *ToolExecutionError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrToolExecution
+func (e *ToolExecutionError) Is(target error) bool {
+	return target == ErrToolExecution
+}
+
+// GuardrailViolation is returned when a guardrail blocks a request, a
+// response or a tool call. It matches ErrGuardrailViolation.
+type GuardrailViolation struct {
+	// Guardrail is the name of the guardrail or rule that blocked the content
+	Guardrail string
+
+	// Stage is the content that was blocked, e.g. "request", "input",
+	// "tool_call" or "output" (optional)
+	Stage string
+
+	// Reason explains why the content was blocked (optional)
+	Reason string
+
+	// Err is a more specific error of the guardrail (optional)
+	Err error
+}
+
+// Error implements the error interface
+func (e *GuardrailViolation) Error() string {
+	message := fmt.Sprintf("blocked by %s guardrail", e.Guardrail)
+	if e.Stage != "" {
+		message = e.Stage + " " + message
+	}
+	if e.Reason != "" {
+		message += ": " + e.Reason
+	}
+	return message
+}
+
+// Unwrap returns the specific error of the guardrail, if any
+func (e *GuardrailViolation) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrGuardrailViolation
+func (e *GuardrailViolation) Is(target error) bool {
+	return target == ErrGuardrailViolation
+}
+
+func withCause(message string, err error) string {
+	if err == nil {
+		return message
+	}
+	return message + ": " + err.Error()
+}
@@ -10,3 +10,9 @@ type Guardrails interface {
 	// ProcessOutput processes LLM output before returning to the user
 	ProcessOutput(ctx context.Context, output string) (string, error)
 }
+
+// ToolCallGuardrails is implemented by guardrails that also check tool calls
+type ToolCallGuardrails interface {
+	// ProcessToolCall processes the arguments of a tool call before the tool runs
+	ProcessToolCall(ctx context.Context, toolName string, args string) (string, error)
+}
@@ -45,6 +45,29 @@ type ImageGenerator interface {
 	SupportedImageFormats() []string
 }
 
+// ImageEditor represents a provider that can edit an existing image in a
+// single request, without a multi-turn editing session
+type ImageEditor interface {
+	// EditImage applies an edit prompt to an image, optionally limited to a masked area
+	EditImage(ctx context.Context, request ImageEditRequest) (*ImageGenerationResponse, error)
+}
+
+// ImageEditRequest represents a request to edit an existing image
+type ImageEditRequest struct {
+	// Prompt describes the edit to apply (required)
+	Prompt string
+
+	// Image is the image to edit (required)
+	Image *ImageData
+
+	// Mask is an optional image of the same size whose fully transparent
+	// areas mark where Image should be edited
+	Mask *ImageData
+
+	// Options contains generation configuration
+	Options *ImageGenerationOptions
+}
+
 // ImageGenerationRequest represents a request to generate an image
 type ImageGenerationRequest struct {
 	// Prompt is the text description of the image to generate (required)
@@ -105,6 +128,10 @@ type GeneratedImage struct {
 	FinishReason string
 }
 
+// GeneratedMedia is generated media of any type, such as an image, speech
+// or a video, identified by its MimeType
+type GeneratedMedia = GeneratedImage
+
 // ImageData represents input image data for image-to-image generation
 type ImageData struct {
 	// Data contains raw image bytes
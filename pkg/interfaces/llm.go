@@ -1,6 +1,9 @@
 package interfaces
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // LLM represents a large language model provider
 type LLM interface {
@@ -37,6 +40,12 @@ type GenerateOptions struct {
 	Memory              Memory          // Optional memory for storing tool calls and results
 	StreamConfig        *StreamConfig   // Optional streaming configuration
 	CacheConfig         *CacheConfig    // Optional prompt caching configuration (Anthropic only)
+	RepairRetries       *int            // Optional retries for structured output that does not match its schema (structuredoutput.GenerateAs)
+	ContentParts        []ContentPart   // Optional multimodal content (images, audio, files) sent with the prompt
+	ToolsFunc           func() []Tool   // Optional current tool set, read before each tool-calling iteration (OpenAI, Anthropic and Gemini)
+	ToolLoopPolicy      *ToolLoopPolicy // Optional policy of the tool-calling loop, see ToolLoop
+	ToolTimeout         time.Duration   // Optional timeout of each tool call of GenerateWithTools (0 = no limit)
+	ParallelToolCalls   bool            // When true, run the tool calls of a model response concurrently
 }
 
 // CacheConfig contains configuration for prompt caching (Anthropic only)
@@ -63,6 +72,9 @@ type LLMConfig struct {
 	Reasoning        string   // Reasoning mode (minimal, low, medium, high) to control reasoning effort
 	EnableReasoning  bool     // Enable native reasoning tokens (Anthropic thinking/OpenAI o1)
 	ReasoningBudget  int      // Optional token budget for reasoning (Anthropic only), minimum 1024
+	MaxOutputTokens  int      `json:",omitempty"` // Maximum number of tokens to generate (0 for the provider default)
+	Seed             *int     `json:",omitempty"` // Optional sampling seed for reproducible generations, where the provider supports it
+	Deterministic    bool     `json:",omitempty"` // Pin temperature to 0, sent even where a zero temperature means the model default (Gemini)
 }
 
 // WithMaxIterations creates a GenerateOption to set the maximum number of tool-calling iterations
@@ -72,6 +84,45 @@ func WithMaxIterations(maxIterations int) GenerateOption {
 	}
 }
 
+// WithRepairRetries creates a GenerateOption to set how many times
+// structuredoutput.GenerateAs re-prompts the model when its response does not
+// match the schema
+func WithRepairRetries(maxRetries int) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.RepairRetries = &maxRetries
+	}
+}
+
+// WithToolsFunc creates a GenerateOption to read the tools before each
+// tool-calling iteration of GenerateWithTools and GenerateWithToolsStream, so
+// tools registered or removed while it runs take effect from the next
+// iteration. Providers without support use the tools passed to
+// GenerateWithTools.
+func WithToolsFunc(toolsFunc func() []Tool) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.ToolsFunc = toolsFunc
+	}
+}
+
+// WithParallelToolCalls creates a GenerateOption to run the tool calls of a
+// model response concurrently. By default they run one after the other, in
+// the order the model made them, for tools that depend on that order or
+// share state.
+func WithParallelToolCalls(parallel bool) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.ParallelToolCalls = parallel
+	}
+}
+
+// WithToolTimeout creates a GenerateOption to limit how long each tool call
+// of GenerateWithTools may run. The tool's context is canceled after the
+// timeout, and its error is returned to the model.
+func WithToolTimeout(timeout time.Duration) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.ToolTimeout = timeout
+	}
+}
+
 // WithDisableFinalSummary creates a GenerateOption to disable the final summary LLM call
 func WithDisableFinalSummary(disable bool) GenerateOption {
 	return func(options *GenerateOptions) {
@@ -199,9 +250,51 @@ func WithStopSequences(stopSequences []string) GenerateOption {
 	}
 }
 
+// WithMaxOutputTokens creates a GenerateOption to set the maximum number of
+// tokens to generate
+func WithMaxOutputTokens(maxOutputTokens int) GenerateOption {
+	return func(options *GenerateOptions) {
+		if options.LLMConfig == nil {
+			options.LLMConfig = &LLMConfig{}
+		}
+		options.LLMConfig.MaxOutputTokens = maxOutputTokens
+	}
+}
+
+// WithSeed creates a GenerateOption to set the sampling seed
+func WithSeed(seed int) GenerateOption {
+	return func(options *GenerateOptions) {
+		if options.LLMConfig == nil {
+			options.LLMConfig = &LLMConfig{}
+		}
+		options.LLMConfig.Seed = &seed
+	}
+}
+
+// WithDeterministic creates a GenerateOption that pins the sampling of the
+// generation: temperature 0 and the given seed
+func WithDeterministic(seed int) GenerateOption {
+	return func(options *GenerateOptions) {
+		if options.LLMConfig == nil {
+			options.LLMConfig = &LLMConfig{}
+		}
+		options.LLMConfig.Temperature = 0
+		options.LLMConfig.Seed = &seed
+		options.LLMConfig.Deterministic = true
+	}
+}
+
 // WithResponseFormat creates a GenerateOption to set the response format
 func WithResponseFormat(format ResponseFormat) GenerateOption {
 	return func(options *GenerateOptions) {
 		options.ResponseFormat = &format
 	}
 }
+
+// ConnectionWarmer is implemented by LLM providers that can pre-establish
+// connections (DNS, TLS handshake, HTTP/2 session) to their API endpoint
+// before the first request, reducing cold-start latency.
+type ConnectionWarmer interface {
+	// WarmConnections opens or refreshes pooled connections to the provider
+	WarmConnections(ctx context.Context) error
+}
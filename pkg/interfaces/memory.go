@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 )
 
 // MessageRole represents the role of a message sender
@@ -34,6 +35,10 @@ type Message struct {
 
 	// ToolCalls contains tool call information for assistant messages
 	ToolCalls []ToolCall
+
+	// ContentParts contains multimodal content, such as images or documents,
+	// sent along with Content
+	ContentParts []ContentPart
 }
 
 // ToolCall represents a tool call made by the assistant
@@ -86,6 +91,30 @@ type AdminConversationMemory interface {
 	GetMemoryStatisticsAcrossOrgs() (totalConversations, totalMessages int, err error)
 }
 
+// MemoryExporter is implemented by memories that export and import whole
+// conversations natively instead of through GetMessages and AddMessage
+type MemoryExporter interface {
+	// Export returns all messages of a conversation in the current org
+	Export(ctx context.Context, conversationID string) ([]Message, error)
+
+	// Import appends messages to a conversation in the current org
+	Import(ctx context.Context, conversationID string, messages []Message) error
+}
+
+// ErrConversationExists is returned when creating a conversation that
+// already has messages
+var ErrConversationExists = errors.New("conversation already exists")
+
+// ConversationCreator is implemented by memories that create conversations
+// atomically, so that of concurrent creations of a conversation only one
+// succeeds
+type ConversationCreator interface {
+	// CreateConversation stores messages as a new conversation in the current
+	// org. It fails with ErrConversationExists if the conversation already
+	// has messages.
+	CreateConversation(ctx context.Context, conversationID string, messages []Message) error
+}
+
 // GetMessagesOptions contains options for retrieving messages
 type GetMessagesOptions struct {
 	// Limit is the maximum number of messages to retrieve
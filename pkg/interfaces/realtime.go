@@ -0,0 +1,108 @@
+package interfaces
+
+import "context"
+
+// RealtimeEventType identifies the kind of a realtime session event
+type RealtimeEventType string
+
+const (
+	// RealtimeEventAudio carries a chunk of the model's spoken response
+	RealtimeEventAudio RealtimeEventType = "audio"
+	// RealtimeEventTranscript carries text of the model's response, or the
+	// transcript of its spoken response
+	RealtimeEventTranscript RealtimeEventType = "transcript"
+	// RealtimeEventInputTranscript carries the transcript of the user's speech
+	RealtimeEventInputTranscript RealtimeEventType = "input_transcript"
+	// RealtimeEventToolCall asks the client to run a tool and send the result
+	// with SendToolResult
+	RealtimeEventToolCall RealtimeEventType = "tool_call"
+	// RealtimeEventToolResult reports the result of a tool call run on the
+	// client's behalf, e.g. by an agent
+	RealtimeEventToolResult RealtimeEventType = "tool_result"
+	// RealtimeEventInterrupted reports that the model's response was cut off,
+	// because the user started speaking or Interrupt was called. Clients
+	// should stop playing buffered audio.
+	RealtimeEventInterrupted RealtimeEventType = "interrupted"
+	// RealtimeEventTurnComplete reports that the model finished its response
+	RealtimeEventTurnComplete RealtimeEventType = "turn_complete"
+	// RealtimeEventError reports an error; the session may still be usable
+	RealtimeEventError RealtimeEventType = "error"
+)
+
+// RealtimeEvent is an event received from a realtime session
+type RealtimeEvent struct {
+	// Type is the kind of the event
+	Type RealtimeEventType
+
+	// Audio is the audio of audio events
+	Audio []byte
+
+	// MIMEType is the format of Audio, e.g. audio/pcm;rate=24000
+	MIMEType string
+
+	// Text is the text of transcript events
+	Text string
+
+	// ToolCall is the call of tool call events
+	ToolCall *ToolCall
+
+	// ToolResult is the result of tool result events
+	ToolResult *ToolResult
+
+	// Error is the error of error events
+	Error error
+}
+
+// RealtimeConfig configures a realtime session
+type RealtimeConfig struct {
+	// Model is the realtime model; empty uses the provider's default
+	Model string
+
+	// Instructions is the system prompt of the session
+	Instructions string
+
+	// Voice is the provider's voice name for spoken responses
+	Voice string
+
+	// Tools are the tools the model may call during the session
+	Tools []Tool
+
+	// TextOnly makes the model respond with text instead of audio
+	TextOnly bool
+}
+
+// RealtimeSession is a low-latency, bidirectional conversation with a model
+// that takes streamed audio or text and answers with streamed audio and text.
+// Audio is 16-bit little-endian mono PCM; the sample rates depend on the
+// provider and are given by InputAudioFormat and the MIMEType of audio events.
+type RealtimeSession interface {
+	// SendAudio streams a chunk of user audio. The provider detects the end
+	// of the user's speech and responds on its own.
+	SendAudio(ctx context.Context, audio []byte) error
+
+	// SendText sends a user text message and asks for a response
+	SendText(ctx context.Context, text string) error
+
+	// SendToolResult returns the result of a tool call to the model
+	SendToolResult(ctx context.Context, result ToolResult) error
+
+	// Interrupt cuts off the response in progress
+	Interrupt(ctx context.Context) error
+
+	// InputAudioFormat returns the MIME type of the audio SendAudio expects,
+	// e.g. audio/pcm;rate=16000
+	InputAudioFormat() string
+
+	// Events returns the events of the session. The channel is closed when
+	// the session ends.
+	Events() <-chan RealtimeEvent
+
+	// Close ends the session
+	Close() error
+}
+
+// RealtimeProvider opens realtime sessions
+type RealtimeProvider interface {
+	// ConnectRealtime opens a realtime session
+	ConnectRealtime(ctx context.Context, config RealtimeConfig) (RealtimeSession, error)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import "context"
+
+// RerankResult is the relevance of a single document to a query
+type RerankResult struct {
+	// Index is the position of the document in the input slice
+	Index int
+
+	// Score is the relevance score returned by the reranker (higher is more relevant)
+	Score float32
+}
+
+// Reranker reorders candidate documents by their relevance to a query.
+// It is typically used as a second stage after vector search.
+type Reranker interface {
+	// Rerank scores documents against the query and returns at most topN
+	// results ordered by descending relevance. A topN <= 0 returns all documents.
+	Rerank(ctx context.Context, query string, documents []string, topN int) ([]RerankResult, error)
+}
@@ -0,0 +1,102 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// Transcription is the text of transcribed speech
+type Transcription struct {
+	// Text is the transcribed text
+	Text string
+
+	// Language is the language of the speech, when known (ISO-639-1, e.g. "en")
+	Language string
+
+	// Duration is the length of the audio, when known
+	Duration time.Duration
+}
+
+// TranscribeOptions contains options for transcribing speech
+type TranscribeOptions struct {
+	// Language is the language of the speech (ISO-639-1). It is detected when empty.
+	Language string
+
+	// Prompt is optional text guiding the transcription, e.g. names or jargon
+	Prompt string
+}
+
+// TranscribeOption represents an option for transcribing speech
+type TranscribeOption func(*TranscribeOptions)
+
+// WithTranscriptionLanguage sets the language of the speech
+func WithTranscriptionLanguage(language string) TranscribeOption {
+	return func(options *TranscribeOptions) {
+		options.Language = language
+	}
+}
+
+// WithTranscriptionPrompt sets text guiding the transcription
+func WithTranscriptionPrompt(prompt string) TranscribeOption {
+	return func(options *TranscribeOptions) {
+		options.Prompt = prompt
+	}
+}
+
+// SpeechToText transcribes speech to text
+type SpeechToText interface {
+	// Transcribe returns the transcription of audio of the given MIME type,
+	// e.g. audio/wav, audio/mpeg or audio/webm
+	Transcribe(ctx context.Context, audio []byte, mimeType string, options ...TranscribeOption) (*Transcription, error)
+}
+
+// Speech is audio synthesized from text
+type Speech struct {
+	// Data is the encoded audio
+	Data []byte
+
+	// MIMEType is the media type of Data, e.g. audio/mpeg
+	MIMEType string
+}
+
+// SynthesizeOptions contains options for synthesizing speech
+type SynthesizeOptions struct {
+	// Voice is the provider's voice name or ID; the backend default is used when empty
+	Voice string
+
+	// Format is the audio format: mp3 (default), wav, opus, aac or flac
+	Format string
+
+	// Speed is the speaking rate, where 1.0 is normal speed
+	Speed float64
+}
+
+// SynthesizeOption represents an option for synthesizing speech
+type SynthesizeOption func(*SynthesizeOptions)
+
+// WithSpeechVoice sets the voice used to synthesize speech
+func WithSpeechVoice(voice string) SynthesizeOption {
+	return func(options *SynthesizeOptions) {
+		options.Voice = voice
+	}
+}
+
+// WithSpeechFormat sets the audio format of synthesized speech
+func WithSpeechFormat(format string) SynthesizeOption {
+	return func(options *SynthesizeOptions) {
+		options.Format = format
+	}
+}
+
+// WithSpeechSpeed sets the speaking rate of synthesized speech
+func WithSpeechSpeed(speed float64) SynthesizeOption {
+	return func(options *SynthesizeOptions) {
+		options.Speed = speed
+	}
+}
+
+// TextToSpeech synthesizes speech from text
+type TextToSpeech interface {
+	// Synthesize returns text spoken as audio
+	Synthesize(ctx context.Context, text string, options ...SynthesizeOption) (*Speech, error)
+}
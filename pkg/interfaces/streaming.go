@@ -72,6 +72,16 @@ const (
 	AgentEventToolResult AgentEventType = "tool_result"
 	AgentEventError      AgentEventType = "error"
 	AgentEventComplete   AgentEventType = "complete"
+
+	// AgentEventHandoff is sent when an agent transfers the conversation to
+	// another agent. Its metadata holds the names of the agents under "from"
+	// and "to", and its content the reason of the handoff.
+	AgentEventHandoff AgentEventType = "handoff"
+
+	// AgentEventPlanStep is sent as each step of an approved execution plan
+	// starts and ends. Its metadata holds the task_id, step, total_steps,
+	// status, description, tool and progress (percent) of the plan.
+	AgentEventPlanStep AgentEventType = "plan_step"
 )
 
 // ToolCallEvent represents a tool call in streaming context
@@ -35,6 +35,14 @@ type InternalTool interface {
 	Internal() bool
 }
 
+// ToolWithAvailability is an optional interface for tools that can become
+// temporarily unavailable, such as the tools of an MCP server that lost its
+// connection. Agents leave unavailable tools out of their requests.
+type ToolWithAvailability interface {
+	// Available returns false while the tool cannot be called
+	Available() bool
+}
+
 // ParameterSpec defines the specification for a tool parameter
 type ParameterSpec struct {
 	// Type is the data type of the parameter (string, number, boolean, etc.) or union of types e.g. ["array", "null"]
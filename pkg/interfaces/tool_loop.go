@@ -0,0 +1,181 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// DefaultMaxToolIterations is the number of tool-calling iterations of
+// GenerateWithTools when none is set
+const DefaultMaxToolIterations = 2
+
+// DefaultFinalPrompt asks the model for its final response in the call
+// without tools made when the tool-calling iterations are exhausted
+const DefaultFinalPrompt = "Please provide your final response based on the information available. Do not request any additional tools."
+
+// ErrToolLoopExhausted is returned by GenerateWithTools when the model still
+// calls tools after the last iteration and the policy's OnExhausted is
+// ToolLoopFail
+var ErrToolLoopExhausted = errors.New("tool-calling iterations exhausted")
+
+// ToolLoopExhaustion is what GenerateWithTools does when the model still
+// calls tools after the last iteration
+type ToolLoopExhaustion string
+
+const (
+	// ToolLoopFinalCall makes a last call without tools, asking the model
+	// for its final response with the policy's FinalPrompt
+	ToolLoopFinalCall ToolLoopExhaustion = "final_call"
+
+	// ToolLoopLastResponse returns the text of the last response
+	ToolLoopLastResponse ToolLoopExhaustion = "last_response"
+
+	// ToolLoopFail returns ErrToolLoopExhausted
+	ToolLoopFail ToolLoopExhaustion = "fail"
+)
+
+// ToolLoopPolicy configures the tool-calling loop of GenerateWithTools
+type ToolLoopPolicy struct {
+	// MaxIterations is the maximum number of calls to the model that may
+	// call tools (0 = MaxIterations of the options, or
+	// DefaultMaxToolIterations)
+	MaxIterations int `json:"max_iterations,omitempty" yaml:"max_iterations,omitempty"`
+
+	// StopTools end the loop once one of them ran, returning its result as
+	// the response, e.g. a final_answer tool
+	StopTools []string `json:"stop_tools,omitempty" yaml:"stop_tools,omitempty"`
+
+	// FinalPrompt asks for the final response in the call without tools of
+	// ToolLoopFinalCall (empty = DefaultFinalPrompt)
+	FinalPrompt string `json:"final_prompt,omitempty" yaml:"final_prompt,omitempty"`
+
+	// OnExhausted is what happens when the model still calls tools after the
+	// last iteration (empty = ToolLoopFinalCall, or ToolLoopLastResponse
+	// with DisableFinalSummary)
+	OnExhausted ToolLoopExhaustion `json:"on_exhausted,omitempty" yaml:"on_exhausted,omitempty"`
+}
+
+// WithToolLoopPolicy creates a GenerateOption to set the policy of the
+// tool-calling loop. Its fields take precedence over MaxIterations and
+// DisableFinalSummary.
+func WithToolLoopPolicy(policy ToolLoopPolicy) GenerateOption {
+	return func(options *GenerateOptions) {
+		options.ToolLoopPolicy = &policy
+	}
+}
+
+// ToolLoop returns the policy of the tool-calling loop, with the defaults
+// and the MaxIterations and DisableFinalSummary options applied
+func (o *GenerateOptions) ToolLoop() ToolLoopPolicy {
+	var policy ToolLoopPolicy
+	if o.ToolLoopPolicy != nil {
+		policy = *o.ToolLoopPolicy
+	}
+	if policy.MaxIterations <= 0 {
+		policy.MaxIterations = o.MaxIterations
+	}
+	if policy.MaxIterations <= 0 {
+		policy.MaxIterations = DefaultMaxToolIterations
+	}
+	if policy.FinalPrompt == "" {
+		policy.FinalPrompt = DefaultFinalPrompt
+	}
+	if policy.OnExhausted == "" {
+		policy.OnExhausted = ToolLoopFinalCall
+		if o.DisableFinalSummary {
+			policy.OnExhausted = ToolLoopLastResponse
+		}
+	}
+	return policy
+}
+
+// Exhausted returns the error of an exhausted loop under ToolLoopFail
+func (p ToolLoopPolicy) Exhausted() error {
+	return fmt.Errorf("%w after %d iterations", ErrToolLoopExhausted, p.MaxIterations)
+}
+
+// ToolLoopStop records the results of the stop tools of a policy during a
+// tool-calling loop
+type ToolLoopStop struct {
+	stopTools []string
+
+	mu      sync.Mutex
+	stopped bool
+	result  string
+}
+
+// NewToolLoopStop creates a ToolLoopStop for the stop tools of a policy
+func NewToolLoopStop(policy ToolLoopPolicy) *ToolLoopStop {
+	return &ToolLoopStop{stopTools: policy.StopTools}
+}
+
+// Wrap wraps the stop tools so their results are recorded. Returns the
+// original slice unchanged when the policy has no stop tools.
+func (s *ToolLoopStop) Wrap(tools []Tool) []Tool {
+	if len(s.stopTools) == 0 {
+		return tools
+	}
+	wrapped := make([]Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = tool
+		if slices.Contains(s.stopTools, tool.Name()) {
+			wrapped[i] = &stopTool{Tool: tool, stop: s}
+		}
+	}
+	return wrapped
+}
+
+// Result returns the result of the stop tool that ran, if one did
+func (s *ToolLoopStop) Result() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.result, s.stopped
+}
+
+func (s *ToolLoopStop) record(result string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	s.result = result
+}
+
+// stopTool records the results of a stop tool
+type stopTool struct {
+	Tool
+	stop *ToolLoopStop
+}
+
+func (t *stopTool) Run(ctx context.Context, input string) (string, error) {
+	result, err := t.Tool.Run(ctx, input)
+	if err == nil {
+		t.stop.record(result)
+	}
+	return result, err
+}
+
+func (t *stopTool) Execute(ctx context.Context, args string) (string, error) {
+	result, err := t.Tool.Execute(ctx, args)
+	if err == nil {
+		t.stop.record(result)
+	}
+	return result, err
+}
+
+// DisplayName forwards to the tool when it implements ToolWithDisplayName.
+func (t *stopTool) DisplayName() string {
+	if d, ok := t.Tool.(ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.Tool.Name()
+}
+
+// Internal forwards to the tool when it implements InternalTool.
+func (t *stopTool) Internal() bool {
+	if i, ok := t.Tool.(InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
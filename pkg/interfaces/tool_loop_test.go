@@ -0,0 +1,45 @@
+package interfaces
+
+import "testing"
+
+func TestToolLoop(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []GenerateOption
+		expected ToolLoopPolicy
+	}{
+		{
+			name:     "defaults",
+			expected: ToolLoopPolicy{MaxIterations: DefaultMaxToolIterations, FinalPrompt: DefaultFinalPrompt, OnExhausted: ToolLoopFinalCall},
+		},
+		{
+			name:     "generate options",
+			options:  []GenerateOption{WithMaxIterations(5), WithDisableFinalSummary(true)},
+			expected: ToolLoopPolicy{MaxIterations: 5, FinalPrompt: DefaultFinalPrompt, OnExhausted: ToolLoopLastResponse},
+		},
+		{
+			name: "policy takes precedence",
+			options: []GenerateOption{
+				WithMaxIterations(5),
+				WithDisableFinalSummary(true),
+				WithToolLoopPolicy(ToolLoopPolicy{MaxIterations: 3, FinalPrompt: "Answer now.", OnExhausted: ToolLoopFail}),
+			},
+			expected: ToolLoopPolicy{MaxIterations: 3, FinalPrompt: "Answer now.", OnExhausted: ToolLoopFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := &GenerateOptions{}
+			for _, option := range tt.options {
+				option(options)
+			}
+			policy := options.ToolLoop()
+			if policy.MaxIterations != tt.expected.MaxIterations ||
+				policy.FinalPrompt != tt.expected.FinalPrompt ||
+				policy.OnExhausted != tt.expected.OnExhausted {
+				t.Errorf("Expected %+v, got %+v", tt.expected, policy)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrVideoGenerationNotSupported indicates the provider doesn't support video generation
+var ErrVideoGenerationNotSupported = errors.New("video generation not supported by this provider")
+
+// VideoGenerator represents a provider that can generate videos. Video
+// generation takes minutes, so it runs as a job: StartVideoGeneration starts
+// it and PollVideoGeneration reports its progress until it is done.
+type VideoGenerator interface {
+	// StartVideoGeneration starts generating a video from a text prompt
+	StartVideoGeneration(ctx context.Context, request VideoGenerationRequest) (*VideoGenerationJob, error)
+
+	// PollVideoGeneration returns the current state of a job. It returns an
+	// error when the generation failed, e.g. ErrContentBlocked.
+	PollVideoGeneration(ctx context.Context, job *VideoGenerationJob) (*VideoGenerationJob, error)
+}
+
+// VideoGenerationRequest represents a request to generate a video
+type VideoGenerationRequest struct {
+	// Prompt is the text description of the video to generate (required)
+	Prompt string
+
+	// ReferenceImage is an optional first frame for image-to-video generation
+	ReferenceImage *ImageData
+
+	// Options contains generation configuration
+	Options *VideoGenerationOptions
+}
+
+// VideoGenerationOptions configures video generation behavior
+type VideoGenerationOptions struct {
+	// AspectRatio controls the video dimensions ("16:9" or "9:16")
+	AspectRatio string
+
+	// DurationSeconds is the length of the video; supported values depend on the model
+	DurationSeconds int
+
+	// Resolution is the video resolution ("720p" or "1080p")
+	Resolution string
+
+	// NegativePrompt describes what the video should not contain (Veo only)
+	NegativePrompt string
+}
+
+// DefaultVideoGenerationOptions returns the default options for video generation
+func DefaultVideoGenerationOptions() *VideoGenerationOptions {
+	return &VideoGenerationOptions{
+		AspectRatio:     "16:9",
+		DurationSeconds: 8,
+		Resolution:      "720p",
+	}
+}
+
+// VideoGenerationJob represents a video generation in progress
+type VideoGenerationJob struct {
+	// ID identifies the job at the provider
+	ID string
+
+	// Done is true once the videos are generated
+	Done bool
+
+	// Videos contains the generated videos once the job is done
+	Videos []GeneratedMedia
+
+	// Metadata contains provider-specific information, such as progress
+	Metadata map[string]interface{}
+}
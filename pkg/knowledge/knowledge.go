@@ -0,0 +1,318 @@
+// Package knowledge loads documents from files, directories, URLs and inline
+// text, splits them into overlapping chunks and stores them in a vector store
+// so they can be retrieved by the retriever tool.
+package knowledge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultChunkSize is the default maximum chunk size in characters
+	DefaultChunkSize = 1000
+
+	// DefaultChunkOverlap is the default number of characters shared by consecutive chunks
+	DefaultChunkOverlap = 100
+)
+
+// Source types
+const (
+	SourceTypeFile      = "file"
+	SourceTypeDirectory = "directory"
+	SourceTypeURL       = "url"
+	SourceTypeText      = "text"
+)
+
+// defaultExtensions are the file extensions ingested from directories when none are configured
+var defaultExtensions = []string{".txt", ".md", ".markdown", ".rst", ".html", ".csv", ".json", ".yaml", ".yml"}
+
+// Source describes where knowledge documents come from
+type Source struct {
+	// Type is one of "file", "directory", "url" or "text"
+	Type string
+
+	// Path is the file or directory path
+	Path string
+
+	// URL is fetched for "url" sources
+	URL string
+
+	// Content is the inline text for "text" sources
+	Content string
+
+	// Name is an optional citation name; defaults to the path or URL
+	Name string
+
+	// Extensions restricts which files are loaded from a directory
+	Extensions []string
+
+	// Metadata is attached to every chunk from this source
+	Metadata map[string]interface{}
+}
+
+// Options configures ingestion
+type Options struct {
+	ChunkSize     int
+	ChunkOverlap  int
+	StoreOptions  []interfaces.StoreOption
+	HTTPClient    *http.Client
+	MaxFetchBytes int64
+}
+
+// Option represents an option for configuring ingestion
+type Option func(*Options)
+
+// WithChunkSize sets the maximum chunk size in characters
+func WithChunkSize(size int) Option {
+	return func(o *Options) {
+		o.ChunkSize = size
+	}
+}
+
+// WithChunkOverlap sets the overlap between consecutive chunks in characters
+func WithChunkOverlap(overlap int) Option {
+	return func(o *Options) {
+		o.ChunkOverlap = overlap
+	}
+}
+
+// WithStoreOptions sets options passed to the vector store (class, tenant, ...)
+func WithStoreOptions(options ...interfaces.StoreOption) Option {
+	return func(o *Options) {
+		o.StoreOptions = append(o.StoreOptions, options...)
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to fetch URL sources
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *Options) {
+		o.HTTPClient = client
+	}
+}
+
+// Ingest loads every source, splits it into chunks and stores the chunks in
+// the vector store. Chunk IDs are derived from the source and chunk index, so
+// re-ingesting the same sources overwrites rather than duplicates documents.
+// It returns the number of chunks stored.
+func Ingest(ctx context.Context, store interfaces.VectorStore, sources []Source, options ...Option) (int, error) {
+	opts := Options{
+		ChunkSize:     DefaultChunkSize,
+		ChunkOverlap:  DefaultChunkOverlap,
+		HTTPClient:    &http.Client{Timeout: 30 * time.Second},
+		MaxFetchBytes: 10 << 20,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	var documents []interfaces.Document
+	for _, source := range sources {
+		loaded, err := load(ctx, source, opts)
+		if err != nil {
+			return 0, err
+		}
+		for _, doc := range loaded {
+			documents = append(documents, chunkDocument(doc, source.Metadata, opts)...)
+		}
+	}
+
+	if len(documents) == 0 {
+		return 0, nil
+	}
+
+	if err := store.Store(ctx, documents, opts.StoreOptions...); err != nil {
+		return 0, fmt.Errorf("failed to store knowledge chunks: %w", err)
+	}
+
+	return len(documents), nil
+}
+
+// loadedDocument is the full text of a single loaded document
+type loadedDocument struct {
+	name    string
+	content string
+}
+
+func load(ctx context.Context, source Source, opts Options) ([]loadedDocument, error) {
+	switch source.Type {
+	case SourceTypeText:
+		name := source.Name
+		if name == "" {
+			name = "inline"
+		}
+		return []loadedDocument{{name: name, content: source.Content}}, nil
+
+	case SourceTypeFile:
+		data, err := os.ReadFile(source.Path) // #nosec G304 - Path comes from trusted agent configuration
+		if err != nil {
+			return nil, fmt.Errorf("failed to read knowledge file %s: %w", source.Path, err)
+		}
+		return []loadedDocument{{name: nameOr(source.Name, source.Path), content: string(data)}}, nil
+
+	case SourceTypeDirectory:
+		return loadDirectory(source)
+
+	case SourceTypeURL:
+		content, err := fetch(ctx, source.URL, opts)
+		if err != nil {
+			return nil, err
+		}
+		return []loadedDocument{{name: nameOr(source.Name, source.URL), content: content}}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported knowledge source type: %q", source.Type)
+	}
+}
+
+func loadDirectory(source Source) ([]loadedDocument, error) {
+	extensions := source.Extensions
+	if len(extensions) == 0 {
+		extensions = defaultExtensions
+	}
+
+	var docs []loadedDocument
+	err := filepath.WalkDir(source.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !hasExtension(path, extensions) {
+			return nil
+		}
+		data, err := os.ReadFile(path) // #nosec G304 - Path is inside a configured knowledge directory
+		if err != nil {
+			return err
+		}
+		docs = append(docs, loadedDocument{name: path, content: string(data)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read knowledge directory %s: %w", source.Path, err)
+	}
+	return docs, nil
+}
+
+func fetch(ctx context.Context, url string, opts Options) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+	resp, err := opts.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch %s: status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, opts.MaxFetchBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return string(data), nil
+}
+
+func chunkDocument(doc loadedDocument, metadata map[string]interface{}, opts Options) []interfaces.Document {
+	chunks := SplitText(doc.content, opts.ChunkSize, opts.ChunkOverlap)
+	documents := make([]interfaces.Document, 0, len(chunks))
+	for i, chunk := range chunks {
+		meta := map[string]interface{}{
+			"source": doc.name,
+			"chunk":  i,
+		}
+		for k, v := range metadata {
+			meta[k] = v
+		}
+		documents = append(documents, interfaces.Document{
+			ID:       chunkID(doc.name, i),
+			Content:  chunk,
+			Metadata: meta,
+		})
+	}
+	return documents
+}
+
+// chunkID returns a stable ID for the i-th chunk of a named document
+func chunkID(name string, index int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s#%d", name, index)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// SplitText splits text into chunks of at most size characters with the given
+// overlap, preferring to break on paragraph, line, sentence and word boundaries.
+func SplitText(text string, size, overlap int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	runes := []rune(text)
+	var chunks []string
+	for start := 0; start < len(runes); {
+		end := start + size
+		if end >= len(runes) {
+			chunks = append(chunks, strings.TrimSpace(string(runes[start:])))
+			break
+		}
+
+		end = breakPoint(runes, start, end)
+		if chunk := strings.TrimSpace(string(runes[start:end])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+
+		next := end - overlap
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+	return chunks
+}
+
+// breakPoint finds the best boundary in the second half of runes[start:end]
+func breakPoint(runes []rune, start, end int) int {
+	window := string(runes[start:end])
+	minimum := len(window) / 2
+	for _, sep := range []string{"\n\n", "\n", ". ", " "} {
+		if i := strings.LastIndex(window, sep); i >= minimum {
+			return start + len([]rune(window[:i+len(sep)]))
+		}
+	}
+	return end
+}
+
+func hasExtension(path string, extensions []string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range extensions {
+		if strings.ToLower(e) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+func nameOr(name, fallback string) string {
+	if name != "" {
+		return name
+	}
+	return fallback
+}
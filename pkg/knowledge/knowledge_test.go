@@ -0,0 +1,108 @@
+package knowledge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/embedding"
+	vectormemory "github.com/Ingenimax/agent-sdk-go/pkg/vectorstore/memory"
+)
+
+// letterEmbedder embeds text as normalized letter frequencies
+type letterEmbedder struct{}
+
+func (letterEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vector := make([]float32, 26)
+	for _, r := range strings.ToLower(text) {
+		if r >= 'a' && r <= 'z' {
+			vector[r-'a']++
+		}
+	}
+	return vector, nil
+}
+
+func (e letterEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.Embed(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (letterEmbedder) CalculateSimilarity(vec1, vec2 []float32, metric string) (float32, error) {
+	return embedding.CalculateSimilarity(vec1, vec2, metric)
+}
+
+func TestSplitText(t *testing.T) {
+	text := strings.Repeat("word ", 100) // 500 characters
+	chunks := SplitText(text, 120, 20)
+	if len(chunks) < 4 {
+		t.Fatalf("expected at least 4 chunks, got %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if len([]rune(chunk)) > 120 {
+			t.Errorf("chunk exceeds size: %d", len(chunk))
+		}
+		if strings.HasPrefix(chunk, "ord") {
+			t.Errorf("chunk should break on word boundaries: %q", chunk)
+		}
+	}
+
+	if got := SplitText("  short  ", 100, 10); len(got) != 1 || got[0] != "short" {
+		t.Errorf("unexpected split of short text: %v", got)
+	}
+	if got := SplitText("", 100, 10); got != nil {
+		t.Errorf("expected no chunks for empty text, got %v", got)
+	}
+}
+
+func TestIngest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "zebra.md"), []byte("zebras zigzag across the zoo"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ignored.bin"), []byte("binary"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store := vectormemory.New(letterEmbedder{})
+	sources := []Source{
+		{Type: SourceTypeDirectory, Path: dir},
+		{Type: SourceTypeText, Name: "apples", Content: "apples and bananas are tasty"},
+	}
+
+	count, err := Ingest(context.Background(), store, sources)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 chunks, got %d", count)
+	}
+
+	results, err := store.Search(context.Background(), "zigzag zebra", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || !strings.HasSuffix(results[0].Document.Metadata["source"].(string), "zebra.md") {
+		t.Fatalf("expected zebra.md to be the best match, got %+v", results)
+	}
+
+	// Re-ingesting must overwrite rather than duplicate chunks
+	if _, err := Ingest(context.Background(), store, sources); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, _ = store.Search(context.Background(), "anything", 10)
+	if len(results) != 2 {
+		t.Errorf("expected 2 documents after re-ingestion, got %d", len(results))
+	}
+}
+
+func TestIngestUnsupportedSource(t *testing.T) {
+	store := vectormemory.New(letterEmbedder{})
+	if _, err := Ingest(context.Background(), store, []Source{{Type: "ftp"}}); err == nil {
+		t.Error("expected error for unsupported source type")
+	}
+}
@@ -71,6 +71,15 @@ func (b *cacheRequestBuilder) BuildMessages(messages []Message) (json.RawMessage
 
 	// Last message gets cache_control
 	lastMsg := messages[len(messages)-1]
+	if len(lastMsg.Blocks) > 0 {
+		blocks := lastMsg.contentBlocks()
+		blocks[len(blocks)-1].CacheControl = b.getCacheControl()
+		result[len(messages)-1] = struct {
+			Role    string       `json:"role"`
+			Content []InputBlock `json:"content"`
+		}{lastMsg.Role, blocks}
+		return json.Marshal(result)
+	}
 	result[len(messages)-1] = CacheableMessage{
 		Role: lastMsg.Role,
 		Content: []CacheableContent{
@@ -8,7 +8,6 @@ import (
 	"io"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
@@ -339,6 +338,10 @@ func SupportsThinking(model string) bool {
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+
+	// Blocks holds image and document blocks sent after Content. Messages
+	// with blocks are encoded with an array of content blocks.
+	Blocks []InputBlock `json:"-"`
 }
 
 // ToolUse represents a tool call for Anthropic API
@@ -357,6 +360,28 @@ type ToolResult struct {
 	ToolName string `json:"tool_name"`
 }
 
+// defaultMaxTokens is the max_tokens of requests without MaxOutputTokens
+const defaultMaxTokens = 2048
+
+// maxTokensFor returns the max_tokens of a request. With reasoning, max_tokens
+// must exceed the thinking budget, so the response gets MaxOutputTokens, or a
+// 4000 token buffer, on top of it.
+func maxTokensFor(config *interfaces.LLMConfig) int {
+	if config == nil {
+		return defaultMaxTokens
+	}
+	if config.EnableReasoning && config.ReasoningBudget > 0 {
+		if config.MaxOutputTokens > 0 {
+			return config.ReasoningBudget + config.MaxOutputTokens
+		}
+		return config.ReasoningBudget + 4000
+	}
+	if config.MaxOutputTokens > 0 {
+		return config.MaxOutputTokens
+	}
+	return defaultMaxTokens
+}
+
 // CompletionRequest represents a request for Anthropic API
 type CompletionRequest struct {
 	Model            string         `json:"model,omitempty"`
@@ -411,6 +436,31 @@ type CompletionResponse struct {
 	Usage      Usage          `json:"usage"`
 }
 
+// stopReasonRefusal is the stop reason reported when the model declines to respond
+const stopReasonRefusal = "refusal"
+
+// refusalError returns a *interfaces.ContentFilterError when the model
+// refused to respond, or nil otherwise
+func (r *CompletionResponse) refusalError() error {
+	if r.StopReason != stopReasonRefusal {
+		return nil
+	}
+
+	var text []string
+	for _, block := range r.Content {
+		if block.Type == "text" && block.Text != "" {
+			text = append(text, block.Text)
+		}
+	}
+
+	return &interfaces.ContentFilterError{
+		Provider:   "anthropic",
+		Category:   interfaces.ContentFilterCategoryRefusal,
+		Message:    strings.Join(text, "\n"),
+		StopReason: r.StopReason,
+	}
+}
+
 // Usage represents token usage information
 type Usage struct {
 	InputTokens              int `json:"input_tokens"`
@@ -519,8 +569,14 @@ func (c *AnthropicClient) generateInternal(ctx context.Context, prompt string, o
 	// Build messages with memory and current prompt
 	messages := c.buildMessagesWithMemory(ctx, prompt, params)
 
+	// Structured output is enforced by forcing a call to a synthetic tool whose
+	// input schema is the response schema. When that is not possible, fall
+	// back to describing the schema in the prompt.
+	thinking := params.LLMConfig != nil && params.LLMConfig.EnableReasoning && SupportsThinking(c.Model)
+	structuredTool, useStructuredTool := structuredOutputTool(params.ResponseFormat, thinking)
+
 	// Handle structured output if requested
-	if params.ResponseFormat != nil {
+	if params.ResponseFormat != nil && !useStructuredTool {
 		// Convert the schema to a string representation for the prompt
 		schemaJSON, err := json.MarshalIndent(params.ResponseFormat.Schema, "", "  ")
 		if err != nil {
@@ -544,12 +600,7 @@ Example output:
 Return only the JSON object, with no additional text or markdown formatting.`, prompt, string(schemaJSON), string(exampleStr))
 	}
 
-	// Calculate maxTokens - must be greater than budget_tokens when reasoning is enabled
-	maxTokens := 2048 // default
-	if params.LLMConfig != nil && params.LLMConfig.EnableReasoning && params.LLMConfig.ReasoningBudget > 0 {
-		// Ensure max_tokens > budget_tokens for reasoning
-		maxTokens = params.LLMConfig.ReasoningBudget + 4000 // Add buffer for actual response
-	}
+	maxTokens := maxTokensFor(params.LLMConfig)
 
 	// Create request
 	req := CompletionRequest{
@@ -565,8 +616,13 @@ Return only the JSON object, with no additional text or markdown formatting.`, p
 		req.StopSequences = params.LLMConfig.StopSequences
 	}
 
+	if useStructuredTool {
+		req.Tools = []Tool{structuredTool}
+		req.ToolChoice = structuredOutputToolChoice()
+	}
+
 	// Handle reasoning/thinking if supported
-	if params.LLMConfig != nil && params.LLMConfig.EnableReasoning && SupportsThinking(c.Model) {
+	if thinking {
 		req.Thinking = &ReasoningSpec{
 			Type: "enabled",
 		}
@@ -664,7 +720,7 @@ Return only the JSON object, with no additional text or markdown formatting.`, p
 		// Perform the request
 		httpResp, err := c.HTTPClient.Do(httpReq)
 		if err != nil {
-			return fmt.Errorf("failed to send request to %s: %w", apiType, err)
+			return llm.ClassifyError("anthropic", fmt.Errorf("failed to send request to %s: %w", apiType, err))
 		}
 		defer func() {
 			if err := httpResp.Body.Close(); err != nil {
@@ -681,7 +737,7 @@ Return only the JSON object, with no additional text or markdown formatting.`, p
 
 		// Check for HTTP errors
 		if httpResp.StatusCode != http.StatusOK {
-			return fmt.Errorf("%s API error (status %d): %s", apiType, httpResp.StatusCode, string(body))
+			return apiError(httpResp, body, fmt.Errorf("%s API error (status %d): %s", apiType, httpResp.StatusCode, string(body)))
 		}
 
 		// Parse response
@@ -705,23 +761,35 @@ Return only the JSON object, with no additional text or markdown formatting.`, p
 		return nil, err
 	}
 
-	// Extract text from content blocks
-	var contentText []string
-	for _, block := range resp.Content {
-		if block.Type == "text" {
-			contentText = append(contentText, block.Text)
-		}
+	if err := resp.refusalError(); err != nil {
+		return nil, err
 	}
 
-	if len(contentText) == 0 {
-		return nil, fmt.Errorf("no text content in response")
-	}
+	var content string
+	if useStructuredTool {
+		var ok bool
+		if content, ok = structuredOutputFromResponse(&resp); !ok {
+			return nil, fmt.Errorf("no %s tool call in structured output response", structuredOutputToolName)
+		}
+	} else {
+		// Extract text from content blocks
+		var contentText []string
+		for _, block := range resp.Content {
+			if block.Type == "text" {
+				contentText = append(contentText, block.Text)
+			}
+		}
 
-	content := strings.Join(contentText, "\n")
+		if len(contentText) == 0 {
+			return nil, fmt.Errorf("no text content in response")
+		}
 
-	// For structured output, prepend the opening brace that was used as prefill
-	if params.ResponseFormat != nil && !strings.HasPrefix(strings.TrimSpace(content), "{") {
-		content = "{" + content
+		content = strings.Join(contentText, "\n")
+
+		// For structured output, prepend the opening brace that was used as prefill
+		if params.ResponseFormat != nil && !strings.HasPrefix(strings.TrimSpace(content), "{") {
+			content = "{" + content
+		}
 	}
 
 	// Create detailed response
@@ -874,7 +942,7 @@ func (c *AnthropicClient) Chat(ctx context.Context, messages []llm.Message, para
 				"error": err.Error(),
 				"model": c.Model,
 			})
-			return fmt.Errorf("failed to send request: %w", err)
+			return llm.ClassifyError("anthropic", fmt.Errorf("failed to send request: %w", err))
 		}
 		defer func() {
 			if closeErr := httpResp.Body.Close(); closeErr != nil {
@@ -897,7 +965,7 @@ func (c *AnthropicClient) Chat(ctx context.Context, messages []llm.Message, para
 				"response":    string(respBody),
 				"model":       c.Model,
 			})
-			return fmt.Errorf("error from Anthropic API: %s", string(respBody))
+			return apiError(httpResp, respBody, fmt.Errorf("error from Anthropic API: %s", string(respBody)))
 		}
 
 		// Log raw response before unmarshaling for debugging
@@ -985,44 +1053,8 @@ func (c *AnthropicClient) Chat(ctx context.Context, messages []llm.Message, para
 	return response, nil
 }
 
-// GenerateWithTools implements interfaces.LLM.GenerateWithTools
-func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
-	// Check if model is specified
-	if c.Model == "" {
-		return "", fmt.Errorf("model not specified: use WithModel option when creating the client")
-	}
-
-	// Apply options
-	params := &interfaces.GenerateOptions{
-		LLMConfig: &interfaces.LLMConfig{
-			Temperature: 0.7, // Default temperature
-		},
-	}
-
-	// Apply user-provided options
-	for _, opt := range options {
-		if opt != nil {
-			opt(params)
-		}
-	}
-
-	// Set default max iterations if not provided
-	maxIterations := params.MaxIterations
-	if maxIterations == 0 {
-		maxIterations = 2 // Default to current behavior
-	}
-
-	// Check for organization ID in context, and add a default one if missing
-	defaultOrgID := "default"
-	if id, err := multitenancy.GetOrgID(ctx); err == nil {
-		// Organization ID found in context, use it
-		ctx = multitenancy.WithOrgID(ctx, id) // Ensure consistency in context
-	} else {
-		// Add default organization ID to context to prevent errors in tool execution
-		ctx = multitenancy.WithOrgID(ctx, defaultOrgID)
-	}
-
-	// Convert tools to Anthropic format
+// convertTools converts tools to the Anthropic tool format
+func convertTools(tools []interfaces.Tool) []Tool {
 	anthropicTools := make([]Tool, len(tools))
 	for i, tool := range tools {
 		// Convert ParameterSpec to JSON Schema
@@ -1066,383 +1098,362 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string,
 			InputSchema: inputSchema,
 		}
 	}
+	return anthropicTools
+}
 
-	// Track tool call repetitions for loop detection
-	toolCallHistory := make(map[string]int)
+// GenerateWithTools implements interfaces.LLM.GenerateWithTools
+func (c *AnthropicClient) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	// Check if model is specified
+	if c.Model == "" {
+		return "", fmt.Errorf("model not specified: use WithModel option when creating the client")
+	}
 
-	// Build messages with memory and current prompt
-	messages := c.buildMessagesWithMemory(ctx, prompt, params)
+	// Apply options
+	params := &interfaces.GenerateOptions{
+		LLMConfig: &interfaces.LLMConfig{
+			Temperature: 0.7, // Default temperature
+		},
+	}
 
-	// Calculate maxTokens - must be greater than budget_tokens when reasoning is enabled
-	maxTokens := 2048 // default
-	if params.LLMConfig != nil && params.LLMConfig.EnableReasoning && params.LLMConfig.ReasoningBudget > 0 {
-		// Ensure max_tokens > budget_tokens for reasoning
-		maxTokens = params.LLMConfig.ReasoningBudget + 4000 // Add buffer for actual response
-	}
-
-	// Track the last response content from the tool-calling loop
-	var lastContent string
-
-	// Iterative tool calling loop
-	for iteration := 0; iteration < maxIterations; iteration++ {
-		// Create request
-		req := CompletionRequest{
-			Model:       c.Model,
-			Messages:    messages,
-			MaxTokens:   maxTokens,
-			Temperature: params.LLMConfig.Temperature,
-			TopP:        params.LLMConfig.TopP,
-			Tools:       anthropicTools,
-			// Auto use tools when needed
-			ToolChoice: map[string]string{
-				"type": "auto",
-			},
-		}
-
-		// Add system message if available
-		if params.SystemMessage != "" {
-			// If structured output is requested, enhance the system message to ensure raw JSON
-			if params.ResponseFormat != nil {
-				req.System = params.SystemMessage + "\n\nIMPORTANT: You must respond with valid JSON that matches the specified schema. Return ONLY the raw JSON object without any markdown formatting, code blocks, or wrapper text. Pay special attention to array fields - if a field is defined as an array in the schema, it MUST be an array in your response, not an object."
-			} else {
-				req.System = params.SystemMessage
-			}
-			c.logger.Debug(ctx, "Using system message", map[string]interface{}{"system_message": params.SystemMessage})
-		} else if params.ResponseFormat != nil {
-			// If no system message but structured output is requested, add a system message for JSON
-			req.System = "You must respond with valid JSON that matches the specified schema. Return ONLY the raw JSON object without any markdown formatting, code blocks, or wrapper text. Pay special attention to array fields - if a field is defined as an array in the schema, it MUST be an array in your response, not an object."
-			c.logger.Debug(ctx, "Added system message for structured output", nil)
+	// Apply user-provided options
+	for _, opt := range options {
+		if opt != nil {
+			opt(params)
 		}
+	}
 
-		// Add reasoning parameter if available
-		if params.LLMConfig != nil && params.LLMConfig.Reasoning != "" {
-			c.logger.Debug(ctx, "Reasoning mode not supported in current API version", map[string]interface{}{"reasoning": params.LLMConfig.Reasoning})
-		}
+	// Check for organization ID in context, and add a default one if missing
+	defaultOrgID := "default"
+	if id, err := multitenancy.GetOrgID(ctx); err == nil {
+		// Organization ID found in context, use it
+		ctx = multitenancy.WithOrgID(ctx, id) // Ensure consistency in context
+	} else {
+		// Add default organization ID to context to prevent errors in tool execution
+		ctx = multitenancy.WithOrgID(ctx, defaultOrgID)
+	}
 
-		// Send request
-		c.logger.Debug(ctx, "Sending request with tools to Anthropic", map[string]interface{}{
-			"model":         c.Model,
-			"temperature":   req.Temperature,
-			"top_p":         req.TopP,
-			"messages":      len(req.Messages),
-			"tools":         len(req.Tools),
-			"system":        req.System != "",
-			"iteration":     iteration + 1,
-			"maxIterations": maxIterations,
-		})
+	// Run the tool-calling loop with the shared tool executor
+	executor := llm.NewToolExecutor(c.logger, llm.WithToolMemory(params.Memory), llm.WithToolTimeout(params.ToolTimeout), llm.WithParallelToolCalls(params.ParallelToolCalls), llm.WithToolsFunc(params.ToolsFunc))
+	loop := &messagesToolLoop{
+		client:    c,
+		params:    params,
+		messages:  c.buildMessagesWithMemory(ctx, prompt, params),
+		maxTokens: maxTokensFor(params.LLMConfig),
+	}
+	result, err := executor.Run(ctx, loop, tools, params.ToolLoop())
+	if err != nil {
+		return "", err
+	}
+	return result.Content, nil
+}
 
-		var resp CompletionResponse
-		var err error
+// messagesToolLoop sends the requests of GenerateWithTools to the Messages
+// API, for the shared tool-calling loop
+type messagesToolLoop struct {
+	client    *AnthropicClient
+	params    *interfaces.GenerateOptions
+	messages  []Message
+	maxTokens int
 
-		// Define operation for retry mechanism
-		operation := func() error {
-			// Bedrock uses AWS SDK, not HTTP requests
-			if c.BedrockConfig != nil && c.BedrockConfig.Enabled {
-				bedrockResp, err := c.BedrockConfig.InvokeModel(ctx, c.Model, &req, params.CacheConfig)
-				if err != nil {
-					return fmt.Errorf("failed to invoke Bedrock model (iteration %d): %w", iteration+1, err)
-				}
-				resp = *bedrockResp
-				return nil
-			}
+	// text is the text content of the last response
+	text string
+}
 
-			// Create HTTP request (supports both Vertex AI and standard Anthropic API, with caching)
-			httpReq, err := c.createHTTPRequestWithCache(ctx, &req, "/v1/messages", params.CacheConfig)
-			if err != nil {
-				return fmt.Errorf("failed to create request (iteration %d): %w", iteration+1, err)
-			}
+// Send implements llm.ToolLoopProvider.Send
+func (l *messagesToolLoop) Send(ctx context.Context, iteration int, tools []interfaces.Tool) (llm.ToolLoopTurn, error) {
+	c := l.client
+	params := l.params
 
-			// Send request
-			httpResp, err := c.HTTPClient.Do(httpReq)
-			if err != nil {
-				c.logger.Error(ctx, "Error from Anthropic API", map[string]interface{}{
-					"error":     err.Error(),
-					"model":     c.Model,
-					"iteration": iteration + 1,
-				})
-				return fmt.Errorf("failed to send request (iteration %d): %w", iteration+1, err)
-			}
-			defer func() {
-				if closeErr := httpResp.Body.Close(); closeErr != nil {
-					c.logger.Warn(ctx, "Failed to close response body", map[string]interface{}{
-						"error": closeErr.Error(),
-					})
-				}
-			}()
+	// Create request
+	req := CompletionRequest{
+		Model:       c.Model,
+		Messages:    l.messages,
+		MaxTokens:   l.maxTokens,
+		Temperature: params.LLMConfig.Temperature,
+		TopP:        params.LLMConfig.TopP,
+		Tools:       convertTools(tools),
+		// Auto use tools when needed
+		ToolChoice: map[string]string{
+			"type": "auto",
+		},
+	}
 
-			// Read response body
-			respBody, err := io.ReadAll(httpResp.Body)
-			if err != nil {
-				return fmt.Errorf("failed to read response body (iteration %d): %w", iteration+1, err)
-			}
+	// Add system message if available
+	if params.SystemMessage != "" {
+		// If structured output is requested, enhance the system message to ensure raw JSON
+		if params.ResponseFormat != nil {
+			req.System = params.SystemMessage + "\n\nIMPORTANT: You must respond with valid JSON that matches the specified schema. Return ONLY the raw JSON object without any markdown formatting, code blocks, or wrapper text. Pay special attention to array fields - if a field is defined as an array in the schema, it MUST be an array in your response, not an object."
+		} else {
+			req.System = params.SystemMessage
+		}
+		c.logger.Debug(ctx, "Using system message", map[string]interface{}{"system_message": params.SystemMessage})
+	} else if params.ResponseFormat != nil {
+		// If no system message but structured output is requested, add a system message for JSON
+		req.System = "You must respond with valid JSON that matches the specified schema. Return ONLY the raw JSON object without any markdown formatting, code blocks, or wrapper text. Pay special attention to array fields - if a field is defined as an array in the schema, it MUST be an array in your response, not an object."
+		c.logger.Debug(ctx, "Added system message for structured output", nil)
+	}
 
-			// Check for error response
-			if httpResp.StatusCode != http.StatusOK {
-				c.logger.Error(ctx, "Error from Anthropic API", map[string]interface{}{
-					"status_code": httpResp.StatusCode,
-					"response":    string(respBody),
-					"model":       c.Model,
-					"iteration":   iteration + 1,
-				})
-				return fmt.Errorf("error from Anthropic API (iteration %d): %s", iteration+1, string(respBody))
-			}
+	// Add reasoning parameter if available
+	if params.LLMConfig != nil && params.LLMConfig.Reasoning != "" {
+		c.logger.Debug(ctx, "Reasoning mode not supported in current API version", map[string]interface{}{"reasoning": params.LLMConfig.Reasoning})
+	}
+
+	// Send request
+	c.logger.Debug(ctx, "Sending request with tools to Anthropic", map[string]interface{}{
+		"model":       c.Model,
+		"temperature": req.Temperature,
+		"top_p":       req.TopP,
+		"messages":    len(req.Messages),
+		"tools":       len(req.Tools),
+		"system":      req.System != "",
+		"iteration":   iteration + 1,
+	})
 
-			// Log raw response before unmarshaling for debugging
-			c.logger.Debug(ctx, "Raw response before unmarshaling", map[string]interface{}{
-				"response_length": len(respBody),
-				"response_prefix": func() string {
-					if len(respBody) > 100 {
-						return string(respBody[:100])
-					}
-					return string(respBody)
-				}(),
-				"first_char": func() string {
-					if len(respBody) > 0 {
-						return fmt.Sprintf("'%c' (0x%02x)", respBody[0], respBody[0])
-					}
-					return "empty"
-				}(),
-				"iteration": iteration + 1,
-			})
+	var resp CompletionResponse
+	var err error
 
-			// Unmarshal response
-			err = json.Unmarshal(respBody, &resp)
+	// Define operation for retry mechanism
+	operation := func() error {
+		// Bedrock uses AWS SDK, not HTTP requests
+		if c.BedrockConfig != nil && c.BedrockConfig.Enabled {
+			bedrockResp, err := c.BedrockConfig.InvokeModel(ctx, c.Model, &req, params.CacheConfig)
 			if err != nil {
-				return fmt.Errorf("failed to unmarshal response (iteration %d): %w", iteration+1, err)
+				return fmt.Errorf("failed to invoke Bedrock model (iteration %d): %w", iteration+1, err)
 			}
-
+			resp = *bedrockResp
 			return nil
 		}
 
-		// Execute operation with retry mechanism
-		if c.vertexRetryExecutor != nil {
-			c.logger.Info(ctx, "Using Vertex retry mechanism with region rotation for GenerateWithTools", map[string]interface{}{
-				"model":          c.Model,
-				"current_region": c.VertexConfig.GetCurrentRegion(),
-				"iteration":      iteration + 1,
-			})
-			err = c.vertexRetryExecutor.Execute(ctx, operation)
-		} else if c.retryExecutor != nil {
-			c.logger.Info(ctx, "Using standard retry mechanism for GenerateWithTools", map[string]interface{}{
-				"model":                   c.Model,
-				"vertex_config_available": c.VertexConfig != nil,
-				"iteration":               iteration + 1,
-			})
-			err = c.retryExecutor.Execute(ctx, operation)
-		} else {
-			c.logger.Debug(ctx, "No retry mechanism configured for GenerateWithTools", map[string]interface{}{
+		// Create HTTP request (supports both Vertex AI and standard Anthropic API, with caching)
+		httpReq, err := c.createHTTPRequestWithCache(ctx, &req, "/v1/messages", params.CacheConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create request (iteration %d): %w", iteration+1, err)
+		}
+
+		// Send request
+		httpResp, err := c.HTTPClient.Do(httpReq)
+		if err != nil {
+			c.logger.Error(ctx, "Error from Anthropic API", map[string]interface{}{
+				"error":     err.Error(),
 				"model":     c.Model,
 				"iteration": iteration + 1,
 			})
-			err = operation()
+			return llm.ClassifyError("anthropic", fmt.Errorf("failed to send request (iteration %d): %w", iteration+1, err))
 		}
+		defer func() {
+			if closeErr := httpResp.Body.Close(); closeErr != nil {
+				c.logger.Warn(ctx, "Failed to close response body", map[string]interface{}{
+					"error": closeErr.Error(),
+				})
+			}
+		}()
 
+		// Read response body
+		respBody, err := io.ReadAll(httpResp.Body)
 		if err != nil {
-			return "", err
+			return fmt.Errorf("failed to read response body (iteration %d): %w", iteration+1, err)
 		}
 
-		// Make sure content is not nil
-		if resp.Content == nil {
-			c.logger.Error(ctx, "No content in response", map[string]interface{}{"iteration": iteration + 1})
-			return "", fmt.Errorf("no content in response (iteration %d)", iteration+1)
+		// Check for error response
+		if httpResp.StatusCode != http.StatusOK {
+			c.logger.Error(ctx, "Error from Anthropic API", map[string]interface{}{
+				"status_code": httpResp.StatusCode,
+				"response":    string(respBody),
+				"model":       c.Model,
+				"iteration":   iteration + 1,
+			})
+			return apiError(httpResp, respBody, fmt.Errorf("error from Anthropic API (iteration %d): %s", iteration+1, string(respBody)))
 		}
 
-		// Check if the model wants to use tools
-		var hasToolUse bool
-		var toolCalls []ToolUse
-		var textContent []string
-
-		c.logger.Debug(ctx, "Response content blocks", map[string]interface{}{
-			"numBlocks": len(resp.Content),
-			"iteration": iteration + 1,
-			"blockTypes": func() []string {
-				types := make([]string, len(resp.Content))
-				for i, block := range resp.Content {
-					types[i] = block.Type
-					if block.Type == "tool_use" && block.ToolUse != nil {
-						toolName := ""
-						if block.ToolUse.Name != "" {
-							toolName = block.ToolUse.Name
-						} else if block.ToolUse.RecipientName != "" {
-							toolName = block.ToolUse.RecipientName
-						}
-						c.logger.Debug(ctx, "Found tool use block", map[string]interface{}{
-							"toolName":  toolName,
-							"toolID":    block.ToolUse.ID,
-							"iteration": iteration + 1,
-						})
-					}
+		// Log raw response before unmarshaling for debugging
+		c.logger.Debug(ctx, "Raw response before unmarshaling", map[string]interface{}{
+			"response_length": len(respBody),
+			"response_prefix": func() string {
+				if len(respBody) > 100 {
+					return string(respBody[:100])
 				}
-				return types
+				return string(respBody)
 			}(),
-		})
-
-		for _, contentBlock := range resp.Content {
-			switch contentBlock.Type {
-			case "tool_use":
-				hasToolUse = true
-				// Handle both nested ToolUse (direct API) and direct fields (Vertex AI)
-				if contentBlock.ToolUse != nil {
-					toolCalls = append(toolCalls, *contentBlock.ToolUse)
-				} else if contentBlock.ID != "" && contentBlock.Name != "" {
-					// Create ToolUse from direct fields (Vertex AI format)
-					toolUse := ToolUse{
-						ID:    contentBlock.ID,
-						Name:  contentBlock.Name,
-						Input: contentBlock.Input,
-					}
-					toolCalls = append(toolCalls, toolUse)
+			"first_char": func() string {
+				if len(respBody) > 0 {
+					return fmt.Sprintf("'%c' (0x%02x)", respBody[0], respBody[0])
 				}
-			case "text":
-				textContent = append(textContent, contentBlock.Text)
-			}
-		}
-
-		c.logger.Debug(ctx, "Tool use detection results", map[string]interface{}{
-			"hasToolUse": hasToolUse,
-			"toolCalls":  len(toolCalls),
-			"iteration":  iteration + 1,
+				return "empty"
+			}(),
+			"iteration": iteration + 1,
 		})
 
-		// Capture the last text content from this iteration
-		if len(textContent) > 0 {
-			lastContent = strings.Join(textContent, "\n")
+		// Unmarshal response
+		err = json.Unmarshal(respBody, &resp)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal response (iteration %d): %w", iteration+1, err)
 		}
 
-		// If no tool use, return the text content
-		if !hasToolUse {
-			if len(textContent) == 0 {
-				return "", fmt.Errorf("no text content in response (iteration %d)", iteration+1)
-			}
-
-			// Join the text content
-			response := strings.Join(textContent, "\n")
-
-			// If we have a ResponseFormat, extract JSON from the response
-			if params.ResponseFormat != nil {
-				extractedJSON := extractJSONFromResponse(response)
-				if extractedJSON != response {
-					c.logger.Debug(ctx, "Extracted JSON from response", map[string]interface{}{
-						"original_length":  len(response),
-						"extracted_length": len(extractedJSON),
-					})
-					response = extractedJSON
-				}
-			}
-
-			c.logger.Debug(ctx, "Returning final response (no tool use)", map[string]interface{}{
-				"response_length": len(response),
-				"response_preview": func() string {
-
-					return response
-				}(),
-				"iteration": iteration + 1,
-			})
-
-			return response, nil
-		}
+		return nil
+	}
 
-		// The model wants to use tools
-		c.logger.Info(ctx, "Processing tool calls", map[string]interface{}{
-			"count":     len(toolCalls),
+	// Execute operation with retry mechanism
+	if c.vertexRetryExecutor != nil {
+		c.logger.Info(ctx, "Using Vertex retry mechanism with region rotation for GenerateWithTools", map[string]interface{}{
+			"model":          c.Model,
+			"current_region": c.VertexConfig.GetCurrentRegion(),
+			"iteration":      iteration + 1,
+		})
+		err = c.vertexRetryExecutor.Execute(ctx, operation)
+	} else if c.retryExecutor != nil {
+		c.logger.Info(ctx, "Using standard retry mechanism for GenerateWithTools", map[string]interface{}{
+			"model":                   c.Model,
+			"vertex_config_available": c.VertexConfig != nil,
+			"iteration":               iteration + 1,
+		})
+		err = c.retryExecutor.Execute(ctx, operation)
+	} else {
+		c.logger.Debug(ctx, "No retry mechanism configured for GenerateWithTools", map[string]interface{}{
+			"model":     c.Model,
 			"iteration": iteration + 1,
 		})
+		err = operation()
+	}
 
-		// Add the assistant response to messages only if there's text content
-		// (Tool-only responses will have empty text content)
-		assistantContent := strings.Join(textContent, "\n")
-		if strings.TrimSpace(assistantContent) != "" {
-			messages = append(messages, Message{
-				Role:    "assistant",
-				Content: assistantContent,
-			})
+	if err != nil {
+		return llm.ToolLoopTurn{}, err
+	}
+
+	if err := resp.refusalError(); err != nil {
+		return llm.ToolLoopTurn{}, err
+	}
+
+	// Make sure content is not nil
+	if resp.Content == nil {
+		c.logger.Error(ctx, "No content in response", map[string]interface{}{"iteration": iteration + 1})
+		return llm.ToolLoopTurn{}, fmt.Errorf("no content in response (iteration %d)", iteration+1)
+	}
+
+	// Check if the model wants to use tools
+	var toolCalls []ToolUse
+	var textContent []string
+	for _, contentBlock := range resp.Content {
+		switch contentBlock.Type {
+		case "tool_use":
+			// Handle both nested ToolUse (direct API) and direct fields (Vertex AI)
+			if contentBlock.ToolUse != nil {
+				toolCalls = append(toolCalls, *contentBlock.ToolUse)
+			} else if contentBlock.ID != "" && contentBlock.Name != "" {
+				// Create ToolUse from direct fields (Vertex AI format)
+				toolCalls = append(toolCalls, ToolUse{
+					ID:    contentBlock.ID,
+					Name:  contentBlock.Name,
+					Input: contentBlock.Input,
+				})
+			}
+		case "text":
+			textContent = append(textContent, contentBlock.Text)
 		}
+	}
 
-		// Process tool calls in PARALLEL for better performance
-		toolResults := c.executeToolsParallel(ctx, toolCalls, tools, params, toolCallHistory, iteration)
+	c.logger.Debug(ctx, "Tool use detection results", map[string]interface{}{
+		"toolCalls": len(toolCalls),
+		"iteration": iteration + 1,
+	})
 
-		// Create a new message from the user with the tool results
-		toolResultsJSON, err := json.Marshal(toolResults)
-		if err != nil {
-			return "", fmt.Errorf("failed to marshal tool results (iteration %d): %w", iteration+1, err)
-		}
+	l.text = strings.Join(textContent, "\n")
+	turn := llm.ToolLoopTurn{Content: l.text, Calls: c.decodeToolCalls(ctx, toolCalls)}
+	if len(toolCalls) > 0 {
+		return turn, nil
+	}
 
-		// Add a user message with the tool results
-		messages = append(messages, Message{
-			Role:    "user",
-			Content: fmt.Sprintf("Here are the tool results: %s", string(toolResultsJSON)),
-		})
+	// If no tool use, return the text content
+	if len(textContent) == 0 {
+		return llm.ToolLoopTurn{}, fmt.Errorf("no text content in response (iteration %d)", iteration+1)
+	}
 
-		// Continue to the next iteration with updated messages
+	// If we have a ResponseFormat, extract JSON from the response
+	if params.ResponseFormat != nil {
+		extractedJSON := extractJSONFromResponse(turn.Content)
+		if extractedJSON != turn.Content {
+			c.logger.Debug(ctx, "Extracted JSON from response", map[string]interface{}{
+				"original_length":  len(turn.Content),
+				"extracted_length": len(extractedJSON),
+			})
+			turn.Content = extractedJSON
+		}
 	}
 
-	// If we've reached the maximum iterations and the model is still requesting tools,
-	// make one final call without tools to get a conclusion
+	c.logger.Debug(ctx, "Returning final response (no tool use)", map[string]interface{}{
+		"response_length": len(turn.Content),
+		"iteration":       iteration + 1,
+	})
+	return turn, nil
+}
 
-	// If DisableFinalSummary is enabled, return the last response from the tool-calling loop
-	if params.DisableFinalSummary {
-		c.logger.Info(ctx, "DisableFinalSummary enabled, skipping final summary call", map[string]interface{}{
-			"maxIterations": maxIterations,
+// AddResults implements llm.ToolLoopProvider.AddResults
+func (l *messagesToolLoop) AddResults(ctx context.Context, iteration int, results []llm.ToolResult) error {
+	// Add the assistant response to messages only if there's text content
+	// (Tool-only responses will have empty text content)
+	if strings.TrimSpace(l.text) != "" {
+		l.messages = append(l.messages, Message{
+			Role:    "assistant",
+			Content: l.text,
 		})
-		return lastContent, nil
 	}
 
-	c.logger.Info(ctx, "Maximum iterations reached, making final call without tools", map[string]interface{}{
-		"maxIterations": maxIterations,
+	// Create a new message from the user with the tool results
+	toolResults := make([]ToolResult, len(results))
+	for i, result := range results {
+		toolResults[i] = ToolResult{Type: "tool_result", Content: result.Content, ToolName: result.Call.Name}
+	}
+	toolResultsJSON, err := json.Marshal(toolResults)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool results (iteration %d): %w", iteration+1, err)
+	}
+
+	// Add a user message with the tool results
+	l.messages = append(l.messages, Message{
+		Role:    "user",
+		Content: fmt.Sprintf("Here are the tool results: %s", string(toolResultsJSON)),
 	})
+	return nil
+}
+
+// Conclude implements llm.ToolLoopProvider.Conclude
+func (l *messagesToolLoop) Conclude(ctx context.Context, finalPrompt string) (string, error) {
+	c := l.client
+	params := l.params
+	messages := l.messages
 
 	// Create a final request without tools to force the LLM to provide a conclusion
 	finalReq := CompletionRequest{
 		Model:       c.Model,
-		Messages:    messages,
-		MaxTokens:   maxTokens, // Use calculated maxTokens (already accounts for reasoning budget)
+		MaxTokens:   l.maxTokens, // Use calculated maxTokens (already accounts for reasoning budget)
 		Temperature: params.LLMConfig.Temperature,
 		TopP:        params.LLMConfig.TopP,
 		Tools:       nil, // No tools for final call
 	}
 
+	// Structured output is enforced by forcing the synthetic result tool, the
+	// only tool offered in the final call
+	structuredTool, useStructuredTool := structuredOutputTool(params.ResponseFormat, false)
+	if useStructuredTool {
+		finalReq.Tools = []Tool{structuredTool}
+		finalReq.ToolChoice = structuredOutputToolChoice()
+	}
+
 	// Add system message if available and enhance for structured output
 	if params.SystemMessage != "" {
-		if params.ResponseFormat != nil {
+		if params.ResponseFormat != nil && !useStructuredTool {
 			finalReq.System = params.SystemMessage + "\n\nIMPORTANT: You must respond with valid JSON that matches the specified schema. Return ONLY the raw JSON object without any markdown formatting, code blocks, or wrapper text. Pay special attention to array fields - if a field is defined as an array in the schema, it MUST be an array in your response, not an object."
 		} else {
 			finalReq.System = params.SystemMessage
 		}
-	} else if params.ResponseFormat != nil {
+	} else if params.ResponseFormat != nil && !useStructuredTool {
 		// If no system message but structured output is requested, add a system message for JSON
 		finalReq.System = "You must respond with valid JSON that matches the specified schema. Return ONLY the raw JSON object without any markdown formatting, code blocks, or wrapper text. Pay special attention to array fields - if a field is defined as an array in the schema, it MUST be an array in your response, not an object."
 	}
 
 	// Add a user message to encourage conclusion
-	finalUserMessage := "Please provide your final response based on the information available. Do not request any additional tools."
-
-	// If structured output is requested, enhance the final message with schema and examples
-	if params.ResponseFormat != nil {
-		// Convert the schema to a string representation for the prompt
-		schemaJSON, err := json.MarshalIndent(params.ResponseFormat.Schema, "", "  ")
-		if err == nil {
-			// Create an example JSON structure based on the schema
-			exampleJSON := createExampleFromSchema(params.ResponseFormat.Schema)
-			exampleStr, _ := json.MarshalIndent(exampleJSON, "", "  ")
-
-			// Enhance the final user message with schema information and example
-			finalUserMessage = fmt.Sprintf(`%s
-
-You must respond with a valid JSON object that exactly follows this schema:
-%s
-
-Here is an example of the expected JSON structure:
-%s
-
-CRITICAL INSTRUCTIONS:
-- Output ONLY valid JSON, no additional text before or after
-- Follow the EXACT structure shown in the schema and example
-- Use the field names exactly as specified
-- Ensure all required fields are present
-- Pay special attention to array fields - they must be arrays of objects, not simple objects
-- If a field is defined as an array in the schema, it MUST be an array in your response
-- The JSON must be directly parsable and match the schema precisely`, finalUserMessage, string(schemaJSON), string(exampleStr))
-		}
+	finalUserMessage := finalPrompt
+	if !useStructuredTool {
+		finalUserMessage = finalPromptWithSchema(finalPrompt, params.ResponseFormat)
 	}
 
 	messages = append(messages, Message{
@@ -1451,7 +1462,7 @@ CRITICAL INSTRUCTIONS:
 	})
 
 	// Add assistant message prefill for structured output to enforce JSON output
-	if params.ResponseFormat != nil {
+	if params.ResponseFormat != nil && !useStructuredTool {
 		messages = append(messages, Message{
 			Role:    "assistant",
 			Content: "{",
@@ -1475,7 +1486,7 @@ CRITICAL INSTRUCTIONS:
 	finalHTTPResp, err := c.HTTPClient.Do(finalHTTPReq)
 	if err != nil {
 		c.logger.Error(ctx, "Error in final call without tools", map[string]interface{}{"error": err.Error()})
-		return "", fmt.Errorf("failed to send final request: %w", err)
+		return "", llm.ClassifyError("anthropic", fmt.Errorf("failed to send final request: %w", err))
 	}
 	defer func() {
 		if closeErr := finalHTTPResp.Body.Close(); closeErr != nil {
@@ -1497,26 +1508,9 @@ CRITICAL INSTRUCTIONS:
 			"status_code": finalHTTPResp.StatusCode,
 			"response":    string(finalRespBody),
 		})
-		return "", fmt.Errorf("error from Anthropic API in final call: %s", string(finalRespBody))
+		return "", apiError(finalHTTPResp, finalRespBody, fmt.Errorf("error from Anthropic API in final call: %s", string(finalRespBody)))
 	}
 
-	// Log raw final response before unmarshaling for debugging
-	c.logger.Debug(ctx, "Raw final response before unmarshaling", map[string]interface{}{
-		"response_length": len(finalRespBody),
-		"response_prefix": func() string {
-			if len(finalRespBody) > 100 {
-				return string(finalRespBody[:100])
-			}
-			return string(finalRespBody)
-		}(),
-		"first_char": func() string {
-			if len(finalRespBody) > 0 {
-				return fmt.Sprintf("'%c' (0x%02x)", finalRespBody[0], finalRespBody[0])
-			}
-			return "empty"
-		}(),
-	})
-
 	// Unmarshal final response
 	var finalResp CompletionResponse
 	err = json.Unmarshal(finalRespBody, &finalResp)
@@ -1534,11 +1528,26 @@ CRITICAL INSTRUCTIONS:
 		return "", fmt.Errorf("failed to unmarshal final response: %w", err)
 	}
 
+	if err := finalResp.refusalError(); err != nil {
+		return "", err
+	}
+
 	// Extract text content from final response
 	if finalResp.Content == nil {
 		return "", fmt.Errorf("no content in final response")
 	}
 
+	if useStructuredTool {
+		response, ok := structuredOutputFromResponse(&finalResp)
+		if !ok {
+			return "", fmt.Errorf("no %s tool call in final structured output response", structuredOutputToolName)
+		}
+		c.logger.Info(ctx, "Successfully received final structured response", map[string]interface{}{
+			"response_length": len(response),
+		})
+		return response, nil
+	}
+
 	var finalTextContent []string
 	for _, contentBlock := range finalResp.Content {
 		if contentBlock.Type == "text" {
@@ -1571,15 +1580,46 @@ CRITICAL INSTRUCTIONS:
 
 	c.logger.Info(ctx, "Successfully received final response without tools", map[string]interface{}{
 		"response_length": len(response),
-		"response_preview": func() string {
-
-			return response
-		}(),
 	})
 
 	return response, nil
 }
 
+// finalPromptWithSchema enhances the prompt of the final call without tools
+// with the schema of a structured output, and an example of it
+func finalPromptWithSchema(finalPrompt string, format *interfaces.ResponseFormat) string {
+	if format == nil {
+		return finalPrompt
+	}
+
+	// Convert the schema to a string representation for the prompt
+	schemaJSON, err := json.MarshalIndent(format.Schema, "", "  ")
+	if err != nil {
+		return finalPrompt
+	}
+
+	// Create an example JSON structure based on the schema
+	exampleJSON := createExampleFromSchema(format.Schema)
+	exampleStr, _ := json.MarshalIndent(exampleJSON, "", "  ")
+
+	return fmt.Sprintf(`%s
+
+You must respond with a valid JSON object that exactly follows this schema:
+%s
+
+Here is an example of the expected JSON structure:
+%s
+
+CRITICAL INSTRUCTIONS:
+- Output ONLY valid JSON, no additional text before or after
+- Follow the EXACT structure shown in the schema and example
+- Use the field names exactly as specified
+- Ensure all required fields are present
+- Pay special attention to array fields - they must be arrays of objects, not simple objects
+- If a field is defined as an array in the schema, it MUST be an array in your response
+- The JSON must be directly parsable and match the schema precisely`, finalPrompt, string(schemaJSON), string(exampleStr))
+}
+
 // GenerateWithToolsDetailed generates text with tools and returns detailed response information including token usage
 func (c *AnthropicClient) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
 	// For now, call the existing method and construct a detailed response
@@ -1714,11 +1754,23 @@ func (c *AnthropicClient) createStreamingHTTPRequestWithCache(ctx context.Contex
 	}
 }
 
+// apiError maps a failed Anthropic API response to the typed errors of the
+// interfaces package, e.g. *interfaces.ProviderUnavailable for 529 "overloaded"
+// responses. err is returned unchanged for other failures.
+func apiError(resp *http.Response, body []byte, err error) error {
+	return llm.ClassifyHTTPError("anthropic", resp.StatusCode, resp.Header, string(body), err)
+}
+
 // Name implements interfaces.LLM.Name
 func (c *AnthropicClient) Name() string {
 	return "anthropic"
 }
 
+// WarmConnections implements interfaces.ConnectionWarmer.WarmConnections
+func (c *AnthropicClient) WarmConnections(ctx context.Context) error {
+	return llm.WarmConnection(ctx, c.HTTPClient, c.BaseURL)
+}
+
 // SupportsStreaming implements interfaces.LLM.SupportsStreaming
 func (c *AnthropicClient) SupportsStreaming() bool {
 	return true
@@ -1927,249 +1979,35 @@ func isValidJSONStart(s string) bool {
 	return strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")
 }
 
-// toolExecResult holds the result of a parallel tool execution
-type toolExecResult struct {
-	index    int
-	result   ToolResult
-	toolName string
-	toolJSON string
-	err      error
-}
-
-// executeToolsParallel executes multiple tool calls concurrently for better performance
-func (c *AnthropicClient) executeToolsParallel(
-	ctx context.Context,
-	toolCalls []ToolUse,
-	tools []interfaces.Tool,
-	params *interfaces.GenerateOptions,
-	toolCallHistory map[string]int,
-	iteration int,
-) []ToolResult {
+// decodeToolCalls decodes the tool_use blocks of a response into the tool
+// calls of the shared tool-calling loop
+func (c *AnthropicClient) decodeToolCalls(ctx context.Context, toolCalls []ToolUse) []interfaces.ToolCall {
 	if len(toolCalls) == 0 {
 		return nil
 	}
 
-	c.logger.Info(ctx, "Executing tools in parallel", map[string]interface{}{
-		"count":     len(toolCalls),
-		"iteration": iteration + 1,
-	})
-
-	// Channel to collect results
-	resultChan := make(chan toolExecResult, len(toolCalls))
-	var wg sync.WaitGroup
-
-	// Mutex for toolCallHistory (shared state)
-	var historyMu sync.Mutex
-
+	calls := make([]interfaces.ToolCall, len(toolCalls))
 	for i, toolCall := range toolCalls {
-		// Get tool name
-		toolName := ""
-		if toolCall.Name != "" {
-			toolName = toolCall.Name
-		} else if toolCall.RecipientName != "" {
-			toolName = toolCall.RecipientName
-		} else {
-			c.logger.Error(ctx, "Tool call missing both Name and RecipientName", map[string]interface{}{"iteration": iteration + 1})
-			resultChan <- toolExecResult{
-				index:  i,
-				result: ToolResult{Type: "tool_result", Content: "Error: tool call missing name", ToolName: "unknown"},
-			}
-			continue
+		name := toolCall.Name
+		if name == "" {
+			name = toolCall.RecipientName
 		}
 
-		// Find the requested tool
-		var selectedTool interfaces.Tool
-		for _, tool := range tools {
-			if tool.Name() == toolName {
-				selectedTool = tool
-				break
-			}
-		}
-
-		if selectedTool == nil {
-			c.logger.Error(ctx, "Tool not found", map[string]interface{}{
-				"toolName":  toolName,
-				"iteration": iteration + 1,
-			})
-			errorMessage := fmt.Sprintf("Error: tool not found: %s", toolName)
-
-			// Store failed tool call in memory if provided
-			if params.Memory != nil {
-				_ = params.Memory.AddMessage(ctx, interfaces.Message{
-					Role:    "assistant",
-					Content: "",
-					ToolCalls: []interfaces.ToolCall{{
-						ID:        toolCall.ID,
-						Name:      toolName,
-						Arguments: "{}",
-					}},
-				})
-				_ = params.Memory.AddMessage(ctx, interfaces.Message{
-					Role:       "tool",
-					Content:    errorMessage,
-					ToolCallID: toolCall.ID,
-					Metadata: map[string]interface{}{
-						"tool_name": toolName,
-					},
-				})
-			}
-
-			resultChan <- toolExecResult{
-				index:    i,
-				result:   ToolResult{Type: "tool_result", Content: errorMessage, ToolName: toolName},
-				toolName: toolName,
-			}
-			continue
-		}
-
-		// Get parameters
-		var parameters map[string]interface{}
-		if len(toolCall.Input) > 0 {
-			parameters = toolCall.Input
-		} else if len(toolCall.Parameters) > 0 {
+		parameters := toolCall.Input
+		if len(parameters) == 0 {
 			parameters = toolCall.Parameters
 		}
-
-		toolCallJSON, err := json.Marshal(parameters)
+		arguments, err := json.Marshal(parameters)
 		if err != nil {
 			c.logger.Error(ctx, "Error marshalling parameters", map[string]interface{}{
-				"error":     err.Error(),
-				"iteration": iteration + 1,
+				"error": err.Error(),
 			})
-			resultChan <- toolExecResult{
-				index:    i,
-				result:   ToolResult{Type: "tool_result", Content: fmt.Sprintf("Error: %v", err), ToolName: toolName},
-				toolName: toolName,
-				err:      err,
-			}
-			continue
+			arguments = []byte("{}")
 		}
 
-		// Execute tool in goroutine
-		wg.Add(1)
-		go func(idx int, tc ToolUse, tool interfaces.Tool, tName string, tJSON []byte) {
-			defer wg.Done()
-
-			c.logger.Debug(ctx, "Tool parameters", map[string]interface{}{
-				"toolName":   tName,
-				"parameters": string(tJSON),
-				"iteration":  iteration + 1,
-			})
-
-			c.logger.Info(ctx, "Executing tool (parallel)", map[string]interface{}{
-				"toolName":  tName,
-				"iteration": iteration + 1,
-			})
-
-			toolResult, execErr := tool.Execute(ctx, string(tJSON))
-
-			// Check for repetitive calls (thread-safe)
-			historyMu.Lock()
-			cacheKey := tName + ":" + string(tJSON)
-			toolCallHistory[cacheKey]++
-			callCount := toolCallHistory[cacheKey]
-			historyMu.Unlock()
-
-			if callCount > 2 {
-				warning := fmt.Sprintf("\n\n[WARNING: This is call #%d to %s with identical parameters. You may be in a loop.]",
-					callCount, tName)
-				if execErr == nil {
-					toolResult += warning
-				}
-				c.logger.Warn(ctx, "Repetitive tool call detected", map[string]interface{}{
-					"toolName":  tName,
-					"callCount": callCount,
-					"iteration": iteration + 1,
-				})
-			}
-
-			// Store in memory
-			if params.Memory != nil {
-				_ = params.Memory.AddMessage(ctx, interfaces.Message{
-					Role:    "assistant",
-					Content: "",
-					ToolCalls: []interfaces.ToolCall{{
-						ID:        tc.ID,
-						Name:      tName,
-						Arguments: string(tJSON),
-					}},
-				})
-				if execErr != nil {
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:       "tool",
-						Content:    fmt.Sprintf("Error: %v", execErr),
-						ToolCallID: tc.ID,
-						Metadata:   map[string]interface{}{"tool_name": tName},
-					})
-				} else {
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:       "tool",
-						Content:    toolResult,
-						ToolCallID: tc.ID,
-						Metadata:   map[string]interface{}{"tool_name": tName},
-					})
-				}
-			}
-
-			if execErr != nil {
-				c.logger.Error(ctx, "Error executing tool", map[string]interface{}{
-					"toolName":  tName,
-					"error":     execErr.Error(),
-					"iteration": iteration + 1,
-				})
-				resultChan <- toolExecResult{
-					index:    idx,
-					result:   ToolResult{Type: "tool_result", Content: fmt.Sprintf("Error: %v", execErr), ToolName: tName},
-					toolName: tName,
-					toolJSON: string(tJSON),
-					err:      execErr,
-				}
-				return
-			}
-
-			resultChan <- toolExecResult{
-				index:    idx,
-				result:   ToolResult{Type: "tool_result", Content: toolResult, ToolName: tName},
-				toolName: tName,
-				toolJSON: string(tJSON),
-			}
-		}(i, toolCall, selectedTool, toolName, toolCallJSON)
-	}
-
-	// Wait for all goroutines to complete
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
-
-	// Collect results and preserve order
-	results := make([]toolExecResult, 0, len(toolCalls))
-	for result := range resultChan {
-		results = append(results, result)
-	}
-
-	// Sort by original index to preserve order
-	toolResults := make([]ToolResult, len(results))
-	for _, r := range results {
-		if r.index < len(toolResults) {
-			toolResults[r.index] = r.result
-		}
-	}
-
-	// Filter out empty results (from failed tool name resolution)
-	filteredResults := make([]ToolResult, 0, len(toolResults))
-	for _, r := range toolResults {
-		if r.ToolName != "" {
-			filteredResults = append(filteredResults, r)
-		}
+		calls[i] = interfaces.ToolCall{ID: toolCall.ID, Name: name, Arguments: string(arguments)}
 	}
-
-	c.logger.Info(ctx, "Parallel tool execution completed", map[string]interface{}{
-		"count":     len(filteredResults),
-		"iteration": iteration + 1,
-	})
-
-	return filteredResults
+	return calls
 }
 
 // buildMessagesWithMemory builds Anthropic messages from memory and current prompt
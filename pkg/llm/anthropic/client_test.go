@@ -129,3 +129,25 @@ func TestEmptyContentHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxTokensFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *interfaces.LLMConfig
+		expected int
+	}{
+		{name: "no config", config: nil, expected: defaultMaxTokens},
+		{name: "default", config: &interfaces.LLMConfig{}, expected: defaultMaxTokens},
+		{name: "max output tokens", config: &interfaces.LLMConfig{MaxOutputTokens: 500}, expected: 500},
+		{name: "reasoning", config: &interfaces.LLMConfig{EnableReasoning: true, ReasoningBudget: 2000}, expected: 6000},
+		{name: "reasoning with max output tokens", config: &interfaces.LLMConfig{EnableReasoning: true, ReasoningBudget: 2000, MaxOutputTokens: 500}, expected: 2500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maxTokensFor(tt.config); got != tt.expected {
+				t.Errorf("Expected max_tokens %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
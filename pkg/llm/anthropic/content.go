@@ -0,0 +1,96 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// InputBlock represents a content block of a message sent to Anthropic API
+type InputBlock struct {
+	Type         string         `json:"type"` // "text", "image" or "document"
+	Text         string         `json:"text,omitempty"`
+	Source       *ContentSource `json:"source,omitempty"`
+	Title        string         `json:"title,omitempty"` // Document title
+	CacheControl *CacheControl  `json:"cache_control,omitempty"`
+}
+
+// ContentSource represents the source of an image or document block
+type ContentSource struct {
+	Type      string `json:"type"` // "base64", "url" or "text"
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// MarshalJSON encodes the message with string content, or with an array of
+// content blocks when the message has blocks
+func (m Message) MarshalJSON() ([]byte, error) {
+	if len(m.Blocks) == 0 {
+		return json.Marshal(struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		}{m.Role, m.Content})
+	}
+	return json.Marshal(struct {
+		Role    string       `json:"role"`
+		Content []InputBlock `json:"content"`
+	}{m.Role, m.contentBlocks()})
+}
+
+// contentBlocks returns Content as a text block followed by Blocks
+func (m Message) contentBlocks() []InputBlock {
+	blocks := make([]InputBlock, 0, len(m.Blocks)+1)
+	if m.Content != "" {
+		blocks = append(blocks, InputBlock{Type: "text", Text: m.Content})
+	}
+	return append(blocks, m.Blocks...)
+}
+
+// userMessage builds a user message from text and multimodal content parts
+func userMessage(text string, parts []interfaces.ContentPart) Message {
+	return Message{
+		Role:    "user",
+		Content: text,
+		Blocks:  convertContentParts(parts),
+	}
+}
+
+// convertContentParts converts content parts to Anthropic content blocks.
+// Parts Anthropic cannot take as user content (audio, tool calls and
+// results) are skipped.
+func convertContentParts(parts []interfaces.ContentPart) []InputBlock {
+	var blocks []InputBlock
+	for _, part := range parts {
+		switch part.Type {
+		case interfaces.ContentPartText:
+			blocks = append(blocks, InputBlock{Type: "text", Text: part.Text})
+		case interfaces.ContentPartImage:
+			blocks = append(blocks, InputBlock{Type: "image", Source: contentSource(part)})
+		case interfaces.ContentPartFile:
+			blocks = append(blocks, InputBlock{Type: "document", Source: contentSource(part), Title: part.FileName})
+		}
+	}
+	return blocks
+}
+
+// contentSource returns the source of an image or file part. Plain text
+// documents are sent as text, other media as base64 data. Data URLs are
+// decoded and sent as base64 data, since URL sources only take http(s) URLs.
+func contentSource(part interfaces.ContentPart) *ContentSource {
+	if len(part.Data) == 0 {
+		mimeType, data, err := interfaces.ParseDataURL(part.URL)
+		if err != nil {
+			return &ContentSource{Type: "url", URL: part.URL}
+		}
+		part.Data = data
+		if part.MIMEType == "" {
+			part.MIMEType = mimeType
+		}
+	}
+	if part.Type == interfaces.ContentPartFile && strings.HasPrefix(part.MIMEType, "text/") {
+		return &ContentSource{Type: "text", MediaType: "text/plain", Data: string(part.Data)}
+	}
+	return &ContentSource{Type: "base64", MediaType: part.MIMEType, Data: part.Base64()}
+}
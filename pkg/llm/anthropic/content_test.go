@@ -0,0 +1,83 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestMessageMarshalJSON(t *testing.T) {
+	plain, err := json.Marshal(Message{Role: "user", Content: "Hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plain) != `{"role":"user","content":"Hello"}` {
+		t.Errorf("plain message = %s", plain)
+	}
+
+	data, err := json.Marshal(userMessage("Summarize", []interfaces.ContentPart{
+		interfaces.ImagePart([]byte("png"), "image/png"),
+		interfaces.ImageURLPart("https://example.com/cat.png"),
+		interfaces.FilePart([]byte("%PDF"), "application/pdf", "report.pdf"),
+		interfaces.FilePart([]byte("notes"), "text/plain", "notes.txt"),
+		interfaces.AudioPart([]byte("wav"), "audio/wav"),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var message struct {
+		Content []InputBlock `json:"content"`
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		t.Fatal(err)
+	}
+	blocks := message.Content
+	if len(blocks) != 5 {
+		t.Fatalf("expected 5 blocks (audio skipped), got %s", data)
+	}
+	if blocks[0].Type != "text" || blocks[0].Text != "Summarize" {
+		t.Errorf("text block = %+v", blocks[0])
+	}
+	if blocks[1].Type != "image" || *blocks[1].Source != (ContentSource{Type: "base64", MediaType: "image/png", Data: "cG5n"}) {
+		t.Errorf("image block = %+v", blocks[1])
+	}
+	if blocks[2].Source.Type != "url" || blocks[2].Source.URL != "https://example.com/cat.png" {
+		t.Errorf("image url block = %+v", blocks[2])
+	}
+	if blocks[3].Type != "document" || blocks[3].Title != "report.pdf" || blocks[3].Source.MediaType != "application/pdf" {
+		t.Errorf("document block = %+v", blocks[3])
+	}
+	if blocks[4].Source.Type != "text" || blocks[4].Source.Data != "notes" {
+		t.Errorf("text document block = %+v", blocks[4])
+	}
+}
+
+func TestContentSourceDataURL(t *testing.T) {
+	source := contentSource(interfaces.ImageURLPart("data:image/jpeg;base64,anBn"))
+	if *source != (ContentSource{Type: "base64", MediaType: "image/jpeg", Data: "anBn"}) {
+		t.Errorf("contentSource() = %+v", source)
+	}
+}
+
+func TestCacheRequestBuilder_BuildMessagesWithBlocks(t *testing.T) {
+	builder := newCacheRequestBuilder(&interfaces.CacheConfig{CacheConversation: true})
+	data, err := builder.BuildMessages([]Message{
+		userMessage("What is this?", []interfaces.ContentPart{interfaces.ImagePart([]byte("png"), "image/png")}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []struct {
+		Content []InputBlock `json:"content"`
+	}
+	if err := json.Unmarshal(data, &messages); err != nil {
+		t.Fatal(err)
+	}
+	blocks := messages[0].Content
+	if len(blocks) != 2 || blocks[0].CacheControl != nil || blocks[1].CacheControl == nil {
+		t.Errorf("expected cache control on the image block, got %s", data)
+	}
+}
@@ -44,10 +44,7 @@ func (b *messageHistoryBuilder) buildMessages(ctx context.Context, prompt string
 		}
 	} else {
 		// Only append current user message when memory is nil
-		messages = append(messages, Message{
-			Role:    "user",
-			Content: prompt,
-		})
+		messages = append(messages, userMessage(prompt, params.ContentParts))
 	}
 
 	return messages
@@ -57,10 +54,8 @@ func (b *messageHistoryBuilder) buildMessages(ctx context.Context, prompt string
 func (b *messageHistoryBuilder) convertMemoryMessage(msg interfaces.Message) *Message {
 	switch msg.Role {
 	case interfaces.MessageRoleUser:
-		return &Message{
-			Role:    "user",
-			Content: msg.Content,
-		}
+		message := userMessage(msg.Content, msg.ContentParts)
+		return &message
 
 	case interfaces.MessageRoleAssistant:
 		if len(msg.ToolCalls) > 0 {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 )
@@ -58,12 +59,7 @@ func (c *AnthropicClient) GenerateStream(
 	messages := builder.buildMessages(ctx, prompt, params)
 
 	// Create request with streaming enabled
-	// Note: MaxTokens must be greater than reasoning budget_tokens
-	maxTokens := 2048 // default
-	if params.LLMConfig != nil && params.LLMConfig.EnableReasoning && params.LLMConfig.ReasoningBudget > 0 {
-		// Ensure max_tokens > budget_tokens for reasoning
-		maxTokens = params.LLMConfig.ReasoningBudget + 4000 // Add buffer for actual response
-	}
+	maxTokens := maxTokensFor(params.LLMConfig)
 
 	req := CompletionRequest{
 		Model:       c.Model,
@@ -200,7 +196,7 @@ func (c *AnthropicClient) executeStreamingRequestWithMemory(
 				"error": err.Error(),
 				"model": c.Model,
 			})
-			return fmt.Errorf("failed to send request: %w", err)
+			return llm.ClassifyError("anthropic", fmt.Errorf("failed to send request: %w", err))
 		}
 		defer func() {
 			if closeErr := httpResp.Body.Close(); closeErr != nil {
@@ -227,9 +223,9 @@ func (c *AnthropicClient) executeStreamingRequestWithMemory(
 			})
 
 			if len(errorBody) > 0 {
-				return fmt.Errorf("error from Anthropic API: HTTP %d - %s", httpResp.StatusCode, string(errorBody))
+				return apiError(httpResp, errorBody, fmt.Errorf("error from Anthropic API: HTTP %d - %s", httpResp.StatusCode, string(errorBody)))
 			}
-			return fmt.Errorf("error from Anthropic API: HTTP %d", httpResp.StatusCode)
+			return apiError(httpResp, nil, fmt.Errorf("error from Anthropic API: HTTP %d", httpResp.StatusCode))
 		}
 
 		// Verify content type
@@ -328,51 +324,6 @@ func (c *AnthropicClient) GenerateWithToolsStream(
 		ctx = multitenancy.WithOrgID(ctx, defaultOrgID)
 	}
 
-	// Convert tools to Anthropic format
-	anthropicTools := make([]Tool, len(tools))
-	for i, tool := range tools {
-		// Convert ParameterSpec to JSON Schema
-		properties := make(map[string]interface{})
-		required := []string{}
-
-		for name, param := range tool.Parameters() {
-			properties[name] = map[string]interface{}{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			if param.Default != nil {
-				properties[name].(map[string]interface{})["default"] = param.Default
-			}
-			if param.Required {
-				required = append(required, name)
-			}
-			if param.Items != nil {
-				properties[name].(map[string]interface{})["items"] = map[string]interface{}{
-					"type": param.Items.Type,
-				}
-				if param.Items.Enum != nil {
-					properties[name].(map[string]interface{})["items"].(map[string]interface{})["enum"] = param.Items.Enum
-				}
-			}
-			if param.Enum != nil {
-				properties[name].(map[string]interface{})["enum"] = param.Enum
-			}
-		}
-
-		// Create the input schema for this tool
-		inputSchema := map[string]interface{}{
-			"type":       "object",
-			"properties": properties,
-			"required":   required,
-		}
-
-		anthropicTools[i] = Tool{
-			Name:        tool.Name(),
-			Description: tool.Description(),
-			InputSchema: inputSchema,
-		}
-	}
-
 	// Get buffer size from stream config
 	bufferSize := 100 // default
 	if params.StreamConfig != nil {
@@ -396,7 +347,7 @@ func (c *AnthropicClient) GenerateWithToolsStream(
 		}()
 
 		// Execute streaming with tools with iterative loop
-		if err := c.executeStreamingWithTools(ctx, prompt, anthropicTools, tools, params, eventChan); err != nil {
+		if err := c.executeStreamingWithTools(ctx, prompt, tools, params, eventChan); err != nil {
 			select {
 			case eventChan <- interfaces.StreamEvent{
 				Type:      interfaces.StreamEventError,
@@ -412,374 +363,227 @@ func (c *AnthropicClient) GenerateWithToolsStream(
 	return eventChan, nil
 }
 
-// executeStreamingWithTools handles streaming requests with tools using iterative loop
+// executeStreamingWithTools runs the shared tool-calling loop with streaming
+// requests
 func (c *AnthropicClient) executeStreamingWithTools(
 	ctx context.Context,
 	prompt string,
-	anthropicTools []Tool,
-	originalTools []interfaces.Tool,
+	tools []interfaces.Tool,
 	params *interfaces.GenerateOptions,
 	eventChan chan<- interfaces.StreamEvent,
 ) error {
 	// Build messages using unified builder
 	builder := newMessageHistoryBuilder(c.logger)
-	messages := builder.buildMessages(ctx, prompt, params)
-
-	// Get maxIterations from params
-	maxIterations := 2 // Default to match non-streaming behavior
-	if params.MaxIterations > 0 {
-		maxIterations = params.MaxIterations
+	policy := params.ToolLoop()
+	loop := &streamToolLoop{
+		client:        c,
+		params:        params,
+		maxIterations: policy.MaxIterations,
+		events:        eventChan,
+		messages:      builder.buildMessages(ctx, prompt, params),
+		maxTokens:     maxTokensFor(params.LLMConfig),
+		// Filter content deltas for internal iterations - only stream thinking
+		// and tool events, unless intermediate messages are included (default
+		// is false for backward compatibility)
+		filterContentDeltas: params.StreamConfig == nil || !params.StreamConfig.IncludeIntermediateMessages,
+	}
+
+	// Execute tool calls with the shared tool executor. The agent stores
+	// streamed tool calls in memory itself.
+	executor := llm.NewToolExecutor(c.logger, llm.WithToolTimeout(params.ToolTimeout), llm.WithParallelToolCalls(params.ParallelToolCalls), llm.WithToolsFunc(params.ToolsFunc))
+	result, err := executor.Run(ctx, loop, tools, policy)
+	if err != nil {
+		return err
 	}
 
-	// Create base request configuration
-	maxTokens := 2048 // default
-	if params.LLMConfig != nil && params.LLMConfig.EnableReasoning && params.LLMConfig.ReasoningBudget > 0 {
-		// Ensure max_tokens > budget_tokens for reasoning
-		maxTokens = params.LLMConfig.ReasoningBudget + 4000 // Add buffer for actual response
+	// Stream the result of a stop tool that ended the loop
+	if result.Stopped {
+		return loop.send(ctx, interfaces.StreamEvent{
+			Type:      interfaces.StreamEventContentDelta,
+			Content:   result.Content,
+			Timestamp: time.Now(),
+		})
 	}
+	return nil
+}
 
-	gotCompleteResponse := false
-	finalIterationCount := 0 // Track total iterations for logging after loop
-
-	// Iterative tool calling loop
-	for iteration := 0; iteration < maxIterations; iteration++ {
-		finalIterationCount = iteration + 1 // Update the count
-		// Create request for this iteration
-		req := CompletionRequest{
-			Model:       c.Model,
-			Messages:    messages,
-			MaxTokens:   maxTokens,
-			Temperature: params.LLMConfig.Temperature,
-			TopP:        params.LLMConfig.TopP,
-			Tools:       anthropicTools,
-			// Auto use tools when needed
-			ToolChoice: map[string]string{
-				"type": "auto",
-			},
-			Stream: true, // Enable streaming
-		}
-
-		// Add system message if available
-		if params.SystemMessage != "" {
-			req.System = params.SystemMessage
-		}
-
-		// Add reasoning (thinking) support if enabled and model supports it
-		if params.LLMConfig != nil && params.LLMConfig.EnableReasoning {
-			if SupportsThinking(c.Model) {
-				req.Thinking = &ReasoningSpec{
-					Type: "enabled",
-				}
-				if params.LLMConfig.ReasoningBudget > 0 {
-					req.Thinking.BudgetTokens = params.LLMConfig.ReasoningBudget
-				}
-				// Anthropic requires temperature = 1.0 when thinking is enabled
-				req.Temperature = 1.0
-				c.logger.Debug(ctx, "Enabled reasoning (thinking) tokens for tools", map[string]interface{}{
-					"model":         c.Model,
-					"budget_tokens": params.LLMConfig.ReasoningBudget,
-					"max_tokens":    maxTokens,
-					"temperature":   req.Temperature,
-					"iteration":     iteration + 1,
-					"maxIterations": maxIterations,
-				})
-			}
-		}
+// streamToolLoop streams the requests of GenerateWithToolsStream from the
+// Messages API, for the shared tool-calling loop
+type streamToolLoop struct {
+	client        *AnthropicClient
+	params        *interfaces.GenerateOptions
+	maxIterations int
+	events        chan<- interfaces.StreamEvent
+	messages      []Message
+	maxTokens     int
+
+	// filterContentDeltas holds back the content of each response until it
+	// is known to be the final one
+	filterContentDeltas bool
+
+	// text is the text content of the last response
+	text string
+}
 
-		// Execute streaming request and collect tool calls
-		c.logger.Debug(ctx, "[LLM RESPONSE DEBUG] Calling LLM for iteration", map[string]interface{}{
-			"iteration":     iteration + 1,
-			"maxIterations": maxIterations,
-			"hasTools":      len(anthropicTools) > 0,
-		})
-		// First pass: Filter content deltas for internal iterations - only stream thinking and tool events
-		// Check if we should include intermediate messages (default is false for backward compatibility)
-		filterContentDeltas := true
-		if params.StreamConfig != nil && params.StreamConfig.IncludeIntermediateMessages {
-			filterContentDeltas = false
-		}
-		toolCalls, hasContent, capturedContentEvents, err := c.executeStreamingRequestWithToolCapture(ctx, req, eventChan, filterContentDeltas, params)
-		if err != nil {
-			c.logger.Error(ctx, "[LLM RESPONSE DEBUG] LLM call failed", map[string]interface{}{
-				"iteration": iteration + 1,
-				"error":     err.Error(),
-			})
-			return err
-		}
-		c.logger.Info(ctx, "[LLM RESPONSE DEBUG] LLM response received", map[string]interface{}{
-			"iteration":      iteration + 1,
-			"toolCallsCount": len(toolCalls),
-			"hasContent":     hasContent,
-			"gotToolCalls":   len(toolCalls) > 0,
-		})
+// Send implements llm.ToolLoopProvider.Send
+func (l *streamToolLoop) Send(ctx context.Context, iteration int, tools []interfaces.Tool) (llm.ToolLoopTurn, error) {
+	c := l.client
+	params := l.params
 
-		// If no tool calls, check if we have content
-		if len(toolCalls) == 0 {
-			// If we have content, we're done with iterations - the model provided a final response
-			if hasContent {
-				c.logger.Info(ctx, "[LLM RESPONSE DEBUG] Got final content response without tool calls", map[string]interface{}{
-					"iteration":      iteration + 1,
-					"hasContent":     hasContent,
-					"responseType":   "final_answer",
-					"toolCallsCount": 0,
-					"capturedEvents": len(capturedContentEvents),
-				})
+	// Create request for this iteration
+	req := CompletionRequest{
+		Model:       c.Model,
+		Messages:    l.messages,
+		MaxTokens:   l.maxTokens,
+		Temperature: params.LLMConfig.Temperature,
+		TopP:        params.LLMConfig.TopP,
+		Tools:       convertTools(tools),
+		// Auto use tools when needed
+		ToolChoice: map[string]string{
+			"type": "auto",
+		},
+		Stream: true, // Enable streaming
+	}
 
-				// Only replay if content was filtered (not already forwarded)
-				if filterContentDeltas {
-					// Replay the captured content events to stream the final response
-					c.logger.Debug(ctx, "[LLM RESPONSE DEBUG] Replaying captured content events", map[string]interface{}{
-						"iteration":   iteration + 1,
-						"eventsCount": len(capturedContentEvents),
-					})
-
-					for _, contentEvent := range capturedContentEvents {
-						select {
-						case eventChan <- contentEvent:
-						case <-ctx.Done():
-							return ctx.Err()
-						}
-					}
-				}
+	// Add system message if available
+	if params.SystemMessage != "" {
+		req.System = params.SystemMessage
+	}
 
-				// Send completion event
-				select {
-				case eventChan <- interfaces.StreamEvent{
-					Type:      interfaces.StreamEventContentComplete,
-					Timestamp: time.Now(),
-					Metadata: map[string]interface{}{
-						"iteration": iteration + 1,
-					},
-				}:
-				case <-ctx.Done():
-					return ctx.Err()
-				}
-				// Mark that we got a complete response
-				gotCompleteResponse = true
-				// Break out of iteration loop (don't return - let final synthesis check happen)
-				break
-			}
-			// If no tool calls and no content, log warning and continue to next iteration
-			// This might happen if the model returns an empty response
-			c.logger.Warn(ctx, "[LLM RESPONSE DEBUG] No tool calls and no content in iteration", map[string]interface{}{
-				"iteration":     iteration + 1,
-				"maxIterations": maxIterations,
-				"responseType":  "empty_response",
-			})
-			// Continue to next iteration or break if this was the last one
-			if iteration >= maxIterations-1 {
-				// We've reached max iterations, break out to make final call
-				break
-			}
-			continue
-		}
+	// Add reasoning (thinking) support if enabled and model supports it
+	l.enableThinking(ctx, &req)
 
-		// Execute tools and add results to conversation
-		c.logger.Info(ctx, "[LLM RESPONSE DEBUG] Processing tool calls from LLM response", map[string]interface{}{
-			"count":        len(toolCalls),
-			"iteration":    iteration + 1,
-			"responseType": "tool_calls",
+	// Execute streaming request and collect tool calls
+	c.logger.Debug(ctx, "[LLM RESPONSE DEBUG] Calling LLM for iteration", map[string]interface{}{
+		"iteration":     iteration + 1,
+		"maxIterations": l.maxIterations,
+		"hasTools":      len(req.Tools) > 0,
+	})
+	toolCalls, hasContent, capturedContentEvents, err := c.executeStreamingRequestWithToolCapture(ctx, req, l.events, l.filterContentDeltas, params)
+	if err != nil {
+		c.logger.Error(ctx, "[LLM RESPONSE DEBUG] LLM call failed", map[string]interface{}{
+			"iteration": iteration + 1,
+			"error":     err.Error(),
 		})
+		return llm.ToolLoopTurn{}, err
+	}
+	c.logger.Info(ctx, "[LLM RESPONSE DEBUG] LLM response received", map[string]interface{}{
+		"iteration":      iteration + 1,
+		"toolCallsCount": len(toolCalls),
+		"hasContent":     hasContent,
+	})
 
-		// Add assistant message to conversation history (matching non-streaming behavior)
-		// Build assistant content from captured events
-		var assistantContent strings.Builder
-		for _, event := range capturedContentEvents {
-			if event.Type == interfaces.StreamEventContentDelta {
-				assistantContent.WriteString(event.Content)
-			}
+	// Build the text content from the captured events
+	var text strings.Builder
+	for _, event := range capturedContentEvents {
+		if event.Type == interfaces.StreamEventContentDelta {
+			text.WriteString(event.Content)
 		}
+	}
+	l.text = text.String()
+	turn := llm.ToolLoopTurn{Content: l.text, Calls: toolCalls}
 
-		// Add assistant message only if there's text content (matching client.go:1196-1200)
-		if strings.TrimSpace(assistantContent.String()) != "" {
-			messages = append(messages, Message{
-				Role:    "assistant",
-				Content: assistantContent.String(),
-			})
+	// No tool calls and content: the model provided a final response
+	if len(toolCalls) == 0 && hasContent {
+		// Only replay if content was filtered (not already forwarded)
+		if l.filterContentDeltas {
+			for _, contentEvent := range capturedContentEvents {
+				if err := l.send(ctx, contentEvent); err != nil {
+					return llm.ToolLoopTurn{}, err
+				}
+			}
 		}
 
-		// Send a line break before tool execution for clarity
-		select {
-		case eventChan <- interfaces.StreamEvent{
-			Type:      interfaces.StreamEventContentDelta,
-			Content:   "\n", // Single line break before tools
+		// Send completion event
+		if err := l.send(ctx, interfaces.StreamEvent{
+			Type:      interfaces.StreamEventContentComplete,
 			Timestamp: time.Now(),
 			Metadata: map[string]interface{}{
-				"before_tools": true,
-				"iteration":    iteration + 1,
-			},
-		}:
-		case <-ctx.Done():
-			return ctx.Err()
-		}
-
-		// Execute each tool and add results
-		for _, toolCall := range toolCalls {
-			// Find the requested tool
-			var selectedTool interfaces.Tool
-			for _, tool := range originalTools {
-				if tool.Name() == toolCall.Name {
-					selectedTool = tool
-					break
-				}
-			}
-
-			if selectedTool == nil {
-				c.logger.Error(ctx, "Tool not found in streaming", map[string]interface{}{
-					"toolName": toolCall.Name,
-				})
-
-				// Add tool not found error as tool result instead of returning
-				errorMessage := fmt.Sprintf("Error: tool not found: %s", toolCall.Name)
-
-				// Add tool result message
-				messages = append(messages, Message{
-					Role:    "user", // Tool results come as user messages to Anthropic
-					Content: fmt.Sprintf("Tool %s result: %s", toolCall.Name, errorMessage),
-				})
-
-				// Send tool result event with error
-				select {
-				case eventChan <- interfaces.StreamEvent{
-					Type: interfaces.StreamEventToolResult,
-					ToolCall: &interfaces.ToolCall{
-						ID:        toolCall.ID,
-						Name:      toolCall.Name,
-						Arguments: toolCall.Arguments,
-					},
-					Content:   errorMessage,
-					Timestamp: time.Now(),
-				}:
-				case <-ctx.Done():
-					return ctx.Err()
-				}
-
-				continue // Continue processing other tool calls
-			}
-
-			// Execute tool
-			c.logger.Info(ctx, "[TOOL EXECUTION DEBUG] Executing tool in streaming", map[string]interface{}{
-				"toolName":  toolCall.Name,
-				"arguments": toolCall.Arguments,
 				"iteration": iteration + 1,
-			})
-
-			toolResult, err := selectedTool.Execute(ctx, toolCall.Arguments)
-			if err != nil {
-				toolResult = fmt.Sprintf("Error: %v", err)
-			}
-
-			// Add tool result message
-			messages = append(messages, Message{
-				Role:    "user", // Tool results come as user messages to Anthropic
-				Content: fmt.Sprintf("Tool %s result: %s", toolCall.Name, toolResult),
-			})
-
-			// Send tool result event
-			select {
-			case eventChan <- interfaces.StreamEvent{
-				Type: interfaces.StreamEventToolResult,
-				ToolCall: &interfaces.ToolCall{
-					ID:        toolCall.ID,
-					Name:      toolCall.Name,
-					Arguments: toolCall.Arguments,
-				},
-				Content:   toolResult, // Tool result goes in Content field
-				Timestamp: time.Now(),
-			}:
-			case <-ctx.Done():
-				return ctx.Err()
-			}
-		}
-
-		// Send a line break between iterations for better readability
-		if iteration < maxIterations-1 { // Don't add break after last iteration
-			select {
-			case eventChan <- interfaces.StreamEvent{
-				Type:      interfaces.StreamEventContentDelta,
-				Content:   "\n\n", // Add double line break for visual separation
-				Timestamp: time.Now(),
-				Metadata: map[string]interface{}{
-					"iteration_boundary": true,
-					"iteration":          iteration + 1,
-				},
-			}:
-			case <-ctx.Done():
-				return ctx.Err()
-			}
+			},
+		}); err != nil {
+			return llm.ToolLoopTurn{}, err
 		}
-
-		// Continue to next iteration with updated conversation
 	}
+	return turn, nil
+}
 
-	if gotCompleteResponse {
-		c.logger.Debug(ctx, "[LLM RESPONSE DEBUG] Skipping final synthesis call - already got complete response", map[string]interface{}{
-			"maxIterations":        maxIterations,
-			"totalLLMCalls":        finalIterationCount,
-			"skippedSynthesisCall": true,
+// AddResults implements llm.ToolLoopProvider.AddResults
+func (l *streamToolLoop) AddResults(ctx context.Context, iteration int, results []llm.ToolResult) error {
+	// Add assistant message only if there's text content (matching the
+	// non-streaming loop)
+	if strings.TrimSpace(l.text) != "" {
+		l.messages = append(l.messages, Message{
+			Role:    "assistant",
+			Content: l.text,
 		})
-		return nil
 	}
 
-	// After all tool iterations, make a final call without tools to get the synthesized answer
-	// This ensures the LLM provides a final response after processing all tool results
-
-	// If DisableFinalSummary is enabled, skip the final synthesis call
-	if params.DisableFinalSummary {
-		c.logger.Info(ctx, "DisableFinalSummary enabled, skipping final synthesis call", map[string]interface{}{
-			"maxIterations": maxIterations,
-		})
-		return nil
+	// Send a line break before tool execution for clarity
+	if err := l.send(ctx, interfaces.StreamEvent{
+		Type:      interfaces.StreamEventContentDelta,
+		Content:   "\n", // Single line break before tools
+		Timestamp: time.Now(),
+		Metadata: map[string]interface{}{
+			"before_tools": true,
+			"iteration":    iteration + 1,
+		},
+	}); err != nil {
+		return err
 	}
 
-	c.logger.Info(ctx, "[LLM RESPONSE DEBUG] Making final synthesis call after tool iterations", map[string]interface{}{
-		"maxIterations":         maxIterations,
-		"totalPreviousLLMCalls": finalIterationCount,
-		"reason":                "no_complete_response_received",
-	})
+	for _, result := range results {
+		// Add tool result message
+		l.messages = append(l.messages, Message{
+			Role:    "user", // Tool results come as user messages to Anthropic
+			Content: fmt.Sprintf("Tool %s result: %s", result.Call.Name, result.Content),
+		})
 
-	// Build the final user message
-	finalUserMessage := "Please provide your final response based on the information available. Do not request any additional tools."
-
-	// If structured output is requested, enhance the final message with schema and examples
-	if params.ResponseFormat != nil {
-		// Convert the schema to a string representation for the prompt
-		schemaJSON, err := json.MarshalIndent(params.ResponseFormat.Schema, "", "  ")
-		if err == nil {
-			// Create an example JSON structure based on the schema
-			exampleJSON := createExampleFromSchema(params.ResponseFormat.Schema)
-			exampleStr, _ := json.MarshalIndent(exampleJSON, "", "  ")
-
-			// Enhance the final user message with schema information and example
-			finalUserMessage = fmt.Sprintf(`%s
-
-You must respond with a valid JSON object that exactly follows this schema:
-%s
-
-Here is an example of the expected JSON structure:
-%s
-
-CRITICAL INSTRUCTIONS:
-- Output ONLY valid JSON, no additional text before or after
-- Follow the EXACT structure shown in the schema and example
-- Use the field names exactly as specified
-- Ensure all required fields are present
-- Pay special attention to array fields - they must be arrays of objects, not simple objects
-- If a field is defined as an array in the schema, it MUST be an array in your response
-- The JSON must be directly parsable and match the schema precisely`, finalUserMessage, string(schemaJSON), string(exampleStr))
+		// Send tool result event
+		if err := l.send(ctx, interfaces.StreamEvent{
+			Type: interfaces.StreamEventToolResult,
+			ToolCall: &interfaces.ToolCall{
+				ID:        result.Call.ID,
+				Name:      result.Call.Name,
+				Arguments: result.Call.Arguments,
+			},
+			Content:   result.Content, // Tool result goes in Content field
+			Timestamp: time.Now(),
+		}); err != nil {
+			return err
 		}
 	}
 
+	// Send a line break between iterations for better readability
+	if iteration < l.maxIterations-1 { // Don't add break after last iteration
+		return l.send(ctx, interfaces.StreamEvent{
+			Type:      interfaces.StreamEventContentDelta,
+			Content:   "\n\n", // Add double line break for visual separation
+			Timestamp: time.Now(),
+			Metadata: map[string]interface{}{
+				"iteration_boundary": true,
+				"iteration":          iteration + 1,
+			},
+		})
+	}
+	return nil
+}
+
+// Conclude implements llm.ToolLoopProvider.Conclude
+func (l *streamToolLoop) Conclude(ctx context.Context, finalPrompt string) (string, error) {
+	c := l.client
+	params := l.params
+
 	// Add a message to inform the LLM this is the final call
-	finalMessages := append(messages, Message{
+	finalMessages := append(l.messages, Message{
 		Role:    "user",
-		Content: finalUserMessage,
+		Content: finalPromptWithSchema(finalPrompt, params.ResponseFormat),
 	})
 
 	finalReq := CompletionRequest{
 		Model:       c.Model,
 		Messages:    finalMessages,
-		MaxTokens:   maxTokens,
+		MaxTokens:   l.maxTokens,
 		Temperature: params.LLMConfig.Temperature,
 		TopP:        params.LLMConfig.TopP,
 		// No tools in final request - we want a final answer
@@ -799,41 +603,56 @@ CRITICAL INSTRUCTIONS:
 	}
 
 	// Add reasoning (thinking) support if enabled and model supports it
-	if params.LLMConfig != nil && params.LLMConfig.EnableReasoning {
-		if SupportsThinking(c.Model) {
-			finalReq.Thinking = &ReasoningSpec{
-				Type: "enabled",
-			}
-			if params.LLMConfig.ReasoningBudget > 0 {
-				finalReq.Thinking.BudgetTokens = params.LLMConfig.ReasoningBudget
-			}
-			// Anthropic requires temperature = 1.0 when thinking is enabled
-			finalReq.Temperature = 1.0
-			c.logger.Debug(ctx, "Getting final answer with reasoning after tools", map[string]interface{}{
-				"model":         c.Model,
-				"budget_tokens": params.LLMConfig.ReasoningBudget,
-				"max_tokens":    maxTokens,
-				"temperature":   finalReq.Temperature,
-			})
-		}
-	}
+	l.enableThinking(ctx, &finalReq)
 
 	// Execute final request to get synthesized answer with memory support
 	c.logger.Debug(ctx, "[LLM RESPONSE DEBUG] Executing final synthesis LLM call", map[string]interface{}{
-		"finalCallNumber": finalIterationCount + 1,
-		"messageCount":    len(finalMessages),
+		"messageCount": len(finalMessages),
 	})
-	err := c.executeStreamingRequestWithMemory(ctx, finalReq, eventChan, "", params)
-	if err != nil {
+	if err := c.executeStreamingRequestWithMemory(ctx, finalReq, l.events, "", params); err != nil {
 		c.logger.Error(ctx, "[LLM RESPONSE DEBUG] Final synthesis call failed", map[string]interface{}{
 			"error": err.Error(),
 		})
-	} else {
-		c.logger.Info(ctx, "[LLM RESPONSE DEBUG] Final synthesis call completed successfully", map[string]interface{}{
-			"totalLLMCalls": finalIterationCount + 1,
-		})
+		return "", err
+	}
+	c.logger.Info(ctx, "[LLM RESPONSE DEBUG] Final synthesis call completed successfully", nil)
+
+	// The final response was streamed to the events
+	return "", nil
+}
+
+// enableThinking adds reasoning (thinking) to a request when enabled and
+// supported by the model
+func (l *streamToolLoop) enableThinking(ctx context.Context, req *CompletionRequest) {
+	config := l.params.LLMConfig
+	if config == nil || !config.EnableReasoning || !SupportsThinking(l.client.Model) {
+		return
+	}
+
+	req.Thinking = &ReasoningSpec{
+		Type: "enabled",
+	}
+	if config.ReasoningBudget > 0 {
+		req.Thinking.BudgetTokens = config.ReasoningBudget
+	}
+	// Anthropic requires temperature = 1.0 when thinking is enabled
+	req.Temperature = 1.0
+	l.client.logger.Debug(ctx, "Enabled reasoning (thinking) tokens for tools", map[string]interface{}{
+		"model":         l.client.Model,
+		"budget_tokens": config.ReasoningBudget,
+		"max_tokens":    req.MaxTokens,
+		"temperature":   req.Temperature,
+	})
+}
+
+// send sends an event unless the context is done
+func (l *streamToolLoop) send(ctx context.Context, event interfaces.StreamEvent) error {
+	select {
+	case l.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return err
 }
 
 // createFilteredEventForwarder processes events and optionally captures content for later replay
@@ -0,0 +1,72 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// structuredOutputToolName is the synthetic tool Claude is forced to call
+// when structured output is requested. Its input is returned as the response.
+const structuredOutputToolName = "emit_result"
+
+// structuredOutputTool returns the synthetic tool whose input schema is the
+// requested response schema. It returns false when the format cannot be
+// enforced with a tool: the schema is not an object, or thinking is enabled,
+// which Anthropic does not allow together with a forced tool choice.
+func structuredOutputTool(format *interfaces.ResponseFormat, thinking bool) (Tool, bool) {
+	if format == nil || format.Type != interfaces.ResponseFormatJSON || format.Schema == nil || thinking {
+		return Tool{}, false
+	}
+	if schemaType, ok := format.Schema["type"]; ok && schemaType != "object" {
+		return Tool{}, false
+	}
+
+	description := "Return the final result. The input must be the complete result and match the schema exactly."
+	if format.Name != "" {
+		description = fmt.Sprintf("Return the final result as a %s. The input must be the complete result and match the schema exactly.", format.Name)
+	}
+
+	return Tool{
+		Name:        structuredOutputToolName,
+		Description: description,
+		InputSchema: format.Schema,
+	}, true
+}
+
+// structuredOutputToolChoice forces Claude to call the synthetic tool
+func structuredOutputToolChoice() map[string]string {
+	return map[string]string{
+		"type": "tool",
+		"name": structuredOutputToolName,
+	}
+}
+
+// structuredOutputFromResponse returns the JSON input of the synthetic tool
+// call in resp, or false when the model did not call it
+func structuredOutputFromResponse(resp *CompletionResponse) (string, bool) {
+	for _, block := range resp.Content {
+		if block.Type != "tool_use" {
+			continue
+		}
+
+		name, input := block.Name, block.Input
+		if block.ToolUse != nil {
+			name, input = block.ToolUse.Name, block.ToolUse.Input
+		}
+		if name != structuredOutputToolName {
+			continue
+		}
+
+		if input == nil {
+			input = map[string]interface{}{}
+		}
+		data, err := json.Marshal(input)
+		if err != nil {
+			return "", false
+		}
+		return string(data), true
+	}
+	return "", false
+}
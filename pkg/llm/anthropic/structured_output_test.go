@@ -0,0 +1,119 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+func TestGenerateStructuredOutputForcesTool(t *testing.T) {
+	format := interfaces.ResponseFormat{
+		Type: interfaces.ResponseFormatJSON,
+		Name: "Person",
+		Schema: interfaces.JSONSchema{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{"type": "string"},
+				"age":  map[string]interface{}{"type": "integer"},
+			},
+			"required": []string{"name", "age"},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		if len(req.Tools) != 1 || req.Tools[0].Name != structuredOutputToolName {
+			t.Errorf("Expected only the %s tool, got %+v", structuredOutputToolName, req.Tools)
+		} else if req.Tools[0].InputSchema["type"] != "object" {
+			t.Errorf("Expected the response schema as input schema, got %v", req.Tools[0].InputSchema)
+		}
+
+		choice, _ := req.ToolChoice.(map[string]interface{})
+		if choice["type"] != "tool" || choice["name"] != structuredOutputToolName {
+			t.Errorf("Expected tool choice forcing %s, got %v", structuredOutputToolName, req.ToolChoice)
+		}
+
+		if req.Messages[len(req.Messages)-1].Content != "Tell me about Ada" {
+			t.Errorf("Expected the prompt to be sent unchanged, got %q", req.Messages[len(req.Messages)-1].Content)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		response := map[string]interface{}{
+			"id":          "msg_123",
+			"type":        "message",
+			"role":        "assistant",
+			"model":       "claude-sonnet-4-20250514",
+			"stop_reason": "tool_use",
+			"content": []map[string]interface{}{
+				{
+					"type":  "tool_use",
+					"id":    "toolu_123",
+					"name":  structuredOutputToolName,
+					"input": map[string]interface{}{"name": "Ada Lovelace", "age": 36},
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		"test-key",
+		WithModel("claude-sonnet-4-20250514"),
+		WithBaseURL(server.URL),
+		WithLogger(logging.New()),
+	)
+
+	response, err := client.Generate(context.Background(), "Tell me about Ada",
+		interfaces.WithResponseFormat(format))
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	var person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	if err := json.Unmarshal([]byte(response), &person); err != nil {
+		t.Fatalf("Expected JSON response, got %q: %v", response, err)
+	}
+	if person.Name != "Ada Lovelace" || person.Age != 36 {
+		t.Errorf("Unexpected result: %+v", person)
+	}
+}
+
+func TestStructuredOutputTool(t *testing.T) {
+	object := &interfaces.ResponseFormat{
+		Type:   interfaces.ResponseFormatJSON,
+		Schema: interfaces.JSONSchema{"type": "object"},
+	}
+
+	if _, ok := structuredOutputTool(object, false); !ok {
+		t.Error("Expected an object schema to use the result tool")
+	}
+	if _, ok := structuredOutputTool(object, true); ok {
+		t.Error("Expected thinking to fall back to prompting")
+	}
+	if _, ok := structuredOutputTool(nil, false); ok {
+		t.Error("Expected no result tool without a response format")
+	}
+
+	array := &interfaces.ResponseFormat{
+		Type:   interfaces.ResponseFormatJSON,
+		Schema: interfaces.JSONSchema{"type": "array"},
+	}
+	if _, ok := structuredOutputTool(array, false); ok {
+		t.Error("Expected a non-object schema to fall back to prompting")
+	}
+}
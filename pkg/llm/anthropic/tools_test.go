@@ -0,0 +1,150 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// testTool is a tool without parameters returning a fixed result
+type testTool struct {
+	name string
+}
+
+func (t *testTool) Name() string        { return t.name }
+func (t *testTool) Description() string { return "Test tool " + t.name }
+
+func (t *testTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{}
+}
+
+func (t *testTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+func (t *testTool) Execute(ctx context.Context, args string) (string, error) {
+	return "result from " + t.name, nil
+}
+
+// newToolsFuncServer serves Anthropic messages requests, recording the tools
+// sent with each one. The first response calls test_tool_1 and the next ones
+// answer.
+func newToolsFuncServer(t *testing.T, requestTools *[][]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Stream bool `json:"stream"`
+			Tools  []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		var names []string
+		for _, tool := range reqBody.Tools {
+			names = append(names, tool.Name)
+		}
+		*requestTools = append(*requestTools, names)
+		callTool := len(*requestTools) == 1
+
+		if !reqBody.Stream {
+			content := map[string]interface{}{"type": "text", "text": "done"}
+			stopReason := "end_turn"
+			if callTool {
+				content = map[string]interface{}{"type": "tool_use", "id": "toolu_1", "name": "test_tool_1", "input": map[string]interface{}{}}
+				stopReason = "tool_use"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":          "msg_1",
+				"type":        "message",
+				"role":        "assistant",
+				"model":       "claude-3-sonnet-20240229",
+				"content":     []map[string]interface{}{content},
+				"stop_reason": stopReason,
+			})
+			return
+		}
+
+		block := map[string]interface{}{"type": "text", "text": ""}
+		delta := map[string]interface{}{"type": "text_delta", "text": "done"}
+		stopReason := "end_turn"
+		if callTool {
+			block = map[string]interface{}{"type": "tool_use", "id": "toolu_1", "name": "test_tool_1", "input": map[string]interface{}{}}
+			delta = map[string]interface{}{"type": "input_json_delta", "partial_json": "{}"}
+			stopReason = "tool_use"
+		}
+		events := []map[string]interface{}{
+			{"type": "message_start", "message": map[string]interface{}{"id": "msg_1", "role": "assistant", "model": "claude-3-sonnet-20240229"}},
+			{"type": "content_block_start", "index": 0, "content_block": block},
+			{"type": "content_block_delta", "index": 0, "delta": delta},
+			{"type": "content_block_stop", "index": 0},
+			{"type": "message_delta", "delta": map[string]interface{}{"stop_reason": stopReason}},
+			{"type": "message_stop"},
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			data, _ := json.Marshal(event)
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event["type"], data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerateWithToolsFunc(t *testing.T) {
+	var requestTools [][]string
+	server := newToolsFuncServer(t, &requestTools)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithLogger(logging.New()))
+
+	// The tools change after the first iteration
+	toolsFunc := func() []interfaces.Tool {
+		return []interfaces.Tool{&testTool{name: "test_tool_2"}}
+	}
+	resp, err := client.GenerateWithTools(context.Background(), "test prompt",
+		[]interfaces.Tool{&testTool{name: "test_tool_1"}},
+		interfaces.WithToolsFunc(toolsFunc))
+	if err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+	if resp != "done" {
+		t.Errorf("Expected response 'done', got '%s'", resp)
+	}
+
+	if fmt.Sprint(requestTools) != "[[test_tool_1] [test_tool_2]]" {
+		t.Errorf("Expected the second request to send the current tools, got %v", requestTools)
+	}
+}
+
+func TestGenerateWithToolsStream_ToolsFunc(t *testing.T) {
+	var requestTools [][]string
+	server := newToolsFuncServer(t, &requestTools)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithLogger(logging.New()))
+
+	// The tools change after the first iteration
+	toolsFunc := func() []interfaces.Tool {
+		return []interfaces.Tool{&testTool{name: "test_tool_2"}}
+	}
+	eventCh, err := client.GenerateWithToolsStream(context.Background(), "test prompt",
+		[]interfaces.Tool{&testTool{name: "test_tool_1"}},
+		interfaces.WithToolsFunc(toolsFunc))
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+	for event := range eventCh {
+		if event.Type == interfaces.StreamEventError {
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		}
+	}
+
+	if fmt.Sprint(requestTools) != "[[test_tool_1] [test_tool_2]]" {
+		t.Errorf("Expected the second request to send the current tools, got %v", requestTools)
+	}
+}
@@ -3,7 +3,9 @@ package azureopenai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -112,6 +114,20 @@ func (c *AzureOpenAIClient) getTemperatureForModel(requestedTemp float64) float6
 	return requestedTemp
 }
 
+// setGenerationLimits sets the sampling seed and maximum output tokens of
+// config on req, when config sets them
+func setGenerationLimits(req *openai.ChatCompletionNewParams, config *interfaces.LLMConfig) {
+	if config == nil {
+		return
+	}
+	if config.Seed != nil {
+		req.Seed = openai.Int(int64(*config.Seed))
+	}
+	if config.MaxOutputTokens > 0 {
+		req.MaxCompletionTokens = openai.Int(int64(config.MaxOutputTokens))
+	}
+}
+
 // WithLogger sets the logger for the Azure OpenAI client
 func WithLogger(logger logging.Logger) Option {
 	return func(c *AzureOpenAIClient) {
@@ -264,6 +280,7 @@ func (c *AzureOpenAIClient) generateInternal(ctx context.Context, prompt string,
 		Model:    openai.ChatModel(c.deployment),
 		Messages: messages,
 	}
+	setGenerationLimits(&req, params.LLMConfig)
 
 	if params.LLMConfig != nil {
 		req.Temperature = openai.Float(c.getTemperatureForModel(params.LLMConfig.Temperature))
@@ -336,7 +353,7 @@ func (c *AzureOpenAIClient) generateInternal(ctx context.Context, prompt string,
 				"model":      c.Model,
 				"deployment": c.deployment,
 			})
-			return fmt.Errorf("failed to generate text: %w", err)
+			return classifyError(fmt.Errorf("failed to generate text: %w", err))
 		}
 		return nil
 	}
@@ -352,11 +369,19 @@ func (c *AzureOpenAIClient) generateInternal(ctx context.Context, prompt string,
 	}
 
 	if err != nil {
+		if filterErr := promptFilterError(err); filterErr != nil {
+			return nil, filterErr
+		}
 		return nil, err
 	}
 
 	// Return response
 	if len(resp.Choices) > 0 {
+		choice := resp.Choices[0]
+		if err := llm.CheckChatCompletionFilter("azure-openai", string(choice.FinishReason), choice.Message.Refusal, choice.RawJSON()); err != nil {
+			return nil, err
+		}
+
 		c.logger.Debug(ctx, "Successfully received response from Azure OpenAI", map[string]interface{}{
 			"model":      c.Model,
 			"deployment": c.deployment,
@@ -466,7 +491,7 @@ func (c *AzureOpenAIClient) Chat(ctx context.Context, messages []llm.Message, pa
 				"model":      c.Model,
 				"deployment": c.deployment,
 			})
-			return fmt.Errorf("failed to create chat completion: %w", err)
+			return classifyError(fmt.Errorf("failed to create chat completion: %w", err))
 		}
 		return nil
 	}
@@ -598,6 +623,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 		FrequencyPenalty: openai.Float(params.LLMConfig.FrequencyPenalty),
 		PresencePenalty:  openai.Float(params.LLMConfig.PresencePenalty),
 	}
+	setGenerationLimits(&req, params.LLMConfig)
 
 	// Reasoning models don't support top_p parameter
 	if !isReasoningModel(c.Model) {
@@ -674,12 +700,18 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 				"error":      err.Error(),
 				"deployment": c.deployment,
 			})
-			return "", fmt.Errorf("failed to create chat completion: %w", err)
+			if filterErr := promptFilterError(err); filterErr != nil {
+				return "", filterErr
+			}
+			return "", classifyError(fmt.Errorf("failed to create chat completion: %w", err))
 		}
 
 		if len(resp.Choices) == 0 {
 			return "", fmt.Errorf("no completions returned")
 		}
+		if err := llm.CheckChatCompletionFilter("azure-openai", string(resp.Choices[0].FinishReason), resp.Choices[0].Message.Refusal, resp.Choices[0].RawJSON()); err != nil {
+			return "", err
+		}
 
 		// Capture the last content from the response
 		lastContent = strings.TrimSpace(resp.Choices[0].Message.Content)
@@ -759,7 +791,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 						}
 
 						if tool == nil {
-							err := fmt.Errorf("tool not found: %s", toolName)
+							err := &interfaces.ToolExecutionError{Tool: toolName, Err: fmt.Errorf("tool not found: %s", toolName)}
 							c.logger.Error(ctx, "Tool not found in parallel execution", map[string]interface{}{"toolName": toolName})
 							resultCh <- toolResult{index: index, result: "", err: err}
 							return
@@ -833,6 +865,9 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 							}
 						}
 
+						if err != nil {
+							err = &interfaces.ToolExecutionError{Tool: toolName, Err: err}
+						}
 						resultCh <- toolResult{index: index, result: result, err: err}
 					}(i, toolUse)
 				}
@@ -848,7 +883,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 				for result := range resultCh {
 					if result.err != nil {
 						c.logger.Error(ctx, "Error executing tool", map[string]interface{}{"error": result.err.Error()})
-						return "", fmt.Errorf("error executing tool: %s", result.err.Error())
+						return "", fmt.Errorf("error executing tool: %w", result.err)
 					}
 					toolsResults[result.index] = result.result
 				}
@@ -1051,6 +1086,7 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 		FrequencyPenalty: openai.Float(params.LLMConfig.FrequencyPenalty),
 		PresencePenalty:  openai.Float(params.LLMConfig.PresencePenalty),
 	}
+	setGenerationLimits(&finalReq, params.LLMConfig)
 
 	// Reasoning models don't support top_p parameter
 	if !isReasoningModel(c.Model) {
@@ -1087,23 +1123,69 @@ func (c *AzureOpenAIClient) GenerateWithTools(ctx context.Context, prompt string
 	finalResp, err := c.ChatService.Completions.New(ctx, finalReq)
 	if err != nil {
 		c.logger.Error(ctx, "Error in final call without tools", map[string]interface{}{"error": err.Error()})
-		return "", fmt.Errorf("failed to create final chat completion: %w", err)
+		return "", classifyError(fmt.Errorf("failed to create final chat completion: %w", err))
 	}
 
 	if len(finalResp.Choices) == 0 {
 		return "", fmt.Errorf("no completions returned in final call")
 	}
+	if err := llm.CheckChatCompletionFilter("azure-openai", string(finalResp.Choices[0].FinishReason), finalResp.Choices[0].Message.Refusal, finalResp.Choices[0].RawJSON()); err != nil {
+		return "", err
+	}
 
 	content := strings.TrimSpace(finalResp.Choices[0].Message.Content)
 	c.logger.Info(ctx, "Successfully received final response without tools", nil)
 	return content, nil
 }
 
+// promptFilterError converts an Azure OpenAI rejection of the prompt by its
+// content filters into a *interfaces.ContentFilterError, or returns nil
+func promptFilterError(err error) error {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != llm.FinishReasonContentFilter {
+		return nil
+	}
+
+	category := interfaces.ContentFilterCategoryUnknown
+	if inner, ok := apiErr.JSON.ExtraFields["innererror"]; ok {
+		category = llm.FilteredCategory(inner.Raw(), "content_filter_result")
+	}
+
+	return &interfaces.ContentFilterError{
+		Provider:      "azure-openai",
+		Category:      category,
+		Message:       apiErr.Message,
+		StopReason:    apiErr.Code,
+		PromptBlocked: true,
+	}
+}
+
+// classifyError maps an error of the Azure OpenAI API to the typed errors of
+// the interfaces package, e.g. *interfaces.RateLimitError for 429 responses
+func classifyError(err error) error {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		var header http.Header
+		if apiErr.Response != nil {
+			header = apiErr.Response.Header
+		}
+		return llm.ClassifyHTTPError("azure-openai", apiErr.StatusCode, header, apiErr.Code+": "+apiErr.Message, err)
+	}
+	return llm.ClassifyError("azure-openai", err)
+}
+
 // Name implements interfaces.LLM.Name
 func (c *AzureOpenAIClient) Name() string {
 	return "azure-openai"
 }
 
+// WarmConnections implements interfaces.ConnectionWarmer.WarmConnections
+// The OpenAI SDK sends requests through http.DefaultClient, so warming it
+// populates the same connection pool.
+func (c *AzureOpenAIClient) WarmConnections(ctx context.Context) error {
+	return llm.WarmConnection(ctx, http.DefaultClient, c.baseURL)
+}
+
 // SupportsStreaming implements interfaces.LLM.SupportsStreaming
 func (c *AzureOpenAIClient) SupportsStreaming() bool {
 	return true
@@ -67,6 +67,7 @@ func (c *AzureOpenAIClient) GenerateStream(
 			Model:    openai.ChatModel(c.deployment),
 			Messages: messages,
 		}
+		setGenerationLimits(&streamParams, params.LLMConfig)
 
 		// Reasoning models only support temperature=1 (default), so don't set it
 		if !isReasoningModel(c.Model) {
@@ -239,7 +240,7 @@ func (c *AzureOpenAIClient) GenerateStream(
 			})
 			eventChan <- interfaces.StreamEvent{
 				Type:      interfaces.StreamEventError,
-				Error:     fmt.Errorf("azure openai streaming error: %w", err),
+				Error:     classifyError(fmt.Errorf("azure openai streaming error: %w", err)),
 				Timestamp: time.Now(),
 			}
 			return
@@ -359,6 +360,7 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 				Tools:      openaiTools,
 				ToolChoice: openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String("auto")},
 			}
+			setGenerationLimits(&streamParams, params.LLMConfig)
 
 			// Reasoning models only support temperature=1 (default), so don't set it
 			if !isReasoningModel(c.Model) {
@@ -564,7 +566,7 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 				})
 				eventChan <- interfaces.StreamEvent{
 					Type:      interfaces.StreamEventError,
-					Error:     fmt.Errorf("azure openai streaming error: %w", err),
+					Error:     classifyError(fmt.Errorf("azure openai streaming error: %w", err)),
 					Timestamp: time.Now(),
 				}
 				return
@@ -723,15 +725,11 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 				// OpenAI spec places no 40-char bound on tool_call_id.
 
 				// Add the tool call to the tracing context
-				fmt.Printf("DEBUG AzureOpenAI: Adding tool call %s to tracing context\n", toolCallTrace.Name)
 				tracing.AddToolCallToContext(ctx, toolCallTrace)
-
-				// Debug: Check context after adding
-				if currentToolCalls := tracing.GetToolCallsFromContext(ctx); currentToolCalls != nil {
-					fmt.Printf("DEBUG AzureOpenAI: Context now has %d tool calls\n", len(currentToolCalls))
-				} else {
-					fmt.Printf("DEBUG AzureOpenAI: WARNING: Context has nil tool calls after adding\n")
-				}
+				c.logger.Debug(ctx, "Added tool call to tracing context", map[string]interface{}{
+					"tool_name":  toolCallTrace.Name,
+					"tool_calls": len(tracing.GetToolCallsFromContext(ctx)),
+				})
 
 				// Store tool call and result in memory if provided
 				if params.Memory != nil {
@@ -837,6 +835,7 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 			Model:    openai.ChatModel(c.deployment),
 			Messages: finalMessages,
 		}
+		setGenerationLimits(&finalStreamParams, params.LLMConfig)
 
 		// Reasoning models only support temperature=1 (default), so don't set it
 		if !isReasoningModel(c.Model) {
@@ -942,7 +941,7 @@ func (c *AzureOpenAIClient) GenerateWithToolsStream(
 			})
 			eventChan <- interfaces.StreamEvent{
 				Type:      interfaces.StreamEventError,
-				Error:     fmt.Errorf("azure openai final streaming error: %w", err),
+				Error:     classifyError(fmt.Errorf("azure openai final streaming error: %w", err)),
 				Timestamp: time.Now(),
 			}
 			return
@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// FinishReasonContentFilter is the finish reason OpenAI-compatible APIs report
+// when a safety filter stopped the response
+const FinishReasonContentFilter = "content_filter"
+
+// azureFilterCategories maps Azure OpenAI content_filter_results keys to categories
+var azureFilterCategories = map[string]interfaces.ContentFilterCategory{
+	"hate":                    interfaces.ContentFilterCategoryHate,
+	"sexual":                  interfaces.ContentFilterCategorySexual,
+	"violence":                interfaces.ContentFilterCategoryViolence,
+	"self_harm":               interfaces.ContentFilterCategorySelfHarm,
+	"profanity":               interfaces.ContentFilterCategoryHarassment,
+	"jailbreak":               interfaces.ContentFilterCategoryDangerous,
+	"protected_material_text": interfaces.ContentFilterCategoryRecitation,
+	"protected_material_code": interfaces.ContentFilterCategoryRecitation,
+}
+
+// CheckChatCompletionFilter inspects a chat completion choice from an
+// OpenAI-compatible API and returns a *interfaces.ContentFilterError when the
+// response was filtered or the model refused, or nil otherwise. rawChoice is
+// the choice JSON and is used to read Azure content_filter_results; it may be empty.
+func CheckChatCompletionFilter(provider, finishReason, refusal, rawChoice string) error {
+	if refusal != "" {
+		return &interfaces.ContentFilterError{
+			Provider:   provider,
+			Category:   interfaces.ContentFilterCategoryRefusal,
+			Message:    refusal,
+			StopReason: finishReason,
+		}
+	}
+
+	if finishReason != FinishReasonContentFilter {
+		return nil
+	}
+
+	return &interfaces.ContentFilterError{
+		Provider:   provider,
+		Category:   FilteredCategory(rawChoice, "content_filter_results"),
+		StopReason: finishReason,
+	}
+}
+
+// FilteredCategory returns the first filtered category in the Azure-style
+// content filter results stored under field in rawJSON, or
+// ContentFilterCategoryUnknown when none is reported
+func FilteredCategory(rawJSON, field string) interfaces.ContentFilterCategory {
+	if rawJSON == "" {
+		return interfaces.ContentFilterCategoryUnknown
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(rawJSON), &payload); err != nil {
+		return interfaces.ContentFilterCategoryUnknown
+	}
+
+	var results map[string]struct {
+		Filtered bool `json:"filtered"`
+	}
+	if err := json.Unmarshal(payload[field], &results); err != nil {
+		return interfaces.ContentFilterCategoryUnknown
+	}
+
+	// Sort keys so the reported category is deterministic
+	keys := make([]string, 0, len(results))
+	for key := range results {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if result := results[key]; result.Filtered {
+			if category, ok := azureFilterCategories[key]; ok {
+				return category
+			}
+		}
+	}
+
+	return interfaces.ContentFilterCategoryUnknown
+}
@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestCheckChatCompletionFilter(t *testing.T) {
+	tests := []struct {
+		name         string
+		finishReason string
+		refusal      string
+		rawChoice    string
+		wantCategory interfaces.ContentFilterCategory
+		wantBlocked  bool
+	}{
+		{name: "stop", finishReason: "stop"},
+		{
+			name:         "refusal",
+			finishReason: "stop",
+			refusal:      "I can't help with that.",
+			wantCategory: interfaces.ContentFilterCategoryRefusal,
+			wantBlocked:  true,
+		},
+		{
+			name:         "content filter without details",
+			finishReason: "content_filter",
+			wantCategory: interfaces.ContentFilterCategoryUnknown,
+			wantBlocked:  true,
+		},
+		{
+			name:         "azure content filter results",
+			finishReason: "content_filter",
+			rawChoice:    `{"finish_reason":"content_filter","content_filter_results":{"hate":{"filtered":false,"severity":"safe"},"violence":{"filtered":true,"severity":"high"}}}`,
+			wantCategory: interfaces.ContentFilterCategoryViolence,
+			wantBlocked:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckChatCompletionFilter("openai", tt.finishReason, tt.refusal, tt.rawChoice)
+			if !tt.wantBlocked {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			blocked, ok := interfaces.AsContentFilterError(err)
+			if !ok {
+				t.Fatalf("expected ContentFilterError, got %v", err)
+			}
+			if blocked.Category != tt.wantCategory {
+				t.Errorf("expected category %s, got %s", tt.wantCategory, blocked.Category)
+			}
+			if blocked.Message != tt.refusal {
+				t.Errorf("expected message %q, got %q", tt.refusal, blocked.Message)
+			}
+		})
+	}
+}
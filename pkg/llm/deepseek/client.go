@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
@@ -101,6 +102,11 @@ func (c *DeepSeekClient) Name() string {
 	return "deepseek"
 }
 
+// WarmConnections implements interfaces.ConnectionWarmer.WarmConnections
+func (c *DeepSeekClient) WarmConnections(ctx context.Context) error {
+	return llm.WarmConnection(ctx, c.HTTPClient, c.BaseURL)
+}
+
 // SupportsStreaming returns true if this LLM supports streaming
 func (c *DeepSeekClient) SupportsStreaming() bool {
 	return true
@@ -239,6 +245,7 @@ func (c *DeepSeekClient) GenerateDetailed(ctx context.Context, prompt string, op
 		req.TopP = params.LLMConfig.TopP
 		req.FrequencyPenalty = params.LLMConfig.FrequencyPenalty
 		req.PresencePenalty = params.LLMConfig.PresencePenalty
+		req.MaxTokens = params.LLMConfig.MaxOutputTokens
 		if len(params.LLMConfig.StopSequences) > 0 {
 			req.Stop = params.LLMConfig.StopSequences
 		}
@@ -295,6 +302,10 @@ func (c *DeepSeekClient) GenerateDetailed(ctx context.Context, prompt string, op
 
 	// Return response
 	if len(resp.Choices) > 0 {
+		if err := llm.CheckChatCompletionFilter("deepseek", resp.Choices[0].FinishReason, "", ""); err != nil {
+			return nil, err
+		}
+
 		c.logger.Debug(ctx, "Successfully received response from DeepSeek", map[string]interface{}{
 			"model": c.Model,
 		})
@@ -344,7 +355,7 @@ func (c *DeepSeekClient) doRequest(ctx context.Context, req ChatCompletionReques
 	// Make request
 	httpResp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, llm.ClassifyError("deepseek", fmt.Errorf("failed to make request: %w", err))
 	}
 	defer func() {
 		if err := httpResp.Body.Close(); err != nil {
@@ -362,7 +373,8 @@ func (c *DeepSeekClient) doRequest(ctx context.Context, req ChatCompletionReques
 
 	// Check for errors
 	if httpResp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("DeepSeek API error: status=%d, body=%s", httpResp.StatusCode, string(body))
+		return nil, llm.ClassifyHTTPError("deepseek", httpResp.StatusCode, httpResp.Header, string(body),
+			fmt.Errorf("DeepSeek API error: status=%d, body=%s", httpResp.StatusCode, string(body)))
 	}
 
 	// Parse response
@@ -441,6 +453,7 @@ func (c *DeepSeekClient) GenerateWithToolsDetailed(ctx context.Context, prompt s
 			req.TopP = params.LLMConfig.TopP
 			req.FrequencyPenalty = params.LLMConfig.FrequencyPenalty
 			req.PresencePenalty = params.LLMConfig.PresencePenalty
+			req.MaxTokens = params.LLMConfig.MaxOutputTokens
 			if len(params.LLMConfig.StopSequences) > 0 {
 				req.Stop = params.LLMConfig.StopSequences
 			}
@@ -477,6 +490,9 @@ func (c *DeepSeekClient) GenerateWithToolsDetailed(ctx context.Context, prompt s
 		if len(resp.Choices) == 0 {
 			return nil, fmt.Errorf("no response from DeepSeek API")
 		}
+		if err := llm.CheckChatCompletionFilter("deepseek", resp.Choices[0].FinishReason, "", ""); err != nil {
+			return nil, err
+		}
 
 		// Accumulate token usage
 		totalInputTokens += resp.Usage.PromptTokens
@@ -573,6 +589,7 @@ func (c *DeepSeekClient) GenerateWithToolsDetailed(ctx context.Context, prompt s
 		req.TopP = params.LLMConfig.TopP
 		req.FrequencyPenalty = params.LLMConfig.FrequencyPenalty
 		req.PresencePenalty = params.LLMConfig.PresencePenalty
+		req.MaxTokens = params.LLMConfig.MaxOutputTokens
 		if len(params.LLMConfig.StopSequences) > 0 {
 			req.Stop = params.LLMConfig.StopSequences
 		}
@@ -586,6 +603,9 @@ func (c *DeepSeekClient) GenerateWithToolsDetailed(ctx context.Context, prompt s
 	if len(resp.Choices) == 0 {
 		return nil, fmt.Errorf("no response from DeepSeek API")
 	}
+	if err := llm.CheckChatCompletionFilter("deepseek", resp.Choices[0].FinishReason, "", ""); err != nil {
+		return nil, err
+	}
 
 	totalInputTokens += resp.Usage.PromptTokens
 	totalOutputTokens += resp.Usage.CompletionTokens
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 )
 
@@ -105,6 +106,7 @@ func (c *DeepSeekClient) GenerateStream(
 			req.TopP = params.LLMConfig.TopP
 			req.FrequencyPenalty = params.LLMConfig.FrequencyPenalty
 			req.PresencePenalty = params.LLMConfig.PresencePenalty
+			req.MaxTokens = params.LLMConfig.MaxOutputTokens
 			if len(params.LLMConfig.StopSequences) > 0 {
 				req.Stop = params.LLMConfig.StopSequences
 			}
@@ -382,6 +384,7 @@ func (c *DeepSeekClient) GenerateWithToolsStream(
 				req.TopP = params.LLMConfig.TopP
 				req.FrequencyPenalty = params.LLMConfig.FrequencyPenalty
 				req.PresencePenalty = params.LLMConfig.PresencePenalty
+				req.MaxTokens = params.LLMConfig.MaxOutputTokens
 			}
 
 			c.logger.Debug(ctx, "Creating DeepSeek streaming request with tools", map[string]interface{}{
@@ -714,6 +717,7 @@ func (c *DeepSeekClient) GenerateWithToolsStream(
 			finalReq.TopP = params.LLMConfig.TopP
 			finalReq.FrequencyPenalty = params.LLMConfig.FrequencyPenalty
 			finalReq.PresencePenalty = params.LLMConfig.PresencePenalty
+			finalReq.MaxTokens = params.LLMConfig.MaxOutputTokens
 		}
 
 		// Add structured output if specified
@@ -860,7 +864,7 @@ func (c *DeepSeekClient) doStreamRequest(ctx context.Context, req ChatCompletion
 	// Make request
 	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, llm.ClassifyError("deepseek", fmt.Errorf("failed to make request: %w", err))
 	}
 
 	// Check status code
@@ -869,7 +873,8 @@ func (c *DeepSeekClient) doStreamRequest(ctx context.Context, req ChatCompletion
 		if err := resp.Body.Close(); err != nil {
 			return nil, fmt.Errorf("deepseek API error (status %d): %s (close error: %w)", resp.StatusCode, string(body), err)
 		}
-		return nil, fmt.Errorf("deepseek API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, llm.ClassifyHTTPError("deepseek", resp.StatusCode, resp.Header, string(body),
+			fmt.Errorf("deepseek API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return resp, nil
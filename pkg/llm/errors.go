@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// contextLengthMessages are fragments of the error messages providers return
+// for requests that do not fit in the model's context window
+var contextLengthMessages = []string{
+	"context_length_exceeded",              // OpenAI, Azure OpenAI
+	"maximum context length",               // OpenAI-compatible APIs, e.g. vLLM and DeepSeek
+	"prompt is too long",                   // Anthropic
+	"exceeds the maximum number of tokens", // Gemini
+	"exceeds the context window",
+	"context window exceeded",
+}
+
+// ClassifyHTTPError maps a failed provider API response to the typed errors
+// of the interfaces package: a *interfaces.RateLimitError for 429 responses,
+// a *interfaces.ProviderUnavailable for server errors and overloads, and a
+// *interfaces.ContextLengthError when the provider reports the request is too
+// long. message is the error message or body of the response and err the
+// error returned to the caller, which the typed error wraps. err is returned
+// unchanged when the response matches none of them.
+func ClassifyHTTPError(provider string, statusCode int, header http.Header, message string, err error) error {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return &interfaces.RateLimitError{Provider: provider, RetryAfter: RetryAfter(header), Err: err}
+	case statusCode >= http.StatusInternalServerError:
+		// Includes Anthropic's 529 "overloaded" responses
+		return &interfaces.ProviderUnavailable{Provider: provider, StatusCode: statusCode, Err: err}
+	case statusCode == http.StatusBadRequest || statusCode == http.StatusRequestEntityTooLarge:
+		if IsContextLengthMessage(message) {
+			return &interfaces.ContextLengthError{Provider: provider, Err: err}
+		}
+	}
+	return err
+}
+
+// ClassifyError maps errors of provider API calls that carry no HTTP
+// response: network failures become a *interfaces.ProviderUnavailable.
+// Errors that are already typed, and cancellations and deadlines of the
+// caller's context, are returned unchanged.
+func ClassifyError(provider string, err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if errors.Is(err, interfaces.ErrRateLimitExceeded) || errors.Is(err, interfaces.ErrProviderUnavailable) ||
+		errors.Is(err, interfaces.ErrContextLengthExceeded) {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &interfaces.ProviderUnavailable{Provider: provider, Err: err}
+	}
+	return err
+}
+
+// IsContextLengthMessage reports whether a provider error message says the
+// request does not fit in the model's context window
+func IsContextLengthMessage(message string) bool {
+	message = strings.ToLower(message)
+	for _, fragment := range contextLengthMessages {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryAfter returns how long a response asks to wait before retrying, from
+// its retry-after-ms or Retry-After header (0 when absent)
+func RetryAfter(header http.Header) time.Duration {
+	if header == nil {
+		return 0
+	}
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestClassifyHTTPError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		message    string
+		want       error
+		retryAfter time.Duration
+	}{
+		{name: "rate limit", statusCode: 429, header: http.Header{"Retry-After": {"7"}}, want: interfaces.ErrRateLimitExceeded, retryAfter: 7 * time.Second},
+		{name: "rate limit in ms", statusCode: 429, header: http.Header{"Retry-After-Ms": {"1500"}}, want: interfaces.ErrRateLimitExceeded, retryAfter: 1500 * time.Millisecond},
+		{name: "overloaded", statusCode: 529, message: `{"type":"error","error":{"type":"overloaded_error"}}`, want: interfaces.ErrProviderUnavailable},
+		{name: "server error", statusCode: 503, want: interfaces.ErrProviderUnavailable},
+		{name: "prompt too long", statusCode: 400, message: `{"error":{"message":"prompt is too long: 210000 tokens > 200000 maximum"}}`, want: interfaces.ErrContextLengthExceeded},
+		{name: "context length code", statusCode: 400, message: "context_length_exceeded: This model's maximum context length is 128000 tokens", want: interfaces.ErrContextLengthExceeded},
+		{name: "other bad request", statusCode: 400, message: "invalid model"},
+		{name: "unauthorized", statusCode: 401, message: "invalid api key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := fmt.Errorf("API error (status %d): %s", tt.statusCode, tt.message)
+			err := ClassifyHTTPError("test", tt.statusCode, tt.header, tt.message, apiErr)
+
+			if !errors.Is(err, apiErr) {
+				t.Errorf("Expected the error to wrap the API error, got %v", err)
+			}
+			if tt.want == nil {
+				if err != apiErr {
+					t.Errorf("Expected the API error unchanged, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+
+			var rateLimited *interfaces.RateLimitError
+			if errors.As(err, &rateLimited) && rateLimited.RetryAfter != tt.retryAfter {
+				t.Errorf("Expected retry after %s, got %s", tt.retryAfter, rateLimited.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	dialErr := fmt.Errorf("failed to send request: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+	var unavailable *interfaces.ProviderUnavailable
+	if err := ClassifyError("test", dialErr); !errors.As(err, &unavailable) || unavailable.Provider != "test" {
+		t.Errorf("Expected a ProviderUnavailable error, got %v", err)
+	}
+
+	for _, err := range []error{
+		context.Canceled,
+		fmt.Errorf("failed to send request: %w", context.DeadlineExceeded),
+		&interfaces.RateLimitError{Provider: "test"},
+		errors.New("invalid request"),
+	} {
+		if classified := ClassifyError("test", err); classified != err {
+			t.Errorf("Expected %v unchanged, got %v", err, classified)
+		}
+	}
+}
@@ -3,19 +3,18 @@ package gemini
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
-	"sync"
-	"time"
 
 	"cloud.google.com/go/auth/credentials"
 	"google.golang.org/genai"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
-	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
 )
 
 // Model constants for Gemini API
@@ -166,16 +165,22 @@ func WithMaxOutputTokens(maxTokens int32) Option {
 	}
 }
 
-// applyMaxOutputTokens applies the client's max output tokens to the generation config if set
-func (c *GeminiClient) applyMaxOutputTokens(genConfig **genai.GenerationConfig) {
-	if c.maxOutputTokens != nil {
-		if *genConfig == nil {
-			*genConfig = &genai.GenerationConfig{}
-		}
-		// MaxOutputTokens expects int32 value, not pointer
-		maxTokens := *c.maxOutputTokens
-		(*genConfig).MaxOutputTokens = maxTokens
-	}
+// applyMaxOutputTokens applies the max output tokens of the request, or else
+// of the client, to the generation config if set
+func (c *GeminiClient) applyMaxOutputTokens(genConfig **genai.GenerationConfig, llmConfig *interfaces.LLMConfig) {
+	var maxTokens int32
+	switch {
+	case llmConfig != nil && llmConfig.MaxOutputTokens > 0:
+		maxTokens = int32(llmConfig.MaxOutputTokens)
+	case c.maxOutputTokens != nil:
+		maxTokens = *c.maxOutputTokens
+	default:
+		return
+	}
+	if *genConfig == nil {
+		*genConfig = &genai.GenerationConfig{}
+	}
+	(*genConfig).MaxOutputTokens = maxTokens
 }
 
 // NewClient creates a new Gemini client
@@ -344,10 +349,14 @@ func (c *GeminiClient) generateInternal(ctx context.Context, prompt string, opti
 	if params.LLMConfig != nil {
 		genConfig = &genai.GenerationConfig{}
 
-		if params.LLMConfig.Temperature > 0 {
+		if params.LLMConfig.Temperature > 0 || params.LLMConfig.Deterministic {
 			temp := float32(params.LLMConfig.Temperature)
 			genConfig.Temperature = &temp
 		}
+		if params.LLMConfig.Seed != nil {
+			seed := int32(*params.LLMConfig.Seed)
+			genConfig.Seed = &seed
+		}
 		if params.LLMConfig.TopP > 0 {
 			topP := float32(params.LLMConfig.TopP)
 			genConfig.TopP = &topP
@@ -357,8 +366,8 @@ func (c *GeminiClient) generateInternal(ctx context.Context, prompt string, opti
 		}
 	}
 
-	// Apply max output tokens if configured at client level
-	c.applyMaxOutputTokens(&genConfig)
+	// Apply max output tokens if configured for the request or client
+	c.applyMaxOutputTokens(&genConfig, params.LLMConfig)
 
 	// Set response format if provided
 	if params.ResponseFormat != nil {
@@ -402,6 +411,12 @@ func (c *GeminiClient) generateInternal(ctx context.Context, prompt string, opti
 			if genConfig.Temperature != nil {
 				config.Temperature = genConfig.Temperature
 			}
+			if genConfig.Seed != nil {
+				config.Seed = genConfig.Seed
+			}
+			if genConfig.MaxOutputTokens > 0 {
+				config.MaxOutputTokens = genConfig.MaxOutputTokens
+			}
 			if genConfig.TopP != nil {
 				config.TopP = genConfig.TopP
 			}
@@ -437,7 +452,7 @@ func (c *GeminiClient) generateInternal(ctx context.Context, prompt string, opti
 				"error": err.Error(),
 				"model": c.model,
 			})
-			return fmt.Errorf("failed to generate text: %w", err)
+			return classifyError(fmt.Errorf("failed to generate text: %w", err))
 		}
 		return nil
 	}
@@ -455,6 +470,10 @@ func (c *GeminiClient) generateInternal(ctx context.Context, prompt string, opti
 		return nil, err
 	}
 
+	if err := contentFilterError(result); err != nil {
+		return nil, err
+	}
+
 	// Extract response and separate thinking from final content
 	if len(result.Candidates) > 0 && len(result.Candidates[0].Content.Parts) > 0 {
 		c.logger.Debug(ctx, "Successfully received response from Gemini", map[string]interface{}{
@@ -548,31 +567,10 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		}
 	}
 
-	// Set default max iterations if not provided
-	maxIterations := params.MaxIterations
-	if maxIterations == 0 {
-		maxIterations = 2 // Default to current behavior
-	}
-
-	// Check for organization ID in context
-	orgID := "default"
-	if id, err := multitenancy.GetOrgID(ctx); err == nil {
-		orgID = id
-	}
-	_ = orgID // Mark as used to avoid linter warning
-
-	// Convert tools to Gemini format. Shared with GenerateStreamWithTools so
-	// Ask and Stream agree on schema shape, including array `items`.
-	geminiTools := convertToolsToFunctionDeclarations(tools)
-
 	// Build contents with memory and current prompt
 	contents := c.buildContentsWithMemory(ctx, prompt, params)
 	var systemInstruction *genai.Content
 
-	// Track tool call repetitions for loop detection
-	toolCallHistory := make(map[string]int)
-	var toolCallHistoryMu sync.Mutex
-
 	// Add system message if available
 	if params.SystemMessage != "" {
 		systemMessage := params.SystemMessage
@@ -601,390 +599,175 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		c.logger.Debug(ctx, "Using system message", map[string]interface{}{"system_message": systemMessage})
 	}
 
-	// Iterative tool calling loop
-	// Track the last response content from the tool-calling loop
-	var lastContent string
+	// Run the tool-calling loop with the shared tool executor
+	executor := llm.NewToolExecutor(c.logger, llm.WithToolMemory(params.Memory), llm.WithToolTimeout(params.ToolTimeout), llm.WithParallelToolCalls(params.ParallelToolCalls), llm.WithToolsFunc(params.ToolsFunc))
+	loop := &contentToolLoop{client: c, params: params, contents: contents, systemInstruction: systemInstruction}
+	result, err := executor.Run(ctx, loop, tools, params.ToolLoop())
+	if err != nil {
+		return "", err
+	}
+	return result.Content, nil
+}
 
-	for iteration := 0; iteration < maxIterations; iteration++ {
-		// Set generation config
-		var genConfig *genai.GenerationConfig
-		if params.LLMConfig != nil {
-			genConfig = &genai.GenerationConfig{}
+// contentToolLoop sends the requests of GenerateWithTools to the
+// GenerateContent API, for the shared tool-calling loop
+type contentToolLoop struct {
+	client            *GeminiClient
+	params            *interfaces.GenerateOptions
+	contents          []*genai.Content
+	systemInstruction *genai.Content
+}
 
-			if params.LLMConfig.Temperature > 0 {
-				temp := float32(params.LLMConfig.Temperature)
-				genConfig.Temperature = &temp
-			}
-			if params.LLMConfig.TopP > 0 {
-				topP := float32(params.LLMConfig.TopP)
-				genConfig.TopP = &topP
-			}
-			if len(params.LLMConfig.StopSequences) > 0 {
-				genConfig.StopSequences = params.LLMConfig.StopSequences
-			}
-		}
+// Send implements llm.ToolLoopProvider.Send
+func (l *contentToolLoop) Send(ctx context.Context, iteration int, tools []interfaces.Tool) (llm.ToolLoopTurn, error) {
+	c := l.client
 
-		// Apply max output tokens if configured at client level
-		c.applyMaxOutputTokens(&genConfig)
+	// Convert tools to Gemini format. Shared with GenerateWithToolsStream so
+	// Ask and Stream agree on schema shape, including array `items`.
+	config := c.toolLoopConfig(ctx, l.params, l.systemInstruction, true)
+	config.Tools = []*genai.Tool{
+		{
+			FunctionDeclarations: convertToolsToFunctionDeclarations(tools),
+		},
+	}
 
-		// Set response format if provided
-		if params.ResponseFormat != nil {
-			if genConfig == nil {
-				genConfig = &genai.GenerationConfig{}
-			}
-			genConfig.ResponseMIMEType = "application/json"
+	c.logger.Debug(ctx, "Sending request with tools to Gemini", map[string]interface{}{
+		"model":           c.model,
+		"contents":        len(l.contents),
+		"tools":           len(tools),
+		"response_format": l.params.ResponseFormat != nil,
+		"iteration":       iteration + 1,
+	})
 
-			// Convert schema for genai
-			if schemaBytes, err := json.Marshal(params.ResponseFormat.Schema); err == nil {
-				var schema *genai.Schema
-				if err := json.Unmarshal(schemaBytes, &schema); err != nil {
-					c.logger.Warn(ctx, "Failed to convert response schema", map[string]interface{}{"error": err.Error()})
-				} else {
-					genConfig.ResponseSchema = schema
-				}
-			}
-			c.logger.Debug(ctx, "Using response format", map[string]interface{}{"format": *params.ResponseFormat})
-		}
+	result, err := c.genaiClient.Models.GenerateContent(ctx, c.model, l.contents, config)
+	if err != nil {
+		c.logger.Error(ctx, "Error from Gemini API", map[string]interface{}{"error": err.Error()})
+		return llm.ToolLoopTurn{}, classifyError(fmt.Errorf("failed to create content: %w", err))
+	}
 
-		logData := map[string]interface{}{
-			"model":           c.model,
-			"contents":        len(contents),
-			"tools":           len(geminiTools),
-			"response_format": params.ResponseFormat != nil,
-			"iteration":       iteration + 1,
-			"maxIterations":   maxIterations,
-		}
+	if err := contentFilterError(result); err != nil {
+		return llm.ToolLoopTurn{}, err
+	}
 
-		if genConfig != nil {
-			if genConfig.Temperature != nil {
-				logData["temperature"] = *genConfig.Temperature
-			}
-			if genConfig.TopP != nil {
-				logData["top_p"] = *genConfig.TopP
-			}
-			if len(genConfig.StopSequences) > 0 {
-				logData["stop_sequences"] = genConfig.StopSequences
-			}
-		}
+	if len(result.Candidates) == 0 {
+		return llm.ToolLoopTurn{}, fmt.Errorf("no candidates returned")
+	}
 
-		c.logger.Debug(ctx, "Sending request with tools to Gemini", logData)
+	candidate := result.Candidates[0]
+	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
+		return llm.ToolLoopTurn{}, fmt.Errorf("no content in response")
+	}
 
-		config := &genai.GenerateContentConfig{
-			Tools: []*genai.Tool{
-				{
-					FunctionDeclarations: geminiTools,
-				},
-			},
-			SystemInstruction: systemInstruction,
+	// Extract the text content and decode the function calls
+	var turn llm.ToolLoopTurn
+	var textParts []string
+	for _, part := range candidate.Content.Parts {
+		if part.Text != "" {
+			textParts = append(textParts, part.Text)
 		}
-
-		// Apply generation config parameters directly to config
-		if genConfig != nil {
-			if genConfig.Temperature != nil {
-				config.Temperature = genConfig.Temperature
-			}
-			if genConfig.TopP != nil {
-				config.TopP = genConfig.TopP
-			}
-			if len(genConfig.StopSequences) > 0 {
-				config.StopSequences = genConfig.StopSequences
-			}
-			if genConfig.ResponseMIMEType != "" {
-				config.ResponseMIMEType = genConfig.ResponseMIMEType
-			}
-			if genConfig.ResponseSchema != nil {
-				config.ResponseSchema = genConfig.ResponseSchema
-			}
+		if part.FunctionCall == nil {
+			continue
 		}
 
-		result, err := c.genaiClient.Models.GenerateContent(ctx, c.model, contents, config)
+		// Convert function call arguments to JSON string
+		argsBytes, err := json.Marshal(part.FunctionCall.Args)
 		if err != nil {
-			c.logger.Error(ctx, "Error from Gemini API", map[string]interface{}{"error": err.Error()})
-			return "", fmt.Errorf("failed to create content: %w", err)
-		}
-
-		if len(result.Candidates) == 0 {
-			return "", fmt.Errorf("no candidates returned")
-		}
-
-		candidate := result.Candidates[0]
-		if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
-			return "", fmt.Errorf("no content in response")
-		}
-
-		// Check if any part contains function calls
-		hasFunctionCalls := false
-		for _, part := range candidate.Content.Parts {
-			if part.FunctionCall != nil {
-				hasFunctionCalls = true
-				break
-			}
-		}
-
-		// Extract text content from this iteration
-		var iterTextParts []string
-		for _, part := range candidate.Content.Parts {
-			if part.Text != "" {
-				iterTextParts = append(iterTextParts, part.Text)
-			}
-		}
-		if len(iterTextParts) > 0 {
-			lastContent = strings.Join(iterTextParts, " ")
-		}
-
-		// If no function calls, return the text response
-		if !hasFunctionCalls {
-			return lastContent, nil
+			c.logger.Error(ctx, "Failed to marshal function call arguments", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return llm.ToolLoopTurn{}, fmt.Errorf("failed to marshal function call arguments: %w", err)
 		}
 
-		// Process function calls
-		c.logger.Info(ctx, "Processing function calls", map[string]interface{}{
-			"iteration": iteration + 1,
+		turn.Calls = append(turn.Calls, interfaces.ToolCall{
+			Name:             part.FunctionCall.Name,
+			Arguments:        string(argsBytes),
+			ThoughtSignature: part.ThoughtSignature,
 		})
+	}
+	turn.Content = strings.Join(textParts, " ")
 
-		// Add the assistant's message with function calls to the conversation
-		// Ensure the role is set to "model"
-		assistantContent := &genai.Content{
+	// Add the assistant's message with function calls to the conversation
+	if len(turn.Calls) > 0 {
+		l.contents = append(l.contents, &genai.Content{
 			Role:  "model",
 			Parts: candidate.Content.Parts,
-		}
-		contents = append(contents, assistantContent)
-
-		// Collect all function responses to add them in a single content message
-		var functionResponses []*genai.Part
-
-		// Process each function call
-		for _, part := range candidate.Content.Parts {
-			if part.FunctionCall == nil {
-				continue
-			}
-
-			functionCall := part.FunctionCall
-
-			// Find the requested tool
-			var selectedTool interfaces.Tool
-			for _, tool := range tools {
-				if tool.Name() == functionCall.Name {
-					selectedTool = tool
-					break
-				}
-			}
-
-			if selectedTool == nil {
-				c.logger.Error(ctx, "Tool not found", map[string]interface{}{
-					"toolName": functionCall.Name,
-				})
-
-				// Add tool not found error as function response
-				functionResponses = append(functionResponses, &genai.Part{
-					FunctionResponse: &genai.FunctionResponse{
-						Name: functionCall.Name,
-						Response: map[string]any{
-							"error": fmt.Sprintf("tool not found: %s", functionCall.Name),
-						},
-					},
-				})
-
-				// Store failed tool call in memory if provided
-				if params.Memory != nil {
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:    "assistant",
-						Content: "",
-						ToolCalls: []interfaces.ToolCall{{
-							Name:             functionCall.Name,
-							Arguments:        "{}",
-							ThoughtSignature: part.ThoughtSignature,
-						}},
-					})
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:    "tool",
-						Content: fmt.Sprintf("Error: tool not found: %s", functionCall.Name),
-						Metadata: map[string]interface{}{
-							"tool_name": functionCall.Name,
-						},
-					})
-				}
-
-				// Add to tracing context
-				toolCallTrace := tracing.ToolCall{
-					Name:       functionCall.Name,
-					Arguments:  "{}",
-					Timestamp:  time.Now().Format(time.RFC3339),
-					StartTime:  time.Now(),
-					Duration:   0,
-					DurationMs: 0,
-					Error:      fmt.Sprintf("tool not found: %s", functionCall.Name),
-					Result:     fmt.Sprintf("Error: tool not found: %s", functionCall.Name),
-				}
-
-				tracing.AddToolCallToContext(ctx, toolCallTrace)
-
-				continue // Continue processing other function calls
-			}
-
-			// Convert function call arguments to JSON string
-			argsBytes, err := json.Marshal(functionCall.Args)
-			if err != nil {
-				c.logger.Error(ctx, "Failed to marshal function call arguments", map[string]interface{}{
-					"error": err.Error(),
-				})
-				return "", fmt.Errorf("failed to marshal function call arguments: %w", err)
-			}
-
-			// Execute the tool
-			c.logger.Info(ctx, "Executing tool", map[string]interface{}{"toolName": selectedTool.Name()})
-			toolStartTime := time.Now()
-			toolResult, err := selectedTool.Execute(ctx, string(argsBytes))
-			toolEndTime := time.Now()
-
-			// Check for repetitive calls and add warning if needed
-			cacheKey := functionCall.Name + ":" + string(argsBytes)
-
-			toolCallHistoryMu.Lock()
-			toolCallHistory[cacheKey]++
-			callCount := toolCallHistory[cacheKey]
-			toolCallHistoryMu.Unlock()
-
-			if callCount > 1 {
-				warning := fmt.Sprintf("\n\n[WARNING: This is call #%d to %s with identical parameters. You may be in a loop. Consider using the available information to provide a final answer.]",
-					callCount,
-					functionCall.Name)
-				if err == nil {
-					toolResult += warning
-				}
-				c.logger.Warn(ctx, "Repetitive tool call detected", map[string]interface{}{
-					"toolName":  functionCall.Name,
-					"callCount": callCount,
-				})
-			}
+		})
+	}
+	return turn, nil
+}
 
-			// Add tool call to tracing context
-			executionDuration := toolEndTime.Sub(toolStartTime)
-			toolCallTrace := tracing.ToolCall{
-				Name:       functionCall.Name,
-				Arguments:  string(argsBytes),
-				Timestamp:  toolStartTime.Format(time.RFC3339),
-				StartTime:  toolStartTime,
-				Duration:   executionDuration,
-				DurationMs: executionDuration.Milliseconds(),
-			}
+// AddResults implements llm.ToolLoopProvider.AddResults
+func (l *contentToolLoop) AddResults(ctx context.Context, iteration int, results []llm.ToolResult) error {
+	l.contents = appendFunctionResponses(l.contents, results)
+	return nil
+}
 
-			// Store tool call and result in memory if provided
-			if params.Memory != nil {
-				if err != nil {
-					// Store failed tool call result
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:    "assistant",
-						Content: "",
-						ToolCalls: []interfaces.ToolCall{{
-							Name:             functionCall.Name,
-							Arguments:        string(argsBytes),
-							ThoughtSignature: part.ThoughtSignature,
-						}},
-					})
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:    "tool",
-						Content: fmt.Sprintf("Error: %v", err),
-						Metadata: map[string]interface{}{
-							"tool_name": functionCall.Name,
-						},
-					})
-				} else {
-					// Store successful tool call and result
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:    "assistant",
-						Content: "",
-						ToolCalls: []interfaces.ToolCall{{
-							Name:             functionCall.Name,
-							Arguments:        string(argsBytes),
-							ThoughtSignature: part.ThoughtSignature,
-						}},
-					})
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:    "tool",
-						Content: toolResult,
-						Metadata: map[string]interface{}{
-							"tool_name": functionCall.Name,
-						},
-					})
-				}
-			}
+// Conclude implements llm.ToolLoopProvider.Conclude
+func (l *contentToolLoop) Conclude(ctx context.Context, finalPrompt string) (string, error) {
+	c := l.client
 
-			if err != nil {
-				c.logger.Error(ctx, "Tool execution failed", map[string]interface{}{
-					"toolName": selectedTool.Name(),
-					"toolArgs": string(argsBytes),
-					"error":    err.Error(),
-					"duration": toolEndTime.Sub(toolStartTime).String(),
-				})
-				toolCallTrace.Error = err.Error()
-				toolCallTrace.Result = fmt.Sprintf("Error: %v", err)
-
-				// Add error message as function response
-				functionResponses = append(functionResponses, &genai.Part{
-					FunctionResponse: &genai.FunctionResponse{
-						Name: functionCall.Name,
-						Response: map[string]any{
-							"error": err.Error(),
-						},
-					},
-				})
-			} else {
-				toolCallTrace.Result = toolResult
-
-				// Add tool result as function response
-				functionResponses = append(functionResponses, &genai.Part{
-					FunctionResponse: &genai.FunctionResponse{
-						Name: functionCall.Name,
-						Response: map[string]any{
-							"result": toolResult,
-						},
-					},
-				})
-			}
+	// Add a conclusion instruction to the contents
+	contents := append(l.contents, &genai.Content{
+		Role: "user",
+		Parts: []*genai.Part{
+			{Text: finalPrompt},
+		},
+	})
 
-			// Add the tool call to the tracing context
-			tracing.AddToolCallToContext(ctx, toolCallTrace)
-		}
+	c.logger.Debug(ctx, "Making final request without tools", map[string]interface{}{
+		"contents": len(contents),
+	})
 
-		// Add all function responses in a single content message
-		if len(functionResponses) > 0 {
+	config := c.toolLoopConfig(ctx, l.params, l.systemInstruction, true)
+	finalResult, err := c.genaiClient.Models.GenerateContent(ctx, c.model, contents, config)
+	if err != nil {
+		c.logger.Error(ctx, "Error in final call without tools", map[string]interface{}{"error": err.Error()})
+		return "", classifyError(fmt.Errorf("failed to create final content: %w", err))
+	}
 
-			// Add all function responses in a single content message
-			resultContent := &genai.Content{
-				Role:  "user",
-				Parts: functionResponses,
-			}
-			contents = append(contents, resultContent)
-		}
+	if err := contentFilterError(finalResult); err != nil {
+		return "", err
+	}
 
-		// Continue to the next iteration with updated contents
+	if len(finalResult.Candidates) == 0 {
+		return "", fmt.Errorf("no candidates returned in final call")
 	}
 
-	// If we've reached the maximum iterations and the model is still requesting tools,
-	// make one final call without tools to get a conclusion
+	candidate := finalResult.Candidates[0]
+	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
+		return "", fmt.Errorf("no content in final response")
+	}
 
-	// If DisableFinalSummary is enabled, return the last response from the tool-calling loop
-	if params.DisableFinalSummary {
-		c.logger.Info(ctx, "DisableFinalSummary enabled, skipping final summary call", map[string]interface{}{
-			"maxIterations": maxIterations,
-		})
-		return lastContent, nil
+	// Extract text from all parts
+	var textParts []string
+	for _, part := range candidate.Content.Parts {
+		if part.Text != "" {
+			textParts = append(textParts, part.Text)
+		}
 	}
 
-	c.logger.Info(ctx, "Maximum iterations reached, making final call without tools", map[string]interface{}{
-		"maxIterations": maxIterations,
-	})
+	content := strings.TrimSpace(strings.Join(textParts, " "))
+	c.logger.Info(ctx, "Successfully received final response without tools", nil)
+	return content, nil
+}
 
+// toolLoopConfig builds the config of a request of a tool-calling loop from
+// the generation options, with their response format when responseFormat is
+// set
+func (c *GeminiClient) toolLoopConfig(ctx context.Context, params *interfaces.GenerateOptions, systemInstruction *genai.Content, responseFormat bool) *genai.GenerateContentConfig {
 	// Set generation config
 	var genConfig *genai.GenerationConfig
 	if params.LLMConfig != nil {
 		genConfig = &genai.GenerationConfig{}
 
-		if params.LLMConfig.Temperature > 0 {
+		if params.LLMConfig.Temperature > 0 || params.LLMConfig.Deterministic {
 			temp := float32(params.LLMConfig.Temperature)
 			genConfig.Temperature = &temp
 		}
+		if params.LLMConfig.Seed != nil {
+			seed := int32(*params.LLMConfig.Seed)
+			genConfig.Seed = &seed
+		}
 		if params.LLMConfig.TopP > 0 {
 			topP := float32(params.LLMConfig.TopP)
 			genConfig.TopP = &topP
@@ -994,11 +777,11 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		}
 	}
 
-	// Apply max output tokens if configured at client level
-	c.applyMaxOutputTokens(&genConfig)
+	// Apply max output tokens if configured for the request or client
+	c.applyMaxOutputTokens(&genConfig, params.LLMConfig)
 
 	// Set response format if provided
-	if params.ResponseFormat != nil {
+	if responseFormat && params.ResponseFormat != nil {
 		if genConfig == nil {
 			genConfig = &genai.GenerationConfig{}
 		}
@@ -1015,18 +798,6 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		}
 	}
 
-	// Add a conclusion instruction to the contents
-	contents = append(contents, &genai.Content{
-		Role: "user",
-		Parts: []*genai.Part{
-			{Text: "Please provide your final response based on the information available. Do not request any additional functions."},
-		},
-	})
-
-	c.logger.Debug(ctx, "Making final request without tools", map[string]interface{}{
-		"contents": len(contents),
-	})
-
 	config := &genai.GenerateContentConfig{
 		SystemInstruction: systemInstruction,
 	}
@@ -1036,6 +807,12 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 		if genConfig.Temperature != nil {
 			config.Temperature = genConfig.Temperature
 		}
+		if genConfig.Seed != nil {
+			config.Seed = genConfig.Seed
+		}
+		if genConfig.MaxOutputTokens > 0 {
+			config.MaxOutputTokens = genConfig.MaxOutputTokens
+		}
 		if genConfig.TopP != nil {
 			config.TopP = genConfig.TopP
 		}
@@ -1049,33 +826,42 @@ func (c *GeminiClient) GenerateWithTools(ctx context.Context, prompt string, too
 			config.ResponseSchema = genConfig.ResponseSchema
 		}
 	}
+	return config
+}
 
-	finalResult, err := c.genaiClient.Models.GenerateContent(ctx, c.model, contents, config)
-	if err != nil {
-		c.logger.Error(ctx, "Error in final call without tools", map[string]interface{}{"error": err.Error()})
-		return "", fmt.Errorf("failed to create final content: %w", err)
+// appendFunctionResponses adds the results of the function calls of a
+// response to the contents, in a single content message
+func appendFunctionResponses(contents []*genai.Content, results []llm.ToolResult) []*genai.Content {
+	var functionResponses []*genai.Part
+	for _, result := range results {
+		response := map[string]any{"result": result.Content}
+		if result.Err != nil {
+			response = map[string]any{"error": result.Err.Error()}
+		}
+		functionResponses = append(functionResponses, &genai.Part{
+			FunctionResponse: &genai.FunctionResponse{
+				Name:     result.Call.Name,
+				Response: response,
+			},
+		})
 	}
-
-	if len(finalResult.Candidates) == 0 {
-		return "", fmt.Errorf("no candidates returned in final call")
-	}
-
-	candidate := finalResult.Candidates[0]
-	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
-		return "", fmt.Errorf("no content in final response")
+	if len(functionResponses) == 0 {
+		return contents
 	}
+	return append(contents, &genai.Content{
+		Role:  "user",
+		Parts: functionResponses,
+	})
+}
 
-	// Extract text from all parts
-	var textParts []string
-	for _, part := range candidate.Content.Parts {
-		if part.Text != "" {
-			textParts = append(textParts, part.Text)
-		}
+// classifyError maps an error of the Gemini API to the typed errors of the
+// interfaces package, e.g. *interfaces.RateLimitError for RESOURCE_EXHAUSTED
+func classifyError(err error) error {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return llm.ClassifyHTTPError("gemini", apiErr.Code, nil, apiErr.Message, err)
 	}
-
-	content := strings.TrimSpace(strings.Join(textParts, " "))
-	c.logger.Info(ctx, "Successfully received final response without tools", nil)
-	return content, nil
+	return llm.ClassifyError("gemini", err)
 }
 
 // Name implements interfaces.LLM.Name
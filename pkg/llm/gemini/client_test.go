@@ -3,8 +3,10 @@ package gemini
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -1063,3 +1065,75 @@ func TestBuildContentsWithMemory(t *testing.T) {
 		})
 	}
 }
+
+// newToolsFuncServer serves Gemini requests, recording the tools sent with
+// each one. The first response calls test_tool_1 and the next ones answer.
+func newToolsFuncServer(t *testing.T, requestTools *[][]string) *genai.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Tools []struct {
+				FunctionDeclarations []struct {
+					Name string `json:"name"`
+				} `json:"functionDeclarations"`
+			} `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		var names []string
+		for _, tool := range reqBody.Tools {
+			for _, decl := range tool.FunctionDeclarations {
+				names = append(names, decl.Name)
+			}
+		}
+		*requestTools = append(*requestTools, names)
+
+		part := map[string]interface{}{"text": "done"}
+		if len(*requestTools) == 1 {
+			part = map[string]interface{}{"functionCall": map[string]interface{}{"name": "test_tool_1", "args": map[string]interface{}{}}}
+		}
+		response, _ := json.Marshal(map[string]interface{}{
+			"candidates": []map[string]interface{}{
+				{"content": map[string]interface{}{"role": "model", "parts": []map[string]interface{}{part}}},
+			},
+		})
+
+		if strings.Contains(r.URL.Path, "streamGenerateContent") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", response)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(response)
+	}))
+	t.Cleanup(server.Close)
+
+	genaiClient, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		Backend:     genai.BackendVertexAI,
+		APIKey:      "test-key",
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	require.NoError(t, err)
+	return genaiClient
+}
+
+func TestGenerateWithToolsFunc(t *testing.T) {
+	var requestTools [][]string
+	client := &GeminiClient{
+		model:       DefaultModel,
+		genaiClient: newToolsFuncServer(t, &requestTools),
+		logger:      logging.New(),
+	}
+
+	// The tools change after the first iteration
+	toolsFunc := func() []interfaces.Tool {
+		return []interfaces.Tool{&MockTool{name: "test_tool_2", description: "Test tool 2"}}
+	}
+	resp, err := client.GenerateWithTools(context.Background(), "test prompt",
+		[]interfaces.Tool{&MockTool{name: "test_tool_1", description: "Test tool 1"}},
+		interfaces.WithToolsFunc(toolsFunc))
+	require.NoError(t, err)
+	assert.Equal(t, "done", resp)
+	assert.Equal(t, "[[test_tool_1] [test_tool_2]]", fmt.Sprint(requestTools))
+}
@@ -0,0 +1,43 @@
+package gemini
+
+import (
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"google.golang.org/genai"
+)
+
+// userContent builds user content from text and multimodal content parts
+func userContent(text string, parts []interfaces.ContentPart) *genai.Content {
+	var content []*genai.Part
+	if text != "" || len(parts) == 0 {
+		content = append(content, &genai.Part{Text: text})
+	}
+	content = append(content, convertContentParts(parts)...)
+	return &genai.Content{
+		Role:  "user",
+		Parts: content,
+	}
+}
+
+// convertContentParts converts content parts to Gemini parts. Media with
+// data is sent inline, media given by URL as file data. Tool calls and
+// results are skipped.
+func convertContentParts(parts []interfaces.ContentPart) []*genai.Part {
+	var content []*genai.Part
+	for _, part := range parts {
+		switch {
+		case part.Type == interfaces.ContentPartText:
+			content = append(content, &genai.Part{Text: part.Text})
+		case part.IsMedia() && len(part.Data) > 0:
+			content = append(content, &genai.Part{InlineData: &genai.Blob{
+				MIMEType: part.MIMEType,
+				Data:     part.Data,
+			}})
+		case part.IsMedia() && part.URL != "":
+			content = append(content, &genai.Part{FileData: &genai.FileData{
+				FileURI:  part.URL,
+				MIMEType: part.MIMEType,
+			}})
+		}
+	}
+	return content
+}
@@ -0,0 +1,77 @@
+package gemini
+
+import (
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// harmCategories maps Gemini harm categories to content filter categories
+var harmCategories = map[genai.HarmCategory]interfaces.ContentFilterCategory{
+	genai.HarmCategoryHateSpeech:            interfaces.ContentFilterCategoryHate,
+	genai.HarmCategoryImageHate:             interfaces.ContentFilterCategoryHate,
+	genai.HarmCategoryHarassment:            interfaces.ContentFilterCategoryHarassment,
+	genai.HarmCategoryImageHarassment:       interfaces.ContentFilterCategoryHarassment,
+	genai.HarmCategorySexuallyExplicit:      interfaces.ContentFilterCategorySexual,
+	genai.HarmCategoryImageSexuallyExplicit: interfaces.ContentFilterCategorySexual,
+	genai.HarmCategoryDangerousContent:      interfaces.ContentFilterCategoryDangerous,
+	genai.HarmCategoryImageDangerousContent: interfaces.ContentFilterCategoryDangerous,
+	genai.HarmCategoryCivicIntegrity:        interfaces.ContentFilterCategoryDangerous,
+	genai.HarmCategoryUnspecified:           interfaces.ContentFilterCategoryUnknown,
+}
+
+// contentFilterError returns a *interfaces.ContentFilterError when Gemini
+// blocked the prompt or stopped the first candidate for safety reasons, or nil
+func contentFilterError(result *genai.GenerateContentResponse) error {
+	if result == nil {
+		return nil
+	}
+
+	if feedback := result.PromptFeedback; feedback != nil && feedback.BlockReason != "" && feedback.BlockReason != genai.BlockedReasonUnspecified {
+		return &interfaces.ContentFilterError{
+			Provider:      "gemini",
+			Category:      blockedCategory(feedback.SafetyRatings),
+			Message:       feedback.BlockReasonMessage,
+			StopReason:    string(feedback.BlockReason),
+			PromptBlocked: true,
+		}
+	}
+
+	if len(result.Candidates) == 0 || result.Candidates[0] == nil {
+		return nil
+	}
+
+	candidate := result.Candidates[0]
+	var category interfaces.ContentFilterCategory
+	switch candidate.FinishReason {
+	case genai.FinishReasonSafety, genai.FinishReasonImageSafety:
+		category = blockedCategory(candidate.SafetyRatings)
+	case genai.FinishReasonRecitation:
+		category = interfaces.ContentFilterCategoryRecitation
+	case genai.FinishReasonBlocklist, genai.FinishReasonProhibitedContent,
+		genai.FinishReasonImageProhibitedContent, genai.FinishReasonSPII:
+		category = interfaces.ContentFilterCategoryDangerous
+	default:
+		return nil
+	}
+
+	return &interfaces.ContentFilterError{
+		Provider:   "gemini",
+		Category:   category,
+		Message:    candidate.FinishMessage,
+		StopReason: string(candidate.FinishReason),
+	}
+}
+
+// blockedCategory returns the category of the first blocked safety rating
+func blockedCategory(ratings []*genai.SafetyRating) interfaces.ContentFilterCategory {
+	for _, rating := range ratings {
+		if rating == nil || !rating.Blocked {
+			continue
+		}
+		if category, ok := harmCategories[rating.Category]; ok {
+			return category
+		}
+	}
+	return interfaces.ContentFilterCategoryUnknown
+}
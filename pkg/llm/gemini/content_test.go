@@ -0,0 +1,47 @@
+package gemini
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+func TestBuildContentsWithContentParts(t *testing.T) {
+	builder := newMessageHistoryBuilder(logging.New())
+	contents := builder.buildContents(context.Background(), "Describe", &interfaces.GenerateOptions{
+		ContentParts: []interfaces.ContentPart{
+			interfaces.ImagePart([]byte("png"), "image/png"),
+			{Type: interfaces.ContentPartFile, URL: "gs://bucket/report.pdf", MIMEType: "application/pdf"},
+		},
+	})
+	if len(contents) != 1 {
+		t.Fatalf("expected 1 content, got %d", len(contents))
+	}
+
+	parts := contents[0].Parts
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(parts))
+	}
+	if parts[0].Text != "Describe" {
+		t.Errorf("text part = %+v", parts[0])
+	}
+	if parts[1].InlineData == nil || parts[1].InlineData.MIMEType != "image/png" || string(parts[1].InlineData.Data) != "png" {
+		t.Errorf("inline part = %+v", parts[1])
+	}
+	if parts[2].FileData == nil || parts[2].FileData.FileURI != "gs://bucket/report.pdf" {
+		t.Errorf("file part = %+v", parts[2])
+	}
+}
+
+func TestConvertMemoryMessageWithContentParts(t *testing.T) {
+	builder := newMessageHistoryBuilder(logging.New())
+	content := builder.convertMemoryMessage(interfaces.Message{
+		Role:         interfaces.MessageRoleUser,
+		ContentParts: []interfaces.ContentPart{interfaces.AudioPart([]byte("wav"), "audio/wav")},
+	})
+	if content == nil || len(content.Parts) != 1 || content.Parts[0].InlineData == nil {
+		t.Fatalf("unexpected content %+v", content)
+	}
+}
@@ -108,6 +108,56 @@ func (c *GeminiClient) GenerateImage(ctx context.Context, request interfaces.Ima
 	return c.parseImageResponse(result, opts.OutputFormat)
 }
 
+// EditImage edits an image with a single-turn image edit session. Gemini has
+// no mask input, so the area to edit must be described in the prompt.
+func (c *GeminiClient) EditImage(ctx context.Context, request interfaces.ImageEditRequest) (*interfaces.ImageGenerationResponse, error) {
+	if request.Prompt == "" {
+		return nil, interfaces.ErrInvalidPrompt
+	}
+	if request.Image == nil {
+		return nil, fmt.Errorf("image is required")
+	}
+	if request.Mask != nil {
+		return nil, fmt.Errorf("%w: masks are not supported by Gemini, describe the area to edit in the prompt", interfaces.ErrImageGenerationNotSupported)
+	}
+
+	session, err := c.CreateImageEditSession(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = session.Close() }()
+
+	var options *interfaces.ImageEditOptions
+	if request.Options != nil && request.Options.AspectRatio != "" {
+		options = &interfaces.ImageEditOptions{AspectRatio: request.Options.AspectRatio}
+	}
+	edited, err := session.SendMessageWithImage(ctx, request.Prompt, request.Image, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(edited.Images) == 0 {
+		if edited.Text != "" {
+			return nil, fmt.Errorf("no images generated in response, model returned text: %s", edited.Text)
+		}
+		return nil, fmt.Errorf("no images generated in response")
+	}
+
+	response := &interfaces.ImageGenerationResponse{
+		Images:   edited.Images,
+		Usage:    edited.Usage,
+		Metadata: edited.Metadata,
+	}
+	if response.Metadata == nil {
+		response.Metadata = make(map[string]interface{})
+	}
+	if edited.Text != "" {
+		response.Metadata["text_response"] = edited.Text
+	}
+	return response, nil
+}
+
+var _ interfaces.ImageEditor = (*GeminiClient)(nil)
+
 // parseImageResponse extracts generated images from the API response
 func (c *GeminiClient) parseImageResponse(result *genai.GenerateContentResponse, outputFormat string) (*interfaces.ImageGenerationResponse, error) {
 	response := &interfaces.ImageGenerationResponse{
@@ -45,10 +45,7 @@ func (b *messageHistoryBuilder) buildContents(ctx context.Context, prompt string
 		}
 	} else {
 		// Only append current user message when memory is nil
-		contents = append(contents, &genai.Content{
-			Role:  "user",
-			Parts: []*genai.Part{{Text: prompt}},
-		})
+		contents = append(contents, userContent(prompt, params.ContentParts))
 	}
 
 	return contents
@@ -58,10 +55,7 @@ func (b *messageHistoryBuilder) buildContents(ctx context.Context, prompt string
 func (b *messageHistoryBuilder) convertMemoryMessage(msg interfaces.Message) *genai.Content {
 	switch msg.Role {
 	case interfaces.MessageRoleUser:
-		return &genai.Content{
-			Role:  "user",
-			Parts: []*genai.Part{{Text: msg.Content}},
-		}
+		return userContent(msg.Content, msg.ContentParts)
 
 	case interfaces.MessageRoleAssistant:
 		if len(msg.ToolCalls) > 0 {
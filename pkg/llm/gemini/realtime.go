@@ -0,0 +1,270 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// DefaultRealtimeModel is the model of Live API sessions when none is configured
+const DefaultRealtimeModel = "gemini-2.0-flash-live-001"
+
+// realtimeInputFormat is the input audio format of Live API sessions:
+// 16kHz 16-bit mono PCM. Output audio is 24kHz.
+const realtimeInputFormat = "audio/pcm;rate=16000"
+
+// RealtimeSession is a session with the Gemini Live API. Voice activity
+// detection runs on the server: the model responds when the user stops
+// speaking and is interrupted when the user starts speaking.
+type RealtimeSession struct {
+	session *genai.Session
+	events  chan interfaces.RealtimeEvent
+	// writeMu serializes writes; the connection allows one writer at a time
+	writeMu sync.Mutex
+
+	// sendMu serializes sending events and closing the events channel
+	sendMu     sync.Mutex
+	eventsDone bool
+	done       chan struct{}
+	closeOnce  sync.Once
+
+	// mu guards the state of the current response; interrupted drops the
+	// rest of the response after Interrupt
+	mu          sync.Mutex
+	responding  bool
+	interrupted bool
+}
+
+// ConnectRealtime opens a realtime session with the Gemini Live API
+func (c *GeminiClient) ConnectRealtime(ctx context.Context, config interfaces.RealtimeConfig) (interfaces.RealtimeSession, error) {
+	if c.genaiClient == nil {
+		return nil, fmt.Errorf("gemini client is not initialized")
+	}
+	model := config.Model
+	if model == "" {
+		model = DefaultRealtimeModel
+	}
+
+	session, err := c.genaiClient.Live.Connect(ctx, model, liveConnectConfig(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Gemini Live API: %w", err)
+	}
+
+	c.logger.Debug(ctx, "Connected realtime session", map[string]interface{}{"model": model})
+	s := &RealtimeSession{
+		session: session,
+		events:  make(chan interfaces.RealtimeEvent, 64),
+		done:    make(chan struct{}),
+	}
+	go s.receive()
+	return s, nil
+}
+
+// liveConnectConfig returns the Live API configuration for a config
+func liveConnectConfig(config interfaces.RealtimeConfig) *genai.LiveConnectConfig {
+	live := &genai.LiveConnectConfig{
+		ResponseModalities: []genai.Modality{genai.ModalityAudio},
+		// Transcripts of both sides of the conversation
+		InputAudioTranscription:  &genai.AudioTranscriptionConfig{},
+		OutputAudioTranscription: &genai.AudioTranscriptionConfig{},
+	}
+	if config.TextOnly {
+		live.ResponseModalities = []genai.Modality{genai.ModalityText}
+		live.OutputAudioTranscription = nil
+	}
+	if config.Instructions != "" {
+		live.SystemInstruction = genai.NewContentFromText(config.Instructions, genai.RoleUser)
+	}
+	if config.Voice != "" {
+		live.SpeechConfig = &genai.SpeechConfig{
+			VoiceConfig: &genai.VoiceConfig{
+				PrebuiltVoiceConfig: &genai.PrebuiltVoiceConfig{VoiceName: config.Voice},
+			},
+		}
+	}
+	if len(config.Tools) > 0 {
+		live.Tools = []*genai.Tool{{FunctionDeclarations: convertToolsToFunctionDeclarations(config.Tools)}}
+	}
+	return live
+}
+
+// SendAudio streams 16kHz 16-bit mono PCM audio
+func (s *RealtimeSession) SendAudio(ctx context.Context, audio []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.session.SendRealtimeInput(genai.LiveRealtimeInput{
+		Audio: &genai.Blob{Data: audio, MIMEType: realtimeInputFormat},
+	})
+}
+
+// SendText sends a user message and asks for a response
+func (s *RealtimeSession) SendText(ctx context.Context, text string) error {
+	turnComplete := true
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.session.SendClientContent(genai.LiveClientContentInput{
+		Turns:        []*genai.Content{genai.NewContentFromText(text, genai.RoleUser)},
+		TurnComplete: &turnComplete,
+	})
+}
+
+// SendToolResult returns the result of a function call to the model
+func (s *RealtimeSession) SendToolResult(ctx context.Context, result interfaces.ToolResult) error {
+	response := map[string]any{"output": result.Content}
+	if result.IsError {
+		response = map[string]any{"error": result.Content}
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.session.SendToolResponse(genai.LiveToolResponseInput{
+		FunctionResponses: []*genai.FunctionResponse{{
+			ID:       result.ToolCallID,
+			Name:     result.Name,
+			Response: response,
+		}},
+	})
+}
+
+// Interrupt cuts off the response in progress. The Live API has no request
+// to cancel a response, so the rest of the response is dropped instead.
+func (s *RealtimeSession) Interrupt(ctx context.Context) error {
+	s.mu.Lock()
+	responding := s.responding
+	s.interrupted = responding
+	s.mu.Unlock()
+	if !responding {
+		return nil
+	}
+	s.emit(interfaces.RealtimeEvent{Type: interfaces.RealtimeEventInterrupted})
+	return nil
+}
+
+// InputAudioFormat returns audio/pcm;rate=16000
+func (s *RealtimeSession) InputAudioFormat() string {
+	return realtimeInputFormat
+}
+
+// Events returns the events of the session
+func (s *RealtimeSession) Events() <-chan interfaces.RealtimeEvent {
+	return s.events
+}
+
+// Close ends the session
+func (s *RealtimeSession) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return s.session.Close()
+}
+
+// emit sends an event unless the session is closed. Events are sent by the
+// receive loop and by Interrupt, so sending is serialized with sendMu.
+func (s *RealtimeSession) emit(event interfaces.RealtimeEvent) {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	if s.eventsDone {
+		return
+	}
+	select {
+	case s.events <- event:
+	case <-s.done:
+	}
+}
+
+// receive converts server messages to realtime events until the connection closes
+func (s *RealtimeSession) receive() {
+	defer func() {
+		s.sendMu.Lock()
+		s.eventsDone = true
+		close(s.events)
+		s.sendMu.Unlock()
+	}()
+
+	for {
+		message, err := s.session.Receive()
+		if err != nil {
+			select {
+			case <-s.done:
+			default:
+				s.emit(interfaces.RealtimeEvent{Type: interfaces.RealtimeEventError, Error: fmt.Errorf("live connection failed: %w", err)})
+			}
+			return
+		}
+		for _, event := range s.convert(message) {
+			s.emit(event)
+		}
+	}
+}
+
+// convert converts a server message to realtime events
+func (s *RealtimeSession) convert(message *genai.LiveServerMessage) []interfaces.RealtimeEvent {
+	var events []interfaces.RealtimeEvent
+
+	if message.ToolCall != nil {
+		for _, call := range message.ToolCall.FunctionCalls {
+			arguments, err := json.Marshal(call.Args)
+			if err != nil {
+				events = append(events, interfaces.RealtimeEvent{Type: interfaces.RealtimeEventError, Error: fmt.Errorf("invalid arguments of %s: %w", call.Name, err)})
+				continue
+			}
+			events = append(events, interfaces.RealtimeEvent{
+				Type:     interfaces.RealtimeEventToolCall,
+				ToolCall: &interfaces.ToolCall{ID: call.ID, Name: call.Name, Arguments: string(arguments)},
+			})
+		}
+	}
+
+	content := message.ServerContent
+	if content == nil {
+		return events
+	}
+
+	if content.InputTranscription != nil && content.InputTranscription.Text != "" {
+		events = append(events, interfaces.RealtimeEvent{Type: interfaces.RealtimeEventInputTranscript, Text: content.InputTranscription.Text})
+	}
+
+	s.mu.Lock()
+	interrupted := s.interrupted
+	if content.ModelTurn != nil || content.OutputTranscription != nil {
+		s.responding = true
+	}
+	if content.TurnComplete || content.Interrupted {
+		s.responding, s.interrupted = false, false
+	}
+	s.mu.Unlock()
+
+	if content.Interrupted {
+		if !interrupted {
+			events = append(events, interfaces.RealtimeEvent{Type: interfaces.RealtimeEventInterrupted})
+		}
+		return events
+	}
+	if interrupted {
+		// Drop the rest of a response cut off by Interrupt
+		return events
+	}
+
+	if content.ModelTurn != nil {
+		for _, part := range content.ModelTurn.Parts {
+			switch {
+			case part.InlineData != nil:
+				events = append(events, interfaces.RealtimeEvent{Type: interfaces.RealtimeEventAudio, Audio: part.InlineData.Data, MIMEType: part.InlineData.MIMEType})
+			case part.Text != "" && !part.Thought:
+				events = append(events, interfaces.RealtimeEvent{Type: interfaces.RealtimeEventTranscript, Text: part.Text})
+			}
+		}
+	}
+	if content.OutputTranscription != nil && content.OutputTranscription.Text != "" {
+		events = append(events, interfaces.RealtimeEvent{Type: interfaces.RealtimeEventTranscript, Text: content.OutputTranscription.Text})
+	}
+	if content.TurnComplete {
+		events = append(events, interfaces.RealtimeEvent{Type: interfaces.RealtimeEventTurnComplete})
+	}
+	return events
+}
+
+var _ interfaces.RealtimeSession = (*RealtimeSession)(nil)
+var _ interfaces.RealtimeProvider = (*GeminiClient)(nil)
@@ -0,0 +1,94 @@
+package gemini
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestLiveConnectConfig(t *testing.T) {
+	config := liveConnectConfig(interfaces.RealtimeConfig{Instructions: "Be brief.", Voice: "Puck"})
+	if len(config.ResponseModalities) != 1 || config.ResponseModalities[0] != genai.ModalityAudio {
+		t.Errorf("expected audio responses, got %v", config.ResponseModalities)
+	}
+	if config.SystemInstruction == nil || config.SystemInstruction.Parts[0].Text != "Be brief." {
+		t.Errorf("unexpected system instruction %+v", config.SystemInstruction)
+	}
+	if config.SpeechConfig.VoiceConfig.PrebuiltVoiceConfig.VoiceName != "Puck" {
+		t.Errorf("unexpected speech config %+v", config.SpeechConfig)
+	}
+	if config.OutputAudioTranscription == nil {
+		t.Error("expected output transcription for audio responses")
+	}
+
+	text := liveConnectConfig(interfaces.RealtimeConfig{TextOnly: true})
+	if text.ResponseModalities[0] != genai.ModalityText || text.OutputAudioTranscription != nil {
+		t.Errorf("unexpected text config %+v", text)
+	}
+}
+
+func TestRealtimeConvert(t *testing.T) {
+	s := &RealtimeSession{events: make(chan interfaces.RealtimeEvent, 8), done: make(chan struct{})}
+
+	events := s.convert(&genai.LiveServerMessage{
+		ToolCall: &genai.LiveServerToolCall{FunctionCalls: []*genai.FunctionCall{
+			{ID: "call-1", Name: "weather", Args: map[string]any{"city": "Paris"}},
+		}},
+	})
+	if len(events) != 1 || events[0].ToolCall.ID != "call-1" || events[0].ToolCall.Arguments != `{"city":"Paris"}` {
+		t.Fatalf("unexpected tool call events %+v", events)
+	}
+
+	events = s.convert(&genai.LiveServerMessage{ServerContent: &genai.LiveServerContent{
+		InputTranscription: &genai.Transcription{Text: "What's the weather?"},
+		ModelTurn: &genai.Content{Parts: []*genai.Part{
+			{InlineData: &genai.Blob{Data: []byte{1, 2}, MIMEType: "audio/pcm;rate=24000"}},
+		}},
+		OutputTranscription: &genai.Transcription{Text: "It's sunny."},
+	}})
+	want := []interfaces.RealtimeEventType{interfaces.RealtimeEventInputTranscript, interfaces.RealtimeEventAudio, interfaces.RealtimeEventTranscript}
+	if len(events) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, events)
+	}
+	for i := range want {
+		if events[i].Type != want[i] {
+			t.Errorf("expected %v, got %+v", want, events)
+		}
+	}
+
+	// Interrupt drops the rest of the response until the turn completes
+	if err := s.Interrupt(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event := <-s.events; event.Type != interfaces.RealtimeEventInterrupted {
+		t.Errorf("expected an interrupted event, got %+v", event)
+	}
+	events = s.convert(&genai.LiveServerMessage{ServerContent: &genai.LiveServerContent{
+		ModelTurn: &genai.Content{Parts: []*genai.Part{{Text: "dropped"}}},
+	}})
+	if len(events) != 0 {
+		t.Errorf("expected the interrupted response to be dropped, got %+v", events)
+	}
+	if events = s.convert(&genai.LiveServerMessage{ServerContent: &genai.LiveServerContent{TurnComplete: true}}); len(events) != 0 {
+		t.Errorf("expected no events for the end of an interrupted turn, got %+v", events)
+	}
+
+	events = s.convert(&genai.LiveServerMessage{ServerContent: &genai.LiveServerContent{
+		ModelTurn:    &genai.Content{Parts: []*genai.Part{{Text: "Hello"}}},
+		TurnComplete: true,
+	}})
+	if len(events) != 2 || events[0].Text != "Hello" || events[1].Type != interfaces.RealtimeEventTurnComplete {
+		t.Errorf("unexpected events after interruption %+v", events)
+	}
+
+	// Interrupt without a response in progress is a no-op
+	if err := s.Interrupt(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.events) != 0 {
+		t.Errorf("expected no interrupted event without a response")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"google.golang.org/genai"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 )
 
@@ -74,10 +75,14 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 	if params.LLMConfig != nil {
 		genConfig = &genai.GenerationConfig{}
 
-		if params.LLMConfig.Temperature > 0 {
+		if params.LLMConfig.Temperature > 0 || params.LLMConfig.Deterministic {
 			temp := float32(params.LLMConfig.Temperature)
 			genConfig.Temperature = &temp
 		}
+		if params.LLMConfig.Seed != nil {
+			seed := int32(*params.LLMConfig.Seed)
+			genConfig.Seed = &seed
+		}
 		if params.LLMConfig.TopP > 0 {
 			topP := float32(params.LLMConfig.TopP)
 			genConfig.TopP = &topP
@@ -87,8 +92,8 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 		}
 	}
 
-	// Apply max output tokens if configured at client level
-	c.applyMaxOutputTokens(&genConfig)
+	// Apply max output tokens if configured for the request or client
+	c.applyMaxOutputTokens(&genConfig, params.LLMConfig)
 
 	// Set response format if provided
 	if params.ResponseFormat != nil {
@@ -119,6 +124,12 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 		if genConfig.Temperature != nil {
 			config.Temperature = genConfig.Temperature
 		}
+		if genConfig.Seed != nil {
+			config.Seed = genConfig.Seed
+		}
+		if genConfig.MaxOutputTokens > 0 {
+			config.MaxOutputTokens = genConfig.MaxOutputTokens
+		}
 		if genConfig.TopP != nil {
 			config.TopP = genConfig.TopP
 		}
@@ -182,7 +193,7 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string, option
 				select {
 				case eventCh <- interfaces.StreamEvent{
 					Type:      interfaces.StreamEventError,
-					Error:     err,
+					Error:     classifyError(err),
 					Timestamp: time.Now(),
 				}:
 				case <-ctx.Done():
@@ -282,11 +293,8 @@ func (c *GeminiClient) GenerateWithToolsStream(ctx context.Context, prompt strin
 		}
 	}
 
-	// Set default max iterations if not provided
-	maxIterations := params.MaxIterations
-	if maxIterations == 0 {
-		maxIterations = 2 // Default to current behavior
-	}
+	// Resolve the policy of the tool-calling loop
+	policy := params.ToolLoop()
 
 	// Get streaming config or use default
 	streamConfig := interfaces.DefaultStreamConfig()
@@ -313,11 +321,36 @@ func (c *GeminiClient) GenerateWithToolsStream(ctx context.Context, prompt strin
 		c.logger.Debug(ctx, "Starting streaming with tools with real-time events", map[string]interface{}{
 			"model":         c.model,
 			"tools":         len(tools),
-			"maxIterations": maxIterations,
+			"maxIterations": policy.MaxIterations,
 		})
 
-		// Execute the tool calling process with streaming events
-		response, err := c.generateWithToolsAndStream(ctx, prompt, tools, params, maxIterations, eventCh)
+		// Add system instruction if provided
+		var systemInstruction *genai.Content
+		if params.SystemMessage != "" {
+			systemInstruction = &genai.Content{
+				Parts: []*genai.Part{
+					{Text: params.SystemMessage},
+				},
+			}
+			c.logger.Debug(ctx, "Using system message for tool streaming", map[string]interface{}{"system_message": params.SystemMessage})
+		}
+
+		// Execute the tool calling process with streaming events, with the
+		// shared tool executor. The agent stores streamed tool calls in
+		// memory itself.
+		builder := newMessageHistoryBuilder(c.logger)
+		loop := &streamToolLoop{
+			client:            c,
+			params:            params,
+			maxIterations:     policy.MaxIterations,
+			events:            eventCh,
+			contents:          builder.buildContents(ctx, prompt, params),
+			systemInstruction: systemInstruction,
+			// Filter intermediate content for backward compatibility
+			filterIntermediateContent: params.StreamConfig == nil || !params.StreamConfig.IncludeIntermediateMessages,
+		}
+		executor := llm.NewToolExecutor(c.logger, llm.WithToolTimeout(params.ToolTimeout), llm.WithParallelToolCalls(params.ParallelToolCalls), llm.WithToolsFunc(params.ToolsFunc))
+		result, err := executor.Run(ctx, loop, tools, policy)
 		if err != nil {
 			// Send error event
 			select {
@@ -331,8 +364,14 @@ func (c *GeminiClient) GenerateWithToolsStream(ctx context.Context, prompt strin
 			return
 		}
 
-		// Stream the final response in chunks
-		c.streamResponse(ctx, response, eventCh)
+		// Replay the content captured during the tool iterations, and stream
+		// the result of a stop tool that ended the loop
+		if err := loop.replay(ctx); err != nil {
+			return
+		}
+		if result.Stopped {
+			c.streamResponse(ctx, result.Content, eventCh)
+		}
 
 		// Send content complete event
 		select {
@@ -355,385 +394,170 @@ func (c *GeminiClient) GenerateWithToolsStream(ctx context.Context, prompt strin
 		}
 
 		c.logger.Info(ctx, "Successfully completed streaming response with tools", map[string]interface{}{
-			"maxIterations": maxIterations,
+			"maxIterations": policy.MaxIterations,
 		})
 	}()
 
 	return eventCh, nil
 }
 
-// generateWithToolsAndStream executes tool calling with real-time streaming events
-func (c *GeminiClient) generateWithToolsAndStream(ctx context.Context, prompt string, tools []interfaces.Tool, params *interfaces.GenerateOptions, maxIterations int, eventCh chan interfaces.StreamEvent) (string, error) {
-	// Determine if we should filter intermediate content (for backward compatibility)
-	filterIntermediateContent := params.StreamConfig == nil || !params.StreamConfig.IncludeIntermediateMessages
-
-	// Track captured content for final iteration replay if filtering is enabled
-	var capturedContentEvents []interfaces.StreamEvent
-	// Track whether any tool was actually executed across iterations. Used to
-	// decide whether an iteration that returns no tool calls and no content
-	// should fall through to the final-synthesis call (forcing the model to
-	// answer from tool results) instead of returning an empty response.
-	var executedAnyTool bool
-	// Build tool map for quick lookup
-	toolMap := make(map[string]interfaces.Tool)
-	for _, tool := range tools {
-		toolMap[tool.Name()] = tool
-	}
-
-	// Track tool calls for clean loop continuation
-
-	// Build contents using unified builder
-	builder := newMessageHistoryBuilder(c.logger)
-	contents := builder.buildContents(ctx, prompt, params)
+// streamToolLoop streams the requests of GenerateWithToolsStream from the
+// GenerateContent API, for the shared tool-calling loop
+type streamToolLoop struct {
+	client            *GeminiClient
+	params            *interfaces.GenerateOptions
+	maxIterations     int
+	events            chan interfaces.StreamEvent
+	contents          []*genai.Content
+	systemInstruction *genai.Content
+
+	// filterIntermediateContent holds back the content of the tool
+	// iterations, captured to be replayed once the loop ended
+	filterIntermediateContent bool
+	captured                  []interfaces.StreamEvent
+}
 
-	// Add system instruction if provided
-	var systemInstruction *genai.Content
-	if params.SystemMessage != "" {
-		systemInstruction = &genai.Content{
-			Parts: []*genai.Part{
-				{Text: params.SystemMessage},
-			},
-		}
-		c.logger.Debug(ctx, "Using system message for tool streaming", map[string]interface{}{"system_message": params.SystemMessage})
-	}
+// Send implements llm.ToolLoopProvider.Send
+func (l *streamToolLoop) Send(ctx context.Context, iteration int, tools []interfaces.Tool) (llm.ToolLoopTurn, error) {
+	c := l.client
 
 	// Convert tools to Gemini format - all function declarations in a single tool.
 	// Shared with the non-streaming path so array `items` and other schema
 	// details stay consistent between Ask and Stream.
-	geminiTools := []*genai.Tool{
+	config := c.toolLoopConfig(ctx, l.params, l.systemInstruction, false)
+	config.Tools = []*genai.Tool{
 		{
 			FunctionDeclarations: convertToolsToFunctionDeclarations(tools),
 		},
 	}
 
-	// Main conversation loop with streaming events
-	for iteration := 0; iteration < maxIterations; iteration++ {
-		// Set generation config
-		var genConfig *genai.GenerationConfig
-		if params.LLMConfig != nil {
-			genConfig = &genai.GenerationConfig{}
-
-			if params.LLMConfig.Temperature > 0 {
-				temp := float32(params.LLMConfig.Temperature)
-				genConfig.Temperature = &temp
-			}
-			if params.LLMConfig.TopP > 0 {
-				topP := float32(params.LLMConfig.TopP)
-				genConfig.TopP = &topP
-			}
-			if len(params.LLMConfig.StopSequences) > 0 {
-				genConfig.StopSequences = params.LLMConfig.StopSequences
-			}
-		}
-
-		// Apply max output tokens if configured at client level
-		c.applyMaxOutputTokens(&genConfig)
-
-		// Create config
-		config := &genai.GenerateContentConfig{
-			SystemInstruction: systemInstruction,
-			Tools:             geminiTools,
-		}
-
-		// Apply generation config parameters
-		if genConfig != nil {
-			if genConfig.Temperature != nil {
-				config.Temperature = genConfig.Temperature
-			}
-			if genConfig.TopP != nil {
-				config.TopP = genConfig.TopP
-			}
-			if len(genConfig.StopSequences) > 0 {
-				config.StopSequences = genConfig.StopSequences
-			}
-		}
-
-		c.logger.Debug(ctx, "Sending request with tools for streaming", map[string]interface{}{
-			"contents":      len(contents),
-			"iteration":     iteration + 1,
-			"maxIterations": maxIterations,
-			"model":         c.model,
-			"tools":         len(tools),
-		})
-
-		// Execute streaming request and collect tool calls
-		shouldFilter := filterIntermediateContent && len(tools) > 0 && iteration < maxIterations-1
-		var iterationContentEvents []interfaces.StreamEvent
-		toolCalls, hasContent, err := c.executeStreamingRequestWithToolCapture(ctx, contents, config, eventCh, shouldFilter, &iterationContentEvents)
-		if err != nil {
-			return "", err
-		}
-
-		// If we had content during this iteration and tools were called, capture it for final replay
-		if shouldFilter && hasContent && len(toolCalls) > 0 {
-			capturedContentEvents = append(capturedContentEvents, iterationContentEvents...)
-		}
-
-		// If no tool calls, we're done with the iteration loop
-		if len(toolCalls) == 0 {
-			// The model produced no content AND no tool call, but tools ran in
-			// an earlier iteration: the model ended the turn without
-			// synthesizing the tool results into an answer (observed with
-			// Gemini on large tool outputs, where it emits a function call and
-			// then an empty candidate). Returning here yields an empty
-			// response even though the conversation holds tool results. Break
-			// instead so the final-call-without-tools below forces a textual
-			// answer. The maxIterations-exhaustion path already relies on that
-			// same synthesis call.
-			if !hasContent && executedAnyTool {
-				break
-			}
-			// No tool calls means we have received the final response content
-			// If content was filtered (captured), we need to replay it now
-			if shouldFilter && len(iterationContentEvents) > 0 {
-				for _, event := range iterationContentEvents {
-					select {
-					case eventCh <- event:
-					case <-ctx.Done():
-						return "", ctx.Err()
-					}
-				}
-			}
-			return "", nil
-		}
-
-		executedAnyTool = true
-
-		// Execute tools and add results to conversation
-		c.logger.Info(ctx, "Processing tool calls in streaming", map[string]interface{}{
-			"count":     len(toolCalls),
-			"iteration": iteration + 1,
-		})
-
-		// Add assistant message with tool calls
-		assistantMessage := &genai.Content{
-			Role:  "model",
-			Parts: []*genai.Part{},
-		}
-
-		// Convert tool calls to Gemini format and add to message
-		for _, toolCall := range toolCalls {
-			var args map[string]interface{}
-			if err := json.Unmarshal([]byte(toolCall.Arguments), &args); err != nil {
-				args = make(map[string]interface{})
-			}
-			assistantMessage.Parts = append(assistantMessage.Parts, &genai.Part{
-				ThoughtSignature: toolCall.ThoughtSignature,
-				FunctionCall: &genai.FunctionCall{
-					Name: toolCall.Name,
-					Args: args,
-				},
-			})
-		}
-
-		contents = append(contents, assistantMessage)
-
-		// Collect all tool results to add them in a single content message
-		var functionResponses []*genai.Part
-
-		// Execute each tool and collect results
-		for _, toolCall := range toolCalls {
-			// Find the requested tool
-			var selectedTool interfaces.Tool
-			for _, tool := range tools {
-				if tool.Name() == toolCall.Name {
-					selectedTool = tool
-					break
-				}
-			}
-
-			if selectedTool == nil {
-				c.logger.Error(ctx, "Tool not found in streaming", map[string]interface{}{
-					"toolName": toolCall.Name,
-				})
-
-				// Add tool not found error as function response
-				errorMessage := fmt.Sprintf("Error: tool not found: %s", toolCall.Name)
-				functionResponses = append(functionResponses, &genai.Part{
-					FunctionResponse: &genai.FunctionResponse{
-						Name: toolCall.Name,
-						Response: map[string]any{
-							"error": errorMessage,
-						},
-					},
-				})
-
-				// Send tool result event with error
-				select {
-				case eventCh <- interfaces.StreamEvent{
-					Type: interfaces.StreamEventToolResult,
-					ToolCall: &interfaces.ToolCall{
-						ID:        toolCall.ID,
-						Name:      toolCall.Name,
-						Arguments: toolCall.Arguments,
-					},
-					Content:   errorMessage,
-					Timestamp: time.Now(),
-				}:
-				case <-ctx.Done():
-					return "", ctx.Err()
-				}
-
-				continue // Continue processing other tool calls
-			}
-
-			// Execute tool
-			c.logger.Info(ctx, "Executing tool in streaming", map[string]interface{}{
-				"toolName":  toolCall.Name,
-				"arguments": toolCall.Arguments,
-				"iteration": iteration + 1,
-			})
-
-			toolResult, err := selectedTool.Execute(ctx, toolCall.Arguments)
-			if err != nil {
-				toolResult = fmt.Sprintf("Error: %v", err)
-			}
+	c.logger.Debug(ctx, "Sending request with tools for streaming", map[string]interface{}{
+		"contents":      len(l.contents),
+		"iteration":     iteration + 1,
+		"maxIterations": l.maxIterations,
+		"model":         c.model,
+		"tools":         len(tools),
+	})
 
-			// Add tool result as function response
-			functionResponses = append(functionResponses, &genai.Part{
-				FunctionResponse: &genai.FunctionResponse{
-					Name: toolCall.Name,
-					Response: map[string]any{
-						"result": toolResult,
-					},
-				},
-			})
+	// Execute streaming request and collect tool calls
+	shouldFilter := l.filterIntermediateContent && len(tools) > 0 && iteration < l.maxIterations-1
+	var iterationContentEvents []interfaces.StreamEvent
+	toolCalls, content, err := c.executeStreamingRequestWithToolCapture(ctx, l.contents, config, l.events, shouldFilter, &iterationContentEvents)
+	if err != nil {
+		return llm.ToolLoopTurn{}, err
+	}
+	turn := llm.ToolLoopTurn{Content: content, Calls: toolCalls}
 
-			// Send tool result event
+	// No tool calls means we have received the final response content. If
+	// content was filtered (captured), we need to replay it now.
+	if len(toolCalls) == 0 {
+		for _, event := range iterationContentEvents {
 			select {
-			case eventCh <- interfaces.StreamEvent{
-				Type: interfaces.StreamEventToolResult,
-				ToolCall: &interfaces.ToolCall{
-					ID:        toolCall.ID,
-					Name:      toolCall.Name,
-					Arguments: toolCall.Arguments,
-				},
-				Content:   toolResult, // Tool result goes in Content field
-				Timestamp: time.Now(),
-			}:
+			case l.events <- event:
 			case <-ctx.Done():
-				return "", ctx.Err()
+				return llm.ToolLoopTurn{}, ctx.Err()
 			}
 		}
+		return turn, nil
+	}
 
-		// Add all function responses in a single content message
-		if len(functionResponses) > 0 {
-			contents = append(contents, &genai.Content{
-				Role:  "user",
-				Parts: functionResponses,
-			})
-		}
+	// If we had content during this iteration and tools were called, capture it for final replay
+	if shouldFilter && content != "" {
+		l.captured = append(l.captured, iterationContentEvents...)
+	}
 
-		// Continue to next iteration with updated conversation
+	// Add assistant message with tool calls
+	assistantMessage := &genai.Content{
+		Role:  "model",
+		Parts: []*genai.Part{},
 	}
 
-	// Replay captured content events if we filtered them during iterations
-	if filterIntermediateContent && len(capturedContentEvents) > 0 {
-		c.logger.Debug(ctx, "Replaying captured content events from tool iterations", map[string]interface{}{
-			"eventsCount": len(capturedContentEvents),
+	// Convert tool calls to Gemini format and add to message
+	for _, toolCall := range toolCalls {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(toolCall.Arguments), &args); err != nil {
+			args = make(map[string]interface{})
+		}
+		assistantMessage.Parts = append(assistantMessage.Parts, &genai.Part{
+			ThoughtSignature: toolCall.ThoughtSignature,
+			FunctionCall: &genai.FunctionCall{
+				Name: toolCall.Name,
+				Args: args,
+			},
 		})
-		for _, contentEvent := range capturedContentEvents {
-			select {
-			case eventCh <- contentEvent:
-			case <-ctx.Done():
-				return "", ctx.Err()
-			}
-		}
 	}
 
-	// After all tool iterations, make a final call without tools to get the synthesized answer
-	// This ensures the LLM provides a final response after processing all tool results
+	l.contents = append(l.contents, assistantMessage)
+	return turn, nil
+}
 
-	// If DisableFinalSummary is enabled, skip the final synthesis call
-	if params.DisableFinalSummary {
-		c.logger.Info(ctx, "DisableFinalSummary enabled, skipping final synthesis call", map[string]interface{}{
-			"maxIterations": maxIterations,
-		})
-		return "", nil
+// AddResults implements llm.ToolLoopProvider.AddResults
+func (l *streamToolLoop) AddResults(ctx context.Context, iteration int, results []llm.ToolResult) error {
+	for _, result := range results {
+		// Send tool result event
+		select {
+		case l.events <- interfaces.StreamEvent{
+			Type: interfaces.StreamEventToolResult,
+			ToolCall: &interfaces.ToolCall{
+				ID:        result.Call.ID,
+				Name:      result.Call.Name,
+				Arguments: result.Call.Arguments,
+			},
+			Content:   result.Content, // Tool result goes in Content field
+			Timestamp: time.Now(),
+		}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
-	c.logger.Info(ctx, "Tool loop ended, making final call without tools to synthesize answer", map[string]interface{}{
-		"maxIterations": maxIterations,
-	})
+	// Add all function responses in a single content message
+	l.contents = appendFunctionResponses(l.contents, results)
+	return nil
+}
+
+// Conclude implements llm.ToolLoopProvider.Conclude
+func (l *streamToolLoop) Conclude(ctx context.Context, finalPrompt string) (string, error) {
+	if err := l.replay(ctx); err != nil {
+		return "", err
+	}
 
 	// Add a message to inform the LLM this is the final call
-	contents = append(contents, &genai.Content{
+	contents := append(l.contents, &genai.Content{
 		Role: "user",
 		Parts: []*genai.Part{
-			{Text: "Please provide your final response based on the information available. Do not request any additional functions."},
+			{Text: finalPrompt},
 		},
 	})
 
-	// Set generation config without tools
-	var genConfig *genai.GenerationConfig
-	if params.LLMConfig != nil {
-		genConfig = &genai.GenerationConfig{}
-
-		if params.LLMConfig.Temperature > 0 {
-			temp := float32(params.LLMConfig.Temperature)
-			genConfig.Temperature = &temp
-		}
-		if params.LLMConfig.TopP > 0 {
-			topP := float32(params.LLMConfig.TopP)
-			genConfig.TopP = &topP
-		}
-		if len(params.LLMConfig.StopSequences) > 0 {
-			genConfig.StopSequences = params.LLMConfig.StopSequences
-		}
+	// Execute final request to get synthesized answer using streaming (no
+	// filtering for final call). No tools in final request - we want a final
+	// answer.
+	config := l.client.toolLoopConfig(ctx, l.params, l.systemInstruction, true)
+	if _, _, err := l.client.executeStreamingRequestWithToolCapture(ctx, contents, config, l.events, false, nil); err != nil {
+		return "", classifyError(fmt.Errorf("failed to create final content: %w", err))
 	}
 
-	// Apply max output tokens if configured at client level
-	c.applyMaxOutputTokens(&genConfig)
-
-	// Add ResponseFormat if specified
-	if params.ResponseFormat != nil {
-		if genConfig == nil {
-			genConfig = &genai.GenerationConfig{}
-		}
-		genConfig.ResponseMIMEType = "application/json"
-
-		// Convert schema for genai
-		if schemaBytes, err := json.Marshal(params.ResponseFormat.Schema); err == nil {
-			var schema *genai.Schema
-			if err := json.Unmarshal(schemaBytes, &schema); err != nil {
-				c.logger.Warn(ctx, "Failed to convert response schema for final call", map[string]interface{}{"error": err.Error()})
-			} else {
-				genConfig.ResponseSchema = schema
-			}
-		}
-	}
+	// The final response was streamed to the events
+	return "", nil
+}
 
-	config := &genai.GenerateContentConfig{
-		SystemInstruction: systemInstruction,
-		// No tools in final request - we want a final answer
+// replay streams the content captured during the tool iterations
+func (l *streamToolLoop) replay(ctx context.Context) error {
+	if len(l.captured) > 0 {
+		l.client.logger.Debug(ctx, "Replaying captured content events from tool iterations", map[string]interface{}{
+			"eventsCount": len(l.captured),
+		})
 	}
-
-	// Apply generation config parameters
-	if genConfig != nil {
-		if genConfig.Temperature != nil {
-			config.Temperature = genConfig.Temperature
-		}
-		if genConfig.TopP != nil {
-			config.TopP = genConfig.TopP
-		}
-		if len(genConfig.StopSequences) > 0 {
-			config.StopSequences = genConfig.StopSequences
-		}
-		if genConfig.ResponseMIMEType != "" {
-			config.ResponseMIMEType = genConfig.ResponseMIMEType
-		}
-		if genConfig.ResponseSchema != nil {
-			config.ResponseSchema = genConfig.ResponseSchema
+	for _, contentEvent := range l.captured {
+		select {
+		case l.events <- contentEvent:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
-
-	// Execute final request to get synthesized answer using streaming (no filtering for final call)
-	_, _, err := c.executeStreamingRequestWithToolCapture(ctx, contents, config, eventCh, false, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create final content: %w", err)
-	}
-
-	return "", nil
+	l.captured = nil
+	return nil
 }
 
 // streamResponse streams a response string in chunks
@@ -762,7 +586,8 @@ func (c *GeminiClient) streamResponse(ctx context.Context, response string, even
 	}
 }
 
-// executeStreamingRequestWithToolCapture executes a streaming request and captures tool calls
+// executeStreamingRequestWithToolCapture executes a streaming request and
+// captures its tool calls and text content
 func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 	ctx context.Context,
 	contents []*genai.Content,
@@ -770,10 +595,10 @@ func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 	eventCh chan<- interfaces.StreamEvent,
 	filterContent bool,
 	capturedEvents *[]interfaces.StreamEvent,
-) ([]interfaces.ToolCall, bool, error) {
+) ([]interfaces.ToolCall, string, error) {
 
 	var toolCalls []interfaces.ToolCall
-	var hasContent bool
+	var content strings.Builder
 
 	c.logger.Debug(ctx, "Executing Gemini streaming request with tool capture", map[string]interface{}{
 		"model":         c.model,
@@ -800,7 +625,7 @@ func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 
 	for response, err := range streamIter {
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to generate content stream: %w", err)
+			return nil, "", classifyError(fmt.Errorf("failed to generate content stream: %w", err))
 		}
 
 		// Process each candidate in the response
@@ -830,7 +655,7 @@ func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 						ToolCall:  &toolCall,
 					}:
 					case <-ctx.Done():
-						return nil, false, ctx.Err()
+						return nil, "", ctx.Err()
 					}
 				} else if part.Text != "" {
 					// Check if this is thinking content
@@ -846,11 +671,11 @@ func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 							},
 						}:
 						case <-ctx.Done():
-							return nil, false, ctx.Err()
+							return nil, "", ctx.Err()
 						}
 					} else {
 						// This is content
-						hasContent = true
+						content.WriteString(part.Text)
 						contentEvent := interfaces.StreamEvent{
 							Type:      interfaces.StreamEventContentDelta,
 							Content:   part.Text,
@@ -865,7 +690,7 @@ func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 							select {
 							case eventCh <- contentEvent:
 							case <-ctx.Done():
-								return nil, false, ctx.Err()
+								return nil, "", ctx.Err()
 							}
 						}
 					}
@@ -874,5 +699,5 @@ func (c *GeminiClient) executeStreamingRequestWithToolCapture(
 		}
 	}
 
-	return toolCalls, hasContent, nil
+	return toolCalls, content.String(), nil
 }
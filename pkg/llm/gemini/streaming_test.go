@@ -2,10 +2,12 @@ package gemini
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 )
 
 // collectEvents drains every event from eventCh until it is closed.
@@ -132,3 +134,32 @@ func TestStreamResponse_StopsEarlyOnContextCancel(t *testing.T) {
 		t.Fatalf("received %d chunks; expected early stop with fewer than 4", received)
 	}
 }
+
+func TestGenerateWithToolsStream_ToolsFunc(t *testing.T) {
+	var requestTools [][]string
+	client := &GeminiClient{
+		model:       DefaultModel,
+		genaiClient: newToolsFuncServer(t, &requestTools),
+		logger:      logging.New(),
+	}
+
+	// The tools change after the first iteration
+	toolsFunc := func() []interfaces.Tool {
+		return []interfaces.Tool{&MockTool{name: "test_tool_2", description: "Test tool 2"}}
+	}
+	eventCh, err := client.GenerateWithToolsStream(context.Background(), "test prompt",
+		[]interfaces.Tool{&MockTool{name: "test_tool_1", description: "Test tool 1"}},
+		interfaces.WithToolsFunc(toolsFunc))
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+	for _, event := range collectEvents(eventCh) {
+		if event.Type == interfaces.StreamEventError {
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		}
+	}
+
+	if fmt.Sprint(requestTools) != "[[test_tool_1] [test_tool_2]]" {
+		t.Errorf("Expected the second request to send the current tools, got %v", requestTools)
+	}
+}
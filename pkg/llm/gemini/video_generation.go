@@ -0,0 +1,151 @@
+package gemini
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// DefaultVideoModel is the Veo model used when the client's model is not a video model
+const DefaultVideoModel = "veo-3.0-generate-001"
+
+// videoModel returns the client's model when it is a Veo model, otherwise DefaultVideoModel
+func (c *GeminiClient) videoModel() string {
+	if strings.HasPrefix(c.model, "veo-") {
+		return c.model
+	}
+	return DefaultVideoModel
+}
+
+// StartVideoGeneration starts generating a video with Veo. It works with both
+// the Gemini API and Vertex AI backends.
+func (c *GeminiClient) StartVideoGeneration(ctx context.Context, request interfaces.VideoGenerationRequest) (*interfaces.VideoGenerationJob, error) {
+	if c.genaiClient == nil {
+		return nil, fmt.Errorf("gemini client is not initialized")
+	}
+	if request.Prompt == "" {
+		return nil, interfaces.ErrInvalidPrompt
+	}
+
+	var image *genai.Image
+	if request.ReferenceImage != nil {
+		data := request.ReferenceImage.Data
+		if data == nil && request.ReferenceImage.Base64 != "" {
+			decoded, err := base64.StdEncoding.DecodeString(request.ReferenceImage.Base64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode reference image: %w", err)
+			}
+			data = decoded
+		}
+		mimeType := request.ReferenceImage.MimeType
+		if mimeType == "" {
+			mimeType = "image/png"
+		}
+		image = &genai.Image{ImageBytes: data, MIMEType: mimeType}
+	}
+
+	model := c.videoModel()
+	c.logger.Debug(ctx, "Starting video generation", map[string]interface{}{"model": model})
+
+	operation, err := c.genaiClient.Models.GenerateVideos(ctx, model, request.Prompt, image, videoConfig(request.Options))
+	if err != nil {
+		return nil, fmt.Errorf("video generation failed: %w", err)
+	}
+	return &interfaces.VideoGenerationJob{ID: operation.Name, Metadata: map[string]interface{}{"model": model}}, nil
+}
+
+// videoConfig converts video generation options to a Veo configuration
+func videoConfig(opts *interfaces.VideoGenerationOptions) *genai.GenerateVideosConfig {
+	if opts == nil {
+		opts = interfaces.DefaultVideoGenerationOptions()
+	}
+	config := &genai.GenerateVideosConfig{
+		NumberOfVideos: 1,
+		AspectRatio:    opts.AspectRatio,
+		Resolution:     opts.Resolution,
+		NegativePrompt: opts.NegativePrompt,
+	}
+	if opts.DurationSeconds > 0 {
+		// #nosec G115 - durations are a few seconds
+		duration := int32(opts.DurationSeconds)
+		config.DurationSeconds = &duration
+	}
+	return config
+}
+
+// PollVideoGeneration returns the state of a Veo operation. Videos the Gemini
+// API returns as file URIs are downloaded; Cloud Storage (gs://) URIs are kept.
+func (c *GeminiClient) PollVideoGeneration(ctx context.Context, job *interfaces.VideoGenerationJob) (*interfaces.VideoGenerationJob, error) {
+	if c.genaiClient == nil {
+		return nil, fmt.Errorf("gemini client is not initialized")
+	}
+
+	operation, err := c.genaiClient.Operations.GetVideosOperation(ctx, &genai.GenerateVideosOperation{Name: job.ID}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video generation status: %w", err)
+	}
+
+	result, err := videoJob(operation)
+	if err != nil || !result.Done {
+		return result, err
+	}
+
+	for i, video := range result.Videos {
+		if len(video.Data) > 0 || video.URL == "" || strings.HasPrefix(video.URL, "gs://") {
+			continue
+		}
+		data, err := c.genaiClient.Files.Download(ctx, genai.NewDownloadURIFromVideo(&genai.Video{URI: video.URL}), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download video: %w", err)
+		}
+		result.Videos[i].Data = data
+		result.Videos[i].Base64 = base64.StdEncoding.EncodeToString(data)
+	}
+	return result, nil
+}
+
+// videoJob converts a Veo operation to a job. Videos without bytes keep
+// their URI, e.g. a gs:// URI on Vertex AI.
+func videoJob(operation *genai.GenerateVideosOperation) (*interfaces.VideoGenerationJob, error) {
+	job := &interfaces.VideoGenerationJob{ID: operation.Name, Done: operation.Done, Metadata: operation.Metadata}
+	if !operation.Done {
+		return job, nil
+	}
+	if operation.Error != nil {
+		return nil, fmt.Errorf("video generation failed: %v", operation.Error["message"])
+	}
+
+	response := operation.Response
+	if response == nil || len(response.GeneratedVideos) == 0 {
+		if response != nil && response.RAIMediaFilteredCount > 0 {
+			return nil, fmt.Errorf("%w: %s", interfaces.ErrContentBlocked, strings.Join(response.RAIMediaFilteredReasons, "; "))
+		}
+		return nil, fmt.Errorf("no videos were generated")
+	}
+
+	for _, generated := range response.GeneratedVideos {
+		if generated.Video == nil {
+			continue
+		}
+		video := interfaces.GeneratedMedia{
+			Data:     generated.Video.VideoBytes,
+			MimeType: generated.Video.MIMEType,
+			URL:      generated.Video.URI,
+		}
+		if video.MimeType == "" {
+			video.MimeType = "video/mp4"
+		}
+		if len(video.Data) > 0 {
+			video.Base64 = base64.StdEncoding.EncodeToString(video.Data)
+		}
+		job.Videos = append(job.Videos, video)
+	}
+	return job, nil
+}
+
+var _ interfaces.VideoGenerator = (*GeminiClient)(nil)
@@ -0,0 +1,63 @@
+package gemini
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestVideoConfig(t *testing.T) {
+	config := videoConfig(&interfaces.VideoGenerationOptions{AspectRatio: "9:16", DurationSeconds: 6, NegativePrompt: "rain"})
+	if config.AspectRatio != "9:16" || config.NegativePrompt != "rain" || config.NumberOfVideos != 1 {
+		t.Errorf("unexpected config %+v", config)
+	}
+	if config.DurationSeconds == nil || *config.DurationSeconds != 6 {
+		t.Errorf("expected a duration of 6 seconds")
+	}
+
+	config = videoConfig(nil)
+	if config.AspectRatio != "16:9" || config.Resolution != "720p" || *config.DurationSeconds != 8 {
+		t.Errorf("expected default options, got %+v", config)
+	}
+}
+
+func TestVideoJob(t *testing.T) {
+	job, err := videoJob(&genai.GenerateVideosOperation{Name: "operations/1"})
+	if err != nil || job.Done || job.ID != "operations/1" {
+		t.Errorf("expected an unfinished job, got %+v, %v", job, err)
+	}
+
+	job, err = videoJob(&genai.GenerateVideosOperation{
+		Name: "operations/1",
+		Done: true,
+		Response: &genai.GenerateVideosResponse{GeneratedVideos: []*genai.GeneratedVideo{
+			{Video: &genai.Video{VideoBytes: []byte("mp4")}},
+			{Video: &genai.Video{URI: "gs://bucket/video.mp4", MIMEType: "video/webm"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(job.Videos) != 2 || job.Videos[0].MimeType != "video/mp4" || job.Videos[0].Base64 != "bXA0" {
+		t.Errorf("unexpected videos %+v", job.Videos)
+	}
+	if job.Videos[1].URL != "gs://bucket/video.mp4" || job.Videos[1].MimeType != "video/webm" {
+		t.Errorf("expected the Cloud Storage URI to be kept, got %+v", job.Videos[1])
+	}
+
+	_, err = videoJob(&genai.GenerateVideosOperation{Done: true, Error: map[string]any{"message": "quota exceeded"}})
+	if err == nil {
+		t.Error("expected an error for a failed operation")
+	}
+
+	_, err = videoJob(&genai.GenerateVideosOperation{
+		Done:     true,
+		Response: &genai.GenerateVideosResponse{RAIMediaFilteredCount: 1, RAIMediaFilteredReasons: []string{"unsafe"}},
+	})
+	if !errors.Is(err, interfaces.ErrContentBlocked) {
+		t.Errorf("expected ErrContentBlocked, got %v", err)
+	}
+}
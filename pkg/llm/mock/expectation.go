@@ -0,0 +1,227 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Matcher decides whether an expectation answers a request
+type Matcher interface {
+	Match(request Request) bool
+	String() string
+}
+
+// MatcherFunc adapts a function to a Matcher
+type MatcherFunc func(request Request) bool
+
+// Match implements Matcher.Match
+func (f MatcherFunc) Match(request Request) bool {
+	return f(request)
+}
+
+// String implements Matcher.String
+func (f MatcherFunc) String() string {
+	return "custom matcher"
+}
+
+type matcher struct {
+	match       func(request Request) bool
+	description string
+}
+
+func (m matcher) Match(request Request) bool {
+	return m.match(request)
+}
+
+func (m matcher) String() string {
+	return m.description
+}
+
+// Any matches every request
+func Any() Matcher {
+	return matcher{
+		match:       func(Request) bool { return true },
+		description: "any request",
+	}
+}
+
+// PromptEquals matches requests whose prompt is exactly prompt
+func PromptEquals(prompt string) Matcher {
+	return matcher{
+		match:       func(r Request) bool { return r.Prompt == prompt },
+		description: fmt.Sprintf("prompt equal to %q", prompt),
+	}
+}
+
+// PromptContains matches requests whose prompt contains substr
+func PromptContains(substr string) Matcher {
+	return matcher{
+		match:       func(r Request) bool { return strings.Contains(r.Prompt, substr) },
+		description: fmt.Sprintf("prompt containing %q", substr),
+	}
+}
+
+// PromptMatches matches requests whose prompt matches the regular expression
+// pattern. It panics if pattern does not compile.
+func PromptMatches(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+	return matcher{
+		match:       func(r Request) bool { return re.MatchString(r.Prompt) },
+		description: fmt.Sprintf("prompt matching %q", pattern),
+	}
+}
+
+// SystemMessageContains matches requests whose system message contains substr
+func SystemMessageContains(substr string) Matcher {
+	return matcher{
+		match:       func(r Request) bool { return strings.Contains(r.SystemMessage, substr) },
+		description: fmt.Sprintf("system message containing %q", substr),
+	}
+}
+
+// WithTool matches requests that were given the named tool
+func WithTool(name string) Matcher {
+	return matcher{
+		match:       func(r Request) bool { return r.HasTool(name) },
+		description: fmt.Sprintf("tool %q available", name),
+	}
+}
+
+// All matches requests accepted by every matcher
+func All(matchers ...Matcher) Matcher {
+	descriptions := make([]string, len(matchers))
+	for i, m := range matchers {
+		descriptions[i] = m.String()
+	}
+	return matcher{
+		match: func(r Request) bool {
+			for _, m := range matchers {
+				if !m.Match(r) {
+					return false
+				}
+			}
+			return true
+		},
+		description: strings.Join(descriptions, " and "),
+	}
+}
+
+// Expectation is a scripted answer to the requests accepted by a matcher
+type Expectation struct {
+	client    *Client
+	matcher   Matcher
+	text      string
+	respond   func(request Request, results []ToolResult) string
+	toolCalls []toolCall
+	events    []interfaces.StreamEvent
+	err       error
+	times     int
+	calls     int
+}
+
+type toolCall struct {
+	name      string
+	arguments string
+}
+
+// Respond sets the response content
+func (e *Expectation) Respond(content string) *Expectation {
+	e.client.mu.Lock()
+	defer e.client.mu.Unlock()
+	e.text = content
+	return e
+}
+
+// RespondFunc computes the response content from the request and the
+// results of the expectation's tool calls
+func (e *Expectation) RespondFunc(respond func(request Request, results []ToolResult) string) *Expectation {
+	e.client.mu.Lock()
+	defer e.client.mu.Unlock()
+	e.respond = respond
+	return e
+}
+
+// CallTool makes the mock call the named tool with arguments (JSON) before
+// responding. Calls are made in the order they were added; a call to a tool
+// that was not passed to the request fails it.
+func (e *Expectation) CallTool(name, arguments string) *Expectation {
+	e.client.mu.Lock()
+	defer e.client.mu.Unlock()
+	e.toolCalls = append(e.toolCalls, toolCall{name: name, arguments: arguments})
+	return e
+}
+
+// Stream sets the events returned by streaming calls, replacing the events
+// built from the tool calls and the response
+func (e *Expectation) Stream(events ...interfaces.StreamEvent) *Expectation {
+	e.client.mu.Lock()
+	defer e.client.mu.Unlock()
+	e.events = events
+	return e
+}
+
+// Fail makes the matched calls return err
+func (e *Expectation) Fail(err error) *Expectation {
+	e.client.mu.Lock()
+	defer e.client.mu.Unlock()
+	e.err = err
+	return e
+}
+
+// Once limits the expectation to a single call
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+// Times limits the expectation to n calls. ExpectationsMet reports
+// expectations with a limit that were called fewer times. Without a limit
+// an expectation answers any number of calls.
+func (e *Expectation) Times(n int) *Expectation {
+	e.client.mu.Lock()
+	defer e.client.mu.Unlock()
+	e.times = n
+	return e
+}
+
+func (e *Expectation) exhausted() bool {
+	return e.times > 0 && e.calls >= e.times
+}
+
+func (e *Expectation) describe() string {
+	return e.matcher.String()
+}
+
+func (e *Expectation) response(request Request, results []ToolResult) string {
+	if e.respond != nil {
+		return e.respond(request, results)
+	}
+	return e.text
+}
+
+// runTools executes the expectation's tool calls in order
+func (e *Expectation) runTools(ctx context.Context, request Request, tools []interfaces.Tool) ([]ToolResult, error) {
+	var results []ToolResult
+	for _, call := range e.toolCalls {
+		if !request.HasTool(call.name) {
+			return results, fmt.Errorf("mock: tool %q was not passed to %s", call.name, request.Method)
+		}
+		results = append(results, execute(ctx, call, tools))
+	}
+	return results, nil
+}
+
+func execute(ctx context.Context, call toolCall, tools []interfaces.Tool) ToolResult {
+	result := ToolResult{Name: call.name, Arguments: call.arguments}
+	for _, tool := range tools {
+		if tool.Name() == call.name {
+			result.Result, result.Error = tool.Execute(ctx, call.arguments)
+			return result
+		}
+	}
+	result.Error = fmt.Errorf("tool %q not found", call.name)
+	return result
+}
@@ -0,0 +1,392 @@
+// Package mock provides a scriptable LLM for unit testing agents.
+//
+// Expectations pair a matcher with a canned response, tool calls or stream
+// events. Each call is answered by the first expectation that matches it and
+// has calls left, so a conversation can be scripted turn by turn:
+//
+//	llm := mock.New()
+//	llm.On(mock.PromptContains("weather")).
+//		CallTool("get_weather", `{"city":"Paris"}`).
+//		Respond("It is sunny in Paris").
+//		Once()
+//	llm.On(mock.Any()).Respond("I can only talk about the weather")
+//
+// Like a real provider, the client executes the tool calls of an expectation
+// with the tools passed to GenerateWithTools before responding, so the agent's
+// tool wrappers (tracking, guardrails, tracing) run as they would in
+// production.
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ErrUnexpectedCall is returned for calls no expectation matches
+var ErrUnexpectedCall = errors.New("mock: unexpected call")
+
+// Request describes a call made to the mock
+type Request struct {
+	// Method is the LLM method that was called, e.g. "Generate" or "GenerateWithToolsStream"
+	Method string
+
+	// Prompt is the prompt passed to the call
+	Prompt string
+
+	// SystemMessage is the system message set by the generate options
+	SystemMessage string
+
+	// Tools are the names of the tools passed to the call
+	Tools []string
+
+	// Options are the resolved generate options
+	Options *interfaces.GenerateOptions
+}
+
+// HasTool returns true if a tool with the given name was passed to the call
+func (r Request) HasTool(name string) bool {
+	for _, tool := range r.Tools {
+		if tool == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolResult is the outcome of a tool call made by the mock
+type ToolResult struct {
+	Name      string
+	Arguments string
+	Result    string
+	Error     error
+}
+
+// Call records a call made to the mock and how it was answered
+type Call struct {
+	Request     Request
+	Response    string
+	ToolResults []ToolResult
+	Error       error
+}
+
+// Client implements interfaces.StreamingLLM with scripted responses
+type Client struct {
+	name  string
+	model string
+	usage *interfaces.TokenUsage
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+}
+
+// Option represents an option for configuring the mock client
+type Option func(*Client)
+
+// WithName sets the provider name returned by Name (default: "mock")
+func WithName(name string) Option {
+	return func(c *Client) {
+		c.name = name
+	}
+}
+
+// WithModel sets the model returned by GetModel and detailed responses (default: "mock-model")
+func WithModel(model string) Option {
+	return func(c *Client) {
+		c.model = model
+	}
+}
+
+// WithUsage sets the token usage reported by detailed responses
+func WithUsage(usage interfaces.TokenUsage) Option {
+	return func(c *Client) {
+		c.usage = &usage
+	}
+}
+
+// New creates a mock client without expectations
+func New(options ...Option) *Client {
+	c := &Client{
+		name:  "mock",
+		model: "mock-model",
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// On adds an expectation for calls accepted by matcher. Expectations are
+// tried in the order they were added.
+func (c *Client) On(matcher Matcher) *Expectation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &Expectation{matcher: matcher, client: c}
+	c.expectations = append(c.expectations, e)
+	return e
+}
+
+// Calls returns the calls made so far
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+// Prompts returns the prompts of the calls made so far
+func (c *Client) Prompts() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prompts := make([]string, len(c.calls))
+	for i, call := range c.calls {
+		prompts[i] = call.Request.Prompt
+	}
+	return prompts
+}
+
+// ExpectationsMet returns an error describing the expectations that were
+// called fewer times than required by Once or Times
+func (c *Client) ExpectationsMet() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var missing []string
+	for _, e := range c.expectations {
+		if e.times > 0 && e.calls < e.times {
+			missing = append(missing, fmt.Sprintf("%s (called %d of %d times)", e.describe(), e.calls, e.times))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("mock: expectations not met: %s", strings.Join(missing, "; "))
+	}
+	return nil
+}
+
+// Reset removes all expectations and recorded calls
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expectations = nil
+	c.calls = nil
+}
+
+// Generate implements interfaces.LLM.Generate
+func (c *Client) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	response, err := c.generate(ctx, "Generate", prompt, nil, options)
+	return content(response), err
+}
+
+// GenerateWithTools implements interfaces.LLM.GenerateWithTools
+func (c *Client) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	response, err := c.generate(ctx, "GenerateWithTools", prompt, tools, options)
+	return content(response), err
+}
+
+// GenerateDetailed implements interfaces.LLM.GenerateDetailed
+func (c *Client) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return c.generate(ctx, "GenerateDetailed", prompt, nil, options)
+}
+
+// GenerateWithToolsDetailed implements interfaces.LLM.GenerateWithToolsDetailed
+func (c *Client) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return c.generate(ctx, "GenerateWithToolsDetailed", prompt, tools, options)
+}
+
+// GenerateStream implements interfaces.StreamingLLM.GenerateStream
+func (c *Client) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return c.stream(ctx, "GenerateStream", prompt, nil, options)
+}
+
+// GenerateWithToolsStream implements interfaces.StreamingLLM.GenerateWithToolsStream
+func (c *Client) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return c.stream(ctx, "GenerateWithToolsStream", prompt, tools, options)
+}
+
+// Name implements interfaces.LLM.Name
+func (c *Client) Name() string {
+	return c.name
+}
+
+// SupportsStreaming implements interfaces.LLM.SupportsStreaming
+func (c *Client) SupportsStreaming() bool {
+	return true
+}
+
+// GetModel returns the configured model name
+func (c *Client) GetModel() string {
+	return c.model
+}
+
+// generate answers a non-streaming call
+func (c *Client) generate(ctx context.Context, method, prompt string, tools []interfaces.Tool, options []interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	request := newRequest(method, prompt, tools, options)
+	e, err := c.match(request)
+	if err != nil {
+		c.record(Call{Request: request, Error: err})
+		return nil, err
+	}
+
+	results, err := e.runTools(ctx, request, tools)
+	if err == nil {
+		err = e.err
+	}
+	if err != nil {
+		c.record(Call{Request: request, ToolResults: results, Error: err})
+		return nil, err
+	}
+
+	text := e.response(request, results)
+	c.record(Call{Request: request, Response: text, ToolResults: results})
+
+	return &interfaces.LLMResponse{
+		Content:    text,
+		Model:      c.model,
+		StopReason: "stop",
+		Usage:      c.usage,
+	}, nil
+}
+
+// stream answers a streaming call with the scripted events or, without
+// them, with events built from the tool calls and the response
+func (c *Client) stream(ctx context.Context, method, prompt string, tools []interfaces.Tool, options []interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	request := newRequest(method, prompt, tools, options)
+	e, err := c.match(request)
+	if err != nil {
+		c.record(Call{Request: request, Error: err})
+		return nil, err
+	}
+	if e.err != nil && len(e.toolCalls) == 0 && len(e.events) == 0 {
+		c.record(Call{Request: request, Error: e.err})
+		return nil, e.err
+	}
+
+	events := make(chan interfaces.StreamEvent, 16)
+	go func() {
+		defer close(events)
+
+		send := func(event interfaces.StreamEvent) bool {
+			event.Timestamp = time.Now()
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if len(e.events) > 0 {
+			var text strings.Builder
+			for _, event := range e.events {
+				if event.Type == interfaces.StreamEventContentDelta {
+					text.WriteString(event.Content)
+				}
+				if !send(event) {
+					break
+				}
+			}
+			c.record(Call{Request: request, Response: text.String(), Error: e.err})
+			return
+		}
+
+		if !send(interfaces.StreamEvent{Type: interfaces.StreamEventMessageStart}) {
+			return
+		}
+
+		var results []ToolResult
+		for i, toolCall := range e.toolCalls {
+			call := &interfaces.ToolCall{ID: fmt.Sprintf("call_%d", i+1), Name: toolCall.name, Arguments: toolCall.arguments}
+			if !send(interfaces.StreamEvent{Type: interfaces.StreamEventToolUse, ToolCall: call}) {
+				return
+			}
+			result := execute(ctx, toolCall, tools)
+			results = append(results, result)
+			event := interfaces.StreamEvent{Type: interfaces.StreamEventToolResult, ToolCall: call, Content: result.Result}
+			if result.Error != nil {
+				event.Content = fmt.Sprintf("Error: %v", result.Error)
+			}
+			if !send(event) {
+				return
+			}
+		}
+
+		if e.err != nil {
+			c.record(Call{Request: request, ToolResults: results, Error: e.err})
+			send(interfaces.StreamEvent{Type: interfaces.StreamEventError, Error: e.err})
+			return
+		}
+
+		text := e.response(request, results)
+		c.record(Call{Request: request, Response: text, ToolResults: results})
+		if text != "" && !send(interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: text}) {
+			return
+		}
+		send(interfaces.StreamEvent{Type: interfaces.StreamEventMessageStop})
+	}()
+
+	return events, nil
+}
+
+// match returns the first expectation accepting request that has calls left
+func (c *Client) match(request Request) (*Expectation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range c.expectations {
+		if e.exhausted() || !e.matcher.Match(request) {
+			continue
+		}
+		e.calls++
+		return e, nil
+	}
+	return nil, fmt.Errorf("%w: %s %q", ErrUnexpectedCall, request.Method, truncate(request.Prompt, 80))
+}
+
+func (c *Client) record(call Call) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, call)
+}
+
+func newRequest(method, prompt string, tools []interfaces.Tool, options []interfaces.GenerateOption) Request {
+	params := &interfaces.GenerateOptions{LLMConfig: &interfaces.LLMConfig{}}
+	for _, option := range options {
+		if option != nil {
+			option(params)
+		}
+	}
+
+	request := Request{
+		Method:        method,
+		Prompt:        prompt,
+		SystemMessage: params.SystemMessage,
+		Options:       params,
+	}
+	for _, tool := range tools {
+		request.Tools = append(request.Tools, tool.Name())
+	}
+	return request
+}
+
+func content(response *interfaces.LLMResponse) string {
+	if response == nil {
+		return ""
+	}
+	return response.Content
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
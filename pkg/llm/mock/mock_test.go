@@ -0,0 +1,184 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+type echoTool struct {
+	name  string
+	calls []string
+}
+
+func (t *echoTool) Name() string        { return t.name }
+func (t *echoTool) Description() string { return "Echoes its arguments" }
+func (t *echoTool) Parameters() map[string]interfaces.ParameterSpec {
+	return nil
+}
+func (t *echoTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (t *echoTool) Execute(ctx context.Context, args string) (string, error) {
+	t.calls = append(t.calls, args)
+	return "echo " + args, nil
+}
+
+func TestExpectationsAreMatchedInOrder(t *testing.T) {
+	llm := New()
+	llm.On(PromptContains("hello")).Respond("first").Once()
+	llm.On(PromptContains("hello")).Respond("second")
+	llm.On(SystemMessageContains("pirate")).Respond("arr")
+
+	ctx := context.Background()
+	for _, expected := range []string{"first", "second", "second"} {
+		if got, err := llm.Generate(ctx, "hello there"); err != nil || got != expected {
+			t.Errorf("expected %q, got %q (%v)", expected, got, err)
+		}
+	}
+	if got, _ := llm.Generate(ctx, "ahoy", interfaces.WithSystemMessage("You are a pirate")); got != "arr" {
+		t.Errorf("expected the system message expectation, got %q", got)
+	}
+
+	if _, err := llm.Generate(ctx, "unknown"); !errors.Is(err, ErrUnexpectedCall) {
+		t.Errorf("expected ErrUnexpectedCall, got %v", err)
+	}
+	if len(llm.Calls()) != 5 {
+		t.Errorf("expected 5 recorded calls, got %d", len(llm.Calls()))
+	}
+	if prompts := llm.Prompts(); prompts[3] != "ahoy" {
+		t.Errorf("unexpected prompts %v", prompts)
+	}
+}
+
+func TestExpectationsMet(t *testing.T) {
+	llm := New()
+	llm.On(PromptEquals("a")).Respond("1").Times(2)
+	llm.On(Any()).Respond("fallback")
+
+	_, _ = llm.Generate(context.Background(), "a")
+	err := llm.ExpectationsMet()
+	if err == nil || !strings.Contains(err.Error(), `prompt equal to "a" (called 1 of 2 times)`) {
+		t.Fatalf("expected an unmet expectation, got %v", err)
+	}
+
+	_, _ = llm.Generate(context.Background(), "a")
+	if err := llm.ExpectationsMet(); err != nil {
+		t.Errorf("expected expectations to be met, got %v", err)
+	}
+	if got, _ := llm.Generate(context.Background(), "a"); got != "fallback" {
+		t.Errorf("expected the fallback once the expectation is exhausted, got %q", got)
+	}
+}
+
+func TestToolCalls(t *testing.T) {
+	tool := &echoTool{name: "echo"}
+	llm := New(WithUsage(interfaces.TokenUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}))
+	llm.On(All(PromptMatches(`^echo`), WithTool("echo"))).
+		CallTool("echo", `{"text":"a"}`).
+		CallTool("echo", `{"text":"b"}`).
+		RespondFunc(func(request Request, results []ToolResult) string {
+			var parts []string
+			for _, result := range results {
+				parts = append(parts, result.Result)
+			}
+			return strings.Join(parts, ", ")
+		})
+
+	response, err := llm.GenerateWithToolsDetailed(context.Background(), "echo twice", []interfaces.Tool{tool})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Content != `echo {"text":"a"}, echo {"text":"b"}` {
+		t.Errorf("unexpected response %q", response.Content)
+	}
+	if response.Model != "mock-model" || response.Usage.TotalTokens != 15 {
+		t.Errorf("unexpected response metadata %+v", response)
+	}
+	if len(tool.calls) != 2 {
+		t.Errorf("expected 2 tool calls, got %v", tool.calls)
+	}
+
+	// The expectation requires the tool, so a call without it is unexpected
+	if _, err := llm.Generate(context.Background(), "echo once"); !errors.Is(err, ErrUnexpectedCall) {
+		t.Errorf("expected ErrUnexpectedCall without the tool, got %v", err)
+	}
+}
+
+func TestMissingToolFailsTheCall(t *testing.T) {
+	llm := New()
+	llm.On(Any()).CallTool("search", `{}`).Respond("done")
+
+	_, err := llm.GenerateWithTools(context.Background(), "find", []interfaces.Tool{&echoTool{name: "echo"}})
+	if err == nil || !strings.Contains(err.Error(), `tool "search" was not passed`) {
+		t.Errorf("expected a missing tool error, got %v", err)
+	}
+}
+
+func TestFail(t *testing.T) {
+	llm := New()
+	llm.On(Any()).Fail(errors.New("rate limited"))
+
+	if _, err := llm.Generate(context.Background(), "hi"); err == nil || err.Error() != "rate limited" {
+		t.Errorf("expected the scripted error, got %v", err)
+	}
+	if _, err := llm.GenerateStream(context.Background(), "hi"); err == nil {
+		t.Error("expected the scripted error from the stream")
+	}
+}
+
+func TestStream(t *testing.T) {
+	tool := &echoTool{name: "echo"}
+	llm := New()
+	llm.On(PromptEquals("tools")).CallTool("echo", `{}`).Respond("done")
+	llm.On(PromptEquals("scripted")).Stream(
+		interfaces.StreamEvent{Type: interfaces.StreamEventThinking, Content: "hmm"},
+		interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "Hel"},
+		interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "lo"},
+	)
+
+	events, err := llm.GenerateWithToolsStream(context.Background(), "tools", []interfaces.Tool{tool})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var types []interfaces.StreamEventType
+	for event := range events {
+		types = append(types, event.Type)
+	}
+	expected := []interfaces.StreamEventType{
+		interfaces.StreamEventMessageStart,
+		interfaces.StreamEventToolUse,
+		interfaces.StreamEventToolResult,
+		interfaces.StreamEventContentDelta,
+		interfaces.StreamEventMessageStop,
+	}
+	if len(types) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("expected events %v, got %v", expected, types)
+			break
+		}
+	}
+	if len(tool.calls) != 1 {
+		t.Errorf("expected the tool to be called while streaming, got %v", tool.calls)
+	}
+
+	events, _ = llm.GenerateStream(context.Background(), "scripted")
+	var content string
+	for event := range events {
+		if event.Type == interfaces.StreamEventContentDelta {
+			content += event.Content
+		}
+	}
+	if content != "Hello" {
+		t.Errorf("expected scripted content %q, got %q", "Hello", content)
+	}
+	if calls := llm.Calls(); calls[len(calls)-1].Response != "Hello" {
+		t.Errorf("expected the streamed response to be recorded, got %q", calls[len(calls)-1].Response)
+	}
+}
@@ -196,9 +196,13 @@ func (c *OllamaClient) Generate(ctx context.Context, prompt string, options ...i
 			Temperature: params.LLMConfig.Temperature,
 			TopP:        params.LLMConfig.TopP,
 			Stop:        params.LLMConfig.StopSequences,
+			NumPredict:  params.LLMConfig.MaxOutputTokens,
 		},
 		System: params.SystemMessage,
 	}
+	if params.LLMConfig.Seed != nil {
+		req.Options.Seed = *params.LLMConfig.Seed
+	}
 
 	// Handle structured output if provided
 	if params.ResponseFormat != nil && params.ResponseFormat.Type == interfaces.ResponseFormatJSON {
@@ -294,8 +298,12 @@ func (c *OllamaClient) GenerateWithTools(ctx context.Context, prompt string, too
 				Temperature: params.LLMConfig.Temperature,
 				TopP:        params.LLMConfig.TopP,
 				Stop:        params.LLMConfig.StopSequences,
+				NumPredict:  params.LLMConfig.MaxOutputTokens,
 			},
 		}
+		if params.LLMConfig.Seed != nil {
+			req.Options.Seed = *params.LLMConfig.Seed
+		}
 
 		resp, err := c.makeRequest(ctx, "/api/chat", req)
 		if err != nil {
@@ -498,6 +506,11 @@ func (c *OllamaClient) Name() string {
 	return "ollama"
 }
 
+// WarmConnections implements interfaces.ConnectionWarmer.WarmConnections
+func (c *OllamaClient) WarmConnections(ctx context.Context) error {
+	return llm.WarmConnection(ctx, c.HTTPClient, c.BaseURL)
+}
+
 // SupportsStreaming returns false as streaming is not yet implemented for Ollama
 func (c *OllamaClient) SupportsStreaming() bool {
 	return false
@@ -537,7 +550,7 @@ func (c *OllamaClient) makeRequest(ctx context.Context, endpoint string, payload
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, llm.ClassifyError("ollama", fmt.Errorf("failed to execute request: %w", err))
 	}
 	defer func() {
 		if resp != nil {
@@ -553,7 +566,8 @@ func (c *OllamaClient) makeRequest(ctx context.Context, endpoint string, payload
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, llm.ClassifyHTTPError("ollama", resp.StatusCode, resp.Header, string(body),
+			fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body)))
 	}
 
 	return body, nil
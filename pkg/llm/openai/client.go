@@ -4,16 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
-	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
 	"github.com/openai/openai-go/v2"
 	"github.com/openai/openai-go/v2/option"
 	"github.com/openai/openai-go/v2/shared"
@@ -80,6 +78,20 @@ func (c *OpenAIClient) getTemperatureForModel(requestedTemp float64) float64 {
 	return requestedTemp
 }
 
+// setGenerationLimits sets the sampling seed and maximum output tokens of
+// config on req, when config sets them
+func setGenerationLimits(req *openai.ChatCompletionNewParams, config *interfaces.LLMConfig) {
+	if config == nil {
+		return
+	}
+	if config.Seed != nil {
+		req.Seed = openai.Int(int64(*config.Seed))
+	}
+	if config.MaxOutputTokens > 0 {
+		req.MaxCompletionTokens = openai.Int(int64(config.MaxOutputTokens))
+	}
+}
+
 // WithLogger sets the logger for the OpenAI client
 func WithLogger(logger logging.Logger) Option {
 	return func(c *OpenAIClient) {
@@ -165,13 +177,14 @@ func (c *OpenAIClient) generateInternal(ctx context.Context, prompt string, opti
 
 	// Build messages using unified builder
 	builder := newMessageHistoryBuilder(c.logger)
-	messages = append(messages, builder.buildMessages(ctx, prompt, params.Memory)...)
+	messages = append(messages, builder.buildMessages(ctx, prompt, params.Memory, params.ContentParts...)...)
 
 	// Create request
 	req := openai.ChatCompletionNewParams{
 		Model:    openai.ChatModel(c.Model),
 		Messages: messages,
 	}
+	setGenerationLimits(&req, params.LLMConfig)
 
 	if params.LLMConfig != nil {
 		req.Temperature = openai.Float(c.getTemperatureForModel(params.LLMConfig.Temperature))
@@ -249,7 +262,7 @@ func (c *OpenAIClient) generateInternal(ctx context.Context, prompt string, opti
 				"error": err.Error(),
 				"model": c.Model,
 			})
-			return fmt.Errorf("failed to generate text: %w", err)
+			return classifyError(fmt.Errorf("failed to generate text: %w", err))
 		}
 		return nil
 	}
@@ -269,6 +282,11 @@ func (c *OpenAIClient) generateInternal(ctx context.Context, prompt string, opti
 
 	// Return response
 	if len(resp.Choices) > 0 {
+		choice := resp.Choices[0]
+		if err := llm.CheckChatCompletionFilter("openai", string(choice.FinishReason), choice.Message.Refusal, choice.RawJSON()); err != nil {
+			return nil, err
+		}
+
 		c.logger.Debug(ctx, "Successfully received response from OpenAI", map[string]interface{}{
 			"model": c.Model,
 		})
@@ -382,7 +400,7 @@ func (c *OpenAIClient) Chat(ctx context.Context, messages []llm.Message, params
 				"error": err.Error(),
 				"model": c.Model,
 			})
-			return fmt.Errorf("failed to create chat completion: %w", err)
+			return classifyError(fmt.Errorf("failed to create chat completion: %w", err))
 		}
 		return nil
 	}
@@ -411,40 +429,8 @@ func (c *OpenAIClient) Chat(ctx context.Context, messages []llm.Message, params
 	return resp.Choices[0].Message.Content, nil
 }
 
-// GenerateWithTools implements interfaces.LLM.GenerateWithTools
-func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
-	// Convert options to params
-	params := &interfaces.GenerateOptions{}
-	for _, opt := range options {
-		if opt != nil {
-			opt(params)
-		}
-	}
-
-	// Set default values only if they're not provided
-	if params.LLMConfig == nil {
-		params.LLMConfig = &interfaces.LLMConfig{
-			Temperature:      0.7,
-			TopP:             1.0,
-			FrequencyPenalty: 0.0,
-			PresencePenalty:  0.0,
-		}
-	}
-
-	// Set default max iterations if not provided
-	maxIterations := params.MaxIterations
-	if maxIterations == 0 {
-		maxIterations = 2 // Default to current behavior
-	}
-
-	// Check for organization ID in context
-	orgID := "default"
-	if id, err := multitenancy.GetOrgID(ctx); err == nil {
-		orgID = id
-	}
-	ctx = context.WithValue(ctx, organizationKey, orgID)
-
-	// Convert tools to OpenAI format
+// convertTools converts tools to the OpenAI function tool format
+func convertTools(tools []interfaces.Tool) []openai.ChatCompletionToolUnionParam {
 	openaiTools := make([]openai.ChatCompletionToolUnionParam, len(tools))
 	for i, tool := range tools {
 		// Convert ParameterSpec to JSON Schema
@@ -485,14 +471,39 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 			},
 		})
 	}
+	return openaiTools
+}
+
+// GenerateWithTools implements interfaces.LLM.GenerateWithTools
+func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	// Convert options to params
+	params := &interfaces.GenerateOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(params)
+		}
+	}
+
+	// Set default values only if they're not provided
+	if params.LLMConfig == nil {
+		params.LLMConfig = &interfaces.LLMConfig{
+			Temperature:      0.7,
+			TopP:             1.0,
+			FrequencyPenalty: 0.0,
+			PresencePenalty:  0.0,
+		}
+	}
+
+	// Check for organization ID in context
+	orgID := "default"
+	if id, err := multitenancy.GetOrgID(ctx); err == nil {
+		orgID = id
+	}
+	ctx = context.WithValue(ctx, organizationKey, orgID)
 
 	// Build messages with memory and current prompt
 	builder := newMessageHistoryBuilder(c.logger)
-	messages := builder.buildMessages(ctx, prompt, params.Memory)
-
-	// Track tool call repetitions for loop detection
-	toolCallHistory := make(map[string]int)
-	var toolCallHistoryMu sync.Mutex
+	messages := builder.buildMessages(ctx, prompt, params.Memory, params.ContentParts...)
 
 	// Add system message if available (for reasoning mode)
 	if params.SystemMessage != "" {
@@ -502,10 +513,9 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 
 	req := openai.ChatCompletionNewParams{
 		Model:       openai.ChatModel(c.Model),
-		Messages:    messages,
-		Tools:       openaiTools,
 		Temperature: openai.Float(c.getTemperatureForModel(params.LLMConfig.Temperature)),
 	}
+	setGenerationLimits(&req, params.LLMConfig)
 
 	// Only send penalties when explicitly set. Some OpenAI-compatible
 	// providers (e.g. xAI Grok reasoning models) reject the parameters
@@ -522,11 +532,6 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 		req.TopP = openai.Float(params.LLMConfig.TopP)
 	}
 
-	// Only set ParallelToolCalls for non-reasoning models
-	if !isReasoningModel(c.Model) {
-		req.ParallelToolCalls = openai.Bool(true)
-	}
-
 	if len(params.LLMConfig.StopSequences) > 0 {
 		req.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: params.LLMConfig.StopSequences}
 	}
@@ -554,489 +559,172 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 		c.logger.Debug(ctx, "Using response format", map[string]interface{}{"format": *params.ResponseFormat})
 	}
 
-	// Track the last response content from the tool-calling loop
-	var lastContent string
-
-	// Iterative tool calling loop
-	for iteration := 0; iteration < maxIterations; iteration++ {
-		// Update request with current messages
-		req.Messages = messages
-
-		// Send request
-		var reasoningEffort string
-		if params.LLMConfig != nil && params.LLMConfig.Reasoning != "" {
-			reasoningEffort = params.LLMConfig.Reasoning
-		} else {
-			reasoningEffort = "none"
-		}
-
-		c.logger.Debug(ctx, "Sending request with tools to OpenAI", map[string]interface{}{
-			"model":             c.Model,
-			"temperature":       req.Temperature,
-			"top_p":             req.TopP,
-			"frequency_penalty": req.FrequencyPenalty,
-			"presence_penalty":  req.PresencePenalty,
-			"stop_sequences":    req.Stop,
-			"messages":          len(req.Messages),
-			"tools":             len(req.Tools),
-			"response_format":   params.ResponseFormat != nil,
-			"parallel_tools":    req.ParallelToolCalls,
-			"reasoning_effort":  reasoningEffort,
-			"iteration":         iteration + 1,
-			"maxIterations":     maxIterations,
-		})
-		resp, err := c.ChatService.Completions.New(ctx, req)
-		if err != nil {
-			c.logger.Error(ctx, "Error from OpenAI API", map[string]interface{}{"error": err.Error()})
-			return "", fmt.Errorf("failed to create chat completion: %w", err)
-		}
-
-		if len(resp.Choices) == 0 {
-			return "", fmt.Errorf("no completions returned")
-		}
-
-		// Accumulate per-iteration token usage so GenerateWithToolsDetailed
-		// can report a total that reflects every underlying call (#276).
-		if acc := getUsageAccumulator(ctx); acc != nil {
-			acc.add(
-				int(resp.Usage.PromptTokens),
-				int(resp.Usage.CompletionTokens),
-				int(resp.Usage.TotalTokens),
-				int(resp.Usage.CompletionTokensDetails.ReasoningTokens),
-				c.Model,
-			)
-		}
-
-		// Capture the last content from the response
-		lastContent = strings.TrimSpace(resp.Choices[0].Message.Content)
-
-		// Check if the model wants to use tools
-		if len(resp.Choices[0].Message.ToolCalls) == 0 {
-			// No tool calls, return the response
-			return lastContent, nil
-		}
-
-		// The model wants to use tools
-		toolCalls := resp.Choices[0].Message.ToolCalls
-		c.logger.Info(ctx, "Processing tool calls", map[string]interface{}{
-			"count":     len(toolCalls),
-			"iteration": iteration + 1,
-		})
-
-		// Add the assistant's message with tool calls to the conversation
-		messages = append(messages, resp.Choices[0].Message.ToParam())
-
-		// Process each tool call
-		for _, toolCall := range toolCalls {
-			// Replace multi_tool_use.parallel name if present
-			if toolCall.Function.Name == "multi_tool_use.parallel" {
-				c.logger.Info(ctx, "Replacing multi_tool_use.parallel with parallel_tool_use", nil)
-				toolCall.Function.Name = "parallel_tool_use"
-			}
-
-			if toolCall.Function.Name == "parallel_tool_use" {
-				c.logger.Info(ctx, "Parallel tool call", map[string]interface{}{"toolName": toolCall.Function.Name})
-
-				arguments := toolCall.Function.Arguments
-				var toolUsesWrapper struct {
-					ToolUses []map[string]interface{} `json:"tool_uses"`
-				}
-				err := json.Unmarshal([]byte(arguments), &toolUsesWrapper)
-				if err != nil {
-					c.logger.Error(ctx, "Error unmarshalling tool uses", map[string]interface{}{"error": err.Error()})
-					continue
-				}
-
-				type toolResult struct {
-					index  int
-					result string
-					err    error
-				}
-
-				resultCh := make(chan toolResult, len(toolUsesWrapper.ToolUses))
-				var wg sync.WaitGroup
-
-				// Launch goroutines for concurrent tool execution
-				for i, toolUse := range toolUsesWrapper.ToolUses {
-					wg.Add(1)
-					go func(index int, toolUse map[string]interface{}) {
-						defer wg.Done()
-
-						toolName := toolUse["recipient_name"].(string)
-						parameters := toolUse["parameters"].(map[string]interface{})
-
-						c.logger.Info(ctx, "Parallel tool use", map[string]interface{}{"toolName": toolName, "parameters": parameters})
-
-						// Convert parameters to JSON string
-						paramsBytes, err := json.Marshal(parameters)
-						if err != nil {
-							c.logger.Error(ctx, "Error marshalling parameters", map[string]interface{}{"error": err.Error()})
-							resultCh <- toolResult{index: index, result: "", err: err}
-							return
-						}
-
-						// Find the correct tool for this operation
-						var tool interfaces.Tool
-						for _, t := range tools {
-							if t.Name() == toolName {
-								tool = t
-								break
-							}
-						}
-
-						if tool == nil {
-							err := fmt.Errorf("tool not found: %s", toolName)
-							c.logger.Error(ctx, "Tool not found in parallel execution", map[string]interface{}{"toolName": toolName})
-							resultCh <- toolResult{index: index, result: "", err: err}
-							return
-						}
-
-						c.logger.Info(ctx, "Executing tool", map[string]interface{}{"toolName": toolName, "parameters": string(paramsBytes)})
-
-						result, err := tool.Execute(ctx, string(paramsBytes))
-
-						// Check for repetitive calls and add warning if needed
-						cacheKey := toolName + ":" + string(paramsBytes)
-
-						toolCallHistoryMu.Lock()
-						toolCallHistory[cacheKey]++
-						callCount := toolCallHistory[cacheKey]
-						toolCallHistoryMu.Unlock()
-
-						if callCount > 2 {
-							warning := fmt.Sprintf("\n\n[WARNING: This is call #%d to %s with identical parameters. You may be in a loop. Consider using the available information to provide a final answer.]",
-								callCount,
-								toolName)
-							if err == nil {
-								result += warning
-							}
-							c.logger.Warn(ctx, "Repetitive tool call detected in parallel execution", map[string]interface{}{
-								"toolName":  toolName,
-								"callCount": callCount,
-							})
-						}
-
-						// Store tool call and result in memory if provided
-						if params.Memory != nil {
-							if err != nil {
-								// Store failed parallel tool call result
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:    "assistant",
-									Content: "",
-									ToolCalls: []interfaces.ToolCall{{
-										ID:        toolCall.ID,
-										Name:      toolName,
-										Arguments: string(paramsBytes),
-									}},
-								})
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:       "tool",
-									Content:    fmt.Sprintf("Error: %v", err),
-									ToolCallID: toolCall.ID,
-									Metadata: map[string]interface{}{
-										"tool_name": toolCall.Function.Name,
-									},
-								})
-							} else {
-								// Store successful parallel tool call and result
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:    "assistant",
-									Content: "",
-									ToolCalls: []interfaces.ToolCall{{
-										ID:        toolCall.ID,
-										Name:      toolName,
-										Arguments: string(paramsBytes),
-									}},
-								})
-								_ = params.Memory.AddMessage(ctx, interfaces.Message{
-									Role:       "tool",
-									Content:    result,
-									ToolCallID: toolCall.ID,
-									Metadata: map[string]interface{}{
-										"tool_name": toolCall.Function.Name,
-									},
-								})
-							}
-						}
-
-						resultCh <- toolResult{index: index, result: result, err: err}
-					}(i, toolUse)
-				}
-
-				// Close channel when all goroutines complete
-				go func() {
-					wg.Wait()
-					close(resultCh)
-				}()
-
-				// Collect results and check for errors
-				toolsResults := make([]string, len(toolUsesWrapper.ToolUses))
-				for result := range resultCh {
-					if result.err != nil {
-						c.logger.Error(ctx, "Error executing tool", map[string]interface{}{"error": result.err.Error()})
-						return "", fmt.Errorf("error executing tool: %s", result.err.Error())
-					}
-					toolsResults[result.index] = result.result
-				}
-
-				// For parallel tool use, we need to create a tool message
-				// The new API uses openai.ToolMessage(content, toolCallID) instead of struct literals
-				// Create a structured response that clearly identifies which tool each result came from
-				var structuredResults []string
-				for i, toolUse := range toolUsesWrapper.ToolUses {
-					toolName := toolUse["recipient_name"].(string)
-					result := toolsResults[i]
-					structuredResults = append(structuredResults, fmt.Sprintf("Tool: %s\nResult: %s", toolName, result))
-				}
-				messages = append(messages, openai.ToolMessage(strings.Join(structuredResults, "\n\n"), toolCall.ID))
-				continue
-			}
-
-			// Find the requested tool
-			var selectedTool interfaces.Tool
-			for _, tool := range tools {
-				if tool.Name() == toolCall.Function.Name {
-					selectedTool = tool
-					break
-				}
-			}
-
-			if selectedTool == nil || selectedTool.Name() == "" {
-				c.logger.Error(ctx, "Tool not found", map[string]interface{}{
-					"toolName": toolCall.Function.Name,
-					"toolcall": toolCall,
-					"resp":     resp,
-				})
-
-				// Add tool not found error as tool result instead of returning
-				errorMessage := fmt.Sprintf("Error: tool not found: %s", toolCall.Function.Name)
-
-				// Store failed tool call in memory if provided
-				if params.Memory != nil {
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:    "assistant",
-						Content: "",
-						ToolCalls: []interfaces.ToolCall{{
-							ID:        toolCall.ID,
-							Name:      toolCall.Function.Name,
-							Arguments: toolCall.Function.Arguments,
-						}},
-					})
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:       "tool",
-						Content:    errorMessage,
-						ToolCallID: toolCall.ID,
-						Metadata: map[string]interface{}{
-							"tool_name": toolCall.Function.Name,
-						},
-					})
-				}
-
-				// Add to tracing context
-				toolCallTrace := tracing.ToolCall{
-					Name:       toolCall.Function.Name,
-					Arguments:  toolCall.Function.Arguments,
-					ID:         toolCall.ID,
-					Timestamp:  time.Now().Format(time.RFC3339),
-					StartTime:  time.Now(),
-					Duration:   0,
-					DurationMs: 0,
-					Error:      fmt.Sprintf("tool not found: %s", toolCall.Function.Name),
-					Result:     errorMessage,
-				}
-
-				tracing.AddToolCallToContext(ctx, toolCallTrace)
-
-				// Add error message as tool response
-				messages = append(messages, openai.ToolMessage(errorMessage, toolCall.ID))
-
-				continue // Continue processing other tool calls
-			}
-
-			// Execute the tool
-			c.logger.Info(ctx, "Executing tool", map[string]interface{}{"toolName": selectedTool.Name()})
-			toolStartTime := time.Now()
-			toolResult, err := selectedTool.Execute(ctx, toolCall.Function.Arguments)
-			toolEndTime := time.Now()
-
-			// Check for repetitive calls and add warning if needed
-			cacheKey := toolCall.Function.Name + ":" + toolCall.Function.Arguments
-
-			toolCallHistoryMu.Lock()
-			toolCallHistory[cacheKey]++
-			callCount := toolCallHistory[cacheKey]
-			toolCallHistoryMu.Unlock()
-
-			if callCount > 1 {
-				warning := fmt.Sprintf("\n\n[WARNING: This is call #%d to %s with identical parameters. You may be in a loop. Consider using the available information to provide a final answer.]",
-					callCount,
-					toolCall.Function.Name)
-				if err == nil {
-					toolResult += warning
-				}
-				c.logger.Warn(ctx, "Repetitive tool call detected", map[string]interface{}{
-					"toolName":  toolCall.Function.Name,
-					"callCount": callCount,
-				})
-			}
-
-			// Add tool call to tracing context
-			executionDuration := toolEndTime.Sub(toolStartTime)
-			toolCallTrace := tracing.ToolCall{
-				Name:       toolCall.Function.Name,
-				Arguments:  toolCall.Function.Arguments,
-				ID:         toolCall.ID,
-				Timestamp:  toolStartTime.Format(time.RFC3339),
-				StartTime:  toolStartTime,
-				Duration:   executionDuration,
-				DurationMs: executionDuration.Milliseconds(),
-			}
-
-			// Store tool call and result in memory if provided
-			if params.Memory != nil {
-				if err != nil {
-					// Store failed tool call result
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:    "assistant",
-						Content: "",
-						ToolCalls: []interfaces.ToolCall{{
-							ID:        toolCall.ID,
-							Name:      toolCall.Function.Name,
-							Arguments: toolCall.Function.Arguments,
-						}},
-					})
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:       "tool",
-						Content:    fmt.Sprintf("Error: %v", err),
-						ToolCallID: toolCall.ID,
-						Metadata: map[string]interface{}{
-							"tool_name": toolCall.Function.Name,
-						},
-					})
-				} else {
-					// Store successful tool call and result
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:    "assistant",
-						Content: "",
-						ToolCalls: []interfaces.ToolCall{{
-							ID:        toolCall.ID,
-							Name:      toolCall.Function.Name,
-							Arguments: toolCall.Function.Arguments,
-						}},
-					})
-					_ = params.Memory.AddMessage(ctx, interfaces.Message{
-						Role:       "tool",
-						Content:    toolResult,
-						ToolCallID: toolCall.ID,
-						Metadata: map[string]interface{}{
-							"tool_name": toolCall.Function.Name,
-						},
-					})
-				}
-			}
-
-			if err != nil {
-				c.logger.Error(ctx, "Error executing tool", map[string]interface{}{"toolName": selectedTool.Name(), "error": err.Error()})
-				toolCallTrace.Error = err.Error()
-				toolCallTrace.Result = fmt.Sprintf("Error: %v", err)
-				// Add error message as tool response
-				messages = append(messages, openai.ToolMessage(fmt.Sprintf("Error: %v", err), toolCall.ID))
-			} else {
-				toolCallTrace.Result = toolResult
-				// Add tool result to messages
-				messages = append(messages, openai.ToolMessage(toolResult, toolCall.ID))
-			}
-
-			// Add the tool call to the tracing context
-			tracing.AddToolCallToContext(ctx, toolCallTrace)
-		}
-
-		// Continue to the next iteration with updated messages
+	// Run the tool-calling loop with the shared tool executor
+	executor := llm.NewToolExecutor(c.logger, llm.WithToolMemory(params.Memory), llm.WithToolTimeout(params.ToolTimeout), llm.WithParallelToolCalls(params.ParallelToolCalls), llm.WithToolsFunc(params.ToolsFunc))
+	loop := &chatToolLoop{client: c, params: params, req: req, messages: messages}
+	result, err := executor.Run(ctx, loop, tools, params.ToolLoop())
+	if err != nil {
+		return "", err
 	}
+	return result.Content, nil
+}
+
+// chatToolLoop sends the requests of GenerateWithTools to the Chat
+// Completions API, for the shared tool-calling loop
+type chatToolLoop struct {
+	client   *OpenAIClient
+	params   *interfaces.GenerateOptions
+	req      openai.ChatCompletionNewParams
+	messages []openai.ChatCompletionMessageParamUnion
+
+	// toolCalls are the tool calls of the last response, and wrapped the IDs
+	// of its parallel_tool_use calls
+	toolCalls []openai.ChatCompletionMessageToolCallUnion
+	wrapped   map[string]bool
+}
 
-	// If we've reached the maximum iterations and the model is still requesting tools,
-	// make one final call without tools to get a conclusion
+// Send implements llm.ToolLoopProvider.Send
+func (l *chatToolLoop) Send(ctx context.Context, iteration int, tools []interfaces.Tool) (llm.ToolLoopTurn, error) {
+	c := l.client
+	req := l.req
+	req.Messages = l.messages
+	req.Tools = convertTools(tools)
 
-	// If DisableFinalSummary is enabled, return the last response from the tool-calling loop
-	if params.DisableFinalSummary {
-		c.logger.Info(ctx, "DisableFinalSummary enabled, skipping final summary call", map[string]interface{}{
-			"maxIterations": maxIterations,
-		})
-		return lastContent, nil
+	// Only set ParallelToolCalls for non-reasoning models
+	if !isReasoningModel(c.Model) {
+		req.ParallelToolCalls = openai.Bool(true)
 	}
 
-	c.logger.Info(ctx, "Maximum iterations reached, making final call without tools", map[string]interface{}{
-		"maxIterations": maxIterations,
+	// Send request
+	var reasoningEffort string
+	if l.params.LLMConfig != nil && l.params.LLMConfig.Reasoning != "" {
+		reasoningEffort = l.params.LLMConfig.Reasoning
+	} else {
+		reasoningEffort = "none"
+	}
+
+	c.logger.Debug(ctx, "Sending request with tools to OpenAI", map[string]interface{}{
+		"model":             c.Model,
+		"temperature":       req.Temperature,
+		"top_p":             req.TopP,
+		"frequency_penalty": req.FrequencyPenalty,
+		"presence_penalty":  req.PresencePenalty,
+		"stop_sequences":    req.Stop,
+		"messages":          len(req.Messages),
+		"tools":             len(req.Tools),
+		"response_format":   l.params.ResponseFormat != nil,
+		"parallel_tools":    req.ParallelToolCalls,
+		"reasoning_effort":  reasoningEffort,
+		"iteration":         iteration + 1,
 	})
-
-	// Create a final request without tools to force the LLM to provide a conclusion
-	finalReq := openai.ChatCompletionNewParams{
-		Model:       openai.ChatModel(c.Model),
-		Messages:    messages,
-		Tools:       nil, // No tools for final call
-		Temperature: openai.Float(c.getTemperatureForModel(params.LLMConfig.Temperature)),
+	resp, err := l.complete(ctx, req)
+	if err != nil {
+		c.logger.Error(ctx, "Error from OpenAI API", map[string]interface{}{"error": err.Error()})
+		return llm.ToolLoopTurn{}, classifyError(fmt.Errorf("failed to create chat completion: %w", err))
 	}
-
-	// Only send penalties when explicitly set. Some OpenAI-compatible
-	// providers (e.g. xAI Grok reasoning models) reject the parameters
-	// outright, returning a 400 even for a 0 value.
-	if params.LLMConfig.FrequencyPenalty != 0 {
-		finalReq.FrequencyPenalty = openai.Float(params.LLMConfig.FrequencyPenalty)
+	if len(resp.Choices) == 0 {
+		return llm.ToolLoopTurn{}, fmt.Errorf("no completions returned")
 	}
-	if params.LLMConfig.PresencePenalty != 0 {
-		finalReq.PresencePenalty = openai.Float(params.LLMConfig.PresencePenalty)
+	if err := llm.CheckChatCompletionFilter("openai", string(resp.Choices[0].FinishReason), resp.Choices[0].Message.Refusal, resp.Choices[0].RawJSON()); err != nil {
+		return llm.ToolLoopTurn{}, err
 	}
 
-	// Reasoning models don't support top_p parameter
-	if !isReasoningModel(c.Model) && params.LLMConfig.TopP > 0 && params.LLMConfig.TopP <= 1 {
-		finalReq.TopP = openai.Float(params.LLMConfig.TopP)
+	message := resp.Choices[0].Message
+	turn := llm.ToolLoopTurn{Content: strings.TrimSpace(message.Content)}
+	l.toolCalls = message.ToolCalls
+	if len(l.toolCalls) == 0 {
+		return turn, nil
 	}
 
-	if len(params.LLMConfig.StopSequences) > 0 {
-		finalReq.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: params.LLMConfig.StopSequences}
-	}
+	// Add the assistant's message with tool calls to the conversation
+	l.messages = append(l.messages, message.ToParam())
 
-	// Set response format if provided
-	if params.ResponseFormat != nil {
-		jsonSchema := shared.ResponseFormatJSONSchemaJSONSchemaParam{
-			Name:   params.ResponseFormat.Name,
-			Schema: params.ResponseFormat.Schema,
+	// Decode the tool calls, expanding the parallel_tool_use calls of older
+	// models into the calls they wrap
+	l.wrapped = make(map[string]bool)
+	for _, toolCall := range l.toolCalls {
+		call := interfaces.ToolCall{
+			ID:        toolCall.ID,
+			Name:      toolCall.Function.Name,
+			Arguments: toolCall.Function.Arguments,
+		}
+		if call.Name != "multi_tool_use.parallel" && call.Name != "parallel_tool_use" {
+			turn.Calls = append(turn.Calls, call)
+			continue
 		}
 
-		finalReq.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
-			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
-				Type:       "json_schema",
-				JSONSchema: jsonSchema,
-			},
+		var toolUsesWrapper struct {
+			ToolUses []struct {
+				RecipientName string                 `json:"recipient_name"`
+				Parameters    map[string]interface{} `json:"parameters"`
+			} `json:"tool_uses"`
+		}
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &toolUsesWrapper); err != nil {
+			// Sent back as a call to an unknown tool
+			c.logger.Error(ctx, "Error unmarshalling tool uses", map[string]interface{}{"error": err.Error()})
+			turn.Calls = append(turn.Calls, call)
+			continue
+		}
+		l.wrapped[toolCall.ID] = true
+		for _, toolUse := range toolUsesWrapper.ToolUses {
+			arguments, err := json.Marshal(toolUse.Parameters)
+			if err != nil {
+				arguments = []byte("{}")
+			}
+			turn.Calls = append(turn.Calls, interfaces.ToolCall{
+				ID:        toolCall.ID,
+				Name:      toolUse.RecipientName,
+				Arguments: string(arguments),
+			})
 		}
 	}
+	return turn, nil
+}
 
-	// Add a system message to encourage conclusion
-	conclusionMessage := openai.SystemMessage("Please provide your final response based on the information available. Do not request any additional tools.")
-	finalReq.Messages = append(finalReq.Messages, conclusionMessage)
+// AddResults implements llm.ToolLoopProvider.AddResults. The results of a
+// parallel_tool_use call are sent back together, labeled with their tool.
+func (l *chatToolLoop) AddResults(ctx context.Context, iteration int, results []llm.ToolResult) error {
+	parallelResults := make(map[string][]string)
+	for _, result := range results {
+		if l.wrapped[result.Call.ID] {
+			parallelResults[result.Call.ID] = append(parallelResults[result.Call.ID],
+				fmt.Sprintf("Tool: %s\nResult: %s", result.Call.Name, result.Content))
+			continue
+		}
+		l.messages = append(l.messages, openai.ToolMessage(result.Content, result.Call.ID))
+	}
+	for _, toolCall := range l.toolCalls {
+		if results, ok := parallelResults[toolCall.ID]; ok {
+			l.messages = append(l.messages, openai.ToolMessage(strings.Join(results, "\n\n"), toolCall.ID))
+		}
+	}
+	return nil
+}
+
+// Conclude implements llm.ToolLoopProvider.Conclude
+func (l *chatToolLoop) Conclude(ctx context.Context, finalPrompt string) (string, error) {
+	c := l.client
+
+	// Create a final request without tools to force the LLM to provide a
+	// conclusion, with a system message to encourage it
+	finalReq := l.req
+	finalReq.Messages = append(l.messages, openai.SystemMessage(finalPrompt))
 
 	c.logger.Debug(ctx, "Making final request without tools", map[string]interface{}{
 		"messages": len(finalReq.Messages),
 	})
 
-	finalResp, err := c.ChatService.Completions.New(ctx, finalReq)
+	finalResp, err := l.complete(ctx, finalReq)
 	if err != nil {
 		c.logger.Error(ctx, "Error in final call without tools", map[string]interface{}{"error": err.Error()})
-		return "", fmt.Errorf("failed to create final chat completion: %w", err)
+		return "", classifyError(fmt.Errorf("failed to create final chat completion: %w", err))
 	}
 
 	if len(finalResp.Choices) == 0 {
 		return "", fmt.Errorf("no completions returned in final call")
 	}
-
-	if acc := getUsageAccumulator(ctx); acc != nil {
-		acc.add(
-			int(finalResp.Usage.PromptTokens),
-			int(finalResp.Usage.CompletionTokens),
-			int(finalResp.Usage.TotalTokens),
-			int(finalResp.Usage.CompletionTokensDetails.ReasoningTokens),
-			c.Model,
-		)
+	if err := llm.CheckChatCompletionFilter("openai", string(finalResp.Choices[0].FinishReason), finalResp.Choices[0].Message.Refusal, finalResp.Choices[0].RawJSON()); err != nil {
+		return "", err
 	}
 
 	content := strings.TrimSpace(finalResp.Choices[0].Message.Content)
@@ -1044,11 +732,38 @@ func (c *OpenAIClient) GenerateWithTools(ctx context.Context, prompt string, too
 	return content, nil
 }
 
+// complete sends a request of the loop, accumulating its token usage so
+// GenerateWithToolsDetailed can report a total that reflects every
+// underlying call (#276)
+func (l *chatToolLoop) complete(ctx context.Context, req openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	resp, err := l.client.ChatService.Completions.New(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if acc := getUsageAccumulator(ctx); acc != nil {
+		acc.add(
+			int(resp.Usage.PromptTokens),
+			int(resp.Usage.CompletionTokens),
+			int(resp.Usage.TotalTokens),
+			int(resp.Usage.CompletionTokensDetails.ReasoningTokens),
+			l.client.Model,
+		)
+	}
+	return resp, nil
+}
+
 // Name implements interfaces.LLM.Name
 func (c *OpenAIClient) Name() string {
 	return "openai"
 }
 
+// WarmConnections implements interfaces.ConnectionWarmer.WarmConnections
+// The OpenAI SDK sends requests through http.DefaultClient, so warming it
+// populates the same connection pool.
+func (c *OpenAIClient) WarmConnections(ctx context.Context) error {
+	return llm.WarmConnection(ctx, http.DefaultClient, c.baseURL)
+}
+
 // SupportsStreaming implements interfaces.LLM.SupportsStreaming
 func (c *OpenAIClient) SupportsStreaming() bool {
 	return true
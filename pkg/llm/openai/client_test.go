@@ -3,10 +3,12 @@ package openai_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
@@ -148,6 +150,99 @@ func TestGenerate_IncludesTopPWhenExplicitlySet(t *testing.T) {
 	}
 }
 
+func TestGenerate_Deterministic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		if seed, ok := reqBody["seed"].(float64); !ok || seed != 42 {
+			t.Errorf("expected seed=42, got %v", reqBody["seed"])
+		}
+		if temperature, ok := reqBody["temperature"].(float64); !ok || temperature != 0 {
+			t.Errorf("expected temperature=0, got %v", reqBody["temperature"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "ok", Role: "assistant"}}}})
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"), openai_client.WithLogger(logging.New()))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	if _, err := client.Generate(context.Background(), "who are you", interfaces.WithDeterministic(42)); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+}
+
+func TestGenerate_MaxOutputTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		if maxTokens, ok := reqBody["max_completion_tokens"].(float64); !ok || maxTokens != 256 {
+			t.Errorf("expected max_completion_tokens=256, got %v", reqBody["max_completion_tokens"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "ok", Role: "assistant"}}}})
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"), openai_client.WithLogger(logging.New()))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	if _, err := client.Generate(context.Background(), "who are you", interfaces.WithMaxOutputTokens(256)); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+}
+
+func TestGenerate_TypedErrors(t *testing.T) {
+	status := http.StatusTooManyRequests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "20")
+		w.WriteHeader(status)
+		if status == http.StatusBadRequest {
+			_, _ = w.Write([]byte(`{"error":{"message":"This model's maximum context length is 8192 tokens.","type":"invalid_request_error","code":"context_length_exceeded"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`))
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key", openai_client.WithModel("gpt-4"), openai_client.WithLogger(logging.New()))
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+
+	_, err := client.Generate(context.Background(), "hello")
+	var rateLimited *interfaces.RateLimitError
+	if !errors.As(err, &rateLimited) || rateLimited.Provider != "openai" || rateLimited.RetryAfter != 20*time.Second {
+		t.Errorf("Expected a RateLimitError retrying after 20s, got %v", err)
+	}
+	if !errors.Is(err, interfaces.ErrRateLimitExceeded) {
+		t.Errorf("Expected the error to match ErrRateLimitExceeded, got %v", err)
+	}
+
+	status = http.StatusBadRequest
+	if _, err := client.Generate(context.Background(), "hello"); !errors.Is(err, interfaces.ErrContextLengthExceeded) {
+		t.Errorf("Expected a context length error, got %v", err)
+	}
+}
+
 func TestChat(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -505,6 +600,222 @@ func TestParallelToolExecution(t *testing.T) {
 	}
 }
 
+func TestGenerateWithToolsFunc(t *testing.T) {
+	// Record the tools sent with each request
+	var requestTools [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Tools []struct {
+				Function struct {
+					Name string `json:"name"`
+				} `json:"function"`
+			} `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		var names []string
+		for _, tool := range reqBody.Tools {
+			names = append(names, tool.Function.Name)
+		}
+		requestTools = append(requestTools, names)
+
+		message := openai.ChatCompletionMessage{Content: "done", Role: "assistant"}
+		if len(requestTools) == 1 {
+			message = openai.ChatCompletionMessage{
+				Role: "assistant",
+				ToolCalls: []openai.ChatCompletionMessageToolCallUnion{
+					{
+						ID:   "call_123",
+						Type: "function",
+						Function: openai.ChatCompletionMessageFunctionToolCallFunction{
+							Name:      "test_tool_1",
+							Arguments: `{"param": "value"}`,
+						},
+					},
+				},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{Message: message}}})
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-4"),
+		openai_client.WithLogger(logging.New()),
+	)
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	// The tools change after the first iteration
+	toolsFunc := func() []interfaces.Tool {
+		return []interfaces.Tool{&mockTool{name: "test_tool_2", description: "Test tool 2"}}
+	}
+	resp, err := client.GenerateWithTools(context.Background(), "test prompt",
+		[]interfaces.Tool{&mockTool{name: "test_tool_1", description: "Test tool 1"}},
+		interfaces.WithToolsFunc(toolsFunc))
+	if err != nil {
+		t.Fatalf("Failed to generate with tools: %v", err)
+	}
+	if resp != "done" {
+		t.Errorf("Expected response 'done', got '%s'", resp)
+	}
+
+	if fmt.Sprint(requestTools) != "[[test_tool_1] [test_tool_2]]" {
+		t.Errorf("Expected the second request to send the current tools, got %v", requestTools)
+	}
+}
+
+func TestGenerateWithToolsStream_ToolsFunc(t *testing.T) {
+	// Record the tools sent with each request
+	var requestTools [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Tools []struct {
+				Function struct {
+					Name string `json:"name"`
+				} `json:"function"`
+			} `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		var names []string
+		for _, tool := range reqBody.Tools {
+			names = append(names, tool.Function.Name)
+		}
+		requestTools = append(requestTools, names)
+
+		chunk := `{"id":"chunk","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"role":"assistant","content":"done"},"finish_reason":"stop"}]}`
+		if len(requestTools) == 1 {
+			chunk = `{"id":"chunk","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_123","type":"function","function":{"name":"test_tool_1","arguments":"{}"}}]},"finish_reason":"tool_calls"}]}`
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", chunk)
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-4"),
+		openai_client.WithLogger(logging.New()),
+	)
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+
+	// The tools change after the first iteration
+	toolsFunc := func() []interfaces.Tool {
+		return []interfaces.Tool{&mockTool{name: "test_tool_2", description: "Test tool 2"}}
+	}
+	eventCh, err := client.GenerateWithToolsStream(context.Background(), "test prompt",
+		[]interfaces.Tool{&mockTool{name: "test_tool_1", description: "Test tool 1"}},
+		interfaces.WithToolsFunc(toolsFunc))
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+	for event := range eventCh {
+		if event.Type == interfaces.StreamEventError {
+			t.Fatalf("Unexpected stream error: %v", event.Error)
+		}
+	}
+
+	if fmt.Sprint(requestTools) != "[[test_tool_1] [test_tool_2]]" {
+		t.Errorf("Expected the second request to send the current tools, got %v", requestTools)
+	}
+}
+
+func TestGenerateWithToolLoopPolicy(t *testing.T) {
+	// The model calls a tool whenever tools are offered
+	var requests int
+	var lastMessage interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Messages []struct {
+				Content interface{} `json:"content"`
+			} `json:"messages"`
+			Tools []json.RawMessage `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		requests++
+		lastMessage = reqBody.Messages[len(reqBody.Messages)-1].Content
+
+		message := openai.ChatCompletionMessage{Content: "final", Role: "assistant"}
+		if len(reqBody.Tools) > 0 {
+			message = openai.ChatCompletionMessage{
+				Role: "assistant",
+				ToolCalls: []openai.ChatCompletionMessageToolCallUnion{
+					{
+						ID:   fmt.Sprintf("call_%d", requests),
+						Type: "function",
+						Function: openai.ChatCompletionMessageFunctionToolCallFunction{
+							Name:      "test_tool_1",
+							Arguments: `{"param": "value"}`,
+						},
+					},
+				},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{Message: message}}})
+	}))
+	defer server.Close()
+
+	client := openai_client.NewClient("test-key",
+		openai_client.WithModel("gpt-4"),
+		openai_client.WithLogger(logging.New()),
+	)
+	client.ChatService = openai.NewChatService(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+	)
+	tools := []interfaces.Tool{&mockTool{name: "test_tool_1", description: "Test tool 1"}}
+
+	t.Run("final prompt", func(t *testing.T) {
+		requests = 0
+		resp, err := client.GenerateWithTools(context.Background(), "test prompt", tools,
+			interfaces.WithToolLoopPolicy(interfaces.ToolLoopPolicy{MaxIterations: 1, FinalPrompt: "Wrap up now."}))
+		if err != nil {
+			t.Fatalf("Failed to generate with tools: %v", err)
+		}
+		if resp != "final" || requests != 2 {
+			t.Errorf("Expected a final call after one iteration, got %q after %d requests", resp, requests)
+		}
+		if lastMessage != "Wrap up now." {
+			t.Errorf("Expected the final call to end with the final prompt, got %v", lastMessage)
+		}
+	})
+
+	t.Run("fail on exhaustion", func(t *testing.T) {
+		requests = 0
+		_, err := client.GenerateWithTools(context.Background(), "test prompt", tools,
+			interfaces.WithToolLoopPolicy(interfaces.ToolLoopPolicy{MaxIterations: 1, OnExhausted: interfaces.ToolLoopFail}))
+		if !errors.Is(err, interfaces.ErrToolLoopExhausted) {
+			t.Errorf("Expected ErrToolLoopExhausted, got %v", err)
+		}
+		if requests != 1 {
+			t.Errorf("Expected no final call, got %d requests", requests)
+		}
+	})
+
+	t.Run("stop tool", func(t *testing.T) {
+		requests = 0
+		resp, err := client.GenerateWithTools(context.Background(), "test prompt", tools,
+			interfaces.WithToolLoopPolicy(interfaces.ToolLoopPolicy{MaxIterations: 3, StopTools: []string{"test_tool_1"}}))
+		if err != nil {
+			t.Fatalf("Failed to generate with tools: %v", err)
+		}
+		if resp != `Result from test_tool_1: {"param": "value"}` || requests != 1 {
+			t.Errorf("Expected the stop tool result after one request, got %q after %d requests", resp, requests)
+		}
+	})
+}
+
 // mockTool implements interfaces.Tool for testing
 type mockTool struct {
 	name        string
@@ -0,0 +1,73 @@
+package openai
+
+import (
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/openai/openai-go/v2"
+)
+
+// userMessage builds a user message from text and multimodal content parts.
+// Messages without media are sent as plain text.
+func userMessage(text string, parts []interfaces.ContentPart) openai.ChatCompletionMessageParamUnion {
+	if !hasMedia(parts) {
+		return openai.UserMessage(text)
+	}
+	return openai.UserMessage(convertContentParts(text, parts))
+}
+
+// convertContentParts converts content parts to OpenAI content parts. Parts
+// OpenAI cannot take as user content (file URLs, tool calls and results) are
+// skipped.
+func convertContentParts(text string, parts []interfaces.ContentPart) []openai.ChatCompletionContentPartUnionParam {
+	var content []openai.ChatCompletionContentPartUnionParam
+	if text != "" {
+		content = append(content, openai.TextContentPart(text))
+	}
+
+	for _, part := range parts {
+		switch part.Type {
+		case interfaces.ContentPartText:
+			content = append(content, openai.TextContentPart(part.Text))
+		case interfaces.ContentPartImage:
+			content = append(content, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{
+				URL:    part.DataURL(),
+				Detail: part.Detail,
+			}))
+		case interfaces.ContentPartAudio:
+			content = append(content, openai.InputAudioContentPart(openai.ChatCompletionContentPartInputAudioInputAudioParam{
+				Data:   part.Base64(),
+				Format: audioFormat(part.MIMEType),
+			}))
+		case interfaces.ContentPartFile:
+			if len(part.Data) == 0 {
+				continue
+			}
+			file := openai.ChatCompletionContentPartFileFileParam{
+				FileData: openai.String(part.DataURL()),
+			}
+			if part.FileName != "" {
+				file.Filename = openai.String(part.FileName)
+			}
+			content = append(content, openai.FileContentPart(file))
+		}
+	}
+	return content
+}
+
+// audioFormat returns the input audio format for a MIME type: wav or mp3
+func audioFormat(mimeType string) string {
+	if strings.Contains(mimeType, "mp3") || strings.Contains(mimeType, "mpeg") {
+		return "mp3"
+	}
+	return "wav"
+}
+
+func hasMedia(parts []interfaces.ContentPart) bool {
+	for _, part := range parts {
+		if part.IsMedia() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package openai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestUserMessage(t *testing.T) {
+	plain, err := json.Marshal(userMessage("Hello", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plain) != `{"content":"Hello","role":"user"}` {
+		t.Errorf("plain message = %s", plain)
+	}
+
+	data, err := json.Marshal(userMessage("Describe these", []interfaces.ContentPart{
+		interfaces.ImagePart([]byte("png"), "image/png"),
+		interfaces.ImageURLPart("https://example.com/cat.png"),
+		interfaces.AudioPart([]byte("mp3"), "audio/mpeg"),
+		interfaces.FilePart([]byte("%PDF"), "application/pdf", "report.pdf"),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var message struct {
+		Content []map[string]interface{} `json:"content"`
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, part := range message.Content {
+		types = append(types, part["type"].(string))
+	}
+	if got := strings.Join(types, ","); got != "text,image_url,image_url,input_audio,file" {
+		t.Fatalf("content types = %s", got)
+	}
+	if url := message.Content[1]["image_url"].(map[string]interface{})["url"]; url != "data:image/png;base64,cG5n" {
+		t.Errorf("image url = %v", url)
+	}
+	if format := message.Content[3]["input_audio"].(map[string]interface{})["format"]; format != "mp3" {
+		t.Errorf("audio format = %v", format)
+	}
+	if name := message.Content[4]["file"].(map[string]interface{})["filename"]; name != "report.pdf" {
+		t.Errorf("file name = %v", name)
+	}
+}
@@ -0,0 +1,23 @@
+package openai
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
+	"github.com/openai/openai-go/v2"
+)
+
+// classifyError maps an error of the OpenAI API to the typed errors of the
+// interfaces package, e.g. *interfaces.RateLimitError for 429 responses
+func classifyError(err error) error {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		var header http.Header
+		if apiErr.Response != nil {
+			header = apiErr.Response.Header
+		}
+		return llm.ClassifyHTTPError("openai", apiErr.StatusCode, header, apiErr.Code+": "+apiErr.Message, err)
+	}
+	return llm.ClassifyError("openai", err)
+}
@@ -0,0 +1,284 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/openai/openai-go/v2"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// DefaultImageModel is the image model used when the client's model is not an image model
+const DefaultImageModel = "gpt-image-1"
+
+// imageModel returns the client's model when it is an image model
+// (gpt-image-* or dall-e-*), otherwise DefaultImageModel
+func (c *OpenAIClient) imageModel() string {
+	if strings.HasPrefix(c.Model, "gpt-image") || strings.HasPrefix(c.Model, "dall-e") {
+		return c.Model
+	}
+	return DefaultImageModel
+}
+
+// SupportsImageGeneration returns true; clients configured with a chat model
+// generate images with DefaultImageModel
+func (c *OpenAIClient) SupportsImageGeneration() bool {
+	return true
+}
+
+// SupportedImageFormats returns the supported output formats for the image model
+func (c *OpenAIClient) SupportedImageFormats() []string {
+	if strings.HasPrefix(c.imageModel(), "dall-e") {
+		return []string{"png"}
+	}
+	return []string{"png", "jpeg", "webp"}
+}
+
+// GenerateImage generates images from a text prompt using gpt-image-1 or
+// DALL·E. A reference image is edited with the images edit endpoint.
+func (c *OpenAIClient) GenerateImage(ctx context.Context, request interfaces.ImageGenerationRequest) (*interfaces.ImageGenerationResponse, error) {
+	if request.Prompt == "" {
+		return nil, interfaces.ErrInvalidPrompt
+	}
+
+	opts := request.Options
+	if opts == nil {
+		opts = interfaces.DefaultImageGenerationOptions()
+	}
+	if opts.NumberOfImages <= 0 {
+		opts.NumberOfImages = 1
+	}
+	if opts.OutputFormat == "" {
+		opts.OutputFormat = "png"
+	}
+
+	model := c.imageModel()
+	dallE := strings.HasPrefix(model, "dall-e")
+	size := openAIImageSize(model, opts.AspectRatio)
+
+	var result *openai.ImagesResponse
+	var err error
+	if request.ReferenceImage != nil {
+		result, err = c.editImage(ctx, model, size, request.Prompt, request.ReferenceImage, nil, opts)
+	} else {
+		params := openai.ImageGenerateParams{
+			Prompt: request.Prompt,
+			Model:  openai.ImageModel(model),
+			N:      openai.Int(int64(opts.NumberOfImages)),
+			Size:   openai.ImageGenerateParamsSize(size),
+		}
+		if dallE {
+			// DALL·E returns URLs unless asked for base64
+			params.ResponseFormat = openai.ImageGenerateParamsResponseFormatB64JSON
+		} else {
+			params.OutputFormat = openai.ImageGenerateParamsOutputFormat(opts.OutputFormat)
+			if opts.SafetyFilterLevel == "none" || opts.SafetyFilterLevel == "low" {
+				params.Moderation = openai.ImageGenerateParamsModerationLow
+			}
+		}
+		result, err = c.Client.Images.Generate(ctx, params)
+	}
+	if err != nil {
+		return nil, imageError(err)
+	}
+
+	mimeType := "image/" + opts.OutputFormat
+	if dallE {
+		mimeType = "image/png"
+	}
+	return parseImagesResponse(result, model, mimeType)
+}
+
+// EditImage edits an image with the images edit endpoint. Fully transparent
+// areas of the mask mark where the image is edited.
+func (c *OpenAIClient) EditImage(ctx context.Context, request interfaces.ImageEditRequest) (*interfaces.ImageGenerationResponse, error) {
+	if request.Prompt == "" {
+		return nil, interfaces.ErrInvalidPrompt
+	}
+	if request.Image == nil {
+		return nil, fmt.Errorf("image is required")
+	}
+
+	// Without an aspect ratio, the edited image keeps the size of the input
+	opts := request.Options
+	if opts == nil {
+		opts = &interfaces.ImageGenerationOptions{}
+	}
+	if opts.NumberOfImages <= 0 {
+		opts.NumberOfImages = 1
+	}
+	if opts.OutputFormat == "" {
+		opts.OutputFormat = "png"
+	}
+
+	model := c.imageModel()
+	size := "auto"
+	if opts.AspectRatio != "" {
+		size = openAIImageSize(model, opts.AspectRatio)
+	}
+	if strings.HasPrefix(model, "dall-e") {
+		// DALL·E does not support automatic sizes; dall-e-2 is the DALL·E edit model
+		model = "dall-e-2"
+		size = "1024x1024"
+	}
+
+	result, err := c.editImage(ctx, model, size, request.Prompt, request.Image, request.Mask, opts)
+	if err != nil {
+		return nil, imageError(err)
+	}
+
+	mimeType := "image/" + opts.OutputFormat
+	if model == "dall-e-2" {
+		mimeType = "image/png"
+	}
+	return parseImagesResponse(result, model, mimeType)
+}
+
+// editImage generates images from a prompt and an input image, optionally
+// limited to the transparent areas of a mask
+func (c *OpenAIClient) editImage(ctx context.Context, model, size, prompt string, image, mask *interfaces.ImageData, opts *interfaces.ImageGenerationOptions) (*openai.ImagesResponse, error) {
+	imageFile, err := imageDataFile(image, "image")
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	params := openai.ImageEditParams{
+		Image:  openai.ImageEditParamsImageUnion{OfFile: imageFile},
+		Prompt: prompt,
+		Model:  openai.ImageModel(model),
+		N:      openai.Int(int64(opts.NumberOfImages)),
+		Size:   openai.ImageEditParamsSize(size),
+	}
+	if mask != nil {
+		params.Mask, err = imageDataFile(mask, "mask")
+		if err != nil {
+			return nil, fmt.Errorf("invalid mask: %w", err)
+		}
+	}
+	if strings.HasPrefix(model, "dall-e") {
+		params.ResponseFormat = openai.ImageEditParamsResponseFormatB64JSON
+	} else {
+		params.OutputFormat = openai.ImageEditParamsOutputFormat(opts.OutputFormat)
+	}
+	return c.Client.Images.Edit(ctx, params)
+}
+
+// imageDataFile returns image data as a named file for multipart uploads
+func imageDataFile(image *interfaces.ImageData, name string) (io.Reader, error) {
+	data := image.Data
+	if data == nil && image.Base64 != "" {
+		decoded, err := base64.StdEncoding.DecodeString(image.Base64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+		data = decoded
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("image has no data")
+	}
+	mimeType := image.MimeType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	return openai.File(bytes.NewReader(data), name+"."+strings.TrimPrefix(mimeType, "image/"), mimeType), nil
+}
+
+// openAIImageSize returns the image size closest to an aspect ratio for a model
+func openAIImageSize(model, aspectRatio string) string {
+	orientation := 0
+	if w, h, ok := strings.Cut(aspectRatio, ":"); ok {
+		width, errW := strconv.ParseFloat(w, 64)
+		height, errH := strconv.ParseFloat(h, 64)
+		if errW == nil && errH == nil {
+			switch {
+			case width > height:
+				orientation = 1
+			case width < height:
+				orientation = -1
+			}
+		}
+	}
+
+	switch {
+	case model == "dall-e-2":
+		return "1024x1024"
+	case strings.HasPrefix(model, "dall-e"):
+		switch orientation {
+		case 1:
+			return "1792x1024"
+		case -1:
+			return "1024x1792"
+		}
+	default:
+		switch orientation {
+		case 1:
+			return "1536x1024"
+		case -1:
+			return "1024x1536"
+		}
+	}
+	return "1024x1024"
+}
+
+// parseImagesResponse converts an images API response
+func parseImagesResponse(result *openai.ImagesResponse, model, mimeType string) (*interfaces.ImageGenerationResponse, error) {
+	response := &interfaces.ImageGenerationResponse{
+		Images:   make([]interfaces.GeneratedImage, 0, len(result.Data)),
+		Metadata: map[string]interface{}{"model": model},
+	}
+
+	for _, image := range result.Data {
+		if image.B64JSON == "" {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(image.B64JSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+		response.Images = append(response.Images, interfaces.GeneratedImage{
+			Data:          data,
+			Base64:        image.B64JSON,
+			MimeType:      mimeType,
+			RevisedPrompt: image.RevisedPrompt,
+		})
+	}
+	if len(response.Images) == 0 {
+		return nil, fmt.Errorf("no images generated in response")
+	}
+
+	if result.Usage.TotalTokens > 0 {
+		response.Usage = &interfaces.ImageUsage{
+			InputTokens:     int(result.Usage.InputTokens),
+			OutputTokens:    int(result.Usage.OutputTokens),
+			ImagesGenerated: len(response.Images),
+		}
+	}
+	return response, nil
+}
+
+// imageError maps images API errors to the interfaces image generation errors
+func imageError(err error) error {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Code == "moderation_blocked" || apiErr.Code == "content_policy_violation":
+			return fmt.Errorf("%w: %s", interfaces.ErrContentBlocked, apiErr.Message)
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return fmt.Errorf("%w: %s", interfaces.ErrRateLimitExceeded, apiErr.Message)
+		}
+	}
+	return fmt.Errorf("image generation failed: %w", err)
+}
+
+var (
+	_ interfaces.ImageGenerator = (*OpenAIClient)(nil)
+	_ interfaces.ImageEditor    = (*OpenAIClient)(nil)
+)
@@ -0,0 +1,115 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestGenerateImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images/generations" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if body["model"] != "gpt-image-1" || body["size"] != "1536x1024" || body["output_format"] != "jpeg" {
+			t.Errorf("unexpected request %v", body)
+		}
+		if _, ok := body["response_format"]; ok {
+			t.Error("response_format is not supported by gpt-image-1")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"created":1,"data":[{"b64_json":"aW1hZ2U="}],"usage":{"input_tokens":10,"output_tokens":20,"total_tokens":30}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	response, err := client.GenerateImage(context.Background(), interfaces.ImageGenerationRequest{
+		Prompt:  "A lighthouse",
+		Options: &interfaces.ImageGenerationOptions{AspectRatio: "16:9", OutputFormat: "jpeg"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Images) != 1 || string(response.Images[0].Data) != "image" || response.Images[0].MimeType != "image/jpeg" {
+		t.Errorf("unexpected images %+v", response.Images)
+	}
+	if response.Usage == nil || response.Usage.OutputTokens != 20 {
+		t.Errorf("unexpected usage %+v", response.Usage)
+	}
+}
+
+func TestGenerateImageBlocked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":"moderation_blocked","message":"rejected","type":"image_generation_user_error"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithModel("dall-e-3"))
+	_, err := client.GenerateImage(context.Background(), interfaces.ImageGenerationRequest{Prompt: "x"})
+	if !errors.Is(err, interfaces.ErrContentBlocked) {
+		t.Errorf("expected ErrContentBlocked, got %v", err)
+	}
+}
+
+func TestOpenAIImageSize(t *testing.T) {
+	tests := []struct {
+		model, aspectRatio, want string
+	}{
+		{"gpt-image-1", "1:1", "1024x1024"},
+		{"gpt-image-1", "4:3", "1536x1024"},
+		{"gpt-image-1", "9:16", "1024x1536"},
+		{"dall-e-3", "16:9", "1792x1024"},
+		{"dall-e-3", "2:3", "1024x1792"},
+		{"dall-e-2", "16:9", "1024x1024"},
+		{"gpt-image-1", "", "1024x1024"},
+	}
+	for _, tt := range tests {
+		if got := openAIImageSize(tt.model, tt.aspectRatio); got != tt.want {
+			t.Errorf("openAIImageSize(%q, %q) = %q, want %q", tt.model, tt.aspectRatio, got, tt.want)
+		}
+	}
+}
+
+func TestEditImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images/edits" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if r.FormValue("prompt") != "Add a hat" || r.FormValue("size") != "auto" {
+			t.Errorf("unexpected form %v", r.MultipartForm.Value)
+		}
+		if len(r.MultipartForm.File["image"]) != 1 || len(r.MultipartForm.File["mask"]) != 1 {
+			t.Errorf("expected an image and a mask, got %v", r.MultipartForm.File)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"created":1,"data":[{"b64_json":"aW1hZ2U="}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	response, err := client.EditImage(context.Background(), interfaces.ImageEditRequest{
+		Prompt: "Add a hat",
+		Image:  &interfaces.ImageData{Data: []byte("\x89PNG\r\n\x1a\n")},
+		Mask:   &interfaces.ImageData{Data: []byte("\x89PNG\r\n\x1a\n"), MimeType: "image/png"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Images) != 1 || string(response.Images[0].Data) != "image" {
+		t.Errorf("unexpected images %+v", response.Images)
+	}
+}
@@ -21,8 +21,10 @@ func newMessageHistoryBuilder(logger logging.Logger) *messageHistoryBuilder {
 }
 
 // buildMessages constructs OpenAI messages from memory and current prompt
-// Returns messages ready for OpenAI API calls, preserving chronological order
-func (b *messageHistoryBuilder) buildMessages(ctx context.Context, prompt string, memory interfaces.Memory) []openai.ChatCompletionMessageParamUnion {
+// Returns messages ready for OpenAI API calls, preserving chronological order.
+// Content parts are attached to the prompt when memory is nil; otherwise the
+// prompt and its parts are expected in memory.
+func (b *messageHistoryBuilder) buildMessages(ctx context.Context, prompt string, memory interfaces.Memory, parts ...interfaces.ContentPart) []openai.ChatCompletionMessageParamUnion {
 	messages := []openai.ChatCompletionMessageParamUnion{}
 
 	// Add memory messages
@@ -43,7 +45,7 @@ func (b *messageHistoryBuilder) buildMessages(ctx context.Context, prompt string
 		}
 	} else {
 		// Only append current user message when memory is nil
-		messages = append(messages, openai.UserMessage(prompt))
+		messages = append(messages, userMessage(prompt, parts))
 	}
 
 	return messages
@@ -53,7 +55,7 @@ func (b *messageHistoryBuilder) buildMessages(ctx context.Context, prompt string
 func (b *messageHistoryBuilder) convertMemoryMessage(msg interfaces.Message) *openai.ChatCompletionMessageParamUnion {
 	switch msg.Role {
 	case interfaces.MessageRoleUser:
-		userMsg := openai.UserMessage(msg.Content)
+		userMsg := userMessage(msg.Content, msg.ContentParts)
 		return &userMsg
 
 	case interfaces.MessageRoleAssistant:
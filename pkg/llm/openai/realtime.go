@@ -0,0 +1,367 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// DefaultRealtimeModel is the model of realtime sessions when none is configured
+const DefaultRealtimeModel = "gpt-4o-realtime-preview"
+
+// realtimeAudioFormat is the input and output audio format of OpenAI realtime
+// sessions: 24kHz 16-bit mono PCM
+const realtimeAudioFormat = "audio/pcm;rate=24000"
+
+// realtimeMessage is a client or server event of the OpenAI Realtime API.
+// Only the fields used by RealtimeSession are declared.
+type realtimeMessage struct {
+	Type       string                 `json:"type"`
+	Session    map[string]interface{} `json:"session,omitempty"`
+	Item       map[string]interface{} `json:"item,omitempty"`
+	Audio      string                 `json:"audio,omitempty"`
+	Delta      string                 `json:"delta,omitempty"`
+	Transcript string                 `json:"transcript,omitempty"`
+	CallID     string                 `json:"call_id,omitempty"`
+	Name       string                 `json:"name,omitempty"`
+	Arguments  string                 `json:"arguments,omitempty"`
+	Response   *struct {
+		Status string `json:"status"`
+	} `json:"response,omitempty"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// RealtimeSession is a session with the OpenAI Realtime API. Turn detection
+// runs on the server: the model responds when the user stops speaking and
+// stops responding when the user starts speaking.
+type RealtimeSession struct {
+	conn      *websocket.Conn
+	events    chan interfaces.RealtimeEvent
+	writeMu   sync.Mutex
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// mu guards the tool call state of the current response
+	mu           sync.Mutex
+	pendingCalls map[string]bool
+	hasCalls     bool
+	responding   bool
+}
+
+// ConnectRealtime opens a realtime session with the OpenAI Realtime API
+func (c *OpenAIClient) ConnectRealtime(ctx context.Context, config interfaces.RealtimeConfig) (interfaces.RealtimeSession, error) {
+	model := config.Model
+	if model == "" {
+		model = DefaultRealtimeModel
+	}
+	endpoint, err := realtimeURL(c.baseURL, model)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+c.apiKey)
+	header.Set("OpenAI-Beta", "realtime=v1")
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, endpoint, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to connect to OpenAI realtime API: %w (status %d)", err, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to connect to OpenAI realtime API: %w", err)
+	}
+
+	session := &RealtimeSession{
+		conn:         conn,
+		events:       make(chan interfaces.RealtimeEvent, 64),
+		done:         make(chan struct{}),
+		pendingCalls: make(map[string]bool),
+	}
+	if err := session.send(realtimeMessage{Type: "session.update", Session: realtimeSessionConfig(config)}); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to configure realtime session: %w", err)
+	}
+
+	c.logger.Debug(ctx, "Connected realtime session", map[string]interface{}{"model": model})
+	go session.receive()
+	return session, nil
+}
+
+// realtimeURL returns the WebSocket URL of the realtime API for an API base URL
+func realtimeURL(baseURL, model string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/realtime")
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+	u.RawQuery = url.Values{"model": {model}}.Encode()
+	return u.String(), nil
+}
+
+// realtimeSessionConfig returns the session.update payload for a config
+func realtimeSessionConfig(config interfaces.RealtimeConfig) map[string]interface{} {
+	session := map[string]interface{}{
+		"modalities":          []string{"text", "audio"},
+		"input_audio_format":  "pcm16",
+		"output_audio_format": "pcm16",
+		"input_audio_transcription": map[string]interface{}{
+			"model": "whisper-1",
+		},
+		"turn_detection": map[string]interface{}{
+			"type": "server_vad",
+		},
+	}
+	if config.TextOnly {
+		session["modalities"] = []string{"text"}
+	}
+	if config.Instructions != "" {
+		session["instructions"] = config.Instructions
+	}
+	if config.Voice != "" {
+		session["voice"] = config.Voice
+	}
+	if len(config.Tools) > 0 {
+		tools := make([]map[string]interface{}, len(config.Tools))
+		for i, tool := range config.Tools {
+			tools[i] = map[string]interface{}{
+				"type":        "function",
+				"name":        tool.Name(),
+				"description": tool.Description(),
+				"parameters":  toolParameters(tool),
+			}
+		}
+		session["tools"] = tools
+		session["tool_choice"] = "auto"
+	}
+	return session
+}
+
+// toolParameters converts the parameters of a tool to JSON Schema
+func toolParameters(tool interfaces.Tool) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	for name, param := range tool.Parameters() {
+		property := map[string]interface{}{
+			"type":        param.Type,
+			"description": param.Description,
+		}
+		if param.Default != nil {
+			property["default"] = param.Default
+		}
+		if param.Items != nil {
+			items := map[string]interface{}{"type": param.Items.Type}
+			if param.Items.Enum != nil {
+				items["enum"] = param.Items.Enum
+			}
+			property["items"] = items
+		}
+		if param.Enum != nil {
+			property["enum"] = param.Enum
+		}
+		if param.Required {
+			required = append(required, name)
+		}
+		properties[name] = property
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// send writes a client event; gorilla connections allow one writer at a time
+func (s *RealtimeSession) send(msg realtimeMessage) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteJSON(msg)
+}
+
+// SendAudio appends 24kHz 16-bit mono PCM audio to the input buffer
+func (s *RealtimeSession) SendAudio(ctx context.Context, audio []byte) error {
+	return s.send(realtimeMessage{Type: "input_audio_buffer.append", Audio: base64.StdEncoding.EncodeToString(audio)})
+}
+
+// SendText adds a user message to the conversation and asks for a response
+func (s *RealtimeSession) SendText(ctx context.Context, text string) error {
+	err := s.send(realtimeMessage{Type: "conversation.item.create", Item: map[string]interface{}{
+		"type": "message",
+		"role": "user",
+		"content": []map[string]interface{}{
+			{"type": "input_text", "text": text},
+		},
+	}})
+	if err != nil {
+		return err
+	}
+	return s.send(realtimeMessage{Type: "response.create"})
+}
+
+// SendToolResult adds the output of a function call to the conversation. The
+// model continues once the results of all calls of a response are sent.
+func (s *RealtimeSession) SendToolResult(ctx context.Context, result interfaces.ToolResult) error {
+	output := result.Content
+	if result.IsError {
+		output = "Error: " + output
+	}
+	err := s.send(realtimeMessage{Type: "conversation.item.create", Item: map[string]interface{}{
+		"type":    "function_call_output",
+		"call_id": result.ToolCallID,
+		"output":  output,
+	}})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.pendingCalls, result.ToolCallID)
+	ready := len(s.pendingCalls) == 0 && !s.responding
+	s.mu.Unlock()
+	if ready {
+		return s.send(realtimeMessage{Type: "response.create"})
+	}
+	return nil
+}
+
+// Interrupt cancels the response in progress
+func (s *RealtimeSession) Interrupt(ctx context.Context) error {
+	return s.send(realtimeMessage{Type: "response.cancel"})
+}
+
+// InputAudioFormat returns audio/pcm;rate=24000
+func (s *RealtimeSession) InputAudioFormat() string {
+	return realtimeAudioFormat
+}
+
+// Events returns the events of the session
+func (s *RealtimeSession) Events() <-chan interfaces.RealtimeEvent {
+	return s.events
+}
+
+// Close ends the session
+func (s *RealtimeSession) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return s.conn.Close()
+}
+
+// emit sends an event, returning false once the session is closed
+func (s *RealtimeSession) emit(event interfaces.RealtimeEvent) bool {
+	select {
+	case s.events <- event:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// receive converts server events to realtime events until the connection closes
+func (s *RealtimeSession) receive() {
+	defer close(s.events)
+
+	for {
+		var msg realtimeMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			select {
+			case <-s.done:
+			default:
+				if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					s.emit(interfaces.RealtimeEvent{Type: interfaces.RealtimeEventError, Error: fmt.Errorf("realtime connection failed: %w", err)})
+				}
+			}
+			return
+		}
+
+		event, ok, err := s.handle(msg)
+		if err != nil {
+			event, ok = interfaces.RealtimeEvent{Type: interfaces.RealtimeEventError, Error: err}, true
+		}
+		if ok && !s.emit(event) {
+			return
+		}
+	}
+}
+
+// handle converts a server event, reporting false for events that have no
+// realtime event
+func (s *RealtimeSession) handle(msg realtimeMessage) (interfaces.RealtimeEvent, bool, error) {
+	switch msg.Type {
+	case "response.created":
+		s.mu.Lock()
+		s.responding = true
+		s.mu.Unlock()
+
+	case "response.audio.delta":
+		audio, err := base64.StdEncoding.DecodeString(msg.Delta)
+		if err != nil {
+			return interfaces.RealtimeEvent{}, false, fmt.Errorf("invalid audio delta: %w", err)
+		}
+		return interfaces.RealtimeEvent{Type: interfaces.RealtimeEventAudio, Audio: audio, MIMEType: realtimeAudioFormat}, true, nil
+
+	case "response.audio_transcript.delta", "response.text.delta":
+		return interfaces.RealtimeEvent{Type: interfaces.RealtimeEventTranscript, Text: msg.Delta}, true, nil
+
+	case "conversation.item.input_audio_transcription.completed":
+		return interfaces.RealtimeEvent{Type: interfaces.RealtimeEventInputTranscript, Text: msg.Transcript}, true, nil
+
+	case "input_audio_buffer.speech_started":
+		return interfaces.RealtimeEvent{Type: interfaces.RealtimeEventInterrupted}, true, nil
+
+	case "response.function_call_arguments.done":
+		s.mu.Lock()
+		s.pendingCalls[msg.CallID] = true
+		s.hasCalls = true
+		s.mu.Unlock()
+		return interfaces.RealtimeEvent{
+			Type:     interfaces.RealtimeEventToolCall,
+			ToolCall: &interfaces.ToolCall{ID: msg.CallID, Name: msg.Name, Arguments: msg.Arguments},
+		}, true, nil
+
+	case "response.done":
+		s.mu.Lock()
+		s.responding = false
+		hasCalls, ready := s.hasCalls, len(s.pendingCalls) == 0
+		s.hasCalls = false
+		s.mu.Unlock()
+
+		if msg.Response != nil && msg.Response.Status == "cancelled" {
+			return interfaces.RealtimeEvent{Type: interfaces.RealtimeEventInterrupted}, true, nil
+		}
+		if hasCalls {
+			// The turn continues with a new response once all tool results
+			// are sent; SendToolResult starts it if some are still missing
+			if ready {
+				if err := s.send(realtimeMessage{Type: "response.create"}); err != nil {
+					return interfaces.RealtimeEvent{}, false, fmt.Errorf("failed to continue response: %w", err)
+				}
+			}
+			return interfaces.RealtimeEvent{}, false, nil
+		}
+		return interfaces.RealtimeEvent{Type: interfaces.RealtimeEventTurnComplete}, true, nil
+
+	case "error":
+		message := "unknown error"
+		if msg.Error != nil {
+			message = msg.Error.Message
+		}
+		return interfaces.RealtimeEvent{}, false, fmt.Errorf("realtime API error: %s", message)
+	}
+	return interfaces.RealtimeEvent{}, false, nil
+}
+
+var _ interfaces.RealtimeSession = (*RealtimeSession)(nil)
+var _ interfaces.RealtimeProvider = (*OpenAIClient)(nil)
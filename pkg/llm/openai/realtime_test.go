@@ -0,0 +1,158 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+type realtimeTestTool struct{}
+
+func (realtimeTestTool) Name() string        { return "weather" }
+func (realtimeTestTool) Description() string { return "Gets the weather" }
+func (realtimeTestTool) Run(ctx context.Context, input string) (string, error) {
+	return "sunny", nil
+}
+func (realtimeTestTool) Execute(ctx context.Context, args string) (string, error) {
+	return "sunny", nil
+}
+func (realtimeTestTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"city": {Type: "string", Description: "City", Required: true},
+	}
+}
+
+func TestRealtimeSession(t *testing.T) {
+	received := make(chan realtimeMessage, 10)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/realtime" || r.URL.Query().Get("model") != DefaultRealtimeModel {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" || r.Header.Get("OpenAI-Beta") != "realtime=v1" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		for _, msg := range []string{
+			`{"type":"response.created"}`,
+			`{"type":"response.audio.delta","delta":"AAEC"}`,
+			`{"type":"response.audio_transcript.delta","delta":"Let me check."}`,
+			`{"type":"response.function_call_arguments.done","call_id":"call-1","name":"weather","arguments":"{\"city\":\"Paris\"}"}`,
+			`{"type":"response.done","response":{"status":"completed"}}`,
+		} {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+		for {
+			var msg realtimeMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			received <- msg
+			if msg.Type == "response.create" {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"response.created"}`))
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"response.done","response":{"status":"completed"}}`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL+"/v1"))
+	session, err := client.ConnectRealtime(context.Background(), interfaces.RealtimeConfig{
+		Instructions: "Be brief.",
+		Voice:        "verse",
+		Tools:        []interfaces.Tool{realtimeTestTool{}},
+	})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	update := receiveMessage(t, received)
+	if update.Type != "session.update" || update.Session["instructions"] != "Be brief." || update.Session["voice"] != "verse" {
+		t.Errorf("unexpected session update %+v", update)
+	}
+	if tools, ok := update.Session["tools"].([]interface{}); !ok || len(tools) != 1 {
+		t.Errorf("expected one tool, got %v", update.Session["tools"])
+	}
+
+	audio := receiveEvent(t, session)
+	if audio.Type != interfaces.RealtimeEventAudio || string(audio.Audio) != "\x00\x01\x02" || audio.MIMEType != "audio/pcm;rate=24000" {
+		t.Errorf("unexpected audio event %+v", audio)
+	}
+	if event := receiveEvent(t, session); event.Type != interfaces.RealtimeEventTranscript || event.Text != "Let me check." {
+		t.Errorf("unexpected transcript event %+v", event)
+	}
+	call := receiveEvent(t, session)
+	if call.Type != interfaces.RealtimeEventToolCall || call.ToolCall.ID != "call-1" || call.ToolCall.Arguments != `{"city":"Paris"}` {
+		t.Fatalf("unexpected tool call event %+v", call)
+	}
+
+	// The response with the tool call does not complete the turn; the
+	// result starts a new response, which does
+	if err := session.SendToolResult(context.Background(), interfaces.ToolResult{ToolCallID: "call-1", Content: "sunny"}); err != nil {
+		t.Fatalf("failed to send tool result: %v", err)
+	}
+	output := receiveMessage(t, received)
+	if output.Type != "conversation.item.create" || output.Item["call_id"] != "call-1" || output.Item["output"] != "sunny" {
+		t.Errorf("unexpected tool output %+v", output)
+	}
+	if msg := receiveMessage(t, received); msg.Type != "response.create" {
+		t.Errorf("expected response.create, got %+v", msg)
+	}
+	if event := receiveEvent(t, session); event.Type != interfaces.RealtimeEventTurnComplete {
+		t.Errorf("expected turn_complete, got %+v", event)
+	}
+
+	if err := session.Interrupt(context.Background()); err != nil {
+		t.Fatalf("failed to interrupt: %v", err)
+	}
+	if msg := receiveMessage(t, received); msg.Type != "response.cancel" {
+		t.Errorf("expected response.cancel, got %+v", msg)
+	}
+}
+
+func receiveMessage(t *testing.T, received <-chan realtimeMessage) realtimeMessage {
+	t.Helper()
+	select {
+	case msg := <-received:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a client message")
+		return realtimeMessage{}
+	}
+}
+
+func receiveEvent(t *testing.T, session interfaces.RealtimeSession) interfaces.RealtimeEvent {
+	t.Helper()
+	select {
+	case event := <-session.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return interfaces.RealtimeEvent{}
+	}
+}
+
+func TestRealtimeURL(t *testing.T) {
+	url, err := realtimeURL("https://api.openai.com/v1", "gpt-4o-realtime-preview")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "wss://api.openai.com/v1/realtime?model=gpt-4o-realtime-preview" {
+		t.Errorf("unexpected URL %s", url)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/openai/openai-go/v2"
 	"github.com/openai/openai-go/v2/shared"
@@ -59,13 +60,14 @@ func (c *OpenAIClient) GenerateStream(
 
 		// Build messages using unified builder
 		builder := newMessageHistoryBuilder(c.logger)
-		messages = append(messages, builder.buildMessages(ctx, prompt, params.Memory)...)
+		messages = append(messages, builder.buildMessages(ctx, prompt, params.Memory, params.ContentParts...)...)
 
 		// Create stream request
 		streamParams := openai.ChatCompletionNewParams{
 			Model:    openai.ChatModel(c.Model),
 			Messages: messages,
 		}
+		setGenerationLimits(&streamParams, params.LLMConfig)
 
 		// Reasoning models only support temperature=1 (default), so don't set it
 		if !isReasoningModel(c.Model) {
@@ -233,7 +235,7 @@ func (c *OpenAIClient) GenerateStream(
 			})
 			eventChan <- interfaces.StreamEvent{
 				Type:      interfaces.StreamEventError,
-				Error:     fmt.Errorf("openai streaming error: %w", err),
+				Error:     classifyError(fmt.Errorf("openai streaming error: %w", err)),
 				Timestamp: time.Now(),
 			}
 			return
@@ -253,6 +255,21 @@ func (c *OpenAIClient) GenerateStream(
 	return eventChan, nil
 }
 
+// convertStreamingTools converts tools to OpenAI format for streaming requests
+func (c *OpenAIClient) convertStreamingTools(tools []interfaces.Tool) []openai.ChatCompletionToolUnionParam {
+	openaiTools := make([]openai.ChatCompletionToolUnionParam, len(tools))
+	for i, tool := range tools {
+		schema := c.convertToOpenAISchema(tool.Parameters())
+
+		openaiTools[i] = openai.ChatCompletionFunctionTool(shared.FunctionDefinitionParam{
+			Name:        tool.Name(),
+			Description: openai.String(tool.Description()),
+			Parameters:  schema,
+		})
+	}
+	return openaiTools
+}
+
 // GenerateWithToolsStream implements interfaces.StreamingLLM.GenerateWithToolsStream with iterative tool calling
 func (c *OpenAIClient) GenerateWithToolsStream(
 	ctx context.Context,
@@ -271,12 +288,6 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 		option(params)
 	}
 
-	// Set default max iterations if not provided
-	maxIterations := params.MaxIterations
-	if maxIterations == 0 {
-		maxIterations = 2
-	}
-
 	// Check for organization ID in context
 	defaultOrgID := "default"
 	if id, err := multitenancy.GetOrgID(ctx); err == nil {
@@ -298,18 +309,6 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 	go func() {
 		defer close(eventChan)
 
-		// Convert tools to OpenAI format
-		openaiTools := make([]openai.ChatCompletionToolUnionParam, len(tools))
-		for i, tool := range tools {
-			schema := c.convertToOpenAISchema(tool.Parameters())
-
-			openaiTools[i] = openai.ChatCompletionFunctionTool(shared.FunctionDefinitionParam{
-				Name:        tool.Name(),
-				Description: openai.String(tool.Description()),
-				Parameters:  schema,
-			})
-		}
-
 		// Build messages starting with system message if provided
 		messages := []openai.ChatCompletionMessageParamUnion{}
 		if params.SystemMessage != "" {
@@ -319,7 +318,7 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 
 		// Build messages using unified builder
 		builder := newMessageHistoryBuilder(c.logger)
-		messages = append(messages, builder.buildMessages(ctx, prompt, params.Memory)...)
+		messages = append(messages, builder.buildMessages(ctx, prompt, params.Memory, params.ContentParts...)...)
 
 		// Send initial message start event
 		eventChan <- interfaces.StreamEvent{
@@ -327,515 +326,494 @@ func (c *OpenAIClient) GenerateWithToolsStream(
 			Timestamp: time.Now(),
 			Metadata: map[string]interface{}{
 				"model": c.Model,
-				"tools": len(openaiTools),
+				"tools": len(tools),
 			},
 		}
 
-		// Determine if we should filter intermediate content (for backward compatibility)
-		filterIntermediateContent := params.StreamConfig == nil || !params.StreamConfig.IncludeIntermediateMessages
-
-		// Track captured content for final iteration replay if filtering is enabled
-		var capturedContentEvents []interfaces.StreamEvent
-
-		// Track if we got a complete response (no tool calls)
-		gotCompleteResponse := false
-
-		// Iterative tool calling loop
-		for iteration := 0; iteration < maxIterations; iteration++ {
-			iterationHasContent := false
-			var iterationContentEvents []interfaces.StreamEvent
-			streamParams := openai.ChatCompletionNewParams{
-				Model:      openai.ChatModel(c.Model),
-				Messages:   messages,
-				Tools:      openaiTools,
-				ToolChoice: openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String("auto")},
+		// Run the tool-calling loop with the shared tool executor. The agent
+		// stores streamed tool calls in memory itself.
+		executor := llm.NewToolExecutor(c.logger, llm.WithToolTimeout(params.ToolTimeout), llm.WithParallelToolCalls(params.ParallelToolCalls), llm.WithToolsFunc(params.ToolsFunc))
+		policy := params.ToolLoop()
+		loop := &streamToolLoop{
+			client:        c,
+			params:        params,
+			maxIterations: policy.MaxIterations,
+			events:        eventChan,
+			messages:      messages,
+			// Filter intermediate content for backward compatibility
+			filterIntermediateContent: params.StreamConfig == nil || !params.StreamConfig.IncludeIntermediateMessages,
+		}
+		result, err := executor.Run(ctx, loop, tools, policy)
+		if err != nil {
+			eventChan <- interfaces.StreamEvent{
+				Type:      interfaces.StreamEventError,
+				Error:     err,
+				Timestamp: time.Now(),
 			}
+			return
+		}
 
-			// Reasoning models only support temperature=1 (default), so don't set it
-			if !isReasoningModel(c.Model) {
-				streamParams.Temperature = openai.Float(params.LLMConfig.Temperature)
+		// Replay the content captured during the tool iterations, and the
+		// result of a stop tool that ended the loop
+		loop.replay(ctx)
+		if result.Stopped {
+			eventChan <- interfaces.StreamEvent{
+				Type:      interfaces.StreamEventContentDelta,
+				Content:   result.Content,
+				Timestamp: time.Now(),
 			}
+		}
 
-			// Handle reasoning models
-			if isReasoningModel(c.Model) || (params.LLMConfig != nil && params.LLMConfig.EnableReasoning) {
-				streamParams.StreamOptions = openai.ChatCompletionStreamOptionsParam{
-					IncludeUsage: openai.Bool(true),
-				}
+		eventChan <- interfaces.StreamEvent{
+			Type:      interfaces.StreamEventMessageStop,
+			Timestamp: time.Now(),
+		}
 
-				if isReasoningModel(c.Model) {
-					c.logger.Debug(ctx, "Using reasoning model with built-in reasoning for tools", map[string]interface{}{
-						"model": c.Model,
-						"note":  "reasoning models have internal reasoning but don't expose raw thinking tokens in streaming",
-					})
-				} else {
-					c.logger.Debug(ctx, "Reasoning enabled for non-reasoning model with tools", map[string]interface{}{
-						"model": c.Model,
-						"note":  "reasoning tokens not supported for this model type",
-					})
-				}
-			}
+		c.logger.Debug(ctx, "Successfully completed OpenAI streaming request with tools", map[string]interface{}{
+			"model": c.Model,
+		})
+	}()
 
-			// Add other LLM parameters
-			if params.LLMConfig != nil {
-				// Reasoning models don't support top_p parameter
-				if params.LLMConfig.TopP > 0 && !isReasoningModel(c.Model) {
-					streamParams.TopP = openai.Float(params.LLMConfig.TopP)
-				}
-				if params.LLMConfig.FrequencyPenalty != 0 {
-					streamParams.FrequencyPenalty = openai.Float(params.LLMConfig.FrequencyPenalty)
-				}
-				if params.LLMConfig.PresencePenalty != 0 {
-					streamParams.PresencePenalty = openai.Float(params.LLMConfig.PresencePenalty)
-				}
-				// Set reasoning effort for reasoning models
-				if isReasoningModel(c.Model) && params.LLMConfig.Reasoning != "" {
-					streamParams.ReasoningEffort = shared.ReasoningEffort(params.LLMConfig.Reasoning)
-					c.logger.Debug(ctx, "Setting reasoning effort for tools streaming", map[string]interface{}{"reasoning_effort": params.LLMConfig.Reasoning})
-				}
-			}
+	return eventChan, nil
+}
 
-			c.logger.Debug(ctx, "Creating OpenAI streaming request with tools", map[string]interface{}{
-				"model":         c.Model,
-				"tools":         len(openaiTools),
-				"temperature":   params.LLMConfig.Temperature,
-				"iteration":     iteration + 1,
-				"maxIterations": maxIterations,
-				"message_count": len(messages),
-			})
+// streamToolLoop streams the requests of GenerateWithToolsStream from the
+// Chat Completions API, for the shared tool-calling loop
+type streamToolLoop struct {
+	client        *OpenAIClient
+	params        *interfaces.GenerateOptions
+	maxIterations int
+	events        chan<- interfaces.StreamEvent
+	messages      []openai.ChatCompletionMessageParamUnion
+
+	// filterIntermediateContent holds back the content of the tool
+	// iterations, captured to be replayed once the loop ended
+	filterIntermediateContent bool
+	captured                  []interfaces.StreamEvent
+}
 
-			// Debug log messages array for second iteration
-			if iteration > 0 {
-				c.logger.Debug(ctx, "Messages array for iteration", map[string]interface{}{
-					"iteration":     iteration + 1,
-					"message_count": len(messages),
-				})
-				for i, msg := range messages {
-					c.logger.Debug(ctx, "Message details", map[string]interface{}{
-						"index": i,
-						"type":  fmt.Sprintf("%T", msg),
-					})
-				}
-			}
+// Send implements llm.ToolLoopProvider.Send
+func (l *streamToolLoop) Send(ctx context.Context, iteration int, tools []interfaces.Tool) (llm.ToolLoopTurn, error) {
+	c := l.client
+	params := l.params
+	openaiTools := c.convertStreamingTools(tools)
+
+	iterationHasContent := false
+	var iterationContentEvents []interfaces.StreamEvent
+	streamParams := openai.ChatCompletionNewParams{
+		Model:      openai.ChatModel(c.Model),
+		Messages:   l.messages,
+		Tools:      openaiTools,
+		ToolChoice: openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String("auto")},
+	}
+	setGenerationLimits(&streamParams, params.LLMConfig)
 
-			// Create stream
-			stream := c.ChatService.Completions.NewStreaming(ctx, streamParams)
-			if stream.Err() != nil {
-				c.logger.Error(ctx, "Failed to create OpenAI streaming", map[string]interface{}{
-					"error": stream.Err().Error(),
-				})
-				eventChan <- interfaces.StreamEvent{
-					Type:      interfaces.StreamEventError,
-					Error:     fmt.Errorf("openai streaming error: %w", stream.Err()),
-					Timestamp: time.Now(),
-				}
-				return
-			}
+	// Reasoning models only support temperature=1 (default), so don't set it
+	if !isReasoningModel(c.Model) {
+		streamParams.Temperature = openai.Float(params.LLMConfig.Temperature)
+	}
 
-			// Track streaming state
-			var currentToolCall *interfaces.ToolCall
-			var toolCallBuffer strings.Builder
-			var assistantResponse openai.ChatCompletionMessage
-			var hasContent bool
-
-			// Process stream chunks
-			for stream.Next() {
-				chunk := stream.Current()
-
-				for _, choice := range chunk.Choices {
-					// Handle content
-					if choice.Delta.Content != "" {
-						hasContent = true
-						iterationHasContent = true
-						assistantResponse.Content += choice.Delta.Content
-
-						contentEvent := interfaces.StreamEvent{
-							Type:      interfaces.StreamEventContentDelta,
-							Content:   choice.Delta.Content,
-							Timestamp: time.Now(),
-							Metadata: map[string]interface{}{
-								"choice_index": choice.Index,
-								"iteration":    iteration + 1,
-							},
-						}
+	// Handle reasoning models
+	if isReasoningModel(c.Model) || (params.LLMConfig != nil && params.LLMConfig.EnableReasoning) {
+		streamParams.StreamOptions = openai.ChatCompletionStreamOptionsParam{
+			IncludeUsage: openai.Bool(true),
+		}
 
-						if filterIntermediateContent && len(openaiTools) > 0 && iteration < maxIterations-1 {
-							// Capture content for potential replay later
-							iterationContentEvents = append(iterationContentEvents, contentEvent)
-						} else {
-							// Stream content immediately
-							eventChan <- contentEvent
-						}
-					}
+		if isReasoningModel(c.Model) {
+			c.logger.Debug(ctx, "Using reasoning model with built-in reasoning for tools", map[string]interface{}{
+				"model": c.Model,
+				"note":  "reasoning models have internal reasoning but don't expose raw thinking tokens in streaming",
+			})
+		} else {
+			c.logger.Debug(ctx, "Reasoning enabled for non-reasoning model with tools", map[string]interface{}{
+				"model": c.Model,
+				"note":  "reasoning tokens not supported for this model type",
+			})
+		}
+	}
 
-					// Handle tool calls - OpenAI streams them incrementally
-					if len(choice.Delta.ToolCalls) > 0 {
-						for _, toolCall := range choice.Delta.ToolCalls {
-							if toolCall.Function.Name != "" || toolCall.Function.Arguments != "" {
-								// Check if this is a new tool call or continuation
-								if toolCall.Function.Name != "" {
-									// New tool call started
-									if currentToolCall != nil && toolCallBuffer.Len() > 0 {
-										// Finish previous tool call
-										currentToolCall.Arguments = toolCallBuffer.String()
-										eventChan <- interfaces.StreamEvent{
-											Type:      interfaces.StreamEventToolUse,
-											ToolCall:  currentToolCall,
-											Timestamp: time.Now(),
-										}
-									}
-
-									// Start new tool call
-									currentToolCall = &interfaces.ToolCall{
-										ID:   toolCall.ID,
-										Name: toolCall.Function.Name,
-									}
-									toolCallBuffer.Reset()
-
-									// Add to assistant response
-									assistantResponse.ToolCalls = append(assistantResponse.ToolCalls, openai.ChatCompletionMessageToolCallUnion{
-										ID:   toolCall.ID,
-										Type: "function",
-										Function: openai.ChatCompletionMessageFunctionToolCallFunction{
-											Name: toolCall.Function.Name,
-										},
-									})
-
-									c.logger.Debug(ctx, "Started new tool call", map[string]interface{}{
-										"tool_id":   toolCall.ID,
-										"tool_name": toolCall.Function.Name,
-									})
-								}
+	// Add other LLM parameters
+	if params.LLMConfig != nil {
+		// Reasoning models don't support top_p parameter
+		if params.LLMConfig.TopP > 0 && !isReasoningModel(c.Model) {
+			streamParams.TopP = openai.Float(params.LLMConfig.TopP)
+		}
+		if params.LLMConfig.FrequencyPenalty != 0 {
+			streamParams.FrequencyPenalty = openai.Float(params.LLMConfig.FrequencyPenalty)
+		}
+		if params.LLMConfig.PresencePenalty != 0 {
+			streamParams.PresencePenalty = openai.Float(params.LLMConfig.PresencePenalty)
+		}
+		// Set reasoning effort for reasoning models
+		if isReasoningModel(c.Model) && params.LLMConfig.Reasoning != "" {
+			streamParams.ReasoningEffort = shared.ReasoningEffort(params.LLMConfig.Reasoning)
+			c.logger.Debug(ctx, "Setting reasoning effort for tools streaming", map[string]interface{}{"reasoning_effort": params.LLMConfig.Reasoning})
+		}
+	}
 
-								// Accumulate arguments
-								if toolCall.Function.Arguments != "" {
-									toolCallBuffer.WriteString(toolCall.Function.Arguments)
-									// Update the last tool call arguments
-									if len(assistantResponse.ToolCalls) > 0 {
-										lastIdx := len(assistantResponse.ToolCalls) - 1
-										assistantResponse.ToolCalls[lastIdx].Function.Arguments += toolCall.Function.Arguments
-									}
-								}
-							}
-						}
-					}
+	c.logger.Debug(ctx, "Creating OpenAI streaming request with tools", map[string]interface{}{
+		"model":         c.Model,
+		"tools":         len(openaiTools),
+		"temperature":   params.LLMConfig.Temperature,
+		"iteration":     iteration + 1,
+		"maxIterations": l.maxIterations,
+		"message_count": len(l.messages),
+	})
+
+	// Create stream
+	stream := c.ChatService.Completions.NewStreaming(ctx, streamParams)
+	if stream.Err() != nil {
+		c.logger.Error(ctx, "Failed to create OpenAI streaming", map[string]interface{}{
+			"error": stream.Err().Error(),
+		})
+		return llm.ToolLoopTurn{}, classifyError(fmt.Errorf("openai streaming error: %w", stream.Err()))
+	}
 
-					// Check for finish reason
-					if choice.FinishReason == "tool_calls" && currentToolCall != nil {
-						// Finish last tool call
-						currentToolCall.Arguments = toolCallBuffer.String()
-						eventChan <- interfaces.StreamEvent{
-							Type:      interfaces.StreamEventToolUse,
-							ToolCall:  currentToolCall,
-							Timestamp: time.Now(),
-							Metadata: map[string]interface{}{
-								"finish_reason": "tool_calls",
-								"iteration":     iteration + 1,
-							},
-						}
-						currentToolCall = nil
-						toolCallBuffer.Reset()
+	// Hold back the content of the iterations that may call tools
+	filter := l.filterIntermediateContent && len(openaiTools) > 0 && iteration < l.maxIterations-1
 
-						c.logger.Debug(ctx, "Finished tool calls", map[string]interface{}{
-							"finish_reason": choice.FinishReason,
-							"iteration":     iteration + 1,
-						})
-					}
-				}
-			}
+	// Track streaming state
+	var currentToolCall *interfaces.ToolCall
+	var toolCallBuffer strings.Builder
+	var assistantResponse openai.ChatCompletionMessage
+	var hasContent bool
 
-			// Check for stream error
-			if err := stream.Err(); err != nil {
-				c.logger.Error(ctx, "OpenAI streaming with tools error", map[string]interface{}{
-					"error": err.Error(),
-					"model": c.Model,
-				})
-				eventChan <- interfaces.StreamEvent{
-					Type:      interfaces.StreamEventError,
-					Error:     fmt.Errorf("openai streaming error: %w", err),
+	// Process stream chunks
+	for stream.Next() {
+		chunk := stream.Current()
+
+		for _, choice := range chunk.Choices {
+			// Handle content
+			if choice.Delta.Content != "" {
+				hasContent = true
+				iterationHasContent = true
+				assistantResponse.Content += choice.Delta.Content
+
+				contentEvent := interfaces.StreamEvent{
+					Type:      interfaces.StreamEventContentDelta,
+					Content:   choice.Delta.Content,
 					Timestamp: time.Now(),
+					Metadata: map[string]interface{}{
+						"choice_index": choice.Index,
+						"iteration":    iteration + 1,
+					},
 				}
-				return
-			}
 
-			// Check if the model wants to use tools
-			if len(assistantResponse.ToolCalls) == 0 {
-				// No tool calls, we're done
-				if hasContent {
-					eventChan <- interfaces.StreamEvent{
-						Type:      interfaces.StreamEventContentComplete,
-						Timestamp: time.Now(),
-						Metadata: map[string]interface{}{
-							"iteration": iteration + 1,
-						},
-					}
+				if filter {
+					// Capture content for potential replay later
+					iterationContentEvents = append(iterationContentEvents, contentEvent)
+				} else {
+					// Stream content immediately
+					l.events <- contentEvent
 				}
-				gotCompleteResponse = true
-				break // Exit the iteration loop
 			}
 
-			// The model wants to use tools
-			c.logger.Info(ctx, "Processing tool calls", map[string]interface{}{
-				"count":     len(assistantResponse.ToolCalls),
-				"iteration": iteration + 1,
-			})
-
-			// Debug log all tool calls in assistant response
-			for i, tc := range assistantResponse.ToolCalls {
-				c.logger.Debug(ctx, "Assistant tool call", map[string]interface{}{
-					"index":     i,
-					"id":        tc.ID,
-					"id_length": len(tc.ID),
-					"name":      tc.Function.Name,
-					"args_len":  len(tc.Function.Arguments),
-				})
-			}
+			// Handle tool calls - OpenAI streams them incrementally
+			if len(choice.Delta.ToolCalls) > 0 {
+				for _, toolCall := range choice.Delta.ToolCalls {
+					if toolCall.Function.Name != "" || toolCall.Function.Arguments != "" {
+						// Check if this is a new tool call or continuation
+						if toolCall.Function.Name != "" {
+							// New tool call started
+							if currentToolCall != nil && toolCallBuffer.Len() > 0 {
+								// Finish previous tool call
+								currentToolCall.Arguments = toolCallBuffer.String()
+								l.events <- interfaces.StreamEvent{
+									Type:      interfaces.StreamEventToolUse,
+									ToolCall:  currentToolCall,
+									Timestamp: time.Now(),
+								}
+							}
 
-			// Add the assistant's message with tool calls to the conversation
-			assistantResponse.Role = "assistant"
-			messages = append(messages, assistantResponse.ToParam())
-
-			// Process each tool call
-			for _, toolCall := range assistantResponse.ToolCalls {
-				// Find the matching tool
-				var foundTool interfaces.Tool
-				for _, tool := range tools {
-					if tool.Name() == toolCall.Function.Name {
-						foundTool = tool
-						break
-					}
-				}
+							// Start new tool call
+							currentToolCall = &interfaces.ToolCall{
+								ID:   toolCall.ID,
+								Name: toolCall.Function.Name,
+							}
+							toolCallBuffer.Reset()
+
+							// Add to assistant response
+							assistantResponse.ToolCalls = append(assistantResponse.ToolCalls, openai.ChatCompletionMessageToolCallUnion{
+								ID:   toolCall.ID,
+								Type: "function",
+								Function: openai.ChatCompletionMessageFunctionToolCallFunction{
+									Name: toolCall.Function.Name,
+								},
+							})
 
-				if foundTool == nil {
-					c.logger.Error(ctx, "Tool not found", map[string]interface{}{
-						"tool_name": toolCall.Function.Name,
-					})
-					continue
-				}
+							c.logger.Debug(ctx, "Started new tool call", map[string]interface{}{
+								"tool_id":   toolCall.ID,
+								"tool_name": toolCall.Function.Name,
+							})
+						}
 
-				// Execute the tool
-				result, err := foundTool.Execute(ctx, toolCall.Function.Arguments)
-				if err != nil {
-					c.logger.Error(ctx, "Tool execution error", map[string]interface{}{
-						"tool_name": toolCall.Function.Name,
-						"error":     err.Error(),
-					})
-					result = fmt.Sprintf("Error executing tool: %v", err)
+						// Accumulate arguments
+						if toolCall.Function.Arguments != "" {
+							toolCallBuffer.WriteString(toolCall.Function.Arguments)
+							// Update the last tool call arguments
+							if len(assistantResponse.ToolCalls) > 0 {
+								lastIdx := len(assistantResponse.ToolCalls) - 1
+								assistantResponse.ToolCalls[lastIdx].Function.Arguments += toolCall.Function.Arguments
+							}
+						}
+					}
 				}
+			}
 
-				// Send tool result event
-				eventChan <- interfaces.StreamEvent{
-					Type:      interfaces.StreamEventToolResult,
+			// Check for finish reason
+			if choice.FinishReason == "tool_calls" && currentToolCall != nil {
+				// Finish last tool call
+				currentToolCall.Arguments = toolCallBuffer.String()
+				l.events <- interfaces.StreamEvent{
+					Type:      interfaces.StreamEventToolUse,
+					ToolCall:  currentToolCall,
 					Timestamp: time.Now(),
-					Content:   result,
-					ToolCall: &interfaces.ToolCall{
-						ID:        toolCall.ID,
-						Name:      toolCall.Function.Name,
-						Arguments: toolCall.Function.Arguments,
-					},
 					Metadata: map[string]interface{}{
-						"iteration": iteration + 1,
-						"result":    result,
+						"finish_reason": "tool_calls",
+						"iteration":     iteration + 1,
 					},
 				}
+				currentToolCall = nil
+				toolCallBuffer.Reset()
 
-				// Add the tool result to the conversation
-				c.logger.Debug(ctx, "Adding tool result to conversation", map[string]interface{}{
-					"tool_call_id":  toolCall.ID,
-					"id_length":     len(toolCall.ID),
-					"tool_name":     toolCall.Function.Name,
-					"result_length": len(result),
-				})
-
-				// Create tool message - correct parameter order: content first, then tool_call_id.
-				// The previous `len(toolCall.ID) > 40` guard was a defensive check
-				// to catch an ID/result swap, but any provider that hands out IDs
-				// longer than 40 characters (vLLM's OpenAI-compatible API emits
-				// `chatcmpl-tool-{uuid}` at 46) silently dropped the tool result
-				// here, leaving the agent stuck in an infinite tool-call loop
-				// because the message history never reflected the tool output
-				// (#299). Let the upstream ToolMessage constructor accept the ID
-				// verbatim - OpenAI spec places no 40-char bound on tool_call_id.
-				toolMessage := openai.ToolMessage(result, toolCall.ID)
-				c.logger.Debug(ctx, "Created tool message", map[string]interface{}{
-					"message_type": "tool",
+				c.logger.Debug(ctx, "Finished tool calls", map[string]interface{}{
+					"finish_reason": choice.FinishReason,
+					"iteration":     iteration + 1,
 				})
-				messages = append(messages, toolMessage)
-			}
-
-			// If we had content during this iteration and tools were called, capture it for final replay
-			if filterIntermediateContent && iterationHasContent && len(assistantResponse.ToolCalls) > 0 {
-				capturedContentEvents = append(capturedContentEvents, iterationContentEvents...)
 			}
 		}
+	}
 
-		// Replay captured content events if we filtered them during iterations
-		if filterIntermediateContent && len(capturedContentEvents) > 0 {
-			c.logger.Debug(ctx, "Replaying captured content events from tool iterations", map[string]interface{}{
-				"eventsCount": len(capturedContentEvents),
-			})
-			for _, contentEvent := range capturedContentEvents {
-				eventChan <- contentEvent
-			}
-		}
+	// Check for stream error
+	if err := stream.Err(); err != nil {
+		c.logger.Error(ctx, "OpenAI streaming with tools error", map[string]interface{}{
+			"error": err.Error(),
+			"model": c.Model,
+		})
+		return llm.ToolLoopTurn{}, classifyError(fmt.Errorf("openai streaming error: %w", err))
+	}
 
-		// If we got a complete response (no tool calls), skip the final synthesis call
-		if gotCompleteResponse {
-			c.logger.Debug(ctx, "Skipping final synthesis call - already got complete response", map[string]interface{}{
-				"maxIterations": maxIterations,
-			})
-			eventChan <- interfaces.StreamEvent{
-				Type:      interfaces.StreamEventMessageStop,
-				Timestamp: time.Now(),
-			}
-			return
-		}
+	turn := llm.ToolLoopTurn{Content: strings.TrimSpace(assistantResponse.Content)}
 
-		// If DisableFinalSummary is enabled, skip the final synthesis call
-		if params.DisableFinalSummary {
-			c.logger.Info(ctx, "DisableFinalSummary enabled, skipping final synthesis call", map[string]interface{}{
-				"maxIterations": maxIterations,
-			})
-			eventChan <- interfaces.StreamEvent{
-				Type:      interfaces.StreamEventMessageStop,
+	// No tool calls, the held back content is the response
+	if len(assistantResponse.ToolCalls) == 0 {
+		for _, contentEvent := range iterationContentEvents {
+			l.events <- contentEvent
+		}
+		if hasContent {
+			l.events <- interfaces.StreamEvent{
+				Type:      interfaces.StreamEventContentComplete,
 				Timestamp: time.Now(),
+				Metadata: map[string]interface{}{
+					"iteration": iteration + 1,
+				},
 			}
-			return
 		}
+		return turn, nil
+	}
 
-		// Final call without tools to get synthesis
-		c.logger.Info(ctx, "Maximum iterations reached, making final call without tools", map[string]interface{}{
-			"maxIterations": maxIterations,
+	// Debug log all tool calls in assistant response
+	for i, tc := range assistantResponse.ToolCalls {
+		c.logger.Debug(ctx, "Assistant tool call", map[string]interface{}{
+			"index":     i,
+			"id":        tc.ID,
+			"id_length": len(tc.ID),
+			"name":      tc.Function.Name,
+			"args_len":  len(tc.Function.Arguments),
 		})
+	}
 
-		// Add explicit message to inform LLM this is the final call
-		finalMessages := append(messages, openai.UserMessage("Please provide your final response based on the information available. Do not request any additional tools."))
+	// Add the assistant's message with tool calls to the conversation
+	assistantResponse.Role = "assistant"
+	l.messages = append(l.messages, assistantResponse.ToParam())
 
-		// Create final request without tools
-		finalStreamParams := openai.ChatCompletionNewParams{
-			Model:    openai.ChatModel(c.Model),
-			Messages: finalMessages,
-		}
+	// If we had content during this iteration and tools were called, capture it for final replay
+	if filter && iterationHasContent {
+		l.captured = append(l.captured, iterationContentEvents...)
+	}
 
-		// Reasoning models only support temperature=1 (default), so don't set it
-		if !isReasoningModel(c.Model) {
-			finalStreamParams.Temperature = openai.Float(params.LLMConfig.Temperature)
-		}
+	for _, toolCall := range assistantResponse.ToolCalls {
+		turn.Calls = append(turn.Calls, interfaces.ToolCall{
+			ID:        toolCall.ID,
+			Name:      toolCall.Function.Name,
+			Arguments: toolCall.Function.Arguments,
+		})
+	}
+	return turn, nil
+}
 
-		// Add structured output if specified
-		if params.ResponseFormat != nil {
-			jsonSchema := shared.ResponseFormatJSONSchemaJSONSchemaParam{
-				Name:   params.ResponseFormat.Name,
-				Schema: params.ResponseFormat.Schema,
-			}
+// AddResults implements llm.ToolLoopProvider.AddResults
+func (l *streamToolLoop) AddResults(ctx context.Context, iteration int, results []llm.ToolResult) error {
+	for _, toolResult := range results {
+		result := toolResult.Content
 
-			finalStreamParams.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
-				OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
-					Type:       "json_schema",
-					JSONSchema: jsonSchema,
-				},
-			}
+		// Send tool result event
+		l.events <- interfaces.StreamEvent{
+			Type:      interfaces.StreamEventToolResult,
+			Timestamp: time.Now(),
+			Content:   result,
+			ToolCall: &interfaces.ToolCall{
+				ID:        toolResult.Call.ID,
+				Name:      toolResult.Call.Name,
+				Arguments: toolResult.Call.Arguments,
+			},
+			Metadata: map[string]interface{}{
+				"iteration": iteration + 1,
+				"result":    result,
+			},
 		}
 
-		// Add other parameters
-		if params.LLMConfig != nil {
-			// Reasoning models don't support top_p parameter
-			if params.LLMConfig.TopP > 0 && !isReasoningModel(c.Model) {
-				finalStreamParams.TopP = openai.Float(params.LLMConfig.TopP)
-			}
-			if params.LLMConfig.FrequencyPenalty != 0 {
-				finalStreamParams.FrequencyPenalty = openai.Float(params.LLMConfig.FrequencyPenalty)
-			}
-			if params.LLMConfig.PresencePenalty != 0 {
-				finalStreamParams.PresencePenalty = openai.Float(params.LLMConfig.PresencePenalty)
-			}
-			// Set reasoning effort for reasoning models
-			if isReasoningModel(c.Model) && params.LLMConfig.Reasoning != "" {
-				finalStreamParams.ReasoningEffort = shared.ReasoningEffort(params.LLMConfig.Reasoning)
-				c.logger.Debug(ctx, "Setting reasoning effort for final call", map[string]interface{}{"reasoning_effort": params.LLMConfig.Reasoning})
-			}
+		// Add the tool result to the conversation
+		l.client.logger.Debug(ctx, "Adding tool result to conversation", map[string]interface{}{
+			"tool_call_id":  toolResult.Call.ID,
+			"id_length":     len(toolResult.Call.ID),
+			"tool_name":     toolResult.Call.Name,
+			"result_length": len(result),
+		})
+
+		// Create tool message - correct parameter order: content first, then tool_call_id.
+		// The previous `len(toolCall.ID) > 40` guard was a defensive check
+		// to catch an ID/result swap, but any provider that hands out IDs
+		// longer than 40 characters (vLLM's OpenAI-compatible API emits
+		// `chatcmpl-tool-{uuid}` at 46) silently dropped the tool result
+		// here, leaving the agent stuck in an infinite tool-call loop
+		// because the message history never reflected the tool output
+		// (#299). Let the upstream ToolMessage constructor accept the ID
+		// verbatim - OpenAI spec places no 40-char bound on tool_call_id.
+		l.messages = append(l.messages, openai.ToolMessage(result, toolResult.Call.ID))
+	}
+	return nil
+}
+
+// Conclude implements llm.ToolLoopProvider.Conclude
+func (l *streamToolLoop) Conclude(ctx context.Context, finalPrompt string) (string, error) {
+	c := l.client
+	params := l.params
+
+	// Replay the content captured during the tool iterations first
+	l.replay(ctx)
+
+	// Create final request without tools, with an explicit message to inform
+	// the LLM this is the final call
+	finalStreamParams := openai.ChatCompletionNewParams{
+		Model:    openai.ChatModel(c.Model),
+		Messages: append(l.messages, openai.UserMessage(finalPrompt)),
+	}
+	setGenerationLimits(&finalStreamParams, params.LLMConfig)
+
+	// Reasoning models only support temperature=1 (default), so don't set it
+	if !isReasoningModel(c.Model) {
+		finalStreamParams.Temperature = openai.Float(params.LLMConfig.Temperature)
+	}
+
+	// Add structured output if specified
+	if params.ResponseFormat != nil {
+		jsonSchema := shared.ResponseFormatJSONSchemaJSONSchemaParam{
+			Name:   params.ResponseFormat.Name,
+			Schema: params.ResponseFormat.Schema,
 		}
 
-		c.logger.Debug(ctx, "Making final streaming call without tools", map[string]interface{}{
-			"model": c.Model,
-		})
+		finalStreamParams.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+				Type:       "json_schema",
+				JSONSchema: jsonSchema,
+			},
+		}
+	}
 
-		// Create final stream
-		finalStream := c.ChatService.Completions.NewStreaming(ctx, finalStreamParams)
-		if finalStream.Err() != nil {
-			c.logger.Error(ctx, "Error in final streaming call without tools", map[string]interface{}{
-				"error": finalStream.Err().Error(),
-			})
-			eventChan <- interfaces.StreamEvent{
-				Type:      interfaces.StreamEventError,
-				Error:     fmt.Errorf("openai final streaming error: %w", finalStream.Err()),
-				Timestamp: time.Now(),
-			}
-			return
+	// Add other parameters
+	if params.LLMConfig != nil {
+		// Reasoning models don't support top_p parameter
+		if params.LLMConfig.TopP > 0 && !isReasoningModel(c.Model) {
+			finalStreamParams.TopP = openai.Float(params.LLMConfig.TopP)
+		}
+		if params.LLMConfig.FrequencyPenalty != 0 {
+			finalStreamParams.FrequencyPenalty = openai.Float(params.LLMConfig.FrequencyPenalty)
+		}
+		if params.LLMConfig.PresencePenalty != 0 {
+			finalStreamParams.PresencePenalty = openai.Float(params.LLMConfig.PresencePenalty)
+		}
+		// Set reasoning effort for reasoning models
+		if isReasoningModel(c.Model) && params.LLMConfig.Reasoning != "" {
+			finalStreamParams.ReasoningEffort = shared.ReasoningEffort(params.LLMConfig.Reasoning)
+			c.logger.Debug(ctx, "Setting reasoning effort for final call", map[string]interface{}{"reasoning_effort": params.LLMConfig.Reasoning})
 		}
+	}
 
-		// Track final content for memory storage
-		var finalContent strings.Builder
+	c.logger.Debug(ctx, "Making final streaming call without tools", map[string]interface{}{
+		"model": c.Model,
+	})
 
-		// Process final stream
-		for finalStream.Next() {
-			chunk := finalStream.Current()
+	// Create final stream
+	finalStream := c.ChatService.Completions.NewStreaming(ctx, finalStreamParams)
+	if finalStream.Err() != nil {
+		c.logger.Error(ctx, "Error in final streaming call without tools", map[string]interface{}{
+			"error": finalStream.Err().Error(),
+		})
+		return "", classifyError(fmt.Errorf("openai final streaming error: %w", finalStream.Err()))
+	}
 
-			for _, choice := range chunk.Choices {
-				// Handle final content
-				if choice.Delta.Content != "" {
-					finalContent.WriteString(choice.Delta.Content)
-					eventChan <- interfaces.StreamEvent{
-						Type:      interfaces.StreamEventContentDelta,
-						Content:   choice.Delta.Content,
-						Timestamp: time.Now(),
-						Metadata: map[string]interface{}{
-							"choice_index": choice.Index,
-							"final_call":   true,
-						},
-					}
-				}
+	// Track final content
+	var finalContent strings.Builder
 
-				// Check for finish reason
-				if choice.FinishReason != "" {
-					eventChan <- interfaces.StreamEvent{
-						Type: interfaces.StreamEventContentComplete,
-						Metadata: map[string]interface{}{
-							"finish_reason": choice.FinishReason,
-							"choice_index":  choice.Index,
-							"final_call":    true,
-						},
-						Timestamp: time.Now(),
-					}
+	// Process final stream
+	for finalStream.Next() {
+		chunk := finalStream.Current()
+
+		for _, choice := range chunk.Choices {
+			// Handle final content
+			if choice.Delta.Content != "" {
+				finalContent.WriteString(choice.Delta.Content)
+				l.events <- interfaces.StreamEvent{
+					Type:      interfaces.StreamEventContentDelta,
+					Content:   choice.Delta.Content,
+					Timestamp: time.Now(),
+					Metadata: map[string]interface{}{
+						"choice_index": choice.Index,
+						"final_call":   true,
+					},
 				}
 			}
-		}
 
-		// Check for final stream error
-		if err := finalStream.Err(); err != nil {
-			c.logger.Error(ctx, "OpenAI final streaming error", map[string]interface{}{
-				"error": err.Error(),
-				"model": c.Model,
-			})
-			eventChan <- interfaces.StreamEvent{
-				Type:      interfaces.StreamEventError,
-				Error:     fmt.Errorf("openai final streaming error: %w", err),
-				Timestamp: time.Now(),
+			// Check for finish reason
+			if choice.FinishReason != "" {
+				l.events <- interfaces.StreamEvent{
+					Type: interfaces.StreamEventContentComplete,
+					Metadata: map[string]interface{}{
+						"finish_reason": choice.FinishReason,
+						"choice_index":  choice.Index,
+						"final_call":    true,
+					},
+					Timestamp: time.Now(),
+				}
 			}
-			return
-		}
-
-		// Send final message stop event
-		eventChan <- interfaces.StreamEvent{
-			Type:      interfaces.StreamEventMessageStop,
-			Timestamp: time.Now(),
 		}
+	}
 
-		c.logger.Debug(ctx, "Successfully completed OpenAI streaming request with tools", map[string]interface{}{
+	// Check for final stream error
+	if err := finalStream.Err(); err != nil {
+		c.logger.Error(ctx, "OpenAI final streaming error", map[string]interface{}{
+			"error": err.Error(),
 			"model": c.Model,
 		})
-	}()
+		return "", classifyError(fmt.Errorf("openai final streaming error: %w", err))
+	}
 
-	return eventChan, nil
+	return finalContent.String(), nil
+}
+
+// replay streams the content captured during the tool iterations
+func (l *streamToolLoop) replay(ctx context.Context) {
+	if len(l.captured) == 0 {
+		return
+	}
+	l.client.logger.Debug(ctx, "Replaying captured content events from tool iterations", map[string]interface{}{
+		"eventsCount": len(l.captured),
+	})
+	for _, contentEvent := range l.captured {
+		l.events <- contentEvent
+	}
+	l.captured = nil
 }
 
 // convertToOpenAISchema converts tool parameters to OpenAI function schema
@@ -0,0 +1,180 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// DefaultVideoModel is the Sora model used when the client's model is not a video model
+const DefaultVideoModel = "sora-2"
+
+// soraVideo is a video job of the OpenAI videos API
+type soraVideo struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"` // queued, in_progress, completed or failed
+	Progress int    `json:"progress"`
+	Error    *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// videoModel returns the client's model when it is a Sora model, otherwise DefaultVideoModel
+func (c *OpenAIClient) videoModel() string {
+	if strings.HasPrefix(c.Model, "sora") {
+		return c.Model
+	}
+	return DefaultVideoModel
+}
+
+// StartVideoGeneration starts generating a video with Sora. Reference images
+// are not supported.
+func (c *OpenAIClient) StartVideoGeneration(ctx context.Context, request interfaces.VideoGenerationRequest) (*interfaces.VideoGenerationJob, error) {
+	if request.Prompt == "" {
+		return nil, interfaces.ErrInvalidPrompt
+	}
+	if request.ReferenceImage != nil {
+		return nil, fmt.Errorf("%w: reference images are not supported for Sora", interfaces.ErrVideoGenerationNotSupported)
+	}
+
+	opts := request.Options
+	if opts == nil {
+		opts = interfaces.DefaultVideoGenerationOptions()
+	}
+	size, err := soraSize(opts.AspectRatio, opts.Resolution)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]string{
+		"model":  c.videoModel(),
+		"prompt": request.Prompt,
+		"size":   size,
+	}
+	if opts.DurationSeconds > 0 {
+		body["seconds"] = strconv.Itoa(opts.DurationSeconds)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/videos", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var video soraVideo
+	if err := c.videoRequest(req, &video); err != nil {
+		return nil, fmt.Errorf("video generation failed: %w", err)
+	}
+	return &interfaces.VideoGenerationJob{ID: video.ID, Metadata: map[string]interface{}{"model": body["model"], "status": video.Status}}, nil
+}
+
+// soraSize returns the Sora video size for an aspect ratio and resolution
+func soraSize(aspectRatio, resolution string) (string, error) {
+	width, height := 1280, 720
+	if resolution == "1080p" {
+		width, height = 1792, 1024
+	}
+	switch aspectRatio {
+	case "", "16:9":
+		return fmt.Sprintf("%dx%d", width, height), nil
+	case "9:16":
+		return fmt.Sprintf("%dx%d", height, width), nil
+	default:
+		return "", fmt.Errorf("unsupported aspect ratio %q for Sora: use 16:9 or 9:16", aspectRatio)
+	}
+}
+
+// PollVideoGeneration returns the state of a Sora job, downloading the video
+// once it is completed
+func (c *OpenAIClient) PollVideoGeneration(ctx context.Context, job *interfaces.VideoGenerationJob) (*interfaces.VideoGenerationJob, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/videos/"+job.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	var video soraVideo
+	if err := c.videoRequest(req, &video); err != nil {
+		return nil, fmt.Errorf("failed to get video generation status: %w", err)
+	}
+
+	result := &interfaces.VideoGenerationJob{
+		ID:       video.ID,
+		Metadata: map[string]interface{}{"status": video.Status, "progress": video.Progress},
+	}
+	switch video.Status {
+	case "failed":
+		message := "unknown error"
+		if video.Error != nil {
+			message = video.Error.Message
+			if video.Error.Code == "moderation_blocked" {
+				return nil, fmt.Errorf("%w: %s", interfaces.ErrContentBlocked, message)
+			}
+		}
+		return nil, fmt.Errorf("video generation failed: %s", message)
+	case "completed":
+	default:
+		return result, nil
+	}
+
+	req, err = http.NewRequestWithContext(ctx, "GET", c.baseURL+"/videos/"+job.ID+"/content", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	data, err := c.videoContent(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download video: %w", err)
+	}
+
+	result.Done = true
+	result.Videos = []interfaces.GeneratedMedia{{
+		Data:     data,
+		Base64:   base64.StdEncoding.EncodeToString(data),
+		MimeType: "video/mp4",
+	}}
+	return result, nil
+}
+
+// videoRequest sends a videos API request and decodes the JSON response
+func (c *OpenAIClient) videoRequest(req *http.Request, out interface{}) error {
+	data, err := c.videoContent(req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// videoContent sends an authenticated videos API request and returns the response body
+func (c *OpenAIClient) videoContent(req *http.Request) ([]byte, error) {
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenAI API returned status %d: %s", resp.StatusCode, string(data))
+	}
+	return data, nil
+}
+
+var _ interfaces.VideoGenerator = (*OpenAIClient)(nil)
@@ -0,0 +1,100 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestVideoGeneration(t *testing.T) {
+	status := "in_progress"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing authorization header")
+		}
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/videos":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			if body["model"] != DefaultVideoModel || body["size"] != "720x1280" || body["seconds"] != "4" {
+				t.Errorf("unexpected request %v", body)
+			}
+			_, _ = w.Write([]byte(`{"id":"video_1","status":"queued"}`))
+		case r.URL.Path == "/v1/videos/video_1":
+			_, _ = w.Write([]byte(`{"id":"video_1","status":"` + status + `","progress":50}`))
+		case r.URL.Path == "/v1/videos/video_1/content":
+			_, _ = w.Write([]byte("mp4"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL+"/v1"))
+	ctx := context.Background()
+
+	job, err := client.StartVideoGeneration(ctx, interfaces.VideoGenerationRequest{
+		Prompt:  "A cat surfing",
+		Options: &interfaces.VideoGenerationOptions{AspectRatio: "9:16", DurationSeconds: 4},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.ID != "video_1" || job.Done {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	job, err = client.PollVideoGeneration(ctx, job)
+	if err != nil || job.Done {
+		t.Fatalf("expected an unfinished job, got %+v, %v", job, err)
+	}
+
+	status = "completed"
+	job, err = client.PollVideoGeneration(ctx, job)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !job.Done || len(job.Videos) != 1 || string(job.Videos[0].Data) != "mp4" || job.Videos[0].MimeType != "video/mp4" {
+		t.Errorf("unexpected job %+v", job)
+	}
+}
+
+func TestVideoGenerationFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"video_1","status":"failed","error":{"code":"moderation_blocked","message":"blocked"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	_, err := client.PollVideoGeneration(context.Background(), &interfaces.VideoGenerationJob{ID: "video_1"})
+	if !errors.Is(err, interfaces.ErrContentBlocked) {
+		t.Errorf("expected ErrContentBlocked, got %v", err)
+	}
+}
+
+func TestSoraSize(t *testing.T) {
+	tests := []struct {
+		aspectRatio, resolution, want string
+	}{
+		{"16:9", "720p", "1280x720"},
+		{"9:16", "720p", "720x1280"},
+		{"", "1080p", "1792x1024"},
+		{"9:16", "1080p", "1024x1792"},
+	}
+	for _, tt := range tests {
+		got, err := soraSize(tt.aspectRatio, tt.resolution)
+		if err != nil || got != tt.want {
+			t.Errorf("soraSize(%q, %q) = %q, %v; want %q", tt.aspectRatio, tt.resolution, got, err, tt.want)
+		}
+	}
+	if _, err := soraSize("1:1", "720p"); err == nil {
+		t.Error("expected an error for an unsupported aspect ratio")
+	}
+}
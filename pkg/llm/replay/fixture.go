@@ -0,0 +1,147 @@
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// FixtureVersion is the version of the fixture file format
+const FixtureVersion = 1
+
+// Fixture is the content of a fixture file: the interactions recorded
+// with a provider, in the order they happened
+type Fixture struct {
+	Version      int            `json:"version"`
+	Provider     string         `json:"provider,omitempty"`
+	Model        string         `json:"model,omitempty"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded LLM call
+type Interaction struct {
+	Key       string        `json:"key"`
+	Request   Request       `json:"request"`
+	Response  *Response     `json:"response,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	ToolCalls []ToolCall    `json:"tool_calls,omitempty"`
+	Events    []StreamEvent `json:"events,omitempty"`
+	Recorded  time.Time     `json:"recorded_at"`
+}
+
+// Request identifies an LLM call. Interactions are replayed for requests
+// with the same key.
+type Request struct {
+	Method         string                     `json:"method"`
+	Prompt         string                     `json:"prompt"`
+	SystemMessage  string                     `json:"system_message,omitempty"`
+	Tools          []string                   `json:"tools,omitempty"`
+	ResponseFormat *interfaces.ResponseFormat `json:"response_format,omitempty"`
+	LLMConfig      *interfaces.LLMConfig      `json:"llm_config,omitempty"`
+	MaxIterations  int                        `json:"max_iterations,omitempty"`
+}
+
+// Response is a recorded LLM response
+type Response struct {
+	Content    string                 `json:"content"`
+	Model      string                 `json:"model,omitempty"`
+	StopReason string                 `json:"stop_reason,omitempty"`
+	Usage      *interfaces.TokenUsage `json:"usage,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ToolCall is a tool executed by the provider while handling a request
+type ToolCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	Result    string `json:"result,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// StreamEvent is a recorded stream event
+type StreamEvent struct {
+	Type     interfaces.StreamEventType `json:"type"`
+	Content  string                     `json:"content,omitempty"`
+	ToolCall *interfaces.ToolCall       `json:"tool_call,omitempty"`
+	Error    string                     `json:"error,omitempty"`
+	Metadata map[string]interface{}     `json:"metadata,omitempty"`
+}
+
+// newRequest builds the request of a call from its method, prompt, tools and options
+func newRequest(method, prompt string, tools []interfaces.Tool, options []interfaces.GenerateOption) Request {
+	opts := &interfaces.GenerateOptions{LLMConfig: &interfaces.LLMConfig{}}
+	for _, option := range options {
+		option(opts)
+	}
+
+	request := Request{
+		Method:         method,
+		Prompt:         prompt,
+		SystemMessage:  opts.SystemMessage,
+		ResponseFormat: opts.ResponseFormat,
+		MaxIterations:  opts.MaxIterations,
+	}
+	if opts.LLMConfig != nil && !isZeroConfig(opts.LLMConfig) {
+		request.LLMConfig = opts.LLMConfig
+	}
+	for _, tool := range tools {
+		request.Tools = append(request.Tools, tool.Name())
+	}
+	return request
+}
+
+// key returns a stable hash of the request
+func (r Request) key() string {
+	data, _ := json.Marshal(r)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+func isZeroConfig(config *interfaces.LLMConfig) bool {
+	return config.Temperature == 0 && config.TopP == 0 && config.FrequencyPenalty == 0 &&
+		config.PresencePenalty == 0 && len(config.StopSequences) == 0 && config.Reasoning == "" &&
+		!config.EnableReasoning && config.ReasoningBudget == 0 && config.MaxOutputTokens == 0 &&
+		config.Seed == nil && !config.Deterministic
+}
+
+// loadFixture reads a fixture file. A missing file is an empty fixture.
+func loadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - Path is provided by the test
+	if os.IsNotExist(err) {
+		return &Fixture{Version: FixtureVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if fixture.Version != FixtureVersion {
+		return nil, fmt.Errorf("fixture %s has unsupported version %d", path, fixture.Version)
+	}
+	return &fixture, nil
+}
+
+// save writes the fixture as indented JSON so changes are easy to review
+func (f *Fixture) save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	// #nosec G306 - Fixtures are committed test data
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+// Package replay records the responses of an LLM provider to fixture files
+// and replays them, so agents can be tested deterministically in CI without
+// API keys or network calls.
+//
+// On the first run (or with the record mode) calls go to the real provider
+// and each request and response is saved to the fixture. Later runs replay
+// the fixture: requests are matched by method, prompt, system message, tool
+// names and generation settings, and identical requests are answered in the
+// order they were recorded.
+//
+//	llm := replay.ForTest(t, openai.NewClient(os.Getenv("OPENAI_API_KEY")))
+//	agent, _ := agent.NewAgent(agent.WithLLM(llm), agent.WithTools(calculator))
+//
+// Set LLM_REPLAY_MODE=record to refresh fixtures and LLM_REPLAY_MODE=replay
+// in CI to fail on requests that were not recorded.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// EnvMode is the environment variable that sets the default mode
+const EnvMode = "LLM_REPLAY_MODE"
+
+// Mode decides whether calls are recorded or replayed
+type Mode string
+
+const (
+	// ModeAuto replays recorded interactions and records the others
+	ModeAuto Mode = "auto"
+
+	// ModeRecord sends every call to the provider and rewrites the fixture
+	ModeRecord Mode = "record"
+
+	// ModeReplay only replays recorded interactions; the provider is never called
+	ModeReplay Mode = "replay"
+)
+
+// ErrNotRecorded is returned in replay mode for requests missing from the fixture
+var ErrNotRecorded = errors.New("request was not recorded")
+
+// LLM implements interfaces.StreamingLLM by recording and replaying the
+// calls of an underlying provider
+type LLM struct {
+	llm         interfaces.LLM
+	path        string
+	mode        Mode
+	replayTools bool
+
+	mu      sync.Mutex
+	fixture *Fixture
+	used    []bool
+}
+
+// Option represents an option for configuring the replay LLM
+type Option func(*LLM)
+
+// WithMode sets the mode, overriding LLM_REPLAY_MODE
+func WithMode(mode Mode) Option {
+	return func(l *LLM) {
+		l.mode = mode
+	}
+}
+
+// WithToolReplay controls whether the tool calls a provider made while
+// handling a recorded request are executed again, with the recorded
+// arguments, when it is replayed (default: true). Tools therefore still see
+// the calls an agent test expects, while their results do not change the
+// replayed response.
+func WithToolReplay(enabled bool) Option {
+	return func(l *LLM) {
+		l.replayTools = enabled
+	}
+}
+
+// New creates a replay LLM using the fixture at path. llm may be nil in
+// replay mode, e.g. in CI where no API key is available.
+func New(llm interfaces.LLM, path string, options ...Option) (*LLM, error) {
+	l := &LLM{
+		llm:         llm,
+		path:        path,
+		mode:        modeFromEnv(),
+		replayTools: true,
+	}
+
+	for _, option := range options {
+		option(l)
+	}
+
+	switch l.mode {
+	case ModeAuto, ModeReplay:
+		fixture, err := loadFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		l.fixture = fixture
+	case ModeRecord:
+		if llm == nil {
+			return nil, fmt.Errorf("an LLM is required to record")
+		}
+		l.fixture = &Fixture{Version: FixtureVersion}
+	default:
+		return nil, fmt.Errorf("unknown replay mode %q (use auto, record or replay)", l.mode)
+	}
+	if llm != nil {
+		l.fixture.Provider = llm.Name()
+		if modelProvider, ok := llm.(interface{ GetModel() string }); ok {
+			l.fixture.Model = modelProvider.GetModel()
+		}
+	}
+	l.used = make([]bool, len(l.fixture.Interactions))
+
+	return l, nil
+}
+
+// Generate implements interfaces.LLM.Generate
+func (l *LLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	response, err := l.call(ctx, newRequest("generate", prompt, nil, options), nil, func(ctx context.Context, tools []interfaces.Tool) (*interfaces.LLMResponse, error) {
+		content, err := l.llm.Generate(ctx, prompt, options...)
+		return &interfaces.LLMResponse{Content: content}, err
+	})
+	return content(response), err
+}
+
+// GenerateWithTools implements interfaces.LLM.GenerateWithTools
+func (l *LLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	response, err := l.call(ctx, newRequest("generate_with_tools", prompt, tools, options), tools, func(ctx context.Context, tools []interfaces.Tool) (*interfaces.LLMResponse, error) {
+		content, err := l.llm.GenerateWithTools(ctx, prompt, tools, options...)
+		return &interfaces.LLMResponse{Content: content}, err
+	})
+	return content(response), err
+}
+
+// GenerateDetailed implements interfaces.LLM.GenerateDetailed
+func (l *LLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return l.call(ctx, newRequest("generate_detailed", prompt, nil, options), nil, func(ctx context.Context, tools []interfaces.Tool) (*interfaces.LLMResponse, error) {
+		return l.llm.GenerateDetailed(ctx, prompt, options...)
+	})
+}
+
+// GenerateWithToolsDetailed implements interfaces.LLM.GenerateWithToolsDetailed
+func (l *LLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return l.call(ctx, newRequest("generate_with_tools_detailed", prompt, tools, options), tools, func(ctx context.Context, tools []interfaces.Tool) (*interfaces.LLMResponse, error) {
+		return l.llm.GenerateWithToolsDetailed(ctx, prompt, tools, options...)
+	})
+}
+
+// GenerateStream implements interfaces.StreamingLLM.GenerateStream
+func (l *LLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return l.stream(ctx, newRequest("generate_stream", prompt, nil, options), nil, func(ctx context.Context, streamingLLM interfaces.StreamingLLM, tools []interfaces.Tool) (<-chan interfaces.StreamEvent, error) {
+		return streamingLLM.GenerateStream(ctx, prompt, options...)
+	})
+}
+
+// GenerateWithToolsStream implements interfaces.StreamingLLM.GenerateWithToolsStream
+func (l *LLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return l.stream(ctx, newRequest("generate_with_tools_stream", prompt, tools, options), tools, func(ctx context.Context, streamingLLM interfaces.StreamingLLM, tools []interfaces.Tool) (<-chan interfaces.StreamEvent, error) {
+		return streamingLLM.GenerateWithToolsStream(ctx, prompt, tools, options...)
+	})
+}
+
+// Name implements interfaces.LLM.Name
+func (l *LLM) Name() string {
+	if l.llm != nil {
+		return l.llm.Name()
+	}
+	if l.fixture.Provider != "" {
+		return l.fixture.Provider
+	}
+	return "replay"
+}
+
+// SupportsStreaming implements interfaces.LLM.SupportsStreaming
+func (l *LLM) SupportsStreaming() bool {
+	if l.llm != nil {
+		return l.llm.SupportsStreaming()
+	}
+	return true
+}
+
+// GetModel returns the model of the underlying LLM, or the recorded model
+func (l *LLM) GetModel() string {
+	if modelProvider, ok := l.llm.(interface{ GetModel() string }); ok {
+		return modelProvider.GetModel()
+	}
+	return l.fixture.Model
+}
+
+// Fixture returns the path of the fixture file
+func (l *LLM) Fixture() string {
+	return l.path
+}
+
+// Unused returns the recorded interactions that have not been replayed,
+// which usually means the code under test changed its requests
+func (l *LLM) Unused() []*Interaction {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var unused []*Interaction
+	for i, interaction := range l.fixture.Interactions {
+		if !l.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// call replays the interaction of request or records it by calling generate
+func (l *LLM) call(ctx context.Context, request Request, tools []interfaces.Tool, generate func(context.Context, []interfaces.Tool) (*interfaces.LLMResponse, error)) (*interfaces.LLMResponse, error) {
+	interaction, err := l.lookup(request)
+	if err != nil {
+		return nil, err
+	}
+	if interaction != nil {
+		l.replayToolCalls(ctx, interaction, tools)
+		return interaction.response()
+	}
+
+	interaction = &Interaction{Key: request.key(), Request: request, Recorded: time.Now().UTC()}
+	recorder := &toolRecorder{}
+	response, err := generate(ctx, recorder.wrap(tools))
+	interaction.ToolCalls = recorder.calls()
+	if err != nil {
+		interaction.Error = err.Error()
+	} else if response != nil {
+		interaction.Response = &Response{
+			Content:    response.Content,
+			Model:      response.Model,
+			StopReason: response.StopReason,
+			Usage:      response.Usage,
+			Metadata:   response.Metadata,
+		}
+	}
+
+	if saveErr := l.record(interaction); saveErr != nil {
+		return nil, saveErr
+	}
+	return response, err
+}
+
+// stream replays the events of request or records them by calling generate
+func (l *LLM) stream(ctx context.Context, request Request, tools []interfaces.Tool, generate func(context.Context, interfaces.StreamingLLM, []interfaces.Tool) (<-chan interfaces.StreamEvent, error)) (<-chan interfaces.StreamEvent, error) {
+	interaction, err := l.lookup(request)
+	if err != nil {
+		return nil, err
+	}
+	if interaction != nil {
+		if interaction.Error != "" {
+			return nil, errors.New(interaction.Error)
+		}
+		l.replayToolCalls(ctx, interaction, tools)
+		return interaction.stream(), nil
+	}
+
+	streamingLLM, ok := l.llm.(interfaces.StreamingLLM)
+	if !ok {
+		return nil, fmt.Errorf("underlying LLM does not support streaming")
+	}
+
+	interaction = &Interaction{Key: request.key(), Request: request, Recorded: time.Now().UTC()}
+	recorder := &toolRecorder{}
+	events, err := generate(ctx, streamingLLM, recorder.wrap(tools))
+	if err != nil {
+		interaction.Error = err.Error()
+		if saveErr := l.record(interaction); saveErr != nil {
+			return nil, saveErr
+		}
+		return nil, err
+	}
+
+	out := make(chan interfaces.StreamEvent, cap(events))
+	go func() {
+		defer close(out)
+		for event := range events {
+			recorded := StreamEvent{
+				Type:     event.Type,
+				Content:  event.Content,
+				ToolCall: event.ToolCall,
+				Metadata: event.Metadata,
+			}
+			if event.Error != nil {
+				recorded.Error = event.Error.Error()
+			}
+			interaction.Events = append(interaction.Events, recorded)
+			out <- event
+		}
+
+		interaction.ToolCalls = recorder.calls()
+		if err := l.record(interaction); err != nil {
+			out <- interfaces.StreamEvent{Type: interfaces.StreamEventError, Error: err, Timestamp: time.Now()}
+		}
+	}()
+	return out, nil
+}
+
+// lookup returns the next unused interaction recorded for request. It
+// returns nil when the request must be sent to the provider.
+func (l *LLM) lookup(request Request) (*Interaction, error) {
+	key := request.key()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.mode != ModeRecord {
+		for i, interaction := range l.fixture.Interactions {
+			if !l.used[i] && interaction.Key == key {
+				l.used[i] = true
+				return interaction, nil
+			}
+		}
+	}
+
+	if l.mode == ModeReplay || l.llm == nil {
+		return nil, fmt.Errorf("%w in %s: %s %q", ErrNotRecorded, l.path, request.Method, truncate(request.Prompt, 80))
+	}
+	return nil, nil
+}
+
+// record appends interaction to the fixture and saves it
+func (l *LLM) record(interaction *Interaction) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.fixture.Interactions = append(l.fixture.Interactions, interaction)
+	l.used = append(l.used, true)
+	return l.fixture.save(l.path)
+}
+
+// replayToolCalls executes the recorded tool calls of interaction again
+func (l *LLM) replayToolCalls(ctx context.Context, interaction *Interaction, tools []interfaces.Tool) {
+	if !l.replayTools {
+		return
+	}
+	for _, call := range interaction.ToolCalls {
+		for _, tool := range tools {
+			if tool.Name() == call.Name {
+				_, _ = tool.Execute(ctx, call.Arguments)
+				break
+			}
+		}
+	}
+}
+
+// response returns the recorded response or error
+func (i *Interaction) response() (*interfaces.LLMResponse, error) {
+	if i.Error != "" {
+		return nil, errors.New(i.Error)
+	}
+	if i.Response == nil {
+		return &interfaces.LLMResponse{}, nil
+	}
+	return &interfaces.LLMResponse{
+		Content:    i.Response.Content,
+		Model:      i.Response.Model,
+		StopReason: i.Response.StopReason,
+		Usage:      i.Response.Usage,
+		Metadata:   i.Response.Metadata,
+	}, nil
+}
+
+// stream returns the recorded events on a closed channel
+func (i *Interaction) stream() <-chan interfaces.StreamEvent {
+	events := make(chan interfaces.StreamEvent, len(i.Events))
+	for _, recorded := range i.Events {
+		event := interfaces.StreamEvent{
+			Type:      recorded.Type,
+			Content:   recorded.Content,
+			ToolCall:  recorded.ToolCall,
+			Metadata:  recorded.Metadata,
+			Timestamp: time.Now(),
+		}
+		if recorded.Error != "" {
+			event.Error = errors.New(recorded.Error)
+		}
+		events <- event
+	}
+	close(events)
+	return events
+}
+
+func modeFromEnv() Mode {
+	switch mode := Mode(strings.ToLower(os.Getenv(EnvMode))); mode {
+	case ModeRecord, ModeReplay:
+		return mode
+	default:
+		return ModeAuto
+	}
+}
+
+func content(response *interfaces.LLMResponse) string {
+	if response == nil {
+		return ""
+	}
+	return response.Content
+}
+
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen] + "..."
+}
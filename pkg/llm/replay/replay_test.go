@@ -0,0 +1,213 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+type countingLLM struct {
+	calls int
+	tool  string
+}
+
+func (l *countingLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	l.calls++
+	if prompt == "fail" {
+		return "", errors.New("provider unavailable")
+	}
+	return "answer " + prompt, nil
+}
+
+func (l *countingLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	l.calls++
+	result, err := tools[0].Execute(ctx, `{"city":"Paris"}`)
+	if err != nil {
+		return "", err
+	}
+	return "weather: " + result, nil
+}
+
+func (l *countingLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := l.Generate(ctx, prompt, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &interfaces.LLMResponse{Content: content, Model: "counting-1", Usage: &interfaces.TokenUsage{InputTokens: 3, OutputTokens: 2, TotalTokens: 5}}, nil
+}
+
+func (l *countingLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := l.GenerateWithTools(ctx, prompt, tools, options...)
+	return &interfaces.LLMResponse{Content: content}, err
+}
+
+func (l *countingLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	l.calls++
+	events := make(chan interfaces.StreamEvent, 2)
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "Hel"}
+	events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: "lo"}
+	close(events)
+	return events, nil
+}
+
+func (l *countingLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return l.GenerateStream(ctx, prompt, options...)
+}
+
+func (l *countingLLM) Name() string            { return "counting" }
+func (l *countingLLM) SupportsStreaming() bool { return true }
+
+type weatherTool struct {
+	calls []string
+}
+
+func (t *weatherTool) Name() string        { return "weather" }
+func (t *weatherTool) Description() string { return "Returns the weather" }
+func (t *weatherTool) Parameters() map[string]interfaces.ParameterSpec {
+	return nil
+}
+func (t *weatherTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (t *weatherTool) Execute(ctx context.Context, args string) (string, error) {
+	t.calls = append(t.calls, args)
+	return "sunny", nil
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	provider := &countingLLM{}
+	ctx := context.Background()
+
+	recorder, err := New(provider, path, WithMode(ModeAuto))
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	first, _ := recorder.Generate(ctx, "one", interfaces.WithTemperature(0.2))
+	second, _ := recorder.Generate(ctx, "two")
+	if _, err := recorder.Generate(ctx, "fail"); err == nil {
+		t.Fatal("expected the provider error")
+	}
+	if provider.calls != 3 {
+		t.Fatalf("expected 3 provider calls while recording, got %d", provider.calls)
+	}
+
+	replayer, err := New(nil, path, WithMode(ModeReplay))
+	if err != nil {
+		t.Fatalf("failed to create replayer: %v", err)
+	}
+	if got, _ := replayer.Generate(ctx, "two"); got != second {
+		t.Errorf("expected %q, got %q", second, got)
+	}
+	if got, _ := replayer.Generate(ctx, "one", interfaces.WithTemperature(0.2)); got != first {
+		t.Errorf("expected %q, got %q", first, got)
+	}
+	if _, err := replayer.Generate(ctx, "fail"); err == nil || err.Error() != "provider unavailable" {
+		t.Errorf("expected the recorded error, got %v", err)
+	}
+
+	// Different settings, or a request replayed more often than recorded, miss
+	if _, err := replayer.Generate(ctx, "one", interfaces.WithTemperature(0.9)); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("expected ErrNotRecorded for other settings, got %v", err)
+	}
+	if _, err := replayer.Generate(ctx, "two"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("expected ErrNotRecorded for a repeated request, got %v", err)
+	}
+	if len(replayer.Unused()) != 0 {
+		t.Errorf("expected every interaction to be replayed, got %d unused", len(replayer.Unused()))
+	}
+}
+
+func TestReplayDetailedResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	recorder, _ := New(&countingLLM{}, path, WithMode(ModeRecord))
+	if _, err := recorder.GenerateDetailed(context.Background(), "usage"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replayer, _ := New(nil, path, WithMode(ModeReplay))
+	response, err := replayer.GenerateDetailed(context.Background(), "usage")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Model != "counting-1" || response.Usage == nil || response.Usage.TotalTokens != 5 {
+		t.Errorf("unexpected replayed response %+v", response)
+	}
+	if replayer.Name() != "counting" || replayer.GetModel() != "" {
+		t.Errorf("expected the recorded provider name, got %q", replayer.Name())
+	}
+}
+
+func TestReplayExecutesRecordedToolCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	tool := &weatherTool{}
+	recorder, _ := New(&countingLLM{}, path, WithMode(ModeRecord))
+	if _, err := recorder.GenerateWithTools(context.Background(), "weather?", []interfaces.Tool{tool}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replayedTool := &weatherTool{}
+	replayer, _ := New(nil, path, WithMode(ModeReplay))
+	got, err := replayer.GenerateWithTools(context.Background(), "weather?", []interfaces.Tool{replayedTool})
+	if err != nil || got != "weather: sunny" {
+		t.Fatalf("unexpected replayed response %q, %v", got, err)
+	}
+	if len(replayedTool.calls) != 1 || replayedTool.calls[0] != `{"city":"Paris"}` {
+		t.Errorf("expected the recorded tool call to be executed, got %v", replayedTool.calls)
+	}
+
+	silent := &weatherTool{}
+	replayer, _ = New(nil, path, WithMode(ModeReplay), WithToolReplay(false))
+	_, _ = replayer.GenerateWithTools(context.Background(), "weather?", []interfaces.Tool{silent})
+	if len(silent.calls) != 0 {
+		t.Errorf("expected no tool calls with tool replay disabled, got %v", silent.calls)
+	}
+}
+
+func TestReplayStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	recorder, _ := New(&countingLLM{}, path, WithMode(ModeRecord))
+	events, err := recorder.GenerateStream(context.Background(), "stream")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range events {
+	}
+
+	replayer, _ := New(nil, path, WithMode(ModeReplay))
+	events, err = replayer.GenerateStream(context.Background(), "stream")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content := ""
+	for event := range events {
+		content += event.Content
+	}
+	if content != "Hello" {
+		t.Errorf("expected replayed content %q, got %q", "Hello", content)
+	}
+}
+
+func TestModeFromEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	t.Setenv(EnvMode, "replay")
+
+	l, err := New(nil, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.Generate(context.Background(), "anything"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("expected ErrNotRecorded in replay mode, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected no fixture to be written in replay mode")
+	}
+
+	if _, err := New(nil, path, WithMode(ModeRecord)); err == nil {
+		t.Error("expected record mode without an LLM to fail")
+	}
+}
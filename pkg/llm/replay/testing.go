@@ -0,0 +1,34 @@
+package replay
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// ForTest returns a replay LLM using testdata/replay/<test name>.json as its
+// fixture. The test fails when the fixture cannot be loaded and, in replay
+// mode, when it ends without replaying every recorded interaction.
+func ForTest(t testing.TB, llm interfaces.LLM, options ...Option) *LLM {
+	t.Helper()
+
+	name := unsafeNameChars.ReplaceAllString(t.Name(), "_")
+	l, err := New(llm, filepath.Join("testdata", "replay", name+".json"), options...)
+	if err != nil {
+		t.Fatalf("failed to open replay fixture: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if l.mode != ModeReplay || t.Failed() {
+			return
+		}
+		for _, interaction := range l.Unused() {
+			t.Errorf("recorded %s request was not replayed: %q", interaction.Request.Method, truncate(interaction.Request.Prompt, 80))
+		}
+	})
+	return l
+}
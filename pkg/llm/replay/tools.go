@@ -0,0 +1,93 @@
+package replay
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// toolRecorder collects the tool calls made while a request is recorded
+type toolRecorder struct {
+	mu      sync.Mutex
+	records []ToolCall
+}
+
+func (r *toolRecorder) wrap(tools []interfaces.Tool) []interfaces.Tool {
+	if len(tools) == 0 {
+		return tools
+	}
+	wrapped := make([]interfaces.Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = &recordedTool{tool: tool, recorder: r}
+	}
+	return wrapped
+}
+
+func (r *toolRecorder) add(name, args, result string, err error) {
+	call := ToolCall{Name: name, Arguments: args, Result: result}
+	if err != nil {
+		call.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, call)
+}
+
+func (r *toolRecorder) calls() []ToolCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.records
+}
+
+// recordedTool records the executions of a tool
+type recordedTool struct {
+	tool     interfaces.Tool
+	recorder *toolRecorder
+}
+
+// Name implements interfaces.Tool.Name
+func (t *recordedTool) Name() string {
+	return t.tool.Name()
+}
+
+// Description implements interfaces.Tool.Description
+func (t *recordedTool) Description() string {
+	return t.tool.Description()
+}
+
+// Parameters implements interfaces.Tool.Parameters
+func (t *recordedTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.tool.Parameters()
+}
+
+// Run implements interfaces.Tool.Run
+func (t *recordedTool) Run(ctx context.Context, input string) (string, error) {
+	result, err := t.tool.Run(ctx, input)
+	t.recorder.add(t.tool.Name(), input, result, err)
+	return result, err
+}
+
+// Execute implements interfaces.Tool.Execute
+func (t *recordedTool) Execute(ctx context.Context, args string) (string, error) {
+	result, err := t.tool.Execute(ctx, args)
+	t.recorder.add(t.tool.Name(), args, result, err)
+	return result, err
+}
+
+// DisplayName forwards to the underlying tool when it implements ToolWithDisplayName
+func (t *recordedTool) DisplayName() string {
+	if d, ok := t.tool.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.tool.Name()
+}
+
+// Internal forwards to the underlying tool when it implements InternalTool
+func (t *recordedTool) Internal() bool {
+	if i, ok := t.tool.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
@@ -0,0 +1,441 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tokens"
+)
+
+// Complexity is the complexity of a request, which decides the model it is
+// routed to
+type Complexity string
+
+const (
+	// ComplexitySimple requests are routed to the cheap model
+	ComplexitySimple Complexity = "simple"
+
+	// ComplexityComplex requests are routed to the premium model
+	ComplexityComplex Complexity = "complex"
+)
+
+// Classifier classifies the complexity of requests
+type Classifier interface {
+	Classify(ctx context.Context, prompt string) (Complexity, error)
+}
+
+// ClassifierFunc adapts a function to the Classifier interface
+type ClassifierFunc func(ctx context.Context, prompt string) (Complexity, error)
+
+// Classify implements Classifier
+func (f ClassifierFunc) Classify(ctx context.Context, prompt string) (Complexity, error) {
+	return f(ctx, prompt)
+}
+
+// DefaultComplexKeywords are the words that make HeuristicClassifier treat a
+// request as complex
+var DefaultComplexKeywords = []string{
+	"analyze", "analyse", "architecture", "compare", "debug", "derive",
+	"design", "evaluate", "explain why", "implement", "optimize", "plan",
+	"proof", "prove", "refactor", "step by step", "strategy", "trade-off",
+}
+
+// HeuristicClassifier classifies requests without calling a model. Requests
+// are complex when they are long, contain code, ask several questions or
+// contain one of the keywords.
+type HeuristicClassifier struct {
+	// MaxSimpleTokens is the number of tokens above which requests are complex
+	MaxSimpleTokens int
+
+	// Keywords are matched case-insensitively against the request
+	Keywords []string
+}
+
+// NewHeuristicClassifier creates a heuristic classifier treating requests of
+// more than 200 tokens, or containing DefaultComplexKeywords, as complex
+func NewHeuristicClassifier() *HeuristicClassifier {
+	return &HeuristicClassifier{
+		MaxSimpleTokens: 200,
+		Keywords:        DefaultComplexKeywords,
+	}
+}
+
+// Classify implements Classifier
+func (c *HeuristicClassifier) Classify(ctx context.Context, prompt string) (Complexity, error) {
+	if count, err := tokens.CountText("", prompt); err == nil && c.MaxSimpleTokens > 0 && count > c.MaxSimpleTokens {
+		return ComplexityComplex, nil
+	}
+	if strings.Contains(prompt, "```") || strings.Count(prompt, "?") > 1 {
+		return ComplexityComplex, nil
+	}
+
+	lower := strings.ToLower(prompt)
+	for _, keyword := range c.Keywords {
+		if strings.Contains(lower, strings.ToLower(keyword)) {
+			return ComplexityComplex, nil
+		}
+	}
+	return ComplexitySimple, nil
+}
+
+// classifierPrompt asks a model for the complexity of a request
+const classifierPrompt = `Classify the complexity of the request below.
+Answer SIMPLE if it can be handled by a small, fast model: greetings, short factual questions, lookups, formatting or simple rewrites.
+Answer COMPLEX if it needs careful reasoning, planning, coding, analysis or long-form writing.
+Answer with one word, SIMPLE or COMPLEX.
+
+Request:
+%s`
+
+// LLMClassifier classifies requests with a model, typically a small and
+// cheap one
+type LLMClassifier struct {
+	llm interfaces.LLM
+}
+
+// NewLLMClassifier creates a classifier asking llm for the complexity of
+// requests
+func NewLLMClassifier(llm interfaces.LLM) *LLMClassifier {
+	return &LLMClassifier{llm: llm}
+}
+
+// Classify implements Classifier
+func (c *LLMClassifier) Classify(ctx context.Context, prompt string) (Complexity, error) {
+	answer, err := c.llm.Generate(ctx, fmt.Sprintf(classifierPrompt, prompt), interfaces.WithTemperature(0))
+	if err != nil {
+		return "", fmt.Errorf("failed to classify request: %w", err)
+	}
+
+	answer = strings.ToUpper(answer)
+	switch {
+	case strings.Contains(answer, "COMPLEX"):
+		return ComplexityComplex, nil
+	case strings.Contains(answer, "SIMPLE"):
+		return ComplexitySimple, nil
+	}
+	return "", fmt.Errorf("unexpected classification %q", strings.TrimSpace(answer))
+}
+
+// complexityKey is the context key for the complexity of a request
+type complexityKey struct{}
+
+// ContextWithComplexity returns a context whose requests are routed for the
+// given complexity without being classified, e.g. to route every call of an
+// agent run to the same model
+func ContextWithComplexity(ctx context.Context, complexity Complexity) context.Context {
+	return context.WithValue(ctx, complexityKey{}, complexity)
+}
+
+// ComplexityFromContext returns the complexity set with ContextWithComplexity
+func ComplexityFromContext(ctx context.Context) (Complexity, bool) {
+	complexity, ok := ctx.Value(complexityKey{}).(Complexity)
+	return complexity, ok
+}
+
+// Pricing is the price of a model in dollars per million tokens
+type Pricing struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// Cost returns the cost of usage in dollars
+func (p Pricing) Cost(usage *interfaces.TokenUsage) float64 {
+	if usage == nil {
+		return 0
+	}
+	return (float64(usage.InputTokens)*p.InputPerMillion + float64(usage.OutputTokens)*p.OutputPerMillion) / 1e6
+}
+
+// ModelRouter implements interfaces.StreamingLLM by routing simple requests
+// to a cheap model and complex requests to a premium model
+type ModelRouter struct {
+	cheap          interfaces.LLM
+	premium        interfaces.LLM
+	classifier     Classifier
+	cheapPricing   Pricing
+	premiumPricing Pricing
+	logger         logging.Logger
+
+	mu      sync.Mutex
+	metrics ModelMetrics
+}
+
+// ModelRouterOption represents an option for configuring the model router
+type ModelRouterOption func(*ModelRouter)
+
+// WithClassifier sets the classifier of the model router, a
+// HeuristicClassifier by default
+func WithClassifier(classifier Classifier) ModelRouterOption {
+	return func(r *ModelRouter) {
+		r.classifier = classifier
+	}
+}
+
+// WithPricing sets the prices of the cheap and premium models, which the
+// cost metrics are computed from
+func WithPricing(cheap, premium Pricing) ModelRouterOption {
+	return func(r *ModelRouter) {
+		r.cheapPricing = cheap
+		r.premiumPricing = premium
+	}
+}
+
+// WithModelRouterLogger sets the logger for the model router
+func WithModelRouterLogger(logger logging.Logger) ModelRouterOption {
+	return func(r *ModelRouter) {
+		r.logger = logger
+	}
+}
+
+// NewModelRouter creates a model router sending simple requests to cheap and
+// complex requests to premium. Requests that cannot be classified go to
+// premium.
+func NewModelRouter(cheap, premium interfaces.LLM, options ...ModelRouterOption) *ModelRouter {
+	r := &ModelRouter{
+		cheap:      cheap,
+		premium:    premium,
+		classifier: NewHeuristicClassifier(),
+		logger:     logging.New(),
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// ModelMetrics contains counters describing the routing decisions of a
+// model router and their cost
+type ModelMetrics struct {
+	// Requests is the number of requests handled
+	Requests int64
+
+	// Cheap is the number of requests routed to the cheap model
+	Cheap int64
+
+	// Premium is the number of requests routed to the premium model
+	Premium int64
+
+	// ClassifierErrors is the number of requests that could not be classified
+	// and were routed to the premium model
+	ClassifierErrors int64
+
+	// Cost is the cost of the requests in dollars
+	Cost float64
+
+	// Savings is what the requests routed to the cheap model would have cost
+	// more on the premium model, in dollars
+	Savings float64
+}
+
+// CheapRate returns the fraction of requests routed to the cheap model
+func (m ModelMetrics) CheapRate() float64 {
+	if m.Requests == 0 {
+		return 0
+	}
+	return float64(m.Cheap) / float64(m.Requests)
+}
+
+// Metrics returns a snapshot of the routing metrics
+func (r *ModelRouter) Metrics() ModelMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metrics
+}
+
+// ResetMetrics clears the routing metrics
+func (r *ModelRouter) ResetMetrics() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = ModelMetrics{}
+}
+
+// Classify returns the complexity of a request, the complexity of ctx when
+// set with ContextWithComplexity. Requests that cannot be classified are
+// complex.
+func (r *ModelRouter) Classify(ctx context.Context, prompt string) Complexity {
+	if complexity, ok := ComplexityFromContext(ctx); ok {
+		return complexity
+	}
+
+	complexity, err := r.classifier.Classify(ctx, prompt)
+	if err == nil && (complexity == ComplexitySimple || complexity == ComplexityComplex) {
+		return complexity
+	}
+
+	if err == nil {
+		err = fmt.Errorf("unknown complexity %q", complexity)
+	}
+	r.mu.Lock()
+	r.metrics.ClassifierErrors++
+	r.mu.Unlock()
+	r.logger.Warn(ctx, "Failed to classify request, routing to premium model", map[string]interface{}{
+		"error": err.Error(),
+	})
+	return ComplexityComplex
+}
+
+// selectModel returns the model a request is routed to
+func (r *ModelRouter) selectModel(ctx context.Context, prompt string) (interfaces.LLM, Complexity) {
+	complexity := r.Classify(ctx, prompt)
+
+	r.mu.Lock()
+	r.metrics.Requests++
+	if complexity == ComplexitySimple {
+		r.metrics.Cheap++
+	} else {
+		r.metrics.Premium++
+	}
+	r.mu.Unlock()
+
+	r.logger.Debug(ctx, "Routing request", map[string]interface{}{
+		"complexity": string(complexity),
+	})
+	if complexity == ComplexitySimple {
+		return r.cheap, complexity
+	}
+	return r.premium, complexity
+}
+
+// record adds the cost of a request to the metrics. Usage is estimated from
+// the prompt and the response when the model does not report it.
+func (r *ModelRouter) record(llm interfaces.LLM, complexity Complexity, prompt, response string, usage *interfaces.TokenUsage) {
+	if usage == nil {
+		model := modelName(llm)
+		inputTokens, _ := tokens.CountText(model, prompt)
+		outputTokens, _ := tokens.CountText(model, response)
+		usage = &interfaces.TokenUsage{
+			InputTokens:  inputTokens,
+			OutputTokens: outputTokens,
+			TotalTokens:  inputTokens + outputTokens,
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if complexity == ComplexitySimple {
+		cost := r.cheapPricing.Cost(usage)
+		r.metrics.Cost += cost
+		r.metrics.Savings += r.premiumPricing.Cost(usage) - cost
+	} else {
+		r.metrics.Cost += r.premiumPricing.Cost(usage)
+	}
+}
+
+// modelName returns the model of a provider when it exposes one
+func modelName(llm interfaces.LLM) string {
+	if modelProvider, ok := llm.(interface{ GetModel() string }); ok {
+		return modelProvider.GetModel()
+	}
+	return ""
+}
+
+// Name implements interfaces.LLM.Name and reports the premium provider
+func (r *ModelRouter) Name() string {
+	return r.premium.Name()
+}
+
+// GetModel returns the premium provider's model when it exposes one
+func (r *ModelRouter) GetModel() string {
+	return modelName(r.premium)
+}
+
+// SupportsStreaming implements interfaces.LLM.SupportsStreaming. Both models
+// must support streaming, since either can be selected.
+func (r *ModelRouter) SupportsStreaming() bool {
+	return r.cheap.SupportsStreaming() && r.premium.SupportsStreaming()
+}
+
+// Generate implements interfaces.LLM.Generate
+func (r *ModelRouter) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	llm, complexity := r.selectModel(ctx, prompt)
+	response, err := llm.Generate(ctx, prompt, options...)
+	if err == nil {
+		r.record(llm, complexity, prompt, response, nil)
+	}
+	return response, err
+}
+
+// GenerateDetailed implements interfaces.LLM.GenerateDetailed
+func (r *ModelRouter) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	llm, complexity := r.selectModel(ctx, prompt)
+	response, err := llm.GenerateDetailed(ctx, prompt, options...)
+	if err == nil {
+		r.record(llm, complexity, prompt, response.Content, response.Usage)
+	}
+	return response, err
+}
+
+// GenerateWithTools implements interfaces.LLM.GenerateWithTools
+func (r *ModelRouter) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	llm, complexity := r.selectModel(ctx, prompt)
+	response, err := llm.GenerateWithTools(ctx, prompt, tools, options...)
+	if err == nil {
+		r.record(llm, complexity, prompt, response, nil)
+	}
+	return response, err
+}
+
+// GenerateWithToolsDetailed implements interfaces.LLM.GenerateWithToolsDetailed
+func (r *ModelRouter) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	llm, complexity := r.selectModel(ctx, prompt)
+	response, err := llm.GenerateWithToolsDetailed(ctx, prompt, tools, options...)
+	if err == nil {
+		r.record(llm, complexity, prompt, response.Content, response.Usage)
+	}
+	return response, err
+}
+
+// GenerateStream implements interfaces.StreamingLLM.GenerateStream
+func (r *ModelRouter) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return r.routeStream(ctx, prompt, func(ctx context.Context, llm interfaces.StreamingLLM) (<-chan interfaces.StreamEvent, error) {
+		return llm.GenerateStream(ctx, prompt, options...)
+	})
+}
+
+// GenerateWithToolsStream implements interfaces.StreamingLLM.GenerateWithToolsStream
+func (r *ModelRouter) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return r.routeStream(ctx, prompt, func(ctx context.Context, llm interfaces.StreamingLLM) (<-chan interfaces.StreamEvent, error) {
+		return llm.GenerateWithToolsStream(ctx, prompt, tools, options...)
+	})
+}
+
+// routeStream starts a stream on the selected model and records its cost,
+// estimated from the streamed content, when it ends
+func (r *ModelRouter) routeStream(ctx context.Context, prompt string, call func(context.Context, interfaces.StreamingLLM) (<-chan interfaces.StreamEvent, error)) (<-chan interfaces.StreamEvent, error) {
+	llm, complexity := r.selectModel(ctx, prompt)
+	streamingLLM, ok := llm.(interfaces.StreamingLLM)
+	if !ok || !llm.SupportsStreaming() {
+		return nil, fmt.Errorf("LLM provider %s does not support streaming", llm.Name())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events, err := call(ctx, streamingLLM)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	out := make(chan interfaces.StreamEvent, 100)
+	go func() {
+		defer close(out)
+		var content strings.Builder
+		for event := range events {
+			if event.Type == interfaces.StreamEventContentDelta {
+				content.WriteString(event.Content)
+			}
+			if !send(ctx, out, event) {
+				cancelAndDrain(&stream{events: events, cancel: cancel})
+				return
+			}
+		}
+		cancel()
+		r.record(llm, complexity, prompt, content.String(), nil)
+	}()
+	return out, nil
+}
@@ -0,0 +1,99 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestHeuristicClassifier(t *testing.T) {
+	classifier := NewHeuristicClassifier()
+	tests := []struct {
+		prompt   string
+		expected Complexity
+	}{
+		{"What time is it in Paris?", ComplexitySimple},
+		{"Translate 'hello' to Spanish", ComplexitySimple},
+		{"Analyze the quarterly sales figures", ComplexityComplex},
+		{"Why is the sky blue? And why are sunsets red?", ComplexityComplex},
+		{"Fix this:\n```go\nfunc main() {}\n```", ComplexityComplex},
+	}
+	for _, tt := range tests {
+		complexity, err := classifier.Classify(context.Background(), tt.prompt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if complexity != tt.expected {
+			t.Errorf("expected %q to be %s, got %s", tt.prompt, tt.expected, complexity)
+		}
+	}
+}
+
+func TestModelRouterRoutesByComplexity(t *testing.T) {
+	r := NewModelRouter(&fakeLLM{name: "cheap"}, &fakeLLM{name: "premium"},
+		WithPricing(Pricing{InputPerMillion: 1, OutputPerMillion: 1}, Pricing{InputPerMillion: 10, OutputPerMillion: 10}))
+
+	if out, _ := r.Generate(context.Background(), "hi"); out != "cheap" {
+		t.Errorf("expected simple request to go to the cheap model, got %q", out)
+	}
+	if out, _ := r.Generate(context.Background(), "Design a caching strategy"); out != "premium" {
+		t.Errorf("expected complex request to go to the premium model, got %q", out)
+	}
+	ctx := ContextWithComplexity(context.Background(), ComplexityComplex)
+	if out, _ := r.Generate(ctx, "hi"); out != "premium" {
+		t.Errorf("expected the complexity of the context to be used, got %q", out)
+	}
+
+	m := r.Metrics()
+	if m.Requests != 3 || m.Cheap != 1 || m.Premium != 2 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+	if m.Savings <= 0 || m.Cost <= 0 {
+		t.Errorf("expected cost and savings to be recorded: %+v", m)
+	}
+	if rate := m.CheapRate(); math.Abs(rate-1.0/3) > 1e-9 {
+		t.Errorf("expected cheap rate 1/3, got %v", rate)
+	}
+}
+
+func TestModelRouterClassifierError(t *testing.T) {
+	classifier := ClassifierFunc(func(ctx context.Context, prompt string) (Complexity, error) {
+		return "", errors.New("classifier unavailable")
+	})
+	r := NewModelRouter(&fakeLLM{name: "cheap"}, &fakeLLM{name: "premium"}, WithClassifier(classifier))
+
+	if out, _ := r.Generate(context.Background(), "hi"); out != "premium" {
+		t.Errorf("expected unclassified request to go to the premium model, got %q", out)
+	}
+	if m := r.Metrics(); m.ClassifierErrors != 1 || m.Premium != 1 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestLLMClassifier(t *testing.T) {
+	classifier := NewLLMClassifier(&fakeLLM{name: "COMPLEX"})
+	complexity, err := classifier.Classify(context.Background(), "hi")
+	if err != nil || complexity != ComplexityComplex {
+		t.Errorf("expected complex, got %s (%v)", complexity, err)
+	}
+
+	if _, err := NewLLMClassifier(&fakeLLM{name: "maybe"}).Classify(context.Background(), "hi"); err == nil {
+		t.Error("expected an error for an unexpected answer")
+	}
+}
+
+func TestModelRouterStream(t *testing.T) {
+	r := NewModelRouter(&fakeLLM{name: "cheap"}, &fakeLLM{name: "premium"})
+
+	events, err := r.GenerateStream(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content := collect(t, events); content != "cheap done" {
+		t.Errorf("expected the cheap model to stream, got %q", content)
+	}
+	if m := r.Metrics(); m.Requests != 1 || m.Cheap != 1 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
@@ -0,0 +1,462 @@
+// Package router provides an LLM that routes requests to a primary provider
+// with a secondary provider for failover and, optionally, request hedging.
+//
+// With hedging enabled, if the primary provider has not produced its first
+// streamed event (or, for non-streaming calls, its response) within the hedge
+// threshold, a duplicate request is sent to the secondary provider. Whichever
+// provider answers first wins and the other request is cancelled. This trims
+// tail latency for providers with occasional slow starts at the cost of some
+// duplicate requests, which is reported by Metrics.HedgeRate.
+//
+// ModelRouter routes requests by their complexity instead: simple requests go
+// to a cheap model and complex ones to a premium model, and its metrics report
+// the savings.
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// Router implements interfaces.StreamingLLM on top of a primary and a secondary provider
+type Router struct {
+	primary        interfaces.LLM
+	secondary      interfaces.LLM
+	hedgeThreshold time.Duration
+	logger         logging.Logger
+
+	requests      atomic.Int64
+	hedged        atomic.Int64
+	secondaryWins atomic.Int64
+	failovers     atomic.Int64
+}
+
+// Option represents an option for configuring the router
+type Option func(*Router)
+
+// WithHedging enables request hedging: when the primary provider has not
+// returned its first token within threshold, the request is duplicated to the
+// secondary provider and the first to respond wins.
+func WithHedging(threshold time.Duration) Option {
+	return func(r *Router) {
+		r.hedgeThreshold = threshold
+	}
+}
+
+// WithLogger sets the logger for the router
+func WithLogger(logger logging.Logger) Option {
+	return func(r *Router) {
+		r.logger = logger
+	}
+}
+
+// New creates a new router. Requests go to primary; secondary is used when
+// the primary fails or, with hedging enabled, when it is slow to respond.
+func New(primary, secondary interfaces.LLM, options ...Option) *Router {
+	r := &Router{
+		primary:   primary,
+		secondary: secondary,
+		logger:    logging.New(),
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// Metrics contains counters describing routing decisions
+type Metrics struct {
+	// Requests is the number of requests handled
+	Requests int64
+
+	// Hedged is the number of requests duplicated to the secondary provider
+	// because the primary exceeded the hedge threshold
+	Hedged int64
+
+	// SecondaryWins is the number of hedged requests won by the secondary provider
+	SecondaryWins int64
+
+	// Failovers is the number of requests sent to the secondary provider
+	// because the primary failed
+	Failovers int64
+}
+
+// HedgeRate returns the fraction of requests that were hedged
+func (m Metrics) HedgeRate() float64 {
+	if m.Requests == 0 {
+		return 0
+	}
+	return float64(m.Hedged) / float64(m.Requests)
+}
+
+// Metrics returns a snapshot of the routing metrics
+func (r *Router) Metrics() Metrics {
+	return Metrics{
+		Requests:      r.requests.Load(),
+		Hedged:        r.hedged.Load(),
+		SecondaryWins: r.secondaryWins.Load(),
+		Failovers:     r.failovers.Load(),
+	}
+}
+
+// ResetMetrics clears the routing metrics
+func (r *Router) ResetMetrics() {
+	r.requests.Store(0)
+	r.hedged.Store(0)
+	r.secondaryWins.Store(0)
+	r.failovers.Store(0)
+}
+
+// Name implements interfaces.LLM.Name and reports the primary provider
+func (r *Router) Name() string {
+	return r.primary.Name()
+}
+
+// GetModel returns the primary provider's model when it exposes one
+func (r *Router) GetModel() string {
+	if modelProvider, ok := r.primary.(interface{ GetModel() string }); ok {
+		return modelProvider.GetModel()
+	}
+	return ""
+}
+
+// SupportsStreaming implements interfaces.LLM.SupportsStreaming
+func (r *Router) SupportsStreaming() bool {
+	return r.primary.SupportsStreaming() || (r.secondary != nil && r.secondary.SupportsStreaming())
+}
+
+// Generate implements interfaces.LLM.Generate
+func (r *Router) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return route(ctx, r, true, func(ctx context.Context, llm interfaces.LLM) (string, error) {
+		return llm.Generate(ctx, prompt, options...)
+	})
+}
+
+// GenerateDetailed implements interfaces.LLM.GenerateDetailed
+func (r *Router) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return route(ctx, r, true, func(ctx context.Context, llm interfaces.LLM) (*interfaces.LLMResponse, error) {
+		return llm.GenerateDetailed(ctx, prompt, options...)
+	})
+}
+
+// GenerateWithTools implements interfaces.LLM.GenerateWithTools. Non-streaming
+// tool calls are never hedged since both providers could execute tools;
+// the secondary is only used for failover.
+func (r *Router) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return route(ctx, r, false, func(ctx context.Context, llm interfaces.LLM) (string, error) {
+		return llm.GenerateWithTools(ctx, prompt, tools, options...)
+	})
+}
+
+// GenerateWithToolsDetailed implements interfaces.LLM.GenerateWithToolsDetailed.
+// Like GenerateWithTools it only fails over and never hedges.
+func (r *Router) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return route(ctx, r, false, func(ctx context.Context, llm interfaces.LLM) (*interfaces.LLMResponse, error) {
+		return llm.GenerateWithToolsDetailed(ctx, prompt, tools, options...)
+	})
+}
+
+// GenerateStream implements interfaces.StreamingLLM.GenerateStream
+func (r *Router) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return r.routeStream(ctx, func(ctx context.Context, llm interfaces.StreamingLLM) (<-chan interfaces.StreamEvent, error) {
+		return llm.GenerateStream(ctx, prompt, options...)
+	})
+}
+
+// GenerateWithToolsStream implements interfaces.StreamingLLM.GenerateWithToolsStream.
+// The hedging decision is made on the first event, before any tool call is
+// streamed, and the losing request is cancelled immediately.
+func (r *Router) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return r.routeStream(ctx, func(ctx context.Context, llm interfaces.StreamingLLM) (<-chan interfaces.StreamEvent, error) {
+		return llm.GenerateWithToolsStream(ctx, prompt, tools, options...)
+	})
+}
+
+// result is the outcome of a non-streaming call to one provider
+type result[T any] struct {
+	value     T
+	err       error
+	secondary bool
+}
+
+// route sends a non-streaming call to the primary provider, hedging to the
+// secondary after the threshold when allowed and failing over on error
+func route[T any](ctx context.Context, r *Router, hedgeable bool, call func(context.Context, interfaces.LLM) (T, error)) (T, error) {
+	r.requests.Add(1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan result[T], 2)
+	launch := func(secondary bool) {
+		llm := r.primary
+		if secondary {
+			llm = r.secondary
+		}
+		go func() {
+			value, err := call(ctx, llm)
+			results <- result[T]{value: value, err: err, secondary: secondary}
+		}()
+	}
+
+	launch(false)
+	pending := 1
+	secondaryLaunched := false
+	hedged := false
+
+	var timeout <-chan time.Time
+	if hedgeable && r.secondary != nil && r.hedgeThreshold > 0 {
+		timer := time.NewTimer(r.hedgeThreshold)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var lastErr error
+	for {
+		select {
+		case <-timeout:
+			timeout = nil
+			r.hedged.Add(1)
+			hedged = true
+			secondaryLaunched = true
+			pending++
+			launch(true)
+			r.logger.Debug(ctx, "Primary LLM exceeded hedge threshold, hedging to secondary", map[string]interface{}{
+				"threshold_ms": r.hedgeThreshold.Milliseconds(),
+			})
+
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				if res.secondary && hedged {
+					r.secondaryWins.Add(1)
+				}
+				return res.value, nil
+			}
+			lastErr = res.err
+
+			if !res.secondary && !secondaryLaunched && r.secondary != nil {
+				timeout = nil
+				r.failovers.Add(1)
+				secondaryLaunched = true
+				pending++
+				launch(true)
+				r.logger.Warn(ctx, "Primary LLM failed, failing over to secondary", map[string]interface{}{
+					"error": res.err.Error(),
+				})
+				continue
+			}
+			if pending == 0 {
+				var zero T
+				return zero, lastErr
+			}
+		}
+	}
+}
+
+// stream is an in-flight streaming request to one provider
+type stream struct {
+	events    <-chan interfaces.StreamEvent
+	cancel    context.CancelFunc
+	secondary bool
+}
+
+// start begins a streaming request to a provider
+func (r *Router) start(ctx context.Context, secondary bool, call func(context.Context, interfaces.StreamingLLM) (<-chan interfaces.StreamEvent, error)) (*stream, error) {
+	llm := r.primary
+	if secondary {
+		llm = r.secondary
+	}
+	streamingLLM, ok := llm.(interfaces.StreamingLLM)
+	if !ok || !llm.SupportsStreaming() {
+		return nil, fmt.Errorf("LLM provider %s does not support streaming", llm.Name())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events, err := call(ctx, streamingLLM)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &stream{events: events, cancel: cancel, secondary: secondary}, nil
+}
+
+// routeStream starts a stream on the primary provider, hedging to the
+// secondary when the first event does not arrive within the threshold and
+// failing over when the primary stream errors before producing output
+func (r *Router) routeStream(ctx context.Context, call func(context.Context, interfaces.StreamingLLM) (<-chan interfaces.StreamEvent, error)) (<-chan interfaces.StreamEvent, error) {
+	r.requests.Add(1)
+
+	primary, err := r.start(ctx, false, call)
+	if err != nil {
+		if r.secondary == nil {
+			return nil, err
+		}
+		r.failovers.Add(1)
+		r.logger.Warn(ctx, "Primary LLM stream failed to start, failing over to secondary", map[string]interface{}{
+			"error": err.Error(),
+		})
+		secondary, secondaryErr := r.start(ctx, true, call)
+		if secondaryErr != nil {
+			return nil, fmt.Errorf("primary: %w; secondary: %v", err, secondaryErr)
+		}
+		return secondary.events, nil
+	}
+
+	out := make(chan interfaces.StreamEvent, 100)
+	go r.race(ctx, primary, call, out)
+	return out, nil
+}
+
+// race waits for the first event from the primary (and the hedged secondary,
+// if started), then forwards the winner's events to out and cancels the loser
+func (r *Router) race(ctx context.Context, primary *stream, call func(context.Context, interfaces.StreamingLLM) (<-chan interfaces.StreamEvent, error), out chan<- interfaces.StreamEvent) {
+	defer close(out)
+
+	var secondary *stream
+	hedged := false
+
+	var timeout <-chan time.Time
+	if r.secondary != nil && r.hedgeThreshold > 0 {
+		timer := time.NewTimer(r.hedgeThreshold)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	startSecondary := func() {
+		s, err := r.start(ctx, true, call)
+		if err != nil {
+			r.logger.Warn(ctx, "Failed to start secondary LLM stream", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		secondary = s
+	}
+
+	// A nil channel blocks forever, which disables its select case
+	primaryEvents := primary.events
+	var secondaryEvents <-chan interfaces.StreamEvent
+
+	var winner *stream
+	var first interfaces.StreamEvent
+	var lastErr *interfaces.StreamEvent
+
+	for winner == nil {
+		select {
+		case <-ctx.Done():
+			primary.cancel()
+			if secondary != nil {
+				secondary.cancel()
+			}
+			return
+
+		case <-timeout:
+			timeout = nil
+			r.hedged.Add(1)
+			hedged = true
+			r.logger.Debug(ctx, "Primary LLM exceeded hedge threshold, hedging to secondary", map[string]interface{}{
+				"threshold_ms": r.hedgeThreshold.Milliseconds(),
+			})
+			startSecondary()
+			if secondary != nil {
+				secondaryEvents = secondary.events
+			}
+
+		case event, ok := <-primaryEvents:
+			if ok && event.Type != interfaces.StreamEventError {
+				winner, first = primary, event
+				break
+			}
+			// Primary failed before producing output
+			primaryEvents = nil
+			cancelAndDrain(primary)
+			if ok {
+				lastErr = &event
+			}
+			if secondary == nil && r.secondary != nil {
+				timeout = nil
+				r.failovers.Add(1)
+				r.logger.Warn(ctx, "Primary LLM stream failed, failing over to secondary", nil)
+				startSecondary()
+				if secondary != nil {
+					secondaryEvents = secondary.events
+				}
+			}
+
+		case event, ok := <-secondaryEvents:
+			if ok && event.Type != interfaces.StreamEventError {
+				winner, first = secondary, event
+				break
+			}
+			secondaryEvents = nil
+			cancelAndDrain(secondary)
+			if ok {
+				lastErr = &event
+			}
+		}
+
+		if winner == nil && primaryEvents == nil && secondaryEvents == nil && timeout == nil {
+			// Both providers failed
+			if lastErr != nil {
+				out <- *lastErr
+			} else {
+				out <- interfaces.StreamEvent{
+					Type:      interfaces.StreamEventError,
+					Error:     fmt.Errorf("LLM stream closed without producing output"),
+					Timestamp: time.Now(),
+				}
+			}
+			return
+		}
+	}
+
+	// Cancel the loser and drain it so its producer can exit
+	if winner == primary && secondaryEvents != nil {
+		cancelAndDrain(secondary)
+	}
+	if winner == secondary {
+		if primaryEvents != nil {
+			cancelAndDrain(primary)
+		}
+		if hedged {
+			r.secondaryWins.Add(1)
+		}
+	}
+
+	if !send(ctx, out, first) {
+		winner.cancel()
+		return
+	}
+	for event := range winner.events {
+		if !send(ctx, out, event) {
+			cancelAndDrain(winner)
+			return
+		}
+	}
+	winner.cancel()
+}
+
+func send(ctx context.Context, out chan<- interfaces.StreamEvent, event interfaces.StreamEvent) bool {
+	select {
+	case out <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func cancelAndDrain(s *stream) {
+	s.cancel()
+	go func() {
+		for range s.events {
+		}
+	}()
+}
@@ -0,0 +1,200 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// fakeLLM answers after a delay, or fails when err is set
+type fakeLLM struct {
+	name  string
+	delay time.Duration
+	err   error
+}
+
+func (f *fakeLLM) wait(ctx context.Context) error {
+	select {
+	case <-time.After(f.delay):
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *fakeLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	if err := f.wait(ctx); err != nil {
+		return "", err
+	}
+	return f.name, nil
+}
+
+func (f *fakeLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return f.Generate(ctx, prompt, options...)
+}
+
+func (f *fakeLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := f.Generate(ctx, prompt, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &interfaces.LLMResponse{Content: content}, nil
+}
+
+func (f *fakeLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return f.GenerateDetailed(ctx, prompt, options...)
+}
+
+func (f *fakeLLM) Name() string            { return f.name }
+func (f *fakeLLM) SupportsStreaming() bool { return true }
+
+func (f *fakeLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	events := make(chan interfaces.StreamEvent)
+	go func() {
+		defer close(events)
+		if err := f.wait(ctx); err != nil {
+			if ctx.Err() == nil {
+				events <- interfaces.StreamEvent{Type: interfaces.StreamEventError, Error: err}
+			}
+			return
+		}
+		for _, content := range []string{f.name, " done"} {
+			select {
+			case events <- interfaces.StreamEvent{Type: interfaces.StreamEventContentDelta, Content: content}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (f *fakeLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	return f.GenerateStream(ctx, prompt, options...)
+}
+
+func collect(t *testing.T, events <-chan interfaces.StreamEvent) string {
+	t.Helper()
+	var content string
+	for event := range events {
+		if event.Type == interfaces.StreamEventError {
+			t.Fatalf("unexpected error event: %v", event.Error)
+		}
+		content += event.Content
+	}
+	return content
+}
+
+func TestGenerateWithoutHedging(t *testing.T) {
+	r := New(&fakeLLM{name: "primary", delay: 20 * time.Millisecond}, &fakeLLM{name: "secondary"})
+
+	out, err := r.Generate(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "primary" {
+		t.Errorf("expected primary response, got %q", out)
+	}
+	if m := r.Metrics(); m.Requests != 1 || m.Hedged != 0 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestGenerateHedgesSlowPrimary(t *testing.T) {
+	r := New(&fakeLLM{name: "primary", delay: time.Second}, &fakeLLM{name: "secondary", delay: 10 * time.Millisecond},
+		WithHedging(20*time.Millisecond))
+
+	out, err := r.Generate(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "secondary" {
+		t.Errorf("expected hedged secondary response, got %q", out)
+	}
+	m := r.Metrics()
+	if m.Hedged != 1 || m.SecondaryWins != 1 || m.HedgeRate() != 1 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestGenerateWithToolsIsNotHedged(t *testing.T) {
+	r := New(&fakeLLM{name: "primary", delay: 50 * time.Millisecond}, &fakeLLM{name: "secondary"},
+		WithHedging(time.Millisecond))
+
+	out, err := r.GenerateWithTools(context.Background(), "hi", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "primary" || r.Metrics().Hedged != 0 {
+		t.Errorf("expected primary response without hedging, got %q (%+v)", out, r.Metrics())
+	}
+}
+
+func TestGenerateFailover(t *testing.T) {
+	r := New(&fakeLLM{name: "primary", err: fmt.Errorf("overloaded")}, &fakeLLM{name: "secondary"})
+
+	out, err := r.Generate(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "secondary" || r.Metrics().Failovers != 1 {
+		t.Errorf("expected failover to secondary, got %q (%+v)", out, r.Metrics())
+	}
+
+	r = New(&fakeLLM{name: "primary", err: fmt.Errorf("overloaded")}, &fakeLLM{name: "secondary", err: fmt.Errorf("down")})
+	if _, err := r.Generate(context.Background(), "hi"); err == nil {
+		t.Error("expected error when both providers fail")
+	}
+}
+
+func TestStreamHedgesSlowPrimary(t *testing.T) {
+	r := New(&fakeLLM{name: "primary", delay: time.Second}, &fakeLLM{name: "secondary", delay: 10 * time.Millisecond},
+		WithHedging(20*time.Millisecond))
+
+	events, err := r.GenerateStream(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content := collect(t, events); content != "secondary done" {
+		t.Errorf("expected secondary stream, got %q", content)
+	}
+	m := r.Metrics()
+	if m.Hedged != 1 || m.SecondaryWins != 1 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestStreamPrimaryWinsRace(t *testing.T) {
+	r := New(&fakeLLM{name: "primary", delay: 40 * time.Millisecond}, &fakeLLM{name: "secondary", delay: time.Second},
+		WithHedging(10*time.Millisecond))
+
+	events, err := r.GenerateStream(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content := collect(t, events); content != "primary done" {
+		t.Errorf("expected primary stream, got %q", content)
+	}
+	m := r.Metrics()
+	if m.Hedged != 1 || m.SecondaryWins != 0 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestStreamFailover(t *testing.T) {
+	r := New(&fakeLLM{name: "primary", err: fmt.Errorf("overloaded")}, &fakeLLM{name: "secondary"})
+
+	events, err := r.GenerateStream(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content := collect(t, events); content != "secondary done" {
+		t.Errorf("expected failover stream, got %q", content)
+	}
+	if r.Metrics().Failovers != 1 {
+		t.Errorf("expected a failover, got %+v", r.Metrics())
+	}
+}
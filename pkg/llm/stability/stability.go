@@ -0,0 +1,237 @@
+// Package stability provides an interfaces.ImageGenerator backed by the
+// Stability AI Stable Image API (Stable Image Ultra, Core and Stable Diffusion 3.5).
+package stability
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultModel is the default Stability AI image model
+	DefaultModel = "core"
+
+	// DefaultBaseURL is the Stability AI API base URL
+	DefaultBaseURL = "https://api.stability.ai"
+)
+
+// aspectRatios are the aspect ratios supported by the Stable Image API
+var aspectRatios = map[string]bool{
+	"1:1": true, "16:9": true, "9:16": true, "21:9": true, "9:21": true,
+	"2:3": true, "3:2": true, "4:5": true, "5:4": true,
+}
+
+// Client implements interfaces.ImageGenerator using the Stability AI API.
+// The model is "ultra", "core" or a Stable Diffusion 3.5 model such as
+// "sd3.5-large".
+type Client struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option represents an option for configuring the client
+type Option func(*Client)
+
+// WithModel sets the image model
+func WithModel(model string) Option {
+	return func(c *Client) {
+		c.model = model
+	}
+}
+
+// WithBaseURL overrides the API base URL (useful for proxies and tests)
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for API calls
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// NewClient creates a new Stability AI image generation client
+func NewClient(apiKey string, options ...Option) *Client {
+	c := &Client{
+		apiKey:     apiKey,
+		model:      DefaultModel,
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{Timeout: 2 * time.Minute},
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// SupportsImageGeneration returns true as all Stability AI models generate images
+func (c *Client) SupportsImageGeneration() bool {
+	return true
+}
+
+// SupportedImageFormats returns the supported output formats
+func (c *Client) SupportedImageFormats() []string {
+	return []string{"png", "jpeg", "webp"}
+}
+
+// GenerateImage generates images from a text prompt. The API returns one
+// image per call, so one request is made per image. A reference image is
+// used for image-to-image generation, which Stable Image Core does not support.
+func (c *Client) GenerateImage(ctx context.Context, request interfaces.ImageGenerationRequest) (*interfaces.ImageGenerationResponse, error) {
+	if request.Prompt == "" {
+		return nil, interfaces.ErrInvalidPrompt
+	}
+
+	opts := request.Options
+	if opts == nil {
+		opts = interfaces.DefaultImageGenerationOptions()
+	}
+	if opts.NumberOfImages <= 0 {
+		opts.NumberOfImages = 1
+	}
+	if opts.OutputFormat == "" {
+		opts.OutputFormat = "png"
+	}
+	if opts.AspectRatio != "" && !aspectRatios[opts.AspectRatio] {
+		return nil, fmt.Errorf("unsupported aspect ratio %q", opts.AspectRatio)
+	}
+
+	var reference []byte
+	if request.ReferenceImage != nil {
+		if c.model == "core" {
+			return nil, fmt.Errorf("%w: model core does not support reference images", interfaces.ErrImageGenerationNotSupported)
+		}
+		reference = request.ReferenceImage.Data
+		if reference == nil && request.ReferenceImage.Base64 != "" {
+			decoded, err := base64.StdEncoding.DecodeString(request.ReferenceImage.Base64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode reference image: %w", err)
+			}
+			reference = decoded
+		}
+	}
+
+	response := &interfaces.ImageGenerationResponse{
+		Images:   make([]interfaces.GeneratedImage, 0, opts.NumberOfImages),
+		Metadata: map[string]interface{}{"model": c.model},
+	}
+	for i := 0; i < opts.NumberOfImages; i++ {
+		image, err := c.generate(ctx, request.Prompt, reference, opts)
+		if err != nil {
+			return nil, err
+		}
+		response.Images = append(response.Images, *image)
+	}
+	response.Usage = &interfaces.ImageUsage{ImagesGenerated: len(response.Images)}
+	return response, nil
+}
+
+// generate makes a single Stable Image API request
+func (c *Client) generate(ctx context.Context, prompt string, reference []byte, opts *interfaces.ImageGenerationOptions) (*interfaces.GeneratedImage, error) {
+	endpoint := c.model
+	fields := map[string]string{
+		"prompt":        prompt,
+		"output_format": opts.OutputFormat,
+	}
+	if strings.HasPrefix(c.model, "sd3") {
+		endpoint = "sd3"
+		fields["model"] = c.model
+	}
+	if len(reference) > 0 {
+		// The aspect ratio of image-to-image generation follows the reference image
+		fields["strength"] = "0.7"
+		if endpoint == "sd3" {
+			fields["mode"] = "image-to-image"
+		}
+	} else if opts.AspectRatio != "" {
+		fields["aspect_ratio"] = opts.AspectRatio
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("failed to write form field: %w", err)
+		}
+	}
+	if len(reference) > 0 {
+		part, err := writer.CreateFormFile("image", "image")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create form file: %w", err)
+		}
+		if _, err := part.Write(reference); err != nil {
+			return nil, fmt.Errorf("failed to write reference image: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close form: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v2beta/stable-image/generate/%s", c.baseURL, endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("image generation failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s", interfaces.ErrContentBlocked, string(data))
+	case http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: %s", interfaces.ErrRateLimitExceeded, string(data))
+	default:
+		return nil, fmt.Errorf("stability API returned status %d: %s", resp.StatusCode, string(data))
+	}
+
+	var result struct {
+		Image        string `json:"image"`
+		FinishReason string `json:"finish_reason"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.FinishReason == "CONTENT_FILTERED" {
+		return nil, interfaces.ErrContentBlocked
+	}
+	image, err := base64.StdEncoding.DecodeString(result.Image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	return &interfaces.GeneratedImage{
+		Data:         image,
+		Base64:       result.Image,
+		MimeType:     "image/" + opts.OutputFormat,
+		FinishReason: result.FinishReason,
+	}, nil
+}
+
+var _ interfaces.ImageGenerator = (*Client)(nil)
@@ -0,0 +1,72 @@
+package stability
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestGenerateImage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/v2beta/stable-image/generate/sd3" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" || r.Header.Get("Accept") != "application/json" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if r.FormValue("model") != "sd3.5-large" || r.FormValue("prompt") != "A fox" || r.FormValue("aspect_ratio") != "16:9" || r.FormValue("output_format") != "webp" {
+			t.Errorf("unexpected form %v", r.MultipartForm.Value)
+		}
+		_, _ = w.Write([]byte(`{"image":"aW1hZ2U=","finish_reason":"SUCCESS","seed":1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithModel("sd3.5-large"))
+	response, err := client.GenerateImage(context.Background(), interfaces.ImageGenerationRequest{
+		Prompt:  "A fox",
+		Options: &interfaces.ImageGenerationOptions{NumberOfImages: 2, AspectRatio: "16:9", OutputFormat: "webp"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 || len(response.Images) != 2 {
+		t.Fatalf("expected one request per image, got %d requests and %d images", requests, len(response.Images))
+	}
+	if string(response.Images[0].Data) != "image" || response.Images[0].MimeType != "image/webp" {
+		t.Errorf("unexpected image %+v", response.Images[0])
+	}
+}
+
+func TestGenerateImageErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"image":"","finish_reason":"CONTENT_FILTERED"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	if _, err := client.GenerateImage(ctx, interfaces.ImageGenerationRequest{Prompt: "x"}); !errors.Is(err, interfaces.ErrContentBlocked) {
+		t.Errorf("expected ErrContentBlocked, got %v", err)
+	}
+	if _, err := client.GenerateImage(ctx, interfaces.ImageGenerationRequest{}); !errors.Is(err, interfaces.ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt, got %v", err)
+	}
+	request := interfaces.ImageGenerationRequest{Prompt: "x", ReferenceImage: &interfaces.ImageData{Data: []byte("png")}}
+	if _, err := client.GenerateImage(ctx, request); !errors.Is(err, interfaces.ErrImageGenerationNotSupported) {
+		t.Errorf("expected ErrImageGenerationNotSupported for core, got %v", err)
+	}
+	request.Options = &interfaces.ImageGenerationOptions{AspectRatio: "4:3"}
+	if _, err := NewClient("test-key", WithModel("ultra")).GenerateImage(ctx, request); err == nil {
+		t.Error("expected an error for an unsupported aspect ratio")
+	}
+}
@@ -0,0 +1,232 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
+)
+
+// ToolResult is the result of a tool call requested by a model
+type ToolResult struct {
+	// Call is the tool call
+	Call interfaces.ToolCall
+
+	// Content is the result to send back to the model: the output of the
+	// tool, or "Error: ..." when it failed
+	Content string
+
+	// Err is a *interfaces.ToolExecutionError when the tool failed or was
+	// not found
+	Err error
+
+	// Duration is how long the tool ran
+	Duration time.Duration
+
+	start time.Time
+}
+
+// ToolExecutor runs the tool-calling loop of the LLM clients. Providers build
+// the requests and decode the tool calls of each model response for Run, so
+// that every provider runs tools the same way: in order or in parallel, with
+// an optional timeout, warning the model about repeated calls, and recording
+// them in tracing and memory.
+type ToolExecutor struct {
+	logger    logging.Logger
+	memory    interfaces.Memory
+	timeout   time.Duration
+	parallel  bool
+	toolsFunc func() []interfaces.Tool
+
+	mu      sync.Mutex
+	history map[string]int
+}
+
+// ToolExecutorOption represents an option for configuring a ToolExecutor
+type ToolExecutorOption func(*ToolExecutor)
+
+// WithToolMemory stores the tool calls and their results in memory
+func WithToolMemory(memory interfaces.Memory) ToolExecutorOption {
+	return func(e *ToolExecutor) {
+		e.memory = memory
+	}
+}
+
+// WithToolTimeout limits how long each tool call may run (0 = no limit)
+func WithToolTimeout(timeout time.Duration) ToolExecutorOption {
+	return func(e *ToolExecutor) {
+		e.timeout = timeout
+	}
+}
+
+// WithParallelToolCalls runs the tool calls of a model response concurrently.
+// By default they run one after the other, in the order of the calls.
+func WithParallelToolCalls(parallel bool) ToolExecutorOption {
+	return func(e *ToolExecutor) {
+		e.parallel = parallel
+	}
+}
+
+// WithToolsFunc reads the current tool set before each iteration of the
+// loop after the first, see interfaces.WithToolsFunc
+func WithToolsFunc(toolsFunc func() []interfaces.Tool) ToolExecutorOption {
+	return func(e *ToolExecutor) {
+		e.toolsFunc = toolsFunc
+	}
+}
+
+// NewToolExecutor creates a ToolExecutor for one tool-calling loop
+func NewToolExecutor(logger logging.Logger, options ...ToolExecutorOption) *ToolExecutor {
+	e := &ToolExecutor{
+		logger:  logger,
+		history: make(map[string]int),
+	}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// CurrentTools returns the current tool set before an iteration of the loop.
+// It returns false for the first iteration, which uses the tools of the
+// request, and when the loop has no WithToolsFunc.
+func (e *ToolExecutor) CurrentTools(iteration int) ([]interfaces.Tool, bool) {
+	if e.toolsFunc == nil || iteration == 0 {
+		return nil, false
+	}
+	return e.toolsFunc(), true
+}
+
+// Execute runs the tool calls of a model response, one after the other or in
+// parallel with WithParallelToolCalls, and returns their results in the order
+// of the calls. Failed calls and calls to unknown tools are returned as
+// errors for the model rather than ending the loop.
+func (e *ToolExecutor) Execute(ctx context.Context, tools []interfaces.Tool, calls []interfaces.ToolCall) []ToolResult {
+	results := make([]ToolResult, len(calls))
+	if e.parallel {
+		var wg sync.WaitGroup
+		for i, call := range calls {
+			wg.Add(1)
+			go func(i int, call interfaces.ToolCall) {
+				defer wg.Done()
+				results[i] = e.execute(ctx, tools, call)
+			}(i, call)
+		}
+		wg.Wait()
+	} else {
+		for i, call := range calls {
+			results[i] = e.execute(ctx, tools, call)
+		}
+	}
+
+	// Record the calls in order once they all ran, as neither tracing nor
+	// memory expect concurrent writes
+	for _, result := range results {
+		e.record(ctx, result)
+	}
+	return results
+}
+
+// execute runs one tool call
+func (e *ToolExecutor) execute(ctx context.Context, tools []interfaces.Tool, call interfaces.ToolCall) ToolResult {
+	result := ToolResult{Call: call, start: time.Now()}
+
+	var tool interfaces.Tool
+	for _, t := range tools {
+		if t.Name() == call.Name {
+			tool = t
+			break
+		}
+	}
+	if tool == nil {
+		e.logger.Error(ctx, "Tool not found", map[string]interface{}{"toolName": call.Name})
+		notFound := fmt.Errorf("tool not found: %s", call.Name)
+		result.Err = &interfaces.ToolExecutionError{Tool: call.Name, Err: notFound}
+		result.Content = fmt.Sprintf("Error: %v", notFound)
+		return result
+	}
+
+	callCtx := ctx
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	e.logger.Info(ctx, "Executing tool", map[string]interface{}{"toolName": call.Name})
+	result.start = time.Now()
+	output, err := tool.Execute(callCtx, call.Arguments)
+	result.Duration = time.Since(result.start)
+	count := e.count(call)
+
+	if err != nil {
+		e.logger.Error(ctx, "Error executing tool", map[string]interface{}{
+			"toolName": call.Name,
+			"error":    err.Error(),
+		})
+		result.Err = &interfaces.ToolExecutionError{Tool: call.Name, Err: err}
+		result.Content = fmt.Sprintf("Error: %v", err)
+		return result
+	}
+
+	// Warn the model about repeated calls, without blocking them
+	result.Content = output
+	if count > 1 {
+		result.Content += fmt.Sprintf("\n\n[WARNING: This is call #%d to %s with identical parameters. You may be in a loop. Consider using the available information to provide a final answer.]",
+			count, call.Name)
+		e.logger.Warn(ctx, "Repetitive tool call detected", map[string]interface{}{
+			"toolName":  call.Name,
+			"callCount": count,
+		})
+	}
+	return result
+}
+
+// count counts a call with its arguments, and returns how many times it was
+// made during the loop
+func (e *ToolExecutor) count(call interfaces.ToolCall) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := call.Name + ":" + call.Arguments
+	e.history[key]++
+	return e.history[key]
+}
+
+// record adds a tool call to the tracing context, and to memory when set
+func (e *ToolExecutor) record(ctx context.Context, result ToolResult) {
+	trace := tracing.ToolCall{
+		Name:       result.Call.Name,
+		Arguments:  result.Call.Arguments,
+		ID:         result.Call.ID,
+		Timestamp:  result.start.Format(time.RFC3339),
+		StartTime:  result.start,
+		Duration:   result.Duration,
+		DurationMs: result.Duration.Milliseconds(),
+		Result:     result.Content,
+	}
+	if result.Err != nil {
+		trace.Error = result.Err.Error()
+	}
+	tracing.AddToolCallToContext(ctx, trace)
+
+	if e.memory == nil {
+		return
+	}
+	_ = e.memory.AddMessage(ctx, interfaces.Message{
+		Role:      "assistant",
+		Content:   "",
+		ToolCalls: []interfaces.ToolCall{result.Call},
+	})
+	_ = e.memory.AddMessage(ctx, interfaces.Message{
+		Role:       "tool",
+		Content:    result.Content,
+		ToolCallID: result.Call.ID,
+		Metadata: map[string]interface{}{
+			"tool_name": result.Call.Name,
+		},
+	})
+}
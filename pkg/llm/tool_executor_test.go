@@ -0,0 +1,184 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// executorTestTool runs a function as a tool
+type executorTestTool struct {
+	name string
+	run  func(ctx context.Context, args string) (string, error)
+}
+
+func (t *executorTestTool) Name() string        { return t.name }
+func (t *executorTestTool) Description() string { return t.name }
+func (t *executorTestTool) Parameters() map[string]interfaces.ParameterSpec {
+	return nil
+}
+func (t *executorTestTool) Run(ctx context.Context, input string) (string, error) {
+	return t.run(ctx, input)
+}
+func (t *executorTestTool) Execute(ctx context.Context, args string) (string, error) {
+	return t.run(ctx, args)
+}
+
+// recordingMemory records the messages added to it
+type recordingMemory struct {
+	messages []interfaces.Message
+}
+
+func (m *recordingMemory) AddMessage(ctx context.Context, message interfaces.Message) error {
+	m.messages = append(m.messages, message)
+	return nil
+}
+func (m *recordingMemory) GetMessages(ctx context.Context, options ...interfaces.GetMessagesOption) ([]interfaces.Message, error) {
+	return m.messages, nil
+}
+func (m *recordingMemory) Clear(ctx context.Context) error {
+	m.messages = nil
+	return nil
+}
+
+func TestToolExecutor(t *testing.T) {
+	tools := []interfaces.Tool{
+		&executorTestTool{name: "slow", run: func(ctx context.Context, args string) (string, error) {
+			time.Sleep(20 * time.Millisecond)
+			return "slow " + args, nil
+		}},
+		&executorTestTool{name: "fast", run: func(ctx context.Context, args string) (string, error) {
+			return "fast " + args, nil
+		}},
+		&executorTestTool{name: "failing", run: func(ctx context.Context, args string) (string, error) {
+			return "", errors.New("boom")
+		}},
+		&executorTestTool{name: "hanging", run: func(ctx context.Context, args string) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}},
+	}
+
+	memory := &recordingMemory{}
+	executor := NewToolExecutor(logging.New(), WithToolMemory(memory), WithToolTimeout(50*time.Millisecond))
+	results := executor.Execute(context.Background(), tools, []interfaces.ToolCall{
+		{ID: "1", Name: "slow", Arguments: "a"},
+		{ID: "2", Name: "fast", Arguments: "b"},
+		{ID: "3", Name: "failing", Arguments: "c"},
+		{ID: "4", Name: "missing", Arguments: "d"},
+		{ID: "5", Name: "hanging", Arguments: "e"},
+	})
+
+	expected := []string{"slow a", "fast b", "Error: boom", "Error: tool not found: missing", "Error: context deadline exceeded"}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+	for i, result := range results {
+		if result.Content != expected[i] {
+			t.Errorf("Result %d: expected %q, got %q", i, expected[i], result.Content)
+		}
+		if (result.Err != nil) != (i >= 2) {
+			t.Errorf("Result %d: unexpected error %v", i, result.Err)
+		}
+		var toolErr *interfaces.ToolExecutionError
+		if result.Err != nil && (!errors.As(result.Err, &toolErr) || toolErr.Tool != result.Call.Name) {
+			t.Errorf("Result %d: expected a ToolExecutionError of %s, got %v", i, result.Call.Name, result.Err)
+		}
+	}
+
+	// Each call is stored in order, as an assistant tool call and its result
+	if len(memory.messages) != 10 {
+		t.Fatalf("Expected 10 messages in memory, got %d", len(memory.messages))
+	}
+	if memory.messages[1].ToolCallID != "1" || memory.messages[1].Content != "slow a" {
+		t.Errorf("Unexpected first tool message %+v", memory.messages[1])
+	}
+
+	// Repeated calls are flagged to the model
+	results = executor.Execute(context.Background(), tools, []interfaces.ToolCall{{ID: "6", Name: "fast", Arguments: "b"}})
+	if !strings.Contains(results[0].Content, "[WARNING: This is call #2 to fast") {
+		t.Errorf("Expected a loop warning, got %q", results[0].Content)
+	}
+}
+
+func TestToolExecutor_Sequential(t *testing.T) {
+	var order []string
+	running := 0
+	record := func(ctx context.Context, args string) (string, error) {
+		running++
+		defer func() { running-- }()
+		if running > 1 {
+			t.Errorf("Expected one call at a time, got %d", running)
+		}
+		time.Sleep(5 * time.Millisecond)
+		order = append(order, args)
+		return args, nil
+	}
+	tools := []interfaces.Tool{&executorTestTool{name: "record", run: record}}
+
+	executor := NewToolExecutor(logging.New())
+	executor.Execute(context.Background(), tools, []interfaces.ToolCall{
+		{ID: "1", Name: "record", Arguments: "a"},
+		{ID: "2", Name: "record", Arguments: "b"},
+		{ID: "3", Name: "record", Arguments: "c"},
+	})
+	if strings.Join(order, ",") != "a,b,c" {
+		t.Errorf("Expected the calls to run in order, got %v", order)
+	}
+}
+
+func TestToolExecutor_Parallel(t *testing.T) {
+	// Each call waits for the other, which only completes when they run concurrently
+	first, second := make(chan struct{}), make(chan struct{})
+	tools := []interfaces.Tool{
+		&executorTestTool{name: "first", run: func(ctx context.Context, args string) (string, error) {
+			close(first)
+			select {
+			case <-second:
+				return "first", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}},
+		&executorTestTool{name: "second", run: func(ctx context.Context, args string) (string, error) {
+			close(second)
+			select {
+			case <-first:
+				return "second", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}},
+	}
+
+	executor := NewToolExecutor(logging.New(), WithParallelToolCalls(true), WithToolTimeout(5*time.Second))
+	results := executor.Execute(context.Background(), tools, []interfaces.ToolCall{
+		{ID: "1", Name: "first"},
+		{ID: "2", Name: "second"},
+	})
+	if results[0].Content != "first" || results[1].Content != "second" {
+		t.Errorf("Expected both calls to complete concurrently, got %+v", results)
+	}
+}
+
+func TestToolExecutor_CurrentTools(t *testing.T) {
+	if _, ok := NewToolExecutor(logging.New()).CurrentTools(1); ok {
+		t.Error("Expected no current tools without WithToolsFunc")
+	}
+
+	added := &executorTestTool{name: "added"}
+	executor := NewToolExecutor(logging.New(), WithToolsFunc(func() []interfaces.Tool {
+		return []interfaces.Tool{added}
+	}))
+	if _, ok := executor.CurrentTools(0); ok {
+		t.Error("Expected the first iteration to keep the tools of the request")
+	}
+	if tools, ok := executor.CurrentTools(1); !ok || len(tools) != 1 || tools[0] != added {
+		t.Errorf("Expected the current tools from the second iteration, got %v", tools)
+	}
+}
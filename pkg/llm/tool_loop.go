@@ -0,0 +1,127 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ToolLoopTurn is a model response decoded by a ToolLoopProvider
+type ToolLoopTurn struct {
+	// Content is the text of the response
+	Content string
+
+	// Calls are the tool calls of the response
+	Calls []interfaces.ToolCall
+}
+
+// ToolLoopProvider builds the requests of a tool-calling loop for a model and
+// decodes its responses. The provider keeps the conversation, while
+// ToolExecutor.Run runs the loop itself, so that every provider follows the
+// same loop policy.
+type ToolLoopProvider interface {
+	// Send sends the conversation with the tools to the model, and decodes
+	// its response
+	Send(ctx context.Context, iteration int, tools []interfaces.Tool) (ToolLoopTurn, error)
+
+	// AddResults adds the last response and the results of its tool calls
+	// to the conversation
+	AddResults(ctx context.Context, iteration int, results []ToolResult) error
+
+	// Conclude sends the conversation without tools, asking for the final
+	// response with finalPrompt
+	Conclude(ctx context.Context, finalPrompt string) (string, error)
+}
+
+// ToolLoopResult is the outcome of a tool-calling loop
+type ToolLoopResult struct {
+	// Content is the final response
+	Content string
+
+	// Stopped is true when a stop tool ended the loop, Content being its
+	// result
+	Stopped bool
+}
+
+// Run runs a tool-calling loop with a provider: it sends the conversation
+// until the model answers without calling tools, executing the tool calls of
+// each response. It refreshes the tools before each iteration with
+// WithToolsFunc, ends the loop when a stop tool of the policy ran, and applies
+// the policy's OnExhausted when the model still calls tools after the last
+// iteration.
+func (e *ToolExecutor) Run(ctx context.Context, provider ToolLoopProvider, tools []interfaces.Tool, loop interfaces.ToolLoopPolicy) (ToolLoopResult, error) {
+	stop := interfaces.NewToolLoopStop(loop)
+	tools = stop.Wrap(tools)
+
+	// Track the last response content, and whether the model ended its turn
+	// without an answer after tools ran. Gemini does so on large tool
+	// outputs, emitting a function call and then an empty response: the final
+	// call without tools makes it answer from the tool results.
+	var lastContent string
+	var ranTools, endedEmpty bool
+
+	for iteration := 0; iteration < loop.MaxIterations; iteration++ {
+		// Pick up tools registered or removed since the last iteration
+		if current, ok := e.CurrentTools(iteration); ok {
+			tools = stop.Wrap(current)
+		}
+
+		turn, err := provider.Send(ctx, iteration, tools)
+		if err != nil {
+			return ToolLoopResult{}, err
+		}
+		if turn.Content != "" {
+			lastContent = turn.Content
+		}
+
+		// No tool calls, the model answered
+		if len(turn.Calls) == 0 {
+			if turn.Content == "" && ranTools {
+				endedEmpty = true
+				break
+			}
+			return ToolLoopResult{Content: turn.Content}, nil
+		}
+
+		e.logger.Info(ctx, "Processing tool calls", map[string]interface{}{
+			"count":     len(turn.Calls),
+			"iteration": iteration + 1,
+		})
+		ranTools = true
+		if err := provider.AddResults(ctx, iteration, e.Execute(ctx, tools, turn.Calls)); err != nil {
+			return ToolLoopResult{}, err
+		}
+
+		// End the loop with the result of a stop tool
+		if result, stopped := stop.Result(); stopped {
+			e.logger.Info(ctx, "Stop tool called, ending tool-calling loop", map[string]interface{}{
+				"iteration": iteration + 1,
+			})
+			return ToolLoopResult{Content: result, Stopped: true}, nil
+		}
+	}
+
+	// The model still calls tools after the last iteration: make one final
+	// call without tools to get a conclusion, unless the policy says otherwise
+	switch loop.OnExhausted {
+	case interfaces.ToolLoopLastResponse:
+		e.logger.Info(ctx, "Maximum iterations reached, returning the last response", map[string]interface{}{
+			"maxIterations": loop.MaxIterations,
+		})
+		return ToolLoopResult{Content: lastContent}, nil
+	case interfaces.ToolLoopFail:
+		if !endedEmpty {
+			return ToolLoopResult{}, loop.Exhausted()
+		}
+	}
+
+	e.logger.Info(ctx, "Making final call without tools", map[string]interface{}{
+		"maxIterations": loop.MaxIterations,
+		"endedEmpty":    endedEmpty,
+	})
+	content, err := provider.Conclude(ctx, loop.FinalPrompt)
+	if err != nil {
+		return ToolLoopResult{}, err
+	}
+	return ToolLoopResult{Content: content}, nil
+}
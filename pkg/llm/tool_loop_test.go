@@ -0,0 +1,149 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// scriptedProvider answers the requests of a tool-calling loop with a list
+// of responses, calling tools once they run out
+type scriptedProvider struct {
+	turns []ToolLoopTurn
+
+	sent      [][]string
+	results   []string
+	concluded string
+}
+
+func (p *scriptedProvider) Send(ctx context.Context, iteration int, tools []interfaces.Tool) (ToolLoopTurn, error) {
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name())
+	}
+	p.sent = append(p.sent, names)
+
+	if iteration < len(p.turns) {
+		return p.turns[iteration], nil
+	}
+	return ToolLoopTurn{Content: "calling", Calls: []interfaces.ToolCall{{ID: fmt.Sprint(iteration), Name: "lookup"}}}, nil
+}
+
+func (p *scriptedProvider) AddResults(ctx context.Context, iteration int, results []ToolResult) error {
+	for _, result := range results {
+		p.results = append(p.results, result.Content)
+	}
+	return nil
+}
+
+func (p *scriptedProvider) Conclude(ctx context.Context, finalPrompt string) (string, error) {
+	p.concluded = finalPrompt
+	return "concluded", nil
+}
+
+func TestToolExecutor_Run(t *testing.T) {
+	lookup := &executorTestTool{name: "lookup", run: func(ctx context.Context, args string) (string, error) {
+		return "found", nil
+	}}
+	policy := func(policy interfaces.ToolLoopPolicy) interfaces.ToolLoopPolicy {
+		return (&interfaces.GenerateOptions{ToolLoopPolicy: &policy}).ToolLoop()
+	}
+
+	t.Run("answer", func(t *testing.T) {
+		provider := &scriptedProvider{turns: []ToolLoopTurn{
+			{Calls: []interfaces.ToolCall{{ID: "1", Name: "lookup"}}},
+			{Content: "answer"},
+		}}
+		result, err := NewToolExecutor(logging.New()).Run(context.Background(), provider, []interfaces.Tool{lookup}, policy(interfaces.ToolLoopPolicy{}))
+		if err != nil {
+			t.Fatalf("Failed to run loop: %v", err)
+		}
+		if result.Content != "answer" || result.Stopped {
+			t.Errorf("Expected the answer of the model, got %+v", result)
+		}
+		if fmt.Sprint(provider.results) != "[found]" {
+			t.Errorf("Expected the tool result to be added, got %v", provider.results)
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		provider := &scriptedProvider{}
+		result, err := NewToolExecutor(logging.New()).Run(context.Background(), provider, []interfaces.Tool{lookup}, policy(interfaces.ToolLoopPolicy{MaxIterations: 2, FinalPrompt: "Wrap up."}))
+		if err != nil {
+			t.Fatalf("Failed to run loop: %v", err)
+		}
+		if result.Content != "concluded" || provider.concluded != "Wrap up." || len(provider.sent) != 2 {
+			t.Errorf("Expected a final call after 2 iterations, got %+v after %d", result, len(provider.sent))
+		}
+
+		provider = &scriptedProvider{}
+		result, err = NewToolExecutor(logging.New()).Run(context.Background(), provider, []interfaces.Tool{lookup}, policy(interfaces.ToolLoopPolicy{MaxIterations: 1, OnExhausted: interfaces.ToolLoopLastResponse}))
+		if err != nil || result.Content != "calling" || provider.concluded != "" {
+			t.Errorf("Expected the last response, got %+v, %v", result, err)
+		}
+
+		_, err = NewToolExecutor(logging.New()).Run(context.Background(), &scriptedProvider{}, []interfaces.Tool{lookup}, policy(interfaces.ToolLoopPolicy{MaxIterations: 1, OnExhausted: interfaces.ToolLoopFail}))
+		if !errors.Is(err, interfaces.ErrToolLoopExhausted) {
+			t.Errorf("Expected ErrToolLoopExhausted, got %v", err)
+		}
+	})
+
+	t.Run("empty response after tools", func(t *testing.T) {
+		provider := &scriptedProvider{turns: []ToolLoopTurn{
+			{Calls: []interfaces.ToolCall{{ID: "1", Name: "lookup"}}},
+			{},
+		}}
+		result, err := NewToolExecutor(logging.New()).Run(context.Background(), provider, []interfaces.Tool{lookup}, policy(interfaces.ToolLoopPolicy{MaxIterations: 5, OnExhausted: interfaces.ToolLoopFail}))
+		if err != nil || result.Content != "concluded" {
+			t.Errorf("Expected a final call after an empty response, got %+v, %v", result, err)
+		}
+	})
+
+	t.Run("stop tool", func(t *testing.T) {
+		provider := &scriptedProvider{}
+		result, err := NewToolExecutor(logging.New()).Run(context.Background(), provider, []interfaces.Tool{lookup}, policy(interfaces.ToolLoopPolicy{MaxIterations: 3, StopTools: []string{"lookup"}}))
+		if err != nil {
+			t.Fatalf("Failed to run loop: %v", err)
+		}
+		if !result.Stopped || result.Content != "found" || len(provider.sent) != 1 {
+			t.Errorf("Expected the stop tool to end the loop, got %+v after %d", result, len(provider.sent))
+		}
+	})
+
+	t.Run("current tools", func(t *testing.T) {
+		added := &executorTestTool{name: "added"}
+		executor := NewToolExecutor(logging.New(), WithToolsFunc(func() []interfaces.Tool {
+			return []interfaces.Tool{lookup, added}
+		}))
+		provider := &scriptedProvider{}
+		if _, err := executor.Run(context.Background(), provider, []interfaces.Tool{lookup}, policy(interfaces.ToolLoopPolicy{MaxIterations: 2})); err != nil {
+			t.Fatalf("Failed to run loop: %v", err)
+		}
+		if got := fmt.Sprint(provider.sent); got != "[[lookup] [lookup added]]" {
+			t.Errorf("Expected the tools to be refreshed before the second iteration, got %s", got)
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		provider := &failingProvider{err: errors.New("unavailable")}
+		_, err := NewToolExecutor(logging.New()).Run(context.Background(), provider, nil, policy(interfaces.ToolLoopPolicy{}))
+		if err == nil || !strings.Contains(err.Error(), "unavailable") {
+			t.Errorf("Expected the error of the provider, got %v", err)
+		}
+	})
+}
+
+// failingProvider fails every request
+type failingProvider struct {
+	scriptedProvider
+	err error
+}
+
+func (p *failingProvider) Send(ctx context.Context, iteration int, tools []interfaces.Tool) (ToolLoopTurn, error) {
+	return ToolLoopTurn{}, p.err
+}
@@ -95,6 +95,7 @@ type GenerateRequest struct {
 	UseBeamSearch bool     `json:"use_beam_search,omitempty"`
 	BestOf        int      `json:"best_of,omitempty"`
 	N             int      `json:"n,omitempty"`
+	Seed          *int     `json:"seed,omitempty"`
 }
 
 type GenerateResponse struct {
@@ -127,6 +128,7 @@ type ChatRequest struct {
 	UseBeamSearch bool          `json:"use_beam_search,omitempty"`
 	BestOf        int           `json:"best_of,omitempty"`
 	N             int           `json:"n,omitempty"`
+	Seed          *int          `json:"seed,omitempty"`
 }
 
 type ChatMessage struct {
@@ -187,6 +189,8 @@ func (c *VLLMClient) Generate(ctx context.Context, prompt string, options ...int
 		Temperature: params.LLMConfig.Temperature,
 		TopP:        params.LLMConfig.TopP,
 		Stop:        params.LLMConfig.StopSequences,
+		MaxTokens:   params.LLMConfig.MaxOutputTokens,
+		Seed:        params.LLMConfig.Seed,
 	}
 
 	// Handle structured output if provided
@@ -304,6 +308,11 @@ func (c *VLLMClient) Name() string {
 	return "vllm"
 }
 
+// WarmConnections implements interfaces.ConnectionWarmer.WarmConnections
+func (c *VLLMClient) WarmConnections(ctx context.Context) error {
+	return llm.WarmConnection(ctx, c.HTTPClient, c.BaseURL)
+}
+
 // SupportsStreaming returns false as streaming is not yet implemented for VLLM
 func (c *VLLMClient) SupportsStreaming() bool {
 	return false
@@ -343,7 +352,7 @@ func (c *VLLMClient) makeRequest(ctx context.Context, endpoint string, payload i
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, llm.ClassifyError("vllm", fmt.Errorf("failed to execute request: %w", err))
 	}
 	defer func() {
 		if resp != nil {
@@ -359,7 +368,8 @@ func (c *VLLMClient) makeRequest(ctx context.Context, endpoint string, payload i
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, llm.ClassifyHTTPError("vllm", resp.StatusCode, resp.Header, string(body),
+			fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body)))
 	}
 
 	return body, nil
@@ -386,7 +396,7 @@ func (c *VLLMClient) makeGETRequest(ctx context.Context, endpoint string) ([]byt
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, llm.ClassifyError("vllm", fmt.Errorf("failed to execute request: %w", err))
 	}
 	defer func() {
 		if resp != nil {
@@ -402,7 +412,8 @@ func (c *VLLMClient) makeGETRequest(ctx context.Context, endpoint string) ([]byt
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, llm.ClassifyHTTPError("vllm", resp.StatusCode, resp.Header, string(body),
+			fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body)))
 	}
 
 	return body, nil
@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultWarmupTimeout bounds a single warmup request
+	DefaultWarmupTimeout = 10 * time.Second
+
+	// DefaultKeepAliveInterval is below net/http's default IdleConnTimeout (90s),
+	// so pooled connections are refreshed before the transport closes them
+	DefaultKeepAliveInterval = 60 * time.Second
+)
+
+// WarmConnection opens a connection to baseURL through the given HTTP client so
+// the DNS lookup, TCP/TLS handshake and HTTP/2 session are already established
+// when the first real request is sent. A lightweight HEAD request is used and
+// any HTTP response, including 4xx errors, counts as a successful warmup since
+// the connection is returned to the client's pool either way.
+func WarmConnection(ctx context.Context, client *http.Client, baseURL string) error {
+	if baseURL == "" {
+		return fmt.Errorf("base URL is required for connection warmup")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultWarmupTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create warmup request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("connection warmup to %s failed: %w", baseURL, err)
+	}
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return nil
+}
+
+// KeepWarm warms the provider's connections immediately and then every interval
+// until the returned stop function is called or ctx is cancelled. Errors are
+// passed to onError when it is not nil. When interval is <= 0 only the initial
+// warmup is performed.
+func KeepWarm(ctx context.Context, warmer interfaces.ConnectionWarmer, interval time.Duration, onError func(error)) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+
+	warm := func() {
+		if err := warmer.WarmConnections(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		warm()
+		if interval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				warm()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarmConnection(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD request, got %s", r.Method)
+		}
+		atomic.AddInt32(&requests, 1)
+		// Providers typically answer unauthenticated requests with an error status
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if err := WarmConnection(context.Background(), server.Client(), server.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("expected 1 warmup request, got %d", requests)
+	}
+
+	if err := WarmConnection(context.Background(), nil, ""); err == nil {
+		t.Error("expected error for empty base URL")
+	}
+}
+
+type countingWarmer struct {
+	calls int32
+}
+
+func (w *countingWarmer) WarmConnections(ctx context.Context) error {
+	atomic.AddInt32(&w.calls, 1)
+	return nil
+}
+
+func TestKeepWarm(t *testing.T) {
+	warmer := &countingWarmer{}
+	stop := KeepWarm(context.Background(), warmer, 10*time.Millisecond, nil)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&warmer.calls) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	stop()
+	stop() // stopping twice is safe
+
+	calls := atomic.LoadInt32(&warmer.calls)
+	if calls < 3 {
+		t.Fatalf("expected at least 3 warmups, got %d", calls)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if after := atomic.LoadInt32(&warmer.calls); after != calls {
+		t.Errorf("expected no warmups after stop, got %d more", after-calls)
+	}
+}
+
+func TestKeepWarmStartupOnly(t *testing.T) {
+	warmer := &countingWarmer{}
+	stop := KeepWarm(context.Background(), warmer, 0, nil)
+	stop()
+
+	if calls := atomic.LoadInt32(&warmer.calls); calls != 1 {
+		t.Errorf("expected a single warmup, got %d", calls)
+	}
+}
@@ -13,6 +13,9 @@ import (
 // Global logger configuration
 var (
 	zeroLogJsonEnable bool = false
+
+	// output is where loggers created by New write
+	output io.Writer = os.Stdout
 )
 
 func init() {
@@ -32,6 +35,12 @@ func SetZeroLogJsonEnabled() {
 	zeroLogJsonEnable = true
 }
 
+// SetOutput sets where loggers created afterwards write (default: stdout),
+// e.g. os.Stderr in processes whose stdout carries a protocol such as MCP
+func SetOutput(w io.Writer) {
+	output = w
+}
+
 // Logger is an interface for logging
 type Logger interface {
 	Info(ctx context.Context, msg string, fields map[string]interface{})
@@ -47,11 +56,11 @@ type ZeroLogger struct {
 
 // New creates a new ZeroLogger
 func New() *ZeroLogger {
-	var output io.Writer = os.Stdout
+	output := output
 
 	if !zeroLogJsonEnable {
 		output = zerolog.ConsoleWriter{
-			Out:        os.Stdout,
+			Out:        output,
 			TimeFormat: time.RFC3339,
 		}
 	}
@@ -0,0 +1,177 @@
+package requestlog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// LoggedLLM implements middleware that logs the calls of an LLM
+type LoggedLLM struct {
+	llm    interfaces.LLM
+	logger *Logger
+}
+
+// NewLoggedLLM creates an LLM middleware that logs every call with logger
+func NewLoggedLLM(llm interfaces.LLM, logger *Logger) *LoggedLLM {
+	return &LoggedLLM{
+		llm:    llm,
+		logger: logger,
+	}
+}
+
+// Generate implements interfaces.LLM.Generate
+func (m *LoggedLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	start := time.Now()
+	response, err := m.llm.Generate(ctx, prompt, options...)
+	m.log(ctx, "generate", prompt, nil, &interfaces.LLMResponse{Content: response}, start, err)
+	return response, err
+}
+
+// GenerateWithTools implements interfaces.LLM.GenerateWithTools
+func (m *LoggedLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	start := time.Now()
+	response, err := m.llm.GenerateWithTools(ctx, prompt, tools, options...)
+	m.log(ctx, "generate_with_tools", prompt, tools, &interfaces.LLMResponse{Content: response}, start, err)
+	return response, err
+}
+
+// GenerateDetailed implements interfaces.LLM.GenerateDetailed
+func (m *LoggedLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	start := time.Now()
+	response, err := m.llm.GenerateDetailed(ctx, prompt, options...)
+	m.log(ctx, "generate", prompt, nil, response, start, err)
+	return response, err
+}
+
+// GenerateWithToolsDetailed implements interfaces.LLM.GenerateWithToolsDetailed
+func (m *LoggedLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	start := time.Now()
+	response, err := m.llm.GenerateWithToolsDetailed(ctx, prompt, tools, options...)
+	m.log(ctx, "generate_with_tools", prompt, tools, response, start, err)
+	return response, err
+}
+
+// GenerateStream implements interfaces.StreamingLLM.GenerateStream. The
+// completion is logged when the stream ends.
+func (m *LoggedLLM) GenerateStream(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	streamingLLM, ok := m.llm.(interfaces.StreamingLLM)
+	if !ok {
+		return nil, fmt.Errorf("underlying LLM does not support streaming")
+	}
+
+	start := time.Now()
+	events, err := streamingLLM.GenerateStream(ctx, prompt, options...)
+	if err != nil {
+		m.log(ctx, "generate_stream", prompt, nil, nil, start, err)
+		return nil, err
+	}
+	return m.logStream(ctx, "generate_stream", prompt, nil, events, start), nil
+}
+
+// GenerateWithToolsStream implements interfaces.StreamingLLM.GenerateWithToolsStream.
+// The completion is logged when the stream ends.
+func (m *LoggedLLM) GenerateWithToolsStream(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (<-chan interfaces.StreamEvent, error) {
+	streamingLLM, ok := m.llm.(interfaces.StreamingLLM)
+	if !ok {
+		return nil, fmt.Errorf("underlying LLM does not support streaming")
+	}
+
+	start := time.Now()
+	events, err := streamingLLM.GenerateWithToolsStream(ctx, prompt, tools, options...)
+	if err != nil {
+		m.log(ctx, "generate_with_tools_stream", prompt, tools, nil, start, err)
+		return nil, err
+	}
+	return m.logStream(ctx, "generate_with_tools_stream", prompt, tools, events, start), nil
+}
+
+// Name implements interfaces.LLM.Name
+func (m *LoggedLLM) Name() string {
+	return m.llm.Name()
+}
+
+// SupportsStreaming implements interfaces.LLM.SupportsStreaming
+func (m *LoggedLLM) SupportsStreaming() bool {
+	return m.llm.SupportsStreaming()
+}
+
+// GetModel returns the model name from the underlying LLM
+func (m *LoggedLLM) GetModel() string {
+	if modelProvider, ok := m.llm.(interface{ GetModel() string }); ok {
+		return modelProvider.GetModel()
+	}
+	return m.llm.Name()
+}
+
+// Unwrap returns the underlying LLM, e.g. to reach the interfaces it
+// implements that the middleware does not forward
+func (m *LoggedLLM) Unwrap() interfaces.LLM {
+	return m.llm
+}
+
+// WarmConnections implements interfaces.ConnectionWarmer when the underlying LLM does
+func (m *LoggedLLM) WarmConnections(ctx context.Context) error {
+	if warmer, ok := m.llm.(interfaces.ConnectionWarmer); ok {
+		return warmer.WarmConnections(ctx)
+	}
+	return nil
+}
+
+func (m *LoggedLLM) logStream(ctx context.Context, operation, prompt string, tools []interfaces.Tool, events <-chan interfaces.StreamEvent, start time.Time) <-chan interfaces.StreamEvent {
+	out := make(chan interfaces.StreamEvent, cap(events))
+	go func() {
+		defer close(out)
+
+		var content strings.Builder
+		var streamErr error
+		for event := range events {
+			switch event.Type {
+			case interfaces.StreamEventContentDelta:
+				content.WriteString(event.Content)
+			case interfaces.StreamEventError:
+				streamErr = event.Error
+			}
+			out <- event
+		}
+
+		m.log(ctx, operation, prompt, tools, &interfaces.LLMResponse{Content: content.String()}, start, streamErr)
+	}()
+	return out
+}
+
+func (m *LoggedLLM) log(ctx context.Context, operation, prompt string, tools []interfaces.Tool, response *interfaces.LLMResponse, start time.Time, err error) {
+	fields := map[string]interface{}{
+		FieldOperation:  operation,
+		FieldModel:      m.GetModel(),
+		FieldPrompt:     prompt,
+		FieldDurationMs: durationMs(start),
+	}
+	if len(tools) > 0 {
+		names := make([]string, len(tools))
+		for i, tool := range tools {
+			names[i] = tool.Name()
+		}
+		fields[FieldTools] = strings.Join(names, ",")
+	}
+	if response != nil {
+		fields[FieldCompletion] = response.Content
+		if response.Model != "" {
+			fields[FieldModel] = response.Model
+		}
+		if response.StopReason != "" {
+			fields[FieldFinishReason] = response.StopReason
+		}
+		if response.Usage != nil {
+			fields[FieldInputTokens] = response.Usage.InputTokens
+			fields[FieldOutputTokens] = response.Usage.OutputTokens
+		}
+	}
+	if err != nil {
+		fields[FieldError] = err.Error()
+	}
+	m.logger.Log(ctx, KindLLMCall, fields)
+}
@@ -0,0 +1,265 @@
+package requestlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OTLPSink exports records as OpenTelemetry logs over OTLP/HTTP (JSON
+// encoding). Records are batched and sent in the background.
+type OTLPSink struct {
+	endpoint      string
+	headers       map[string]string
+	serviceName   string
+	httpClient    *http.Client
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending []Record
+	flushCh chan struct{}
+	done    chan struct{}
+	closed  bool
+	wg      sync.WaitGroup
+	lastErr error
+}
+
+// OTLPOption configures the OTLP sink
+type OTLPOption func(*OTLPSink)
+
+// WithOTLPHeaders sets headers sent with every export, e.g. for authentication
+func WithOTLPHeaders(headers map[string]string) OTLPOption {
+	return func(s *OTLPSink) {
+		s.headers = headers
+	}
+}
+
+// WithOTLPServiceName sets the service.name resource attribute (default: agent-sdk-go)
+func WithOTLPServiceName(name string) OTLPOption {
+	return func(s *OTLPSink) {
+		s.serviceName = name
+	}
+}
+
+// WithOTLPHTTPClient sets the HTTP client used for exports
+func WithOTLPHTTPClient(client *http.Client) OTLPOption {
+	return func(s *OTLPSink) {
+		s.httpClient = client
+	}
+}
+
+// WithOTLPBatch sets the number of records per export (default: 100) and the
+// interval at which partial batches are exported (default: 5s)
+func WithOTLPBatch(size int, interval time.Duration) OTLPOption {
+	return func(s *OTLPSink) {
+		if size > 0 {
+			s.batchSize = size
+		}
+		if interval > 0 {
+			s.flushInterval = interval
+		}
+	}
+}
+
+// NewOTLPSink creates a sink exporting to the OTLP/HTTP logs endpoint of a
+// collector, e.g. http://localhost:4318/v1/logs. An endpoint without a path
+// gets /v1/logs appended.
+func NewOTLPSink(endpoint string, options ...OTLPOption) *OTLPSink {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1/logs") {
+		endpoint += "/v1/logs"
+	}
+
+	s := &OTLPSink{
+		endpoint:      endpoint,
+		serviceName:   "agent-sdk-go",
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		batchSize:     100,
+		flushInterval: 5 * time.Second,
+		flushCh:       make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.wg.Add(1)
+	go s.run()
+
+	return s
+}
+
+// Write implements Sink.Write. Export errors are returned by later writes
+// and by Close.
+func (s *OTLPSink) Write(ctx context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("OTLP sink is closed")
+	}
+	s.pending = append(s.pending, record)
+	if len(s.pending) >= s.batchSize {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+
+	err := s.lastErr
+	s.lastErr = nil
+	return err
+}
+
+// Close implements Sink.Close. It exports the pending records.
+func (s *OTLPSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.done)
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+func (s *OTLPSink) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.flushCh:
+			s.flush()
+		case <-s.done:
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *OTLPSink) flush() {
+	s.mu.Lock()
+	records := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(records) == 0 {
+		return
+	}
+	if err := s.export(records); err != nil {
+		s.mu.Lock()
+		s.lastErr = err
+		s.mu.Unlock()
+	}
+}
+
+func (s *OTLPSink) export(records []Record) error {
+	body, err := json.Marshal(s.payload(records))
+	if err != nil {
+		return fmt.Errorf("failed to marshal OTLP logs: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export OTLP logs: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("OTLP export failed (status %d): %s", resp.StatusCode, string(data))
+	}
+	return nil
+}
+
+// payload builds an OTLP ExportLogsServiceRequest in its JSON encoding
+func (s *OTLPSink) payload(records []Record) map[string]interface{} {
+	logRecords := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		attributes := []map[string]interface{}{otlpAttribute("kind", string(record.Kind))}
+		body := ""
+		for k, v := range record.Fields {
+			attributes = append(attributes, otlpAttribute(k, v))
+		}
+		if completion, ok := record.Fields[FieldCompletion].(string); ok {
+			body = completion
+		} else if result, ok := record.Fields[FieldResult].(string); ok {
+			body = result
+		}
+
+		severity, severityNumber := "INFO", 9
+		if _, failed := record.Fields[FieldError]; failed {
+			severity, severityNumber = "ERROR", 17
+		}
+
+		logRecords[i] = map[string]interface{}{
+			"timeUnixNano":   strconv.FormatInt(record.Time.UnixNano(), 10),
+			"severityText":   severity,
+			"severityNumber": severityNumber,
+			"body":           map[string]interface{}{"stringValue": body},
+			"attributes":     attributes,
+		}
+	}
+
+	return map[string]interface{}{
+		"resourceLogs": []map[string]interface{}{{
+			"resource": map[string]interface{}{
+				"attributes": []map[string]interface{}{otlpAttribute("service.name", s.serviceName)},
+			},
+			"scopeLogs": []map[string]interface{}{{
+				"scope":      map[string]interface{}{"name": "github.com/Ingenimax/agent-sdk-go/pkg/logging/requestlog"},
+				"logRecords": logRecords,
+			}},
+		}},
+	}
+}
+
+func otlpAttribute(key string, value interface{}) map[string]interface{} {
+	var v map[string]interface{}
+	switch value := value.(type) {
+	case string:
+		v = map[string]interface{}{"stringValue": value}
+	case bool:
+		v = map[string]interface{}{"boolValue": value}
+	case int:
+		v = map[string]interface{}{"intValue": strconv.Itoa(value)}
+	case int64:
+		v = map[string]interface{}{"intValue": strconv.FormatInt(value, 10)}
+	case float64:
+		v = map[string]interface{}{"doubleValue": value}
+	default:
+		v = map[string]interface{}{"stringValue": fmt.Sprintf("%v", value)}
+	}
+	return map[string]interface{}{"key": key, "value": v}
+}
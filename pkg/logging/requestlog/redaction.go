@@ -0,0 +1,84 @@
+package requestlog
+
+import (
+	"regexp"
+)
+
+// defaultReplacement replaces redacted values when a rule has no replacement
+const defaultReplacement = "[REDACTED]"
+
+// RedactionRule redacts fields of records. A rule with a Pattern replaces
+// the matches in string fields; a rule without one replaces the whole field,
+// or removes it when Drop is set.
+type RedactionRule struct {
+	// Fields are the fields the rule applies to; all string fields when empty
+	Fields []string
+
+	// Pattern selects the text to redact
+	Pattern *regexp.Regexp
+
+	// Replacement replaces redacted text (default "[REDACTED]")
+	Replacement string
+
+	// Drop removes the fields instead of replacing them
+	Drop bool
+}
+
+// DefaultRedactionRules returns the rules used when none are set: API keys,
+// bearer tokens, email addresses and card numbers in any field
+func DefaultRedactionRules() []RedactionRule {
+	return []RedactionRule{
+		{Pattern: regexp.MustCompile(`\b(sk|pk|rk)-[A-Za-z0-9_-]{16,}\b`), Replacement: "[API_KEY]"},
+		{Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{16,}=*`), Replacement: "Bearer [TOKEN]"},
+		{Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`), Replacement: "[EMAIL]"},
+		{Pattern: regexp.MustCompile(`\b(?:\d[ -]?){13,16}\b`), Replacement: "[CARD]"},
+	}
+}
+
+// DropFields returns a rule that removes fields, e.g. DropFields(FieldPrompt,
+// FieldCompletion) to keep only metadata and timings
+func DropFields(fields ...string) RedactionRule {
+	return RedactionRule{Fields: fields, Drop: true}
+}
+
+// MaskFields returns a rule that replaces fields with "[REDACTED]"
+func MaskFields(fields ...string) RedactionRule {
+	return RedactionRule{Fields: fields}
+}
+
+func redact(fields map[string]interface{}, rules []RedactionRule) {
+	for _, rule := range rules {
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = defaultReplacement
+		}
+
+		for _, name := range rule.fieldNames(fields) {
+			value, ok := fields[name]
+			if !ok {
+				continue
+			}
+			switch {
+			case rule.Drop:
+				delete(fields, name)
+			case rule.Pattern == nil:
+				fields[name] = replacement
+			default:
+				if s, ok := value.(string); ok {
+					fields[name] = rule.Pattern.ReplaceAllLiteralString(s, replacement)
+				}
+			}
+		}
+	}
+}
+
+func (r *RedactionRule) fieldNames(fields map[string]interface{}) []string {
+	if len(r.Fields) > 0 {
+		return r.Fields
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	return names
+}
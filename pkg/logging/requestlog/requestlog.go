@@ -0,0 +1,163 @@
+// Package requestlog records LLM prompts and completions, tool calls and
+// their timings as structured records. Records pass through field-level
+// redaction rules before they are written to a sink: JSON lines on stdout
+// or in a file, or OTLP logs.
+package requestlog
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
+)
+
+// Kind is the kind of a record
+type Kind string
+
+const (
+	// KindLLMCall records an LLM call: prompt, completion, model and usage
+	KindLLMCall Kind = "llm_call"
+
+	// KindToolCall records a tool execution: arguments and result
+	KindToolCall Kind = "tool_call"
+)
+
+// Field names of records, used by redaction rules
+const (
+	FieldAgent          = "agent"
+	FieldOrgID          = "org_id"
+	FieldConversationID = "conversation_id"
+	FieldOperation      = "operation"
+	FieldModel          = "model"
+	FieldPrompt         = "prompt"
+	FieldCompletion     = "completion"
+	FieldTools          = "tools"
+	FieldFinishReason   = "finish_reason"
+	FieldInputTokens    = "input_tokens"
+	FieldOutputTokens   = "output_tokens"
+	FieldTool           = "tool"
+	FieldArguments      = "arguments"
+	FieldResult         = "result"
+	FieldError          = "error"
+	FieldDurationMs     = "duration_ms"
+)
+
+// Record is a structured log record
+type Record struct {
+	Time   time.Time
+	Kind   Kind
+	Fields map[string]interface{}
+}
+
+// MarshalJSON writes the record as a flat JSON object
+func (r Record) MarshalJSON() ([]byte, error) {
+	flat := make(map[string]interface{}, len(r.Fields)+2)
+	for k, v := range r.Fields {
+		flat[k] = v
+	}
+	flat["time"] = r.Time.UTC().Format(time.RFC3339Nano)
+	flat["kind"] = r.Kind
+	return json.Marshal(flat)
+}
+
+// Sink writes records
+type Sink interface {
+	// Write writes a record
+	Write(ctx context.Context, record Record) error
+
+	// Close flushes buffered records and releases the sink
+	Close() error
+}
+
+// Logger redacts records and writes them to a sink
+type Logger struct {
+	sink     Sink
+	rules    []RedactionRule
+	logger   logging.Logger
+	clock    func() time.Time
+	redactOn bool
+}
+
+// Option configures a Logger
+type Option func(*Logger)
+
+// WithRedaction sets the redaction rules applied to every record, replacing
+// DefaultRedactionRules
+func WithRedaction(rules ...RedactionRule) Option {
+	return func(l *Logger) {
+		l.rules = rules
+	}
+}
+
+// WithoutRedaction writes records as they are
+func WithoutRedaction() Option {
+	return func(l *Logger) {
+		l.redactOn = false
+	}
+}
+
+// WithLogger sets the logger used to report sink failures
+func WithLogger(logger logging.Logger) Option {
+	return func(l *Logger) {
+		l.logger = logger
+	}
+}
+
+// New creates a Logger writing to sink. Records are redacted with
+// DefaultRedactionRules unless other rules are set.
+func New(sink Sink, options ...Option) *Logger {
+	l := &Logger{
+		sink:     sink,
+		rules:    DefaultRedactionRules(),
+		clock:    time.Now,
+		redactOn: true,
+	}
+
+	for _, option := range options {
+		option(l)
+	}
+
+	if l.logger == nil {
+		l.logger = logging.New()
+	}
+
+	return l
+}
+
+// Log redacts fields and writes them as a record of kind. The agent,
+// organization and conversation are taken from ctx when set.
+func (l *Logger) Log(ctx context.Context, kind Kind, fields map[string]interface{}) {
+	if agent, ok := tracing.GetAgentName(ctx); ok && agent != "" {
+		fields[FieldAgent] = agent
+	}
+	if orgID, err := multitenancy.GetOrgID(ctx); err == nil && orgID != "" {
+		fields[FieldOrgID] = orgID
+	}
+	if conversationID, ok := memory.GetConversationID(ctx); ok && conversationID != "" {
+		fields[FieldConversationID] = conversationID
+	}
+	if l.redactOn {
+		redact(fields, l.rules)
+	}
+
+	record := Record{Time: l.clock(), Kind: kind, Fields: fields}
+	if err := l.sink.Write(ctx, record); err != nil {
+		l.logger.Warn(ctx, "Failed to write request log record", map[string]interface{}{
+			"kind":  string(kind),
+			"error": err.Error(),
+		})
+	}
+}
+
+// Close closes the sink
+func (l *Logger) Close() error {
+	return l.sink.Close()
+}
+
+func durationMs(start time.Time) int64 {
+	return time.Since(start).Milliseconds()
+}
@@ -0,0 +1,309 @@
+package requestlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
+)
+
+type memorySink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (s *memorySink) Write(ctx context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *memorySink) Close() error { return nil }
+
+func (s *memorySink) all() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Record(nil), s.records...)
+}
+
+type stubLLM struct {
+	response string
+	err      error
+}
+
+func (l *stubLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return l.response, l.err
+}
+
+func (l *stubLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return l.response, l.err
+}
+
+func (l *stubLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return &interfaces.LLMResponse{
+		Content:    l.response,
+		Model:      "stub-model",
+		StopReason: "stop",
+		Usage:      &interfaces.TokenUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+func (l *stubLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return l.GenerateDetailed(ctx, prompt, options...)
+}
+
+func (l *stubLLM) Name() string            { return "stub" }
+func (l *stubLLM) SupportsStreaming() bool { return false }
+
+type stubTool struct {
+	result string
+	err    error
+}
+
+func (t *stubTool) Name() string        { return "lookup" }
+func (t *stubTool) Description() string { return "Looks things up" }
+func (t *stubTool) Parameters() map[string]interfaces.ParameterSpec {
+	return nil
+}
+func (t *stubTool) Run(ctx context.Context, input string) (string, error) {
+	return t.result, t.err
+}
+func (t *stubTool) Execute(ctx context.Context, args string) (string, error) {
+	return t.result, t.err
+}
+
+func TestLoggedLLMRecordsCall(t *testing.T) {
+	sink := &memorySink{}
+	llm := NewLoggedLLM(&stubLLM{response: "Paris"}, New(sink))
+
+	ctx := tracing.WithAgentName(context.Background(), "geo")
+	ctx = multitenancy.WithOrgID(ctx, "org-1")
+	ctx = memory.WithConversationID(ctx, "conv-1")
+
+	if _, err := llm.GenerateDetailed(ctx, "What is the capital of France?"); err != nil {
+		t.Fatalf("GenerateDetailed failed: %v", err)
+	}
+
+	records := sink.all()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if record.Kind != KindLLMCall {
+		t.Errorf("expected kind %s, got %s", KindLLMCall, record.Kind)
+	}
+
+	expected := map[string]interface{}{
+		FieldAgent:          "geo",
+		FieldOrgID:          "org-1",
+		FieldConversationID: "conv-1",
+		FieldModel:          "stub-model",
+		FieldPrompt:         "What is the capital of France?",
+		FieldCompletion:     "Paris",
+		FieldFinishReason:   "stop",
+		FieldInputTokens:    10,
+		FieldOutputTokens:   5,
+	}
+	for field, want := range expected {
+		if got := record.Fields[field]; got != want {
+			t.Errorf("expected %s=%v, got %v", field, want, got)
+		}
+	}
+	if _, ok := record.Fields[FieldDurationMs]; !ok {
+		t.Error("expected duration_ms to be recorded")
+	}
+}
+
+func TestLoggedLLMRecordsError(t *testing.T) {
+	sink := &memorySink{}
+	llm := NewLoggedLLM(&stubLLM{err: errors.New("rate limited")}, New(sink))
+
+	if _, err := llm.Generate(context.Background(), "hello"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	records := sink.all()
+	if len(records) != 1 || records[0].Fields[FieldError] != "rate limited" {
+		t.Fatalf("expected error record, got %+v", records)
+	}
+}
+
+func TestLoggedToolRecordsCall(t *testing.T) {
+	sink := &memorySink{}
+	tool := NewLoggedTool(&stubTool{result: "42"}, New(sink))
+
+	result, err := tool.Execute(context.Background(), `{"q":"answer"}`)
+	if err != nil || result != "42" {
+		t.Fatalf("unexpected result %q, %v", result, err)
+	}
+
+	records := sink.all()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	fields := records[0].Fields
+	if records[0].Kind != KindToolCall || fields[FieldTool] != "lookup" || fields[FieldArguments] != `{"q":"answer"}` || fields[FieldResult] != "42" {
+		t.Errorf("unexpected record %+v", records[0])
+	}
+}
+
+func TestDefaultRedaction(t *testing.T) {
+	sink := &memorySink{}
+	logger := New(sink)
+
+	logger.Log(context.Background(), KindLLMCall, map[string]interface{}{
+		FieldPrompt:      "Email jane.doe@example.com with key sk-abcdefghijklmnopqrstuvwx",
+		FieldCompletion:  "Charged card 4111 1111 1111 1111",
+		FieldInputTokens: 12,
+	})
+
+	fields := sink.all()[0].Fields
+	if got := fields[FieldPrompt]; got != "Email [EMAIL] with key [API_KEY]" {
+		t.Errorf("unexpected prompt %q", got)
+	}
+	if got := fields[FieldCompletion]; got != "Charged card [CARD]" {
+		t.Errorf("unexpected completion %q", got)
+	}
+	if got := fields[FieldInputTokens]; got != 12 {
+		t.Errorf("expected non-string fields to be kept, got %v", got)
+	}
+}
+
+func TestFieldRedactionRules(t *testing.T) {
+	sink := &memorySink{}
+	logger := New(sink, WithRedaction(DropFields(FieldPrompt), MaskFields(FieldResult)))
+
+	logger.Log(context.Background(), KindToolCall, map[string]interface{}{
+		FieldPrompt: "secret prompt",
+		FieldResult: "secret result",
+		FieldTool:   "lookup",
+	})
+
+	fields := sink.all()[0].Fields
+	if _, ok := fields[FieldPrompt]; ok {
+		t.Error("expected prompt to be dropped")
+	}
+	if fields[FieldResult] != "[REDACTED]" {
+		t.Errorf("expected result to be masked, got %v", fields[FieldResult])
+	}
+	if fields[FieldTool] != "lookup" {
+		t.Errorf("expected tool to be kept, got %v", fields[FieldTool])
+	}
+}
+
+func TestWithoutRedaction(t *testing.T) {
+	sink := &memorySink{}
+	New(sink, WithoutRedaction()).Log(context.Background(), KindLLMCall, map[string]interface{}{
+		FieldPrompt: "jane.doe@example.com",
+	})
+
+	if got := sink.all()[0].Fields[FieldPrompt]; got != "jane.doe@example.com" {
+		t.Errorf("expected prompt to be kept, got %v", got)
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONSink(&buf)
+
+	record := Record{
+		Time:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Kind:   KindToolCall,
+		Fields: map[string]interface{}{FieldTool: "lookup", FieldDurationMs: 7},
+	}
+	if err := sink.Write(context.Background(), record); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := sink.Write(context.Background(), record); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if decoded["kind"] != "tool_call" || decoded["tool"] != "lookup" || decoded["duration_ms"] != float64(7) {
+		t.Errorf("unexpected record %v", decoded)
+	}
+	if decoded["time"] != "2025-01-02T03:04:05Z" {
+		t.Errorf("unexpected time %v", decoded["time"])
+	}
+}
+
+func TestOTLPSink(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []map[string]interface{}
+		paths  []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("invalid OTLP payload: %v", err)
+		}
+		mu.Lock()
+		bodies = append(bodies, body)
+		paths = append(paths, r.URL.Path+" "+r.Header.Get("X-Api-Key"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sink := NewOTLPSink(server.URL, WithOTLPHeaders(map[string]string{"X-Api-Key": "secret"}), WithOTLPBatch(10, time.Hour))
+	logger := New(sink)
+	logger.Log(context.Background(), KindLLMCall, map[string]interface{}{
+		FieldModel:      "gpt-4o",
+		FieldCompletion: "Hello",
+	})
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 export, got %d", len(bodies))
+	}
+	if paths[0] != "/v1/logs secret" {
+		t.Errorf("unexpected request %q", paths[0])
+	}
+
+	logRecords := bodies[0]["resourceLogs"].([]interface{})[0].(map[string]interface{})["scopeLogs"].([]interface{})[0].(map[string]interface{})["logRecords"].([]interface{})
+	if len(logRecords) != 1 {
+		t.Fatalf("expected 1 log record, got %d", len(logRecords))
+	}
+	logRecord := logRecords[0].(map[string]interface{})
+	if body := logRecord["body"].(map[string]interface{})["stringValue"]; body != "Hello" {
+		t.Errorf("expected completion as body, got %v", body)
+	}
+
+	attributes := map[string]interface{}{}
+	for _, a := range logRecord["attributes"].([]interface{}) {
+		attribute := a.(map[string]interface{})
+		attributes[attribute["key"].(string)] = attribute["value"].(map[string]interface{})["stringValue"]
+	}
+	if attributes["kind"] != "llm_call" || attributes[FieldModel] != "gpt-4o" {
+		t.Errorf("unexpected attributes %v", attributes)
+	}
+}
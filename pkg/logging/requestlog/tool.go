@@ -0,0 +1,82 @@
+package requestlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// LoggedTool implements middleware that logs the executions of a tool
+type LoggedTool struct {
+	tool   interfaces.Tool
+	logger *Logger
+}
+
+// NewLoggedTool creates a tool middleware that logs every execution with logger
+func NewLoggedTool(tool interfaces.Tool, logger *Logger) *LoggedTool {
+	return &LoggedTool{
+		tool:   tool,
+		logger: logger,
+	}
+}
+
+// Name implements interfaces.Tool.Name
+func (t *LoggedTool) Name() string {
+	return t.tool.Name()
+}
+
+// Description implements interfaces.Tool.Description
+func (t *LoggedTool) Description() string {
+	return t.tool.Description()
+}
+
+// Parameters implements interfaces.Tool.Parameters
+func (t *LoggedTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.tool.Parameters()
+}
+
+// Run implements interfaces.Tool.Run
+func (t *LoggedTool) Run(ctx context.Context, input string) (string, error) {
+	start := time.Now()
+	result, err := t.tool.Run(ctx, input)
+	t.log(ctx, input, result, start, err)
+	return result, err
+}
+
+// Execute implements interfaces.Tool.Execute
+func (t *LoggedTool) Execute(ctx context.Context, args string) (string, error) {
+	start := time.Now()
+	result, err := t.tool.Execute(ctx, args)
+	t.log(ctx, args, result, start, err)
+	return result, err
+}
+
+// DisplayName forwards to the underlying tool when it implements ToolWithDisplayName
+func (t *LoggedTool) DisplayName() string {
+	if d, ok := t.tool.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.tool.Name()
+}
+
+// Internal forwards to the underlying tool when it implements InternalTool
+func (t *LoggedTool) Internal() bool {
+	if i, ok := t.tool.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
+
+func (t *LoggedTool) log(ctx context.Context, args, result string, start time.Time, err error) {
+	fields := map[string]interface{}{
+		FieldTool:       t.tool.Name(),
+		FieldArguments:  args,
+		FieldResult:     result,
+		FieldDurationMs: durationMs(start),
+	}
+	if err != nil {
+		fields[FieldError] = err.Error()
+	}
+	t.logger.Log(ctx, KindToolCall, fields)
+}
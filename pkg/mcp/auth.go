@@ -0,0 +1,519 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// OAuthConfig configures the OAuth 2.1 authorization code flow for an MCP
+// server over HTTP, as described by the MCP authorization specification.
+// The flow starts when the server first answers 401 Unauthorized; tokens are
+// then refreshed with their refresh token and kept in the TokenStore.
+type OAuthConfig struct {
+	// ClientID is the client registered with the authorization server
+	ClientID string
+
+	// ClientSecret is the secret of confidential clients; public clients
+	// rely on PKCE alone
+	ClientSecret string
+
+	// RedirectURL receives the authorization code, e.g.
+	// "http://localhost:8085/callback"
+	RedirectURL string
+
+	// Scopes to request. Defaults to the scopes the server asks for.
+	Scopes []string
+
+	// AuthURL and TokenURL are the endpoints of the authorization server.
+	// When empty they are discovered from the protected resource metadata of
+	// the MCP server (RFC 9728) and the metadata of its authorization server
+	// (RFC 8414).
+	AuthURL  string
+	TokenURL string
+
+	// Authorize sends the user to the authorization URL and returns the code
+	// and state delivered to RedirectURL. Defaults to
+	// CallbackAuthorizer(RedirectURL, nil).
+	Authorize AuthorizeFunc
+
+	// TokenStore keeps tokens between connections (default: in memory)
+	TokenStore TokenStore
+}
+
+// AuthorizeFunc sends the user to authURL and returns the authorization code
+// and state delivered to the redirect URL
+type AuthorizeFunc func(ctx context.Context, authURL string) (code, state string, err error)
+
+// TokenStore keeps the OAuth tokens of an MCP server
+type TokenStore interface {
+	// LoadToken returns the stored token, or nil if there is none
+	LoadToken(ctx context.Context) (*oauth2.Token, error)
+
+	// SaveToken stores a new or refreshed token
+	SaveToken(ctx context.Context, token *oauth2.Token) error
+}
+
+// memoryTokenStore keeps a token in memory
+type memoryTokenStore struct {
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func (s *memoryTokenStore) LoadToken(ctx context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token, nil
+}
+
+func (s *memoryTokenStore) SaveToken(ctx context.Context, token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+	return nil
+}
+
+// FileTokenStore keeps a token in a JSON file readable only by its owner, so
+// that users authorize once rather than on every start
+type FileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileTokenStore creates a token store writing to path
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// LoadToken implements TokenStore
+func (s *FileTokenStore) LoadToken(ctx context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	var token oauth2.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse token file: %w", err)
+	}
+	return &token, nil
+}
+
+// SaveToken implements TokenStore
+func (s *FileTokenStore) SaveToken(ctx context.Context, token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	return nil
+}
+
+// CallbackAuthorizer returns an AuthorizeFunc for command-line and desktop
+// applications. It calls open with the authorization URL, e.g. to open a
+// browser, and receives the redirect on the local address of redirectURL.
+// A nil open prints the URL to stderr.
+func CallbackAuthorizer(redirectURL string, open func(authURL string) error) AuthorizeFunc {
+	return func(ctx context.Context, authURL string) (string, string, error) {
+		u, err := url.Parse(redirectURL)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid redirect URL: %w", err)
+		}
+		listener, err := net.Listen("tcp", u.Host)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to listen on redirect URL: %w", err)
+		}
+
+		path := u.Path
+		if path == "" {
+			path = "/"
+		}
+		results := make(chan url.Values, 1)
+		mux := http.NewServeMux()
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case results <- r.URL.Query():
+				fmt.Fprintln(w, "Authorization complete, you can close this window.")
+			default:
+				http.Error(w, "authorization already received", http.StatusConflict)
+			}
+		})
+		server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() { _ = server.Serve(listener) }()
+		defer server.Close()
+
+		if open == nil {
+			fmt.Fprintf(os.Stderr, "Open this URL to authorize the MCP server:\n%s\n", authURL)
+		} else if err := open(authURL); err != nil {
+			return "", "", fmt.Errorf("failed to open authorization URL: %w", err)
+		}
+
+		select {
+		case query := <-results:
+			if authErr := query.Get("error"); authErr != "" {
+				return "", "", fmt.Errorf("authorization denied: %s %s", authErr, query.Get("error_description"))
+			}
+			return query.Get("code"), query.Get("state"), nil
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		}
+	}
+}
+
+// newAuthHTTPClient creates the HTTP client of an MCP server, adding its
+// headers and credentials to each request
+func newAuthHTTPClient(config HTTPServerConfig) *http.Client {
+	if len(config.Headers) == 0 && config.Token == "" && config.TokenSource == nil && config.OAuth == nil {
+		return http.DefaultClient
+	}
+
+	transport := &authTransport{
+		base:    http.DefaultTransport,
+		headers: config.Headers,
+	}
+	switch {
+	case config.OAuth != nil:
+		resource := config.ResourceIndicator
+		if resource == "" {
+			resource = config.BaseURL
+		}
+		transport.oauth = newOAuthFlow(config.OAuth, config.BaseURL, resource)
+	case config.TokenSource != nil:
+		transport.tokenSource = oauth2.ReuseTokenSource(nil, config.TokenSource)
+	case config.Token != "":
+		transport.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: config.Token, TokenType: "Bearer"})
+	}
+	return &http.Client{Transport: transport}
+}
+
+// authTransport adds headers and credentials to the requests of an MCP
+// server, and runs the OAuth flow when the server asks for authorization
+type authTransport struct {
+	base        http.RoundTripper
+	headers     map[string]string
+	tokenSource oauth2.TokenSource
+	oauth       *oauthFlow
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.send(req)
+	if err != nil || t.oauth == nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		// The request cannot be sent again
+		return resp, nil
+	}
+
+	challenges := resp.Header.Values("WWW-Authenticate")
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if err := t.oauth.authorize(req.Context(), challenges); err != nil {
+		return nil, fmt.Errorf("failed to authorize MCP server: %w", err)
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	return t.send(retry)
+}
+
+// send sends a request with the headers and current token
+func (t *authTransport) send(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the request
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+
+	source := t.tokenSource
+	if t.oauth != nil {
+		source = t.oauth.tokenSource(req.Context())
+	}
+	if source != nil {
+		token, err := source.Token()
+		switch {
+		case err == nil:
+			token.SetAuthHeader(req)
+		case t.oauth == nil:
+			return nil, fmt.Errorf("failed to get MCP access token: %w", err)
+		}
+		// Without a valid OAuth token the server answers 401 and the
+		// authorization flow starts again
+	}
+	return t.base.RoundTrip(req)
+}
+
+// oauthFlow obtains and refreshes the OAuth tokens of an MCP server
+type oauthFlow struct {
+	config    *OAuthConfig
+	serverURL string
+	resource  string
+	store     TokenStore
+
+	mu       sync.Mutex
+	endpoint oauth2.Endpoint
+	scopes   []string
+	source   oauth2.TokenSource
+	loaded   bool
+}
+
+func newOAuthFlow(config *OAuthConfig, serverURL, resource string) *oauthFlow {
+	store := config.TokenStore
+	if store == nil {
+		store = &memoryTokenStore{}
+	}
+	return &oauthFlow{
+		config:    config,
+		serverURL: serverURL,
+		resource:  resource,
+		store:     store,
+		scopes:    config.Scopes,
+	}
+}
+
+// tokenSource returns the token source, loading a stored token on first
+// use, or nil before authorization
+func (f *oauthFlow) tokenSource(ctx context.Context) oauth2.TokenSource {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.source != nil || f.loaded {
+		return f.source
+	}
+	f.loaded = true
+
+	token, err := f.store.LoadToken(ctx)
+	if err != nil || token == nil {
+		return nil
+	}
+	// Refreshing needs the token endpoint; without it the token is used
+	// until it expires
+	_ = f.discover(ctx, nil)
+	f.source = f.newSource(token)
+	return f.source
+}
+
+// authorize runs the authorization code flow with PKCE
+func (f *oauthFlow) authorize(ctx context.Context, challenges []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.discover(ctx, challenges); err != nil {
+		return err
+	}
+	config := f.oauth2Config()
+
+	state, err := randomString()
+	if err != nil {
+		return err
+	}
+	verifier := oauth2.GenerateVerifier()
+	resource := oauth2.SetAuthURLParam("resource", f.resource)
+	authURL := config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier), resource)
+
+	authorize := f.config.Authorize
+	if authorize == nil {
+		authorize = CallbackAuthorizer(f.config.RedirectURL, nil)
+	}
+	code, gotState, err := authorize(ctx, authURL)
+	if err != nil {
+		return err
+	}
+	if gotState != state {
+		return fmt.Errorf("authorization state mismatch")
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(verifier), resource)
+	if err != nil {
+		return fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	if err := f.store.SaveToken(ctx, token); err != nil {
+		return err
+	}
+	f.source = f.newSource(token)
+	return nil
+}
+
+// newSource returns a token source refreshing token and storing refreshed
+// tokens
+func (f *oauthFlow) newSource(token *oauth2.Token) oauth2.TokenSource {
+	return &storingTokenSource{
+		source: f.oauth2Config().TokenSource(context.Background(), token),
+		store:  f.store,
+		last:   token.AccessToken,
+	}
+}
+
+func (f *oauthFlow) oauth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     f.config.ClientID,
+		ClientSecret: f.config.ClientSecret,
+		RedirectURL:  f.config.RedirectURL,
+		Scopes:       f.scopes,
+		Endpoint:     f.endpoint,
+	}
+}
+
+// discover finds the endpoints of the authorization server, using the
+// resource metadata URL of the server's challenge when it has one
+func (f *oauthFlow) discover(ctx context.Context, challenges []string) error {
+	if f.config.AuthURL != "" && f.config.TokenURL != "" {
+		f.endpoint = oauth2.Endpoint{AuthURL: f.config.AuthURL, TokenURL: f.config.TokenURL}
+		return nil
+	}
+	if f.endpoint.TokenURL != "" {
+		return nil
+	}
+
+	var resourceMeta struct {
+		AuthorizationServers []string `json:"authorization_servers"`
+		ScopesSupported      []string `json:"scopes_supported"`
+	}
+	metadataURLs := wellKnownURLs(f.serverURL, "oauth-protected-resource")
+	if metadataURL := challengeParam(challenges, "resource_metadata"); metadataURL != "" {
+		metadataURLs = []string{metadataURL}
+	}
+	issuer := f.serverURL
+	if getMetadata(ctx, metadataURLs, &resourceMeta) && len(resourceMeta.AuthorizationServers) > 0 {
+		issuer = resourceMeta.AuthorizationServers[0]
+	}
+
+	var serverMeta struct {
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+	}
+	metadataURLs = append(wellKnownURLs(issuer, "oauth-authorization-server"), wellKnownURLs(issuer, "openid-configuration")...)
+	if !getMetadata(ctx, metadataURLs, &serverMeta) || serverMeta.AuthorizationEndpoint == "" || serverMeta.TokenEndpoint == "" {
+		return fmt.Errorf("failed to discover the authorization server of %s", f.serverURL)
+	}
+	f.endpoint = oauth2.Endpoint{AuthURL: serverMeta.AuthorizationEndpoint, TokenURL: serverMeta.TokenEndpoint}
+
+	if len(f.scopes) == 0 {
+		if scope := challengeParam(challenges, "scope"); scope != "" {
+			f.scopes = strings.Fields(scope)
+		} else {
+			f.scopes = resourceMeta.ScopesSupported
+		}
+	}
+	return nil
+}
+
+// storingTokenSource stores the tokens of a source when they are refreshed
+type storingTokenSource struct {
+	source oauth2.TokenSource
+	store  TokenStore
+
+	mu   sync.Mutex
+	last string
+}
+
+// Token implements oauth2.TokenSource
+func (s *storingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token.AccessToken != s.last {
+		s.last = token.AccessToken
+		// A failed save only costs a new authorization on the next start
+		_ = s.store.SaveToken(context.Background(), token)
+	}
+	return token, nil
+}
+
+// wellKnownURLs returns the well-known metadata URLs of a server, with the
+// path of the server inserted after the well-known name and at the root
+func wellKnownURLs(serverURL, name string) []string {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil
+	}
+	origin := u.Scheme + "://" + u.Host
+	path := strings.TrimSuffix(u.Path, "/")
+	if path == "" {
+		return []string{origin + "/.well-known/" + name}
+	}
+	return []string{origin + "/.well-known/" + name + path, origin + "/.well-known/" + name}
+}
+
+// getMetadata decodes the first metadata document found at urls
+func getMetadata(ctx context.Context, urls []string, metadata interface{}) bool {
+	for _, metadataURL := range urls {
+		req, err := http.NewRequestWithContext(ctx, "GET", metadataURL, nil)
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			continue
+		}
+		ok := resp.StatusCode == http.StatusOK &&
+			json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(metadata) == nil
+		resp.Body.Close()
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+var challengeParamPattern = regexp.MustCompile(`([a-zA-Z_]+)="([^"]*)"`)
+
+// challengeParam returns a parameter of the Bearer WWW-Authenticate
+// challenges
+func challengeParam(challenges []string, name string) string {
+	for _, challenge := range challenges {
+		if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(challenge)), "bearer") {
+			continue
+		}
+		for _, match := range challengeParamPattern.FindAllStringSubmatch(challenge, -1) {
+			if match[1] == name {
+				return match[2]
+			}
+		}
+	}
+	return ""
+}
+
+// randomString returns a random URL-safe string
+func randomString() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
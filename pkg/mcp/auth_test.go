@@ -0,0 +1,261 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+func TestNewHTTPServer_Headers(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "protected"}, nil)
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+
+	var mu sync.Mutex
+	var rawQueries []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		rawQueries = append(rawQueries, r.URL.RawQuery)
+		mu.Unlock()
+		if r.Header.Get("X-API-Key") != "key-1" || r.Header.Get("Authorization") != "Bearer token-1" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+
+	client, err := NewHTTPServer(context.Background(), HTTPServerConfig{
+		BaseURL:      httpServer.URL,
+		ProtocolType: StreamableHTTP,
+		Token:        "token-1",
+		Headers:      map[string]string{"X-API-Key": "key-1"},
+		Logger:       logging.New(),
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	info, err := client.GetServerInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "protected", info.Name)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, query := range rawQueries {
+		assert.NotContains(t, query, "token")
+	}
+}
+
+// oauthServer is an MCP endpoint with its own authorization server
+type oauthServer struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	challenge     string
+	resource      string
+	authorized    int
+	refreshed     int
+	accessToken   string
+	tokenRequests []url.Values
+}
+
+func newOAuthServer(t *testing.T) *oauthServer {
+	s := &oauthServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		valid := s.accessToken != "" && r.Header.Get("Authorization") == "Bearer "+s.accessToken
+		s.mu.Unlock()
+		if !valid {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer resource_metadata="%s/.well-known/oauth-protected-resource/mcp", scope="tools"`, s.URL))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body := make([]byte, 64)
+		n, _ := r.Body.Read(body)
+		fmt.Fprintf(w, "ok %s", body[:n])
+	})
+	mux.HandleFunc("/.well-known/oauth-protected-resource/mcp", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"resource":              s.URL + "/mcp",
+			"authorization_servers": []string{s.URL + "/auth"},
+		})
+	})
+	mux.HandleFunc("/.well-known/oauth-authorization-server/auth", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                 s.URL + "/auth",
+			"authorization_endpoint": s.URL + "/auth/authorize",
+			"token_endpoint":         s.URL + "/auth/token",
+		})
+	})
+	mux.HandleFunc("/auth/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.tokenRequests = append(s.tokenRequests, r.PostForm)
+
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			if r.PostForm.Get("code") != "code-1" || oauth2.S256ChallengeFromVerifier(r.PostForm.Get("code_verifier")) != s.challenge {
+				http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+			s.authorized++
+			s.accessToken = "access-1"
+		case "refresh_token":
+			s.refreshed++
+			s.accessToken = "access-2"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  s.accessToken,
+			"token_type":    "Bearer",
+			"refresh_token": "refresh-1",
+			"expires_in":    3600,
+		})
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// authorize plays the user, approving the authorization request
+func (s *oauthServer) authorize(ctx context.Context, authURL string) (string, string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	query := u.Query()
+	s.mu.Lock()
+	s.challenge = query.Get("code_challenge")
+	s.resource = query.Get("resource")
+	s.mu.Unlock()
+	if !strings.HasPrefix(authURL, s.URL+"/auth/authorize?") || query.Get("code_challenge_method") != "S256" || query.Get("scope") != "tools" {
+		return "", "", fmt.Errorf("unexpected authorization URL %s", authURL)
+	}
+	return "code-1", query.Get("state"), nil
+}
+
+func TestOAuthAuthorizationCodeFlow(t *testing.T) {
+	server := newOAuthServer(t)
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "token.json"))
+	client := newAuthHTTPClient(HTTPServerConfig{
+		BaseURL: server.URL + "/mcp",
+		OAuth: &OAuthConfig{
+			ClientID:    "client-1",
+			RedirectURL: "http://localhost:8085/callback",
+			Authorize:   server.authorize,
+			TokenStore:  store,
+		},
+	})
+
+	// The first request is authorized and sent again with its body
+	resp, err := client.Post(server.URL+"/mcp", "application/json", strings.NewReader("hello"))
+	require.NoError(t, err)
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok hello", string(body[:n]))
+
+	resp, err = client.Post(server.URL+"/mcp", "application/json", strings.NewReader("again"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	server.mu.Lock()
+	assert.Equal(t, 1, server.authorized)
+	assert.Equal(t, server.URL+"/mcp", server.resource)
+	assert.Equal(t, server.URL+"/mcp", server.tokenRequests[0].Get("resource"))
+	server.mu.Unlock()
+
+	token, err := store.LoadToken(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, token)
+	assert.Equal(t, "access-1", token.AccessToken)
+}
+
+func TestOAuthRefreshesStoredToken(t *testing.T) {
+	server := newOAuthServer(t)
+	store := &memoryTokenStore{}
+	// An expired token from an earlier run
+	require.NoError(t, store.SaveToken(context.Background(), &oauth2.Token{
+		AccessToken:  "expired",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(-time.Hour),
+	}))
+
+	client := newAuthHTTPClient(HTTPServerConfig{
+		BaseURL: server.URL + "/mcp",
+		OAuth: &OAuthConfig{
+			ClientID:   "client-1",
+			TokenStore: store,
+			Authorize: func(context.Context, string) (string, string, error) {
+				return "", "", fmt.Errorf("unexpected authorization")
+			},
+		},
+	})
+
+	resp, err := client.Get(server.URL + "/mcp")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	server.mu.Lock()
+	assert.Equal(t, 1, server.refreshed)
+	assert.Equal(t, 0, server.authorized)
+	server.mu.Unlock()
+
+	token, _ := store.LoadToken(context.Background())
+	assert.Equal(t, "access-2", token.AccessToken)
+}
+
+func TestCallbackAuthorizer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	redirectURL := "http://" + listener.Addr().String() + "/callback"
+	listener.Close()
+
+	authorize := CallbackAuthorizer(redirectURL, func(authURL string) error {
+		// The user approves in the browser, which follows the redirect
+		go func() {
+			resp, err := http.Get(redirectURL + "?code=code-1&state=state-1")
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	})
+
+	code, state, err := authorize(context.Background(), "https://auth.example.com/authorize")
+	require.NoError(t, err)
+	assert.Equal(t, "code-1", code)
+	assert.Equal(t, "state-1", state)
+}
+
+func TestChallengeParam(t *testing.T) {
+	challenges := []string{`Basic realm="x"`, `Bearer error="invalid_token", resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource", scope="read write"`}
+	assert.Equal(t, "https://mcp.example.com/.well-known/oauth-protected-resource", challengeParam(challenges, "resource_metadata"))
+	assert.Equal(t, "read write", challengeParam(challenges, "scope"))
+	assert.Empty(t, challengeParam(challenges, "realm"))
+
+	assert.Equal(t, []string{
+		"https://mcp.example.com/.well-known/oauth-protected-resource/v1/mcp",
+		"https://mcp.example.com/.well-known/oauth-protected-resource",
+	}, wellKnownURLs("https://mcp.example.com/v1/mcp", "oauth-protected-resource"))
+}
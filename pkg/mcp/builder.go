@@ -18,6 +18,7 @@ type Builder struct {
 	logger       logging.Logger
 	retryOptions *RetryOptions
 	healthCheck  bool
+	reconnect    *ReconnectConfig
 	timeout      time.Duration
 	errors       []error
 }
@@ -70,6 +71,14 @@ func (b *Builder) WithHealthCheck(enabled bool) *Builder {
 	return b
 }
 
+// WithReconnect configures how servers reconnect after losing their
+// connection, and how often they are health checked and their tools
+// refreshed. Defaults to DefaultReconnectConfig.
+func (b *Builder) WithReconnect(config *ReconnectConfig) *Builder {
+	b.reconnect = config
+	return b
+}
+
 // AddServer adds an MCP server from a URL string
 // Supports formats:
 // - stdio://command/path/to/executable
@@ -79,6 +88,8 @@ func (b *Builder) WithHealthCheck(enabled bool) *Builder {
 // - mcp://preset-name (for presets)
 //
 // For HTTP URLs, optional query params: token (auth), transport (sse or streamable).
+// The token is sent as a bearer token rather than in the query string; prefer
+// AddHTTPServerWithAuth, which keeps it out of URLs and logs.
 func (b *Builder) AddServer(urlStr string) *Builder {
 	server, config, err := b.parseServerURL(urlStr)
 	if err != nil {
@@ -118,31 +129,53 @@ func (b *Builder) AddHTTPServer(name, baseURL string) *Builder {
 	return b
 }
 
-// AddHTTPServerWithAuth adds an HTTP-based MCP server with authentication
+// AddHTTPServerWithAuth adds an HTTP-based MCP server authenticated with a
+// bearer token in the Authorization header
 func (b *Builder) AddHTTPServerWithAuth(name, baseURL, token string) *Builder {
-	// Parse URL and add token as query parameter if not present
-	u, err := url.Parse(baseURL)
+	return b.addHTTPServer(LazyMCPServerConfig{
+		Name:  name,
+		Type:  "http",
+		URL:   baseURL,
+		Token: token,
+	})
+}
+
+// AddHTTPServerWithHeaders adds an HTTP-based MCP server whose requests carry
+// the given headers, e.g. {"X-API-Key": key}
+func (b *Builder) AddHTTPServerWithHeaders(name, baseURL string, headers map[string]string) *Builder {
+	return b.addHTTPServer(LazyMCPServerConfig{
+		Name:    name,
+		Type:    "http",
+		URL:     baseURL,
+		Headers: headers,
+	})
+}
+
+// AddHTTPServerWithOAuth adds an HTTP-based MCP server authorized with the
+// OAuth 2.1 authorization code flow
+func (b *Builder) AddHTTPServerWithOAuth(name, baseURL string, oauth *OAuthConfig) *Builder {
+	return b.addHTTPServer(LazyMCPServerConfig{
+		Name:  name,
+		Type:  "http",
+		URL:   baseURL,
+		OAuth: oauth,
+	})
+}
+
+// addHTTPServer validates the URL of an HTTP server and adds it
+func (b *Builder) addHTTPServer(config LazyMCPServerConfig) *Builder {
+	u, err := url.Parse(config.URL)
 	if err != nil {
-		b.errors = append(b.errors, fmt.Errorf("invalid URL %q: %w", baseURL, err))
+		b.errors = append(b.errors, fmt.Errorf("invalid URL %q: %w", config.URL, err))
 		return b
 	}
 
 	// Validate URL has proper scheme for HTTP server
 	if u.Scheme != "http" && u.Scheme != "https" {
-		b.errors = append(b.errors, fmt.Errorf("invalid URL scheme for HTTP server %q: expected http or https, got %q", baseURL, u.Scheme))
+		b.errors = append(b.errors, fmt.Errorf("invalid URL scheme for HTTP server %q: expected http or https, got %q", config.URL, u.Scheme))
 		return b
 	}
 
-	// Add token to query parameters
-	q := u.Query()
-	q.Set("token", token)
-	u.RawQuery = q.Encode()
-
-	config := LazyMCPServerConfig{
-		Name: name,
-		Type: "http",
-		URL:  u.String(),
-	}
 	b.lazyConfigs = append(b.lazyConfigs, config)
 	return b
 }
@@ -178,7 +211,9 @@ func (b *Builder) Build(ctx context.Context) ([]interfaces.MCPServer, []LazyMCPS
 		for _, config := range b.lazyConfigs {
 			// Only initialize if it's a critical server (could be configured)
 			if b.shouldInitializeEagerly(config) {
-				server, err := b.initializeServer(ctx, config)
+				server, err := NewResilientServer(ctx, config.Name, func(ctx context.Context) (interfaces.MCPServer, error) {
+					return b.initializeServer(ctx, config)
+				}, b.reconnect)
 				if err != nil {
 					b.logger.Warn(ctx, "Failed to initialize MCP server", map[string]interface{}{
 						"server_name": config.Name,
@@ -192,6 +227,7 @@ func (b *Builder) Build(ctx context.Context) ([]interfaces.MCPServer, []LazyMCPS
 		}
 	}
 
+	b.applyReconnect()
 	return b.servers, b.lazyConfigs, nil
 }
 
@@ -200,9 +236,22 @@ func (b *Builder) BuildLazy() ([]LazyMCPServerConfig, error) {
 	if len(b.errors) > 0 {
 		return nil, fmt.Errorf("builder errors: %v", b.errors)
 	}
+	b.applyReconnect()
 	return b.lazyConfigs, nil
 }
 
+// applyReconnect sets the reconnection configuration of the lazy servers
+func (b *Builder) applyReconnect() {
+	if b.reconnect == nil {
+		return
+	}
+	for i := range b.lazyConfigs {
+		if b.lazyConfigs[i].Reconnect == nil {
+			b.lazyConfigs[i].Reconnect = b.reconnect
+		}
+	}
+}
+
 // parseServerURL parses an MCP server URL and returns either a server or a lazy config
 func (b *Builder) parseServerURL(urlStr string) (interfaces.MCPServer, *LazyMCPServerConfig, error) {
 	u, err := url.Parse(urlStr)
@@ -310,6 +359,8 @@ func (b *Builder) initializeServer(ctx context.Context, config LazyMCPServerConf
 		server, err = NewHTTPServerWithRetry(ctx, HTTPServerConfig{
 			BaseURL:      config.URL,
 			Token:        config.Token,
+			Headers:      config.Headers,
+			OAuth:        config.OAuth,
 			Logger:       b.logger,
 			ProtocolType: ServerProtocolType(config.HttpTransportMode),
 		}, &RetryConfig{
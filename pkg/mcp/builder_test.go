@@ -172,7 +172,8 @@ func TestBuilder_AddHTTPServerWithAuth(t *testing.T) {
 				config := builder.lazyConfigs[0]
 				assert.Equal(t, tt.serverName, config.Name)
 				assert.Equal(t, "http", config.Type)
-				assert.Contains(t, config.URL, "token="+tt.token)
+				assert.Equal(t, tt.baseURL, config.URL)
+				assert.Equal(t, tt.token, config.Token)
 			}
 		})
 	}
@@ -519,16 +520,14 @@ func TestBuilder_parseServerURL_Security(t *testing.T) {
 func TestBuilder_AddHTTPServerWithAuth_TokenSecurity(t *testing.T) {
 	builder := NewBuilder()
 
-	// Test that token is added to query params (current behavior)
 	builder.AddHTTPServerWithAuth("test", "https://api.example.com/mcp", "secret-token")
 
 	assert.Len(t, builder.lazyConfigs, 1)
 	config := builder.lazyConfigs[0]
 
-	// Current implementation exposes token in URL
-	// This test documents the behavior that needs to be fixed
-	assert.Contains(t, config.URL, "token=secret-token")
-	t.Log("Warning: Token is exposed in URL query parameters - security issue")
+	// The token is sent as a bearer header, never in the URL
+	assert.NotContains(t, config.URL, "secret-token")
+	assert.Equal(t, "secret-token", config.Token)
 }
 
 // Benchmark tests for performance monitoring
@@ -0,0 +1,374 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// Catalog gives agents the resources and prompts of MCP servers. Lists are
+// cached for servers that notify list changes, and refreshed when they do;
+// other servers are listed on every call.
+type Catalog struct {
+	servers     []interfaces.MCPServer
+	lazyConfigs []LazyMCPServerConfig
+	logger      logging.Logger
+
+	mu        sync.Mutex
+	resolved  bool
+	resources map[int][]interfaces.MCPResource
+	prompts   map[int][]interfaces.MCPPrompt
+}
+
+// NewCatalog creates a catalog of the given servers. Servers of lazy
+// configurations are started on first use.
+func NewCatalog(servers []interfaces.MCPServer, lazyConfigs ...LazyMCPServerConfig) *Catalog {
+	return &Catalog{
+		servers:     servers,
+		lazyConfigs: lazyConfigs,
+		logger:      logging.New(),
+		resources:   make(map[int][]interfaces.MCPResource),
+		prompts:     make(map[int][]interfaces.MCPPrompt),
+	}
+}
+
+// Resources lists the resources of all servers
+func (c *Catalog) Resources(ctx context.Context) ([]ResourceMatch, error) {
+	var matches []ResourceMatch
+	var lastErr error
+	servers := c.resolveServers(ctx)
+
+	for i, server := range servers {
+		resources, err := c.listResources(ctx, i, server)
+		if err != nil {
+			c.logger.Warn(ctx, "Failed to list resources from server", map[string]interface{}{
+				"server": serverName(i, server),
+				"error":  err.Error(),
+			})
+			lastErr = err
+			continue
+		}
+		for _, resource := range resources {
+			matches = append(matches, ResourceMatch{Server: server, Resource: resource})
+		}
+	}
+
+	if len(matches) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return matches, nil
+}
+
+// ReadResource reads a resource from the server listing it, or from the
+// first server that can read it, such as a server with a matching template
+func (c *Catalog) ReadResource(ctx context.Context, uri string) (*interfaces.MCPResourceContent, error) {
+	matches, err := c.Resources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		if match.Resource.URI == uri {
+			return match.Server.GetResource(ctx, uri)
+		}
+	}
+
+	for _, server := range c.resolveServers(ctx) {
+		if content, err := server.GetResource(ctx, uri); err == nil {
+			return content, nil
+		}
+	}
+	return nil, fmt.Errorf("resource not found on any server: %s", uri)
+}
+
+// Prompts lists the prompts of all servers
+func (c *Catalog) Prompts(ctx context.Context) ([]PromptMatch, error) {
+	var matches []PromptMatch
+	var lastErr error
+	servers := c.resolveServers(ctx)
+
+	for i, server := range servers {
+		prompts, err := c.listPrompts(ctx, i, server)
+		if err != nil {
+			c.logger.Warn(ctx, "Failed to list prompts from server", map[string]interface{}{
+				"server": serverName(i, server),
+				"error":  err.Error(),
+			})
+			lastErr = err
+			continue
+		}
+		for _, prompt := range prompts {
+			matches = append(matches, PromptMatch{Server: server, Prompt: prompt})
+		}
+	}
+
+	if len(matches) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return matches, nil
+}
+
+// GetPrompt renders a prompt of the first server listing it with the given
+// arguments
+func (c *Catalog) GetPrompt(ctx context.Context, name string, args map[string]interface{}) (*interfaces.MCPPromptResult, error) {
+	matches, err := c.Prompts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		if match.Prompt.Name != name {
+			continue
+		}
+		var missing []string
+		for _, arg := range match.Prompt.Arguments {
+			if _, ok := args[arg.Name]; arg.Required && !ok {
+				missing = append(missing, arg.Name)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("prompt %s is missing required arguments: %s", name, strings.Join(missing, ", "))
+		}
+		return match.Server.GetPrompt(ctx, name, args)
+	}
+	return nil, fmt.Errorf("prompt not found on any server: %s", name)
+}
+
+// PromptText joins the messages of a rendered prompt into a single input
+func PromptText(result *interfaces.MCPPromptResult) string {
+	if result.Prompt != "" {
+		return result.Prompt
+	}
+	parts := make([]string, 0, len(result.Messages))
+	for _, message := range result.Messages {
+		if message.Content != "" {
+			parts = append(parts, message.Content)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// ResourceTools returns tools letting an agent list and read the resources
+// of the catalog
+func (c *Catalog) ResourceTools() []interfaces.Tool {
+	return []interfaces.Tool{&listResourcesTool{catalog: c}, &readResourceTool{catalog: c}}
+}
+
+// resolveServers starts the servers of lazy configurations on first use and
+// subscribes to the change notifications of all servers
+func (c *Catalog) resolveServers(ctx context.Context) []interfaces.MCPServer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.resolved {
+		return c.servers
+	}
+	c.resolved = true
+
+	for _, config := range c.lazyConfigs {
+		server, err := GetOrCreateServerFromCache(ctx, config)
+		if err != nil {
+			c.logger.Warn(ctx, "Failed to start MCP server", map[string]interface{}{
+				"server": config.Name,
+				"error":  err.Error(),
+			})
+			continue
+		}
+		c.servers = append(c.servers, server)
+	}
+
+	for i, server := range c.servers {
+		if !notifiesChanges(server) {
+			continue
+		}
+		i := i
+		server.(ChangeNotifier).OnListChanged(func(change ListChange) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			switch change {
+			case ListChangeResources:
+				delete(c.resources, i)
+			case ListChangePrompts:
+				delete(c.prompts, i)
+			}
+		})
+	}
+	return c.servers
+}
+
+// listResources returns the cached resources of a server, listing them when
+// they are not cached
+func (c *Catalog) listResources(ctx context.Context, i int, server interfaces.MCPServer) ([]interfaces.MCPResource, error) {
+	c.mu.Lock()
+	resources, ok := c.resources[i]
+	c.mu.Unlock()
+	if ok {
+		return resources, nil
+	}
+
+	resources, err := server.ListResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if caps, _ := server.GetCapabilities(); notifiesChanges(server) && caps != nil && caps.Resources != nil && caps.Resources.ListChanged {
+		c.mu.Lock()
+		c.resources[i] = resources
+		c.mu.Unlock()
+	}
+	return resources, nil
+}
+
+// listPrompts returns the cached prompts of a server, listing them when they
+// are not cached
+func (c *Catalog) listPrompts(ctx context.Context, i int, server interfaces.MCPServer) ([]interfaces.MCPPrompt, error) {
+	c.mu.Lock()
+	prompts, ok := c.prompts[i]
+	c.mu.Unlock()
+	if ok {
+		return prompts, nil
+	}
+
+	prompts, err := server.ListPrompts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if caps, _ := server.GetCapabilities(); notifiesChanges(server) && caps != nil && caps.Prompts != nil && caps.Prompts.ListChanged {
+		c.mu.Lock()
+		c.prompts[i] = prompts
+		c.mu.Unlock()
+	}
+	return prompts, nil
+}
+
+// notifiesChanges reports whether a server forwards change notifications
+func notifiesChanges(server interfaces.MCPServer) bool {
+	_, ok := server.(ChangeNotifier)
+	return ok
+}
+
+// serverName names a server in logs and tool output
+func serverName(i int, server interfaces.MCPServer) string {
+	if info, err := server.GetServerInfo(); err == nil && info != nil && info.Name != "" {
+		return info.Name
+	}
+	return fmt.Sprintf("server-%d", i)
+}
+
+// listResourcesTool lists the resources of a catalog
+type listResourcesTool struct {
+	catalog *Catalog
+}
+
+func (t *listResourcesTool) Name() string {
+	return "mcp_list_resources"
+}
+
+func (t *listResourcesTool) Description() string {
+	return "Lists the resources (documents, files and data) available from MCP servers. " +
+		"Read a resource with mcp_read_resource."
+}
+
+func (t *listResourcesTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"query": {
+			Type:        "string",
+			Description: "Only list resources whose URI, name or description contains this text",
+		},
+	}
+}
+
+func (t *listResourcesTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+func (t *listResourcesTool) Execute(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Query string `json:"query"`
+	}
+	if strings.TrimSpace(args) != "" {
+		if err := json.Unmarshal([]byte(args), &params); err != nil {
+			return "", fmt.Errorf("failed to parse arguments: %w", err)
+		}
+	}
+
+	matches, err := t.catalog.Resources(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := strings.ToLower(params.Query)
+	var lines []string
+	for _, match := range matches {
+		r := match.Resource
+		if query != "" && !strings.Contains(strings.ToLower(r.URI+"\n"+r.Name+"\n"+r.Description), query) {
+			continue
+		}
+		line := "- " + r.URI
+		if r.Name != "" {
+			line += " (" + r.Name + ")"
+		}
+		if r.MimeType != "" {
+			line += " [" + r.MimeType + "]"
+		}
+		if r.Description != "" {
+			line += ": " + r.Description
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return "No resources found.", nil
+	}
+	return fmt.Sprintf("Found %d resources:\n%s", len(lines), strings.Join(lines, "\n")), nil
+}
+
+// readResourceTool reads a resource of a catalog
+type readResourceTool struct {
+	catalog *Catalog
+}
+
+func (t *readResourceTool) Name() string {
+	return "mcp_read_resource"
+}
+
+func (t *readResourceTool) Description() string {
+	return "Reads the content of a resource from MCP servers by URI, as listed by mcp_list_resources."
+}
+
+func (t *readResourceTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"uri": {
+			Type:        "string",
+			Description: "URI of the resource",
+			Required:    true,
+		},
+	}
+}
+
+func (t *readResourceTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+func (t *readResourceTool) Execute(ctx context.Context, args string) (string, error) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse arguments: %w", err)
+	}
+	if params.URI == "" {
+		return "", fmt.Errorf("uri is required")
+	}
+
+	content, err := t.catalog.ReadResource(ctx, params.URI)
+	if err != nil {
+		return "", err
+	}
+	if content.Text == "" && len(content.Blob) > 0 {
+		return fmt.Sprintf("Resource %s is binary content (%s, %d bytes) and cannot be shown as text.",
+			params.URI, content.MimeType, len(content.Blob)), nil
+	}
+	return content.Text, nil
+}
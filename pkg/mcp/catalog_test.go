@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// docsServer is an MCP server with a resource and a prompt
+type docsServer struct {
+	server *mcp.Server
+
+	mu     sync.Mutex
+	readme string
+}
+
+func newDocsServer() *docsServer {
+	d := &docsServer{readme: "# Readme"}
+	d.server = mcp.NewServer(&mcp.Implementation{Name: "docs"}, &mcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+	})
+	d.server.AddResource(&mcp.Resource{URI: "file:///readme.md", Name: "readme", MIMEType: "text/markdown", Description: "Project readme"}, d.read)
+	d.server.AddPrompt(&mcp.Prompt{
+		Name:      "summarize",
+		Arguments: []*mcp.PromptArgument{{Name: "topic", Required: true}},
+	}, func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{Messages: []*mcp.PromptMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: "Summarize " + req.Params.Arguments["topic"]}},
+		}}, nil
+	})
+	return d
+}
+
+func (d *docsServer) read(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+		{URI: req.Params.URI, MIMEType: "text/markdown", Text: d.readme},
+	}}, nil
+}
+
+func (d *docsServer) setReadme(text string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.readme = text
+}
+
+// connect connects a client to the server over in-memory transports
+func (d *docsServer) connect(t *testing.T) interfaces.MCPServer {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	session, err := d.server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	client, err := NewMCPServer(ctx, clientTransport)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestCatalog_ResourcesAndPrompts(t *testing.T) {
+	ctx := context.Background()
+	catalog := NewCatalog([]interfaces.MCPServer{newDocsServer().connect(t)})
+
+	tools := catalog.ResourceTools()
+	require.Len(t, tools, 2)
+
+	list, err := tools[0].Execute(ctx, `{"query": "README"}`)
+	require.NoError(t, err)
+	assert.Contains(t, list, "file:///readme.md (readme) [text/markdown]: Project readme")
+
+	list, err = tools[0].Execute(ctx, `{"query": "changelog"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "No resources found.", list)
+
+	text, err := tools[1].Execute(ctx, `{"uri": "file:///readme.md"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "# Readme", text)
+
+	_, err = catalog.GetPrompt(ctx, "summarize", nil)
+	assert.ErrorContains(t, err, "missing required arguments: topic")
+
+	result, err := catalog.GetPrompt(ctx, "summarize", map[string]interface{}{"topic": "MCP"})
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize MCP", PromptText(result))
+
+	_, err = catalog.GetPrompt(ctx, "missing", nil)
+	assert.ErrorContains(t, err, "prompt not found")
+}
+
+func TestCatalog_RefreshesOnListChanged(t *testing.T) {
+	ctx := context.Background()
+	docs := newDocsServer()
+	catalog := NewCatalog([]interfaces.MCPServer{docs.connect(t)})
+
+	resources, err := catalog.Resources(ctx)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	// The list is cached until the server reports a change
+	catalog.mu.Lock()
+	_, cached := catalog.resources[0]
+	catalog.mu.Unlock()
+	assert.True(t, cached)
+
+	docs.server.AddResource(&mcp.Resource{URI: "file:///changelog.md", Name: "changelog"}, docs.read)
+	assert.Eventually(t, func() bool {
+		resources, err := catalog.Resources(ctx)
+		return err == nil && len(resources) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	docs.server.AddPrompt(&mcp.Prompt{Name: "review"}, func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+	assert.Eventually(t, func() bool {
+		prompts, err := catalog.Prompts(ctx)
+		return err == nil && len(prompts) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestMCPServerImpl_WatchResourceSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	docs := newDocsServer()
+	client := docs.connect(t)
+
+	updates, err := client.WatchResource(ctx, "file:///readme.md")
+	require.NoError(t, err)
+
+	docs.setReadme("# Readme v2")
+	require.NoError(t, docs.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: "file:///readme.md"}))
+
+	// Notified updates arrive well before the polling interval
+	select {
+	case update := <-updates:
+		assert.Equal(t, interfaces.MCPResourceUpdateTypeChanged, update.Type)
+		require.NotNil(t, update.Content)
+		assert.True(t, strings.HasSuffix(update.Content.Text, "v2"))
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected an update after the notification")
+	}
+
+	cancel()
+	for range updates {
+	}
+}
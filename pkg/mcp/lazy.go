@@ -37,7 +37,7 @@ var globalServerCache = &LazyMCPServerCache{
 
 // getOrCreateServer gets an existing server or creates a new one
 func (cache *LazyMCPServerCache) getOrCreateServer(ctx context.Context, config LazyMCPServerConfig) (interfaces.MCPServer, error) {
-	serverKey := fmt.Sprintf("%s:%s:%v:%s", config.Type, config.Name, config.Command, config.CustomTransportType)
+	serverKey := cacheKey(config)
 
 	// Try to get existing server (read lock)
 	cache.mu.RLock()
@@ -61,9 +61,96 @@ func (cache *LazyMCPServerCache) getOrCreateServer(ctx context.Context, config L
 		serverLogger = logging.New()
 	}
 
-	var server interfaces.MCPServer
-	var err error
+	// Reconnect with backoff if the connection is lost later on
+	reconnectConfig := DefaultReconnectConfig()
+	if config.Reconnect != nil {
+		copied := *config.Reconnect
+		reconnectConfig = &copied
+	}
+	if reconnectConfig.Logger == nil {
+		reconnectConfig.Logger = serverLogger
+	}
+	server, err := NewResilientServer(ctx, config.Name, func(ctx context.Context) (interfaces.MCPServer, error) {
+		return cache.connect(ctx, config, serverLogger)
+	}, reconnectConfig)
+
+	if err != nil {
+		cache.logger.Error(ctx, "Failed to initialize MCP server", map[string]interface{}{
+			"server_name": config.Name,
+			"error":       err.Error(),
+		})
+		return nil, fmt.Errorf("failed to initialize MCP server '%s': %v", config.Name, err)
+	}
+
+	cache.servers[serverKey] = server
+
+	// Capture server metadata if available
+	if serverInfo, err := server.GetServerInfo(); err == nil && serverInfo != nil {
+		cache.serverMetadata[serverKey] = serverInfo
+		cache.logger.Info(ctx, "MCP server initialized successfully with metadata", map[string]interface{}{
+			"server_name":        config.Name,
+			"discovered_name":    serverInfo.Name,
+			"discovered_title":   serverInfo.Title,
+			"discovered_version": serverInfo.Version,
+		})
+	} else {
+		cache.logger.Info(ctx, "MCP server initialized successfully", map[string]interface{}{
+			"server_name": config.Name,
+		})
+	}
+
+	// Wait for MCP server to be ready with retries
+	cache.logger.Info(ctx, "Waiting for MCP server to be ready", map[string]interface{}{
+		"server_name":    config.Name,
+		"max_retries":    defaultMaxRetryAttempts,
+		"retry_interval": defaultRetryInterval.String(),
+	})
+
+	for attempt := 1; attempt <= defaultMaxRetryAttempts; attempt++ {
+		// Try to list tools to check if server is ready
+		_, err := server.ListTools(ctx)
+		if err == nil {
+			cache.logger.Info(ctx, "MCP server is ready", map[string]interface{}{
+				"server_name": config.Name,
+				"attempt":     attempt,
+			})
+			break
+		}
+
+		if attempt < defaultMaxRetryAttempts {
+			cache.logger.Debug(ctx, "MCP server not ready, retrying", map[string]interface{}{
+				"server_name": config.Name,
+				"attempt":     attempt,
+				"error":       err.Error(),
+			})
+			time.Sleep(defaultRetryInterval)
+		} else {
+			cache.logger.Warn(ctx, "MCP server may not be fully ready after retries", map[string]interface{}{
+				"server_name": config.Name,
+				"attempts":    attempt,
+				"last_error":  err.Error(),
+			})
+		}
+	}
+
+	return server, nil
+}
+
+// lookup returns the server of a configuration if it has been created
+func (cache *LazyMCPServerCache) lookup(config LazyMCPServerConfig) (interfaces.MCPServer, bool) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	server, exists := cache.servers[cacheKey(config)]
+	return server, exists
+}
 
+// cacheKey identifies the server of a configuration in the cache
+func cacheKey(config LazyMCPServerConfig) string {
+	return fmt.Sprintf("%s:%s:%v:%s", config.Type, config.Name, config.Command, config.CustomTransportType)
+}
+
+// connect connects to the MCP server of a lazy configuration
+func (cache *LazyMCPServerCache) connect(ctx context.Context, config LazyMCPServerConfig, serverLogger logging.Logger) (interfaces.MCPServer, error) {
 	switch config.Type {
 	case "stdio":
 		// Log environment variables being passed (masking sensitive values)
@@ -92,7 +179,7 @@ func (cache *LazyMCPServerCache) getOrCreateServer(ctx context.Context, config L
 			"env_count":   len(config.Env),
 			"env_vars":    envDebug,
 		})
-		server, err = NewStdioServer(ctx, StdioServerConfig{
+		return NewStdioServer(ctx, StdioServerConfig{
 			Command: config.Command,
 			Args:    config.Args,
 			Env:     config.Env,
@@ -104,9 +191,11 @@ func (cache *LazyMCPServerCache) getOrCreateServer(ctx context.Context, config L
 			"server_type":    config.Type,
 			"transport_mode": config.HttpTransportMode,
 		})
-		server, err = NewHTTPServer(ctx, HTTPServerConfig{
+		return NewHTTPServer(ctx, HTTPServerConfig{
 			BaseURL:      config.URL,
 			Token:        config.Token,
+			Headers:      config.Headers,
+			OAuth:        config.OAuth,
 			ProtocolType: ServerProtocolType(config.HttpTransportMode),
 			Logger:       serverLogger,
 		})
@@ -124,7 +213,7 @@ func (cache *LazyMCPServerCache) getOrCreateServer(ctx context.Context, config L
 			"server_type":           config.Type,
 			"custom_transport_type": config.CustomTransportType,
 		})
-		server, err = NewCustomTransportServer(ctx, CustomTransportServerConfig{
+		return NewCustomTransportServer(ctx, CustomTransportServerConfig{
 			Transport:     config.CustomMCPTransport,
 			Logger:        serverLogger,
 			TransportType: config.CustomTransportType,
@@ -132,67 +221,6 @@ func (cache *LazyMCPServerCache) getOrCreateServer(ctx context.Context, config L
 	default:
 		return nil, fmt.Errorf("unsupported MCP server type: %s", config.Type)
 	}
-
-	if err != nil {
-		cache.logger.Error(ctx, "Failed to initialize MCP server", map[string]interface{}{
-			"server_name": config.Name,
-			"error":       err.Error(),
-		})
-		return nil, fmt.Errorf("failed to initialize MCP server '%s': %v", config.Name, err)
-	}
-
-	cache.servers[serverKey] = server
-
-	// Capture server metadata if available
-	if serverInfo, err := server.GetServerInfo(); err == nil && serverInfo != nil {
-		cache.serverMetadata[serverKey] = serverInfo
-		cache.logger.Info(ctx, "MCP server initialized successfully with metadata", map[string]interface{}{
-			"server_name":        config.Name,
-			"discovered_name":    serverInfo.Name,
-			"discovered_title":   serverInfo.Title,
-			"discovered_version": serverInfo.Version,
-		})
-	} else {
-		cache.logger.Info(ctx, "MCP server initialized successfully", map[string]interface{}{
-			"server_name": config.Name,
-		})
-	}
-
-	// Wait for MCP server to be ready with retries
-	cache.logger.Info(ctx, "Waiting for MCP server to be ready", map[string]interface{}{
-		"server_name":    config.Name,
-		"max_retries":    defaultMaxRetryAttempts,
-		"retry_interval": defaultRetryInterval.String(),
-	})
-
-	for attempt := 1; attempt <= defaultMaxRetryAttempts; attempt++ {
-		// Try to list tools to check if server is ready
-		_, err := server.ListTools(ctx)
-		if err == nil {
-			cache.logger.Info(ctx, "MCP server is ready", map[string]interface{}{
-				"server_name": config.Name,
-				"attempt":     attempt,
-			})
-			break
-		}
-
-		if attempt < defaultMaxRetryAttempts {
-			cache.logger.Debug(ctx, "MCP server not ready, retrying", map[string]interface{}{
-				"server_name": config.Name,
-				"attempt":     attempt,
-				"error":       err.Error(),
-			})
-			time.Sleep(defaultRetryInterval)
-		} else {
-			cache.logger.Warn(ctx, "MCP server may not be fully ready after retries", map[string]interface{}{
-				"server_name": config.Name,
-				"attempts":    attempt,
-				"last_error":  err.Error(),
-			})
-		}
-	}
-
-	return server, nil
 }
 
 // LazyMCPServerConfig holds configuration for creating an MCP server on demand
@@ -203,12 +231,15 @@ type LazyMCPServerConfig struct {
 	Args                []string
 	Env                 []string
 	URL                 string
-	Token               string         // Bearer token for HTTP authentication
-	HttpTransportMode   string         // "sse" or "streamable"
-	AllowedTools        []string       // List of allowed tool names for this MCP server
-	CustomMCPTransport  mcp.Transport  // Custom transport for "custom" server type
-	Logger              logging.Logger // Optional logger for server initialization
-	CustomTransportType string         // Type of custom transport (e.g. "websocket", "kafka")
+	Token               string            // Bearer token for HTTP authentication
+	Headers             map[string]string // Headers added to HTTP requests, e.g. API keys
+	OAuth               *OAuthConfig      // OAuth authorization of HTTP servers
+	HttpTransportMode   string            // "sse" or "streamable"
+	AllowedTools        []string          // List of allowed tool names for this MCP server
+	CustomMCPTransport  mcp.Transport     // Custom transport for "custom" server type
+	Logger              logging.Logger    // Optional logger for server initialization
+	CustomTransportType string            // Type of custom transport (e.g. "websocket", "kafka")
+	Reconnect           *ReconnectConfig  // Reconnection and health checks (default: DefaultReconnectConfig)
 }
 
 // LazyMCPTool is a tool that initializes its MCP server on first use
@@ -269,6 +300,16 @@ func (t *LazyMCPTool) Internal() bool {
 	return false
 }
 
+// Available implements interfaces.ToolWithAvailability.Available. Tools
+// whose server has not been started yet are available.
+func (t *LazyMCPTool) Available() bool {
+	server, exists := globalServerCache.lookup(t.serverConfig)
+	if !exists {
+		return true
+	}
+	return toolAvailable(server, t.name)
+}
+
 // getServer gets the MCP server, initializing it if necessary
 func (t *LazyMCPTool) getServer(ctx context.Context) (interfaces.MCPServer, error) {
 	server, err := globalServerCache.getOrCreateServer(ctx, t.serverConfig)
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/oauth2"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
@@ -49,6 +50,9 @@ type MCPServerImpl struct {
 	logger       logging.Logger
 	serverInfo   *interfaces.MCPServerInfo
 	capabilities *interfaces.MCPServerCapabilities
+
+	// notifications dispatches the change notifications of the server
+	notifications *notifications
 }
 
 const TraceParentAttribute = "traceparent"
@@ -116,10 +120,11 @@ func NewMCPServer(ctx context.Context, transport mcp.Transport) (interfaces.MCPS
 	logger := logging.New()
 
 	// Create a new client with basic implementation info
+	notes := newNotifications(logger)
 	client := mcp.NewClient(&mcp.Implementation{
 		Name:    "agent-sdk-go",
 		Version: "0.0.0",
-	}, nil)
+	}, notes.clientOptions())
 
 	// Add tracing middleware to the client
 	client.AddSendingMiddleware(tracingMiddleware)
@@ -167,10 +172,11 @@ func NewMCPServer(ctx context.Context, transport mcp.Transport) (interfaces.MCPS
 	})
 
 	return &MCPServerImpl{
-		session:      session,
-		logger:       logger,
-		serverInfo:   serverInfo,
-		capabilities: capabilities,
+		session:       session,
+		logger:        logger,
+		serverInfo:    serverInfo,
+		capabilities:  capabilities,
+		notifications: notes,
 	}, nil
 }
 
@@ -342,11 +348,22 @@ func (s *MCPServerImpl) WatchResource(ctx context.Context, uri string) (<-chan i
 		"uri": uri,
 	})
 
+	// Servers supporting subscriptions notify us of updates
+	if s.capabilities != nil && s.capabilities.Resources != nil && s.capabilities.Resources.Subscribe && s.notifications != nil {
+		updates, err := s.subscribeResource(ctx, uri)
+		if err == nil {
+			return updates, nil
+		}
+		s.logger.Warn(ctx, "Failed to subscribe to MCP resource, polling instead", map[string]interface{}{
+			"uri":   uri,
+			"error": err.Error(),
+		})
+	}
+
 	// Create update channel
 	updates := make(chan interfaces.MCPResourceUpdate, 10)
 
-	// For now, we'll implement basic polling since the Go SDK doesn't have built-in watching
-	// In a real implementation, this would use server-sent events or websockets
+	// Other servers are polled for changes
 	go func() {
 		defer close(updates)
 
@@ -391,6 +408,60 @@ func (s *MCPServerImpl) WatchResource(ctx context.Context, uri string) (<-chan i
 	return updates, nil
 }
 
+// subscribeResource subscribes to the updates of a resource and reads the
+// resource on each update notification until ctx is done
+func (s *MCPServerImpl) subscribeResource(ctx context.Context, uri string) (<-chan interfaces.MCPResourceUpdate, error) {
+	signal, stop := s.notifications.watch(uri)
+	if err := s.session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+		stop()
+		return nil, ClassifyError(err, "Subscribe", "server", "unknown").WithMetadata("uri", uri)
+	}
+
+	updates := make(chan interfaces.MCPResourceUpdate, 10)
+	go func() {
+		defer close(updates)
+		defer stop()
+		defer func() {
+			// The session may already be closed, so failures are expected
+			_ = s.session.Unsubscribe(context.Background(), &mcp.UnsubscribeParams{URI: uri})
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signal:
+			}
+
+			update := interfaces.MCPResourceUpdate{
+				URI:       uri,
+				Type:      interfaces.MCPResourceUpdateTypeChanged,
+				Timestamp: time.Now(),
+			}
+			content, err := s.GetResource(ctx, uri)
+			if err != nil {
+				update.Type = interfaces.MCPResourceUpdateTypeError
+				update.Error = err
+			} else {
+				update.Content = content
+			}
+
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+// OnListChanged implements ChangeNotifier
+func (s *MCPServerImpl) OnListChanged(handler func(ListChange)) {
+	s.notifications.onListChanged(handler)
+}
+
 // ListPrompts lists the prompts available on the MCP server
 func (s *MCPServerImpl) ListPrompts(ctx context.Context) ([]interfaces.MCPPrompt, error) {
 	s.logger.Debug(ctx, "Listing MCP prompts", nil)
@@ -691,6 +762,14 @@ func (s *MCPServerImpl) GetCapabilities() (*interfaces.MCPServerCapabilities, er
 	return s.capabilities, nil
 }
 
+// Ping checks that the MCP server is responsive
+func (s *MCPServerImpl) Ping(ctx context.Context) error {
+	if err := s.session.Ping(ctx, nil); err != nil {
+		return ClassifyError(err, "Ping", "server", "unknown")
+	}
+	return nil
+}
+
 // Close closes the connection to the MCP server
 func (s *MCPServerImpl) Close() error {
 	s.logger.Debug(context.Background(), "Closing MCP server connection", nil)
@@ -842,10 +921,23 @@ func NewStdioServerWithRetry(ctx context.Context, config StdioServerConfig, retr
 type HTTPServerConfig struct {
 	BaseURL      string
 	Path         string
-	Token        string
+	Token        string // Static bearer token
 	ProtocolType ServerProtocolType
 	Logger       logging.Logger
 
+	// Headers are added to every request, e.g. {"X-API-Key": key}
+	Headers map[string]string
+
+	// TokenSource provides bearer tokens that change over time, such as
+	// tokens of a client credentials grant; it takes precedence over Token
+	TokenSource oauth2.TokenSource
+
+	// OAuth enables the OAuth 2.1 authorization code flow; it takes
+	// precedence over TokenSource and Token
+	OAuth *OAuthConfig
+
+	// ResourceIndicator identifies the server in OAuth requests (RFC 8707).
+	// Defaults to BaseURL.
 	ResourceIndicator string `json:"resource_indicator,omitempty"`
 }
 
@@ -865,28 +957,6 @@ const (
 	SSE            ServerProtocolType = "sse"
 )
 
-// Wrap an http.RoundTripper to add the Authorization header
-type customRoundTripper struct {
-	delegate http.RoundTripper
-	token    string
-}
-
-// RoundTrip implements the http.RoundTripper interface
-func (rt *customRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Set("Authorization", "Bearer "+rt.token)
-	return rt.delegate.RoundTrip(req)
-}
-
-// customHTTPClient creates an HTTP client that adds the Authorization header to each request
-func customHTTPClient(token string) *http.Client {
-	return &http.Client{
-		Transport: &customRoundTripper{
-			delegate: http.DefaultTransport,
-			token:    token,
-		},
-	}
-}
-
 // NewHTTPServer creates a new MCPServer that communicates over HTTP using the official SDK
 func NewHTTPServer(ctx context.Context, config HTTPServerConfig) (interfaces.MCPServer, error) {
 	return NewHTTPServerWithRetry(ctx, config, nil)
@@ -895,13 +965,8 @@ func NewHTTPServer(ctx context.Context, config HTTPServerConfig) (interfaces.MCP
 // NewHTTPServerWithRetry creates a new HTTP MCPServer with retry logic
 func NewHTTPServerWithRetry(ctx context.Context, config HTTPServerConfig, retryConfig *RetryConfig) (interfaces.MCPServer, error) {
 
-	httpClient := http.DefaultClient
-
-	// Handle token-based authentication
-	if config.Token != "" {
-		// Fallback to legacy token-based authentication
-		httpClient = customHTTPClient(config.Token)
-	}
+	// Add headers and credentials to requests
+	httpClient := newAuthHTTPClient(config)
 
 	var transport mcp.Transport
 	switch config.ProtocolType {
@@ -962,10 +1027,11 @@ func NewCustomTransportServerWithRetry(ctx context.Context, config CustomTranspo
 func newServerFromTransport(ctx context.Context, transport mcp.Transport, serverName, serverType string, retryConfig *RetryConfig, logger logging.Logger) (interfaces.MCPServer, *MCPError) {
 
 	// Create a new client with basic implementation info
+	notes := newNotifications(logger)
 	client := mcp.NewClient(&mcp.Implementation{
 		Name:    "agent-sdk-go",
 		Version: "0.0.0",
-	}, nil)
+	}, notes.clientOptions())
 
 	// Add tracing middleware to the client
 	client.AddSendingMiddleware(tracingMiddleware)
@@ -1011,10 +1077,11 @@ func newServerFromTransport(ctx context.Context, transport mcp.Transport, server
 	})
 
 	server := &MCPServerImpl{
-		session:      session,
-		logger:       logger,
-		serverInfo:   serverInfo,
-		capabilities: capabilities,
+		session:       session,
+		logger:        logger,
+		serverInfo:    serverInfo,
+		capabilities:  capabilities,
+		notifications: notes,
 	}
 
 	// Wrap with retry logic if configured
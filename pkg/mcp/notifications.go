@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// ListChange names a list that an MCP server reported as changed
+type ListChange string
+
+const (
+	ListChangeTools     ListChange = "tools"
+	ListChangeResources ListChange = "resources"
+	ListChangePrompts   ListChange = "prompts"
+)
+
+// ChangeNotifier is implemented by MCP servers that forward the change
+// notifications of the server, such as the servers created by this package
+type ChangeNotifier interface {
+	// OnListChanged registers a handler called when the server reports that
+	// its tools, resources or prompts changed. Handlers must not block.
+	OnListChanged(handler func(ListChange))
+}
+
+// notifications dispatches the notifications a server sends to a client
+// session
+type notifications struct {
+	logger logging.Logger
+
+	mu           sync.Mutex
+	listHandlers []func(ListChange)
+	watchers     map[string][]chan struct{}
+}
+
+func newNotifications(logger logging.Logger) *notifications {
+	return &notifications{
+		logger:   logger,
+		watchers: make(map[string][]chan struct{}),
+	}
+}
+
+// clientOptions returns client options routing notifications to n
+func (n *notifications) clientOptions() *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		ToolListChangedHandler: func(ctx context.Context, _ *mcp.ToolListChangedRequest) {
+			n.listChanged(ctx, ListChangeTools)
+		},
+		ResourceListChangedHandler: func(ctx context.Context, _ *mcp.ResourceListChangedRequest) {
+			n.listChanged(ctx, ListChangeResources)
+		},
+		PromptListChangedHandler: func(ctx context.Context, _ *mcp.PromptListChangedRequest) {
+			n.listChanged(ctx, ListChangePrompts)
+		},
+		ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			n.resourceUpdated(ctx, req.Params.URI)
+		},
+	}
+}
+
+// onListChanged registers a list change handler
+func (n *notifications) onListChanged(handler func(ListChange)) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.listHandlers = append(n.listHandlers, handler)
+}
+
+func (n *notifications) listChanged(ctx context.Context, change ListChange) {
+	n.logger.Debug(ctx, "MCP server list changed", map[string]interface{}{
+		"list": string(change),
+	})
+
+	n.mu.Lock()
+	handlers := append([]func(ListChange){}, n.listHandlers...)
+	n.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(change)
+	}
+}
+
+// watch returns a channel signalled when the server reports an update of
+// the resource, and a function to stop watching
+func (n *notifications) watch(uri string) (<-chan struct{}, func()) {
+	signal := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	n.watchers[uri] = append(n.watchers[uri], signal)
+	n.mu.Unlock()
+
+	return signal, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		watchers := n.watchers[uri]
+		for i, watcher := range watchers {
+			if watcher == signal {
+				n.watchers[uri] = append(watchers[:i], watchers[i+1:]...)
+				break
+			}
+		}
+		if len(n.watchers[uri]) == 0 {
+			delete(n.watchers, uri)
+		}
+	}
+}
+
+func (n *notifications) resourceUpdated(ctx context.Context, uri string) {
+	n.logger.Debug(ctx, "MCP resource updated", map[string]interface{}{
+		"uri": uri,
+	})
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, signal := range n.watchers[uri] {
+		// A pending signal already covers this update
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+	}
+}
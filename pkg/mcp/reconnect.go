@@ -0,0 +1,526 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+)
+
+// ErrServerUnavailable is returned by a ResilientServer while it is
+// reconnecting to its MCP server
+var ErrServerUnavailable = errors.New("MCP server unavailable")
+
+// ReconnectConfig configures how a ResilientServer keeps its connection alive
+type ReconnectConfig struct {
+	// InitialDelay, MaxDelay and BackoffMultiplier control the exponential
+	// backoff between reconnection attempts
+	InitialDelay      time.Duration
+	MaxDelay          time.Duration
+	BackoffMultiplier float64
+
+	// MaxAttempts limits consecutive reconnection attempts; 0 keeps trying.
+	// After giving up, the next health check starts over.
+	MaxAttempts int
+
+	// HealthCheckInterval is how often the server is pinged; 0 disables
+	// health checks, so lost connections are only noticed by failing calls
+	HealthCheckInterval time.Duration
+
+	// ToolRefreshInterval is how often the tool list is refreshed, for
+	// servers that do not send list changed notifications; 0 disables it
+	ToolRefreshInterval time.Duration
+
+	Logger logging.Logger
+}
+
+// DefaultReconnectConfig returns a sensible default reconnection configuration
+func DefaultReconnectConfig() *ReconnectConfig {
+	return &ReconnectConfig{
+		InitialDelay:        1 * time.Second,
+		MaxDelay:            30 * time.Second,
+		BackoffMultiplier:   2.0,
+		HealthCheckInterval: 30 * time.Second,
+		ToolRefreshInterval: 5 * time.Minute,
+	}
+}
+
+// ConnectFunc connects to an MCP server
+type ConnectFunc func(ctx context.Context) (interfaces.MCPServer, error)
+
+// HealthChecker is implemented by MCP servers that know whether they are
+// connected
+type HealthChecker interface {
+	// Healthy returns false while the server is unreachable
+	Healthy() bool
+}
+
+// ResilientServer keeps a connection to an MCP server alive. When the
+// connection is lost, calls fail fast with ErrServerUnavailable while it
+// reconnects with backoff in the background, so agents keep running with the
+// tools of their other servers. It also pings the server and refreshes its
+// tool list periodically, reporting changes to OnListChanged handlers.
+type ResilientServer struct {
+	name    string
+	connect ConnectFunc
+	config  ReconnectConfig
+	logger  logging.Logger
+
+	mu           sync.RWMutex
+	server       interfaces.MCPServer // nil while disconnected
+	reconnecting bool
+	tools        []interfaces.MCPTool // last listed tools
+	handlers     []func(ListChange)
+	closed       bool
+
+	ctx    context.Context // canceled on Close
+	cancel context.CancelFunc
+}
+
+// NewResilientServer connects to an MCP server and keeps the connection
+// alive. A nil config uses DefaultReconnectConfig.
+func NewResilientServer(ctx context.Context, name string, connect ConnectFunc, config *ReconnectConfig) (*ResilientServer, error) {
+	if config == nil {
+		config = DefaultReconnectConfig()
+	}
+	server, err := connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &ResilientServer{
+		name:    name,
+		connect: connect,
+		config:  *config,
+		logger:  config.Logger,
+	}
+	if r.logger == nil {
+		r.logger = logging.New()
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.setServer(server)
+
+	if config.HealthCheckInterval > 0 || config.ToolRefreshInterval > 0 {
+		go r.monitor()
+	}
+	return r, nil
+}
+
+// Healthy implements HealthChecker
+func (r *ResilientServer) Healthy() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.server != nil
+}
+
+// ToolAvailable reports whether the server is connected and, once its tools
+// have been listed, still offers the named tool
+func (r *ResilientServer) ToolAvailable(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.server == nil {
+		return false
+	}
+	if r.tools == nil {
+		return true
+	}
+	for _, tool := range r.tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Initialize implements interfaces.MCPServer
+func (r *ResilientServer) Initialize(ctx context.Context) error {
+	return r.do(func(server interfaces.MCPServer) error {
+		return server.Initialize(ctx)
+	})
+}
+
+// ListTools implements interfaces.MCPServer
+func (r *ResilientServer) ListTools(ctx context.Context) ([]interfaces.MCPTool, error) {
+	var tools []interfaces.MCPTool
+	err := r.do(func(server interfaces.MCPServer) error {
+		var err error
+		tools, err = server.ListTools(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if r.updateTools(tools) {
+		r.listChanged(ListChangeTools)
+	}
+	return tools, nil
+}
+
+// CallTool implements interfaces.MCPServer
+func (r *ResilientServer) CallTool(ctx context.Context, name string, args interface{}) (*interfaces.MCPToolResponse, error) {
+	var response *interfaces.MCPToolResponse
+	err := r.do(func(server interfaces.MCPServer) error {
+		var err error
+		response, err = server.CallTool(ctx, name, args)
+		return err
+	})
+	return response, err
+}
+
+// ListResources implements interfaces.MCPServer
+func (r *ResilientServer) ListResources(ctx context.Context) ([]interfaces.MCPResource, error) {
+	var resources []interfaces.MCPResource
+	err := r.do(func(server interfaces.MCPServer) error {
+		var err error
+		resources, err = server.ListResources(ctx)
+		return err
+	})
+	return resources, err
+}
+
+// GetResource implements interfaces.MCPServer
+func (r *ResilientServer) GetResource(ctx context.Context, uri string) (*interfaces.MCPResourceContent, error) {
+	var content *interfaces.MCPResourceContent
+	err := r.do(func(server interfaces.MCPServer) error {
+		var err error
+		content, err = server.GetResource(ctx, uri)
+		return err
+	})
+	return content, err
+}
+
+// WatchResource implements interfaces.MCPServer. Watches end when the
+// connection is lost.
+func (r *ResilientServer) WatchResource(ctx context.Context, uri string) (<-chan interfaces.MCPResourceUpdate, error) {
+	var updates <-chan interfaces.MCPResourceUpdate
+	err := r.do(func(server interfaces.MCPServer) error {
+		var err error
+		updates, err = server.WatchResource(ctx, uri)
+		return err
+	})
+	return updates, err
+}
+
+// ListPrompts implements interfaces.MCPServer
+func (r *ResilientServer) ListPrompts(ctx context.Context) ([]interfaces.MCPPrompt, error) {
+	var prompts []interfaces.MCPPrompt
+	err := r.do(func(server interfaces.MCPServer) error {
+		var err error
+		prompts, err = server.ListPrompts(ctx)
+		return err
+	})
+	return prompts, err
+}
+
+// GetPrompt implements interfaces.MCPServer
+func (r *ResilientServer) GetPrompt(ctx context.Context, name string, variables map[string]interface{}) (*interfaces.MCPPromptResult, error) {
+	var result *interfaces.MCPPromptResult
+	err := r.do(func(server interfaces.MCPServer) error {
+		var err error
+		result, err = server.GetPrompt(ctx, name, variables)
+		return err
+	})
+	return result, err
+}
+
+// CreateMessage implements interfaces.MCPServer
+func (r *ResilientServer) CreateMessage(ctx context.Context, request *interfaces.MCPSamplingRequest) (*interfaces.MCPSamplingResponse, error) {
+	var response *interfaces.MCPSamplingResponse
+	err := r.do(func(server interfaces.MCPServer) error {
+		var err error
+		response, err = server.CreateMessage(ctx, request)
+		return err
+	})
+	return response, err
+}
+
+// GetServerInfo implements interfaces.MCPServer
+func (r *ResilientServer) GetServerInfo() (*interfaces.MCPServerInfo, error) {
+	server, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return server.GetServerInfo()
+}
+
+// GetCapabilities implements interfaces.MCPServer
+func (r *ResilientServer) GetCapabilities() (*interfaces.MCPServerCapabilities, error) {
+	server, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return server.GetCapabilities()
+}
+
+// OnListChanged implements ChangeNotifier. Handlers are also called with
+// every kind of change after a reconnection, as the lists may have changed
+// meanwhile.
+func (r *ResilientServer) OnListChanged(handler func(ListChange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, handler)
+}
+
+// Close stops reconnecting and closes the connection
+func (r *ResilientServer) Close() error {
+	r.mu.Lock()
+	server := r.server
+	r.server = nil
+	r.closed = true
+	r.mu.Unlock()
+
+	r.cancel()
+	if server == nil {
+		return nil
+	}
+	return server.Close()
+}
+
+// current returns the connected server
+func (r *ResilientServer) current() (interfaces.MCPServer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.server == nil {
+		return nil, NewConnectionError(r.name, "resilient", ErrServerUnavailable)
+	}
+	return r.server, nil
+}
+
+// do runs an operation on the connected server, reconnecting if it reveals
+// that the connection is lost
+func (r *ResilientServer) do(operation func(server interfaces.MCPServer) error) error {
+	server, err := r.current()
+	if err != nil {
+		return err
+	}
+	err = operation(server)
+	if isConnectionLost(err) {
+		r.disconnected(server, err)
+	}
+	return err
+}
+
+// setServer makes server the connected server
+func (r *ResilientServer) setServer(server interfaces.MCPServer) {
+	r.mu.Lock()
+	r.server = server
+	r.reconnecting = false
+	r.tools = nil
+	r.mu.Unlock()
+
+	if notifier, ok := server.(ChangeNotifier); ok {
+		notifier.OnListChanged(func(change ListChange) {
+			if change == ListChangeTools {
+				r.mu.Lock()
+				r.tools = nil
+				r.mu.Unlock()
+			}
+			r.listChanged(change)
+		})
+	}
+}
+
+// updateTools records the listed tools and reports whether they changed
+func (r *ResilientServer) updateTools(tools []interfaces.MCPTool) bool {
+	if tools == nil {
+		tools = []interfaces.MCPTool{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := r.tools != nil && !reflect.DeepEqual(r.tools, tools)
+	r.tools = tools
+	return changed
+}
+
+// listChanged calls the OnListChanged handlers
+func (r *ResilientServer) listChanged(changes ...ListChange) {
+	r.mu.RLock()
+	handlers := append([]func(ListChange){}, r.handlers...)
+	r.mu.RUnlock()
+
+	for _, change := range changes {
+		for _, handler := range handlers {
+			handler(change)
+		}
+	}
+}
+
+// disconnected drops a lost connection and starts reconnecting
+func (r *ResilientServer) disconnected(server interfaces.MCPServer, cause error) {
+	r.mu.Lock()
+	if r.server != server || r.closed {
+		// Already replaced or closed
+		r.mu.Unlock()
+		return
+	}
+	r.server = nil
+	r.mu.Unlock()
+
+	r.logger.Warn(r.ctx, "Lost connection to MCP server, reconnecting", map[string]interface{}{
+		"server_name": r.name,
+		"error":       cause.Error(),
+	})
+	go func() { _ = server.Close() }()
+	r.startReconnecting()
+}
+
+// startReconnecting starts the reconnection loop unless it is running
+func (r *ResilientServer) startReconnecting() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reconnecting || r.closed || r.server != nil {
+		return
+	}
+	r.reconnecting = true
+	go r.reconnect()
+}
+
+// reconnect connects again with exponential backoff
+func (r *ResilientServer) reconnect() {
+	delay := r.config.InitialDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		server, err := r.connect(r.ctx)
+		if err == nil {
+			r.mu.RLock()
+			closed := r.closed
+			r.mu.RUnlock()
+			if closed {
+				_ = server.Close()
+				return
+			}
+
+			r.setServer(server)
+			r.logger.Info(r.ctx, "Reconnected to MCP server", map[string]interface{}{
+				"server_name": r.name,
+				"attempt":     attempt,
+			})
+			r.listChanged(ListChangeTools, ListChangeResources, ListChangePrompts)
+			return
+		}
+
+		if r.config.MaxAttempts > 0 && attempt >= r.config.MaxAttempts {
+			r.logger.Error(r.ctx, "Failed to reconnect to MCP server, giving up", map[string]interface{}{
+				"server_name": r.name,
+				"attempts":    attempt,
+				"error":       err.Error(),
+			})
+			r.mu.Lock()
+			r.reconnecting = false
+			r.mu.Unlock()
+			return
+		}
+
+		r.logger.Debug(r.ctx, "Failed to reconnect to MCP server, retrying", map[string]interface{}{
+			"server_name": r.name,
+			"attempt":     attempt,
+			"delay":       delay.String(),
+			"error":       err.Error(),
+		})
+		delay = time.Duration(float64(delay) * r.config.BackoffMultiplier)
+		if r.config.MaxDelay > 0 && delay > r.config.MaxDelay {
+			delay = r.config.MaxDelay
+		}
+	}
+}
+
+// monitor runs the periodic health checks and tool refreshes
+func (r *ResilientServer) monitor() {
+	var healthChecks, toolRefreshes <-chan time.Time
+	if r.config.HealthCheckInterval > 0 {
+		ticker := time.NewTicker(r.config.HealthCheckInterval)
+		defer ticker.Stop()
+		healthChecks = ticker.C
+	}
+	if r.config.ToolRefreshInterval > 0 {
+		ticker := time.NewTicker(r.config.ToolRefreshInterval)
+		defer ticker.Stop()
+		toolRefreshes = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-healthChecks:
+			r.checkHealth()
+		case <-toolRefreshes:
+			if r.Healthy() {
+				ctx, cancel := context.WithTimeout(r.ctx, r.checkTimeout())
+				_, _ = r.ListTools(ctx)
+				cancel()
+			}
+		}
+	}
+}
+
+// checkHealth pings the server, or lists its tools if it cannot be pinged,
+// and reconnects if it does not answer
+func (r *ResilientServer) checkHealth() {
+	server, err := r.current()
+	if err != nil {
+		// Resume reconnecting after giving up
+		r.startReconnecting()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.ctx, r.checkTimeout())
+	defer cancel()
+	if pinger, ok := server.(interface{ Ping(context.Context) error }); ok {
+		err = pinger.Ping(ctx)
+	} else {
+		_, err = server.ListTools(ctx)
+	}
+	if err != nil && r.ctx.Err() == nil {
+		r.disconnected(server, err)
+	}
+}
+
+// checkTimeout bounds health checks and tool refreshes
+func (r *ResilientServer) checkTimeout() time.Duration {
+	if r.config.HealthCheckInterval > 0 && r.config.HealthCheckInterval < 10*time.Second {
+		return r.config.HealthCheckInterval
+	}
+	return 10 * time.Second
+}
+
+// toolAvailable reports whether server can currently run the named tool
+func toolAvailable(server interfaces.MCPServer, name string) bool {
+	switch s := server.(type) {
+	case interface{ ToolAvailable(string) bool }:
+		return s.ToolAvailable(name)
+	case HealthChecker:
+		return s.Healthy()
+	}
+	return true
+}
+
+// isConnectionLost reports whether err means the connection to the server
+// is gone rather than that a single call failed
+func isConnectionLost(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, mcp.ErrConnectionClosed) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrClosedPipe) || errors.Is(err, net.ErrClosed) {
+		return true
+	}
+	var mcpErr *MCPError
+	if errors.As(err, &mcpErr) {
+		return mcpErr.ErrorType == MCPErrorTypeConnection || mcpErr.ErrorType == MCPErrorTypeServerCrash
+	}
+	return false
+}
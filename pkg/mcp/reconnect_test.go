@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// flakyServer is an MCP server whose connections can be dropped
+type flakyServer struct {
+	server *mcp.Server
+
+	mu       sync.Mutex
+	sessions []*mcp.ServerSession
+	connects int
+	down     bool
+}
+
+func newFlakyServer() *flakyServer {
+	f := &flakyServer{server: mcp.NewServer(&mcp.Implementation{Name: "flaky"}, nil)}
+	f.addTool("echo")
+	return f
+}
+
+func (f *flakyServer) addTool(name string) {
+	mcp.AddTool(f.server, &mcp.Tool{Name: name, Description: name},
+		func(_ context.Context, _ *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: name}}}, nil, nil
+		})
+}
+
+// connect is a ConnectFunc over in-memory transports, failing while the
+// server is down
+func (f *flakyServer) connect(ctx context.Context) (interfaces.MCPServer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connects++
+	if f.down {
+		return nil, NewConnectionError("flaky", "memory", errors.New("connection refused"))
+	}
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	session, err := f.server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	f.sessions = append(f.sessions, session)
+	return NewMCPServer(ctx, clientTransport)
+}
+
+// drop closes the open connections, keeping the server down if requested
+func (f *flakyServer) drop(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+	for _, session := range f.sessions {
+		_ = session.Close()
+	}
+	f.sessions = nil
+}
+
+func (f *flakyServer) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func (f *flakyServer) connectCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connects
+}
+
+func TestResilientServer_Reconnects(t *testing.T) {
+	ctx := context.Background()
+	flaky := newFlakyServer()
+	server, err := NewResilientServer(ctx, "flaky", flaky.connect, &ReconnectConfig{
+		InitialDelay:      10 * time.Millisecond,
+		MaxDelay:          50 * time.Millisecond,
+		BackoffMultiplier: 2,
+	})
+	require.NoError(t, err)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var changes []ListChange
+	server.OnListChanged(func(change ListChange) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, change)
+	})
+
+	_, err = server.CallTool(ctx, "echo", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, server.ToolAvailable("echo"))
+
+	// The call that finds the connection gone starts reconnecting
+	flaky.drop(true)
+	_, err = server.CallTool(ctx, "echo", map[string]interface{}{})
+	require.Error(t, err)
+	assert.False(t, server.Healthy())
+	assert.False(t, server.ToolAvailable("echo"))
+
+	// Calls fail fast meanwhile
+	_, err = server.CallTool(ctx, "echo", map[string]interface{}{})
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+
+	require.Eventually(t, func() bool { return flaky.connectCount() >= 3 }, time.Second, 5*time.Millisecond)
+	flaky.setDown(false)
+	require.Eventually(t, server.Healthy, time.Second, 5*time.Millisecond)
+
+	response, err := server.CallTool(ctx, "echo", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.False(t, response.IsError)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Subset(t, changes, []ListChange{ListChangeTools, ListChangeResources, ListChangePrompts})
+}
+
+func TestResilientServer_HealthCheck(t *testing.T) {
+	flaky := newFlakyServer()
+	server, err := NewResilientServer(context.Background(), "flaky", flaky.connect, &ReconnectConfig{
+		InitialDelay:        10 * time.Millisecond,
+		BackoffMultiplier:   1,
+		HealthCheckInterval: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer server.Close()
+
+	// Without any calls, the health check notices the lost connection
+	flaky.drop(false)
+	require.Eventually(t, func() bool { return flaky.connectCount() == 2 }, time.Second, 5*time.Millisecond)
+	require.Eventually(t, server.Healthy, time.Second, 5*time.Millisecond)
+}
+
+func TestResilientServer_GivesUp(t *testing.T) {
+	flaky := newFlakyServer()
+	server, err := NewResilientServer(context.Background(), "flaky", flaky.connect, &ReconnectConfig{
+		InitialDelay:      5 * time.Millisecond,
+		BackoffMultiplier: 1,
+		MaxAttempts:       2,
+	})
+	require.NoError(t, err)
+	defer server.Close()
+
+	flaky.drop(true)
+	_, err = server.ListTools(context.Background())
+	require.Error(t, err)
+
+	require.Eventually(t, func() bool { return flaky.connectCount() == 3 }, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 3, flaky.connectCount())
+	assert.False(t, server.Healthy())
+}
+
+func TestResilientServer_RefreshesTools(t *testing.T) {
+	flaky := newFlakyServer()
+	// Hide change notifications, so only the refresh finds the new tool
+	connect := func(ctx context.Context) (interfaces.MCPServer, error) {
+		server, err := flaky.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return struct{ interfaces.MCPServer }{server}, nil
+	}
+	server, err := NewResilientServer(context.Background(), "flaky", connect, &ReconnectConfig{
+		ToolRefreshInterval: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer server.Close()
+
+	changed := make(chan ListChange, 1)
+	server.OnListChanged(func(change ListChange) {
+		select {
+		case changed <- change:
+		default:
+		}
+	})
+
+	_, err = server.ListTools(context.Background())
+	require.NoError(t, err)
+	assert.False(t, server.ToolAvailable("search"))
+
+	flaky.addTool("search")
+	select {
+	case change := <-changed:
+		assert.Equal(t, ListChangeTools, change)
+	case <-time.After(time.Second):
+		t.Fatal("expected the refresh to report the new tool")
+	}
+	assert.True(t, server.ToolAvailable("search"))
+}
+
+func TestMCPTool_Available(t *testing.T) {
+	flaky := newFlakyServer()
+	server, err := NewResilientServer(context.Background(), "flaky", flaky.connect, &ReconnectConfig{
+		InitialDelay: time.Hour,
+	})
+	require.NoError(t, err)
+	defer server.Close()
+
+	tool := NewMCPTool("echo", "echo", nil, server).(interfaces.ToolWithAvailability)
+	assert.True(t, tool.Available())
+
+	flaky.drop(true)
+	_, _ = server.ListTools(context.Background())
+	assert.False(t, tool.Available())
+}
@@ -179,11 +179,29 @@ func (r *RetryableServer) GetCapabilities() (*interfaces.MCPServerCapabilities,
 	return r.server.GetCapabilities()
 }
 
+// Ping checks that the wrapped server is responsive (no retry, as a failed
+// ping is the answer)
+func (r *RetryableServer) Ping(ctx context.Context) error {
+	if pinger, ok := r.server.(interface{ Ping(context.Context) error }); ok {
+		return pinger.Ping(ctx)
+	}
+	_, err := r.server.ListTools(ctx)
+	return err
+}
+
 // Close closes the connection (no retry needed)
 func (r *RetryableServer) Close() error {
 	return r.server.Close()
 }
 
+// OnListChanged implements ChangeNotifier for wrapped servers that forward
+// change notifications
+func (r *RetryableServer) OnListChanged(handler func(ListChange)) {
+	if notifier, ok := r.server.(ChangeNotifier); ok {
+		notifier.OnListChanged(handler)
+	}
+}
+
 // retryOperation executes an operation with exponential backoff retry
 func (r *RetryableServer) retryOperation(ctx context.Context, operationName string, operation func() error) error {
 	delay := r.config.InitialDelay
@@ -0,0 +1,285 @@
+// Package server publishes agents and tools as an MCP server, so that MCP
+// clients such as Claude Desktop can call them over stdio or streamable HTTP.
+//
+//	srv := server.New(
+//	    server.WithAgent(myAgent),
+//	    server.WithTools(calculator, webSearch),
+//	)
+//	err := srv.ServeStdio(ctx)
+//
+// Stdout carries the protocol of stdio servers, so loggers must write to
+// stderr; call logging.SetOutput(os.Stderr) before creating agents. Claude
+// Desktop starts stdio servers from its configuration:
+//
+//	{"mcpServers": {"my-agent": {"command": "/path/to/my-agent-binary"}}}
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools"
+)
+
+// Server publishes agents and tools to MCP clients
+type Server struct {
+	server *mcp.Server
+	logger logging.Logger
+
+	name         string
+	version      string
+	instructions string
+	orgID        string
+	tools        []interfaces.Tool
+
+	// conversationID is the conversation of agent calls in sessions without
+	// an ID, such as the single session of a stdio server
+	conversationID string
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithName sets the server name reported to clients (default: the name of
+// the first agent, or "agent-sdk-go")
+func WithName(name string) Option {
+	return func(s *Server) {
+		s.name = name
+	}
+}
+
+// WithVersion sets the server version reported to clients (default: "1.0.0")
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// WithInstructions sets instructions telling clients how to use the server
+func WithInstructions(instructions string) Option {
+	return func(s *Server) {
+		s.instructions = instructions
+	}
+}
+
+// WithTools publishes tools. Their arguments are passed to Execute as JSON.
+func WithTools(tools ...interfaces.Tool) Option {
+	return func(s *Server) {
+		s.tools = append(s.tools, tools...)
+	}
+}
+
+// WithAgent publishes an agent as a tool taking a query. Calls in the same
+// MCP session share a conversation in the agent's memory. To also publish
+// the tools of the agent, add WithTools(a.GetTools()...).
+func WithAgent(a *agent.Agent) Option {
+	return func(s *Server) {
+		if s.name == "" {
+			s.name = a.GetName()
+		}
+		s.tools = append(s.tools, tools.NewAgentTool(a))
+	}
+}
+
+// WithOrgID sets the organization of calls, for agents whose memory or
+// tools are multi-tenant and that have no organization of their own
+func WithOrgID(orgID string) Option {
+	return func(s *Server) {
+		s.orgID = orgID
+	}
+}
+
+// WithLogger sets the logger of the server
+func WithLogger(logger logging.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// New creates an MCP server publishing the given agents and tools
+func New(options ...Option) *Server {
+	s := &Server{
+		logger:         logging.New(),
+		version:        "1.0.0",
+		conversationID: uuid.New().String(),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.name == "" {
+		s.name = "agent-sdk-go"
+	}
+
+	s.server = mcp.NewServer(&mcp.Implementation{
+		Name:    s.name,
+		Version: s.version,
+	}, &mcp.ServerOptions{
+		Instructions: s.instructions,
+	})
+	for _, tool := range s.tools {
+		s.addTool(tool)
+	}
+	return s
+}
+
+// AddTool publishes a tool on a running server; connected clients are
+// notified that the tool list changed
+func (s *Server) AddTool(tool interfaces.Tool) {
+	s.addTool(tool)
+}
+
+// ServeStdio serves a single client over stdin and stdout until the client
+// disconnects or ctx is cancelled. Stdout carries the protocol, so output
+// printed while serving goes to stderr; call logging.SetOutput(os.Stderr)
+// before creating agents and tools so their loggers do too.
+func (s *Server) ServeStdio(ctx context.Context) error {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+
+	return s.Serve(ctx, &mcp.IOTransport{Reader: os.Stdin, Writer: nopCloser{stdout}})
+}
+
+// nopCloser keeps stdout open when the transport closes
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// Serve serves a single client over a transport until the client
+// disconnects or ctx is cancelled
+func (s *Server) Serve(ctx context.Context, transport mcp.Transport) error {
+	return s.server.Run(ctx, transport)
+}
+
+// Handler returns an HTTP handler serving clients over the streamable HTTP
+// transport, e.g. mounted at /mcp
+func (s *Server) Handler() http.Handler {
+	return mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return s.server
+	}, nil)
+}
+
+// MCPServer returns the underlying MCP server, for adding resources or
+// prompts
+func (s *Server) MCPServer() *mcp.Server {
+	return s.server
+}
+
+// addTool registers a tool with the MCP server
+func (s *Server) addTool(tool interfaces.Tool) {
+	mcpTool := &mcp.Tool{
+		Name:        toolName(tool.Name()),
+		Description: tool.Description(),
+		InputSchema: inputSchema(tool.Parameters()),
+	}
+	if named, ok := tool.(interfaces.ToolWithDisplayName); ok {
+		mcpTool.Title = named.DisplayName()
+	}
+
+	s.server.AddTool(mcpTool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := string(req.Params.Arguments)
+		if args == "" || args == "null" {
+			args = "{}"
+		}
+
+		result, err := tool.Execute(s.callContext(ctx, req.Session), args)
+		if err != nil {
+			// Tool errors are results, so the calling model can react to them
+			s.logger.Warn(ctx, "MCP tool call failed", map[string]interface{}{
+				"tool":  tool.Name(),
+				"error": err.Error(),
+			})
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+			}, nil
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: result}},
+		}, nil
+	})
+}
+
+// callContext adds the organization and conversation of a session to the
+// context of a tool call
+func (s *Server) callContext(ctx context.Context, session *mcp.ServerSession) context.Context {
+	if s.orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, s.orgID)
+	}
+	conversationID := s.conversationID
+	if session != nil && session.ID() != "" {
+		conversationID = session.ID()
+	}
+	return memory.WithConversationID(ctx, conversationID)
+}
+
+// toolName replaces the characters MCP does not allow in tool names
+func toolName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	if len(name) > 128 {
+		name = name[:128]
+	}
+	return name
+}
+
+// inputSchema converts tool parameters to the JSON schema of MCP tool inputs
+func inputSchema(params map[string]interfaces.ParameterSpec) map[string]interface{} {
+	properties := make(map[string]interface{}, len(params))
+	required := []string{}
+	for name, spec := range params {
+		properties[name] = parameterSchema(spec)
+		if spec.Required {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// parameterSchema converts a parameter to its JSON schema
+func parameterSchema(spec interfaces.ParameterSpec) map[string]interface{} {
+	schema := map[string]interface{}{}
+	if spec.Type != nil {
+		schema["type"] = spec.Type
+	}
+	if spec.Description != "" {
+		schema["description"] = spec.Description
+	}
+	if spec.Default != nil {
+		schema["default"] = spec.Default
+	}
+	if spec.Enum != nil {
+		schema["enum"] = spec.Enum
+	}
+	if spec.Items != nil {
+		schema["items"] = parameterSchema(*spec.Items)
+	}
+	return schema
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+type greetTool struct{}
+
+func (greetTool) Name() string        { return "greet" }
+func (greetTool) Description() string { return "Greets someone" }
+func (greetTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"name":  {Type: "string", Description: "Who to greet", Required: true},
+		"langs": {Type: "array", Items: &interfaces.ParameterSpec{Type: "string", Enum: []interface{}{"en", "fr"}}},
+	}
+}
+func (t greetTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (greetTool) Execute(ctx context.Context, args string) (string, error) {
+	if strings.Contains(args, "nobody") {
+		return "", errors.New("nobody to greet")
+	}
+	return "Hello " + args, nil
+}
+
+func connect(t *testing.T, s *Server) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	go func() { _ = s.Serve(ctx, serverTransport) }()
+
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func callText(t *testing.T, session *mcp.ClientSession, name string, args map[string]interface{}) (string, bool) {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("CallTool(%s) failed: %v", name, err)
+	}
+	if len(result.Content) != 1 {
+		t.Fatalf("Expected one content, got %d", len(result.Content))
+	}
+	return result.Content[0].(*mcp.TextContent).Text, result.IsError
+}
+
+func TestServer_Tools(t *testing.T) {
+	session := connect(t, New(WithName("tools"), WithTools(greetTool{})))
+
+	if name := session.InitializeResult().ServerInfo.Name; name != "tools" {
+		t.Errorf("Expected server name tools, got %q", name)
+	}
+
+	list, err := session.ListTools(context.Background(), nil)
+	if err != nil || len(list.Tools) != 1 {
+		t.Fatalf("ListTools() = %+v, %v", list, err)
+	}
+	schema := list.Tools[0].InputSchema.(map[string]interface{})
+	required, _ := schema["required"].([]interface{})
+	if list.Tools[0].Name != "greet" || len(required) != 1 || required[0] != "name" {
+		t.Errorf("Unexpected tool %+v", list.Tools[0])
+	}
+	langs := schema["properties"].(map[string]interface{})["langs"].(map[string]interface{})
+	if items := langs["items"].(map[string]interface{}); items["type"] != "string" || len(items["enum"].([]interface{})) != 2 {
+		t.Errorf("Expected the items schema, got %v", langs)
+	}
+
+	if text, isError := callText(t, session, "greet", map[string]interface{}{"name": "Ada"}); isError || text != `Hello {"name":"Ada"}` {
+		t.Errorf("Unexpected result %q (error %v)", text, isError)
+	}
+	if text, isError := callText(t, session, "greet", map[string]interface{}{"name": "nobody"}); !isError || text != "nobody to greet" {
+		t.Errorf("Expected a tool error result, got %q (error %v)", text, isError)
+	}
+}
+
+func TestServer_Agent(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.Any()).Respond("Paris")
+	conversations := memory.NewConversationBuffer()
+
+	researcher, err := agent.NewAgent(
+		agent.WithLLM(llm),
+		agent.WithMemory(conversations),
+		agent.WithName("Research Assistant"),
+		agent.WithDescription("Answers research questions"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	srv := New(WithAgent(researcher), WithOrgID("org-1"))
+	session := connect(t, srv)
+	if name := session.InitializeResult().ServerInfo.Name; name != "Research Assistant" {
+		t.Errorf("Expected the agent name as server name, got %q", name)
+	}
+
+	list, err := session.ListTools(context.Background(), nil)
+	if err != nil || len(list.Tools) != 1 {
+		t.Fatalf("ListTools() = %+v, %v", list, err)
+	}
+	tool := list.Tools[0]
+	if tool.Name != "Research_Assistant_agent" || tool.Description != "Answers research questions" || tool.Title != "Research Assistant Agent" {
+		t.Errorf("Unexpected agent tool %+v", tool)
+	}
+
+	for _, query := range []string{"What is the capital of France?", "And of Italy?"} {
+		if text, isError := callText(t, session, tool.Name, map[string]interface{}{"query": query}); isError || text != "Paris" {
+			t.Errorf("Unexpected answer %q (error %v)", text, isError)
+		}
+	}
+
+	// Calls of the session share a conversation of the organization
+	ctx := srv.callContext(context.Background(), nil)
+	messages, err := conversations.GetMessages(ctx)
+	if err != nil || len(messages) != 4 {
+		t.Errorf("Expected both exchanges in one conversation, got %d messages (%v)", len(messages), err)
+	}
+}
+
+func TestServer_StreamableHTTP(t *testing.T) {
+	httpServer := httptest.NewServer(New(WithTools(greetTool{})).Handler())
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil).
+		Connect(ctx, &mcp.StreamableClientTransport{Endpoint: httpServer.URL}, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer session.Close()
+
+	if text, isError := callText(t, session, "greet", map[string]interface{}{"name": "Grace"}); isError || text != `Hello {"name":"Grace"}` {
+		t.Errorf("Unexpected result %q (error %v)", text, isError)
+	}
+}
@@ -50,6 +50,11 @@ func (t *MCPTool) Internal() bool {
 	return false
 }
 
+// Available implements interfaces.ToolWithAvailability.Available
+func (t *MCPTool) Available() bool {
+	return toolAvailable(t.server, t.name)
+}
+
 // Run executes the tool with the given input
 func (t *MCPTool) Run(ctx context.Context, input string) (string, error) {
 	// Parse the input as JSON to get the arguments
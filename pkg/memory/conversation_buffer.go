@@ -53,16 +53,40 @@ func (c *ConversationBuffer) AddMessage(ctx context.Context, message interfaces.
 	}
 
 	// Add message to buffer
-	c.messages[conversationID] = append(c.messages[conversationID], message)
+	c.messages[conversationID] = append(c.messages[conversationID], withMessageID(message))
+	c.trim(conversationID)
 
-	// Trim buffer if it exceeds max size
-	if c.maxSize > 0 && len(c.messages[conversationID]) > c.maxSize {
-		c.messages[conversationID] = c.messages[conversationID][len(c.messages[conversationID])-c.maxSize:]
+	return nil
+}
+
+// CreateConversation implements interfaces.ConversationCreator
+func (c *ConversationBuffer) CreateConversation(ctx context.Context, conversationID string, messages []interfaces.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, err := getConversationID(WithConversationID(ctx, conversationID))
+	if err != nil {
+		return err
+	}
+	if len(c.messages[key]) > 0 {
+		return fmt.Errorf("%w: %s", interfaces.ErrConversationExists, conversationID)
 	}
 
+	for _, message := range messages {
+		c.messages[key] = append(c.messages[key], withMessageID(message))
+	}
+	c.trim(key)
+
 	return nil
 }
 
+// trim drops the oldest messages of a conversation beyond the max size
+func (c *ConversationBuffer) trim(conversationID string) {
+	if c.maxSize > 0 && len(c.messages[conversationID]) > c.maxSize {
+		c.messages[conversationID] = c.messages[conversationID][len(c.messages[conversationID])-c.maxSize:]
+	}
+}
+
 // GetMessages retrieves messages from the buffer
 func (c *ConversationBuffer) GetMessages(ctx context.Context, options ...interfaces.GetMessagesOption) ([]interfaces.Message, error) {
 	c.mu.RLock()
@@ -139,8 +163,9 @@ func getConversationID(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("conversation ID not found in context")
 	}
 
-	// Combine organization ID and conversation ID
-	return fmt.Sprintf("%s:%s", orgID, conversationID), nil
+	// Combine organization ID and conversation ID. The organization ID is
+	// escaped so that no conversation ID can reach another organization.
+	return multitenancy.EscapeKeySegment(orgID) + ":" + conversationID, nil
 }
 
 // GetAllConversations returns all conversation IDs
@@ -155,7 +180,7 @@ func (c *ConversationBuffer) GetAllConversations(ctx context.Context) ([]string,
 	}
 
 	var conversations []string
-	orgPrefix := orgID + ":"
+	orgPrefix := multitenancy.EscapeKeySegment(orgID) + ":"
 
 	for conversationID := range c.messages {
 		// Only return conversations for the current organization
@@ -181,7 +206,7 @@ func (c *ConversationBuffer) GetConversationMessages(ctx context.Context, conver
 	}
 
 	// Build full conversation ID with org prefix
-	fullConversationID := fmt.Sprintf("%s:%s", orgID, conversationID)
+	fullConversationID := multitenancy.EscapeKeySegment(orgID) + ":" + conversationID
 
 	messages, ok := c.messages[fullConversationID]
 	if !ok {
@@ -202,7 +227,7 @@ func (c *ConversationBuffer) GetMemoryStatistics(ctx context.Context) (totalConv
 		return 0, 0, fmt.Errorf("organization ID not found in context: %w", err)
 	}
 
-	orgPrefix := orgID + ":"
+	orgPrefix := multitenancy.EscapeKeySegment(orgID) + ":"
 	totalConversations = 0
 	totalMessages = 0
 
@@ -228,7 +253,7 @@ func (c *ConversationBuffer) GetAllConversationsAcrossOrgs() (map[string][]strin
 		// Extract orgID and conversationID from the full ID (format: "orgID:conversationID")
 		parts := strings.SplitN(fullConversationID, ":", 2)
 		if len(parts) == 2 {
-			orgID := parts[0]
+			orgID := multitenancy.UnescapeKeySegment(parts[0])
 			conversationID := parts[1]
 			orgConversations[orgID] = append(orgConversations[orgID], conversationID)
 		}
@@ -247,7 +272,7 @@ func (c *ConversationBuffer) GetConversationMessagesAcrossOrgs(conversationID st
 		// Extract orgID and conversationID from the full ID (format: "orgID:conversationID")
 		parts := strings.SplitN(fullConversationID, ":", 2)
 		if len(parts) == 2 {
-			orgID := parts[0]
+			orgID := multitenancy.UnescapeKeySegment(parts[0])
 			convID := parts[1]
 
 			// Check if this is the conversation we're looking for
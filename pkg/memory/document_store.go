@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// MessageSerializer converts messages to and from the bytes stored by
+// document-store memory backends
+type MessageSerializer interface {
+	// Marshal encodes a message
+	Marshal(message interfaces.Message) ([]byte, error)
+
+	// Unmarshal decodes a message
+	Unmarshal(data []byte) (interfaces.Message, error)
+}
+
+// JSONSerializer is the default MessageSerializer and stores messages as JSON
+type JSONSerializer struct{}
+
+// Marshal implements MessageSerializer.Marshal
+func (JSONSerializer) Marshal(message interfaces.Message) ([]byte, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return data, nil
+}
+
+// Unmarshal implements MessageSerializer.Unmarshal
+func (JSONSerializer) Unmarshal(data []byte) (interfaces.Message, error) {
+	var message interfaces.Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return interfaces.Message{}, fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+	return message, nil
+}
+
+// filterMessages applies the role filter and limit of GetMessages options
+func filterMessages(messages []interfaces.Message, opts *interfaces.GetMessagesOptions) []interfaces.Message {
+	if len(opts.Roles) > 0 {
+		var filtered []interfaces.Message
+		for _, msg := range messages {
+			for _, role := range opts.Roles {
+				if msg.Role == interfaces.MessageRole(role) {
+					filtered = append(filtered, msg)
+					break
+				}
+			}
+		}
+		messages = filtered
+	}
+
+	if opts.Limit > 0 && opts.Limit < len(messages) {
+		messages = messages[len(messages)-opts.Limit:]
+	}
+
+	return messages
+}
+
+// conversationScope returns the organization and conversation IDs from ctx.
+// Like the Redis backend, a missing organization falls back to
+// multitenancy.DefaultOrgID.
+func conversationScope(ctx context.Context) (orgID, conversationID string, err error) {
+	conversationID, ok := GetConversationID(ctx)
+	if !ok || conversationID == "" {
+		return "", "", fmt.Errorf("conversation ID not found in context")
+	}
+
+	return multitenancy.OrgIDOrDefault(ctx), conversationID, nil
+}
@@ -0,0 +1,295 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// fakeDynamoDB is an in-memory DynamoDBAPI keyed by partition key, with
+// items kept in sort key order
+type fakeDynamoDB struct {
+	mu    sync.Mutex
+	items map[string][]map[string]types.AttributeValue
+}
+
+func newFakeDynamoDB() *fakeDynamoDB {
+	return &fakeDynamoDB{items: make(map[string][]map[string]types.AttributeValue)}
+}
+
+func (f *fakeDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pk := dynamoString(params.Item, dynamoAttrPartitionKey)
+	f.items[pk] = append(f.items[pk], params.Item)
+	sort.Slice(f.items[pk], func(i, j int) bool {
+		return dynamoString(f.items[pk][i], dynamoAttrSortKey) < dynamoString(f.items[pk][j], dynamoAttrSortKey)
+	})
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pk := params.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS).Value
+	items := f.items[pk]
+
+	// Return one item per page to exercise pagination
+	start := 0
+	if params.ExclusiveStartKey != nil {
+		last := dynamoString(params.ExclusiveStartKey, dynamoAttrSortKey)
+		for start < len(items) && dynamoString(items[start], dynamoAttrSortKey) <= last {
+			start++
+		}
+	}
+	if start >= len(items) {
+		return &dynamodb.QueryOutput{}, nil
+	}
+	output := &dynamodb.QueryOutput{Items: items[start : start+1]}
+	if start+1 < len(items) {
+		output.LastEvaluatedKey = items[start]
+	}
+	return output, nil
+}
+
+func (f *fakeDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var orgID string
+	if v, ok := params.ExpressionAttributeValues[":org"].(*types.AttributeValueMemberS); ok {
+		orgID = v.Value
+	}
+	output := &dynamodb.ScanOutput{}
+	for _, items := range f.items {
+		for _, item := range items {
+			if orgID == "" || dynamoString(item, dynamoAttrOrgID) == orgID {
+				output.Items = append(output.Items, item)
+			}
+		}
+	}
+	return output, nil
+}
+
+func (f *fakeDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, requests := range params.RequestItems {
+		for _, request := range requests {
+			pk := dynamoString(request.DeleteRequest.Key, dynamoAttrPartitionKey)
+			sk := dynamoString(request.DeleteRequest.Key, dynamoAttrSortKey)
+			items := f.items[pk][:0]
+			for _, item := range f.items[pk] {
+				if dynamoString(item, dynamoAttrSortKey) != sk {
+					items = append(items, item)
+				}
+			}
+			f.items[pk] = items
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+// fakeMongoCollection is an in-memory MongoCollection that matches plain
+// equality filters and ignores operators
+type fakeMongoCollection struct {
+	mu   sync.Mutex
+	docs []mongoMessage
+}
+
+func (f *fakeMongoCollection) matching(filter interface{}) []mongoMessage {
+	var result []mongoMessage
+	for _, doc := range f.docs {
+		match := true
+		for key, value := range filter.(bson.M) {
+			switch key {
+			case "org_id":
+				match = match && doc.OrgID == value
+			case "conversation_id":
+				match = match && doc.ConversationID == value
+			}
+		}
+		if match {
+			result = append(result, doc)
+		}
+	}
+	return result
+}
+
+func (f *fakeMongoCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.docs = append(f.docs, document.(mongoMessage))
+	return &mongo.InsertOneResult{}, nil
+}
+
+func (f *fakeMongoCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var docs []interface{}
+	for _, doc := range f.matching(filter) {
+		docs = append(docs, doc)
+	}
+	return mongo.NewCursorFromDocuments(docs, nil, nil)
+}
+
+func (f *fakeMongoCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	deleted := f.matching(filter)
+	var kept []mongoMessage
+	for _, doc := range f.docs {
+		remove := false
+		for _, d := range deleted {
+			if d.OrgID == doc.OrgID && d.ConversationID == doc.ConversationID {
+				remove = true
+				break
+			}
+		}
+		if !remove {
+			kept = append(kept, doc)
+		}
+	}
+	f.docs = kept
+	return &mongo.DeleteResult{DeletedCount: int64(len(deleted))}, nil
+}
+
+func (f *fakeMongoCollection) Distinct(ctx context.Context, fieldName string, filter interface{}, opts ...*options.DistinctOptions) ([]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	seen := make(map[string]bool)
+	var values []interface{}
+	for _, doc := range f.matching(filter) {
+		value := doc.OrgID
+		if fieldName == "conversation_id" {
+			value = doc.ConversationID
+		}
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func (f *fakeMongoCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return int64(len(f.matching(filter))), nil
+}
+
+// upperSerializer is a custom serializer used to check that serialization is pluggable
+type upperSerializer struct{ JSONSerializer }
+
+func (s upperSerializer) Unmarshal(data []byte) (interfaces.Message, error) {
+	message, err := s.JSONSerializer.Unmarshal(data)
+	message.Content = strings.ToUpper(message.Content)
+	return message, err
+}
+
+func TestDocumentStoreMemory(t *testing.T) {
+	backends := map[string]func(serializer MessageSerializer) interfaces.ConversationMemory{
+		"dynamodb": func(serializer MessageSerializer) interfaces.ConversationMemory {
+			return NewDynamoDBMemory(newFakeDynamoDB(), "memory", WithDynamoDBSerializer(serializer))
+		},
+		"mongodb": func(serializer MessageSerializer) interfaces.ConversationMemory {
+			return NewMongoDBMemory(&fakeMongoCollection{}, WithMongoDBSerializer(serializer))
+		},
+	}
+
+	for name, newMemory := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := multitenancy.WithOrgID(context.Background(), "org1")
+			conv1 := WithConversationID(ctx, "conv1")
+			conv2 := WithConversationID(ctx, "conv2")
+			otherOrg := WithConversationID(multitenancy.WithOrgID(context.Background(), "org2"), "conv1")
+
+			mem := newMemory(JSONSerializer{})
+
+			require.NoError(t, mem.AddMessage(conv1, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "hello"}))
+			require.NoError(t, mem.AddMessage(conv1, interfaces.Message{Role: interfaces.MessageRoleAssistant, Content: "hi there"}))
+			require.NoError(t, mem.AddMessage(conv1, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "how are you?"}))
+			require.NoError(t, mem.AddMessage(conv2, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "other"}))
+			require.NoError(t, mem.AddMessage(otherOrg, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "org2"}))
+
+			messages, err := mem.GetMessages(conv1)
+			require.NoError(t, err)
+			require.Len(t, messages, 3)
+			assert.Equal(t, "hello", messages[0].Content)
+			assert.Equal(t, "how are you?", messages[2].Content)
+
+			messages, err = mem.GetMessages(conv1, interfaces.WithRoles(string(interfaces.MessageRoleUser)), interfaces.WithLimit(1))
+			require.NoError(t, err)
+			require.Len(t, messages, 1)
+			assert.Equal(t, "how are you?", messages[0].Content)
+
+			// Organizations are isolated
+			messages, err = mem.GetMessages(otherOrg)
+			require.NoError(t, err)
+			require.Len(t, messages, 1)
+			assert.Equal(t, "org2", messages[0].Content)
+
+			conversations, err := mem.GetAllConversations(ctx)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"conv1", "conv2"}, conversations)
+
+			totalConversations, totalMessages, err := mem.GetMemoryStatistics(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 2, totalConversations)
+			assert.Equal(t, 4, totalMessages)
+
+			require.NoError(t, mem.Clear(conv1))
+			messages, err = mem.GetMessages(conv1)
+			require.NoError(t, err)
+			assert.Empty(t, messages)
+
+			messages, err = mem.GetMessages(otherOrg)
+			require.NoError(t, err)
+			assert.Len(t, messages, 1)
+
+			_, err = mem.GetMessages(ctx)
+			assert.Error(t, err, "conversation ID is required")
+		})
+
+		t.Run(name+"/serializer", func(t *testing.T) {
+			ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+			mem := newMemory(upperSerializer{})
+
+			require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "hello"}))
+			messages, err := mem.GetMessages(ctx)
+			require.NoError(t, err)
+			require.Len(t, messages, 1)
+			assert.Equal(t, "HELLO", messages[0].Content)
+		})
+	}
+}
+
+func TestDynamoDBMemoryExpiredItems(t *testing.T) {
+	client := newFakeDynamoDB()
+	mem := NewDynamoDBMemory(client, "memory", WithDynamoDBTTL(time.Hour))
+	ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "hello"}))
+
+	// Expire the stored item; DynamoDB may still return it until its TTL sweep runs
+	for _, item := range client.items[dynamoPartitionKey("org1", "conv1")] {
+		item[dynamoAttrExpiresAt] = &types.AttributeValueMemberN{Value: "1"}
+	}
+
+	messages, err := mem.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
@@ -0,0 +1,470 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// DynamoDB item attribute names. The table must use "pk" (string) as the
+// partition key and "sk" (string) as the sort key. Enable DynamoDB TTL on
+// "expires_at" to have expired messages deleted automatically.
+const (
+	dynamoAttrPartitionKey   = "pk"
+	dynamoAttrSortKey        = "sk"
+	dynamoAttrOrgID          = "org_id"
+	dynamoAttrConversationID = "conversation_id"
+	dynamoAttrData           = "data"
+	dynamoAttrExpiresAt      = "expires_at"
+)
+
+// dynamoBatchSize is the maximum number of requests in a BatchWriteItem call
+const dynamoBatchSize = 25
+
+// DynamoDBAPI is the subset of the DynamoDB client used by DynamoDBMemory.
+// *dynamodb.Client satisfies it.
+type DynamoDBAPI interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+}
+
+// DynamoDBMemory implements a DynamoDB-backed memory store. Each message is
+// stored as one item partitioned by organization and conversation.
+type DynamoDBMemory struct {
+	client         DynamoDBAPI
+	tableName      string
+	ttl            time.Duration
+	serializer     MessageSerializer
+	maxMessageSize int
+}
+
+// DynamoDBOption represents an option for configuring the DynamoDB memory
+type DynamoDBOption func(*DynamoDBMemory)
+
+// WithDynamoDBTTL sets how long messages are kept. Zero keeps them forever.
+func WithDynamoDBTTL(ttl time.Duration) DynamoDBOption {
+	return func(d *DynamoDBMemory) {
+		d.ttl = ttl
+	}
+}
+
+// WithDynamoDBSerializer sets the serializer used to encode messages
+func WithDynamoDBSerializer(serializer MessageSerializer) DynamoDBOption {
+	return func(d *DynamoDBMemory) {
+		d.serializer = serializer
+	}
+}
+
+// WithDynamoDBMaxMessageSize sets the maximum size of an encoded message
+func WithDynamoDBMaxMessageSize(size int) DynamoDBOption {
+	return func(d *DynamoDBMemory) {
+		d.maxMessageSize = size
+	}
+}
+
+// NewDynamoDBMemory creates a new DynamoDB-backed memory using tableName
+func NewDynamoDBMemory(client DynamoDBAPI, tableName string, options ...DynamoDBOption) *DynamoDBMemory {
+	d := &DynamoDBMemory{
+		client:         client,
+		tableName:      tableName,
+		ttl:            24 * time.Hour,
+		serializer:     JSONSerializer{},
+		maxMessageSize: 400 * 1024, // DynamoDB item size limit
+	}
+
+	for _, option := range options {
+		option(d)
+	}
+
+	return d
+}
+
+// AddMessage adds a message to the memory
+func (d *DynamoDBMemory) AddMessage(ctx context.Context, message interfaces.Message) error {
+	orgID, conversationID, err := conversationScope(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := d.serializer.Marshal(withMessageID(message))
+	if err != nil {
+		return err
+	}
+	if d.maxMessageSize > 0 && len(data) > d.maxMessageSize {
+		return fmt.Errorf("message size exceeds maximum allowed size of %d bytes", d.maxMessageSize)
+	}
+
+	now := time.Now()
+	item := map[string]types.AttributeValue{
+		dynamoAttrPartitionKey:   &types.AttributeValueMemberS{Value: dynamoPartitionKey(orgID, conversationID)},
+		dynamoAttrSortKey:        &types.AttributeValueMemberS{Value: fmt.Sprintf("%020d#%s", now.UnixNano(), uuid.New().String()[:8])},
+		dynamoAttrOrgID:          &types.AttributeValueMemberS{Value: orgID},
+		dynamoAttrConversationID: &types.AttributeValueMemberS{Value: conversationID},
+		dynamoAttrData:           &types.AttributeValueMemberB{Value: data},
+	}
+	if d.ttl > 0 {
+		item[dynamoAttrExpiresAt] = &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(d.ttl).Unix(), 10)}
+	}
+
+	_, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(d.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add message to DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// GetMessages retrieves messages from the memory
+func (d *DynamoDBMemory) GetMessages(ctx context.Context, options ...interfaces.GetMessagesOption) ([]interfaces.Message, error) {
+	orgID, conversationID, err := conversationScope(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation ID: %w", err)
+	}
+
+	opts := &interfaces.GetMessagesOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	items, err := d.queryConversation(ctx, orgID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := d.decodeItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterMessages(messages, opts), nil
+}
+
+// Clear clears the memory for a conversation
+func (d *DynamoDBMemory) Clear(ctx context.Context) error {
+	orgID, conversationID, err := conversationScope(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation ID: %w", err)
+	}
+
+	items, err := d.queryConversation(ctx, orgID, conversationID)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(items); start += dynamoBatchSize {
+		end := start + dynamoBatchSize
+		if end > len(items) {
+			end = len(items)
+		}
+
+		requests := make([]types.WriteRequest, 0, end-start)
+		for _, item := range items[start:end] {
+			requests = append(requests, types.WriteRequest{
+				DeleteRequest: &types.DeleteRequest{
+					Key: map[string]types.AttributeValue{
+						dynamoAttrPartitionKey: item[dynamoAttrPartitionKey],
+						dynamoAttrSortKey:      item[dynamoAttrSortKey],
+					},
+				},
+			})
+		}
+
+		if err := d.batchWrite(ctx, requests); err != nil {
+			return fmt.Errorf("failed to clear memory in DynamoDB: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetAllConversations returns all conversation IDs for the current org.
+// This scans the table and is intended for administrative use.
+func (d *DynamoDBMemory) GetAllConversations(ctx context.Context) ([]string, error) {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("organization ID not found in context: %w", err)
+	}
+
+	counts, err := d.scanConversations(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	conversations := make([]string, 0, len(counts[orgID]))
+	for conversationID := range counts[orgID] {
+		conversations = append(conversations, conversationID)
+	}
+
+	return conversations, nil
+}
+
+// GetConversationMessages gets all messages for a specific conversation in current org
+func (d *DynamoDBMemory) GetConversationMessages(ctx context.Context, conversationID string) ([]interfaces.Message, error) {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("organization ID not found in context: %w", err)
+	}
+
+	items, err := d.queryConversation(ctx, orgID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.decodeItems(items)
+}
+
+// GetMemoryStatistics returns basic memory statistics for current org
+func (d *DynamoDBMemory) GetMemoryStatistics(ctx context.Context) (totalConversations, totalMessages int, err error) {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("organization ID not found in context: %w", err)
+	}
+
+	counts, err := d.scanConversations(ctx, orgID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, count := range counts[orgID] {
+		totalMessages += count
+	}
+
+	return len(counts[orgID]), totalMessages, nil
+}
+
+// GetAllConversationsAcrossOrgs returns all conversation IDs from all organizations
+func (d *DynamoDBMemory) GetAllConversationsAcrossOrgs() (map[string][]string, error) {
+	counts, err := d.scanConversations(context.Background(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	orgConversations := make(map[string][]string, len(counts))
+	for orgID, conversations := range counts {
+		for conversationID := range conversations {
+			orgConversations[orgID] = append(orgConversations[orgID], conversationID)
+		}
+	}
+
+	return orgConversations, nil
+}
+
+// GetConversationMessagesAcrossOrgs finds conversation in any org and returns messages
+func (d *DynamoDBMemory) GetConversationMessagesAcrossOrgs(conversationID string) ([]interfaces.Message, string, error) {
+	ctx := context.Background()
+
+	counts, err := d.scanConversations(ctx, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	for orgID, conversations := range counts {
+		if _, ok := conversations[conversationID]; !ok {
+			continue
+		}
+
+		items, err := d.queryConversation(ctx, orgID, conversationID)
+		if err != nil {
+			return nil, "", err
+		}
+		messages, err := d.decodeItems(items)
+		if err != nil {
+			return nil, "", err
+		}
+		return messages, orgID, nil
+	}
+
+	return []interfaces.Message{}, "", nil // Conversation not found
+}
+
+// GetMemoryStatisticsAcrossOrgs returns memory statistics across all organizations
+func (d *DynamoDBMemory) GetMemoryStatisticsAcrossOrgs() (totalConversations, totalMessages int, err error) {
+	counts, err := d.scanConversations(context.Background(), "")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, conversations := range counts {
+		totalConversations += len(conversations)
+		for _, count := range conversations {
+			totalMessages += count
+		}
+	}
+
+	return totalConversations, totalMessages, nil
+}
+
+// Close is a no-op; the DynamoDB client holds no connections that need closing
+func (d *DynamoDBMemory) Close() error {
+	return nil
+}
+
+// queryConversation returns the unexpired items of a conversation in sort key order
+func (d *DynamoDBMemory) queryConversation(ctx context.Context, orgID, conversationID string) ([]map[string]types.AttributeValue, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(d.tableName),
+		KeyConditionExpression: aws.String("#pk = :pk"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk": dynamoAttrPartitionKey,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: dynamoPartitionKey(orgID, conversationID)},
+		},
+		ScanIndexForward: aws.Bool(true),
+	}
+
+	var items []map[string]types.AttributeValue
+	now := time.Now().Unix()
+	for {
+		output, err := d.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get messages from DynamoDB: %w", err)
+		}
+
+		for _, item := range output.Items {
+			if !dynamoItemExpired(item, now) {
+				items = append(items, item)
+			}
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// scanConversations counts the unexpired messages of every conversation,
+// grouped by organization. An empty orgID scans all organizations.
+func (d *DynamoDBMemory) scanConversations(ctx context.Context, orgID string) (map[string]map[string]int, error) {
+	input := &dynamodb.ScanInput{
+		TableName:            aws.String(d.tableName),
+		ProjectionExpression: aws.String("#org, #conv, #exp"),
+		ExpressionAttributeNames: map[string]string{
+			"#org":  dynamoAttrOrgID,
+			"#conv": dynamoAttrConversationID,
+			"#exp":  dynamoAttrExpiresAt,
+		},
+	}
+	if orgID != "" {
+		input.FilterExpression = aws.String("#org = :org")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":org": &types.AttributeValueMemberS{Value: orgID},
+		}
+	}
+
+	counts := make(map[string]map[string]int)
+	now := time.Now().Unix()
+	for {
+		output, err := d.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan conversations: %w", err)
+		}
+
+		for _, item := range output.Items {
+			if dynamoItemExpired(item, now) {
+				continue
+			}
+			org := dynamoString(item, dynamoAttrOrgID)
+			conversationID := dynamoString(item, dynamoAttrConversationID)
+			if org == "" || conversationID == "" {
+				continue
+			}
+			if counts[org] == nil {
+				counts[org] = make(map[string]int)
+			}
+			counts[org][conversationID]++
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return counts, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// batchWrite sends write requests, retrying unprocessed items with backoff
+func (d *DynamoDBMemory) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+	const maxAttempts = 5
+	backoff := 50 * time.Millisecond
+
+	for attempt := 0; len(requests) > 0; attempt++ {
+		if attempt >= maxAttempts {
+			return fmt.Errorf("%d items were not processed after %d attempts", len(requests), maxAttempts)
+		}
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		output, err := d.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{d.tableName: requests},
+		})
+		if err != nil {
+			return err
+		}
+		requests = output.UnprocessedItems[d.tableName]
+	}
+
+	return nil
+}
+
+// decodeItems deserializes the message stored in each item
+func (d *DynamoDBMemory) decodeItems(items []map[string]types.AttributeValue) ([]interfaces.Message, error) {
+	messages := make([]interfaces.Message, 0, len(items))
+	for _, item := range items {
+		data, ok := item[dynamoAttrData].(*types.AttributeValueMemberB)
+		if !ok {
+			return nil, fmt.Errorf("item %s has no message data", dynamoString(item, dynamoAttrSortKey))
+		}
+		message, err := d.serializer.Unmarshal(data.Value)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// dynamoPartitionKey returns the partition key of a conversation. The
+// organization ID is escaped, so that no conversation ID can reach the
+// partition of another organization.
+func dynamoPartitionKey(orgID, conversationID string) string {
+	return multitenancy.EscapeKeySegment(orgID) + "#" + conversationID
+}
+
+func dynamoString(item map[string]types.AttributeValue, name string) string {
+	if v, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return v.Value
+	}
+	return ""
+}
+
+// dynamoItemExpired reports whether an item is past its TTL. DynamoDB deletes
+// expired items lazily, so reads filter them out explicitly.
+func dynamoItemExpired(item map[string]types.AttributeValue, now int64) bool {
+	v, ok := item[dynamoAttrExpiresAt].(*types.AttributeValueMemberN)
+	if !ok {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(v.Value, 10, 64)
+	return err == nil && expiresAt <= now
+}
@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ErrMessageIndexOutOfRange is returned when branching a conversation at an
+// index past its messages
+var ErrMessageIndexOutOfRange = errors.New("message index out of range")
+
+// ErrMessageNotFound is returned when branching a conversation before a
+// message ID it does not have
+var ErrMessageNotFound = errors.New("message not found")
+
+// MessageIDKey is the metadata key of the ID memories assign to each message
+// they store. Imported and branched messages keep their IDs.
+const MessageIDKey = "message_id"
+
+// MessageID returns the ID of a stored message, or an empty string for
+// messages stored before memories assigned IDs
+func MessageID(message interfaces.Message) string {
+	id, _ := message.Metadata[MessageIDKey].(string)
+	return id
+}
+
+// withMessageID returns message with a new ID unless it already has one. The
+// metadata is copied so the caller's message is left unchanged.
+func withMessageID(message interfaces.Message) interfaces.Message {
+	if MessageID(message) != "" {
+		return message
+	}
+
+	metadata := make(map[string]interface{}, len(message.Metadata)+1)
+	for key, value := range message.Metadata {
+		metadata[key] = value
+	}
+	metadata[MessageIDKey] = uuid.New().String()
+	message.Metadata = metadata
+	return message
+}
+
+// createMu serializes the creation of conversations in memories without
+// native support
+var createMu sync.Mutex
+
+// Export returns all messages of a conversation in the org from ctx, so it can
+// be migrated to another backend, archived for audits or used as a test
+// fixture. Memories implementing interfaces.MemoryExporter export natively;
+// other memories are read through GetMessages.
+func Export(ctx context.Context, mem interfaces.Memory, conversationID string) ([]interfaces.Message, error) {
+	if conversationID == "" {
+		return nil, fmt.Errorf("conversation ID is required")
+	}
+
+	if exporter, ok := mem.(interfaces.MemoryExporter); ok {
+		return exporter.Export(ctx, conversationID)
+	}
+
+	messages, err := mem.GetMessages(WithConversationID(ctx, conversationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to export conversation %s: %w", conversationID, err)
+	}
+	return messages, nil
+}
+
+// Import appends messages to a conversation in the org from ctx. Memories
+// implementing interfaces.MemoryExporter import natively; other memories
+// receive the messages through AddMessage in order.
+func Import(ctx context.Context, mem interfaces.Memory, conversationID string, messages []interfaces.Message) error {
+	if conversationID == "" {
+		return fmt.Errorf("conversation ID is required")
+	}
+
+	if exporter, ok := mem.(interfaces.MemoryExporter); ok {
+		return exporter.Import(ctx, conversationID, messages)
+	}
+
+	ctx = WithConversationID(ctx, conversationID)
+	for i, message := range messages {
+		if err := mem.AddMessage(ctx, message); err != nil {
+			return fmt.Errorf("failed to import message %d of conversation %s: %w", i, conversationID, err)
+		}
+	}
+	return nil
+}
+
+// Create stores messages as a new conversation in the org from ctx. It fails
+// with interfaces.ErrConversationExists if the conversation already has
+// messages. Memories implementing interfaces.ConversationCreator create it
+// atomically; for other memories, creations are serialized within the
+// process only.
+func Create(ctx context.Context, mem interfaces.Memory, conversationID string, messages []interfaces.Message) error {
+	if conversationID == "" {
+		return fmt.Errorf("conversation ID is required")
+	}
+
+	if creator, ok := mem.(interfaces.ConversationCreator); ok {
+		return creator.CreateConversation(ctx, conversationID, messages)
+	}
+
+	createMu.Lock()
+	defer createMu.Unlock()
+
+	existing, err := Export(ctx, mem, conversationID)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("%w: %s", interfaces.ErrConversationExists, conversationID)
+	}
+	return Import(ctx, mem, conversationID, messages)
+}
+
+// Branch copies the messages of a conversation before index at to a new
+// conversation, forking it so that the conversation can continue differently
+// from that point while the original is kept, e.g. to edit and resend a
+// message or regenerate a response. at ranges from 0, for an empty branch, to
+// the number of messages, for a full copy. It fails with
+// interfaces.ErrConversationExists if the new conversation already has
+// messages.
+func Branch(ctx context.Context, mem interfaces.Memory, conversationID, newConversationID string, at int) error {
+	if newConversationID == "" {
+		return fmt.Errorf("branch conversation ID is required")
+	}
+
+	messages, err := Export(ctx, mem, conversationID)
+	if err != nil {
+		return err
+	}
+	if at < 0 || at > len(messages) {
+		return fmt.Errorf("%w: %d for conversation %s with %d messages", ErrMessageIndexOutOfRange, at, conversationID, len(messages))
+	}
+	return Create(ctx, mem, newConversationID, messages[:at])
+}
+
+// BranchBefore forks a conversation like Branch, before the stored message
+// with the given ID. Unlike an index, the ID still points at the same message
+// after the memory trims or summarizes older messages.
+func BranchBefore(ctx context.Context, mem interfaces.Memory, conversationID, newConversationID, messageID string) error {
+	if newConversationID == "" {
+		return fmt.Errorf("branch conversation ID is required")
+	}
+
+	messages, err := Export(ctx, mem, conversationID)
+	if err != nil {
+		return err
+	}
+	for i, message := range messages {
+		if MessageID(message) == messageID {
+			return Create(ctx, mem, newConversationID, messages[:i])
+		}
+	}
+	return fmt.Errorf("%w: %s in conversation %s", ErrMessageNotFound, messageID, conversationID)
+}
+
+// WriteJSONL writes messages as JSON Lines, one message per line
+func WriteJSONL(w io.Writer, messages []interfaces.Message) error {
+	encoder := json.NewEncoder(w)
+	for i, message := range messages {
+		if err := encoder.Encode(message); err != nil {
+			return fmt.Errorf("failed to encode message %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ReadJSONL reads messages written by WriteJSONL. Blank lines are skipped.
+func ReadJSONL(r io.Reader) ([]interfaces.Message, error) {
+	var messages []interfaces.Message
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var message interfaces.Message
+		if err := json.Unmarshal(data, &message); err != nil {
+			return nil, fmt.Errorf("failed to decode message on line %d: %w", line, err)
+		}
+		messages = append(messages, message)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	return messages, nil
+}
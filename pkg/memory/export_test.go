@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestExportImportMigratesBetweenBackends(t *testing.T) {
+	ctx := multitenancy.WithOrgID(context.Background(), "org1")
+
+	source := NewConversationBuffer()
+	messages := []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "What's the weather?"},
+		{Role: interfaces.MessageRoleAssistant, ToolCalls: []interfaces.ToolCall{{ID: "call1", Name: "weather", Arguments: `{"city":"Paris"}`}}},
+		{Role: interfaces.MessageRoleTool, ToolCallID: "call1", Content: "Sunny"},
+		{Role: interfaces.MessageRoleAssistant, Content: "It's sunny in Paris.", Metadata: map[string]interface{}{"model": "test"}},
+	}
+	require.NoError(t, Import(ctx, source, "conv1", messages))
+
+	exported, err := Export(ctx, source, "conv1")
+	require.NoError(t, err)
+	assert.Equal(t, messages, withoutMessageIDs(exported))
+
+	target := NewTokenWindowBuffer(0, nil)
+	require.NoError(t, Import(ctx, target, "conv1", exported))
+
+	migrated, err := Export(ctx, target, "conv1")
+	require.NoError(t, err)
+	assert.Equal(t, exported, migrated, "expected the messages to keep their IDs")
+
+	_, err = Export(ctx, source, "")
+	assert.Error(t, err)
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+	messages := []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "line one\nline two"},
+		{Role: interfaces.MessageRoleAssistant, Content: "ok"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSONL(&buf, messages))
+	assert.Equal(t, 2, strings.Count(buf.String(), "\n"), "expected one message per line")
+
+	decoded, err := ReadJSONL(strings.NewReader(buf.String() + "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, messages, decoded)
+
+	_, err = ReadJSONL(strings.NewReader("{\"Role\":\"user\"}\nnot json\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestBranch(t *testing.T) {
+	ctx := multitenancy.WithOrgID(context.Background(), "org1")
+
+	mem := NewConversationBuffer()
+	messages := []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "Hi"},
+		{Role: interfaces.MessageRoleAssistant, Content: "Hello!"},
+		{Role: interfaces.MessageRoleUser, Content: "Tell me a joke"},
+		{Role: interfaces.MessageRoleAssistant, Content: "No."},
+	}
+	require.NoError(t, Import(ctx, mem, "conv1", messages))
+
+	require.NoError(t, Branch(ctx, mem, "conv1", "conv2", 2))
+
+	original, err := Export(ctx, mem, "conv1")
+	require.NoError(t, err)
+	assert.Equal(t, messages, withoutMessageIDs(original), "expected the original conversation to be kept")
+
+	branch, err := Export(ctx, mem, "conv2")
+	require.NoError(t, err)
+	assert.Equal(t, original[:2], branch)
+
+	assert.Error(t, Branch(ctx, mem, "conv1", "conv3", 5))
+	assert.Error(t, Branch(ctx, mem, "conv1", "", 1))
+	assert.ErrorIs(t, Branch(ctx, mem, "conv1", "conv2", 1), interfaces.ErrConversationExists)
+}
+
+func TestBranchBeforeTrimmedMessages(t *testing.T) {
+	ctx := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+
+	// The window holds four messages of one token each
+	mem := NewTokenWindowBuffer(4, TokenizerFunc(func(string) (int, error) { return 1, nil }), WithMessageTokenOverhead(0))
+	for _, content := range []string{"Hi", "Hello!", "Tell me a joke"} {
+		require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: content}))
+	}
+	messages, err := Export(ctx, mem, "conv1")
+	require.NoError(t, err)
+	joke := MessageID(messages[2])
+	require.NotEmpty(t, joke)
+
+	// Older messages are trimmed, shifting the index of the joke request
+	for _, content := range []string{"No.", "Please?"} {
+		require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: content}))
+	}
+
+	require.NoError(t, BranchBefore(ctx, mem, "conv1", "conv2", joke))
+	branch, err := Export(ctx, mem, "conv2")
+	require.NoError(t, err)
+	require.Len(t, branch, 1)
+	assert.Equal(t, "Hello!", branch[0].Content)
+
+	assert.ErrorIs(t, BranchBefore(ctx, mem, "conv1", "conv3", "missing"), ErrMessageNotFound)
+}
+
+// opaqueMemory hides the native conversation creation of a memory
+type opaqueMemory struct {
+	interfaces.Memory
+}
+
+func TestCreateConcurrently(t *testing.T) {
+	ctx := multitenancy.WithOrgID(context.Background(), "org1")
+	messages := []interfaces.Message{{Role: interfaces.MessageRoleUser, Content: "Hi"}}
+
+	mr := miniredis.RunT(t)
+	memories := map[string]interfaces.Memory{
+		"buffer":       NewConversationBuffer(),
+		"token_window": NewTokenWindowBuffer(100, nil),
+		"redis":        NewRedisMemory(redis.NewClient(&redis.Options{Addr: mr.Addr()})),
+		"fallback":     opaqueMemory{NewConversationBuffer()},
+	}
+	for name, mem := range memories {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			var created, exists atomic.Int32
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					err := Create(ctx, mem, "conv1", messages)
+					switch {
+					case err == nil:
+						created.Add(1)
+					case errors.Is(err, interfaces.ErrConversationExists):
+						exists.Add(1)
+					default:
+						t.Errorf("Unexpected error: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, int32(1), created.Load(), "expected exactly one creation to succeed")
+			assert.Equal(t, int32(19), exists.Load())
+			stored, err := Export(ctx, mem, "conv1")
+			require.NoError(t, err)
+			assert.Len(t, stored, 1)
+		})
+	}
+}
+
+// withoutMessageIDs returns messages without the IDs assigned by memories
+func withoutMessageIDs(messages []interfaces.Message) []interfaces.Message {
+	result := make([]interfaces.Message, len(messages))
+	for i, message := range messages {
+		if MessageID(message) != "" {
+			metadata := make(map[string]interface{})
+			for key, value := range message.Metadata {
+				if key != MessageIDKey {
+					metadata[key] = value
+				}
+			}
+			if len(metadata) == 0 {
+				metadata = nil
+			}
+			message.Metadata = metadata
+		}
+		result[i] = message
+	}
+	return result
+}
@@ -6,7 +6,13 @@ import (
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tokens"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // MemoryFactory provides factory functions to create memory instances from configuration
@@ -33,8 +39,14 @@ func (f *MemoryFactory) CreateMemory(config map[string]interface{}, llmClient in
 		return f.createRedisMemory(config, llmClient)
 	case "buffer":
 		return f.createBufferMemory(config, llmClient)
+	case "token_window":
+		return f.createTokenWindowMemory(config)
 	case "vector":
 		return f.createVectorMemory(config, llmClient)
+	case "dynamodb":
+		return f.createDynamoDBMemory(config)
+	case "mongodb":
+		return f.createMongoDBMemory(config)
 	default:
 		return nil, fmt.Errorf("unsupported memory type: %s", memoryType)
 	}
@@ -213,6 +225,105 @@ func (f *MemoryFactory) createBufferMemory(config map[string]interface{}, llmCli
 	return bufferMemory, nil
 }
 
+// createTokenWindowMemory creates a token window buffer from configuration.
+// Tokens are counted with the tokenizer of the optional "model" entry; use
+// NewTokenWindowBuffer directly to supply an exact tokenizer.
+func (f *MemoryFactory) createTokenWindowMemory(config map[string]interface{}) (*TokenWindowBuffer, error) {
+	maxTokens := 0
+	switch v := config["max_tokens"].(type) {
+	case int:
+		maxTokens = v
+	case float64:
+		maxTokens = int(v)
+	}
+	if maxTokens <= 0 {
+		return nil, fmt.Errorf("token_window max_tokens must be a positive number")
+	}
+
+	model, _ := config["model"].(string)
+	return NewTokenWindowBuffer(maxTokens, tokens.ForModel(model)), nil
+}
+
+// createDynamoDBMemory creates a DynamoDB memory instance from configuration.
+// Credentials are resolved with the default AWS credential chain.
+func (f *MemoryFactory) createDynamoDBMemory(config map[string]interface{}) (*DynamoDBMemory, error) {
+	tableName, ok := config["table_name"].(string)
+	if !ok || tableName == "" {
+		return nil, fmt.Errorf("dynamodb table_name not specified or empty")
+	}
+
+	var loadOptions []func(*awsconfig.LoadOptions) error
+	if region, ok := config["region"].(string); ok && region != "" {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(region))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	client := dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+		// Custom endpoint, e.g. DynamoDB Local
+		if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+
+	return NewDynamoDBMemory(client, tableName, WithDynamoDBTTL(ttlFromConfig(config))), nil
+}
+
+// createMongoDBMemory creates a MongoDB memory instance from configuration
+func (f *MemoryFactory) createMongoDBMemory(config map[string]interface{}) (*MongoDBMemory, error) {
+	uri, ok := config["uri"].(string)
+	if !ok || uri == "" {
+		return nil, fmt.Errorf("mongodb uri not specified or empty")
+	}
+
+	database, ok := config["database"].(string)
+	if !ok || database == "" {
+		return nil, fmt.Errorf("mongodb database not specified or empty")
+	}
+
+	collectionName, ok := config["collection"].(string)
+	if !ok || collectionName == "" {
+		collectionName = "agent_memory" // default collection
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, mongooptions.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	collection := client.Database(database).Collection(collectionName)
+	if err := EnsureMongoDBIndexes(ctx, collection); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, err
+	}
+
+	return NewMongoDBMemory(collection, WithMongoDBTTL(ttlFromConfig(config))), nil
+}
+
+// ttlFromConfig reads ttl_hours from configuration (default: 24 hours)
+func ttlFromConfig(config map[string]interface{}) time.Duration {
+	ttlHours := 24
+	if ttlVal, ok := config["ttl_hours"]; ok {
+		switch v := ttlVal.(type) {
+		case int:
+			ttlHours = v
+		case float64:
+			ttlHours = int(v)
+		}
+	}
+	return time.Duration(ttlHours) * time.Hour
+}
+
 // createVectorMemory creates a vector memory instance from configuration
 func (f *MemoryFactory) createVectorMemory(config map[string]interface{}, llmClient interfaces.LLM) (interfaces.Memory, error) {
 	// Vector memory would require additional dependencies and configuration
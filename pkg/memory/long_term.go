@@ -0,0 +1,278 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// LongTermMemory wraps a short-term memory with semantic recall from a vector
+// store. Messages and facts are embedded as they are added, and relevant
+// memories from earlier conversations of the same organization are merged
+// into the messages returned for the current conversation.
+type LongTermMemory struct {
+	shortTerm     interfaces.Memory
+	vectorStore   interfaces.VectorStore
+	class         string
+	recallLimit   int
+	minScore      float32
+	roles         []interfaces.MessageRole
+	nativeTenancy bool
+}
+
+// LongTermOption represents an option for configuring long-term memory
+type LongTermOption func(*LongTermMemory)
+
+// WithRecallLimit sets the maximum number of memories recalled per query (default: 5)
+func WithRecallLimit(limit int) LongTermOption {
+	return func(m *LongTermMemory) {
+		m.recallLimit = limit
+	}
+}
+
+// WithRecallMinScore sets the minimum similarity score for recalled memories
+func WithRecallMinScore(score float32) LongTermOption {
+	return func(m *LongTermMemory) {
+		m.minScore = score
+	}
+}
+
+// WithLongTermClass sets the vector store class/collection used for memories
+// (default: "AgentMemory")
+func WithLongTermClass(class string) LongTermOption {
+	return func(m *LongTermMemory) {
+		m.class = class
+	}
+}
+
+// WithLongTermRoles sets which message roles are embedded (default: user and assistant)
+func WithLongTermRoles(roles ...interfaces.MessageRole) LongTermOption {
+	return func(m *LongTermMemory) {
+		m.roles = roles
+	}
+}
+
+// WithNativeTenancy stores each organization's memories in its own vector
+// store tenant, for stores with native multi-tenancy such as Weaviate.
+// Memories are always filtered by organization ID as well.
+func WithNativeTenancy(enabled bool) LongTermOption {
+	return func(m *LongTermMemory) {
+		m.nativeTenancy = enabled
+	}
+}
+
+// NewLongTermMemory creates a memory that keeps the current conversation in
+// shortTerm and recalls relevant past messages and facts from vectorStore
+func NewLongTermMemory(shortTerm interfaces.Memory, vectorStore interfaces.VectorStore, options ...LongTermOption) *LongTermMemory {
+	memory := &LongTermMemory{
+		shortTerm:   shortTerm,
+		vectorStore: vectorStore,
+		class:       "AgentMemory",
+		recallLimit: 5,
+		roles:       []interfaces.MessageRole{interfaces.MessageRoleUser, interfaces.MessageRoleAssistant},
+	}
+
+	for _, option := range options {
+		option(memory)
+	}
+
+	return memory
+}
+
+// AddMessage adds a message to the short-term memory and embeds it for later recall
+func (m *LongTermMemory) AddMessage(ctx context.Context, message interfaces.Message) error {
+	if err := m.shortTerm.AddMessage(ctx, message); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(message.Content) == "" || !m.embedsRole(message.Role) {
+		return nil
+	}
+
+	conversationID, _ := GetConversationID(ctx)
+	return m.store(ctx, message.Content, map[string]interface{}{
+		"kind":            "message",
+		"role":            string(message.Role),
+		"conversation_id": conversationID,
+	})
+}
+
+// Remember stores a fact for the organization in ctx, independent of any conversation
+func (m *LongTermMemory) Remember(ctx context.Context, fact string, metadata map[string]interface{}) error {
+	if strings.TrimSpace(fact) == "" {
+		return fmt.Errorf("fact is required")
+	}
+
+	stored := map[string]interface{}{"kind": "fact"}
+	for key, value := range metadata {
+		stored[key] = value
+	}
+	return m.store(ctx, fact, stored)
+}
+
+// Recall returns the memories of the organization in ctx most relevant to query
+func (m *LongTermMemory) Recall(ctx context.Context, query string, limit int) ([]interfaces.SearchResult, error) {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("organization ID not found in context: %w", err)
+	}
+	if limit <= 0 {
+		limit = m.recallLimit
+	}
+
+	options := []interfaces.SearchOption{
+		interfaces.WithFilters(map[string]interface{}{"org_id": orgID}),
+		func(o *interfaces.SearchOptions) { o.Class = m.class },
+	}
+	if m.minScore > 0 {
+		options = append(options, interfaces.WithMinScore(m.minScore))
+	}
+	if m.nativeTenancy {
+		options = append(options, interfaces.WithTenantSearch(orgID))
+	}
+
+	results, err := m.vectorStore.Search(ctx, query, limit, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search long-term memory: %w", err)
+	}
+	return results, nil
+}
+
+// GetMessages returns the short-term messages of the current conversation,
+// preceded by a system message with relevant long-term memories. The query is
+// the WithQuery option or, by default, the latest user message. Memories from
+// the current conversation are not repeated.
+func (m *LongTermMemory) GetMessages(ctx context.Context, options ...interfaces.GetMessagesOption) ([]interfaces.Message, error) {
+	opts := &interfaces.GetMessagesOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	messages, err := m.shortTerm.GetMessages(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	query := opts.Query
+	if query == "" {
+		for i := len(messages) - 1; i >= 0; i-- {
+			if messages[i].Role == interfaces.MessageRoleUser {
+				query = messages[i].Content
+				break
+			}
+		}
+	}
+	if query == "" {
+		return messages, nil
+	}
+
+	// Over-fetch so results from the current conversation can be skipped
+	results, err := m.Recall(ctx, query, m.recallLimit*2)
+	if err != nil {
+		return nil, err
+	}
+
+	conversationID, _ := GetConversationID(ctx)
+	var recalled []string
+	for _, result := range results {
+		if len(recalled) >= m.recallLimit {
+			break
+		}
+		if id, _ := result.Document.Metadata["conversation_id"].(string); id != "" && id == conversationID {
+			continue
+		}
+		recalled = append(recalled, "- "+result.Document.Content)
+	}
+	if len(recalled) == 0 {
+		return messages, nil
+	}
+
+	recall := interfaces.Message{
+		Role:    interfaces.MessageRoleSystem,
+		Content: "Relevant memories from earlier conversations:\n" + strings.Join(recalled, "\n"),
+		Metadata: map[string]interface{}{
+			"long_term_memory": true,
+		},
+	}
+	return append([]interfaces.Message{recall}, messages...), nil
+}
+
+// Clear clears the short-term memory of the current conversation. Long-term
+// memories are kept; use Forget to delete them.
+func (m *LongTermMemory) Clear(ctx context.Context) error {
+	return m.shortTerm.Clear(ctx)
+}
+
+// Forget deletes long-term memories by ID
+func (m *LongTermMemory) Forget(ctx context.Context, ids ...string) error {
+	options := []interfaces.DeleteOption{
+		func(o *interfaces.DeleteOptions) { o.Class = m.class },
+	}
+	if m.nativeTenancy {
+		orgID, err := multitenancy.GetOrgID(ctx)
+		if err != nil {
+			return fmt.Errorf("organization ID not found in context: %w", err)
+		}
+		options = append(options, interfaces.WithTenantDelete(orgID))
+	}
+	return m.vectorStore.Delete(ctx, ids, options...)
+}
+
+// Export implements interfaces.MemoryExporter. It exports the short-term
+// conversation without recalled memories.
+func (m *LongTermMemory) Export(ctx context.Context, conversationID string) ([]interfaces.Message, error) {
+	return Export(ctx, m.shortTerm, conversationID)
+}
+
+// Import implements interfaces.MemoryExporter. Imported messages are embedded
+// for recall like added messages.
+func (m *LongTermMemory) Import(ctx context.Context, conversationID string, messages []interfaces.Message) error {
+	ctx = WithConversationID(ctx, conversationID)
+	for i, message := range messages {
+		if err := m.AddMessage(ctx, message); err != nil {
+			return fmt.Errorf("failed to import message %d of conversation %s: %w", i, conversationID, err)
+		}
+	}
+	return nil
+}
+
+// store embeds content in the vector store under the organization in ctx
+func (m *LongTermMemory) store(ctx context.Context, content string, metadata map[string]interface{}) error {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return fmt.Errorf("organization ID not found in context: %w", err)
+	}
+
+	metadata["org_id"] = orgID
+	metadata["timestamp"] = time.Now().Unix()
+
+	options := []interfaces.StoreOption{interfaces.WithClass(m.class)}
+	if m.nativeTenancy {
+		options = append(options, interfaces.WithTenant(orgID))
+	}
+
+	doc := interfaces.Document{
+		ID:       uuid.New().String(),
+		Content:  content,
+		Metadata: metadata,
+	}
+	if err := m.vectorStore.Store(ctx, []interfaces.Document{doc}, options...); err != nil {
+		return fmt.Errorf("failed to store long-term memory: %w", err)
+	}
+	return nil
+}
+
+func (m *LongTermMemory) embedsRole(role interfaces.MessageRole) bool {
+	for _, r := range m.roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	vsmemory "github.com/Ingenimax/agent-sdk-go/pkg/vectorstore/memory"
+)
+
+// topicEmbedder embeds text as counts of a few topic words, so texts that
+// share topics are similar
+type topicEmbedder struct{}
+
+var embedderTopics = []string{"coffee", "tea", "paris", "berlin", "dog"}
+
+func (topicEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vector := make([]float32, len(embedderTopics)+1)
+	vector[len(embedderTopics)] = 0.01 // Avoid zero vectors
+	for i, topic := range embedderTopics {
+		vector[i] = float32(strings.Count(strings.ToLower(text), topic))
+	}
+	return vector, nil
+}
+
+func (e topicEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.Embed(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (topicEmbedder) CalculateSimilarity(vec1, vec2 []float32, metric string) (float32, error) {
+	return 0, nil
+}
+
+func longTermContext(orgID, conversationID string) context.Context {
+	return WithConversationID(multitenancy.WithOrgID(context.Background(), orgID), conversationID)
+}
+
+func TestLongTermMemoryRecallsEarlierConversations(t *testing.T) {
+	mem := NewLongTermMemory(NewConversationBuffer(), vsmemory.New(topicEmbedder{}), WithRecallLimit(1), WithRecallMinScore(0.5))
+
+	past := longTermContext("org1", "conv1")
+	require.NoError(t, mem.AddMessage(past, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "I always drink coffee in the morning"}))
+	require.NoError(t, mem.AddMessage(past, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "My dog is called Rex"}))
+
+	current := longTermContext("org1", "conv2")
+	require.NoError(t, mem.AddMessage(current, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "What coffee should I buy?"}))
+
+	messages, err := mem.GetMessages(current)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, interfaces.MessageRoleSystem, messages[0].Role)
+	assert.Contains(t, messages[0].Content, "I always drink coffee in the morning")
+	assert.NotContains(t, messages[0].Content, "Rex")
+	assert.Equal(t, "What coffee should I buy?", messages[1].Content)
+}
+
+func TestLongTermMemoryIsolatesOrganizations(t *testing.T) {
+	mem := NewLongTermMemory(NewConversationBuffer(), vsmemory.New(topicEmbedder{}))
+
+	require.NoError(t, mem.Remember(longTermContext("org1", "conv1"), "The team offsite is in Paris", nil))
+
+	other := longTermContext("org2", "conv1")
+	require.NoError(t, mem.AddMessage(other, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "Where is the offsite, Paris?"}))
+
+	messages, err := mem.GetMessages(other)
+	require.NoError(t, err)
+	require.Len(t, messages, 1, "memories of another organization must not be recalled")
+
+	results, err := mem.Recall(longTermContext("org1", "conv9"), "paris", 0)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "fact", results[0].Document.Metadata["kind"])
+}
+
+func TestLongTermMemorySkipsCurrentConversation(t *testing.T) {
+	mem := NewLongTermMemory(NewConversationBuffer(), vsmemory.New(topicEmbedder{}))
+	ctx := longTermContext("org1", "conv1")
+
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "Tell me about tea"}))
+	require.NoError(t, mem.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleAssistant, Content: "Tea is a drink"}))
+
+	messages, err := mem.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Len(t, messages, 2, "messages already in the short-term buffer must not be recalled")
+
+	require.NoError(t, mem.Clear(ctx))
+	results, err := mem.Recall(ctx, "tea", 0)
+	require.NoError(t, err)
+	assert.Len(t, results, 2, "Clear keeps long-term memories")
+
+	require.NoError(t, mem.Forget(ctx, results[0].Document.ID, results[1].Document.ID))
+	results, err = mem.Recall(ctx, "tea", 0)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Metadata keys used to record milestones on memory messages
+const (
+	// MilestoneMetadataKey holds the milestone name
+	MilestoneMetadataKey = "milestone"
+	// MilestoneDescriptionMetadataKey holds the optional milestone description
+	MilestoneDescriptionMetadataKey = "milestone_description"
+	// MilestoneDataMetadataKey holds optional structured milestone data
+	MilestoneDataMetadataKey = "milestone_data"
+	// MilestoneTimeMetadataKey holds the time the milestone was reached (RFC 3339)
+	MilestoneTimeMetadataKey = "milestone_at"
+)
+
+// Milestone is a named point of progress in a conversation, such as
+// "requirements confirmed" or "draft approved"
+type Milestone struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	ReachedAt   time.Time              `json:"reached_at"`
+}
+
+// MarkMilestone records a milestone in the conversation identified by ctx.
+// The milestone is stored as a system message whose metadata carries the
+// milestone fields, so it persists with any memory backend and is visible to
+// the model as conversation context. Milestones share the memory's retention,
+// so a bounded buffer may evict old milestones along with old messages.
+func MarkMilestone(ctx context.Context, mem interfaces.Memory, name, description string, data map[string]interface{}) error {
+	if mem == nil {
+		return fmt.Errorf("memory is required to record milestones")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("milestone name is required")
+	}
+
+	content := fmt.Sprintf("Milestone reached: %s", name)
+	if description != "" {
+		content += " - " + description
+	}
+
+	metadata := map[string]interface{}{
+		MilestoneMetadataKey:     name,
+		MilestoneTimeMetadataKey: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if description != "" {
+		metadata[MilestoneDescriptionMetadataKey] = description
+	}
+	if len(data) > 0 {
+		metadata[MilestoneDataMetadataKey] = data
+	}
+
+	return mem.AddMessage(ctx, interfaces.Message{
+		Role:     interfaces.MessageRoleSystem,
+		Content:  content,
+		Metadata: metadata,
+	})
+}
+
+// GetMilestones returns the milestones recorded in the conversation identified by ctx, oldest first
+func GetMilestones(ctx context.Context, mem interfaces.Memory) ([]Milestone, error) {
+	if mem == nil {
+		return nil, fmt.Errorf("memory is required to read milestones")
+	}
+	messages, err := mem.GetMessages(ctx, interfaces.WithRoles(string(interfaces.MessageRoleSystem)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	return MilestonesFromMessages(messages), nil
+}
+
+// HasMilestone reports whether the named milestone was reached in the conversation identified by ctx
+func HasMilestone(ctx context.Context, mem interfaces.Memory, name string) (bool, error) {
+	milestones, err := GetMilestones(ctx, mem)
+	if err != nil {
+		return false, err
+	}
+	for _, milestone := range milestones {
+		if strings.EqualFold(milestone.Name, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MilestonesFromMessages extracts the milestones recorded in a list of messages
+func MilestonesFromMessages(messages []interfaces.Message) []Milestone {
+	var milestones []Milestone
+	for _, msg := range messages {
+		name, ok := msg.Metadata[MilestoneMetadataKey].(string)
+		if !ok || name == "" {
+			continue
+		}
+
+		milestone := Milestone{Name: name}
+		milestone.Description, _ = msg.Metadata[MilestoneDescriptionMetadataKey].(string)
+		milestone.Data, _ = msg.Metadata[MilestoneDataMetadataKey].(map[string]interface{})
+		if reachedAt, ok := msg.Metadata[MilestoneTimeMetadataKey].(string); ok {
+			milestone.ReachedAt, _ = time.Parse(time.RFC3339Nano, reachedAt)
+		}
+		milestones = append(milestones, milestone)
+	}
+	return milestones
+}
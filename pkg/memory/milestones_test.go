@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestMilestones(t *testing.T) {
+	buffer := NewConversationBuffer()
+	ctx := multitenancy.WithOrgID(context.Background(), "org-1")
+	ctx = WithConversationID(ctx, "conv-1")
+
+	if err := buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "Here are my requirements"}); err != nil {
+		t.Fatalf("failed to add message: %v", err)
+	}
+	if err := MarkMilestone(ctx, buffer, "requirements confirmed", "user approved the list", map[string]interface{}{"count": 3}); err != nil {
+		t.Fatalf("failed to mark milestone: %v", err)
+	}
+	if err := MarkMilestone(ctx, buffer, " ", "", nil); err == nil {
+		t.Error("expected error for empty milestone name")
+	}
+
+	milestones, err := GetMilestones(ctx, buffer)
+	if err != nil {
+		t.Fatalf("failed to get milestones: %v", err)
+	}
+	if len(milestones) != 1 {
+		t.Fatalf("expected 1 milestone, got %d", len(milestones))
+	}
+	m := milestones[0]
+	if m.Name != "requirements confirmed" || m.Description != "user approved the list" || m.Data["count"] != 3 || m.ReachedAt.IsZero() {
+		t.Errorf("unexpected milestone: %+v", m)
+	}
+
+	reached, err := HasMilestone(ctx, buffer, "Requirements Confirmed")
+	if err != nil || !reached {
+		t.Errorf("expected milestone to be reached, got %v (err: %v)", reached, err)
+	}
+
+	// Milestones are scoped to the conversation
+	other := WithConversationID(ctx, "conv-2")
+	if reached, _ := HasMilestone(other, buffer, "requirements confirmed"); reached {
+		t.Error("expected milestone not to leak into another conversation")
+	}
+}
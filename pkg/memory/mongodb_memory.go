@@ -0,0 +1,332 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// MongoCollection is the subset of *mongo.Collection used by MongoDBMemory
+type MongoCollection interface {
+	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	Distinct(ctx context.Context, fieldName string, filter interface{}, opts ...*options.DistinctOptions) ([]interface{}, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+}
+
+// mongoMessage is the document stored for each message
+type mongoMessage struct {
+	OrgID          string     `bson:"org_id"`
+	ConversationID string     `bson:"conversation_id"`
+	CreatedAt      time.Time  `bson:"created_at"`
+	ExpiresAt      *time.Time `bson:"expires_at,omitempty"`
+	Data           []byte     `bson:"data"`
+}
+
+// MongoDBMemory implements a MongoDB-backed memory store. Each message is
+// stored as one document tagged with its organization and conversation.
+type MongoDBMemory struct {
+	collection     MongoCollection
+	ttl            time.Duration
+	serializer     MessageSerializer
+	maxMessageSize int
+}
+
+// MongoDBOption represents an option for configuring the MongoDB memory
+type MongoDBOption func(*MongoDBMemory)
+
+// WithMongoDBTTL sets how long messages are kept. Zero keeps them forever.
+func WithMongoDBTTL(ttl time.Duration) MongoDBOption {
+	return func(m *MongoDBMemory) {
+		m.ttl = ttl
+	}
+}
+
+// WithMongoDBSerializer sets the serializer used to encode messages
+func WithMongoDBSerializer(serializer MessageSerializer) MongoDBOption {
+	return func(m *MongoDBMemory) {
+		m.serializer = serializer
+	}
+}
+
+// WithMongoDBMaxMessageSize sets the maximum size of an encoded message
+func WithMongoDBMaxMessageSize(size int) MongoDBOption {
+	return func(m *MongoDBMemory) {
+		m.maxMessageSize = size
+	}
+}
+
+// NewMongoDBMemory creates a new MongoDB-backed memory. Call
+// EnsureMongoDBIndexes once to create the query and TTL indexes.
+func NewMongoDBMemory(collection MongoCollection, options ...MongoDBOption) *MongoDBMemory {
+	m := &MongoDBMemory{
+		collection:     collection,
+		ttl:            24 * time.Hour,
+		serializer:     JSONSerializer{},
+		maxMessageSize: 1048576, // 1MB
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+// EnsureMongoDBIndexes creates the indexes used by MongoDBMemory: a compound
+// index for conversation lookups and a TTL index on expires_at
+func EnsureMongoDBIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "conversation_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create MongoDB indexes: %w", err)
+	}
+	return nil
+}
+
+// AddMessage adds a message to the memory
+func (m *MongoDBMemory) AddMessage(ctx context.Context, message interfaces.Message) error {
+	orgID, conversationID, err := conversationScope(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := m.serializer.Marshal(withMessageID(message))
+	if err != nil {
+		return err
+	}
+	if m.maxMessageSize > 0 && len(data) > m.maxMessageSize {
+		return fmt.Errorf("message size exceeds maximum allowed size of %d bytes", m.maxMessageSize)
+	}
+
+	doc := mongoMessage{
+		OrgID:          orgID,
+		ConversationID: conversationID,
+		CreatedAt:      time.Now().UTC(),
+		Data:           data,
+	}
+	if m.ttl > 0 {
+		expiresAt := doc.CreatedAt.Add(m.ttl)
+		doc.ExpiresAt = &expiresAt
+	}
+
+	if _, err := m.collection.InsertOne(ctx, doc); err != nil {
+		return fmt.Errorf("failed to add message to MongoDB: %w", err)
+	}
+
+	return nil
+}
+
+// GetMessages retrieves messages from the memory
+func (m *MongoDBMemory) GetMessages(ctx context.Context, options ...interfaces.GetMessagesOption) ([]interfaces.Message, error) {
+	orgID, conversationID, err := conversationScope(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation ID: %w", err)
+	}
+
+	opts := &interfaces.GetMessagesOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	messages, err := m.find(ctx, bson.M{"org_id": orgID, "conversation_id": conversationID})
+	if err != nil {
+		return nil, err
+	}
+
+	return filterMessages(messages, opts), nil
+}
+
+// Clear clears the memory for a conversation
+func (m *MongoDBMemory) Clear(ctx context.Context) error {
+	orgID, conversationID, err := conversationScope(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation ID: %w", err)
+	}
+
+	_, err = m.collection.DeleteMany(ctx, bson.M{"org_id": orgID, "conversation_id": conversationID})
+	if err != nil {
+		return fmt.Errorf("failed to clear memory in MongoDB: %w", err)
+	}
+
+	return nil
+}
+
+// GetAllConversations returns all conversation IDs for the current org
+func (m *MongoDBMemory) GetAllConversations(ctx context.Context) ([]string, error) {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("organization ID not found in context: %w", err)
+	}
+
+	return m.distinct(ctx, "conversation_id", bson.M{"org_id": orgID})
+}
+
+// GetConversationMessages gets all messages for a specific conversation in current org
+func (m *MongoDBMemory) GetConversationMessages(ctx context.Context, conversationID string) ([]interfaces.Message, error) {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("organization ID not found in context: %w", err)
+	}
+
+	return m.find(ctx, bson.M{"org_id": orgID, "conversation_id": conversationID})
+}
+
+// GetMemoryStatistics returns basic memory statistics for current org
+func (m *MongoDBMemory) GetMemoryStatistics(ctx context.Context) (totalConversations, totalMessages int, err error) {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("organization ID not found in context: %w", err)
+	}
+
+	return m.statistics(ctx, bson.M{"org_id": orgID})
+}
+
+// GetAllConversationsAcrossOrgs returns all conversation IDs from all organizations
+func (m *MongoDBMemory) GetAllConversationsAcrossOrgs() (map[string][]string, error) {
+	ctx := context.Background()
+
+	orgIDs, err := m.distinct(ctx, "org_id", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	orgConversations := make(map[string][]string, len(orgIDs))
+	for _, orgID := range orgIDs {
+		conversations, err := m.distinct(ctx, "conversation_id", bson.M{"org_id": orgID})
+		if err != nil {
+			return nil, err
+		}
+		orgConversations[orgID] = conversations
+	}
+
+	return orgConversations, nil
+}
+
+// GetConversationMessagesAcrossOrgs finds conversation in any org and returns messages
+func (m *MongoDBMemory) GetConversationMessagesAcrossOrgs(conversationID string) ([]interfaces.Message, string, error) {
+	ctx := context.Background()
+
+	orgIDs, err := m.distinct(ctx, "org_id", bson.M{"conversation_id": conversationID})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(orgIDs) == 0 {
+		return []interfaces.Message{}, "", nil // Conversation not found
+	}
+
+	// Use the first match (there should typically be only one)
+	orgID := orgIDs[0]
+	messages, err := m.find(ctx, bson.M{"org_id": orgID, "conversation_id": conversationID})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return messages, orgID, nil
+}
+
+// GetMemoryStatisticsAcrossOrgs returns memory statistics across all organizations
+func (m *MongoDBMemory) GetMemoryStatisticsAcrossOrgs() (totalConversations, totalMessages int, err error) {
+	ctx := context.Background()
+
+	conversations, err := m.GetAllConversationsAcrossOrgs()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, ids := range conversations {
+		totalConversations += len(ids)
+	}
+
+	count, err := m.collection.CountDocuments(ctx, m.unexpired(bson.M{}))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	return totalConversations, int(count), nil
+}
+
+// Close is a no-op; the MongoDB client is owned by the caller
+func (m *MongoDBMemory) Close() error {
+	return nil
+}
+
+// find returns the unexpired messages matching filter in insertion order
+func (m *MongoDBMemory) find(ctx context.Context, filter bson.M) ([]interfaces.Message, error) {
+	cursor, err := m.collection.Find(ctx, m.unexpired(filter),
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages from MongoDB: %w", err)
+	}
+
+	var docs []mongoMessage
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode messages: %w", err)
+	}
+
+	messages := make([]interfaces.Message, 0, len(docs))
+	for _, doc := range docs {
+		message, err := m.serializer.Unmarshal(doc.Data)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// distinct returns the distinct string values of field among unexpired documents
+func (m *MongoDBMemory) distinct(ctx context.Context, field string, filter bson.M) ([]string, error) {
+	values, err := m.collection.Distinct(ctx, field, m.unexpired(filter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s values: %w", field, err)
+	}
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *MongoDBMemory) statistics(ctx context.Context, filter bson.M) (totalConversations, totalMessages int, err error) {
+	conversations, err := m.distinct(ctx, "conversation_id", filter)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	count, err := m.collection.CountDocuments(ctx, m.unexpired(filter))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	return len(conversations), int(count), nil
+}
+
+// unexpired restricts filter to documents that are not past their TTL. The
+// MongoDB TTL monitor deletes expired documents periodically, not immediately.
+func (m *MongoDBMemory) unexpired(filter bson.M) bson.M {
+	result := bson.M{
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": time.Now().UTC()}},
+		},
+	}
+	for k, v := range filter {
+		result[k] = v
+	}
+	return result
+}
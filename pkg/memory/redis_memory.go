@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -148,14 +149,12 @@ func (r *RedisMemory) AddMessage(ctx context.Context, message interfaces.Message
 	}
 
 	// Get organization ID from context for multi-tenancy support
-	orgID, err := multitenancy.GetOrgID(ctx)
-	if err != nil {
-		// If no organization ID is found, use a default
-		orgID = "default"
-	}
+	orgID := multitenancy.OrgIDOrDefault(ctx)
 
 	// Create Redis key with org and conversation IDs for proper isolation
-	key := fmt.Sprintf("%s%s:%s", r.keyPrefix, orgID, conversationID)
+	key := r.conversationKey(r.keyPrefix, orgID, conversationID)
+
+	message = withMessageID(message)
 
 	// Validate message size if configured
 	if r.maxMessageSize > 0 {
@@ -218,6 +217,61 @@ func (r *RedisMemory) AddMessage(ctx context.Context, message interfaces.Message
 		r.retryOptions.MaxRetries, retryErr)
 }
 
+// CreateConversation implements interfaces.ConversationCreator. The messages
+// are written in a transaction that fails if the conversation is written
+// concurrently.
+func (r *RedisMemory) CreateConversation(ctx context.Context, conversationID string, messages []interfaces.Message) error {
+	ctx = WithConversationID(ctx, conversationID)
+	storedID, err := getConversationID(ctx)
+	if err != nil {
+		return err
+	}
+	key := r.conversationKey(r.keyPrefix, multitenancy.OrgIDOrDefault(ctx), storedID)
+
+	values := make([]interface{}, len(messages))
+	for i, message := range messages {
+		processedMessage := withMessageID(message)
+		if r.compressionEnabled || r.encryptionKey != nil {
+			processedMessage, err = r.processMessage(processedMessage)
+			if err != nil {
+				return fmt.Errorf("failed to process message: %w", err)
+			}
+		}
+		messageJSON, err := json.Marshal(processedMessage)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+		values[i] = messageJSON
+	}
+
+	err = r.client.Watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if exists > 0 {
+			return fmt.Errorf("%w: %s", interfaces.ErrConversationExists, conversationID)
+		}
+		if len(values) == 0 {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.RPush(ctx, key, values...)
+			pipe.Expire(ctx, key, r.ttl)
+			return nil
+		})
+		return err
+	}, key)
+	switch {
+	case errors.Is(err, redis.TxFailedErr):
+		return fmt.Errorf("%w: %s", interfaces.ErrConversationExists, conversationID)
+	case err != nil && !errors.Is(err, interfaces.ErrConversationExists):
+		return fmt.Errorf("failed to create conversation %s in Redis: %w", conversationID, err)
+	}
+	return err
+}
+
 // processMessage handles compression and encryption of messages
 func (r *RedisMemory) processMessage(message interfaces.Message) (interfaces.Message, error) {
 	// Create a copy of the message to avoid modifying the original
@@ -249,14 +303,10 @@ func (r *RedisMemory) GetMessages(ctx context.Context, options ...interfaces.Get
 	}
 
 	// Get organization ID from context for multi-tenancy support
-	orgID, err := multitenancy.GetOrgID(ctx)
-	if err != nil {
-		// If no organization ID is found, use a default
-		orgID = "default"
-	}
+	orgID := multitenancy.OrgIDOrDefault(ctx)
 
 	// Create Redis key with org and conversation IDs
-	key := fmt.Sprintf("%s%s:%s", r.keyPrefix, orgID, conversationID)
+	key := r.conversationKey(r.keyPrefix, orgID, conversationID)
 
 	// Apply options
 	opts := &interfaces.GetMessagesOptions{}
@@ -323,14 +373,10 @@ func (r *RedisMemory) Clear(ctx context.Context) error {
 	}
 
 	// Get organization ID from context for multi-tenancy support
-	orgID, err := multitenancy.GetOrgID(ctx)
-	if err != nil {
-		// If no organization ID is found, use a default
-		orgID = "default"
-	}
+	orgID := multitenancy.OrgIDOrDefault(ctx)
 
 	// Create Redis key with org and conversation IDs
-	key := fmt.Sprintf("%s%s:%s", r.keyPrefix, orgID, conversationID)
+	key := r.conversationKey(r.keyPrefix, orgID, conversationID)
 
 	// Delete the messages key from Redis
 	err = r.client.Del(ctx, key).Err()
@@ -340,8 +386,8 @@ func (r *RedisMemory) Clear(ctx context.Context) error {
 
 	// Clear summaries if summarization is enabled
 	if r.summarizationEnabled {
-		summaryKey := fmt.Sprintf("%s%s:%s", r.summaryKeyPrefix, orgID, conversationID)
-		metaKey := fmt.Sprintf("%smeta:%s:%s", r.summaryKeyPrefix, orgID, conversationID)
+		summaryKey := r.conversationKey(r.summaryKeyPrefix, orgID, conversationID)
+		metaKey := r.conversationKey(r.summaryKeyPrefix+"meta:", orgID, conversationID)
 
 		// Delete summary and metadata keys
 		err = r.client.Del(ctx, summaryKey, metaKey).Err()
@@ -383,13 +429,10 @@ func (r *RedisMemory) checkAndSummarize(ctx context.Context) error {
 	}
 
 	// Get organization ID from context
-	orgID, err := multitenancy.GetOrgID(ctx)
-	if err != nil {
-		orgID = "default"
-	}
+	orgID := multitenancy.OrgIDOrDefault(ctx)
 
 	// Create Redis key
-	key := fmt.Sprintf("%s%s:%s", r.keyPrefix, orgID, conversationID)
+	key := r.conversationKey(r.keyPrefix, orgID, conversationID)
 
 	// Get message count
 	count, err := r.client.LLen(ctx, key).Result()
@@ -493,13 +536,10 @@ func (r *RedisMemory) storeSummary(ctx context.Context, summary interfaces.Messa
 	}
 
 	// Get organization ID from context
-	orgID, err := multitenancy.GetOrgID(ctx)
-	if err != nil {
-		orgID = "default"
-	}
+	orgID := multitenancy.OrgIDOrDefault(ctx)
 
 	// Create Redis key for summaries
-	summaryKey := fmt.Sprintf("%s%s:%s", r.summaryKeyPrefix, orgID, conversationID)
+	summaryKey := r.conversationKey(r.summaryKeyPrefix, orgID, conversationID)
 
 	// Marshal summary
 	summaryJSON, err := json.Marshal(summary)
@@ -527,13 +567,10 @@ func (r *RedisMemory) getSummaries(ctx context.Context) ([]interfaces.Message, e
 	}
 
 	// Get organization ID from context
-	orgID, err := multitenancy.GetOrgID(ctx)
-	if err != nil {
-		orgID = "default"
-	}
+	orgID := multitenancy.OrgIDOrDefault(ctx)
 
 	// Create Redis key for summaries
-	summaryKey := fmt.Sprintf("%s%s:%s", r.summaryKeyPrefix, orgID, conversationID)
+	summaryKey := r.conversationKey(r.summaryKeyPrefix, orgID, conversationID)
 
 	// Get all summaries from Redis
 	results, err := r.client.LRange(ctx, summaryKey, 0, -1).Result()
@@ -563,13 +600,10 @@ func (r *RedisMemory) rotateSummaries(ctx context.Context) error {
 	}
 
 	// Get organization ID from context
-	orgID, err := multitenancy.GetOrgID(ctx)
-	if err != nil {
-		orgID = "default"
-	}
+	orgID := multitenancy.OrgIDOrDefault(ctx)
 
 	// Create Redis key for summaries
-	summaryKey := fmt.Sprintf("%s%s:%s", r.summaryKeyPrefix, orgID, conversationID)
+	summaryKey := r.conversationKey(r.summaryKeyPrefix, orgID, conversationID)
 
 	// Get summary count
 	count, err := r.client.LLen(ctx, summaryKey).Result()
@@ -599,7 +633,7 @@ func (r *RedisMemory) GetAllConversations(ctx context.Context) ([]string, error)
 	}
 
 	// Search for all keys matching the pattern for this org
-	pattern := fmt.Sprintf("%s%s:*", r.keyPrefix, orgID)
+	pattern := r.conversationKey(r.keyPrefix, orgID, "*")
 	keys, err := r.client.Keys(ctx, pattern).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get conversation keys: %w", err)
@@ -607,7 +641,7 @@ func (r *RedisMemory) GetAllConversations(ctx context.Context) ([]string, error)
 
 	// Extract conversation IDs from keys
 	conversations := make([]string, 0, len(keys))
-	expectedPrefix := fmt.Sprintf("%s%s:", r.keyPrefix, orgID)
+	expectedPrefix := r.conversationKey(r.keyPrefix, orgID, "")
 
 	for _, key := range keys {
 		if strings.HasPrefix(key, expectedPrefix) {
@@ -628,7 +662,7 @@ func (r *RedisMemory) GetConversationMessages(ctx context.Context, conversationI
 	}
 
 	// Create Redis key
-	key := fmt.Sprintf("%s%s:%s", r.keyPrefix, orgID, conversationID)
+	key := r.conversationKey(r.keyPrefix, orgID, conversationID)
 
 	// Get all messages from Redis list
 	data, err := r.client.LRange(ctx, key, 0, -1).Result()
@@ -657,7 +691,7 @@ func (r *RedisMemory) GetMemoryStatistics(ctx context.Context) (totalConversatio
 	}
 
 	// Search for all keys matching the pattern for this org
-	pattern := fmt.Sprintf("%s%s:*", r.keyPrefix, orgID)
+	pattern := r.conversationKey(r.keyPrefix, orgID, "*")
 	keys, err := r.client.Keys(ctx, pattern).Result()
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get conversation keys: %w", err)
@@ -698,7 +732,7 @@ func (r *RedisMemory) GetAllConversationsAcrossOrgs() (map[string][]string, erro
 			remainder := strings.TrimPrefix(key, r.keyPrefix)
 			parts := strings.SplitN(remainder, ":", 2)
 			if len(parts) == 2 {
-				orgID := parts[0]
+				orgID := multitenancy.UnescapeKeySegment(parts[0])
 				conversationID := parts[1]
 				orgConversations[orgID] = append(orgConversations[orgID], conversationID)
 			}
@@ -708,6 +742,13 @@ func (r *RedisMemory) GetAllConversationsAcrossOrgs() (map[string][]string, erro
 	return orgConversations, nil
 }
 
+// conversationKey returns the key of a conversation of an organization. The
+// organization ID is escaped, so that no conversation ID or key pattern can
+// reach the keys of another organization.
+func (r *RedisMemory) conversationKey(prefix, orgID, conversationID string) string {
+	return prefix + multitenancy.EscapeKeySegment(orgID) + ":" + conversationID
+}
+
 // GetConversationMessagesAcrossOrgs finds conversation in any org and returns messages
 func (r *RedisMemory) GetConversationMessagesAcrossOrgs(conversationID string) ([]interfaces.Message, string, error) {
 	ctx := context.Background()
@@ -731,7 +772,7 @@ func (r *RedisMemory) GetConversationMessagesAcrossOrgs(conversationID string) (
 		remainder := strings.TrimPrefix(key, r.keyPrefix)
 		parts := strings.SplitN(remainder, ":", 2)
 		if len(parts) == 2 {
-			orgID := parts[0]
+			orgID := multitenancy.UnescapeKeySegment(parts[0])
 
 			// Get messages from Redis
 			data, err := r.client.LRange(ctx, key, 0, -1).Result()
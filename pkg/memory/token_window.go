@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tokens"
+)
+
+// Tokenizer counts the tokens in a piece of text for a specific model
+type Tokenizer interface {
+	CountTokens(text string) (int, error)
+}
+
+// TokenizerFunc adapts a function to the Tokenizer interface, e.g. to wrap a
+// model-specific encoder such as tiktoken
+type TokenizerFunc func(text string) (int, error)
+
+// CountTokens implements Tokenizer.CountTokens
+func (f TokenizerFunc) CountTokens(text string) (int, error) {
+	return f(text)
+}
+
+// TokenWindowBuffer is a conversation buffer that keeps the most recent
+// messages that fit within a token budget. Unlike a message-count window it
+// trims by the actual size of each message, so long conversations stay below
+// the model's context length.
+type TokenWindowBuffer struct {
+	*ConversationBuffer
+	maxTokens       int
+	tokenizer       Tokenizer
+	messageOverhead int
+	tokenCounts     map[string][]int // Per-message token counts, guarded by ConversationBuffer.mu
+}
+
+// TokenWindowOption represents an option for configuring the token window buffer
+type TokenWindowOption func(*TokenWindowBuffer)
+
+// WithMessageTokenOverhead sets the number of tokens added to each message for
+// the provider's message framing (default: 4)
+func WithMessageTokenOverhead(tokens int) TokenWindowOption {
+	return func(b *TokenWindowBuffer) {
+		b.messageOverhead = tokens
+	}
+}
+
+// NewTokenWindowBuffer creates a conversation buffer that drops the oldest
+// messages once the conversation exceeds maxTokens as counted by tokenizer. A
+// nil tokenizer uses tokens.DefaultEstimator. The most recent message is always kept.
+func NewTokenWindowBuffer(maxTokens int, tokenizer Tokenizer, options ...TokenWindowOption) *TokenWindowBuffer {
+	if tokenizer == nil {
+		tokenizer = tokens.DefaultEstimator
+	}
+
+	buffer := &TokenWindowBuffer{
+		ConversationBuffer: NewConversationBuffer(WithMaxSize(0)),
+		maxTokens:          maxTokens,
+		tokenizer:          tokenizer,
+		messageOverhead:    4,
+		tokenCounts:        make(map[string][]int),
+	}
+
+	for _, option := range options {
+		option(buffer)
+	}
+
+	return buffer
+}
+
+// AddMessage adds a message to the buffer and trims the oldest messages that
+// no longer fit in the token budget
+func (b *TokenWindowBuffer) AddMessage(ctx context.Context, message interfaces.Message) error {
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := b.countMessage(message)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.messages[conversationID] = append(b.messages[conversationID], withMessageID(message))
+	b.tokenCounts[conversationID] = append(b.tokenCounts[conversationID], tokens)
+	b.trim(conversationID)
+
+	return nil
+}
+
+// CreateConversation implements interfaces.ConversationCreator
+func (b *TokenWindowBuffer) CreateConversation(ctx context.Context, conversationID string, messages []interfaces.Message) error {
+	key, err := getConversationID(WithConversationID(ctx, conversationID))
+	if err != nil {
+		return err
+	}
+
+	counts := make([]int, len(messages))
+	for i, message := range messages {
+		if counts[i], err = b.countMessage(message); err != nil {
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.messages[key]) > 0 {
+		return fmt.Errorf("%w: %s", interfaces.ErrConversationExists, conversationID)
+	}
+
+	for _, message := range messages {
+		b.messages[key] = append(b.messages[key], withMessageID(message))
+	}
+	b.tokenCounts[key] = counts
+	b.trim(key)
+
+	return nil
+}
+
+// Clear clears the buffer for a conversation
+func (b *TokenWindowBuffer) Clear(ctx context.Context) error {
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.messages, conversationID)
+	delete(b.tokenCounts, conversationID)
+
+	return nil
+}
+
+// TokenCount returns the number of tokens currently held for the conversation in ctx
+func (b *TokenWindowBuffer) TokenCount(ctx context.Context) (int, error) {
+	conversationID, err := getConversationID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	total := 0
+	for _, tokens := range b.tokenCounts[conversationID] {
+		total += tokens
+	}
+	return total, nil
+}
+
+// trim drops the oldest messages of a conversation until it fits the budget.
+// Tool results left at the start of the window are dropped as well, since
+// providers reject tool messages whose tool call is no longer present.
+func (b *TokenWindowBuffer) trim(conversationID string) {
+	if b.maxTokens <= 0 {
+		return
+	}
+
+	messages := b.messages[conversationID]
+	counts := b.tokenCounts[conversationID]
+
+	total := 0
+	for _, tokens := range counts {
+		total += tokens
+	}
+
+	start := 0
+	for total > b.maxTokens && start < len(messages)-1 {
+		total -= counts[start]
+		start++
+	}
+	// Tool results whose call was trimmed are dropped as well
+	for start > 0 && start < len(messages)-1 && messages[start].Role == interfaces.MessageRoleTool {
+		total -= counts[start]
+		start++
+	}
+
+	if start == 0 {
+		return
+	}
+
+	// Copy so the trimmed messages can be garbage collected
+	b.messages[conversationID] = append([]interfaces.Message(nil), messages[start:]...)
+	b.tokenCounts[conversationID] = append([]int(nil), counts[start:]...)
+}
+
+// countMessage returns the token count of a message's content and tool calls
+func (b *TokenWindowBuffer) countMessage(message interfaces.Message) (int, error) {
+	texts := []string{message.Content}
+	for _, call := range message.ToolCalls {
+		texts = append(texts, call.Name, call.Arguments)
+	}
+
+	total := b.messageOverhead
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+		tokens, err := b.tokenizer.CountTokens(text)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count tokens: %w", err)
+		}
+		total += tokens
+	}
+	return total, nil
+}
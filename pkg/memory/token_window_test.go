@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// wordTokenizer counts one token per word
+var wordTokenizer = TokenizerFunc(func(text string) (int, error) {
+	return len(strings.Fields(text)), nil
+})
+
+func tokenWindowContext() context.Context {
+	return WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv1")
+}
+
+func TestTokenWindowBufferTrimsByTokens(t *testing.T) {
+	ctx := tokenWindowContext()
+	buffer := NewTokenWindowBuffer(10, wordTokenizer, WithMessageTokenOverhead(0))
+
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "one two three four"}))
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleAssistant, Content: "five six seven"}))
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "eight nine"}))
+
+	messages, err := buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Len(t, messages, 3)
+
+	// 4 + 3 + 2 + 5 = 14 tokens: the first message no longer fits
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleAssistant, Content: "a b c d e"}))
+
+	messages, err = buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	assert.Equal(t, "five six seven", messages[0].Content)
+
+	tokens, err := buffer.TokenCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 10, tokens)
+}
+
+func TestTokenWindowBufferKeepsLatestMessage(t *testing.T) {
+	ctx := tokenWindowContext()
+	buffer := NewTokenWindowBuffer(3, wordTokenizer, WithMessageTokenOverhead(0))
+
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "hi"}))
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "a very long message that exceeds the budget"}))
+
+	messages, err := buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "a very long message that exceeds the budget", messages[0].Content)
+}
+
+func TestTokenWindowBufferDropsOrphanedToolResults(t *testing.T) {
+	ctx := tokenWindowContext()
+	buffer := NewTokenWindowBuffer(6, wordTokenizer, WithMessageTokenOverhead(1))
+
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{
+		Role:      interfaces.MessageRoleAssistant,
+		ToolCalls: []interfaces.ToolCall{{ID: "1", Name: "search", Arguments: "query"}},
+	}))
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleTool, ToolCallID: "1", Content: "result"}))
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleAssistant, Content: "the answer is"}))
+
+	messages, err := buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, interfaces.MessageRoleAssistant, messages[0].Role)
+	assert.Equal(t, "the answer is", messages[0].Content)
+}
+
+func TestTokenWindowBufferToolResultAtBoundary(t *testing.T) {
+	ctx := tokenWindowContext()
+	buffer := NewTokenWindowBuffer(8, wordTokenizer, WithMessageTokenOverhead(0))
+
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{
+		Role:      interfaces.MessageRoleAssistant,
+		Content:   "searching",
+		ToolCalls: []interfaces.ToolCall{{ID: "1", Name: "search", Arguments: "query"}},
+	}))
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleTool, ToolCallID: "1", Content: "three result words"}))
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleAssistant, Content: "the answer is here"}))
+
+	// 1 + 3 + 4 + 2 = 10 tokens: dropping the tool call brings the window
+	// within budget, and its result goes with it
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "thank you"}))
+
+	messages, err := buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "the answer is here", messages[0].Content)
+	assert.Equal(t, "thank you", messages[1].Content)
+
+	tokens, err := buffer.TokenCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 6, tokens)
+}
+
+func TestTokenWindowBufferIsolationAndClear(t *testing.T) {
+	ctx := tokenWindowContext()
+	other := WithConversationID(multitenancy.WithOrgID(context.Background(), "org1"), "conv2")
+	buffer := NewTokenWindowBuffer(100, nil)
+
+	require.NoError(t, buffer.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "hello"}))
+	require.NoError(t, buffer.AddMessage(other, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "other"}))
+
+	require.NoError(t, buffer.Clear(ctx))
+
+	messages, err := buffer.GetMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	tokens, err := buffer.TokenCount(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, tokens)
+
+	messages, err = buffer.GetMessages(other)
+	require.NoError(t, err)
+	assert.Len(t, messages, 1)
+}
+
+func TestTokenWindowBufferTokenizerError(t *testing.T) {
+	buffer := NewTokenWindowBuffer(100, TokenizerFunc(func(string) (int, error) {
+		return 0, errors.New("unknown model")
+	}))
+
+	err := buffer.AddMessage(tokenWindowContext(), interfaces.Message{Role: interfaces.MessageRoleUser, Content: "hello"})
+	assert.Error(t, err)
+}
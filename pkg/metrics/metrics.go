@@ -0,0 +1,114 @@
+// Package metrics exports Prometheus metrics for agents and microservices:
+// request counts and latencies, token usage, tool calls, errors and active
+// streams. Metrics are served in the Prometheus text format by Handler.
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Status label values
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// Metrics are the metrics recorded for agent runs
+type Metrics struct {
+	// Requests counts agent runs by agent and status (success or error)
+	Requests *Counter
+
+	// RequestDuration observes the duration of agent runs in seconds
+	RequestDuration *Histogram
+
+	// Tokens counts LLM tokens by agent, model and type (input or output)
+	Tokens *Counter
+
+	// ToolCalls counts tool executions by agent, tool and status
+	ToolCalls *Counter
+
+	// Errors counts failures by agent and source (run, stream or tool)
+	Errors *Counter
+
+	// ActiveStreams is the number of streaming runs in progress by agent
+	ActiveStreams *Gauge
+}
+
+// New creates the agent metrics and registers them with registry
+func New(registry *Registry) *Metrics {
+	return &Metrics{
+		Requests:        registry.NewCounter("agent_sdk_requests_total", "Agent runs by agent and status.", "agent", "status"),
+		RequestDuration: registry.NewHistogram("agent_sdk_request_duration_seconds", "Duration of agent runs in seconds.", DefaultBuckets, "agent"),
+		Tokens:          registry.NewCounter("agent_sdk_tokens_total", "LLM tokens used by agent, model and type.", "agent", "model", "type"),
+		ToolCalls:       registry.NewCounter("agent_sdk_tool_calls_total", "Tool executions by agent, tool and status.", "agent", "tool", "status"),
+		Errors:          registry.NewCounter("agent_sdk_errors_total", "Failures by agent and source.", "agent", "source"),
+		ActiveStreams:   registry.NewGauge("agent_sdk_active_streams", "Streaming runs in progress by agent.", "agent"),
+	}
+}
+
+var (
+	defaultMetrics     *Metrics
+	defaultMetricsOnce sync.Once
+)
+
+// Default returns the metrics registered with DefaultRegistry
+func Default() *Metrics {
+	defaultMetricsOnce.Do(func() {
+		defaultMetrics = New(DefaultRegistry)
+	})
+	return defaultMetrics
+}
+
+// ObserveRequest records a run of agent that took duration and failed with err, if any
+func (m *Metrics) ObserveRequest(agent string, duration time.Duration, err error) {
+	m.Requests.Inc(agent, status(err))
+	m.RequestDuration.Observe(duration.Seconds(), agent)
+	if err != nil {
+		m.Errors.Inc(agent, "run")
+	}
+}
+
+// ObserveStream records a streaming run of agent, like ObserveRequest
+func (m *Metrics) ObserveStream(agent string, duration time.Duration, err error) {
+	m.Requests.Inc(agent, status(err))
+	m.RequestDuration.Observe(duration.Seconds(), agent)
+	if err != nil {
+		m.Errors.Inc(agent, "stream")
+	}
+}
+
+// AddTokens records the tokens used by agent with model
+func (m *Metrics) AddTokens(agent, model string, inputTokens, outputTokens int) {
+	if inputTokens > 0 {
+		m.Tokens.Add(float64(inputTokens), agent, model, "input")
+	}
+	if outputTokens > 0 {
+		m.Tokens.Add(float64(outputTokens), agent, model, "output")
+	}
+}
+
+// ObserveToolCall records an execution of tool by agent that failed with err, if any
+func (m *Metrics) ObserveToolCall(agent, tool string, err error) {
+	m.ToolCalls.Inc(agent, tool, status(err))
+	if err != nil {
+		m.Errors.Inc(agent, "tool")
+	}
+}
+
+// StreamStarted increments the active streams of agent
+func (m *Metrics) StreamStarted(agent string) {
+	m.ActiveStreams.Add(1, agent)
+}
+
+// StreamEnded decrements the active streams of agent
+func (m *Metrics) StreamEnded(agent string) {
+	m.ActiveStreams.Add(-1, agent)
+}
+
+func status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusSuccess
+}
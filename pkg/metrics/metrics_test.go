@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryTextFormat(t *testing.T) {
+	registry := NewRegistry()
+	m := New(registry)
+
+	m.ObserveRequest("support", 300*time.Millisecond, nil)
+	m.ObserveRequest("support", 2*time.Second, errors.New("boom"))
+	m.AddTokens("support", "gpt-4o", 120, 30)
+	m.ObserveToolCall("support", "search", nil)
+	m.StreamStarted("support")
+
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+
+	if ct := recorder.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	for _, want := range []string{
+		"# TYPE agent_sdk_requests_total counter",
+		`agent_sdk_requests_total{agent="support",status="success"} 1`,
+		`agent_sdk_requests_total{agent="support",status="error"} 1`,
+		`agent_sdk_errors_total{agent="support",source="run"} 1`,
+		"# TYPE agent_sdk_request_duration_seconds histogram",
+		`agent_sdk_request_duration_seconds_bucket{agent="support",le="0.5"} 1`,
+		`agent_sdk_request_duration_seconds_bucket{agent="support",le="+Inf"} 2`,
+		`agent_sdk_request_duration_seconds_sum{agent="support"} 2.3`,
+		`agent_sdk_request_duration_seconds_count{agent="support"} 2`,
+		`agent_sdk_tokens_total{agent="support",model="gpt-4o",type="input"} 120`,
+		`agent_sdk_tokens_total{agent="support",model="gpt-4o",type="output"} 30`,
+		`agent_sdk_tool_calls_total{agent="support",tool="search",status="success"} 1`,
+		`agent_sdk_active_streams{agent="support"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in:\n%s", want, body)
+		}
+	}
+
+	m.StreamEnded("support")
+	if got := m.ActiveStreams.Value("support"); got != 0 {
+		t.Errorf("expected no active streams, got %v", got)
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("test_total", "Test counter.", "name")
+	counter.Inc("say \"hi\"\n")
+
+	var out strings.Builder
+	if _, err := registry.WriteTo(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `test_total{name="say \"hi\"\n"} 1`) {
+		t.Errorf("expected escaped label, got:\n%s", out.String())
+	}
+}
+
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGauge("test", "Test gauge.")
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	registry.NewGauge("test", "Test gauge.")
+}
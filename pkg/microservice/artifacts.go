@@ -0,0 +1,81 @@
+package microservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// artifactsSuffix is the suffix of the per-conversation artifacts endpoint
+// (/api/v1/conversations/{id}/artifacts)
+const artifactsSuffix = "/artifacts"
+
+// ArtifactList is the response of the conversation artifacts endpoint
+type ArtifactList struct {
+	ConversationID string                `json:"conversation_id"`
+	Artifacts      []interfaces.Artifact `json:"artifacts"`
+	Total          int                   `json:"total"`
+	Limit          int                   `json:"limit"`
+	Offset         int                   `json:"offset"`
+}
+
+// handleConversationArtifacts lists the artifacts produced in a conversation,
+// newest first (GET /api/v1/conversations/{id}/artifacts?org_id=...&mime_type=...&limit=...&offset=...).
+// A mime_type ending in "/" such as "image/" matches all types with that prefix.
+func (h *HTTPServer) handleConversationArtifacts(w http.ResponseWriter, r *http.Request, conversationID string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	store := h.Agent().GetArtifactStore()
+	if store == nil {
+		http.Error(w, "Agent has no artifact store configured", http.StatusNotImplemented)
+		return
+	}
+
+	limit := 100
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+	filter := storage.ArtifactFilter{
+		ThreadID: conversationID,
+		MimeType: r.URL.Query().Get("mime_type"),
+	}
+	filter.OrgID, _ = multitenancy.GetOrgID(ctx)
+
+	artifacts, err := store.List(ctx, filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list artifacts: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	list := ArtifactList{
+		ConversationID: conversationID,
+		Artifacts:      []interfaces.Artifact{},
+		Total:          len(artifacts),
+		Limit:          limit,
+		Offset:         offset,
+	}
+	if offset < len(artifacts) {
+		list.Artifacts = artifacts[offset:min(offset+limit, len(artifacts))]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(list)
+}
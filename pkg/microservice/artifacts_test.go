@@ -0,0 +1,102 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// urlBackend is a MediaStorage that only hands out URLs
+type urlBackend struct {
+	stored int
+}
+
+func (b *urlBackend) Store(ctx context.Context, media *interfaces.GeneratedMedia, metadata storage.StorageMetadata) (string, error) {
+	b.stored++
+	return fmt.Sprintf("https://files.example.com/%s/%d", metadata.ThreadID, b.stored), nil
+}
+
+func (b *urlBackend) Delete(ctx context.Context, url string) error { return nil }
+
+func (b *urlBackend) Get(ctx context.Context, url string) ([]byte, error) { return nil, nil }
+
+func (b *urlBackend) Name() string { return "url" }
+
+func TestHTTPServer_ConversationArtifacts(t *testing.T) {
+	store := storage.NewArtifactStore(&urlBackend{})
+	testAgent, err := agent.NewAgent(
+		agent.WithLLM(&MockLLM{response: "unused"}),
+		agent.WithName("TestAgent"),
+		agent.WithArtifactStore(store),
+		// Stands in for a tool saving a report
+		agent.WithCustomRunFunction(func(ctx context.Context, input string, a *agent.Agent) (string, error) {
+			store, _ := storage.ArtifactStoreFromContext(ctx)
+			if _, err := store.Save(ctx, []byte(input), interfaces.Artifact{Name: "report.txt", MimeType: "text/plain", Tool: "report"}); err != nil {
+				return "", err
+			}
+			return "saved", nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(testAgent, 8080)
+
+	req := httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(`{"input":"quarterly numbers","conversation_id":"conv-1"}`))
+	w := httptest.NewRecorder()
+	server.handleRun(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var run struct {
+		Artifacts []interfaces.Artifact `json:"artifacts"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &run); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(run.Artifacts) != 1 || run.Artifacts[0].Name != "report.txt" || run.Artifacts[0].ThreadID != "conv-1" {
+		t.Fatalf("Expected the saved artifact in the run response, got %+v", run.Artifacts)
+	}
+
+	// Artifacts of other conversations are not listed
+	if _, err := store.Save(context.Background(), []byte("x"), interfaces.Artifact{MimeType: "image/png", ThreadID: "conv-2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/conversations/conv-1/artifacts", nil)
+	w = httptest.NewRecorder()
+	server.handleConversation(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var list ArtifactList
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if list.ConversationID != "conv-1" || list.Total != 1 || list.Artifacts[0].ID != run.Artifacts[0].ID {
+		t.Errorf("Unexpected artifact list: %+v", list)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/conversations/conv-1/artifacts?mime_type=image/", nil)
+	w = httptest.NewRecorder()
+	server.handleConversation(w, req)
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || list.Total != 0 {
+		t.Errorf("Expected no image artifacts, got %+v (%v)", list, err)
+	}
+
+	// Agents without an artifact store do not expose artifacts
+	plain := NewHTTPServer(createTestAgent("unused", nil).(*MockStreamingAgent).Agent, 8080)
+	w = httptest.NewRecorder()
+	plain.handleConversation(w, httptest.NewRequest("GET", "/api/v1/conversations/conv-1/artifacts", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501 without an artifact store, got %d", w.Code)
+	}
+}
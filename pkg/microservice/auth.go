@@ -0,0 +1,326 @@
+package microservice
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// adminKeysPath is the base path of the API key management endpoints
+const adminKeysPath = "/api/v1/admin/keys"
+
+// APIKeyAuthConfig configures API key authentication for the HTTP servers
+type APIKeyAuthConfig struct {
+	// Manager authenticates the keys presented by tenants
+	Manager *multitenancy.APIKeyManager
+
+	// Tenants manages organizations (optional). When set, its key manager
+	// authenticates keys if Manager is nil, only keys of existing, enabled
+	// organizations authenticate, keys are only issued to existing
+	// organizations, organization quotas apply when quotas are enabled, and
+	// the /api/v1/admin/orgs endpoints are registered with the admin token.
+	Tenants *multitenancy.TenantManager
+
+	// AdminToken enables the /api/v1/admin/keys endpoints for operators.
+	// The endpoints are not registered when it is empty.
+	AdminToken string
+}
+
+// APIKeyRequest is the JSON body for issuing an API key
+type APIKeyRequest struct {
+	OrgID     string   `json:"org_id"`
+	Name      string   `json:"name"`
+	Endpoints []string `json:"endpoints,omitempty"`
+	Agents    []string `json:"agents,omitempty"`
+	TTLHours  int      `json:"ttl_hours,omitempty"`
+}
+
+// APIKeyResponse is the JSON response for an issued or rotated API key. The
+// secret is only returned here and cannot be retrieved again.
+type APIKeyResponse struct {
+	Key    *multitenancy.APIKey `json:"key"`
+	Secret string               `json:"secret"`
+}
+
+// EnableAPIKeyAuth requires a valid API key on every /api/ and /ws/ request.
+// Keys are read from "Authorization: Bearer <key>" or the X-API-Key header;
+// the key's organization is put in the request context and overrides any
+// org_id sent by the client. Must be called before Start.
+func (h *HTTPServer) EnableAPIKeyAuth(config APIKeyAuthConfig) {
+	if config.Manager == nil && config.Tenants != nil {
+		config.Manager = config.Tenants.Keys()
+	}
+	h.auth = &config
+}
+
+// withAuth wraps handler with API key and JWT authentication when they are
+// enabled
+func (h *HTTPServer) withAuth(handler http.Handler) http.Handler {
+	apiKeys := h.auth != nil && h.auth.Manager != nil
+	if !apiKeys && h.jwtAuth == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if isAdminPath(path) || (!strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/ws/")) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		// Operators holding the admin token have unrestricted access
+		if h.isAdminRequest(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		plaintext := apiKeyFromRequest(r)
+		if h.jwtAuth != nil && looksLikeJWT(plaintext) {
+			h.serveJWT(w, r, plaintext, handler)
+			return
+		}
+		if !apiKeys {
+			writeAuthError(w, http.StatusUnauthorized, "bearer token required")
+			return
+		}
+		if plaintext == "" {
+			writeAuthError(w, http.StatusUnauthorized, "API key required")
+			return
+		}
+
+		key, err := h.authenticateAPIKey(r.Context(), plaintext)
+		if err != nil {
+			status := http.StatusUnauthorized
+			if errors.Is(err, multitenancy.ErrOrgDisabled) {
+				status = http.StatusForbidden
+			} else if !errors.Is(err, multitenancy.ErrInvalidAPIKey) &&
+				!errors.Is(err, multitenancy.ErrAPIKeyRevoked) &&
+				!errors.Is(err, multitenancy.ErrAPIKeyExpired) {
+				status = http.StatusInternalServerError
+			}
+			writeAuthError(w, status, err.Error())
+			return
+		}
+
+		if !key.AllowsEndpoint(path) {
+			writeAuthError(w, http.StatusForbidden, "API key is not allowed to access this endpoint")
+			return
+		}
+		if !key.AllowsAgent(h.Agent().GetName()) {
+			writeAuthError(w, http.StatusForbidden, "API key is not allowed to access this agent")
+			return
+		}
+
+		ctx := multitenancy.WithAPIKey(r.Context(), key)
+		ctx = multitenancy.WithOrgID(ctx, key.OrgID)
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticateAPIKey validates a plaintext API key, with the tenant manager
+// when one is configured so that the key's organization must exist
+func (h *HTTPServer) authenticateAPIKey(ctx context.Context, plaintext string) (*multitenancy.APIKey, error) {
+	if h.auth.Tenants != nil {
+		key, _, err := h.auth.Tenants.Authenticate(ctx, plaintext)
+		return key, err
+	}
+	return h.auth.Manager.Authenticate(ctx, plaintext)
+}
+
+// withRequestOrgID applies an org ID sent by the client unless the request
+// was authenticated with an API key or JWT, whose organization always wins
+func withRequestOrgID(ctx context.Context, orgID string) context.Context {
+	if orgID == "" || isTenantAuthenticated(ctx) {
+		return ctx
+	}
+	return multitenancy.WithOrgID(ctx, orgID)
+}
+
+// isTenantAuthenticated returns true if the request was authenticated with a
+// tenant API key or JWT
+func isTenantAuthenticated(ctx context.Context) bool {
+	if _, ok := multitenancy.GetAPIKey(ctx); ok {
+		return true
+	}
+	_, ok := GetJWTClaims(ctx)
+	return ok
+}
+
+// apiKeyFromRequest extracts the API key from the request headers
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+func writeAuthError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": message,
+	})
+}
+
+// registerAdminEndpoints registers the API key and organization management
+// endpoints when an admin token is configured
+func (h *HTTPServer) registerAdminEndpoints(mux *http.ServeMux) {
+	if h.auth == nil || h.auth.Manager == nil || h.auth.AdminToken == "" {
+		return
+	}
+
+	mux.HandleFunc(adminKeysPath, h.withAdminToken(h.handleAdminKeys))
+	mux.HandleFunc(adminKeysPath+"/", h.withAdminToken(h.handleAdminKey))
+	h.registerOrgEndpoints(mux)
+}
+
+// isAdminRequest returns true if the request carries the operator admin token
+func (h *HTTPServer) isAdminRequest(r *http.Request) bool {
+	if h.auth == nil || h.auth.AdminToken == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.auth.AdminToken)) == 1
+}
+
+// withAdminToken requires the operator admin token as a bearer token
+func (h *HTTPServer) withAdminToken(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !h.isAdminRequest(r) {
+			writeAuthError(w, http.StatusUnauthorized, "admin token required")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// withoutTenantKey rejects requests authenticated with a tenant API key or
+// JWT, for endpoints that expose data across organizations
+func (h *HTTPServer) withoutTenantKey(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if isTenantAuthenticated(r.Context()) {
+			writeAuthError(w, http.StatusForbidden, "endpoint requires the admin token")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// handleAdminKeys issues keys (POST) and lists an organization's keys
+// (GET /api/v1/admin/keys?org_id=...)
+func (h *HTTPServer) handleAdminKeys(w http.ResponseWriter, r *http.Request) {
+	manager := h.auth.Manager
+
+	switch r.Method {
+	case "GET":
+		orgID := r.URL.Query().Get("org_id")
+		if orgID == "" {
+			http.Error(w, "Query parameter 'org_id' is required", http.StatusBadRequest)
+			return
+		}
+
+		keys, err := manager.ListKeys(r.Context(), orgID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to list API keys: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"org_id": orgID,
+			"keys":   keys,
+		})
+
+	case "POST":
+		var req APIKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.OrgID == "" {
+			http.Error(w, "org_id is required", http.StatusBadRequest)
+			return
+		}
+
+		options := []multitenancy.APIKeyOption{
+			multitenancy.WithAPIKeyEndpoints(req.Endpoints...),
+			multitenancy.WithAPIKeyAgents(req.Agents...),
+		}
+		if req.TTLHours > 0 {
+			options = append(options, multitenancy.WithAPIKeyTTL(time.Duration(req.TTLHours)*time.Hour))
+		}
+
+		var key *multitenancy.APIKey
+		var secret string
+		var err error
+		if h.auth.Tenants != nil {
+			key, secret, err = h.auth.Tenants.IssueKey(r.Context(), req.OrgID, req.Name, options...)
+		} else {
+			key, secret, err = manager.CreateKey(r.Context(), req.OrgID, req.Name, options...)
+		}
+		if err != nil {
+			writeAdminOrgError(w, "Failed to create API key", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(APIKeyResponse{Key: key, Secret: secret})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAdminKey revokes (DELETE /api/v1/admin/keys/{id}) or rotates
+// (POST /api/v1/admin/keys/{id}/rotate) a key
+func (h *HTTPServer) handleAdminKey(w http.ResponseWriter, r *http.Request) {
+	manager := h.auth.Manager
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, adminKeysPath+"/"), "/")
+	if id == "" {
+		http.Error(w, "Key ID is required", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case r.Method == "DELETE" && action == "":
+		if err := manager.RevokeKey(r.Context(), id); err != nil {
+			writeAdminKeyError(w, "Failed to revoke API key", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "POST" && action == "rotate":
+		key, secret, err := manager.RotateKey(r.Context(), id)
+		if err != nil {
+			writeAdminKeyError(w, "Failed to rotate API key", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(APIKeyResponse{Key: key, Secret: secret})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeAdminKeyError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, multitenancy.ErrAPIKeyNotFound):
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusNotFound)
+	case errors.Is(err, multitenancy.ErrAPIKeyRevoked):
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,177 @@
+package microservice
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func newAuthTestServer(t *testing.T) (*HTTPServer, *multitenancy.APIKeyManager, http.Handler) {
+	t.Helper()
+
+	testAgent := createTestAgent("Hello, world!", nil)
+	server := NewHTTPServer(testAgent.(*MockStreamingAgent).Agent, 8080)
+
+	manager := multitenancy.NewAPIKeyManager(nil)
+	server.EnableAPIKeyAuth(APIKeyAuthConfig{Manager: manager, AdminToken: "admin-secret"})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", server.handleHealth)
+	mux.HandleFunc("/api/v1/agent/run", server.handleRun)
+	mux.HandleFunc("/api/v1/agent/metadata", server.handleMetadata)
+	server.registerAdminEndpoints(mux)
+
+	return server, manager, server.withAuth(mux)
+}
+
+func TestAPIKeyAuth_RequiresKey(t *testing.T) {
+	_, manager, handler := newAuthTestServer(t)
+
+	// Health checks stay unauthenticated
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for /health, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/agent/metadata", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a key, got %d", w.Code)
+	}
+
+	_, secret, err := manager.CreateKey(t.Context(), "tenant-a", "test")
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/agent/metadata", nil)
+	req.Header.Set("X-API-Key", secret)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with a valid key, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/agent/metadata", nil)
+	req.Header.Set("Authorization", "Bearer "+secret+"tampered")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 with an invalid key, got %d", w.Code)
+	}
+}
+
+func TestAPIKeyAuth_Scopes(t *testing.T) {
+	_, manager, handler := newAuthTestServer(t)
+
+	_, runOnly, _ := manager.CreateKey(t.Context(), "tenant-a", "run-only",
+		multitenancy.WithAPIKeyEndpoints("/api/v1/agent/run"))
+	_, otherAgent, _ := manager.CreateKey(t.Context(), "tenant-a", "other-agent",
+		multitenancy.WithAPIKeyAgents("BillingAgent"))
+
+	tests := []struct {
+		name   string
+		key    string
+		status int
+	}{
+		{name: "endpoint not in scope", key: runOnly, status: http.StatusForbidden},
+		{name: "agent not in scope", key: otherAgent, status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/agent/metadata", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.key)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuth_KeyOrgOverridesRequest(t *testing.T) {
+	_, manager, _ := newAuthTestServer(t)
+
+	key, _, _ := manager.CreateKey(t.Context(), "tenant-a", "test")
+	ctx := multitenancy.WithOrgID(multitenancy.WithAPIKey(t.Context(), key), key.OrgID)
+
+	orgID, err := multitenancy.GetOrgID(withRequestOrgID(ctx, "tenant-b"))
+	if err != nil || orgID != "tenant-a" {
+		t.Errorf("Expected org 'tenant-a' from the API key, got %q (%v)", orgID, err)
+	}
+
+	orgID, _ = multitenancy.GetOrgID(withRequestOrgID(t.Context(), "tenant-b"))
+	if orgID != "tenant-b" {
+		t.Errorf("Expected the requested org without an API key, got %q", orgID)
+	}
+}
+
+func TestAPIKeyAuth_AdminEndpoints(t *testing.T) {
+	_, _, handler := newAuthTestServer(t)
+
+	body, _ := json.Marshal(APIKeyRequest{OrgID: "tenant-a", Name: "onboarding"})
+
+	req := httptest.NewRequest("POST", adminKeysPath, bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401 without the admin token, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("POST", adminKeysPath, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created APIKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if created.Secret == "" || created.Key.OrgID != "tenant-a" {
+		t.Fatalf("Unexpected response: %+v", created)
+	}
+
+	// The issued key authenticates against the agent endpoints
+	req = httptest.NewRequest("GET", "/api/v1/agent/metadata", nil)
+	req.Header.Set("X-API-Key", created.Secret)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with the issued key, got %d", w.Code)
+	}
+
+	// Rotating returns a new secret and invalidates the old one
+	req = httptest.NewRequest("POST", adminKeysPath+"/"+created.Key.ID+"/rotate", nil)
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for rotate, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/agent/metadata", nil)
+	req.Header.Set("X-API-Key", created.Secret)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 with the rotated-out key, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", adminKeysPath+"?org_id=tenant-a", nil)
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for list, got %d", w.Code)
+	}
+}
@@ -0,0 +1,402 @@
+package microservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+// conversationsListPath lists conversations; conversationsPath is the base
+// path of the per-conversation endpoints
+const (
+	conversationsListPath = "/api/v1/conversations"
+	conversationsPath     = conversationsListPath + "/"
+)
+
+// branchSuffix and regenerateSuffix are the suffixes of the per-conversation
+// endpoints that fork a conversation
+const (
+	branchSuffix     = "/branch"
+	regenerateSuffix = "/regenerate"
+)
+
+// jsonlContentType is the media type of JSON Lines conversation exports
+const jsonlContentType = "application/x-ndjson"
+
+// ConversationExport is the JSON body of an exported or imported conversation
+type ConversationExport struct {
+	ConversationID string               `json:"conversation_id"`
+	Messages       []interfaces.Message `json:"messages"`
+}
+
+// ConversationSummary describes a conversation in a conversation list
+type ConversationSummary struct {
+	ConversationID string `json:"conversation_id"`
+	MessageCount   int    `json:"message_count"`
+	LastMessage    string `json:"last_message,omitempty"`
+}
+
+// ConversationList is the response of the conversation list endpoint
+type ConversationList struct {
+	Conversations []ConversationSummary `json:"conversations"`
+	Total         int                   `json:"total"`
+	Limit         int                   `json:"limit"`
+	Offset        int                   `json:"offset"`
+}
+
+// ConversationRename is the body of a conversation rename request
+type ConversationRename struct {
+	ConversationID string `json:"conversation_id"`
+}
+
+// ConversationBranch is the body of a conversation branch request
+type ConversationBranch struct {
+	// ConversationID is the ID of the new conversation (generated if empty)
+	ConversationID string `json:"conversation_id,omitempty"`
+
+	// At is the index of the first message left out of the new conversation
+	At int `json:"at"`
+}
+
+// ConversationBranchResponse is the response of a conversation branch request
+type ConversationBranchResponse struct {
+	ConversationID       string `json:"conversation_id"`
+	ParentConversationID string `json:"parent_conversation_id"`
+	Messages             int    `json:"messages"`
+}
+
+// ConversationRegenerate is the body of a regenerate request. Without Input,
+// the response to the last user message is regenerated; with Input, the
+// message at index At is replaced by Input and sent again.
+type ConversationRegenerate struct {
+	// ConversationID is the ID of the new conversation (generated if empty)
+	ConversationID string `json:"conversation_id,omitempty"`
+
+	// At is the index of the edited message (required with Input)
+	At *int `json:"at,omitempty"`
+
+	// Input is the edited message
+	Input string `json:"input,omitempty"`
+}
+
+// handleConversations lists the conversations of an organization
+// (/api/v1/conversations?org_id=...&limit=...&offset=...)
+func (h *HTTPServer) handleConversations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.Agent().GetMemory().(interfaces.ConversationMemory); !ok {
+		http.Error(w, "Agent memory does not support listing conversations", http.StatusNotImplemented)
+		return
+	}
+
+	limit := 100
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+	conversationIDs, err := h.Agent().GetAllConversations(ctx)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list conversations: %v", err), http.StatusInternalServerError)
+		return
+	}
+	conversationIDs = slices.DeleteFunc(conversationIDs, isUISessionsConversation)
+	sort.Strings(conversationIDs)
+
+	list := ConversationList{
+		Conversations: []ConversationSummary{},
+		Total:         len(conversationIDs),
+		Limit:         limit,
+		Offset:        offset,
+	}
+	if offset < len(conversationIDs) {
+		end := min(offset+limit, len(conversationIDs))
+		for _, conversationID := range conversationIDs[offset:end] {
+			messages, err := h.Agent().GetConversationMessages(ctx, conversationID)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Failed to get conversation %s: %v", conversationID, err), http.StatusInternalServerError)
+				return
+			}
+
+			summary := ConversationSummary{ConversationID: conversationID, MessageCount: len(messages)}
+			if len(messages) > 0 {
+				summary.LastMessage = messages[len(messages)-1].Content
+			}
+			list.Conversations = append(list.Conversations, summary)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// handleConversation manages a conversation in the agent's memory
+// (/api/v1/conversations/{id}?org_id=...): GET exports its messages, PUT
+// replaces them, PATCH renames it and DELETE removes it. Send or request
+// application/x-ndjson (or ?format=jsonl) for one message per line. Its
+// artifacts are listed at /api/v1/conversations/{id}/artifacts, and it is
+// forked at /api/v1/conversations/{id}/branch and /regenerate.
+func (h *HTTPServer) handleConversation(w http.ResponseWriter, r *http.Request) {
+	conversationID := strings.TrimPrefix(r.URL.Path, conversationsPath)
+	if conversationID == "" {
+		h.handleConversations(w, r)
+		return
+	}
+	if id, ok := strings.CutSuffix(conversationID, artifactsSuffix); ok && id != "" && !strings.Contains(id, "/") {
+		h.handleConversationArtifacts(w, r, id)
+		return
+	}
+	if id, ok := strings.CutSuffix(conversationID, branchSuffix); ok && id != "" && !strings.Contains(id, "/") {
+		h.handleConversationBranch(w, r, id)
+		return
+	}
+	if id, ok := strings.CutSuffix(conversationID, regenerateSuffix); ok && id != "" && !strings.Contains(id, "/") {
+		h.handleConversationRegenerate(w, r, id)
+		return
+	}
+	if strings.Contains(conversationID, "/") {
+		http.Error(w, "Invalid conversation ID", http.StatusBadRequest)
+		return
+	}
+
+	if h.Agent().GetMemory() == nil {
+		http.Error(w, "Agent has no memory configured", http.StatusNotImplemented)
+		return
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+
+	switch r.Method {
+	case "GET":
+		messages, err := h.Agent().ExportConversation(ctx, conversationID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to export conversation: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if wantsJSONL(r) {
+			w.Header().Set("Content-Type", jsonlContentType)
+			_ = memory.WriteJSONL(w, messages)
+			return
+		}
+
+		if messages == nil {
+			messages = []interfaces.Message{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ConversationExport{
+			ConversationID: conversationID,
+			Messages:       messages,
+		})
+
+	case "PUT":
+		var messages []interfaces.Message
+		if strings.HasPrefix(r.Header.Get("Content-Type"), jsonlContentType) {
+			var err error
+			if messages, err = memory.ReadJSONL(r.Body); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid JSONL: %v", err), http.StatusBadRequest)
+				return
+			}
+		} else {
+			var body ConversationExport
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+				return
+			}
+			messages = body.Messages
+		}
+
+		if err := h.Agent().ImportConversation(ctx, conversationID, messages); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to import conversation: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"conversation_id": conversationID,
+			"imported":        len(messages),
+		})
+
+	case "PATCH":
+		var body ConversationRename
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if body.ConversationID == "" || strings.Contains(body.ConversationID, "/") {
+			http.Error(w, "A valid conversation_id is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.Agent().RenameConversation(ctx, conversationID, body.ConversationID); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, agent.ErrConversationExists) {
+				status = http.StatusConflict
+			}
+			http.Error(w, fmt.Sprintf("Failed to rename conversation: %v", err), status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"conversation_id":          body.ConversationID,
+			"previous_conversation_id": conversationID,
+		})
+
+	case "DELETE":
+		if err := h.Agent().DeleteConversation(ctx, conversationID); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to delete conversation: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"conversation_id": conversationID,
+			"deleted":         true,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleConversationBranch forks a conversation before a message
+// (POST /api/v1/conversations/{id}/branch)
+func (h *HTTPServer) handleConversationBranch(w http.ResponseWriter, r *http.Request, conversationID string) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Agent().GetMemory() == nil {
+		http.Error(w, "Agent has no memory configured", http.StatusNotImplemented)
+		return
+	}
+
+	var body ConversationBranch
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	if strings.Contains(body.ConversationID, "/") {
+		http.Error(w, "Invalid conversation_id", http.StatusBadRequest)
+		return
+	}
+	if body.ConversationID == "" {
+		body.ConversationID = uuid.New().String()
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+	if err := h.Agent().BranchConversation(ctx, conversationID, body.ConversationID, body.At); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to branch conversation: %v", err), branchErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(ConversationBranchResponse{
+		ConversationID:       body.ConversationID,
+		ParentConversationID: conversationID,
+		Messages:             body.At,
+	})
+}
+
+// handleConversationRegenerate runs the agent in a fork of a conversation
+// (POST /api/v1/conversations/{id}/regenerate), to regenerate its last
+// response or edit and resend one of its messages. The original conversation
+// is kept, and the response names the new one.
+func (h *HTTPServer) handleConversationRegenerate(w http.ResponseWriter, r *http.Request, conversationID string) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Agent().GetMemory() == nil {
+		http.Error(w, "Agent has no memory configured", http.StatusNotImplemented)
+		return
+	}
+
+	var body ConversationRegenerate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	if body.Input != "" && body.At == nil {
+		http.Error(w, "at is required to resend an edited message", http.StatusBadRequest)
+		return
+	}
+	if strings.Contains(body.ConversationID, "/") {
+		http.Error(w, "Invalid conversation_id", http.StatusBadRequest)
+		return
+	}
+	if body.ConversationID == "" {
+		body.ConversationID = uuid.New().String()
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+	ctx, run, ok := h.startRun(w, ctx, &StreamRequest{Input: body.Input, ConversationID: body.ConversationID}, false)
+	if !ok {
+		return
+	}
+	defer func() { h.runs.finish(run, r.Context().Err()) }()
+
+	var response *interfaces.AgentResponse
+	var err error
+	if body.Input != "" {
+		response, err = h.Agent().Resend(ctx, conversationID, body.ConversationID, *body.At, body.Input)
+	} else {
+		response, err = h.Agent().Regenerate(ctx, conversationID, body.ConversationID)
+	}
+	h.runs.finish(run, err)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(branchErrorStatus(err))
+		_ = json.NewEncoder(w).Encode(RunErrorResponse{
+			Error: err.Error(),
+			RunID: run.status.ID,
+		})
+		return
+	}
+
+	responseData := newRunResponse(response, run.status.ID)
+	responseData.ConversationID = body.ConversationID
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(responseData)
+}
+
+// branchErrorStatus returns the HTTP status of a failure to fork a conversation
+func branchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, agent.ErrConversationExists):
+		return http.StatusConflict
+	case errors.Is(err, memory.ErrMessageIndexOutOfRange):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// wantsJSONL returns true if the client asked for a JSON Lines export
+func wantsJSONL(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "jsonl" || strings.Contains(r.Header.Get("Accept"), jsonlContentType)
+}
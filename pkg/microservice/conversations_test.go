@@ -0,0 +1,237 @@
+package microservice
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+func TestHTTPServer_ConversationImportExport(t *testing.T) {
+	testAgent := createTestAgent("unused", nil)
+	server := NewHTTPServer(testAgent.(*MockStreamingAgent).Agent, 8080)
+
+	body, _ := json.Marshal(ConversationExport{Messages: []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "Hello"},
+		{Role: interfaces.MessageRoleAssistant, Content: "Hi there"},
+	}})
+	req := httptest.NewRequest("PUT", "/api/v1/conversations/conv-1", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleConversation(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for import, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// A second import replaces the conversation
+	var jsonl bytes.Buffer
+	_ = memory.WriteJSONL(&jsonl, []interfaces.Message{{Role: interfaces.MessageRoleUser, Content: "Replaced"}})
+	req = httptest.NewRequest("PUT", "/api/v1/conversations/conv-1", &jsonl)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w = httptest.NewRecorder()
+	server.handleConversation(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for JSONL import, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/conversations/conv-1", nil)
+	w = httptest.NewRecorder()
+	server.handleConversation(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for export, got %d", w.Code)
+	}
+
+	var exported ConversationExport
+	if err := json.Unmarshal(w.Body.Bytes(), &exported); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if exported.ConversationID != "conv-1" || len(exported.Messages) != 1 || exported.Messages[0].Content != "Replaced" {
+		t.Errorf("Unexpected export: %+v", exported)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/conversations/conv-1?format=jsonl", nil)
+	w = httptest.NewRecorder()
+	server.handleConversation(w, req)
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Expected JSONL content type, got %q", ct)
+	}
+	messages, err := memory.ReadJSONL(strings.NewReader(w.Body.String()))
+	if err != nil || len(messages) != 1 {
+		t.Errorf("Expected one JSONL message, got %v (%v)", messages, err)
+	}
+}
+
+func TestHTTPServer_ConversationManagement(t *testing.T) {
+	testAgent := createTestAgent("unused", nil)
+	server := NewHTTPServer(testAgent.(*MockStreamingAgent).Agent, 8080)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.handleConversation(w, req)
+		return w
+	}
+
+	for _, id := range []string{"conv-a", "conv-b"} {
+		body := `{"messages":[{"Role":"user","Content":"Hello from ` + id + `"}]}`
+		if w := do("PUT", "/api/v1/conversations/"+id, body); w.Code != http.StatusOK {
+			t.Fatalf("Failed to seed %s: %d %s", id, w.Code, w.Body.String())
+		}
+	}
+
+	listIDs := func() []string {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/v1/conversations", nil)
+		w := httptest.NewRecorder()
+		server.handleConversations(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for list, got %d", w.Code)
+		}
+		var list ConversationList
+		if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+			t.Fatalf("Failed to unmarshal list: %v", err)
+		}
+		var ids []string
+		for _, c := range list.Conversations {
+			ids = append(ids, c.ConversationID)
+			if c.MessageCount != 1 || !strings.HasPrefix(c.LastMessage, "Hello from ") {
+				t.Errorf("Unexpected summary: %+v", c)
+			}
+		}
+		return ids
+	}
+
+	if ids := listIDs(); strings.Join(ids, ",") != "conv-a,conv-b" {
+		t.Fatalf("Expected conv-a,conv-b, got %v", ids)
+	}
+
+	// Renaming onto an existing conversation conflicts
+	if w := do("PATCH", "/api/v1/conversations/conv-a", `{"conversation_id":"conv-b"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for conflicting rename, got %d", w.Code)
+	}
+
+	if w := do("PATCH", "/api/v1/conversations/conv-b", `{"conversation_id":"conv-c"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for rename, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("DELETE", "/api/v1/conversations/conv-a", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for delete, got %d", w.Code)
+	}
+
+	if ids := listIDs(); len(ids) != 1 {
+		t.Fatalf("Expected only the renamed conversation, got %v", ids)
+	}
+
+	var exported ConversationExport
+	w := do("GET", "/api/v1/conversations/conv-c", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &exported); err != nil {
+		t.Fatalf("Failed to unmarshal export: %v", err)
+	}
+	if len(exported.Messages) != 1 || exported.Messages[0].Content != "Hello from conv-b" {
+		t.Errorf("Expected the messages of conv-b under conv-c, got %+v", exported.Messages)
+	}
+}
+
+func TestHTTPServer_ConversationRegenerate(t *testing.T) {
+	testAgent := createTestAgent("Regenerated", nil)
+	server := NewHTTPServer(testAgent.(*MockStreamingAgent).Agent, 8080)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.handleConversation(w, req)
+		return w
+	}
+	export := func(id string) []interfaces.Message {
+		t.Helper()
+		var exported ConversationExport
+		if err := json.Unmarshal(do("GET", "/api/v1/conversations/"+id, "").Body.Bytes(), &exported); err != nil {
+			t.Fatalf("Failed to unmarshal export: %v", err)
+		}
+		return exported.Messages
+	}
+
+	body := `{"messages":[{"Role":"user","Content":"Tell me a joke"},{"Role":"assistant","Content":"No."}]}`
+	if w := do("PUT", "/api/v1/conversations/conv-1", body); w.Code != http.StatusOK {
+		t.Fatalf("Failed to seed conversation: %d %s", w.Code, w.Body.String())
+	}
+
+	w := do("POST", "/api/v1/conversations/conv-1/regenerate", `{"conversation_id":"conv-2"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for regenerate, got %d: %s", w.Code, w.Body.String())
+	}
+	var response RunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.Output != "Regenerated" || response.ConversationID != "conv-2" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	if messages := export("conv-2"); len(messages) != 2 || messages[0].Content != "Tell me a joke" || messages[1].Content != "Regenerated" {
+		t.Errorf("Expected the user message and the new response in the branch, got %+v", messages)
+	}
+	if messages := export("conv-1"); len(messages) != 2 || messages[1].Content != "No." {
+		t.Errorf("Expected the original conversation to be kept, got %+v", messages)
+	}
+
+	// Edit & resend
+	w = do("POST", "/api/v1/conversations/conv-1/regenerate", `{"conversation_id":"conv-3","at":0,"input":"Tell me a story"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for resend, got %d: %s", w.Code, w.Body.String())
+	}
+	if messages := export("conv-3"); len(messages) != 2 || messages[0].Content != "Tell me a story" {
+		t.Errorf("Expected the edited message in the branch, got %+v", messages)
+	}
+
+	if w := do("POST", "/api/v1/conversations/conv-1/regenerate", `{"conversation_id":"conv-2"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 when regenerating into an existing conversation, got %d", w.Code)
+	}
+	if w := do("POST", "/api/v1/conversations/conv-1/regenerate", `{"input":"Hi"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a resend without index, got %d", w.Code)
+	}
+}
+
+func TestHTTPServer_ConversationBranch(t *testing.T) {
+	testAgent := createTestAgent("unused", nil)
+	server := NewHTTPServer(testAgent.(*MockStreamingAgent).Agent, 8080)
+
+	do := func(path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.handleConversation(w, req)
+		return w
+	}
+
+	body, _ := json.Marshal(ConversationExport{Messages: []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "Hello"},
+		{Role: interfaces.MessageRoleAssistant, Content: "Hi there"},
+	}})
+	req := httptest.NewRequest("PUT", "/api/v1/conversations/conv-1", bytes.NewReader(body))
+	server.handleConversation(httptest.NewRecorder(), req)
+
+	w := do("/api/v1/conversations/conv-1/branch", `{"at":1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for branch, got %d: %s", w.Code, w.Body.String())
+	}
+	var branch ConversationBranchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &branch); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if branch.ConversationID == "" || branch.ParentConversationID != "conv-1" || branch.Messages != 1 {
+		t.Errorf("Unexpected branch: %+v", branch)
+	}
+
+	if w := do("/api/v1/conversations/conv-1/branch", `{"conversation_id":"conv-2","at":3}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an index past the messages, got %d", w.Code)
+	}
+	if w := do("/api/v1/conversations/conv-1/branch", `{"conversation_id":"`+branch.ConversationID+`","at":1}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for an existing branch, got %d", w.Code)
+	}
+}
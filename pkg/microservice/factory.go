@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"sync"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 	"github.com/Ingenimax/agent-sdk-go/pkg/grpc/pb"
 	"github.com/Ingenimax/agent-sdk-go/pkg/grpc/server"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/metrics"
+	"github.com/Ingenimax/agent-sdk-go/pkg/schedule"
 )
 
 // AgentMicroservice represents a microservice wrapping an agent
@@ -188,7 +191,6 @@ func (m *AgentMicroservice) WaitForReady(timeout time.Duration) error {
 	servingTimeout := time.Until(deadline)
 	select {
 	case <-m.servingCh:
-		fmt.Printf("Debug: Server started serving on port %d\n", m.port)
 		// Server has started serving, give it a moment to initialize
 		time.Sleep(100 * time.Millisecond)
 	case <-time.After(servingTimeout):
@@ -456,7 +458,6 @@ func (m *AgentMicroservice) testGRPCHealth() error {
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 	)
 	if err != nil {
-		fmt.Printf("Debug: Failed to create gRPC connection to localhost:%d: %v\n", m.port, err)
 		return fmt.Errorf("failed to connect: %w", err)
 	}
 	defer func() {
@@ -468,23 +469,22 @@ func (m *AgentMicroservice) testGRPCHealth() error {
 
 	// Test the standard gRPC health service
 	healthClient := grpc_health_v1.NewHealthClient(conn)
-	resp, err := healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+	_, err = healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{
 		Service: "", // Check overall server health
 	})
 
 	if err != nil {
-		fmt.Printf("Debug: Health check failed for localhost:%d: %v\n", m.port, err)
 		return err
 	}
-
-	fmt.Printf("Debug: Health check succeeded for localhost:%d, status: %v\n", m.port, resp.Status)
 	return nil
 }
 
 // MicroserviceManager manages multiple agent microservices
 type MicroserviceManager struct {
-	services map[string]*AgentMicroservice
-	mu       sync.RWMutex
+	services      map[string]*AgentMicroservice
+	mu            sync.RWMutex
+	metricsServer *http.Server
+	scheduler     *schedule.Scheduler
 }
 
 // NewMicroserviceManager creates a new microservice manager
@@ -533,7 +533,16 @@ func (mm *MicroserviceManager) StopService(name string) error {
 	return service.Stop()
 }
 
-// StartAll starts all registered services
+// SetScheduler sets a scheduler of agent runs. It starts with StartAll,
+// stops with StopAll and its jobs and run history are served by
+// MetricsHandler.
+func (mm *MicroserviceManager) SetScheduler(scheduler *schedule.Scheduler) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.scheduler = scheduler
+}
+
+// StartAll starts all registered services and the scheduler, if set
 func (mm *MicroserviceManager) StartAll() error {
 	mm.mu.RLock()
 	defer mm.mu.RUnlock()
@@ -544,14 +553,26 @@ func (mm *MicroserviceManager) StartAll() error {
 		}
 	}
 
+	if mm.scheduler != nil {
+		if err := mm.scheduler.Start(context.Background()); err != nil {
+			return fmt.Errorf("failed to start scheduler: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// StopAll stops all running services
+// StopAll stops the scheduler, all running services and the metrics server,
+// if started
 func (mm *MicroserviceManager) StopAll() error {
 	mm.mu.RLock()
 	defer mm.mu.RUnlock()
 
+	// Stop the scheduled runs first, they may call the services
+	if mm.scheduler != nil {
+		mm.scheduler.Stop()
+	}
+
 	var lastErr error
 	for name, service := range mm.services {
 		if err := service.Stop(); err != nil {
@@ -559,9 +580,69 @@ func (mm *MicroserviceManager) StopAll() error {
 		}
 	}
 
+	if mm.metricsServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := mm.metricsServer.Shutdown(ctx); err != nil {
+			lastErr = fmt.Errorf("failed to stop metrics server: %w", err)
+		}
+	}
+
 	return lastErr
 }
 
+// MetricsHandler serves the Prometheus metrics of the services' agents
+// (agents created with agent.WithMetrics(metrics.Default())) at /metrics and
+// the scheduler's jobs and run history at /api/v1/schedules/ (see
+// schedule.Scheduler.ServeHTTP)
+func (mm *MicroserviceManager) MetricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/api/v1/schedules/", http.StripPrefix("/api/v1/schedules", http.HandlerFunc(mm.serveSchedules)))
+	return mux
+}
+
+// serveSchedules serves the scheduler, set after the handler may have been
+// created
+func (mm *MicroserviceManager) serveSchedules(w http.ResponseWriter, r *http.Request) {
+	mm.mu.RLock()
+	scheduler := mm.scheduler
+	mm.mu.RUnlock()
+
+	if scheduler == nil {
+		http.Error(w, "No scheduler is configured", http.StatusNotFound)
+		return
+	}
+	scheduler.ServeHTTP(w, r)
+}
+
+// StartMetricsServer serves MetricsHandler on port in the background until
+// StopAll is called
+func (mm *MicroserviceManager) StartMetricsServer(port int) error {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	if mm.metricsServer != nil {
+		return fmt.Errorf("metrics server is already running")
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %d: %w", port, err)
+	}
+
+	server := &http.Server{
+		Handler:           mm.MetricsHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	mm.metricsServer = server
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	return nil
+}
+
 // GetService returns a service by name
 func (mm *MicroserviceManager) GetService(name string) (*AgentMicroservice, bool) {
 	mm.mu.RLock()
@@ -6,20 +6,55 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/metrics"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/queue"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
 )
 
 // HTTPServer provides HTTP/SSE endpoints for agent streaming
 type HTTPServer struct {
-	agent  *agent.Agent
+	agent  atomic.Pointer[agent.Agent]
 	port   int
 	server *http.Server
+	auth   *APIKeyAuthConfig
+
+	jwtAuth        *jwtAuthenticator
+	allowedOrigins []string
+	quotas         *QuotaConfig
+
+	sessionLimits SessionLimits
+	streamLimits  StreamLimits
+	speechToText  interfaces.SpeechToText
+	uploadStorage storage.SignedURLStorage
+	runs          runTracker
+	webhookSecret []byte
+	jobQueue      queue.Queue
+
+	// webhookAllowedHosts restricts the hosts of job webhooks
+	webhookAllowedHosts []string
+	// webhookClient delivers the job webhooks
+	webhookClient     *http.Client
+	webhookClientOnce sync.Once
+
+	drain           drainer
+	readinessChecks []readinessCheck
+
+	// uiSessionsMu serializes updates of the session records of the UI
+	uiSessionsMu sync.Mutex
+
+	// reloader rebuilds the agent when its config file changes
+	reloader *configReloader
+	// holds tracks the requests and jobs using each agent
+	holds agentHolds
 }
 
 // StreamRequest represents the JSON request for streaming
@@ -29,6 +64,49 @@ type StreamRequest struct {
 	ConversationID string            `json:"conversation_id,omitempty"`
 	Context        map[string]string `json:"context,omitempty"`
 	MaxIterations  int               `json:"max_iterations,omitempty"`
+
+	// ContentParts holds images, audio or documents sent with the input
+	ContentParts []interfaces.ContentPart `json:"content_parts,omitempty"`
+
+	// Toolsets selects the toolsets of the agent exposed to the run. The
+	// tools of every toolset are exposed when it is omitted.
+	Toolsets []string `json:"toolsets,omitempty"`
+
+	// MaxOutputTokens and StopSequences bound the LLM responses of the run,
+	// in place of the agent's (see agent.ContextWithOutputLimits)
+	MaxOutputTokens int      `json:"max_output_tokens,omitempty"`
+	StopSequences   []string `json:"stop_sequences,omitempty"`
+
+	// RunID identifies the run for the run control endpoints. A new ID is
+	// generated when empty.
+	RunID string `json:"run_id,omitempty"`
+}
+
+// withOutputLimits applies the output limits of the request to ctx
+func (r *StreamRequest) withOutputLimits(ctx context.Context) context.Context {
+	if r.MaxOutputTokens == 0 && r.StopSequences == nil {
+		return ctx
+	}
+	return agent.ContextWithOutputLimits(ctx, agent.OutputLimits{
+		MaxOutputTokens: r.MaxOutputTokens,
+		StopSequences:   r.StopSequences,
+	})
+}
+
+// validate checks that the request has input and normalizes its content parts
+func (r *StreamRequest) validate() error {
+	if r.Input == "" && len(r.ContentParts) == 0 {
+		return fmt.Errorf("Input is required")
+	}
+	if r.MaxOutputTokens < 0 {
+		return fmt.Errorf("max_output_tokens must not be negative")
+	}
+	for i := range r.ContentParts {
+		if err := r.ContentParts[i].Normalize(); err != nil {
+			return fmt.Errorf("invalid content part %d: %w", i, err)
+		}
+	}
+	return nil
 }
 
 // SSEEvent represents a Server-Sent Event
@@ -61,33 +139,96 @@ type ToolCallData struct {
 	Status    string `json:"status"`
 }
 
+// RunResponse is the response of the run endpoint
+type RunResponse struct {
+	Output           string                      `json:"output"`
+	Agent            string                      `json:"agent"`
+	ExecutionSummary interfaces.ExecutionSummary `json:"execution_summary"`
+	RunID            string                      `json:"run_id"`
+	Usage            *interfaces.TokenUsage      `json:"usage,omitempty"`
+
+	// AudioURL is the spoken response of agents with text-to-speech
+	AudioURL      string `json:"audio_url,omitempty"`
+	AudioMIMEType string `json:"audio_mime_type,omitempty"`
+
+	Artifacts []interfaces.Artifact `json:"artifacts,omitempty"`
+
+	// ConversationID is the new conversation of regenerated runs
+	ConversationID string `json:"conversation_id,omitempty"`
+}
+
+// RunErrorResponse is the response of the run endpoint when the run fails
+type RunErrorResponse struct {
+	Error string `json:"error"`
+	RunID string `json:"run_id"`
+}
+
+// HealthResponse is the response of the health endpoint
+type HealthResponse struct {
+	Status string `json:"status"`
+	Agent  string `json:"agent"`
+	Time   int64  `json:"time"`
+}
+
+// MetadataResponse is the response of the metadata endpoint
+type MetadataResponse struct {
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	SupportsStreaming bool              `json:"supports_streaming"`
+	Capabilities      []string          `json:"capabilities"`
+	Endpoints         map[string]string `json:"endpoints"`
+}
+
 // NewHTTPServer creates a new HTTP server for agent streaming
 func NewHTTPServer(agent *agent.Agent, port int) *HTTPServer {
-	return &HTTPServer{
-		agent: agent,
-		port:  port,
-	}
+	server := &HTTPServer{port: port}
+	server.agent.Store(agent)
+	return server
 }
 
-// Start starts the HTTP server
-func (h *HTTPServer) Start() error {
+// Handler returns the handler serving the endpoints of the server, with
+// CORS, authentication and quotas applied. Start serves it; it can also be
+// mounted on another server or used in tests.
+func (h *HTTPServer) Handler() http.Handler {
 	mux := http.NewServeMux()
 
-	// Add CORS middleware
-	corsHandler := h.addCORS(mux)
-
 	// Register endpoints
 	mux.HandleFunc("/health", h.handleHealth)
+	h.registerProbeEndpoints(mux)
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc(openAPIPath, h.handleOpenAPI)
 	mux.HandleFunc("/api/v1/agent/run", h.handleRun)
 	mux.HandleFunc("/api/v1/agent/stream", h.handleStream)
 	mux.HandleFunc("/api/v1/agent/metadata", h.handleMetadata)
+	mux.HandleFunc("/api/v1/agent/milestones", h.handleMilestones)
+	mux.HandleFunc(agentRunsPath, h.handleAgentRun)
+	mux.HandleFunc(agentJobsPath, h.handleJobs)
+	mux.HandleFunc(agentQueuePath, h.handleQueue)
+	mux.HandleFunc(agentQueuePath+"/", h.handleQueue)
+	mux.HandleFunc(quotaPath, h.handleQuota)
+	mux.HandleFunc(transcriptionsPath, h.handleTranscription)
+	mux.HandleFunc(uploadsPath, h.handleUpload)
+	mux.HandleFunc(conversationsListPath, h.handleConversations)
+	mux.HandleFunc(conversationsPath, h.handleConversation)
+	h.registerRunEndpoints(mux)
+	mux.HandleFunc("/ws/chat", h.handleSession)
+	mux.HandleFunc(agentWebSocketPath, h.handleSession)
+	mux.HandleFunc(realtimePath, h.handleRealtime)
+	h.registerAdminEndpoints(mux)
+	mux.HandleFunc(reloadPath, h.withoutTenantKey(h.handleReload))
 
 	// Serve static files for browser example (if they exist)
 	mux.Handle("/", http.FileServer(http.Dir("./web/")))
 
+	// Add CORS, draining, agent holding, authentication and quota middleware
+	return h.addCORS(h.withDraining(h.holdingAgent(h.withAuth(h.withQuotas(mux)))))
+}
+
+// Start starts the HTTP server
+func (h *HTTPServer) Start() error {
 	h.server = &http.Server{
 		Addr:         fmt.Sprintf(":%d", h.port),
-		Handler:      corsHandler,
+		Handler:      h.Handler(),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 15 * time.Minute, // Longer timeout for streaming
 		IdleTimeout:  60 * time.Second,
@@ -98,27 +239,61 @@ func (h *HTTPServer) Start() error {
 	fmt.Printf("  - POST /api/v1/agent/run (non-streaming)\n")
 	fmt.Printf("  - POST /api/v1/agent/stream (SSE streaming)\n")
 	fmt.Printf("  - GET /api/v1/agent/metadata\n")
-	fmt.Printf("  - GET /health\n")
+	fmt.Printf("  - GET /api/v1/agent/milestones\n")
+	fmt.Printf("  - GET /api/v1/agent/runs/{id}, POST /api/v1/agent/runs/{id}/cancel\n")
+	fmt.Printf("  - GET /api/v1/conversations\n")
+	fmt.Printf("  - GET/PUT/PATCH/DELETE /api/v1/conversations/{id}\n")
+	if h.Agent().GetArtifactStore() != nil {
+		fmt.Printf("  - GET /api/v1/conversations/{id}/artifacts\n")
+	}
+	if h.Agent().GetRunRecorder() != nil {
+		fmt.Printf("  - GET /api/v1/runs, /api/v1/runs/{id} (run audit trail)\n")
+	}
+	if h.quotas != nil {
+		fmt.Printf("  - GET /api/v1/quota\n")
+	}
+	if len(h.webhookSecret) > 0 {
+		fmt.Printf("  - POST /api/v1/agent/jobs (async with webhook callbacks)\n")
+	}
+	if h.speechToText != nil {
+		fmt.Printf("  - POST /api/v1/audio/transcriptions (speech-to-text)\n")
+	}
+	if h.uploadStorage != nil {
+		fmt.Printf("  - POST /api/v1/uploads (signed upload URLs)\n")
+	}
+	if h.reloader != nil {
+		fmt.Printf("  - POST /api/v1/agent/reload (reload agent config)\n")
+	}
+	fmt.Printf("  - GET /ws/chat, /api/v1/agent/ws (WebSocket session)\n")
+	fmt.Printf("  - GET /ws/realtime (WebSocket realtime voice session)\n")
+	fmt.Printf("  - GET /health, /livez, /readyz\n")
+	fmt.Printf("  - GET /metrics (Prometheus)\n")
+	fmt.Printf("  - GET /openapi.json (OpenAPI 3 document)\n")
 
 	return h.server.ListenAndServe()
 }
 
-// Stop stops the HTTP server
-func (h *HTTPServer) Stop(ctx context.Context) error {
-	if h.server != nil {
-		return h.server.Shutdown(ctx)
-	}
-	return nil
+// SetAllowedOrigins restricts browser access to the given origins, such as
+// "https://app.example.com". All origins are allowed by default.
+func (h *HTTPServer) SetAllowedOrigins(origins ...string) {
+	h.allowedOrigins = origins
 }
 
 // addCORS adds CORS headers to allow browser access
 func (h *HTTPServer) addCORS(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
-		w.Header().Set("Access-Control-Expose-Headers", "Content-Type")
+		if len(h.allowedOrigins) == 0 {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Add("Vary", "Origin")
+			if origin := r.Header.Get("Origin"); origin != "" && slices.Contains(h.allowedOrigins, origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-API-Key")
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Type, X-Run-ID")
 
 		// Handle preflight requests
 		if r.Method == "OPTIONS" {
@@ -138,10 +313,10 @@ func (h *HTTPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"status": "healthy",
-		"agent":  h.agent.GetName(),
-		"time":   time.Now().Unix(),
+	if err := json.NewEncoder(w).Encode(HealthResponse{
+		Status: "healthy",
+		Agent:  h.Agent().GetName(),
+		Time:   time.Now().Unix(),
 	}); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
@@ -160,27 +335,47 @@ func (h *HTTPServer) handleRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Input == "" {
-		http.Error(w, "Input is required", http.StatusBadRequest)
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.resolveStorageRefs(withRequestOrgID(r.Context(), req.OrgID), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.transcribeAudio(r.Context(), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// Build context
 	ctx := r.Context()
-	if req.OrgID != "" {
-		ctx = multitenancy.WithOrgID(ctx, req.OrgID)
+	ctx = withRequestOrgID(ctx, req.OrgID)
+	if len(req.ContentParts) > 0 {
+		ctx = interfaces.ContextWithContentParts(ctx, req.ContentParts)
+	}
+	if req.Toolsets != nil {
+		ctx = agent.ContextWithToolsets(ctx, req.Toolsets...)
 	}
+	ctx = req.withOutputLimits(ctx)
 	if req.ConversationID != "" {
 		ctx = memory.WithConversationID(ctx, req.ConversationID)
 	}
+	ctx, run, ok := h.startRun(w, ctx, &req, false)
+	if !ok {
+		return
+	}
+	defer func() { h.runs.finish(run, r.Context().Err()) }()
 
 	// Execute agent with detailed tracking
-	response, err := h.agent.RunDetailed(ctx, req.Input)
+	response, err := h.Agent().RunDetailed(ctx, req.Input)
+	h.runs.finish(run, err)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
+		_ = json.NewEncoder(w).Encode(RunErrorResponse{
+			Error: err.Error(),
+			RunID: run.status.ID,
 		})
 		return
 	}
@@ -210,19 +405,26 @@ func (h *HTTPServer) handleRun(w http.ResponseWriter, r *http.Request) {
 
 	// Return result with execution details
 	w.Header().Set("Content-Type", "application/json")
-	responseData := map[string]interface{}{
-		"output":            response.Content,
-		"agent":             response.AgentName,
-		"execution_summary": response.ExecutionSummary,
-	}
-	if response.Usage != nil {
-		responseData["usage"] = response.Usage
-	}
-	if err := json.NewEncoder(w).Encode(responseData); err != nil {
+	if err := json.NewEncoder(w).Encode(newRunResponse(response, run.status.ID)); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
 
+// newRunResponse returns the response of the run endpoint for a run result
+func newRunResponse(response *interfaces.AgentResponse, runID string) RunResponse {
+	responseData := RunResponse{
+		Output:           response.Content,
+		Agent:            response.AgentName,
+		ExecutionSummary: response.ExecutionSummary,
+		RunID:            runID,
+		Usage:            response.Usage,
+		Artifacts:        response.Artifacts,
+	}
+	responseData.AudioURL, _ = response.Metadata[agent.MetadataAudioURL].(string)
+	responseData.AudioMIMEType, _ = response.Metadata[agent.MetadataAudioMIMEType].(string)
+	return responseData
+}
+
 // handleStream provides SSE streaming endpoint
 func (h *HTTPServer) handleStream(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
@@ -237,8 +439,23 @@ func (h *HTTPServer) handleStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Input == "" {
-		http.Error(w, "Input is required", http.StatusBadRequest)
+	// Resume the stream of a run the client lost its connection to
+	if req.RunID != "" && r.Header.Get(lastEventIDHeader) != "" {
+		orgID, _ := multitenancy.GetOrgID(withRequestOrgID(r.Context(), req.OrgID))
+		h.resumeStream(w, r, req.RunID, orgID)
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.resolveStorageRefs(withRequestOrgID(r.Context(), req.OrgID), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.transcribeAudio(r.Context(), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -255,20 +472,33 @@ func (h *HTTPServer) handleStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build context
-	ctx := r.Context()
-	if req.OrgID != "" {
-		ctx = multitenancy.WithOrgID(ctx, req.OrgID)
+	// Build context. The run outlives the request, so that the client can
+	// resume its stream after losing the connection.
+	ctx := context.WithoutCancel(r.Context())
+	ctx = withRequestOrgID(ctx, req.OrgID)
+	if len(req.ContentParts) > 0 {
+		ctx = interfaces.ContextWithContentParts(ctx, req.ContentParts)
+	}
+	if req.Toolsets != nil {
+		ctx = agent.ContextWithToolsets(ctx, req.Toolsets...)
 	}
+	ctx = req.withOutputLimits(ctx)
 	if req.ConversationID != "" {
 		ctx = memory.WithConversationID(ctx, req.ConversationID)
 	}
+	ctx, run, ok := h.startRun(w, ctx, &req, true)
+	if !ok {
+		return
+	}
 
 	// Check if agent supports streaming
-	streamingAgent, ok := interface{}(h.agent).(interfaces.StreamingAgent)
+	streamingAgent, ok := interface{}(h.Agent()).(interfaces.StreamingAgent)
 	if !ok {
-		// Fall back to non-streaming execution
-		response, err := h.agent.RunDetailed(ctx, req.Input)
+		// Fall back to non-streaming execution, which cannot be resumed
+		stop := context.AfterFunc(r.Context(), run.cancel)
+		defer stop()
+		response, err := h.Agent().RunDetailed(ctx, req.Input)
+		h.runs.finish(run, err)
 		if err != nil {
 			h.sendSSEEvent(w, flusher, "error", StreamEventData{
 				Type:    "error",
@@ -312,6 +542,7 @@ func (h *HTTPServer) handleStream(w http.ResponseWriter, r *http.Request) {
 	// Start streaming
 	eventChan, err := streamingAgent.RunStream(ctx, req.Input)
 	if err != nil {
+		h.runs.finish(run, err)
 		h.sendSSEEvent(w, flusher, "error", StreamEventData{
 			Type:    "error",
 			Error:   err.Error(),
@@ -319,59 +550,64 @@ func (h *HTTPServer) handleStream(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	eventChan = watchIdle(context.Background(), eventChan, h.streamLimits.IdleTimeout, run.cancel)
+	eventChan = h.runs.track(context.Background(), run, eventChan)
+
+	// Buffer the events for resuming clients, counting the run as in flight
+	// until it ends even if the client is gone
+	h.drain.addDuring()
+	go func() {
+		defer h.drain.done()
+		h.bufferEvents(run.events, eventChan)
+	}()
 
 	// Send initial connection event
 	h.sendSSEEvent(w, flusher, "connected", StreamEventData{
 		Type: "connected",
 		Metadata: map[string]interface{}{
-			"agent": h.agent.GetName(),
+			"agent":  h.Agent().GetName(),
+			"run_id": run.status.ID,
 		},
 	})
 
 	// Stream events to client
-	eventID := 0
-	for event := range eventChan {
-		eventID++
-
-		// Convert agent event to HTTP event data
-		eventData := h.convertAgentEventToHTTPEvent(event)
-
-		// Determine event type for SSE
-		var sseEventType string
-		switch event.Type {
-		case interfaces.AgentEventContent:
-			sseEventType = "content"
-		case interfaces.AgentEventThinking:
-			sseEventType = "thinking"
-		case interfaces.AgentEventToolCall:
-			sseEventType = "tool_call"
-		case interfaces.AgentEventToolResult:
-			sseEventType = "tool_result"
-		case interfaces.AgentEventError:
-			sseEventType = "error"
-		case interfaces.AgentEventComplete:
-			sseEventType = "complete"
-			eventData.IsFinal = true
-		default:
-			sseEventType = "content"
-		}
+	h.followStream(w, r, flusher, run.events, 0)
+}
 
-		// Send SSE event
-		h.sendSSEEventWithID(w, flusher, sseEventType, eventData, strconv.Itoa(eventID))
+// handleMilestones returns the milestones reached in a conversation
+// (GET /api/v1/agent/milestones?conversation_id=...&org_id=...)
+func (h *HTTPServer) handleMilestones(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-		// Check if client disconnected
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
+	conversationID := r.URL.Query().Get("conversation_id")
+	if conversationID == "" {
+		http.Error(w, "Query parameter 'conversation_id' is required", http.StatusBadRequest)
+		return
 	}
 
-	// Send final completion event
-	h.sendSSEEvent(w, flusher, "done", StreamEventData{
-		Type:    "done",
-		IsFinal: true,
-	})
+	ctx := memory.WithConversationID(r.Context(), conversationID)
+	ctx = withRequestOrgID(ctx, r.URL.Query().Get("org_id"))
+
+	milestones, err := h.Agent().GetMilestones(ctx)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get milestones: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if milestones == nil {
+		milestones = []memory.Milestone{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"conversation_id": conversationID,
+		"milestones":      milestones,
+		"count":           len(milestones),
+	}); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
 }
 
 // handleMetadata provides agent metadata
@@ -384,22 +620,27 @@ func (h *HTTPServer) handleMetadata(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	// Check if agent supports streaming
-	_, supportsStreaming := interface{}(h.agent).(interfaces.StreamingAgent)
+	_, supportsStreaming := interface{}(h.Agent()).(interfaces.StreamingAgent)
 
-	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"name":               h.agent.GetName(),
-		"description":        h.agent.GetDescription(),
-		"supports_streaming": supportsStreaming,
-		"capabilities": []string{
+	if err := json.NewEncoder(w).Encode(MetadataResponse{
+		Name:              h.Agent().GetName(),
+		Description:       h.Agent().GetDescription(),
+		SupportsStreaming: supportsStreaming,
+		Capabilities: []string{
 			"run",
 			"stream",
 			"metadata",
+			"milestones",
 		},
-		"endpoints": map[string]string{
-			"run":      "/api/v1/agent/run",
-			"stream":   "/api/v1/agent/stream",
-			"metadata": "/api/v1/agent/metadata",
-			"health":   "/health",
+		Endpoints: map[string]string{
+			"run":        "/api/v1/agent/run",
+			"stream":     "/api/v1/agent/stream",
+			"metadata":   "/api/v1/agent/metadata",
+			"milestones": "/api/v1/agent/milestones",
+			"health":     "/health",
+			"liveness":   livezPath,
+			"readiness":  readyzPath,
+			"openapi":    openAPIPath,
 		},
 	}); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
@@ -443,8 +684,10 @@ func (h *HTTPServer) sendSSEEvent(w http.ResponseWriter, flusher http.Flusher, e
 
 // sendSSEEventWithID sends a Server-Sent Event with ID
 func (h *HTTPServer) sendSSEEventWithID(w http.ResponseWriter, flusher http.Flusher, eventType string, data StreamEventData, id string) {
-	// Add timestamp
-	data.Timestamp = time.Now().UnixMilli()
+	// Add timestamp, unless the event was buffered
+	if data.Timestamp == 0 {
+		data.Timestamp = time.Now().UnixMilli()
+	}
 
 	// Convert data to JSON
 	jsonData, err := json.Marshal(data)
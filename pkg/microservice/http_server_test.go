@@ -12,6 +12,7 @@ import (
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
 	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
 )
 
@@ -309,6 +310,82 @@ func TestHTTPServer_Run(t *testing.T) {
 	}
 }
 
+func TestHTTPServer_RunWithContentParts(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.Any()).Respond("A cat")
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(llm),
+		agent.WithName("TestAgent"),
+		agent.WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+
+	body := `{"input":"What is this?","content_parts":[{"type":"image","url":"data:image/png;base64,cG5n"}]}`
+	req := httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleRun(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	calls := llm.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 LLM call, got %d", len(calls))
+	}
+	parts := calls[0].Request.Options.ContentParts
+	if len(parts) != 1 || parts[0].MIMEType != "image/png" || string(parts[0].Data) != "png" {
+		t.Errorf("Expected the decoded image to reach the LLM, got %+v", parts)
+	}
+
+	// Invalid content parts are rejected
+	req = httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(`{"content_parts":[{"type":"image"}]}`))
+	w = httptest.NewRecorder()
+	server.handleRun(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestHTTPServer_RunWithToolsets(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.Any()).Respond("Done")
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(llm),
+		agent.WithName("TestAgent"),
+		agent.WithRequirePlanApproval(false),
+		agent.WithTools(&MockTestTool{name: "search", description: "Search the web"}),
+		agent.WithToolset("billing", &MockTestTool{name: "refund", description: "Refund a payment"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+
+	for _, tt := range []struct {
+		body     string
+		expected string
+	}{
+		{`{"input":"Help me"}`, "search refund"},
+		{`{"input":"Help me","toolsets":["billing"]}`, "search refund"},
+		{`{"input":"Help me","toolsets":[]}`, "search"},
+	} {
+		req := httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		server.handleRun(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		calls := llm.Calls()
+		if tools := strings.Join(calls[len(calls)-1].Request.Tools, " "); tools != tt.expected {
+			t.Errorf("Expected tools %q for %s, got %q", tt.expected, tt.body, tools)
+		}
+	}
+}
+
 func TestHTTPServer_Stream(t *testing.T) {
 	// Create test agent
 	testAgent := createTestAgent("Hello streaming world", nil)
@@ -439,6 +516,31 @@ func TestHTTPServer_CORS(t *testing.T) {
 	}
 }
 
+func TestHTTPServer_AllowedOrigins(t *testing.T) {
+	server := NewHTTPServer(createTestAgent("test response", nil).(*MockStreamingAgent).Agent, 8080)
+	server.SetAllowedOrigins("https://app.example.com")
+
+	handler := server.addCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("OPTIONS", "/api/v1/agent/stream", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("Expected the allowed origin, got %v", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	req = httptest.NewRequest("OPTIONS", "/api/v1/agent/stream", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+		t.Errorf("Expected no allowed origin, got %v", origin)
+	}
+}
+
 func TestStreamEventData_Conversion(t *testing.T) {
 	// Create test agent event
 	agentEvent := interfaces.AgentStreamEvent{
@@ -0,0 +1,124 @@
+package microservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/queue"
+)
+
+// agentQueuePath accepts agent runs queued for the workers of a job queue
+const agentQueuePath = "/api/v1/agent/queue"
+
+// QueueRequest is the body of a queued run
+type QueueRequest struct {
+	Input          string            `json:"input"`
+	OrgID          string            `json:"org_id,omitempty"`
+	ConversationID string            `json:"conversation_id,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+
+	// Priority is -1 (low), 0 (normal) or 1 (high)
+	Priority queue.Priority `json:"priority,omitempty"`
+
+	// MaxAttempts is how many times the run is tried before it fails
+	MaxAttempts int `json:"max_attempts,omitempty"`
+}
+
+// QueueResponse acknowledges a queued run
+type QueueResponse struct {
+	JobID string `json:"job_id"`
+
+	// StatusURL returns the job status and, once finished, its output
+	StatusURL string `json:"status_url"`
+}
+
+// SetJobQueue sets the queue that runs submitted to the queue endpoint are
+// added to. Worker pools sharing the queue run them with the agent, named
+// after the server's agent (see queue.AgentHandler), so that requests are
+// accepted without waiting for the LLM and workers scale separately.
+func (h *HTTPServer) SetJobQueue(q queue.Queue) {
+	h.jobQueue = q
+}
+
+// handleQueue queues a run (POST /api/v1/agent/queue) or returns the status
+// of a queued run (GET /api/v1/agent/queue/{job_id})
+func (h *HTTPServer) handleQueue(w http.ResponseWriter, r *http.Request) {
+	if h.jobQueue == nil {
+		http.Error(w, "Job queue is not configured", http.StatusNotImplemented)
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, agentQueuePath), "/")
+	switch {
+	case id == "" && r.Method == "POST":
+		h.enqueueRun(w, r)
+	case id != "" && !strings.Contains(id, "/") && r.Method == "GET":
+		h.queuedRunStatus(w, r, id)
+	case id != "" && strings.Contains(id, "/"):
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// enqueueRun adds a run to the job queue and returns 202 with its job ID
+func (h *HTTPServer) enqueueRun(w http.ResponseWriter, r *http.Request) {
+	var req QueueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	orgID, _ := multitenancy.GetOrgID(withRequestOrgID(r.Context(), req.OrgID))
+	id, err := h.jobQueue.Enqueue(r.Context(), queue.Job{
+		Agent:          h.Agent().GetName(),
+		Input:          req.Input,
+		OrgID:          orgID,
+		ConversationID: req.ConversationID,
+		Metadata:       req.Metadata,
+		Priority:       req.Priority,
+		MaxAttempts:    req.MaxAttempts,
+	})
+	if err != nil {
+		if errors.Is(err, queue.ErrClosed) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(QueueResponse{
+		JobID:     id,
+		StatusURL: agentQueuePath + "/" + id,
+	})
+}
+
+// queuedRunStatus returns the status of a queued run of the request's
+// organization
+func (h *HTTPServer) queuedRunStatus(w http.ResponseWriter, r *http.Request, id string) {
+	orgID, _ := multitenancy.GetOrgID(withRequestOrgID(r.Context(), r.URL.Query().Get("org_id")))
+
+	status, err := h.jobQueue.Status(r.Context(), id)
+	switch {
+	case errors.Is(err, queue.ErrJobNotFound):
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, fmt.Sprintf("Failed to get job: %v", err), http.StatusInternalServerError)
+		return
+	case status.OrgID != orgID:
+		// Do not reveal that the job exists in another organization
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
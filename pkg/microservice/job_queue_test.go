@@ -0,0 +1,76 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/queue"
+)
+
+func TestHTTPServer_Queue(t *testing.T) {
+	testAgent := createTestAgent("Hello, world!", nil).(*MockStreamingAgent).Agent
+	server := NewHTTPServer(testAgent, 8080)
+
+	w := httptest.NewRecorder()
+	server.handleQueue(w, httptest.NewRequest("POST", agentQueuePath, strings.NewReader(`{"input":"hi"}`)))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501 without a job queue, got %d", w.Code)
+	}
+
+	jobQueue := queue.NewMemoryQueue()
+	server.SetJobQueue(jobQueue)
+
+	w = httptest.NewRecorder()
+	server.handleQueue(w, httptest.NewRequest("POST", agentQueuePath, strings.NewReader(`{"input":"hi","priority":3}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid priority, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	server.handleQueue(w, httptest.NewRequest("POST", agentQueuePath, strings.NewReader(`{"input":"hi","org_id":"acme","conversation_id":"c1","priority":1}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var response QueueResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.JobID == "" || response.StatusURL != agentQueuePath+"/"+response.JobID {
+		t.Fatalf("Unexpected response %+v", response)
+	}
+
+	// A worker pool in another process would share the queue
+	pool := queue.NewWorkerPool(jobQueue, queue.AgentHandler(map[string]queue.Runner{testAgent.GetName(): testAgent}))
+	if err := pool.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start workers: %v", err)
+	}
+	defer pool.Stop()
+
+	var status queue.Status
+	deadline := time.Now().Add(5 * time.Second)
+	for status.State != queue.StateSucceeded && time.Now().Before(deadline) {
+		w = httptest.NewRecorder()
+		server.handleQueue(w, httptest.NewRequest("GET", response.StatusURL+"?org_id=acme", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("Failed to unmarshal status: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status.State != queue.StateSucceeded || status.Output != "Hello, world!" || status.Priority != queue.PriorityHigh {
+		t.Errorf("Unexpected status %+v", status)
+	}
+
+	w = httptest.NewRecorder()
+	server.handleQueue(w, httptest.NewRequest("GET", response.StatusURL+"?org_id=other", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for another organization, got %d", w.Code)
+	}
+}
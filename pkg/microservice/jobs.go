@@ -0,0 +1,390 @@
+package microservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/compliance"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+// agentJobsPath accepts agent runs that execute in the background and report
+// to a webhook
+const agentJobsPath = "/api/v1/agent/jobs"
+
+const (
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 signature of
+	// "{timestamp}.{body}", prefixed with "sha256="
+	WebhookSignatureHeader = "X-Webhook-Signature"
+
+	// WebhookTimestampHeader carries the Unix time the webhook was signed at
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+)
+
+const (
+	// webhookTimeout bounds each webhook delivery attempt
+	webhookTimeout = 10 * time.Second
+
+	// webhookAttempts is how many times the final job event is delivered
+	// before giving up
+	webhookAttempts = 3
+)
+
+// webhookChunkQueueSize is how many chunk events of a job wait for delivery
+// before further chunks are dropped
+var webhookChunkQueueSize = 64
+
+// webhookRetryDelay is the delay before the first retry of a failed webhook
+// delivery, doubled for each further retry
+var webhookRetryDelay = time.Second
+
+// Job event types
+const (
+	JobEventChunk     = "chunk"
+	JobEventCompleted = "completed"
+	JobEventFailed    = "failed"
+	JobEventCancelled = "cancelled"
+)
+
+// JobRequest is the body of a job submission: a run request plus where to
+// report the result
+type JobRequest struct {
+	StreamRequest
+
+	// WebhookURL receives the job events
+	WebhookURL string `json:"webhook_url"`
+
+	// Stream sends every stream event of the run to the webhook as a chunk
+	// event before the final event
+	Stream bool `json:"stream,omitempty"`
+}
+
+// JobResponse acknowledges a job submission
+type JobResponse struct {
+	JobID string `json:"job_id"`
+
+	// StatusURL returns the job status, and cancels the job with a POST to
+	// StatusURL + "/cancel"
+	StatusURL string `json:"status_url"`
+}
+
+// JobEvent is the body POSTed to the webhook of a job
+type JobEvent struct {
+	JobID string `json:"job_id"`
+	Type  string `json:"type"`
+
+	// Sequence orders the events of a job, starting at 1
+	Sequence int `json:"sequence"`
+
+	// Event is the stream event of a chunk
+	Event *StreamEventData `json:"event,omitempty"`
+
+	// Output is the final response of a completed job
+	Output           string                       `json:"output,omitempty"`
+	Error            string                       `json:"error,omitempty"`
+	ExecutionSummary *interfaces.ExecutionSummary `json:"execution_summary,omitempty"`
+	Usage            *interfaces.TokenUsage       `json:"usage,omitempty"`
+	Timestamp        int64                        `json:"timestamp"`
+}
+
+// SetWebhookSecret sets the secret that job webhooks are signed with. It
+// enables the job endpoint; receivers check requests with
+// VerifyWebhookSignature.
+func (h *HTTPServer) SetWebhookSecret(secret []byte) {
+	h.webhookSecret = secret
+}
+
+// SetWebhookAllowedHosts restricts job webhooks to the given hosts, host
+// names or IP addresses without port, which may be private. Without it, any
+// host is accepted, but webhooks are not delivered to loopback, private or
+// link-local addresses.
+func (h *HTTPServer) SetWebhookAllowedHosts(hosts ...string) {
+	h.webhookAllowedHosts = hosts
+}
+
+// VerifyWebhookSignature checks the signature headers of a job webhook
+// request against its body. Receivers should also reject old timestamps to
+// prevent replays.
+func VerifyWebhookSignature(secret []byte, header http.Header, body []byte) error {
+	signature, ok := strings.CutPrefix(header.Get(WebhookSignatureHeader), "sha256=")
+	if !ok {
+		return compliance.ErrInvalidSignature
+	}
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		return compliance.ErrInvalidSignature
+	}
+	return compliance.NewHMACSigner(secret).Verify(webhookSigningPayload(header.Get(WebhookTimestampHeader), body), decoded)
+}
+
+// webhookSigningPayload returns the data signed for a webhook request
+func webhookSigningPayload(timestamp string, body []byte) []byte {
+	return append([]byte(timestamp+"."), body...)
+}
+
+// handleJobs accepts a job (POST /api/v1/agent/jobs) and runs it in the
+// background, returning 202 with the job ID right away. The job ID is a run
+// ID, so the run control endpoints report and cancel jobs.
+func (h *HTTPServer) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(h.webhookSecret) == 0 {
+		http.Error(w, "Webhook secret is not configured", http.StatusNotImplemented)
+		return
+	}
+
+	var req JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := h.validateWebhookURL(req.WebhookURL); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.resolveStorageRefs(withRequestOrgID(r.Context(), req.OrgID), &req.StreamRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.transcribeAudio(r.Context(), &req.StreamRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The job outlives the request
+	ctx := context.WithoutCancel(r.Context())
+	ctx = withRequestOrgID(ctx, req.OrgID)
+	if len(req.ContentParts) > 0 {
+		ctx = interfaces.ContextWithContentParts(ctx, req.ContentParts)
+	}
+	if req.Toolsets != nil {
+		ctx = agent.ContextWithToolsets(ctx, req.Toolsets...)
+	}
+	ctx = req.withOutputLimits(ctx)
+	if req.ConversationID != "" {
+		ctx = memory.WithConversationID(ctx, req.ConversationID)
+	}
+	ctx, run, ok := h.startRun(w, ctx, &req.StreamRequest, req.Stream)
+	if !ok {
+		return
+	}
+
+	// Stop waits for accepted jobs too, and a reload keeps their agent
+	// connected until they finish
+	h.drain.addDuring()
+	release := h.holdAgent()
+	go func() {
+		defer h.drain.done()
+		defer release()
+		h.runJob(ctx, run, req)
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(JobResponse{
+		JobID:     run.status.ID,
+		StatusURL: agentRunsPath + run.status.ID,
+	})
+}
+
+// runJob executes a job and reports its events to the webhook. Chunk events
+// are delivered in the background so that a slow webhook does not hold up the
+// run; when too many are waiting, further chunks are dropped, leaving a gap in
+// the sequence.
+func (h *HTTPServer) runJob(ctx context.Context, run *trackedRun, req JobRequest) {
+	jobID := run.status.ID
+	sequence := 0
+	next := func(event JobEvent) JobEvent {
+		sequence++
+		event.JobID = jobID
+		event.Sequence = sequence
+		event.Timestamp = time.Now().UnixMilli()
+		return event
+	}
+	deliver := func(event JobEvent, attempts int) {
+		if err := h.sendWebhook(req.WebhookURL, event, attempts); err != nil {
+			log.Printf("[HTTP Server] Failed to deliver %s event of job %s: %v", event.Type, jobID, err)
+		}
+	}
+
+	final := JobEvent{Type: JobEventCompleted}
+	var delivered chan struct{}
+	var err error
+	if streamingAgent, ok := interface{}(h.Agent()).(interfaces.StreamingAgent); ok && req.Stream {
+		var events <-chan interfaces.AgentStreamEvent
+		events, err = streamingAgent.RunStream(ctx, req.Input)
+		if err == nil {
+			chunks := make(chan JobEvent, webhookChunkQueueSize)
+			delivered = make(chan struct{})
+			go func() {
+				defer close(delivered)
+				for chunk := range chunks {
+					deliver(chunk, 1)
+				}
+			}()
+
+			var output strings.Builder
+			for event := range h.runs.track(context.Background(), run, events) {
+				if event.Type == interfaces.AgentEventContent {
+					output.WriteString(event.Content)
+				}
+				if event.Type == interfaces.AgentEventError && event.Error != nil && err == nil {
+					err = event.Error
+				}
+				data := h.convertAgentEventToHTTPEvent(event)
+				data.Timestamp = event.Timestamp.UnixMilli()
+				chunk := next(JobEvent{Type: JobEventChunk, Event: &data})
+				select {
+				case chunks <- chunk:
+				default:
+					log.Printf("[HTTP Server] Webhook of job %s is behind, dropping chunk %d", jobID, chunk.Sequence)
+				}
+			}
+			final.Output = output.String()
+			close(chunks)
+		}
+	} else {
+		var response *interfaces.AgentResponse
+		response, err = h.Agent().RunDetailed(ctx, req.Input)
+		if err == nil {
+			final.Output = response.Content
+			final.ExecutionSummary = &response.ExecutionSummary
+			final.Usage = response.Usage
+		}
+	}
+	h.runs.finish(run, err)
+
+	// Deliver the queued chunks before the final event
+	if delivered != nil {
+		<-delivered
+	}
+
+	status, _ := h.runs.get(jobID, run.status.OrgID)
+	switch status.State {
+	case RunStateCancelled:
+		final = JobEvent{Type: JobEventCancelled}
+	case RunStateFailed:
+		final = JobEvent{Type: JobEventFailed, Error: status.Error}
+	}
+	deliver(next(final), webhookAttempts)
+}
+
+// sendWebhook POSTs a signed job event to webhookURL, retrying failed deliveries
+func (h *HTTPServer) sendWebhook(webhookURL string, event JobEvent, attempts int) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := compliance.NewHMACSigner(h.webhookSecret).Sign(webhookSigningPayload(timestamp, body))
+	if err != nil {
+		return err
+	}
+
+	client := h.webhookHTTPClient()
+	delay := webhookRetryDelay
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(signature))
+
+		resp, err := client.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return nil
+			}
+			err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		}
+		if attempt >= attempts {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// webhookHTTPClient returns the client delivering job webhooks. Unless
+// their host is allowed with SetWebhookAllowedHosts, it refuses to connect to
+// loopback, private and link-local addresses, checking the address dialed so
+// that neither DNS nor redirects can point a webhook at internal services.
+func (h *HTTPServer) webhookHTTPClient() *http.Client {
+	h.webhookClientOnce.Do(func() {
+		dialer := &net.Dialer{Timeout: webhookTimeout}
+		publicDialer := &net.Dialer{Timeout: webhookTimeout, Control: refuseInternalAddress}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		// Connect to the webhook itself, so that its address is checked
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, _, err := net.SplitHostPort(addr)
+			if err == nil && h.webhookHostAllowed(host) {
+				return dialer.DialContext(ctx, network, addr)
+			}
+			return publicDialer.DialContext(ctx, network, addr)
+		}
+		h.webhookClient = &http.Client{Timeout: webhookTimeout, Transport: transport}
+	})
+	return h.webhookClient
+}
+
+// webhookHostAllowed reports whether host is one of the allowed webhook hosts
+func (h *HTTPServer) webhookHostAllowed(host string) bool {
+	return slices.ContainsFunc(h.webhookAllowedHosts, func(allowed string) bool {
+		return strings.EqualFold(allowed, host)
+	})
+}
+
+// refuseInternalAddress is a net.Dialer Control function refusing
+// connections to loopback, private, link-local and unspecified addresses
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute HTTP(S) URL,
+// with an allowed host when SetWebhookAllowedHosts was called
+func (h *HTTPServer) validateWebhookURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("webhook_url is required")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook_url must be an absolute http or https URL")
+	}
+	if len(h.webhookAllowedHosts) > 0 && !h.webhookHostAllowed(u.Hostname()) {
+		return fmt.Errorf("webhook_url host is not allowed")
+	}
+	return nil
+}
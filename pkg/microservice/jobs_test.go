@@ -0,0 +1,265 @@
+package microservice
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookReceiver collects the verified job events POSTed to it
+type webhookReceiver struct {
+	secret []byte
+	mu     sync.Mutex
+	events []JobEvent
+	failed int
+	done   chan struct{}
+}
+
+func newWebhookReceiver(secret string) *webhookReceiver {
+	return &webhookReceiver{secret: []byte(secret), done: make(chan struct{})}
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if err := VerifyWebhookSignature(wr.secret, r.Header, body); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	if wr.failed > 0 {
+		wr.failed--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var event JobEvent
+	_ = json.Unmarshal(body, &event)
+	wr.events = append(wr.events, event)
+	if event.Type != JobEventChunk {
+		close(wr.done)
+	}
+}
+
+func (wr *webhookReceiver) wait(t *testing.T) []JobEvent {
+	t.Helper()
+	select {
+	case <-wr.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Job did not report a final event")
+	}
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	return wr.events
+}
+
+func submitJob(t *testing.T, server *HTTPServer, body string) JobResponse {
+	t.Helper()
+	w := httptest.NewRecorder()
+	server.handleJobs(w, httptest.NewRequest("POST", agentJobsPath, strings.NewReader(body)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var response JobResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	return response
+}
+
+func TestHTTPServer_Job(t *testing.T) {
+	webhookRetryDelay = time.Millisecond
+	receiver := newWebhookReceiver("secret")
+	receiver.failed = 1
+	webhook := httptest.NewServer(receiver)
+	defer webhook.Close()
+
+	server := NewHTTPServer(createTestAgent("Hello, world!", nil).(*MockStreamingAgent).Agent, 8080)
+
+	w := httptest.NewRecorder()
+	server.handleJobs(w, httptest.NewRequest("POST", agentJobsPath, strings.NewReader(`{"input":"hi"}`)))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501 without a webhook secret, got %d", w.Code)
+	}
+
+	server.SetWebhookSecret([]byte("secret"))
+	server.SetWebhookAllowedHosts("127.0.0.1")
+
+	w = httptest.NewRecorder()
+	server.handleJobs(w, httptest.NewRequest("POST", agentJobsPath, strings.NewReader(`{"input":"hi","webhook_url":"file:///etc/passwd"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid webhook URL, got %d", w.Code)
+	}
+
+	job := submitJob(t, server, `{"input":"hi","conversation_id":"c1","webhook_url":"`+webhook.URL+`"}`)
+	if job.JobID == "" || job.StatusURL != agentRunsPath+job.JobID {
+		t.Fatalf("Unexpected job response %+v", job)
+	}
+
+	// The final event is retried until delivered
+	events := receiver.wait(t)
+	if len(events) != 1 {
+		t.Fatalf("Expected one event, got %+v", events)
+	}
+	if events[0].JobID != job.JobID || events[0].Type != JobEventCompleted || events[0].Output != "Hello, world!" || events[0].ExecutionSummary == nil {
+		t.Errorf("Unexpected final event %+v", events[0])
+	}
+
+	if code, status := getRunStatus(t, server, job.StatusURL); code != http.StatusOK || status.State != RunStateCompleted {
+		t.Errorf("Expected a completed job, got %d %+v", code, status)
+	}
+}
+
+func TestHTTPServer_StreamingJob(t *testing.T) {
+	receiver := newWebhookReceiver("secret")
+	webhook := httptest.NewServer(receiver)
+	defer webhook.Close()
+
+	server := NewHTTPServer(createTestAgent("Hello, world!", nil).(*MockStreamingAgent).Agent, 8080)
+	server.SetWebhookSecret([]byte("secret"))
+	server.SetWebhookAllowedHosts("127.0.0.1")
+
+	job := submitJob(t, server, `{"input":"hi","conversation_id":"c1","webhook_url":"`+webhook.URL+`","stream":true,"run_id":"job-1"}`)
+	if job.JobID != "job-1" {
+		t.Errorf("Expected the requested job ID, got %q", job.JobID)
+	}
+
+	events := receiver.wait(t)
+	if len(events) < 2 {
+		t.Fatalf("Expected chunks before the final event, got %+v", events)
+	}
+	for i, event := range events {
+		if event.Sequence != i+1 {
+			t.Errorf("Expected sequence %d, got %d", i+1, event.Sequence)
+		}
+	}
+	if events[0].Type != JobEventChunk || events[0].Event == nil {
+		t.Errorf("Expected a chunk event, got %+v", events[0])
+	}
+	final := events[len(events)-1]
+	if final.Type != JobEventCompleted || strings.TrimSpace(final.Output) != "Hello, world!" {
+		t.Errorf("Unexpected final event %+v", final)
+	}
+}
+
+func TestHTTPServer_JobWebhookAddress(t *testing.T) {
+	receiver := newWebhookReceiver("secret")
+	webhook := httptest.NewServer(receiver)
+	defer webhook.Close()
+
+	server := NewHTTPServer(createTestAgent("Hello, world!", nil).(*MockStreamingAgent).Agent, 8080)
+	server.SetWebhookSecret([]byte("secret"))
+
+	// Without an allowlist, webhooks are not delivered to internal addresses
+	job := submitJob(t, server, `{"input":"hi","conversation_id":"c1","webhook_url":"`+webhook.URL+`"}`)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, status := getRunStatus(t, server, job.StatusURL); status.State == RunStateCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Job did not complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := server.sendWebhook(webhook.URL, JobEvent{JobID: job.JobID}, 1); err == nil || !strings.Contains(err.Error(), "not public") {
+		t.Errorf("Expected the loopback webhook to be refused, got %v", err)
+	}
+	receiver.mu.Lock()
+	if len(receiver.events) != 0 {
+		t.Errorf("Expected no event delivered to a loopback address, got %+v", receiver.events)
+	}
+	receiver.mu.Unlock()
+
+	// With an allowlist, other hosts are rejected
+	server.SetWebhookAllowedHosts("hooks.example.com")
+	w := httptest.NewRecorder()
+	server.handleJobs(w, httptest.NewRequest("POST", agentJobsPath, strings.NewReader(`{"input":"hi","webhook_url":"`+webhook.URL+`"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a host that is not allowed, got %d", w.Code)
+	}
+}
+
+func TestRefuseInternalAddress(t *testing.T) {
+	for address, refused := range map[string]bool{
+		"127.0.0.1:80":       true,
+		"[::1]:443":          true,
+		"10.1.2.3:80":        true,
+		"192.168.0.10:80":    true,
+		"169.254.169.254:80": true,
+		"[fe80::1]:80":       true,
+		"0.0.0.0:80":         true,
+		"93.184.216.34:443":  false,
+	} {
+		if err := refuseInternalAddress("tcp", address, nil); (err != nil) != refused {
+			t.Errorf("Expected %s refused=%v, got %v", address, refused, err)
+		}
+	}
+}
+
+func TestHTTPServer_StreamingJobDropsChunks(t *testing.T) {
+	defer func(size int) { webhookChunkQueueSize = size }(webhookChunkQueueSize)
+	webhookChunkQueueSize = 1
+
+	// The webhook holds the first chunk until the run has streamed all events
+	release := make(chan struct{})
+	receiver := newWebhookReceiver("secret")
+	var once sync.Once
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			select {
+			case <-release:
+			case <-time.After(10 * time.Second):
+			}
+		})
+		receiver.ServeHTTP(w, r)
+	}))
+	defer webhook.Close()
+
+	server := NewHTTPServer(createTestAgent("one two three four five six seven eight", nil).(*MockStreamingAgent).Agent, 8080)
+	server.SetWebhookSecret([]byte("secret"))
+	server.SetWebhookAllowedHosts("127.0.0.1")
+
+	job := submitJob(t, server, `{"input":"hi","conversation_id":"c1","webhook_url":"`+webhook.URL+`","stream":true}`)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, status := getRunStatus(t, server, job.StatusURL); status.State == RunStateCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			_, status := getRunStatus(t, server, job.StatusURL)
+			t.Fatalf("Job did not complete while its webhook was blocked: %+v", status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(release)
+
+	events := receiver.wait(t)
+	final := events[len(events)-1]
+	if final.Type != JobEventCompleted || final.Sequence <= len(events) {
+		t.Errorf("Expected chunks to be dropped before the final event, got %+v", events)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Sequence <= events[i-1].Sequence {
+			t.Errorf("Expected events in sequence order, got %+v", events)
+		}
+	}
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	header := http.Header{}
+	header.Set(WebhookTimestampHeader, "1700000000")
+	header.Set(WebhookSignatureHeader, "sha256=00")
+	if err := VerifyWebhookSignature([]byte("secret"), header, []byte(`{}`)); err == nil {
+		t.Error("Expected an invalid signature error")
+	}
+	header.Del(WebhookSignatureHeader)
+	if err := VerifyWebhookSignature([]byte("secret"), header, []byte(`{}`)); err == nil {
+		t.Error("Expected an error without a signature")
+	}
+}
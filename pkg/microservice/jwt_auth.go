@@ -0,0 +1,388 @@
+package microservice
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+const (
+	// defaultJWKSRefreshInterval is how long fetched signing keys are used
+	// before they are fetched again
+	defaultJWKSRefreshInterval = time.Hour
+
+	// jwksMinRefreshInterval limits refetches for tokens signed with unknown keys
+	jwksMinRefreshInterval = time.Minute
+)
+
+// JWTAuthConfig configures bearer token authentication with JWTs, such as
+// the access tokens of an OIDC provider
+type JWTAuthConfig struct {
+	// Issuer is the expected "iss" claim. When JWKSURL is empty, the signing
+	// keys are discovered from {Issuer}/.well-known/openid-configuration.
+	Issuer string
+
+	// JWKSURL is the URL of the JSON Web Key Set with the signing keys
+	JWKSURL string
+
+	// HMACSecret verifies HS256/HS384/HS512 tokens signed with a shared secret
+	HMACSecret []byte
+
+	// Audience is the expected "aud" claim, if set
+	Audience string
+
+	// OrgClaim is the claim holding the organization of the caller, put in the
+	// request context like the organization of an API key (default "org_id")
+	OrgClaim string
+
+	// OrgIDFromClaims maps the claims to an organization instead of OrgClaim,
+	// for providers that encode tenants differently
+	OrgIDFromClaims func(claims map[string]interface{}) (string, error)
+
+	// RouteScopes maps path prefixes to the scopes allowed to call them; a
+	// token needs one of the scopes of the longest matching prefix, read from
+	// the "scope" (space-separated) or "scp" claims. Routes without a
+	// matching prefix need no scope.
+	//
+	//	RouteScopes: map[string][]string{
+	//		"/api/v1/agent/":        {"agent:run"},
+	//		"/api/v1/conversations": {"conversations:read", "conversations:write"},
+	//	}
+	RouteScopes map[string][]string
+
+	// JWKSRefreshInterval is how long fetched keys are used (default 1 hour)
+	JWKSRefreshInterval time.Duration
+
+	// HTTPClient fetches the discovery document and the key set
+	HTTPClient *http.Client
+}
+
+// EnableJWTAuth requires a valid JWT bearer token on every /api/ and /ws/
+// request, verified with the keys of an OIDC issuer or JWKS URL, or with a
+// shared secret. It can be combined with EnableAPIKeyAuth; bearer tokens that
+// are JWTs are checked as such. The organization claim of the token is put in
+// the request context and overrides any org_id sent by the client. Must be
+// called before Start.
+func (h *HTTPServer) EnableJWTAuth(config JWTAuthConfig) error {
+	if config.Issuer == "" && config.JWKSURL == "" && len(config.HMACSecret) == 0 {
+		return fmt.Errorf("JWT auth requires an issuer, a JWKS URL or an HMAC secret")
+	}
+	if config.OrgClaim == "" {
+		config.OrgClaim = "org_id"
+	}
+	if config.JWKSRefreshInterval <= 0 {
+		config.JWKSRefreshInterval = defaultJWKSRefreshInterval
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	h.jwtAuth = &jwtAuthenticator{config: config}
+	return nil
+}
+
+type jwtClaimsKey struct{}
+
+// GetJWTClaims returns the claims of the JWT that authenticated the request,
+// if any
+func GetJWTClaims(ctx context.Context) (map[string]interface{}, bool) {
+	claims, ok := ctx.Value(jwtClaimsKey{}).(jwt.MapClaims)
+	return claims, ok
+}
+
+// serveJWT authenticates a request carrying a JWT and serves it with the
+// token's claims and organization in the context
+func (h *HTTPServer) serveJWT(w http.ResponseWriter, r *http.Request, token string, handler http.Handler) {
+	claims, err := h.jwtAuth.authenticate(r.Context(), token)
+	if err != nil {
+		writeAuthError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if scopes := h.jwtAuth.requiredScopes(r.URL.Path); len(scopes) > 0 && !hasAnyScope(claims, scopes) {
+		writeAuthError(w, http.StatusForbidden, "token is not allowed to access this endpoint")
+		return
+	}
+
+	orgID, err := h.jwtAuth.orgID(claims)
+	if err != nil {
+		writeAuthError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), jwtClaimsKey{}, claims)
+	if orgID != "" {
+		ctx = multitenancy.WithOrgID(ctx, orgID)
+	}
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// looksLikeJWT returns true if token has the three segments of a JWS
+func looksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2 && strings.HasPrefix(token, "eyJ")
+}
+
+// jwtAuthenticator verifies JWTs and caches the signing keys of the issuer
+type jwtAuthenticator struct {
+	config JWTAuthConfig
+
+	mu        sync.Mutex
+	jwksURL   string
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// authenticate verifies a token and returns its claims
+func (a *jwtAuthenticator) authenticate(ctx context.Context, token string) (jwt.MapClaims, error) {
+	var methods []string
+	if len(a.config.HMACSecret) > 0 {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if a.config.Issuer != "" || a.config.JWKSURL != "" {
+		methods = append(methods, "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512")
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if a.config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(a.config.Issuer))
+	}
+	if a.config.Audience != "" {
+		options = append(options, jwt.WithAudience(a.config.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+			return a.config.HMACSecret, nil
+		}
+		kid, _ := t.Header["kid"].(string)
+		return a.signingKey(ctx, kid)
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	return claims, nil
+}
+
+// signingKey returns the key with the given ID, fetching the key set when it
+// is stale or does not have the key
+func (a *jwtAuthenticator) signingKey(ctx context.Context, kid string) (interface{}, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	age := time.Since(a.fetchedAt)
+	key, ok := a.lookupKey(kid)
+	if age > a.config.JWKSRefreshInterval || (!ok && age > jwksMinRefreshInterval) {
+		if err := a.fetchKeys(ctx); err != nil {
+			if ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		key, ok = a.lookupKey(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// lookupKey finds a cached key; tokens without a key ID use the only key
+func (a *jwtAuthenticator) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, true
+		}
+	}
+	key, ok := a.keys[kid]
+	return key, ok
+}
+
+// fetchKeys fetches the key set, discovering its URL from the issuer first
+func (a *jwtAuthenticator) fetchKeys(ctx context.Context) error {
+	if a.jwksURL == "" {
+		a.jwksURL = a.config.JWKSURL
+	}
+	if a.jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := a.getJSON(ctx, strings.TrimSuffix(a.config.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return fmt.Errorf("failed to discover signing keys: %w", err)
+		}
+		if discovery.JWKSURI == "" {
+			return fmt.Errorf("issuer has no jwks_uri")
+		}
+		a.jwksURL = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := a.getJSON(ctx, a.jwksURL, &set); err != nil {
+		return fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Skip key types that cannot verify tokens
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	a.keys = keys
+	a.fetchedAt = time.Now()
+	return nil
+}
+
+func (a *jwtAuthenticator) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// requiredScopes returns the scopes of the longest route prefix matching path
+func (a *jwtAuthenticator) requiredScopes(path string) []string {
+	var scopes []string
+	longest := -1
+	for prefix, routeScopes := range a.config.RouteScopes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			scopes, longest = routeScopes, len(prefix)
+		}
+	}
+	return scopes
+}
+
+// orgID maps the claims of a token to an organization
+func (a *jwtAuthenticator) orgID(claims jwt.MapClaims) (string, error) {
+	if a.config.OrgIDFromClaims != nil {
+		return a.config.OrgIDFromClaims(claims)
+	}
+	switch orgID := claims[a.config.OrgClaim].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return orgID, nil
+	default:
+		return "", fmt.Errorf("claim %s is not a string", a.config.OrgClaim)
+	}
+}
+
+// hasAnyScope returns true if the token was granted one of scopes
+func hasAnyScope(claims jwt.MapClaims, scopes []string) bool {
+	granted := make(map[string]bool)
+	if scope, ok := claims["scope"].(string); ok {
+		for _, s := range strings.Fields(scope) {
+			granted[s] = true
+		}
+	}
+	switch scp := claims["scp"].(type) {
+	case string:
+		for _, s := range strings.Fields(scp) {
+			granted[s] = true
+		}
+	case []interface{}:
+		for _, s := range scp {
+			if s, ok := s.(string); ok {
+				granted[s] = true
+			}
+		}
+	}
+
+	for _, scope := range scopes {
+		if granted[scope] {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonWebKey is a public key of a JSON Web Key Set
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey decodes an RSA or EC key
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, errors.New("invalid EC key")
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
@@ -0,0 +1,159 @@
+package microservice
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// newOIDCTestIssuer serves the discovery document and key set of an issuer
+// signing with key
+func newOIDCTestIssuer(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	var issuer *httptest.Server
+	issuer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.URL, "jwks_uri": issuer.URL + "/keys"})
+		case "/keys":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestJWTAuth(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	issuer := newOIDCTestIssuer(t, key)
+
+	server := NewHTTPServer(createTestAgent("Hello, world!", nil).(*MockStreamingAgent).Agent, 8080)
+	if err := server.EnableJWTAuth(JWTAuthConfig{}); err == nil {
+		t.Error("Expected an error without a key source")
+	}
+	if err := server.EnableJWTAuth(JWTAuthConfig{
+		Issuer:      issuer.URL,
+		Audience:    "agents",
+		OrgClaim:    "tenant",
+		RouteScopes: map[string][]string{"/api/v1/agent/": {"agent:run"}, "/api/v1/agent/metadata": {"agent:read", "agent:run"}},
+	}); err != nil {
+		t.Fatalf("EnableJWTAuth failed: %v", err)
+	}
+
+	var gotOrg string
+	handler := server.withAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotOrg, _ = multitenancy.GetOrgID(withRequestOrgID(r.Context(), "spoofed"))
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	valid := jwt.MapClaims{
+		"iss":    issuer.URL,
+		"aud":    "agents",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"tenant": "tenant-a",
+		"scope":  "openid agent:read",
+	}
+	withClaims := func(changes jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		for k, v := range changes {
+			claims[k] = v
+		}
+		return claims
+	}
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"valid token", "/api/v1/agent/metadata", signTestToken(t, jwt.SigningMethodRS256, key, valid), http.StatusOK},
+		{"missing scope", "/api/v1/agent/run", signTestToken(t, jwt.SigningMethodRS256, key, valid), http.StatusForbidden},
+		{"granted scope", "/api/v1/agent/run", signTestToken(t, jwt.SigningMethodRS256, key, withClaims(jwt.MapClaims{"scp": []string{"agent:run"}})), http.StatusOK},
+		{"no token", "/api/v1/agent/metadata", "", http.StatusUnauthorized},
+		{"expired", "/api/v1/agent/metadata", signTestToken(t, jwt.SigningMethodRS256, key, withClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})), http.StatusUnauthorized},
+		{"wrong audience", "/api/v1/agent/metadata", signTestToken(t, jwt.SigningMethodRS256, key, withClaims(jwt.MapClaims{"aud": "other"})), http.StatusUnauthorized},
+		{"wrong issuer", "/api/v1/agent/metadata", signTestToken(t, jwt.SigningMethodRS256, key, withClaims(jwt.MapClaims{"iss": "https://evil.example.com"})), http.StatusUnauthorized},
+		{"wrong key", "/api/v1/agent/metadata", signTestToken(t, jwt.SigningMethodRS256, otherKey, valid), http.StatusUnauthorized},
+		{"unexpected HMAC", "/api/v1/agent/metadata", signTestToken(t, jwt.SigningMethodHS256, []byte("secret"), valid), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOrg = ""
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == http.StatusOK && gotOrg != "tenant-a" {
+				t.Errorf("Expected the token's organization to win, got %q", gotOrg)
+			}
+		})
+	}
+}
+
+func TestJWTAuth_WithAPIKeys(t *testing.T) {
+	server, manager, _ := newAuthTestServer(t)
+	if err := server.EnableJWTAuth(JWTAuthConfig{HMACSecret: []byte("secret")}); err != nil {
+		t.Fatalf("EnableJWTAuth failed: %v", err)
+	}
+	handler := server.withAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	_, apiKey, err := manager.CreateKey(t.Context(), "tenant-a", "test")
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	token := signTestToken(t, jwt.SigningMethodHS256, []byte("secret"), jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()})
+
+	for _, credential := range []string{apiKey, token} {
+		req := httptest.NewRequest("GET", "/api/v1/agent/metadata", nil)
+		req.Header.Set("Authorization", "Bearer "+credential)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package microservice
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/queue"
+)
+
+// openAPIPath serves the OpenAPI document of the server
+const openAPIPath = "/openapi.json"
+
+// OpenAPISpec returns an OpenAPI 3 document describing the run, stream,
+// metadata, run control, file and health endpoints of the server. Endpoints
+// that are not enabled, such as uploads without upload storage, are left
+// out. Schemas are generated from the request and response types.
+func (h *HTTPServer) OpenAPISpec() map[string]interface{} {
+	schemas := make(map[string]interface{})
+	ref := func(v interface{}) map[string]interface{} {
+		return openAPISchema(reflect.TypeOf(v), schemas)
+	}
+
+	errorResponse := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": description,
+			"content": map[string]interface{}{
+				"text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+			},
+		}
+	}
+	jsonResponse := func(description string, v interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"description": description,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": ref(v)},
+			},
+		}
+	}
+	jsonBody := func(v interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": ref(v)},
+			},
+		}
+	}
+	queryParam := func(name, description string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":        name,
+			"in":          "query",
+			"description": description,
+			"schema":      map[string]interface{}{"type": "string"},
+		}
+	}
+	pathParam := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		}
+	}
+	orgIDParam := queryParam("org_id", "Organization of the request when it is not set by authentication")
+
+	paths := map[string]interface{}{
+		"/health": map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "getHealth",
+				"summary":     "Check the health of the server",
+				"security":    []interface{}{},
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The server is healthy", HealthResponse{}),
+				},
+			},
+		},
+		livezPath: map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "getLiveness",
+				"summary":     "Check that the server process is alive",
+				"security":    []interface{}{},
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The server is alive", HealthResponse{}),
+				},
+			},
+		},
+		readyzPath: map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "getReadiness",
+				"summary":     "Check that the server accepts requests",
+				"security":    []interface{}{},
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The server is ready", ReadinessResponse{}),
+					"503": jsonResponse("A readiness check failed or the server is draining", ReadinessResponse{}),
+				},
+			},
+		},
+		"/api/v1/agent/run": map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": "runAgent",
+				"summary":     "Run the agent and return its response",
+				"requestBody": jsonBody(StreamRequest{}),
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The response of the agent", RunResponse{}),
+					"400": errorResponse("The request is invalid"),
+					"409": errorResponse("A run with the requested run_id already exists"),
+					"500": jsonResponse("The run failed", RunErrorResponse{}),
+				},
+			},
+		},
+		"/api/v1/agent/stream": map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": "streamAgent",
+				"summary":     "Run the agent and stream its events",
+				"description": "Streams Server-Sent Events whose data is a StreamEventData. The event names are connected, " +
+					"content, thinking, tool_call, tool_result, handoff, plan_step, error, complete and done. The run ID is in the " +
+					"X-Run-ID header and the metadata of the connected event. Send the run_id with a Last-Event-ID " +
+					"header to resume the stream of a run after a lost connection.",
+				"parameters": []interface{}{
+					map[string]interface{}{"name": lastEventIDHeader, "in": "header", "schema": map[string]interface{}{"type": "string"}},
+				},
+				"requestBody": jsonBody(StreamRequest{}),
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "A stream of agent events",
+						"content": map[string]interface{}{
+							"text/event-stream": map[string]interface{}{"schema": ref(StreamEventData{})},
+						},
+					},
+					"400": errorResponse("The request is invalid"),
+					"404": errorResponse("The stream to resume does not exist"),
+					"409": errorResponse("A run with the requested run_id already exists"),
+				},
+			},
+		},
+		"/api/v1/agent/metadata": map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "getAgentMetadata",
+				"summary":     "Describe the agent",
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The agent metadata", MetadataResponse{}),
+				},
+			},
+		},
+		agentRunsPath + "{run_id}": map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "getRun",
+				"summary":     "Get the status of a run",
+				"parameters":  []interface{}{pathParam("run_id")},
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The run status", RunStatus{}),
+					"404": errorResponse("The run does not exist"),
+				},
+			},
+		},
+		agentRunsPath + "{run_id}/cancel": map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": "cancelRun",
+				"summary":     "Cancel a running run",
+				"parameters":  []interface{}{pathParam("run_id")},
+				"responses": map[string]interface{}{
+					"202": jsonResponse("The run is being cancelled", RunStatus{}),
+					"200": jsonResponse("The run had already finished", RunStatus{}),
+					"404": errorResponse("The run does not exist"),
+				},
+			},
+		},
+		agentRunsPath + "{run_id}/events": map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "resumeStream",
+				"summary":     "Resume the stream of a run",
+				"description": "Streams the events of a streaming run after the Last-Event-ID header or last_event_id " +
+					"parameter, then follows the run until it ends.",
+				"parameters": []interface{}{
+					pathParam("run_id"),
+					map[string]interface{}{"name": lastEventIDHeader, "in": "header", "schema": map[string]interface{}{"type": "string"}},
+					map[string]interface{}{"name": "last_event_id", "in": "query", "schema": map[string]interface{}{"type": "integer"}},
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "A stream of agent events",
+						"content": map[string]interface{}{
+							"text/event-stream": map[string]interface{}{"schema": ref(StreamEventData{})},
+						},
+					},
+					"400": errorResponse("The Last-Event-ID is invalid"),
+					"404": errorResponse("The run does not exist or is not streaming"),
+				},
+			},
+		},
+	}
+
+	if h.uploadStorage != nil {
+		paths[uploadsPath] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": "createUpload",
+				"summary":     "Get a signed URL for uploading a file",
+				"description": "Upload the file to upload_url, then reference it in content_parts with its storage_ref.",
+				"parameters":  []interface{}{orgIDParam},
+				"requestBody": jsonBody(UploadRequest{}),
+				"responses": map[string]interface{}{
+					"200": jsonResponse("Where to upload the file", UploadResponse{}),
+					"400": errorResponse("The request is invalid"),
+				},
+			},
+		}
+	}
+	if h.jobQueue != nil {
+		paths[agentQueuePath] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": "queueRun",
+				"summary":     "Queue a run for the workers of the job queue",
+				"requestBody": jsonBody(QueueRequest{}),
+				"responses": map[string]interface{}{
+					"202": jsonResponse("The run is queued", QueueResponse{}),
+					"400": errorResponse("The request is invalid"),
+				},
+			},
+		}
+		paths[agentQueuePath+"/{job_id}"] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "getQueuedRun",
+				"summary":     "Get the status and output of a queued run",
+				"parameters":  []interface{}{pathParam("job_id"), orgIDParam},
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The job status", queue.Status{}),
+					"404": errorResponse("The job does not exist"),
+				},
+			},
+		}
+	}
+	if h.Agent().GetMemory() != nil {
+		paths[conversationsPath+"{conversation_id}"+branchSuffix] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": "branchConversation",
+				"summary":     "Fork a conversation before a message",
+				"parameters":  []interface{}{pathParam("conversation_id"), orgIDParam},
+				"requestBody": jsonBody(ConversationBranch{}),
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The new conversation", ConversationBranchResponse{}),
+					"400": errorResponse("The request or message index is invalid"),
+					"409": errorResponse("The new conversation already exists"),
+				},
+			},
+		}
+		paths[conversationsPath+"{conversation_id}"+regenerateSuffix] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": "regenerateConversation",
+				"summary":     "Regenerate the last response, or edit and resend a message, in a fork of a conversation",
+				"description": "Without input, the last user message is sent again. With input and at, the message at index at " +
+					"is replaced by input. The original conversation is kept; the response names the new one.",
+				"parameters":  []interface{}{pathParam("conversation_id"), orgIDParam},
+				"requestBody": jsonBody(ConversationRegenerate{}),
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The response of the agent", RunResponse{}),
+					"400": jsonResponse("The request or message index is invalid", RunErrorResponse{}),
+					"409": jsonResponse("The new conversation already exists", RunErrorResponse{}),
+					"500": jsonResponse("The run failed", RunErrorResponse{}),
+				},
+			},
+		}
+	}
+	if h.Agent().GetArtifactStore() != nil {
+		paths[conversationsPath+"{conversation_id}"+artifactsSuffix] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "listArtifacts",
+				"summary":     "List the files produced in a conversation, newest first",
+				"parameters": []interface{}{
+					pathParam("conversation_id"),
+					orgIDParam,
+					queryParam("mime_type", "Only list files of this type, or of all types with this prefix when it ends in /"),
+					queryParam("limit", "Maximum number of files to list (default 100)"),
+					queryParam("offset", "Number of files to skip"),
+				},
+				"responses": map[string]interface{}{
+					"200": jsonResponse("The files of the conversation", ArtifactList{}),
+				},
+			},
+		}
+	}
+
+	spec := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       h.Agent().GetName() + " API",
+			"description": h.Agent().GetDescription(),
+			"version":     "1.0.0",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+
+	if h.auth != nil || h.jwtAuth != nil {
+		components := spec["components"].(map[string]interface{})
+		components["securitySchemes"] = map[string]interface{}{
+			"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			"apiKeyAuth": map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+		}
+		spec["security"] = []interface{}{
+			map[string]interface{}{"bearerAuth": []string{}},
+			map[string]interface{}{"apiKeyAuth": []string{}},
+		}
+	}
+
+	return spec
+}
+
+// handleOpenAPI serves the OpenAPI document of the server (GET /openapi.json)
+func (h *HTTPServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.OpenAPISpec()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// openAPISchema returns the schema of t. Named struct types are added to
+// schemas and referenced.
+func openAPISchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		if _, ok := schemas[t.Name()]; !ok {
+			// Register the name first so recursive types terminate
+			schemas[t.Name()] = nil
+			schemas[t.Name()] = openAPIObjectSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": openAPISchema(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": openAPISchema(t.Elem(), schemas)}
+	case reflect.Struct:
+		return openAPIObjectSchema(t, schemas)
+	default:
+		// Interfaces can hold any value
+		return map[string]interface{}{}
+	}
+}
+
+// openAPIObjectSchema returns the object schema of a struct type following
+// its JSON encoding. Fields without omitempty are required.
+func openAPIObjectSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" || !field.IsExported() {
+				continue
+			}
+			name, options, _ := strings.Cut(tag, ",")
+			if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+				addFields(field.Type)
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			properties[name] = openAPISchema(field.Type, schemas)
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	addFields(t)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
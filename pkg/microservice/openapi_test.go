@@ -0,0 +1,86 @@
+package microservice
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestHTTPServer_OpenAPI(t *testing.T) {
+	server := NewHTTPServer(createTestAgent("test response", nil).(*MockStreamingAgent).Agent, 8080)
+
+	w := httptest.NewRecorder()
+	server.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var spec struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Info       map[string]string                            `json:"info"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]interface{} `json:"properties"`
+				Required   []string               `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+		Security []interface{} `json:"security"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("Failed to unmarshal the document: %v", err)
+	}
+
+	if !strings.HasPrefix(spec.OpenAPI, "3.") || spec.Info["title"] != "TestAgent API" {
+		t.Errorf("Unexpected document header %q %v", spec.OpenAPI, spec.Info)
+	}
+	for _, path := range []string{"/health", "/livez", "/readyz", "/api/v1/agent/run", "/api/v1/agent/stream", "/api/v1/agent/metadata", "/api/v1/agent/runs/{run_id}"} {
+		if _, ok := spec.Paths[path]; !ok {
+			t.Errorf("Expected path %s", path)
+		}
+	}
+	if _, ok := spec.Paths[uploadsPath]; ok {
+		t.Error("Expected no upload path without upload storage")
+	}
+	if spec.Security != nil {
+		t.Error("Expected no security requirement without authentication")
+	}
+
+	// Every schema reference resolves
+	for _, ref := range strings.Split(w.Body.String(), `"$ref":"#/components/schemas/`)[1:] {
+		name := ref[:strings.Index(ref, `"`)]
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("Unresolved schema reference %s", name)
+		}
+	}
+
+	request := spec.Components.Schemas["StreamRequest"]
+	if _, ok := request.Properties["content_parts"]; !ok || len(request.Required) != 1 || request.Required[0] != "input" {
+		t.Errorf("Unexpected StreamRequest schema %+v", request)
+	}
+	if _, ok := spec.Components.Schemas["RunResponse"].Properties["execution_summary"]; !ok {
+		t.Errorf("Expected execution_summary in RunResponse, got %+v", spec.Components.Schemas["RunResponse"])
+	}
+	if _, ok := spec.Components.Schemas["ContentPart"].Properties["storage_ref"]; !ok {
+		t.Error("Expected the ContentPart schema")
+	}
+}
+
+func TestHTTPServer_OpenAPIOptionalEndpoints(t *testing.T) {
+	server := NewHTTPServer(createTestAgent("test response", nil).(*MockStreamingAgent).Agent, 8080)
+	server.SetUploadStorage(&signedBackend{})
+	server.EnableAPIKeyAuth(APIKeyAuthConfig{Manager: multitenancy.NewAPIKeyManager(nil)})
+
+	spec := server.OpenAPISpec()
+	paths := spec["paths"].(map[string]interface{})
+	if _, ok := paths[uploadsPath]; !ok {
+		t.Error("Expected the upload path with upload storage")
+	}
+	if spec["security"] == nil {
+		t.Error("Expected security requirements with authentication")
+	}
+}
@@ -0,0 +1,253 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// livezPath reports whether the process is alive (Kubernetes liveness probe)
+	livezPath = "/livez"
+
+	// readyzPath reports whether the server accepts requests (Kubernetes
+	// readiness probe)
+	readyzPath = "/readyz"
+)
+
+// readinessCheckTimeout bounds each readiness check
+const readinessCheckTimeout = 5 * time.Second
+
+// cancelledRunGrace is how long Stop lets cancelled runs end their streams
+// before closing their connections
+const cancelledRunGrace = 2 * time.Second
+
+// ReadinessResponse is the response of the readiness endpoint
+type ReadinessResponse struct {
+	// Status is "ready", "not_ready" when a check fails, or "draining" once
+	// the server is stopping
+	Status string `json:"status"`
+
+	// Checks holds the errors of the failed readiness checks
+	Checks map[string]string `json:"checks,omitempty"`
+
+	// InFlight is the number of requests and jobs being served
+	InFlight int `json:"in_flight"`
+}
+
+// readinessCheck is a named check of a dependency of the server
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// drainer tracks in-flight requests and jobs so that the server can stop
+// once they are done. The zero value is ready to use.
+type drainer struct {
+	mu       sync.Mutex
+	active   int
+	draining bool
+	idle     chan struct{} // closed when draining and nothing is in flight
+}
+
+// add registers an in-flight request or job; it returns false once the
+// server is draining
+func (d *drainer) add() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.active++
+	return true
+}
+
+// addDuring registers work started by a request that is already in flight,
+// such as a background job, even while draining
+func (d *drainer) addDuring() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.active++
+}
+
+// done unregisters an in-flight request or job
+func (d *drainer) done() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.active--
+	if d.active == 0 && d.idle != nil {
+		close(d.idle)
+		d.idle = nil
+	}
+}
+
+// inFlight returns the number of in-flight requests and jobs, and whether
+// the server is draining
+func (d *drainer) inFlight() (int, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.active, d.draining
+}
+
+// drain stops accepting new requests and waits until nothing is in flight
+// or ctx is done
+func (d *drainer) drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	if d.active == 0 {
+		d.mu.Unlock()
+		return nil
+	}
+	if d.idle == nil {
+		d.idle = make(chan struct{})
+	}
+	idle := d.idle
+	d.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AddReadinessCheck adds a check of a dependency, such as a database or an
+// MCP server, to the readiness endpoint. The server is not ready while a
+// check fails. Must be called before Start.
+func (h *HTTPServer) AddReadinessCheck(name string, check func(ctx context.Context) error) {
+	h.readinessChecks = append(h.readinessChecks, readinessCheck{name: name, check: check})
+}
+
+// InFlight returns the number of requests and background jobs being served
+func (h *HTTPServer) InFlight() int {
+	active, _ := h.drain.inFlight()
+	return active
+}
+
+// Stop stops the server gracefully. The readiness endpoint starts failing
+// and new API requests are refused, while in-flight requests, SSE streams
+// and jobs run to completion. Runs still active when ctx is done are
+// cancelled, and their connections closed.
+func (h *HTTPServer) Stop(ctx context.Context) error {
+	err := h.drain.drain(ctx)
+	if err != nil {
+		remaining := h.InFlight()
+		h.runs.cancelAll()
+		graceCtx, cancel := context.WithTimeout(context.Background(), cancelledRunGrace)
+		_ = h.drain.drain(graceCtx)
+		cancel()
+		if h.server != nil {
+			_ = h.server.Close()
+		}
+		return fmt.Errorf("failed to drain %d in-flight requests: %w", remaining, err)
+	}
+	if h.server != nil {
+		return h.server.Shutdown(ctx)
+	}
+	return nil
+}
+
+// withDraining counts in-flight API requests and refuses new ones once the
+// server is stopping, so clients retry on another replica
+func (h *HTTPServer) withDraining(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if !strings.HasPrefix(path, "/api/") || isWebSocketPath(path) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		if !h.drain.add() {
+			w.Header().Set("Connection", "close")
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		defer h.drain.done()
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// isWebSocketPath reports whether path serves long-lived WebSocket sessions,
+// which are not waited for when draining
+func isWebSocketPath(path string) bool {
+	return strings.HasPrefix(path, "/ws/") || path == agentWebSocketPath
+}
+
+// registerProbeEndpoints registers the liveness and readiness endpoints
+func (h *HTTPServer) registerProbeEndpoints(mux *http.ServeMux) {
+	mux.HandleFunc(livezPath, h.handleLivez)
+	mux.HandleFunc(readyzPath, h.handleReadyz)
+}
+
+// handleLivez reports that the process is alive, including while draining
+func (h *HTTPServer) handleLivez(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(HealthResponse{
+		Status: "alive",
+		Agent:  h.Agent().GetName(),
+		Time:   time.Now().Unix(),
+	})
+}
+
+// handleReadyz reports whether the server accepts requests: it is not
+// draining and its readiness checks pass
+func (h *HTTPServer) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	active, draining := h.drain.inFlight()
+	response := ReadinessResponse{Status: "ready", InFlight: active}
+	if draining {
+		response.Status = "draining"
+	} else if failed := h.runReadinessChecks(r.Context()); len(failed) > 0 {
+		response.Status = "not_ready"
+		response.Checks = failed
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if response.Status != "ready" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// runReadinessChecks runs the readiness checks concurrently and returns the
+// errors of the failed ones
+func (h *HTTPServer) runReadinessChecks(ctx context.Context) map[string]string {
+	if len(h.readinessChecks) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failed := make(map[string]string)
+	for _, check := range h.readinessChecks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := check.check(ctx); err != nil {
+				mu.Lock()
+				failed[check.name] = err.Error()
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return failed
+}
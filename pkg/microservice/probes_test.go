@@ -0,0 +1,189 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+)
+
+func getReadiness(t *testing.T, handler http.Handler) (int, ReadinessResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", readyzPath, nil))
+	var response ReadinessResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal readiness: %v", err)
+	}
+	return w.Code, response
+}
+
+// newBlockingServer returns a server whose runs block until release is
+// closed or they are cancelled
+func newBlockingServer(t *testing.T) (*HTTPServer, chan struct{}, chan struct{}) {
+	t.Helper()
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(&MockLLM{response: "unused"}),
+		agent.WithName("TestAgent"),
+		agent.WithCustomRunFunction(func(ctx context.Context, input string, a *agent.Agent) (string, error) {
+			started <- struct{}{}
+			select {
+			case <-release:
+				return "finished", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	return NewHTTPServer(agentInstance, 8080), started, release
+}
+
+func TestHTTPServer_Probes(t *testing.T) {
+	server := NewHTTPServer(createTestAgent("test response", nil).(*MockStreamingAgent).Agent, 8080)
+	handler := server.Handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", livezPath, nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 from %s, got %d", livezPath, w.Code)
+	}
+
+	if code, response := getReadiness(t, handler); code != http.StatusOK || response.Status != "ready" {
+		t.Errorf("Expected the server to be ready, got %d %+v", code, response)
+	}
+
+	healthy := false
+	server.AddReadinessCheck("database", func(ctx context.Context) error {
+		if !healthy {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	code, response := getReadiness(t, handler)
+	if code != http.StatusServiceUnavailable || response.Status != "not_ready" {
+		t.Errorf("Expected the server not to be ready, got %d %+v", code, response)
+	}
+	if response.Checks["database"] != "connection refused" {
+		t.Errorf("Expected the failed check to be reported, got %v", response.Checks)
+	}
+
+	healthy = true
+	if code, _ := getReadiness(t, handler); code != http.StatusOK {
+		t.Errorf("Expected status 200 once the check passes, got %d", code)
+	}
+}
+
+func TestHTTPServer_StopDrainsRequests(t *testing.T) {
+	server, started, release := newBlockingServer(t)
+	handler := server.Handler()
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(`{"input":"long job"}`)))
+		done <- w
+	}()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not start")
+	}
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- server.Stop(context.Background())
+	}()
+
+	// The server stops taking traffic but keeps serving the run
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		code, response := getReadiness(t, handler)
+		if code == http.StatusServiceUnavailable && response.Status == "draining" {
+			if response.InFlight != 1 {
+				t.Errorf("Expected 1 in-flight request, got %d", response.InFlight)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the server to be draining, got %d %+v", code, response)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(`{"input":"another job"}`)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 for a new request while draining, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected a Retry-After header while draining")
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", livezPath, nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the server to stay alive while draining, got %d", w.Code)
+	}
+
+	select {
+	case err := <-stopped:
+		t.Fatalf("Stop returned before the run finished: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if run := <-done; run.Code != http.StatusOK {
+		t.Errorf("Expected the in-flight run to succeed, got %d: %s", run.Code, run.Body.String())
+	}
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("Stop() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return once the run finished")
+	}
+}
+
+func TestHTTPServer_StopCancelsRunsOnTimeout(t *testing.T) {
+	server, started, _ := newBlockingServer(t)
+	handler := server.Handler()
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(`{"input":"stuck job"}`)))
+		done <- w
+	}()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := server.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Stop to report the deadline, got %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the run to be cancelled")
+	}
+	if inFlight := server.InFlight(); inFlight != 0 {
+		t.Errorf("Expected no in-flight requests, got %d", inFlight)
+	}
+}
@@ -0,0 +1,207 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// quotaPath reports the quota usage of an organization
+const quotaPath = "/api/v1/quota"
+
+// QuotaLimits are the limits of an organization; zero means unlimited
+type QuotaLimits = multitenancy.QuotaLimits
+
+// QuotaConfig configures per-organization quotas
+type QuotaConfig struct {
+	// Store keeps the counters; share a Redis store between replicas
+	// (default: in memory)
+	Store multitenancy.QuotaStore
+
+	// Default are the limits of organizations without OrgLimits
+	Default QuotaLimits
+
+	// OrgLimits overrides the limits of specific organizations. The quotas
+	// of organizations managed by the tenant manager of APIKeyAuthConfig take
+	// precedence.
+	OrgLimits map[string]QuotaLimits
+}
+
+// QuotaWindow is the usage of one limit
+type QuotaWindow struct {
+	Limit   int64     `json:"limit"`
+	Used    int64     `json:"used"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// QuotaUsage is the current quota usage of an organization
+type QuotaUsage struct {
+	OrgID             string       `json:"org_id"`
+	RequestsPerMinute *QuotaWindow `json:"requests_per_minute,omitempty"`
+	TokensPerDay      *QuotaWindow `json:"tokens_per_day,omitempty"`
+}
+
+// EnableQuotas enforces requests per minute and tokens per day for each
+// organization on /api/ and /ws/ requests. Requests over a limit get 429 Too
+// Many Requests with a Retry-After header. The organization is the one of
+// the API key or token, or the org_id query parameter otherwise. Must be
+// called before Start.
+func (h *HTTPServer) EnableQuotas(config QuotaConfig) {
+	if config.Store == nil {
+		config.Store = multitenancy.NewMemoryQuotaStore()
+	}
+	h.quotas = &config
+}
+
+// quotaLimits returns the limits of an organization: its quota in the
+// tenant manager, if any, or those of the quota configuration
+func (h *HTTPServer) quotaLimits(ctx context.Context, orgID string) QuotaLimits {
+	if h.auth != nil && h.auth.Tenants != nil {
+		org, err := h.auth.Tenants.GetOrg(ctx, orgID)
+		if err == nil && org.Quota != nil {
+			return *org.Quota
+		}
+	}
+	return h.quotas.limits(orgID)
+}
+
+// limits returns the limits of an organization
+func (c *QuotaConfig) limits(orgID string) QuotaLimits {
+	if limits, ok := c.OrgLimits[orgID]; ok {
+		return limits
+	}
+	return c.Default
+}
+
+// quotaOrgID returns the organization that a request counts against
+func quotaOrgID(r *http.Request) string {
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+	if orgID, err := multitenancy.GetOrgID(ctx); err == nil && orgID != "" {
+		return orgID
+	}
+	return "default"
+}
+
+// quotaKeys returns the counter keys of an organization and when they reset.
+// The organization ID is escaped, so that an ID containing ":" cannot share
+// the counters of another organization.
+func quotaKeys(orgID string, now time.Time) (requestsKey string, minuteEnd time.Time, tokensKey string, dayEnd time.Time) {
+	now = now.UTC()
+	minuteEnd = now.Truncate(time.Minute).Add(time.Minute)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	segment := multitenancy.EscapeKeySegment(orgID)
+	return fmt.Sprintf("%s:requests:%d", segment, minuteEnd.Unix()), minuteEnd,
+		fmt.Sprintf("%s:tokens:%s", segment, day.Format("2006-01-02")), day.AddDate(0, 0, 1)
+}
+
+// withQuotas wraps handler with quota enforcement when it is enabled. Token
+// usage is counted when the runs of a request end.
+func (h *HTTPServer) withQuotas(handler http.Handler) http.Handler {
+	if h.quotas == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if path == quotaPath || isAdminPath(path) || (!strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/ws/")) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		store := h.quotas.Store
+		orgID := quotaOrgID(r)
+		limits := h.quotaLimits(r.Context(), orgID)
+		now := time.Now()
+		requestsKey, minuteEnd, tokensKey, dayEnd := quotaKeys(orgID, now)
+
+		// Counter failures let requests through rather than taking the API down
+		if limits.TokensPerDay > 0 {
+			used, err := store.Get(r.Context(), tokensKey)
+			if err != nil {
+				log.Printf("[HTTP Server] Quota check failed for %s: %v", orgID, err)
+			} else if used >= limits.TokensPerDay {
+				writeQuotaError(w, dayEnd.Sub(now), fmt.Sprintf("Token quota of %d tokens per day exceeded", limits.TokensPerDay))
+				return
+			}
+		}
+		if limits.RequestsPerMinute > 0 {
+			count, err := store.Increment(r.Context(), requestsKey, 1, minuteEnd)
+			if err != nil {
+				log.Printf("[HTTP Server] Quota check failed for %s: %v", orgID, err)
+			} else {
+				w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(limits.RequestsPerMinute, 10))
+				remaining := limits.RequestsPerMinute - count
+				if remaining < 0 {
+					remaining = 0
+				}
+				w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+				if count > limits.RequestsPerMinute {
+					writeQuotaError(w, minuteEnd.Sub(now), fmt.Sprintf("Rate limit of %d requests per minute exceeded", limits.RequestsPerMinute))
+					return
+				}
+			}
+		}
+
+		ctx := agent.WithUsageObserver(r.Context(), func(ctx context.Context, usage interfaces.TokenUsage) {
+			// Runs can end on a later day than they started
+			_, _, tokensKey, dayEnd := quotaKeys(orgID, time.Now())
+			if _, err := store.Increment(context.WithoutCancel(ctx), tokensKey, int64(usage.TotalTokens), dayEnd); err != nil {
+				log.Printf("[HTTP Server] Failed to record token usage for %s: %v", orgID, err)
+			}
+		})
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func writeQuotaError(w http.ResponseWriter, retryAfter time.Duration, message string) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	writeAuthError(w, http.StatusTooManyRequests, message)
+}
+
+// handleQuota returns the quota usage of the caller's organization
+// (GET /api/v1/quota?org_id=...)
+func (h *HTTPServer) handleQuota(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.quotas == nil {
+		http.Error(w, "Quotas are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	orgID := quotaOrgID(r)
+	limits := h.quotaLimits(r.Context(), orgID)
+	requestsKey, minuteEnd, tokensKey, dayEnd := quotaKeys(orgID, time.Now())
+
+	usage := QuotaUsage{OrgID: orgID}
+	if limits.RequestsPerMinute > 0 {
+		used, err := h.quotas.Store.Get(r.Context(), requestsKey)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get quota usage: %v", err), http.StatusInternalServerError)
+			return
+		}
+		usage.RequestsPerMinute = &QuotaWindow{Limit: limits.RequestsPerMinute, Used: used, ResetAt: minuteEnd}
+	}
+	if limits.TokensPerDay > 0 {
+		used, err := h.quotas.Store.Get(r.Context(), tokensKey)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get quota usage: %v", err), http.StatusInternalServerError)
+			return
+		}
+		usage.TokensPerDay = &QuotaWindow{Limit: limits.TokensPerDay, Used: used, ResetAt: dayEnd}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(usage)
+}
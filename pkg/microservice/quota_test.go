@@ -0,0 +1,84 @@
+package microservice
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuotas(t *testing.T) {
+	server := NewHTTPServer(createTestAgent("Hello, world!", nil).(*MockStreamingAgent).Agent, 8080)
+	server.EnableQuotas(QuotaConfig{
+		Default:   QuotaLimits{RequestsPerMinute: 2},
+		OrgLimits: map[string]QuotaLimits{"org-tokens": {TokensPerDay: 100}},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/agent/run", server.handleRun)
+	mux.HandleFunc(quotaPath, server.handleQuota)
+	handler := server.withQuotas(mux)
+
+	run := func(orgID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/agent/run?org_id="+orgID, strings.NewReader(`{"input":"hi","conversation_id":"c1"}`)))
+		return w
+	}
+
+	// Keep the requests in the same minute window
+	if time.Until(time.Now().Truncate(time.Minute).Add(time.Minute)) < 2*time.Second {
+		time.Sleep(2 * time.Second)
+	}
+	for i := 0; i < 2; i++ {
+		if w := run("org-a"); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 under the limit, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	w := run("org-a")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 over the limit, got %d", w.Code)
+	}
+	if retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || retryAfter < 1 || retryAfter > 60 {
+		t.Errorf("Expected Retry-After within the minute, got %q", w.Header().Get("Retry-After"))
+	}
+	if w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Expected no remaining requests, got %q", w.Header().Get("X-RateLimit-Remaining"))
+	}
+
+	// Limits are per organization
+	if w := run("org-b"); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for another organization, got %d", w.Code)
+	}
+
+	// The first run uses 150 tokens, exhausting the daily quota of 100
+	if w := run("org-tokens"); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 under the token quota, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := run("org-tokens"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected status 429 over the token quota, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", quotaPath+"?org_id=org-tokens", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var usage QuotaUsage
+	if err := json.Unmarshal(w.Body.Bytes(), &usage); err != nil {
+		t.Fatalf("Failed to unmarshal usage: %v", err)
+	}
+	if usage.OrgID != "org-tokens" || usage.TokensPerDay == nil || usage.TokensPerDay.Used != 150 || usage.TokensPerDay.Limit != 100 || usage.RequestsPerMinute != nil {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+}
+
+func TestQuotaKeys(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	requestsKey, _, tokensKey, _ := quotaKeys("a:tokens:*", now)
+	if !strings.HasPrefix(requestsKey, "a%3Atokens%3A%2A:requests:") || !strings.HasPrefix(tokensKey, "a%3Atokens%3A%2A:tokens:") {
+		t.Errorf("Expected the organization ID to be escaped, got %q and %q", requestsKey, tokensKey)
+	}
+}
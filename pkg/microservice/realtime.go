@@ -0,0 +1,218 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// realtimePath is the WebSocket endpoint of realtime voice sessions
+const realtimePath = "/ws/realtime"
+
+// Realtime message types sent by the client. Audio can also be sent as
+// binary WebSocket messages holding raw PCM.
+const (
+	RealtimeMessageAudio     = "audio"
+	RealtimeMessageText      = "text"
+	RealtimeMessageInterrupt = "interrupt"
+	RealtimeMessageEnd       = "end"
+)
+
+// Realtime message types sent by the server, besides the event types of
+// interfaces.RealtimeEventType
+const (
+	RealtimeEventStarted = "session_started"
+	RealtimeEventEnded   = "session_ended"
+)
+
+// RealtimeClientMessage is a message sent by the client over a realtime session
+type RealtimeClientMessage struct {
+	Type string `json:"type"`
+	// Audio is 16-bit mono PCM at the session's input_audio_format (base64 in JSON)
+	Audio []byte `json:"audio,omitempty"`
+	Text  string `json:"text,omitempty"`
+}
+
+// RealtimeServerMessage is a message sent by the server over a realtime session
+type RealtimeServerMessage struct {
+	Type             string                 `json:"type"`
+	SessionID        string                 `json:"session_id,omitempty"`
+	InputAudioFormat string                 `json:"input_audio_format,omitempty"`
+	Audio            []byte                 `json:"audio,omitempty"`
+	MIMEType         string                 `json:"mime_type,omitempty"`
+	Text             string                 `json:"text,omitempty"`
+	ToolCall         *interfaces.ToolCall   `json:"tool_call,omitempty"`
+	ToolResult       *interfaces.ToolResult `json:"tool_result,omitempty"`
+	Error            string                 `json:"error,omitempty"`
+	Reason           string                 `json:"reason,omitempty"`
+	Timestamp        int64                  `json:"timestamp"`
+}
+
+// realtimeConn is the client connection of a realtime session
+type realtimeConn struct {
+	id      string
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+// send writes a message to the client; gorilla connections allow one writer at a time
+func (c *realtimeConn) send(msg RealtimeServerMessage) error {
+	msg.SessionID = c.id
+	msg.Timestamp = time.Now().UnixMilli()
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteJSON(msg)
+}
+
+// handleRealtime bridges a WebSocket connection to a realtime voice session
+// of the agent (GET /ws/realtime?org_id=...). The client streams audio as
+// binary messages or {"type":"audio","audio":"<base64>"}, and may send
+// {"type":"text","text":"..."}, "interrupt" to cut off the response, or
+// "end" to close the session. The server sends the session events (audio,
+// transcripts, tool calls and results, interruptions, turn completions);
+// tools are run by the agent. The session's MaxDuration and IdleTimeout
+// limits apply, the idle timeout counting from the last client message.
+func (h *HTTPServer) handleRealtime(w http.ResponseWriter, r *http.Request) {
+	limits := h.sessionLimits.withDefaults()
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+	ctx, cancel := context.WithTimeout(ctx, limits.MaxDuration)
+	defer cancel()
+
+	session, err := h.Agent().StartRealtime(ctx)
+	if errors.Is(err, agent.ErrRealtimeNotSupported) {
+		http.Error(w, "Realtime sessions are not configured", http.StatusNotImplemented)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to start realtime session: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = session.Close() }()
+
+	conn, err := sessionUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already wrote an HTTP error response
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	conn.SetReadLimit(limits.MaxMessageBytes)
+
+	client := &realtimeConn{id: uuid.New().String(), conn: conn}
+	if err := client.send(RealtimeServerMessage{
+		Type:             RealtimeEventStarted,
+		InputAudioFormat: session.InputAudioFormat(),
+	}); err != nil {
+		return
+	}
+
+	reason := runRealtime(ctx, client, session, limits)
+	_ = session.Close()
+	_ = client.send(RealtimeServerMessage{Type: RealtimeEventEnded, Reason: reason})
+	_ = conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason),
+		time.Now().Add(time.Second))
+}
+
+// runRealtime relays client messages and session events until the session
+// ends and returns the reason
+func runRealtime(ctx context.Context, client *realtimeConn, session interfaces.RealtimeSession, limits SessionLimits) string {
+	ended := make(chan string, 1)
+	go func() {
+		ended <- readRealtime(ctx, client, session, limits)
+	}()
+
+	events := session.Events()
+	for {
+		select {
+		case <-ctx.Done():
+			return "max_duration"
+
+		case reason := <-ended:
+			return reason
+
+		case event, ok := <-events:
+			if !ok {
+				return "session_closed"
+			}
+			msg := RealtimeServerMessage{
+				Type:       string(event.Type),
+				Audio:      event.Audio,
+				MIMEType:   event.MIMEType,
+				Text:       event.Text,
+				ToolCall:   event.ToolCall,
+				ToolResult: event.ToolResult,
+			}
+			if event.Error != nil {
+				msg.Error = event.Error.Error()
+			}
+			if err := client.send(msg); err != nil {
+				return "connection_error"
+			}
+		}
+	}
+}
+
+// readRealtime forwards client messages to the session until the client ends
+// the session or the connection fails, and returns the reason
+func readRealtime(ctx context.Context, client *realtimeConn, session interfaces.RealtimeSession, limits SessionLimits) string {
+	for {
+		_ = client.conn.SetReadDeadline(time.Now().Add(limits.IdleTimeout))
+		messageType, data, err := client.conn.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			var netErr interface{ Timeout() bool }
+			switch {
+			case errors.As(err, &closeErr):
+				return "client_closed"
+			case errors.Is(err, websocket.ErrReadLimit):
+				return "message_too_large"
+			case errors.As(err, &netErr) && netErr.Timeout():
+				return "idle_timeout"
+			}
+			return "connection_error"
+		}
+
+		if messageType == websocket.BinaryMessage {
+			err = session.SendAudio(ctx, data)
+		} else {
+			var msg RealtimeClientMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				_ = client.send(RealtimeServerMessage{Type: string(interfaces.RealtimeEventError), Error: "invalid message: " + err.Error()})
+				continue
+			}
+
+			switch msg.Type {
+			case RealtimeMessageAudio:
+				err = session.SendAudio(ctx, msg.Audio)
+			case RealtimeMessageText:
+				if msg.Text == "" {
+					_ = client.send(RealtimeServerMessage{Type: string(interfaces.RealtimeEventError), Error: "text is required"})
+					continue
+				}
+				err = session.SendText(ctx, msg.Text)
+			case RealtimeMessageInterrupt:
+				err = session.Interrupt(ctx)
+			case RealtimeMessageEnd:
+				return "client_ended"
+			default:
+				_ = client.send(RealtimeServerMessage{Type: string(interfaces.RealtimeEventError), Error: fmt.Sprintf("unknown message type %q", msg.Type)})
+				continue
+			}
+		}
+		if err != nil {
+			return "session_error"
+		}
+	}
+}
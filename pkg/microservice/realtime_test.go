@@ -0,0 +1,135 @@
+package microservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// echoRealtimeSession answers text with a transcript and audio, and echoes
+// received audio back
+type echoRealtimeSession struct {
+	events chan interfaces.RealtimeEvent
+}
+
+func (s *echoRealtimeSession) SendAudio(ctx context.Context, audio []byte) error {
+	s.events <- interfaces.RealtimeEvent{Type: interfaces.RealtimeEventAudio, Audio: audio, MIMEType: "audio/pcm;rate=24000"}
+	return nil
+}
+
+func (s *echoRealtimeSession) SendText(ctx context.Context, text string) error {
+	s.events <- interfaces.RealtimeEvent{Type: interfaces.RealtimeEventTranscript, Text: "You said " + text}
+	s.events <- interfaces.RealtimeEvent{Type: interfaces.RealtimeEventTurnComplete}
+	return nil
+}
+
+func (s *echoRealtimeSession) SendToolResult(ctx context.Context, result interfaces.ToolResult) error {
+	return nil
+}
+
+func (s *echoRealtimeSession) Interrupt(ctx context.Context) error {
+	s.events <- interfaces.RealtimeEvent{Type: interfaces.RealtimeEventInterrupted}
+	return nil
+}
+
+func (s *echoRealtimeSession) InputAudioFormat() string { return "audio/pcm;rate=24000" }
+
+func (s *echoRealtimeSession) Events() <-chan interfaces.RealtimeEvent { return s.events }
+
+func (s *echoRealtimeSession) Close() error { return nil }
+
+type echoRealtimeProvider struct{}
+
+func (echoRealtimeProvider) ConnectRealtime(ctx context.Context, config interfaces.RealtimeConfig) (interfaces.RealtimeSession, error) {
+	return &echoRealtimeSession{events: make(chan interfaces.RealtimeEvent, 8)}, nil
+}
+
+func readRealtimeMessage(t *testing.T, conn *websocket.Conn) RealtimeServerMessage {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg RealtimeServerMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("Failed to read realtime message: %v", err)
+	}
+	return msg
+}
+
+func TestRealtimeSession(t *testing.T) {
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(&MockLLM{response: "unused"}),
+		agent.WithRealtime(echoRealtimeProvider{}, interfaces.RealtimeConfig{}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+
+	ts := httptest.NewServer(http.HandlerFunc(server.handleRealtime))
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Failed to dial realtime session: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	started := readRealtimeMessage(t, conn)
+	if started.Type != RealtimeEventStarted || started.SessionID == "" || started.InputAudioFormat != "audio/pcm;rate=24000" {
+		t.Fatalf("Unexpected first message: %+v", started)
+	}
+
+	if err := conn.WriteJSON(RealtimeClientMessage{Type: RealtimeMessageText, Text: "hello"}); err != nil {
+		t.Fatalf("Failed to send text: %v", err)
+	}
+	if msg := readRealtimeMessage(t, conn); msg.Type != "transcript" || msg.Text != "You said hello" {
+		t.Errorf("Unexpected transcript: %+v", msg)
+	}
+	if msg := readRealtimeMessage(t, conn); msg.Type != "turn_complete" {
+		t.Errorf("Expected turn_complete, got %+v", msg)
+	}
+
+	// Binary messages are audio
+	if err := conn.WriteMessage(websocket.BinaryMessage, []byte{1, 2, 3}); err != nil {
+		t.Fatalf("Failed to send audio: %v", err)
+	}
+	if msg := readRealtimeMessage(t, conn); msg.Type != "audio" || string(msg.Audio) != "\x01\x02\x03" || msg.MIMEType != "audio/pcm;rate=24000" {
+		t.Errorf("Unexpected audio: %+v", msg)
+	}
+
+	if err := conn.WriteJSON(RealtimeClientMessage{Type: RealtimeMessageInterrupt}); err != nil {
+		t.Fatalf("Failed to interrupt: %v", err)
+	}
+	if msg := readRealtimeMessage(t, conn); msg.Type != "interrupted" {
+		t.Errorf("Expected interrupted, got %+v", msg)
+	}
+
+	if err := conn.WriteJSON(RealtimeClientMessage{Type: RealtimeMessageEnd}); err != nil {
+		t.Fatalf("Failed to end session: %v", err)
+	}
+	if msg := readRealtimeMessage(t, conn); msg.Type != RealtimeEventEnded || msg.Reason != "client_ended" {
+		t.Errorf("Expected session_ended, got %+v", msg)
+	}
+}
+
+func TestRealtimeSessionNotConfigured(t *testing.T) {
+	agentInstance, err := agent.NewAgent(agent.WithLLM(&MockLLM{response: "unused"}))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+
+	recorder := httptest.NewRecorder()
+	server.handleRealtime(recorder, httptest.NewRequest("GET", realtimePath, nil))
+	if recorder.Code != http.StatusNotImplemented {
+		t.Errorf("Expected 501, got %d", recorder.Code)
+	}
+}
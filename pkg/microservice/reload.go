@@ -0,0 +1,275 @@
+package microservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+)
+
+// reloadPath is the path of the endpoint that reloads the agent's configuration
+const reloadPath = "/api/v1/agent/reload"
+
+// defaultReloadInterval is how often the config file is checked for changes
+const defaultReloadInterval = 5 * time.Second
+
+// ConfigReloadConfig configures reloading the agent from a YAML config file
+type ConfigReloadConfig struct {
+	// Path is the agents.yaml file the agent is built from
+	Path string
+
+	// AgentName is the agent of the file to build
+	AgentName string
+
+	// Variables are substituted in the agent's configuration
+	Variables map[string]string
+
+	// Options are applied when building the agent, such as its LLM when the
+	// file does not configure one
+	Options []agent.Option
+
+	// Interval is how often the file is checked for changes (default 5s)
+	Interval time.Duration
+
+	// Validate checks a rebuilt agent before it replaces the running one
+	Validate func(*agent.Agent) error
+}
+
+// ReloadResponse is the response of the reload endpoint
+type ReloadResponse struct {
+	Status     string `json:"status"` // "reloaded"
+	Agent      string `json:"agent"`
+	ReloadedAt int64  `json:"reloaded_at"`
+}
+
+// configReloader rebuilds the agent when its config file changes
+type configReloader struct {
+	config ConfigReloadConfig
+
+	mu      sync.Mutex // serializes reloads
+	content []byte     // content of the file the running agent was built from
+	modTime time.Time
+}
+
+// agentHolds counts the requests and jobs holding each agent the server ran,
+// so that a replaced agent is disconnected once they finish
+type agentHolds struct {
+	mu      sync.Mutex
+	counts  map[*agent.Agent]int
+	retired map[*agent.Agent]bool // replaced agents to disconnect when released
+}
+
+// Agent returns the agent the server runs. It changes when the agent is
+// replaced with SetAgent or reloaded from its config file.
+func (h *HTTPServer) Agent() *agent.Agent {
+	return h.agent.Load()
+}
+
+// SetAgent replaces the agent the server runs. Requests already in flight
+// finish with the agent they started with.
+func (h *HTTPServer) SetAgent(a *agent.Agent) {
+	h.holds.mu.Lock()
+	defer h.holds.mu.Unlock()
+	h.agent.Store(a)
+}
+
+// holdAgent registers a user of the running agent, which is not
+// disconnected when replaced until the returned release is called
+func (h *HTTPServer) holdAgent() (release func()) {
+	h.holds.mu.Lock()
+	defer h.holds.mu.Unlock()
+
+	a := h.agent.Load()
+	if h.holds.counts == nil {
+		h.holds.counts = make(map[*agent.Agent]int)
+	}
+	h.holds.counts[a]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { h.releaseAgent(a) })
+	}
+}
+
+// releaseAgent unregisters a user of a, disconnecting a if it was replaced
+// and this was its last user
+func (h *HTTPServer) releaseAgent(a *agent.Agent) {
+	h.holds.mu.Lock()
+	h.holds.counts[a]--
+	retired := false
+	if h.holds.counts[a] <= 0 {
+		delete(h.holds.counts, a)
+		retired = h.holds.retired[a]
+		delete(h.holds.retired, a)
+	}
+	h.holds.mu.Unlock()
+
+	if retired {
+		disconnectAgent(a)
+	}
+}
+
+// replaceAgent swaps in next and disconnects the replaced agent as soon as
+// no request or job holds it
+func (h *HTTPServer) replaceAgent(next *agent.Agent) {
+	h.holds.mu.Lock()
+	previous := h.agent.Swap(next)
+	idle := false
+	if previous != nil && previous != next {
+		if h.holds.counts[previous] > 0 {
+			if h.holds.retired == nil {
+				h.holds.retired = make(map[*agent.Agent]bool)
+			}
+			h.holds.retired[previous] = true
+		} else {
+			idle = true
+		}
+	}
+	h.holds.mu.Unlock()
+
+	if idle {
+		disconnectAgent(previous)
+	}
+}
+
+// holdingAgent holds the running agent for the duration of each request
+func (h *HTTPServer) holdingAgent(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		release := h.holdAgent()
+		defer release()
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// disconnectAgent stops the keepalive routine and remote connection of an
+// agent that is no longer run
+func disconnectAgent(a *agent.Agent) {
+	if err := a.Disconnect(); err != nil {
+		log.Printf("[Config Reload] Failed to disconnect agent %s: %v", a.GetName(), err)
+	}
+}
+
+// EnableConfigReload rebuilds the agent from config.Path when the file
+// changes, and on POST /api/v1/agent/reload, replacing the running agent
+// only once the new configuration loads, builds and passes config.Validate.
+// The file is watched until ctx is done. Invalid configurations are logged
+// and the running agent is kept. A replaced agent is disconnected once the
+// requests and jobs using it finish; MCP servers passed in config.Options
+// are shared by the rebuilt agents and stay open.
+func (h *HTTPServer) EnableConfigReload(ctx context.Context, config ConfigReloadConfig) error {
+	if config.Path == "" || config.AgentName == "" {
+		return fmt.Errorf("config path and agent name are required")
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultReloadInterval
+	}
+
+	reloader := &configReloader{config: config}
+	// The running agent was built from the current file
+	if info, err := os.Stat(config.Path); err == nil {
+		reloader.modTime = info.ModTime()
+	}
+	content, err := os.ReadFile(config.Path) // #nosec G304 - Path is the operator's config file
+	if err != nil {
+		return fmt.Errorf("failed to read agent config file: %w", err)
+	}
+	reloader.content = content
+	h.reloader = reloader
+
+	go h.watchConfig(ctx, reloader)
+	return nil
+}
+
+// watchConfig reloads the agent whenever its config file changes
+func (h *HTTPServer) watchConfig(ctx context.Context, reloader *configReloader) {
+	ticker := time.NewTicker(reloader.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.reloadConfig(false); err != nil {
+				log.Printf("[Config Reload] Keeping the running agent: %v", err)
+			}
+		}
+	}
+}
+
+// reloadConfig rebuilds and swaps in the agent if its config file changed,
+// or unconditionally when force is set
+func (h *HTTPServer) reloadConfig(force bool) error {
+	reloader := h.reloader
+	reloader.mu.Lock()
+	defer reloader.mu.Unlock()
+
+	config := reloader.config
+	info, err := os.Stat(config.Path)
+	if err != nil {
+		return fmt.Errorf("failed to stat agent config file: %w", err)
+	}
+	if !force && info.ModTime().Equal(reloader.modTime) {
+		return nil
+	}
+	content, err := os.ReadFile(config.Path) // #nosec G304 - Path is the operator's config file
+	if err != nil {
+		return fmt.Errorf("failed to read agent config file: %w", err)
+	}
+	reloader.modTime = info.ModTime()
+	if !force && bytes.Equal(content, reloader.content) {
+		return nil
+	}
+
+	configs, err := agent.LoadAgentConfigsFromFile(config.Path)
+	if err != nil {
+		return err
+	}
+	if _, ok := configs[config.AgentName]; !ok {
+		return fmt.Errorf("agent %s not found in %s", config.AgentName, config.Path)
+	}
+	next, err := agent.NewAgentFromConfig(config.AgentName, configs, config.Variables, config.Options...)
+	if err != nil {
+		return fmt.Errorf("failed to build agent %s: %w", config.AgentName, err)
+	}
+	if config.Validate != nil {
+		if err := config.Validate(next); err != nil {
+			disconnectAgent(next)
+			return fmt.Errorf("agent %s failed validation: %w", config.AgentName, err)
+		}
+	}
+
+	h.replaceAgent(next)
+	reloader.content = content
+	log.Printf("[Config Reload] Reloaded agent %s from %s", config.AgentName, config.Path)
+	return nil
+}
+
+// handleReload rebuilds the agent from its config file (POST), even when
+// the file has not changed since the last reload
+func (h *HTTPServer) handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.reloader == nil {
+		http.Error(w, "Config reload is not enabled", http.StatusNotFound)
+		return
+	}
+
+	if err := h.reloadConfig(true); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to reload agent: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	response := ReloadResponse{Status: "reloaded", Agent: h.Agent().GetName(), ReloadedAt: time.Now().Unix()}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
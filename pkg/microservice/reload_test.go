@@ -0,0 +1,175 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+)
+
+func TestConfigReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.yaml")
+	writeConfig := func(role string) {
+		t.Helper()
+		content := "assistant:\n  role: " + role + "\n  goal: Help\n  backstory: Helpful\n"
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+	writeConfig("Support Agent")
+
+	options := []agent.Option{agent.WithLLM(&MockLLM{response: "Hello"})}
+	configs, err := agent.LoadAgentConfigsFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	initial, err := agent.NewAgentFromConfig("assistant", configs, nil, options...)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	server := NewHTTPServer(initial, 8080)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = server.EnableConfigReload(ctx, ConfigReloadConfig{
+		Path:      path,
+		AgentName: "assistant",
+		Options:   options,
+		Interval:  10 * time.Millisecond,
+		Validate: func(a *agent.Agent) error {
+			if strings.Contains(a.GetSystemPrompt(), "Forbidden") {
+				return errors.New("forbidden role")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("EnableConfigReload failed: %v", err)
+	}
+
+	// Changes to the file replace the running agent
+	writeConfig("Sales Agent")
+	deadline := time.Now().Add(5 * time.Second)
+	for server.Agent() == initial && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	reloaded := server.Agent()
+	if reloaded == initial || !strings.Contains(reloaded.GetSystemPrompt(), "Sales Agent") {
+		t.Fatalf("Expected the agent to be reloaded, got system prompt %q", reloaded.GetSystemPrompt())
+	}
+
+	reload := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, httptest.NewRequest("POST", reloadPath, nil))
+		return w
+	}
+
+	// Configurations that fail validation are not applied
+	writeConfig("Forbidden Agent")
+	if w := reload(); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for an invalid configuration, got %d: %s", w.Code, w.Body.String())
+	}
+	if server.Agent() != reloaded {
+		t.Error("Expected the running agent to be kept")
+	}
+
+	writeConfig("Billing Agent")
+	w := reload()
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response ReloadResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.Status != "reloaded" || response.Agent != "assistant" {
+		t.Errorf("Unexpected response %+v", response)
+	}
+	if !strings.Contains(server.Agent().GetSystemPrompt(), "Billing Agent") {
+		t.Errorf("Expected the reloaded system prompt, got %q", server.Agent().GetSystemPrompt())
+	}
+}
+
+// warmingLLM keeps one connection warmup open per agent until the agent is
+// disconnected
+type warmingLLM struct {
+	MockLLM
+	active atomic.Int32
+}
+
+func (m *warmingLLM) WarmConnections(ctx context.Context) error {
+	m.active.Add(1)
+	defer m.active.Add(-1)
+	<-ctx.Done()
+	return nil
+}
+
+func TestConfigReload_DisconnectsReplacedAgents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.yaml")
+	if err := os.WriteFile(path, []byte("assistant:\n  role: Support Agent\n  goal: Help\n  backstory: Helpful\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	llm := &warmingLLM{MockLLM: MockLLM{response: "Hello"}}
+	options := []agent.Option{agent.WithLLM(llm), agent.WithConnectionWarmup(0)}
+	configs, err := agent.LoadAgentConfigsFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	initial, err := agent.NewAgentFromConfig("assistant", configs, nil, options...)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	server := NewHTTPServer(initial, 8080)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := server.EnableConfigReload(ctx, ConfigReloadConfig{Path: path, AgentName: "assistant", Options: options, Interval: time.Hour}); err != nil {
+		t.Fatalf("EnableConfigReload failed: %v", err)
+	}
+
+	waitForActive := func(want int32) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for llm.active.Load() != want && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if got := llm.active.Load(); got != want {
+			t.Fatalf("Expected %d connected agents, got %d", want, got)
+		}
+	}
+	reload := func() {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, httptest.NewRequest("POST", reloadPath, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	waitForActive(1)
+
+	for i := 0; i < 3; i++ {
+		reload()
+	}
+	waitForActive(1)
+
+	// An agent held by a request in flight stays connected until it finishes
+	release := server.holdAgent()
+	held := server.Agent()
+	reload()
+	if server.Agent() == held {
+		t.Fatal("Expected the agent to be replaced")
+	}
+	waitForActive(2)
+	release()
+	waitForActive(1)
+}
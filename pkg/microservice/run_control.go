@@ -0,0 +1,276 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// agentRunsPath is the base path of the run control endpoints
+// (/api/v1/agent/runs/{id}, /api/v1/agent/runs/{id}/cancel and
+// /api/v1/agent/runs/{id}/events)
+const agentRunsPath = "/api/v1/agent/runs/"
+
+// runIDHeader carries the run ID in the responses of run and stream requests
+const runIDHeader = "X-Run-ID"
+
+// finishedRunRetention is how long the status of finished runs is kept
+const finishedRunRetention = time.Hour
+
+// RunState is the state of a run
+type RunState string
+
+const (
+	RunStateRunning   RunState = "running"
+	RunStateCompleted RunState = "completed"
+	RunStateFailed    RunState = "failed"
+	RunStateCancelled RunState = "cancelled"
+)
+
+// RunStatus describes a run started through the run or stream endpoints
+type RunStatus struct {
+	ID              string     `json:"run_id"`
+	State           RunState   `json:"state"`
+	OrgID           string     `json:"org_id,omitempty"`
+	ConversationID  string     `json:"conversation_id,omitempty"`
+	Streaming       bool       `json:"streaming"`
+	CancelRequested bool       `json:"cancel_requested,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// trackedRun is a run whose context can be cancelled through the API
+type trackedRun struct {
+	status RunStatus
+	cancel context.CancelFunc
+	events *eventBuffer // events of streaming runs, for resuming clients
+}
+
+// runTracker tracks the runs of a server. The zero value is ready to use.
+type runTracker struct {
+	mu   sync.Mutex
+	runs map[string]*trackedRun
+}
+
+// start registers a run and returns its cancellable context. id is the run ID
+// chosen by the client, if any; IDs of tracked runs cannot be reused.
+func (t *runTracker) start(ctx context.Context, id string, req *StreamRequest, streaming bool) (context.Context, *trackedRun, error) {
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.runs == nil {
+		t.runs = make(map[string]*trackedRun)
+	}
+	t.pruneLocked()
+	if _, exists := t.runs[id]; exists {
+		return ctx, nil, fmt.Errorf("run %s already exists", id)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	run := &trackedRun{
+		status: RunStatus{
+			ID:             id,
+			State:          RunStateRunning,
+			ConversationID: req.ConversationID,
+			Streaming:      streaming,
+			StartedAt:      time.Now(),
+		},
+		cancel: cancel,
+	}
+	if streaming {
+		run.events = newEventBuffer(cancel)
+	}
+	run.status.OrgID, _ = multitenancy.GetOrgID(ctx)
+	t.runs[id] = run
+	return ctx, run, nil
+}
+
+// finish records the outcome of a run and releases its context. Only the
+// first call has an effect.
+func (t *runTracker) finish(run *trackedRun, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if run.status.State != RunStateRunning {
+		return
+	}
+
+	now := time.Now()
+	run.status.FinishedAt = &now
+	switch {
+	case run.status.CancelRequested || errors.Is(err, context.Canceled):
+		run.status.State = RunStateCancelled
+	case err != nil:
+		run.status.State = RunStateFailed
+		run.status.Error = err.Error()
+	default:
+		run.status.State = RunStateCompleted
+	}
+	run.cancel()
+}
+
+// track passes the events of a streaming run through, finishing the run with
+// the first error event when the stream ends. Events are dropped once the
+// client's ctx is done.
+func (t *runTracker) track(ctx context.Context, run *trackedRun, events <-chan interfaces.AgentStreamEvent) <-chan interfaces.AgentStreamEvent {
+	out := make(chan interfaces.AgentStreamEvent, cap(events))
+	go func() {
+		defer close(out)
+
+		var runErr error
+		delivered := true
+		for event := range events {
+			if event.Type == interfaces.AgentEventError && event.Error != nil && runErr == nil {
+				runErr = event.Error
+			}
+			// Keep draining after the client is gone so the producer can exit
+			if delivered {
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					delivered = false
+				}
+			}
+		}
+		t.finish(run, runErr)
+	}()
+	return out
+}
+
+// cancel requests the cancellation of a running run of the organization
+func (t *runTracker) cancel(id, orgID string) (RunStatus, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run, ok := t.runs[id]
+	if !ok || run.status.OrgID != orgID {
+		return RunStatus{}, errRunNotFound
+	}
+	if run.status.State == RunStateRunning {
+		run.status.CancelRequested = true
+		run.cancel()
+	}
+	return run.status, nil
+}
+
+// cancelAll requests the cancellation of all running runs
+func (t *runTracker) cancelAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, run := range t.runs {
+		if run.status.State == RunStateRunning {
+			run.status.CancelRequested = true
+			run.cancel()
+		}
+	}
+}
+
+// get returns the status of a run of the organization
+func (t *runTracker) get(id, orgID string) (RunStatus, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run, ok := t.runs[id]
+	if !ok || run.status.OrgID != orgID {
+		return RunStatus{}, errRunNotFound
+	}
+	return run.status, nil
+}
+
+// events returns the event buffer of a streaming run of the organization
+func (t *runTracker) events(id, orgID string) (*eventBuffer, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run, ok := t.runs[id]
+	if !ok || run.status.OrgID != orgID || run.events == nil {
+		return nil, errRunNotFound
+	}
+	return run.events, nil
+}
+
+// pruneLocked removes finished runs older than finishedRunRetention
+func (t *runTracker) pruneLocked() {
+	cutoff := time.Now().Add(-finishedRunRetention)
+	for id, run := range t.runs {
+		if run.status.FinishedAt != nil && run.status.FinishedAt.Before(cutoff) {
+			delete(t.runs, id)
+		}
+	}
+}
+
+var errRunNotFound = errors.New("run not found")
+
+// startRun registers the run of a run or stream request and sets its ID in
+// the response headers. It writes an error response and returns false when
+// the requested run ID is taken.
+func (h *HTTPServer) startRun(w http.ResponseWriter, ctx context.Context, req *StreamRequest, streaming bool) (context.Context, *trackedRun, bool) {
+	ctx, run, err := h.runs.start(ctx, req.RunID, req, streaming)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return ctx, nil, false
+	}
+	w.Header().Set(runIDHeader, run.status.ID)
+	return ctx, run, true
+}
+
+// handleAgentRun returns the status of a run (GET /api/v1/agent/runs/{id}),
+// cancels it (POST /api/v1/agent/runs/{id}/cancel) or resumes its stream
+// (GET /api/v1/agent/runs/{id}/events). Cancelling a run cancels its context,
+// which stops LLM calls and tool executions in progress. Runs of other
+// organizations are not found.
+func (h *HTTPServer) handleAgentRun(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, agentRunsPath)
+	path, events := strings.CutSuffix(path, "/events")
+	id, cancel := strings.CutSuffix(path, "/cancel")
+	if id == "" || strings.Contains(id, "/") || (events && cancel) {
+		http.Error(w, "Run ID required", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case cancel && r.Method != "POST", !cancel && r.Method != "GET":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+	orgID, _ := multitenancy.GetOrgID(ctx)
+
+	if events {
+		h.resumeStream(w, r, id, orgID)
+		return
+	}
+
+	var status RunStatus
+	var err error
+	if cancel {
+		status, err = h.runs.cancel(id, orgID)
+	} else {
+		status, err = h.runs.get(id, orgID)
+	}
+	if err != nil {
+		http.Error(w, "Run not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if cancel && status.State == RunStateRunning {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,140 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+)
+
+func getRunStatus(t *testing.T, server *HTTPServer, path string) (int, RunStatus) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	server.handleAgentRun(w, httptest.NewRequest("GET", path, nil))
+	var status RunStatus
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatalf("Failed to unmarshal status: %v", err)
+		}
+	}
+	return w.Code, status
+}
+
+func TestHTTPServer_CancelRun(t *testing.T) {
+	started := make(chan struct{})
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(&MockLLM{response: "unused"}),
+		agent.WithName("TestAgent"),
+		agent.WithCustomRunFunction(func(ctx context.Context, input string, a *agent.Agent) (string, error) {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		server.handleRun(w, httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(`{"input":"long job","org_id":"org-1","run_id":"job-1"}`)))
+		done <- w
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not start")
+	}
+
+	if code, status := getRunStatus(t, server, agentRunsPath+"job-1?org_id=org-1"); code != http.StatusOK || status.State != RunStateRunning {
+		t.Fatalf("Expected a running run, got %d %+v", code, status)
+	}
+	if code, _ := getRunStatus(t, server, agentRunsPath+"job-1?org_id=org-2"); code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for another organization, got %d", code)
+	}
+
+	// Run IDs of tracked runs cannot be reused
+	w := httptest.NewRecorder()
+	server.handleRun(w, httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(`{"input":"again","org_id":"org-1","run_id":"job-1"}`)))
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a duplicate run ID, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	server.handleAgentRun(w, httptest.NewRequest("POST", agentRunsPath+"job-1/cancel?org_id=org-1", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var run *httptest.ResponseRecorder
+	select {
+	case run = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Cancelled run did not return")
+	}
+	if run.Header().Get(runIDHeader) != "job-1" {
+		t.Errorf("Expected the run ID header, got %q", run.Header().Get(runIDHeader))
+	}
+
+	code, status := getRunStatus(t, server, agentRunsPath+"job-1?org_id=org-1")
+	if code != http.StatusOK || status.State != RunStateCancelled || !status.CancelRequested || status.FinishedAt == nil {
+		t.Errorf("Expected a cancelled run, got %d %+v", code, status)
+	}
+
+	// Cancelling a finished run leaves it unchanged
+	w = httptest.NewRecorder()
+	server.handleAgentRun(w, httptest.NewRequest("POST", agentRunsPath+"job-1/cancel?org_id=org-1", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a finished run, got %d", w.Code)
+	}
+}
+
+func TestHTTPServer_RunStatus(t *testing.T) {
+	server := NewHTTPServer(createTestAgent("Hello, world!", nil).(*MockStreamingAgent).Agent, 8080)
+
+	w := httptest.NewRecorder()
+	server.handleRun(w, httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(`{"input":"hi","conversation_id":"c1"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	runID, _ := response["run_id"].(string)
+	if runID == "" || w.Header().Get(runIDHeader) != runID {
+		t.Fatalf("Expected a generated run ID, got %q and header %q", runID, w.Header().Get(runIDHeader))
+	}
+
+	code, status := getRunStatus(t, server, agentRunsPath+runID)
+	if code != http.StatusOK || status.State != RunStateCompleted || status.Streaming {
+		t.Errorf("Expected a completed run, got %d %+v", code, status)
+	}
+
+	w = httptest.NewRecorder()
+	server.handleStream(w, httptest.NewRequest("POST", "/api/v1/agent/stream", strings.NewReader(`{"input":"hi","conversation_id":"c1","run_id":"stream-1"}`)))
+	if !strings.Contains(w.Body.String(), `"run_id":"stream-1"`) {
+		t.Errorf("Expected the run ID in the connected event, got %s", w.Body.String())
+	}
+	code, status = getRunStatus(t, server, agentRunsPath+"stream-1")
+	if code != http.StatusOK || status.State != RunStateCompleted || !status.Streaming {
+		t.Errorf("Expected a completed streaming run, got %d %+v", code, status)
+	}
+
+	if code, _ := getRunStatus(t, server, agentRunsPath+"unknown"); code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown run, got %d", code)
+	}
+	w = httptest.NewRecorder()
+	server.handleAgentRun(w, httptest.NewRequest("GET", agentRunsPath+runID+"/cancel", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}
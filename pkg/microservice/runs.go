@@ -0,0 +1,32 @@
+package microservice
+
+import (
+	"net/http"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/compliance"
+)
+
+// runsPath is the base path of the run audit trail endpoints
+const runsPath = "/api/v1/runs"
+
+// registerRunEndpoints serves the agent's recorded runs when it has a run
+// recorder with a queryable store (/api/v1/runs?org_id=... and
+// /api/v1/runs/{id}?org_id=...)
+func (h *HTTPServer) registerRunEndpoints(mux *http.ServeMux) {
+	recorder := h.Agent().GetRunRecorder()
+	if recorder == nil {
+		return
+	}
+	store, ok := recorder.Store().(compliance.QueryableStore)
+	if !ok {
+		return
+	}
+
+	runs := http.StripPrefix(runsPath, compliance.NewHandler(store))
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+		runs.ServeHTTP(w, r.WithContext(ctx))
+	}
+	mux.HandleFunc(runsPath, handler)
+	mux.HandleFunc(runsPath+"/", handler)
+}
@@ -0,0 +1,446 @@
+package microservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+// agentWebSocketPath serves agent sessions with control messages, the same
+// sessions as /ws/chat
+const agentWebSocketPath = "/api/v1/agent/ws"
+
+// Session message types sent by the client
+const (
+	SessionMessageTurn        = "message"
+	SessionMessageFollowUp    = "follow_up"
+	SessionMessageApproveTool = "approve_tool"
+	SessionMessageCancel      = "cancel"
+	SessionMessageEnd         = "end"
+)
+
+// Session message types sent by the server
+const (
+	SessionEventStarted        = "session_started"
+	SessionEventTurnStarted    = "turn_started"
+	SessionEventStream         = "event"
+	SessionEventTurnCompleted  = "turn_completed"
+	SessionEventTurnCancelled  = "turn_cancelled"
+	SessionEventFollowUpQueued = "follow_up_queued"
+	SessionEventToolApproval   = "tool_approval_required"
+	SessionEventError          = "error"
+	SessionEventEnded          = "session_ended"
+)
+
+// SessionLimits bounds a WebSocket agent session. Zero values use the defaults.
+type SessionLimits struct {
+	// MaxTurns is the number of user turns allowed per session (default: 100)
+	MaxTurns int
+
+	// MaxDuration is the maximum lifetime of a session (default: 1h)
+	MaxDuration time.Duration
+
+	// IdleTimeout ends a session when no turn is running and the client sends
+	// nothing for this long (default: 5m)
+	IdleTimeout time.Duration
+
+	// TurnTimeout is the maximum duration of a single turn (default: 10m)
+	TurnTimeout time.Duration
+
+	// MaxMessageBytes is the maximum size of a client message (default: 64KB)
+	MaxMessageBytes int64
+}
+
+// withDefaults returns the limits with zero values replaced by defaults
+func (l SessionLimits) withDefaults() SessionLimits {
+	if l.MaxTurns <= 0 {
+		l.MaxTurns = 100
+	}
+	if l.MaxDuration <= 0 {
+		l.MaxDuration = time.Hour
+	}
+	if l.IdleTimeout <= 0 {
+		l.IdleTimeout = 5 * time.Minute
+	}
+	if l.TurnTimeout <= 0 {
+		l.TurnTimeout = 10 * time.Minute
+	}
+	if l.MaxMessageBytes <= 0 {
+		l.MaxMessageBytes = 64 * 1024
+	}
+	return l
+}
+
+// SessionClientMessage is a message sent by the client over a session
+type SessionClientMessage struct {
+	Type   string `json:"type"`
+	TurnID string `json:"turn_id,omitempty"`
+	Input  string `json:"input,omitempty"`
+
+	// ApprovalID, Approved and Reason answer a tool approval request
+	ApprovalID string `json:"approval_id,omitempty"`
+	Approved   bool   `json:"approved,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// SessionServerMessage is a message sent by the server over a session
+type SessionServerMessage struct {
+	Type           string                     `json:"type"`
+	SessionID      string                     `json:"session_id,omitempty"`
+	ConversationID string                     `json:"conversation_id,omitempty"`
+	TurnID         string                     `json:"turn_id,omitempty"`
+	Turn           int                        `json:"turn,omitempty"`
+	Event          *StreamEventData           `json:"event,omitempty"`
+	Approval       *agent.ToolApprovalRequest `json:"approval,omitempty"`
+	Error          string                     `json:"error,omitempty"`
+	Reason         string                     `json:"reason,omitempty"`
+	Timestamp      int64                      `json:"timestamp"`
+}
+
+var sessionUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	// Match the permissive CORS policy of the HTTP API
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// SetSessionLimits sets the limits applied to WebSocket agent sessions
+func (h *HTTPServer) SetSessionLimits(limits SessionLimits) {
+	h.sessionLimits = limits
+}
+
+// agentSession is one client connection carrying many user turns
+type agentSession struct {
+	id             string
+	conversationID string
+	conn           *websocket.Conn
+	limits         SessionLimits
+	writeMu        sync.Mutex
+
+	approvalsMu sync.Mutex
+	approvals   map[string]chan SessionClientMessage // Pending tool approvals by ID
+}
+
+// send writes a message to the client; gorilla connections allow one writer at a time
+func (s *agentSession) send(msg SessionServerMessage) error {
+	msg.SessionID = s.id
+	msg.Timestamp = time.Now().UnixMilli()
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteJSON(msg)
+}
+
+// ping sends a WebSocket ping, which clients answer without seeing it, to
+// keep the connection open through proxies while a turn runs
+func (s *agentSession) ping() error {
+	return s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second))
+}
+
+// approveTool is the ToolApprover of the session's turns. It asks the client
+// and waits for its approve_tool answer.
+func (s *agentSession) approveTool(turnID string) agent.ToolApprover {
+	return func(ctx context.Context, req agent.ToolApprovalRequest) (bool, string, error) {
+		answer := make(chan SessionClientMessage, 1)
+		s.approvalsMu.Lock()
+		s.approvals[req.ID] = answer
+		s.approvalsMu.Unlock()
+		defer func() {
+			s.approvalsMu.Lock()
+			delete(s.approvals, req.ID)
+			s.approvalsMu.Unlock()
+		}()
+
+		if err := s.send(SessionServerMessage{Type: SessionEventToolApproval, TurnID: turnID, Approval: &req}); err != nil {
+			return false, "", err
+		}
+		select {
+		case msg := <-answer:
+			return msg.Approved, msg.Reason, nil
+		case <-ctx.Done():
+			return false, "", ctx.Err()
+		}
+	}
+}
+
+// answerApproval delivers an approve_tool message to the waiting tool call
+func (s *agentSession) answerApproval(msg SessionClientMessage) bool {
+	s.approvalsMu.Lock()
+	defer s.approvalsMu.Unlock()
+	answer, ok := s.approvals[msg.ApprovalID]
+	if ok {
+		delete(s.approvals, msg.ApprovalID)
+		answer <- msg
+	}
+	return ok
+}
+
+// handleSession runs a continuous conversation over one WebSocket connection
+// (GET /ws/chat or /api/v1/agent/ws, with ?conversation_id=...&org_id=...).
+// The client sends {"type":"message","turn_id":"...","input":"..."} for each
+// user turn, and controls the running turn with:
+//   - "follow_up" to queue input that runs as the next turn
+//   - "approve_tool" with approval_id, approved and reason to answer a
+//     tool_approval_required message, sent for tools configured with
+//     agent.WithToolApproval
+//   - "cancel" to stop the running turn and drop queued follow-ups, or "end"
+//     to close the session
+//
+// Every turn shares the session's conversation ID, so memory carries over
+// between turns, and authentication happens once at connection time.
+func (h *HTTPServer) handleSession(w http.ResponseWriter, r *http.Request) {
+	conn, err := sessionUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already wrote an HTTP error response
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	limits := h.sessionLimits.withDefaults()
+	conn.SetReadLimit(limits.MaxMessageBytes)
+
+	session := &agentSession{
+		id:             uuid.New().String(),
+		conversationID: r.URL.Query().Get("conversation_id"),
+		conn:           conn,
+		limits:         limits,
+		approvals:      make(map[string]chan SessionClientMessage),
+	}
+	if session.conversationID == "" {
+		session.conversationID = session.id
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+	ctx = memory.WithConversationID(ctx, session.conversationID)
+	ctx, cancel := context.WithTimeout(ctx, limits.MaxDuration)
+	defer cancel()
+
+	if err := session.send(SessionServerMessage{
+		Type:           SessionEventStarted,
+		ConversationID: session.conversationID,
+	}); err != nil {
+		return
+	}
+
+	reason := h.runSession(ctx, session)
+	_ = session.send(SessionServerMessage{Type: SessionEventEnded, Reason: reason})
+	_ = conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason),
+		time.Now().Add(time.Second))
+}
+
+// runSession processes client messages until the session ends and returns the reason
+func (h *HTTPServer) runSession(ctx context.Context, session *agentSession) string {
+	messages := make(chan SessionClientMessage)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			var msg SessionClientMessage
+			if err := session.conn.ReadJSON(&msg); err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	idle := time.NewTimer(session.limits.IdleTimeout)
+	defer idle.Stop()
+
+	turns := 0
+	var current *sessionTurn
+	var followUps []SessionClientMessage
+	defer func() {
+		if current != nil {
+			current.cancel()
+			<-current.done
+		}
+	}()
+
+	// A nil channel never receives, so this case is disabled while no turn runs
+	turnDone := func() <-chan struct{} {
+		if current == nil {
+			return nil
+		}
+		return current.done
+	}
+
+	startTurn := func(msg SessionClientMessage) {
+		turns++
+		turnID := msg.TurnID
+		if turnID == "" {
+			turnID = fmt.Sprintf("turn-%d", turns)
+		}
+		current = h.startSessionTurn(ctx, session, turnID, turns, msg.Input)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "max_duration"
+
+		case err := <-readErr:
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				return "client_closed"
+			}
+			if errors.Is(err, websocket.ErrReadLimit) {
+				return "message_too_large"
+			}
+			return "connection_error"
+
+		case <-idle.C:
+			if current != nil {
+				idle.Reset(session.limits.IdleTimeout)
+				continue
+			}
+			return "idle_timeout"
+
+		case <-turnDone():
+			current.cancel()
+			current = nil
+			idle.Reset(session.limits.IdleTimeout)
+			if turns >= session.limits.MaxTurns {
+				return "max_turns"
+			}
+			if len(followUps) > 0 {
+				next := followUps[0]
+				followUps = followUps[1:]
+				startTurn(next)
+			}
+
+		case msg := <-messages:
+			idle.Reset(session.limits.IdleTimeout)
+
+			switch msg.Type {
+			case SessionMessageTurn:
+				if msg.Input == "" {
+					_ = session.send(SessionServerMessage{Type: SessionEventError, TurnID: msg.TurnID, Error: "input is required"})
+					continue
+				}
+				if current != nil {
+					_ = session.send(SessionServerMessage{Type: SessionEventError, TurnID: msg.TurnID, Error: fmt.Sprintf("turn %s is still running", current.id)})
+					continue
+				}
+
+				startTurn(msg)
+
+			case SessionMessageFollowUp:
+				if msg.Input == "" {
+					_ = session.send(SessionServerMessage{Type: SessionEventError, TurnID: msg.TurnID, Error: "input is required"})
+					continue
+				}
+				if current == nil {
+					startTurn(msg)
+					continue
+				}
+				followUps = append(followUps, msg)
+				_ = session.send(SessionServerMessage{Type: SessionEventFollowUpQueued, TurnID: msg.TurnID})
+
+			case SessionMessageApproveTool:
+				if !session.answerApproval(msg) {
+					_ = session.send(SessionServerMessage{Type: SessionEventError, Error: fmt.Sprintf("no pending approval %q", msg.ApprovalID)})
+				}
+
+			case SessionMessageCancel:
+				if current != nil && (msg.TurnID == "" || msg.TurnID == current.id) {
+					current.cancel()
+					followUps = nil
+				}
+
+			case SessionMessageEnd:
+				return "client_ended"
+
+			default:
+				_ = session.send(SessionServerMessage{Type: SessionEventError, Error: fmt.Sprintf("unknown message type %q", msg.Type)})
+			}
+		}
+	}
+}
+
+// sessionTurn tracks the turn currently running in a session
+type sessionTurn struct {
+	id     string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startSessionTurn runs a turn in the background, bounded by the turn timeout
+func (h *HTTPServer) startSessionTurn(ctx context.Context, session *agentSession, turnID string, turn int, input string) *sessionTurn {
+	ctx, cancel := context.WithTimeout(ctx, session.limits.TurnTimeout)
+	ctx = agent.WithToolApprover(ctx, session.approveTool(turnID))
+	current := &sessionTurn{id: turnID, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(current.done)
+		h.runSessionTurn(ctx, session, turnID, turn, input)
+	}()
+
+	return current
+}
+
+// runSessionTurn runs one user turn and streams its events to the client
+func (h *HTTPServer) runSessionTurn(ctx context.Context, session *agentSession, turnID string, turn int, input string) {
+	if err := session.send(SessionServerMessage{Type: SessionEventTurnStarted, TurnID: turnID, Turn: turn}); err != nil {
+		return
+	}
+
+	events, err := h.Agent().RunStream(ctx, input)
+	if err != nil {
+		_ = session.send(SessionServerMessage{Type: SessionEventError, TurnID: turnID, Turn: turn, Error: err.Error()})
+		return
+	}
+
+	heartbeat := time.NewTicker(h.streamLimits.withDefaults().HeartbeatInterval)
+	defer heartbeat.Stop()
+
+stream:
+	for {
+		var event interfaces.AgentStreamEvent
+		select {
+		case next, ok := <-events:
+			if !ok {
+				break stream
+			}
+			event = next
+		case <-heartbeat.C:
+			if err := session.ping(); err != nil {
+				log.Printf("[HTTP Server] Failed to send session heartbeat: %v", err)
+			}
+			continue
+		}
+
+		eventData := h.convertAgentEventToHTTPEvent(event)
+		eventData.Timestamp = time.Now().UnixMilli()
+		if event.Type == interfaces.AgentEventComplete {
+			eventData.IsFinal = true
+		}
+		if err := session.send(SessionServerMessage{Type: SessionEventStream, TurnID: turnID, Turn: turn, Event: &eventData}); err != nil {
+			log.Printf("[HTTP Server] Failed to send session event: %v", err)
+		}
+	}
+
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		_ = session.send(SessionServerMessage{Type: SessionEventTurnCancelled, TurnID: turnID, Turn: turn})
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		_ = session.send(SessionServerMessage{Type: SessionEventError, TurnID: turnID, Turn: turn, Error: "turn timed out"})
+	default:
+		_ = session.send(SessionServerMessage{Type: SessionEventTurnCompleted, TurnID: turnID, Turn: turn})
+	}
+}
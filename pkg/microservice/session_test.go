@@ -0,0 +1,289 @@
+package microservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+// newSessionTestServer starts a session endpoint backed by an agent that
+// echoes each input along with the conversation ID it ran in
+func newSessionTestServer(t *testing.T, limits SessionLimits, block <-chan struct{}) *websocket.Conn {
+	t.Helper()
+
+	streamFunc := func(ctx context.Context, input string, a *agent.Agent) (<-chan interfaces.AgentStreamEvent, error) {
+		events := make(chan interfaces.AgentStreamEvent, 2)
+		go func() {
+			defer close(events)
+			if block != nil {
+				select {
+				case <-block:
+				case <-ctx.Done():
+					return
+				}
+			}
+			conversationID, _ := memory.GetConversationID(ctx)
+			events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: conversationID + ":" + input}
+			events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventComplete}
+		}()
+		return events, nil
+	}
+
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(&MockLLM{response: "unused"}),
+		agent.WithName("SessionAgent"),
+		agent.WithCustomRunStreamFunction(streamFunc),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	server := NewHTTPServer(agentInstance, 8080)
+	server.SetSessionLimits(limits)
+
+	ts := httptest.NewServer(http.HandlerFunc(server.handleSession))
+	t.Cleanup(ts.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"?conversation_id=conv-1&org_id=org-1", nil)
+	if err != nil {
+		t.Fatalf("Failed to dial session: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	started := readSessionMessage(t, conn)
+	if started.Type != SessionEventStarted || started.ConversationID != "conv-1" || started.SessionID == "" {
+		t.Fatalf("Unexpected first message: %+v", started)
+	}
+	return conn
+}
+
+func readSessionMessage(t *testing.T, conn *websocket.Conn) SessionServerMessage {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg SessionServerMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("Failed to read session message: %v", err)
+	}
+	return msg
+}
+
+// readUntil reads session messages until one of the given type arrives
+func readUntil(t *testing.T, conn *websocket.Conn, msgType string) (SessionServerMessage, []SessionServerMessage) {
+	t.Helper()
+
+	var seen []SessionServerMessage
+	for {
+		msg := readSessionMessage(t, conn)
+		if msg.Type == msgType {
+			return msg, seen
+		}
+		seen = append(seen, msg)
+	}
+}
+
+func TestSession_MultipleTurnsOverOneConnection(t *testing.T) {
+	conn := newSessionTestServer(t, SessionLimits{}, nil)
+
+	for i, input := range []string{"hello", "again"} {
+		if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageTurn, Input: input}); err != nil {
+			t.Fatalf("Failed to send turn: %v", err)
+		}
+
+		completed, seen := readUntil(t, conn, SessionEventTurnCompleted)
+		if completed.Turn != i+1 {
+			t.Errorf("Expected turn %d, got %d", i+1, completed.Turn)
+		}
+
+		var content string
+		for _, msg := range seen {
+			if msg.Type == SessionEventStream && msg.Event.Type == string(interfaces.AgentEventContent) {
+				content += msg.Event.Content
+			}
+		}
+		if content != "conv-1:"+input {
+			t.Errorf("Expected content %q, got %q", "conv-1:"+input, content)
+		}
+	}
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageEnd}); err != nil {
+		t.Fatalf("Failed to end session: %v", err)
+	}
+	ended, _ := readUntil(t, conn, SessionEventEnded)
+	if ended.Reason != "client_ended" {
+		t.Errorf("Expected reason 'client_ended', got %q", ended.Reason)
+	}
+}
+
+func TestSession_MaxTurns(t *testing.T) {
+	conn := newSessionTestServer(t, SessionLimits{MaxTurns: 1}, nil)
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageTurn, Input: "only"}); err != nil {
+		t.Fatalf("Failed to send turn: %v", err)
+	}
+	readUntil(t, conn, SessionEventTurnCompleted)
+
+	ended, _ := readUntil(t, conn, SessionEventEnded)
+	if ended.Reason != "max_turns" {
+		t.Errorf("Expected reason 'max_turns', got %q", ended.Reason)
+	}
+}
+
+func TestSession_CancelAndConcurrentTurn(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	conn := newSessionTestServer(t, SessionLimits{}, block)
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageTurn, TurnID: "t1", Input: "slow"}); err != nil {
+		t.Fatalf("Failed to send turn: %v", err)
+	}
+	readUntil(t, conn, SessionEventTurnStarted)
+
+	// A second turn is rejected while the first is running
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageTurn, TurnID: "t2", Input: "fast"}); err != nil {
+		t.Fatalf("Failed to send turn: %v", err)
+	}
+	rejected, _ := readUntil(t, conn, SessionEventError)
+	if rejected.TurnID != "t2" {
+		t.Errorf("Expected the rejection for turn t2, got %+v", rejected)
+	}
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageCancel, TurnID: "t1"}); err != nil {
+		t.Fatalf("Failed to cancel turn: %v", err)
+	}
+	cancelled, _ := readUntil(t, conn, SessionEventTurnCancelled)
+	if cancelled.TurnID != "t1" {
+		t.Errorf("Expected turn t1 to be cancelled, got %+v", cancelled)
+	}
+}
+
+func TestSession_IdleTimeout(t *testing.T) {
+	conn := newSessionTestServer(t, SessionLimits{IdleTimeout: 50 * time.Millisecond}, nil)
+
+	ended, _ := readUntil(t, conn, SessionEventEnded)
+	if ended.Reason != "idle_timeout" {
+		t.Errorf("Expected reason 'idle_timeout', got %q", ended.Reason)
+	}
+}
+
+func TestSession_FollowUpRunsAfterCurrentTurn(t *testing.T) {
+	block := make(chan struct{})
+	conn := newSessionTestServer(t, SessionLimits{}, block)
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageTurn, TurnID: "t1", Input: "first"}); err != nil {
+		t.Fatalf("Failed to send turn: %v", err)
+	}
+	readUntil(t, conn, SessionEventTurnStarted)
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageFollowUp, TurnID: "t2", Input: "next"}); err != nil {
+		t.Fatalf("Failed to send follow-up: %v", err)
+	}
+	queued, _ := readUntil(t, conn, SessionEventFollowUpQueued)
+	if queued.TurnID != "t2" {
+		t.Errorf("Expected follow-up t2 to be queued, got %+v", queued)
+	}
+	close(block)
+
+	first, _ := readUntil(t, conn, SessionEventTurnCompleted)
+	second, seen := readUntil(t, conn, SessionEventTurnCompleted)
+	if first.TurnID != "t1" || second.TurnID != "t2" || second.Turn != 2 {
+		t.Fatalf("Expected turns t1 and t2 in order, got %+v and %+v", first, second)
+	}
+	var content string
+	for _, msg := range seen {
+		if msg.Type == SessionEventStream && msg.Event.Type == string(interfaces.AgentEventContent) {
+			content += msg.Event.Content
+		}
+	}
+	if content != "conv-1:next" {
+		t.Errorf("Expected the follow-up to run, got content %q", content)
+	}
+}
+
+func TestSession_ToolApproval(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.Any()).CallTool("send_email", `{"input":"hi"}`).Respond("sent")
+
+	sent := make(chan string, 1)
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(llm),
+		agent.WithName("SessionAgent"),
+		agent.WithTools(&approvalTestTool{run: func(input string) { sent <- input }}),
+		agent.WithToolApproval("send_email"),
+		agent.WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+	ts := httptest.NewServer(http.HandlerFunc(server.handleSession))
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+agentWebSocketPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial session: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	readUntil(t, conn, SessionEventStarted)
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageTurn, TurnID: "t1", Input: "email the team"}); err != nil {
+		t.Fatalf("Failed to send turn: %v", err)
+	}
+	request, _ := readUntil(t, conn, SessionEventToolApproval)
+	if request.TurnID != "t1" || request.Approval == nil || request.Approval.ToolName != "send_email" {
+		t.Fatalf("Unexpected approval request %+v", request)
+	}
+	select {
+	case <-sent:
+		t.Fatal("Expected the tool to wait for approval")
+	default:
+	}
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageApproveTool, ApprovalID: "unknown", Approved: true}); err != nil {
+		t.Fatalf("Failed to send approval: %v", err)
+	}
+	if msg, _ := readUntil(t, conn, SessionEventError); !strings.Contains(msg.Error, "no pending approval") {
+		t.Errorf("Expected an error for an unknown approval, got %+v", msg)
+	}
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageApproveTool, ApprovalID: request.Approval.ID, Approved: true}); err != nil {
+		t.Fatalf("Failed to send approval: %v", err)
+	}
+	readUntil(t, conn, SessionEventTurnCompleted)
+	select {
+	case input := <-sent:
+		if input != `{"input":"hi"}` {
+			t.Errorf("Unexpected tool input %q", input)
+		}
+	default:
+		t.Error("Expected the approved tool to run")
+	}
+}
+
+// approvalTestTool is a send_email tool reporting its calls
+type approvalTestTool struct {
+	run func(input string)
+}
+
+func (t *approvalTestTool) Name() string        { return "send_email" }
+func (t *approvalTestTool) Description() string { return "Sends an email" }
+func (t *approvalTestTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{}
+}
+func (t *approvalTestTool) Run(ctx context.Context, input string) (string, error) {
+	t.run(input)
+	return "sent", nil
+}
+func (t *approvalTestTool) Execute(ctx context.Context, args string) (string, error) {
+	return t.Run(ctx, args)
+}
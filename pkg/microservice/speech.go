@@ -0,0 +1,143 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/speech"
+)
+
+// transcriptionsPath is the endpoint transcribing audio uploads
+const transcriptionsPath = "/api/v1/audio/transcriptions"
+
+// maxAudioUploadSize limits audio uploads to the size speech APIs accept
+const maxAudioUploadSize = 25 << 20
+
+// TranscriptionResponse is the response of the transcription endpoint
+type TranscriptionResponse struct {
+	Text       string `json:"text"`
+	Language   string `json:"language,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+// SetSpeechToText sets the speech-to-text backend. It enables the
+// transcription endpoint, and audio content parts of run requests are
+// transcribed before the agent runs, so voice input works with any LLM.
+func (h *HTTPServer) SetSpeechToText(stt interfaces.SpeechToText) {
+	h.speechToText = stt
+}
+
+// handleTranscription transcribes an audio upload (POST
+// /api/v1/audio/transcriptions). The audio is sent as the multipart form file
+// "file" or as the raw request body with an audio Content-Type; the optional
+// "language" and "prompt" parameters guide the transcription.
+func (h *HTTPServer) handleTranscription(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.speechToText == nil {
+		http.Error(w, "Speech-to-text is not configured", http.StatusNotImplemented)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAudioUploadSize)
+	audio, mimeType, err := readAudioUpload(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var options []interfaces.TranscribeOption
+	if language := r.FormValue("language"); language != "" {
+		options = append(options, interfaces.WithTranscriptionLanguage(language))
+	}
+	if prompt := r.FormValue("prompt"); prompt != "" {
+		options = append(options, interfaces.WithTranscriptionPrompt(prompt))
+	}
+
+	transcription, err := h.speechToText.Transcribe(r.Context(), audio, mimeType, options...)
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(TranscriptionResponse{
+		Text:       transcription.Text,
+		Language:   transcription.Language,
+		DurationMs: transcription.Duration.Milliseconds(),
+	})
+}
+
+// readAudioUpload reads the audio of a transcription request and its MIME type
+func readAudioUpload(r *http.Request) ([]byte, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	if mediaType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", fmt.Errorf("missing audio file: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+
+		audio, err := io.ReadAll(file)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read audio: %w", err)
+		}
+		return audio, header.Header.Get("Content-Type"), checkAudio(audio, header.Header.Get("Content-Type"))
+	}
+
+	audio, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read audio: %w", err)
+	}
+	return audio, mediaType, checkAudio(audio, mediaType)
+}
+
+func checkAudio(audio []byte, mimeType string) error {
+	if len(audio) == 0 {
+		return fmt.Errorf("audio is empty")
+	}
+	if !strings.HasPrefix(mimeType, "audio/") && !strings.HasPrefix(mimeType, "video/") {
+		return fmt.Errorf("unsupported audio type %q", mimeType)
+	}
+	return nil
+}
+
+// transcribeAudio replaces the audio content parts of req with their
+// transcription when a speech-to-text backend is set. The transcription of
+// requests without input text becomes the input.
+func (h *HTTPServer) transcribeAudio(ctx context.Context, req *StreamRequest) error {
+	if h.speechToText == nil || !speech.HasAudio(req.ContentParts) {
+		return nil
+	}
+
+	parts, err := speech.TranscribeParts(ctx, h.speechToText, req.ContentParts)
+	if err != nil {
+		return err
+	}
+	if req.Input == "" {
+		var texts []string
+		var media []interfaces.ContentPart
+		for _, part := range parts {
+			if part.Type == interfaces.ContentPartText {
+				texts = append(texts, part.Text)
+			} else {
+				media = append(media, part)
+			}
+		}
+		req.Input, parts = strings.Join(texts, "\n"), media
+	}
+	req.ContentParts = parts
+	return req.validate()
+}
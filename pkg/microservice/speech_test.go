@@ -0,0 +1,120 @@
+package microservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+)
+
+// mockTranscriber transcribes any audio to a fixed text
+type mockTranscriber struct {
+	text     string
+	mimeType string
+	language string
+}
+
+func (m *mockTranscriber) Transcribe(ctx context.Context, audio []byte, mimeType string, options ...interfaces.TranscribeOption) (*interfaces.Transcription, error) {
+	var params interfaces.TranscribeOptions
+	for _, option := range options {
+		option(&params)
+	}
+	m.mimeType, m.language = mimeType, params.Language
+	return &interfaces.Transcription{Text: m.text, Language: params.Language}, nil
+}
+
+func TestHTTPServer_Transcription(t *testing.T) {
+	testAgent := createTestAgent("unused", nil)
+	server := NewHTTPServer(testAgent.(*MockStreamingAgent).Agent, 8080)
+
+	req := httptest.NewRequest("POST", transcriptionsPath, strings.NewReader("wav"))
+	req.Header.Set("Content-Type", "audio/wav")
+	w := httptest.NewRecorder()
+	server.handleTranscription(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501 without speech-to-text, got %d", w.Code)
+	}
+
+	stt := &mockTranscriber{text: "hello there"}
+	server.SetSpeechToText(stt)
+
+	// Raw audio body
+	req = httptest.NewRequest("POST", transcriptionsPath+"?language=en", strings.NewReader("wav"))
+	req.Header.Set("Content-Type", "audio/wav")
+	w = httptest.NewRecorder()
+	server.handleTranscription(w, req)
+
+	var response TranscriptionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v (%s)", err, w.Body.String())
+	}
+	if response.Text != "hello there" || response.Language != "en" || stt.mimeType != "audio/wav" {
+		t.Errorf("Unexpected response %+v (mime type %s)", response, stt.mimeType)
+	}
+
+	// Multipart upload
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="voice.webm"`)
+	header.Set("Content-Type", "audio/webm")
+	part, _ := writer.CreatePart(header)
+	_, _ = part.Write([]byte("webm"))
+	_ = writer.Close()
+
+	req = httptest.NewRequest("POST", transcriptionsPath, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w = httptest.NewRecorder()
+	server.handleTranscription(w, req)
+	if w.Code != http.StatusOK || stt.mimeType != "audio/webm" {
+		t.Errorf("Expected multipart upload to be transcribed, got %d (mime type %s)", w.Code, stt.mimeType)
+	}
+
+	// Non-audio body
+	req = httptest.NewRequest("POST", transcriptionsPath, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.handleTranscription(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for non-audio body, got %d", w.Code)
+	}
+}
+
+func TestHTTPServer_RunWithAudio(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.PromptEquals("what time is it?")).Respond("Noon")
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(llm),
+		agent.WithName("TestAgent"),
+		agent.WithRequirePlanApproval(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+	server.SetSpeechToText(&mockTranscriber{text: "what time is it?"})
+
+	body := `{"content_parts":[{"type":"audio","data":"d2F2","mime_type":"audio/wav"}]}`
+	req := httptest.NewRequest("POST", "/api/v1/agent/run", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleRun(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := llm.ExpectationsMet(); err != nil {
+		t.Errorf("Expected the transcription to be the input: %v", err)
+	}
+	if calls := llm.Calls(); len(calls) == 1 && len(calls[0].Request.Options.ContentParts) != 0 {
+		t.Errorf("Expected no audio to reach the LLM, got %+v", calls[0].Request.Options.ContentParts)
+	}
+}
@@ -0,0 +1,99 @@
+package microservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// StreamLimits configures the keep-alives and idle timeout of streaming runs
+type StreamLimits struct {
+	// HeartbeatInterval is how often an SSE comment is sent to stream
+	// clients, and a ping to WebSocket session clients while a turn runs, so
+	// that proxies do not close connections waiting on long tool executions
+	// (default: 15s)
+	HeartbeatInterval time.Duration
+
+	// IdleTimeout cancels a streaming run when the agent sends no event for
+	// this long, ending its stream with a timeout event. Zero disables it.
+	IdleTimeout time.Duration
+}
+
+// withDefaults returns the limits with zero values replaced by defaults
+func (l StreamLimits) withDefaults() StreamLimits {
+	if l.HeartbeatInterval <= 0 {
+		l.HeartbeatInterval = 15 * time.Second
+	}
+	return l
+}
+
+// SetStreamLimits sets the keep-alives and idle timeout of streaming runs
+func (h *HTTPServer) SetStreamLimits(limits StreamLimits) {
+	h.streamLimits = limits
+}
+
+// errStreamIdleTimeout ends the streaming runs the agent sent no event for
+// within the idle timeout
+var errStreamIdleTimeout = errors.New("stream idle timeout")
+
+// sendSSEHeartbeat sends an SSE comment, which clients ignore
+func sendSSEHeartbeat(w http.ResponseWriter, flusher http.Flusher) {
+	_, _ = fmt.Fprint(w, ": heartbeat\n\n")
+	flusher.Flush()
+}
+
+// watchIdle passes the events of a streaming run through until ctx is done.
+// When the agent sends no event within timeout, it cancels the run and ends
+// the stream with an error event wrapping errStreamIdleTimeout.
+func watchIdle(ctx context.Context, events <-chan interfaces.AgentStreamEvent, timeout time.Duration, cancel context.CancelFunc) <-chan interfaces.AgentStreamEvent {
+	if timeout <= 0 {
+		return events
+	}
+
+	out := make(chan interfaces.AgentStreamEvent, cap(events))
+	go func() {
+		defer close(out)
+		// Drop the remaining events on exit so the agent can exit when the
+		// run is cancelled or the reader is gone
+		defer func() {
+			for range events {
+			}
+		}()
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+				timer.Reset(timeout)
+			case <-timer.C:
+				cancel()
+				select {
+				case out <- interfaces.AgentStreamEvent{
+					Type:      interfaces.AgentEventError,
+					Error:     fmt.Errorf("%w: no event from the agent for %s", errStreamIdleTimeout, timeout),
+					Metadata:  map[string]interface{}{"idle_timeout_ms": timeout.Milliseconds()},
+					Timestamp: time.Now(),
+				}:
+				case <-ctx.Done():
+				}
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
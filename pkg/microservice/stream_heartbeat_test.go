@@ -0,0 +1,206 @@
+package microservice
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestHTTPServer_StreamHeartbeatAndIdleTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(&MockLLM{response: "unused"}),
+		agent.WithName("TestAgent"),
+		agent.WithCustomRunStreamFunction(func(ctx context.Context, input string, a *agent.Agent) (<-chan interfaces.AgentStreamEvent, error) {
+			events := make(chan interfaces.AgentStreamEvent)
+			go func() {
+				defer close(events)
+				events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventThinking, ThinkingStep: "calling a slow tool"}
+				// The tool hangs until the run is cancelled
+				<-ctx.Done()
+				close(cancelled)
+			}()
+			return events, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+	server.SetStreamLimits(StreamLimits{HeartbeatInterval: 10 * time.Millisecond, IdleTimeout: 200 * time.Millisecond})
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	resp, err := http.Post(httpServer.URL+"/api/v1/agent/stream", "application/json", strings.NewReader(`{"input":"work","run_id":"run-1"}`))
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var heartbeats int
+	var events []string
+	var timeout StreamEventData
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == ": heartbeat":
+			heartbeats++
+		case strings.HasPrefix(line, "event: "):
+			events = append(events, strings.TrimPrefix(line, "event: "))
+		case strings.HasPrefix(line, "data: ") && len(events) > 0 && events[len(events)-1] == "timeout":
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &timeout); err != nil {
+				t.Fatalf("Failed to decode timeout event: %v", err)
+			}
+		}
+	}
+
+	if strings.Join(events, ",") != "connected,thinking,timeout,done" {
+		t.Fatalf("Expected the stream to end with a timeout event, got %v", events)
+	}
+	if heartbeats == 0 {
+		t.Error("Expected heartbeats while the agent was idle")
+	}
+	if timeout.Type != "timeout" || !timeout.IsFinal || !strings.Contains(timeout.Error, "stream idle timeout") {
+		t.Errorf("Unexpected timeout event %+v", timeout)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the idle run to be cancelled")
+	}
+	statusResp, err := http.Get(httpServer.URL + agentRunsPath + "run-1")
+	if err != nil {
+		t.Fatalf("Failed to get run: %v", err)
+	}
+	defer statusResp.Body.Close()
+	var status RunStatus
+	if err := json.NewDecoder(statusResp.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode run status: %v", err)
+	}
+	if status.State != RunStateFailed || !strings.Contains(status.Error, "stream idle timeout") {
+		t.Errorf("Expected the run to fail with the idle timeout, got %+v", status)
+	}
+}
+
+func TestWatchIdle_PassesEventsThrough(t *testing.T) {
+	events := make(chan interfaces.AgentStreamEvent)
+	out := watchIdle(context.Background(), events, 50*time.Millisecond, func() { t.Error("Run cancelled while the agent sends events") })
+
+	go func() {
+		defer close(events)
+		for i := 0; i < 5; i++ {
+			time.Sleep(20 * time.Millisecond)
+			events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent}
+		}
+	}()
+
+	var count int
+	for event := range out {
+		if event.Type != interfaces.AgentEventContent {
+			t.Fatalf("Unexpected event %+v", event)
+		}
+		count++
+	}
+	if count != 5 {
+		t.Errorf("Expected 5 events, got %d", count)
+	}
+}
+
+func TestWatchIdle_StopsWhenReaderIsGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan interfaces.AgentStreamEvent)
+	out := watchIdle(ctx, events, time.Minute, func() {})
+
+	// The reader takes one event and disconnects
+	produced := make(chan struct{})
+	go func() {
+		defer close(produced)
+		defer close(events)
+		for i := 0; i < 5; i++ {
+			events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent}
+		}
+	}()
+	<-out
+	cancel()
+
+	select {
+	case <-produced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the agent to send its remaining events after the reader left")
+	}
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for range out {
+		}
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the stream to end after the reader left")
+	}
+}
+
+func TestSession_Heartbeat(t *testing.T) {
+	release := make(chan struct{})
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(&MockLLM{response: "unused"}),
+		agent.WithName("SessionAgent"),
+		agent.WithCustomRunStreamFunction(func(ctx context.Context, input string, a *agent.Agent) (<-chan interfaces.AgentStreamEvent, error) {
+			events := make(chan interfaces.AgentStreamEvent, 1)
+			go func() {
+				defer close(events)
+				// A slow tool keeps the turn silent for a while
+				select {
+				case <-release:
+				case <-ctx.Done():
+					return
+				}
+				events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventComplete}
+			}()
+			return events, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+	server.SetStreamLimits(StreamLimits{HeartbeatInterval: 10 * time.Millisecond})
+	ts := httptest.NewServer(http.HandlerFunc(server.handleSession))
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Failed to dial session: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	var pings atomic.Int32
+	conn.SetPingHandler(func(data string) error {
+		if pings.Add(1) == 3 {
+			close(release)
+		}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	if err := conn.WriteJSON(SessionClientMessage{Type: SessionMessageTurn, Input: "work"}); err != nil {
+		t.Fatalf("Failed to send turn: %v", err)
+	}
+	readUntil(t, conn, SessionEventTurnCompleted)
+	if pings.Load() < 3 {
+		t.Errorf("Expected heartbeats while the turn was silent, got %d", pings.Load())
+	}
+}
@@ -0,0 +1,244 @@
+package microservice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// lastEventIDHeader carries the ID of the last event a client received when
+// it resumes a stream
+const lastEventIDHeader = "Last-Event-ID"
+
+// streamResumeTimeout is how long a streaming run goes on after its client
+// disconnected, waiting for the client to resume the stream
+const streamResumeTimeout = 30 * time.Second
+
+// maxBufferedEvents is the number of recent events kept per streaming run for
+// resuming clients
+const maxBufferedEvents = 1000
+
+// bufferedEvent is an event of a streaming run with its ID
+type bufferedEvent struct {
+	id        int
+	eventType string
+	data      StreamEventData
+}
+
+// eventBuffer keeps the recent events of a streaming run, so that a client
+// losing its connection can resume the stream after the last event it
+// received. A run without clients is cancelled after streamResumeTimeout.
+type eventBuffer struct {
+	mu        sync.Mutex
+	events    []bufferedEvent
+	lastID    int
+	finished  bool
+	changed   chan struct{} // closed and replaced when events are added
+	followers int
+	abandon   *time.Timer
+	cancel    context.CancelFunc
+	timeout   time.Duration
+}
+
+func newEventBuffer(cancel context.CancelFunc) *eventBuffer {
+	return &eventBuffer{changed: make(chan struct{}), cancel: cancel, timeout: streamResumeTimeout}
+}
+
+// add appends an event, dropping the oldest one when the buffer is full
+func (b *eventBuffer) add(eventType string, data StreamEventData) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	data.Timestamp = time.Now().UnixMilli()
+	b.lastID++
+	b.events = append(b.events, bufferedEvent{id: b.lastID, eventType: eventType, data: data})
+	if len(b.events) > maxBufferedEvents {
+		b.events = b.events[len(b.events)-maxBufferedEvents:]
+	}
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// finish marks the end of the stream
+func (b *eventBuffer) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.finished = true
+	if b.abandon != nil {
+		b.abandon.Stop()
+	}
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// since returns the buffered events after lastID, whether the stream is
+// finished, and a channel closed when there is more
+func (b *eventBuffer) since(lastID int) ([]bufferedEvent, bool, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var events []bufferedEvent
+	for _, event := range b.events {
+		if event.id > lastID {
+			events = append(events, event)
+		}
+	}
+	return events, b.finished, b.changed
+}
+
+// attach registers a client following the stream
+func (b *eventBuffer) attach() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.followers++
+	if b.abandon != nil {
+		b.abandon.Stop()
+		b.abandon = nil
+	}
+}
+
+// detach unregisters a client, cancelling the run if no client resumes the
+// stream in time
+func (b *eventBuffer) detach() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.followers--
+	if b.followers > 0 || b.finished {
+		return
+	}
+	b.abandon = time.AfterFunc(b.timeout, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.followers == 0 && !b.finished {
+			b.cancel()
+		}
+	})
+}
+
+// bufferEvents converts the events of a streaming run into SSE events,
+// ending with a done event
+func (h *HTTPServer) bufferEvents(buffer *eventBuffer, events <-chan interfaces.AgentStreamEvent) {
+	for event := range events {
+		eventData := h.convertAgentEventToHTTPEvent(event)
+
+		// Determine event type for SSE
+		var sseEventType string
+		switch event.Type {
+		case interfaces.AgentEventContent:
+			sseEventType = "content"
+		case interfaces.AgentEventThinking:
+			sseEventType = "thinking"
+		case interfaces.AgentEventToolCall:
+			sseEventType = "tool_call"
+		case interfaces.AgentEventToolResult:
+			sseEventType = "tool_result"
+		case interfaces.AgentEventError:
+			sseEventType = "error"
+			if errors.Is(event.Error, errStreamIdleTimeout) {
+				sseEventType = "timeout"
+				eventData.Type = sseEventType
+				eventData.IsFinal = true
+			}
+		case interfaces.AgentEventHandoff:
+			sseEventType = "handoff"
+		case interfaces.AgentEventPlanStep:
+			sseEventType = "plan_step"
+		case interfaces.AgentEventComplete:
+			sseEventType = "complete"
+			eventData.IsFinal = true
+		default:
+			sseEventType = "content"
+		}
+		buffer.add(sseEventType, eventData)
+	}
+
+	// Send final completion event
+	buffer.add("done", StreamEventData{
+		Type:    "done",
+		IsFinal: true,
+	})
+	buffer.finish()
+}
+
+// followStream sends the events of a streaming run after lastID until the
+// stream ends or the client disconnects, with heartbeats in between
+func (h *HTTPServer) followStream(w http.ResponseWriter, r *http.Request, flusher http.Flusher, buffer *eventBuffer, lastID int) {
+	buffer.attach()
+	defer buffer.detach()
+
+	heartbeat := time.NewTicker(h.streamLimits.withDefaults().HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		events, finished, changed := buffer.since(lastID)
+		for _, event := range events {
+			h.sendSSEEventWithID(w, flusher, event.eventType, event.data, strconv.Itoa(event.id))
+			lastID = event.id
+		}
+		if finished {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-heartbeat.C:
+			sendSSEHeartbeat(w, flusher)
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// resumeStream resumes the stream of a run after the event the client
+// received last, from the Last-Event-ID header or the last_event_id query
+// parameter. Events dropped from the buffer of a long run are skipped.
+func (h *HTTPServer) resumeStream(w http.ResponseWriter, r *http.Request, runID, orgID string) {
+	lastEventID := r.Header.Get(lastEventIDHeader)
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	lastID := 0
+	if lastEventID != "" {
+		id, err := strconv.Atoi(lastEventID)
+		if err != nil || id < 0 {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		lastID = id
+	}
+
+	buffer, err := h.runs.events(runID, orgID)
+	if err != nil {
+		http.Error(w, "Stream not found", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "SSE not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.Header().Set(runIDHeader, runID)
+
+	h.sendSSEEvent(w, flusher, "connected", StreamEventData{
+		Type: "connected",
+		Metadata: map[string]interface{}{
+			"agent":   h.Agent().GetName(),
+			"run_id":  runID,
+			"resumed": true,
+		},
+	})
+	h.followStream(w, r, flusher, buffer, lastID)
+}
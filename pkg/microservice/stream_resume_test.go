@@ -0,0 +1,156 @@
+package microservice
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// sseEvent is an event read from an SSE response
+type sseEvent struct {
+	id, event, data string
+}
+
+// readSSE reads events from an SSE response until stop returns true or the
+// stream ends
+func readSSE(t *testing.T, resp *http.Response, stop func(sseEvent) bool) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			events = append(events, current)
+			if stop(current) {
+				break
+			}
+			current = sseEvent{}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			current.id = value
+		case "event":
+			current.event = value
+		case "data":
+			current.data = value
+		}
+	}
+	return events
+}
+
+func TestHTTPServer_StreamResumption(t *testing.T) {
+	resume := make(chan struct{})
+	agentInstance, err := agent.NewAgent(
+		agent.WithLLM(&MockLLM{response: "unused"}),
+		agent.WithName("TestAgent"),
+		agent.WithCustomRunStreamFunction(func(ctx context.Context, input string, a *agent.Agent) (<-chan interfaces.AgentStreamEvent, error) {
+			events := make(chan interfaces.AgentStreamEvent)
+			go func() {
+				defer close(events)
+				events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: "one "}
+				events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: "two "}
+				select {
+				case <-resume:
+				case <-ctx.Done():
+					return
+				}
+				events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: "three"}
+				events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventComplete}
+			}()
+			return events, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	server := NewHTTPServer(agentInstance, 8080)
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	resp, err := http.Post(httpServer.URL+"/api/v1/agent/stream", "application/json", strings.NewReader(`{"input":"count","run_id":"run-1"}`))
+	if err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+	events := readSSE(t, resp, func(event sseEvent) bool { return event.id == "2" })
+	// The connection drops mid-run
+	resp.Body.Close()
+	if len(events) != 3 || !strings.Contains(events[2].data, "two") {
+		t.Fatalf("Expected the first two events, got %+v", events)
+	}
+	close(resume)
+
+	req, _ := http.NewRequest("POST", httpServer.URL+"/api/v1/agent/stream", strings.NewReader(`{"run_id":"run-1"}`))
+	req.Header.Set(lastEventIDHeader, "2")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to resume stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	events = readSSE(t, resp, func(event sseEvent) bool { return event.event == "done" })
+
+	var ids []string
+	for _, event := range events[1:] {
+		ids = append(ids, event.id)
+	}
+	if events[0].event != "connected" || strings.Join(ids, ",") != "3,4,5" {
+		t.Fatalf("Expected the events after the last one received, got %+v", events)
+	}
+	if !strings.Contains(events[1].data, "three") || events[2].event != "complete" {
+		t.Errorf("Unexpected resumed events %+v", events)
+	}
+
+	// A finished run can be replayed from its buffer
+	resp, err = http.Get(httpServer.URL + agentRunsPath + "run-1/events?last_event_id=0")
+	if err != nil {
+		t.Fatalf("Failed to replay stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if events := readSSE(t, resp, func(sseEvent) bool { return false }); len(events) != 6 {
+		t.Errorf("Expected the connected event and 5 buffered events, got %+v", events)
+	}
+
+	resp, err = http.Get(httpServer.URL + agentRunsPath + "unknown/events")
+	if err != nil {
+		t.Fatalf("Failed to request stream: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown run, got %d", resp.StatusCode)
+	}
+}
+
+func TestEventBuffer_CancelsAbandonedRun(t *testing.T) {
+	cancelled := make(chan struct{})
+	buffer := newEventBuffer(func() { close(cancelled) })
+	buffer.timeout = 20 * time.Millisecond
+
+	// A client resuming in time keeps the run going
+	buffer.attach()
+	buffer.detach()
+	buffer.attach()
+	select {
+	case <-cancelled:
+		t.Fatal("Run cancelled while a client follows it")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	buffer.detach()
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the abandoned run to be cancelled")
+	}
+}
@@ -0,0 +1,142 @@
+package microservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// adminOrgsPath is the base path of the organization management endpoints
+const adminOrgsPath = "/api/v1/admin/orgs"
+
+// OrgRequest is the JSON body for creating an organization
+type OrgRequest struct {
+	ID    string                    `json:"id"`
+	Name  string                    `json:"name,omitempty"`
+	Quota *multitenancy.QuotaLimits `json:"quota,omitempty"`
+}
+
+// isAdminPath returns true for the paths of the admin endpoints, which
+// authenticate with the admin token instead of tenant keys
+func isAdminPath(path string) bool {
+	return strings.HasPrefix(path, adminKeysPath) || strings.HasPrefix(path, adminOrgsPath)
+}
+
+// registerOrgEndpoints registers the organization management endpoints when
+// a tenant manager is configured
+func (h *HTTPServer) registerOrgEndpoints(mux *http.ServeMux) {
+	if h.auth.Tenants == nil {
+		return
+	}
+
+	mux.HandleFunc(adminOrgsPath, h.withAdminToken(h.handleAdminOrgs))
+	mux.HandleFunc(adminOrgsPath+"/", h.withAdminToken(h.handleAdminOrg))
+}
+
+// handleAdminOrgs creates (POST) and lists (GET) organizations
+func (h *HTTPServer) handleAdminOrgs(w http.ResponseWriter, r *http.Request) {
+	tenants := h.auth.Tenants
+
+	switch r.Method {
+	case "GET":
+		orgs, err := tenants.ListOrgs(r.Context())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to list organizations: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"orgs": orgs,
+		})
+
+	case "POST":
+		var req OrgRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.ID == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+
+		options := []multitenancy.OrgOption{multitenancy.WithOrgName(req.Name)}
+		if req.Quota != nil {
+			options = append(options, multitenancy.WithOrgQuota(*req.Quota))
+		}
+		org, err := tenants.CreateOrg(r.Context(), req.ID, options...)
+		if err != nil {
+			writeAdminOrgError(w, "Failed to create organization", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(org)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAdminOrg returns (GET /api/v1/admin/orgs/{id}) or disables (DELETE)
+// an organization, or sets its quota (PUT /api/v1/admin/orgs/{id}/quota)
+func (h *HTTPServer) handleAdminOrg(w http.ResponseWriter, r *http.Request) {
+	tenants := h.auth.Tenants
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, adminOrgsPath+"/"), "/")
+	if id == "" {
+		http.Error(w, "Organization ID is required", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case r.Method == "GET" && action == "":
+		org, err := tenants.GetOrg(r.Context(), id)
+		if err != nil {
+			writeAdminOrgError(w, "Failed to get organization", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(org)
+
+	case r.Method == "DELETE" && action == "":
+		if err := tenants.DisableOrg(r.Context(), id); err != nil {
+			writeAdminOrgError(w, "Failed to disable organization", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "PUT" && action == "quota":
+		var limits *multitenancy.QuotaLimits
+		if err := json.NewDecoder(r.Body).Decode(&limits); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		org, err := tenants.SetQuota(r.Context(), id, limits)
+		if err != nil {
+			writeAdminOrgError(w, "Failed to set quota", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(org)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeAdminOrgError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, multitenancy.ErrOrgNotFound):
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusNotFound)
+	case errors.Is(err, multitenancy.ErrOrgExists), errors.Is(err, multitenancy.ErrOrgDisabled):
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,97 @@
+package microservice
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestTenantManagement(t *testing.T) {
+	server := NewHTTPServer(createTestAgent("Hello, world!", nil).(*MockStreamingAgent).Agent, 8080)
+	server.EnableAPIKeyAuth(APIKeyAuthConfig{Tenants: multitenancy.NewTenantManager(nil, nil), AdminToken: "admin-secret"})
+	server.EnableQuotas(QuotaConfig{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/agent/metadata", server.handleMetadata)
+	mux.HandleFunc(quotaPath, server.handleQuota)
+	server.registerAdminEndpoints(mux)
+	handler := server.withAuth(server.withQuotas(mux))
+
+	admin := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	withKey := func(path, secret string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-API-Key", secret)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := admin("POST", adminKeysPath, APIKeyRequest{OrgID: "tenant-a", Name: "ci"}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 issuing a key for an unknown organization, got %d", w.Code)
+	}
+	if w := admin("POST", adminOrgsPath, OrgRequest{ID: "tenant-a", Name: "Tenant A"}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 creating an organization, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := admin("POST", adminOrgsPath, OrgRequest{ID: "tenant-a"}); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for an existing organization, got %d", w.Code)
+	}
+
+	w := admin("POST", adminKeysPath, APIKeyRequest{OrgID: "tenant-a", Name: "ci"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 issuing a key, got %d: %s", w.Code, w.Body.String())
+	}
+	var created APIKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	// Keep the requests in the same minute window
+	if time.Until(time.Now().Truncate(time.Minute).Add(time.Minute)) < 2*time.Second {
+		time.Sleep(2 * time.Second)
+	}
+
+	// The quota set for the organization applies to requests with its keys
+	if w := admin("PUT", adminOrgsPath+"/tenant-a/quota", QuotaLimits{RequestsPerMinute: 2}); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 setting the quota, got %d: %s", w.Code, w.Body.String())
+	}
+	w = withKey(quotaPath, created.Secret)
+	var usage QuotaUsage
+	if err := json.Unmarshal(w.Body.Bytes(), &usage); err != nil {
+		t.Fatalf("Failed to unmarshal quota usage: %v", err)
+	}
+	if usage.OrgID != "tenant-a" || usage.RequestsPerMinute == nil || usage.RequestsPerMinute.Limit != 2 {
+		t.Errorf("Expected the quota of the key's organization, got %+v", usage)
+	}
+	for i := 0; i < 2; i++ {
+		withKey("/api/v1/agent/metadata", created.Secret)
+	}
+	if w := withKey("/api/v1/agent/metadata", created.Secret); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 over the organization's quota, got %d", w.Code)
+	}
+
+	// Disabling the organization revokes its keys
+	if w := admin("DELETE", adminOrgsPath+"/tenant-a", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204 disabling the organization, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := withKey("/api/v1/agent/metadata", created.Secret); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a key of a disabled organization, got %d", w.Code)
+	}
+
+	w = admin("GET", adminOrgsPath+"/tenant-a", nil)
+	var org multitenancy.Organization
+	if err := json.Unmarshal(w.Body.Bytes(), &org); err != nil || !org.IsDisabled() {
+		t.Errorf("Expected a disabled organization, got %s (%v)", w.Body.String(), err)
+	}
+}
@@ -1,11 +1,12 @@
 'use client';
 
-import React from 'react';
-import { MemoryEntry } from '@/types/agent';
+import React, { useState, useEffect } from 'react';
+import { Artifact, MemoryEntry } from '@/types/agent';
+import { agentAPI } from '@/lib/api';
 import { Card, CardContent, CardHeader } from '@/components/ui/card';
 import { Badge } from '@/components/ui/badge';
 import { Button } from '@/components/ui/button';
-import { User, Bot, MessageSquare, ArrowLeft, ChevronLeft, ChevronRight } from 'lucide-react';
+import { User, Bot, MessageSquare, ArrowLeft, ChevronLeft, ChevronRight, Paperclip } from 'lucide-react';
 
 interface ConversationDetailProps {
   conversationId: string;
@@ -32,6 +33,29 @@ export function ConversationDetail({
   onPrevPage,
   onNextPage
 }: ConversationDetailProps) {
+  const [artifacts, setArtifacts] = useState<Artifact[]>([]);
+
+  // Artifacts are only available when the agent has an artifact store
+  useEffect(() => {
+    let cancelled = false;
+    agentAPI.getConversationArtifacts(conversationId)
+      .then((list) => {
+        if (!cancelled) setArtifacts(list.artifacts);
+      })
+      .catch(() => {
+        if (!cancelled) setArtifacts([]);
+      });
+    return () => {
+      cancelled = true;
+    };
+  }, [conversationId]);
+
+  const formatSize = (size: number) => {
+    if (size < 1024) return `${size} B`;
+    if (size < 1024 * 1024) return `${(size / 1024).toFixed(1)} KB`;
+    return `${(size / (1024 * 1024)).toFixed(1)} MB`;
+  };
+
   const formatTimestamp = (timestamp: number) => {
     return new Date(timestamp * 1000).toLocaleString();
   };
@@ -93,6 +117,34 @@ export function ConversationDetail({
         </div>
       </div>
 
+      {/* Artifacts */}
+      {artifacts.length > 0 && (
+        <div className="p-4 border-b border-border">
+          <div className="flex items-center gap-2 mb-2">
+            <Paperclip className="h-4 w-4" />
+            <h3 className="text-sm font-semibold">Artifacts</h3>
+            <Badge variant="secondary">{artifacts.length}</Badge>
+          </div>
+          <div className="space-y-1">
+            {artifacts.map((artifact) => (
+              <div key={artifact.id} className="flex items-center justify-between text-sm">
+                <a
+                  href={artifact.url}
+                  target="_blank"
+                  rel="noopener noreferrer"
+                  className="truncate text-primary hover:underline"
+                >
+                  {artifact.name || artifact.url.split('/').pop()?.split('?')[0] || artifact.id}
+                </a>
+                <span className="text-xs text-muted-foreground ml-2 whitespace-nowrap">
+                  {artifact.tool && `${artifact.tool} • `}{artifact.mime_type} • {formatSize(artifact.size)}
+                </span>
+              </div>
+            ))}
+          </div>
+        </div>
+      )}
+
       {/* Messages */}
       <div className="flex-1 overflow-auto">
         <div className="p-4">
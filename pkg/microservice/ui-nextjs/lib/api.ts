@@ -5,6 +5,11 @@ import {
   MemoryEntry,
   MemoryResponse,
   ConversationInfo,
+  ArtifactList,
+  UISession,
+  UISessionList,
+  UISessionDetail,
+  UISessionUpdate,
   RunRequest,
   StreamRequest,
   RunResponse,
@@ -92,6 +97,48 @@ class AgentAPI {
     return this.get(`/memory?conversation_id=${conversationId}&limit=${limit}&offset=${offset}`);
   }
 
+  // Artifacts produced by tools in a conversation, newest first
+  async getConversationArtifacts(conversationId: string, limit = 100, offset = 0): Promise<ArtifactList> {
+    return this.get(`/conversations/${encodeURIComponent(conversationId)}/artifacts?limit=${limit}&offset=${offset}`);
+  }
+
+  // Chat sessions kept server-side, shared across refreshes and devices
+  async getSessions(limit = 100, offset = 0): Promise<UISessionList> {
+    return this.get(`/ui/sessions?limit=${limit}&offset=${offset}`);
+  }
+
+  async getSession(conversationId: string): Promise<UISessionDetail> {
+    return this.get(`/ui/sessions/${encodeURIComponent(conversationId)}`);
+  }
+
+  async createSession(data: UISessionUpdate = {}): Promise<UISession> {
+    return this.post<UISession>('/ui/sessions', data);
+  }
+
+  async updateSession(conversationId: string, data: UISessionUpdate): Promise<UISession> {
+    const response = await fetch(`${this.baseUrl}/ui/sessions/${encodeURIComponent(conversationId)}`, {
+      method: 'PATCH',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(data),
+    });
+    if (!response.ok) {
+      throw new Error(`Update session failed: ${response.status} ${response.statusText}`);
+    }
+    return response.json();
+  }
+
+  async deleteSession(conversationId: string): Promise<{ conversation_id: string; deleted: boolean }> {
+    const response = await fetch(`${this.baseUrl}/ui/sessions/${encodeURIComponent(conversationId)}`, {
+      method: 'DELETE',
+    });
+    if (!response.ok) {
+      throw new Error(`Delete session failed: ${response.status} ${response.statusText}`);
+    }
+    return response.json();
+  }
+
   async searchMemory(query: string): Promise<{
     query: string;
     results: MemoryEntry[];
@@ -145,6 +145,61 @@ export interface MemoryResponse {
   conversation_id?: string;
 }
 
+export interface Artifact {
+  id: string;
+  url: string;
+  name?: string;
+  mime_type: string;
+  size: number;
+  org_id?: string;
+  thread_id?: string;
+  tool?: string;
+  metadata?: Record<string, string>;
+  created_at: string;
+}
+
+export interface ArtifactList {
+  conversation_id: string;
+  artifacts: Artifact[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
+export interface PinnedMessage {
+  index: number;
+  role: string;
+  content: string;
+  pinned_at: string;
+}
+
+export interface UISession {
+  conversation_id: string;
+  title: string;
+  pinned_messages?: PinnedMessage[];
+  created_at: string;
+  updated_at: string;
+  message_count: number;
+}
+
+export interface UISessionList {
+  sessions: UISession[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
+export interface UISessionDetail extends UISession {
+  messages: { Role: string; Content: string }[];
+}
+
+export interface UISessionUpdate {
+  conversation_id?: string;
+  title?: string;
+  pin?: number[];
+  unpin?: number[];
+}
+
 export interface ChatMessage {
   role: 'user' | 'assistant' | 'system';
   content: string;
@@ -195,6 +250,7 @@ export interface RunResponse {
   output: string;
   agent: string;
   metadata?: Record<string, unknown>;
+  artifacts?: Artifact[];
 }
 
 // Trace-related types
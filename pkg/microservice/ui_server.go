@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
@@ -178,14 +179,12 @@ func NewHTTPServerWithUI(agent *agent.Agent, port int, config *UIConfig) *HTTPSe
 	}
 
 	server := &HTTPServerWithUI{
-		HTTPServer: HTTPServer{
-			agent: agent,
-			port:  port,
-		},
+		HTTPServer:          HTTPServer{port: port},
 		uiConfig:            config,
 		uiFS:                uiFS,
 		conversationHistory: make([]MemoryEntry, 0),
 	}
+	server.agent.Store(agent)
 
 	// Initialize trace collector if enabled
 	if config.Features.Traces && config.Tracing != nil && config.Tracing.Enabled {
@@ -214,11 +213,13 @@ func NewHTTPServerWithUI(agent *agent.Agent, port int, config *UIConfig) *HTTPSe
 func (h *HTTPServerWithUI) Start() error {
 	mux := http.NewServeMux()
 
-	// Add CORS middleware
-	corsHandler := h.addCORS(mux)
+	// Add CORS, draining, agent holding, authentication and quota middleware
+	corsHandler := h.addCORS(h.withDraining(h.holdingAgent(h.withAuth(h.withQuotas(mux)))))
 
 	// Register API endpoints
 	h.registerAPIEndpoints(mux)
+	h.registerAdminEndpoints(mux)
+	mux.HandleFunc(reloadPath, h.withoutTenantKey(h.handleReload))
 
 	// Debug endpoint to list embedded files
 	mux.HandleFunc("/debug/files", func(w http.ResponseWriter, r *http.Request) {
@@ -285,7 +286,8 @@ func (h *HTTPServerWithUI) Start() error {
 	fmt.Printf("  - POST /api/v1/agent/run (non-streaming)\n")
 	fmt.Printf("  - POST /api/v1/agent/stream (SSE streaming)\n")
 	fmt.Printf("  - GET /api/v1/agent/metadata\n")
-	fmt.Printf("  - GET /health\n")
+	fmt.Printf("  - GET /health, /livez, /readyz\n")
+	fmt.Printf("  - GET /openapi.json (OpenAPI 3 document)\n")
 
 	if h.uiConfig.Enabled {
 		fmt.Printf("UI-specific endpoints:\n")
@@ -312,19 +314,33 @@ func (h *HTTPServerWithUI) Start() error {
 func (h *HTTPServerWithUI) registerAPIEndpoints(mux *http.ServeMux) {
 	// Health check (always available)
 	mux.HandleFunc("/health", h.handleHealth)
+	h.registerProbeEndpoints(mux)
+	mux.HandleFunc(openAPIPath, h.handleOpenAPI)
 
 	// Core agent endpoints (always available)
 	mux.HandleFunc("/api/v1/agent/run", h.withOrgContext(h.handleRun))
 	mux.HandleFunc("/api/v1/agent/stream", h.withOrgContext(h.handleStream))
 	mux.HandleFunc("/api/v1/agent/metadata", h.handleMetadata)
+	mux.HandleFunc("/api/v1/agent/milestones", h.withOrgContext(h.handleMilestones))
+	mux.HandleFunc(agentRunsPath, h.withOrgContext(h.handleAgentRun))
+	mux.HandleFunc(agentJobsPath, h.withOrgContext(h.handleJobs))
+	mux.HandleFunc(quotaPath, h.handleQuota)
+	mux.HandleFunc(transcriptionsPath, h.handleTranscription)
+	mux.HandleFunc(uploadsPath, h.withOrgContext(h.handleUpload))
+	mux.HandleFunc(conversationsListPath, h.withOrgContext(h.handleConversations))
+	mux.HandleFunc(conversationsPath, h.withOrgContext(h.handleConversation))
+	mux.HandleFunc(agentWebSocketPath, h.handleSession)
+	mux.HandleFunc(realtimePath, h.handleRealtime)
 
 	// UI-specific endpoints (only when UI is enabled)
 	if h.uiConfig.Enabled {
 		mux.HandleFunc("/api/v1/agent/config", h.handleConfig)
 		mux.HandleFunc("/api/v1/agent/subagents", h.handleSubAgents)
 		mux.HandleFunc("/api/v1/agent/delegate", h.withOrgContext(h.handleDelegate))
-		mux.HandleFunc("/api/v1/memory", h.withOrgContext(h.handleMemory))
-		mux.HandleFunc("/api/v1/memory/search", h.withOrgContext(h.handleMemorySearch))
+		mux.HandleFunc("/api/v1/memory", h.withoutTenantKey(h.withOrgContext(h.handleMemory)))
+		mux.HandleFunc("/api/v1/memory/search", h.withoutTenantKey(h.withOrgContext(h.handleMemorySearch)))
+		mux.HandleFunc(uiSessionsListPath, h.withOrgContext(h.handleUISessions))
+		mux.HandleFunc(uiSessionsPath, h.withOrgContext(h.handleUISession))
 		mux.HandleFunc("/api/v1/tools", h.handleTools)
 		mux.HandleFunc("/ws/chat", h.handleWebSocketChat)
 
@@ -363,8 +379,8 @@ func (h *HTTPServerWithUI) handleConfig(w http.ResponseWriter, r *http.Request)
 	datastoreInfo := h.getDataStoreInfo()
 
 	response := AgentConfigResponse{
-		Name:         h.agent.GetName(),
-		Description:  h.agent.GetDescription(),
+		Name:         h.Agent().GetName(),
+		Description:  h.Agent().GetDescription(),
 		Model:        model,
 		SystemPrompt: systemPrompt,
 		Tools:        tools,
@@ -514,7 +530,7 @@ func (h *HTTPServerWithUI) handleTools(w http.ResponseWriter, r *http.Request) {
 	tools := []map[string]interface{}{}
 
 	// Check if agent is remote and handle accordingly
-	if h.agent.IsRemote() {
+	if h.Agent().IsRemote() {
 		// For remote agents, get tools from system prompt or use alternative method
 		// Parse system prompt to extract tool information
 		systemPrompt := h.getSystemPrompt()
@@ -528,7 +544,7 @@ func (h *HTTPServerWithUI) handleTools(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		// Get tools from local agent
-		agentTools := h.agent.GetTools()
+		agentTools := h.Agent().GetTools()
 		for _, tool := range agentTools {
 			tools = append(tools, map[string]interface{}{
 				"name":        tool.Name(),
@@ -548,9 +564,7 @@ func (h *HTTPServerWithUI) handleTools(w http.ResponseWriter, r *http.Request) {
 
 // handleWebSocketChat handles WebSocket connections for real-time chat
 func (h *HTTPServerWithUI) handleWebSocketChat(w http.ResponseWriter, r *http.Request) {
-	// WebSocket implementation would go here
-	// For now, return not implemented
-	http.Error(w, "WebSocket not yet implemented", http.StatusNotImplemented)
+	h.handleSession(w, r)
 }
 
 // getSubAgentsList returns list of sub-agents
@@ -558,7 +572,7 @@ func (h *HTTPServerWithUI) getSubAgentsList() []SubAgentInfo {
 	subAgents := []SubAgentInfo{}
 
 	// Check if agent is remote
-	if h.agent.IsRemote() {
+	if h.Agent().IsRemote() {
 		// For remote agents, parse from system prompt
 		systemPrompt := h.getSystemPrompt()
 		toolNames := h.parseToolsFromSystemPrompt(systemPrompt)
@@ -580,7 +594,7 @@ func (h *HTTPServerWithUI) getSubAgentsList() []SubAgentInfo {
 		}
 	} else {
 		// Get sub-agents directly from the agent instance
-		agentSubAgents := h.agent.GetSubAgents()
+		agentSubAgents := h.Agent().GetSubAgents()
 		for _, subAgent := range agentSubAgents {
 			subAgentInfo := SubAgentInfo{
 				ID:           subAgent.GetName(),
@@ -595,7 +609,7 @@ func (h *HTTPServerWithUI) getSubAgentsList() []SubAgentInfo {
 		}
 
 		// Also check tools for sub-agent tools (tools that end with _agent)
-		tools := h.agent.GetTools()
+		tools := h.Agent().GetTools()
 		for _, tool := range tools {
 			toolName := tool.Name()
 			// Check if this tool represents a sub-agent (ends with _agent)
@@ -731,7 +745,7 @@ func (h *HTTPServerWithUI) getToolDescriptionFromSystemPrompt(toolName, systemPr
 // getConversationHistory returns conversation history with pagination
 func (h *HTTPServerWithUI) getConversationHistory(limit, offset int) []MemoryEntry {
 	// First, try to get from agent's memory system if available
-	if memGetter, ok := interface{}(h.agent).(interface{ GetMemory() interfaces.Memory }); ok {
+	if memGetter, ok := interface{}(h.Agent()).(interface{ GetMemory() interfaces.Memory }); ok {
 		if mem := memGetter.GetMemory(); mem != nil {
 			return h.getMemoryFromAgent(mem, limit, offset)
 		}
@@ -775,13 +789,13 @@ func (h *HTTPServerWithUI) getConversationMessagesWithContext(ctx context.Contex
 // getAllConversationsFromAllOrgs gets conversations from all organizations
 func (h *HTTPServerWithUI) getAllConversationsFromAllOrgs(limit, offset int) MemoryResponse {
 	// Handle remote agents by making HTTP calls to their memory endpoint
-	if h.agent.IsRemote() {
+	if h.Agent().IsRemote() {
 		log.Println("Fetching conversations from remote agent memory")
 		return h.getRemoteMemoryConversations(limit, offset)
 	}
 
 	// Check if memory supports cross-org operations
-	if adminMem, ok := h.agent.GetMemory().(interfaces.AdminConversationMemory); ok {
+	if adminMem, ok := h.Agent().GetMemory().(interfaces.AdminConversationMemory); ok {
 		log.Println("Fetching conversations from admin conversation memory across all orgs")
 		return h.buildConversationListFromAllOrgs(adminMem, limit, offset)
 	}
@@ -793,13 +807,13 @@ func (h *HTTPServerWithUI) getAllConversationsFromAllOrgs(limit, offset int) Mem
 // getConversationMessagesFromAllOrgs searches for conversation across all orgs
 func (h *HTTPServerWithUI) getConversationMessagesFromAllOrgs(conversationID string, limit, offset int) MemoryResponse {
 	// Handle remote agents by making HTTP calls to their memory endpoint
-	if h.agent.IsRemote() {
+	if h.Agent().IsRemote() {
 		log.Printf("Fetching messages for conversation %s from remote agent memory", conversationID) // #nosec G706 - conversationID is a UUID from internal routing
 		return h.getRemoteMemoryMessages(conversationID, limit, offset)
 	}
 
 	// Check if memory supports cross-org operations
-	if adminMem, ok := h.agent.GetMemory().(interfaces.AdminConversationMemory); ok {
+	if adminMem, ok := h.Agent().GetMemory().(interfaces.AdminConversationMemory); ok {
 		log.Printf("Fetching messages for conversation %s from admin conversation memory across all orgs", conversationID) // #nosec G706 - conversationID is a UUID from internal routing
 		return h.buildMessageListFromAllOrgs(adminMem, conversationID, limit, offset)
 	}
@@ -935,7 +949,7 @@ func (h *HTTPServerWithUI) withOrgContext(handler http.HandlerFunc) http.Handler
 
 // getToolNames extracts tool names from the agent
 func (h *HTTPServerWithUI) getToolNames() []string {
-	tools := h.agent.GetTools()
+	tools := h.Agent().GetTools()
 	toolNames := make([]string, 0, len(tools))
 	for _, tool := range tools {
 		toolNames = append(toolNames, tool.Name())
@@ -946,8 +960,8 @@ func (h *HTTPServerWithUI) getToolNames() []string {
 // getModelName extracts the model name from the agent's LLM
 func (h *HTTPServerWithUI) getModelName() string {
 	// For remote agents, try to get LLM info from metadata
-	if h.agent.IsRemote() {
-		if metadata, err := h.agent.GetRemoteMetadata(); err == nil && metadata != nil {
+	if h.Agent().IsRemote() {
+		if metadata, err := h.Agent().GetRemoteMetadata(); err == nil && metadata != nil {
 			if llmModel, ok := metadata["llm_model"]; ok && llmModel != "" && llmModel != "unknown" {
 				return llmModel
 			}
@@ -959,7 +973,7 @@ func (h *HTTPServerWithUI) getModelName() string {
 	}
 
 	// For local agents, get from LLM directly
-	llm := h.agent.GetLLM()
+	llm := h.Agent().GetLLM()
 	if llm == nil {
 		return "No LLM configured"
 	}
@@ -1029,8 +1043,8 @@ func inferAzureModelFromDeployment(deployment string) string {
 // getMemoryInfo extracts memory information from the agent
 func (h *HTTPServerWithUI) getMemoryInfo() MemoryInfo {
 	// For remote agents, try to get memory info from metadata
-	if h.agent.IsRemote() {
-		if metadata, err := h.agent.GetRemoteMetadata(); err == nil && metadata != nil {
+	if h.Agent().IsRemote() {
+		if metadata, err := h.Agent().GetRemoteMetadata(); err == nil && metadata != nil {
 			if memoryType, ok := metadata["memory"]; ok && memoryType != "" && memoryType != "none" {
 				return MemoryInfo{
 					Type:   memoryType,
@@ -1046,11 +1060,11 @@ func (h *HTTPServerWithUI) getMemoryInfo() MemoryInfo {
 	}
 
 	// For local agents, check memory directly
-	mem := h.agent.GetMemory()
+	mem := h.Agent().GetMemory()
 	if mem == nil {
 		// Check if there's a memory config that indicates the type
 		// even if the instance hasn't been created yet
-		if memConfig := h.agent.GetMemoryConfig(); memConfig != nil {
+		if memConfig := h.Agent().GetMemoryConfig(); memConfig != nil {
 			if memType, ok := memConfig["type"].(string); ok && memType != "" {
 				return MemoryInfo{
 					Type:   memType,
@@ -1116,8 +1130,8 @@ func (h *HTTPServerWithUI) detectMemoryType(mem interfaces.Memory) string {
 // getDataStoreInfo extracts datastore information from the agent
 func (h *HTTPServerWithUI) getDataStoreInfo() DataStoreInfo {
 	// For remote agents, try to get datastore info from metadata
-	if h.agent.IsRemote() {
-		if metadata, err := h.agent.GetRemoteMetadata(); err == nil && metadata != nil {
+	if h.Agent().IsRemote() {
+		if metadata, err := h.Agent().GetRemoteMetadata(); err == nil && metadata != nil {
 			if dsType, ok := metadata["datastore"]; ok && dsType != "" && dsType != "none" {
 				return DataStoreInfo{
 					Type:   dsType,
@@ -1132,7 +1146,7 @@ func (h *HTTPServerWithUI) getDataStoreInfo() DataStoreInfo {
 	}
 
 	// For local agents, check datastore directly
-	ds := h.agent.GetDataStore()
+	ds := h.Agent().GetDataStore()
 	if ds == nil {
 		return DataStoreInfo{
 			Type:   "none",
@@ -1167,8 +1181,8 @@ func (h *HTTPServerWithUI) detectDataStoreType(ds interfaces.DataStore) string {
 // getSystemPrompt gets system prompt, handling remote agents
 func (h *HTTPServerWithUI) getSystemPrompt() string {
 	// For remote agents, try to get from metadata
-	if h.agent.IsRemote() {
-		if metadata, err := h.agent.GetRemoteMetadata(); err == nil && metadata != nil {
+	if h.Agent().IsRemote() {
+		if metadata, err := h.Agent().GetRemoteMetadata(); err == nil && metadata != nil {
 			if systemPrompt, ok := metadata["system_prompt"]; ok && systemPrompt != "" {
 				return systemPrompt
 			}
@@ -1177,7 +1191,7 @@ func (h *HTTPServerWithUI) getSystemPrompt() string {
 	}
 
 	// For local agents, get directly
-	systemPrompt := h.agent.GetSystemPrompt()
+	systemPrompt := h.Agent().GetSystemPrompt()
 	if systemPrompt == "" {
 		systemPrompt = "No system prompt configured"
 	}
@@ -1215,21 +1229,39 @@ func (h *HTTPServerWithUI) handleRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Input == "" {
-		http.Error(w, "Input is required", http.StatusBadRequest)
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.resolveStorageRefs(withRequestOrgID(r.Context(), req.OrgID), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.transcribeAudio(r.Context(), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// Set up context with org ID if provided
 	ctx := r.Context()
-	if req.OrgID != "" {
-		ctx = multitenancy.WithOrgID(ctx, req.OrgID)
+	ctx = withRequestOrgID(ctx, req.OrgID)
+	if len(req.ContentParts) > 0 {
+		ctx = interfaces.ContextWithContentParts(ctx, req.ContentParts)
 	}
+	if req.Toolsets != nil {
+		ctx = agent.ContextWithToolsets(ctx, req.Toolsets...)
+	}
+	ctx = req.withOutputLimits(ctx)
 
 	// Add conversation ID if provided
 	if req.ConversationID != "" {
 		ctx = memory.WithConversationID(ctx, req.ConversationID)
 	}
+	ctx, run, ok := h.startRun(w, ctx, &req, false)
+	if !ok {
+		return
+	}
+	defer func() { h.runs.finish(run, r.Context().Err()) }()
 
 	// Add user input to conversation history
 	h.addToConversationHistory("user", req.Input, map[string]interface{}{
@@ -1238,7 +1270,8 @@ func (h *HTTPServerWithUI) handleRun(w http.ResponseWriter, r *http.Request) {
 	})
 
 	// Execute agent with detailed tracking
-	response, err := h.agent.RunDetailed(ctx, req.Input)
+	response, err := h.Agent().RunDetailed(ctx, req.Input)
+	h.runs.finish(run, err)
 
 	// Add response to conversation history
 	if err != nil {
@@ -1251,6 +1284,7 @@ func (h *HTTPServerWithUI) handleRun(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]interface{}{
 			"error":  err.Error(),
 			"output": "",
+			"run_id": run.status.ID,
 		})
 		return
 	}
@@ -1290,10 +1324,18 @@ func (h *HTTPServerWithUI) handleRun(w http.ResponseWriter, r *http.Request) {
 		"output":            response.Content,
 		"error":             "",
 		"execution_summary": response.ExecutionSummary,
+		"run_id":            run.status.ID,
 	}
 	if response.Usage != nil {
 		responseData["usage"] = response.Usage
 	}
+	if audioURL, ok := response.Metadata[agent.MetadataAudioURL]; ok {
+		responseData[agent.MetadataAudioURL] = audioURL
+		responseData[agent.MetadataAudioMIMEType] = response.Metadata[agent.MetadataAudioMIMEType]
+	}
+	if len(response.Artifacts) > 0 {
+		responseData["artifacts"] = response.Artifacts
+	}
 	_ = json.NewEncoder(w).Encode(responseData)
 }
 
@@ -1310,8 +1352,16 @@ func (h *HTTPServerWithUI) handleStream(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if req.Input == "" {
-		http.Error(w, "Input is required", http.StatusBadRequest)
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.resolveStorageRefs(withRequestOrgID(r.Context(), req.OrgID), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.transcribeAudio(r.Context(), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -1319,18 +1369,27 @@ func (h *HTTPServerWithUI) handleStream(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	// Set up context with org ID if provided
 	ctx := r.Context()
-	if req.OrgID != "" {
-		ctx = multitenancy.WithOrgID(ctx, req.OrgID)
+	ctx = withRequestOrgID(ctx, req.OrgID)
+	if len(req.ContentParts) > 0 {
+		ctx = interfaces.ContextWithContentParts(ctx, req.ContentParts)
+	}
+	if req.Toolsets != nil {
+		ctx = agent.ContextWithToolsets(ctx, req.Toolsets...)
 	}
+	ctx = req.withOutputLimits(ctx)
 
 	// Add conversation ID if provided
 	if req.ConversationID != "" {
 		ctx = memory.WithConversationID(ctx, req.ConversationID)
 	}
+	ctx, run, ok := h.startRun(w, ctx, &req, true)
+	if !ok {
+		return
+	}
+	defer func() { h.runs.finish(run, r.Context().Err()) }()
 
 	// Add user input to conversation history
 	h.addToConversationHistory("user", req.Input, map[string]interface{}{
@@ -1339,10 +1398,11 @@ func (h *HTTPServerWithUI) handleStream(w http.ResponseWriter, r *http.Request)
 	})
 
 	// Check if agent supports streaming
-	streamingAgent, ok := interface{}(h.agent).(interfaces.StreamingAgent)
+	streamingAgent, ok := interface{}(h.Agent()).(interfaces.StreamingAgent)
 	if !ok {
 		// Fall back to non-streaming with detailed tracking
-		response, err := h.agent.RunDetailed(ctx, req.Input)
+		response, err := h.Agent().RunDetailed(ctx, req.Input)
+		h.runs.finish(run, err)
 
 		if err != nil {
 			h.addToConversationHistory("error", err.Error(), map[string]interface{}{
@@ -1401,6 +1461,7 @@ func (h *HTTPServerWithUI) handleStream(w http.ResponseWriter, r *http.Request)
 	// Stream events from agent
 	eventChan, err := streamingAgent.RunStream(ctx, req.Input)
 	if err != nil {
+		h.runs.finish(run, err)
 		h.addToConversationHistory("error", err.Error(), map[string]interface{}{
 			"conversation_id": req.ConversationID,
 			"org_id":          req.OrgID,
@@ -1414,9 +1475,30 @@ func (h *HTTPServerWithUI) handleStream(w http.ResponseWriter, r *http.Request)
 		h.sendSSEEvent(w, event)
 		return
 	}
+	eventChan = watchIdle(r.Context(), eventChan, h.streamLimits.IdleTimeout, run.cancel)
+	eventChan = h.runs.track(r.Context(), run, eventChan)
+
+	flusher, _ := w.(http.Flusher)
+	heartbeat := time.NewTicker(h.streamLimits.withDefaults().HeartbeatInterval)
+	defer heartbeat.Stop()
 
 	var fullResponse strings.Builder
-	for agentEvent := range eventChan {
+events:
+	for {
+		var agentEvent interfaces.AgentStreamEvent
+		select {
+		case event, ok := <-eventChan:
+			if !ok {
+				break events
+			}
+			agentEvent = event
+		case <-heartbeat.C:
+			if flusher != nil {
+				sendSSEHeartbeat(w, flusher)
+			}
+			continue
+		}
+
 		// Collect content for conversation history
 		if agentEvent.Content != "" && agentEvent.Type == interfaces.AgentEventContent {
 			fullResponse.WriteString(agentEvent.Content)
@@ -1448,8 +1530,13 @@ func (h *HTTPServerWithUI) handleStream(w http.ResponseWriter, r *http.Request)
 			eventData.Metadata = agentEvent.Metadata
 		}
 
+		if errors.Is(agentEvent.Error, errStreamIdleTimeout) {
+			eventData.Type = "timeout"
+			eventData.IsFinal = true
+		}
+
 		event := SSEEvent{
-			Event:     string(agentEvent.Type),
+			Event:     eventData.Type,
 			Data:      eventData,
 			Timestamp: agentEvent.Timestamp.UnixMilli(),
 		}
@@ -1457,7 +1544,7 @@ func (h *HTTPServerWithUI) handleStream(w http.ResponseWriter, r *http.Request)
 		h.sendSSEEvent(w, event)
 
 		// Flush for real-time streaming
-		if flusher, ok := w.(http.Flusher); ok {
+		if flusher != nil {
 			flusher.Flush()
 		}
 	}
@@ -1505,6 +1592,10 @@ func (h *HTTPServerWithUI) buildConversationListFromAllOrgs(adminMem interfaces.
 	// Iterate through all orgs and their conversations
 	for orgID, conversations := range orgConversations {
 		for _, convID := range conversations {
+			if isUISessionsConversation(convID) {
+				continue
+			}
+
 			// Get messages to determine last activity and message count
 			messages, foundOrgID, err := adminMem.GetConversationMessagesAcrossOrgs(convID)
 			if err != nil || foundOrgID != orgID {
@@ -1711,7 +1802,7 @@ func (h *HTTPServerWithUI) buildMessageListFromLocalAllOrgs(conversationID strin
 
 // getRemoteMemoryConversations gets conversations from a remote agent via HTTP
 func (h *HTTPServerWithUI) getRemoteMemoryConversations(limit, offset int) MemoryResponse {
-	remoteURL := h.agent.GetRemoteURL()
+	remoteURL := h.Agent().GetRemoteURL()
 	if remoteURL == "" {
 		return MemoryResponse{
 			Mode:          "conversations",
@@ -1764,7 +1855,7 @@ func (h *HTTPServerWithUI) getRemoteMemoryConversations(limit, offset int) Memor
 
 // getRemoteMemoryMessages gets messages for a specific conversation from a remote agent via HTTP
 func (h *HTTPServerWithUI) getRemoteMemoryMessages(conversationID string, limit, offset int) MemoryResponse {
-	remoteURL := h.agent.GetRemoteURL()
+	remoteURL := h.Agent().GetRemoteURL()
 	if remoteURL == "" {
 		return MemoryResponse{
 			Mode:           "messages",
@@ -130,11 +130,8 @@ func TestHTTPServerWithUI_getModelName(t *testing.T) {
 			assert.NoError(t, err)
 
 			// Create UI server
-			server := &HTTPServerWithUI{
-				HTTPServer: HTTPServer{
-					agent: testAgent,
-				},
-			}
+			server := &HTTPServerWithUI{}
+			server.SetAgent(testAgent)
 
 			// Test getModelName
 			result := server.getModelName()
@@ -146,11 +143,8 @@ func TestHTTPServerWithUI_getModelName(t *testing.T) {
 func TestHTTPServerWithUI_getModelName_NoLLM(t *testing.T) {
 	// Test the case where getModelName handles nil LLM gracefully
 	// Create a UI server with an agent that has no LLM
-	server := &HTTPServerWithUI{
-		HTTPServer: HTTPServer{
-			agent: &agent.Agent{}, // Agent with no LLM
-		},
-	}
+	server := &HTTPServerWithUI{}
+	server.SetAgent(&agent.Agent{}) // Agent with no LLM
 
 	// Test getModelName with nil LLM
 	result := server.getModelName()
@@ -205,11 +199,8 @@ func TestHTTPServerWithUI_getToolNames(t *testing.T) {
 			assert.NoError(t, err)
 
 			// Create UI server
-			server := &HTTPServerWithUI{
-				HTTPServer: HTTPServer{
-					agent: testAgent,
-				},
-			}
+			server := &HTTPServerWithUI{}
+			server.SetAgent(testAgent)
 
 			// Test getToolNames
 			result := server.getToolNames()
@@ -267,11 +258,8 @@ func TestHTTPServerWithUI_getMemoryInfo(t *testing.T) {
 			assert.NoError(t, err)
 
 			// Create UI server
-			server := &HTTPServerWithUI{
-				HTTPServer: HTTPServer{
-					agent: testAgent,
-				},
-			}
+			server := &HTTPServerWithUI{}
+			server.SetAgent(testAgent)
 
 			// Test getMemoryInfo
 			result := server.getMemoryInfo()
@@ -301,11 +289,8 @@ func TestHTTPServerWithUI_RemoteAgent(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Create UI server
-	server := &HTTPServerWithUI{
-		HTTPServer: HTTPServer{
-			agent: testAgent,
-		},
-	}
+	server := &HTTPServerWithUI{}
+	server.SetAgent(testAgent)
 
 	// Test that it's recognized as a local agent
 	assert.False(t, testAgent.IsRemote())
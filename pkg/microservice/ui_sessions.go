@@ -0,0 +1,395 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// uiSessionsListPath lists the chat sessions of the UI; uiSessionsPath is the
+// base path of the per-session endpoints
+const (
+	uiSessionsListPath = "/api/v1/ui/sessions"
+	uiSessionsPath     = uiSessionsListPath + "/"
+)
+
+// uiSessionsConversationID is the conversation holding the session records
+// in the agent's memory, one message per session. It is hidden from the
+// conversation lists.
+const uiSessionsConversationID = "_ui_sessions"
+
+// maxSessionTitleLength bounds the titles derived from the first message
+const maxSessionTitleLength = 60
+
+// UISession is a chat session of the UI: a conversation with a title and
+// pinned messages, kept server-side so it follows the user across refreshes
+// and devices
+type UISession struct {
+	ConversationID string          `json:"conversation_id"`
+	Title          string          `json:"title"`
+	PinnedMessages []PinnedMessage `json:"pinned_messages,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+
+	// MessageCount is the number of messages of the conversation; it is not
+	// stored
+	MessageCount int `json:"message_count"`
+}
+
+// PinnedMessage is a message pinned in a session
+type PinnedMessage struct {
+	// Index is the position of the message in the conversation
+	Index    int       `json:"index"`
+	Role     string    `json:"role"`
+	Content  string    `json:"content"`
+	PinnedAt time.Time `json:"pinned_at"`
+}
+
+// UISessionList is the response of the session list endpoint
+type UISessionList struct {
+	Sessions []UISession `json:"sessions"`
+	Total    int         `json:"total"`
+	Limit    int         `json:"limit"`
+	Offset   int         `json:"offset"`
+}
+
+// UISessionDetail is a session with the messages of its conversation
+type UISessionDetail struct {
+	UISession
+	Messages []interfaces.Message `json:"messages"`
+}
+
+// UISessionUpdate is the body of a session create or update request
+type UISessionUpdate struct {
+	// ConversationID is the conversation of a new session; generated when empty
+	ConversationID string `json:"conversation_id,omitempty"`
+
+	// Title renames the session
+	Title *string `json:"title,omitempty"`
+
+	// Pin and Unpin pin and unpin messages by their index in the conversation
+	Pin   []int `json:"pin,omitempty"`
+	Unpin []int `json:"unpin,omitempty"`
+}
+
+// isUISessionsConversation returns true for the conversation holding the
+// session records
+func isUISessionsConversation(conversationID string) bool {
+	return conversationID == uiSessionsConversationID
+}
+
+// loadUISessions returns the session records of the organization of ctx
+func (h *HTTPServer) loadUISessions(ctx context.Context) (map[string]*UISession, error) {
+	messages, err := h.Agent().ExportConversation(ctx, uiSessionsConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make(map[string]*UISession, len(messages))
+	for _, message := range messages {
+		var session UISession
+		if err := json.Unmarshal([]byte(message.Content), &session); err != nil || session.ConversationID == "" {
+			continue
+		}
+		sessions[session.ConversationID] = &session
+	}
+	return sessions, nil
+}
+
+// saveUISessions replaces the session records of the organization of ctx
+func (h *HTTPServer) saveUISessions(ctx context.Context, sessions map[string]*UISession) error {
+	messages := make([]interfaces.Message, 0, len(sessions))
+	for _, session := range sessions {
+		stored := *session
+		stored.MessageCount = 0
+		content, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, interfaces.Message{Role: interfaces.MessageRoleSystem, Content: string(content)})
+	}
+	return h.Agent().ImportConversation(ctx, uiSessionsConversationID, messages)
+}
+
+// sessionTitle derives a title from the first user message of a conversation
+func sessionTitle(messages []interfaces.Message) string {
+	for _, message := range messages {
+		if message.Role != interfaces.MessageRoleUser {
+			continue
+		}
+		title := strings.Join(strings.Fields(message.Content), " ")
+		if runes := []rune(title); len(runes) > maxSessionTitleLength {
+			title = string(runes[:maxSessionTitleLength]) + "..."
+		}
+		return title
+	}
+	return "New conversation"
+}
+
+// handleUISessions lists and creates the chat sessions of the UI
+// (/api/v1/ui/sessions?org_id=...&limit=...&offset=...). Conversations in
+// memory without a session are listed with a title derived from their first
+// message.
+func (h *HTTPServer) handleUISessions(w http.ResponseWriter, r *http.Request) {
+	if h.Agent().GetMemory() == nil {
+		http.Error(w, "Agent has no memory configured", http.StatusNotImplemented)
+		return
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+
+	switch r.Method {
+	case "GET":
+		limit := 100
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+				limit = l
+			}
+		}
+		offset := 0
+		if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+			if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+				offset = o
+			}
+		}
+
+		h.uiSessionsMu.Lock()
+		sessions, err := h.loadUISessions(ctx)
+		h.uiSessionsMu.Unlock()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to load sessions: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if _, ok := h.Agent().GetMemory().(interfaces.ConversationMemory); ok {
+			conversationIDs, err := h.Agent().GetAllConversations(ctx)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Failed to list conversations: %v", err), http.StatusInternalServerError)
+				return
+			}
+			for _, conversationID := range conversationIDs {
+				if _, ok := sessions[conversationID]; !ok && !isUISessionsConversation(conversationID) {
+					sessions[conversationID] = &UISession{ConversationID: conversationID}
+				}
+			}
+		}
+
+		list := UISessionList{Sessions: []UISession{}, Total: len(sessions), Limit: limit, Offset: offset}
+		ordered := make([]*UISession, 0, len(sessions))
+		for _, session := range sessions {
+			ordered = append(ordered, session)
+		}
+		// Most recently updated first; untracked conversations last
+		sort.Slice(ordered, func(i, j int) bool {
+			if !ordered[i].UpdatedAt.Equal(ordered[j].UpdatedAt) {
+				return ordered[i].UpdatedAt.After(ordered[j].UpdatedAt)
+			}
+			return ordered[i].ConversationID < ordered[j].ConversationID
+		})
+		if offset < len(ordered) {
+			for _, session := range ordered[offset:min(offset+limit, len(ordered))] {
+				messages, err := h.Agent().ExportConversation(ctx, session.ConversationID)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Failed to get conversation %s: %v", session.ConversationID, err), http.StatusInternalServerError)
+					return
+				}
+				session.MessageCount = len(messages)
+				if session.Title == "" {
+					session.Title = sessionTitle(messages)
+				}
+				list.Sessions = append(list.Sessions, *session)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(list)
+
+	case "POST":
+		var body UISessionUpdate
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if body.ConversationID == "" {
+			body.ConversationID = uuid.New().String()
+		}
+		if strings.Contains(body.ConversationID, "/") || isUISessionsConversation(body.ConversationID) {
+			http.Error(w, "Invalid conversation ID", http.StatusBadRequest)
+			return
+		}
+
+		h.uiSessionsMu.Lock()
+		defer h.uiSessionsMu.Unlock()
+
+		sessions, err := h.loadUISessions(ctx)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to load sessions: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if _, ok := sessions[body.ConversationID]; ok {
+			http.Error(w, fmt.Sprintf("Session %s already exists", body.ConversationID), http.StatusConflict)
+			return
+		}
+
+		now := time.Now().UTC()
+		session := &UISession{ConversationID: body.ConversationID, CreatedAt: now, UpdatedAt: now}
+		if body.Title != nil {
+			session.Title = *body.Title
+		}
+		sessions[session.ConversationID] = session
+		if err := h.saveUISessions(ctx, sessions); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to save session: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(session)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleUISession manages a chat session of the UI
+// (/api/v1/ui/sessions/{id}?org_id=...): GET returns it with its messages,
+// PATCH renames it or pins and unpins messages, and DELETE removes it along
+// with its conversation
+func (h *HTTPServer) handleUISession(w http.ResponseWriter, r *http.Request) {
+	conversationID := strings.TrimPrefix(r.URL.Path, uiSessionsPath)
+	if conversationID == "" {
+		h.handleUISessions(w, r)
+		return
+	}
+	if strings.Contains(conversationID, "/") || isUISessionsConversation(conversationID) {
+		http.Error(w, "Invalid conversation ID", http.StatusBadRequest)
+		return
+	}
+
+	if h.Agent().GetMemory() == nil {
+		http.Error(w, "Agent has no memory configured", http.StatusNotImplemented)
+		return
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+
+	h.uiSessionsMu.Lock()
+	defer h.uiSessionsMu.Unlock()
+
+	sessions, err := h.loadUISessions(ctx)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load sessions: %v", err), http.StatusInternalServerError)
+		return
+	}
+	messages, err := h.Agent().ExportConversation(ctx, conversationID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get conversation: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	session, tracked := sessions[conversationID]
+	if !tracked {
+		if len(messages) == 0 {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		session = &UISession{ConversationID: conversationID}
+	}
+
+	switch r.Method {
+	case "GET":
+		if messages == nil {
+			messages = []interfaces.Message{}
+		}
+		detail := UISessionDetail{UISession: *session, Messages: messages}
+		detail.MessageCount = len(messages)
+		if detail.Title == "" {
+			detail.Title = sessionTitle(messages)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(detail)
+
+	case "PATCH":
+		var body UISessionUpdate
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		now := time.Now().UTC()
+		if !tracked {
+			session.CreatedAt = now
+		}
+		if body.Title != nil {
+			session.Title = *body.Title
+		}
+		for _, index := range body.Unpin {
+			session.PinnedMessages = slices.DeleteFunc(session.PinnedMessages, func(pinned PinnedMessage) bool {
+				return pinned.Index == index
+			})
+		}
+		for _, index := range body.Pin {
+			if index < 0 || index >= len(messages) {
+				http.Error(w, fmt.Sprintf("Message %d does not exist", index), http.StatusBadRequest)
+				return
+			}
+			if slices.ContainsFunc(session.PinnedMessages, func(pinned PinnedMessage) bool { return pinned.Index == index }) {
+				continue
+			}
+			session.PinnedMessages = append(session.PinnedMessages, PinnedMessage{
+				Index:    index,
+				Role:     string(messages[index].Role),
+				Content:  messages[index].Content,
+				PinnedAt: now,
+			})
+		}
+		sort.Slice(session.PinnedMessages, func(i, j int) bool {
+			return session.PinnedMessages[i].Index < session.PinnedMessages[j].Index
+		})
+		session.UpdatedAt = now
+
+		sessions[conversationID] = session
+		if err := h.saveUISessions(ctx, sessions); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to save session: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		updated := *session
+		updated.MessageCount = len(messages)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(updated)
+
+	case "DELETE":
+		if err := h.Agent().DeleteConversation(ctx, conversationID); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to delete conversation: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if tracked {
+			delete(sessions, conversationID)
+			if err := h.saveUISessions(ctx, sessions); err != nil {
+				http.Error(w, fmt.Sprintf("Failed to save sessions: %v", err), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"conversation_id": conversationID,
+			"deleted":         true,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
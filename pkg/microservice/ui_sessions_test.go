@@ -0,0 +1,111 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestHTTPServer_UISessions(t *testing.T) {
+	testAgent := createTestAgent("unused", nil).(*MockStreamingAgent).Agent
+	server := NewHTTPServer(testAgent, 8080)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.handleUISession(w, req)
+		return w
+	}
+
+	w := do("POST", uiSessionsPath, `{"conversation_id":"conv-1","title":"Trip planning"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", uiSessionsPath, `{"conversation_id":"conv-1"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for an existing session, got %d", w.Code)
+	}
+
+	err := testAgent.ImportConversation(context.Background(), "conv-1", []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "Where should I go?"},
+		{Role: interfaces.MessageRoleAssistant, Content: "Lisbon"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to import conversation: %v", err)
+	}
+	// A conversation started without a session is listed too
+	err = testAgent.ImportConversation(context.Background(), "conv-2", []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "Summarize   this\nreport"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to import conversation: %v", err)
+	}
+
+	w = do("PATCH", uiSessionsPath+"conv-1", `{"title":"Lisbon trip","pin":[1]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("PATCH", uiSessionsPath+"conv-1", `{"pin":[5]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a missing message, got %d", w.Code)
+	}
+
+	// Another server on the same memory, like after a restart, sees the session
+	server = NewHTTPServer(testAgent, 8080)
+	w = do("GET", uiSessionsPath+"conv-1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var detail UISessionDetail
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("Failed to unmarshal session: %v", err)
+	}
+	if detail.Title != "Lisbon trip" || len(detail.Messages) != 2 || detail.MessageCount != 2 {
+		t.Errorf("Unexpected session: %+v", detail)
+	}
+	if len(detail.PinnedMessages) != 1 || detail.PinnedMessages[0].Content != "Lisbon" {
+		t.Errorf("Expected the pinned message, got %+v", detail.PinnedMessages)
+	}
+
+	w = httptest.NewRecorder()
+	server.handleUISessions(w, httptest.NewRequest("GET", uiSessionsListPath, nil))
+	var list UISessionList
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to unmarshal sessions: %v", err)
+	}
+	if list.Total != 2 || len(list.Sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %+v", list)
+	}
+	if list.Sessions[0].ConversationID != "conv-1" || list.Sessions[1].Title != "Summarize this report" {
+		t.Errorf("Unexpected sessions: %+v", list.Sessions)
+	}
+
+	// The session records stay out of the conversation list
+	req := httptest.NewRequest("GET", conversationsListPath, nil)
+	w = httptest.NewRecorder()
+	server.handleConversations(w, req)
+	var conversations ConversationList
+	if err := json.Unmarshal(w.Body.Bytes(), &conversations); err != nil {
+		t.Fatalf("Failed to unmarshal conversations: %v", err)
+	}
+	if conversations.Total != 2 {
+		t.Errorf("Expected 2 conversations, got %+v", conversations)
+	}
+
+	w = do("PATCH", uiSessionsPath+"conv-1", `{"unpin":[1]}`)
+	var updated UISession
+	if err := json.Unmarshal(w.Body.Bytes(), &updated); err != nil || len(updated.PinnedMessages) != 0 {
+		t.Errorf("Expected the message to be unpinned, got %+v (%v)", updated, err)
+	}
+
+	if w := do("DELETE", uiSessionsPath+"conv-1", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if w := do("GET", uiSessionsPath+"conv-1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after delete, got %d", w.Code)
+	}
+}
@@ -0,0 +1,149 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// uploadsPath issues signed URLs for uploading media directly to storage
+const uploadsPath = "/api/v1/uploads"
+
+const (
+	// uploadURLExpiration is how long signed upload URLs are valid
+	uploadURLExpiration = 15 * time.Minute
+
+	// downloadURLExpiration is how long the signed URLs that replace storage
+	// references in content parts are valid, covering the duration of a run
+	downloadURLExpiration = time.Hour
+)
+
+// UploadRequest is the body of an upload URL request
+type UploadRequest struct {
+	MimeType string `json:"mime_type"`
+}
+
+// UploadResponse describes where to upload media and how to reference it
+// in the content_parts of run requests
+type UploadResponse struct {
+	// StorageRef is set as the storage_ref of a content part once uploaded
+	StorageRef string `json:"storage_ref"`
+
+	// UploadURL is the signed URL to send the media to
+	UploadURL string `json:"upload_url"`
+
+	// Method is the HTTP method of the upload
+	Method string `json:"method"`
+
+	// Headers must be sent with the upload
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// ExpiresAt is when the upload URL stops being valid
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SetUploadStorage sets the storage that clients upload media to directly.
+// It enables the upload endpoint, which issues signed upload URLs, and
+// content parts of run requests may then reference uploads by storage_ref
+// instead of carrying the media, so large files are not buffered by the server.
+func (h *HTTPServer) SetUploadStorage(store storage.SignedURLStorage) {
+	h.uploadStorage = store
+}
+
+// handleUpload issues a signed URL for uploading media directly to storage
+// (POST /api/v1/uploads with {"mime_type": "application/pdf"}). The client
+// uploads the media to upload_url with the given method and headers, then
+// sends {"type": "file", "storage_ref": "...", "mime_type": "..."} in the
+// content_parts of a run request.
+func (h *HTTPServer) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.uploadStorage == nil {
+		http.Error(w, "Upload storage is not configured", http.StatusNotImplemented)
+		return
+	}
+
+	var req UploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	mediaType, _, err := mime.ParseMediaType(req.MimeType)
+	if err != nil {
+		http.Error(w, "A valid mime_type is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := withRequestOrgID(r.Context(), r.URL.Query().Get("org_id"))
+	key := uploadKeyPrefix(ctx) + uuid.New().String()
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		key += exts[0]
+	}
+
+	target, err := h.uploadStorage.SignedUploadURL(ctx, key, req.MimeType, uploadURLExpiration)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create upload URL: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(UploadResponse{
+		StorageRef: key,
+		UploadURL:  target.URL,
+		Method:     target.Method,
+		Headers:    target.Headers,
+		ExpiresAt:  target.ExpiresAt,
+	})
+}
+
+// resolveStorageRefs replaces the storage references of content parts with
+// signed download URLs, which providers fetch themselves. Audio parts need
+// their data, so it is downloaded. Only uploads of the context's
+// organization can be referenced.
+func (h *HTTPServer) resolveStorageRefs(ctx context.Context, req *StreamRequest) error {
+	for i := range req.ContentParts {
+		part := &req.ContentParts[i]
+		if part.StorageRef == "" {
+			continue
+		}
+		if h.uploadStorage == nil {
+			return fmt.Errorf("content part %d: storage references require upload storage", i)
+		}
+		if !strings.HasPrefix(part.StorageRef, uploadKeyPrefix(ctx)) || strings.Contains(part.StorageRef, "..") {
+			return fmt.Errorf("content part %d: invalid storage reference", i)
+		}
+
+		url, err := h.uploadStorage.SignedDownloadURL(ctx, part.StorageRef, downloadURLExpiration)
+		if err != nil {
+			return fmt.Errorf("content part %d: %w", i, err)
+		}
+		if part.Type == interfaces.ContentPartAudio {
+			if part.Data, err = h.uploadStorage.Get(ctx, url); err != nil {
+				return fmt.Errorf("content part %d: failed to read upload: %w", i, err)
+			}
+		} else {
+			part.URL = url
+		}
+		part.StorageRef = ""
+	}
+	return nil
+}
+
+// uploadKeyPrefix returns the storage key prefix of the uploads of the
+// context's organization, under the organization's path segment so that
+// storage backends can check that its keys belong to it
+func uploadKeyPrefix(ctx context.Context) string {
+	return storage.OrgPathSegment(multitenancy.OrgIDOrDefault(ctx)) + "/uploads/"
+}
@@ -0,0 +1,106 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// signedBackend is a SignedURLStorage handing out fake signed URLs
+type signedBackend struct {
+	urlBackend
+	objects map[string][]byte
+}
+
+func (b *signedBackend) SignedUploadURL(ctx context.Context, key, contentType string, expiration time.Duration) (*storage.UploadTarget, error) {
+	return &storage.UploadTarget{
+		URL:       "https://uploads.example.com/" + key + "?sig=put",
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: time.Now().Add(expiration),
+	}, nil
+}
+
+func (b *signedBackend) SignedDownloadURL(ctx context.Context, key string, expiration time.Duration) (string, error) {
+	return "https://uploads.example.com/" + key + "?sig=get", nil
+}
+
+func (b *signedBackend) Get(ctx context.Context, url string) ([]byte, error) {
+	return b.objects[url], nil
+}
+
+func TestHTTPServer_Upload(t *testing.T) {
+	server := NewHTTPServer(createTestAgent("unused", nil).(*MockStreamingAgent).Agent, 8080)
+
+	w := httptest.NewRecorder()
+	server.handleUpload(w, httptest.NewRequest("POST", uploadsPath, strings.NewReader(`{"mime_type":"application/pdf"}`)))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status 501 without upload storage, got %d", w.Code)
+	}
+
+	server.SetUploadStorage(&signedBackend{})
+
+	w = httptest.NewRecorder()
+	server.handleUpload(w, httptest.NewRequest("POST", uploadsPath+"?org_id=org-1", strings.NewReader(`{"mime_type":"application/pdf"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var upload UploadResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &upload); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if !strings.HasPrefix(upload.StorageRef, "org-1/uploads/") || !strings.HasSuffix(upload.StorageRef, ".pdf") {
+		t.Errorf("Unexpected storage ref %q", upload.StorageRef)
+	}
+	if upload.Method != http.MethodPut || upload.Headers["Content-Type"] != "application/pdf" || !strings.Contains(upload.UploadURL, upload.StorageRef) {
+		t.Errorf("Unexpected upload response %+v", upload)
+	}
+
+	w = httptest.NewRecorder()
+	server.handleUpload(w, httptest.NewRequest("POST", uploadsPath, strings.NewReader(`{}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a mime_type, got %d", w.Code)
+	}
+}
+
+func TestHTTPServer_ResolveStorageRefs(t *testing.T) {
+	backend := &signedBackend{objects: map[string][]byte{
+		"https://uploads.example.com/org-1/uploads/voice.wav?sig=get": []byte("RIFF"),
+	}}
+	server := NewHTTPServer(createTestAgent("unused", nil).(*MockStreamingAgent).Agent, 8080)
+	ctx := withRequestOrgID(context.Background(), "org-1")
+
+	req := &StreamRequest{ContentParts: []interfaces.ContentPart{
+		{Type: interfaces.ContentPartFile, StorageRef: "org-1/uploads/report.pdf", MIMEType: "application/pdf"},
+	}}
+	if err := server.resolveStorageRefs(ctx, req); err == nil {
+		t.Error("Expected an error without upload storage")
+	}
+
+	server.SetUploadStorage(backend)
+	req.ContentParts = append(req.ContentParts, interfaces.ContentPart{Type: interfaces.ContentPartAudio, StorageRef: "org-1/uploads/voice.wav", MIMEType: "audio/wav"})
+	if err := server.resolveStorageRefs(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if file := req.ContentParts[0]; file.URL != "https://uploads.example.com/org-1/uploads/report.pdf?sig=get" || file.StorageRef != "" {
+		t.Errorf("Expected the file part to reference a signed URL, got %+v", file)
+	}
+	if audio := req.ContentParts[1]; string(audio.Data) != "RIFF" || audio.URL != "" {
+		t.Errorf("Expected the audio part to carry the upload, got %+v", audio)
+	}
+
+	// Uploads of other organizations cannot be referenced
+	for _, ref := range []string{"org-2/uploads/report.pdf", "org-1/uploads/../../org-2/uploads/report.pdf", "report.pdf"} {
+		req := &StreamRequest{ContentParts: []interfaces.ContentPart{{Type: interfaces.ContentPartImage, StorageRef: ref}}}
+		if err := server.resolveStorageRefs(ctx, req); err == nil {
+			t.Errorf("Expected an error for storage ref %q", ref)
+		}
+	}
+}
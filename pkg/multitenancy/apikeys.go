@@ -0,0 +1,367 @@
+package multitenancy
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiKeyPrefix marks secrets issued by APIKeyManager. Secrets have the form
+// "ask_<key id>_<random secret>" so the key can be looked up without storing
+// the plaintext secret.
+const apiKeyPrefix = "ask_"
+
+var (
+	// ErrAPIKeyNotFound is returned when an API key does not exist
+	ErrAPIKeyNotFound = errors.New("API key not found")
+
+	// ErrInvalidAPIKey is returned when a presented API key is malformed or does not match
+	ErrInvalidAPIKey = errors.New("invalid API key")
+
+	// ErrAPIKeyRevoked is returned when a presented API key has been revoked
+	ErrAPIKeyRevoked = errors.New("API key revoked")
+
+	// ErrAPIKeyExpired is returned when a presented API key has expired
+	ErrAPIKeyExpired = errors.New("API key expired")
+)
+
+// APIKeyScopes limits what an API key can access. Empty lists allow everything.
+type APIKeyScopes struct {
+	// Endpoints lists the allowed request paths. An entry ending in "*" matches
+	// every path with that prefix.
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Agents lists the names of the agents the key may call
+	Agents []string `json:"agents,omitempty"`
+}
+
+// APIKey is the stored form of an issued API key. The plaintext secret is
+// never stored; only its SHA-256 hash.
+type APIKey struct {
+	ID         string       `json:"id"`
+	OrgID      string       `json:"org_id"`
+	Name       string       `json:"name"`
+	Hash       string       `json:"-"`
+	Scopes     APIKeyScopes `json:"scopes"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ExpiresAt  time.Time    `json:"expires_at,omitzero"`
+	RevokedAt  time.Time    `json:"revoked_at,omitzero"`
+	LastUsedAt time.Time    `json:"last_used_at,omitzero"`
+}
+
+// IsRevoked returns true if the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// IsExpired returns true if the key has an expiry time that has passed
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// AllowsEndpoint returns true if the key's scopes permit the request path
+func (k *APIKey) AllowsEndpoint(path string) bool {
+	if len(k.Scopes.Endpoints) == 0 {
+		return true
+	}
+	for _, endpoint := range k.Scopes.Endpoints {
+		if prefix, ok := strings.CutSuffix(endpoint, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsAgent returns true if the key's scopes permit calling the named agent
+func (k *APIKey) AllowsAgent(name string) bool {
+	if len(k.Scopes.Agents) == 0 {
+		return true
+	}
+	for _, agent := range k.Scopes.Agents {
+		if agent == name {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyStore persists API keys
+type APIKeyStore interface {
+	// SaveKey creates or replaces a key
+	SaveKey(ctx context.Context, key *APIKey) error
+
+	// GetKey returns the key with the given ID or ErrAPIKeyNotFound
+	GetKey(ctx context.Context, id string) (*APIKey, error)
+
+	// ListKeys returns all keys of an organization
+	ListKeys(ctx context.Context, orgID string) ([]*APIKey, error)
+}
+
+// MemoryAPIKeyStore is an in-memory APIKeyStore
+type MemoryAPIKeyStore struct {
+	keys map[string]APIKey
+	mu   sync.RWMutex
+}
+
+// NewMemoryAPIKeyStore creates a new in-memory API key store
+func NewMemoryAPIKeyStore() *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{
+		keys: make(map[string]APIKey),
+	}
+}
+
+// SaveKey implements APIKeyStore.SaveKey
+func (s *MemoryAPIKeyStore) SaveKey(ctx context.Context, key *APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key.ID] = *key
+	return nil
+}
+
+// GetKey implements APIKeyStore.GetKey
+func (s *MemoryAPIKeyStore) GetKey(ctx context.Context, id string) (*APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+// ListKeys implements APIKeyStore.ListKeys
+func (s *MemoryAPIKeyStore) ListKeys(ctx context.Context, orgID string) ([]*APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]*APIKey, 0)
+	for _, key := range s.keys {
+		if key.OrgID == orgID {
+			key := key
+			keys = append(keys, &key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// APIKeyOption represents an option for issuing an API key
+type APIKeyOption func(*APIKey)
+
+// WithAPIKeyEndpoints limits the key to the given request paths
+func WithAPIKeyEndpoints(endpoints ...string) APIKeyOption {
+	return func(k *APIKey) {
+		k.Scopes.Endpoints = endpoints
+	}
+}
+
+// WithAPIKeyAgents limits the key to the named agents
+func WithAPIKeyAgents(agents ...string) APIKeyOption {
+	return func(k *APIKey) {
+		k.Scopes.Agents = agents
+	}
+}
+
+// WithAPIKeyTTL makes the key expire after the given duration
+func WithAPIKeyTTL(ttl time.Duration) APIKeyOption {
+	return func(k *APIKey) {
+		k.ExpiresAt = k.CreatedAt.Add(ttl)
+	}
+}
+
+// APIKeyManager issues, rotates, revokes and authenticates per-organization API keys
+type APIKeyManager struct {
+	store APIKeyStore
+	now   func() time.Time
+}
+
+// NewAPIKeyManager creates a new API key manager. A nil store keeps keys in memory.
+func NewAPIKeyManager(store APIKeyStore) *APIKeyManager {
+	if store == nil {
+		store = NewMemoryAPIKeyStore()
+	}
+	return &APIKeyManager{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// CreateKey issues a new API key for an organization. The plaintext secret is
+// returned only once and cannot be recovered later.
+func (m *APIKeyManager) CreateKey(ctx context.Context, orgID, name string, options ...APIKeyOption) (*APIKey, string, error) {
+	if orgID == "" {
+		return nil, "", errors.New("organization ID is required")
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return nil, "", err
+	}
+	plaintext := apiKeyPrefix + id + "_" + secret
+
+	key := &APIKey{
+		ID:        id,
+		OrgID:     orgID,
+		Name:      name,
+		Hash:      hashAPIKey(plaintext),
+		CreatedAt: m.now(),
+	}
+	for _, option := range options {
+		option(key)
+	}
+
+	if err := m.store.SaveKey(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("failed to save API key: %w", err)
+	}
+
+	return key, plaintext, nil
+}
+
+// RevokeKey revokes a key so it can no longer authenticate
+func (m *APIKeyManager) RevokeKey(ctx context.Context, id string) error {
+	key, err := m.store.GetKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.IsRevoked() {
+		return nil
+	}
+
+	key.RevokedAt = m.now()
+	if err := m.store.SaveKey(ctx, key); err != nil {
+		return fmt.Errorf("failed to save API key: %w", err)
+	}
+	return nil
+}
+
+// RotateKey issues a replacement for a key with the same organization, name
+// and scopes, and revokes the old key
+func (m *APIKeyManager) RotateKey(ctx context.Context, id string) (*APIKey, string, error) {
+	old, err := m.store.GetKey(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if old.IsRevoked() {
+		return nil, "", ErrAPIKeyRevoked
+	}
+
+	options := []APIKeyOption{
+		WithAPIKeyEndpoints(old.Scopes.Endpoints...),
+		WithAPIKeyAgents(old.Scopes.Agents...),
+	}
+	if !old.ExpiresAt.IsZero() {
+		options = append(options, WithAPIKeyTTL(old.ExpiresAt.Sub(old.CreatedAt)))
+	}
+
+	key, secret, err := m.CreateKey(ctx, old.OrgID, old.Name, options...)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := m.RevokeKey(ctx, id); err != nil {
+		return nil, "", err
+	}
+
+	return key, secret, nil
+}
+
+// ListKeys returns the keys of an organization, including revoked ones
+func (m *APIKeyManager) ListKeys(ctx context.Context, orgID string) ([]*APIKey, error) {
+	return m.store.ListKeys(ctx, orgID)
+}
+
+// GetKey returns a key by ID
+func (m *APIKeyManager) GetKey(ctx context.Context, id string) (*APIKey, error) {
+	return m.store.GetKey(ctx, id)
+}
+
+// Authenticate validates a plaintext API key and returns the stored key
+func (m *APIKeyManager) Authenticate(ctx context.Context, plaintext string) (*APIKey, error) {
+	id, ok := parseAPIKeyID(plaintext)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := m.store.GetKey(ctx, id)
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashAPIKey(plaintext))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	now := m.now()
+	if key.IsRevoked() {
+		return nil, ErrAPIKeyRevoked
+	}
+	if key.IsExpired(now) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	// Last-used tracking is best effort and must not fail the request
+	key.LastUsedAt = now
+	_ = m.store.SaveKey(ctx, key)
+
+	return key, nil
+}
+
+// parseAPIKeyID extracts the key ID from a plaintext secret
+func parseAPIKeyID(plaintext string) (string, bool) {
+	rest, ok := strings.CutPrefix(plaintext, apiKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", false
+	}
+	return id, true
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type apiKeyContextKey struct{}
+
+// WithAPIKey returns a new context carrying the authenticated API key
+func WithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// GetAPIKey returns the authenticated API key from the context, if any
+func GetAPIKey(ctx context.Context) (*APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return key, ok && key != nil
+}
@@ -0,0 +1,129 @@
+package multitenancy_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestAPIKeyManager_CreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	manager := multitenancy.NewAPIKeyManager(nil)
+
+	key, secret, err := manager.CreateKey(ctx, "org-1", "ci", multitenancy.WithAPIKeyEndpoints("/api/v1/agent/*"))
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	if key.Hash == "" || strings.Contains(key.Hash, secret) || key.Hash == secret {
+		t.Errorf("expected a hashed secret, got %q", key.Hash)
+	}
+
+	authenticated, err := manager.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if authenticated.OrgID != "org-1" || authenticated.ID != key.ID {
+		t.Errorf("unexpected key %+v", authenticated)
+	}
+	if authenticated.LastUsedAt.IsZero() {
+		t.Error("expected LastUsedAt to be recorded")
+	}
+
+	if _, err := manager.Authenticate(ctx, secret+"x"); !errors.Is(err, multitenancy.ErrInvalidAPIKey) {
+		t.Errorf("expected ErrInvalidAPIKey for a wrong secret, got %v", err)
+	}
+	if _, err := manager.Authenticate(ctx, "not-a-key"); !errors.Is(err, multitenancy.ErrInvalidAPIKey) {
+		t.Errorf("expected ErrInvalidAPIKey for a malformed key, got %v", err)
+	}
+
+	if _, _, err := manager.CreateKey(ctx, "", "missing org"); err == nil {
+		t.Error("expected an error for a missing organization")
+	}
+}
+
+func TestAPIKeyManager_RevokeAndRotate(t *testing.T) {
+	ctx := context.Background()
+	manager := multitenancy.NewAPIKeyManager(nil)
+
+	key, secret, err := manager.CreateKey(ctx, "org-1", "service", multitenancy.WithAPIKeyAgents("support"))
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+
+	rotated, rotatedSecret, err := manager.RotateKey(ctx, key.ID)
+	if err != nil {
+		t.Fatalf("RotateKey failed: %v", err)
+	}
+	if rotated.ID == key.ID || rotated.OrgID != "org-1" || !rotated.AllowsAgent("support") || rotated.AllowsAgent("billing") {
+		t.Errorf("unexpected rotated key %+v", rotated)
+	}
+
+	if _, err := manager.Authenticate(ctx, secret); !errors.Is(err, multitenancy.ErrAPIKeyRevoked) {
+		t.Errorf("expected the old key to be revoked, got %v", err)
+	}
+	if _, err := manager.Authenticate(ctx, rotatedSecret); err != nil {
+		t.Errorf("expected the rotated key to authenticate, got %v", err)
+	}
+	if _, _, err := manager.RotateKey(ctx, key.ID); !errors.Is(err, multitenancy.ErrAPIKeyRevoked) {
+		t.Errorf("expected rotating a revoked key to fail, got %v", err)
+	}
+
+	if err := manager.RevokeKey(ctx, rotated.ID); err != nil {
+		t.Fatalf("RevokeKey failed: %v", err)
+	}
+	if _, err := manager.Authenticate(ctx, rotatedSecret); !errors.Is(err, multitenancy.ErrAPIKeyRevoked) {
+		t.Errorf("expected ErrAPIKeyRevoked, got %v", err)
+	}
+	if err := manager.RevokeKey(ctx, "missing"); !errors.Is(err, multitenancy.ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+
+	keys, err := manager.ListKeys(ctx, "org-1")
+	if err != nil {
+		t.Fatalf("ListKeys failed: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("expected 2 keys, got %d", len(keys))
+	}
+}
+
+func TestAPIKeyManager_Expiry(t *testing.T) {
+	ctx := context.Background()
+	manager := multitenancy.NewAPIKeyManager(nil)
+
+	_, secret, err := manager.CreateKey(ctx, "org-1", "short-lived", multitenancy.WithAPIKeyTTL(-time.Minute))
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	if _, err := manager.Authenticate(ctx, secret); !errors.Is(err, multitenancy.ErrAPIKeyExpired) {
+		t.Errorf("expected ErrAPIKeyExpired, got %v", err)
+	}
+}
+
+func TestAPIKey_AllowsEndpoint(t *testing.T) {
+	key := &multitenancy.APIKey{
+		Scopes: multitenancy.APIKeyScopes{
+			Endpoints: []string{"/api/v1/agent/run", "/api/v1/memory*"},
+		},
+	}
+
+	tests := map[string]bool{
+		"/api/v1/agent/run":     true,
+		"/api/v1/agent/stream":  false,
+		"/api/v1/memory":        true,
+		"/api/v1/memory/search": true,
+	}
+	for path, want := range tests {
+		if got := key.AllowsEndpoint(path); got != want {
+			t.Errorf("AllowsEndpoint(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if !(&multitenancy.APIKey{}).AllowsEndpoint("/anything") {
+		t.Error("expected a key without endpoint scopes to allow every endpoint")
+	}
+}
@@ -3,12 +3,18 @@ package multitenancy
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"sync"
+	"time"
 )
 
 var (
 	// ErrTenantNotFound is returned when a tenant is not found
 	ErrTenantNotFound = errors.New("tenant not found")
+
+	// ErrBudgetExceeded is returned when an organization has used its token budget
+	ErrBudgetExceeded = errors.New("token budget exceeded")
 )
 
 // TenantConfig represents the configuration for a tenant
@@ -27,19 +33,80 @@ type TenantConfig struct {
 
 	// Custom contains custom configuration values
 	Custom map[string]interface{}
+
+	// AllowedModels lists the models the organization's agent runs may use.
+	// Empty allows every model.
+	AllowedModels []string
+
+	// MaxTemperature caps the sampling temperature of the organization's
+	// agent runs. Nil leaves the temperature unchanged.
+	MaxTemperature *float64
+
+	// AllowedTools lists the tools exposed to the organization's agent runs.
+	// Empty allows every tool.
+	AllowedTools []string
+
+	// MemoryPrefix is prepended to the conversation IDs of the organization's
+	// agent runs, to keep their conversations apart in a shared memory backend
+	MemoryPrefix string
+
+	// TokenBudget limits the LLM tokens used by the organization's agent runs
+	// per UTC day; zero means unlimited. A run started under the budget
+	// completes, so usage can exceed it by one run.
+	TokenBudget int64
+}
+
+// AllowsModel returns true if the organization may use the model
+func (c *TenantConfig) AllowsModel(model string) bool {
+	return len(c.AllowedModels) == 0 || slices.Contains(c.AllowedModels, model)
+}
+
+// AllowsTool returns true if the organization may use the named tool
+func (c *TenantConfig) AllowsTool(name string) bool {
+	return len(c.AllowedTools) == 0 || slices.Contains(c.AllowedTools, name)
+}
+
+// CapTemperature returns temperature, lowered to MaxTemperature when it is
+// higher
+func (c *TenantConfig) CapTemperature(temperature float64) float64 {
+	if c.MaxTemperature != nil && temperature > *c.MaxTemperature {
+		return *c.MaxTemperature
+	}
+	return temperature
 }
 
 // ConfigManager manages tenant configurations
 type ConfigManager struct {
 	configs map[string]*TenantConfig
+	budgets QuotaStore
 	mu      sync.RWMutex
 }
 
+// ConfigManagerOption represents an option for configuring the config manager
+type ConfigManagerOption func(*ConfigManager)
+
+// WithBudgetStore sets the store of the token budget counters; share a
+// Redis store between replicas (default: in memory)
+func WithBudgetStore(store QuotaStore) ConfigManagerOption {
+	return func(m *ConfigManager) {
+		m.budgets = store
+	}
+}
+
 // NewConfigManager creates a new config manager
-func NewConfigManager() *ConfigManager {
-	return &ConfigManager{
+func NewConfigManager(options ...ConfigManagerOption) *ConfigManager {
+	m := &ConfigManager{
 		configs: make(map[string]*TenantConfig),
 	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	if m.budgets == nil {
+		m.budgets = NewMemoryQuotaStore()
+	}
+	return m
 }
 
 // RegisterTenant registers a new tenant
@@ -122,3 +189,43 @@ func (m *ConfigManager) GetCustomConfig(ctx context.Context, key string) (interf
 
 	return value, nil
 }
+
+// budgetKey returns the key of an organization's token budget counter for
+// the UTC day of now, and when the counter resets
+func budgetKey(orgID string, now time.Time) (string, time.Time) {
+	day := now.UTC().Truncate(24 * time.Hour)
+	return "budget:" + orgID + ":" + day.Format("2006-01-02"), day.Add(24 * time.Hour)
+}
+
+// CheckBudget returns ErrBudgetExceeded when the tenant of ctx has used its
+// token budget for the day. Tenants without a budget always pass.
+func (m *ConfigManager) CheckBudget(ctx context.Context) error {
+	config, err := m.GetTenantConfig(ctx)
+	if err != nil || config.TokenBudget <= 0 {
+		return nil
+	}
+
+	key, _ := budgetKey(config.OrgID, time.Now())
+	used, err := m.budgets.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to read token budget: %w", err)
+	}
+	if used >= config.TokenBudget {
+		return fmt.Errorf("%w: organization %s used %d of %d tokens today", ErrBudgetExceeded, config.OrgID, used, config.TokenBudget)
+	}
+	return nil
+}
+
+// RecordUsage counts tokens against the token budget of the tenant of ctx
+func (m *ConfigManager) RecordUsage(ctx context.Context, tokens int64) error {
+	config, err := m.GetTenantConfig(ctx)
+	if err != nil || config.TokenBudget <= 0 || tokens <= 0 {
+		return nil
+	}
+
+	key, resetAt := budgetKey(config.OrgID, time.Now())
+	if _, err := m.budgets.Increment(ctx, key, tokens, resetAt); err != nil {
+		return fmt.Errorf("failed to record token usage: %w", err)
+	}
+	return nil
+}
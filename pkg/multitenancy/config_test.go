@@ -0,0 +1,66 @@
+package multitenancy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func TestTenantConfig_Overrides(t *testing.T) {
+	maxTemperature := 0.3
+	config := &multitenancy.TenantConfig{
+		OrgID:          "acme",
+		AllowedModels:  []string{"gpt-4o-mini"},
+		AllowedTools:   []string{"search"},
+		MaxTemperature: &maxTemperature,
+	}
+
+	if !config.AllowsModel("gpt-4o-mini") || config.AllowsModel("gpt-4o") {
+		t.Error("expected only gpt-4o-mini to be allowed")
+	}
+	if !config.AllowsTool("search") || config.AllowsTool("refund") {
+		t.Error("expected only search to be allowed")
+	}
+	if got := config.CapTemperature(0.7); got != 0.3 {
+		t.Errorf("expected temperature capped at 0.3, got %v", got)
+	}
+	if got := config.CapTemperature(0.1); got != 0.1 {
+		t.Errorf("expected temperature under the cap to be kept, got %v", got)
+	}
+
+	unrestricted := &multitenancy.TenantConfig{OrgID: "other"}
+	if !unrestricted.AllowsModel("gpt-4o") || !unrestricted.AllowsTool("refund") || unrestricted.CapTemperature(1) != 1 {
+		t.Error("expected a config without overrides to allow everything")
+	}
+}
+
+func TestConfigManager_Budget(t *testing.T) {
+	manager := multitenancy.NewConfigManager()
+	if err := manager.RegisterTenant(&multitenancy.TenantConfig{OrgID: "acme", TokenBudget: 100}); err != nil {
+		t.Fatalf("failed to register tenant: %v", err)
+	}
+	ctx := multitenancy.WithOrgID(context.Background(), "acme")
+
+	if err := manager.CheckBudget(ctx); err != nil {
+		t.Fatalf("expected budget to be available, got %v", err)
+	}
+	if err := manager.RecordUsage(ctx, 60); err != nil {
+		t.Fatalf("failed to record usage: %v", err)
+	}
+	if err := manager.CheckBudget(ctx); err != nil {
+		t.Fatalf("expected budget to be available after 60 tokens, got %v", err)
+	}
+	if err := manager.RecordUsage(ctx, 60); err != nil {
+		t.Fatalf("failed to record usage: %v", err)
+	}
+	if err := manager.CheckBudget(ctx); !errors.Is(err, multitenancy.ErrBudgetExceeded) {
+		t.Errorf("expected ErrBudgetExceeded, got %v", err)
+	}
+
+	// Organizations without a configuration have no budget
+	if err := manager.CheckBudget(multitenancy.WithOrgID(context.Background(), "unknown")); err != nil {
+		t.Errorf("expected no budget for unknown organizations, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// DefaultOrgID is the organization of data stored without an organization
+// ID in the context
+const DefaultOrgID = "default"
+
+// ErrCrossTenantAccess is returned when data of another organization is accessed
+var ErrCrossTenantAccess = errors.New("access to data of another organization denied")
+
+// OrgIDOrDefault returns the organization ID from the context, or
+// DefaultOrgID when there is none
+func OrgIDOrDefault(ctx context.Context) string {
+	orgID, err := GetOrgID(ctx)
+	if err != nil {
+		return DefaultOrgID
+	}
+	return orgID
+}
+
+// keySegmentEscaper escapes the characters that separate the segments of
+// storage keys, and the glob characters of key patterns
+var keySegmentEscaper = strings.NewReplacer(
+	"%", "%25",
+	":", "%3A",
+	"/", "%2F",
+	"#", "%23",
+	"*", "%2A",
+	"?", "%3F",
+	"[", "%5B",
+	"]", "%5D",
+	"\\", "%5C",
+)
+
+// EscapeKeySegment escapes an ID for use as a segment of a storage key, such
+// as the organization in "org:conversation". Without escaping, organization
+// "a" with conversation "b:c" and organization "a:b" with conversation "c"
+// would share a key, and an organization ID such as "*" would match the keys
+// of every organization in a key pattern. IDs without special characters are
+// unchanged.
+func EscapeKeySegment(id string) string {
+	return keySegmentEscaper.Replace(id)
+}
+
+// UnescapeKeySegment reverses EscapeKeySegment
+func UnescapeKeySegment(segment string) string {
+	id, err := url.PathUnescape(segment)
+	if err != nil {
+		return segment
+	}
+	return id
+}
@@ -0,0 +1,125 @@
+package multitenancy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage/local"
+	vectormemory "github.com/Ingenimax/agent-sdk-go/pkg/vectorstore/memory"
+)
+
+// orgConversation returns a context of a conversation of an organization
+func orgConversation(orgID, conversationID string) context.Context {
+	return memory.WithConversationID(multitenancy.WithOrgID(context.Background(), orgID), conversationID)
+}
+
+func TestMemoryIsolation(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to start miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	backends := map[string]interfaces.Memory{
+		"buffer": memory.NewConversationBuffer(),
+		"redis":  memory.NewRedisMemory(redis.NewClient(&redis.Options{Addr: mr.Addr()})),
+	}
+	for name, mem := range backends {
+		t.Run(name, func(t *testing.T) {
+			owner := orgConversation("a", "b:c")
+			if err := mem.AddMessage(owner, interfaces.Message{Role: interfaces.MessageRoleUser, Content: "secret"}); err != nil {
+				t.Fatalf("AddMessage: %v", err)
+			}
+
+			// Other organizations cannot read the conversation, even with IDs
+			// that would produce the same key without escaping, and neither
+			// can contexts without an organization
+			for _, ctx := range []context.Context{
+				orgConversation("other", "b:c"),
+				orgConversation("a:b", "c"),
+				orgConversation("*", "b:c"),
+				memory.WithConversationID(context.Background(), "b:c"),
+			} {
+				if messages, _ := mem.GetMessages(ctx); len(messages) != 0 {
+					t.Errorf("Expected no messages for another organization, got %v", messages)
+				}
+			}
+
+			if messages, _ := mem.GetMessages(owner); len(messages) != 1 {
+				t.Errorf("Expected the organization to read its conversation, got %v", messages)
+			}
+		})
+	}
+}
+
+func TestVectorStoreIsolation(t *testing.T) {
+	store := vectormemory.New(&MockEmbedder{})
+	orgA := multitenancy.WithOrgID(context.Background(), "org-a")
+	orgB := multitenancy.WithOrgID(context.Background(), "org-b")
+
+	if err := store.Store(orgA, []interfaces.Document{{ID: "doc-1", Content: "Quarterly revenue"}}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	if results, _ := store.Search(orgB, "revenue", 10); len(results) != 0 {
+		t.Errorf("Expected no results for another organization, got %v", results)
+	}
+	if _, err := store.Get(orgB, "doc-1"); err == nil {
+		t.Error("Expected another organization not to get the document")
+	}
+	if err := store.Delete(orgB, []string{"doc-1"}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if results, _ := store.Search(orgA, "revenue", 10); len(results) != 1 {
+		t.Errorf("Expected the document to survive deletion by another organization, got %v", results)
+	}
+}
+
+func TestStorageIsolation(t *testing.T) {
+	backend, err := local.NewWithOptions(local.WithPath(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	store := storage.NewArtifactStore(backend)
+	orgA := orgConversation("org-a", "conversation")
+	orgB := orgConversation("org-b", "conversation")
+
+	artifact, err := store.Save(orgA, []byte("a,b\n"), interfaces.Artifact{MimeType: "text/csv"})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if _, err := store.Get(orgB, artifact.URL); !errors.Is(err, multitenancy.ErrCrossTenantAccess) {
+		t.Errorf("Expected ErrCrossTenantAccess from the artifact store, got %v", err)
+	}
+	if _, err := backend.Get(orgB, artifact.URL); !errors.Is(err, multitenancy.ErrCrossTenantAccess) {
+		t.Errorf("Expected ErrCrossTenantAccess from the backend, got %v", err)
+	}
+	if err := backend.Delete(orgB, artifact.URL); !errors.Is(err, multitenancy.ErrCrossTenantAccess) {
+		t.Errorf("Expected ErrCrossTenantAccess deleting from the backend, got %v", err)
+	}
+	if _, err := store.Artifact(orgB, artifact.ID); !errors.Is(err, storage.ErrArtifactNotFound) {
+		t.Errorf("Expected ErrArtifactNotFound, got %v", err)
+	}
+	if artifacts, _ := store.List(orgB, storage.ArtifactFilter{}); len(artifacts) != 0 {
+		t.Errorf("Expected no artifacts for another organization, got %v", artifacts)
+	}
+	if _, err := store.List(orgB, storage.ArtifactFilter{OrgID: "org-a"}); !errors.Is(err, multitenancy.ErrCrossTenantAccess) {
+		t.Errorf("Expected ErrCrossTenantAccess listing another organization, got %v", err)
+	}
+	if _, err := store.Save(orgB, []byte("x"), interfaces.Artifact{MimeType: "text/plain", OrgID: "org-a"}); !errors.Is(err, multitenancy.ErrCrossTenantAccess) {
+		t.Errorf("Expected ErrCrossTenantAccess saving for another organization, got %v", err)
+	}
+
+	if data, err := store.Get(orgA, artifact.URL); err != nil || string(data) != "a,b\n" {
+		t.Errorf("Expected the organization to read its artifact, got %q, %v", data, err)
+	}
+}
@@ -0,0 +1,283 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrOrgNotFound is returned when an organization does not exist
+	ErrOrgNotFound = errors.New("organization not found")
+
+	// ErrOrgExists is returned when creating an organization that already exists
+	ErrOrgExists = errors.New("organization already exists")
+
+	// ErrOrgDisabled is returned when authenticating with a key of a disabled organization
+	ErrOrgDisabled = errors.New("organization disabled")
+)
+
+// QuotaLimits are the request and token limits of an organization; zero
+// means unlimited
+type QuotaLimits struct {
+	// RequestsPerMinute limits the API requests per calendar minute
+	RequestsPerMinute int64 `json:"requests_per_minute,omitempty"`
+
+	// TokensPerDay limits the LLM tokens used per UTC day. A run started
+	// under the limit completes, so usage can exceed it by one run.
+	TokensPerDay int64 `json:"tokens_per_day,omitempty"`
+}
+
+// Organization is a tenant managed by a TenantManager
+type Organization struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Quota      *QuotaLimits `json:"quota,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	DisabledAt time.Time    `json:"disabled_at,omitzero"`
+}
+
+// IsDisabled returns true if the organization has been disabled
+func (o *Organization) IsDisabled() bool {
+	return !o.DisabledAt.IsZero()
+}
+
+// OrgStore persists organizations
+type OrgStore interface {
+	// SaveOrg creates or replaces an organization
+	SaveOrg(ctx context.Context, org *Organization) error
+
+	// GetOrg returns the organization with the given ID or ErrOrgNotFound
+	GetOrg(ctx context.Context, id string) (*Organization, error)
+
+	// ListOrgs returns all organizations
+	ListOrgs(ctx context.Context) ([]*Organization, error)
+}
+
+// MemoryOrgStore is an in-memory OrgStore
+type MemoryOrgStore struct {
+	orgs map[string]Organization
+	mu   sync.RWMutex
+}
+
+// NewMemoryOrgStore creates a new in-memory organization store
+func NewMemoryOrgStore() *MemoryOrgStore {
+	return &MemoryOrgStore{
+		orgs: make(map[string]Organization),
+	}
+}
+
+// SaveOrg implements OrgStore.SaveOrg
+func (s *MemoryOrgStore) SaveOrg(ctx context.Context, org *Organization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *org
+	if org.Quota != nil {
+		quota := *org.Quota
+		saved.Quota = &quota
+	}
+	s.orgs[org.ID] = saved
+	return nil
+}
+
+// GetOrg implements OrgStore.GetOrg
+func (s *MemoryOrgStore) GetOrg(ctx context.Context, id string) (*Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	org, ok := s.orgs[id]
+	if !ok {
+		return nil, ErrOrgNotFound
+	}
+	return copyOrg(org), nil
+}
+
+// ListOrgs implements OrgStore.ListOrgs
+func (s *MemoryOrgStore) ListOrgs(ctx context.Context) ([]*Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	orgs := make([]*Organization, 0, len(s.orgs))
+	for _, org := range s.orgs {
+		orgs = append(orgs, copyOrg(org))
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].ID < orgs[j].ID
+	})
+	return orgs, nil
+}
+
+// copyOrg returns a copy of an organization that shares no state with it
+func copyOrg(org Organization) *Organization {
+	if org.Quota != nil {
+		quota := *org.Quota
+		org.Quota = &quota
+	}
+	return &org
+}
+
+// OrgOption represents an option for creating an organization
+type OrgOption func(*Organization)
+
+// WithOrgName sets the display name of the organization
+func WithOrgName(name string) OrgOption {
+	return func(o *Organization) {
+		o.Name = name
+	}
+}
+
+// WithOrgQuota sets the quota limits of the organization
+func WithOrgQuota(limits QuotaLimits) OrgOption {
+	return func(o *Organization) {
+		o.Quota = &limits
+	}
+}
+
+// TenantManager manages organizations and their API keys. Only keys of
+// existing, enabled organizations authenticate.
+type TenantManager struct {
+	store OrgStore
+	keys  *APIKeyManager
+	now   func() time.Time
+}
+
+// NewTenantManager creates a new tenant manager. A nil store keeps
+// organizations in memory, and a nil key manager keeps keys in memory.
+func NewTenantManager(store OrgStore, keys *APIKeyManager) *TenantManager {
+	if store == nil {
+		store = NewMemoryOrgStore()
+	}
+	if keys == nil {
+		keys = NewAPIKeyManager(nil)
+	}
+	return &TenantManager{
+		store: store,
+		keys:  keys,
+		now:   time.Now,
+	}
+}
+
+// Keys returns the API key manager of the organizations
+func (m *TenantManager) Keys() *APIKeyManager {
+	return m.keys
+}
+
+// CreateOrg creates an organization
+func (m *TenantManager) CreateOrg(ctx context.Context, id string, options ...OrgOption) (*Organization, error) {
+	if id == "" {
+		return nil, errors.New("organization ID is required")
+	}
+	if _, err := m.store.GetOrg(ctx, id); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOrgExists, id)
+	} else if !errors.Is(err, ErrOrgNotFound) {
+		return nil, err
+	}
+
+	org := &Organization{
+		ID:        id,
+		CreatedAt: m.now(),
+	}
+	for _, option := range options {
+		option(org)
+	}
+
+	if err := m.store.SaveOrg(ctx, org); err != nil {
+		return nil, fmt.Errorf("failed to save organization: %w", err)
+	}
+	return org, nil
+}
+
+// GetOrg returns an organization by ID
+func (m *TenantManager) GetOrg(ctx context.Context, id string) (*Organization, error) {
+	return m.store.GetOrg(ctx, id)
+}
+
+// ListOrgs returns all organizations, including disabled ones
+func (m *TenantManager) ListOrgs(ctx context.Context) ([]*Organization, error) {
+	return m.store.ListOrgs(ctx)
+}
+
+// SetQuota sets the quota limits of an organization; nil removes them
+func (m *TenantManager) SetQuota(ctx context.Context, id string, limits *QuotaLimits) (*Organization, error) {
+	org, err := m.store.GetOrg(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	org.Quota = limits
+	if err := m.store.SaveOrg(ctx, org); err != nil {
+		return nil, fmt.Errorf("failed to save organization: %w", err)
+	}
+	return org, nil
+}
+
+// DisableOrg disables an organization and revokes all its API keys
+func (m *TenantManager) DisableOrg(ctx context.Context, id string) error {
+	org, err := m.store.GetOrg(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	keys, err := m.keys.ListKeys(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to list API keys: %w", err)
+	}
+	for _, key := range keys {
+		if err := m.keys.RevokeKey(ctx, key.ID); err != nil {
+			return fmt.Errorf("failed to revoke API key %s: %w", key.ID, err)
+		}
+	}
+
+	if org.IsDisabled() {
+		return nil
+	}
+	org.DisabledAt = m.now()
+	if err := m.store.SaveOrg(ctx, org); err != nil {
+		return fmt.Errorf("failed to save organization: %w", err)
+	}
+	return nil
+}
+
+// IssueKey issues an API key for an existing, enabled organization. The
+// plaintext secret is returned only once.
+func (m *TenantManager) IssueKey(ctx context.Context, orgID, name string, options ...APIKeyOption) (*APIKey, string, error) {
+	org, err := m.store.GetOrg(ctx, orgID)
+	if err != nil {
+		return nil, "", err
+	}
+	if org.IsDisabled() {
+		return nil, "", fmt.Errorf("%w: %s", ErrOrgDisabled, orgID)
+	}
+	return m.keys.CreateKey(ctx, orgID, name, options...)
+}
+
+// RevokeKey revokes an API key
+func (m *TenantManager) RevokeKey(ctx context.Context, id string) error {
+	return m.keys.RevokeKey(ctx, id)
+}
+
+// Authenticate validates a plaintext API key and returns it with its
+// organization. Keys of unknown organizations fail with ErrInvalidAPIKey,
+// and keys of disabled ones with ErrOrgDisabled.
+func (m *TenantManager) Authenticate(ctx context.Context, plaintext string) (*APIKey, *Organization, error) {
+	key, err := m.keys.Authenticate(ctx, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	org, err := m.store.GetOrg(ctx, key.OrgID)
+	if errors.Is(err, ErrOrgNotFound) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if org.IsDisabled() {
+		return nil, nil, ErrOrgDisabled
+	}
+	return key, org, nil
+}
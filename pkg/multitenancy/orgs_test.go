@@ -0,0 +1,105 @@
+package multitenancy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// testTenantManager exercises the organization lifecycle of a tenant manager
+func testTenantManager(t *testing.T, tenants *multitenancy.TenantManager) {
+	t.Helper()
+	ctx := context.Background()
+
+	org, err := tenants.CreateOrg(ctx, "org-1", multitenancy.WithOrgName("Acme"), multitenancy.WithOrgQuota(multitenancy.QuotaLimits{RequestsPerMinute: 10}))
+	if err != nil {
+		t.Fatalf("CreateOrg failed: %v", err)
+	}
+	if org.Name != "Acme" || org.Quota == nil || org.Quota.RequestsPerMinute != 10 || org.CreatedAt.IsZero() {
+		t.Errorf("Unexpected organization %+v", org)
+	}
+	if _, err := tenants.CreateOrg(ctx, "org-1"); !errors.Is(err, multitenancy.ErrOrgExists) {
+		t.Errorf("Expected ErrOrgExists, got %v", err)
+	}
+
+	if _, _, err := tenants.IssueKey(ctx, "unknown", "ci"); !errors.Is(err, multitenancy.ErrOrgNotFound) {
+		t.Errorf("Expected ErrOrgNotFound issuing a key for an unknown organization, got %v", err)
+	}
+	key, secret, err := tenants.IssueKey(ctx, "org-1", "ci")
+	if err != nil {
+		t.Fatalf("IssueKey failed: %v", err)
+	}
+	authenticated, resolved, err := tenants.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if authenticated.ID != key.ID || resolved.ID != "org-1" {
+		t.Errorf("Expected key %s of org-1, got %s of %s", key.ID, authenticated.ID, resolved.ID)
+	}
+
+	// Keys of organizations the manager does not know do not authenticate
+	_, orphan, err := tenants.Keys().CreateKey(ctx, "org-2", "orphan")
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	if _, _, err := tenants.Authenticate(ctx, orphan); !errors.Is(err, multitenancy.ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey for a key of an unknown organization, got %v", err)
+	}
+
+	updated, err := tenants.SetQuota(ctx, "org-1", &multitenancy.QuotaLimits{TokensPerDay: 1000})
+	if err != nil {
+		t.Fatalf("SetQuota failed: %v", err)
+	}
+	if updated.Quota.TokensPerDay != 1000 || updated.Quota.RequestsPerMinute != 0 {
+		t.Errorf("Unexpected quota %+v", updated.Quota)
+	}
+	if stored, _ := tenants.GetOrg(ctx, "org-1"); stored.Quota == nil || stored.Quota.TokensPerDay != 1000 {
+		t.Errorf("Expected the quota to be saved, got %+v", stored)
+	}
+
+	if err := tenants.DisableOrg(ctx, "org-1"); err != nil {
+		t.Fatalf("DisableOrg failed: %v", err)
+	}
+	if _, _, err := tenants.Authenticate(ctx, secret); !errors.Is(err, multitenancy.ErrAPIKeyRevoked) {
+		t.Errorf("Expected the keys of a disabled organization to be revoked, got %v", err)
+	}
+	if _, _, err := tenants.IssueKey(ctx, "org-1", "ci"); !errors.Is(err, multitenancy.ErrOrgDisabled) {
+		t.Errorf("Expected ErrOrgDisabled, got %v", err)
+	}
+
+	orgs, err := tenants.ListOrgs(ctx)
+	if err != nil || len(orgs) != 1 || !orgs[0].IsDisabled() {
+		t.Errorf("Expected one disabled organization, got %v (%v)", orgs, err)
+	}
+}
+
+func TestTenantManager(t *testing.T) {
+	testTenantManager(t, multitenancy.NewTenantManager(nil, nil))
+}
+
+func TestRedisTenantStore(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to create miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	store := multitenancy.NewRedisTenantStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "")
+	testTenantManager(t, multitenancy.NewTenantManager(store, multitenancy.NewAPIKeyManager(store)))
+
+	if !mr.Exists("tenants:orgs") || !mr.Exists("tenants:keys") {
+		t.Error("Expected prefixed Redis keys")
+	}
+
+	// A manager of another replica sees the same organizations and keys
+	replica := multitenancy.NewTenantManager(store, multitenancy.NewAPIKeyManager(store))
+	keys, err := replica.Keys().ListKeys(context.Background(), "org-1")
+	if err != nil || len(keys) != 1 || keys[0].Hash == "" {
+		t.Errorf("Expected the stored key with its hash, got %v (%v)", keys, err)
+	}
+}
@@ -0,0 +1,68 @@
+package multitenancy
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// QuotaStore keeps usage counters that reset at the end of fixed windows,
+// shared by the server replicas enforcing the same quotas
+type QuotaStore interface {
+	// Increment adds amount to the counter of key, which expires at
+	// expiresAt, and returns the new value
+	Increment(ctx context.Context, key string, amount int64, expiresAt time.Time) (int64, error)
+
+	// Get returns the value of the counter of key, or 0 when it is unset or
+	// expired
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+// MemoryQuotaStore is an in-memory QuotaStore for single-replica servers
+type MemoryQuotaStore struct {
+	counters map[string]quotaCounter
+	mu       sync.Mutex
+}
+
+type quotaCounter struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// NewMemoryQuotaStore creates a new in-memory quota store
+func NewMemoryQuotaStore() *MemoryQuotaStore {
+	return &MemoryQuotaStore{
+		counters: make(map[string]quotaCounter),
+	}
+}
+
+// Increment implements QuotaStore.Increment
+func (s *MemoryQuotaStore) Increment(ctx context.Context, key string, amount int64, expiresAt time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, counter := range s.counters {
+		if !now.Before(counter.expiresAt) {
+			delete(s.counters, k)
+		}
+	}
+
+	counter := s.counters[key]
+	counter.value += amount
+	counter.expiresAt = expiresAt
+	s.counters[key] = counter
+	return counter.value, nil
+}
+
+// Get implements QuotaStore.Get
+func (s *MemoryQuotaStore) Get(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || !time.Now().Before(counter.expiresAt) {
+		return 0, nil
+	}
+	return counter.value, nil
+}
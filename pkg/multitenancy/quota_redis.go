@@ -0,0 +1,51 @@
+package multitenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisQuotaStore is a QuotaStore backed by Redis, so replicas share counters
+type RedisQuotaStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisQuotaStore creates a quota store whose counters are Redis keys
+// starting with keyPrefix (default "quota:")
+func NewRedisQuotaStore(client *redis.Client, keyPrefix string) *RedisQuotaStore {
+	if keyPrefix == "" {
+		keyPrefix = "quota:"
+	}
+	return &RedisQuotaStore{client: client, keyPrefix: keyPrefix}
+}
+
+// Increment implements QuotaStore.Increment
+func (s *RedisQuotaStore) Increment(ctx context.Context, key string, amount int64, expiresAt time.Time) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.IncrBy(ctx, s.keyPrefix+key, amount)
+		pipe.ExpireAt(ctx, s.keyPrefix+key, expiresAt)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment quota counter: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+// Get implements QuotaStore.Get
+func (s *RedisQuotaStore) Get(ctx context.Context, key string) (int64, error) {
+	value, err := s.client.Get(ctx, s.keyPrefix+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get quota counter: %w", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,67 @@
+package multitenancy_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+func testQuotaStore(t *testing.T, store multitenancy.QuotaStore) {
+	t.Helper()
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute)
+
+	if value, err := store.Get(ctx, "org-1:requests"); err != nil || value != 0 {
+		t.Fatalf("Get() = %d, %v for an unset counter", value, err)
+	}
+	for want := int64(1); want <= 3; want++ {
+		value, err := store.Increment(ctx, "org-1:requests", 1, expiresAt)
+		if err != nil || value != want {
+			t.Fatalf("Increment() = %d, %v, want %d", value, err, want)
+		}
+	}
+	if value, err := store.Increment(ctx, "org-1:tokens", 1500, expiresAt); err != nil || value != 1500 {
+		t.Fatalf("Increment() = %d, %v, want 1500", value, err)
+	}
+	if value, err := store.Get(ctx, "org-1:requests"); err != nil || value != 3 {
+		t.Errorf("Get() = %d, %v, want 3", value, err)
+	}
+}
+
+func TestMemoryQuotaStore(t *testing.T) {
+	store := multitenancy.NewMemoryQuotaStore()
+	testQuotaStore(t, store)
+
+	// Counters reset when their window ends
+	ctx := context.Background()
+	if _, err := store.Increment(ctx, "org-2:requests", 5, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Increment failed: %v", err)
+	}
+	if value, _ := store.Get(ctx, "org-2:requests"); value != 0 {
+		t.Errorf("Expected an expired counter to read 0, got %d", value)
+	}
+}
+
+func TestRedisQuotaStore(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to create miniredis: %v", err)
+	}
+	defer mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	testQuotaStore(t, multitenancy.NewRedisQuotaStore(client, ""))
+
+	if !mr.Exists("quota:org-1:requests") {
+		t.Error("Expected prefixed Redis keys")
+	}
+	mr.FastForward(2 * time.Minute)
+	if value, _ := multitenancy.NewRedisQuotaStore(client, "").Get(context.Background(), "org-1:requests"); value != 0 {
+		t.Errorf("Expected the counter to expire, got %d", value)
+	}
+}
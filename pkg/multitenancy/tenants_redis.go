@@ -0,0 +1,159 @@
+package multitenancy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisTenantStore is an OrgStore and APIKeyStore backed by Redis, so
+// replicas share organizations and API keys
+type RedisTenantStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisTenantStore creates a tenant store whose data is kept in Redis keys
+// starting with keyPrefix (default "tenants:")
+func NewRedisTenantStore(client *redis.Client, keyPrefix string) *RedisTenantStore {
+	if keyPrefix == "" {
+		keyPrefix = "tenants:"
+	}
+	return &RedisTenantStore{client: client, keyPrefix: keyPrefix}
+}
+
+// storedAPIKey is the stored form of an API key, including the hash that
+// APIKey omits from JSON
+type storedAPIKey struct {
+	APIKey
+	Hash string `json:"hash"`
+}
+
+// SaveOrg implements OrgStore.SaveOrg
+func (s *RedisTenantStore) SaveOrg(ctx context.Context, org *Organization) error {
+	data, err := json.Marshal(org)
+	if err != nil {
+		return fmt.Errorf("failed to marshal organization: %w", err)
+	}
+	if err := s.client.HSet(ctx, s.keyPrefix+"orgs", org.ID, data).Err(); err != nil {
+		return fmt.Errorf("failed to save organization: %w", err)
+	}
+	return nil
+}
+
+// GetOrg implements OrgStore.GetOrg
+func (s *RedisTenantStore) GetOrg(ctx context.Context, id string) (*Organization, error) {
+	data, err := s.client.HGet(ctx, s.keyPrefix+"orgs", id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrOrgNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	var org Organization
+	if err := json.Unmarshal(data, &org); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal organization: %w", err)
+	}
+	return &org, nil
+}
+
+// ListOrgs implements OrgStore.ListOrgs
+func (s *RedisTenantStore) ListOrgs(ctx context.Context) ([]*Organization, error) {
+	values, err := s.client.HGetAll(ctx, s.keyPrefix+"orgs").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	orgs := make([]*Organization, 0, len(values))
+	for _, data := range values {
+		var org Organization
+		if err := json.Unmarshal([]byte(data), &org); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal organization: %w", err)
+		}
+		orgs = append(orgs, &org)
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].ID < orgs[j].ID
+	})
+	return orgs, nil
+}
+
+// SaveKey implements APIKeyStore.SaveKey
+func (s *RedisTenantStore) SaveKey(ctx context.Context, key *APIKey) error {
+	data, err := json.Marshal(storedAPIKey{APIKey: *key, Hash: key.Hash})
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.keyPrefix+"keys", key.ID, data)
+		pipe.SAdd(ctx, s.orgKeysKey(key.OrgID), key.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save API key: %w", err)
+	}
+	return nil
+}
+
+// GetKey implements APIKeyStore.GetKey
+func (s *RedisTenantStore) GetKey(ctx context.Context, id string) (*APIKey, error) {
+	data, err := s.client.HGet(ctx, s.keyPrefix+"keys", id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return unmarshalAPIKey(data)
+}
+
+// ListKeys implements APIKeyStore.ListKeys
+func (s *RedisTenantStore) ListKeys(ctx context.Context, orgID string) ([]*APIKey, error) {
+	ids, err := s.client.SMembers(ctx, s.orgKeysKey(orgID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	keys := make([]*APIKey, 0, len(ids))
+	if len(ids) > 0 {
+		values, err := s.client.HMGet(ctx, s.keyPrefix+"keys", ids...).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list API keys: %w", err)
+		}
+		for _, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				continue
+			}
+			key, err := unmarshalAPIKey([]byte(data))
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// orgKeysKey returns the key of the set of API key IDs of an organization
+func (s *RedisTenantStore) orgKeysKey(orgID string) string {
+	return s.keyPrefix + "org_keys:" + EscapeKeySegment(orgID)
+}
+
+func unmarshalAPIKey(data []byte) (*APIKey, error) {
+	var stored storedAPIKey
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal API key: %w", err)
+	}
+	key := stored.APIKey
+	key.Hash = stored.Hash
+	return &key, nil
+}
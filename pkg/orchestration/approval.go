@@ -0,0 +1,268 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrApprovalRejected is returned for an approval task whose content was
+// rejected
+var ErrApprovalRejected = errors.New("approval rejected")
+
+// ErrApprovalNotFound is returned when deciding an approval that is not
+// pending
+var ErrApprovalNotFound = errors.New("approval not found")
+
+// ApprovalConfig configures an approval task
+type ApprovalConfig struct {
+	// Prompt tells the approver what to decide
+	Prompt string
+
+	// Timeout rejects the approval when no decision is made in time; no
+	// timeout when zero
+	Timeout time.Duration
+}
+
+// ApprovalRequest is a decision an approval task waits for
+type ApprovalRequest struct {
+	// ID identifies the request in the decision
+	ID string `json:"id"`
+
+	// WorkflowID is the ID of the workflow, if any
+	WorkflowID string `json:"workflow_id,omitempty"`
+
+	// TaskID is the ID of the approval task
+	TaskID string `json:"task_id"`
+
+	// Prompt tells the approver what to decide
+	Prompt string `json:"prompt,omitempty"`
+
+	// Content is the result of the task's dependencies to approve
+	Content string `json:"content"`
+
+	// CreatedAt is when the approval was requested
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ApprovalDecision is the answer to an approval request
+type ApprovalDecision struct {
+	// Approved tells whether the workflow goes on
+	Approved bool `json:"approved"`
+
+	// Reason explains the decision, e.g. why the content was rejected
+	Reason string `json:"reason,omitempty"`
+
+	// EditedContent replaces the content passed to the next tasks when set
+	EditedContent string `json:"edited_content,omitempty"`
+}
+
+// Approver decides an approval request. It blocks until the decision is made
+// or ctx is done.
+type Approver func(ctx context.Context, req ApprovalRequest) (ApprovalDecision, error)
+
+// AddApproval adds a task pausing the workflow until an approver approves the
+// results of its dependencies. The tasks after it receive the approved
+// content, edited or not; a rejection fails the task with
+// ErrApprovalRejected.
+func (w *Workflow) AddApproval(id string, dependencies []string, approval ApprovalConfig) {
+	w.Tasks = append(w.Tasks, &Task{
+		ID:           id,
+		Dependencies: dependencies,
+		Status:       TaskPending,
+		Approval:     &approval,
+	})
+}
+
+// approve asks the orchestrator's approver to approve the results of the
+// dependencies of an approval task
+func (o *CodeOrchestrator) approve(ctx context.Context, task *Task, workflow *Workflow) (string, error) {
+	if o.approver == nil {
+		return "", fmt.Errorf("approval task %s requires an approver", task.ID)
+	}
+
+	workflow.mu.Lock()
+	parts := make([]string, 0, len(task.Dependencies))
+	for _, depID := range task.Dependencies {
+		if _, failed := workflow.Errors[depID]; failed {
+			workflow.mu.Unlock()
+			return "", fmt.Errorf("%w: %s", ErrDependencyFailed, depID)
+		}
+		parts = append(parts, workflow.Results[depID])
+	}
+	task.Status = TaskAwaitingApproval
+	workflow.mu.Unlock()
+
+	if task.Approval.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Approval.Timeout)
+		defer cancel()
+	}
+
+	content := strings.Join(parts, "\n\n")
+	decision, err := o.approver(ctx, ApprovalRequest{
+		ID:         uuid.New().String(),
+		WorkflowID: workflow.ID,
+		TaskID:     task.ID,
+		Prompt:     task.Approval.Prompt,
+		Content:    content,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("approval failed: %w", err)
+	}
+	if !decision.Approved {
+		if decision.Reason == "" {
+			return "", ErrApprovalRejected
+		}
+		return "", fmt.Errorf("%w: %s", ErrApprovalRejected, decision.Reason)
+	}
+	if decision.EditedContent != "" {
+		return decision.EditedContent, nil
+	}
+	return content, nil
+}
+
+// ApprovalQueue holds the pending approvals of workflows until they are
+// decided, e.g. through its HTTP API. Its Wait method is an Approver:
+//
+//	queue := orchestration.NewApprovalQueue()
+//	orchestrator.WithApprover(queue.Wait)
+//	http.Handle("/approvals/", http.StripPrefix("/approvals", queue))
+type ApprovalQueue struct {
+	mu       sync.Mutex
+	pending  map[string]*pendingApproval
+	onCreate func(ApprovalRequest)
+}
+
+// pendingApproval is an approval request waiting for its decision
+type pendingApproval struct {
+	request  ApprovalRequest
+	decision chan ApprovalDecision
+}
+
+// NewApprovalQueue creates an approval queue
+func NewApprovalQueue() *ApprovalQueue {
+	return &ApprovalQueue{pending: make(map[string]*pendingApproval)}
+}
+
+// OnRequest sets a callback notified of each new approval request, e.g. to
+// send a message to the approvers
+func (q *ApprovalQueue) OnRequest(callback func(ApprovalRequest)) *ApprovalQueue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onCreate = callback
+	return q
+}
+
+// Wait adds a request to the queue and waits for its decision. A request
+// whose ctx is done is removed from the queue.
+func (q *ApprovalQueue) Wait(ctx context.Context, req ApprovalRequest) (ApprovalDecision, error) {
+	pending := &pendingApproval{request: req, decision: make(chan ApprovalDecision, 1)}
+	q.mu.Lock()
+	q.pending[req.ID] = pending
+	onCreate := q.onCreate
+	q.mu.Unlock()
+
+	defer func() {
+		q.mu.Lock()
+		delete(q.pending, req.ID)
+		q.mu.Unlock()
+	}()
+
+	if onCreate != nil {
+		onCreate(req)
+	}
+
+	select {
+	case decision := <-pending.decision:
+		return decision, nil
+	case <-ctx.Done():
+		return ApprovalDecision{}, ctx.Err()
+	}
+}
+
+// Pending returns the pending requests, oldest first
+func (q *ApprovalQueue) Pending() []ApprovalRequest {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests := make([]ApprovalRequest, 0, len(q.pending))
+	for _, pending := range q.pending {
+		requests = append(requests, pending.request)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+	})
+	return requests
+}
+
+// Decide answers a pending request
+func (q *ApprovalQueue) Decide(id string, decision ApprovalDecision) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending, ok := q.pending[id]
+	if !ok {
+		return ErrApprovalNotFound
+	}
+	delete(q.pending, id)
+	pending.decision <- decision
+	return nil
+}
+
+// ServeHTTP serves the queue over HTTP. Mount it with http.StripPrefix;
+// relative to its prefix it serves:
+//
+//	GET /         the pending requests
+//	GET /{id}     a pending request
+//	POST /{id}    decide a request with an ApprovalDecision body
+func (q *ApprovalQueue) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(r.URL.Path, "/")
+	if strings.Contains(id, "/") {
+		http.Error(w, "Invalid approval ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && id == "":
+		writeApprovalJSON(w, http.StatusOK, q.Pending())
+	case r.Method == http.MethodGet:
+		q.mu.Lock()
+		pending, ok := q.pending[id]
+		q.mu.Unlock()
+		if !ok {
+			http.Error(w, "Approval not found", http.StatusNotFound)
+			return
+		}
+		writeApprovalJSON(w, http.StatusOK, pending.request)
+	case r.Method == http.MethodPost && id != "":
+		var decision ApprovalDecision
+		if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := q.Decide(id, decision); err != nil {
+			http.Error(w, "Approval not found", http.StatusNotFound)
+			return
+		}
+		writeApprovalJSON(w, http.StatusOK, decision)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeApprovalJSON writes a JSON response
+func writeApprovalJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
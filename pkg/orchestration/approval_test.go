@@ -0,0 +1,133 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForApproval polls the queue's API until a request is pending
+func waitForApproval(t *testing.T, server *httptest.Server) ApprovalRequest {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(server.URL + "/approvals/")
+		if err != nil {
+			t.Fatalf("Failed to list approvals: %v", err)
+		}
+		var pending []ApprovalRequest
+		err = json.NewDecoder(resp.Body).Decode(&pending)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("Failed to decode approvals: %v", err)
+		}
+		if len(pending) > 0 {
+			return pending[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for an approval request")
+	return ApprovalRequest{}
+}
+
+func newApprovalWorkflow(timeout time.Duration) *Workflow {
+	workflow := NewWorkflow()
+	workflow.AddTask("draft", "writer", "Write the announcement", nil)
+	workflow.AddApproval("review", []string{"draft"}, ApprovalConfig{Prompt: "Publish this announcement?", Timeout: timeout})
+	workflow.AddTask("publish", "publisher", "Publish the announcement", []string{"review"})
+	workflow.SetFinalTask("publish")
+	return workflow
+}
+
+func TestExecuteWorkflow_Approval(t *testing.T) {
+	publisher := &fixedLLM{response: "published"}
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "writer", &fixedLLM{response: "We launch on Monday"})
+	registerAgent(t, registry, "publisher", publisher)
+
+	queue := NewApprovalQueue()
+	var notified []string
+	queue.OnRequest(func(req ApprovalRequest) { notified = append(notified, req.TaskID) })
+	mux := http.NewServeMux()
+	mux.Handle("/approvals/", http.StripPrefix("/approvals", queue))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	orchestrator := NewCodeOrchestrator(registry).WithApprover(queue.Wait)
+
+	// An approval with edits passes the edited content on
+	workflow := newApprovalWorkflow(0)
+	done := make(chan error, 1)
+	go func() {
+		_, err := orchestrator.ExecuteWorkflow(context.Background(), workflow)
+		done <- err
+	}()
+
+	req := waitForApproval(t, server)
+	if req.TaskID != "review" || req.Content != "We launch on Monday" || req.Prompt != "Publish this announcement?" {
+		t.Errorf("Unexpected approval request %+v", req)
+	}
+	resp, err := http.Post(server.URL+"/approvals/"+req.ID, "application/json",
+		strings.NewReader(`{"approved":true,"edited_content":"We launch on Tuesday"}`))
+	if err != nil {
+		t.Fatalf("Failed to approve: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if workflow.Results["review"] != "We launch on Tuesday" || workflow.Results["publish"] != "published" {
+		t.Errorf("Unexpected results %v", workflow.Results)
+	}
+	if len(notified) != 1 {
+		t.Errorf("Expected one notification, got %v", notified)
+	}
+
+	// A rejection stops the workflow
+	workflow = newApprovalWorkflow(0)
+	go func() {
+		_, err := orchestrator.ExecuteWorkflow(context.Background(), workflow)
+		done <- err
+	}()
+	req = waitForApproval(t, server)
+	if err := queue.Decide(req.ID, ApprovalDecision{Reason: "Wrong date"}); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+	if err := <-done; !errors.Is(err, ErrDependencyFailed) {
+		t.Errorf("Expected the workflow to stop, got %v", err)
+	}
+	if err := workflow.Errors["review"]; !errors.Is(err, ErrApprovalRejected) || !strings.Contains(err.Error(), "Wrong date") {
+		t.Errorf("Expected the rejection, got %v", err)
+	}
+	if publisher.calls.Load() != 1 {
+		t.Errorf("Expected the rejected announcement not to be published, got %d calls", publisher.calls.Load())
+	}
+
+	if err := queue.Decide(req.ID, ApprovalDecision{Approved: true}); !errors.Is(err, ErrApprovalNotFound) {
+		t.Errorf("Expected a decided approval not to be found, got %v", err)
+	}
+}
+
+func TestExecuteWorkflow_ApprovalTimeout(t *testing.T) {
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "writer", &fixedLLM{response: "draft"})
+	registerAgent(t, registry, "publisher", &fixedLLM{response: "published"})
+	queue := NewApprovalQueue()
+
+	workflow := newApprovalWorkflow(20 * time.Millisecond)
+	_, err := NewCodeOrchestrator(registry).WithApprover(queue.Wait).ExecuteWorkflow(context.Background(), workflow)
+	if !errors.Is(workflow.Errors["review"], context.DeadlineExceeded) {
+		t.Errorf("Expected the approval to time out, got %v", workflow.Errors["review"])
+	}
+	if err == nil || len(queue.Pending()) != 0 {
+		t.Errorf("Expected the workflow to fail and the queue to be empty, got %v and %v", err, queue.Pending())
+	}
+}
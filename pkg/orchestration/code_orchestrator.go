@@ -3,7 +3,13 @@ package orchestration
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/bus"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools/scratchpad"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tracing"
 )
 
 // TaskStatus represents the status of a task
@@ -16,6 +22,9 @@ const (
 	// TaskRunning indicates the task is running
 	TaskRunning TaskStatus = "running"
 
+	// TaskAwaitingApproval indicates the approval task waits for a decision
+	TaskAwaitingApproval TaskStatus = "awaiting_approval"
+
 	// TaskCompleted indicates the task is completed
 	TaskCompleted TaskStatus = "completed"
 
@@ -45,10 +54,47 @@ type Task struct {
 
 	// Error is any error that occurred during execution
 	Error error
+
+	// Join makes the task merge the results of its dependencies instead of
+	// running an agent
+	Join *JoinConfig
+
+	// Approval makes the task wait for the approval of the results of its
+	// dependencies instead of running an agent
+	Approval *ApprovalConfig
+
+	// Map makes the task run its agent for each item of a list in the
+	// results of its dependencies
+	Map *MapConfig
+
+	// SubWorkflow makes the task run another workflow with the results of
+	// its dependencies
+	SubWorkflow *SubWorkflowConfig
+
+	// Event makes the task wait for an event on the orchestrator's event bus
+	// instead of running an agent
+	Event *EventConfig
+
+	// Retry defines when the agent of the task runs again. For a map task,
+	// it applies to each item.
+	Retry *RetryPolicy
+
+	// Attempts is the number of times the agent of the task ran, for all
+	// the items of a map task
+	Attempts int
 }
 
 // Workflow represents a workflow of tasks
 type Workflow struct {
+	// ID identifies the workflow in the orchestrator's state store. A
+	// workflow with an ID resumes after the tasks of its last checkpoint.
+	ID string
+
+	// MaxAttempts limits the agent runs of all the tasks of the workflow,
+	// including retries and the attempts before it resumed; no limit when
+	// zero. It guards against retry loops.
+	MaxAttempts int
+
 	// Tasks is the list of tasks in the workflow
 	Tasks []*Task
 
@@ -60,14 +106,25 @@ type Workflow struct {
 
 	// FinalTaskID is the ID of the task that produces the final result
 	FinalTaskID string
+
+	// Scratchpad is shared by the agents and sub-agents of the workflow
+	// through the scratchpad tool
+	Scratchpad *scratchpad.Store
+
+	// mu guards Results and Errors while tasks run concurrently
+	mu sync.Mutex
+
+	// checkpointMu serializes the checkpoints of the workflow
+	checkpointMu sync.Mutex
 }
 
 // NewWorkflow creates a new workflow
 func NewWorkflow() *Workflow {
 	return &Workflow{
-		Tasks:   make([]*Task, 0),
-		Results: make(map[string]string),
-		Errors:  make(map[string]error),
+		Tasks:      make([]*Task, 0),
+		Results:    make(map[string]string),
+		Errors:     make(map[string]error),
+		Scratchpad: scratchpad.NewStore(),
 	}
 }
 
@@ -91,7 +148,11 @@ func (w *Workflow) SetFinalTask(id string) {
 
 // CodeOrchestrator orchestrates agents using code-defined workflows
 type CodeOrchestrator struct {
-	registry *AgentRegistry
+	registry   *AgentRegistry
+	tracer     interfaces.Tracer
+	stateStore WorkflowStateStore
+	approver   Approver
+	eventBus   bus.Bus
 }
 
 // NewCodeOrchestrator creates a new code orchestrator
@@ -101,8 +162,45 @@ func NewCodeOrchestrator(registry *AgentRegistry) *CodeOrchestrator {
 	}
 }
 
+// WithTracer sets the tracer for the orchestrator. Each workflow and each
+// of its tasks is traced as a span.
+func (o *CodeOrchestrator) WithTracer(tracer interfaces.Tracer) *CodeOrchestrator {
+	o.tracer = tracer
+	return o
+}
+
+// WithStateStore sets the store workflows with an ID are checkpointed in
+// after each task completes. Executing such a workflow again, e.g. after a
+// crash, skips the tasks completed in its last checkpoint.
+func (o *CodeOrchestrator) WithStateStore(store WorkflowStateStore) *CodeOrchestrator {
+	o.stateStore = store
+	return o
+}
+
+// WithApprover sets the approver deciding the approval tasks of workflows,
+// such as the Wait method of an ApprovalQueue
+func (o *CodeOrchestrator) WithApprover(approver Approver) *CodeOrchestrator {
+	o.approver = approver
+	return o
+}
+
 // ExecuteWorkflow executes a workflow
-func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workflow) (string, error) {
+func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workflow) (result string, err error) {
+	if o.tracer != nil {
+		var span interfaces.Span
+		ctx, span = o.tracer.StartSpan(ctx, "workflow.execute")
+		span.SetAttribute("workflow.tasks.count", len(workflow.Tasks))
+		if workflow.FinalTaskID != "" {
+			span.SetAttribute("workflow.final_task", workflow.FinalTaskID)
+		}
+		defer func() {
+			if err != nil {
+				span.RecordError(err)
+			}
+			span.End()
+		}()
+	}
+
 	// Create a wait group to wait for all tasks
 	var wg sync.WaitGroup
 
@@ -113,6 +211,27 @@ func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workfl
 	completedTasks := make(map[string]bool)
 	var completedTasksMu sync.Mutex
 
+	// Resume after the tasks completed in the last checkpoint
+	if o.stateStore != nil && workflow.ID != "" {
+		state, err := o.stateStore.LoadState(ctx, workflow.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to load workflow state: %w", err)
+		}
+		if state != nil {
+			workflow.restore(state)
+			for _, task := range workflow.Tasks {
+				if task.Status == TaskCompleted {
+					completedTasks[task.ID] = true
+				}
+			}
+		}
+	}
+
+	// Share the workflow scratchpad with every agent
+	if workflow.Scratchpad != nil {
+		ctx = scratchpad.WithStore(ctx, workflow.Scratchpad)
+	}
+
 	// Create a context with cancellation
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -127,6 +246,8 @@ func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workfl
 				completedTasks[taskID] = true
 				completedTasksMu.Unlock()
 
+				workflow.mu.Lock()
+
 				// Check if all tasks are completed
 				allCompleted := true
 				for _, task := range workflow.Tasks {
@@ -138,7 +259,9 @@ func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workfl
 
 				if allCompleted {
 					// All tasks are completed, cancel the context
+					workflow.mu.Unlock()
 					cancel()
+					wg.Done()
 					return
 				}
 
@@ -155,12 +278,20 @@ func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workfl
 						}
 
 						if allDepsCompleted {
-							// All dependencies are completed, execute the task
+							// All dependencies are completed, execute the task.
+							// It is marked running first so that a completion
+							// arriving before it starts does not run it twice.
+							task.Status = TaskRunning
 							wg.Add(1)
-							go o.executeTask(ctx, task, workflow, &wg, taskCompletionCh)
+							go o.executeTask(ctx, task, workflow, taskCompletionCh)
 						}
 					}
 				}
+				workflow.mu.Unlock()
+
+				// The completed task is done once the tasks depending on it
+				// started, so that the wait does not end in between
+				wg.Done()
 			case <-ctx.Done():
 				// Context is cancelled, exit
 				return
@@ -168,13 +299,21 @@ func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workfl
 		}
 	}()
 
-	// Start tasks with no dependencies
+	// Start tasks whose dependencies are completed, i.e. tasks with no
+	// dependencies unless the workflow resumes
+	workflow.mu.Lock()
 	for _, task := range workflow.Tasks {
-		if len(task.Dependencies) == 0 {
+		ready := task.Status == TaskPending
+		for _, depID := range task.Dependencies {
+			ready = ready && completedTasks[depID]
+		}
+		if ready {
+			task.Status = TaskRunning
 			wg.Add(1)
-			go o.executeTask(ctx, task, workflow, &wg, taskCompletionCh)
+			go o.executeTask(ctx, task, workflow, taskCompletionCh)
 		}
 	}
+	workflow.mu.Unlock()
 
 	// Wait for all tasks to complete
 	wg.Wait()
@@ -197,45 +336,162 @@ func (o *CodeOrchestrator) ExecuteWorkflow(ctx context.Context, workflow *Workfl
 }
 
 // executeTask executes a task
-func (o *CodeOrchestrator) executeTask(ctx context.Context, task *Task, workflow *Workflow, wg *sync.WaitGroup, completionCh chan<- string) {
-	defer wg.Done()
+func (o *CodeOrchestrator) executeTask(ctx context.Context, task *Task, workflow *Workflow, completionCh chan<- string) {
+	// Signal task completion
+	defer func() { completionCh <- task.ID }()
+
+	if o.tracer != nil {
+		var span interfaces.Span
+		ctx, span = o.tracer.StartSpan(ctx, "workflow.task")
+		span.SetAttribute("workflow.task.id", task.ID)
+		if task.Join != nil {
+			span.SetAttribute("workflow.task.join", true)
+		} else if task.Approval != nil {
+			span.SetAttribute("workflow.task.approval", true)
+		} else if task.SubWorkflow != nil {
+			span.SetAttribute("workflow.task.subworkflow", true)
+		} else if task.Event != nil {
+			span.SetAttribute("workflow.task.event", task.Event.Topic)
+		} else {
+			span.SetAttribute(tracing.GenAIAgentName, task.AgentID)
+			if task.Map != nil {
+				span.SetAttribute("workflow.task.map", true)
+			}
+		}
+		if len(task.Dependencies) > 0 {
+			span.SetAttribute("workflow.task.dependencies", strings.Join(task.Dependencies, ","))
+		}
+		defer func() {
+			span.SetAttribute("workflow.task.status", string(task.Status))
+			if task.Error != nil {
+				span.RecordError(task.Error)
+			}
+			span.End()
+		}()
+	}
 
-	// Update task status
-	task.Status = TaskRunning
+	// Join tasks handle failed dependencies themselves
+	if task.Join != nil {
+		result, err := workflow.merge(ctx, task)
+		if err != nil {
+			o.failTask(ctx, task, workflow, fmt.Errorf("join failed: %w", err))
+			return
+		}
+		o.completeTask(ctx, task, workflow, result)
+		return
+	}
 
-	// Get the agent
-	agent, ok := o.registry.Get(task.AgentID)
-	if !ok {
-		task.Status = TaskFailed
-		task.Error = fmt.Errorf("agent not found: %s", task.AgentID)
-		workflow.Errors[task.ID] = task.Error
-		completionCh <- task.ID
+	if task.Map != nil {
+		result, err := o.mapItems(ctx, task, workflow)
+		if err != nil {
+			o.failTask(ctx, task, workflow, err)
+			return
+		}
+		o.completeTask(ctx, task, workflow, result)
+		return
+	}
+
+	if task.Event != nil {
+		result, err := o.waitForEvent(ctx, task, workflow)
+		if err != nil {
+			o.failTask(ctx, task, workflow, err)
+			return
+		}
+		o.completeTask(ctx, task, workflow, result)
+		return
+	}
+
+	if task.SubWorkflow != nil {
+		result, err := o.runSubWorkflow(ctx, task, workflow)
+		if err != nil {
+			o.failTask(ctx, task, workflow, err)
+			return
+		}
+		o.completeTask(ctx, task, workflow, result)
+		return
+	}
+
+	if task.Approval != nil {
+		result, err := o.approve(ctx, task, workflow)
+		if err != nil {
+			o.failTask(ctx, task, workflow, err)
+			return
+		}
+		o.completeTask(ctx, task, workflow, result)
 		return
 	}
 
 	// Prepare input with results from dependencies
 	input := task.Input
+	workflow.mu.Lock()
 	for _, depID := range task.Dependencies {
+		if _, failed := workflow.Errors[depID]; failed {
+			workflow.mu.Unlock()
+			o.failTask(ctx, task, workflow, fmt.Errorf("%w: %s", ErrDependencyFailed, depID))
+			return
+		}
 		if result, ok := workflow.Results[depID]; ok {
 			input = fmt.Sprintf("%s\n\nResult from %s: %s", input, depID, result)
 		}
 	}
+	workflow.mu.Unlock()
+
+	// Get the agent
+	agent, ok := o.registry.Get(task.AgentID)
+	if !ok {
+		o.failTask(ctx, task, workflow, fmt.Errorf("agent not found: %s", task.AgentID))
+		return
+	}
 
 	// Execute the agent
-	result, err := agent.Run(ctx, input)
+	result, err := o.runAgent(ctx, task, workflow, agent, input)
 	if err != nil {
-		task.Status = TaskFailed
-		task.Error = fmt.Errorf("agent execution failed: %w", err)
-		workflow.Errors[task.ID] = task.Error
-		completionCh <- task.ID
+		o.failTask(ctx, task, workflow, fmt.Errorf("agent execution failed: %w", err))
 		return
 	}
 
-	// Update task status and result
+	o.completeTask(ctx, task, workflow, result)
+}
+
+// completeTask records the result of a task and checkpoints the workflow. A
+// task whose checkpoint fails fails, so that the workflow does not go on
+// past a task it could not persist.
+func (o *CodeOrchestrator) completeTask(ctx context.Context, task *Task, workflow *Workflow, result string) {
+	workflow.mu.Lock()
+	workflow.Results[task.ID] = result
+	workflow.mu.Unlock()
+
+	if o.stateStore != nil && workflow.ID != "" {
+		if err := o.checkpoint(ctx, workflow); err != nil {
+			workflow.mu.Lock()
+			delete(workflow.Results, task.ID)
+			workflow.mu.Unlock()
+			o.failTask(ctx, task, workflow, err)
+			return
+		}
+	}
+
+	workflow.mu.Lock()
 	task.Status = TaskCompleted
 	task.Result = result
-	workflow.Results[task.ID] = result
+	workflow.mu.Unlock()
 
-	// Signal task completion
-	completionCh <- task.ID
+	o.publishTaskEvent(ctx, workflow, task, result, nil)
+}
+
+// failTask records the error of a task and checkpoints the workflow
+func (o *CodeOrchestrator) failTask(ctx context.Context, task *Task, workflow *Workflow, err error) {
+	workflow.mu.Lock()
+	task.Status = TaskFailed
+	task.Error = err
+	workflow.Errors[task.ID] = err
+	workflow.mu.Unlock()
+
+	if o.stateStore != nil && workflow.ID != "" {
+		// The error is recorded for inspection only: failed tasks run again
+		// when the workflow resumes, so a lost checkpoint loses nothing
+		_ = o.checkpoint(ctx, workflow)
+	}
+
+	o.publishTaskEvent(ctx, workflow, task, "", err)
 }
@@ -0,0 +1,124 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/bus"
+)
+
+// Topics of the events workflows publish on the orchestrator's event bus
+const (
+	// TopicTaskCompleted is the topic of the tasks that completed
+	TopicTaskCompleted = "workflow.task.completed"
+
+	// TopicTaskFailed is the topic of the tasks that failed
+	TopicTaskFailed = "workflow.task.failed"
+)
+
+// TaskEvent is the payload of the events of a task
+type TaskEvent struct {
+	WorkflowID string `json:"workflow_id,omitempty"`
+	TaskID     string `json:"task_id"`
+	Result     string `json:"result,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// EventConfig configures a task waiting for an event
+type EventConfig struct {
+	// Topic is the topic of the event, a glob pattern like orders.* matching
+	// several topics
+	Topic string
+
+	// Match selects the event among the messages of the topic, the first
+	// message by default
+	Match func(msg bus.Message) bool
+
+	// Timeout fails the task without event in time, no timeout when zero
+	Timeout time.Duration
+}
+
+// WithEventBus sets the event bus of the orchestrator. Tasks publish their
+// completion and failure on it, and event tasks wait for events from it.
+func (o *CodeOrchestrator) WithEventBus(b bus.Bus) *CodeOrchestrator {
+	o.eventBus = b
+	return o
+}
+
+// AddEventWait adds a task waiting, once its dependencies complete, for an
+// event published on the orchestrator's event bus. Its result is the
+// payload of the event. Events published before the task starts are not
+// received.
+func (w *Workflow) AddEventWait(id string, dependencies []string, config EventConfig) {
+	w.Tasks = append(w.Tasks, &Task{
+		ID:           id,
+		Dependencies: dependencies,
+		Status:       TaskPending,
+		Event:        &config,
+	})
+}
+
+// waitForEvent waits for the event of an event task
+func (o *CodeOrchestrator) waitForEvent(ctx context.Context, task *Task, workflow *Workflow) (string, error) {
+	if o.eventBus == nil {
+		return "", fmt.Errorf("event task %s requires an event bus", task.ID)
+	}
+
+	workflow.mu.Lock()
+	for _, depID := range task.Dependencies {
+		if _, failed := workflow.Errors[depID]; failed {
+			workflow.mu.Unlock()
+			return "", fmt.Errorf("%w: %s", ErrDependencyFailed, depID)
+		}
+	}
+	workflow.mu.Unlock()
+
+	if task.Event.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Event.Timeout)
+		defer cancel()
+	}
+
+	received := make(chan bus.Message, 1)
+	subscription, err := o.eventBus.Subscribe(ctx, task.Event.Topic, func(ctx context.Context, msg bus.Message) error {
+		if task.Event.Match != nil && !task.Event.Match(msg) {
+			return nil
+		}
+		select {
+		case received <- msg:
+		default:
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to subscribe to %s: %w", task.Event.Topic, err)
+	}
+	defer func() { _ = subscription.Unsubscribe() }()
+
+	select {
+	case msg := <-received:
+		return msg.Text(), nil
+	case <-ctx.Done():
+		return "", fmt.Errorf("no event on %s: %w", task.Event.Topic, ctx.Err())
+	}
+}
+
+// publishTaskEvent publishes the completion or failure of a task. Events are
+// best effort: a task does not fail because its event was not published.
+func (o *CodeOrchestrator) publishTaskEvent(ctx context.Context, workflow *Workflow, task *Task, result string, taskErr error) {
+	if o.eventBus == nil {
+		return
+	}
+
+	topic, event := TopicTaskCompleted, TaskEvent{WorkflowID: workflow.ID, TaskID: task.ID, Result: result}
+	if taskErr != nil {
+		topic, event = TopicTaskFailed, TaskEvent{WorkflowID: workflow.ID, TaskID: task.ID, Error: taskErr.Error()}
+	}
+	msg, err := bus.NewMessage(topic, topic, event)
+	if err != nil {
+		return
+	}
+	msg.Source = task.ID
+	_ = o.eventBus.Publish(ctx, msg)
+}
@@ -0,0 +1,94 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/bus"
+)
+
+func TestExecuteWorkflow_Events(t *testing.T) {
+	ctx := context.Background()
+	eventBus := bus.NewMemoryBus()
+	defer eventBus.Close()
+
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "writer", &fixedLLM{response: "draft"})
+	registerAgent(t, registry, "publisher", &fixedLLM{response: "published"})
+
+	events := make(chan TaskEvent, 10)
+	if _, err := bus.On(ctx, eventBus, "workflow.task.*", func(ctx context.Context, msg bus.Message, event TaskEvent) error {
+		events <- event
+		return nil
+	}); err != nil {
+		t.Fatalf("On() error = %v", err)
+	}
+
+	// Reviews are published by another service until the workflow ends
+	reviewCtx, stopReviews := context.WithCancel(ctx)
+	defer stopReviews()
+	go func() {
+		for reviewCtx.Err() == nil {
+			for _, decision := range []string{"rejected", "approved"} {
+				_ = bus.Publish(reviewCtx, eventBus, "reviews", "review.completed", map[string]string{"decision": decision})
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	workflow := NewWorkflow()
+	workflow.ID = "release-1"
+	workflow.AddTask("draft", "writer", "Write the release notes", nil)
+	workflow.AddEventWait("review", []string{"draft"}, EventConfig{
+		Topic: "reviews",
+		Match: func(msg bus.Message) bool {
+			var review map[string]string
+			return msg.Decode(&review) == nil && review["decision"] == "approved"
+		},
+		Timeout: time.Second,
+	})
+	workflow.AddTask("publish", "publisher", "Publish the release notes", []string{"review"})
+	workflow.SetFinalTask("publish")
+
+	orchestrator := NewCodeOrchestrator(registry).WithEventBus(eventBus)
+	result, err := orchestrator.ExecuteWorkflow(ctx, workflow)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if result != "published" || workflow.Results["review"] != `{"decision":"approved"}` {
+		t.Errorf("Unexpected results %v", workflow.Results)
+	}
+
+	stopReviews()
+
+	var completed []string
+	for len(completed) < 3 {
+		select {
+		case event := <-events:
+			if event.WorkflowID != "release-1" {
+				t.Errorf("Expected the workflow ID in the event, got %+v", event)
+			}
+			completed = append(completed, event.TaskID)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for task events, got %v", completed)
+		}
+	}
+
+	// Without an event in time the task fails
+	workflow = NewWorkflow()
+	workflow.AddEventWait("review", nil, EventConfig{Topic: "reviews", Timeout: 10 * time.Millisecond})
+	workflow.SetFinalTask("review")
+	if _, err := orchestrator.ExecuteWorkflow(ctx, workflow); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+	select {
+	case event := <-events:
+		if event.TaskID != "review" || event.Error == "" {
+			t.Errorf("Expected the failure of the review, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the failure event")
+	}
+}
@@ -0,0 +1,207 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MergeStrategy defines how a join task merges the results of its branches
+type MergeStrategy string
+
+const (
+	// MergeConcat concatenates the branch results in the order of the
+	// dependencies
+	MergeConcat MergeStrategy = "concat"
+
+	// MergeJSON merges branch results that are JSON objects into one
+	// object, later branches overriding the keys of earlier ones. Results
+	// that are not JSON objects are set under the ID of their branch.
+	MergeJSON MergeStrategy = "json"
+
+	// MergeJSONArray returns a JSON array of the branch results, in the order
+	// of the dependencies. Results that are JSON are embedded as such.
+	MergeJSONArray MergeStrategy = "json_array"
+
+	// MergeCustom merges the branch results with the join's Reducer
+	MergeCustom MergeStrategy = "custom"
+)
+
+// BranchErrorPolicy defines how a join task handles failed branches
+type BranchErrorPolicy string
+
+const (
+	// FailOnBranchError fails the join when any of its branches failed
+	FailOnBranchError BranchErrorPolicy = "fail"
+
+	// SkipFailedBranches merges the branches that succeeded and fails the
+	// join only when all of them failed
+	SkipFailedBranches BranchErrorPolicy = "skip"
+)
+
+// ErrDependencyFailed is returned for a task that did not run because one of
+// its dependencies failed
+var ErrDependencyFailed = errors.New("dependency failed")
+
+// BranchResult is the result of a branch of a join
+type BranchResult struct {
+	// TaskID is the ID of the branch task
+	TaskID string
+
+	// Result is the result of the branch, empty if it failed
+	Result string
+
+	// Error is the error of the branch, nil if it succeeded
+	Error error
+}
+
+// Reducer merges the results of the branches of a join. With
+// SkipFailedBranches it also receives the failed branches, with their Error
+// set.
+type Reducer func(ctx context.Context, results []BranchResult) (string, error)
+
+// JoinConfig configures a join task
+type JoinConfig struct {
+	// Strategy is how the branch results are merged, MergeConcat by default
+	Strategy MergeStrategy
+
+	// Separator separates the results merged with MergeConcat, a blank line
+	// by default
+	Separator string
+
+	// Reducer merges the results with MergeCustom
+	Reducer Reducer
+
+	// OnError is how failed branches are handled, FailOnBranchError by
+	// default
+	OnError BranchErrorPolicy
+}
+
+// Branch is an agent task of a parallel fan-out
+type Branch struct {
+	// ID is the unique identifier of the branch task
+	ID string
+
+	// AgentID is the ID of the agent to execute the branch
+	AgentID string
+
+	// Input is the input to provide to the agent
+	Input string
+}
+
+// AddParallel adds branches run concurrently once the dependencies complete,
+// and a join task with the given ID merging their results. Tasks depending on
+// id receive the merged result.
+func (w *Workflow) AddParallel(id string, dependencies []string, branches []Branch, join JoinConfig) {
+	branchIDs := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		w.AddTask(branch.ID, branch.AgentID, branch.Input, dependencies)
+		branchIDs = append(branchIDs, branch.ID)
+	}
+	w.AddJoin(id, branchIDs, join)
+}
+
+// AddJoin adds a task merging the results of its dependencies instead of
+// running an agent
+func (w *Workflow) AddJoin(id string, dependencies []string, join JoinConfig) {
+	w.Tasks = append(w.Tasks, &Task{
+		ID:           id,
+		Dependencies: dependencies,
+		Status:       TaskPending,
+		Join:         &join,
+	})
+}
+
+// merge merges the results of the dependencies of a join task
+func (w *Workflow) merge(ctx context.Context, task *Task) (string, error) {
+	w.mu.Lock()
+	var results []BranchResult
+	for _, depID := range task.Dependencies {
+		results = append(results, BranchResult{TaskID: depID, Result: w.Results[depID], Error: w.Errors[depID]})
+	}
+	w.mu.Unlock()
+
+	return mergeResults(ctx, task.ID, task.Join, results, ErrDependencyFailed)
+}
+
+// mergeResults merges branch results as configured by join. failedErr is
+// wrapped in the error returned when branches failed.
+func mergeResults(ctx context.Context, id string, join *JoinConfig, results []BranchResult, failedErr error) (string, error) {
+	var failed []string
+	for _, result := range results {
+		if result.Error != nil {
+			failed = append(failed, result.TaskID)
+		}
+	}
+
+	if len(failed) > 0 {
+		if join.OnError != SkipFailedBranches {
+			return "", fmt.Errorf("%w: %s", failedErr, strings.Join(failed, ", "))
+		}
+		if len(failed) == len(results) {
+			return "", fmt.Errorf("all branches failed: %s", strings.Join(failed, ", "))
+		}
+	}
+
+	if join.Strategy == MergeCustom {
+		if join.Reducer == nil {
+			return "", fmt.Errorf("join %s has no reducer", id)
+		}
+		return join.Reducer(ctx, results)
+	}
+
+	succeeded := make([]BranchResult, 0, len(results))
+	for _, result := range results {
+		if result.Error == nil {
+			succeeded = append(succeeded, result)
+		}
+	}
+
+	switch join.Strategy {
+	case MergeConcat, "":
+		separator := join.Separator
+		if separator == "" {
+			separator = "\n\n"
+		}
+		parts := make([]string, 0, len(succeeded))
+		for _, result := range succeeded {
+			parts = append(parts, result.Result)
+		}
+		return strings.Join(parts, separator), nil
+	case MergeJSON:
+		merged := make(map[string]interface{})
+		for _, result := range succeeded {
+			var object map[string]interface{}
+			if err := json.Unmarshal([]byte(extractJSON(result.Result)), &object); err != nil || object == nil {
+				merged[result.TaskID] = result.Result
+				continue
+			}
+			for key, value := range object {
+				merged[key] = value
+			}
+		}
+		data, err := json.Marshal(merged)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal merged results: %w", err)
+		}
+		return string(data), nil
+	case MergeJSONArray:
+		merged := make([]interface{}, 0, len(succeeded))
+		for _, result := range succeeded {
+			if json.Valid([]byte(result.Result)) {
+				merged = append(merged, json.RawMessage(result.Result))
+			} else {
+				merged = append(merged, result.Result)
+			}
+		}
+		data, err := json.Marshal(merged)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal merged results: %w", err)
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unknown merge strategy: %s", join.Strategy)
+	}
+}
@@ -0,0 +1,163 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// fixedLLM answers every prompt with the same response, or fails
+type fixedLLM struct {
+	response string
+	err      error
+	delay    time.Duration
+	running  *atomic.Int32
+	peak     *atomic.Int32
+
+	// failures is the number of calls failing with err before the calls
+	// succeed, all of them when zero
+	failures int32
+	calls    atomic.Int32
+}
+
+func (m *fixedLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	if call := m.calls.Add(1); m.failures > 0 && call > m.failures {
+		return m.response, nil
+	}
+	if m.running != nil {
+		n := m.running.Add(1)
+		defer m.running.Add(-1)
+		for {
+			peak := m.peak.Load()
+			if n <= peak || m.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+	}
+	time.Sleep(m.delay)
+	return m.response, m.err
+}
+
+func (m *fixedLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *fixedLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := m.Generate(ctx, prompt, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &interfaces.LLMResponse{Content: content}, nil
+}
+
+func (m *fixedLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return m.GenerateDetailed(ctx, prompt, options...)
+}
+
+func (m *fixedLLM) Name() string { return "fixed" }
+
+func (m *fixedLLM) SupportsStreaming() bool { return false }
+
+func registerAgent(t *testing.T, registry *AgentRegistry, id string, llm *fixedLLM) {
+	t.Helper()
+	a, err := agent.NewAgent(agent.WithName(id), agent.WithLLM(llm))
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	registry.Register(id, a)
+}
+
+func TestExecuteWorkflow_ParallelJoin(t *testing.T) {
+	var running, peak atomic.Int32
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "flights", &fixedLLM{response: `{"flight": "TP123"}`, delay: 50 * time.Millisecond, running: &running, peak: &peak})
+	registerAgent(t, registry, "hotels", &fixedLLM{response: "```json\n{\"hotel\": \"Bairro Alto\"}\n```", delay: 50 * time.Millisecond, running: &running, peak: &peak})
+	registerAgent(t, registry, "weather", &fixedLLM{response: "Sunny", delay: 50 * time.Millisecond, running: &running, peak: &peak})
+
+	workflow := NewWorkflow()
+	workflow.AddParallel("plan", nil, []Branch{
+		{ID: "flight", AgentID: "flights", Input: "Find a flight to Lisbon"},
+		{ID: "hotel", AgentID: "hotels", Input: "Find a hotel in Lisbon"},
+		{ID: "forecast", AgentID: "weather", Input: "Weather in Lisbon"},
+	}, JoinConfig{Strategy: MergeJSON})
+	workflow.SetFinalTask("plan")
+
+	result, err := NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if result != `{"flight":"TP123","forecast":"Sunny","hotel":"Bairro Alto"}` {
+		t.Errorf("Unexpected merged result %s", result)
+	}
+	if peak.Load() != 3 {
+		t.Errorf("Expected the branches to run concurrently, peak concurrency was %d", peak.Load())
+	}
+}
+
+func TestExecuteWorkflow_JoinBranchErrors(t *testing.T) {
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "ok", &fixedLLM{response: "done"})
+	registerAgent(t, registry, "broken", &fixedLLM{err: errors.New("model unavailable")})
+	registerAgent(t, registry, "summary", &fixedLLM{response: "summary"})
+
+	branches := []Branch{
+		{ID: "a", AgentID: "ok"},
+		{ID: "b", AgentID: "broken"},
+		{ID: "c", AgentID: "ok"},
+	}
+
+	// By default a failed branch fails the join and the tasks after it
+	workflow := NewWorkflow()
+	workflow.AddParallel("join", nil, branches, JoinConfig{})
+	workflow.AddTask("summarize", "summary", "Summarize", []string{"join"})
+	workflow.SetFinalTask("summarize")
+	_, err := NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow)
+	if !errors.Is(err, ErrDependencyFailed) {
+		t.Fatalf("Expected a dependency error, got %v", err)
+	}
+	if !errors.Is(workflow.Errors["join"], ErrDependencyFailed) || !strings.Contains(workflow.Errors["join"].Error(), "b") {
+		t.Errorf("Expected the join to fail on branch b, got %v", workflow.Errors["join"])
+	}
+
+	// Skipping failed branches merges the others
+	workflow = NewWorkflow()
+	workflow.AddParallel("join", nil, branches, JoinConfig{OnError: SkipFailedBranches, Separator: " | "})
+	workflow.SetFinalTask("join")
+	result, err := NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if result != "done | done" {
+		t.Errorf("Expected the successful branches, got %q", result)
+	}
+
+	// The reducer sees the failed branches too
+	var failed []string
+	workflow = NewWorkflow()
+	workflow.AddParallel("join", nil, branches, JoinConfig{
+		Strategy: MergeCustom,
+		OnError:  SkipFailedBranches,
+		Reducer: func(ctx context.Context, results []BranchResult) (string, error) {
+			for _, result := range results {
+				if result.Error != nil {
+					failed = append(failed, result.TaskID)
+				}
+			}
+			return "reduced", nil
+		},
+	})
+	workflow.SetFinalTask("join")
+	result, err = NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow)
+	if err != nil || result != "reduced" {
+		t.Fatalf("Expected the reducer's result, got %q, %v", result, err)
+	}
+	if len(failed) != 1 || failed[0] != "b" {
+		t.Errorf("Expected the reducer to see branch b fail, got %v", failed)
+	}
+}
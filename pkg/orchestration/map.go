@@ -0,0 +1,145 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrItemFailed is returned for a map task whose items failed
+var ErrItemFailed = errors.New("map item failed")
+
+// MapConfig configures a map task
+type MapConfig struct {
+	// AgentID is the ID of the agent run for each item
+	AgentID string
+
+	// Input is the input of each run, followed by the item
+	Input string
+
+	// ItemsPath is the dot-separated path of the list in the JSON results of
+	// the dependencies, e.g. "files" or "plan.files". When empty, the
+	// results must be lists.
+	ItemsPath string
+
+	// MaxConcurrency limits the items processed at once; all at once when
+	// zero
+	MaxConcurrency int
+
+	// Join merges the results of the items, in the order of the items
+	Join JoinConfig
+}
+
+// AddMap adds a task running an agent for each item of a list in the JSON
+// results of its dependencies, such as the files of a generation plan, and
+// merging the results of the items
+func (w *Workflow) AddMap(id string, dependencies []string, config MapConfig) {
+	w.Tasks = append(w.Tasks, &Task{
+		ID:           id,
+		AgentID:      config.AgentID,
+		Dependencies: dependencies,
+		Status:       TaskPending,
+		Map:          &config,
+	})
+}
+
+// mapItems runs the agent of a map task for each item and merges the results
+func (o *CodeOrchestrator) mapItems(ctx context.Context, task *Task, workflow *Workflow) (string, error) {
+	config := task.Map
+
+	workflow.mu.Lock()
+	var items []string
+	for _, depID := range task.Dependencies {
+		if _, failed := workflow.Errors[depID]; failed {
+			workflow.mu.Unlock()
+			return "", fmt.Errorf("%w: %s", ErrDependencyFailed, depID)
+		}
+		depItems, err := extractItems(workflow.Results[depID], config.ItemsPath)
+		if err != nil {
+			workflow.mu.Unlock()
+			return "", fmt.Errorf("failed to get the items from %s: %w", depID, err)
+		}
+		items = append(items, depItems...)
+	}
+	workflow.mu.Unlock()
+
+	runner, ok := o.registry.Get(config.AgentID)
+	if !ok {
+		return "", fmt.Errorf("agent not found: %s", config.AgentID)
+	}
+
+	concurrency := config.MaxConcurrency
+	if concurrency <= 0 || concurrency > len(items) {
+		concurrency = len(items)
+	}
+	semaphore := make(chan struct{}, max(concurrency, 1))
+
+	results := make([]BranchResult, len(items))
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i].TaskID = fmt.Sprintf("%s[%d]", task.ID, i)
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				results[i].Error = ctx.Err()
+				return
+			}
+
+			input := fmt.Sprintf("%s\n\nItem %d of %d:\n%s", config.Input, i+1, len(items), item)
+			results[i].Result, results[i].Error = o.runAgent(ctx, task, workflow, runner, input)
+		}()
+	}
+	wg.Wait()
+
+	return mergeResults(ctx, task.ID, &config.Join, results, ErrItemFailed)
+}
+
+// extractItems returns the items of the list at path in a JSON result. Items
+// that are not strings are returned as JSON.
+func extractItems(result, path string) ([]string, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(result)), &value); err != nil {
+		// The JSON may be surrounded by text or in a code block
+		if err := json.Unmarshal([]byte(extractJSON(result)), &value); err != nil {
+			return nil, fmt.Errorf("result is not JSON")
+		}
+	}
+
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("no object at %q", key)
+			}
+			if value, ok = object[key]; !ok {
+				return nil, fmt.Errorf("no field %q", key)
+			}
+		}
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("not a list")
+	}
+	items := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			items = append(items, s)
+			continue
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, string(data))
+	}
+	return items, nil
+}
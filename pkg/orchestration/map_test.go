@@ -0,0 +1,90 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecuteWorkflow_Map(t *testing.T) {
+	var running, peak atomic.Int32
+	writer := &fixedLLM{response: `{"written": true}`, delay: 30 * time.Millisecond, running: &running, peak: &peak}
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "planner", &fixedLLM{response: "```json\n{\"plan\": {\"files\": [{\"path\": \"main.tf\"}, {\"path\": \"variables.tf\"}, \"outputs.tf\", \"README.md\"]}}\n```"})
+	registerAgent(t, registry, "writer", writer)
+
+	workflow := NewWorkflow()
+	workflow.AddTask("plan", "planner", "Plan the files", nil)
+	workflow.AddMap("write", []string{"plan"}, MapConfig{
+		AgentID:        "writer",
+		Input:          "Write this file",
+		ItemsPath:      "plan.files",
+		MaxConcurrency: 2,
+		Join:           JoinConfig{Strategy: MergeJSONArray},
+	})
+	workflow.SetFinalTask("write")
+
+	result, err := NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if result != `[{"written":true},{"written":true},{"written":true},{"written":true}]` {
+		t.Errorf("Unexpected aggregated result %s", result)
+	}
+	if writer.calls.Load() != 4 || workflow.Tasks[1].Attempts != 4 {
+		t.Errorf("Expected a run per item, got %d", writer.calls.Load())
+	}
+	if peak.Load() != 2 {
+		t.Errorf("Expected at most 2 items at once, got %d", peak.Load())
+	}
+}
+
+func TestExecuteWorkflow_MapItemErrors(t *testing.T) {
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "lister", &fixedLLM{response: `["a", "b"]`})
+	registerAgent(t, registry, "broken", &fixedLLM{err: errors.New("model unavailable")})
+
+	workflow := NewWorkflow()
+	workflow.AddTask("list", "lister", "List", nil)
+	workflow.AddMap("process", []string{"list"}, MapConfig{AgentID: "broken"})
+	workflow.SetFinalTask("process")
+
+	_, err := NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow)
+	if !errors.Is(err, ErrItemFailed) || !strings.Contains(err.Error(), "process[0], process[1]") {
+		t.Errorf("Expected the items to fail, got %v", err)
+	}
+}
+
+func TestExtractItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  string
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "list", result: `["a", "b"]`, want: []string{"a", "b"}},
+		{name: "objects", result: `[{"n": 1}, 2]`, want: []string{`{"n":1}`, "2"}},
+		{name: "path", result: `{"plan": {"files": ["x"]}}`, path: "plan.files", want: []string{"x"}},
+		{name: "code block", result: "Here:\n```json\n[\"a\"]\n```", want: []string{"a"}},
+		{name: "text around object", result: `The plan is {"files": ["x"]}.`, path: "files", want: []string{"x"}},
+		{name: "missing field", result: `{"plan": {}}`, path: "plan.files", wantErr: true},
+		{name: "not a list", result: `{"files": "x"}`, path: "files", wantErr: true},
+		{name: "not JSON", result: "no plan", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := extractItems(tt.result, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractItems() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(items, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("extractItems() = %v, want %v", items, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
+)
+
+// ErrRetriesExhausted is returned for a task whose results were all rejected
+// by the RetryIf predicate of its retry policy
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// ErrAttemptLimit is returned for a task that would exceed the MaxAttempts of
+// its workflow
+var ErrAttemptLimit = errors.New("workflow attempt limit reached")
+
+// RetryPolicy defines when and how often the agent of a task runs again
+type RetryPolicy struct {
+	// Backoff sets the maximum attempts and the intervals between them,
+	// retry.NewPolicy() when nil
+	Backoff *retry.Policy
+
+	// RetryIf decides whether an attempt is retried, e.g. because its result
+	// is not valid JSON. By default failed attempts are retried.
+	RetryIf func(result string, err error) bool
+}
+
+// SetRetryPolicy sets the retry policy of the task with the given ID
+func (w *Workflow) SetRetryPolicy(id string, policy RetryPolicy) {
+	for _, task := range w.Tasks {
+		if task.ID == id {
+			task.Retry = &policy
+		}
+	}
+}
+
+// countAttempt counts an attempt of a task, unless the workflow's attempts
+// would exceed MaxAttempts
+func (w *Workflow) countAttempt(task *Task) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.MaxAttempts > 0 {
+		total := 0
+		for _, t := range w.Tasks {
+			total += t.Attempts
+		}
+		if total >= w.MaxAttempts {
+			return fmt.Errorf("%w (%d attempts)", ErrAttemptLimit, w.MaxAttempts)
+		}
+	}
+	task.Attempts++
+	return nil
+}
+
+// runAgent runs the agent of a task, retrying according to its retry
+// policy. Retries are told why the previous attempt was retried.
+func (o *CodeOrchestrator) runAgent(ctx context.Context, task *Task, workflow *Workflow, runner *agent.Agent, input string) (string, error) {
+	maxAttempts := 1
+	var backoff *retry.Policy
+	if task.Retry != nil {
+		backoff = task.Retry.Backoff
+		if backoff == nil {
+			backoff = retry.NewPolicy()
+		}
+		maxAttempts = int(backoff.MaximumAttempts)
+	}
+
+	attemptInput := input
+	for attempt := 1; ; attempt++ {
+		if err := workflow.countAttempt(task); err != nil {
+			return "", err
+		}
+
+		result, err := runner.Run(ctx, attemptInput)
+		retryAttempt := err != nil
+		if task.Retry != nil && task.Retry.RetryIf != nil {
+			retryAttempt = task.Retry.RetryIf(result, err)
+		}
+		if !retryAttempt {
+			return result, err
+		}
+		if attempt >= maxAttempts {
+			if err != nil {
+				return "", err
+			}
+			return "", fmt.Errorf("%w: result rejected after %d attempts", ErrRetriesExhausted, attempt)
+		}
+
+		select {
+		case <-time.After(retryInterval(backoff, attempt)):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+
+		if err != nil {
+			attemptInput = fmt.Sprintf("%s\n\nThe previous attempt failed: %v", input, err)
+		} else {
+			attemptInput = fmt.Sprintf("%s\n\nThe previous result was rejected, try again:\n%s", input, result)
+		}
+	}
+}
+
+// retryInterval returns the interval before the retry following attempt
+func retryInterval(backoff *retry.Policy, attempt int) time.Duration {
+	interval := backoff.InitialInterval
+	for i := 1; i < attempt; i++ {
+		interval = time.Duration(float64(interval) * backoff.BackoffCoefficient)
+		if backoff.MaximumInterval > 0 && interval > backoff.MaximumInterval {
+			return backoff.MaximumInterval
+		}
+	}
+	return interval
+}
@@ -0,0 +1,81 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
+)
+
+func TestExecuteWorkflow_RetryPolicy(t *testing.T) {
+	flaky := &fixedLLM{response: "deployed", err: errors.New("rate limited"), failures: 2}
+	prose := &fixedLLM{response: "Here is the plan"}
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "flaky", flaky)
+	registerAgent(t, registry, "prose", prose)
+	backoff := retry.NewPolicy(retry.WithInitialInterval(time.Millisecond), retry.WithMaxAttempts(3))
+
+	// Failed attempts are retried with backoff
+	workflow := NewWorkflow()
+	workflow.AddTask("deploy", "flaky", "Deploy", nil)
+	workflow.SetRetryPolicy("deploy", RetryPolicy{Backoff: backoff})
+	workflow.SetFinalTask("deploy")
+	result, err := NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow)
+	if err != nil || result != "deployed" {
+		t.Fatalf("Expected the third attempt to succeed, got %q, %v", result, err)
+	}
+	if workflow.Tasks[0].Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", workflow.Tasks[0].Attempts)
+	}
+
+	// A predicate retries results that are not valid, until attempts run out
+	workflow = NewWorkflow()
+	workflow.AddTask("plan", "prose", "Return the plan as JSON", nil)
+	workflow.SetRetryPolicy("plan", RetryPolicy{
+		Backoff: backoff,
+		RetryIf: func(result string, err error) bool {
+			return err != nil || !json.Valid([]byte(result))
+		},
+	})
+	workflow.SetFinalTask("plan")
+	_, err = NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow)
+	if !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("Expected the retries to be exhausted, got %v", err)
+	}
+	if prose.calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", prose.calls.Load())
+	}
+}
+
+func TestExecuteWorkflow_MaxAttempts(t *testing.T) {
+	broken := &fixedLLM{err: errors.New("model unavailable")}
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "broken", broken)
+	store := NewMemoryStateStore()
+	orchestrator := NewCodeOrchestrator(registry).WithStateStore(store)
+
+	newWorkflow := func() *Workflow {
+		workflow := NewWorkflow()
+		workflow.ID = "loop-1"
+		workflow.MaxAttempts = 5
+		workflow.AddTask("step", "broken", "Try", nil)
+		workflow.SetRetryPolicy("step", RetryPolicy{Backoff: retry.NewPolicy(retry.WithInitialInterval(time.Millisecond), retry.WithMaxAttempts(4))})
+		workflow.SetFinalTask("step")
+		return workflow
+	}
+
+	if _, err := orchestrator.ExecuteWorkflow(context.Background(), newWorkflow()); err == nil {
+		t.Fatal("Expected the workflow to fail")
+	}
+	// Resuming counts the attempts made before
+	_, err := orchestrator.ExecuteWorkflow(context.Background(), newWorkflow())
+	if !errors.Is(err, ErrAttemptLimit) {
+		t.Fatalf("Expected the attempt limit, got %v", err)
+	}
+	if broken.calls.Load() != 5 {
+		t.Errorf("Expected 5 attempts in total, got %d", broken.calls.Load())
+	}
+}
@@ -0,0 +1,156 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WorkflowState is a checkpoint of a workflow execution, saved after each
+// task completes
+type WorkflowState struct {
+	// WorkflowID is the ID of the workflow
+	WorkflowID string `json:"workflow_id"`
+
+	// Results maps the IDs of the completed tasks to their results
+	Results map[string]string `json:"results"`
+
+	// Errors maps the IDs of the failed tasks to their errors
+	Errors map[string]string `json:"errors,omitempty"`
+
+	// Attempts maps the IDs of tasks to the number of times their agent ran
+	Attempts map[string]int `json:"attempts,omitempty"`
+
+	// UpdatedAt is when the checkpoint was saved
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WorkflowStateStore persists workflow checkpoints, so that a workflow
+// interrupted by a crash resumes after its completed tasks
+type WorkflowStateStore interface {
+	// SaveState saves the state of a workflow, replacing the previous one
+	SaveState(ctx context.Context, state *WorkflowState) error
+
+	// LoadState returns the state of a workflow, or nil if there is none
+	LoadState(ctx context.Context, workflowID string) (*WorkflowState, error)
+
+	// DeleteState deletes the state of a workflow
+	DeleteState(ctx context.Context, workflowID string) error
+}
+
+// MemoryStateStore is a WorkflowStateStore keeping states in memory, for
+// tests and workflows that only need to resume within one process
+type MemoryStateStore struct {
+	mu     sync.Mutex
+	states map[string]WorkflowState
+}
+
+// NewMemoryStateStore creates an in-memory workflow state store
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{states: make(map[string]WorkflowState)}
+}
+
+// SaveState implements WorkflowStateStore.SaveState
+func (s *MemoryStateStore) SaveState(ctx context.Context, state *WorkflowState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.WorkflowID] = *state.clone()
+	return nil
+}
+
+// LoadState implements WorkflowStateStore.LoadState
+func (s *MemoryStateStore) LoadState(ctx context.Context, workflowID string) (*WorkflowState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[workflowID]
+	if !ok {
+		return nil, nil
+	}
+	return state.clone(), nil
+}
+
+// DeleteState implements WorkflowStateStore.DeleteState
+func (s *MemoryStateStore) DeleteState(ctx context.Context, workflowID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, workflowID)
+	return nil
+}
+
+// clone returns a copy of the state that does not share its maps
+func (s *WorkflowState) clone() *WorkflowState {
+	clone := *s
+	clone.Results = make(map[string]string, len(s.Results))
+	for id, result := range s.Results {
+		clone.Results[id] = result
+	}
+	if s.Errors != nil {
+		clone.Errors = make(map[string]string, len(s.Errors))
+		for id, err := range s.Errors {
+			clone.Errors[id] = err
+		}
+	}
+	if s.Attempts != nil {
+		clone.Attempts = make(map[string]int, len(s.Attempts))
+		for id, attempts := range s.Attempts {
+			clone.Attempts[id] = attempts
+		}
+	}
+	return &clone
+}
+
+// restore marks the tasks completed in a checkpoint as completed, so that
+// they are not run again. The other tasks, including failed ones, run again.
+// Attempts are restored so that MaxAttempts spans resumptions.
+func (w *Workflow) restore(state *WorkflowState) {
+	for _, task := range w.Tasks {
+		task.Attempts = state.Attempts[task.ID]
+		if result, ok := state.Results[task.ID]; ok {
+			task.Status = TaskCompleted
+			task.Result = result
+			w.Results[task.ID] = result
+			continue
+		}
+		task.Status = TaskPending
+		task.Error = nil
+		delete(w.Errors, task.ID)
+	}
+}
+
+// checkpoint saves the state of a workflow. Checkpoints are serialized so
+// that an older state never replaces a newer one.
+func (o *CodeOrchestrator) checkpoint(ctx context.Context, workflow *Workflow) error {
+	workflow.checkpointMu.Lock()
+	defer workflow.checkpointMu.Unlock()
+
+	workflow.mu.Lock()
+	state := &WorkflowState{
+		WorkflowID: workflow.ID,
+		Results:    make(map[string]string, len(workflow.Results)),
+		UpdatedAt:  time.Now(),
+	}
+	for id, result := range workflow.Results {
+		state.Results[id] = result
+	}
+	for id, err := range workflow.Errors {
+		if state.Errors == nil {
+			state.Errors = make(map[string]string)
+		}
+		state.Errors[id] = err.Error()
+	}
+	for _, task := range workflow.Tasks {
+		if task.Attempts > 0 {
+			if state.Attempts == nil {
+				state.Attempts = make(map[string]int)
+			}
+			state.Attempts[task.ID] = task.Attempts
+		}
+	}
+	workflow.mu.Unlock()
+
+	if err := o.stateStore.SaveState(ctx, state); err != nil {
+		return fmt.Errorf("failed to checkpoint workflow %s: %w", workflow.ID, err)
+	}
+	return nil
+}
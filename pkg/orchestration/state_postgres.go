@@ -0,0 +1,109 @@
+package orchestration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// defaultStateTable is the table PostgresStateStore uses unless
+// WithStateTable is set
+const defaultStateTable = "workflow_states"
+
+var stateTablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// PostgresStateStore is a WorkflowStateStore keeping states as JSON in a
+// PostgreSQL table. The database driver (e.g. github.com/lib/pq) is chosen
+// by the caller when opening db.
+type PostgresStateStore struct {
+	db    *sql.DB
+	table string
+}
+
+// PostgresStateStoreOption represents an option for configuring the
+// PostgreSQL state store
+type PostgresStateStoreOption func(*PostgresStateStore)
+
+// WithStateTable sets the table states are stored in (default:
+// workflow_states)
+func WithStateTable(name string) PostgresStateStoreOption {
+	return func(s *PostgresStateStore) {
+		s.table = name
+	}
+}
+
+// NewPostgresStateStore creates a workflow state store on db. Call Migrate
+// to create the table.
+func NewPostgresStateStore(db *sql.DB, options ...PostgresStateStoreOption) (*PostgresStateStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+
+	s := &PostgresStateStore{db: db, table: defaultStateTable}
+	for _, option := range options {
+		option(s)
+	}
+
+	if !stateTablePattern.MatchString(s.table) {
+		return nil, fmt.Errorf("invalid table name %q", s.table)
+	}
+	return s, nil
+}
+
+// Migrate creates the states table if it does not exist
+func (s *PostgresStateStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+		workflow_id TEXT PRIMARY KEY,
+		state TEXT NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to migrate workflow state store: %w", err)
+	}
+	return nil
+}
+
+// SaveState implements WorkflowStateStore.SaveState
+func (s *PostgresStateStore) SaveState(ctx context.Context, state *WorkflowState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow state: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO `+s.table+` (workflow_id, state, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (workflow_id) DO UPDATE SET state = EXCLUDED.state, updated_at = EXCLUDED.updated_at`,
+		state.WorkflowID, string(data), state.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save workflow state: %w", err)
+	}
+	return nil
+}
+
+// LoadState implements WorkflowStateStore.LoadState
+func (s *PostgresStateStore) LoadState(ctx context.Context, workflowID string) (*WorkflowState, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT state FROM `+s.table+` WHERE workflow_id = $1`, workflowID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load workflow state: %w", err)
+	}
+
+	var state WorkflowState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow state: %w", err)
+	}
+	return &state, nil
+}
+
+// DeleteState implements WorkflowStateStore.DeleteState
+func (s *PostgresStateStore) DeleteState(ctx context.Context, workflowID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE workflow_id = $1`, workflowID); err != nil {
+		return fmt.Errorf("failed to delete workflow state: %w", err)
+	}
+	return nil
+}
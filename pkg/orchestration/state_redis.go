@@ -0,0 +1,63 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisStateStore is a WorkflowStateStore keeping each state as JSON in a
+// Redis key
+type RedisStateStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisStateStore creates a workflow state store whose keys start with
+// keyPrefix (default "workflow:")
+func NewRedisStateStore(client *redis.Client, keyPrefix string) *RedisStateStore {
+	if keyPrefix == "" {
+		keyPrefix = "workflow:"
+	}
+	return &RedisStateStore{client: client, keyPrefix: keyPrefix}
+}
+
+// SaveState implements WorkflowStateStore.SaveState
+func (s *RedisStateStore) SaveState(ctx context.Context, state *WorkflowState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode workflow state: %w", err)
+	}
+	if err := s.client.Set(ctx, s.keyPrefix+state.WorkflowID, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save workflow state: %w", err)
+	}
+	return nil
+}
+
+// LoadState implements WorkflowStateStore.LoadState
+func (s *RedisStateStore) LoadState(ctx context.Context, workflowID string) (*WorkflowState, error) {
+	data, err := s.client.Get(ctx, s.keyPrefix+workflowID).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load workflow state: %w", err)
+	}
+
+	var state WorkflowState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow state: %w", err)
+	}
+	return &state, nil
+}
+
+// DeleteState implements WorkflowStateStore.DeleteState
+func (s *RedisStateStore) DeleteState(ctx context.Context, workflowID string) error {
+	if err := s.client.Del(ctx, s.keyPrefix+workflowID).Err(); err != nil {
+		return fmt.Errorf("failed to delete workflow state: %w", err)
+	}
+	return nil
+}
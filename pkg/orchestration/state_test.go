@@ -0,0 +1,109 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func testStateStore(t *testing.T, store WorkflowStateStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	if state, err := store.LoadState(ctx, "wf-1"); err != nil || state != nil {
+		t.Fatalf("LoadState() = %v, %v for an unknown workflow", state, err)
+	}
+
+	state := &WorkflowState{
+		WorkflowID: "wf-1",
+		Results:    map[string]string{"plan": "ok"},
+		Errors:     map[string]string{"apply": "timeout"},
+		UpdatedAt:  time.Now(),
+	}
+	if err := store.SaveState(ctx, state); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	state.Results["plan"] = "changed"
+
+	loaded, err := store.LoadState(ctx, "wf-1")
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if loaded.Results["plan"] != "ok" || loaded.Errors["apply"] != "timeout" {
+		t.Errorf("Unexpected state %+v", loaded)
+	}
+
+	if err := store.DeleteState(ctx, "wf-1"); err != nil {
+		t.Fatalf("DeleteState() error = %v", err)
+	}
+	if state, err := store.LoadState(ctx, "wf-1"); err != nil || state != nil {
+		t.Errorf("LoadState() = %v, %v after delete", state, err)
+	}
+}
+
+func TestMemoryStateStore(t *testing.T) {
+	testStateStore(t, NewMemoryStateStore())
+}
+
+func TestRedisStateStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	testStateStore(t, NewRedisStateStore(client, ""))
+	if err := NewRedisStateStore(client, "").SaveState(context.Background(), &WorkflowState{WorkflowID: "wf-2"}); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if !mr.Exists("workflow:wf-2") {
+		t.Error("Expected the state under the default key prefix")
+	}
+}
+
+func TestExecuteWorkflow_ResumesFromCheckpoint(t *testing.T) {
+	plan := &fixedLLM{response: "plan ready"}
+	apply := &fixedLLM{response: "applied", err: errors.New("connection reset"), failures: 1}
+	verify := &fixedLLM{response: "verified"}
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "planner", plan)
+	registerAgent(t, registry, "applier", apply)
+	registerAgent(t, registry, "verifier", verify)
+
+	store := NewMemoryStateStore()
+	orchestrator := NewCodeOrchestrator(registry).WithStateStore(store)
+	newWorkflow := func() *Workflow {
+		workflow := NewWorkflow()
+		workflow.ID = "deploy-1"
+		workflow.AddTask("plan", "planner", "Plan the changes", nil)
+		workflow.AddTask("apply", "applier", "Apply the plan", []string{"plan"})
+		workflow.AddTask("verify", "verifier", "Verify the deployment", []string{"apply"})
+		workflow.SetFinalTask("verify")
+		return workflow
+	}
+
+	if _, err := orchestrator.ExecuteWorkflow(context.Background(), newWorkflow()); err == nil {
+		t.Fatal("Expected the first execution to fail")
+	}
+	state, err := store.LoadState(context.Background(), "deploy-1")
+	if err != nil || state == nil {
+		t.Fatalf("Expected a checkpoint, got %v, %v", state, err)
+	}
+	if len(state.Results) != 1 || state.Results["plan"] != "plan ready" || state.Errors["apply"] == "" {
+		t.Errorf("Unexpected checkpoint %+v", state)
+	}
+
+	// Executing the workflow again resumes after the plan
+	result, err := orchestrator.ExecuteWorkflow(context.Background(), newWorkflow())
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if result != "verified" {
+		t.Errorf("Expected the final result, got %q", result)
+	}
+	if plan.calls.Load() != 1 || apply.calls.Load() != 2 || verify.calls.Load() != 1 {
+		t.Errorf("Expected only the failed tasks to run again, got %d, %d and %d calls", plan.calls.Load(), apply.calls.Load(), verify.calls.Load())
+	}
+}
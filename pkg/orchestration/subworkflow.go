@@ -0,0 +1,114 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+)
+
+// SubWorkflowConfig configures a task running another workflow
+type SubWorkflowConfig struct {
+	// Workflow is the workflow the task runs. Each run executes a copy of
+	// its tasks, so one workflow can be reused by several tasks.
+	Workflow *Workflow
+
+	// Inputs maps tasks of the sub-workflow to the dependencies of the task
+	// whose results they receive. By default the tasks of the sub-workflow
+	// without dependencies receive the results of all the dependencies.
+	Inputs map[string][]string
+
+	// Output is the task of the sub-workflow whose result is the result of
+	// the task, the final task of the sub-workflow by default
+	Output string
+}
+
+// AddSubWorkflow adds a task running another workflow with the results of
+// its dependencies, for reusable pipeline fragments
+func (w *Workflow) AddSubWorkflow(id string, dependencies []string, config SubWorkflowConfig) {
+	w.Tasks = append(w.Tasks, &Task{
+		ID:           id,
+		Dependencies: dependencies,
+		Status:       TaskPending,
+		SubWorkflow:  &config,
+	})
+}
+
+// runSubWorkflow runs a copy of the sub-workflow of a task. With a workflow
+// ID, the copy is checkpointed as <workflow ID>/<task ID>.
+func (o *CodeOrchestrator) runSubWorkflow(ctx context.Context, task *Task, workflow *Workflow) (string, error) {
+	config := task.SubWorkflow
+	if config.Workflow == nil {
+		return "", fmt.Errorf("sub-workflow task %s has no workflow", task.ID)
+	}
+
+	workflow.mu.Lock()
+	results := make(map[string]string, len(task.Dependencies))
+	for _, depID := range task.Dependencies {
+		if _, failed := workflow.Errors[depID]; failed {
+			workflow.mu.Unlock()
+			return "", fmt.Errorf("%w: %s", ErrDependencyFailed, depID)
+		}
+		results[depID] = workflow.Results[depID]
+	}
+	workflow.mu.Unlock()
+
+	sub := config.Workflow.copy()
+	if workflow.ID != "" {
+		sub.ID = workflow.ID + "/" + task.ID
+	}
+	if config.Output != "" {
+		sub.FinalTaskID = config.Output
+	}
+	if sub.FinalTaskID == "" {
+		return "", fmt.Errorf("sub-workflow task %s has no output task", task.ID)
+	}
+
+	for _, subTask := range sub.Tasks {
+		inputs, ok := config.Inputs[subTask.ID]
+		if !ok {
+			if config.Inputs != nil || len(subTask.Dependencies) > 0 {
+				continue
+			}
+			inputs = task.Dependencies
+		}
+		for _, depID := range inputs {
+			result, ok := results[depID]
+			if !ok {
+				return "", fmt.Errorf("sub-workflow input %s of task %s is not a dependency", depID, subTask.ID)
+			}
+			subTask.Input = fmt.Sprintf("%s\n\nResult from %s: %s", subTask.Input, depID, result)
+		}
+	}
+
+	result, err := o.ExecuteWorkflow(ctx, sub)
+	if err != nil {
+		return "", fmt.Errorf("sub-workflow failed: %w", err)
+	}
+	return result, nil
+}
+
+// copy returns a copy of the workflow with its tasks pending and no ID. The
+// copy shares the scratchpad of the workflow it runs in.
+func (w *Workflow) copy() *Workflow {
+	c := &Workflow{
+		MaxAttempts: w.MaxAttempts,
+		FinalTaskID: w.FinalTaskID,
+		Tasks:       make([]*Task, 0, len(w.Tasks)),
+		Results:     make(map[string]string),
+		Errors:      make(map[string]error),
+	}
+	for _, task := range w.Tasks {
+		c.Tasks = append(c.Tasks, &Task{
+			ID:           task.ID,
+			AgentID:      task.AgentID,
+			Input:        task.Input,
+			Dependencies: append([]string(nil), task.Dependencies...),
+			Status:       TaskPending,
+			Join:         task.Join,
+			Approval:     task.Approval,
+			Map:          task.Map,
+			SubWorkflow:  task.SubWorkflow,
+			Retry:        task.Retry,
+		})
+	}
+	return c
+}
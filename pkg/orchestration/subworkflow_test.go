@@ -0,0 +1,80 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// newReviewWorkflow returns a reusable fragment reviewing and fixing a draft
+func newReviewWorkflow() *Workflow {
+	review := NewWorkflow()
+	review.AddTask("review", "reviewer", "Review the draft", nil)
+	review.AddTask("fix", "fixer", "Fix the issues", []string{"review"})
+	review.SetFinalTask("fix")
+	return review
+}
+
+func TestExecuteWorkflow_SubWorkflow(t *testing.T) {
+	reviewer := &fixedLLM{response: "looks good"}
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "writer", &fixedLLM{response: "draft"})
+	registerAgent(t, registry, "reviewer", reviewer)
+	registerAgent(t, registry, "fixer", &fixedLLM{response: "fixed"})
+	store := NewMemoryStateStore()
+
+	review := newReviewWorkflow()
+	workflow := NewWorkflow()
+	workflow.ID = "docs-1"
+	workflow.AddTask("api", "writer", "Write the API docs", nil)
+	workflow.AddTask("guide", "writer", "Write the guide", nil)
+	workflow.AddSubWorkflow("review_api", []string{"api"}, SubWorkflowConfig{Workflow: review})
+	workflow.AddSubWorkflow("review_guide", []string{"guide"}, SubWorkflowConfig{Workflow: review, Output: "review"})
+	workflow.AddJoin("done", []string{"review_api", "review_guide"}, JoinConfig{Separator: ", "})
+	workflow.SetFinalTask("done")
+
+	result, err := NewCodeOrchestrator(registry).WithStateStore(store).ExecuteWorkflow(context.Background(), workflow)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if result != "fixed, looks good" {
+		t.Errorf("Expected the outputs of the sub-workflows, got %q", result)
+	}
+	if reviewer.calls.Load() != 2 {
+		t.Errorf("Expected the fragment to run twice, got %d reviews", reviewer.calls.Load())
+	}
+	if review.Tasks[0].Status != TaskPending || len(review.Results) != 0 {
+		t.Error("Expected the fragment itself not to run")
+	}
+	if state, _ := store.LoadState(context.Background(), "docs-1/review_api"); state == nil || state.Results["fix"] != "fixed" {
+		t.Errorf("Expected the sub-workflow to be checkpointed, got %+v", state)
+	}
+}
+
+func TestExecuteWorkflow_SubWorkflowInputs(t *testing.T) {
+	registry := NewAgentRegistry()
+	registerAgent(t, registry, "writer", &fixedLLM{response: "draft"})
+	registerAgent(t, registry, "reviewer", &fixedLLM{err: errors.New("model unavailable")})
+	registerAgent(t, registry, "fixer", &fixedLLM{response: "fixed"})
+
+	workflow := NewWorkflow()
+	workflow.AddTask("api", "writer", "Write the API docs", nil)
+	workflow.AddSubWorkflow("check", []string{"api"}, SubWorkflowConfig{
+		Workflow: newReviewWorkflow(),
+		Inputs:   map[string][]string{"review": {"missing"}},
+	})
+	workflow.SetFinalTask("check")
+	if _, err := NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow); err == nil {
+		t.Error("Expected an error for an input that is not a dependency")
+	}
+
+	// A failing sub-workflow fails the task
+	workflow = NewWorkflow()
+	workflow.AddTask("api", "writer", "Write the API docs", nil)
+	workflow.AddSubWorkflow("check", []string{"api"}, SubWorkflowConfig{Workflow: newReviewWorkflow()})
+	workflow.SetFinalTask("check")
+	_, err := NewCodeOrchestrator(registry).ExecuteWorkflow(context.Background(), workflow)
+	if !errors.Is(err, ErrDependencyFailed) {
+		t.Errorf("Expected the sub-workflow error, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryQueue is a Queue held in memory, for workers in the same process
+type MemoryQueue struct {
+	mu       sync.Mutex
+	ready    map[Priority][]Job
+	delayed  []delayedJob
+	statuses map[string]*Status
+	closed   bool
+
+	// changed is closed and replaced when a job is added or the queue closes
+	changed chan struct{}
+}
+
+// delayedJob is a requeued job waiting for its delay
+type delayedJob struct {
+	job     Job
+	readyAt time.Time
+}
+
+// NewMemoryQueue creates an in-memory queue
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		ready:    make(map[Priority][]Job),
+		statuses: make(map[string]*Status),
+		changed:  make(chan struct{}),
+	}
+}
+
+// Enqueue implements Queue.Enqueue
+func (q *MemoryQueue) Enqueue(ctx context.Context, job Job) (string, error) {
+	job, err := prepare(job)
+	if err != nil {
+		return "", err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return "", ErrClosed
+	}
+	q.ready[job.Priority] = append(q.ready[job.Priority], job)
+	q.statuses[job.ID] = queuedStatus(job)
+	q.notify()
+	return job.ID, nil
+}
+
+// Dequeue implements Queue.Dequeue
+func (q *MemoryQueue) Dequeue(ctx context.Context) (*Job, error) {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return nil, ErrClosed
+		}
+		job, wait := q.next()
+		changed := q.changed
+		q.mu.Unlock()
+		if job != nil {
+			return job, nil
+		}
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			fire = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-changed:
+		case <-fire:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// next leases the next ready job, or returns how long until a delayed job
+// is ready (zero when there is none)
+func (q *MemoryQueue) next() (*Job, time.Duration) {
+	now := time.Now()
+	var wait time.Duration
+	remaining := q.delayed[:0]
+	for _, delayed := range q.delayed {
+		if !delayed.readyAt.After(now) {
+			q.ready[delayed.job.Priority] = append(q.ready[delayed.job.Priority], delayed.job)
+			continue
+		}
+		if until := delayed.readyAt.Sub(now); wait == 0 || until < wait {
+			wait = until
+		}
+		remaining = append(remaining, delayed)
+	}
+	q.delayed = remaining
+
+	for _, priority := range priorities {
+		jobs := q.ready[priority]
+		if len(jobs) == 0 {
+			continue
+		}
+		job := jobs[0]
+		q.ready[priority] = jobs[1:]
+
+		job.Attempt++
+		if status, ok := q.statuses[job.ID]; ok {
+			status.State = StateRunning
+			status.Attempts = job.Attempt
+			status.StartedAt = now.UTC()
+		}
+		return &job, 0
+	}
+	return nil, wait
+}
+
+// Finish implements Queue.Finish
+func (q *MemoryQueue) Finish(ctx context.Context, job *Job, output string, runErr error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	status, ok := q.statuses[job.ID]
+	if !ok {
+		return ErrJobNotFound
+	}
+	status.finish(output, runErr)
+	return nil
+}
+
+// Requeue implements Queue.Requeue
+func (q *MemoryQueue) Requeue(ctx context.Context, job *Job, delay time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	if status, ok := q.statuses[job.ID]; ok {
+		status.State = StateQueued
+	}
+	q.delayed = append(q.delayed, delayedJob{job: *job, readyAt: time.Now().Add(delay)})
+	q.notify()
+	return nil
+}
+
+// Status implements Queue.Status
+func (q *MemoryQueue) Status(ctx context.Context, id string) (*Status, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	status, ok := q.statuses[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	copied := *status
+	return &copied, nil
+}
+
+// Close implements Queue.Close
+func (q *MemoryQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		q.notify()
+	}
+	return nil
+}
+
+// notify wakes up the waiting Dequeue calls
+func (q *MemoryQueue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
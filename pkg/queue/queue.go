@@ -0,0 +1,159 @@
+// Package queue runs agent jobs from a queue with pools of workers, so that
+// services accepting requests do not wait for the LLM and workers can be
+// scaled separately.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrClosed is returned by the operations of a closed queue
+	ErrClosed = errors.New("queue is closed")
+
+	// ErrJobNotFound is returned by Status for an unknown job
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// Priority orders the jobs of a queue: jobs of a higher priority are
+// dequeued first, jobs of the same priority in the order they were enqueued
+type Priority int
+
+// Priorities of jobs
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+// priorities lists the priorities from the highest
+var priorities = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// valid reports whether p is a known priority
+func (p Priority) valid() bool {
+	return p >= PriorityLow && p <= PriorityHigh
+}
+
+// State is the state of a job
+type State string
+
+// Job states
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+)
+
+// Job is an agent run waiting in a queue
+type Job struct {
+	ID string `json:"id"`
+
+	// Agent names the agent to run, for workers serving several agents
+	Agent string `json:"agent,omitempty"`
+
+	Input          string            `json:"input"`
+	OrgID          string            `json:"org_id,omitempty"`
+	ConversationID string            `json:"conversation_id,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	Priority       Priority          `json:"priority,omitempty"`
+
+	// MaxAttempts is how many times the job runs before it fails, 1 when
+	// zero
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// Attempt is the number of the current run, starting at 1
+	Attempt int `json:"attempt"`
+
+	EnqueuedAt time.Time `json:"enqueued_at"`
+}
+
+// Status reports the state and result of a job
+type Status struct {
+	ID         string    `json:"id"`
+	Agent      string    `json:"agent,omitempty"`
+	OrgID      string    `json:"org_id,omitempty"`
+	State      State     `json:"state"`
+	Priority   Priority  `json:"priority"`
+	Attempts   int       `json:"attempts"`
+	Output     string    `json:"output,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+}
+
+// Queue holds jobs until workers run them and keeps their status. Other
+// brokers, such as Amazon SQS, can be used by implementing it.
+type Queue interface {
+	// Enqueue adds a job and returns its ID, generated when empty
+	Enqueue(ctx context.Context, job Job) (string, error)
+
+	// Dequeue waits for the next job and leases it to the caller, who must
+	// Finish or Requeue it. It returns the context error when ctx is done.
+	Dequeue(ctx context.Context) (*Job, error)
+
+	// Finish ends a leased job with its output, or its error when runErr is
+	// not nil
+	Finish(ctx context.Context, job *Job, output string, runErr error) error
+
+	// Requeue returns a leased job to the queue to run again after delay
+	Requeue(ctx context.Context, job *Job, delay time.Duration) error
+
+	// Status returns the status of a job, or ErrJobNotFound
+	Status(ctx context.Context, id string) (*Status, error)
+
+	// Close releases the queue's resources. Dequeue calls in progress return
+	// ErrClosed.
+	Close() error
+}
+
+// prepare validates a job and sets its defaults
+func prepare(job Job) (Job, error) {
+	if job.Input == "" {
+		return job, fmt.Errorf("job input is required")
+	}
+	if !job.Priority.valid() {
+		return job, fmt.Errorf("invalid job priority %d", job.Priority)
+	}
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = 1
+	}
+	job.Attempt = 0
+	job.EnqueuedAt = time.Now().UTC()
+	return job, nil
+}
+
+// queuedStatus returns the status of a job that was just enqueued
+func queuedStatus(job Job) *Status {
+	return &Status{
+		ID:         job.ID,
+		Agent:      job.Agent,
+		OrgID:      job.OrgID,
+		State:      StateQueued,
+		Priority:   job.Priority,
+		EnqueuedAt: job.EnqueuedAt,
+	}
+}
+
+// finish updates the status of a job that ended
+func (s *Status) finish(output string, runErr error) {
+	s.FinishedAt = time.Now().UTC()
+	if runErr != nil {
+		s.State = StateFailed
+		s.Error = runErr.Error()
+		s.Output = ""
+		return
+	}
+	s.State = StateSucceeded
+	s.Output = output
+	s.Error = ""
+}
@@ -0,0 +1,222 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// queues returns the queue implementations to test
+func queues(t *testing.T, opts ...RedisOption) map[string]Queue {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return map[string]Queue{
+		"memory": NewMemoryQueue(),
+		"redis":  NewRedisQueue(client, "", opts...),
+	}
+}
+
+func dequeue(t *testing.T, q Queue) *Job {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err := q.Dequeue(ctx)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	return job
+}
+
+func TestQueuePriorities(t *testing.T) {
+	for name, q := range queues(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for _, job := range []Job{
+				{ID: "low", Input: "a", Priority: PriorityLow},
+				{ID: "normal-1", Input: "b", Priority: PriorityNormal},
+				{ID: "high", Input: "c", Priority: PriorityHigh},
+				{ID: "normal-2", Input: "d", Priority: PriorityNormal},
+			} {
+				if _, err := q.Enqueue(ctx, job); err != nil {
+					t.Fatalf("Enqueue: %v", err)
+				}
+			}
+
+			var order []string
+			for i := 0; i < 4; i++ {
+				job := dequeue(t, q)
+				if job.Attempt != 1 {
+					t.Errorf("expected attempt 1, got %d", job.Attempt)
+				}
+				order = append(order, job.ID)
+			}
+			want := []string{"high", "normal-1", "normal-2", "low"}
+			for i := range want {
+				if order[i] != want[i] {
+					t.Fatalf("expected order %v, got %v", want, order)
+				}
+			}
+		})
+	}
+}
+
+func TestQueueStatus(t *testing.T) {
+	for name, q := range queues(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			id, err := q.Enqueue(ctx, Job{Agent: "writer", Input: "hello", MaxAttempts: 2})
+			if err != nil {
+				t.Fatalf("Enqueue: %v", err)
+			}
+			if status, err := q.Status(ctx, id); err != nil || status.State != StateQueued || status.Agent != "writer" {
+				t.Fatalf("unexpected status %+v, %v", status, err)
+			}
+
+			job := dequeue(t, q)
+			if job.ID != id || job.MaxAttempts != 2 {
+				t.Fatalf("unexpected job %+v", job)
+			}
+			if status, _ := q.Status(ctx, id); status.State != StateRunning || status.Attempts != 1 {
+				t.Fatalf("unexpected status %+v", status)
+			}
+
+			if err := q.Requeue(ctx, job, 0); err != nil {
+				t.Fatalf("Requeue: %v", err)
+			}
+			job = dequeue(t, q)
+			if job.Attempt != 2 {
+				t.Fatalf("expected attempt 2, got %d", job.Attempt)
+			}
+
+			if err := q.Finish(ctx, job, "", errors.New("boom")); err != nil {
+				t.Fatalf("Finish: %v", err)
+			}
+			status, err := q.Status(ctx, id)
+			if err != nil || status.State != StateFailed || status.Error != "boom" || status.Attempts != 2 {
+				t.Fatalf("unexpected status %+v, %v", status, err)
+			}
+
+			if _, err := q.Status(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+				t.Errorf("expected ErrJobNotFound, got %v", err)
+			}
+			if _, err := q.Enqueue(ctx, Job{}); err == nil {
+				t.Error("expected an error for a job without input")
+			}
+			if _, err := q.Enqueue(ctx, Job{Input: "x", Priority: 5}); err == nil {
+				t.Error("expected an error for an invalid priority")
+			}
+		})
+	}
+}
+
+func TestQueueDequeueWaits(t *testing.T) {
+	for name, q := range queues(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			if _, err := q.Dequeue(ctx); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected the context error, got %v", err)
+			}
+
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				_, _ = q.Enqueue(context.Background(), Job{ID: "late", Input: "x"})
+			}()
+			if job := dequeue(t, q); job.ID != "late" {
+				t.Errorf("unexpected job %s", job.ID)
+			}
+
+			if err := q.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			if _, err := q.Dequeue(context.Background()); !errors.Is(err, ErrClosed) {
+				t.Errorf("expected ErrClosed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRedisQueueExpiredLease(t *testing.T) {
+	q := queues(t, WithVisibilityTimeout(10*time.Millisecond))["redis"]
+	ctx := context.Background()
+	id, err := q.Enqueue(ctx, Job{Input: "x"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// The first worker dies without finishing the job
+	dequeue(t, q)
+	time.Sleep(20 * time.Millisecond)
+
+	job := dequeue(t, q)
+	if job.ID != id || job.Attempt != 2 {
+		t.Fatalf("expected the job again at attempt 2, got %+v", job)
+	}
+}
+
+// flakyRunner fails a number of runs before succeeding
+type flakyRunner struct {
+	mu       sync.Mutex
+	failures int
+	inputs   []string
+}
+
+func (r *flakyRunner) Run(ctx context.Context, input string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inputs = append(r.inputs, input)
+	if r.failures > 0 {
+		r.failures--
+		return "", errors.New("rate limited")
+	}
+	return "done: " + input, nil
+}
+
+func waitForState(t *testing.T, q Queue, id string, state State) *Status {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, err := q.Status(context.Background(), id)
+		if err == nil && status.State == state {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s did not reach %s: %+v, %v", id, state, status, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWorkerPool(t *testing.T) {
+	for name, q := range queues(t) {
+		t.Run(name, func(t *testing.T) {
+			writer := &flakyRunner{failures: 1}
+			pool := NewWorkerPool(q, AgentHandler(map[string]Runner{"writer": writer}),
+				WithWorkers(2), WithRetryDelay(time.Millisecond))
+			if err := pool.Start(context.Background()); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			defer pool.Stop()
+
+			ctx := context.Background()
+			retried, _ := q.Enqueue(ctx, Job{Agent: "writer", Input: "post", MaxAttempts: 2})
+			failed, _ := q.Enqueue(ctx, Job{Agent: "reviewer", Input: "review"})
+
+			status := waitForState(t, q, retried, StateSucceeded)
+			if status.Output != "done: post" || status.Attempts != 2 {
+				t.Errorf("unexpected status %+v", status)
+			}
+			status = waitForState(t, q, failed, StateFailed)
+			if status.Error != `no agent named "reviewer"` {
+				t.Errorf("unexpected error %q", status.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisPollInterval bounds how long Dequeue blocks in Redis before checking
+// for delayed jobs, expired leases and Close
+const redisPollInterval = time.Second
+
+// RedisQueue is a Queue stored in Redis, shared by the services enqueuing
+// jobs and the workers of any number of processes. A job whose worker dies
+// is run again once its lease expires.
+type RedisQueue struct {
+	client            *redis.Client
+	keyPrefix         string
+	visibilityTimeout time.Duration
+	statusTTL         time.Duration
+	closed            atomic.Bool
+}
+
+// RedisOption configures a RedisQueue
+type RedisOption func(*RedisQueue)
+
+// WithVisibilityTimeout sets how long a job is leased to a worker before it
+// is considered lost and run again, 30 minutes by default. It must exceed
+// the longest run.
+func WithVisibilityTimeout(timeout time.Duration) RedisOption {
+	return func(q *RedisQueue) {
+		q.visibilityTimeout = timeout
+	}
+}
+
+// WithStatusTTL sets how long job statuses are kept, 24 hours by default
+func WithStatusTTL(ttl time.Duration) RedisOption {
+	return func(q *RedisQueue) {
+		q.statusTTL = ttl
+	}
+}
+
+// NewRedisQueue creates a queue whose Redis keys start with keyPrefix
+// (default "queue:")
+func NewRedisQueue(client *redis.Client, keyPrefix string, opts ...RedisOption) *RedisQueue {
+	if keyPrefix == "" {
+		keyPrefix = "queue:"
+	}
+	q := &RedisQueue{
+		client:            client,
+		keyPrefix:         keyPrefix,
+		visibilityTimeout: 30 * time.Minute,
+		statusTTL:         24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// readyKey is the list of the jobs of a priority ready to run
+func (q *RedisQueue) readyKey(priority Priority) string {
+	return q.keyPrefix + "ready:" + strconv.Itoa(int(priority))
+}
+
+// delayedKey is the sorted set of requeued jobs by the time they are ready
+func (q *RedisQueue) delayedKey() string {
+	return q.keyPrefix + "delayed"
+}
+
+// leasesKey is the sorted set of leased job IDs by lease expiry, and
+// leasedKey the hash of the leased jobs
+func (q *RedisQueue) leasesKey() string {
+	return q.keyPrefix + "leases"
+}
+
+func (q *RedisQueue) leasedKey() string {
+	return q.keyPrefix + "leased"
+}
+
+func (q *RedisQueue) statusKey(id string) string {
+	return q.keyPrefix + "status:" + id
+}
+
+// Enqueue implements Queue.Enqueue
+func (q *RedisQueue) Enqueue(ctx context.Context, job Job) (string, error) {
+	if q.closed.Load() {
+		return "", ErrClosed
+	}
+	job, err := prepare(job)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode job: %w", err)
+	}
+	status, err := json.Marshal(queuedStatus(job))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode job status: %w", err)
+	}
+
+	pipe := q.client.TxPipeline()
+	pipe.Set(ctx, q.statusKey(job.ID), status, q.statusTTL)
+	pipe.LPush(ctx, q.readyKey(job.Priority), data)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return job.ID, nil
+}
+
+// Dequeue implements Queue.Dequeue
+func (q *RedisQueue) Dequeue(ctx context.Context) (*Job, error) {
+	keys := make([]string, len(priorities))
+	for i, priority := range priorities {
+		keys[i] = q.readyKey(priority)
+	}
+
+	for {
+		if q.closed.Load() {
+			return nil, ErrClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := q.promote(ctx); err != nil {
+			return nil, err
+		}
+
+		// BRPOP takes from the first non-empty list, the highest priority
+		result, err := q.client.BRPop(ctx, redisPollInterval, keys...).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to dequeue job: %w", err)
+		}
+
+		var job Job
+		if err := json.Unmarshal([]byte(result[1]), &job); err != nil {
+			return nil, fmt.Errorf("failed to decode job: %w", err)
+		}
+		if err := q.lease(ctx, &job); err != nil {
+			return nil, err
+		}
+		return &job, nil
+	}
+}
+
+// lease records a dequeued job as running until its lease expires
+func (q *RedisQueue) lease(ctx context.Context, job *Job) error {
+	job.Attempt++
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+	expiry := time.Now().Add(q.visibilityTimeout)
+
+	pipe := q.client.TxPipeline()
+	pipe.HSet(ctx, q.leasedKey(), job.ID, data)
+	pipe.ZAdd(ctx, q.leasesKey(), &redis.Z{Score: float64(expiry.UnixMilli()), Member: job.ID})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to lease job: %w", err)
+	}
+
+	return q.updateStatus(ctx, job, func(status *Status) {
+		status.State = StateRunning
+		status.Attempts = job.Attempt
+		status.StartedAt = time.Now().UTC()
+	})
+}
+
+// promote moves the delayed jobs that are ready and the jobs of expired
+// leases back to the ready lists. ZREM decides which worker moves a job.
+func (q *RedisQueue) promote(ctx context.Context) error {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	due := &redis.ZRangeBy{Min: "-inf", Max: now}
+
+	delayed, err := q.client.ZRangeByScore(ctx, q.delayedKey(), due).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read delayed jobs: %w", err)
+	}
+	for _, data := range delayed {
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			return fmt.Errorf("failed to decode job: %w", err)
+		}
+		if removed, err := q.client.ZRem(ctx, q.delayedKey(), data).Result(); err != nil || removed == 0 {
+			continue
+		}
+		if err := q.client.LPush(ctx, q.readyKey(job.Priority), data).Err(); err != nil {
+			return fmt.Errorf("failed to requeue job: %w", err)
+		}
+	}
+
+	expired, err := q.client.ZRangeByScore(ctx, q.leasesKey(), due).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read leases: %w", err)
+	}
+	for _, id := range expired {
+		if removed, err := q.client.ZRem(ctx, q.leasesKey(), id).Result(); err != nil || removed == 0 {
+			continue
+		}
+		data, err := q.client.HGet(ctx, q.leasedKey(), id).Result()
+		if err != nil {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			return fmt.Errorf("failed to decode job: %w", err)
+		}
+		// The lost job runs next within its priority
+		pipe := q.client.TxPipeline()
+		pipe.HDel(ctx, q.leasedKey(), id)
+		pipe.RPush(ctx, q.readyKey(job.Priority), data)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to requeue job: %w", err)
+		}
+	}
+	return nil
+}
+
+// release removes the lease of a job
+func (q *RedisQueue) release(ctx context.Context, pipe redis.Pipeliner, job *Job) {
+	pipe.ZRem(ctx, q.leasesKey(), job.ID)
+	pipe.HDel(ctx, q.leasedKey(), job.ID)
+}
+
+// Finish implements Queue.Finish
+func (q *RedisQueue) Finish(ctx context.Context, job *Job, output string, runErr error) error {
+	pipe := q.client.TxPipeline()
+	q.release(ctx, pipe, job)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to release job: %w", err)
+	}
+	return q.updateStatus(ctx, job, func(status *Status) {
+		status.finish(output, runErr)
+	})
+}
+
+// Requeue implements Queue.Requeue
+func (q *RedisQueue) Requeue(ctx context.Context, job *Job, delay time.Duration) error {
+	if q.closed.Load() {
+		return ErrClosed
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	pipe := q.client.TxPipeline()
+	q.release(ctx, pipe, job)
+	readyAt := time.Now().Add(delay).UnixMilli()
+	pipe.ZAdd(ctx, q.delayedKey(), &redis.Z{Score: float64(readyAt), Member: data})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	return q.updateStatus(ctx, job, func(status *Status) {
+		status.State = StateQueued
+	})
+}
+
+// Status implements Queue.Status
+func (q *RedisQueue) Status(ctx context.Context, id string) (*Status, error) {
+	data, err := q.client.Get(ctx, q.statusKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job status: %w", err)
+	}
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to decode job status: %w", err)
+	}
+	return &status, nil
+}
+
+// updateStatus applies update to the status of a job. Only the worker
+// holding the job updates it.
+func (q *RedisQueue) updateStatus(ctx context.Context, job *Job, update func(*Status)) error {
+	status, err := q.Status(ctx, job.ID)
+	if errors.Is(err, ErrJobNotFound) {
+		// The status expired; record it again
+		status = queuedStatus(*job)
+	} else if err != nil {
+		return err
+	}
+	update(status)
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to encode job status: %w", err)
+	}
+	if err := q.client.Set(ctx, q.statusKey(job.ID), data, q.statusTTL).Err(); err != nil {
+		return fmt.Errorf("failed to save job status: %w", err)
+	}
+	return nil
+}
+
+// Close implements Queue.Close. The Redis client is not closed.
+func (q *RedisQueue) Close() error {
+	q.closed.Store(true)
+	return nil
+}
@@ -0,0 +1,209 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// Handler runs a job and returns its output
+type Handler func(ctx context.Context, job *Job) (string, error)
+
+// Runner runs an agent on an input, such as an *agent.Agent
+type Runner interface {
+	Run(ctx context.Context, input string) (string, error)
+}
+
+// AgentHandler returns a handler running the agent named by each job. A job
+// without an agent name runs the only agent of agents. The job's organization
+// and conversation are set in the run context.
+func AgentHandler(agents map[string]Runner) Handler {
+	return func(ctx context.Context, job *Job) (string, error) {
+		runner, ok := agents[job.Agent]
+		if !ok && job.Agent == "" && len(agents) == 1 {
+			for _, only := range agents {
+				runner, ok = only, true
+			}
+		}
+		if !ok {
+			return "", fmt.Errorf("no agent named %q", job.Agent)
+		}
+
+		if job.OrgID != "" {
+			ctx = multitenancy.WithOrgID(ctx, job.OrgID)
+		}
+		if job.ConversationID != "" {
+			ctx = memory.WithConversationID(ctx, job.ConversationID)
+		}
+		return runner.Run(ctx, job.Input)
+	}
+}
+
+// WorkerPool runs the jobs of a queue with a number of concurrent workers.
+// Pools in several processes can share a queue to scale horizontally.
+type WorkerPool struct {
+	queue      Queue
+	handler    Handler
+	workers    int
+	jobTimeout time.Duration
+	retryDelay time.Duration
+	logger     logging.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// WorkerOption configures a WorkerPool
+type WorkerOption func(*WorkerPool)
+
+// WithWorkers sets the number of jobs run concurrently, 4 by default
+func WithWorkers(workers int) WorkerOption {
+	return func(p *WorkerPool) {
+		p.workers = workers
+	}
+}
+
+// WithJobTimeout bounds each run of a job; zero means no limit
+func WithJobTimeout(timeout time.Duration) WorkerOption {
+	return func(p *WorkerPool) {
+		p.jobTimeout = timeout
+	}
+}
+
+// WithRetryDelay sets the delay before the first retry of a failed job,
+// doubled for each further retry, 1 second by default
+func WithRetryDelay(delay time.Duration) WorkerOption {
+	return func(p *WorkerPool) {
+		p.retryDelay = delay
+	}
+}
+
+// WithLogger sets the logger of the pool
+func WithLogger(logger logging.Logger) WorkerOption {
+	return func(p *WorkerPool) {
+		p.logger = logger
+	}
+}
+
+// NewWorkerPool creates a pool running the jobs of q with handler
+func NewWorkerPool(q Queue, handler Handler, opts ...WorkerOption) *WorkerPool {
+	p := &WorkerPool{
+		queue:      q,
+		handler:    handler,
+		workers:    4,
+		retryDelay: time.Second,
+		logger:     logging.New(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.workers < 1 {
+		p.workers = 1
+	}
+	return p
+}
+
+// Start starts the workers in the background
+func (p *WorkerPool) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cancel != nil {
+		return fmt.Errorf("worker pool is already running")
+	}
+
+	// Stopping the pool stops taking jobs, the running jobs finish
+	jobCtx := context.WithoutCancel(ctx)
+	ctx, p.cancel = context.WithCancel(ctx)
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.work(ctx, jobCtx)
+	}
+	return nil
+}
+
+// Stop stops taking jobs and waits for the running ones
+func (p *WorkerPool) Stop() {
+	p.mu.Lock()
+	cancel := p.cancel
+	p.cancel = nil
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	p.wg.Wait()
+}
+
+// work runs jobs until ctx is done or the queue is closed
+func (p *WorkerPool) work(ctx, jobCtx context.Context) {
+	defer p.wg.Done()
+
+	for {
+		job, err := p.queue.Dequeue(ctx)
+		if ctx.Err() != nil || errors.Is(err, ErrClosed) {
+			return
+		}
+		if err != nil {
+			p.logger.Error(ctx, "Failed to dequeue job", map[string]interface{}{
+				"error": err.Error(),
+			})
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.retryDelay):
+			}
+			continue
+		}
+		p.run(jobCtx, job)
+	}
+}
+
+// run runs a job and finishes it, or requeues it when it failed with
+// attempts left
+func (p *WorkerPool) run(ctx context.Context, job *Job) {
+	runCtx := ctx
+	if p.jobTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, p.jobTimeout)
+		defer cancel()
+	}
+	output, runErr := p.handler(runCtx, job)
+
+	if runErr != nil && job.Attempt < job.MaxAttempts {
+		delay := p.retryDelay << (job.Attempt - 1)
+		p.logger.Warn(ctx, "Job failed, retrying", map[string]interface{}{
+			"job_id":  job.ID,
+			"attempt": job.Attempt,
+			"delay":   delay.String(),
+			"error":   runErr.Error(),
+		})
+		if err := p.queue.Requeue(ctx, job, delay); err != nil {
+			p.logger.Error(ctx, "Failed to requeue job", map[string]interface{}{
+				"job_id": job.ID,
+				"error":  err.Error(),
+			})
+		}
+		return
+	}
+
+	if runErr != nil {
+		p.logger.Error(ctx, "Job failed", map[string]interface{}{
+			"job_id":   job.ID,
+			"attempts": job.Attempt,
+			"error":    runErr.Error(),
+		})
+	}
+	if err := p.queue.Finish(ctx, job, output, runErr); err != nil {
+		p.logger.Error(ctx, "Failed to finish job", map[string]interface{}{
+			"job_id": job.ID,
+			"error":  err.Error(),
+		})
+	}
+}
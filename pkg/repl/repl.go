@@ -0,0 +1,335 @@
+// Package repl provides an interactive terminal chat loop for agents, so
+// they can be tried without running the HTTP server and UI.
+package repl
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// maxToolResultLength is the length tool results are truncated to on screen
+const maxToolResultLength = 200
+
+// ModelSwitcher returns the agent to chat with after switching to model
+type ModelSwitcher func(ctx context.Context, model string) (interfaces.StreamingAgent, error)
+
+// Turn is a message of the chat transcript
+type Turn struct {
+	Role      string    `json:"role"` // "user", "assistant" or "tool"
+	Content   string    `json:"content"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// REPL is an interactive chat loop reading messages from its input and
+// streaming the agent's responses to its output. Lines starting with "/"
+// are commands: /help, /reset, /model, /save and /exit.
+type REPL struct {
+	agent          interfaces.StreamingAgent
+	in             io.Reader
+	out            io.Writer
+	prompt         string
+	conversationID string
+	switchModel    ModelSwitcher
+	showThinking   bool
+	interruptible  bool
+
+	transcript []Turn
+}
+
+// Option configures a REPL
+type Option func(*REPL)
+
+// WithInput sets the reader of the messages (default os.Stdin)
+func WithInput(in io.Reader) Option {
+	return func(r *REPL) {
+		r.in = in
+	}
+}
+
+// WithOutput sets the writer of the responses (default os.Stdout)
+func WithOutput(out io.Writer) Option {
+	return func(r *REPL) {
+		r.out = out
+	}
+}
+
+// WithPrompt sets the prompt shown before each message (default "> ")
+func WithPrompt(prompt string) Option {
+	return func(r *REPL) {
+		r.prompt = prompt
+	}
+}
+
+// WithConversationID sets the conversation of the chat when the context
+// passed to Run has none (default "repl")
+func WithConversationID(conversationID string) Option {
+	return func(r *REPL) {
+		r.conversationID = conversationID
+	}
+}
+
+// WithModelSwitcher enables "/model <name>", replacing the agent with the one
+// switch returns
+func WithModelSwitcher(switcher ModelSwitcher) Option {
+	return func(r *REPL) {
+		r.switchModel = switcher
+	}
+}
+
+// WithThinking shows the agent's thinking steps
+func WithThinking(show bool) Option {
+	return func(r *REPL) {
+		r.showThinking = show
+	}
+}
+
+// WithInterrupt makes Ctrl+C cancel the response being streamed instead of
+// exiting the program
+func WithInterrupt(enabled bool) Option {
+	return func(r *REPL) {
+		r.interruptible = enabled
+	}
+}
+
+// New creates a REPL chatting with agent
+func New(agent interfaces.StreamingAgent, options ...Option) *REPL {
+	r := &REPL{
+		agent:          agent,
+		in:             os.Stdin,
+		out:            os.Stdout,
+		prompt:         "> ",
+		conversationID: "repl",
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Transcript returns the messages and tool calls of the chat so far
+func (r *REPL) Transcript() []Turn {
+	return append([]Turn(nil), r.transcript...)
+}
+
+// Run chats until the input ends, /exit is entered or ctx is done. The chat
+// is in the organization and conversation of ctx, or in the default
+// organization and the REPL's conversation when ctx has none.
+func (r *REPL) Run(ctx context.Context) error {
+	if !multitenancy.HasOrgID(ctx) {
+		ctx = multitenancy.WithOrgID(ctx, multitenancy.DefaultOrgID)
+	}
+	if _, ok := memory.GetConversationID(ctx); !ok {
+		ctx = memory.WithConversationID(ctx, r.conversationID)
+	}
+
+	scanner := bufio.NewScanner(r.in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for {
+		r.printf("\n%s", r.prompt)
+		if !scanner.Scan() {
+			r.printf("\n")
+			return scanner.Err()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			continue
+		}
+		if strings.HasPrefix(input, "/") {
+			exit, err := r.command(ctx, input)
+			if err != nil {
+				r.printf("Error: %v\n", err)
+			}
+			if exit {
+				return nil
+			}
+			continue
+		}
+
+		if err := r.turn(ctx, input); err != nil {
+			r.printf("\nError: %v\n", err)
+		}
+	}
+}
+
+// turn streams the agent's response to a message
+func (r *REPL) turn(ctx context.Context, input string) error {
+	if r.interruptible {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+	}
+	r.record("user", input)
+
+	events, err := r.agent.RunStream(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	var response strings.Builder
+	var runErr error
+	for event := range events {
+		switch event.Type {
+		case interfaces.AgentEventContent:
+			response.WriteString(event.Content)
+			r.printf("%s", event.Content)
+		case interfaces.AgentEventThinking:
+			if r.showThinking && event.ThinkingStep != "" {
+				r.printf("\n[thinking] %s\n", event.ThinkingStep)
+			}
+		case interfaces.AgentEventToolCall:
+			if call := event.ToolCall; call != nil && !call.Internal {
+				r.printf("\n→ %s(%s)\n", toolName(call), call.Arguments)
+				r.record("tool", fmt.Sprintf("%s(%s)", call.Name, call.Arguments))
+			}
+		case interfaces.AgentEventToolResult:
+			if call := event.ToolCall; call != nil && !call.Internal {
+				r.printf("← %s: %s\n", toolName(call), truncate(call.Result, maxToolResultLength))
+				r.record("tool", fmt.Sprintf("%s → %s", call.Name, call.Result))
+			}
+		case interfaces.AgentEventError:
+			if event.Error != nil {
+				runErr = event.Error
+			}
+		}
+	}
+	r.printf("\n")
+
+	if response.Len() > 0 {
+		r.record("assistant", response.String())
+	}
+	if runErr != nil {
+		return runErr
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		r.printf("(cancelled)\n")
+	}
+	return nil
+}
+
+// command runs a slash command and returns whether the chat should end
+func (r *REPL) command(ctx context.Context, input string) (bool, error) {
+	name, arg, _ := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case "/exit", "/quit":
+		return true, nil
+
+	case "/help":
+		r.printf(`Commands:
+  /reset          Clear the conversation
+  /model [name]   Show the model, or switch to another
+  /save [file]    Save the transcript as Markdown
+  /exit           Quit
+`)
+
+	case "/reset":
+		if memoryAgent, ok := r.agent.(interface{ GetMemory() interfaces.Memory }); ok && memoryAgent.GetMemory() != nil {
+			if err := memoryAgent.GetMemory().Clear(ctx); err != nil {
+				return false, fmt.Errorf("failed to clear the conversation: %w", err)
+			}
+		}
+		r.transcript = nil
+		r.printf("Conversation cleared\n")
+
+	case "/model":
+		if arg == "" {
+			r.printf("Model: %s\n", r.model())
+			return false, nil
+		}
+		if r.switchModel == nil {
+			return false, fmt.Errorf("switching models is not supported")
+		}
+		agent, err := r.switchModel(ctx, arg)
+		if err != nil {
+			return false, fmt.Errorf("failed to switch to %s: %w", arg, err)
+		}
+		r.agent = agent
+		r.printf("Switched to %s\n", arg)
+
+	case "/save":
+		path := arg
+		if path == "" {
+			path = fmt.Sprintf("chat-%s.md", time.Now().Format("20060102-150405"))
+		}
+		if err := r.Save(path); err != nil {
+			return false, err
+		}
+		r.printf("Saved transcript to %s\n", path)
+
+	default:
+		return false, fmt.Errorf("unknown command %s, type /help for the commands", name)
+	}
+	return false, nil
+}
+
+// Save writes the transcript to path as Markdown
+func (r *REPL) Save(path string) error {
+	var b strings.Builder
+	b.WriteString("# Chat transcript\n")
+	for _, turn := range r.transcript {
+		switch turn.Role {
+		case "user":
+			fmt.Fprintf(&b, "\n**You** (%s):\n\n%s\n", turn.Timestamp.Format(time.RFC3339), turn.Content)
+		case "assistant":
+			fmt.Fprintf(&b, "\n**Assistant**:\n\n%s\n", turn.Content)
+		case "tool":
+			fmt.Fprintf(&b, "\n> Tool: `%s`\n", strings.ReplaceAll(turn.Content, "\n", " "))
+		}
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to save transcript: %w", err)
+	}
+	return nil
+}
+
+// model returns the name of the agent's model, if it exposes its LLM
+func (r *REPL) model() string {
+	llmAgent, ok := r.agent.(interface{ GetLLM() interfaces.LLM })
+	if !ok || llmAgent.GetLLM() == nil {
+		return "unknown"
+	}
+	llm := llmAgent.GetLLM()
+	if modelLLM, ok := llm.(interface{ GetModel() string }); ok && modelLLM.GetModel() != "" {
+		return fmt.Sprintf("%s (%s)", modelLLM.GetModel(), llm.Name())
+	}
+	return llm.Name()
+}
+
+func (r *REPL) record(role, content string) {
+	r.transcript = append(r.transcript, Turn{Role: role, Content: content, Timestamp: time.Now()})
+}
+
+func (r *REPL) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(r.out, format, args...)
+}
+
+func toolName(call *interfaces.ToolCallEvent) string {
+	if call.DisplayName != "" {
+		return call.DisplayName
+	}
+	return call.Name
+}
+
+func truncate(s string, n int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
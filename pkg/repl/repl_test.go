@@ -0,0 +1,96 @@
+package repl
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// fakeAgent streams a tool call and a canned reply to every message
+type fakeAgent struct {
+	reply  string
+	inputs []string
+	memory interfaces.Memory
+}
+
+func (a *fakeAgent) RunStream(ctx context.Context, input string) (<-chan interfaces.AgentStreamEvent, error) {
+	a.inputs = append(a.inputs, input)
+	_ = a.memory.AddMessage(ctx, interfaces.Message{Role: interfaces.MessageRoleUser, Content: input})
+
+	events := make(chan interfaces.AgentStreamEvent, 4)
+	events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventToolCall, ToolCall: &interfaces.ToolCallEvent{Name: "calculator", Arguments: `{"expression":"2+2"}`}}
+	events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventToolResult, ToolCall: &interfaces.ToolCallEvent{Name: "calculator", Result: "4"}}
+	events <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: a.reply}
+	close(events)
+	return events, nil
+}
+
+func (a *fakeAgent) GetMemory() interfaces.Memory {
+	return a.memory
+}
+
+func TestREPL(t *testing.T) {
+	store := memory.NewConversationBuffer()
+	agent := &fakeAgent{reply: "The answer is 4", memory: store}
+	transcriptPath := filepath.Join(t.TempDir(), "chat.md")
+
+	input := strings.Join([]string{
+		"what is 2+2?",
+		"/save " + transcriptPath,
+		"/model premium",
+		"/bogus",
+		"/reset",
+		"/exit",
+		"not sent",
+	}, "\n")
+	var out bytes.Buffer
+	switched := &fakeAgent{reply: "switched", memory: store}
+	r := New(agent,
+		WithInput(strings.NewReader(input)),
+		WithOutput(&out),
+		WithConversationID("test"),
+		WithModelSwitcher(func(ctx context.Context, model string) (interfaces.StreamingAgent, error) {
+			if model != "premium" {
+				t.Errorf("Expected model premium, got %s", model)
+			}
+			return switched, nil
+		}),
+	)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(agent.inputs) != 1 || agent.inputs[0] != "what is 2+2?" {
+		t.Errorf("Expected one message sent, got %v", agent.inputs)
+	}
+	for _, expected := range []string{"→ calculator(", "← calculator: 4", "The answer is 4", "Switched to premium", "unknown command /bogus", "Conversation cleared"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+
+	transcript, err := os.ReadFile(transcriptPath)
+	if err != nil {
+		t.Fatalf("Failed to read transcript: %v", err)
+	}
+	if !strings.Contains(string(transcript), "what is 2+2?") || !strings.Contains(string(transcript), "The answer is 4") {
+		t.Errorf("Unexpected transcript:\n%s", transcript)
+	}
+
+	// /reset clears the conversation
+	ctx := memory.WithConversationID(multitenancy.WithOrgID(context.Background(), multitenancy.DefaultOrgID), "test")
+	if messages, _ := store.GetMessages(ctx); len(messages) != 0 {
+		t.Errorf("Expected the conversation to be cleared, got %d messages", len(messages))
+	}
+	if len(r.Transcript()) != 0 {
+		t.Errorf("Expected the transcript to be cleared, got %v", r.Transcript())
+	}
+}
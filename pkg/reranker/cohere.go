@@ -0,0 +1,62 @@
+package reranker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultCohereModel is the default Cohere rerank model
+	DefaultCohereModel = "rerank-v3.5"
+
+	// DefaultCohereBaseURL is the Cohere API base URL
+	DefaultCohereBaseURL = "https://api.cohere.com"
+)
+
+// CohereReranker implements interfaces.Reranker using the Cohere rerank API
+type CohereReranker struct {
+	apiKey string
+	config config
+}
+
+// NewCohereReranker creates a new Cohere reranker
+func NewCohereReranker(apiKey string, options ...Option) *CohereReranker {
+	return &CohereReranker{
+		apiKey: apiKey,
+		config: newConfig(DefaultCohereModel, DefaultCohereBaseURL, options),
+	}
+}
+
+// Rerank implements interfaces.Reranker.Rerank
+func (r *CohereReranker) Rerank(ctx context.Context, query string, documents []string, topN int) ([]interfaces.RerankResult, error) {
+	if len(documents) == 0 {
+		return []interfaces.RerankResult{}, nil
+	}
+
+	request := map[string]interface{}{
+		"model":     r.config.model,
+		"query":     query,
+		"documents": documents,
+	}
+	if topN > 0 {
+		request["top_n"] = topN
+	}
+
+	var response struct {
+		Results []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float32 `json:"relevance_score"`
+		} `json:"results"`
+	}
+	if err := postJSON(ctx, r.config.httpClient, r.config.baseURL+"/v2/rerank", r.apiKey, request, &response); err != nil {
+		return nil, fmt.Errorf("cohere rerank failed: %w", err)
+	}
+
+	results := make([]interfaces.RerankResult, 0, len(response.Results))
+	for _, result := range response.Results {
+		results = append(results, interfaces.RerankResult{Index: result.Index, Score: result.RelevanceScore})
+	}
+	return sortResults(results, topN), nil
+}
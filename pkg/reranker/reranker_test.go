@@ -0,0 +1,76 @@
+package reranker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCohereRerank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/rerank" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing bearer token")
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if body["top_n"] != float64(2) || body["model"] != DefaultCohereModel {
+			t.Errorf("unexpected request body: %v", body)
+		}
+		_, _ = w.Write([]byte(`{"results":[{"index":2,"relevance_score":0.9},{"index":0,"relevance_score":0.4}]}`))
+	}))
+	defer server.Close()
+
+	r := NewCohereReranker("test-key", WithBaseURL(server.URL))
+	results, err := r.Rerank(context.Background(), "q", []string{"a", "b", "c"}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].Index != 2 || results[1].Index != 0 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestVoyageRerank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/rerank" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if body["top_k"] != float64(1) || body["model"] != "rerank-2-lite" {
+			t.Errorf("unexpected request body: %v", body)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"index":1,"relevance_score":0.8}]}`))
+	}))
+	defer server.Close()
+
+	r := NewVoyageReranker("test-key", WithBaseURL(server.URL), WithModel("rerank-2-lite"))
+	results, err := r.Rerank(context.Background(), "q", []string{"a", "b"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Index != 1 || results[0].Score != 0.8 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestRerankErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	r := NewCohereReranker("bad-key", WithBaseURL(server.URL))
+	if _, err := r.Rerank(context.Background(), "q", []string{"a"}, 1); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
@@ -0,0 +1,62 @@
+package reranker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultVoyageModel is the default Voyage AI rerank model
+	DefaultVoyageModel = "rerank-2"
+
+	// DefaultVoyageBaseURL is the Voyage AI API base URL
+	DefaultVoyageBaseURL = "https://api.voyageai.com"
+)
+
+// VoyageReranker implements interfaces.Reranker using the Voyage AI rerank API
+type VoyageReranker struct {
+	apiKey string
+	config config
+}
+
+// NewVoyageReranker creates a new Voyage AI reranker
+func NewVoyageReranker(apiKey string, options ...Option) *VoyageReranker {
+	return &VoyageReranker{
+		apiKey: apiKey,
+		config: newConfig(DefaultVoyageModel, DefaultVoyageBaseURL, options),
+	}
+}
+
+// Rerank implements interfaces.Reranker.Rerank
+func (r *VoyageReranker) Rerank(ctx context.Context, query string, documents []string, topN int) ([]interfaces.RerankResult, error) {
+	if len(documents) == 0 {
+		return []interfaces.RerankResult{}, nil
+	}
+
+	request := map[string]interface{}{
+		"model":     r.config.model,
+		"query":     query,
+		"documents": documents,
+	}
+	if topN > 0 {
+		request["top_k"] = topN
+	}
+
+	var response struct {
+		Data []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float32 `json:"relevance_score"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, r.config.httpClient, r.config.baseURL+"/v1/rerank", r.apiKey, request, &response); err != nil {
+		return nil, fmt.Errorf("voyage rerank failed: %w", err)
+	}
+
+	results := make([]interfaces.RerankResult, 0, len(response.Data))
+	for _, result := range response.Data {
+		results = append(results, interfaces.RerankResult{Index: result.Index, Score: result.RelevanceScore})
+	}
+	return sortResults(results, topN), nil
+}
@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ServeHTTP serves the jobs and their run history over HTTP. Mount it with
+// http.StripPrefix; relative to its prefix it serves:
+//
+//	GET /                  the registered jobs
+//	GET /{name}/runs       the runs of a job, most recent first (?limit=)
+//	POST /{name}/run       run a job now and return the run
+func (s *Scheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	if path == "" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, http.StatusOK, s.Jobs())
+		return
+	}
+
+	name, action, ok := strings.Cut(path, "/")
+	if !ok || strings.Contains(action, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.hasJob(name) {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && action == "runs":
+		limit := 0
+		if value := r.URL.Query().Get("limit"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		writeJSON(w, http.StatusOK, s.History(name, limit))
+	case r.Method == http.MethodPost && action == "run":
+		run, err := s.RunNow(r.Context(), name)
+		switch {
+		case errors.Is(err, ErrJobNotFound):
+			http.Error(w, "Job not found", http.StatusNotFound)
+		case errors.Is(err, ErrJobRunning):
+			http.Error(w, "Job is already running", http.StatusConflict)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			writeJSON(w, http.StatusOK, run)
+		}
+	case action == "runs" || action == "run":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// hasJob reports whether a job is registered
+func (s *Scheduler) hasJob(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, exists := s.jobs[name]
+	return exists
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
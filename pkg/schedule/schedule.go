@@ -0,0 +1,422 @@
+// Package schedule runs agents on cron schedules and keeps the history of
+// their runs.
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrJobNotFound is returned for a job name that is not registered
+	ErrJobNotFound = errors.New("scheduled job not found")
+
+	// ErrJobRunning is returned by RunNow while the job is already running
+	ErrJobRunning = errors.New("scheduled job is already running")
+)
+
+// Runner runs an agent on an input; *agent.Agent implements it
+type Runner interface {
+	Run(ctx context.Context, input string) (string, error)
+}
+
+// Job is an agent run on a schedule
+type Job struct {
+	// Name identifies the job
+	Name string
+
+	// Cron is the schedule, see ParseCron
+	Cron string
+
+	// Agent runs the job
+	Agent Runner
+
+	// Input is a text/template rendered with TemplateData for each run,
+	// e.g. "Summarize the tickets opened on {{.Time.Format \"2006-01-02\"}}"
+	Input string
+
+	// ConversationID is the memory conversation the runs are recorded in,
+	// "schedule-<name>" by default, so each run can see the previous ones
+	ConversationID string
+
+	// OrgID is the organization the runs belong to, "default" by default
+	OrgID string
+
+	// Timeout bounds each run; zero means no limit
+	Timeout time.Duration
+}
+
+// TemplateData is the data the input template of a job is rendered with
+type TemplateData struct {
+	// Name is the job name
+	Name string
+
+	// Time is the time the run was scheduled for
+	Time time.Time
+
+	// LastRun is the time of the previous run, zero for the first one
+	LastRun time.Time
+
+	// LastOutput is the output of the previous successful run
+	LastOutput string
+}
+
+// Trigger values of Run
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// Run is a run of a job
+type Run struct {
+	ID          string    `json:"id"`
+	Job         string    `json:"job"`
+	Trigger     string    `json:"trigger"`
+	Input       string    `json:"input"`
+	Output      string    `json:"output,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	DurationMs  int64     `json:"duration_ms"`
+}
+
+// JobInfo describes a registered job
+type JobInfo struct {
+	Name    string    `json:"name"`
+	Cron    string    `json:"cron"`
+	NextRun time.Time `json:"next_run"`
+	Running bool      `json:"running"`
+	LastRun *Run      `json:"last_run,omitempty"`
+}
+
+// Option configures a Scheduler
+type Option func(*Scheduler)
+
+// WithLocation sets the time zone cron expressions are evaluated in, UTC by
+// default
+func WithLocation(location *time.Location) Option {
+	return func(s *Scheduler) {
+		s.location = location
+	}
+}
+
+// WithHistoryLimit sets how many runs are kept per job, 100 by default
+func WithHistoryLimit(limit int) Option {
+	return func(s *Scheduler) {
+		s.historyLimit = limit
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger logging.Logger) Option {
+	return func(s *Scheduler) {
+		s.logger = logger
+	}
+}
+
+// Scheduler runs jobs on their schedules between Start and Stop
+type Scheduler struct {
+	mu           sync.Mutex
+	jobs         map[string]*job
+	history      map[string][]Run
+	historyLimit int
+	location     *time.Location
+	logger       logging.Logger
+	now          func() time.Time
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// job is a registered job
+type job struct {
+	Job
+	schedule Schedule
+	template *template.Template
+	next     time.Time
+	running  bool
+}
+
+// New creates a scheduler
+func New(options ...Option) *Scheduler {
+	s := &Scheduler{
+		jobs:         make(map[string]*job),
+		history:      make(map[string][]Run),
+		historyLimit: 100,
+		location:     time.UTC,
+		logger:       logging.New(),
+		now:          time.Now,
+		wake:         make(chan struct{}, 1),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Add registers a job. Its first run is the next time its schedule matches.
+func (s *Scheduler) Add(j Job) error {
+	if j.Name == "" {
+		return fmt.Errorf("job name is required")
+	}
+	if j.Agent == nil {
+		return fmt.Errorf("job %s has no agent", j.Name)
+	}
+	schedule, err := ParseCron(j.Cron, s.location)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", j.Name, err)
+	}
+	tmpl, err := template.New(j.Name).Option("missingkey=error").Parse(j.Input)
+	if err != nil {
+		return fmt.Errorf("job %s has an invalid input template: %w", j.Name, err)
+	}
+	if j.ConversationID == "" {
+		j.ConversationID = "schedule-" + j.Name
+	}
+	if j.OrgID == "" {
+		j.OrgID = "default"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[j.Name]; exists {
+		return fmt.Errorf("job %s already exists", j.Name)
+	}
+	s.jobs[j.Name] = &job{
+		Job:      j,
+		schedule: schedule,
+		template: tmpl,
+		next:     schedule.Next(s.now()),
+	}
+	s.notify()
+	return nil
+}
+
+// Remove unregisters a job. A run in progress finishes; the history is kept.
+func (s *Scheduler) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[name]; !exists {
+		return ErrJobNotFound
+	}
+	delete(s.jobs, name)
+	s.notify()
+	return nil
+}
+
+// Jobs returns the registered jobs sorted by name
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]JobInfo, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		info := JobInfo{Name: j.Name, Cron: j.Cron, NextRun: j.next, Running: j.running}
+		if runs := s.history[j.Name]; len(runs) > 0 {
+			last := runs[len(runs)-1]
+			info.LastRun = &last
+		}
+		jobs = append(jobs, info)
+	}
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].Name < jobs[k].Name
+	})
+	return jobs
+}
+
+// History returns up to limit runs of a job, most recent first. A limit of
+// zero or less returns all the runs kept.
+func (s *Scheduler) History(name string, limit int) []Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := s.history[name]
+	if limit <= 0 || limit > len(runs) {
+		limit = len(runs)
+	}
+	history := make([]Run, 0, limit)
+	for i := len(runs) - 1; i >= len(runs)-limit; i-- {
+		history = append(history, runs[i])
+	}
+	return history
+}
+
+// Start runs the jobs in the background until Stop is called or ctx is
+// cancelled
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("scheduler is already running")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.wg.Add(1)
+	go s.loop(ctx)
+	return nil
+}
+
+// Stop stops scheduling runs, cancels the runs in progress and waits for them
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// RunNow runs a job immediately and returns the run once finished
+func (s *Scheduler) RunNow(ctx context.Context, name string) (Run, error) {
+	s.mu.Lock()
+	j, exists := s.jobs[name]
+	if !exists {
+		s.mu.Unlock()
+		return Run{}, ErrJobNotFound
+	}
+	if j.running {
+		s.mu.Unlock()
+		return Run{}, ErrJobRunning
+	}
+	j.running = true
+	s.mu.Unlock()
+
+	return s.run(ctx, j, s.now(), TriggerManual), nil
+}
+
+// loop starts the jobs that are due and sleeps until the next one
+func (s *Scheduler) loop(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		now := s.now()
+		var next time.Time
+		s.mu.Lock()
+		for _, j := range s.jobs {
+			if !j.next.After(now) {
+				scheduledAt := j.next
+				j.next = j.schedule.Next(now)
+				if j.running {
+					s.logger.Warn(ctx, "Skipping scheduled run, the previous one is still running", map[string]interface{}{
+						"job": j.Name,
+					})
+				} else {
+					j.running = true
+					s.wg.Add(1)
+					go func(j *job) {
+						defer s.wg.Done()
+						s.run(ctx, j, scheduledAt, TriggerSchedule)
+					}(j)
+				}
+			}
+			if !j.next.IsZero() && (next.IsZero() || j.next.Before(next)) {
+				next = j.next
+			}
+		}
+		s.mu.Unlock()
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(next.Sub(now))
+			fire = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-s.wake:
+		case <-fire:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// notify wakes the loop up to reconsider the next run
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run runs a job, marked running by the caller, and records the run
+func (s *Scheduler) run(ctx context.Context, j *job, scheduledAt time.Time, trigger string) Run {
+	run := Run{
+		ID:          uuid.New().String(),
+		Job:         j.Name,
+		Trigger:     trigger,
+		ScheduledAt: scheduledAt,
+		StartedAt:   s.now(),
+	}
+
+	s.mu.Lock()
+	data := TemplateData{Name: j.Name, Time: scheduledAt.In(s.location)}
+	for i := len(s.history[j.Name]) - 1; i >= 0; i-- {
+		previous := s.history[j.Name][i]
+		if data.LastRun.IsZero() {
+			data.LastRun = previous.ScheduledAt
+		}
+		if previous.Error == "" {
+			data.LastOutput = previous.Output
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	var input bytes.Buffer
+	err := j.template.Execute(&input, data)
+	run.Input = input.String()
+	if err != nil {
+		err = fmt.Errorf("failed to render input: %w", err)
+	} else {
+		runCtx := multitenancy.WithOrgID(ctx, j.OrgID)
+		runCtx = memory.WithConversationID(runCtx, j.ConversationID)
+		if j.Timeout > 0 {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithTimeout(runCtx, j.Timeout)
+			defer cancel()
+		}
+		run.Output, err = j.Agent.Run(runCtx, run.Input)
+	}
+
+	run.FinishedAt = s.now()
+	run.DurationMs = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+	if err != nil {
+		run.Error = err.Error()
+		s.logger.Error(ctx, "Scheduled run failed", map[string]interface{}{
+			"job":   j.Name,
+			"run":   run.ID,
+			"error": err.Error(),
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.running = false
+	runs := append(s.history[j.Name], run)
+	if s.historyLimit > 0 && len(runs) > s.historyLimit {
+		runs = runs[len(runs)-s.historyLimit:]
+	}
+	s.history[j.Name] = runs
+	return run
+}
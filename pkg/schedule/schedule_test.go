@@ -0,0 +1,229 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// recordingRunner records the inputs and contexts of its runs
+type recordingRunner struct {
+	mu            sync.Mutex
+	inputs        []string
+	conversations []string
+	orgs          []string
+	err           error
+}
+
+func (r *recordingRunner) Run(ctx context.Context, input string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conversationID, _ := memory.GetConversationID(ctx)
+	orgID, _ := multitenancy.GetOrgID(ctx)
+	r.inputs = append(r.inputs, input)
+	r.conversations = append(r.conversations, conversationID)
+	r.orgs = append(r.orgs, orgID)
+	if r.err != nil {
+		return "", r.err
+	}
+	return "output " + input, nil
+}
+
+func (r *recordingRunner) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.inputs)
+}
+
+func TestSchedulerRunsOnSchedule(t *testing.T) {
+	runner := &recordingRunner{}
+	s := New()
+	if err := s.Add(Job{Name: "tick", Cron: "@every 1s", Agent: runner, Input: "run {{.Name}}"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runner.count() < 2 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	s.Stop()
+
+	if runner.count() < 2 {
+		t.Fatalf("expected at least 2 runs, got %d", runner.count())
+	}
+	if runner.inputs[0] != "run tick" {
+		t.Errorf("unexpected input %q", runner.inputs[0])
+	}
+	if runner.conversations[0] != "schedule-tick" || runner.orgs[0] != "default" {
+		t.Errorf("unexpected context: conversation %q, org %q", runner.conversations[0], runner.orgs[0])
+	}
+
+	history := s.History("tick", 0)
+	if len(history) != runner.count() {
+		t.Fatalf("expected %d runs in history, got %d", runner.count(), len(history))
+	}
+	if history[0].Trigger != TriggerSchedule || history[0].Output != "output run tick" {
+		t.Errorf("unexpected run %+v", history[0])
+	}
+	if !history[0].StartedAt.After(history[1].StartedAt) {
+		t.Error("expected the most recent run first")
+	}
+}
+
+func TestSchedulerRunNowTemplate(t *testing.T) {
+	runner := &recordingRunner{}
+	s := New()
+	err := s.Add(Job{
+		Name:           "report",
+		Cron:           "0 9 * * *",
+		Agent:          runner,
+		Input:          "Report for {{.Time.Format \"2006\"}}. Previous: {{.LastOutput}}",
+		ConversationID: "reports",
+		OrgID:          "acme",
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	first, err := s.RunNow(context.Background(), "report")
+	if err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	year := time.Now().UTC().Format("2006")
+	if first.Input != "Report for "+year+". Previous: " || first.Trigger != TriggerManual {
+		t.Errorf("unexpected first run %+v", first)
+	}
+
+	second, err := s.RunNow(context.Background(), "report")
+	if err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if !strings.HasSuffix(second.Input, "Previous: "+first.Output) {
+		t.Errorf("expected the previous output in the input, got %q", second.Input)
+	}
+	if runner.conversations[1] != "reports" || runner.orgs[1] != "acme" {
+		t.Errorf("unexpected context: conversation %q, org %q", runner.conversations[1], runner.orgs[1])
+	}
+
+	if _, err := s.RunNow(context.Background(), "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestSchedulerRecordsFailures(t *testing.T) {
+	runner := &recordingRunner{err: errors.New("boom")}
+	s := New(WithHistoryLimit(2))
+	if err := s.Add(Job{Name: "flaky", Cron: "@hourly", Agent: runner, Input: "go"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		run, err := s.RunNow(context.Background(), "flaky")
+		if err != nil {
+			t.Fatalf("RunNow: %v", err)
+		}
+		if run.Error != "boom" {
+			t.Errorf("expected the run error, got %q", run.Error)
+		}
+	}
+	if history := s.History("flaky", 0); len(history) != 2 {
+		t.Errorf("expected the history limited to 2 runs, got %d", len(history))
+	}
+}
+
+func TestSchedulerAddValidates(t *testing.T) {
+	s := New()
+	runner := &recordingRunner{}
+	for _, job := range []Job{
+		{Cron: "@daily", Agent: runner},
+		{Name: "no-agent", Cron: "@daily"},
+		{Name: "bad-cron", Cron: "every day", Agent: runner},
+		{Name: "bad-template", Cron: "@daily", Agent: runner, Input: "{{.Name"},
+	} {
+		if err := s.Add(job); err == nil {
+			t.Errorf("Add(%+v) succeeded, want an error", job)
+		}
+	}
+
+	if err := s.Add(Job{Name: "ok", Cron: "@daily", Agent: runner}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Add(Job{Name: "ok", Cron: "@daily", Agent: runner}); err == nil {
+		t.Error("expected an error for a duplicate job")
+	}
+	if err := s.Remove("ok"); err != nil {
+		t.Errorf("Remove: %v", err)
+	}
+	if err := s.Remove("ok"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestSchedulerHTTP(t *testing.T) {
+	s := New()
+	if err := s.Add(Job{Name: "digest", Cron: "0 8 * * mon", Agent: &recordingRunner{}, Input: "digest"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	server := httptest.NewServer(http.StripPrefix("/schedules", s))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/schedules/digest/run", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	var run Run
+	if err := json.NewDecoder(resp.Body).Decode(&run); err != nil {
+		t.Fatalf("decode run: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || run.Output != "output digest" {
+		t.Fatalf("unexpected response %d %+v", resp.StatusCode, run)
+	}
+
+	resp, err = http.Get(server.URL + "/schedules/")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	var jobs []JobInfo
+	if err := json.NewDecoder(resp.Body).Decode(&jobs); err != nil {
+		t.Fatalf("decode jobs: %v", err)
+	}
+	_ = resp.Body.Close()
+	if len(jobs) != 1 || jobs[0].LastRun == nil || jobs[0].LastRun.ID != run.ID || jobs[0].NextRun.Weekday() != time.Monday {
+		t.Errorf("unexpected jobs %+v", jobs)
+	}
+
+	resp, err = http.Get(server.URL + "/schedules/digest/runs?limit=1")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	var runs []Run
+	if err := json.NewDecoder(resp.Body).Decode(&runs); err != nil {
+		t.Fatalf("decode runs: %v", err)
+	}
+	_ = resp.Body.Close()
+	if len(runs) != 1 || runs[0].ID != run.ID {
+		t.Errorf("unexpected runs %+v", runs)
+	}
+
+	resp, err = http.Get(server.URL + "/schedules/missing/runs")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing job, got %d", resp.StatusCode)
+	}
+}
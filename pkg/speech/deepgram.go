@@ -0,0 +1,98 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultDeepgramModel is the default Deepgram transcription model
+	DefaultDeepgramModel = "nova-3"
+
+	// DefaultDeepgramBaseURL is the Deepgram API base URL
+	DefaultDeepgramBaseURL = "https://api.deepgram.com"
+)
+
+// DeepgramTranscriber implements interfaces.SpeechToText using the Deepgram
+// pre-recorded audio API
+type DeepgramTranscriber struct {
+	apiKey string
+	config config
+}
+
+// NewDeepgramTranscriber creates a new Deepgram transcriber
+func NewDeepgramTranscriber(apiKey string, options ...Option) *DeepgramTranscriber {
+	return &DeepgramTranscriber{
+		apiKey: apiKey,
+		config: newConfig(DefaultDeepgramModel, DefaultDeepgramBaseURL, options),
+	}
+}
+
+// Transcribe implements interfaces.SpeechToText.Transcribe. The prompt is
+// sent as a key term to boost its recognition.
+func (t *DeepgramTranscriber) Transcribe(ctx context.Context, audio []byte, mimeType string, options ...interfaces.TranscribeOption) (*interfaces.Transcription, error) {
+	params := transcribeOptions(options)
+
+	query := url.Values{}
+	query.Set("model", t.config.model)
+	query.Set("smart_format", "true")
+	if params.Language != "" {
+		query.Set("language", params.Language)
+	} else {
+		query.Set("detect_language", "true")
+	}
+	if params.Prompt != "" {
+		query.Set("keyterm", params.Prompt)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.baseURL+"/v1/listen?"+query.Encode(), bytes.NewReader(audio))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", mimeType)
+	req.Header.Set("Authorization", "Token "+t.apiKey)
+
+	data, err := do(t.config.httpClient, req, "deepgram")
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Metadata struct {
+			Duration float64 `json:"duration"`
+		} `json:"metadata"`
+		Results struct {
+			Channels []struct {
+				DetectedLanguage string `json:"detected_language"`
+				Alternatives     []struct {
+					Transcript string `json:"transcript"`
+				} `json:"alternatives"`
+			} `json:"channels"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	transcription := &interfaces.Transcription{
+		Language: params.Language,
+		Duration: time.Duration(response.Metadata.Duration * float64(time.Second)),
+	}
+	if len(response.Results.Channels) > 0 {
+		channel := response.Results.Channels[0]
+		if channel.DetectedLanguage != "" {
+			transcription.Language = channel.DetectedLanguage
+		}
+		if len(channel.Alternatives) > 0 {
+			transcription.Text = channel.Alternatives[0].Transcript
+		}
+	}
+	return transcription, nil
+}
@@ -0,0 +1,81 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultElevenLabsModel is the default ElevenLabs text-to-speech model
+	DefaultElevenLabsModel = "eleven_multilingual_v2"
+
+	// DefaultElevenLabsVoice is the ID of the default ElevenLabs voice (Rachel)
+	DefaultElevenLabsVoice = "21m00Tcm4TlvDq8ikWAM"
+
+	// DefaultElevenLabsBaseURL is the ElevenLabs API base URL
+	DefaultElevenLabsBaseURL = "https://api.elevenlabs.io"
+)
+
+// elevenLabsFormats maps audio formats to ElevenLabs output formats
+var elevenLabsFormats = map[string]string{
+	"mp3":  "mp3_44100_128",
+	"opus": "opus_48000_128",
+	"pcm":  "pcm_44100",
+}
+
+// ElevenLabsSynthesizer implements interfaces.TextToSpeech using the
+// ElevenLabs text-to-speech API. Voices are given by ID.
+type ElevenLabsSynthesizer struct {
+	apiKey string
+	config config
+}
+
+// NewElevenLabsSynthesizer creates a new ElevenLabs text-to-speech backend
+func NewElevenLabsSynthesizer(apiKey string, options ...Option) *ElevenLabsSynthesizer {
+	return &ElevenLabsSynthesizer{
+		apiKey: apiKey,
+		config: newVoiceConfig(DefaultElevenLabsModel, DefaultElevenLabsVoice, DefaultElevenLabsBaseURL, options),
+	}
+}
+
+// Synthesize implements interfaces.TextToSpeech.Synthesize. The mp3, opus
+// and pcm formats are supported.
+func (s *ElevenLabsSynthesizer) Synthesize(ctx context.Context, text string, options ...interfaces.SynthesizeOption) (*interfaces.Speech, error) {
+	params := s.config.synthesizeOptions(options)
+	outputFormat, ok := elevenLabsFormats[params.Format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported audio format %q", params.Format)
+	}
+
+	body := map[string]interface{}{
+		"text":     text,
+		"model_id": s.config.model,
+	}
+	if params.Speed > 0 {
+		body["voice_settings"] = map[string]interface{}{"speed": params.Speed}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/text-to-speech/%s?output_format=%s", s.config.baseURL, url.PathEscape(params.Voice), outputFormat)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("xi-api-key", s.apiKey)
+
+	data, err := do(s.config.httpClient, req, "elevenlabs")
+	if err != nil {
+		return nil, err
+	}
+	return &interfaces.Speech{Data: data, MIMEType: mimeTypes[params.Format]}, nil
+}
@@ -0,0 +1,71 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// DefaultGeminiModel is the default Gemini model used for transcription
+const DefaultGeminiModel = "gemini-2.5-flash"
+
+// GeminiTranscriber implements interfaces.SpeechToText by asking a Gemini
+// model to transcribe audio
+type GeminiTranscriber struct {
+	client *genai.Client
+	config config
+}
+
+// NewGeminiTranscriber creates a new Gemini transcriber using the Gemini API
+func NewGeminiTranscriber(ctx context.Context, apiKey string, options ...Option) (*GeminiTranscriber, error) {
+	c := newConfig(DefaultGeminiModel, "", options)
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:      apiKey,
+		Backend:     genai.BackendGeminiAPI,
+		HTTPClient:  c.httpClient,
+		HTTPOptions: genai.HTTPOptions{BaseURL: c.baseURL},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	return &GeminiTranscriber{client: client, config: c}, nil
+}
+
+// NewGeminiTranscriberWithClient creates a new Gemini transcriber using an
+// existing client, e.g. one configured for Vertex AI
+func NewGeminiTranscriberWithClient(client *genai.Client, options ...Option) *GeminiTranscriber {
+	return &GeminiTranscriber{client: client, config: newConfig(DefaultGeminiModel, "", options)}
+}
+
+// Transcribe implements interfaces.SpeechToText.Transcribe
+func (t *GeminiTranscriber) Transcribe(ctx context.Context, audio []byte, mimeType string, options ...interfaces.TranscribeOption) (*interfaces.Transcription, error) {
+	params := transcribeOptions(options)
+
+	instruction := "Transcribe this audio verbatim. Respond with the transcript only, without timestamps, speaker labels or commentary."
+	if params.Language != "" {
+		instruction += fmt.Sprintf(" The speech is in language %q.", params.Language)
+	}
+	if params.Prompt != "" {
+		instruction += " Context: " + params.Prompt
+	}
+
+	contents := []*genai.Content{{
+		Role: "user",
+		Parts: []*genai.Part{
+			{Text: instruction},
+			{InlineData: &genai.Blob{MIMEType: mimeType, Data: audio}},
+		},
+	}}
+	temperature := float32(0)
+	response, err := t.client.Models.GenerateContent(ctx, t.config.model, contents, &genai.GenerateContentConfig{
+		Temperature: &temperature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gemini transcription failed: %w", err)
+	}
+	return &interfaces.Transcription{Text: strings.TrimSpace(response.Text()), Language: params.Language}, nil
+}
@@ -0,0 +1,107 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultGoogleVoice is the default Google Cloud Text-to-Speech voice
+	DefaultGoogleVoice = "en-US-Neural2-F"
+
+	// DefaultGoogleTTSBaseURL is the Google Cloud Text-to-Speech API base URL
+	DefaultGoogleTTSBaseURL = "https://texttospeech.googleapis.com"
+)
+
+// googleEncodings maps audio formats to Google Cloud audio encodings
+var googleEncodings = map[string]string{
+	"mp3":  "MP3",
+	"wav":  "LINEAR16",
+	"opus": "OGG_OPUS",
+}
+
+// GoogleSynthesizer implements interfaces.TextToSpeech using the Google
+// Cloud Text-to-Speech API. Voices are given by name, e.g. en-US-Neural2-F;
+// the language is taken from the voice name.
+type GoogleSynthesizer struct {
+	apiKey string
+	config config
+}
+
+// NewGoogleSynthesizer creates a new Google Cloud text-to-speech backend.
+// With an empty apiKey, requests are authenticated by the HTTP client set
+// with WithHTTPClient, e.g. one from golang.org/x/oauth2/google.
+func NewGoogleSynthesizer(apiKey string, options ...Option) *GoogleSynthesizer {
+	return &GoogleSynthesizer{
+		apiKey: apiKey,
+		config: newVoiceConfig("", DefaultGoogleVoice, DefaultGoogleTTSBaseURL, options),
+	}
+}
+
+// Synthesize implements interfaces.TextToSpeech.Synthesize. The mp3, wav
+// and opus formats are supported.
+func (s *GoogleSynthesizer) Synthesize(ctx context.Context, text string, options ...interfaces.SynthesizeOption) (*interfaces.Speech, error) {
+	params := s.config.synthesizeOptions(options)
+	encoding, ok := googleEncodings[params.Format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported audio format %q", params.Format)
+	}
+
+	audioConfig := map[string]interface{}{"audioEncoding": encoding}
+	if params.Speed > 0 {
+		audioConfig["speakingRate"] = params.Speed
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"input":       map[string]string{"text": text},
+		"voice":       map[string]string{"name": params.Voice, "languageCode": languageCode(params.Voice)},
+		"audioConfig": audioConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := s.config.baseURL + "/v1/text:synthesize"
+	if s.apiKey != "" {
+		endpoint += "?key=" + url.QueryEscape(s.apiKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	data, err := do(s.config.httpClient, req, "google text-to-speech")
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		AudioContent string `json:"audioContent"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	audio, err := base64.StdEncoding.DecodeString(response.AudioContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audio: %w", err)
+	}
+	return &interfaces.Speech{Data: audio, MIMEType: mimeTypes[params.Format]}, nil
+}
+
+// languageCode returns the language code of a voice name, e.g. en-US for
+// en-US-Neural2-F
+func languageCode(voice string) string {
+	parts := strings.SplitN(voice, "-", 3)
+	if len(parts) < 2 {
+		return voice
+	}
+	return parts[0] + "-" + parts[1]
+}
@@ -0,0 +1,71 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultOpenAISpeechModel is the default OpenAI text-to-speech model
+	DefaultOpenAISpeechModel = "gpt-4o-mini-tts"
+
+	// DefaultOpenAIVoice is the default OpenAI voice
+	DefaultOpenAIVoice = "alloy"
+)
+
+// OpenAISynthesizer implements interfaces.TextToSpeech using the OpenAI
+// speech API
+type OpenAISynthesizer struct {
+	client openai.Client
+	config config
+}
+
+// NewOpenAISynthesizer creates a new OpenAI text-to-speech backend
+func NewOpenAISynthesizer(apiKey string, options ...Option) *OpenAISynthesizer {
+	c := newVoiceConfig(DefaultOpenAISpeechModel, DefaultOpenAIVoice, DefaultOpenAIBaseURL, options)
+	return &OpenAISynthesizer{
+		client: openai.NewClient(
+			option.WithAPIKey(apiKey),
+			option.WithBaseURL(c.baseURL),
+			option.WithHTTPClient(c.httpClient),
+		),
+		config: c,
+	}
+}
+
+// Synthesize implements interfaces.TextToSpeech.Synthesize
+func (s *OpenAISynthesizer) Synthesize(ctx context.Context, text string, options ...interfaces.SynthesizeOption) (*interfaces.Speech, error) {
+	params := s.config.synthesizeOptions(options)
+	mimeType, ok := mimeTypes[params.Format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported audio format %q", params.Format)
+	}
+
+	request := openai.AudioSpeechNewParams{
+		Input:          text,
+		Model:          openai.SpeechModel(s.config.model),
+		Voice:          openai.AudioSpeechNewParamsVoice(params.Voice),
+		ResponseFormat: openai.AudioSpeechNewParamsResponseFormat(params.Format),
+	}
+	if params.Speed > 0 {
+		request.Speed = openai.Float(params.Speed)
+	}
+
+	resp, err := s.client.Audio.Speech.New(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("openai speech synthesis failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio: %w", err)
+	}
+	return &interfaces.Speech{Data: data, MIMEType: mimeType}, nil
+}
@@ -0,0 +1,168 @@
+// Package speech provides interfaces.SpeechToText implementations backed by
+// hosted speech APIs (OpenAI Whisper, Gemini, Deepgram), interfaces.TextToSpeech
+// implementations (OpenAI, ElevenLabs, Google Cloud) and helpers to turn audio
+// content parts into text for agents.
+package speech
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Option represents an option for configuring a speech backend
+type Option func(*config)
+
+type config struct {
+	model      string
+	voice      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// WithModel sets the speech model
+func WithModel(model string) Option {
+	return func(c *config) {
+		c.model = model
+	}
+}
+
+// WithVoice sets the default voice of a text-to-speech backend
+func WithVoice(voice string) Option {
+	return func(c *config) {
+		c.voice = voice
+	}
+}
+
+// WithBaseURL overrides the API base URL (useful for proxies and tests)
+func WithBaseURL(baseURL string) Option {
+	return func(c *config) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for API calls
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+func newConfig(defaultModel, defaultBaseURL string, options []Option) config {
+	return newVoiceConfig(defaultModel, "", defaultBaseURL, options)
+}
+
+func newVoiceConfig(defaultModel, defaultVoice, defaultBaseURL string, options []Option) config {
+	c := config{
+		model:      defaultModel,
+		voice:      defaultVoice,
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: 2 * time.Minute},
+	}
+	for _, option := range options {
+		option(&c)
+	}
+	return c
+}
+
+func transcribeOptions(options []interfaces.TranscribeOption) interfaces.TranscribeOptions {
+	var params interfaces.TranscribeOptions
+	for _, option := range options {
+		option(&params)
+	}
+	return params
+}
+
+// synthesizeOptions resolves options, using the backend's voice and mp3 by default
+func (c config) synthesizeOptions(options []interfaces.SynthesizeOption) interfaces.SynthesizeOptions {
+	params := interfaces.SynthesizeOptions{Voice: c.voice, Format: "mp3"}
+	for _, option := range options {
+		option(&params)
+	}
+	return params
+}
+
+// mimeTypes maps audio formats to their MIME types
+var mimeTypes = map[string]string{
+	"mp3":  "audio/mpeg",
+	"wav":  "audio/wav",
+	"opus": "audio/ogg",
+	"aac":  "audio/aac",
+	"flac": "audio/flac",
+	"pcm":  "audio/L16",
+}
+
+// do sends an API request and returns the response body, or an error for
+// non-200 responses
+func do(client *http.Client, req *http.Request, api string) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", api, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s API returned status %d: %s", api, resp.StatusCode, string(data))
+	}
+	return data, nil
+}
+
+// HasAudio returns true if parts contain an audio part
+func HasAudio(parts []interfaces.ContentPart) bool {
+	for _, part := range parts {
+		if part.Type == interfaces.ContentPartAudio {
+			return true
+		}
+	}
+	return false
+}
+
+// TranscribeParts replaces the audio parts of parts with text parts holding
+// their transcription, so speech reaches LLMs that do not take audio input.
+// Other parts are returned unchanged.
+func TranscribeParts(ctx context.Context, stt interfaces.SpeechToText, parts []interfaces.ContentPart, options ...interfaces.TranscribeOption) ([]interfaces.ContentPart, error) {
+	result := make([]interfaces.ContentPart, 0, len(parts))
+	for i, part := range parts {
+		if part.Type != interfaces.ContentPartAudio {
+			result = append(result, part)
+			continue
+		}
+		transcription, err := stt.Transcribe(ctx, part.Data, part.MIMEType, options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transcribe audio part %d: %w", i, err)
+		}
+		if text := strings.TrimSpace(transcription.Text); text != "" {
+			result = append(result, interfaces.TextPart(text))
+		}
+	}
+	return result, nil
+}
+
+// fileName returns a file name with an extension matching mimeType, which
+// APIs taking multipart uploads use to detect the audio format
+func fileName(mimeType string) string {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	switch mimeType {
+	case "audio/mpeg", "audio/mp3":
+		return "audio.mp3"
+	case "audio/mp4", "audio/m4a", "audio/x-m4a":
+		return "audio.m4a"
+	case "audio/ogg":
+		return "audio.ogg"
+	case "audio/webm":
+		return "audio.webm"
+	case "audio/flac", "audio/x-flac":
+		return "audio.flac"
+	default:
+		return "audio.wav"
+	}
+}
@@ -0,0 +1,236 @@
+package speech
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestWhisperTranscribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/transcriptions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing bearer token")
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("missing file: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != "mp3-bytes" || header.Filename != "audio.mp3" {
+			t.Errorf("unexpected file %q (%s)", data, header.Filename)
+		}
+		if r.FormValue("model") != DefaultWhisperModel || r.FormValue("language") != "de" {
+			t.Errorf("unexpected form %v", r.MultipartForm.Value)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":"Guten Tag"}`))
+	}))
+	defer server.Close()
+
+	stt := NewWhisperTranscriber("test-key", WithBaseURL(server.URL))
+	transcription, err := stt.Transcribe(context.Background(), []byte("mp3-bytes"), "audio/mpeg", interfaces.WithTranscriptionLanguage("de"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transcription.Text != "Guten Tag" || transcription.Language != "de" {
+		t.Errorf("unexpected transcription: %+v", transcription)
+	}
+}
+
+func TestDeepgramTranscribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/listen" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Token test-key" || r.Header.Get("Content-Type") != "audio/wav" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		if r.URL.Query().Get("model") != DefaultDeepgramModel || r.URL.Query().Get("detect_language") != "true" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"metadata":{"duration":2.5},"results":{"channels":[{"detected_language":"en","alternatives":[{"transcript":"hello world"}]}]}}`))
+	}))
+	defer server.Close()
+
+	stt := NewDeepgramTranscriber("test-key", WithBaseURL(server.URL))
+	transcription, err := stt.Transcribe(context.Background(), []byte("wav"), "audio/wav")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := interfaces.Transcription{Text: "hello world", Language: "en", Duration: 2500 * time.Millisecond}
+	if *transcription != want {
+		t.Errorf("unexpected transcription: %+v", transcription)
+	}
+}
+
+func TestDeepgramTranscribeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"err_msg":"bad audio"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	stt := NewDeepgramTranscriber("test-key", WithBaseURL(server.URL))
+	if _, err := stt.Transcribe(context.Background(), []byte("wav"), "audio/wav"); err == nil || !strings.Contains(err.Error(), "bad audio") {
+		t.Errorf("expected API error, got %v", err)
+	}
+}
+
+func TestGeminiTranscribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/models/"+DefaultGeminiModel+":generateContent") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"mimeType":"audio/ogg"`) {
+			t.Errorf("expected inline audio, got %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"  bonjour \n"}]}}]}`))
+	}))
+	defer server.Close()
+
+	stt, err := NewGeminiTranscriber(context.Background(), "test-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create transcriber: %v", err)
+	}
+	transcription, err := stt.Transcribe(context.Background(), []byte("ogg"), "audio/ogg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transcription.Text != "bonjour" {
+		t.Errorf("unexpected transcription: %+v", transcription)
+	}
+}
+
+type fakeTranscriber struct {
+	text string
+	err  error
+}
+
+func (f fakeTranscriber) Transcribe(context.Context, []byte, string, ...interfaces.TranscribeOption) (*interfaces.Transcription, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &interfaces.Transcription{Text: f.text}, nil
+}
+
+func TestTranscribeParts(t *testing.T) {
+	parts := []interfaces.ContentPart{
+		interfaces.ImageURLPart("https://example.com/cat.png"),
+		interfaces.AudioPart([]byte("wav"), "audio/wav"),
+	}
+
+	transcribed, err := TranscribeParts(context.Background(), fakeTranscriber{text: "what is this?"}, parts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(transcribed) != 2 || transcribed[0].Type != interfaces.ContentPartImage || transcribed[1].Text != "what is this?" {
+		t.Errorf("unexpected parts: %+v", transcribed)
+	}
+	if HasAudio(transcribed) {
+		t.Error("expected no audio parts left")
+	}
+
+	if _, err := TranscribeParts(context.Background(), fakeTranscriber{err: errors.New("boom")}, parts); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestOpenAISynthesize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/speech" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if body["voice"] != "nova" || body["model"] != DefaultOpenAISpeechModel || body["response_format"] != "wav" || body["input"] != "Hello" {
+			t.Errorf("unexpected request body: %v", body)
+		}
+		_, _ = w.Write([]byte("RIFF"))
+	}))
+	defer server.Close()
+
+	tts := NewOpenAISynthesizer("test-key", WithBaseURL(server.URL), WithVoice("nova"))
+	speech, err := tts.Synthesize(context.Background(), "Hello", interfaces.WithSpeechFormat("wav"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(speech.Data) != "RIFF" || speech.MIMEType != "audio/wav" {
+		t.Errorf("unexpected speech: %+v", speech)
+	}
+
+	if _, err := tts.Synthesize(context.Background(), "Hello", interfaces.WithSpeechFormat("midi")); err == nil {
+		t.Error("expected unsupported format error")
+	}
+}
+
+func TestElevenLabsSynthesize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/text-to-speech/voice-1" || r.URL.Query().Get("output_format") != "mp3_44100_128" {
+			t.Errorf("unexpected URL %s", r.URL)
+		}
+		if r.Header.Get("xi-api-key") != "test-key" {
+			t.Errorf("missing API key")
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if body["text"] != "Hello" || body["model_id"] != DefaultElevenLabsModel {
+			t.Errorf("unexpected request body: %v", body)
+		}
+		_, _ = w.Write([]byte("ID3"))
+	}))
+	defer server.Close()
+
+	tts := NewElevenLabsSynthesizer("test-key", WithBaseURL(server.URL))
+	speech, err := tts.Synthesize(context.Background(), "Hello", interfaces.WithSpeechVoice("voice-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(speech.Data) != "ID3" || speech.MIMEType != "audio/mpeg" {
+		t.Errorf("unexpected speech: %+v", speech)
+	}
+}
+
+func TestGoogleSynthesize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/text:synthesize" || r.URL.Query().Get("key") != "test-key" {
+			t.Errorf("unexpected URL %s", r.URL)
+		}
+		var body struct {
+			Voice       map[string]string      `json:"voice"`
+			AudioConfig map[string]interface{} `json:"audioConfig"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if body.Voice["languageCode"] != "de-DE" || body.AudioConfig["audioEncoding"] != "OGG_OPUS" || body.AudioConfig["speakingRate"] != 1.2 {
+			t.Errorf("unexpected request body: %+v", body)
+		}
+		_, _ = w.Write([]byte(`{"audioContent":"T2dnUw=="}`))
+	}))
+	defer server.Close()
+
+	tts := NewGoogleSynthesizer("test-key", WithBaseURL(server.URL), WithVoice("de-DE-Neural2-B"))
+	speech, err := tts.Synthesize(context.Background(), "Hallo", interfaces.WithSpeechFormat("opus"), interfaces.WithSpeechSpeed(1.2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(speech.Data) != "OggS" || speech.MIMEType != "audio/ogg" {
+		t.Errorf("unexpected speech: %+v", speech)
+	}
+}
@@ -0,0 +1,62 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const (
+	// DefaultWhisperModel is the default OpenAI transcription model
+	DefaultWhisperModel = "whisper-1"
+
+	// DefaultOpenAIBaseURL is the OpenAI API base URL
+	DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+)
+
+// WhisperTranscriber implements interfaces.SpeechToText using the OpenAI
+// transcription API (whisper-1, gpt-4o-transcribe, gpt-4o-mini-transcribe)
+type WhisperTranscriber struct {
+	client openai.Client
+	config config
+}
+
+// NewWhisperTranscriber creates a new OpenAI Whisper transcriber
+func NewWhisperTranscriber(apiKey string, options ...Option) *WhisperTranscriber {
+	c := newConfig(DefaultWhisperModel, DefaultOpenAIBaseURL, options)
+	return &WhisperTranscriber{
+		client: openai.NewClient(
+			option.WithAPIKey(apiKey),
+			option.WithBaseURL(c.baseURL),
+			option.WithHTTPClient(c.httpClient),
+		),
+		config: c,
+	}
+}
+
+// Transcribe implements interfaces.SpeechToText.Transcribe
+func (t *WhisperTranscriber) Transcribe(ctx context.Context, audio []byte, mimeType string, options ...interfaces.TranscribeOption) (*interfaces.Transcription, error) {
+	params := transcribeOptions(options)
+
+	request := openai.AudioTranscriptionNewParams{
+		File:  openai.File(bytes.NewReader(audio), fileName(mimeType), mimeType),
+		Model: openai.AudioModel(t.config.model),
+	}
+	if params.Language != "" {
+		request.Language = openai.String(params.Language)
+	}
+	if params.Prompt != "" {
+		request.Prompt = openai.String(params.Prompt)
+	}
+
+	transcription, err := t.client.Audio.Transcriptions.New(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("whisper transcription failed: %w", err)
+	}
+	return &interfaces.Transcription{Text: transcription.Text, Language: params.Language}, nil
+}
@@ -0,0 +1,418 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// ArtifactStore generalizes MediaStorage to artifacts of any MIME type:
+// stored files are indexed with their metadata so they can be listed per
+// conversation, deleted by ID and expired by a retention policy. Media stored
+// through the MediaStorage methods is indexed as well, so an ArtifactStore
+// can be passed to the image, video and speech tools directly.
+//
+// In a context with an organization ID, only the artifacts of that
+// organization are visible: others are not found by ID, not listed, and
+// multitenancy.ErrCrossTenantAccess is returned for their URLs.
+//
+// Every stored artifact is attached to the current agent run (see
+// AttachArtifact) and returned in the response's Artifacts.
+type ArtifactStore interface {
+	MediaStorage
+
+	// Save stores data as an artifact. ID, URL, Size and CreatedAt are set by
+	// the store; OrgID and ThreadID default to those of the context.
+	Save(ctx context.Context, data []byte, artifact interfaces.Artifact) (*interfaces.Artifact, error)
+
+	// Artifact returns an artifact by ID
+	Artifact(ctx context.Context, id string) (*interfaces.Artifact, error)
+
+	// List returns the artifacts matching filter, newest first
+	List(ctx context.Context, filter ArtifactFilter) ([]interfaces.Artifact, error)
+
+	// DeleteArtifact removes an artifact and its content by ID
+	DeleteArtifact(ctx context.Context, id string) error
+
+	// ApplyRetention deletes the artifacts that violate the retention policy
+	// and returns how many were deleted
+	ApplyRetention(ctx context.Context) (int, error)
+}
+
+// ArtifactFilter selects artifacts in List. Empty fields match all artifacts.
+type ArtifactFilter struct {
+	// OrgID matches the organization of the artifact
+	OrgID string
+
+	// ThreadID matches the conversation of the artifact
+	ThreadID string
+
+	// MimeType matches a MIME type or, when ending in "/", a MIME type prefix such as "image/"
+	MimeType string
+
+	// Tool matches the tool that produced the artifact
+	Tool string
+}
+
+// RetentionPolicy limits how long and how many artifacts are kept
+type RetentionPolicy struct {
+	// MaxAge deletes artifacts older than this duration (0 keeps them indefinitely)
+	MaxAge time.Duration
+
+	// MaxPerThread keeps only the newest artifacts of each conversation (0 for no limit)
+	MaxPerThread int
+}
+
+// ErrArtifactNotFound indicates no artifact exists with the given ID
+var ErrArtifactNotFound = errors.New("artifact not found")
+
+// ArtifactStoreOption configures an artifact store
+type ArtifactStoreOption func(*artifactStore)
+
+// WithRetentionPolicy sets the retention policy, which is applied whenever an
+// artifact is saved and by ApplyRetention
+func WithRetentionPolicy(policy RetentionPolicy) ArtifactStoreOption {
+	return func(s *artifactStore) {
+		s.retention = policy
+	}
+}
+
+// artifactStore indexes the artifacts of a MediaStorage backend in memory
+type artifactStore struct {
+	backend   MediaStorage
+	retention RetentionPolicy
+
+	mu        sync.RWMutex
+	artifacts map[string]*interfaces.Artifact
+	byURL     map[string]string
+}
+
+// NewArtifactStore creates an artifact store that saves content to backend
+// (local, GCS, S3, Azure Blob, ...) and keeps the artifact index in memory
+func NewArtifactStore(backend MediaStorage, options ...ArtifactStoreOption) ArtifactStore {
+	s := &artifactStore{
+		backend:   backend,
+		artifacts: make(map[string]*interfaces.Artifact),
+		byURL:     make(map[string]string),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Name returns the name of the backend
+func (s *artifactStore) Name() string {
+	return s.backend.Name()
+}
+
+// Store saves media through the backend and indexes it as an artifact
+func (s *artifactStore) Store(ctx context.Context, media *interfaces.GeneratedMedia, metadata StorageMetadata) (string, error) {
+	if media == nil {
+		return "", fmt.Errorf("media is nil")
+	}
+
+	artifact := interfaces.Artifact{
+		MimeType:  media.MimeType,
+		OrgID:     metadata.OrgID,
+		ThreadID:  metadata.ThreadID,
+		MessageID: metadata.MessageID,
+		Tool:      metadata.Tool,
+		CreatedAt: metadata.CreatedAt,
+	}
+	if artifact.MimeType == "" {
+		// Backends store media without a type as PNG
+		artifact.MimeType = "image/png"
+	}
+	if metadata.Prompt != "" || len(metadata.Tags) > 0 {
+		artifact.Metadata = make(map[string]string, len(metadata.Tags)+1)
+		for key, value := range metadata.Tags {
+			artifact.Metadata[key] = value
+		}
+		if metadata.Prompt != "" {
+			artifact.Metadata["prompt"] = metadata.Prompt
+		}
+	}
+
+	saved, err := s.Save(ctx, media.Data, artifact)
+	if err != nil {
+		return "", err
+	}
+	return saved.URL, nil
+}
+
+// Save stores data through the backend and indexes the artifact
+func (s *artifactStore) Save(ctx context.Context, data []byte, artifact interfaces.Artifact) (*interfaces.Artifact, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("artifact data is empty")
+	}
+	if artifact.MimeType == "" {
+		return nil, fmt.Errorf("artifact MIME type is required")
+	}
+	orgID, err := ResolveOrgID(ctx, artifact.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	artifact.OrgID = orgID
+	if artifact.ThreadID == "" {
+		artifact.ThreadID, _ = memory.GetConversationID(ctx)
+	}
+	if artifact.CreatedAt.IsZero() {
+		artifact.CreatedAt = time.Now()
+	}
+
+	url, err := s.backend.Store(ctx, &interfaces.GeneratedMedia{Data: data, MimeType: artifact.MimeType}, StorageMetadata{
+		OrgID:     artifact.OrgID,
+		ThreadID:  artifact.ThreadID,
+		MessageID: artifact.MessageID,
+		Prompt:    artifact.Metadata["prompt"],
+		Tool:      artifact.Tool,
+		CreatedAt: artifact.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	artifact.ID = uuid.New().String()
+	artifact.URL = url
+	artifact.Size = int64(len(data))
+
+	s.mu.Lock()
+	s.artifacts[artifact.ID] = &artifact
+	s.byURL[url] = artifact.ID
+	s.mu.Unlock()
+
+	AttachArtifact(ctx, artifact)
+
+	if _, err := s.ApplyRetention(ctx); err != nil {
+		return nil, fmt.Errorf("failed to apply retention policy: %w", err)
+	}
+	return &artifact, nil
+}
+
+// Get retrieves content by URL from the backend
+func (s *artifactStore) Get(ctx context.Context, url string) ([]byte, error) {
+	if err := s.checkURL(ctx, url); err != nil {
+		return nil, err
+	}
+	return s.backend.Get(ctx, url)
+}
+
+// Delete removes content by URL and drops its artifact from the index
+func (s *artifactStore) Delete(ctx context.Context, url string) error {
+	if err := s.checkURL(ctx, url); err != nil {
+		return err
+	}
+	if err := s.backend.Delete(ctx, url); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if id, ok := s.byURL[url]; ok {
+		delete(s.artifacts, id)
+		delete(s.byURL, url)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// Artifact returns an artifact by ID
+func (s *artifactStore) Artifact(ctx context.Context, id string) (*interfaces.Artifact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	artifact, ok := s.artifacts[id]
+	if !ok || !visibleTo(ctx, artifact) {
+		return nil, ErrArtifactNotFound
+	}
+	found := *artifact
+	return &found, nil
+}
+
+// List returns the artifacts matching filter, newest first
+func (s *artifactStore) List(ctx context.Context, filter ArtifactFilter) ([]interfaces.Artifact, error) {
+	orgID, err := ResolveOrgID(ctx, filter.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	filter.OrgID = orgID
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	artifacts := make([]interfaces.Artifact, 0)
+	for _, artifact := range s.artifacts {
+		if filter.matches(artifact) {
+			artifacts = append(artifacts, *artifact)
+		}
+	}
+	sortNewestFirst(artifacts)
+	return artifacts, nil
+}
+
+// DeleteArtifact removes an artifact and its content by ID
+func (s *artifactStore) DeleteArtifact(ctx context.Context, id string) error {
+	artifact, err := s.Artifact(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.Delete(ctx, artifact.URL)
+}
+
+// ApplyRetention deletes artifacts older than MaxAge and those beyond the
+// MaxPerThread newest of their conversation. In a context with an
+// organization ID, only the artifacts of that organization are considered.
+func (s *artifactStore) ApplyRetention(ctx context.Context) (int, error) {
+	if s.retention.MaxAge <= 0 && s.retention.MaxPerThread <= 0 {
+		return 0, nil
+	}
+
+	s.mu.RLock()
+	artifacts := make([]interfaces.Artifact, 0, len(s.artifacts))
+	for _, artifact := range s.artifacts {
+		if visibleTo(ctx, artifact) {
+			artifacts = append(artifacts, *artifact)
+		}
+	}
+	s.mu.RUnlock()
+	sortNewestFirst(artifacts)
+
+	var expired []string
+	perThread := make(map[string]int)
+	for _, artifact := range artifacts {
+		key := artifact.OrgID + "/" + artifact.ThreadID
+		perThread[key]++
+		switch {
+		case s.retention.MaxAge > 0 && time.Since(artifact.CreatedAt) > s.retention.MaxAge:
+			expired = append(expired, artifact.URL)
+		case s.retention.MaxPerThread > 0 && perThread[key] > s.retention.MaxPerThread:
+			expired = append(expired, artifact.URL)
+		}
+	}
+
+	for i, url := range expired {
+		if err := s.Delete(ctx, url); err != nil {
+			return i, err
+		}
+	}
+	return len(expired), nil
+}
+
+// checkURL returns multitenancy.ErrCrossTenantAccess when the URL is that of
+// an artifact of another organization than the context's
+func (s *artifactStore) checkURL(ctx context.Context, url string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.byURL[url]
+	if ok && !visibleTo(ctx, s.artifacts[id]) {
+		return fmt.Errorf("%w: %s", multitenancy.ErrCrossTenantAccess, url)
+	}
+	return nil
+}
+
+// visibleTo returns true if the context has no organization ID or that of
+// the artifact
+func visibleTo(ctx context.Context, artifact *interfaces.Artifact) bool {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	return err != nil || artifact.OrgID == orgID
+}
+
+// matches returns true if the artifact matches all fields of the filter
+func (f ArtifactFilter) matches(artifact *interfaces.Artifact) bool {
+	if f.OrgID != "" && artifact.OrgID != f.OrgID {
+		return false
+	}
+	if f.ThreadID != "" && artifact.ThreadID != f.ThreadID {
+		return false
+	}
+	if f.Tool != "" && artifact.Tool != f.Tool {
+		return false
+	}
+	if strings.HasSuffix(f.MimeType, "/") {
+		return strings.HasPrefix(artifact.MimeType, f.MimeType)
+	}
+	return f.MimeType == "" || artifact.MimeType == f.MimeType
+}
+
+// sortNewestFirst sorts artifacts by creation time, newest first
+func sortNewestFirst(artifacts []interfaces.Artifact) {
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		if artifacts[i].CreatedAt.Equal(artifacts[j].CreatedAt) {
+			return artifacts[i].ID < artifacts[j].ID
+		}
+		return artifacts[i].CreatedAt.After(artifacts[j].CreatedAt)
+	})
+}
+
+// artifactContextKey is the context key type of this package
+type artifactContextKey int
+
+const (
+	artifactCollectorKey artifactContextKey = iota
+	artifactStoreKey
+)
+
+// artifactCollector gathers the artifacts attached during a run; artifacts
+// attached in nested runs (e.g. sub-agents) are also attached to the parent
+type artifactCollector struct {
+	parent    *artifactCollector
+	mu        sync.Mutex
+	artifacts []interfaces.Artifact
+}
+
+// WithArtifactCollector returns a context in which attached artifacts are
+// collected, for CollectedArtifacts to return them
+func WithArtifactCollector(ctx context.Context) context.Context {
+	parent, _ := ctx.Value(artifactCollectorKey).(*artifactCollector)
+	return context.WithValue(ctx, artifactCollectorKey, &artifactCollector{parent: parent})
+}
+
+// AttachArtifact attaches an artifact to the run of the context, so it is
+// returned with the agent's response. Artifact stores attach the artifacts
+// they save; tools that store files elsewhere can attach them directly.
+func AttachArtifact(ctx context.Context, artifact interfaces.Artifact) {
+	collector, _ := ctx.Value(artifactCollectorKey).(*artifactCollector)
+	for ; collector != nil; collector = collector.parent {
+		collector.mu.Lock()
+		collector.artifacts = append(collector.artifacts, artifact)
+		collector.mu.Unlock()
+	}
+}
+
+// CollectedArtifacts returns the artifacts attached in a context created by
+// WithArtifactCollector
+func CollectedArtifacts(ctx context.Context) []interfaces.Artifact {
+	collector, _ := ctx.Value(artifactCollectorKey).(*artifactCollector)
+	if collector == nil {
+		return nil
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if len(collector.artifacts) == 0 {
+		return nil
+	}
+	return append([]interfaces.Artifact(nil), collector.artifacts...)
+}
+
+// WithArtifactStore returns a context carrying an artifact store, which
+// agents set so their tools can save artifacts
+func WithArtifactStore(ctx context.Context, store ArtifactStore) context.Context {
+	return context.WithValue(ctx, artifactStoreKey, store)
+}
+
+// ArtifactStoreFromContext returns the artifact store of the context, if any
+func ArtifactStoreFromContext(ctx context.Context) (ArtifactStore, bool) {
+	store, ok := ctx.Value(artifactStoreKey).(ArtifactStore)
+	return store, ok
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// memoryBackend is a MediaStorage keeping content in memory
+type memoryBackend struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	next  int
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{files: make(map[string][]byte)}
+}
+
+func (b *memoryBackend) Store(ctx context.Context, media *interfaces.GeneratedMedia, metadata StorageMetadata) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.next++
+	url := fmt.Sprintf("mem://%s/%d", metadata.ThreadID, b.next)
+	b.files[url] = media.Data
+	return url, nil
+}
+
+func (b *memoryBackend) Delete(ctx context.Context, url string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.files, url)
+	return nil
+}
+
+func (b *memoryBackend) Get(ctx context.Context, url string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.files[url]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return data, nil
+}
+
+func (b *memoryBackend) Name() string {
+	return "memory"
+}
+
+func TestArtifactStore(t *testing.T) {
+	backend := newMemoryBackend()
+	store := NewArtifactStore(backend)
+	ctx := multitenancy.WithOrgID(memory.WithConversationID(context.Background(), "conv-1"), "org-1")
+
+	report, err := store.Save(ctx, []byte("a,b\n1,2\n"), interfaces.Artifact{Name: "report.csv", MimeType: "text/csv", Tool: "export"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.ID == "" || report.Size != 8 || report.OrgID != "org-1" || report.ThreadID != "conv-1" || report.CreatedAt.IsZero() {
+		t.Errorf("unexpected artifact %+v", report)
+	}
+
+	// Media stored through the MediaStorage interface is indexed too
+	url, err := store.Store(ctx, &interfaces.GeneratedMedia{Data: []byte("png"), MimeType: "image/png"}, StorageMetadata{
+		ThreadID: "conv-2",
+		Prompt:   "a cat",
+		Tool:     "generate_image",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	images, _ := store.List(ctx, ArtifactFilter{MimeType: "image/"})
+	if len(images) != 1 || images[0].URL != url || images[0].Tool != "generate_image" || images[0].Metadata["prompt"] != "a cat" {
+		t.Errorf("unexpected image artifacts %+v", images)
+	}
+	conversation, _ := store.List(ctx, ArtifactFilter{OrgID: "org-1", ThreadID: "conv-1"})
+	if len(conversation) != 1 || conversation[0].ID != report.ID {
+		t.Errorf("unexpected conversation artifacts %+v", conversation)
+	}
+
+	data, err := store.Get(ctx, report.URL)
+	if err != nil || string(data) != "a,b\n1,2\n" {
+		t.Errorf("Get() = %q, %v", data, err)
+	}
+
+	if err := store.DeleteArtifact(ctx, report.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := store.Artifact(ctx, report.ID); !errors.Is(err, ErrArtifactNotFound) {
+		t.Errorf("expected ErrArtifactNotFound, got %v", err)
+	}
+	if _, ok := backend.files[report.URL]; ok {
+		t.Error("expected the content to be deleted from the backend")
+	}
+
+	if _, err := store.Save(ctx, []byte("x"), interfaces.Artifact{}); err == nil {
+		t.Error("expected an error without a MIME type")
+	}
+}
+
+func TestArtifactRetention(t *testing.T) {
+	store := NewArtifactStore(newMemoryBackend(), WithRetentionPolicy(RetentionPolicy{MaxAge: time.Hour, MaxPerThread: 2}))
+	ctx := context.Background()
+
+	old, err := store.Save(ctx, []byte("old"), interfaces.Artifact{MimeType: "text/plain", ThreadID: "conv-2", CreatedAt: time.Now().Add(-2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := store.Artifact(ctx, old.ID); !errors.Is(err, ErrArtifactNotFound) {
+		t.Error("expected an artifact older than MaxAge to be deleted")
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := store.Save(ctx, []byte{byte(i)}, interfaces.Artifact{MimeType: "text/plain", ThreadID: "conv-1", CreatedAt: time.Now().Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	artifacts, _ := store.List(ctx, ArtifactFilter{ThreadID: "conv-1"})
+	if len(artifacts) != 2 || artifacts[0].Size != 1 || artifacts[0].CreatedAt.Before(artifacts[1].CreatedAt) {
+		t.Errorf("expected the 2 newest artifacts, got %+v", artifacts)
+	}
+}
+
+func TestAttachArtifact(t *testing.T) {
+	store := NewArtifactStore(newMemoryBackend())
+
+	// Artifacts of nested runs are attached to the parent run as well
+	parent := WithArtifactCollector(context.Background())
+	child := WithArtifactCollector(parent)
+	if _, err := store.Save(child, []byte("a"), interfaces.Artifact{MimeType: "text/plain"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	AttachArtifact(parent, interfaces.Artifact{ID: "external", URL: "https://example.com/file.pdf"})
+
+	if artifacts := CollectedArtifacts(child); len(artifacts) != 1 {
+		t.Errorf("expected 1 artifact in the child run, got %d", len(artifacts))
+	}
+	if artifacts := CollectedArtifacts(parent); len(artifacts) != 2 || artifacts[1].ID != "external" {
+		t.Errorf("expected 2 artifacts in the parent run, got %+v", artifacts)
+	}
+	if artifacts := CollectedArtifacts(context.Background()); artifacts != nil {
+		t.Errorf("expected no artifacts without a collector, got %+v", artifacts)
+	}
+
+	ctx := WithArtifactStore(context.Background(), store)
+	if found, ok := ArtifactStoreFromContext(ctx); !ok || found != store {
+		t.Error("expected the artifact store of the context")
+	}
+}
@@ -0,0 +1,503 @@
+package azblob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+func init() {
+	// Register the Azure Blob storage factory
+	storage.NewAzureBlobStorage = New
+}
+
+const (
+	// apiVersion is the Blob service REST API version used for requests and SAS tokens
+	apiVersion = "2020-12-06"
+
+	// devStoreAccount and devStoreKey are the well-known Azurite emulator credentials
+	devStoreAccount = "devstoreaccount1"
+	devStoreKey     = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+// Storage implements MediaStorage for Azure Blob Storage. Requests are
+// authorized with the account's shared key, which also signs SAS URLs.
+type Storage struct {
+	httpClient    *http.Client
+	accountName   string
+	accountKey    []byte
+	endpoint      string
+	container     string
+	prefix        string
+	sasExpiration time.Duration
+	useSASURLs    bool
+}
+
+// New creates a new Azure Blob storage backend
+func New(cfg storage.AzureBlobConfig) (storage.ImageStorage, error) {
+	if cfg.ConnectionString != "" {
+		var err error
+		if cfg, err = applyConnectionString(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.AccountName == "" || cfg.AccountKey == "" {
+		return nil, fmt.Errorf("azure storage account name and key are required")
+	}
+	if cfg.Container == "" {
+		return nil, fmt.Errorf("azure blob container name is required")
+	}
+	accountKey, err := base64.StdEncoding.DecodeString(cfg.AccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure storage account key: %w", err)
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", cfg.AccountName)
+	}
+
+	s := &Storage{
+		httpClient:    &http.Client{Timeout: 5 * time.Minute},
+		accountName:   cfg.AccountName,
+		accountKey:    accountKey,
+		endpoint:      strings.TrimSuffix(endpoint, "/"),
+		container:     cfg.Container,
+		prefix:        strings.Trim(cfg.Prefix, "/"),
+		sasExpiration: cfg.SASExpiration,
+		useSASURLs:    cfg.UseSASURLs,
+	}
+
+	// Set defaults
+	if s.sasExpiration == 0 {
+		s.sasExpiration = 24 * time.Hour
+	}
+
+	return s, nil
+}
+
+// applyConnectionString sets the account name, key and endpoint from an
+// Azure Storage connection string
+func applyConnectionString(cfg storage.AzureBlobConfig) (storage.AzureBlobConfig, error) {
+	settings := make(map[string]string)
+	for _, part := range strings.Split(cfg.ConnectionString, ";") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			settings[key] = value
+		}
+	}
+
+	if settings["UseDevelopmentStorage"] == "true" {
+		cfg.AccountName = devStoreAccount
+		cfg.AccountKey = devStoreKey
+		cfg.Endpoint = "http://127.0.0.1:10000/" + devStoreAccount
+		return cfg, nil
+	}
+
+	cfg.AccountName = settings["AccountName"]
+	cfg.AccountKey = settings["AccountKey"]
+	if cfg.AccountName == "" || cfg.AccountKey == "" {
+		return cfg, fmt.Errorf("azure storage connection string must contain AccountName and AccountKey")
+	}
+	switch {
+	case settings["BlobEndpoint"] != "":
+		cfg.Endpoint = settings["BlobEndpoint"]
+	case settings["EndpointSuffix"] != "":
+		protocol := settings["DefaultEndpointsProtocol"]
+		if protocol == "" {
+			protocol = "https"
+		}
+		cfg.Endpoint = fmt.Sprintf("%s://%s.blob.%s", protocol, cfg.AccountName, settings["EndpointSuffix"])
+	}
+	return cfg, nil
+}
+
+// Name returns the storage backend name
+func (s *Storage) Name() string {
+	return "azblob"
+}
+
+// Store saves media to Azure Blob Storage and returns an accessible URL. The
+// container is created on first use if it does not exist.
+func (s *Storage) Store(ctx context.Context, image *interfaces.GeneratedImage, metadata storage.StorageMetadata) (string, error) {
+	if image == nil || len(image.Data) == 0 {
+		return "", fmt.Errorf("image data is empty")
+	}
+
+	orgID, err := storage.ResolveOrgID(ctx, metadata.OrgID)
+	if err != nil {
+		return "", err
+	}
+
+	// Build blob name: prefix/orgID/threadID/timestamp_hash.ext
+	name := s.prefix
+	if orgID != "" {
+		name = joinPath(name, storage.OrgPathSegment(orgID))
+	}
+	if metadata.ThreadID != "" {
+		name = joinPath(name, sanitizePath(metadata.ThreadID))
+	}
+
+	// Generate filename: timestamp_hash.ext
+	filename := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), hashData(image.Data)[:12], getExtension(image.MimeType))
+	name = joinPath(name, filename)
+
+	// Metadata names must be valid C# identifiers and values ASCII
+	headers := http.Header{}
+	headers.Set("Content-Type", image.MimeType)
+	headers.Set("x-ms-blob-type", "BlockBlob")
+	headers.Set("x-ms-meta-prompt", url.QueryEscape(truncateString(metadata.Prompt, 500)))
+	if metadata.OrgID != "" {
+		headers.Set("x-ms-meta-org_id", url.QueryEscape(metadata.OrgID))
+	}
+	if metadata.ThreadID != "" {
+		headers.Set("x-ms-meta-thread_id", url.QueryEscape(metadata.ThreadID))
+	}
+	if metadata.MessageID != "" {
+		headers.Set("x-ms-meta-message_id", url.QueryEscape(metadata.MessageID))
+	}
+
+	status, errorCode, _, err := s.do(ctx, http.MethodPut, s.blobURL(name), headers, image.Data)
+	if err == nil && status == http.StatusNotFound && errorCode == "ContainerNotFound" {
+		if err = s.createContainer(ctx); err == nil {
+			status, errorCode, _, err = s.do(ctx, http.MethodPut, s.blobURL(name), headers, image.Data)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to upload to Azure Blob Storage: %w", err)
+	}
+	if status != http.StatusCreated {
+		return "", fmt.Errorf("failed to upload to Azure Blob Storage: status %d (%s)", status, errorCode)
+	}
+
+	if s.useSASURLs {
+		return s.sasURL(name, "r", time.Now().Add(s.sasExpiration)), nil
+	}
+	return s.blobURL(name), nil
+}
+
+// SignedUploadURL returns a SAS URL for uploading the blob at key under the
+// prefix. The container is created first, since direct uploads cannot create it.
+func (s *Storage) SignedUploadURL(ctx context.Context, key, contentType string, expiration time.Duration) (*storage.UploadTarget, error) {
+	if err := storage.CheckOrgKey(ctx, "", key); err != nil {
+		return nil, err
+	}
+	if err := s.createContainer(ctx); err != nil {
+		return nil, err
+	}
+
+	expires := time.Now().Add(expiration)
+	return &storage.UploadTarget{
+		URL:    s.sasURL(joinPath(s.prefix, key), "cw", expires),
+		Method: http.MethodPut,
+		Headers: map[string]string{
+			"Content-Type":   contentType,
+			"x-ms-blob-type": "BlockBlob",
+		},
+		ExpiresAt: expires,
+	}, nil
+}
+
+// SignedDownloadURL returns a read-only SAS URL for the blob at key under the prefix
+func (s *Storage) SignedDownloadURL(ctx context.Context, key string, expiration time.Duration) (string, error) {
+	if err := storage.CheckOrgKey(ctx, "", key); err != nil {
+		return "", err
+	}
+	return s.sasURL(joinPath(s.prefix, key), "r", time.Now().Add(expiration)), nil
+}
+
+// createContainer creates the container, succeeding if it already exists
+func (s *Storage) createContainer(ctx context.Context) error {
+	status, errorCode, _, err := s.do(ctx, http.MethodPut, s.endpoint+"/"+url.PathEscape(s.container)+"?restype=container", http.Header{}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+	if status != http.StatusCreated && status != http.StatusConflict {
+		return fmt.Errorf("failed to create container: status %d (%s)", status, errorCode)
+	}
+	return nil
+}
+
+// Delete removes media from Azure Blob Storage
+func (s *Storage) Delete(ctx context.Context, url string) error {
+	name := s.urlToBlobName(url)
+	if name == "" {
+		return fmt.Errorf("invalid URL or blob name")
+	}
+	if err := storage.CheckOrgKey(ctx, s.prefix, name); err != nil {
+		return err
+	}
+
+	status, errorCode, _, err := s.do(ctx, http.MethodDelete, s.blobURL(name), http.Header{}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete from Azure Blob Storage: %w", err)
+	}
+	if status == http.StatusNotFound {
+		return nil // Already deleted
+	}
+	if status != http.StatusAccepted {
+		return fmt.Errorf("failed to delete from Azure Blob Storage: status %d (%s)", status, errorCode)
+	}
+	return nil
+}
+
+// Get retrieves media data from Azure Blob Storage
+func (s *Storage) Get(ctx context.Context, url string) ([]byte, error) {
+	name := s.urlToBlobName(url)
+	if name == "" {
+		return nil, fmt.Errorf("invalid URL or blob name")
+	}
+	if err := storage.CheckOrgKey(ctx, s.prefix, name); err != nil {
+		return nil, err
+	}
+
+	status, errorCode, data, err := s.do(ctx, http.MethodGet, s.blobURL(name), http.Header{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from Azure Blob Storage: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to read from Azure Blob Storage: status %d (%s)", status, errorCode)
+	}
+	return data, nil
+}
+
+// do sends a request authorized with the shared key and returns the status,
+// the x-ms-error-code header and the response body
+func (s *Storage) do(ctx context.Context, method, rawURL string, headers http.Header, body []byte) (int, string, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = headers
+	req.ContentLength = int64(len(body))
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", apiVersion)
+	req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", s.accountName, s.sign(s.stringToSign(req))))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp.StatusCode, resp.Header.Get("x-ms-error-code"), data, nil
+}
+
+// stringToSign builds the Shared Key string-to-sign of a Blob service request
+func (s *Storage) stringToSign(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	var b strings.Builder
+	for _, value := range []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date is sent as x-ms-date
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	} {
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+
+	// Canonicalized headers: x-ms-* headers, lowercased and sorted
+	var names []string
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-ms-") {
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+
+	// Canonicalized resource: /account/path followed by the sorted query parameters
+	b.WriteString("/" + s.accountName + req.URL.EscapedPath())
+	query := req.URL.Query()
+	var params []string
+	for name := range query {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	for _, name := range params {
+		values := query[name]
+		sort.Strings(values)
+		b.WriteString("\n" + strings.ToLower(name) + ":" + strings.Join(values, ","))
+	}
+	return b.String()
+}
+
+// sasURL returns a blob URL with a service SAS token granting permissions,
+// e.g. "r" to read or "cw" to upload
+func (s *Storage) sasURL(name, permissions string, expiry time.Time) string {
+	signedExpiry := expiry.UTC().Format("2006-01-02T15:04:05Z")
+	fields := []string{
+		permissions,  // signed permissions
+		"",           // signed start
+		signedExpiry, // signed expiry
+		"/blob/" + s.accountName + "/" + s.container + "/" + name,
+		"",                 // signed identifier
+		"",                 // signed IP
+		"",                 // signed protocol
+		apiVersion,         // signed version
+		"b",                // signed resource
+		"",                 // signed snapshot time
+		"",                 // signed encryption scope
+		"", "", "", "", "", // response headers (rscc, rscd, rsce, rscl, rsct)
+	}
+
+	query := url.Values{}
+	query.Set("sv", apiVersion)
+	query.Set("se", signedExpiry)
+	query.Set("sr", "b")
+	query.Set("sp", permissions)
+	query.Set("sig", s.sign(strings.Join(fields, "\n")))
+	return s.blobURL(name) + "?" + query.Encode()
+}
+
+// sign returns the base64 HMAC-SHA256 signature of a string with the account key
+func (s *Storage) sign(stringToSign string) string {
+	mac := hmac.New(sha256.New, s.accountKey)
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// blobURL returns the URL of a blob
+func (s *Storage) blobURL(name string) string {
+	return s.endpoint + "/" + url.PathEscape(s.container) + "/" + escapeBlobName(name)
+}
+
+// urlToBlobName extracts the blob name from a URL or returns a name as-is
+func (s *Storage) urlToBlobName(rawURL string) string {
+	// Handle direct blob names
+	if !strings.HasPrefix(rawURL, "http") {
+		return rawURL
+	}
+
+	// Remove query parameters (for SAS URLs)
+	if idx := strings.Index(rawURL, "?"); idx != -1 {
+		rawURL = rawURL[:idx]
+	}
+
+	base := s.endpoint + "/" + url.PathEscape(s.container) + "/"
+	if !strings.HasPrefix(rawURL, base) {
+		return ""
+	}
+	name, err := url.PathUnescape(strings.TrimPrefix(rawURL, base))
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// escapeBlobName escapes each segment of a blob name for use in a URL path
+func escapeBlobName(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// getExtension returns the file extension for a MIME type
+func getExtension(mimeType string) string {
+	switch mimeType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg", "image/jpg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "audio/mpeg":
+		return ".mp3"
+	case "audio/wav":
+		return ".wav"
+	case "audio/ogg":
+		return ".ogg"
+	case "audio/aac":
+		return ".aac"
+	case "audio/flac":
+		return ".flac"
+	case "audio/L16":
+		return ".pcm"
+	case "video/mp4":
+		return ".mp4"
+	case "video/webm":
+		return ".webm"
+	case "video/quicktime":
+		return ".mov"
+	default:
+		// Other artifacts use the registered extension of their type
+		if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+			return exts[0]
+		}
+		return ".png"
+	}
+}
+
+// hashData returns a SHA256 hash of the data
+func hashData(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// sanitizePath removes potentially dangerous characters from path components
+func sanitizePath(s string) string {
+	s = strings.ReplaceAll(s, "..", "_")
+	s = strings.ReplaceAll(s, "/", "_")
+	s = strings.ReplaceAll(s, "\\", "_")
+	s = strings.ReplaceAll(s, ":", "_")
+	return s
+}
+
+// joinPath joins path components with forward slashes
+func joinPath(base, path string) string {
+	if base == "" {
+		return path
+	}
+	if path == "" {
+		return base
+	}
+	return base + "/" + path
+}
+
+// truncateString truncates a string to maxLen characters
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen]
+}
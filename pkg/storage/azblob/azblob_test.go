@@ -0,0 +1,194 @@
+package azblob
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// fakeBlobService stores blobs in memory, like a minimal Blob service
+type fakeBlobService struct {
+	mu         sync.Mutex
+	containers map[string]bool
+	blobs      map[string][]byte
+	headers    http.Header
+}
+
+func (f *fakeBlobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey devstoreaccount1:") || r.Header.Get("x-ms-version") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	// Paths are /account/container[/blob]
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/devstoreaccount1/"), "/", 2)
+	if r.URL.Query().Get("restype") == "container" {
+		if f.containers[parts[0]] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.containers[parts[0]] = true
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	if !f.containers[parts[0]] {
+		w.Header().Set("x-ms-error-code", "ContainerNotFound")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.blobs[r.URL.Path] = data
+		f.headers = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		data, ok := f.blobs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(f.blobs, r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func newFakeStorage(t *testing.T, useSAS bool) (*Storage, *fakeBlobService, *httptest.Server) {
+	fake := &fakeBlobService{containers: make(map[string]bool), blobs: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	s, err := New(storage.AzureBlobConfig{
+		ConnectionString: "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;AccountKey=" + devStoreKey + ";BlobEndpoint=" + server.URL + "/devstoreaccount1;",
+		Container:        "media",
+		Prefix:           "generated/",
+		UseSASURLs:       useSAS,
+		SASExpiration:    time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return s.(*Storage), fake, server
+}
+
+func TestStorage(t *testing.T) {
+	s, fake, server := newFakeStorage(t, false)
+	ctx := context.Background()
+
+	// The container does not exist yet and is created on first upload
+	url, err := s.Store(ctx, &interfaces.GeneratedMedia{Data: []byte("mp4"), MimeType: "video/mp4"}, storage.StorageMetadata{
+		OrgID:  "org/a",
+		Prompt: "Un café ☕",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fake.containers["media"] {
+		t.Error("expected the container to be created")
+	}
+	if !strings.HasPrefix(url, server.URL+"/devstoreaccount1/media/generated/org%252Fa/") || !strings.HasSuffix(url, ".mp4") {
+		t.Errorf("unexpected URL %s", url)
+	}
+	if fake.headers.Get("x-ms-blob-type") != "BlockBlob" || fake.headers.Get("Content-Type") != "video/mp4" {
+		t.Errorf("unexpected headers %v", fake.headers)
+	}
+	if fake.headers.Get("x-ms-meta-prompt") != "Un+caf%C3%A9+%E2%98%95" || fake.headers.Get("x-ms-meta-org_id") != "org%2Fa" {
+		t.Errorf("unexpected metadata headers %v", fake.headers)
+	}
+
+	data, err := s.Get(ctx, url)
+	if err != nil || string(data) != "mp4" {
+		t.Fatalf("Get() = %q, %v", data, err)
+	}
+	if err := s.Delete(ctx, url); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.Get(ctx, url); err == nil {
+		t.Error("expected an error for a deleted blob")
+	}
+	if err := s.Delete(ctx, url); err != nil {
+		t.Errorf("expected deleting a missing blob to succeed, got %v", err)
+	}
+}
+
+func TestSASURL(t *testing.T) {
+	s, _, _ := newFakeStorage(t, true)
+
+	rawURL, err := s.Store(context.Background(), &interfaces.GeneratedImage{Data: []byte("png"), MimeType: "image/png"}, storage.StorageMetadata{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("sv") != apiVersion || query.Get("sr") != "b" || query.Get("sp") != "r" || query.Get("sig") == "" {
+		t.Errorf("unexpected SAS parameters %v", query)
+	}
+	expiry, err := time.Parse(time.RFC3339, query.Get("se"))
+	if err != nil || time.Until(expiry) < 59*time.Minute || time.Until(expiry) > time.Hour {
+		t.Errorf("unexpected SAS expiry %q", query.Get("se"))
+	}
+
+	// SAS URLs resolve to the same blob
+	data, err := s.Get(context.Background(), rawURL)
+	if err != nil || string(data) != "png" {
+		t.Errorf("Get() = %q, %v", data, err)
+	}
+}
+
+func TestStringToSign(t *testing.T) {
+	s := &Storage{accountName: "account"}
+	req, _ := http.NewRequest(http.MethodPut, "https://account.blob.core.windows.net/media/a%20b.png?restype=container&comp=list", strings.NewReader("data"))
+	req.Header.Set("Content-Type", "image/png")
+	req.Header.Set("x-ms-version", apiVersion)
+	req.Header.Set("x-ms-date", "Sun, 18 Oct 2026 00:00:00 GMT")
+
+	want := "PUT\n\n\n4\n\nimage/png\n\n\n\n\n\n\n" +
+		"x-ms-date:Sun, 18 Oct 2026 00:00:00 GMT\nx-ms-version:" + apiVersion + "\n" +
+		"/account/media/a%20b.png\ncomp:list\nrestype:container"
+	if got := s.stringToSign(req); got != want {
+		t.Errorf("stringToSign() = %q, want %q", got, want)
+	}
+}
+
+func TestConnectionString(t *testing.T) {
+	s, err := New(storage.AzureBlobConfig{
+		ConnectionString: "DefaultEndpointsProtocol=https;AccountName=acct;AccountKey=" + devStoreKey + ";EndpointSuffix=core.chinacloudapi.cn",
+		Container:        "media",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := s.(*Storage).blobURL("a b/c.png"); got != "https://acct.blob.core.chinacloudapi.cn/media/a%20b/c.png" {
+		t.Errorf("unexpected blob URL %s", got)
+	}
+	if got := s.(*Storage).urlToBlobName("https://acct.blob.core.chinacloudapi.cn/media/a%20b/c.png?sig=x"); got != "a b/c.png" {
+		t.Errorf("unexpected blob name %q", got)
+	}
+	if got := s.(*Storage).urlToBlobName("https://other.example.com/c.png"); got != "" {
+		t.Errorf("expected no blob name for a foreign URL, got %q", got)
+	}
+
+	if _, err := New(storage.AzureBlobConfig{ConnectionString: "AccountName=acct", Container: "media"}); err == nil {
+		t.Error("expected an error without an account key")
+	}
+	if _, err := New(storage.AzureBlobConfig{AccountName: "acct", AccountKey: devStoreKey}); err == nil {
+		t.Error("expected an error without a container")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"mime"
+	"net/http"
 	"strings"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"google.golang.org/api/option"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	imgstorage "github.com/Ingenimax/agent-sdk-go/pkg/storage"
 )
 
@@ -22,7 +25,7 @@ func init() {
 	imgstorage.NewGCSStorage = New
 }
 
-// Storage implements ImageStorage for Google Cloud Storage
+// Storage implements MediaStorage for Google Cloud Storage
 type Storage struct {
 	client              *storage.Client
 	bucket              string
@@ -35,22 +38,28 @@ type Storage struct {
 func New(cfg imgstorage.GCSConfig) (imgstorage.ImageStorage, error) {
 	ctx := context.Background()
 
+	logger := logging.New()
+
 	// Build client options
 	var opts []option.ClientOption
 
 	// CredentialsJSON takes precedence over CredentialsFile
 	if cfg.CredentialsJSON != "" {
 		credentialsJSON := parseCredentialsJSON(cfg.CredentialsJSON)
-		fmt.Printf("[gcs] Using credentials JSON (length=%d, starts_with_brace=%v)\n",
-			len(credentialsJSON), len(credentialsJSON) > 0 && credentialsJSON[0] == '{')
+		logger.Debug(ctx, "Using GCS credentials JSON", map[string]interface{}{
+			"length":            len(credentialsJSON),
+			"starts_with_brace": len(credentialsJSON) > 0 && credentialsJSON[0] == '{',
+		})
 		//nolint:staticcheck // SA1019: WithCredentialsJSON is deprecated but needed for programmatic credentials
 		opts = append(opts, option.WithCredentialsJSON([]byte(credentialsJSON)))
 	} else if cfg.CredentialsFile != "" {
-		fmt.Printf("[gcs] Using credentials file: %s\n", cfg.CredentialsFile)
+		logger.Debug(ctx, "Using GCS credentials file", map[string]interface{}{
+			"credentials_file": cfg.CredentialsFile,
+		})
 		//nolint:staticcheck // SA1019: WithCredentialsFile is deprecated but needed for file-based credentials
 		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
 	} else {
-		fmt.Println("[gcs] No credentials provided, using Application Default Credentials")
+		logger.Info(ctx, "No GCS credentials provided, using Application Default Credentials", nil)
 	}
 
 	// Create GCS client
@@ -91,10 +100,15 @@ func (s *Storage) Store(ctx context.Context, image *interfaces.GeneratedImage, m
 		return "", fmt.Errorf("image data is empty")
 	}
 
+	orgID, err := imgstorage.ResolveOrgID(ctx, metadata.OrgID)
+	if err != nil {
+		return "", err
+	}
+
 	// Build object path: prefix/orgID/threadID/timestamp_hash.ext
 	objectPath := s.prefix
-	if metadata.OrgID != "" {
-		objectPath = joinPath(objectPath, sanitizePath(metadata.OrgID))
+	if orgID != "" {
+		objectPath = joinPath(objectPath, imgstorage.OrgPathSegment(orgID))
 	}
 	if metadata.ThreadID != "" {
 		objectPath = joinPath(objectPath, sanitizePath(metadata.ThreadID))
@@ -152,6 +166,9 @@ func (s *Storage) Delete(ctx context.Context, url string) error {
 	if objectPath == "" {
 		return fmt.Errorf("invalid URL or object path")
 	}
+	if err := imgstorage.CheckOrgKey(ctx, s.prefix, objectPath); err != nil {
+		return err
+	}
 
 	bucket := s.client.Bucket(s.bucket)
 	obj := bucket.Object(objectPath)
@@ -172,6 +189,9 @@ func (s *Storage) Get(ctx context.Context, url string) ([]byte, error) {
 	if objectPath == "" {
 		return nil, fmt.Errorf("invalid URL or object path")
 	}
+	if err := imgstorage.CheckOrgKey(ctx, s.prefix, objectPath); err != nil {
+		return nil, err
+	}
 
 	bucket := s.client.Bucket(s.bucket)
 	obj := bucket.Object(objectPath)
@@ -209,6 +229,47 @@ func (s *Storage) generateSignedURL(ctx context.Context, objectPath string) (str
 	return url, nil
 }
 
+// SignedUploadURL returns a V4 signed PUT URL for the object at key under the
+// prefix. The client must send the returned Content-Type header.
+func (s *Storage) SignedUploadURL(ctx context.Context, key, contentType string, expiration time.Duration) (*imgstorage.UploadTarget, error) {
+	if err := imgstorage.CheckOrgKey(ctx, "", key); err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(expiration)
+	url, err := s.client.Bucket(s.bucket).SignedURL(joinPath(s.prefix, key), &storage.SignedURLOptions{
+		Scheme:      storage.SigningSchemeV4,
+		Method:      http.MethodPut,
+		ContentType: contentType,
+		Expires:     expires,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign upload URL: %w", err)
+	}
+
+	return &imgstorage.UploadTarget{
+		URL:       url,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: expires,
+	}, nil
+}
+
+// SignedDownloadURL returns a V4 signed GET URL for the object at key under the prefix
+func (s *Storage) SignedDownloadURL(ctx context.Context, key string, expiration time.Duration) (string, error) {
+	if err := imgstorage.CheckOrgKey(ctx, "", key); err != nil {
+		return "", err
+	}
+	url, err := s.client.Bucket(s.bucket).SignedURL(joinPath(s.prefix, key), &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  http.MethodGet,
+		Expires: time.Now().Add(expiration),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign download URL: %w", err)
+	}
+	return url, nil
+}
+
 // urlToObjectPath extracts the object path from a URL
 func (s *Storage) urlToObjectPath(url string) string {
 	// Handle direct object paths
@@ -255,7 +316,29 @@ func getExtension(mimeType string) string {
 		return ".gif"
 	case "image/webp":
 		return ".webp"
+	case "audio/mpeg":
+		return ".mp3"
+	case "audio/wav":
+		return ".wav"
+	case "audio/ogg":
+		return ".ogg"
+	case "audio/aac":
+		return ".aac"
+	case "audio/flac":
+		return ".flac"
+	case "audio/L16":
+		return ".pcm"
+	case "video/mp4":
+		return ".mp4"
+	case "video/webm":
+		return ".webm"
+	case "video/quicktime":
+		return ".mov"
 	default:
+		// Other artifacts use the registered extension of their type
+		if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+			return exts[0]
+		}
 		return ".png"
 	}
 }
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// OrgPathSegment returns the path segment under which backends store the
+// objects of an organization. IDs without special characters are used as-is;
+// others are escaped, so that distinct organizations never share a segment
+// and an ID such as ".." cannot escape the backend's prefix.
+func OrgPathSegment(orgID string) string {
+	switch orgID {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	}
+	return multitenancy.EscapeKeySegment(orgID)
+}
+
+// ResolveOrgID returns the organization media is stored for: orgID, which
+// defaults to the organization of the context. It returns
+// multitenancy.ErrCrossTenantAccess when the context has another organization.
+func ResolveOrgID(ctx context.Context, orgID string) (string, error) {
+	ctxOrgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return orgID, nil
+	}
+	if orgID != "" && orgID != ctxOrgID {
+		return "", fmt.Errorf("%w: cannot store media of organization %s", multitenancy.ErrCrossTenantAccess, orgID)
+	}
+	return ctxOrgID, nil
+}
+
+// CheckOrgKey returns multitenancy.ErrCrossTenantAccess when the context has
+// an organization and key, an object key under prefix, is not stored under
+// that organization's path segment. Any key is allowed without an
+// organization in the context.
+func CheckOrgKey(ctx context.Context, prefix, key string) error {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		return nil
+	}
+
+	relative := key
+	if prefix != "" {
+		relative = strings.TrimPrefix(key, prefix+"/")
+		if relative == key {
+			return fmt.Errorf("%w: %s", multitenancy.ErrCrossTenantAccess, key)
+		}
+	}
+	if !strings.HasPrefix(relative, OrgPathSegment(orgID)+"/") {
+		return fmt.Errorf("%w: %s", multitenancy.ErrCrossTenantAccess, key)
+	}
+	return nil
+}
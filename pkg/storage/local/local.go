@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"mime"
 	"os"
 	"path/filepath"
 	"strings"
@@ -19,7 +20,7 @@ func init() {
 	storage.NewLocalStorage = New
 }
 
-// Storage implements ImageStorage for local filesystem
+// Storage implements MediaStorage for local filesystem
 type Storage struct {
 	basePath string
 	baseURL  string
@@ -91,10 +92,15 @@ func (s *Storage) Store(ctx context.Context, image *interfaces.GeneratedImage, m
 		return "", fmt.Errorf("image data is empty")
 	}
 
+	orgID, err := storage.ResolveOrgID(ctx, metadata.OrgID)
+	if err != nil {
+		return "", err
+	}
+
 	// Build directory path: basePath/orgID/threadID/
 	dirPath := s.basePath
-	if metadata.OrgID != "" {
-		dirPath = filepath.Join(dirPath, sanitizePath(metadata.OrgID))
+	if orgID != "" {
+		dirPath = filepath.Join(dirPath, storage.OrgPathSegment(orgID))
 	}
 	if metadata.ThreadID != "" {
 		dirPath = filepath.Join(dirPath, sanitizePath(metadata.ThreadID))
@@ -136,9 +142,9 @@ func (s *Storage) Store(ctx context.Context, image *interfaces.GeneratedImage, m
 
 // Delete removes an image from the local filesystem
 func (s *Storage) Delete(ctx context.Context, url string) error {
-	filePath := s.urlToFilePath(url)
-	if filePath == "" {
-		return fmt.Errorf("invalid URL or file path")
+	filePath, err := s.resolveFilePath(ctx, url)
+	if err != nil {
+		return err
 	}
 
 	// Check if file exists
@@ -151,12 +157,12 @@ func (s *Storage) Delete(ctx context.Context, url string) error {
 
 // Get retrieves image data from the local filesystem
 func (s *Storage) Get(ctx context.Context, url string) ([]byte, error) {
-	filePath := s.urlToFilePath(url)
-	if filePath == "" {
-		return nil, fmt.Errorf("invalid URL or file path")
+	filePath, err := s.resolveFilePath(ctx, url)
+	if err != nil {
+		return nil, err
 	}
 
-	// #nosec G304 - filePath is validated through urlToFilePath which uses sanitizePath
+	// #nosec G304 - filePath is validated through resolveFilePath to be under basePath
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read image file: %w", err)
@@ -165,6 +171,24 @@ func (s *Storage) Get(ctx context.Context, url string) ([]byte, error) {
 	return data, nil
 }
 
+// resolveFilePath converts a URL or file path to the path of a file under
+// basePath, which must belong to the organization of the context, if any
+func (s *Storage) resolveFilePath(ctx context.Context, url string) (string, error) {
+	filePath := s.urlToFilePath(url)
+	if filePath == "" {
+		return "", fmt.Errorf("invalid URL or file path")
+	}
+
+	relPath, err := filepath.Rel(s.basePath, filePath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is outside the storage directory", url)
+	}
+	if err := storage.CheckOrgKey(ctx, "", filepath.ToSlash(relPath)); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
 // urlToFilePath converts a URL or file path to an absolute file path
 func (s *Storage) urlToFilePath(url string) string {
 	// If it's already an absolute path
@@ -194,7 +218,29 @@ func getExtension(mimeType string) string {
 		return ".gif"
 	case "image/webp":
 		return ".webp"
+	case "audio/mpeg":
+		return ".mp3"
+	case "audio/wav":
+		return ".wav"
+	case "audio/ogg":
+		return ".ogg"
+	case "audio/aac":
+		return ".aac"
+	case "audio/flac":
+		return ".flac"
+	case "audio/L16":
+		return ".pcm"
+	case "video/mp4":
+		return ".mp4"
+	case "video/webm":
+		return ".webm"
+	case "video/quicktime":
+		return ".mov"
 	default:
+		// Other artifacts use the registered extension of their type
+		if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+			return exts[0]
+		}
 		return ".png"
 	}
 }
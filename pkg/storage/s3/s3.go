@@ -0,0 +1,442 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+func init() {
+	// Register the S3 storage factory
+	storage.NewS3Storage = New
+}
+
+// emptyPayloadHash is the SHA256 hash of an empty request body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// Storage implements MediaStorage for Amazon S3 and S3-compatible services
+// such as MinIO. Requests are signed with AWS Signature Version 4.
+type Storage struct {
+	credentials            aws.CredentialsProvider
+	signer                 *v4.Signer
+	httpClient             *http.Client
+	bucket                 string
+	prefix                 string
+	region                 string
+	endpoint               *url.URL
+	usePathStyle           bool
+	serverSideEncryption   string
+	kmsKeyID               string
+	presignedURLExpiration time.Duration
+	usePresignedURLs       bool
+}
+
+// New creates a new S3 storage backend
+func New(cfg storage.S3Config) (storage.ImageStorage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket name is required")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	endpointURL, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || endpointURL.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+
+	var credentials aws.CredentialsProvider
+	if cfg.AccessKeyID != "" {
+		static := aws.Credentials{
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+			SessionToken:    cfg.SessionToken,
+			Source:          "S3Config",
+		}
+		credentials = aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return static, nil
+		})
+	} else {
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(region))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+		}
+		credentials = awsCfg.Credentials
+	}
+
+	s := &Storage{
+		credentials: credentials,
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			// S3 object keys are signed as-is, without double escaping
+			o.DisableURIPathEscaping = true
+		}),
+		httpClient:             &http.Client{Timeout: 5 * time.Minute},
+		bucket:                 cfg.Bucket,
+		prefix:                 strings.Trim(cfg.Prefix, "/"),
+		region:                 region,
+		endpoint:               endpointURL,
+		usePathStyle:           cfg.UsePathStyle,
+		serverSideEncryption:   cfg.ServerSideEncryption,
+		kmsKeyID:               cfg.KMSKeyID,
+		presignedURLExpiration: cfg.PresignedURLExpiration,
+		usePresignedURLs:       cfg.UsePresignedURLs,
+	}
+
+	// Set defaults
+	if s.presignedURLExpiration == 0 {
+		s.presignedURLExpiration = 24 * time.Hour
+	}
+
+	return s, nil
+}
+
+// Name returns the storage backend name
+func (s *Storage) Name() string {
+	return "s3"
+}
+
+// Store saves media to S3 and returns an accessible URL
+func (s *Storage) Store(ctx context.Context, image *interfaces.GeneratedImage, metadata storage.StorageMetadata) (string, error) {
+	if image == nil || len(image.Data) == 0 {
+		return "", fmt.Errorf("image data is empty")
+	}
+
+	orgID, err := storage.ResolveOrgID(ctx, metadata.OrgID)
+	if err != nil {
+		return "", err
+	}
+
+	// Build object key: prefix/orgID/threadID/timestamp_hash.ext
+	key := s.prefix
+	if orgID != "" {
+		key = joinPath(key, storage.OrgPathSegment(orgID))
+	}
+	if metadata.ThreadID != "" {
+		key = joinPath(key, sanitizePath(metadata.ThreadID))
+	}
+
+	// Generate filename: timestamp_hash.ext
+	hash := hashData(image.Data)
+	filename := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), hash[:12], getExtension(image.MimeType))
+	key = joinPath(key, filename)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(image.Data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	req.ContentLength = int64(len(image.Data))
+	req.Header.Set("Content-Type", image.MimeType)
+
+	// User metadata must be ASCII, so the prompt is URL-encoded
+	req.Header.Set("x-amz-meta-prompt", url.QueryEscape(truncateString(metadata.Prompt, 500)))
+	if metadata.OrgID != "" {
+		req.Header.Set("x-amz-meta-org-id", url.QueryEscape(metadata.OrgID))
+	}
+	if metadata.ThreadID != "" {
+		req.Header.Set("x-amz-meta-thread-id", url.QueryEscape(metadata.ThreadID))
+	}
+	if metadata.MessageID != "" {
+		req.Header.Set("x-amz-meta-message-id", url.QueryEscape(metadata.MessageID))
+	}
+
+	// Server-side encryption
+	if s.serverSideEncryption != "" {
+		req.Header.Set("x-amz-server-side-encryption", s.serverSideEncryption)
+		if s.kmsKeyID != "" {
+			req.Header.Set("x-amz-server-side-encryption-aws-kms-key-id", s.kmsKeyID)
+		}
+	}
+
+	if _, err := s.do(req, hash); err != nil {
+		return "", fmt.Errorf("failed to upload to S3: %w", err)
+	}
+
+	if s.usePresignedURLs {
+		return s.presignedURL(ctx, key)
+	}
+	return s.objectURL(key), nil
+}
+
+// Delete removes media from S3
+func (s *Storage) Delete(ctx context.Context, url string) error {
+	key := s.urlToKey(url)
+	if key == "" {
+		return fmt.Errorf("invalid URL or object key")
+	}
+	if err := storage.CheckOrgKey(ctx, s.prefix, key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	// S3 also reports success when the object does not exist
+	if _, err := s.do(req, emptyPayloadHash); err != nil {
+		return fmt.Errorf("failed to delete from S3: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves media data from S3
+func (s *Storage) Get(ctx context.Context, url string) ([]byte, error) {
+	key := s.urlToKey(url)
+	if key == "" {
+		return nil, fmt.Errorf("invalid URL or object key")
+	}
+	if err := storage.CheckOrgKey(ctx, s.prefix, key); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	data, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from S3: %w", err)
+	}
+	return data, nil
+}
+
+// do signs and sends a request, returning the response body
+func (s *Storage) do(req *http.Request, payloadHash string) ([]byte, error) {
+	credentials, err := s.credentials.Retrieve(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if err := s.signer.SignHTTP(req.Context(), credentials, req, payloadHash, "s3", s.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("S3 returned status %d: %s", resp.StatusCode, string(data))
+	}
+	return data, nil
+}
+
+// presignedURL creates a presigned GET URL for the object
+func (s *Storage) presignedURL(ctx context.Context, key string) (string, error) {
+	signedURL, _, err := s.presign(ctx, http.MethodGet, key, http.Header{}, s.presignedURLExpiration)
+	return signedURL, err
+}
+
+// SignedUploadURL returns a presigned PUT URL for the object at key under the
+// prefix. Content-Type and the server-side encryption headers are signed, so
+// the client must send the returned headers.
+func (s *Storage) SignedUploadURL(ctx context.Context, key, contentType string, expiration time.Duration) (*storage.UploadTarget, error) {
+	if err := storage.CheckOrgKey(ctx, "", key); err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	if s.serverSideEncryption != "" {
+		header.Set("x-amz-server-side-encryption", s.serverSideEncryption)
+		if s.kmsKeyID != "" {
+			header.Set("x-amz-server-side-encryption-aws-kms-key-id", s.kmsKeyID)
+		}
+	}
+
+	signedURL, signedHeaders, err := s.presign(ctx, http.MethodPut, joinPath(s.prefix, key), header, expiration)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string, len(signedHeaders))
+	for name := range signedHeaders {
+		if !strings.EqualFold(name, "Host") {
+			headers[name] = signedHeaders.Get(name)
+		}
+	}
+	return &storage.UploadTarget{
+		URL:       signedURL,
+		Method:    http.MethodPut,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expiration),
+	}, nil
+}
+
+// SignedDownloadURL returns a presigned GET URL for the object at key under the prefix
+func (s *Storage) SignedDownloadURL(ctx context.Context, key string, expiration time.Duration) (string, error) {
+	if err := storage.CheckOrgKey(ctx, "", key); err != nil {
+		return "", err
+	}
+	signedURL, _, err := s.presign(ctx, http.MethodGet, joinPath(s.prefix, key), http.Header{}, expiration)
+	return signedURL, err
+}
+
+// presign creates a presigned URL for a request on the object, returning the
+// headers the request must be sent with
+func (s *Storage) presign(ctx context.Context, method, key string, header http.Header, expiration time.Duration) (string, http.Header, error) {
+	credentials, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	objectURL, err := url.Parse(s.objectURL(key))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid object URL: %w", err)
+	}
+	query := objectURL.Query()
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiration.Seconds())))
+	objectURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	req.Header = header
+	signedURL, signedHeaders, err := s.signer.PresignHTTP(ctx, credentials, req, "UNSIGNED-PAYLOAD", "s3", s.region, time.Now())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to presign URL: %w", err)
+	}
+	return signedURL, signedHeaders, nil
+}
+
+// objectURL returns the URL of an object, using path-style or
+// virtual-hosted-style addressing
+func (s *Storage) objectURL(key string) string {
+	escapedKey := escapeKey(key)
+	if s.usePathStyle {
+		return fmt.Sprintf("%s://%s%s/%s/%s", s.endpoint.Scheme, s.endpoint.Host, s.endpoint.Path, s.bucket, escapedKey)
+	}
+	return fmt.Sprintf("%s://%s.%s%s/%s", s.endpoint.Scheme, s.bucket, s.endpoint.Host, s.endpoint.Path, escapedKey)
+}
+
+// urlToKey extracts the object key from a URL or returns a key as-is
+func (s *Storage) urlToKey(rawURL string) string {
+	// Handle direct object keys
+	if !strings.HasPrefix(rawURL, "http") {
+		return rawURL
+	}
+
+	// Remove query parameters (for presigned URLs)
+	if idx := strings.Index(rawURL, "?"); idx != -1 {
+		rawURL = rawURL[:idx]
+	}
+
+	base := strings.TrimSuffix(s.objectURL(""), "/") + "/"
+	if !strings.HasPrefix(rawURL, base) {
+		return ""
+	}
+	key, err := url.PathUnescape(strings.TrimPrefix(rawURL, base))
+	if err != nil {
+		return ""
+	}
+	return key
+}
+
+// escapeKey escapes each segment of an object key for use in a URL path
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// getExtension returns the file extension for a MIME type
+func getExtension(mimeType string) string {
+	switch mimeType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg", "image/jpg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "audio/mpeg":
+		return ".mp3"
+	case "audio/wav":
+		return ".wav"
+	case "audio/ogg":
+		return ".ogg"
+	case "audio/aac":
+		return ".aac"
+	case "audio/flac":
+		return ".flac"
+	case "audio/L16":
+		return ".pcm"
+	case "video/mp4":
+		return ".mp4"
+	case "video/webm":
+		return ".webm"
+	case "video/quicktime":
+		return ".mov"
+	default:
+		// Other artifacts use the registered extension of their type
+		if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+			return exts[0]
+		}
+		return ".png"
+	}
+}
+
+// hashData returns a SHA256 hash of the data
+func hashData(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// sanitizePath removes potentially dangerous characters from path components
+func sanitizePath(s string) string {
+	s = strings.ReplaceAll(s, "..", "_")
+	s = strings.ReplaceAll(s, "/", "_")
+	s = strings.ReplaceAll(s, "\\", "_")
+	s = strings.ReplaceAll(s, ":", "_")
+	return s
+}
+
+// joinPath joins path components with forward slashes
+func joinPath(base, path string) string {
+	if base == "" {
+		return path
+	}
+	if path == "" {
+		return base
+	}
+	return base + "/" + path
+}
+
+// truncateString truncates a string to maxLen characters
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen]
+}
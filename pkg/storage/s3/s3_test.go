@@ -0,0 +1,210 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// fakeS3 stores objects in memory, like a minimal path-style S3 service
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	headers http.Header
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = data
+		f.headers = r.Header.Clone()
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s, err := New(storage.S3Config{
+		Bucket:               "media",
+		Prefix:               "generated/",
+		Endpoint:             server.URL,
+		UsePathStyle:         true,
+		AccessKeyID:          "AKID",
+		SecretAccessKey:      "secret",
+		ServerSideEncryption: "aws:kms",
+		KMSKeyID:             "key-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	url, err := s.Store(ctx, &interfaces.GeneratedMedia{Data: []byte("mp4"), MimeType: "video/mp4"}, storage.StorageMetadata{
+		OrgID:  "org/a",
+		Prompt: "Un café ☕",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(url, server.URL+"/media/generated/org%252Fa/") || !strings.HasSuffix(url, ".mp4") {
+		t.Errorf("unexpected URL %s", url)
+	}
+	if fake.headers.Get("X-Amz-Server-Side-Encryption") != "aws:kms" || fake.headers.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id") != "key-1" {
+		t.Errorf("expected server-side encryption headers, got %v", fake.headers)
+	}
+	if fake.headers.Get("X-Amz-Meta-Prompt") != "Un+caf%C3%A9+%E2%98%95" || fake.headers.Get("Content-Type") != "video/mp4" {
+		t.Errorf("unexpected headers %v", fake.headers)
+	}
+
+	data, err := s.Get(ctx, url)
+	if err != nil || string(data) != "mp4" {
+		t.Fatalf("Get() = %q, %v", data, err)
+	}
+	otherOrg := multitenancy.WithOrgID(ctx, "org_a")
+	if _, err := s.Get(otherOrg, url); !errors.Is(err, multitenancy.ErrCrossTenantAccess) {
+		t.Errorf("expected ErrCrossTenantAccess for another organization, got %v", err)
+	}
+	if err := s.Delete(otherOrg, url); !errors.Is(err, multitenancy.ErrCrossTenantAccess) {
+		t.Errorf("expected ErrCrossTenantAccess for another organization, got %v", err)
+	}
+	if err := s.Delete(ctx, url); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.Get(ctx, url); err == nil {
+		t.Error("expected an error for a deleted object")
+	}
+}
+
+func TestPresignedURL(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s, err := New(storage.S3Config{
+		Bucket:                 "media",
+		Endpoint:               server.URL,
+		UsePathStyle:           true,
+		AccessKeyID:            "AKID",
+		SecretAccessKey:        "secret",
+		UsePresignedURLs:       true,
+		PresignedURLExpiration: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	url, err := s.Store(context.Background(), &interfaces.GeneratedImage{Data: []byte("png"), MimeType: "image/png"}, storage.StorageMetadata{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, param := range []string{"X-Amz-Expires=3600", "X-Amz-Signature=", "X-Amz-Credential=AKID"} {
+		if !strings.Contains(url, param) {
+			t.Errorf("expected %s in presigned URL %s", param, url)
+		}
+	}
+
+	// Presigned URLs resolve to the same object
+	data, err := s.Get(context.Background(), url)
+	if err != nil || string(data) != "png" {
+		t.Errorf("Get() = %q, %v", data, err)
+	}
+}
+
+func TestObjectURL(t *testing.T) {
+	s, err := New(storage.S3Config{Bucket: "media", Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	aws := s.(*Storage)
+
+	if got := aws.objectURL("a b/c.png"); got != "https://media.s3.eu-west-1.amazonaws.com/a%20b/c.png" {
+		t.Errorf("unexpected virtual-hosted URL %s", got)
+	}
+	if got := aws.urlToKey("https://media.s3.eu-west-1.amazonaws.com/a%20b/c.png?X-Amz-Signature=x"); got != "a b/c.png" {
+		t.Errorf("unexpected key %q", got)
+	}
+	if got := aws.urlToKey("https://other.example.com/c.png"); got != "" {
+		t.Errorf("expected no key for a foreign URL, got %q", got)
+	}
+
+	if _, err := New(storage.S3Config{}); err == nil {
+		t.Error("expected an error without a bucket")
+	}
+}
+
+func TestSignedUploadURL(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s, err := New(storage.S3Config{
+		Bucket:               "media",
+		Prefix:               "generated/",
+		Endpoint:             server.URL,
+		UsePathStyle:         true,
+		AccessKeyID:          "AKID",
+		SecretAccessKey:      "secret",
+		ServerSideEncryption: "AES256",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	signed, ok := s.(storage.SignedURLStorage)
+	if !ok {
+		t.Fatal("expected S3 storage to implement SignedURLStorage")
+	}
+	target, err := signed.SignedUploadURL(context.Background(), "uploads/report.pdf", "application/pdf", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Method != http.MethodPut || !strings.Contains(target.URL, "/media/generated/uploads/report.pdf?") || !strings.Contains(target.URL, "X-Amz-Expires=900") {
+		t.Errorf("unexpected upload target %+v", target)
+	}
+	if target.Headers["Content-Type"] != "application/pdf" || target.Headers["X-Amz-Server-Side-Encryption"] != "AES256" {
+		t.Errorf("expected the signed headers, got %v", target.Headers)
+	}
+	if _, ok := target.Headers["Host"]; ok {
+		t.Error("expected no Host header")
+	}
+
+	fake.objects["/media/generated/uploads/report.pdf"] = []byte("%PDF")
+	url, err := signed.SignedDownloadURL(context.Background(), "uploads/report.pdf", time.Hour)
+	if err != nil || !strings.Contains(url, "X-Amz-Signature=") {
+		t.Fatalf("SignedDownloadURL() = %q, %v", url, err)
+	}
+	data, err := s.Get(context.Background(), url)
+	if err != nil || string(data) != "%PDF" {
+		t.Errorf("Get() = %q, %v", data, err)
+	}
+}
@@ -7,22 +7,57 @@ import (
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 )
 
-// ImageStorage defines the interface for storing and retrieving generated images
-type ImageStorage interface {
-	// Store saves an image and returns an accessible URL
-	Store(ctx context.Context, image *interfaces.GeneratedImage, metadata StorageMetadata) (string, error)
+// MediaStorage defines the interface for storing and retrieving generated
+// media: images, synthesized speech and videos, identified by their MimeType.
+type MediaStorage interface {
+	// Store saves media and returns an accessible URL
+	Store(ctx context.Context, media *interfaces.GeneratedMedia, metadata StorageMetadata) (string, error)
 
-	// Delete removes an image by URL
+	// Delete removes media by URL
 	Delete(ctx context.Context, url string) error
 
-	// Get retrieves image data by URL (optional, may not be supported by all backends)
+	// Get retrieves media data by URL (optional, may not be supported by all backends)
 	Get(ctx context.Context, url string) ([]byte, error)
 
 	// Name returns the storage backend name
 	Name() string
 }
 
-// StorageMetadata contains metadata for stored images
+// ImageStorage is the original name of MediaStorage, kept for compatibility
+type ImageStorage = MediaStorage
+
+// SignedURLStorage is implemented by backends that issue signed URLs, so
+// clients can upload content directly to storage and LLM providers can
+// download it, without passing it through the agent server. Keys are object
+// names relative to the backend's prefix.
+type SignedURLStorage interface {
+	MediaStorage
+
+	// SignedUploadURL returns a URL accepting one upload of content of
+	// contentType to key until expiration
+	SignedUploadURL(ctx context.Context, key, contentType string, expiration time.Duration) (*UploadTarget, error)
+
+	// SignedDownloadURL returns a URL to download the content at key until
+	// expiration. Get accepts it as well.
+	SignedDownloadURL(ctx context.Context, key string, expiration time.Duration) (string, error)
+}
+
+// UploadTarget describes a signed upload request for a client to make
+type UploadTarget struct {
+	// URL is the signed upload URL
+	URL string
+
+	// Method is the HTTP method of the upload, e.g. PUT
+	Method string
+
+	// Headers must be sent with the upload exactly as given
+	Headers map[string]string
+
+	// ExpiresAt is when the URL stops being valid
+	ExpiresAt time.Time
+}
+
+// StorageMetadata contains metadata for stored media
 type StorageMetadata struct {
 	// OrgID is the organization ID for multi-tenancy
 	OrgID string
@@ -33,19 +68,22 @@ type StorageMetadata struct {
 	// MessageID is the message ID
 	MessageID string
 
-	// Prompt is the original prompt used to generate the image
+	// Prompt is the original prompt used to generate the media
 	Prompt string
 
-	// Tags contains custom tags for the image
+	// Tool is the name of the tool that produced the media (optional)
+	Tool string
+
+	// Tags contains custom tags for the media
 	Tags map[string]string
 
-	// CreatedAt is the timestamp when the image was created
+	// CreatedAt is the timestamp when the media was created
 	CreatedAt time.Time
 }
 
 // Config contains configuration for storage backends
 type Config struct {
-	// Type is the storage backend type ("local", "gcs")
+	// Type is the storage backend type ("local", "gcs", "s3", "azblob")
 	Type string
 
 	// Local storage configuration
@@ -53,6 +91,12 @@ type Config struct {
 
 	// GCS storage configuration
 	GCS GCSConfig
+
+	// S3 storage configuration
+	S3 S3Config
+
+	// Azure Blob storage configuration
+	AzureBlob AzureBlobConfig
 }
 
 // LocalConfig contains configuration for local filesystem storage
@@ -88,6 +132,75 @@ type GCSConfig struct {
 	UseSignedURLs bool
 }
 
+// S3Config contains configuration for Amazon S3 and S3-compatible storage such as MinIO
+type S3Config struct {
+	// Bucket is the S3 bucket name
+	Bucket string
+
+	// Prefix is the key prefix within the bucket
+	Prefix string
+
+	// Region is the AWS region (default: us-east-1)
+	Region string
+
+	// Endpoint is the URL of an S3-compatible service, e.g. http://localhost:9000
+	// for MinIO (optional). If empty, the AWS S3 endpoint of Region is used.
+	Endpoint string
+
+	// UsePathStyle addresses objects as endpoint/bucket/key instead of
+	// bucket.endpoint/key, as most S3-compatible services require
+	UsePathStyle bool
+
+	// AccessKeyID and SecretAccessKey are static credentials (optional)
+	// If empty, the default AWS credential chain is used
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SessionToken is the session token of temporary static credentials (optional)
+	SessionToken string
+
+	// ServerSideEncryption is the server-side encryption algorithm ("AES256" or "aws:kms", optional)
+	ServerSideEncryption string
+
+	// KMSKeyID is the KMS key used with "aws:kms" encryption (optional)
+	KMSKeyID string
+
+	// PresignedURLExpiration is the duration for presigned URLs (default: 24h)
+	PresignedURLExpiration time.Duration
+
+	// UsePresignedURLs determines whether to return presigned URLs or plain object URLs
+	UsePresignedURLs bool
+}
+
+// AzureBlobConfig contains configuration for Azure Blob Storage
+type AzureBlobConfig struct {
+	// ConnectionString is an Azure Storage connection string (optional)
+	// It provides the account name, key and endpoint, overriding the fields below
+	ConnectionString string
+
+	// AccountName is the storage account name
+	AccountName string
+
+	// AccountKey is the base64-encoded storage account key
+	AccountKey string
+
+	// Container is the blob container name; it is created if it does not exist
+	Container string
+
+	// Prefix is the blob name prefix within the container
+	Prefix string
+
+	// Endpoint is the blob service URL (optional), e.g. for Azurite
+	// If empty, https://<account>.blob.core.windows.net is used
+	Endpoint string
+
+	// SASExpiration is the duration for SAS URLs (default: 24h)
+	SASExpiration time.Duration
+
+	// UseSASURLs determines whether to return SAS URLs or plain blob URLs
+	UseSASURLs bool
+}
+
 // NewStorageFromConfig creates a storage backend from configuration
 func NewStorageFromConfig(cfg Config) (ImageStorage, error) {
 	switch cfg.Type {
@@ -95,6 +208,16 @@ func NewStorageFromConfig(cfg Config) (ImageStorage, error) {
 		return NewLocalStorage(cfg.Local)
 	case "gcs":
 		return NewGCSStorage(cfg.GCS)
+	case "s3":
+		if NewS3Storage == nil {
+			return nil, interfaces.ErrStorageUploadFailed
+		}
+		return NewS3Storage(cfg.S3)
+	case "azblob":
+		if NewAzureBlobStorage == nil {
+			return nil, interfaces.ErrStorageUploadFailed
+		}
+		return NewAzureBlobStorage(cfg.AzureBlob)
 	default:
 		return nil, interfaces.ErrStorageUploadFailed
 	}
@@ -107,3 +230,11 @@ var NewLocalStorage func(cfg LocalConfig) (ImageStorage, error)
 // NewGCSStorage creates a new GCS storage
 // This is a placeholder that will be implemented in the gcs package
 var NewGCSStorage func(cfg GCSConfig) (ImageStorage, error)
+
+// NewS3Storage creates a new S3 storage
+// This is a placeholder that will be implemented in the s3 package
+var NewS3Storage func(cfg S3Config) (ImageStorage, error)
+
+// NewAzureBlobStorage creates a new Azure Blob storage
+// This is a placeholder that will be implemented in the azblob package
+var NewAzureBlobStorage func(cfg AzureBlobConfig) (ImageStorage, error)
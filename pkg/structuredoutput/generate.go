@@ -0,0 +1,54 @@
+package structuredoutput
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// GenerateAs asks llm for a response matching the JSON schema of T and
+// decodes it. The response is stripped of markdown fences and surrounding
+// text, validated against the schema and, when invalid, sent back to the
+// model with the validation errors (DefaultMaxRepairRetries times, or as set
+// with interfaces.WithRepairRetries). T must be a struct or a pointer to one.
+//
+//	person, err := structuredoutput.GenerateAs[Person](ctx, llm, "Tell me about Ada Lovelace",
+//		interfaces.WithSystemMessage("You are a biographer."))
+func GenerateAs[T any](ctx context.Context, llm interfaces.LLM, prompt string, opts ...interfaces.GenerateOption) (T, error) {
+	var result T
+
+	t := reflect.TypeOf(result)
+	if t == nil || (t.Kind() != reflect.Struct && (t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct)) {
+		return result, fmt.Errorf("GenerateAs requires a struct type, got %v", t)
+	}
+	format := NewResponseFormat(result)
+
+	options := append(opts[:len(opts):len(opts)], interfaces.WithResponseFormat(*format))
+
+	resolved := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(resolved)
+	}
+	maxRetries := DefaultMaxRepairRetries
+	if resolved.RepairRetries != nil {
+		maxRetries = *resolved.RepairRetries
+	}
+
+	response, err := llm.Generate(ctx, prompt, options...)
+	if err != nil {
+		return result, err
+	}
+
+	valid, err := Repair(ctx, llm, prompt, response, format, maxRetries, options...)
+	if err != nil {
+		return result, err
+	}
+
+	if err := json.Unmarshal([]byte(valid), &result); err != nil {
+		return result, fmt.Errorf("failed to decode %s: %w", format.Name, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package structuredoutput
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// scriptedLLM returns its responses in order and records the prompts and
+// response formats it was called with
+type scriptedLLM struct {
+	responses []string
+	prompts   []string
+	formats   []*interfaces.ResponseFormat
+}
+
+func (l *scriptedLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	opts := &interfaces.GenerateOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	l.prompts = append(l.prompts, prompt)
+	l.formats = append(l.formats, opts.ResponseFormat)
+
+	if len(l.responses) == 0 {
+		return "", errors.New("no more responses")
+	}
+	response := l.responses[0]
+	l.responses = l.responses[1:]
+	return response, nil
+}
+
+func (l *scriptedLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return l.Generate(ctx, prompt, options...)
+}
+
+func (l *scriptedLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := l.Generate(ctx, prompt, options...)
+	return &interfaces.LLMResponse{Content: content}, err
+}
+
+func (l *scriptedLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return l.GenerateDetailed(ctx, prompt, options...)
+}
+
+func (l *scriptedLLM) Name() string            { return "scripted" }
+func (l *scriptedLLM) SupportsStreaming() bool { return false }
+
+func TestGenerateAs(t *testing.T) {
+	llm := &scriptedLLM{responses: []string{
+		"```json\n{\"name\": \"Ada\", \"age\": \"unknown\", \"tags\": [], \"address\": {\"street\": \"St James's Square\"}}\n```",
+		`{"name": "Ada", "age": 36, "tags": ["math"], "address": {"street": "St James's Square"}}`,
+	}}
+
+	p, err := GenerateAs[person](context.Background(), llm, "Tell me about Ada Lovelace")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Name != "Ada" || p.Age != 36 || p.Address.Street != "St James's Square" {
+		t.Errorf("unexpected result: %+v", p)
+	}
+
+	if len(llm.prompts) != 2 || !strings.Contains(llm.prompts[1], "$.age: expected integer, got string") {
+		t.Errorf("expected one repair prompt with the validation error, got %q", llm.prompts)
+	}
+	for _, format := range llm.formats {
+		if format == nil || format.Name != "person" {
+			t.Errorf("expected the person response format on every call, got %+v", format)
+		}
+	}
+
+	pointer, err := GenerateAs[*address](context.Background(), &scriptedLLM{responses: []string{`{"street": "Main St"}`}}, "address")
+	if err != nil || pointer == nil || pointer.Street != "Main St" {
+		t.Errorf("expected a decoded pointer, got %+v (%v)", pointer, err)
+	}
+}
+
+func TestGenerateAsRetries(t *testing.T) {
+	llm := &scriptedLLM{responses: []string{`{"street": 1}`, `{"street": 2}`}}
+
+	_, err := GenerateAs[address](context.Background(), llm, "address", interfaces.WithRepairRetries(0))
+	if !errors.Is(err, ErrInvalidOutput) {
+		t.Fatalf("expected ErrInvalidOutput, got %v", err)
+	}
+	if len(llm.prompts) != 1 {
+		t.Errorf("expected no repair attempts, got %d calls", len(llm.prompts))
+	}
+
+	if _, err := GenerateAs[string](context.Background(), llm, "text"); err == nil {
+		t.Error("expected an error for a non-struct type")
+	}
+}
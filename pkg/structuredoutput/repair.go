@@ -0,0 +1,114 @@
+package structuredoutput
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// DefaultMaxRepairRetries is the number of times a response that does not
+// match its schema is sent back to the model for correction by default
+const DefaultMaxRepairRetries = 2
+
+// ErrInvalidOutput is returned when a response still does not match its
+// schema after all repair retries. The final *ValidationError is wrapped too.
+var ErrInvalidOutput = errors.New("structured output is invalid")
+
+// ExtractJSON returns the JSON value in a model response, stripping markdown
+// code fences and any text around the outermost object or array. The
+// response is returned trimmed if no JSON value is found.
+func ExtractJSON(response string) string {
+	response = strings.TrimSpace(response)
+
+	if start := strings.Index(response, "```"); start >= 0 {
+		fenced := response[start+3:]
+		if newline := strings.Index(fenced, "\n"); newline >= 0 {
+			fenced = fenced[newline+1:]
+		}
+		if end := strings.Index(fenced, "```"); end >= 0 {
+			response = strings.TrimSpace(fenced[:end])
+		}
+	}
+
+	start := strings.IndexAny(response, "{[")
+	if start < 0 {
+		return response
+	}
+	open, closing := response[start], byte('}')
+	if open == '[' {
+		closing = ']'
+	}
+
+	depth, inString, escaped := 0, false, false
+	for i := start; i < len(response); i++ {
+		c := response[i]
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && inString:
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == open:
+			depth++
+		case c == closing:
+			depth--
+			if depth == 0 {
+				return response[start : i+1]
+			}
+		}
+	}
+	return response[start:]
+}
+
+// Repair validates a response against format and, while it does not match,
+// asks llm to correct it up to maxRetries times, sending the validation
+// errors along with the original prompt. It returns the extracted JSON of the
+// first valid response. options are passed to every repair call and should
+// include the original system message and response format.
+func Repair(ctx context.Context, llm interfaces.LLM, prompt, response string, format *interfaces.ResponseFormat, maxRetries int, options ...interfaces.GenerateOption) (string, error) {
+	if format == nil || format.Type != interfaces.ResponseFormatJSON {
+		return response, nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		extracted := ExtractJSON(response)
+		err := Validate(format.Schema, []byte(extracted))
+		if err == nil {
+			return extracted, nil
+		}
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return "", err
+		}
+		if attempt >= maxRetries {
+			return "", fmt.Errorf("%w after %d repair attempts: %w", ErrInvalidOutput, attempt, err)
+		}
+
+		response, err = llm.Generate(ctx, repairPrompt(prompt, response, validationErr), options...)
+		if err != nil {
+			return "", fmt.Errorf("failed to repair structured output: %w", err)
+		}
+	}
+}
+
+// repairPrompt asks the model to correct its previous response
+func repairPrompt(prompt, response string, validationErr *ValidationError) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\nYour previous response was:\n")
+	b.WriteString(response)
+	b.WriteString("\n\nIt does not match the required JSON schema:\n")
+	for _, e := range validationErr.Errors {
+		b.WriteString("- ")
+		b.WriteString(e)
+		b.WriteString("\n")
+	}
+	b.WriteString("\nRespond again with only the corrected JSON, without markdown or explanations.")
+	return b.String()
+}
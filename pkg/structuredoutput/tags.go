@@ -0,0 +1,122 @@
+package structuredoutput
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tagKeywords parses the jsonschema tag of a field into keyword/value pairs
+// in tag order. Flags such as required have an empty value.
+func tagKeywords(field reflect.StructField) [][2]string {
+	tag := field.Tag.Get("jsonschema")
+	if tag == "" {
+		return nil
+	}
+
+	var keywords [][2]string
+	for _, part := range splitTag(tag) {
+		key, value, _ := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		keywords = append(keywords, [2]string{key, value})
+	}
+	return keywords
+}
+
+// splitTag splits a tag on commas that are not escaped with a backslash
+func splitTag(tag string) []string {
+	var parts []string
+	var current strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(tag[i])
+		}
+	}
+	return append(parts, current.String())
+}
+
+// isRequired reports whether a field belongs in its object's required list
+func isRequired(field reflect.StructField) bool {
+	required := !strings.Contains(field.Tag.Get("json"), "omitempty")
+	for _, keyword := range tagKeywords(field) {
+		switch keyword[0] {
+		case "required":
+			required = true
+		case "optional":
+			required = false
+		}
+	}
+	return required
+}
+
+// applyTagKeywords adds the constraints declared on field to its property
+// schema. For slices of scalar values, item constraints go on items.
+func applyTagKeywords(property map[string]any, field reflect.StructField, fieldType reflect.Type) {
+	keywords := tagKeywords(field)
+	if len(keywords) == 0 {
+		return
+	}
+
+	target, targetType := property, fieldType
+	if items, ok := property["items"].(map[string]any); ok && (fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) {
+		itemType := fieldType.Elem()
+		if itemType.Kind() == reflect.Pointer {
+			itemType = itemType.Elem()
+		}
+		if itemType.Kind() != reflect.Struct {
+			target, targetType = items, itemType
+		}
+	}
+
+	for _, keyword := range keywords {
+		key, value := keyword[0], keyword[1]
+		switch key {
+		case "enum":
+			enum, _ := target["enum"].([]any)
+			target["enum"] = append(enum, parseTagValue(value, targetType))
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				target[key] = n
+			}
+		case "minLength", "maxLength":
+			if n, err := strconv.Atoi(value); err == nil {
+				target[key] = n
+			}
+		case "minItems", "maxItems":
+			if n, err := strconv.Atoi(value); err == nil {
+				property[key] = n
+			}
+		case "pattern", "format":
+			target[key] = value
+		}
+	}
+}
+
+// parseTagValue converts an enum value to the JSON type of the field
+func parseTagValue(value string, t reflect.Type) any {
+	switch getJSONType(t) {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+package structuredoutput
+
+import (
+	"reflect"
+	"testing"
+)
+
+type ticket struct {
+	Status   string   `json:"status" jsonschema:"enum=open,enum=closed"`
+	Priority int      `json:"priority" jsonschema:"enum=1,enum=2,enum=3"`
+	Score    float64  `json:"score" jsonschema:"minimum=0,maximum=1"`
+	Code     string   `json:"code" jsonschema:"pattern=^[A-Z]{3}(\\,[A-Z]{3})*$,minLength=3,maxLength=20"`
+	Email    string   `json:"email,omitempty" jsonschema:"format=email,required"`
+	Labels   []string `json:"labels" jsonschema:"minItems=1,maxItems=5,maxLength=10,optional"`
+	Notes    string   `json:"notes,omitempty"`
+}
+
+func TestNewResponseFormatTagKeywords(t *testing.T) {
+	schema := NewResponseFormat(ticket{}).Schema
+	properties := schema["properties"].(map[string]any)
+
+	expected := map[string]map[string]any{
+		"status":   {"enum": []any{"open", "closed"}},
+		"priority": {"enum": []any{int64(1), int64(2), int64(3)}},
+		"score":    {"minimum": float64(0), "maximum": float64(1)},
+		"code":     {"pattern": "^[A-Z]{3}(,[A-Z]{3})*$", "minLength": 3, "maxLength": 20},
+		"email":    {"format": "email"},
+		"labels":   {"minItems": 1, "maxItems": 5},
+	}
+	for name, keywords := range expected {
+		property := properties[name].(map[string]any)
+		for key, value := range keywords {
+			if !reflect.DeepEqual(property[key], value) {
+				t.Errorf("%s.%s = %#v, expected %#v", name, key, property[key], value)
+			}
+		}
+	}
+
+	items := properties["labels"].(map[string]any)["items"].(map[string]any)
+	if items["maxLength"] != 10 {
+		t.Errorf("expected maxLength on the label items, got %#v", items)
+	}
+
+	required := schema["required"].([]string)
+	if !reflect.DeepEqual(required, []string{"status", "priority", "score", "code", "email"}) {
+		t.Errorf("unexpected required fields: %v", required)
+	}
+
+	valid := `{"status": "open", "priority": 2, "score": 0.5, "code": "ABC,DEF", "email": "a@b.co"}`
+	if err := Validate(schema, []byte(valid)); err != nil {
+		t.Errorf("expected a valid ticket, got %v", err)
+	}
+	invalid := `{"status": "done", "priority": 4, "score": 2, "code": "abc", "email": "nope", "labels": []}`
+	if err := Validate(schema, []byte(invalid)); err == nil {
+		t.Error("expected the constraints to be enforced")
+	} else if n := len(err.(*ValidationError).Errors); n != 6 {
+		t.Errorf("expected 6 violations, got %d: %v", n, err)
+	}
+}
@@ -7,7 +7,26 @@ import (
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 )
 
-// NewResponseFormat creates a ResponseFormat from a struct type
+// NewResponseFormat creates a ResponseFormat from a struct type. Field
+// descriptions come from the description struct tag.
+//
+// Schema constraints are declared with the jsonschema struct tag as
+// comma-separated keywords, e.g.
+//
+//	Status string   `json:"status" jsonschema:"enum=open,enum=closed"`
+//	Score  float64  `json:"score" jsonschema:"minimum=0,maximum=1"`
+//	Code   string   `json:"code" jsonschema:"pattern=^[A-Z]{3}$,minLength=3,maxLength=3"`
+//	Email  string   `json:"email,omitempty" jsonschema:"format=email,required"`
+//	Tags   []string `json:"tags" jsonschema:"minItems=1,maxItems=5,optional"`
+//
+// Supported keywords are enum (repeated once per value), minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, minLength, maxLength, pattern, format,
+// minItems and maxItems. For slices of scalar values, minItems and maxItems
+// apply to the array and the other keywords to its items. A literal comma in
+// a value is written as \,.
+//
+// Fields are required unless their json tag has omitempty; the required and
+// optional keywords override this.
 func NewResponseFormat(v interface{}) *interfaces.ResponseFormat {
 	t := reflect.TypeOf(v)
 	if t.Kind() == reflect.Pointer {
@@ -85,7 +104,7 @@ func getJSONSchema(t reflect.Type) map[string]any {
 				properties[jsonTag] = map[string]any{
 					"type":        "array",
 					"description": field.Tag.Get("description"),
-					"items": map[string]string{
+					"items": map[string]any{
 						"type": getJSONType(itemType),
 					},
 				}
@@ -106,6 +125,10 @@ func getJSONSchema(t reflect.Type) map[string]any {
 				"description": field.Tag.Get("description"),
 			}
 		}
+
+		if property, ok := properties[jsonTag].(map[string]any); ok {
+			applyTagKeywords(property, field, fieldType)
+		}
 	}
 	return properties
 }
@@ -142,7 +165,7 @@ func getRequiredFields(t reflect.Type) []string {
 	var required []string
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		if !strings.Contains(field.Tag.Get("json"), "omitempty") {
+		if isRequired(field) {
 			jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
 			if jsonTag == "-" {
 				// Excluded from JSON entirely; must not be required.
@@ -0,0 +1,295 @@
+package structuredoutput
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ValidationError lists the ways a response violates its JSON schema
+type ValidationError struct {
+	Errors []string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return "response does not match schema: " + strings.Join(e.Errors, "; ")
+}
+
+// Validate checks that data is JSON matching schema. It supports the schema
+// keywords generated by NewResponseFormat: type, properties, required,
+// additionalProperties, items, enum, minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, minLength, maxLength, pattern, format, minItems and
+// maxItems. Violations are returned as a *ValidationError.
+func Validate(schema interfaces.JSONSchema, data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &ValidationError{Errors: []string{fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+
+	// Round-trip the schema so nested maps and slices of any Go type can be
+	// walked uniformly
+	normalized, err := normalizeSchema(schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	var errs []string
+	validateValue(normalized, value, "$", &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func normalizeSchema(schema interfaces.JSONSchema) (map[string]interface{}, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func validateValue(schema map[string]interface{}, value interface{}, path string, errs *[]string) {
+	if !matchesType(schema["type"], value) {
+		*errs = append(*errs, fmt.Sprintf("%s: expected %s, got %s", path, describeType(schema["type"]), jsonType(value)))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		*errs = append(*errs, fmt.Sprintf("%s: %s is not one of %s", path, formatValue(value), formatValue(enum)))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateObject(schema, v, path, errs)
+	case []interface{}:
+		validateArray(schema, v, path, errs)
+	case string:
+		validateString(schema, v, path, errs)
+	case float64:
+		validateNumber(schema, v, path, errs)
+	}
+}
+
+func validateObject(schema map[string]interface{}, object map[string]interface{}, path string, errs *[]string) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, present := object[key]; !present {
+					*errs = append(*errs, fmt.Sprintf("%s: missing required property %q", path, key))
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if propertySchema, ok := properties[key].(map[string]interface{}); ok {
+			validateValue(propertySchema, object[key], path+"."+key, errs)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*errs = append(*errs, fmt.Sprintf("%s: unexpected property %q", path, key))
+			}
+		case map[string]interface{}:
+			validateValue(additional, object[key], path+"."+key, errs)
+		}
+	}
+}
+
+func validateArray(schema map[string]interface{}, array []interface{}, path string, errs *[]string) {
+	if minItems, ok := schema["minItems"].(float64); ok && float64(len(array)) < minItems {
+		*errs = append(*errs, fmt.Sprintf("%s: expected at least %g items, got %d", path, minItems, len(array)))
+	}
+	if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(array)) > maxItems {
+		*errs = append(*errs, fmt.Sprintf("%s: expected at most %g items, got %d", path, maxItems, len(array)))
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range array {
+			validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func validateString(schema map[string]interface{}, s string, path string, errs *[]string) {
+	length := float64(len([]rune(s)))
+	if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+		*errs = append(*errs, fmt.Sprintf("%s: expected at least %g characters, got %g", path, minLength, length))
+	}
+	if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+		*errs = append(*errs, fmt.Sprintf("%s: expected at most %g characters, got %g", path, maxLength, length))
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			*errs = append(*errs, fmt.Sprintf("%s: invalid pattern %q in schema", path, pattern))
+		} else if !re.MatchString(s) {
+			*errs = append(*errs, fmt.Sprintf("%s: %q does not match pattern %q", path, s, pattern))
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok && !matchesFormat(format, s) {
+		*errs = append(*errs, fmt.Sprintf("%s: %q is not a valid %s", path, s, format))
+	}
+}
+
+func validateNumber(schema map[string]interface{}, n float64, path string, errs *[]string) {
+	if minimum, ok := schema["minimum"].(float64); ok && n < minimum {
+		*errs = append(*errs, fmt.Sprintf("%s: %g is less than the minimum %g", path, n, minimum))
+	}
+	if maximum, ok := schema["maximum"].(float64); ok && n > maximum {
+		*errs = append(*errs, fmt.Sprintf("%s: %g is greater than the maximum %g", path, n, maximum))
+	}
+	if minimum, ok := schema["exclusiveMinimum"].(float64); ok && n <= minimum {
+		*errs = append(*errs, fmt.Sprintf("%s: %g must be greater than %g", path, n, minimum))
+	}
+	if maximum, ok := schema["exclusiveMaximum"].(float64); ok && n >= maximum {
+		*errs = append(*errs, fmt.Sprintf("%s: %g must be less than %g", path, n, maximum))
+	}
+}
+
+// matchesType reports whether value has the schema type, which may be a
+// single type name or a list of them. A missing type matches anything.
+func matchesType(schemaType interface{}, value interface{}) bool {
+	switch t := schemaType.(type) {
+	case nil:
+		return true
+	case string:
+		return isType(t, value)
+	case []interface{}:
+		for _, candidate := range t {
+			if name, ok := candidate.(string); ok && isType(name, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func isType(name string, value interface{}) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+func matchesFormat(format, s string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, s)
+	case "date":
+		_, err = time.Parse(time.DateOnly, s)
+	case "time":
+		_, err = time.Parse(time.TimeOnly, s)
+	case "email":
+		_, err = mail.ParseAddress(s)
+	case "uri", "url":
+		var u *url.URL
+		if u, err = url.Parse(s); err == nil && u.Scheme == "" {
+			return false
+		}
+	case "uuid":
+		_, err = uuid.Parse(s)
+	}
+	// Unknown formats are annotations only
+	return err == nil
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if formatValue(candidate) == formatValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func describeType(schemaType interface{}) string {
+	if types, ok := schemaType.([]interface{}); ok {
+		names := make([]string, 0, len(types))
+		for _, t := range types {
+			names = append(names, fmt.Sprint(t))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(schemaType)
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,111 @@
+package structuredoutput
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+type address struct {
+	Street string `json:"street"`
+	Zip    string `json:"zip,omitempty"`
+}
+
+type person struct {
+	Name    string   `json:"name"`
+	Age     int      `json:"age"`
+	Tags    []string `json:"tags"`
+	Address address  `json:"address"`
+}
+
+func TestValidateGeneratedSchema(t *testing.T) {
+	schema := NewResponseFormat(person{}).Schema
+
+	valid := `{"name": "Ada", "age": 36, "tags": ["math"], "address": {"street": "Main St"}}`
+	if err := Validate(schema, []byte(valid)); err != nil {
+		t.Errorf("expected valid JSON to pass, got %v", err)
+	}
+
+	invalid := `{"name": 1, "age": 36.5, "tags": ["math", 2], "address": {}}`
+	err := Validate(schema, []byte(invalid))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	expected := []string{
+		`$.address: missing required property "street"`,
+		"$.age: expected integer, got number",
+		"$.name: expected string, got integer",
+		"$.tags[1]: expected string, got integer",
+	}
+	if !reflect.DeepEqual(validationErr.Errors, expected) {
+		t.Errorf("unexpected errors:\n%q\nexpected:\n%q", validationErr.Errors, expected)
+	}
+
+	if err := Validate(schema, []byte("not json")); !errors.As(err, &validationErr) {
+		t.Errorf("expected a ValidationError for invalid JSON, got %v", err)
+	}
+}
+
+func TestValidateKeywords(t *testing.T) {
+	schema := interfaces.JSONSchema{
+		"type": "object",
+		"properties": map[string]any{
+			"status": map[string]any{"type": "string", "enum": []string{"open", "closed"}},
+			"score":  map[string]any{"type": "number", "minimum": 0, "maximum": 1},
+			"code":   map[string]any{"type": "string", "pattern": "^[A-Z]{3}$", "maxLength": 3},
+			"email":  map[string]any{"type": "string", "format": "email"},
+			"items":  map[string]any{"type": "array", "minItems": 1},
+		},
+		"additionalProperties": false,
+	}
+
+	tests := []struct {
+		name  string
+		data  string
+		valid bool
+	}{
+		{"valid", `{"status": "open", "score": 0.5, "code": "ABC", "email": "a@b.co", "items": [1]}`, true},
+		{"enum", `{"status": "pending"}`, false},
+		{"minimum", `{"score": -1}`, false},
+		{"maximum", `{"score": 1.5}`, false},
+		{"pattern", `{"code": "abc"}`, false},
+		{"maxLength", `{"code": "ABCD"}`, false},
+		{"format", `{"email": "not an email"}`, false},
+		{"minItems", `{"items": []}`, false},
+		{"additionalProperties", `{"extra": true}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(schema, []byte(tt.data))
+			if tt.valid && err != nil {
+				t.Errorf("expected valid, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"```json\n{\"a\": 1}\n```", `{"a": 1}`},
+		{"```\n[1, 2]\n```", `[1, 2]`},
+		{`Sure! {"a": "}"} Hope this helps.`, `{"a": "}"}`},
+		{`{"a": {"b": [1]}}`, `{"a": {"b": [1]}}`},
+		{"no json here", "no json here"},
+	}
+
+	for _, tt := range tests {
+		if result := ExtractJSON(tt.input); result != tt.expected {
+			t.Errorf("ExtractJSON(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
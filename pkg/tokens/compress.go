@@ -0,0 +1,88 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// SummaryPrefix starts the system message holding the summary written by Compress
+const SummaryPrefix = "Summary of the earlier conversation:\n"
+
+// Compress fits a conversation in budget tokens for model like TrimToBudget,
+// but replaces the dropped messages with a summary written by llm instead of
+// discarding them. The summary is inserted as a system message after the
+// leading system messages and uses at most a quarter of the budget.
+func Compress(ctx context.Context, llm interfaces.LLM, model string, messages []interfaces.Message, budget int) ([]interfaces.Message, error) {
+	total, err := CountTokens(model, messages)
+	if err != nil {
+		return nil, err
+	}
+	if total <= budget {
+		return messages, nil
+	}
+
+	summaryBudget := budget / 4
+	trimmed, keep, err := trimToBudget(model, messages, budget-summaryBudget)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []interfaces.Message
+	for i, message := range messages {
+		if !keep[i] {
+			dropped = append(dropped, message)
+		}
+	}
+	if len(dropped) == 0 {
+		return trimmed, nil
+	}
+
+	summary, err := llm.Generate(ctx, summaryPrompt(dropped, summaryBudget),
+		interfaces.WithSystemMessage("You summarize conversations for an AI assistant that will continue them."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+	summary, err = TruncateText(model, SummaryPrefix+strings.TrimSpace(summary), summaryBudget-MessageOverhead)
+	if err != nil {
+		return nil, err
+	}
+	if summary == "" {
+		return trimmed, nil
+	}
+
+	leading := 0
+	for leading < len(trimmed) && trimmed[leading].Role == interfaces.MessageRoleSystem {
+		leading++
+	}
+
+	compressed := make([]interfaces.Message, 0, len(trimmed)+1)
+	compressed = append(compressed, trimmed[:leading]...)
+	compressed = append(compressed, interfaces.Message{Role: interfaces.MessageRoleSystem, Content: summary})
+	compressed = append(compressed, trimmed[leading:]...)
+	return compressed, nil
+}
+
+func summaryPrompt(messages []interfaces.Message, budget int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summarize the following conversation in at most %d words. Keep facts, decisions, names, numbers and open questions the assistant needs to continue; leave out pleasantries.\n\n", budget*3/4)
+	for _, message := range messages {
+		switch {
+		case message.Role == interfaces.MessageRoleTool:
+			fmt.Fprintf(&b, "tool result: %s\n", message.Content)
+		case len(message.ToolCalls) > 0:
+			for _, call := range message.ToolCalls {
+				fmt.Fprintf(&b, "%s called tool %s with %s\n", message.Role, call.Name, call.Arguments)
+			}
+			if message.Content != "" {
+				fmt.Fprintf(&b, "%s: %s\n", message.Role, message.Content)
+			}
+		default:
+			fmt.Fprintf(&b, "%s: %s\n", message.Role, message.Content)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package tokens
+
+import (
+	"regexp"
+	"strings"
+)
+
+// minTruncatedChunk is the smallest budget, in tokens, worth filling with a
+// truncated chunk
+const minTruncatedChunk = 32
+
+var (
+	horizontalSpace = regexp.MustCompile(`[ \t\f\v\x{00a0}]+`)
+	blankLines      = regexp.MustCompile(`\n{3,}`)
+)
+
+// Compact removes redundant whitespace from text: runs of spaces and tabs
+// become one space, trailing spaces are removed and more than one blank line
+// becomes a single blank line. It typically saves 5-20% of the tokens of
+// scraped or extracted documents without changing their meaning.
+func Compact(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(horizontalSpace.ReplaceAllString(line, " "), " ")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// TrimContext fits retrieved context, such as documents from a vector store,
+// in budget tokens for model. Chunks are expected in order of relevance:
+// they are compacted and kept in order while they fit, and the first chunk
+// that does not fit is truncated when enough of the budget is left.
+func TrimContext(model string, chunks []string, budget int) ([]string, error) {
+	tokenizer := ForModel(model)
+
+	var kept []string
+	used := 0
+	for _, chunk := range chunks {
+		chunk = Compact(chunk)
+		if chunk == "" {
+			continue
+		}
+
+		tokens, err := tokenizer.CountTokens(chunk)
+		if err != nil {
+			return nil, err
+		}
+		if used+tokens <= budget {
+			kept = append(kept, chunk)
+			used += tokens
+			continue
+		}
+
+		if remaining := budget - used; remaining >= minTruncatedChunk {
+			truncated, err := TruncateText(model, chunk, remaining)
+			if err != nil {
+				return nil, err
+			}
+			kept = append(kept, truncated)
+		}
+		break
+	}
+	return kept, nil
+}
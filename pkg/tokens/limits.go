@@ -0,0 +1,80 @@
+package tokens
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultContextWindow is the context window, in tokens, assumed for models
+// of unknown families
+const DefaultContextWindow = 128000
+
+// contextWindows maps model name fragments to the context window of the
+// models, in tokens. The first matching fragment wins, so more specific
+// fragments come first.
+var contextWindows = []struct {
+	fragment string
+	tokens   int
+}{
+	{"gpt-4.1", 1047576},
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4.5", 128000},
+	{"gpt-5", 400000},
+	{"gpt-oss", 131072},
+	{"gpt-4-32k", 32768},
+	{"gpt-4", 8192},
+	{"gpt-3.5", 16385},
+	{"claude", 200000},
+	{"gemini-1.5-pro", 2097152},
+	{"gemini", 1048576},
+	{"deepseek", 128000},
+	{"llama3", 128000},
+	{"llama-3", 128000},
+	{"mistral", 32768},
+	{"grok", 131072},
+}
+
+var (
+	contextWindowsMu sync.RWMutex
+	registeredWindow = make(map[string]int)
+)
+
+// RegisterContextWindow sets the context window, in tokens, of models whose
+// name starts with prefix (case-insensitive), e.g. for fine-tuned or
+// self-hosted models. The longest registered prefix matching a model wins.
+func RegisterContextWindow(prefix string, tokens int) {
+	contextWindowsMu.Lock()
+	defer contextWindowsMu.Unlock()
+	registeredWindow[strings.ToLower(prefix)] = tokens
+}
+
+// ContextWindow returns the context window of model in tokens: the
+// registered window with the longest matching prefix, the window of the
+// model's family, or DefaultContextWindow
+func ContextWindow(model string) int {
+	model = strings.ToLower(model)
+
+	contextWindowsMu.RLock()
+	longest, window := "", 0
+	for prefix, tokens := range registeredWindow {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(longest) {
+			longest, window = prefix, tokens
+		}
+	}
+	contextWindowsMu.RUnlock()
+	if window > 0 {
+		return window
+	}
+
+	// o-series reasoning models (o1, o3, o4-mini, ...)
+	if len(model) > 1 && model[0] == 'o' && model[1] >= '1' && model[1] <= '9' {
+		return 200000
+	}
+	for _, family := range contextWindows {
+		if strings.Contains(model, family.fragment) {
+			return family.tokens
+		}
+	}
+	return DefaultContextWindow
+}
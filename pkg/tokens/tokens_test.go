@@ -0,0 +1,268 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/llm/mock"
+)
+
+func TestEstimator(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{"empty", "", 0},
+		{"short words", "the cat sat", 3},
+		{"long word", "internationalization", 4},
+		{"punctuation", "Hello, world!", 4},
+		{"digits", "1234567", 3},
+		{"cjk", "你好世界", 4},
+		{"indentation", "if x {\n\treturn\n}", 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Estimator{WordChars: 6}.CountTokens(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %d tokens for %q, got %d", tt.want, tt.text, got)
+			}
+		})
+	}
+}
+
+func TestForModel(t *testing.T) {
+	tests := []struct {
+		model string
+		want  Estimator
+	}{
+		{"gpt-4o-mini", O200kEstimator},
+		{"o3-mini", O200kEstimator},
+		{"gpt-4-turbo", Cl100kEstimator},
+		{"claude-sonnet-4-20250514", ClaudeEstimator},
+		{"anthropic.claude-3-haiku-20240307-v1:0", ClaudeEstimator},
+		{"gemini-2.5-flash", GeminiEstimator},
+		{"llama3", DefaultEstimator},
+		{"ollama", DefaultEstimator},
+	}
+	for _, tt := range tests {
+		if got := ForModel(tt.model); got != tt.want {
+			t.Errorf("expected %v for %s, got %v", tt.want, tt.model, got)
+		}
+	}
+
+	Register("my-model", TokenizerFunc(func(text string) (int, error) { return len(text), nil }))
+	Register("my-model-large", TokenizerFunc(func(text string) (int, error) { return 2 * len(text), nil }))
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "my-model")
+		delete(registry, "my-model-large")
+		registryMu.Unlock()
+	}()
+
+	if n, _ := CountText("My-Model-7b", "abcd"); n != 4 {
+		t.Errorf("expected the registered tokenizer, got %d", n)
+	}
+	if n, _ := CountText("my-model-large-v2", "abcd"); n != 8 {
+		t.Errorf("expected the longest registered prefix to win, got %d", n)
+	}
+}
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{"gpt-4o-mini", 128000},
+		{"gpt-4.1-nano", 1047576},
+		{"gpt-4", 8192},
+		{"o3-mini", 200000},
+		{"claude-sonnet-4-20250514", 200000},
+		{"gemini-2.5-flash", 1048576},
+		{"unknown-model", DefaultContextWindow},
+	}
+	for _, tt := range tests {
+		if got := ContextWindow(tt.model); got != tt.want {
+			t.Errorf("expected %d for %s, got %d", tt.want, tt.model, got)
+		}
+	}
+
+	RegisterContextWindow("gpt-4o-finetuned", 16000)
+	defer func() {
+		contextWindowsMu.Lock()
+		delete(registeredWindow, "gpt-4o-finetuned")
+		contextWindowsMu.Unlock()
+	}()
+	if got := ContextWindow("GPT-4o-finetuned-v2"); got != 16000 {
+		t.Errorf("expected the registered context window, got %d", got)
+	}
+}
+
+func TestCountTokens(t *testing.T) {
+	messages := []interfaces.Message{
+		{Role: interfaces.MessageRoleSystem, Content: "be brief"},
+		{Role: interfaces.MessageRoleAssistant, ToolCalls: []interfaces.ToolCall{{Name: "search", Arguments: `{"q":"go"}`}}},
+	}
+	got, err := CountTokens("gpt-4o", messages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2 + overhead, then search (1) + {"q":"go"} (9) + overhead
+	if want := 2 + MessageOverhead + 10 + MessageOverhead; got != want {
+		t.Errorf("expected %d tokens, got %d", want, got)
+	}
+}
+
+func conversation() []interfaces.Message {
+	return []interfaces.Message{
+		{Role: interfaces.MessageRoleSystem, Content: "You are a helpful assistant."},
+		{Role: interfaces.MessageRoleUser, Content: "What is the weather in Paris today?"},
+		{Role: interfaces.MessageRoleAssistant, ToolCalls: []interfaces.ToolCall{{ID: "1", Name: "weather", Arguments: `{"city":"Paris"}`}}},
+		{Role: interfaces.MessageRoleTool, ToolCallID: "1", Content: "sunny, 24 degrees"},
+		{Role: interfaces.MessageRoleAssistant, Content: "It is sunny and 24 degrees in Paris."},
+		{Role: interfaces.MessageRoleUser, Content: "And tomorrow?"},
+	}
+}
+
+func TestTrimToBudget(t *testing.T) {
+	messages := conversation()
+	total, _ := CountTokens("gpt-4o", messages)
+
+	trimmed, err := TrimToBudget("gpt-4o", messages, total)
+	if err != nil || len(trimmed) != len(messages) {
+		t.Fatalf("expected all messages to fit, got %d (%v)", len(trimmed), err)
+	}
+
+	// Dropping the first question and the tool call leaves an orphaned tool result
+	first, _ := CountTokens("gpt-4o", messages[1:3])
+	budget := total - first
+	trimmed, err = TrimToBudget("gpt-4o", messages, budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trimmed[0].Role != interfaces.MessageRoleSystem {
+		t.Errorf("expected the system message to be kept, got %v", trimmed[0].Role)
+	}
+	for _, message := range trimmed {
+		if message.Role == interfaces.MessageRoleTool {
+			t.Error("expected the orphaned tool result to be dropped")
+		}
+	}
+	if trimmed[len(trimmed)-1].Content != "And tomorrow?" {
+		t.Errorf("expected the latest message to be kept, got %q", trimmed[len(trimmed)-1].Content)
+	}
+	if n, _ := CountTokens("gpt-4o", trimmed); n > budget {
+		t.Errorf("expected at most %d tokens, got %d", budget, n)
+	}
+
+	if _, err := TrimToBudget("gpt-4o", messages, 3); !errors.Is(err, ErrBudgetTooSmall) {
+		t.Errorf("expected ErrBudgetTooSmall, got %v", err)
+	}
+}
+
+func TestTrimToBudgetTruncatesLatestMessage(t *testing.T) {
+	long := strings.Repeat("lorem ipsum dolor sit amet ", 100)
+	messages := []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "hi"},
+		{Role: interfaces.MessageRoleUser, Content: long},
+	}
+
+	trimmed, err := TrimToBudget("claude-sonnet-4", messages, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trimmed) != 1 || !strings.HasSuffix(trimmed[0].Content, "...") {
+		t.Fatalf("expected only the truncated latest message, got %+v", trimmed)
+	}
+	if n, _ := CountTokens("claude-sonnet-4", trimmed); n > 50 {
+		t.Errorf("expected at most 50 tokens, got %d", n)
+	}
+	if messages[1].Content != long {
+		t.Error("expected the input messages not to be modified")
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog"
+	if got, _ := TruncateText("gpt-4o", text, 100); got != text {
+		t.Errorf("expected text that fits to be unchanged, got %q", got)
+	}
+
+	got, err := TruncateText("gpt-4o", text, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "The quick brown..." {
+		t.Errorf("expected truncation at a word boundary, got %q", got)
+	}
+	if got, _ := TruncateText("gpt-4o", text, 1); got != "" {
+		t.Errorf("expected an empty string for a tiny budget, got %q", got)
+	}
+}
+
+func TestCompactAndTrimContext(t *testing.T) {
+	if got := Compact("  a  \t b   \r\n\n\n\n c  "); got != "a b\n\n c" {
+		t.Errorf("unexpected compacted text %q", got)
+	}
+
+	chunks := []string{
+		"first   chunk",
+		"second chunk",
+		strings.Repeat("third chunk is long ", 20),
+		"fourth chunk",
+	}
+	kept, err := TrimContext("gpt-4o", chunks, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kept) != 3 || kept[0] != "first chunk" || !strings.HasSuffix(kept[2], "...") {
+		t.Fatalf("expected two chunks and a truncated third, got %q", kept)
+	}
+
+	kept, _ = TrimContext("gpt-4o", chunks, 10)
+	if len(kept) != 2 {
+		t.Errorf("expected no truncated chunk when little budget is left, got %q", kept)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	llm := mock.New()
+	llm.On(mock.PromptContains("Berlin")).Respond("The user planned a trip to Berlin and then asked about the weather.")
+
+	messages := conversation()
+	earlier := []interfaces.Message{
+		{Role: interfaces.MessageRoleUser, Content: "I am planning a trip to Berlin next month and would like some suggestions for museums."},
+		{Role: interfaces.MessageRoleAssistant, Content: "Berlin has the Pergamon Museum, the Neues Museum and the Jewish Museum, all worth a visit."},
+	}
+	messages = append(messages[:1], append(earlier, messages[1:]...)...)
+	total, _ := CountTokens("gpt-4o", messages)
+
+	unchanged, err := Compress(context.Background(), llm, "gpt-4o", messages, total)
+	if err != nil || len(unchanged) != len(messages) || len(llm.Calls()) != 0 {
+		t.Fatalf("expected a conversation that fits to be returned as is, got %d messages (%v)", len(unchanged), err)
+	}
+
+	budget := total - 10
+	compressed, err := Compress(context.Background(), llm, "gpt-4o", messages, budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compressed[0].Content != messages[0].Content {
+		t.Errorf("expected the system prompt first, got %q", compressed[0].Content)
+	}
+	if !strings.HasPrefix(compressed[1].Content, SummaryPrefix) || !strings.Contains(compressed[1].Content, "Berlin") {
+		t.Errorf("expected the summary after the system prompt, got %q", compressed[1].Content)
+	}
+	if compressed[len(compressed)-1].Content != "And tomorrow?" {
+		t.Errorf("expected the latest message to be kept, got %q", compressed[len(compressed)-1].Content)
+	}
+	if n, _ := CountTokens("gpt-4o", compressed); n > budget {
+		t.Errorf("expected at most %d tokens, got %d", budget, n)
+	}
+}
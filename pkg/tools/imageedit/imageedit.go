@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
 )
@@ -26,6 +27,7 @@ type Tool struct {
 	sessionTimeout time.Duration
 	maxSessions    int
 	defaultModel   string
+	logger         logging.Logger
 }
 
 type sessionEntry struct {
@@ -66,6 +68,13 @@ func WithDefaultModel(model string) Option {
 	}
 }
 
+// WithLogger sets the logger used to report storage failures
+func WithLogger(logger logging.Logger) Option {
+	return func(t *Tool) {
+		t.logger = logger
+	}
+}
+
 // New creates a new multi-turn image editing tool.
 func New(editor interfaces.MultiTurnImageEditor, storage storage.ImageStorage, options ...Option) *Tool {
 	tool := &Tool{
@@ -82,6 +91,10 @@ func New(editor interfaces.MultiTurnImageEditor, storage storage.ImageStorage, o
 		opt(tool)
 	}
 
+	if tool.logger == nil {
+		tool.logger = logging.New()
+	}
+
 	// Start background cleanup goroutine
 	go tool.cleanupExpiredSessions()
 
@@ -354,6 +367,7 @@ func (t *Tool) formatResponse(ctx context.Context, sessionID string, resp *inter
 			if t.storage != nil {
 				metadata := storage.StorageMetadata{
 					Prompt:    prompt,
+					Tool:      t.Name(),
 					CreatedAt: time.Now(),
 				}
 
@@ -365,7 +379,7 @@ func (t *Tool) formatResponse(ctx context.Context, sessionID string, resp *inter
 				url, err := t.storage.Store(ctx, &image, metadata)
 				if err != nil {
 					// Log warning but continue with base64
-					fmt.Printf("[imageedit] Storage failed, using base64: %v\n", err)
+					t.logger.Warn(ctx, "Image storage failed, using base64", map[string]interface{}{"error": err.Error()})
 					result += t.formatImageBase64(&image, i)
 				} else {
 					result += t.formatImageURL(url, &image, i)
@@ -0,0 +1,185 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// EditTool implements image editing as a tool for agents. Unlike multi-turn
+// editing, each call is independent: the image to edit is given by URL and
+// fetched from storage, so any stored image (e.g. one shown in the UI) can be
+// modified.
+type EditTool struct {
+	editor interfaces.ImageEditor
+	// base holds the storage, options and result formatting shared with the generation tool
+	base *Tool
+}
+
+// NewEditTool creates a new image editing tool. Storage is needed to fetch
+// images by URL; without it only data URIs can be edited.
+func NewEditTool(editor interfaces.ImageEditor, storage storage.ImageStorage, options ...Option) *EditTool {
+	return &EditTool{
+		editor: editor,
+		base:   New(nil, storage, options...),
+	}
+}
+
+// EditTool returns an image editing tool that shares the storage and options
+// of this tool, or nil if its generator cannot edit images
+func (t *Tool) EditTool() *EditTool {
+	editor, ok := t.generator.(interfaces.ImageEditor)
+	if !ok {
+		return nil
+	}
+	return &EditTool{editor: editor, base: t}
+}
+
+// Name returns the tool name
+func (t *EditTool) Name() string {
+	return "edit_image"
+}
+
+// DisplayName returns a human-friendly name
+func (t *EditTool) DisplayName() string {
+	return "Image Editor"
+}
+
+// Description returns what the tool does
+func (t *EditTool) Description() string {
+	return "Edit an existing image given its URL. Describe the change to make in the prompt. Optionally provide a mask image URL whose transparent areas mark the region to edit. Returns the URL of the edited image."
+}
+
+// Internal returns false as this is a user-visible tool
+func (t *EditTool) Internal() bool {
+	return false
+}
+
+// Parameters returns the tool's parameter specifications
+func (t *EditTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"image_url": {
+			Type:        "string",
+			Description: "The URL of the image to edit, e.g. the URL of a previously generated image",
+			Required:    true,
+		},
+		"prompt": {
+			Type:        "string",
+			Description: "A description of the edit to apply to the image",
+			Required:    true,
+		},
+		"mask_url": {
+			Type:        "string",
+			Description: "The URL of a mask image whose transparent areas mark the region to edit",
+			Required:    false,
+		},
+	}
+}
+
+// Run executes the tool with the given input
+func (t *EditTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// Execute implements the tool execution
+func (t *EditTool) Execute(ctx context.Context, args string) (string, error) {
+	var params struct {
+		ImageURL string `json:"image_url"`
+		Prompt   string `json:"prompt"`
+		MaskURL  string `json:"mask_url,omitempty"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse arguments: %w", err)
+	}
+
+	if params.ImageURL == "" {
+		return "", fmt.Errorf("image_url is required")
+	}
+	if params.Prompt == "" {
+		return "", fmt.Errorf("prompt is required")
+	}
+	if len(params.Prompt) > t.base.maxPromptLen {
+		return "", fmt.Errorf("prompt exceeds maximum length of %d characters", t.base.maxPromptLen)
+	}
+
+	image, err := t.fetchImage(ctx, params.ImageURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch image: %w", err)
+	}
+	request := interfaces.ImageEditRequest{
+		Prompt: params.Prompt,
+		Image:  image,
+		Options: &interfaces.ImageGenerationOptions{
+			NumberOfImages: 1,
+			OutputFormat:   t.base.defaultFormat,
+		},
+	}
+	if params.MaskURL != "" {
+		if request.Mask, err = t.fetchImage(ctx, params.MaskURL); err != nil {
+			return "", fmt.Errorf("failed to fetch mask: %w", err)
+		}
+	}
+
+	response, err := t.editor.EditImage(ctx, request)
+	if err != nil {
+		return "", fmt.Errorf("image editing failed: %w", err)
+	}
+	if len(response.Images) == 0 {
+		return "", fmt.Errorf("no images were generated")
+	}
+
+	result := fmt.Sprintf("Successfully edited image for prompt: \"%s\"\n\n", truncateString(params.Prompt, 100))
+	edited := &response.Images[0]
+	if t.base.storage == nil {
+		return result + t.base.formatImageBase64(edited, 0), nil
+	}
+
+	metadata := storage.StorageMetadata{
+		Prompt:    params.Prompt,
+		Tool:      t.Name(),
+		CreatedAt: time.Now(),
+	}
+	metadata.OrgID, _ = multitenancy.GetOrgID(ctx)
+
+	url, err := t.base.storage.Store(ctx, edited, metadata)
+	if err != nil {
+		// Log warning but don't fail - return base64 instead
+		t.base.logger.Warn(ctx, "Image storage failed, using base64", map[string]interface{}{"error": err.Error()})
+		return result + t.base.formatImageBase64(edited, 0), nil
+	}
+	edited.URL = url
+	return result + t.base.formatImageURL(url, edited, 0), nil
+}
+
+// fetchImage loads an image from a data URI or from storage
+func (t *EditTool) fetchImage(ctx context.Context, url string) (*interfaces.ImageData, error) {
+	if strings.HasPrefix(url, "data:") {
+		header, encoded, ok := strings.Cut(strings.TrimPrefix(url, "data:"), ",")
+		if !ok || !strings.HasSuffix(header, ";base64") {
+			return nil, fmt.Errorf("unsupported data URI, expected base64 data")
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data URI: %w", err)
+		}
+		return &interfaces.ImageData{Data: data, MimeType: strings.TrimSuffix(header, ";base64")}, nil
+	}
+
+	if t.base.storage == nil {
+		return nil, fmt.Errorf("no image storage is configured to fetch %s", url)
+	}
+	data, err := t.base.storage.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return &interfaces.ImageData{Data: data, MimeType: http.DetectContentType(data), URL: url}, nil
+}
@@ -0,0 +1,80 @@
+package imagegen
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+type fakeEditor struct {
+	request interfaces.ImageEditRequest
+}
+
+func (e *fakeEditor) EditImage(ctx context.Context, request interfaces.ImageEditRequest) (*interfaces.ImageGenerationResponse, error) {
+	e.request = request
+	return &interfaces.ImageGenerationResponse{
+		Images: []interfaces.GeneratedImage{{Data: []byte("edited"), Base64: "ZWRpdGVk", MimeType: "image/png"}},
+	}, nil
+}
+
+// memoryStorage stores images in a map keyed by URL
+type memoryStorage map[string][]byte
+
+func (s memoryStorage) Store(ctx context.Context, image *interfaces.GeneratedImage, metadata storage.StorageMetadata) (string, error) {
+	url := fmt.Sprintf("https://cdn.example.com/%d.png", len(s))
+	s[url] = image.Data
+	return url, nil
+}
+
+func (s memoryStorage) Delete(ctx context.Context, url string) error { return nil }
+
+func (s memoryStorage) Get(ctx context.Context, url string) ([]byte, error) {
+	data, ok := s[url]
+	if !ok {
+		return nil, fmt.Errorf("image not found")
+	}
+	return data, nil
+}
+
+func (s memoryStorage) Name() string { return "memory" }
+
+func TestEditTool(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	store := memoryStorage{"https://cdn.example.com/cat.png": png, "https://cdn.example.com/mask.png": png}
+	editor := &fakeEditor{}
+	tool := NewEditTool(editor, store)
+
+	result, err := tool.Execute(context.Background(), `{"image_url":"https://cdn.example.com/cat.png","mask_url":"https://cdn.example.com/mask.png","prompt":"Add a hat"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if editor.request.Image.MimeType != "image/png" || editor.request.Mask == nil || editor.request.Prompt != "Add a hat" {
+		t.Errorf("unexpected request %+v", editor.request)
+	}
+	if !strings.Contains(result, "![Generated image](https://cdn.example.com/2.png)") || string(store["https://cdn.example.com/2.png"]) != "edited" {
+		t.Errorf("expected the edited image to be stored, got %q", result)
+	}
+
+	// Data URIs are decoded without storage
+	result, err = NewEditTool(editor, nil).Execute(context.Background(), `{"image_url":"data:image/jpeg;base64,aW1hZ2U=","prompt":"Make it blue"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(editor.request.Image.Data) != "image" || editor.request.Image.MimeType != "image/jpeg" {
+		t.Errorf("unexpected image %+v", editor.request.Image)
+	}
+	if !strings.Contains(result, "data:image/png;base64,ZWRpdGVk") {
+		t.Errorf("expected the edited image inline, got %q", result)
+	}
+
+	if _, err := NewEditTool(editor, nil).Execute(context.Background(), `{"image_url":"https://cdn.example.com/cat.png","prompt":"x"}`); err == nil {
+		t.Error("expected an error without storage")
+	}
+	if _, err := tool.Execute(context.Background(), `{"image_url":"https://cdn.example.com/missing.png","prompt":"x"}`); err == nil {
+		t.Error("expected an error for a missing image")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
 	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
 )
@@ -30,6 +31,7 @@ type Tool struct {
 	sessionsMu        sync.RWMutex
 	sessionTimeout    time.Duration
 	maxSessionsPerOrg int
+	logger            logging.Logger
 }
 
 // sessionEntry tracks an active multi-turn editing session
@@ -99,6 +101,13 @@ func WithMaxSessionsPerOrg(max int) Option {
 	}
 }
 
+// WithLogger sets the logger used to report storage failures
+func WithLogger(logger logging.Logger) Option {
+	return func(t *Tool) {
+		t.logger = logger
+	}
+}
+
 // New creates a new image generation tool
 func New(generator interfaces.ImageGenerator, storage storage.ImageStorage, options ...Option) *Tool {
 	tool := &Tool{
@@ -115,6 +124,10 @@ func New(generator interfaces.ImageGenerator, storage storage.ImageStorage, opti
 		opt(tool)
 	}
 
+	if tool.logger == nil {
+		tool.logger = logging.New()
+	}
+
 	return tool
 }
 
@@ -182,12 +195,17 @@ func (t *Tool) Parameters() map[string]interfaces.ParameterSpec {
 			Enum:        []interface{}{"1K", "2K", "4K"},
 		}
 	} else {
+		// Offer the formats the generator supports (e.g. webp for OpenAI and Stability AI)
+		var formats []interface{}
+		for _, format := range t.generator.SupportedImageFormats() {
+			formats = append(formats, format)
+		}
 		params["output_format"] = interfaces.ParameterSpec{
 			Type:        "string",
 			Description: "The output image format",
 			Required:    false,
 			Default:     t.defaultFormat,
-			Enum:        []interface{}{"png", "jpeg"},
+			Enum:        formats,
 		}
 	}
 
@@ -271,23 +289,24 @@ func (t *Tool) executeSingleShot(ctx context.Context, prompt, aspectRatio, outpu
 	if t.storage != nil {
 		metadata := storage.StorageMetadata{
 			Prompt:    prompt,
+			Tool:      t.Name(),
 			CreatedAt: time.Now(),
 		}
 
 		url, err := t.storage.Store(ctx, &response.Images[0], metadata)
 		if err != nil {
 			// Log warning but don't fail - return base64 instead
-			fmt.Printf("[imagegen] Storage failed, using base64: %v\n", err)
+			t.logger.Warn(ctx, "Image storage failed, using base64", map[string]interface{}{"error": err.Error()})
 			return t.formatResultWithBase64(response, prompt), nil
 		}
 		response.Images[0].URL = url
-		fmt.Printf("[imagegen] Image stored at: %s\n", url)
+		t.logger.Debug(ctx, "Image stored", map[string]interface{}{"url": url})
 		// Format result with URL
 		return t.formatResult(response, prompt, url), nil
 	}
 
 	// No storage configured - return base64 embedded image
-	fmt.Printf("[imagegen] No storage configured, using base64\n")
+	t.logger.Debug(ctx, "No image storage configured, using base64", nil)
 	return t.formatResultWithBase64(response, prompt), nil
 }
 
@@ -491,6 +510,7 @@ func (t *Tool) formatMultiTurnResponse(ctx context.Context, resp *interfaces.Ima
 			if t.storage != nil {
 				metadata := storage.StorageMetadata{
 					Prompt:    prompt,
+					Tool:      t.Name(),
 					CreatedAt: time.Now(),
 				}
 
@@ -502,7 +522,7 @@ func (t *Tool) formatMultiTurnResponse(ctx context.Context, resp *interfaces.Ima
 				url, err := t.storage.Store(ctx, &image, metadata)
 				if err != nil {
 					// Log warning but continue with base64
-					fmt.Printf("[imagegen] Storage failed, using base64: %v\n", err)
+					t.logger.Warn(ctx, "Image storage failed, using base64", map[string]interface{}{"error": err.Error()})
 					result += t.formatImageBase64(&image, i)
 				} else {
 					result += t.formatImageURL(url, &image, i)
@@ -0,0 +1,127 @@
+// Package milestone provides a tool that lets the model mark named
+// milestones in the current conversation.
+package milestone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+// Tool marks conversation milestones in memory.
+type Tool struct {
+	memory  interfaces.Memory
+	allowed []string
+}
+
+// Option represents an option for configuring the milestone tool
+type Option func(*Tool)
+
+// WithAllowedMilestones restricts the milestones the model may mark. The
+// names are listed in the tool description so the model knows what to use.
+func WithAllowedMilestones(names ...string) Option {
+	return func(t *Tool) {
+		t.allowed = append(t.allowed, names...)
+	}
+}
+
+// New creates a new milestone tool that records milestones in the given memory.
+func New(mem interfaces.Memory, options ...Option) *Tool {
+	t := &Tool{memory: mem}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return "mark_milestone"
+}
+
+// Description returns the tool description.
+func (t *Tool) Description() string {
+	description := "Mark that the conversation has reached a named milestone, such as 'requirements confirmed' " +
+		"or 'draft approved'. Only mark a milestone once the user has clearly reached it."
+	if len(t.allowed) > 0 {
+		description += " Allowed milestones: " + strings.Join(t.allowed, ", ") + "."
+	}
+	return description
+}
+
+// Parameters returns the tool parameters.
+func (t *Tool) Parameters() map[string]interfaces.ParameterSpec {
+	name := interfaces.ParameterSpec{
+		Type:        "string",
+		Description: "The milestone name",
+		Required:    true,
+	}
+	if len(t.allowed) > 0 {
+		enum := make([]interface{}, len(t.allowed))
+		for i, allowed := range t.allowed {
+			enum[i] = allowed
+		}
+		name.Enum = enum
+	}
+
+	return map[string]interfaces.ParameterSpec{
+		"name": name,
+		"description": {
+			Type:        "string",
+			Description: "A short note on why the milestone was reached",
+			Required:    false,
+		},
+		"data": {
+			Type:        "object",
+			Description: "Optional structured details about the milestone as key-value pairs",
+			Required:    false,
+		},
+	}
+}
+
+// Run executes the tool with the milestone name as input.
+func (t *Tool) Run(ctx context.Context, input string) (string, error) {
+	return t.mark(ctx, strings.TrimSpace(input), "", nil)
+}
+
+// Execute implements the tool interface with JSON arguments.
+func (t *Tool) Execute(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description"`
+		Data        map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse arguments: %w", err)
+	}
+
+	return t.mark(ctx, params.Name, params.Description, params.Data)
+}
+
+func (t *Tool) mark(ctx context.Context, name, description string, data map[string]interface{}) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	if len(t.allowed) > 0 && !t.isAllowed(name) {
+		return "", fmt.Errorf("unknown milestone %q, allowed milestones: %s", name, strings.Join(t.allowed, ", "))
+	}
+
+	if err := memory.MarkMilestone(ctx, t.memory, name, description, data); err != nil {
+		return "", fmt.Errorf("failed to mark milestone: %w", err)
+	}
+
+	return fmt.Sprintf("Milestone '%s' recorded.", name), nil
+}
+
+func (t *Tool) isAllowed(name string) bool {
+	for _, allowed := range t.allowed {
+		if strings.EqualFold(allowed, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,370 @@
+// Package retriever provides a retrieval (RAG) tool that queries a vector store
+// and formats the matching chunks with numbered citations.
+package retriever
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tokens"
+)
+
+const (
+	// DefaultTopK is the default number of chunks returned by a search
+	DefaultTopK = 5
+
+	// DefaultTokenBudget is the default maximum number of tokens of retrieved context
+	DefaultTokenBudget = 2000
+
+	// DefaultRerankCandidates is the default number of vector search candidates passed to the reranker
+	DefaultRerankCandidates = 25
+)
+
+// Retriever queries a vector store and formats the results as cited context.
+// It implements interfaces.Tool so it can be exposed to the model directly,
+// and it is also used by agent.WithRetriever to inject context automatically.
+type Retriever struct {
+	store         interfaces.VectorStore
+	name          string
+	description   string
+	topK          int
+	tokenBudget   int
+	model         string
+	minScore      float32
+	searchOptions []interfaces.SearchOption
+
+	reranker         interfaces.Reranker
+	rerankCandidates int
+}
+
+// Option represents an option for configuring the retriever
+type Option func(*Retriever)
+
+// WithTopK sets the maximum number of chunks to retrieve
+func WithTopK(k int) Option {
+	return func(r *Retriever) {
+		r.topK = k
+	}
+}
+
+// WithTokenBudget sets the maximum number of tokens of formatted context.
+// Chunks are added in score order until the budget is exhausted.
+func WithTokenBudget(tokens int) Option {
+	return func(r *Retriever) {
+		r.tokenBudget = tokens
+	}
+}
+
+// WithModel sets the model whose tokenizer counts the token budget. Without
+// it, tokens are counted with tokens.DefaultEstimator.
+func WithModel(model string) Option {
+	return func(r *Retriever) {
+		r.model = model
+	}
+}
+
+// WithMinScore sets the minimum similarity score a chunk must have to be included
+func WithMinScore(score float32) Option {
+	return func(r *Retriever) {
+		r.minScore = score
+	}
+}
+
+// WithSearchOptions sets additional vector store search options (class, filters, tenant, ...)
+func WithSearchOptions(options ...interfaces.SearchOption) Option {
+	return func(r *Retriever) {
+		r.searchOptions = append(r.searchOptions, options...)
+	}
+}
+
+// WithReranker adds a reranking stage. The vector store is queried for the
+// given number of candidate documents (DefaultRerankCandidates when <= 0),
+// which are then reranked and cut down to top-k before the token budget is applied.
+func WithReranker(reranker interfaces.Reranker, candidates int) Option {
+	return func(r *Retriever) {
+		r.reranker = reranker
+		r.rerankCandidates = candidates
+	}
+}
+
+// WithName overrides the tool name (useful when an agent has several knowledge bases)
+func WithName(name string) Option {
+	return func(r *Retriever) {
+		r.name = name
+	}
+}
+
+// WithDescription overrides the tool description shown to the model
+func WithDescription(description string) Option {
+	return func(r *Retriever) {
+		r.description = description
+	}
+}
+
+// New creates a new retriever backed by the given vector store
+func New(store interfaces.VectorStore, options ...Option) *Retriever {
+	r := &Retriever{
+		store:       store,
+		name:        "retriever",
+		description: "Search the knowledge base for passages relevant to a query. Results include numbered citations that should be referenced in the answer.",
+		topK:        DefaultTopK,
+		tokenBudget: DefaultTokenBudget,
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// Chunk is a retrieved passage together with its citation information
+type Chunk struct {
+	// Index is the 1-based citation number of the chunk
+	Index int `json:"index"`
+
+	// Source identifies where the chunk came from (title, URL or document ID)
+	Source string `json:"source"`
+
+	// Content is the text of the chunk
+	Content string `json:"content"`
+
+	// Score is the similarity score reported by the vector store
+	Score float32 `json:"score"`
+
+	// Metadata is the document metadata
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Name implements interfaces.Tool.Name
+func (r *Retriever) Name() string {
+	return r.name
+}
+
+// DisplayName implements interfaces.ToolWithDisplayName.DisplayName
+func (r *Retriever) DisplayName() string {
+	return "Knowledge Retriever"
+}
+
+// Description implements interfaces.Tool.Description
+func (r *Retriever) Description() string {
+	return r.description
+}
+
+// Internal implements interfaces.InternalTool.Internal
+func (r *Retriever) Internal() bool {
+	return false
+}
+
+// Parameters implements interfaces.Tool.Parameters
+func (r *Retriever) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"query": {
+			Type:        "string",
+			Description: "The search query",
+			Required:    true,
+		},
+		"top_k": {
+			Type:        "number",
+			Description: fmt.Sprintf("Maximum number of passages to return (default: %d)", r.topK),
+			Required:    false,
+			Default:     r.topK,
+		},
+	}
+}
+
+// Run implements interfaces.Tool.Run
+func (r *Retriever) Run(ctx context.Context, input string) (string, error) {
+	return r.search(ctx, strings.TrimSpace(input), r.topK)
+}
+
+// Execute implements interfaces.Tool.Execute
+func (r *Retriever) Execute(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Query string `json:"query"`
+		TopK  int    `json:"top_k"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse arguments: %w", err)
+	}
+
+	topK := params.TopK
+	if topK <= 0 {
+		topK = r.topK
+	}
+
+	return r.search(ctx, params.Query, topK)
+}
+
+func (r *Retriever) search(ctx context.Context, query string, topK int) (string, error) {
+	if query == "" {
+		return "", fmt.Errorf("query parameter is required")
+	}
+
+	chunks, err := r.retrieve(ctx, query, topK)
+	if err != nil {
+		return "", err
+	}
+	if len(chunks) == 0 {
+		return "No relevant passages found.", nil
+	}
+
+	return FormatChunks(chunks), nil
+}
+
+// Retrieve searches the vector store and returns the chunks that fit within
+// the configured top-k, minimum score and token budget.
+func (r *Retriever) Retrieve(ctx context.Context, query string) ([]Chunk, error) {
+	return r.retrieve(ctx, query, r.topK)
+}
+
+func (r *Retriever) retrieve(ctx context.Context, query string, topK int) ([]Chunk, error) {
+	if r.store == nil {
+		return nil, fmt.Errorf("retriever has no vector store configured")
+	}
+
+	options := r.searchOptions
+	if r.minScore > 0 {
+		options = append(append([]interfaces.SearchOption{}, options...), interfaces.WithMinScore(r.minScore))
+	}
+
+	limit := topK
+	if r.reranker != nil {
+		limit = r.rerankCandidates
+		if limit <= 0 {
+			limit = DefaultRerankCandidates
+		}
+		if limit < topK {
+			limit = topK
+		}
+	}
+
+	results, err := r.store.Search(ctx, query, limit, options...)
+	if err != nil {
+		return nil, fmt.Errorf("vector store search failed: %w", err)
+	}
+
+	if r.minScore > 0 {
+		filtered := make([]interfaces.SearchResult, 0, len(results))
+		for _, result := range results {
+			if result.Score >= r.minScore {
+				filtered = append(filtered, result)
+			}
+		}
+		results = filtered
+	}
+
+	if r.reranker != nil && len(results) > 0 {
+		results, err = r.rerank(ctx, query, results, topK)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chunks := make([]Chunk, 0, len(results))
+	usedTokens := 0
+	for _, result := range results {
+		chunk := Chunk{
+			Index:    len(chunks) + 1,
+			Source:   sourceOf(result.Document),
+			Content:  result.Document.Content,
+			Score:    result.Score,
+			Metadata: result.Document.Metadata,
+		}
+
+		chunkTokens, err := tokens.CountText(r.model, formatChunk(chunk))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count tokens: %w", err)
+		}
+		if r.tokenBudget > 0 && usedTokens+chunkTokens > r.tokenBudget {
+			// Keep at least a truncated version of the best chunk
+			if len(chunks) == 0 {
+				headerTokens, err := tokens.CountText(r.model, formatChunk(Chunk{Index: 1, Source: chunk.Source}))
+				if err != nil {
+					return nil, fmt.Errorf("failed to count tokens: %w", err)
+				}
+				chunk.Content, err = tokens.TruncateText(r.model, chunk.Content, r.tokenBudget-headerTokens)
+				if err != nil {
+					return nil, fmt.Errorf("failed to truncate chunk: %w", err)
+				}
+				chunks = append(chunks, chunk)
+			}
+			break
+		}
+
+		usedTokens += chunkTokens
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
+
+// rerank reorders search results with the reranker and keeps the top-k.
+// Scores are replaced with the reranker's relevance scores.
+func (r *Retriever) rerank(ctx context.Context, query string, results []interfaces.SearchResult, topK int) ([]interfaces.SearchResult, error) {
+	documents := make([]string, len(results))
+	for i, result := range results {
+		documents[i] = result.Document.Content
+	}
+
+	ranked, err := r.reranker.Rerank(ctx, query, documents, topK)
+	if err != nil {
+		return nil, fmt.Errorf("rerank failed: %w", err)
+	}
+
+	reranked := make([]interfaces.SearchResult, 0, len(ranked))
+	for _, rr := range ranked {
+		if rr.Index < 0 || rr.Index >= len(results) {
+			continue
+		}
+		result := results[rr.Index]
+		result.Score = rr.Score
+		reranked = append(reranked, result)
+	}
+	return reranked, nil
+}
+
+// RetrieveContext returns the retrieved chunks formatted as a context block,
+// or an empty string when nothing relevant was found.
+func (r *Retriever) RetrieveContext(ctx context.Context, query string) (string, error) {
+	chunks, err := r.Retrieve(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	if len(chunks) == 0 {
+		return "", nil
+	}
+	return FormatChunks(chunks), nil
+}
+
+// FormatChunks formats chunks as numbered passages followed by their sources
+func FormatChunks(chunks []Chunk) string {
+	var sb strings.Builder
+	for i, chunk := range chunks {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(formatChunk(chunk))
+	}
+	return sb.String()
+}
+
+func formatChunk(chunk Chunk) string {
+	return fmt.Sprintf("[%d] (source: %s)\n%s", chunk.Index, chunk.Source, strings.TrimSpace(chunk.Content))
+}
+
+// sourceOf returns the best human-readable citation for a document
+func sourceOf(doc interfaces.Document) string {
+	for _, key := range []string{"source", "title", "url", "filename", "path"} {
+		if value, ok := doc.Metadata[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	if doc.ID != "" {
+		return doc.ID
+	}
+	return "unknown"
+}
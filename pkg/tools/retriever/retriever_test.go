@@ -0,0 +1,178 @@
+package retriever
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tokens"
+)
+
+// fakeStore is a minimal in-memory vector store returning canned results
+type fakeStore struct {
+	results   []interfaces.SearchResult
+	lastLimit int
+}
+
+func (f *fakeStore) Store(ctx context.Context, documents []interfaces.Document, options ...interfaces.StoreOption) error {
+	return nil
+}
+
+func (f *fakeStore) Get(ctx context.Context, id string, options ...interfaces.StoreOption) (*interfaces.Document, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) Search(ctx context.Context, query string, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	f.lastLimit = limit
+	if limit < len(f.results) {
+		return f.results[:limit], nil
+	}
+	return f.results, nil
+}
+
+func (f *fakeStore) SearchByVector(ctx context.Context, vector []float32, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) Delete(ctx context.Context, ids []string, options ...interfaces.DeleteOption) error {
+	return nil
+}
+
+func (f *fakeStore) GlobalStore(ctx context.Context, documents []interfaces.Document, options ...interfaces.StoreOption) error {
+	return nil
+}
+
+func (f *fakeStore) GlobalSearch(ctx context.Context, query string, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	return f.Search(ctx, query, limit, options...)
+}
+
+func (f *fakeStore) GlobalSearchByVector(ctx context.Context, vector []float32, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) GlobalDelete(ctx context.Context, ids []string, options ...interfaces.DeleteOption) error {
+	return nil
+}
+
+func (f *fakeStore) CreateTenant(ctx context.Context, tenantName string) error { return nil }
+func (f *fakeStore) DeleteTenant(ctx context.Context, tenantName string) error { return nil }
+func (f *fakeStore) ListTenants(ctx context.Context) ([]string, error)         { return nil, nil }
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		results: []interfaces.SearchResult{
+			{Document: interfaces.Document{ID: "doc-1", Content: "Paris is the capital of France.", Metadata: map[string]interface{}{"title": "France"}}, Score: 0.9},
+			{Document: interfaces.Document{ID: "doc-2", Content: "Berlin is the capital of Germany."}, Score: 0.7},
+			{Document: interfaces.Document{ID: "doc-3", Content: "Madrid is the capital of Spain."}, Score: 0.2},
+		},
+	}
+}
+
+func TestRetrieverExecuteFormatsCitations(t *testing.T) {
+	store := newFakeStore()
+	r := New(store, WithTopK(2))
+
+	out, err := r.Execute(context.Background(), `{"query": "capitals"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.lastLimit != 2 {
+		t.Errorf("expected limit 2, got %d", store.lastLimit)
+	}
+	if !strings.Contains(out, "[1] (source: France)") {
+		t.Errorf("expected first citation to use the title, got:\n%s", out)
+	}
+	if !strings.Contains(out, "[2] (source: doc-2)") {
+		t.Errorf("expected second citation to fall back to the document ID, got:\n%s", out)
+	}
+	if strings.Contains(out, "Madrid") {
+		t.Errorf("expected top-k to exclude the third result, got:\n%s", out)
+	}
+}
+
+func TestRetrieverMinScore(t *testing.T) {
+	r := New(newFakeStore(), WithMinScore(0.5))
+
+	chunks, err := r.Retrieve(context.Background(), "capitals")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks above min score, got %d", len(chunks))
+	}
+}
+
+func TestRetrieverTokenBudget(t *testing.T) {
+	r := New(newFakeStore(), WithTokenBudget(15))
+
+	chunks, err := r.Retrieve(context.Background(), "capitals")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("expected the budget to allow a single chunk, got %d", len(chunks))
+	}
+	if count, _ := tokens.CountText("", FormatChunks(chunks)); count > 15 {
+		t.Errorf("formatted context exceeds the token budget: %q", FormatChunks(chunks))
+	}
+}
+
+func TestRetrieverTruncatesBestChunk(t *testing.T) {
+	r := New(newFakeStore(), WithTokenBudget(16), WithModel("claude-sonnet-4"))
+
+	chunks, err := r.Retrieve(context.Background(), "capitals")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) != 1 || !strings.HasSuffix(chunks[0].Content, "...") {
+		t.Fatalf("expected the best chunk truncated, got %+v", chunks)
+	}
+	if count, _ := tokens.CountText("claude-sonnet-4", FormatChunks(chunks)); count > 16 {
+		t.Errorf("formatted context exceeds the token budget: %q", FormatChunks(chunks))
+	}
+}
+
+func TestRetrieverRequiresQuery(t *testing.T) {
+	r := New(newFakeStore())
+	if _, err := r.Execute(context.Background(), `{}`); err == nil {
+		t.Error("expected error for missing query")
+	}
+}
+
+// reverseReranker ranks documents in reverse order of the vector search
+type reverseReranker struct {
+	documents []string
+}
+
+func (r *reverseReranker) Rerank(ctx context.Context, query string, documents []string, topN int) ([]interfaces.RerankResult, error) {
+	r.documents = documents
+	results := make([]interfaces.RerankResult, 0, len(documents))
+	for i := len(documents) - 1; i >= 0; i-- {
+		results = append(results, interfaces.RerankResult{Index: i, Score: float32(i + 1)})
+	}
+	if topN > 0 && len(results) > topN {
+		results = results[:topN]
+	}
+	return results, nil
+}
+
+func TestRetrieverWithReranker(t *testing.T) {
+	store := newFakeStore()
+	reranker := &reverseReranker{}
+	r := New(store, WithTopK(1), WithReranker(reranker, 10))
+
+	chunks, err := r.Retrieve(context.Background(), "capitals")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.lastLimit != 10 {
+		t.Errorf("expected reranker candidates to be requested, got limit %d", store.lastLimit)
+	}
+	if len(reranker.documents) != 3 {
+		t.Errorf("expected all candidates to be reranked, got %d", len(reranker.documents))
+	}
+	if len(chunks) != 1 || chunks[0].Source != "doc-3" || chunks[0].Score != 3 {
+		t.Fatalf("expected the reranked top result doc-3, got %+v", chunks)
+	}
+}
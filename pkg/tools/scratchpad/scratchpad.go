@@ -0,0 +1,195 @@
+// Package scratchpad provides a key/value store shared by the agents of a
+// workflow, and a tool that lets them read and write it. Agents can pass
+// plans, drafts and reviews through the scratchpad instead of repeating
+// them in every prompt.
+package scratchpad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Store is a concurrency-safe key/value store shared within a workflow
+type Store struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewStore creates an empty scratchpad store
+func NewStore() *Store {
+	return &Store{values: make(map[string]string)}
+}
+
+// Set stores a value under key, replacing any previous value
+func (s *Store) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Get returns the value stored under key
+func (s *Store) Get(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Delete removes key from the store
+func (s *Store) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Keys returns the stored keys in sorted order
+func (s *Store) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Snapshot returns a copy of all stored values
+func (s *Store) Snapshot() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snapshot := make(map[string]string, len(s.values))
+	for key, value := range s.values {
+		snapshot[key] = value
+	}
+	return snapshot
+}
+
+type storeKey struct{}
+
+// WithStore returns a context carrying the scratchpad store. Agents and
+// sub-agents run with this context share the store through the tool.
+func WithStore(ctx context.Context, store *Store) context.Context {
+	return context.WithValue(ctx, storeKey{}, store)
+}
+
+// GetStore returns the scratchpad store from the context
+func GetStore(ctx context.Context) (*Store, bool) {
+	store, ok := ctx.Value(storeKey{}).(*Store)
+	return store, ok && store != nil
+}
+
+// Tool reads and writes the scratchpad store of the current workflow
+type Tool struct {
+	store *Store
+}
+
+// Input represents the input for the scratchpad tool
+type Input struct {
+	Action string `json:"action"`
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+}
+
+// New creates a scratchpad tool. The store in the run context takes
+// precedence; store is used when the context has none and may be nil.
+func New(store *Store) *Tool {
+	return &Tool{store: store}
+}
+
+// Name implements interfaces.Tool.Name
+func (t *Tool) Name() string {
+	return "scratchpad"
+}
+
+// DisplayName implements interfaces.ToolWithDisplayName.DisplayName
+func (t *Tool) DisplayName() string {
+	return "Scratchpad"
+}
+
+// Description implements interfaces.Tool.Description
+func (t *Tool) Description() string {
+	return "Read and write artifacts shared with the other agents of this workflow, such as plans, drafts " +
+		"and reviews. Use 'list' to see the available keys, 'get' to read one, 'set' to store a value " +
+		"and 'delete' to remove it."
+}
+
+// Internal implements interfaces.InternalTool.Internal
+func (t *Tool) Internal() bool {
+	return false
+}
+
+// Parameters implements interfaces.Tool.Parameters
+func (t *Tool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"action": {
+			Type:        "string",
+			Description: "The operation to perform",
+			Required:    true,
+			Enum:        []interface{}{"get", "set", "delete", "list"},
+		},
+		"key": {
+			Type:        "string",
+			Description: "The key to read, write or delete (not needed for 'list')",
+		},
+		"value": {
+			Type:        "string",
+			Description: "The value to store (only for 'set')",
+		},
+	}
+}
+
+// Run implements interfaces.Tool.Run
+func (t *Tool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// Execute implements interfaces.Tool.Execute
+func (t *Tool) Execute(ctx context.Context, args string) (string, error) {
+	var input Input
+	if err := json.Unmarshal([]byte(args), &input); err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	store, ok := GetStore(ctx)
+	if !ok {
+		store = t.store
+	}
+	if store == nil {
+		return "", fmt.Errorf("no scratchpad is available in this run")
+	}
+
+	action := strings.ToLower(strings.TrimSpace(input.Action))
+	if action != "list" && input.Key == "" {
+		return "", fmt.Errorf("key is required for action %q", input.Action)
+	}
+
+	switch action {
+	case "get":
+		value, ok := store.Get(input.Key)
+		if !ok {
+			return fmt.Sprintf("No value stored under %q", input.Key), nil
+		}
+		return value, nil
+	case "set":
+		store.Set(input.Key, input.Value)
+		return fmt.Sprintf("Stored %q", input.Key), nil
+	case "delete":
+		store.Delete(input.Key)
+		return fmt.Sprintf("Deleted %q", input.Key), nil
+	case "list":
+		keys := store.Keys()
+		if len(keys) == 0 {
+			return "The scratchpad is empty", nil
+		}
+		return "Keys: " + strings.Join(keys, ", "), nil
+	default:
+		return "", fmt.Errorf("unknown action %q: use get, set, delete or list", input.Action)
+	}
+}
@@ -0,0 +1,66 @@
+package scratchpad
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestToolUsesStoreFromContext(t *testing.T) {
+	store := NewStore()
+	ctx := WithStore(context.Background(), store)
+	tool := New(nil)
+
+	if _, err := tool.Execute(ctx, `{"action":"set","key":"plan","value":"1. outline 2. draft"}`); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if value, ok := store.Get("plan"); !ok || value != "1. outline 2. draft" {
+		t.Errorf("Expected the plan in the shared store, got %q", value)
+	}
+
+	result, err := tool.Execute(ctx, `{"action":"get","key":"plan"}`)
+	if err != nil || result != "1. outline 2. draft" {
+		t.Errorf("Expected to read the plan, got %q (%v)", result, err)
+	}
+
+	store.Set("draft", "v1")
+	result, _ = tool.Execute(ctx, `{"action":"list"}`)
+	if result != "Keys: draft, plan" {
+		t.Errorf("Unexpected list result: %q", result)
+	}
+
+	if _, err := tool.Execute(ctx, `{"action":"delete","key":"draft"}`); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, ok := store.Get("draft"); ok {
+		t.Error("Expected draft to be deleted")
+	}
+}
+
+func TestToolFallsBackToOwnStore(t *testing.T) {
+	own := NewStore()
+	tool := New(own)
+
+	if _, err := tool.Execute(context.Background(), `{"action":"set","key":"k","value":"v"}`); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if value, _ := own.Get("k"); value != "v" {
+		t.Errorf("Expected the value in the tool's own store, got %q", value)
+	}
+
+	// The store in the run context takes precedence
+	shared := NewStore()
+	if _, err := tool.Execute(WithStore(context.Background(), shared), `{"action":"set","key":"k","value":"shared"}`); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if value, _ := own.Get("k"); value != "v" {
+		t.Errorf("Expected the tool's own store to be untouched, got %q", value)
+	}
+
+	if _, err := New(nil).Execute(context.Background(), `{"action":"list"}`); err == nil {
+		t.Error("Expected an error without any store")
+	}
+	if _, err := tool.Execute(context.Background(), `{"action":"get"}`); err == nil || !strings.Contains(err.Error(), "key is required") {
+		t.Errorf("Expected a missing key error, got %v", err)
+	}
+}
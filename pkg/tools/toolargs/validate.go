@@ -0,0 +1,271 @@
+// Package toolargs validates the arguments of tool calls.
+//
+// Models sometimes call tools with missing, mistyped or out-of-range
+// arguments. Validate checks the JSON arguments of a call against the tool's
+// ParameterSpecs: types, required parameters and enums, filling in defaults.
+// Its ValidationError lists every problem, so when it is returned to the
+// model as the result of the call, the model can correct the call. Wrap
+// applies it before each Execute, so tools do not have to check their
+// arguments by hand.
+package toolargs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Problem is a problem with one argument of a tool call
+type Problem struct {
+	// Parameter is the path of the argument, e.g. "units" or "tags[2]"
+	Parameter string `json:"parameter"`
+
+	// Message describes the problem
+	Message string `json:"message"`
+}
+
+// ValidationError reports the problems with the arguments of a tool call
+type ValidationError struct {
+	Tool     string    `json:"tool"`
+	Problems []Problem `json:"problems"`
+}
+
+// Error lists the problems, and asks the model to call the tool again
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "invalid arguments for tool %s:", e.Tool)
+	for _, problem := range e.Problems {
+		if problem.Parameter == "" {
+			fmt.Fprintf(&sb, "\n- %s", problem.Message)
+		} else {
+			fmt.Fprintf(&sb, "\n- %s: %s", problem.Parameter, problem.Message)
+		}
+	}
+	sb.WriteString("\nFix the arguments and call the tool again.")
+	return sb.String()
+}
+
+// Validate checks the JSON arguments of a call to the named tool against its
+// parameters, and returns the arguments with the defaults of missing
+// parameters filled in. Parameters that are not declared are kept as they
+// are. The arguments are returned unchanged when the tool declares no
+// parameters. The error is a *ValidationError when the arguments are invalid.
+func Validate(toolName string, params map[string]interfaces.ParameterSpec, args string) (string, error) {
+	if len(params) == 0 {
+		return args, nil
+	}
+
+	values := map[string]interface{}{}
+	if strings.TrimSpace(args) != "" {
+		if err := json.Unmarshal([]byte(args), &values); err != nil {
+			return "", &ValidationError{Tool: toolName, Problems: []Problem{{
+				Message: fmt.Sprintf("arguments must be a JSON object: %v", err),
+			}}}
+		}
+		if values == nil {
+			values = map[string]interface{}{}
+		}
+	}
+
+	// Check the parameters in a stable order
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []Problem
+	filled := false
+	for _, name := range names {
+		spec := params[name]
+		value, ok := values[name]
+		if !ok {
+			switch {
+			case spec.Default != nil:
+				values[name] = spec.Default
+				filled = true
+			case spec.Required:
+				problems = append(problems, Problem{Parameter: name, Message: "is required"})
+			}
+			continue
+		}
+		problems = append(problems, checkValue(name, spec, value)...)
+	}
+
+	if len(problems) > 0 {
+		return "", &ValidationError{Tool: toolName, Problems: problems}
+	}
+	if !filled {
+		return args, nil
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	return string(data), nil
+}
+
+// checkValue checks a value against its spec, and its items against the
+// spec of the items
+func checkValue(path string, spec interfaces.ParameterSpec, value interface{}) []Problem {
+	if types := specTypes(spec.Type); len(types) > 0 && !hasType(value, types) {
+		return []Problem{{
+			Parameter: path,
+			Message:   fmt.Sprintf("must be of type %s, got %s", strings.Join(types, " or "), jsonType(value)),
+		}}
+	}
+
+	if len(spec.Enum) > 0 && !inEnum(value, spec.Enum) {
+		return []Problem{{
+			Parameter: path,
+			Message:   fmt.Sprintf("must be one of %s", formatEnum(spec.Enum)),
+		}}
+	}
+
+	var problems []Problem
+	if items, ok := value.([]interface{}); ok && spec.Items != nil {
+		for i, item := range items {
+			problems = append(problems, checkValue(fmt.Sprintf("%s[%d]", path, i), *spec.Items, item)...)
+		}
+	}
+	return problems
+}
+
+// specTypes returns the types of a spec, which is a type name or a list of
+// type names
+func specTypes(specType interface{}) []string {
+	switch t := specType.(type) {
+	case string:
+		if t == "" {
+			return nil
+		}
+		return []string{t}
+	case []string:
+		return t
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// hasType reports whether a decoded JSON value is of one of the types
+func hasType(value interface{}, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if n, ok := value.(float64); ok && n == math.Trunc(n) {
+				return true
+			}
+		case "number", "string", "boolean", "array", "object", "null":
+			if jsonType(value) == t {
+				return true
+			}
+		default:
+			// Types unknown to JSON Schema are not checked
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType returns the JSON Schema type of a decoded JSON value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// inEnum reports whether a decoded JSON value is one of the enum values.
+// Values are compared by their JSON encoding, so that the number 1 matches
+// an enum value of 1 whatever its Go type.
+func inEnum(value interface{}, enum []interface{}) bool {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	for _, v := range enum {
+		if e, err := json.Marshal(v); err == nil && string(e) == string(encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatEnum formats enum values as a JSON list
+func formatEnum(enum []interface{}) string {
+	data, err := json.Marshal(enum)
+	if err != nil {
+		return fmt.Sprint(enum)
+	}
+	return string(data)
+}
+
+// Wrap returns a tool whose Execute validates its arguments with Validate
+// before running the tool. Run is not validated, as its input is not
+// necessarily JSON.
+func Wrap(tool interfaces.Tool) interfaces.Tool {
+	return &validatedTool{inner: tool}
+}
+
+// validatedTool validates the arguments of a tool's calls
+type validatedTool struct {
+	inner interfaces.Tool
+}
+
+func (t *validatedTool) Name() string        { return t.inner.Name() }
+func (t *validatedTool) Description() string { return t.inner.Description() }
+func (t *validatedTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.inner.Parameters()
+}
+
+func (t *validatedTool) Run(ctx context.Context, input string) (string, error) {
+	return t.inner.Run(ctx, input)
+}
+
+func (t *validatedTool) Execute(ctx context.Context, args string) (string, error) {
+	args, err := Validate(t.inner.Name(), t.inner.Parameters(), args)
+	if err != nil {
+		return "", err
+	}
+	return t.inner.Execute(ctx, args)
+}
+
+// DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
+func (t *validatedTool) DisplayName() string {
+	if d, ok := t.inner.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.inner.Name()
+}
+
+// Internal forwards to the inner tool when it implements InternalTool.
+func (t *validatedTool) Internal() bool {
+	if i, ok := t.inner.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
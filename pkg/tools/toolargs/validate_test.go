@@ -0,0 +1,103 @@
+package toolargs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+var weatherParams = map[string]interfaces.ParameterSpec{
+	"location": {Type: "string", Description: "City name", Required: true},
+	"units":    {Type: "string", Description: "Temperature units", Enum: []interface{}{"celsius", "fahrenheit"}, Default: "celsius"},
+	"days":     {Type: "integer", Description: "Forecast days"},
+	"hours":    {Type: "array", Description: "Hours of the day", Items: &interfaces.ParameterSpec{Type: "integer"}},
+	"note":     {Type: []string{"string", "null"}, Description: "Optional note"},
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     string
+		expected string
+		problems []string
+	}{
+		{"valid", `{"location": "Paris", "units": "fahrenheit", "days": 3}`, `{"location": "Paris", "units": "fahrenheit", "days": 3}`, nil},
+		{"fills defaults", `{"location":"Paris"}`, `{"location":"Paris","units":"celsius"}`, nil},
+		{"union type", `{"location": "Paris", "units": "celsius", "note": null}`, `{"location": "Paris", "units": "celsius", "note": null}`, nil},
+		{"missing required", `{}`, "", []string{"location: is required"}},
+		{"empty arguments", ``, "", []string{"location: is required"}},
+		{"wrong type", `{"location": 75001, "units": "celsius"}`, "", []string{"location: must be of type string, got number"}},
+		{"not an integer", `{"location": "Paris", "units": "celsius", "days": 1.5}`, "", []string{"days: must be of type integer, got number"}},
+		{"enum", `{"location": "Paris", "units": "kelvin"}`, "", []string{`units: must be one of ["celsius","fahrenheit"]`}},
+		{"items", `{"location": "Paris", "units": "celsius", "hours": [9, "noon"]}`, "", []string{"hours[1]: must be of type integer, got string"}},
+		{"several problems", `{"units": "kelvin"}`, "", []string{"location: is required", "units: must be one of"}},
+		{"not an object", `["Paris"]`, "", []string{"arguments must be a JSON object"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := Validate("get_weather", weatherParams, tt.args)
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if args != tt.expected {
+					t.Errorf("Expected arguments %s, got %s", tt.expected, args)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+			if len(validationErr.Problems) != len(tt.problems) {
+				t.Fatalf("Expected %d problems, got %+v", len(tt.problems), validationErr.Problems)
+			}
+			for _, problem := range tt.problems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("Expected %q in %q", problem, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateWithoutParameters(t *testing.T) {
+	if args, err := Validate("now", nil, "not json"); err != nil || args != "not json" {
+		t.Errorf("Expected the arguments to be unchanged, got %q, %v", args, err)
+	}
+}
+
+// echoTool returns its arguments
+type echoTool struct{}
+
+func (t *echoTool) Name() string        { return "get_weather" }
+func (t *echoTool) Description() string { return "Echoes its arguments" }
+func (t *echoTool) Parameters() map[string]interfaces.ParameterSpec {
+	return weatherParams
+}
+func (t *echoTool) Run(ctx context.Context, input string) (string, error) { return input, nil }
+func (t *echoTool) Execute(ctx context.Context, args string) (string, error) {
+	return args, nil
+}
+
+func TestWrap(t *testing.T) {
+	tool := Wrap(&echoTool{})
+
+	result, err := tool.Execute(context.Background(), `{"location":"Paris"}`)
+	if err != nil || result != `{"location":"Paris","units":"celsius"}` {
+		t.Errorf("Expected the tool to receive the defaults, got %q, %v", result, err)
+	}
+
+	if _, err := tool.Execute(context.Background(), `{"units":"kelvin"}`); err == nil || !strings.Contains(err.Error(), "call the tool again") {
+		t.Errorf("Expected a validation error for the model, got %v", err)
+	}
+
+	if result, _ := tool.Run(context.Background(), "Paris"); result != "Paris" {
+		t.Errorf("Expected Run not to be validated, got %q", result)
+	}
+}
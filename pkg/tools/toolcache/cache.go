@@ -0,0 +1,224 @@
+// Package toolcache caches the results of tool calls.
+//
+// Agents often call the same tool with the same arguments several times,
+// within one run or across the turns of a conversation, e.g. looking up a
+// stock price. A Cache keeps the result of successful calls of the tools it
+// lists for a TTL, keyed by organization, tool name and normalized
+// arguments, so repeated calls do not hit external APIs again.
+package toolcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// DefaultTTL is how long results are cached by default
+const DefaultTTL = 5 * time.Minute
+
+// Store stores cached tool results
+type Store interface {
+	// Get returns the result stored under key, and whether it was found
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// Set stores a result under key for ttl
+	Set(ctx context.Context, key, result string, ttl time.Duration) error
+}
+
+// Cache caches the results of tool calls in a Store
+type Cache struct {
+	store  Store
+	ttl    time.Duration
+	tools  map[string]time.Duration
+	logger logging.Logger
+}
+
+// Option represents an option for configuring the cache
+type Option func(*Cache)
+
+// WithStore sets the store of the cache, an in-memory store by default
+func WithStore(store Store) Option {
+	return func(c *Cache) {
+		c.store = store
+	}
+}
+
+// WithTTL sets how long results are cached, DefaultTTL by default
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// WithTools caches the results of the named tools. Only the tools named with
+// WithTools or WithToolTTL are cached, so tools with side effects are never
+// cached by accident.
+func WithTools(names ...string) Option {
+	return func(c *Cache) {
+		for _, name := range names {
+			c.tools[name] = 0
+		}
+	}
+}
+
+// WithToolTTL caches the results of the named tool for ttl, overriding the
+// cache's TTL
+func WithToolTTL(name string, ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.tools[name] = ttl
+	}
+}
+
+// WithLogger sets the logger of the cache
+func WithLogger(logger logging.Logger) Option {
+	return func(c *Cache) {
+		c.logger = logger
+	}
+}
+
+// New creates a new tool result cache
+func New(options ...Option) *Cache {
+	c := &Cache{
+		ttl:    DefaultTTL,
+		tools:  make(map[string]time.Duration),
+		logger: logging.New(),
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	if c.store == nil {
+		c.store = NewMemoryStore()
+	}
+	return c
+}
+
+// Caches reports whether the results of the named tool are cached, i.e.
+// whether it was named with WithTools or WithToolTTL
+func (c *Cache) Caches(toolName string) bool {
+	_, ok := c.tools[toolName]
+	return ok
+}
+
+// Wrap returns a tool whose results are cached, or the tool itself when its
+// results are not cached
+func (c *Cache) Wrap(tool interfaces.Tool) interfaces.Tool {
+	if !c.Caches(tool.Name()) {
+		return tool
+	}
+	return &cachedTool{inner: tool, cache: c}
+}
+
+// Key returns the key of a tool call in the organization of ctx. Arguments
+// that are JSON are normalized, so calls differing only in whitespace or
+// the order of object keys share a key.
+func Key(ctx context.Context, toolName, args string) string {
+	orgID, err := multitenancy.GetOrgID(ctx)
+	if err != nil {
+		orgID = "default"
+	}
+	sum := sha256.Sum256([]byte(normalizeArgs(args)))
+	return "toolcache:" + orgID + ":" + toolName + ":" + hex.EncodeToString(sum[:])
+}
+
+// normalizeArgs re-encodes JSON arguments, which sorts object keys and
+// removes insignificant whitespace
+func normalizeArgs(args string) string {
+	args = strings.TrimSpace(args)
+	decoder := json.NewDecoder(strings.NewReader(args))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return args
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return args
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// call returns the cached result of a tool call, or runs it and caches its
+// result when it succeeds
+func (c *Cache) call(ctx context.Context, toolName, args string, run func(context.Context, string) (string, error)) (string, error) {
+	key := Key(ctx, toolName, args)
+
+	result, found, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.logger.Warn(ctx, "Failed to read cached tool result", map[string]interface{}{
+			"tool":  toolName,
+			"error": err.Error(),
+		})
+	} else if found {
+		c.logger.Debug(ctx, "Using cached tool result", map[string]interface{}{
+			"tool": toolName,
+		})
+		return result, nil
+	}
+
+	result, err = run(ctx, args)
+	if err != nil {
+		// Errors are not cached, so the call is tried again
+		return result, err
+	}
+
+	ttl := c.ttl
+	if toolTTL := c.tools[toolName]; toolTTL > 0 {
+		ttl = toolTTL
+	}
+	if err := c.store.Set(ctx, key, result, ttl); err != nil {
+		c.logger.Warn(ctx, "Failed to cache tool result", map[string]interface{}{
+			"tool":  toolName,
+			"error": err.Error(),
+		})
+	}
+	return result, nil
+}
+
+// cachedTool serves the calls of a tool from the cache
+type cachedTool struct {
+	inner interfaces.Tool
+	cache *Cache
+}
+
+func (t *cachedTool) Name() string        { return t.inner.Name() }
+func (t *cachedTool) Description() string { return t.inner.Description() }
+func (t *cachedTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.inner.Parameters()
+}
+
+func (t *cachedTool) Run(ctx context.Context, input string) (string, error) {
+	return t.cache.call(ctx, t.inner.Name(), input, t.inner.Run)
+}
+
+func (t *cachedTool) Execute(ctx context.Context, args string) (string, error) {
+	return t.cache.call(ctx, t.inner.Name(), args, t.inner.Execute)
+}
+
+// DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
+func (t *cachedTool) DisplayName() string {
+	if d, ok := t.inner.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.inner.Name()
+}
+
+// Internal forwards to the inner tool when it implements InternalTool.
+func (t *cachedTool) Internal() bool {
+	if i, ok := t.inner.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
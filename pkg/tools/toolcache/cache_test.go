@@ -0,0 +1,117 @@
+package toolcache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// countingTool returns the number of times it was called
+type countingTool struct {
+	name  string
+	calls int
+	err   error
+}
+
+func (t *countingTool) Name() string        { return t.name }
+func (t *countingTool) Description() string { return "Counts its calls" }
+func (t *countingTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{}
+}
+func (t *countingTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (t *countingTool) Execute(ctx context.Context, args string) (string, error) {
+	t.calls++
+	if t.err != nil {
+		return "", t.err
+	}
+	return fmt.Sprintf("call %d", t.calls), nil
+}
+
+func TestCachedTool(t *testing.T) {
+	tool := &countingTool{name: "get_stock_price"}
+	cached := New(WithTools("get_stock_price")).Wrap(tool)
+	ctx := multitenancy.WithOrgID(context.Background(), "acme")
+
+	first, err := cached.Execute(ctx, `{"symbol": "AAPL", "currency": "USD"}`)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	// The same arguments in another order and spacing hit the cache
+	second, err := cached.Execute(ctx, `{"currency":"USD","symbol":"AAPL"}`)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if first != "call 1" || second != "call 1" || tool.calls != 1 {
+		t.Errorf("Expected one call, got %q, %q after %d calls", first, second, tool.calls)
+	}
+
+	// Other arguments and other organizations miss the cache
+	if result, _ := cached.Execute(ctx, `{"symbol": "MSFT", "currency": "USD"}`); result != "call 2" {
+		t.Errorf("Expected a new call for other arguments, got %q", result)
+	}
+	other := multitenancy.WithOrgID(context.Background(), "globex")
+	if result, _ := cached.Execute(other, `{"symbol": "AAPL", "currency": "USD"}`); result != "call 3" {
+		t.Errorf("Expected a new call for another organization, got %q", result)
+	}
+}
+
+func TestCacheSkipsErrorsAndUncachedTools(t *testing.T) {
+	cache := New(WithTools("get_stock_price"))
+
+	failing := &countingTool{name: "get_stock_price", err: fmt.Errorf("rate limited")}
+	cached := cache.Wrap(failing)
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Execute(context.Background(), `{}`); err == nil {
+			t.Fatal("Expected the error of the tool")
+		}
+	}
+	if failing.calls != 2 {
+		t.Errorf("Expected errors not to be cached, got %d calls", failing.calls)
+	}
+
+	sender := &countingTool{name: "send_email"}
+	if cache.Wrap(sender) != interfaces.Tool(sender) {
+		t.Error("Expected tools outside WithTools not to be wrapped")
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	tool := &countingTool{name: "get_stock_price"}
+	cached := New(WithToolTTL("get_stock_price", 20*time.Millisecond)).Wrap(tool)
+
+	_, _ = cached.Execute(context.Background(), `{"symbol": "AAPL"}`)
+	time.Sleep(40 * time.Millisecond)
+	if result, _ := cached.Execute(context.Background(), `{"symbol": "AAPL"}`); result != "call 2" {
+		t.Errorf("Expected the result to expire, got %q", result)
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := NewRedisStore(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	ctx := context.Background()
+
+	if _, found, err := store.Get(ctx, "key"); err != nil || found {
+		t.Fatalf("Expected a miss, got %v, %v", found, err)
+	}
+	if err := store.Set(ctx, "key", "result", time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if result, found, err := store.Get(ctx, "key"); err != nil || !found || result != "result" {
+		t.Errorf("Expected the stored result, got %q, %v, %v", result, found, err)
+	}
+
+	server.FastForward(2 * time.Minute)
+	if _, found, _ := store.Get(ctx, "key"); found {
+		t.Error("Expected the result to expire")
+	}
+}
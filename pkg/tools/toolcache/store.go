@@ -0,0 +1,92 @@
+package toolcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// memoryEntry is a result stored in a MemoryStore
+type memoryEntry struct {
+	result    string
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store in the memory of the process
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	sets    int
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+// Get implements Store.Get
+func (s *MemoryStore) Get(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return "", false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return "", false, nil
+	}
+	return entry.result, true, nil
+}
+
+// Set implements Store.Set
+func (s *MemoryStore) Set(ctx context.Context, key, result string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.entries[key] = memoryEntry{result: result, expiresAt: now.Add(ttl)}
+
+	// Drop expired entries from time to time, as entries that are not read
+	// again are never removed by Get
+	s.sets++
+	if s.sets%1000 == 0 {
+		for k, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	return nil
+}
+
+// RedisStore is a Store in Redis, shared by the replicas of a service
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a store in Redis
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Get implements Store.Get
+func (s *RedisStore) Get(ctx context.Context, key string) (string, bool, error) {
+	result, err := s.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return result, true, nil
+}
+
+// Set implements Store.Set
+func (s *RedisStore) Set(ctx context.Context, key, result string, ttl time.Duration) error {
+	return s.client.Set(ctx, key, result, ttl).Err()
+}
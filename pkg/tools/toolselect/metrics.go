@@ -0,0 +1,112 @@
+package toolselect
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// Metrics contains runtime statistics about tool selection
+type Metrics struct {
+	// Selections is the number of times Select narrowed the tool set
+	Selections int
+
+	// CandidateTools is the total number of tools considered across selections
+	CandidateTools int
+
+	// SelectedTools is the total number of tools sent to the model across selections
+	SelectedTools int
+
+	// ToolCalls is the number of times the model invoked a selected tool
+	ToolCalls int
+}
+
+// ReductionRatio returns the fraction of tool schemas that were not sent to the model
+func (m Metrics) ReductionRatio() float64 {
+	if m.CandidateTools == 0 {
+		return 0
+	}
+	return 1 - float64(m.SelectedTools)/float64(m.CandidateTools)
+}
+
+// CallsPerSelection returns the average number of tool calls per selection
+func (m Metrics) CallsPerSelection() float64 {
+	if m.Selections == 0 {
+		return 0
+	}
+	return float64(m.ToolCalls) / float64(m.Selections)
+}
+
+// EvalCase is a labelled query used to measure selection accuracy
+type EvalCase struct {
+	// Query is the user turn
+	Query string
+
+	// ExpectedTools are the names of the tools that should be selected
+	ExpectedTools []string
+}
+
+// EvalResult reports selection accuracy over a set of labelled cases
+type EvalResult struct {
+	// Cases is the number of evaluated cases
+	Cases int
+
+	// Recall is the fraction of expected tools that were selected
+	Recall float64
+
+	// HitRate is the fraction of cases where every expected tool was selected
+	HitRate float64
+
+	// Misses maps a query to the expected tools that were not selected
+	Misses map[string][]string
+}
+
+// Evaluate runs the selector against labelled cases and reports recall and
+// hit rate. It does not update the runtime metrics.
+func (s *Selector) Evaluate(ctx context.Context, tools []interfaces.Tool, cases []EvalCase) (*EvalResult, error) {
+	result := &EvalResult{
+		Misses: make(map[string][]string),
+	}
+
+	expectedTotal, foundTotal, hits := 0, 0, 0
+	for _, c := range cases {
+		selected := tools
+		if len(tools) > s.threshold {
+			var err error
+			selected, err = s.rank(ctx, c.Query, tools)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate query %q: %w", c.Query, err)
+			}
+		}
+
+		names := make(map[string]bool, len(selected))
+		for _, tool := range selected {
+			names[tool.Name()] = true
+		}
+
+		allFound := true
+		for _, expected := range c.ExpectedTools {
+			expectedTotal++
+			if names[expected] {
+				foundTotal++
+			} else {
+				allFound = false
+				result.Misses[c.Query] = append(result.Misses[c.Query], expected)
+			}
+		}
+		if allFound {
+			hits++
+		}
+		result.Cases++
+	}
+
+	if expectedTotal > 0 {
+		result.Recall = float64(foundTotal) / float64(expectedTotal)
+	}
+	if result.Cases > 0 {
+		result.HitRate = float64(hits) / float64(result.Cases)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,273 @@
+// Package toolselect provides embedding-based dynamic tool selection.
+//
+// Agents with many tools can exceed the model's context budget just by sending
+// every tool schema on every turn. A Selector embeds each tool's name and
+// description once, then per user turn returns only the top-k most relevant
+// tools (plus any pinned tools), so only those schemas are sent to the model.
+package toolselect
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// DefaultTopK is the default number of tools selected per turn
+const DefaultTopK = 8
+
+// Selector selects the tools most relevant to a query using embeddings
+type Selector struct {
+	embedder  interfaces.Embedder
+	topK      int
+	minScore  float32
+	metric    string
+	pinned    map[string]bool
+	threshold int
+
+	mu         sync.RWMutex
+	embeddings map[string][]float32 // keyed by tool name + description
+	metrics    Metrics
+}
+
+// Option represents an option for configuring the selector
+type Option func(*Selector)
+
+// WithTopK sets the number of tools to select per turn (pinned tools are not counted)
+func WithTopK(k int) Option {
+	return func(s *Selector) {
+		s.topK = k
+	}
+}
+
+// WithPinnedTools sets tools that are always sent to the model regardless of relevance
+func WithPinnedTools(names ...string) Option {
+	return func(s *Selector) {
+		for _, name := range names {
+			s.pinned[name] = true
+		}
+	}
+}
+
+// WithMinScore drops tools whose similarity to the query is below the given score
+func WithMinScore(score float32) Option {
+	return func(s *Selector) {
+		s.minScore = score
+	}
+}
+
+// WithSimilarityMetric sets the metric passed to the embedder ("cosine", "euclidean", "dot_product")
+func WithSimilarityMetric(metric string) Option {
+	return func(s *Selector) {
+		s.metric = metric
+	}
+}
+
+// WithThreshold disables selection when the agent has at most this many tools,
+// in which case all tools are sent. Defaults to the top-k value.
+func WithThreshold(n int) Option {
+	return func(s *Selector) {
+		s.threshold = n
+	}
+}
+
+// New creates a new tool selector
+func New(embedder interfaces.Embedder, options ...Option) *Selector {
+	s := &Selector{
+		embedder:   embedder,
+		topK:       DefaultTopK,
+		metric:     "cosine",
+		pinned:     make(map[string]bool),
+		threshold:  -1,
+		embeddings: make(map[string][]float32),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.threshold < 0 {
+		s.threshold = s.topK
+	}
+
+	return s
+}
+
+// Select returns the pinned tools plus the top-k tools most relevant to the query.
+// The returned tools are wrapped so that invocations are recorded in the
+// selector's metrics.
+func (s *Selector) Select(ctx context.Context, query string, tools []interfaces.Tool) ([]interfaces.Tool, error) {
+	if len(tools) <= s.threshold || strings.TrimSpace(query) == "" {
+		return tools, nil
+	}
+
+	selected, err := s.rank(ctx, query, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.metrics.Selections++
+	s.metrics.CandidateTools += len(tools)
+	s.metrics.SelectedTools += len(selected)
+	s.mu.Unlock()
+
+	wrapped := make([]interfaces.Tool, len(selected))
+	for i, tool := range selected {
+		wrapped[i] = &selectedTool{inner: tool, selector: s}
+	}
+	return wrapped, nil
+}
+
+// rank returns the pinned tools followed by the top-k scoring tools
+func (s *Selector) rank(ctx context.Context, query string, tools []interfaces.Tool) ([]interfaces.Tool, error) {
+	if err := s.ensureEmbeddings(ctx, tools); err != nil {
+		return nil, err
+	}
+
+	queryVector, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	type scoredTool struct {
+		tool  interfaces.Tool
+		score float32
+	}
+
+	selected := make([]interfaces.Tool, 0, s.topK+len(s.pinned))
+	candidates := make([]scoredTool, 0, len(tools))
+
+	s.mu.RLock()
+	for _, tool := range tools {
+		if s.pinned[tool.Name()] {
+			selected = append(selected, tool)
+			continue
+		}
+		score, err := s.embedder.CalculateSimilarity(queryVector, s.embeddings[embeddingKey(tool)], s.metric)
+		if err != nil {
+			s.mu.RUnlock()
+			return nil, fmt.Errorf("failed to score tool %s: %w", tool.Name(), err)
+		}
+		if score < s.minScore {
+			continue
+		}
+		candidates = append(candidates, scoredTool{tool: tool, score: score})
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	for i := 0; i < len(candidates) && i < s.topK; i++ {
+		selected = append(selected, candidates[i].tool)
+	}
+
+	return selected, nil
+}
+
+// ensureEmbeddings embeds every tool that has not been embedded yet
+func (s *Selector) ensureEmbeddings(ctx context.Context, tools []interfaces.Tool) error {
+	s.mu.RLock()
+	var missing []interfaces.Tool
+	for _, tool := range tools {
+		if s.pinned[tool.Name()] {
+			continue
+		}
+		if _, ok := s.embeddings[embeddingKey(tool)]; !ok {
+			missing = append(missing, tool)
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	texts := make([]string, len(missing))
+	for i, tool := range missing {
+		texts[i] = embeddingKey(tool)
+	}
+
+	vectors, err := s.embedder.EmbedBatch(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed tool descriptions: %w", err)
+	}
+	if len(vectors) != len(missing) {
+		return fmt.Errorf("embedder returned %d vectors for %d tools", len(vectors), len(missing))
+	}
+
+	s.mu.Lock()
+	for i, text := range texts {
+		s.embeddings[text] = vectors[i]
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// embeddingKey is the text embedded for a tool; it also keys the cache so a
+// tool whose description changes is re-embedded.
+func embeddingKey(tool interfaces.Tool) string {
+	return tool.Name() + ": " + tool.Description()
+}
+
+// recordCall records that the model invoked a selected tool
+func (s *Selector) recordCall() {
+	s.mu.Lock()
+	s.metrics.ToolCalls++
+	s.mu.Unlock()
+}
+
+// Metrics returns a snapshot of the selection metrics
+func (s *Selector) Metrics() Metrics {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.metrics
+}
+
+// ResetMetrics clears the selection metrics
+func (s *Selector) ResetMetrics() {
+	s.mu.Lock()
+	s.metrics = Metrics{}
+	s.mu.Unlock()
+}
+
+// selectedTool wraps a selected tool so its invocations are counted
+type selectedTool struct {
+	inner    interfaces.Tool
+	selector *Selector
+}
+
+func (t *selectedTool) Name() string                                    { return t.inner.Name() }
+func (t *selectedTool) Description() string                             { return t.inner.Description() }
+func (t *selectedTool) Parameters() map[string]interfaces.ParameterSpec { return t.inner.Parameters() }
+
+func (t *selectedTool) Run(ctx context.Context, input string) (string, error) {
+	t.selector.recordCall()
+	return t.inner.Run(ctx, input)
+}
+
+func (t *selectedTool) Execute(ctx context.Context, args string) (string, error) {
+	t.selector.recordCall()
+	return t.inner.Execute(ctx, args)
+}
+
+// DisplayName forwards to the inner tool when it implements ToolWithDisplayName.
+func (t *selectedTool) DisplayName() string {
+	if d, ok := t.inner.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.inner.Name()
+}
+
+// Internal forwards to the inner tool when it implements InternalTool.
+func (t *selectedTool) Internal() bool {
+	if i, ok := t.inner.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
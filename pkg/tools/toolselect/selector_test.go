@@ -0,0 +1,144 @@
+package toolselect
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/embedding"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// keywordEmbedder embeds text as a bag of known keywords
+type keywordEmbedder struct {
+	vocabulary []string
+}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	text = strings.ToLower(text)
+	vector := make([]float32, len(e.vocabulary)+1)
+	vector[len(e.vocabulary)] = 0.01 // avoid zero vectors
+	for i, word := range e.vocabulary {
+		if strings.Contains(text, word) {
+			vector[i] = 1
+		}
+	}
+	return vector, nil
+}
+
+func (e *keywordEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.Embed(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (e *keywordEmbedder) CalculateSimilarity(vec1, vec2 []float32, metric string) (float32, error) {
+	return embedding.CalculateSimilarity(vec1, vec2, metric)
+}
+
+type stubTool struct {
+	name        string
+	description string
+	calls       int
+}
+
+func (t *stubTool) Name() string        { return t.name }
+func (t *stubTool) Description() string { return t.description }
+func (t *stubTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{}
+}
+func (t *stubTool) Run(ctx context.Context, input string) (string, error) {
+	t.calls++
+	return "ok", nil
+}
+func (t *stubTool) Execute(ctx context.Context, args string) (string, error) {
+	t.calls++
+	return "ok", nil
+}
+
+func testTools() []interfaces.Tool {
+	return []interfaces.Tool{
+		&stubTool{name: "weather", description: "Get the weather forecast for a city"},
+		&stubTool{name: "calculator", description: "Evaluate math expressions"},
+		&stubTool{name: "email", description: "Send an email message"},
+		&stubTool{name: "calendar", description: "Create a calendar event"},
+		&stubTool{name: "help", description: "Show help"},
+	}
+}
+
+func newTestSelector(options ...Option) *Selector {
+	embedder := &keywordEmbedder{vocabulary: []string{"weather", "math", "email", "calendar", "help"}}
+	return New(embedder, options...)
+}
+
+func TestSelectTopKWithPins(t *testing.T) {
+	s := newTestSelector(WithTopK(1), WithPinnedTools("help"), WithThreshold(2))
+
+	selected, err := s.Select(context.Background(), "what's the weather in Paris?", testTools())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, tool := range selected {
+		names = append(names, tool.Name())
+	}
+	if len(names) != 2 || names[0] != "help" || names[1] != "weather" {
+		t.Fatalf("expected [help weather], got %v", names)
+	}
+
+	if _, err := selected[1].Execute(context.Background(), "{}"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metrics := s.Metrics()
+	if metrics.Selections != 1 || metrics.CandidateTools != 5 || metrics.SelectedTools != 2 || metrics.ToolCalls != 1 {
+		t.Errorf("unexpected metrics: %+v", metrics)
+	}
+	if ratio := metrics.ReductionRatio(); ratio < 0.59 || ratio > 0.61 {
+		t.Errorf("expected reduction ratio 0.6, got %f", ratio)
+	}
+}
+
+func TestSelectBelowThresholdReturnsAllTools(t *testing.T) {
+	s := newTestSelector(WithTopK(10))
+
+	tools := testTools()
+	selected, err := s.Select(context.Background(), "weather", tools)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selected) != len(tools) {
+		t.Errorf("expected all %d tools, got %d", len(tools), len(selected))
+	}
+	if s.Metrics().Selections != 0 {
+		t.Error("expected no selection to be recorded")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	s := newTestSelector(WithTopK(1), WithThreshold(1))
+
+	result, err := s.Evaluate(context.Background(), testTools(), []EvalCase{
+		{Query: "send an email to Bob", ExpectedTools: []string{"email"}},
+		{Query: "schedule a calendar meeting and email everyone", ExpectedTools: []string{"calendar", "email"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Cases != 2 {
+		t.Errorf("expected 2 cases, got %d", result.Cases)
+	}
+	if result.HitRate != 0.5 {
+		t.Errorf("expected hit rate 0.5, got %f", result.HitRate)
+	}
+	if result.Recall < 0.66 || result.Recall > 0.67 {
+		t.Errorf("expected recall 2/3, got %f", result.Recall)
+	}
+	if s.Metrics().Selections != 0 {
+		t.Error("evaluation should not update runtime metrics")
+	}
+}
@@ -0,0 +1,284 @@
+package videogen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// Tool implements video generation as a tool for agents. It starts a
+// generation job, polls it until the video is ready and stores the video.
+type Tool struct {
+	generator       interfaces.VideoGenerator
+	storage         storage.MediaStorage
+	maxPromptLen    int
+	defaultAspect   string
+	defaultDuration int
+	resolution      string
+	pollInterval    time.Duration
+	timeout         time.Duration
+	logger          logging.Logger
+}
+
+// Option represents an option for configuring the tool
+type Option func(*Tool)
+
+// WithMaxPromptLength sets maximum prompt length
+func WithMaxPromptLength(maxLen int) Option {
+	return func(t *Tool) {
+		t.maxPromptLen = maxLen
+	}
+}
+
+// WithDefaultAspectRatio sets the default aspect ratio
+func WithDefaultAspectRatio(ratio string) Option {
+	return func(t *Tool) {
+		t.defaultAspect = ratio
+	}
+}
+
+// WithDefaultDuration sets the default video length in seconds
+func WithDefaultDuration(seconds int) Option {
+	return func(t *Tool) {
+		t.defaultDuration = seconds
+	}
+}
+
+// WithResolution sets the video resolution ("720p" or "1080p")
+func WithResolution(resolution string) Option {
+	return func(t *Tool) {
+		t.resolution = resolution
+	}
+}
+
+// WithPollInterval sets how often the generation job is polled
+func WithPollInterval(interval time.Duration) Option {
+	return func(t *Tool) {
+		t.pollInterval = interval
+	}
+}
+
+// WithTimeout sets how long to wait for a video before giving up
+func WithTimeout(timeout time.Duration) Option {
+	return func(t *Tool) {
+		t.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger used to report generation progress
+func WithLogger(logger logging.Logger) Option {
+	return func(t *Tool) {
+		t.logger = logger
+	}
+}
+
+// New creates a new video generation tool. Videos are too large to return
+// inline, so storage should be configured to get shareable URLs.
+func New(generator interfaces.VideoGenerator, storage storage.MediaStorage, options ...Option) *Tool {
+	tool := &Tool{
+		generator:       generator,
+		storage:         storage,
+		maxPromptLen:    2000,
+		defaultAspect:   "16:9",
+		defaultDuration: 8,
+		resolution:      "720p",
+		pollInterval:    10 * time.Second,
+		timeout:         10 * time.Minute,
+	}
+
+	for _, opt := range options {
+		opt(tool)
+	}
+
+	if tool.logger == nil {
+		tool.logger = logging.New()
+	}
+
+	return tool
+}
+
+// Name returns the tool name
+func (t *Tool) Name() string {
+	return "generate_video"
+}
+
+// DisplayName returns a human-friendly name
+func (t *Tool) DisplayName() string {
+	return "Video Generator"
+}
+
+// Description returns what the tool does
+func (t *Tool) Description() string {
+	return "Generate short videos from text descriptions using AI. Provide a detailed prompt describing the scene, subjects, camera movement and style. Generation takes a few minutes. Returns the URL of the generated MP4 video."
+}
+
+// Internal returns false as this is a user-visible tool
+func (t *Tool) Internal() bool {
+	return false
+}
+
+// Parameters returns the tool's parameter specifications
+func (t *Tool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{
+		"prompt": {
+			Type:        "string",
+			Description: "A detailed text description of the video to generate.",
+			Required:    true,
+		},
+		"aspect_ratio": {
+			Type:        "string",
+			Description: "The aspect ratio of the video",
+			Required:    false,
+			Default:     t.defaultAspect,
+			Enum:        []interface{}{"16:9", "9:16"},
+		},
+		"duration_seconds": {
+			Type:        "integer",
+			Description: "The length of the video in seconds",
+			Required:    false,
+			Default:     t.defaultDuration,
+		},
+		"negative_prompt": {
+			Type:        "string",
+			Description: "What the video should not contain",
+			Required:    false,
+		},
+	}
+}
+
+// Run executes the tool with the given input
+func (t *Tool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// Execute implements the tool execution
+func (t *Tool) Execute(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Prompt          string `json:"prompt"`
+		AspectRatio     string `json:"aspect_ratio,omitempty"`
+		DurationSeconds int    `json:"duration_seconds,omitempty"`
+		NegativePrompt  string `json:"negative_prompt,omitempty"`
+	}
+
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse arguments: %w", err)
+	}
+
+	if params.Prompt == "" {
+		return "", fmt.Errorf("prompt is required")
+	}
+	if len(params.Prompt) > t.maxPromptLen {
+		return "", fmt.Errorf("prompt exceeds maximum length of %d characters", t.maxPromptLen)
+	}
+	if params.AspectRatio == "" {
+		params.AspectRatio = t.defaultAspect
+	}
+	if params.DurationSeconds <= 0 {
+		params.DurationSeconds = t.defaultDuration
+	}
+
+	request := interfaces.VideoGenerationRequest{
+		Prompt: params.Prompt,
+		Options: &interfaces.VideoGenerationOptions{
+			AspectRatio:     params.AspectRatio,
+			DurationSeconds: params.DurationSeconds,
+			Resolution:      t.resolution,
+			NegativePrompt:  params.NegativePrompt,
+		},
+	}
+
+	job, err := t.generate(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	if len(job.Videos) == 0 {
+		return "", fmt.Errorf("no videos were generated")
+	}
+	video := &job.Videos[0]
+
+	if t.storage == nil || len(video.Data) == 0 {
+		// Without storage, only a provider URL (if any) can be returned
+		t.logger.Debug(ctx, "Video not stored", map[string]interface{}{"url": video.URL})
+		return t.formatResult(video, params.Prompt, video.URL), nil
+	}
+
+	metadata := storage.StorageMetadata{
+		Prompt:    params.Prompt,
+		Tool:      t.Name(),
+		CreatedAt: time.Now(),
+	}
+	metadata.OrgID, _ = multitenancy.GetOrgID(ctx)
+	metadata.ThreadID, _ = memory.GetConversationID(ctx)
+
+	url, err := t.storage.Store(ctx, video, metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to store video: %w", err)
+	}
+	video.URL = url
+	t.logger.Debug(ctx, "Video stored", map[string]interface{}{"url": url})
+	return t.formatResult(video, params.Prompt, url), nil
+}
+
+// generate starts a generation job and polls it until it is done, the
+// timeout passes or ctx is cancelled
+func (t *Tool) generate(ctx context.Context, request interfaces.VideoGenerationRequest) (*interfaces.VideoGenerationJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	job, err := t.generator.StartVideoGeneration(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("video generation failed: %w", err)
+	}
+	t.logger.Debug(ctx, "Video generation started", map[string]interface{}{"job_id": job.ID})
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for !job.Done {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("video generation did not finish in time (job %s): %w", job.ID, ctx.Err())
+		case <-ticker.C:
+		}
+
+		job, err = t.generator.PollVideoGeneration(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+// formatResult creates a human-readable result string with the video URL.
+// The video is linked using markdown syntax so UIs can render it.
+func (t *Tool) formatResult(video *interfaces.GeneratedMedia, prompt, videoURL string) string {
+	result := fmt.Sprintf("Successfully generated video for prompt: \"%s\"\n\n", truncateString(prompt, 100))
+
+	if videoURL != "" {
+		result += fmt.Sprintf("[Generated video](%s)\n\n", videoURL)
+	} else {
+		result += "Note: Video was generated but no storage is configured to save it.\n"
+		result += "Configure GCS or local storage to get shareable video URLs.\n\n"
+	}
+
+	result += fmt.Sprintf("Format: %s\n", video.MimeType)
+	if len(video.Data) > 0 {
+		result += fmt.Sprintf("Size: %d bytes\n", len(video.Data))
+	}
+	return result
+}
+
+// truncateString truncates a string to maxLen characters
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen] + "..."
+}
@@ -0,0 +1,97 @@
+package videogen
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	"github.com/Ingenimax/agent-sdk-go/pkg/storage"
+)
+
+// fakeGenerator finishes a job after the given number of polls
+type fakeGenerator struct {
+	polls   int
+	request interfaces.VideoGenerationRequest
+	err     error
+}
+
+func (g *fakeGenerator) StartVideoGeneration(ctx context.Context, request interfaces.VideoGenerationRequest) (*interfaces.VideoGenerationJob, error) {
+	g.request = request
+	return &interfaces.VideoGenerationJob{ID: "job-1"}, nil
+}
+
+func (g *fakeGenerator) PollVideoGeneration(ctx context.Context, job *interfaces.VideoGenerationJob) (*interfaces.VideoGenerationJob, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	g.polls--
+	if g.polls > 0 {
+		return job, nil
+	}
+	return &interfaces.VideoGenerationJob{
+		ID:     job.ID,
+		Done:   true,
+		Videos: []interfaces.GeneratedMedia{{Data: []byte("mp4"), MimeType: "video/mp4"}},
+	}, nil
+}
+
+type fakeStorage struct {
+	media    *interfaces.GeneratedMedia
+	metadata storage.StorageMetadata
+}
+
+func (s *fakeStorage) Store(ctx context.Context, media *interfaces.GeneratedMedia, metadata storage.StorageMetadata) (string, error) {
+	s.media, s.metadata = media, metadata
+	return "https://cdn.example.com/video.mp4", nil
+}
+
+func (s *fakeStorage) Delete(ctx context.Context, url string) error { return nil }
+
+func (s *fakeStorage) Get(ctx context.Context, url string) ([]byte, error) { return nil, nil }
+
+func (s *fakeStorage) Name() string { return "fake" }
+
+func TestExecute(t *testing.T) {
+	generator := &fakeGenerator{polls: 3}
+	store := &fakeStorage{}
+	tool := New(generator, store, WithPollInterval(time.Millisecond), WithDefaultDuration(4))
+
+	ctx := multitenancy.WithOrgID(context.Background(), "org-a")
+	result, err := tool.Execute(ctx, `{"prompt":"A cat surfing at sunset","aspect_ratio":"9:16"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "[Generated video](https://cdn.example.com/video.mp4)") {
+		t.Errorf("expected the video URL in the result, got %q", result)
+	}
+	if generator.polls != 0 {
+		t.Errorf("expected the job to be polled until done")
+	}
+	if opts := generator.request.Options; opts.AspectRatio != "9:16" || opts.DurationSeconds != 4 || opts.Resolution != "720p" {
+		t.Errorf("unexpected options %+v", opts)
+	}
+	if string(store.media.Data) != "mp4" || store.metadata.OrgID != "org-a" || store.metadata.Prompt != "A cat surfing at sunset" {
+		t.Errorf("unexpected stored video %+v %+v", store.media, store.metadata)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	blocked := &fakeGenerator{polls: 1, err: interfaces.ErrContentBlocked}
+	if _, err := New(blocked, nil, WithPollInterval(time.Millisecond)).Execute(context.Background(), `{"prompt":"x"}`); !errors.Is(err, interfaces.ErrContentBlocked) {
+		t.Errorf("expected ErrContentBlocked, got %v", err)
+	}
+
+	slow := &fakeGenerator{polls: 1000}
+	tool := New(slow, nil, WithPollInterval(time.Millisecond), WithTimeout(20*time.Millisecond))
+	if _, err := tool.Execute(context.Background(), `{"prompt":"x"}`); err == nil || !strings.Contains(err.Error(), "did not finish in time") {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+
+	if _, err := tool.Execute(context.Background(), `{}`); err == nil {
+		t.Error("expected an error without a prompt")
+	}
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Attribute keys from the OpenTelemetry semantic conventions for generative
+// AI systems (https://opentelemetry.io/docs/specs/semconv/gen-ai/)
+const (
+	GenAIOperationName         = "gen_ai.operation.name"
+	GenAISystem                = "gen_ai.system"
+	GenAIRequestModel          = "gen_ai.request.model"
+	GenAIResponseModel         = "gen_ai.response.model"
+	GenAIResponseFinishReasons = "gen_ai.response.finish_reasons"
+	GenAIUsageInputTokens      = "gen_ai.usage.input_tokens"
+	GenAIUsageOutputTokens     = "gen_ai.usage.output_tokens"
+	GenAIAgentName             = "gen_ai.agent.name"
+	GenAIToolName              = "gen_ai.tool.name"
+	GenAIToolDescription       = "gen_ai.tool.description"
+)
+
+// Values of the gen_ai.operation.name attribute
+const (
+	GenAIOperationChat        = "chat"
+	GenAIOperationInvokeAgent = "invoke_agent"
+	GenAIOperationExecuteTool = "execute_tool"
+)
+
+// setGenAIRequestAttributes sets the GenAI attributes of an LLM request
+func setGenAIRequestAttributes(span interfaces.Span, system, model string) {
+	span.SetAttribute(GenAIOperationName, GenAIOperationChat)
+	span.SetAttribute(GenAISystem, system)
+	span.SetAttribute(GenAIRequestModel, model)
+}
+
+// setGenAIResponseAttributes sets the GenAI attributes of an LLM response
+func setGenAIResponseAttributes(span interfaces.Span, response *interfaces.LLMResponse) {
+	if response.Model != "" {
+		span.SetAttribute(GenAIResponseModel, response.Model)
+	}
+	if response.StopReason != "" {
+		span.SetAttribute(GenAIResponseFinishReasons, []string{response.StopReason})
+	}
+	if response.Usage != nil {
+		span.SetAttribute(GenAIUsageInputTokens, response.Usage.InputTokens)
+		span.SetAttribute(GenAIUsageOutputTokens, response.Usage.OutputTokens)
+	}
+}
+
+// toAttribute converts a span attribute value to a typed OpenTelemetry
+// attribute, so numbers and lists keep their type. Other values are
+// formatted as strings.
+func toAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	default:
+		return attribute.String(key, fmt.Sprintf("%v", v))
+	}
+}
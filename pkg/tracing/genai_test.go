@@ -0,0 +1,137 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type genaiTestLLM struct{}
+
+func (l *genaiTestLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	return "ok", nil
+}
+
+func (l *genaiTestLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return "ok", nil
+}
+
+func (l *genaiTestLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return &interfaces.LLMResponse{
+		Content:    "ok",
+		Model:      "test-model-2024",
+		StopReason: "stop",
+		Usage:      &interfaces.TokenUsage{InputTokens: 12, OutputTokens: 3, TotalTokens: 15},
+	}, nil
+}
+
+func (l *genaiTestLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return l.GenerateDetailed(ctx, prompt, options...)
+}
+
+func (l *genaiTestLLM) Name() string            { return "test" }
+func (l *genaiTestLLM) GetModel() string        { return "test-model" }
+func (l *genaiTestLLM) SupportsStreaming() bool { return false }
+
+type genaiTestTool struct {
+	err error
+}
+
+func (t *genaiTestTool) Name() string        { return "lookup" }
+func (t *genaiTestTool) Description() string { return "Looks things up" }
+func (t *genaiTestTool) Parameters() map[string]interfaces.ParameterSpec {
+	return nil
+}
+func (t *genaiTestTool) Run(ctx context.Context, input string) (string, error) {
+	return "found", t.err
+}
+func (t *genaiTestTool) Execute(ctx context.Context, args string) (string, error) {
+	return t.Run(ctx, args)
+}
+
+func newRecordingTracer() (*OTelTracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return NewOTelTracerWrapper(provider.Tracer("test")), recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracedLLMGenAIAttributes(t *testing.T) {
+	tracer, recorder := newRecordingTracer()
+
+	llm := NewTracedLLM(&genaiTestLLM{}, tracer)
+	if _, err := llm.GenerateDetailed(context.Background(), "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	attrs := spanAttributes(spans[0])
+
+	if got := attrs[GenAIOperationName].AsString(); got != GenAIOperationChat {
+		t.Errorf("expected operation %q, got %q", GenAIOperationChat, got)
+	}
+	if got := attrs[GenAISystem].AsString(); got != "test" {
+		t.Errorf("expected system test, got %q", got)
+	}
+	if got := attrs[GenAIRequestModel].AsString(); got != "test-model" {
+		t.Errorf("expected request model test-model, got %q", got)
+	}
+	if got := attrs[GenAIResponseModel].AsString(); got != "test-model-2024" {
+		t.Errorf("expected response model test-model-2024, got %q", got)
+	}
+	if got := attrs[GenAIUsageInputTokens]; got.Type() != attribute.INT64 || got.AsInt64() != 12 {
+		t.Errorf("expected 12 input tokens as an integer, got %v", got.Emit())
+	}
+	if got := attrs[GenAIUsageOutputTokens].AsInt64(); got != 3 {
+		t.Errorf("expected 3 output tokens, got %d", got)
+	}
+	if got := attrs[GenAIResponseFinishReasons].AsStringSlice(); len(got) != 1 || got[0] != "stop" {
+		t.Errorf("expected finish reasons [stop], got %v", got)
+	}
+}
+
+func TestTracedTool(t *testing.T) {
+	tracer, recorder := newRecordingTracer()
+
+	tool := NewTracedTool(&genaiTestTool{}, tracer)
+	if result, err := tool.Run(context.Background(), `{"q":"x"}`); err != nil || result != "found" {
+		t.Fatalf("expected tool result, got %q, %v", result, err)
+	}
+
+	failing := NewTracedTool(&genaiTestTool{err: errors.New("lookup failed")}, tracer)
+	if _, err := failing.Execute(context.Background(), "{}"); err == nil {
+		t.Fatal("expected tool error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		attrs := spanAttributes(span)
+		if got := attrs[GenAIOperationName].AsString(); got != GenAIOperationExecuteTool {
+			t.Errorf("expected operation %q, got %q", GenAIOperationExecuteTool, got)
+		}
+		if got := attrs[GenAIToolName].AsString(); got != "lookup" {
+			t.Errorf("expected tool name lookup, got %q", got)
+		}
+	}
+	if len(spans[1].Events()) == 0 {
+		t.Error("expected the tool error to be recorded")
+	}
+}
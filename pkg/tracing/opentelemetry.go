@@ -44,7 +44,7 @@ func (s *OTelSpan) AddEvent(name string, attributes map[string]interface{}) {
 
 // SetAttribute implements interfaces.Span
 func (s *OTelSpan) SetAttribute(key string, value interface{}) {
-	s.span.SetAttributes(attribute.String(key, fmt.Sprintf("%v", value)))
+	s.span.SetAttributes(toAttribute(key, value))
 }
 
 func (s *OTelSpan) RecordError(err error) {
@@ -53,7 +53,7 @@ func (s *OTELLangfuseSpan) AddEvent(name string, attributes map[string]interface
 
 // SetAttribute implements interfaces.Span
 func (s *OTELLangfuseSpan) SetAttribute(key string, value interface{}) {
-	s.span.SetAttributes(attribute.String(key, fmt.Sprintf("%v", value)))
+	s.span.SetAttributes(toAttribute(key, value))
 }
 
 func (s *OTELLangfuseSpan) RecordError(err error) {
@@ -447,12 +447,9 @@ func GetToolCallsFromContext(ctx context.Context) []ToolCall {
 // createToolCallSpansAsTraceItems creates individual spans for each tool call at the trace root level
 func (t *OTELLangfuseTracer) createToolCallSpansAsTraceItems(ctx context.Context, toolCalls []ToolCall) {
 	if !t.enabled || len(toolCalls) == 0 {
-		fmt.Printf("DEBUG: Tool call spans not created - enabled: %v, toolCalls count: %d\n", t.enabled, len(toolCalls))
 		return
 	}
 
-	fmt.Printf("DEBUG: Creating %d tool call spans\n", len(toolCalls))
-
 	// Get organization ID from context
 	orgID, _ := multitenancy.GetOrgID(ctx)
 
@@ -55,6 +55,7 @@ func (m *TracedLLM) Generate(ctx context.Context, prompt string, options ...inte
 		model = m.llm.Name() // fallback to provider name
 	}
 	span.SetAttribute("model", model)
+	setGenAIRequestAttributes(span, m.llm.Name(), model)
 
 	// Call the underlying LLM
 	response, err := m.llm.Generate(ctx, prompt, options...)
@@ -106,6 +107,7 @@ func (m *TracedLLM) GenerateWithTools(ctx context.Context, prompt string, tools
 			model = m.llm.Name() // fallback to provider name
 		}
 		span.SetAttribute("model", model)
+		setGenAIRequestAttributes(span, m.llm.Name(), model)
 
 		// Add tool names if available
 		if len(tools) > 0 {
@@ -219,6 +221,7 @@ func (m *TracedLLM) GenerateStream(ctx context.Context, prompt string, options .
 		model = m.llm.Name() // fallback to provider name
 	}
 	span.SetAttribute("model", model)
+	setGenAIRequestAttributes(span, m.llm.Name(), model)
 
 	// Include actual prompt content if configured (response is streamed)
 	if m.shouldIncludeContent() {
@@ -255,6 +258,7 @@ func (m *TracedLLM) GenerateWithToolsStream(ctx context.Context, prompt string,
 		model = m.llm.Name() // fallback to provider name
 	}
 	span.SetAttribute("model", model)
+	setGenAIRequestAttributes(span, m.llm.Name(), model)
 
 	// Add tool names if available
 	if len(tools) > 0 {
@@ -353,6 +357,7 @@ func (m *TracedLLM) GenerateDetailed(ctx context.Context, prompt string, options
 		model = m.llm.Name() // fallback to provider name
 	}
 	span.SetAttribute("model", model)
+	setGenAIRequestAttributes(span, m.llm.Name(), model)
 
 	// Call the underlying LLM
 	response, err := m.llm.GenerateDetailed(ctx, prompt, options...)
@@ -376,6 +381,7 @@ func (m *TracedLLM) GenerateDetailed(ctx context.Context, prompt string, options
 			span.SetAttribute("usage.reasoning_tokens", response.Usage.ReasoningTokens)
 		}
 	}
+	setGenAIResponseAttributes(span, response)
 	span.SetAttribute("duration_ms", duration.Milliseconds())
 
 	// Include actual content if configured
@@ -409,6 +415,7 @@ func (m *TracedLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string
 		model = m.llm.Name() // fallback to provider name
 	}
 	span.SetAttribute("model", model)
+	setGenAIRequestAttributes(span, m.llm.Name(), model)
 
 	// Add tool names as attributes
 	toolNames := make([]string, len(tools))
@@ -439,7 +446,22 @@ func (m *TracedLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string
 			span.SetAttribute("usage.reasoning_tokens", response.Usage.ReasoningTokens)
 		}
 	}
+	setGenAIResponseAttributes(span, response)
 	span.SetAttribute("duration_ms", duration.Milliseconds())
 
 	return response, nil
 }
+
+// Unwrap returns the underlying LLM, e.g. to reach the interfaces it
+// implements that the middleware does not forward
+func (m *TracedLLM) Unwrap() interfaces.LLM {
+	return m.llm
+}
+
+// WarmConnections implements interfaces.ConnectionWarmer when the underlying LLM does
+func (m *TracedLLM) WarmConnections(ctx context.Context) error {
+	if warmer, ok := m.llm.(interfaces.ConnectionWarmer); ok {
+		return warmer.WarmConnections(ctx)
+	}
+	return nil
+}
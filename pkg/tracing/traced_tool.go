@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// TracedTool implements middleware for tool executions with unified tracing
+type TracedTool struct {
+	tool   interfaces.Tool
+	tracer interfaces.Tracer
+}
+
+// NewTracedTool creates a new tool middleware with unified tracing
+func NewTracedTool(tool interfaces.Tool, tracer interfaces.Tracer) *TracedTool {
+	return &TracedTool{
+		tool:   tool,
+		tracer: tracer,
+	}
+}
+
+// Name implements interfaces.Tool.Name
+func (t *TracedTool) Name() string {
+	return t.tool.Name()
+}
+
+// Description implements interfaces.Tool.Description
+func (t *TracedTool) Description() string {
+	return t.tool.Description()
+}
+
+// Parameters implements interfaces.Tool.Parameters
+func (t *TracedTool) Parameters() map[string]interfaces.ParameterSpec {
+	return t.tool.Parameters()
+}
+
+// Run executes the tool with tracing
+func (t *TracedTool) Run(ctx context.Context, input string) (string, error) {
+	return t.trace(ctx, input, t.tool.Run)
+}
+
+// Execute executes the tool with tracing
+func (t *TracedTool) Execute(ctx context.Context, args string) (string, error) {
+	return t.trace(ctx, args, t.tool.Execute)
+}
+
+func (t *TracedTool) trace(ctx context.Context, args string, run func(context.Context, string) (string, error)) (string, error) {
+	startTime := time.Now()
+
+	// Start span
+	ctx, span := t.tracer.StartSpan(ctx, "tool.execute")
+	defer span.End()
+
+	// Add attributes
+	span.SetAttribute(GenAIOperationName, GenAIOperationExecuteTool)
+	span.SetAttribute(GenAIToolName, t.tool.Name())
+	span.SetAttribute(GenAIToolDescription, t.tool.Description())
+	span.SetAttribute("tool.arguments.length", len(args))
+	span.SetAttribute("tool.arguments.hash", hashString(args))
+
+	// Call the underlying tool
+	result, err := run(ctx, args)
+
+	span.SetAttribute("duration_ms", time.Since(startTime).Milliseconds())
+	if err != nil {
+		span.RecordError(err)
+		return result, err
+	}
+	span.SetAttribute("tool.result.length", len(result))
+	span.SetAttribute("tool.result.hash", hashString(result))
+
+	return result, nil
+}
+
+// DisplayName forwards to the underlying tool when it implements ToolWithDisplayName
+func (t *TracedTool) DisplayName() string {
+	if d, ok := t.tool.(interfaces.ToolWithDisplayName); ok {
+		return d.DisplayName()
+	}
+	return t.tool.Name()
+}
+
+// Internal forwards to the underlying tool when it implements InternalTool
+func (t *TracedTool) Internal() bool {
+	if i, ok := t.tool.(interfaces.InternalTool); ok {
+		return i.Internal()
+	}
+	return false
+}
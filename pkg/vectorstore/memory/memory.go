@@ -0,0 +1,327 @@
+// Package memory provides an in-process vector store that keeps documents and
+// their embeddings in memory and performs brute-force similarity search.
+// It is intended for tests, local development and small knowledge bases.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+)
+
+// Store is an in-memory implementation of interfaces.VectorStore
+type Store struct {
+	embedder interfaces.Embedder
+	metric   string
+
+	mu sync.RWMutex
+	// documents are keyed by tenant ("" for global data), then class, then
+	// document ID. Documents are stored under the organization of the
+	// context when no tenant is given, so organizations cannot read each
+	// other's documents.
+	documents map[string]map[string]map[string]interfaces.Document
+	tenants   map[string]bool
+}
+
+// Option represents an option for configuring the store
+type Option func(*Store)
+
+// WithDistanceMetric sets the similarity metric ("cosine", "euclidean", "dot_product")
+func WithDistanceMetric(metric string) Option {
+	return func(s *Store) {
+		s.metric = metric
+	}
+}
+
+// New creates a new in-memory vector store. The embedder is used to vectorize
+// documents stored without a vector and text queries.
+func New(embedder interfaces.Embedder, options ...Option) *Store {
+	s := &Store{
+		embedder:  embedder,
+		metric:    "cosine",
+		documents: make(map[string]map[string]map[string]interfaces.Document),
+		tenants:   make(map[string]bool),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// tenantOf returns the tenant of an operation: the tenant of its options, or
+// the organization of ctx
+func tenantOf(ctx context.Context, tenant string) string {
+	if tenant != "" {
+		return tenant
+	}
+	orgID, _ := multitenancy.GetOrgID(ctx)
+	return orgID
+}
+
+// Store implements interfaces.VectorStore.Store
+func (s *Store) Store(ctx context.Context, documents []interfaces.Document, options ...interfaces.StoreOption) error {
+	opts := &interfaces.StoreOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	return s.store(ctx, tenantOf(ctx, opts.Tenant), opts.Class, documents)
+}
+
+// GlobalStore implements interfaces.VectorStore.GlobalStore
+func (s *Store) GlobalStore(ctx context.Context, documents []interfaces.Document, options ...interfaces.StoreOption) error {
+	opts := &interfaces.StoreOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	return s.store(ctx, "", opts.Class, documents)
+}
+
+func (s *Store) store(ctx context.Context, tenant, class string, documents []interfaces.Document) error {
+	// Embed documents that do not carry a vector in a single batch
+	var texts []string
+	var indexes []int
+	for i, doc := range documents {
+		if doc.ID == "" {
+			return fmt.Errorf("document at index %d has no ID", i)
+		}
+		if len(doc.Vector) == 0 {
+			texts = append(texts, doc.Content)
+			indexes = append(indexes, i)
+		}
+	}
+
+	stored := make([]interfaces.Document, len(documents))
+	copy(stored, documents)
+
+	if len(texts) > 0 {
+		if s.embedder == nil {
+			return fmt.Errorf("documents without vectors require an embedder")
+		}
+		vectors, err := s.embedder.EmbedBatch(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed documents: %w", err)
+		}
+		if len(vectors) != len(texts) {
+			return fmt.Errorf("embedder returned %d vectors for %d documents", len(vectors), len(texts))
+		}
+		for i, index := range indexes {
+			stored[index].Vector = vectors[i]
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	classes, ok := s.documents[tenant]
+	if !ok {
+		classes = make(map[string]map[string]interfaces.Document)
+		s.documents[tenant] = classes
+	}
+	docs, ok := classes[class]
+	if !ok {
+		docs = make(map[string]interfaces.Document)
+		classes[class] = docs
+	}
+	for _, doc := range stored {
+		docs[doc.ID] = doc
+	}
+
+	return nil
+}
+
+// Get implements interfaces.VectorStore.Get
+func (s *Store) Get(ctx context.Context, id string, options ...interfaces.StoreOption) (*interfaces.Document, error) {
+	opts := &interfaces.StoreOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc, ok := s.documents[tenantOf(ctx, opts.Tenant)][opts.Class][id]
+	if !ok {
+		return nil, fmt.Errorf("document %s not found", id)
+	}
+	return &doc, nil
+}
+
+// Search implements interfaces.VectorStore.Search
+func (s *Store) Search(ctx context.Context, query string, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	opts := searchOptions(options)
+	return s.searchText(ctx, tenantOf(ctx, opts.Tenant), query, limit, opts)
+}
+
+// GlobalSearch implements interfaces.VectorStore.GlobalSearch
+func (s *Store) GlobalSearch(ctx context.Context, query string, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	return s.searchText(ctx, "", query, limit, searchOptions(options))
+}
+
+// SearchByVector implements interfaces.VectorStore.SearchByVector
+func (s *Store) SearchByVector(ctx context.Context, vector []float32, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	opts := searchOptions(options)
+	return s.searchVector(tenantOf(ctx, opts.Tenant), vector, limit, opts)
+}
+
+// GlobalSearchByVector implements interfaces.VectorStore.GlobalSearchByVector
+func (s *Store) GlobalSearchByVector(ctx context.Context, vector []float32, limit int, options ...interfaces.SearchOption) ([]interfaces.SearchResult, error) {
+	return s.searchVector("", vector, limit, searchOptions(options))
+}
+
+func searchOptions(options []interfaces.SearchOption) *interfaces.SearchOptions {
+	opts := &interfaces.SearchOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	return opts
+}
+
+func (s *Store) searchText(ctx context.Context, tenant, query string, limit int, opts *interfaces.SearchOptions) ([]interfaces.SearchResult, error) {
+	if s.embedder == nil {
+		return nil, fmt.Errorf("text search requires an embedder")
+	}
+	vector, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	return s.searchVector(tenant, vector, limit, opts)
+}
+
+func (s *Store) searchVector(tenant string, vector []float32, limit int, opts *interfaces.SearchOptions) ([]interfaces.SearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results []interfaces.SearchResult
+	for _, doc := range s.documents[tenant][opts.Class] {
+		if !matchesFilters(doc, opts.Filters) {
+			continue
+		}
+		score, err := similarity(vector, doc.Vector, s.metric)
+		if err != nil {
+			return nil, fmt.Errorf("failed to score document %s: %w", doc.ID, err)
+		}
+		if score < opts.MinScore {
+			continue
+		}
+		results = append(results, interfaces.SearchResult{Document: doc, Score: score})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].Document.ID < results[j].Document.ID
+		}
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// matchesFilters checks simple equality filters against document metadata
+func matchesFilters(doc interfaces.Document, filters map[string]interface{}) bool {
+	for key, want := range filters {
+		got, ok := doc.Metadata[key]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// Delete implements interfaces.VectorStore.Delete
+func (s *Store) Delete(ctx context.Context, ids []string, options ...interfaces.DeleteOption) error {
+	opts := &interfaces.DeleteOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	s.delete(tenantOf(ctx, opts.Tenant), opts.Class, ids)
+	return nil
+}
+
+// GlobalDelete implements interfaces.VectorStore.GlobalDelete
+func (s *Store) GlobalDelete(ctx context.Context, ids []string, options ...interfaces.DeleteOption) error {
+	opts := &interfaces.DeleteOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	s.delete("", opts.Class, ids)
+	return nil
+}
+
+func (s *Store) delete(tenant, class string, ids []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docs := s.documents[tenant][class]
+	for _, id := range ids {
+		delete(docs, id)
+	}
+}
+
+// CreateTenant implements interfaces.VectorStore.CreateTenant
+func (s *Store) CreateTenant(ctx context.Context, tenantName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenants[tenantName] = true
+	return nil
+}
+
+// DeleteTenant implements interfaces.VectorStore.DeleteTenant
+func (s *Store) DeleteTenant(ctx context.Context, tenantName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tenants, tenantName)
+	delete(s.documents, tenantName)
+	return nil
+}
+
+// ListTenants implements interfaces.VectorStore.ListTenants
+func (s *Store) ListTenants(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenants := make([]string, 0, len(s.tenants))
+	for tenant := range s.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+// similarity computes the similarity between two vectors. Euclidean distance is
+// converted to a similarity in (0, 1] so that higher is always better.
+func similarity(a, b []float32, metric string) (float32, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("vector dimensions differ: %d vs %d", len(a), len(b))
+	}
+
+	var dot, normA, normB, dist float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		normA += x * x
+		normB += y * y
+		dist += (x - y) * (x - y)
+	}
+
+	switch metric {
+	case "dot", "dot_product":
+		return float32(dot), nil
+	case "euclidean":
+		return float32(1 / (1 + math.Sqrt(dist))), nil
+	default:
+		if normA == 0 || normB == 0 {
+			return 0, nil
+		}
+		return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB))), nil
+	}
+}
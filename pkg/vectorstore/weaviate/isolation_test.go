@@ -0,0 +1,130 @@
+package weaviate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
+	weaviatestore "github.com/Ingenimax/agent-sdk-go/pkg/vectorstore/weaviate"
+)
+
+// fakeWeaviate serves the object, batch and GraphQL endpoints of Weaviate
+// from memory
+type fakeWeaviate struct {
+	mu      sync.Mutex
+	objects map[string]map[string]interface{}
+	queries []string
+}
+
+func (f *fakeWeaviate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/v1/meta":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"version": "1.25.0"})
+	case r.URL.Path == "/v1/batch/objects":
+		var body struct {
+			Objects []map[string]interface{} `json:"objects"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, object := range body.Objects {
+			f.objects[object["id"].(string)] = object
+			object["result"] = map[string]interface{}{}
+		}
+		_ = json.NewEncoder(w).Encode(body.Objects)
+	case r.URL.Path == "/v1/graphql":
+		query, _ := io.ReadAll(r.Body)
+		f.queries = append(f.queries, string(query))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"Get": map[string]interface{}{}}})
+	case strings.HasPrefix(r.URL.Path, "/v1/objects/"):
+		id := path.Base(r.URL.Path)
+		object, ok := f.objects[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(f.objects, id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(object)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestStoreIsolatesOrganizations(t *testing.T) {
+	fake := &fakeWeaviate{objects: make(map[string]map[string]interface{})}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store := weaviatestore.New(&interfaces.VectorStoreConfig{
+		Host:   strings.TrimPrefix(server.URL, "http://"),
+		Scheme: "http",
+	}, weaviatestore.WithEmbedder(&MockEmbedder{}))
+
+	orgA := multitenancy.WithOrgID(context.Background(), "org-a")
+	orgB := multitenancy.WithOrgID(context.Background(), "org-b")
+	const id = "6f1c5a54-8c1e-4a8e-9d0a-3b1e2f1a7c01"
+
+	if err := store.Store(orgA, []interfaces.Document{{ID: id, Content: "org A's chunk"}}); err != nil {
+		t.Fatalf("Failed to store document: %v", err)
+	}
+
+	// Another organization can neither read nor delete the document
+	if _, err := store.Get(orgB, id); err == nil {
+		t.Error("Expected another organization's document to be reported as not found")
+	}
+	if _, err := store.Get(context.Background(), id); err == nil {
+		t.Error("Expected a context without organization not to read an organization's document")
+	}
+	if err := store.Delete(orgB, []string{id}); err == nil {
+		t.Error("Expected deleting another organization's document to fail")
+	}
+	if err := store.Delete(context.Background(), []string{id}); err == nil {
+		t.Error("Expected a context without organization not to delete an organization's document")
+	}
+
+	// Storing a document with the same ID must not take it over
+	err := store.Store(orgB, []interfaces.Document{{ID: id, Content: "org B's chunk"}})
+	if !errors.Is(err, multitenancy.ErrCrossTenantAccess) {
+		t.Errorf("Expected ErrCrossTenantAccess storing over another organization's document, got %v", err)
+	}
+
+	doc, err := store.Get(orgA, id)
+	if err != nil {
+		t.Fatalf("Failed to get document: %v", err)
+	}
+	if doc.Content != "org A's chunk" {
+		t.Errorf("Expected the document of org A to be kept, got %q", doc.Content)
+	}
+
+	// The organization can update and delete its own document
+	if err := store.Store(orgA, []interfaces.Document{{ID: id, Content: "org A's update"}}); err != nil {
+		t.Errorf("Failed to update own document: %v", err)
+	}
+	if err := store.Delete(orgA, []string{id}); err != nil {
+		t.Errorf("Failed to delete own document: %v", err)
+	}
+
+	// Searches without organization are limited to the default organization
+	if _, err := store.Search(context.Background(), "chunk", 5); err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.queries) != 1 || !strings.Contains(fake.queries[0], `orgId`) || !strings.Contains(fake.queries[0], multitenancy.DefaultOrgID) {
+		t.Errorf("Expected the search to filter on the default organization, got %v", fake.queries)
+	}
+}
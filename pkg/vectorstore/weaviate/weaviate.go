@@ -2,11 +2,14 @@ package weaviate
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/auth"
+	"github.com/weaviate/weaviate-go-client/v5/weaviate/fault"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/filters"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/graphql"
 	"github.com/weaviate/weaviate/entities/models"
@@ -14,6 +17,7 @@ import (
 	"github.com/Ingenimax/agent-sdk-go/pkg/embedding"
 	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
 	"github.com/Ingenimax/agent-sdk-go/pkg/logging"
+	"github.com/Ingenimax/agent-sdk-go/pkg/multitenancy"
 	"github.com/go-openapi/strfmt"
 )
 
@@ -109,6 +113,60 @@ func (s *Store) getClassName(ctx context.Context, class string) (string, error)
 	return class, nil
 }
 
+// orgIDProperty is the property holding the organization of a document
+const orgIDProperty = "orgId"
+
+// orgScope returns the organization whose documents an operation is limited
+// to: the organization of the context, or multitenancy.DefaultOrgID without
+// one, unless a native tenant is set
+func orgScope(ctx context.Context, tenant string) string {
+	if tenant != "" {
+		return ""
+	}
+	return multitenancy.OrgIDOrDefault(ctx)
+}
+
+// checkOwner fails with multitenancy.ErrCrossTenantAccess if the object with
+// the given ID belongs to another organization. Object IDs are unique per
+// class, so storing it would take over the other organization's document.
+func (s *Store) checkOwner(ctx context.Context, className, id, orgID string) error {
+	result, err := s.client.Data().ObjectsGetter().
+		WithClassName(className).
+		WithID(id).
+		Do(ctx)
+	if err != nil {
+		var clientErr *fault.WeaviateClientError
+		if errors.As(err, &clientErr) && clientErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to check document %s: %w", id, err)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+
+	properties, _ := result[0].Properties.(map[string]interface{})
+	if owner := fmt.Sprint(properties[orgIDProperty]); owner != orgID {
+		return fmt.Errorf("failed to store document %s: %w", id, multitenancy.ErrCrossTenantAccess)
+	}
+	return nil
+}
+
+// withOrgFilter limits a where filter to the documents of orgID
+func withOrgFilter(where *filters.WhereBuilder, orgID string) *filters.WhereBuilder {
+	if orgID == "" {
+		return where
+	}
+	orgFilter := filters.Where().
+		WithPath([]string{orgIDProperty}).
+		WithOperator(filters.Equal).
+		WithValueString(orgID)
+	if where == nil {
+		return orgFilter
+	}
+	return filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{where, orgFilter})
+}
+
 // Store stores documents in Weaviate with optional tenant support
 func (s *Store) Store(ctx context.Context, documents []interfaces.Document, options ...interfaces.StoreOption) error {
 	// Apply options
@@ -143,6 +201,12 @@ func (s *Store) Store(ctx context.Context, documents []interfaces.Document, opti
 		for k, v := range doc.Metadata {
 			properties[k] = v
 		}
+		if orgID := orgScope(ctx, opts.Tenant); orgID != "" {
+			if err := s.checkOwner(ctx, className, doc.ID, orgID); err != nil {
+				return err
+			}
+			properties[orgIDProperty] = orgID
+		}
 
 		obj := &models.Object{
 			Class:      className,
@@ -202,8 +266,8 @@ func (s *Store) Search(ctx context.Context, query string, limit int, options ...
 		return nil, fmt.Errorf("failed to generate embedding for query: %w", err)
 	}
 
-	// Build query
-	whereFilter := s.buildWhereFilter(opts.Filters)
+	// Build query, limited to the documents of the context's organization
+	whereFilter := withOrgFilter(s.buildWhereFilter(opts.Filters), orgScope(ctx, opts.Tenant))
 
 	// Debug log for filter
 	if len(opts.Filters) > 0 {
@@ -301,8 +365,8 @@ func (s *Store) SearchByVector(ctx context.Context, vector []float32, limit int,
 		return nil, err
 	}
 
-	// Build query
-	whereFilter := s.buildWhereFilter(opts.Filters)
+	// Build query, limited to the documents of the context's organization
+	whereFilter := withOrgFilter(s.buildWhereFilter(opts.Filters), orgScope(ctx, opts.Tenant))
 
 	// Build dynamic field list
 	fieldList, err := s.buildFieldList(ctx, className, opts.Fields)
@@ -358,7 +422,15 @@ func (s *Store) Delete(ctx context.Context, ids []string, options ...interfaces.
 	}
 
 	// Delete objects
+	orgID := orgScope(ctx, opts.Tenant)
 	for _, id := range ids {
+		// Documents of other organizations may not be deleted
+		if orgID != "" {
+			if _, err := s.Get(ctx, id, interfaces.WithClass(opts.Class)); err != nil {
+				return fmt.Errorf("failed to delete document %s: %w", id, err)
+			}
+		}
+
 		deleter := s.client.Data().Deleter().
 			WithClassName(className).
 			WithID(id)
@@ -409,6 +481,13 @@ func (s *Store) Get(ctx context.Context, id string, options ...interfaces.StoreO
 		return nil, fmt.Errorf("document %s not found", id)
 	}
 
+	// Documents of other organizations are reported as not found
+	if orgID := orgScope(ctx, opts.Tenant); orgID != "" {
+		if properties, _ := result[0].Properties.(map[string]interface{}); fmt.Sprint(properties[orgIDProperty]) != orgID {
+			return nil, fmt.Errorf("document %s not found", id)
+		}
+	}
+
 	doc := &interfaces.Document{
 		ID:       id,
 		Content:  result[0].Properties.(map[string]interface{})["content"].(string),
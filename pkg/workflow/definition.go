@@ -0,0 +1,335 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/orchestration"
+	"github.com/Ingenimax/agent-sdk-go/pkg/retry"
+)
+
+// Task types of a workflow definition
+const (
+	// TaskTypeAgent runs an agent on the results of its dependencies
+	TaskTypeAgent = "agent"
+
+	// TaskTypeJoin merges the results of its dependencies
+	TaskTypeJoin = "join"
+
+	// TaskTypeMap runs an agent for each item of a list in the results of
+	// its dependencies
+	TaskTypeMap = "map"
+
+	// TaskTypeApproval waits for the approval of the results of its
+	// dependencies
+	TaskTypeApproval = "approval"
+)
+
+// retryPredicates are the conditions a retry policy can retry on
+var retryPredicates = map[string]func(result string, err error) bool{
+	"error": func(result string, err error) bool {
+		return err != nil
+	},
+	"empty": func(result string, err error) bool {
+		return err != nil || strings.TrimSpace(result) == ""
+	},
+	"invalid_json": func(result string, err error) bool {
+		return err != nil || !json.Valid([]byte(strings.TrimSpace(result)))
+	},
+}
+
+// Definition is a workflow defined in YAML, next to the agents.yaml of its
+// agents
+type Definition struct {
+	// Name is the name of the workflow
+	Name string `yaml:"name"`
+
+	// Description describes what the workflow does
+	Description string `yaml:"description,omitempty"`
+
+	// FinalTask is the task whose result is the result of the workflow
+	FinalTask string `yaml:"final_task"`
+
+	// MaxAttempts limits the agent runs of all the tasks
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+
+	// Tasks are the tasks of the workflow by ID
+	Tasks map[string]TaskDefinition `yaml:"tasks"`
+}
+
+// TaskDefinition is a task of a workflow definition
+type TaskDefinition struct {
+	// Type is agent (default), join, map or approval
+	Type string `yaml:"type,omitempty"`
+
+	// Agent is the name of the agent in agents.yaml, for agent and map tasks
+	Agent string `yaml:"agent,omitempty"`
+
+	// Input is the input of the agent. {variable} placeholders are replaced
+	// when the workflow is built.
+	Input string `yaml:"input,omitempty"`
+
+	// DependsOn lists the tasks whose results the task receives
+	DependsOn []string `yaml:"depends_on,omitempty"`
+
+	// Merge is the merge strategy of join and map tasks: concat (default),
+	// json or json_array
+	Merge string `yaml:"merge,omitempty"`
+
+	// Separator separates the results merged with concat
+	Separator string `yaml:"separator,omitempty"`
+
+	// OnError is fail (default) or skip, for the failed branches of join
+	// and map tasks
+	OnError string `yaml:"on_error,omitempty"`
+
+	// ItemsPath is the path of the list of a map task, e.g. plan.files
+	ItemsPath string `yaml:"items_path,omitempty"`
+
+	// MaxConcurrency limits the items a map task processes at once
+	MaxConcurrency int `yaml:"max_concurrency,omitempty"`
+
+	// Prompt tells the approver of an approval task what to decide
+	Prompt string `yaml:"prompt,omitempty"`
+
+	// Timeout rejects an approval without decision in time, e.g. 24h
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// Retry is the retry policy of agent and map tasks
+	Retry *RetryDefinition `yaml:"retry,omitempty"`
+}
+
+// RetryDefinition is the retry policy of a task
+type RetryDefinition struct {
+	MaxAttempts        int32         `yaml:"max_attempts,omitempty"`
+	InitialInterval    time.Duration `yaml:"initial_interval,omitempty"`
+	BackoffCoefficient float64       `yaml:"backoff_coefficient,omitempty"`
+	MaxInterval        time.Duration `yaml:"max_interval,omitempty"`
+
+	// RetryIf is the condition attempts are retried on: error (default),
+	// empty or invalid_json
+	RetryIf string `yaml:"retry_if,omitempty"`
+}
+
+// LoadFromFile loads and validates a workflow definition from a YAML file
+func LoadFromFile(filePath string) (*Definition, error) {
+	if filePath == "" || strings.Contains(filepath.Clean(filePath), "..") {
+		return nil, fmt.Errorf("invalid file path")
+	}
+
+	data, err := os.ReadFile(filepath.Clean(filePath)) // #nosec G304 - Path is validated above
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates a workflow definition
+func Parse(data []byte) (*Definition, error) {
+	var definition Definition
+	if err := yaml.Unmarshal(data, &definition); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal workflow definition: %w", err)
+	}
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// Validate checks the tasks, their dependencies and the final task
+func (d *Definition) Validate() error {
+	if len(d.Tasks) == 0 {
+		return fmt.Errorf("workflow %s has no tasks", d.Name)
+	}
+	if _, ok := d.Tasks[d.FinalTask]; d.FinalTask != "" && !ok {
+		return fmt.Errorf("final task %s not found", d.FinalTask)
+	}
+
+	for id, task := range d.Tasks {
+		switch task.Type {
+		case "", TaskTypeAgent, TaskTypeMap:
+			if task.Agent == "" {
+				return fmt.Errorf("task %s has no agent", id)
+			}
+		case TaskTypeJoin, TaskTypeApproval:
+			if len(task.DependsOn) == 0 {
+				return fmt.Errorf("%s task %s has no dependencies", task.Type, id)
+			}
+		default:
+			return fmt.Errorf("task %s has unknown type %q", id, task.Type)
+		}
+
+		switch orchestration.MergeStrategy(task.Merge) {
+		case "", orchestration.MergeConcat, orchestration.MergeJSON, orchestration.MergeJSONArray:
+		default:
+			return fmt.Errorf("task %s has unknown merge strategy %q", id, task.Merge)
+		}
+		switch orchestration.BranchErrorPolicy(task.OnError) {
+		case "", orchestration.FailOnBranchError, orchestration.SkipFailedBranches:
+		default:
+			return fmt.Errorf("task %s has unknown on_error %q", id, task.OnError)
+		}
+		if task.Retry != nil && task.Retry.RetryIf != "" && retryPredicates[task.Retry.RetryIf] == nil {
+			return fmt.Errorf("task %s has unknown retry_if %q", id, task.Retry.RetryIf)
+		}
+
+		for _, dep := range task.DependsOn {
+			if _, ok := d.Tasks[dep]; !ok {
+				return fmt.Errorf("task %s depends on unknown task %s", id, dep)
+			}
+		}
+	}
+
+	return d.checkCycles()
+}
+
+// checkCycles returns an error if tasks depend on each other in a cycle,
+// which would never run
+func (d *Definition) checkCycles() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(d.Tasks))
+
+	var visit func(id string, path []string) error
+	visit = func(id string, path []string) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("tasks depend on each other: %s", strings.Join(append(path, id), " -> "))
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		for _, dep := range d.Tasks[id].DependsOn {
+			if err := visit(dep, append(path, id)); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		return nil
+	}
+
+	for _, id := range d.taskIDs() {
+		if err := visit(id, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskIDs returns the IDs of the tasks in a stable order
+func (d *Definition) taskIDs() []string {
+	ids := make([]string, 0, len(d.Tasks))
+	for id := range d.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Build creates the workflow of the definition, replacing the {variable}
+// placeholders of inputs and prompts with variables
+func (d *Definition) Build(variables map[string]string) (*orchestration.Workflow, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	replacer := newVariableReplacer(variables)
+	w := orchestration.NewWorkflow()
+	w.MaxAttempts = d.MaxAttempts
+
+	for _, id := range d.taskIDs() {
+		task := d.Tasks[id]
+		join := orchestration.JoinConfig{
+			Strategy:  orchestration.MergeStrategy(task.Merge),
+			Separator: task.Separator,
+			OnError:   orchestration.BranchErrorPolicy(task.OnError),
+		}
+
+		switch task.Type {
+		case "", TaskTypeAgent:
+			w.AddTask(id, task.Agent, replacer.Replace(task.Input), task.DependsOn)
+		case TaskTypeJoin:
+			w.AddJoin(id, task.DependsOn, join)
+		case TaskTypeMap:
+			w.AddMap(id, task.DependsOn, orchestration.MapConfig{
+				AgentID:        task.Agent,
+				Input:          replacer.Replace(task.Input),
+				ItemsPath:      task.ItemsPath,
+				MaxConcurrency: task.MaxConcurrency,
+				Join:           join,
+			})
+		case TaskTypeApproval:
+			w.AddApproval(id, task.DependsOn, orchestration.ApprovalConfig{
+				Prompt:  replacer.Replace(task.Prompt),
+				Timeout: task.Timeout,
+			})
+		}
+
+		if task.Retry != nil {
+			w.SetRetryPolicy(id, task.Retry.policy())
+		}
+	}
+
+	w.SetFinalTask(d.FinalTask)
+	return w, nil
+}
+
+// NewRegistry creates the agents of the agent and map tasks from their
+// configurations in agents.yaml
+func (d *Definition) NewRegistry(configs agent.AgentConfigs, variables map[string]string, options ...agent.Option) (*orchestration.AgentRegistry, error) {
+	registry := orchestration.NewAgentRegistry()
+	for _, id := range d.taskIDs() {
+		name := d.Tasks[id].Agent
+		if name == "" {
+			continue
+		}
+		if _, ok := registry.Get(name); ok {
+			continue
+		}
+		a, err := agent.NewAgentFromConfig(name, configs, variables, options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create agent %s for task %s: %w", name, id, err)
+		}
+		registry.Register(name, a)
+	}
+	return registry, nil
+}
+
+// policy converts the definition to a retry policy, with the defaults of
+// retry.NewPolicy for the unset fields
+func (r *RetryDefinition) policy() orchestration.RetryPolicy {
+	backoff := retry.NewPolicy()
+	if r.MaxAttempts > 0 {
+		backoff.MaximumAttempts = r.MaxAttempts
+	}
+	if r.InitialInterval > 0 {
+		backoff.InitialInterval = r.InitialInterval
+	}
+	if r.BackoffCoefficient > 0 {
+		backoff.BackoffCoefficient = r.BackoffCoefficient
+	}
+	if r.MaxInterval > 0 {
+		backoff.MaximumInterval = r.MaxInterval
+	}
+	return orchestration.RetryPolicy{Backoff: backoff, RetryIf: retryPredicates[r.RetryIf]}
+}
+
+// newVariableReplacer replaces {variable} placeholders, like agent
+// configurations do
+func newVariableReplacer(variables map[string]string) *strings.Replacer {
+	pairs := make([]string, 0, len(variables)*2)
+	for key, value := range variables {
+		pairs = append(pairs, "{"+key+"}", value)
+	}
+	return strings.NewReplacer(pairs...)
+}
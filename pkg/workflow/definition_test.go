@@ -0,0 +1,157 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/orchestration"
+)
+
+const testDefinition = `
+name: terraform_module
+final_task: review
+max_attempts: 20
+tasks:
+  plan:
+    agent: planner
+    input: Plan a Terraform module for {service}
+    retry:
+      max_attempts: 2
+      initial_interval: 1ms
+      retry_if: invalid_json
+  generate:
+    type: map
+    agent: generator
+    depends_on: [plan]
+    input: Write this file
+    items_path: files
+    max_concurrency: 2
+    merge: json_array
+  review:
+    type: approval
+    depends_on: [generate]
+    prompt: Apply the module for {service}?
+    timeout: 24h
+`
+
+// scriptedLLM answers with the response of the first key its prompt contains
+type scriptedLLM struct {
+	responses map[string]string
+}
+
+func (m *scriptedLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	for key, response := range m.responses {
+		if strings.Contains(prompt, key) {
+			return response, nil
+		}
+	}
+	return "", nil
+}
+
+func (m *scriptedLLM) GenerateWithTools(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *scriptedLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := m.Generate(ctx, prompt, options...)
+	return &interfaces.LLMResponse{Content: content}, err
+}
+
+func (m *scriptedLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, tools []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return m.GenerateDetailed(ctx, prompt, options...)
+}
+
+func (m *scriptedLLM) Name() string { return "scripted" }
+
+func (m *scriptedLLM) SupportsStreaming() bool { return false }
+
+func TestLoadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workflow.yaml")
+	if err := os.WriteFile(path, []byte(testDefinition), 0600); err != nil {
+		t.Fatalf("Failed to write definition: %v", err)
+	}
+
+	definition, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	w, err := definition.Build(map[string]string{"service": "billing"})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tasks := make(map[string]*orchestration.Task)
+	for _, task := range w.Tasks {
+		tasks[task.ID] = task
+	}
+	if w.FinalTaskID != "review" || w.MaxAttempts != 20 || len(tasks) != 3 {
+		t.Fatalf("Unexpected workflow %+v", w)
+	}
+	if tasks["plan"].Input != "Plan a Terraform module for billing" || tasks["plan"].Retry.Backoff.MaximumAttempts != 2 {
+		t.Errorf("Unexpected plan task %+v", tasks["plan"])
+	}
+	if m := tasks["generate"].Map; m == nil || m.ItemsPath != "files" || m.MaxConcurrency != 2 || m.Join.Strategy != orchestration.MergeJSONArray {
+		t.Errorf("Unexpected map task %+v", tasks["generate"])
+	}
+	if a := tasks["review"].Approval; a == nil || a.Prompt != "Apply the module for billing?" || a.Timeout != 24*time.Hour {
+		t.Errorf("Unexpected approval task %+v", tasks["review"])
+	}
+
+	// The agents come from agents.yaml
+	configs := agent.AgentConfigs{
+		"planner":   {Role: "Planner", Goal: "Plan modules", Backstory: "An architect"},
+		"generator": {Role: "Generator", Goal: "Write files", Backstory: "An engineer"},
+	}
+	llm := &scriptedLLM{responses: map[string]string{
+		"Plan a Terraform module": `{"files": ["main.tf", "variables.tf"]}`,
+		"Write this file":         `"resource"`,
+	}}
+	registry, err := definition.NewRegistry(configs, nil, agent.WithLLM(llm))
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	queue := orchestration.NewApprovalQueue()
+	queue.OnRequest(func(req orchestration.ApprovalRequest) {
+		go func() { _ = queue.Decide(req.ID, orchestration.ApprovalDecision{Approved: true}) }()
+	})
+
+	result, err := orchestration.NewCodeOrchestrator(registry).WithApprover(queue.Wait).ExecuteWorkflow(context.Background(), w)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if result != `["resource","resource"]` {
+		t.Errorf("Unexpected result %q", result)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"no tasks", "name: empty", "has no tasks"},
+		{"unknown final task", "final_task: x\ntasks:\n  a: {agent: a}", "final task x not found"},
+		{"no agent", "tasks:\n  a: {input: hi}", "has no agent"},
+		{"unknown type", "tasks:\n  a: {type: loop, agent: a}", "unknown type"},
+		{"unknown dependency", "tasks:\n  a: {agent: a, depends_on: [b]}", "unknown task b"},
+		{"unknown merge", "tasks:\n  a: {type: map, agent: a, merge: custom}", "unknown merge strategy"},
+		{"unknown retry condition", "tasks:\n  a: {agent: a, retry: {retry_if: always}}", "unknown retry_if"},
+		{"cycle", "tasks:\n  a: {agent: a, depends_on: [b]}\n  b: {agent: b, depends_on: [a]}", "a -> b -> a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}